@@ -0,0 +1,52 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package common
+
+import (
+	"context"
+	"sync"
+)
+
+type afterCommitKey struct{}
+
+// 事务提交成功后才执行的操作, 如向其他服务登记血缘. 不能在事务内调用外部服务,
+// 否则事务回滚后外部服务中的数据无法撤回
+type AfterCommitHooks struct {
+	mu    sync.Mutex
+	hooks []func()
+}
+
+// 开启事务时在 ctx 中放入新的回调收集器, 事务内通过 OnAfterCommit 登记回调,
+// 提交成功后由开启事务的一方调用 Run
+func WithAfterCommitHooks(ctx context.Context) (context.Context, *AfterCommitHooks) {
+	hooks := &AfterCommitHooks{}
+	return context.WithValue(ctx, afterCommitKey{}, hooks), hooks
+}
+
+// 登记事务提交后执行的回调. ctx 中没有收集器说明调用方没有开启事务, 直接执行
+func OnAfterCommit(ctx context.Context, hook func()) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*AfterCommitHooks)
+	if !ok || hooks == nil {
+		hook()
+		return
+	}
+
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.hooks = append(hooks.hooks, hook)
+}
+
+// 按登记顺序执行回调
+func (h *AfterCommitHooks) Run() {
+	h.mu.Lock()
+	hooks := h.hooks
+	h.hooks = nil
+	h.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+}
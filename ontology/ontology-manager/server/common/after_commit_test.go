@@ -0,0 +1,45 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func Test_AfterCommit_OnAfterCommit(t *testing.T) {
+	Convey("Test OnAfterCommit", t, func() {
+		Convey("Run immediately without hooks in context\n", func() {
+			called := false
+			OnAfterCommit(context.Background(), func() { called = true })
+			So(called, ShouldBeTrue)
+		})
+
+		Convey("Deferred until Run, in order\n", func() {
+			ctx, hooks := WithAfterCommitHooks(context.Background())
+			calls := []int{}
+			OnAfterCommit(ctx, func() { calls = append(calls, 1) })
+			OnAfterCommit(ctx, func() { calls = append(calls, 2) })
+			So(calls, ShouldBeEmpty)
+
+			hooks.Run()
+			So(calls, ShouldResemble, []int{1, 2})
+
+			// 回调只执行一次
+			hooks.Run()
+			So(calls, ShouldResemble, []int{1, 2})
+		})
+
+		Convey("Dropped when Run is not called after rollback\n", func() {
+			ctx, _ := WithAfterCommitHooks(context.Background())
+			called := false
+			OnAfterCommit(ctx, func() { called = true })
+			So(called, ShouldBeFalse)
+		})
+	})
+}
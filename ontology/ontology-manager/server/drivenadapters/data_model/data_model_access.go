@@ -120,3 +120,114 @@ func (dda *dataModelAccess) GetMetricModelByID(ctx context.Context, id string) (
 	o11y.AddHttpAttrs4Ok(span, respCode)
 	return models[0], nil
 }
+
+// 整体替换本体模型登记到 data-model 的血缘边
+func (dda *dataModelAccess) ReplaceLineageEdges(ctx context.Context, owner interfaces.LineageNode, edges []*interfaces.LineageEdge) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Replace lineage edges in data-model service", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("owner_type").String(owner.Type),
+		attr.Key("owner_id").String(owner.ID))
+
+	httpUrl := fmt.Sprintf("%s/lineage/owners/%s/%s", dda.appSetting.DataModelUrl, owner.Type, owner.ID)
+	o11y.AddAttrs4InternalHttp(span, o11y.TraceAttrs{
+		HttpUrl:         httpUrl,
+		HttpMethod:      http.MethodPut,
+		HttpContentType: rest.ContentTypeJson,
+	})
+
+	if edges == nil {
+		edges = []*interfaces.LineageEdge{}
+	}
+	body := map[string]any{
+		"edges": edges,
+	}
+	respCode, respData, err := dda.httpClient.PutNoUnmarshal(ctx, httpUrl, dda.getHeaders(ctx), body)
+	if err != nil {
+		errDetails := fmt.Sprintf("ReplaceLineageEdges http request failed: %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Http replace lineage edges failed")
+
+		return fmt.Errorf("put request method failed: %s", err)
+	}
+
+	if respCode != http.StatusNoContent {
+		logger.Errorf("replace lineage edges failed: %s", respData)
+
+		var baseError rest.BaseError
+		if err = sonic.Unmarshal(respData, &baseError); err != nil {
+			logger.Errorf("Unmalshal baesError failed: %s", err)
+			o11y.Error(ctx, err.Error())
+			o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Unmarshal baseError failed")
+			return err
+		}
+
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", baseError.Description, baseError.ErrorDetails))
+		o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Http status code is not 204")
+		return fmt.Errorf("ReplaceLineageEdges failed: %v", baseError.ErrorDetails)
+	}
+
+	o11y.AddHttpAttrs4Ok(span, respCode)
+	return nil
+}
+
+// 删除本体模型登记到 data-model 的血缘边
+func (dda *dataModelAccess) DeleteLineageEdges(ctx context.Context, owner interfaces.LineageNode) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Delete lineage edges in data-model service", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("owner_type").String(owner.Type),
+		attr.Key("owner_id").String(owner.ID))
+
+	httpUrl := fmt.Sprintf("%s/lineage/owners/%s/%s", dda.appSetting.DataModelUrl, owner.Type, owner.ID)
+	o11y.AddAttrs4InternalHttp(span, o11y.TraceAttrs{
+		HttpUrl:         httpUrl,
+		HttpMethod:      http.MethodDelete,
+		HttpContentType: rest.ContentTypeJson,
+	})
+
+	respCode, respData, err := dda.httpClient.DeleteNoUnmarshal(ctx, httpUrl, dda.getHeaders(ctx))
+	if err != nil {
+		errDetails := fmt.Sprintf("DeleteLineageEdges http request failed: %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Http delete lineage edges failed")
+
+		return fmt.Errorf("delete request method failed: %s", err)
+	}
+
+	if respCode != http.StatusNoContent {
+		logger.Errorf("delete lineage edges failed: %s", respData)
+
+		var baseError rest.BaseError
+		if err = sonic.Unmarshal(respData, &baseError); err != nil {
+			logger.Errorf("Unmalshal baesError failed: %s", err)
+			o11y.Error(ctx, err.Error())
+			o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Unmarshal baseError failed")
+			return err
+		}
+
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", baseError.Description, baseError.ErrorDetails))
+		o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Http status code is not 204")
+		return fmt.Errorf("DeleteLineageEdges failed: %v", baseError.ErrorDetails)
+	}
+
+	o11y.AddHttpAttrs4Ok(span, respCode)
+	return nil
+}
+
+func (dda *dataModelAccess) getHeaders(ctx context.Context) map[string]string {
+	accountInfo := interfaces.AccountInfo{}
+	if ctx.Value(interfaces.ACCOUNT_INFO_KEY) != nil {
+		accountInfo = ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo)
+	}
+	return map[string]string{
+		interfaces.CONTENT_TYPE_NAME:        interfaces.CONTENT_TYPE_JSON,
+		"X-Language":                        rest.GetLanguageByCtx(ctx),
+		interfaces.HTTP_HEADER_ACCOUNT_ID:   accountInfo.ID,
+		interfaces.HTTP_HEADER_ACCOUNT_TYPE: accountInfo.Type,
+	}
+}
@@ -154,3 +154,104 @@ func Test_dataModelAccess_GetMetricModelByID(t *testing.T) {
 		})
 	})
 }
+
+func Test_dataModelAccess_ReplaceLineageEdges(t *testing.T) {
+	Convey("Test ReplaceLineageEdges", t, func() {
+		ctx := context.Background()
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{
+			DataModelUrl: "http://test-data-model",
+		}
+		mockHTTPClient := rmock.NewMockHTTPClient(mockCtrl)
+
+		dda := newTestDataModelAccess(appSetting, mockHTTPClient)
+
+		owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE, ID: "kn1/ot1"}
+		edges := []*interfaces.LineageEdge{
+			{
+				Source: interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, ID: "v1"},
+				Target: owner,
+			},
+		}
+
+		Convey("Success replacing lineage edges", func() {
+			mockHTTPClient.EXPECT().
+				PutNoUnmarshal(gomock.Any(), "http://test-data-model/lineage/owners/object_type/kn1/ot1", gomock.Any(), gomock.Any()).
+				Return(http.StatusNoContent, nil, nil)
+
+			err := dda.ReplaceLineageEdges(ctx, owner, edges)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("HTTP request error", func() {
+			mockHTTPClient.EXPECT().
+				PutNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(0, nil, errors.New("network error"))
+
+			err := dda.ReplaceLineageEdges(ctx, owner, edges)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Non-204 status code with error", func() {
+			baseError := rest.BaseError{
+				ErrorCode:    "DataModel.Lineage.InvalidParameter.Edges",
+				Description:  "Invalid edges",
+				ErrorDetails: "edge target must be the owner",
+			}
+			respData, _ := sonic.Marshal(baseError)
+
+			mockHTTPClient.EXPECT().
+				PutNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(http.StatusBadRequest, respData, nil)
+
+			err := dda.ReplaceLineageEdges(ctx, owner, edges)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_dataModelAccess_DeleteLineageEdges(t *testing.T) {
+	Convey("Test DeleteLineageEdges", t, func() {
+		ctx := context.Background()
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{
+			DataModelUrl: "http://test-data-model",
+		}
+		mockHTTPClient := rmock.NewMockHTTPClient(mockCtrl)
+
+		dda := newTestDataModelAccess(appSetting, mockHTTPClient)
+
+		owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_RELATION_TYPE, ID: "kn1/rt1"}
+
+		Convey("Success deleting lineage edges", func() {
+			mockHTTPClient.EXPECT().
+				DeleteNoUnmarshal(gomock.Any(), "http://test-data-model/lineage/owners/relation_type/kn1/rt1", gomock.Any()).
+				Return(http.StatusNoContent, nil, nil)
+
+			err := dda.DeleteLineageEdges(ctx, owner)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("HTTP request error", func() {
+			mockHTTPClient.EXPECT().
+				DeleteNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(0, nil, errors.New("network error"))
+
+			err := dda.DeleteLineageEdges(ctx, owner)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Non-204 status code with invalid error format", func() {
+			mockHTTPClient.EXPECT().
+				DeleteNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(http.StatusInternalServerError, []byte("invalid json"), nil)
+
+			err := dda.DeleteLineageEdges(ctx, owner)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -13,6 +13,12 @@ import (
 const (
 	VIEW_QueryType_DSL = "DSL"
 	VIEW_QueryType_SQL = "SQL"
+
+	// data-model 血缘节点类型
+	LINEAGE_NODE_TYPE_DATA_VIEW     = "data_view"
+	LINEAGE_NODE_TYPE_METRIC_MODEL  = "metric_model"
+	LINEAGE_NODE_TYPE_OBJECT_TYPE   = "object_type"   // id 为 <kn_id>/<ot_id>
+	LINEAGE_NODE_TYPE_RELATION_TYPE = "relation_type" // id 为 <kn_id>/<rt_id>
 )
 
 var (
@@ -145,7 +151,30 @@ type MetricModel struct {
 	FieldsMap    map[string]Field `json:"fields_map"` // 字段集
 }
 
+// 血缘节点
+type LineageNode struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// 字段级血缘, 上游节点的字段流向下游节点的字段
+type LineageFieldMapping struct {
+	SourceField string `json:"source_field"`
+	TargetField string `json:"target_field"`
+}
+
+// 登记到 data-model 的血缘边, 下游节点为本体中的对象类或关系类
+type LineageEdge struct {
+	Source LineageNode           `json:"source"`
+	Target LineageNode           `json:"target"`
+	Fields []LineageFieldMapping `json:"fields"`
+}
+
 //go:generate mockgen -source ../interfaces/data_model_access.go -destination ../interfaces/mock/mock_data_model_access.go
 type DataModelAccess interface {
 	GetMetricModelByID(ctx context.Context, id string) (*MetricModel, error)
+
+	// 整体替换 owner 在 data-model 中登记的血缘边
+	ReplaceLineageEdges(ctx context.Context, owner LineageNode, edges []*LineageEdge) error
+	DeleteLineageEdges(ctx context.Context, owner LineageNode) error
 }
@@ -40,6 +40,20 @@ func (m *MockDataModelAccess) EXPECT() *MockDataModelAccessMockRecorder {
 	return m.recorder
 }
 
+// DeleteLineageEdges mocks base method.
+func (m *MockDataModelAccess) DeleteLineageEdges(ctx context.Context, owner interfaces.LineageNode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLineageEdges", ctx, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLineageEdges indicates an expected call of DeleteLineageEdges.
+func (mr *MockDataModelAccessMockRecorder) DeleteLineageEdges(ctx, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLineageEdges", reflect.TypeOf((*MockDataModelAccess)(nil).DeleteLineageEdges), ctx, owner)
+}
+
 // GetMetricModelByID mocks base method.
 func (m *MockDataModelAccess) GetMetricModelByID(ctx context.Context, id string) (*interfaces.MetricModel, error) {
 	m.ctrl.T.Helper()
@@ -54,3 +68,17 @@ func (mr *MockDataModelAccessMockRecorder) GetMetricModelByID(ctx, id interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricModelByID", reflect.TypeOf((*MockDataModelAccess)(nil).GetMetricModelByID), ctx, id)
 }
+
+// ReplaceLineageEdges mocks base method.
+func (m *MockDataModelAccess) ReplaceLineageEdges(ctx context.Context, owner interfaces.LineageNode, edges []*interfaces.LineageEdge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceLineageEdges", ctx, owner, edges)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceLineageEdges indicates an expected call of ReplaceLineageEdges.
+func (mr *MockDataModelAccessMockRecorder) ReplaceLineageEdges(ctx, owner, edges interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceLineageEdges", reflect.TypeOf((*MockDataModelAccess)(nil).ReplaceLineageEdges), ctx, owner, edges)
+}
@@ -164,6 +164,8 @@ func (cgs *conceptGroupService) CreateConceptGroup(ctx context.Context, tx *sql.
 
 	if tx == nil {
 		// 0. 开始事务
+		var afterCommit *common.AfterCommitHooks
+		ctx, afterCommit = common.WithAfterCommitHooks(ctx)
 		tx, err = cgs.db.Begin()
 		if err != nil {
 			logger.Errorf("Begin transaction error: %s", err.Error())
@@ -189,6 +191,7 @@ func (cgs *conceptGroupService) CreateConceptGroup(ctx context.Context, tx *sql.
 				}
 				logger.Infof("CreateConceptGroup Transaction Commit Success")
 				o11y.Debug(ctx, "CreateConceptGroup Transaction Commit Success")
+				afterCommit.Run()
 			default:
 				rollbackErr := tx.Rollback()
 				if rollbackErr != nil {
@@ -165,6 +165,7 @@ func (kns *knowledgeNetworkService) CreateKN(ctx context.Context, kn *interfaces
 	kn.UpdateTime = currentTime
 
 	// 0. 开始事务
+	ctx, afterCommit := common.WithAfterCommitHooks(ctx)
 	tx, err := kns.db.Begin()
 	if err != nil {
 		logger.Errorf("Begin transaction error: %s", err.Error())
@@ -190,6 +191,7 @@ func (kns *knowledgeNetworkService) CreateKN(ctx context.Context, kn *interfaces
 			}
 			logger.Infof("CreateKN Transaction Commit Success")
 			o11y.Debug(ctx, "CreateKN Transaction Commit Success")
+			afterCommit.Run()
 		default:
 			rollbackErr := tx.Rollback()
 			if rollbackErr != nil {
@@ -752,6 +754,7 @@ func (kns *knowledgeNetworkService) DeleteKN(ctx context.Context, kn *interfaces
 	}
 
 	// 0. 开始事务
+	ctx, afterCommit := common.WithAfterCommitHooks(ctx)
 	tx, err := kns.db.Begin()
 	if err != nil {
 		logger.Errorf("Begin transaction error: %s", err.Error())
@@ -777,6 +780,7 @@ func (kns *knowledgeNetworkService) DeleteKN(ctx context.Context, kn *interfaces
 			}
 			logger.Infof("DeleteKN Transaction Commit Success")
 			o11y.Debug(ctx, "DeleteKN Transaction Commit Success")
+			afterCommit.Run()
 		default:
 			rollbackErr := tx.Rollback()
 			if rollbackErr != nil {
@@ -162,6 +162,8 @@ func (ots *objectTypeService) CreateObjectTypes(ctx context.Context, tx *sql.Tx,
 
 	// 0. 开始事务
 	if tx == nil {
+		var afterCommit *common.AfterCommitHooks
+		ctx, afterCommit = common.WithAfterCommitHooks(ctx)
 		tx, err = ots.db.Begin()
 		if err != nil {
 			logger.Errorf("Begin transaction error: %s", err.Error())
@@ -185,6 +187,7 @@ func (ots *objectTypeService) CreateObjectTypes(ctx context.Context, tx *sql.Tx,
 				}
 				logger.Infof("CreateObjectType Transaction Commit Success")
 				o11y.Debug(ctx, "CreateObjectType Transaction Commit Success")
+				afterCommit.Run()
 			default:
 				rollbackErr := tx.Rollback()
 				if rollbackErr != nil {
@@ -259,6 +262,11 @@ func (ots *objectTypeService) CreateObjectTypes(ctx context.Context, tx *sql.Tx,
 			WithErrorDetails(err.Error())
 	}
 
+	// 向 data-model 登记新建对象类的血缘, 更新的对象类已在 UpdateObjectType 中登记
+	for _, objectType := range createObjectTypes {
+		ots.reportObjectTypeLineage(ctx, objectType)
+	}
+
 	span.SetStatus(codes.Ok, "")
 	return otIDs, nil
 }
@@ -678,6 +686,8 @@ func (ots *objectTypeService) UpdateObjectType(ctx context.Context, tx *sql.Tx,
 
 	if tx == nil {
 		// 0. 开始事务
+		var afterCommit *common.AfterCommitHooks
+		ctx, afterCommit = common.WithAfterCommitHooks(ctx)
 		tx, err = ots.db.Begin()
 		if err != nil {
 			logger.Errorf("Begin transaction error: %s", err.Error())
@@ -698,9 +708,11 @@ func (ots *objectTypeService) UpdateObjectType(ctx context.Context, tx *sql.Tx,
 					logger.Errorf("UpdateObjectType Transaction Commit Failed:%v", err)
 					span.SetStatus(codes.Error, "提交事务失败")
 					o11y.Error(ctx, fmt.Sprintf("UpdateObjectType Transaction Commit Failed: %s", err.Error()))
+					return
 				}
 				logger.Infof("UpdateObjectType Transaction Commit Success:%v", objectType.OTName)
 				o11y.Debug(ctx, fmt.Sprintf("UpdateObjectType Transaction Commit Success: %s", objectType.OTName))
+				afterCommit.Run()
 			default:
 				rollbackErr := tx.Rollback()
 				if rollbackErr != nil {
@@ -770,6 +782,8 @@ func (ots *objectTypeService) UpdateObjectType(ctx context.Context, tx *sql.Tx,
 			WithErrorDetails(err.Error())
 	}
 
+	ots.reportObjectTypeLineage(ctx, objectType)
+
 	span.SetStatus(codes.Ok, "")
 	return nil
 }
@@ -863,6 +877,8 @@ func (ots *objectTypeService) UpdateDataProperties(ctx context.Context,
 
 	// 0. 开始事务
 	var tx *sql.Tx
+	var afterCommit *common.AfterCommitHooks
+	ctx, afterCommit = common.WithAfterCommitHooks(ctx)
 	tx, err = ots.db.Begin()
 	if err != nil {
 		logger.Errorf("Begin transaction error: %s", err.Error())
@@ -883,9 +899,11 @@ func (ots *objectTypeService) UpdateDataProperties(ctx context.Context,
 				logger.Errorf("UpdateObjectType Transaction Commit Failed:%v", err)
 				span.SetStatus(codes.Error, "提交事务失败")
 				o11y.Error(ctx, fmt.Sprintf("UpdateObjectType Transaction Commit Failed: %s", err.Error()))
+				return
 			}
 			logger.Infof("UpdateObjectType Transaction Commit Success:%v", objectType.OTName)
 			o11y.Debug(ctx, fmt.Sprintf("UpdateObjectType Transaction Commit Success: %s", objectType.OTName))
+			afterCommit.Run()
 		default:
 			rollbackErr := tx.Rollback()
 			if rollbackErr != nil {
@@ -941,6 +959,8 @@ func (ots *objectTypeService) UpdateDataProperties(ctx context.Context,
 			WithErrorDetails(err.Error())
 	}
 
+	ots.reportObjectTypeLineage(ctx, objectType)
+
 	span.SetStatus(codes.Ok, "")
 	return nil
 }
@@ -960,6 +980,8 @@ func (ots *objectTypeService) DeleteObjectTypesByIDs(ctx context.Context, tx *sq
 
 	if tx == nil {
 		// 0. 开始事务
+		var afterCommit *common.AfterCommitHooks
+		ctx, afterCommit = common.WithAfterCommitHooks(ctx)
 		tx, err = ots.db.Begin()
 		if err != nil {
 			logger.Errorf("Begin transaction error: %s", err.Error())
@@ -980,9 +1002,11 @@ func (ots *objectTypeService) DeleteObjectTypesByIDs(ctx context.Context, tx *sq
 					logger.Errorf("DeleteObjectTypes Transaction Commit Failed:%v", err)
 					span.SetStatus(codes.Error, "提交事务失败")
 					o11y.Error(ctx, fmt.Sprintf("DeleteObjectTypes Transaction Commit Failed: %s", err.Error()))
+					return
 				}
 				logger.Infof("DeleteObjectTypes Transaction Commit Success: kn_id:%s,ot_ids:%v", knID, otIDs)
 				o11y.Debug(ctx, fmt.Sprintf("DeleteObjectTypes Transaction Commit Success: kn_id:%s,ot_ids:%v", knID, otIDs))
+				afterCommit.Run()
 			default:
 				rollbackErr := tx.Rollback()
 				if rollbackErr != nil {
@@ -1052,6 +1076,8 @@ func (ots *objectTypeService) DeleteObjectTypesByIDs(ctx context.Context, tx *sq
 	logger.Infof("DeleteObjectTypesFromGroup success, the kn_id is [%s], branch is [%s], ot_ids is [%v], rowsAffect is [%d]",
 		knID, branch, otIDs, rowsAffect)
 
+	ots.removeObjectTypesLineage(ctx, knID, branch, otIDs)
+
 	span.SetStatus(codes.Ok, "")
 	return nil
 }
@@ -1070,6 +1096,13 @@ func (ots *objectTypeService) DeleteObjectTypesByKnID(ctx context.Context, tx *s
 			WithErrorDetails("missing transaction")
 	}
 
+	// 删除前记下对象类id, 用于清理登记的血缘
+	otIDs, err := ots.ota.GetObjectTypeIDsByKnID(ctx, knID, branch)
+	if err != nil {
+		logger.Warnf("GetObjectTypeIDsByKnID error: %s", err.Error())
+		o11y.Warn(ctx, fmt.Sprintf("GetObjectTypeIDsByKnID error: %s", err.Error()))
+	}
+
 	// 删除对象类
 	rowsAffect, err := ots.ota.DeleteObjectTypesByKnID(ctx, tx, knID, branch)
 	if err != nil {
@@ -1091,6 +1124,8 @@ func (ots *objectTypeService) DeleteObjectTypesByKnID(ctx context.Context, tx *s
 	// 记录info日志，删除的条数
 	logger.Infof("DeleteObjectTypesByKnID success, the kn_id is [%s], branch is [%s], rowsAffect is [%d]",
 		knID, branch, rowsAffect)
+
+	ots.removeObjectTypesLineage(ctx, knID, branch, otIDs)
 	span.SetStatus(codes.Ok, "")
 	return nil
 }
@@ -2058,3 +2093,94 @@ func (ots *objectTypeService) GetTotalWithOTIDs(ctx context.Context,
 
 	return total, nil
 }
+
+// 向 data-model 登记对象类的血缘, 只登记主分支. 血缘为旁路信息, 登记失败只记录日志, 不影响对象类的保存.
+// 登记在事务提交成功后执行, 事务回滚时不会留下血缘
+func (ots *objectTypeService) reportObjectTypeLineage(ctx context.Context, objectType *interfaces.ObjectType) {
+	if objectType.Branch != "" && objectType.Branch != interfaces.MAIN_BRANCH {
+		return
+	}
+
+	owner := interfaces.LineageNode{
+		Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE,
+		ID:   objectType.KNID + "/" + objectType.OTID,
+	}
+	edges := buildObjectTypeLineage(objectType, owner)
+	common.OnAfterCommit(ctx, func() {
+		err := ots.dda.ReplaceLineageEdges(ctx, owner, edges)
+		if err != nil {
+			logger.Warnf("Report lineage of object type [%s] failed: %s", owner.ID, err.Error())
+			o11y.Warn(ctx, fmt.Sprintf("Report lineage of object type [%s] failed: %s", owner.ID, err.Error()))
+		}
+	})
+}
+
+// 删除对象类在 data-model 中登记的血缘, 在事务提交成功后执行
+func (ots *objectTypeService) removeObjectTypesLineage(ctx context.Context, knID string, branch string, otIDs []string) {
+	if branch != "" && branch != interfaces.MAIN_BRANCH {
+		return
+	}
+
+	common.OnAfterCommit(ctx, func() {
+		for _, otID := range otIDs {
+			owner := interfaces.LineageNode{
+				Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE,
+				ID:   knID + "/" + otID,
+			}
+			err := ots.dda.DeleteLineageEdges(ctx, owner)
+			if err != nil {
+				logger.Warnf("Remove lineage of object type [%s] failed: %s", owner.ID, err.Error())
+				o11y.Warn(ctx, fmt.Sprintf("Remove lineage of object type [%s] failed: %s", owner.ID, err.Error()))
+			}
+		}
+	})
+}
+
+// 对象类的上游为数据来源视图和指标类逻辑属性引用的指标模型
+func buildObjectTypeLineage(objectType *interfaces.ObjectType, owner interfaces.LineageNode) []*interfaces.LineageEdge {
+	edges := []*interfaces.LineageEdge{}
+
+	// 只有数据来源为数据视图时, 数据来源 id 才是视图 id
+	if objectType.DataSource != nil && objectType.DataSource.Type == interfaces.DATA_SOURCE_TYPE_DATA_VIEW &&
+		objectType.DataSource.ID != "" {
+		fields := []interfaces.LineageFieldMapping{}
+		for _, prop := range objectType.DataProperties {
+			if prop.MappedField != nil && prop.MappedField.Name != "" {
+				fields = append(fields, interfaces.LineageFieldMapping{
+					SourceField: prop.MappedField.Name,
+					TargetField: prop.Name,
+				})
+			}
+		}
+		edges = append(edges, &interfaces.LineageEdge{
+			Source: interfaces.LineageNode{
+				Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW,
+				ID:   objectType.DataSource.ID,
+			},
+			Target: owner,
+			Fields: fields,
+		})
+	}
+
+	metricIDs := map[string]struct{}{}
+	for _, prop := range objectType.LogicProperties {
+		if prop.DataSource == nil || prop.DataSource.Type != interfaces.LOGIC_PROPERTY_TYPE_METRIC || prop.DataSource.ID == "" {
+			continue
+		}
+		if _, ok := metricIDs[prop.DataSource.ID]; ok {
+			continue
+		}
+		metricIDs[prop.DataSource.ID] = struct{}{}
+
+		edges = append(edges, &interfaces.LineageEdge{
+			Source: interfaces.LineageNode{
+				Type: interfaces.LINEAGE_NODE_TYPE_METRIC_MODEL,
+				ID:   prop.DataSource.ID,
+			},
+			Target: owner,
+			Fields: []interfaces.LineageFieldMapping{},
+		})
+	}
+
+	return edges
+}
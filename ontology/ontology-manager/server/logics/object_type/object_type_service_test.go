@@ -585,6 +585,7 @@ func Test_objectTypeService_CreateObjectTypes(t *testing.T) {
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		cga := dmock.NewMockConceptGroupAccess(mockCtrl)
 		osa := dmock.NewMockOpenSearchAccess(mockCtrl)
+		dda := dmock.NewMockDataModelAccess(mockCtrl)
 		db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 
 		service := &objectTypeService{
@@ -594,7 +595,9 @@ func Test_objectTypeService_CreateObjectTypes(t *testing.T) {
 			ps:         ps,
 			cga:        cga,
 			osa:        osa,
+			dda:        dda,
 		}
+		dda.EXPECT().ReplaceLineageEdges(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 		Convey("Success creating object types with normal mode\n", func() {
 			objectTypes := []*interfaces.ObjectType{
@@ -1079,6 +1082,7 @@ func Test_objectTypeService_UpdateObjectType(t *testing.T) {
 		cga := dmock.NewMockConceptGroupAccess(mockCtrl)
 		mfa := dmock.NewMockModelFactoryAccess(mockCtrl)
 		osa := dmock.NewMockOpenSearchAccess(mockCtrl)
+		dda := dmock.NewMockDataModelAccess(mockCtrl)
 		db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 
 		service := &objectTypeService{
@@ -1089,7 +1093,9 @@ func Test_objectTypeService_UpdateObjectType(t *testing.T) {
 			cga:        cga,
 			mfa:        mfa,
 			osa:        osa,
+			dda:        dda,
 		}
+		dda.EXPECT().ReplaceLineageEdges(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 		Convey("Success updating object type\n", func() {
 			objectType := &interfaces.ObjectType{
@@ -1209,6 +1215,7 @@ func Test_objectTypeService_UpdateDataProperties(t *testing.T) {
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		mfa := dmock.NewMockModelFactoryAccess(mockCtrl)
 		osa := dmock.NewMockOpenSearchAccess(mockCtrl)
+		dda := dmock.NewMockDataModelAccess(mockCtrl)
 		db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 
 		service := &objectTypeService{
@@ -1218,7 +1225,9 @@ func Test_objectTypeService_UpdateDataProperties(t *testing.T) {
 			ps:         ps,
 			mfa:        mfa,
 			osa:        osa,
+			dda:        dda,
 		}
+		dda.EXPECT().ReplaceLineageEdges(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 		Convey("Success updating data properties\n", func() {
 			objectType := &interfaces.ObjectType{
@@ -1369,6 +1378,7 @@ func Test_objectTypeService_DeleteObjectTypesByIDs(t *testing.T) {
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		cga := dmock.NewMockConceptGroupAccess(mockCtrl)
 		osa := dmock.NewMockOpenSearchAccess(mockCtrl)
+		dda := dmock.NewMockDataModelAccess(mockCtrl)
 		db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 
 		service := &objectTypeService{
@@ -1378,7 +1388,9 @@ func Test_objectTypeService_DeleteObjectTypesByIDs(t *testing.T) {
 			ps:         ps,
 			cga:        cga,
 			osa:        osa,
+			dda:        dda,
 		}
+		dda.EXPECT().DeleteLineageEdges(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 		Convey("Success deleting object types\n", func() {
 			knID := "kn1"
@@ -1472,6 +1484,62 @@ func Test_objectTypeService_DeleteObjectTypesByIDs(t *testing.T) {
 	})
 }
 
+func Test_objectTypeService_DeleteObjectTypesByIDs_Lineage(t *testing.T) {
+	Convey("Test lineage removal of DeleteObjectTypesByIDs\n", t, func() {
+		ctx := context.Background()
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		ota := dmock.NewMockObjectTypeAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		cga := dmock.NewMockConceptGroupAccess(mockCtrl)
+		osa := dmock.NewMockOpenSearchAccess(mockCtrl)
+		dda := dmock.NewMockDataModelAccess(mockCtrl)
+		db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+
+		service := &objectTypeService{
+			appSetting: &common.AppSetting{},
+			db:         db,
+			ota:        ota,
+			ps:         ps,
+			cga:        cga,
+			osa:        osa,
+			dda:        dda,
+		}
+
+		ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		ota.EXPECT().DeleteObjectTypesByIDs(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
+		ota.EXPECT().DeleteObjectTypeStatusByIDs(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
+		osa.EXPECT().DeleteData(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		cga.EXPECT().DeleteObjectTypesFromGroup(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
+
+		Convey("Lineage is removed after the transaction commits\n", func() {
+			smock.ExpectBegin()
+			smock.ExpectCommit()
+			dda.EXPECT().DeleteLineageEdges(gomock.Any(), interfaces.LineageNode{
+				Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE,
+				ID:   "kn1/ot1",
+			}).DoAndReturn(func(ctx context.Context, owner interfaces.LineageNode) error {
+				// 调用血缘接口时事务已提交
+				So(smock.ExpectationsWereMet(), ShouldBeNil)
+				return nil
+			})
+
+			err := service.DeleteObjectTypesByIDs(ctx, nil, "kn1", interfaces.MAIN_BRANCH, []string{"ot1"})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Lineage is kept when the transaction commit fails\n", func() {
+			smock.ExpectBegin()
+			smock.ExpectCommit().WillReturnError(rest.NewHTTPError(ctx, 500, oerrors.OntologyManager_ObjectType_InternalError))
+			dda.EXPECT().DeleteLineageEdges(gomock.Any(), gomock.Any()).Times(0)
+
+			_ = service.DeleteObjectTypesByIDs(ctx, nil, "kn1", interfaces.MAIN_BRANCH, []string{"ot1"})
+			So(smock.ExpectationsWereMet(), ShouldBeNil)
+		})
+	})
+}
+
 func Test_objectTypeService_GetObjectTypesMapByIDs(t *testing.T) {
 	Convey("Test GetObjectTypesMapByIDs\n", t, func() {
 		ctx := context.Background()
@@ -3143,3 +3211,61 @@ func Test_objectTypeService_syncObjectGroups(t *testing.T) {
 		})
 	})
 }
+
+func Test_objectTypeService_buildObjectTypeLineage(t *testing.T) {
+	Convey("Test buildObjectTypeLineage\n", t, func() {
+		owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE, ID: "kn1/ot1"}
+
+		Convey("Data view and metric logic properties\n", func() {
+			objectType := &interfaces.ObjectType{
+				ObjectTypeWithKeyField: interfaces.ObjectTypeWithKeyField{
+					OTID:       "ot1",
+					DataSource: &interfaces.ResourceInfo{Type: interfaces.DATA_SOURCE_TYPE_DATA_VIEW, ID: "v1"},
+					DataProperties: []*interfaces.DataProperty{
+						{Name: "id", MappedField: &interfaces.Field{Name: "f_id"}},
+						{Name: "name"},
+					},
+					LogicProperties: []*interfaces.LogicProperty{
+						{Name: "cpu", DataSource: &interfaces.ResourceInfo{Type: interfaces.LOGIC_PROPERTY_TYPE_METRIC, ID: "m1"}},
+						{Name: "cpu2", DataSource: &interfaces.ResourceInfo{Type: interfaces.LOGIC_PROPERTY_TYPE_METRIC, ID: "m1"}},
+						{Name: "op", DataSource: &interfaces.ResourceInfo{Type: interfaces.LOGIC_PROPERTY_TYPE_OPERATOR, ID: "op1"}},
+					},
+				},
+				KNID: "kn1",
+			}
+
+			edges := buildObjectTypeLineage(objectType, owner)
+			So(len(edges), ShouldEqual, 2)
+			So(edges[0].Source, ShouldResemble, interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, ID: "v1"})
+			So(edges[0].Target, ShouldResemble, owner)
+			So(edges[0].Fields, ShouldResemble, []interfaces.LineageFieldMapping{{SourceField: "f_id", TargetField: "id"}})
+			So(edges[1].Source, ShouldResemble, interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_METRIC_MODEL, ID: "m1"})
+		})
+
+		Convey("Object type without data source\n", func() {
+			objectType := &interfaces.ObjectType{
+				ObjectTypeWithKeyField: interfaces.ObjectTypeWithKeyField{OTID: "ot1"},
+				KNID:                   "kn1",
+			}
+
+			edges := buildObjectTypeLineage(objectType, owner)
+			So(len(edges), ShouldEqual, 0)
+		})
+
+		Convey("Data source is not a data view\n", func() {
+			objectType := &interfaces.ObjectType{
+				ObjectTypeWithKeyField: interfaces.ObjectTypeWithKeyField{
+					OTID:       "ot1",
+					DataSource: &interfaces.ResourceInfo{Type: "resource", ID: "r1"},
+					DataProperties: []*interfaces.DataProperty{
+						{Name: "id", MappedField: &interfaces.Field{Name: "f_id"}},
+					},
+				},
+				KNID: "kn1",
+			}
+
+			edges := buildObjectTypeLineage(objectType, owner)
+			So(len(edges), ShouldEqual, 0)
+		})
+	})
+}
@@ -42,6 +42,7 @@ type relationTypeService struct {
 	appSetting *common.AppSetting
 	db         *sql.DB
 	cga        interfaces.ConceptGroupAccess
+	dda        interfaces.DataModelAccess
 	dva        interfaces.DataViewAccess
 	mfa        interfaces.ModelFactoryAccess
 	osa        interfaces.OpenSearchAccess
@@ -57,6 +58,7 @@ func NewRelationTypeService(appSetting *common.AppSetting) interfaces.RelationTy
 			appSetting: appSetting,
 			db:         logics.DB,
 			cga:        logics.CGA,
+			dda:        logics.DDA,
 			dva:        logics.DVA,
 			mfa:        logics.MFA,
 			osa:        logics.OSA,
@@ -124,6 +126,8 @@ func (rts *relationTypeService) CreateRelationTypes(ctx context.Context, tx *sql
 
 	// 0. 开始事务
 	if tx == nil {
+		var afterCommit *common.AfterCommitHooks
+		ctx, afterCommit = common.WithAfterCommitHooks(ctx)
 		tx, err = rts.db.Begin()
 		if err != nil {
 			logger.Errorf("Begin transaction error: %s", err.Error())
@@ -147,6 +151,7 @@ func (rts *relationTypeService) CreateRelationTypes(ctx context.Context, tx *sql
 				}
 				logger.Infof("CreateRelationType Transaction Commit Success")
 				o11y.Debug(ctx, "CreateRelationType Transaction Commit Success")
+				afterCommit.Run()
 			default:
 				rollbackErr := tx.Rollback()
 				if rollbackErr != nil {
@@ -220,6 +225,11 @@ func (rts *relationTypeService) CreateRelationTypes(ctx context.Context, tx *sql
 			WithErrorDetails(err.Error())
 	}
 
+	// 向 data-model 登记新建关系类的血缘, 更新的关系类已在 UpdateRelationType 中登记
+	for _, relationType := range createRelationTypes {
+		rts.reportRelationTypeLineage(ctx, relationType)
+	}
+
 	span.SetStatus(codes.Ok, "")
 	return rtIDs, nil
 }
@@ -490,6 +500,8 @@ func (rts *relationTypeService) UpdateRelationType(ctx context.Context, tx *sql.
 
 	if tx == nil {
 		// 0. 开始事务
+		var afterCommit *common.AfterCommitHooks
+		ctx, afterCommit = common.WithAfterCommitHooks(ctx)
 		tx, err = rts.db.Begin()
 		if err != nil {
 			logger.Errorf("Begin transaction error: %s", err.Error())
@@ -510,9 +522,11 @@ func (rts *relationTypeService) UpdateRelationType(ctx context.Context, tx *sql.
 					logger.Errorf("UpdateRelationType Transaction Commit Failed:%v", err)
 					span.SetStatus(codes.Error, "提交事务失败")
 					o11y.Error(ctx, fmt.Sprintf("UpdateRelationType Transaction Commit Failed: %s", err.Error()))
+					return
 				}
 				logger.Infof("UpdateRelationType Transaction Commit Success:%v", relationType.RTName)
 				o11y.Debug(ctx, fmt.Sprintf("UpdateRelationType Transaction Commit Success: %s", relationType.RTName))
+				afterCommit.Run()
 			default:
 				rollbackErr := tx.Rollback()
 				if rollbackErr != nil {
@@ -552,6 +566,8 @@ func (rts *relationTypeService) UpdateRelationType(ctx context.Context, tx *sql.
 			WithErrorDetails(err.Error())
 	}
 
+	rts.reportRelationTypeLineage(ctx, relationType)
+
 	span.SetStatus(codes.Ok, "")
 	return nil
 }
@@ -571,6 +587,8 @@ func (rts *relationTypeService) DeleteRelationTypesByIDs(ctx context.Context, tx
 
 	if tx == nil {
 		// 0. 开始事务
+		var afterCommit *common.AfterCommitHooks
+		ctx, afterCommit = common.WithAfterCommitHooks(ctx)
 		tx, err = rts.db.Begin()
 		if err != nil {
 			logger.Errorf("Begin transaction error: %s", err.Error())
@@ -591,9 +609,11 @@ func (rts *relationTypeService) DeleteRelationTypesByIDs(ctx context.Context, tx
 					logger.Errorf("DeleteRelationTypes Transaction Commit Failed:%v", err)
 					span.SetStatus(codes.Error, "提交事务失败")
 					o11y.Error(ctx, fmt.Sprintf("DeleteRelationTypes Transaction Commit Failed: %s", err.Error()))
+					return
 				}
 				logger.Infof("DeleteRelationTypes Transaction Commit Success: kn_id:%s,ot_ids:%v", knID, rtIDs)
 				o11y.Debug(ctx, fmt.Sprintf("DeleteRelationTypes Transaction Commit Success: kn_id:%s,ot_ids:%v", knID, rtIDs))
+				afterCommit.Run()
 			default:
 				rollbackErr := tx.Rollback()
 				if rollbackErr != nil {
@@ -629,6 +649,8 @@ func (rts *relationTypeService) DeleteRelationTypesByIDs(ctx context.Context, tx
 		}
 	}
 
+	rts.removeRelationTypesLineage(ctx, knID, branch, rtIDs)
+
 	span.SetStatus(codes.Ok, "")
 	return nil
 }
@@ -647,6 +669,13 @@ func (rts *relationTypeService) DeleteRelationTypesByKnID(ctx context.Context, t
 			WithErrorDetails("missing transaction")
 	}
 
+	// 删除前记下关系类id, 用于清理登记的血缘
+	rtIDs, err := rts.rta.GetRelationTypeIDsByKnID(ctx, knID, branch)
+	if err != nil {
+		logger.Warnf("GetRelationTypeIDsByKnID error: %s", err.Error())
+		o11y.Warn(ctx, fmt.Sprintf("GetRelationTypeIDsByKnID error: %s", err.Error()))
+	}
+
 	// 删除指标模型
 	rowsAffect, err := rts.rta.DeleteRelationTypesByKnID(ctx, tx, knID, branch)
 	if err != nil {
@@ -658,6 +687,8 @@ func (rts *relationTypeService) DeleteRelationTypesByKnID(ctx context.Context, t
 
 	logger.Infof("DeleteRelationTypesByKnID success, the kn_id is [%s], branch is [%s], rowsAffect is [%d]",
 		knID, branch, rowsAffect)
+
+	rts.removeRelationTypesLineage(ctx, knID, branch, rtIDs)
 	span.SetStatus(codes.Ok, "")
 	return nil
 }
@@ -1222,3 +1253,132 @@ func (rts *relationTypeService) validateDependency(ctx context.Context, tx *sql.
 	}
 	return nil
 }
+
+// 向 data-model 登记关系类的血缘, 只登记主分支. 血缘为旁路信息, 登记失败只记录日志, 不影响关系类的保存.
+// 登记在事务提交成功后执行, 事务回滚时不会留下血缘
+func (rts *relationTypeService) reportRelationTypeLineage(ctx context.Context, relationType *interfaces.RelationType) {
+	if relationType.Branch != "" && relationType.Branch != interfaces.MAIN_BRANCH {
+		return
+	}
+
+	owner := interfaces.LineageNode{
+		Type: interfaces.LINEAGE_NODE_TYPE_RELATION_TYPE,
+		ID:   relationType.KNID + "/" + relationType.RTID,
+	}
+	edges := buildRelationTypeLineage(relationType, owner)
+	common.OnAfterCommit(ctx, func() {
+		err := rts.dda.ReplaceLineageEdges(ctx, owner, edges)
+		if err != nil {
+			logger.Warnf("Report lineage of relation type [%s] failed: %s", owner.ID, err.Error())
+			o11y.Warn(ctx, fmt.Sprintf("Report lineage of relation type [%s] failed: %s", owner.ID, err.Error()))
+		}
+	})
+}
+
+// 删除关系类在 data-model 中登记的血缘, 在事务提交成功后执行
+func (rts *relationTypeService) removeRelationTypesLineage(ctx context.Context, knID string, branch string, rtIDs []string) {
+	if branch != "" && branch != interfaces.MAIN_BRANCH {
+		return
+	}
+
+	common.OnAfterCommit(ctx, func() {
+		for _, rtID := range rtIDs {
+			owner := interfaces.LineageNode{
+				Type: interfaces.LINEAGE_NODE_TYPE_RELATION_TYPE,
+				ID:   knID + "/" + rtID,
+			}
+			err := rts.dda.DeleteLineageEdges(ctx, owner)
+			if err != nil {
+				logger.Warnf("Remove lineage of relation type [%s] failed: %s", owner.ID, err.Error())
+				o11y.Warn(ctx, fmt.Sprintf("Remove lineage of relation type [%s] failed: %s", owner.ID, err.Error()))
+			}
+		}
+	})
+}
+
+// 关系类的上游为起点和终点对象类, 视图映射时还包括中间视图
+func buildRelationTypeLineage(relationType *interfaces.RelationType, owner interfaces.LineageNode) []*interfaces.LineageEdge {
+	sourceFields := []interfaces.LineageFieldMapping{}
+	targetFields := []interfaces.LineageFieldMapping{}
+	var viewEdge *interfaces.LineageEdge
+
+	switch rules := relationType.MappingRules.(type) {
+	case []interfaces.Mapping:
+		for _, m := range rules {
+			sourceFields = append(sourceFields, interfaces.LineageFieldMapping{
+				SourceField: m.SourceProp.Name,
+				TargetField: m.SourceProp.Name,
+			})
+			targetFields = append(targetFields, interfaces.LineageFieldMapping{
+				SourceField: m.TargetProp.Name,
+				TargetField: m.TargetProp.Name,
+			})
+		}
+	case interfaces.InDirectMapping:
+		viewFields := []interfaces.LineageFieldMapping{}
+		// 起点对象类属性映射到视图字段, 视图字段映射到终点对象类属性
+		for _, m := range rules.SourceMappingRules {
+			sourceFields = append(sourceFields, interfaces.LineageFieldMapping{
+				SourceField: m.SourceProp.Name,
+				TargetField: m.SourceProp.Name,
+			})
+			viewFields = append(viewFields, interfaces.LineageFieldMapping{
+				SourceField: m.TargetProp.Name,
+				TargetField: m.TargetProp.Name,
+			})
+		}
+		for _, m := range rules.TargetMappingRules {
+			viewFields = append(viewFields, interfaces.LineageFieldMapping{
+				SourceField: m.SourceProp.Name,
+				TargetField: m.SourceProp.Name,
+			})
+			targetFields = append(targetFields, interfaces.LineageFieldMapping{
+				SourceField: m.TargetProp.Name,
+				TargetField: m.TargetProp.Name,
+			})
+		}
+		if rules.BackingDataSource != nil && rules.BackingDataSource.Type == interfaces.DATA_SOURCE_TYPE_DATA_VIEW &&
+			rules.BackingDataSource.ID != "" {
+			viewEdge = &interfaces.LineageEdge{
+				Source: interfaces.LineageNode{
+					Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW,
+					ID:   rules.BackingDataSource.ID,
+				},
+				Target: owner,
+				Fields: viewFields,
+			}
+		}
+	}
+
+	edges := []*interfaces.LineageEdge{}
+	if relationType.SourceObjectTypeID != "" {
+		edges = append(edges, &interfaces.LineageEdge{
+			Source: interfaces.LineageNode{
+				Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE,
+				ID:   relationType.KNID + "/" + relationType.SourceObjectTypeID,
+			},
+			Target: owner,
+			Fields: sourceFields,
+		})
+	}
+	// 自关联时起点和终点为同一对象类, 合并到一条边
+	if relationType.TargetObjectTypeID != "" {
+		if relationType.TargetObjectTypeID == relationType.SourceObjectTypeID {
+			edges[0].Fields = append(edges[0].Fields, targetFields...)
+		} else {
+			edges = append(edges, &interfaces.LineageEdge{
+				Source: interfaces.LineageNode{
+					Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE,
+					ID:   relationType.KNID + "/" + relationType.TargetObjectTypeID,
+				},
+				Target: owner,
+				Fields: targetFields,
+			})
+		}
+	}
+	if viewEdge != nil {
+		edges = append(edges, viewEdge)
+	}
+
+	return edges
+}
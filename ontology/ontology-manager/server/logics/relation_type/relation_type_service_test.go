@@ -800,6 +800,7 @@ func Test_relationTypeService_CreateRelationTypes(t *testing.T) {
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		ots := dmock.NewMockObjectTypeService(mockCtrl)
 		osa := dmock.NewMockOpenSearchAccess(mockCtrl)
+		dda := dmock.NewMockDataModelAccess(mockCtrl)
 		db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 
 		service := &relationTypeService{
@@ -809,7 +810,9 @@ func Test_relationTypeService_CreateRelationTypes(t *testing.T) {
 			ps:         ps,
 			ots:        ots,
 			osa:        osa,
+			dda:        dda,
 		}
+		dda.EXPECT().ReplaceLineageEdges(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 		Convey("Success creating relation types with normal mode\n", func() {
 			relationTypes := []*interfaces.RelationType{
@@ -1059,6 +1062,7 @@ func Test_relationTypeService_UpdateRelationType(t *testing.T) {
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		ots := dmock.NewMockObjectTypeService(mockCtrl)
 		osa := dmock.NewMockOpenSearchAccess(mockCtrl)
+		dda := dmock.NewMockDataModelAccess(mockCtrl)
 		db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 
 		service := &relationTypeService{
@@ -1068,7 +1072,9 @@ func Test_relationTypeService_UpdateRelationType(t *testing.T) {
 			ps:         ps,
 			ots:        ots,
 			osa:        osa,
+			dda:        dda,
 		}
+		dda.EXPECT().ReplaceLineageEdges(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 		Convey("Success updating relation type\n", func() {
 			relationType := &interfaces.RelationType{
@@ -1185,6 +1191,7 @@ func Test_relationTypeService_DeleteRelationTypesByIDs(t *testing.T) {
 		rta := dmock.NewMockRelationTypeAccess(mockCtrl)
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		osa := dmock.NewMockOpenSearchAccess(mockCtrl)
+		dda := dmock.NewMockDataModelAccess(mockCtrl)
 		db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 
 		service := &relationTypeService{
@@ -1193,7 +1200,9 @@ func Test_relationTypeService_DeleteRelationTypesByIDs(t *testing.T) {
 			rta:        rta,
 			ps:         ps,
 			osa:        osa,
+			dda:        dda,
 		}
+		dda.EXPECT().DeleteLineageEdges(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 		Convey("Success deleting relation types\n", func() {
 			knID := "kn1"
@@ -1970,3 +1979,91 @@ func Test_relationTypeService_validateDependency(t *testing.T) {
 		})
 	})
 }
+
+func Test_relationTypeService_buildRelationTypeLineage(t *testing.T) {
+	Convey("Test buildRelationTypeLineage\n", t, func() {
+		owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_RELATION_TYPE, ID: "kn1/rt1"}
+
+		Convey("Direct mapping\n", func() {
+			relationType := &interfaces.RelationType{
+				RelationTypeWithKeyField: interfaces.RelationTypeWithKeyField{
+					RTID:               "rt1",
+					SourceObjectTypeID: "ot1",
+					TargetObjectTypeID: "ot2",
+					Type:               interfaces.RELATION_TYPE_DIRECT,
+					MappingRules: []interfaces.Mapping{
+						{
+							SourceProp: interfaces.SimpleProperty{Name: "id"},
+							TargetProp: interfaces.SimpleProperty{Name: "owner_id"},
+						},
+					},
+				},
+				KNID: "kn1",
+			}
+
+			edges := buildRelationTypeLineage(relationType, owner)
+			So(len(edges), ShouldEqual, 2)
+			So(edges[0].Source, ShouldResemble, interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE, ID: "kn1/ot1"})
+			So(edges[0].Fields, ShouldResemble, []interfaces.LineageFieldMapping{{SourceField: "id", TargetField: "id"}})
+			So(edges[1].Source, ShouldResemble, interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE, ID: "kn1/ot2"})
+			So(edges[1].Fields, ShouldResemble, []interfaces.LineageFieldMapping{{SourceField: "owner_id", TargetField: "owner_id"}})
+		})
+
+		Convey("Data view mapping on the same object type\n", func() {
+			relationType := &interfaces.RelationType{
+				RelationTypeWithKeyField: interfaces.RelationTypeWithKeyField{
+					RTID:               "rt1",
+					SourceObjectTypeID: "ot1",
+					TargetObjectTypeID: "ot1",
+					Type:               interfaces.RELATION_TYPE_DATA_VIEW,
+					MappingRules: interfaces.InDirectMapping{
+						BackingDataSource: &interfaces.ResourceInfo{Type: interfaces.RELATION_TYPE_DATA_VIEW, ID: "v1"},
+						SourceMappingRules: []interfaces.Mapping{
+							{
+								SourceProp: interfaces.SimpleProperty{Name: "id"},
+								TargetProp: interfaces.SimpleProperty{Name: "parent_id"},
+							},
+						},
+						TargetMappingRules: []interfaces.Mapping{
+							{
+								SourceProp: interfaces.SimpleProperty{Name: "child_id"},
+								TargetProp: interfaces.SimpleProperty{Name: "id"},
+							},
+						},
+					},
+				},
+				KNID: "kn1",
+			}
+
+			edges := buildRelationTypeLineage(relationType, owner)
+			So(len(edges), ShouldEqual, 2)
+			So(edges[0].Source, ShouldResemble, interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE, ID: "kn1/ot1"})
+			So(len(edges[0].Fields), ShouldEqual, 2)
+			So(edges[1].Source, ShouldResemble, interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, ID: "v1"})
+			So(edges[1].Fields, ShouldResemble, []interfaces.LineageFieldMapping{
+				{SourceField: "parent_id", TargetField: "parent_id"},
+				{SourceField: "child_id", TargetField: "child_id"},
+			})
+		})
+
+		Convey("Backing data source is not a data view\n", func() {
+			relationType := &interfaces.RelationType{
+				RelationTypeWithKeyField: interfaces.RelationTypeWithKeyField{
+					RTID:               "rt1",
+					SourceObjectTypeID: "ot1",
+					TargetObjectTypeID: "ot2",
+					Type:               interfaces.RELATION_TYPE_DATA_VIEW,
+					MappingRules: interfaces.InDirectMapping{
+						BackingDataSource: &interfaces.ResourceInfo{Type: "resource", ID: "r1"},
+					},
+				},
+				KNID: "kn1",
+			}
+
+			edges := buildRelationTypeLineage(relationType, owner)
+			So(len(edges), ShouldEqual, 2)
+			So(edges[0].Source.Type, ShouldEqual, interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE)
+			So(edges[1].Source.Type, ShouldEqual, interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE)
+		})
+	})
+}
@@ -104,6 +104,7 @@ const (
 	OperationNotExist    = "not_exist"
 	OperationEmpty       = "empty"
 	OperationNotEmpty    = "not_empty"
+	OperationNotNull     = "not_null"
 	OperationRegex       = "regex"
 	OperationMatch       = "match"
 	OperationMatchPhrase = "match_phrase"
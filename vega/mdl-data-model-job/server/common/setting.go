@@ -7,6 +7,7 @@ package common
 
 import (
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -21,19 +22,24 @@ import (
 
 // server配置项
 type ServerSetting struct {
-	RunMode              string        `mapstructure:"runMode"`
-	HttpPort             int           `mapstructure:"httpPort"`
-	Language             string        `mapstructure:"language"`
-	ReadTimeOut          time.Duration `mapstructure:"readTimeOut"`
-	WriteTimeout         time.Duration `mapstructure:"writeTimeOut"`
-	RetryIntervalMs      int           `mapstructure:"retryIntervalMs"`
-	FlushMiB             int           `mapstructure:"flushMiB"`
-	FlushItems           int           `mapstructure:"flushItems"`
-	FlushIntervalSec     int           `mapstructure:"flushIntervalSec"`
-	FailureThreshold     int           `mapstructure:"failureThreshold"`
-	PackagePoolSize      int           `mapstructure:"packagePoolSize"`
-	ConnectCheckTimeout  time.Duration `mapstructure:"connectCheckTimeout"`
-	WatchJobsIntervalMin time.Duration `mapstructure:"watchJobsIntervalMin"`
+	RunMode               string        `mapstructure:"runMode"`
+	HttpPort              int           `mapstructure:"httpPort"`
+	Language              string        `mapstructure:"language"`
+	ReadTimeOut           time.Duration `mapstructure:"readTimeOut"`
+	WriteTimeout          time.Duration `mapstructure:"writeTimeOut"`
+	RetryIntervalMs       int           `mapstructure:"retryIntervalMs"`
+	FlushMiB              int           `mapstructure:"flushMiB"`
+	FlushItems            int           `mapstructure:"flushItems"`
+	FlushIntervalSec      int           `mapstructure:"flushIntervalSec"`
+	FailureThreshold      int           `mapstructure:"failureThreshold"`
+	PackagePoolSize       int           `mapstructure:"packagePoolSize"`
+	ConnectCheckTimeout   time.Duration `mapstructure:"connectCheckTimeout"`
+	WatchJobsIntervalMin  time.Duration `mapstructure:"watchJobsIntervalMin"`
+	LeaseTTLSec           int           `mapstructure:"leaseTTLSec"`
+	LeaseRenewIntervalSec int           `mapstructure:"leaseRenewIntervalSec"`
+	BackfillChunkSize     int           `mapstructure:"backfillChunkSize"`
+	BackfillIntervalMs    int           `mapstructure:"backfillIntervalMs"`
+	BackfillMaxRunning    int           `mapstructure:"backfillMaxRunning"`
 }
 
 // Kafka配置
@@ -74,6 +80,11 @@ type AppSetting struct {
 	EventTaskUrl string
 	//event-model url
 	EventModelUrl string
+	// data-dict url
+	DataDictUrl string
+
+	// 当前副本标识, 作为任务租约的持有者
+	ReplicaID string
 }
 
 const (
@@ -157,6 +168,10 @@ func loadSetting(vp *viper.Viper) {
 
 	SetEventModelSetting()
 
+	SetDataDictSetting()
+
+	SetReplicaID()
+
 	s, _ := sonic.MarshalString(appSetting)
 	logger.Debug(s)
 }
@@ -275,3 +290,30 @@ func SetEventModelSetting() {
 
 	appSetting.EventModelUrl = fmt.Sprintf("%s://%s:%d/api/mdl-data-model/in/v1/event-models", protocol, host, port)
 }
+
+func SetDataDictSetting() {
+	setting, ok := appSetting.DepServices[dataModelServiceName]
+	if !ok {
+		logger.Fatalf("service %s not found in depServices", dataModelServiceName)
+	}
+
+	protocol := setting["protocol"].(string)
+	host := setting["host"].(string)
+	port := setting["port"].(int)
+
+	appSetting.DataDictUrl = fmt.Sprintf("%s://%s:%d/api/mdl-data-model/in/v1/data-dicts", protocol, host, port)
+}
+
+// 副本标识优先取 POD_NAME, 未设置时取主机名
+func SetReplicaID() {
+	replicaID := os.Getenv("POD_NAME")
+	if replicaID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			logger.Fatalf("get hostname failed, %v", err)
+		}
+		replicaID = hostname
+	}
+
+	appSetting.ReplicaID = replicaID
+}
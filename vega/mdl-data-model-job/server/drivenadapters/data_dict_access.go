@@ -0,0 +1,88 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package drivenadapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+
+	"data-model-job/common"
+	"data-model-job/interfaces"
+)
+
+var (
+	ddAccessOnce sync.Once
+	ddAccess     interfaces.DataDictAccess
+)
+
+type dataDictAccess struct {
+	dataDictUrl string
+	httpClient  rest.HTTPClient
+}
+
+func NewDataDictAccess(appSetting *common.AppSetting) interfaces.DataDictAccess {
+	ddAccessOnce.Do(func() {
+		ddAccess = &dataDictAccess{
+			dataDictUrl: appSetting.DataDictUrl,
+			httpClient:  common.NewHTTPClient(),
+		}
+	})
+
+	return ddAccess
+}
+
+// 获取数据字典及其字典项
+func (dda *dataDictAccess) GetDataDict(ctx context.Context, dictID string) (interfaces.DataDict, error) {
+	url := fmt.Sprintf("%s/%s", dda.dataDictUrl, dictID)
+
+	accountInfo := interfaces.AccountInfo{}
+	if ctx.Value(interfaces.ACCOUNT_INFO_KEY) != nil {
+		accountInfo = ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo)
+	}
+	headers := map[string]string{
+		interfaces.CONTENT_TYPE_NAME:        interfaces.CONTENT_TYPE_JSON,
+		interfaces.HTTP_HEADER_ACCOUNT_ID:   accountInfo.ID,
+		interfaces.HTTP_HEADER_ACCOUNT_TYPE: accountInfo.Type,
+	}
+	respCode, respData, err := dda.httpClient.GetNoUnmarshal(ctx, url, nil, headers)
+	logger.Debugf("get [%s] with headers[%v] finished, response code is [%d], error is [%v]",
+		url, headers, respCode, err)
+	if err != nil {
+		logger.Errorf("Get data dict by dictID '%s' failed, %s", dictID, err)
+		return interfaces.DataDict{}, err
+	}
+
+	if respCode != http.StatusOK {
+		// 转成 baseerror
+		var baseError rest.BaseError
+		if err := json.Unmarshal(respData, &baseError); err != nil {
+			logger.Errorf("unmalshal BaesError failed: %v\n", err)
+			return interfaces.DataDict{}, err
+		}
+		httpErr := &rest.HTTPError{HTTPCode: respCode, BaseError: baseError}
+		logger.Errorf("Get data dict return error: %v", httpErr.Error())
+
+		return interfaces.DataDict{}, fmt.Errorf("get data dict %s return error %v", dictID, httpErr.Error())
+	}
+
+	// 按 id 获取时返回数组
+	dicts := []interfaces.DataDict{}
+	if err := json.Unmarshal(respData, &dicts); err != nil {
+		logger.Errorf("Unmarshal data dict failed, %s", err)
+		return interfaces.DataDict{}, err
+	}
+	if len(dicts) == 0 {
+		return interfaces.DataDict{}, fmt.Errorf("data dict %s not found", dictID)
+	}
+
+	return dicts[0], nil
+}
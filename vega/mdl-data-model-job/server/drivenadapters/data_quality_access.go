@@ -0,0 +1,205 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package drivenadapters
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/bytedance/sonic"
+	libCommon "github.com/kweaver-ai/kweaver-go-lib/common"
+	libdb "github.com/kweaver-ai/kweaver-go-lib/db"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"data-model-job/common"
+	"data-model-job/interfaces"
+)
+
+const (
+	DATA_QUALITY_RULE_TABLE_NAME   = "t_data_quality_rule"
+	DATA_QUALITY_RESULT_TABLE_NAME = "t_data_quality_result"
+)
+
+var (
+	dqAccessOnce sync.Once
+	dqAccess     interfaces.DataQualityAccess
+)
+
+type dataQualityAccess struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+}
+
+func NewDataQualityAccess(appSetting *common.AppSetting) interfaces.DataQualityAccess {
+	dqAccessOnce.Do(func() {
+		dqAccess = &dataQualityAccess{
+			appSetting: appSetting,
+			db:         libdb.NewDB(&appSetting.DBSetting),
+		}
+	})
+
+	return dqAccess
+}
+
+// 查询启用的数据质量规则, 每条规则是一个定时任务, 任务id即规则id
+func (dqa *dataQualityAccess) ListDataQualityJobs(ctx context.Context) ([]interfaces.JobInfo, error) {
+	jobs := make([]interfaces.JobInfo, 0)
+	sqlStr, args, err := sq.Select(
+		"f_rule_id",
+		"f_rule_name",
+		"f_view_id",
+		"f_kind",
+		"f_config",
+		"f_schedule",
+		"f_emit_event",
+		"f_event_config",
+		"f_tags",
+		"f_update_time",
+		"f_creator",
+		"f_creator_type",
+		"f_last_status").
+		From(DATA_QUALITY_RULE_TABLE_NAME).
+		Where(sq.Eq{"f_enabled": 1}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'list data quality rules' sql stmt failed, %v", err)
+		return nil, err
+	}
+
+	rows, err := dqa.db.Query(sqlStr, args...)
+	if err != nil {
+		logger.Errorf("List data quality rules failed, %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			configStr, scheduleStr, tagsStr string
+			eventConfigStr                  sql.NullString
+		)
+
+		rule := interfaces.DataQualityRule{}
+		err := rows.Scan(
+			&rule.RuleID,
+			&rule.RuleName,
+			&rule.ViewID,
+			&rule.Kind,
+			&configStr,
+			&scheduleStr,
+			&rule.EmitEvent,
+			&eventConfigStr,
+			&tagsStr,
+			&rule.UpdateTime,
+			&rule.Creator.ID,
+			&rule.Creator.Type,
+			&rule.LastStatus,
+		)
+		if err != nil {
+			logger.Errorf("Row scan failed, err: %v", err)
+			return nil, err
+		}
+
+		err = sonic.UnmarshalString(configStr, &rule.Config)
+		if err != nil {
+			logger.Errorf("Unmarshal config of data quality rule '%s' failed, %v", rule.RuleID, err)
+			return nil, err
+		}
+		err = sonic.UnmarshalString(scheduleStr, &rule.Schedule)
+		if err != nil {
+			logger.Errorf("Unmarshal schedule of data quality rule '%s' failed, %v", rule.RuleID, err)
+			return nil, err
+		}
+		if eventConfigStr.Valid && eventConfigStr.String != "" {
+			rule.EventConfig = &interfaces.DataQualityEventCfg{}
+			err = sonic.UnmarshalString(eventConfigStr.String, rule.EventConfig)
+			if err != nil {
+				logger.Errorf("Unmarshal event config of data quality rule '%s' failed, %v", rule.RuleID, err)
+				return nil, err
+			}
+		}
+		rule.Tags = libCommon.TagString2TagSlice(tagsStr)
+
+		jobs = append(jobs, interfaces.JobInfo{
+			JobId:       rule.RuleID,
+			JobType:     interfaces.JOB_TYPE_SCHEDULE,
+			ModuleType:  interfaces.MODULE_TYPE_DATA_QUALITY_RULE,
+			UpdateTime:  rule.UpdateTime,
+			Creator:     rule.Creator,
+			Schedule:    rule.Schedule,
+			QualityRule: &rule,
+		})
+	}
+
+	return jobs, nil
+}
+
+// 写入一次检查结果
+func (dqa *dataQualityAccess) CreateDataQualityResult(ctx context.Context, result interfaces.DataQualityResult) error {
+	detailsStr, err := sonic.MarshalString(result.Details)
+	if err != nil {
+		logger.Errorf("Marshal data quality result details failed, %v", err)
+		return err
+	}
+
+	sqlStr, args, err := sq.Insert(DATA_QUALITY_RESULT_TABLE_NAME).
+		Columns(
+			"f_rule_id",
+			"f_view_id",
+			"f_check_time",
+			"f_status",
+			"f_value",
+			"f_details",
+			"f_error",
+		).
+		Values(
+			result.RuleID,
+			result.ViewID,
+			result.CheckTime,
+			result.Status,
+			result.Value,
+			detailsStr,
+			result.Error,
+		).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'insert data quality result' sql stmt failed, %v", err)
+		return err
+	}
+
+	if _, err = dqa.db.Exec(sqlStr, args...); err != nil {
+		logger.Errorf("Insert result of data quality rule '%s' failed, %v", result.RuleID, err)
+		return err
+	}
+
+	return nil
+}
+
+// 回写规则最近一次检查的状态
+func (dqa *dataQualityAccess) UpdateDataQualityRuleStatus(ctx context.Context, ruleID string,
+	status string, checkTime int64) error {
+
+	sqlStr, args, err := sq.Update(DATA_QUALITY_RULE_TABLE_NAME).
+		SetMap(map[string]any{
+			"f_last_status":     status,
+			"f_last_check_time": checkTime,
+		}).
+		Where(sq.Eq{"f_rule_id": ruleID}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'update data quality rule status' sql stmt failed, %v", err)
+		return err
+	}
+
+	if _, err = dqa.db.Exec(sqlStr, args...); err != nil {
+		logger.Errorf("Update status of data quality rule '%s' failed, %v", ruleID, err)
+		return err
+	}
+
+	return nil
+}
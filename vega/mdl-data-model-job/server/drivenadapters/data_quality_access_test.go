@@ -0,0 +1,155 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package drivenadapters
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model-job/interfaces"
+)
+
+func MockNewDataQualityAccess() (*dataQualityAccess, sqlmock.Sqlmock) {
+	db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	dqa := &dataQualityAccess{
+		db: db,
+	}
+	return dqa, smock
+}
+
+func Test_DataQualityAccess_ListDataQualityJobs(t *testing.T) {
+	Convey("Test ListDataQualityJobs", t, func() {
+		dqa, smock := MockNewDataQualityAccess()
+
+		sqlStr := fmt.Sprintf("SELECT f_rule_id, f_rule_name, f_view_id, f_kind, f_config, f_schedule, "+
+			"f_emit_event, f_event_config, f_tags, f_update_time, f_creator, f_creator_type, f_last_status "+
+			"FROM %s WHERE f_enabled = ?", DATA_QUALITY_RULE_TABLE_NAME)
+		columns := []string{"f_rule_id", "f_rule_name", "f_view_id", "f_kind", "f_config", "f_schedule",
+			"f_emit_event", "f_event_config", "f_tags", "f_update_time", "f_creator", "f_creator_type", "f_last_status"}
+		schedule := `{"type":"FIX_RATE","expression":"5m"}`
+
+		Convey("List failed, caused by query error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(sqlStr).WithArgs(1).WillReturnError(expectedErr)
+
+			_, err := dqa.ListDataQualityJobs(testCtx)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("List failed, caused by unmarshal config error", func() {
+			rows := sqlmock.NewRows(columns).AddRow("r1", "rule1", "v1", interfaces.DATA_QUALITY_KIND_NOT_NULL,
+				`{"fields":`, schedule, false, nil, "", 1000, "u1", "user", "")
+			smock.ExpectQuery(sqlStr).WithArgs(1).WillReturnRows(rows)
+
+			_, err := dqa.ListDataQualityJobs(testCtx)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("List succeed", func() {
+			rows := sqlmock.NewRows(columns).
+				AddRow("r1", "rule1", "v1", interfaces.DATA_QUALITY_KIND_NOT_NULL, `{"fields":["a"]}`, schedule,
+					false, nil, "", 1000, "u1", "user", "").
+				AddRow("r2", "rule2", "v1", interfaces.DATA_QUALITY_KIND_ROW_COUNT, `{"min_count":10}`, schedule,
+					true, `{"index_base":"base1","level":2}`, `"t1"`, 2000, "u1", "user", interfaces.DATA_QUALITY_STATUS_FAILED)
+			smock.ExpectQuery(sqlStr).WithArgs(1).WillReturnRows(rows)
+
+			jobs, err := dqa.ListDataQualityJobs(testCtx)
+			So(err, ShouldBeNil)
+			So(len(jobs), ShouldEqual, 2)
+
+			So(jobs[0].JobId, ShouldEqual, "r1")
+			So(jobs[0].JobType, ShouldEqual, interfaces.JOB_TYPE_SCHEDULE)
+			So(jobs[0].ModuleType, ShouldEqual, interfaces.MODULE_TYPE_DATA_QUALITY_RULE)
+			So(jobs[0].UpdateTime, ShouldEqual, 1000)
+			So(jobs[0].QualityRule.Config.Fields, ShouldResemble, []string{"a"})
+			So(jobs[0].QualityRule.EventConfig, ShouldBeNil)
+
+			minCount := int64(10)
+			So(jobs[1].QualityRule.Config.MinCount, ShouldResemble, &minCount)
+			So(jobs[1].QualityRule.EmitEvent, ShouldBeTrue)
+			So(jobs[1].QualityRule.EventConfig, ShouldResemble, &interfaces.DataQualityEventCfg{IndexBase: "base1", Level: 2})
+			So(jobs[1].QualityRule.Tags, ShouldResemble, []string{"t1"})
+			So(jobs[1].QualityRule.LastStatus, ShouldEqual, interfaces.DATA_QUALITY_STATUS_FAILED)
+			So(jobs[1].Schedule, ShouldResemble, jobs[1].QualityRule.Schedule)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_DataQualityAccess_CreateDataQualityResult(t *testing.T) {
+	Convey("Test CreateDataQualityResult", t, func() {
+		dqa, smock := MockNewDataQualityAccess()
+
+		sqlStr := fmt.Sprintf("INSERT INTO %s (f_rule_id,f_view_id,f_check_time,f_status,f_value,f_details,f_error) "+
+			"VALUES (?,?,?,?,?,?,?)", DATA_QUALITY_RESULT_TABLE_NAME)
+		result := interfaces.DataQualityResult{
+			RuleID:    "r1",
+			ViewID:    "v1",
+			CheckTime: 1000,
+			Status:    interfaces.DATA_QUALITY_STATUS_PASSED,
+			Value:     1,
+			Details:   map[string]any{"total": 10},
+		}
+		args := []driver.Value{"r1", "v1", int64(1000), interfaces.DATA_QUALITY_STATUS_PASSED, float64(1),
+			`{"total":10}`, ""}
+
+		Convey("Create failed, caused by exec error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(sqlStr).WithArgs(args...).WillReturnError(expectedErr)
+
+			err := dqa.CreateDataQualityResult(testCtx, result)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Create succeed", func() {
+			smock.ExpectExec(sqlStr).WithArgs(args...).WillReturnResult(sqlmock.NewResult(1, 1))
+
+			err := dqa.CreateDataQualityResult(testCtx, result)
+			So(err, ShouldBeNil)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_DataQualityAccess_UpdateDataQualityRuleStatus(t *testing.T) {
+	Convey("Test UpdateDataQualityRuleStatus", t, func() {
+		dqa, smock := MockNewDataQualityAccess()
+
+		sqlStr := fmt.Sprintf("UPDATE %s SET f_last_check_time = ?, f_last_status = ? WHERE f_rule_id = ?",
+			DATA_QUALITY_RULE_TABLE_NAME)
+		args := []driver.Value{int64(1000), interfaces.DATA_QUALITY_STATUS_FAILED, "r1"}
+
+		Convey("Update failed, caused by exec error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(sqlStr).WithArgs(args...).WillReturnError(expectedErr)
+
+			err := dqa.UpdateDataQualityRuleStatus(testCtx, "r1", interfaces.DATA_QUALITY_STATUS_FAILED, 1000)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Update succeed", func() {
+			smock.ExpectExec(sqlStr).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := dqa.UpdateDataQualityRuleStatus(testCtx, "r1", interfaces.DATA_QUALITY_STATUS_FAILED, 1000)
+			So(err, ShouldBeNil)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
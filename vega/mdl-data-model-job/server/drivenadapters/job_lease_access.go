@@ -0,0 +1,190 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package drivenadapters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	libdb "github.com/kweaver-ai/kweaver-go-lib/db"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	sq "github.com/Masterminds/squirrel"
+
+	"data-model-job/common"
+	"data-model-job/interfaces"
+)
+
+const (
+	JOB_LEASE_TABLE_NAME = "t_job_lease"
+)
+
+var (
+	jlAccessOnce sync.Once
+	jlAccess     interfaces.JobLeaseAccess
+)
+
+type jobLeaseAccess struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+}
+
+func NewJobLeaseAccess(appSetting *common.AppSetting) interfaces.JobLeaseAccess {
+	jlAccessOnce.Do(func() {
+		jlAccess = &jobLeaseAccess{
+			appSetting: appSetting,
+			db:         libdb.NewDB(&appSetting.DBSetting),
+		}
+	})
+
+	return jlAccess
+}
+
+// 获取或续约任务租约
+// 1. 租约由自己持有或已过期时, 直接更新为自己持有
+// 2. 租约不存在时插入, 多个副本同时插入时只有一个成功
+// 3. 插入失败时以数据库中的持有者为准
+func (jla *jobLeaseAccess) AcquireJobLease(ctx context.Context, lease interfaces.JobLease) (bool, error) {
+	updateStr, updateArgs, err := sq.Update(JOB_LEASE_TABLE_NAME).
+		Set("f_owner", lease.Owner).
+		Set("f_expire_time", lease.ExpireTime).
+		Set("f_update_time", lease.UpdateTime).
+		Where(sq.And{
+			sq.Eq{"f_job_id": lease.JobID},
+			sq.Or{
+				sq.Eq{"f_owner": lease.Owner},
+				sq.Lt{"f_expire_time": lease.UpdateTime},
+			},
+		}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'update job lease' sql stmt failed, %v", err)
+		return false, err
+	}
+
+	result, err := jla.db.Exec(updateStr, updateArgs...)
+	if err != nil {
+		logger.Errorf("Update lease of job '%s' failed, %v", lease.JobID, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.Errorf("Get RowsAffected of updating lease of job '%s' failed, %v", lease.JobID, err)
+		return false, err
+	}
+	if rowsAffected > 0 {
+		return true, nil
+	}
+
+	insertStr, insertArgs, err := sq.Insert(JOB_LEASE_TABLE_NAME).
+		Columns(
+			"f_job_id",
+			"f_owner",
+			"f_expire_time",
+			"f_update_time",
+		).
+		Values(
+			lease.JobID,
+			lease.Owner,
+			lease.ExpireTime,
+			lease.UpdateTime,
+		).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'insert job lease' sql stmt failed, %v", err)
+		return false, err
+	}
+
+	_, insertErr := jla.db.Exec(insertStr, insertArgs...)
+	if insertErr == nil {
+		return true, nil
+	}
+
+	selectStr, selectArgs, err := sq.Select("f_owner").
+		From(JOB_LEASE_TABLE_NAME).
+		Where(sq.Eq{"f_job_id": lease.JobID}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'get job lease owner' sql stmt failed, %v", err)
+		return false, err
+	}
+
+	var owner string
+	err = jla.db.QueryRow(selectStr, selectArgs...).Scan(&owner)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			logger.Errorf("Insert lease of job '%s' failed, %v", lease.JobID, insertErr)
+			return false, insertErr
+		}
+		logger.Errorf("Get lease owner of job '%s' failed, %v", lease.JobID, err)
+		return false, err
+	}
+
+	return owner == lease.Owner, nil
+}
+
+// 释放自己持有的任务租约
+func (jla *jobLeaseAccess) ReleaseJobLease(ctx context.Context, jobID string, owner string) error {
+	sqlStr, args, err := sq.Delete(JOB_LEASE_TABLE_NAME).
+		Where(sq.Eq{"f_job_id": jobID}).
+		Where(sq.Eq{"f_owner": owner}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'delete job lease' sql stmt failed, %v", err)
+		return err
+	}
+
+	_, err = jla.db.Exec(sqlStr, args...)
+	if err != nil {
+		logger.Errorf("Delete lease of job '%s' failed, %v", jobID, err)
+		return err
+	}
+
+	return nil
+}
+
+// 查询全部任务租约
+func (jla *jobLeaseAccess) ListJobLeases(ctx context.Context) ([]interfaces.JobLease, error) {
+	leases := make([]interfaces.JobLease, 0)
+	sqlStr, args, err := sq.Select(
+		"f_job_id",
+		"f_owner",
+		"f_expire_time",
+		"f_update_time").
+		From(JOB_LEASE_TABLE_NAME).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'list job leases' sql stmt failed, %v", err)
+		return nil, err
+	}
+
+	rows, err := jla.db.Query(sqlStr, args...)
+	if err != nil {
+		logger.Errorf("List job leases failed, %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		lease := interfaces.JobLease{}
+		err := rows.Scan(
+			&lease.JobID,
+			&lease.Owner,
+			&lease.ExpireTime,
+			&lease.UpdateTime,
+		)
+		if err != nil {
+			logger.Errorf("Row scan failed, err: %v", err)
+			return nil, err
+		}
+
+		leases = append(leases, lease)
+	}
+
+	return leases, nil
+}
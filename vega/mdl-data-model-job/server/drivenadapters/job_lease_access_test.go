@@ -0,0 +1,181 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package drivenadapters
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model-job/interfaces"
+)
+
+func MockNewJobLeaseAccess() (*jobLeaseAccess, sqlmock.Sqlmock) {
+	db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	jla := &jobLeaseAccess{
+		db: db,
+	}
+	return jla, smock
+}
+
+func Test_JobLeaseAccess_AcquireJobLease(t *testing.T) {
+	Convey("Test AcquireJobLease", t, func() {
+		jla, smock := MockNewJobLeaseAccess()
+
+		updateStr := fmt.Sprintf("UPDATE %s SET f_owner = ?, f_expire_time = ?, f_update_time = ? "+
+			"WHERE (f_job_id = ? AND (f_owner = ? OR f_expire_time < ?))", JOB_LEASE_TABLE_NAME)
+		insertStr := fmt.Sprintf("INSERT INTO %s (f_job_id,f_owner,f_expire_time,f_update_time) "+
+			"VALUES (?,?,?,?)", JOB_LEASE_TABLE_NAME)
+		selectStr := fmt.Sprintf("SELECT f_owner FROM %s WHERE f_job_id = ?", JOB_LEASE_TABLE_NAME)
+
+		lease := interfaces.JobLease{
+			JobID:      "1a",
+			Owner:      "replica-a",
+			ExpireTime: 31000,
+			UpdateTime: 1000,
+		}
+		updateArgs := []driver.Value{"replica-a", int64(31000), int64(1000), "1a", "replica-a", int64(1000)}
+		insertArgs := []driver.Value{"1a", "replica-a", int64(31000), int64(1000)}
+
+		Convey("Acquire failed, caused by update error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(updateStr).WithArgs(updateArgs...).WillReturnError(expectedErr)
+
+			owned, err := jla.AcquireJobLease(testCtx, lease)
+			So(err, ShouldResemble, expectedErr)
+			So(owned, ShouldBeFalse)
+		})
+
+		Convey("Acquire succeed, renew or take over an expired lease", func() {
+			smock.ExpectExec(updateStr).WithArgs(updateArgs...).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			owned, err := jla.AcquireJobLease(testCtx, lease)
+			So(err, ShouldBeNil)
+			So(owned, ShouldBeTrue)
+		})
+
+		Convey("Acquire succeed, insert a new lease", func() {
+			smock.ExpectExec(updateStr).WithArgs(updateArgs...).WillReturnResult(sqlmock.NewResult(0, 0))
+			smock.ExpectExec(insertStr).WithArgs(insertArgs...).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			owned, err := jla.AcquireJobLease(testCtx, lease)
+			So(err, ShouldBeNil)
+			So(owned, ShouldBeTrue)
+		})
+
+		Convey("Acquire failed, lease owned by other replica", func() {
+			smock.ExpectExec(updateStr).WithArgs(updateArgs...).WillReturnResult(sqlmock.NewResult(0, 0))
+			smock.ExpectExec(insertStr).WithArgs(insertArgs...).WillReturnError(errors.New("duplicate key"))
+			rows := sqlmock.NewRows([]string{"f_owner"}).AddRow("replica-b")
+			smock.ExpectQuery(selectStr).WithArgs("1a").WillReturnRows(rows)
+
+			owned, err := jla.AcquireJobLease(testCtx, lease)
+			So(err, ShouldBeNil)
+			So(owned, ShouldBeFalse)
+		})
+
+		Convey("Acquire failed, caused by insert error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(updateStr).WithArgs(updateArgs...).WillReturnResult(sqlmock.NewResult(0, 0))
+			smock.ExpectExec(insertStr).WithArgs(insertArgs...).WillReturnError(expectedErr)
+			smock.ExpectQuery(selectStr).WithArgs("1a").WillReturnError(sql.ErrNoRows)
+
+			owned, err := jla.AcquireJobLease(testCtx, lease)
+			So(err, ShouldResemble, expectedErr)
+			So(owned, ShouldBeFalse)
+		})
+
+		Convey("Acquire failed, caused by query owner error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(updateStr).WithArgs(updateArgs...).WillReturnResult(sqlmock.NewResult(0, 0))
+			smock.ExpectExec(insertStr).WithArgs(insertArgs...).WillReturnError(errors.New("duplicate key"))
+			smock.ExpectQuery(selectStr).WithArgs("1a").WillReturnError(expectedErr)
+
+			owned, err := jla.AcquireJobLease(testCtx, lease)
+			So(err, ShouldResemble, expectedErr)
+			So(owned, ShouldBeFalse)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_JobLeaseAccess_ReleaseJobLease(t *testing.T) {
+	Convey("Test ReleaseJobLease", t, func() {
+		jla, smock := MockNewJobLeaseAccess()
+
+		sqlStr := fmt.Sprintf("DELETE FROM %s WHERE f_job_id = ? AND f_owner = ?", JOB_LEASE_TABLE_NAME)
+
+		Convey("Release failed", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(sqlStr).WithArgs("1a", "replica-a").WillReturnError(expectedErr)
+
+			err := jla.ReleaseJobLease(testCtx, "1a", "replica-a")
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Release succeed", func() {
+			smock.ExpectExec(sqlStr).WithArgs("1a", "replica-a").WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := jla.ReleaseJobLease(testCtx, "1a", "replica-a")
+			So(err, ShouldBeNil)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_JobLeaseAccess_ListJobLeases(t *testing.T) {
+	Convey("Test ListJobLeases", t, func() {
+		jla, smock := MockNewJobLeaseAccess()
+
+		sqlStr := fmt.Sprintf("SELECT f_job_id, f_owner, f_expire_time, f_update_time FROM %s", JOB_LEASE_TABLE_NAME)
+		columns := []string{"f_job_id", "f_owner", "f_expire_time", "f_update_time"}
+
+		Convey("List failed, caused by query error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(sqlStr).WillReturnError(expectedErr)
+
+			_, err := jla.ListJobLeases(testCtx)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("List failed, caused by scan error", func() {
+			rows := sqlmock.NewRows(columns).AddRow("1a", "replica-a", "abc", 1000)
+			smock.ExpectQuery(sqlStr).WillReturnRows(rows)
+
+			_, err := jla.ListJobLeases(testCtx)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("List succeed", func() {
+			rows := sqlmock.NewRows(columns).
+				AddRow("1a", "replica-a", 31000, 1000).
+				AddRow("1b", "replica-b", 32000, 2000)
+			smock.ExpectQuery(sqlStr).WillReturnRows(rows)
+
+			leases, err := jla.ListJobLeases(testCtx)
+			So(err, ShouldBeNil)
+			So(leases, ShouldResemble, []interfaces.JobLease{
+				{JobID: "1a", Owner: "replica-a", ExpireTime: 31000, UpdateTime: 1000},
+				{JobID: "1b", Owner: "replica-b", ExpireTime: 32000, UpdateTime: 2000},
+			})
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package drivenadapters
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/bytedance/sonic"
+	libdb "github.com/kweaver-ai/kweaver-go-lib/db"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"data-model-job/common"
+	"data-model-job/interfaces"
+)
+
+const (
+	METRIC_TASK_BACKFILL_TABLE_NAME = "t_metric_task_backfill"
+)
+
+var (
+	mtbAccessOnce sync.Once
+	mtbAccess     interfaces.MetricTaskBackfillAccess
+)
+
+type metricTaskBackfillAccess struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+}
+
+func NewMetricTaskBackfillAccess(appSetting *common.AppSetting) interfaces.MetricTaskBackfillAccess {
+	mtbAccessOnce.Do(func() {
+		mtbAccess = &metricTaskBackfillAccess{
+			appSetting: appSetting,
+			db:         libdb.NewDB(&appSetting.DBSetting),
+		}
+	})
+
+	return mtbAccess
+}
+
+// 查询待执行和执行中的补数任务, 按创建时间先后执行
+func (mtba *metricTaskBackfillAccess) ListActiveBackfills(ctx context.Context) ([]interfaces.MetricTaskBackfill, error) {
+	backfills := make([]interfaces.MetricTaskBackfill, 0)
+	sqlStr, args, err := sq.Select(
+		"f_id",
+		"f_task_id",
+		"f_model_id",
+		"f_module_type",
+		"f_start",
+		"f_end",
+		"f_steps",
+		"f_status",
+		"f_checkpoint",
+		"f_total_points",
+		"f_done_points").
+		From(METRIC_TASK_BACKFILL_TABLE_NAME).
+		Where(sq.Eq{"f_status": []string{interfaces.BACKFILL_STATUS_PENDING, interfaces.BACKFILL_STATUS_RUNNING}}).
+		OrderBy("f_create_time").
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'list active backfills' sql stmt failed, %v", err)
+		return nil, err
+	}
+
+	rows, err := mtba.db.Query(sqlStr, args...)
+	if err != nil {
+		logger.Errorf("List active backfills failed, %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stepsBytes []byte
+		backfill := interfaces.MetricTaskBackfill{}
+		err := rows.Scan(
+			&backfill.ID,
+			&backfill.TaskID,
+			&backfill.ModelID,
+			&backfill.ModuleType,
+			&backfill.Start,
+			&backfill.End,
+			&stepsBytes,
+			&backfill.Status,
+			&backfill.Checkpoint,
+			&backfill.TotalPoints,
+			&backfill.DonePoints,
+		)
+		if err != nil {
+			logger.Errorf("Row scan failed, err: %v", err)
+			return nil, err
+		}
+
+		err = sonic.Unmarshal(stepsBytes, &backfill.Steps)
+		if err != nil {
+			logger.Errorf("Unmarshal steps of backfill '%s' failed, %v", backfill.ID, err)
+			return nil, err
+		}
+
+		backfills = append(backfills, backfill)
+	}
+
+	return backfills, nil
+}
+
+// 更新补数任务的状态和进度. 只更新未结束的补数任务, 在 data-model 中被取消的补数任务不会被覆盖
+func (mtba *metricTaskBackfillAccess) UpdateBackfillProgress(ctx context.Context, backfill interfaces.MetricTaskBackfill) (bool, error) {
+	sqlStr, args, err := sq.Update(METRIC_TASK_BACKFILL_TABLE_NAME).
+		Set("f_status", backfill.Status).
+		Set("f_status_details", backfill.StatusDetails).
+		Set("f_checkpoint", backfill.Checkpoint).
+		Set("f_done_points", backfill.DonePoints).
+		Set("f_update_time", backfill.UpdateTime).
+		Where(sq.Eq{"f_id": backfill.ID}).
+		Where(sq.Eq{"f_status": []string{interfaces.BACKFILL_STATUS_PENDING, interfaces.BACKFILL_STATUS_RUNNING}}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'update backfill progress' sql stmt failed, %v", err)
+		return false, err
+	}
+
+	result, err := mtba.db.Exec(sqlStr, args...)
+	if err != nil {
+		logger.Errorf("Update progress of backfill '%s' failed, %v", backfill.ID, err)
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logger.Errorf("Get RowsAffected of updating backfill '%s' failed, %v", backfill.ID, err)
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
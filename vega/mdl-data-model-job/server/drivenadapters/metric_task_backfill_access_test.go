@@ -0,0 +1,130 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package drivenadapters
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model-job/interfaces"
+)
+
+func MockNewMetricTaskBackfillAccess() (*metricTaskBackfillAccess, sqlmock.Sqlmock) {
+	db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	mtba := &metricTaskBackfillAccess{
+		db: db,
+	}
+	return mtba, smock
+}
+
+func Test_MetricTaskBackfillAccess_ListActiveBackfills(t *testing.T) {
+	Convey("Test ListActiveBackfills", t, func() {
+		mtba, smock := MockNewMetricTaskBackfillAccess()
+
+		sqlStr := fmt.Sprintf("SELECT f_id, f_task_id, f_model_id, f_module_type, f_start, f_end, f_steps, "+
+			"f_status, f_checkpoint, f_total_points, f_done_points FROM %s WHERE f_status IN (?,?) "+
+			"ORDER BY f_create_time", METRIC_TASK_BACKFILL_TABLE_NAME)
+		columns := []string{"f_id", "f_task_id", "f_model_id", "f_module_type", "f_start", "f_end", "f_steps",
+			"f_status", "f_checkpoint", "f_total_points", "f_done_points"}
+		args := []driver.Value{interfaces.BACKFILL_STATUS_PENDING, interfaces.BACKFILL_STATUS_RUNNING}
+
+		Convey("List failed, caused by query error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(sqlStr).WithArgs(args...).WillReturnError(expectedErr)
+
+			_, err := mtba.ListActiveBackfills(testCtx)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("List failed, caused by unmarshal steps error", func() {
+			rows := sqlmock.NewRows(columns).AddRow("b1", "1a", "m1", interfaces.MODULE_TYPE_METRIC_MODEL,
+				0, 600000, "[5m", interfaces.BACKFILL_STATUS_PENDING, 0, 3, 0)
+			smock.ExpectQuery(sqlStr).WithArgs(args...).WillReturnRows(rows)
+
+			_, err := mtba.ListActiveBackfills(testCtx)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("List succeed", func() {
+			rows := sqlmock.NewRows(columns).AddRow("b1", "1a", "m1", interfaces.MODULE_TYPE_METRIC_MODEL,
+				0, 600000, `["5m"]`, interfaces.BACKFILL_STATUS_RUNNING, 300000, 3, 1)
+			smock.ExpectQuery(sqlStr).WithArgs(args...).WillReturnRows(rows)
+
+			backfills, err := mtba.ListActiveBackfills(testCtx)
+			So(err, ShouldBeNil)
+			So(backfills, ShouldResemble, []interfaces.MetricTaskBackfill{{
+				ID:          "b1",
+				TaskID:      "1a",
+				ModelID:     "m1",
+				ModuleType:  interfaces.MODULE_TYPE_METRIC_MODEL,
+				Start:       0,
+				End:         600000,
+				Steps:       []string{"5m"},
+				Status:      interfaces.BACKFILL_STATUS_RUNNING,
+				Checkpoint:  300000,
+				TotalPoints: 3,
+				DonePoints:  1,
+			}})
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_MetricTaskBackfillAccess_UpdateBackfillProgress(t *testing.T) {
+	Convey("Test UpdateBackfillProgress", t, func() {
+		mtba, smock := MockNewMetricTaskBackfillAccess()
+
+		sqlStr := fmt.Sprintf("UPDATE %s SET f_status = ?, f_status_details = ?, f_checkpoint = ?, "+
+			"f_done_points = ?, f_update_time = ? WHERE f_id = ? AND f_status IN (?,?)", METRIC_TASK_BACKFILL_TABLE_NAME)
+
+		backfill := interfaces.MetricTaskBackfill{
+			ID:         "b1",
+			Status:     interfaces.BACKFILL_STATUS_RUNNING,
+			Checkpoint: 300000,
+			DonePoints: 1,
+			UpdateTime: 1000,
+		}
+		args := []driver.Value{interfaces.BACKFILL_STATUS_RUNNING, "", int64(300000), int64(1), int64(1000), "b1",
+			interfaces.BACKFILL_STATUS_PENDING, interfaces.BACKFILL_STATUS_RUNNING}
+
+		Convey("Update failed, caused by exec error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(sqlStr).WithArgs(args...).WillReturnError(expectedErr)
+
+			active, err := mtba.UpdateBackfillProgress(testCtx, backfill)
+			So(err, ShouldResemble, expectedErr)
+			So(active, ShouldBeFalse)
+		})
+
+		Convey("Update nothing, backfill canceled", func() {
+			smock.ExpectExec(sqlStr).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			active, err := mtba.UpdateBackfillProgress(testCtx, backfill)
+			So(err, ShouldBeNil)
+			So(active, ShouldBeFalse)
+		})
+
+		Convey("Update succeed", func() {
+			smock.ExpectExec(sqlStr).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			active, err := mtba.UpdateBackfillProgress(testCtx, backfill)
+			So(err, ShouldBeNil)
+			So(active, ShouldBeTrue)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
@@ -0,0 +1,206 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package drivenadapters
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/bytedance/sonic"
+	libdb "github.com/kweaver-ai/kweaver-go-lib/db"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"data-model-job/common"
+	"data-model-job/interfaces"
+)
+
+const (
+	OBJECTIVE_ALERT_TABLE_NAME = "t_objective_alert"
+)
+
+var (
+	oaAccessOnce sync.Once
+	oaAccess     interfaces.ObjectiveAlertAccess
+)
+
+type objectiveAlertAccess struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+}
+
+func NewObjectiveAlertAccess(appSetting *common.AppSetting) interfaces.ObjectiveAlertAccess {
+	oaAccessOnce.Do(func() {
+		oaAccess = &objectiveAlertAccess{
+			appSetting: appSetting,
+			db:         libdb.NewDB(&appSetting.DBSetting),
+		}
+	})
+
+	return oaAccess
+}
+
+// 查询目标模型当前的燃烧率告警状态
+func (oaa *objectiveAlertAccess) ListObjectiveAlerts(ctx context.Context, modelID string) ([]interfaces.ObjectiveAlert, error) {
+	alerts := make([]interfaces.ObjectiveAlert, 0)
+	sqlStr, args, err := sq.Select(
+		"f_model_id",
+		"f_policy_name",
+		"f_series_id",
+		"f_labels",
+		"f_status",
+		"f_level",
+		"f_threshold",
+		"f_long_burn_rate",
+		"f_short_burn_rate",
+		"f_active_since",
+		"f_last_eval_time",
+		"f_last_event_id",
+		"f_last_event_time").
+		From(OBJECTIVE_ALERT_TABLE_NAME).
+		Where(sq.Eq{"f_model_id": modelID}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'list objective alerts' sql stmt failed, %v", err)
+		return nil, err
+	}
+
+	rows, err := oaa.db.Query(sqlStr, args...)
+	if err != nil {
+		logger.Errorf("List objective alerts of model '%s' failed, %v", modelID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var labelsStr string
+		alert := interfaces.ObjectiveAlert{}
+		err := rows.Scan(
+			&alert.ModelID,
+			&alert.PolicyName,
+			&alert.SeriesID,
+			&labelsStr,
+			&alert.Status,
+			&alert.Level,
+			&alert.Threshold,
+			&alert.LongBurnRate,
+			&alert.ShortBurnRate,
+			&alert.ActiveSince,
+			&alert.LastEvalTime,
+			&alert.LastEventID,
+			&alert.LastEventTime,
+		)
+		if err != nil {
+			logger.Errorf("Row scan failed, err: %v", err)
+			return nil, err
+		}
+
+		if labelsStr != "" {
+			err = sonic.UnmarshalString(labelsStr, &alert.Labels)
+			if err != nil {
+				logger.Errorf("Unmarshal objective alert labels failed, %v", err)
+				return nil, err
+			}
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+// 用本轮评估后的告警状态整体替换目标模型的告警状态
+func (oaa *objectiveAlertAccess) ReplaceObjectiveAlerts(ctx context.Context, modelID string,
+	alerts []interfaces.ObjectiveAlert) (err error) {
+
+	tx, err := oaa.db.Begin()
+	if err != nil {
+		logger.Errorf("Begin transaction failed, %v", err)
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				logger.Errorf("Replace objective alerts transaction rollback failed, %v", rollbackErr)
+			}
+		}
+	}()
+
+	delStr, delArgs, err := sq.Delete(OBJECTIVE_ALERT_TABLE_NAME).
+		Where(sq.Eq{"f_model_id": modelID}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Generate 'delete objective alerts' sql stmt failed, %v", err)
+		return err
+	}
+
+	if _, err = tx.Exec(delStr, delArgs...); err != nil {
+		logger.Errorf("Delete objective alerts of model '%s' failed, %v", modelID, err)
+		return err
+	}
+
+	if len(alerts) > 0 {
+		builder := sq.Insert(OBJECTIVE_ALERT_TABLE_NAME).
+			Columns(
+				"f_model_id",
+				"f_policy_name",
+				"f_series_id",
+				"f_labels",
+				"f_status",
+				"f_level",
+				"f_threshold",
+				"f_long_burn_rate",
+				"f_short_burn_rate",
+				"f_active_since",
+				"f_last_eval_time",
+				"f_last_event_id",
+				"f_last_event_time",
+			)
+		for _, alert := range alerts {
+			labelsStr, mErr := sonic.MarshalString(alert.Labels)
+			if mErr != nil {
+				err = mErr
+				logger.Errorf("Marshal objective alert labels failed, %v", err)
+				return err
+			}
+			builder = builder.Values(
+				modelID,
+				alert.PolicyName,
+				alert.SeriesID,
+				labelsStr,
+				alert.Status,
+				alert.Level,
+				alert.Threshold,
+				alert.LongBurnRate,
+				alert.ShortBurnRate,
+				alert.ActiveSince,
+				alert.LastEvalTime,
+				alert.LastEventID,
+				alert.LastEventTime,
+			)
+		}
+
+		insStr, insArgs, bErr := builder.ToSql()
+		if bErr != nil {
+			err = bErr
+			logger.Errorf("Generate 'insert objective alerts' sql stmt failed, %v", err)
+			return err
+		}
+
+		if _, err = tx.Exec(insStr, insArgs...); err != nil {
+			logger.Errorf("Insert objective alerts of model '%s' failed, %v", modelID, err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.Errorf("Replace objective alerts transaction commit failed, %v", err)
+		return err
+	}
+
+	return nil
+}
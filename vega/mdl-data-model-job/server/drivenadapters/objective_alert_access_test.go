@@ -0,0 +1,134 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package drivenadapters
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model-job/interfaces"
+)
+
+func MockNewObjectiveAlertAccess() (*objectiveAlertAccess, sqlmock.Sqlmock) {
+	db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	oaa := &objectiveAlertAccess{
+		db: db,
+	}
+	return oaa, smock
+}
+
+func Test_ObjectiveAlertAccess_ListObjectiveAlerts(t *testing.T) {
+	Convey("Test ListObjectiveAlerts", t, func() {
+		oaa, smock := MockNewObjectiveAlertAccess()
+
+		sqlStr := fmt.Sprintf("SELECT f_model_id, f_policy_name, f_series_id, f_labels, f_status, f_level, "+
+			"f_threshold, f_long_burn_rate, f_short_burn_rate, f_active_since, f_last_eval_time, "+
+			"f_last_event_id, f_last_event_time FROM %s WHERE f_model_id = ?", OBJECTIVE_ALERT_TABLE_NAME)
+		columns := []string{"f_model_id", "f_policy_name", "f_series_id", "f_labels", "f_status", "f_level",
+			"f_threshold", "f_long_burn_rate", "f_short_burn_rate", "f_active_since", "f_last_eval_time",
+			"f_last_event_id", "f_last_event_time"}
+
+		Convey("List failed, caused by query error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(sqlStr).WithArgs("1").WillReturnError(expectedErr)
+
+			_, err := oaa.ListObjectiveAlerts(testCtx, "1")
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("List failed, caused by labels unmarshal error", func() {
+			rows := sqlmock.NewRows(columns).
+				AddRow("1", "page", "s1", "{", "firing", 1, 14.4, 15, 20, 1000, 1000, "e1", 1000)
+			smock.ExpectQuery(sqlStr).WithArgs("1").WillReturnRows(rows)
+
+			_, err := oaa.ListObjectiveAlerts(testCtx, "1")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("List succeed", func() {
+			rows := sqlmock.NewRows(columns).
+				AddRow("1", "page", "s1", `{"service":"a"}`, "firing", 1, 14.4, 15, 20, 1000, 1000, "e1", 1000)
+			smock.ExpectQuery(sqlStr).WithArgs("1").WillReturnRows(rows)
+
+			alerts, err := oaa.ListObjectiveAlerts(testCtx, "1")
+			So(err, ShouldBeNil)
+			So(len(alerts), ShouldEqual, 1)
+			So(alerts[0].Labels, ShouldResemble, map[string]string{"service": "a"})
+			So(alerts[0].LongBurnRate, ShouldEqual, 15)
+		})
+	})
+}
+
+func Test_ObjectiveAlertAccess_ReplaceObjectiveAlerts(t *testing.T) {
+	Convey("Test ReplaceObjectiveAlerts", t, func() {
+		oaa, smock := MockNewObjectiveAlertAccess()
+
+		delStr := fmt.Sprintf("DELETE FROM %s WHERE f_model_id = ?", OBJECTIVE_ALERT_TABLE_NAME)
+		insStr := fmt.Sprintf("INSERT INTO %s (f_model_id,f_policy_name,f_series_id,f_labels,f_status,f_level,"+
+			"f_threshold,f_long_burn_rate,f_short_burn_rate,f_active_since,f_last_eval_time,f_last_event_id,"+
+			"f_last_event_time) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)", OBJECTIVE_ALERT_TABLE_NAME)
+		alerts := []interfaces.ObjectiveAlert{
+			{PolicyName: "page", SeriesID: "s1", Labels: map[string]string{"service": "a"},
+				Status: interfaces.OBJECTIVE_ALERT_STATUS_FIRING, Level: 1, Threshold: 14.4},
+		}
+
+		Convey("Replace failed, caused by begin error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectBegin().WillReturnError(expectedErr)
+
+			err := oaa.ReplaceObjectiveAlerts(testCtx, "1", alerts)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Replace failed, caused by delete error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectBegin()
+			smock.ExpectExec(delStr).WithArgs("1").WillReturnError(expectedErr)
+			smock.ExpectRollback()
+
+			err := oaa.ReplaceObjectiveAlerts(testCtx, "1", alerts)
+			So(err, ShouldResemble, expectedErr)
+			So(smock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("Replace failed, caused by insert error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectBegin()
+			smock.ExpectExec(delStr).WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 1))
+			smock.ExpectExec(insStr).WillReturnError(expectedErr)
+			smock.ExpectRollback()
+
+			err := oaa.ReplaceObjectiveAlerts(testCtx, "1", alerts)
+			So(err, ShouldResemble, expectedErr)
+			So(smock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("Replace succeed", func() {
+			smock.ExpectBegin()
+			smock.ExpectExec(delStr).WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 1))
+			smock.ExpectExec(insStr).WillReturnResult(sqlmock.NewResult(0, 1))
+			smock.ExpectCommit()
+
+			err := oaa.ReplaceObjectiveAlerts(testCtx, "1", alerts)
+			So(err, ShouldBeNil)
+			So(smock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("Replace succeed with no alerts", func() {
+			smock.ExpectBegin()
+			smock.ExpectExec(delStr).WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 1))
+			smock.ExpectCommit()
+
+			err := oaa.ReplaceObjectiveAlerts(testCtx, "1", nil)
+			So(err, ShouldBeNil)
+			So(smock.ExpectationsWereMet(), ShouldBeNil)
+		})
+	})
+}
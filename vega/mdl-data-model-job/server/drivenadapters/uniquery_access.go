@@ -199,3 +199,56 @@ func (ua *uniqueryAccess) GetEventModelData(ctx context.Context, query interface
 
 	return eventData, nil
 }
+
+// 查询视图数据
+func (ua *uniqueryAccess) GetViewData(ctx context.Context, viewID string, query interfaces.ViewQuery) (interfaces.ViewUniResponse, error) {
+
+	accountInfo := interfaces.AccountInfo{}
+	if ctx.Value(interfaces.ACCOUNT_INFO_KEY) != nil {
+		accountInfo = ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo)
+	}
+	uniqueryViewHeaders := map[string]string{
+		interfaces.CONTENT_TYPE_NAME:           interfaces.CONTENT_TYPE_JSON,
+		interfaces.HTTP_HEADER_METHOD_OVERRIDE: http.MethodGet,
+		interfaces.HTTP_HEADER_ACCOUNT_ID:      accountInfo.ID,
+		interfaces.HTTP_HEADER_ACCOUNT_TYPE:    accountInfo.Type,
+	}
+
+	url := fmt.Sprintf("%s/data-views/%s", ua.uniqueryUrl, viewID)
+
+	respCode, result, err := ua.httpClient.PostNoUnmarshal(ctx, url, uniqueryViewHeaders, query)
+	logger.Debugf("post [%s] finished, request is [%v] response code is [%d], error is [%v]", url,
+		query, respCode, err)
+
+	viewData := interfaces.ViewUniResponse{}
+
+	if err != nil {
+		logger.Errorf("get request method failed: %v", err)
+
+		return viewData, fmt.Errorf("get request method failed: %v", err)
+	}
+	if respCode != http.StatusOK {
+		// 转成 baseerror
+		var baseError rest.BaseError
+		if err := json.Unmarshal(result, &baseError); err != nil {
+			logger.Errorf("unmalshal BaesError failed: %v\n", err)
+			return viewData, err
+		}
+		httpErr := &rest.HTTPError{HTTPCode: respCode, BaseError: baseError}
+		logger.Errorf("Get view data failed: %v", httpErr.Error())
+
+		return viewData, fmt.Errorf("get view data %s return error %v", viewID, httpErr.Error())
+	}
+
+	if result == nil {
+		return viewData, fmt.Errorf("get view data %s return null", viewID)
+	}
+
+	if err := json.Unmarshal(result, &viewData); err != nil {
+		logger.Errorf("Unmarshal View Data failed, %s", err)
+
+		return viewData, err
+	}
+
+	return viewData, nil
+}
@@ -350,3 +350,55 @@ func Test_UniQueryAccess_GetObjectiveModelData(t *testing.T) {
 		})
 	})
 }
+
+func Test_UniQueryAccess_GetViewData(t *testing.T) {
+	Convey("TestGetViewData", t, func() {
+		mockCtl := gomock.NewController(t)
+		defer mockCtl.Finish()
+
+		ua, httpClient := MockNewUniQueryAccess(mockCtl)
+
+		total := int64(1)
+		query := interfaces.ViewQuery{Limit: 1, NeedTotal: true}
+		viewResponse := interfaces.ViewUniResponse{
+			Entries:    []map[string]any{{"a": "1"}},
+			TotalCount: &total,
+		}
+
+		Convey("success", func() {
+			okResp, _ := json.Marshal(viewResponse)
+			patches := ApplyMethodReturn(httpClient, "PostNoUnmarshal", http.StatusOK, okResp, nil)
+			defer patches.Reset()
+
+			data, err := ua.GetViewData(testCtx, "v1", query)
+			So(err, ShouldBeNil)
+			So(data, ShouldResemble, viewResponse)
+		})
+
+		Convey("failed, caused by http client error", func() {
+			patches := ApplyMethodReturn(httpClient, "PostNoUnmarshal", http.StatusOK, nil, errors.New("error"))
+			defer patches.Reset()
+
+			data, err := ua.GetViewData(testCtx, "v1", query)
+			So(data, ShouldResemble, interfaces.ViewUniResponse{})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("failed, caused by http status not ok", func() {
+			errResp, _ := json.Marshal(rest.BaseError{ErrorCode: "a", Description: "a", ErrorDetails: "a"})
+			patches := ApplyMethodReturn(httpClient, "PostNoUnmarshal", http.StatusBadRequest, errResp, nil)
+			defer patches.Reset()
+
+			_, err := ua.GetViewData(testCtx, "v1", query)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("failed, caused by http result is null", func() {
+			patches := ApplyMethodReturn(httpClient, "PostNoUnmarshal", http.StatusOK, nil, nil)
+			defer patches.Reset()
+
+			_, err := ua.GetViewData(testCtx, "v1", query)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
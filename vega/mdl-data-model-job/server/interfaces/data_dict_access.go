@@ -0,0 +1,32 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import "context"
+
+// 数据字典及其字典项, 字典项以维度名称为 key
+type DataDict struct {
+	DictID    string              `json:"id"`
+	DictName  string              `json:"name"`
+	DictType  string              `json:"type"`
+	Dimension Dimension           `json:"dimension"`
+	DictItems []map[string]string `json:"items"`
+}
+
+type Dimension struct {
+	Keys   []DimensionItem `json:"keys"`
+	Values []DimensionItem `json:"values"`
+}
+
+type DimensionItem struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+//go:generate mockgen -source ../interfaces/data_dict_access.go -destination ../interfaces/mock/mock_data_dict_access.go
+type DataDictAccess interface {
+	GetDataDict(ctx context.Context, dictID string) (DataDict, error)
+}
@@ -0,0 +1,100 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+	"fmt"
+
+	cond "data-model-job/common/condition"
+)
+
+const (
+	MODULE_TYPE_DATA_QUALITY_RULE = "data_quality_rule"
+
+	// 数据质量规则类型, 与 data-model 保持一致
+	DATA_QUALITY_KIND_NOT_NULL        = "not_null"
+	DATA_QUALITY_KIND_UNIQUE          = "unique"
+	DATA_QUALITY_KIND_ACCEPTED_VALUES = "accepted_values"
+	DATA_QUALITY_KIND_FRESHNESS       = "freshness"
+	DATA_QUALITY_KIND_ROW_COUNT       = "row_count"
+	DATA_QUALITY_KIND_CUSTOM          = "custom"
+
+	// 检查状态
+	DATA_QUALITY_STATUS_PASSED = "passed"
+	DATA_QUALITY_STATUS_FAILED = "failed"
+	DATA_QUALITY_STATUS_ERROR  = "error"
+
+	// 唯一性检查默认扫描的行数
+	DEFAULT_DATA_QUALITY_SAMPLE_SIZE = 10000
+
+	// 检查状态变化产生的事件
+	DATA_QUALITY_EVENT_TYPE    = "atomic"
+	DATA_QUALITY_DETECT_TYPE   = "data_quality_detect"
+	DEFAULT_DATA_QUALITY_LEVEL = 3
+	DATA_QUALITY_CLEARED_LEVEL = 6
+)
+
+// 数据质量规则, 由 data-model 创建, 在 data-model-job 中按调度执行
+type DataQualityRule struct {
+	RuleID      string                `json:"id"`
+	RuleName    string                `json:"name"`
+	ViewID      string                `json:"view_id"`
+	Kind        string                `json:"kind"`
+	Config      DataQualityRuleConfig `json:"config"`
+	Schedule    Schedule              `json:"schedule"`
+	EmitEvent   bool                  `json:"emit_event"`
+	EventConfig *DataQualityEventCfg  `json:"event_config,omitempty"`
+	Tags        []string              `json:"tags"`
+	UpdateTime  int64                 `json:"update_time"`
+	Creator     AccountInfo           `json:"creator"`
+
+	// 最近一次检查的状态, 用于判断是否需要发送状态变化的事件
+	LastStatus string `json:"last_status"`
+}
+
+func (r *DataQualityRule) String() string {
+	return fmt.Sprintf("{id = %s, name = %s, view_id = %s, kind = %s, schedule = %v, update_time = %d}",
+		r.RuleID, r.RuleName, r.ViewID, r.Kind, r.Schedule, r.UpdateTime)
+}
+
+type DataQualityRuleConfig struct {
+	Fields     []string      `json:"fields,omitempty"`
+	Filters    *cond.CondCfg `json:"filters,omitempty"`
+	TimeWindow string        `json:"time_window,omitempty"`
+	MinRatio   *float64      `json:"min_ratio,omitempty"`
+	SampleSize int           `json:"sample_size,omitempty"`
+	DictID     string        `json:"dict_id,omitempty"`
+	DictKey    string        `json:"dict_key,omitempty"`
+	MaxDelay   string        `json:"max_delay,omitempty"`
+	MinCount   *int64        `json:"min_count,omitempty"`
+	MaxCount   *int64        `json:"max_count,omitempty"`
+	Condition  *cond.CondCfg `json:"condition,omitempty"`
+}
+
+type DataQualityEventCfg struct {
+	IndexBase string `json:"index_base"`
+	Level     int    `json:"level"`
+}
+
+// 一次检查的结果, 按时间序列写入 t_data_quality_result
+type DataQualityResult struct {
+	RuleID    string
+	ViewID    string
+	CheckTime int64
+	Status    string
+	Value     float64
+	Details   map[string]any
+	Error     string
+}
+
+//go:generate mockgen -source ../interfaces/data_quality_access.go -destination ../interfaces/mock/mock_data_quality_access.go
+type DataQualityAccess interface {
+	// 获取启用的数据质量规则, 转换为定时任务
+	ListDataQualityJobs(ctx context.Context) ([]JobInfo, error)
+	CreateDataQualityResult(ctx context.Context, result DataQualityResult) error
+	UpdateDataQualityRuleStatus(ctx context.Context, ruleID string, status string, checkTime int64) error
+}
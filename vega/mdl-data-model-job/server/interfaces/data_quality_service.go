@@ -0,0 +1,14 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import "context"
+
+//go:generate mockgen -source ../interfaces/data_quality_service.go -destination ../interfaces/mock/mock_data_quality_service.go
+type DataQualityService interface {
+	// 执行一次数据质量检查, 记录检查结果并回写规则的最近状态
+	DataQualityRuleExecutor(ctx context.Context, rule *DataQualityRule) DataQualityResult
+}
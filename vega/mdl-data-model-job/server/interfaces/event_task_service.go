@@ -34,7 +34,7 @@ type FormulaItem struct {
 type DetectRule struct {
 	DetectRuleId string        `json:"id,omitempty"`
 	Priority     int           `json:"priority"`
-	Type         string        `json:"type" binding:"required_with=Formula,omitempty,oneof=range_detect status_detect"`
+	Type         string        `json:"type" binding:"required_with=Formula,omitempty,oneof=range_detect status_detect statistical_detect"`
 	Formula      []FormulaItem `json:"formula" binding:"required_with=Type,dive,required,omitempty"`
 	UpdateTime   int64         `json:"update_time,omitempty" binding:"omitempty"`
 }
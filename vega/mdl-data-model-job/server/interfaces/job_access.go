@@ -15,7 +15,7 @@ import (
 type JobInfo struct {
 	JobId            string         `json:"job_id"`      // data-view对应的是job_id字段，指标、事件、目标对应的是task_id字段
 	JobType          string         `json:"job_type"`    // 任务类型：流式任务(streaming)、定时任务（scheduled）
-	ModuleType       string         `json:"module_type"` // 模块类型：data_view, metric_model, event_model, objective_model
+	ModuleType       string         `json:"module_type"` // 模块类型：data_view, metric_model, event_model, objective_model, data_quality_rule
 	JobConfig        map[string]any `json:"job_config"`
 	JobStatus        string         `json:"job_status"`
 	JobStatusDetails string         `json:"job_status_details"`
@@ -28,6 +28,8 @@ type JobInfo struct {
 	EventTask  *EventTask  `josn:"event_task,omitempty"`  // 事件模型的持久化任务信息
 	Schedule   `json:"schedule,omitempty"`
 
+	QualityRule *DataQualityRule `json:"quality_rule,omitempty"` // 数据质量规则的定时检查
+
 	Ticker   *time.Ticker  // 固定频率的计时器
 	StopChan chan struct{} `json:"-"` // 用于停止固定频率任务
 	CronID   cron.EntryID  // Cron 任务的 ID
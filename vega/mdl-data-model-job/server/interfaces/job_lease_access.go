@@ -0,0 +1,27 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+)
+
+// 任务租约, 同一时刻每个任务只由一个存活的副本持有并运行
+type JobLease struct {
+	JobID      string
+	Owner      string // 持有租约的副本标识
+	ExpireTime int64  // 租约过期时间, 毫秒
+	UpdateTime int64  // 最近一次获取或续约的时间, 毫秒
+}
+
+//go:generate mockgen -source ../interfaces/job_lease_access.go -destination ../interfaces/mock/mock_job_lease_access.go
+type JobLeaseAccess interface {
+	// 获取或续约租约. 租约不存在、已过期或已由 lease.Owner 持有时成功, 否则返回 false
+	AcquireJobLease(ctx context.Context, lease JobLease) (bool, error)
+	// 释放 owner 持有的租约, 其他副本持有的租约不受影响
+	ReleaseJobLease(ctx context.Context, jobID string, owner string) error
+	ListJobLeases(ctx context.Context) ([]JobLease, error)
+}
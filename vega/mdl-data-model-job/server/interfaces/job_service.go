@@ -84,5 +84,6 @@ type JobService interface {
 	UpdateJob(ctx context.Context, jobInfo *JobInfo) error
 	StopJob(ctx context.Context, jobId string) error
 	WatchJob(interval time.Duration)
+	KeepJobLeases(interval time.Duration)
 	ListenToErrChan()
 }
@@ -0,0 +1,44 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+)
+
+const (
+	// 补数任务状态, 与 data-model 保持一致
+	BACKFILL_STATUS_PENDING   = "pending"
+	BACKFILL_STATUS_RUNNING   = "running"
+	BACKFILL_STATUS_SUCCEEDED = "succeeded"
+	BACKFILL_STATUS_FAILED    = "failed"
+	BACKFILL_STATUS_CANCELED  = "canceled"
+)
+
+// 持久化任务的补数任务, 由 data-model 创建, 在 data-model-job 中按批次执行
+type MetricTaskBackfill struct {
+	ID            string
+	TaskID        string
+	ModelID       string
+	ModuleType    string
+	Start         int64
+	End           int64
+	Steps         []string
+	Status        string
+	StatusDetails string
+	Checkpoint    int64 // 下一个待执行的计划时间点
+	TotalPoints   int64
+	DonePoints    int64
+	UpdateTime    int64
+}
+
+//go:generate mockgen -source ../interfaces/metric_task_backfill_access.go -destination ../interfaces/mock/mock_metric_task_backfill_access.go
+type MetricTaskBackfillAccess interface {
+	// 获取待执行和执行中的补数任务
+	ListActiveBackfills(ctx context.Context) ([]MetricTaskBackfill, error)
+	// 更新未结束的补数任务的状态和进度, 补数任务已被取消或已结束时返回 false
+	UpdateBackfillProgress(ctx context.Context, backfill MetricTaskBackfill) (bool, error)
+}
@@ -65,4 +65,6 @@ type Filter struct {
 type MetricTaskService interface {
 	// 参数为jobconfig，其内是任务信息
 	MetricTaskExecutor(cxt context.Context, metricTask MetricTask) (msg string)
+	// 按补数任务的时间范围重新计算持久化任务的数据
+	MetricTaskBackfillExecutor(ctx context.Context, metricTask MetricTask, backfill MetricTaskBackfill) error
 }
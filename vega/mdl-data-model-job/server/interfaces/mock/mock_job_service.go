@@ -41,6 +41,18 @@ func (m *MockJobService) EXPECT() *MockJobServiceMockRecorder {
 	return m.recorder
 }
 
+// KeepJobLeases mocks base method.
+func (m *MockJobService) KeepJobLeases(interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "KeepJobLeases", interval)
+}
+
+// KeepJobLeases indicates an expected call of KeepJobLeases.
+func (mr *MockJobServiceMockRecorder) KeepJobLeases(interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeepJobLeases", reflect.TypeOf((*MockJobService)(nil).KeepJobLeases), interval)
+}
+
 // ListenToErrChan mocks base method.
 func (m *MockJobService) ListenToErrChan() {
 	m.ctrl.T.Helper()
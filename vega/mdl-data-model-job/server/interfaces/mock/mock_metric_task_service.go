@@ -40,6 +40,20 @@ func (m *MockMetricTaskService) EXPECT() *MockMetricTaskServiceMockRecorder {
 	return m.recorder
 }
 
+// MetricTaskBackfillExecutor mocks base method.
+func (m *MockMetricTaskService) MetricTaskBackfillExecutor(ctx context.Context, metricTask interfaces.MetricTask, backfill interfaces.MetricTaskBackfill) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MetricTaskBackfillExecutor", ctx, metricTask, backfill)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MetricTaskBackfillExecutor indicates an expected call of MetricTaskBackfillExecutor.
+func (mr *MockMetricTaskServiceMockRecorder) MetricTaskBackfillExecutor(ctx, metricTask, backfill interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MetricTaskBackfillExecutor", reflect.TypeOf((*MockMetricTaskService)(nil).MetricTaskBackfillExecutor), ctx, metricTask, backfill)
+}
+
 // MetricTaskExecutor mocks base method.
 func (m *MockMetricTaskService) MetricTaskExecutor(cxt context.Context, metricTask interfaces.MetricTask) string {
 	m.ctrl.T.Helper()
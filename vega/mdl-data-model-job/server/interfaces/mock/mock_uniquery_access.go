@@ -84,3 +84,18 @@ func (mr *MockUniqueryAccessMockRecorder) GetObjectiveModelData(ctx, modelId, qu
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectiveModelData", reflect.TypeOf((*MockUniqueryAccess)(nil).GetObjectiveModelData), ctx, modelId, query)
 }
+
+// GetViewData mocks base method.
+func (m *MockUniqueryAccess) GetViewData(ctx context.Context, viewID string, query interfaces.ViewQuery) (interfaces.ViewUniResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetViewData", ctx, viewID, query)
+	ret0, _ := ret[0].(interfaces.ViewUniResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetViewData indicates an expected call of GetViewData.
+func (mr *MockUniqueryAccessMockRecorder) GetViewData(ctx, viewID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewData", reflect.TypeOf((*MockUniqueryAccess)(nil).GetViewData), ctx, viewID, query)
+}
@@ -0,0 +1,16 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+)
+
+//go:generate mockgen -source ../interfaces/objective_alert_access.go -destination ../interfaces/mock/mock_objective_alert_access.go
+type ObjectiveAlertAccess interface {
+	ListObjectiveAlerts(ctx context.Context, modelID string) ([]ObjectiveAlert, error)
+	ReplaceObjectiveAlerts(ctx context.Context, modelID string, alerts []ObjectiveAlert) error
+}
@@ -32,6 +32,28 @@ const (
 	KPI_ASSOCIATE_METRIC_NUM = "__kpi_associate_metric_nums"
 	KPI_STATUS               = "__kpi_status"
 	KPI_STATUS_CODE          = "__kpi_status_code"
+
+	// 燃烧率告警状态
+	OBJECTIVE_ALERT_STATUS_FIRING   = "firing"
+	OBJECTIVE_ALERT_STATUS_RESOLVED = "resolved"
+
+	// 燃烧率告警产生的事件
+	BURN_RATE_EVENT_TYPE    = "atomic"
+	BURN_RATE_DETECT_TYPE   = "burn_rate_detect"
+	BURN_RATE_DETECT_ALGO   = "multi_window_burn_rate"
+	BURN_RATE_CLEARED_LEVEL = 6
+)
+
+var (
+	// 事件级别名称，同事件模型
+	BURN_RATE_EVENT_LEVEL_NAME = map[int]string{
+		1: "Critical",
+		2: "Major",
+		3: "Minor",
+		4: "Warning",
+		5: "Indeterminate",
+		6: "Cleared",
+	}
 )
 
 // 目标模型的数据返回结构体
@@ -87,6 +109,39 @@ type SLOObjective struct {
 	GoodMetricModel  *BundleMetricModel     `json:"good_metric_model"`
 	TotalMetricModel *BundleMetricModel     `json:"total_metric_model"`
 	StatusConfig     *ObjectiveStatusConfig `json:"status_config"`
+	BurnRateAlert    *BurnRateAlertConfig   `json:"burn_rate_alert,omitempty"`
+}
+
+// SLO 多窗口多燃烧率告警配置，告警事件写入 index_base 对应的索引库
+type BurnRateAlertConfig struct {
+	IndexBase string           `json:"index_base"`
+	Policies  []BurnRatePolicy `json:"policies"`
+}
+
+// 燃烧率告警策略，长短两个窗口的燃烧率同时达到阈值时触发
+type BurnRatePolicy struct {
+	Name        string  `json:"name"`
+	LongWindow  string  `json:"long_window"`
+	ShortWindow string  `json:"short_window"`
+	BurnRate    float64 `json:"burn_rate"`
+	Level       int     `json:"level"`
+}
+
+// 目标模型的燃烧率告警状态，按序列和策略维护
+type ObjectiveAlert struct {
+	ModelID       string            `json:"model_id"`
+	PolicyName    string            `json:"policy_name"`
+	SeriesID      string            `json:"series_id"`
+	Labels        map[string]string `json:"labels"`
+	Status        string            `json:"status"`
+	Level         int               `json:"level"`
+	Threshold     float64           `json:"threshold"`
+	LongBurnRate  float64           `json:"long_burn_rate"`
+	ShortBurnRate float64           `json:"short_burn_rate"`
+	ActiveSince   int64             `json:"active_since"`
+	LastEvalTime  int64             `json:"last_eval_time"`
+	LastEventID   string            `json:"last_event_id"`
+	LastEventTime int64             `json:"last_event_time"`
 }
 
 type KPIObjective struct {
@@ -7,6 +7,8 @@ package interfaces
 
 import (
 	"context"
+
+	cond "data-model-job/common/condition"
 )
 
 type MetricModelQuery struct {
@@ -15,6 +17,37 @@ type MetricModelQuery struct {
 	IsInstantQuery bool   `json:"instant"`         // 用于标记 instant query，默认为 false，即默认是 query_range
 }
 
+// 视图数据查询请求体, 对应 uniquery 视图数据查询 v2
+type ViewQuery struct {
+	Start          int64         `json:"start,omitempty"`
+	End            int64         `json:"end,omitempty"`
+	Offset         int           `json:"offset"`
+	Limit          int           `json:"limit"`
+	NeedTotal      bool          `json:"need_total"`
+	UseSearchAfter bool          `json:"use_search_after"`
+	SearchAfter    []any         `json:"search_after,omitempty"`
+	OutputFields   []string      `json:"output_fields,omitempty"`
+	Filters        *cond.CondCfg `json:"filters,omitempty"`
+	Sort           []*ViewSort   `json:"sort,omitempty"`
+}
+
+const (
+	ASC_DIRECTION  = "asc"
+	DESC_DIRECTION = "desc"
+)
+
+type ViewSort struct {
+	Field     string `json:"field"`
+	Direction string `json:"direction"`
+}
+
+// 视图数据查询返回结构体
+type ViewUniResponse struct {
+	Entries     []map[string]any `json:"entries"`
+	TotalCount  *int64           `json:"total_count,omitempty"`
+	SearchAfter []any            `json:"search_after,omitempty"`
+}
+
 type TimeInterval struct {
 	Interval int    `json:"interval"`
 	Unit     string `json:"unit"`
@@ -60,10 +93,22 @@ type Context struct {
 	What          string        `json:"what,omitempty"`
 	HowMuch       string        `json:"how_much,omitempty"`
 	Who           string        `json:"who,omitempty"`
+	Anomaly       *AnomalyBand  `json:"anomaly,omitempty"`
 	// AnalysisStartTime time.Time     `json:"analysis_start_time,omitempty"`
 	// AnalysisEndTime   time.Time     `json:"analysis_end_time,omitempty"`
 	// RunId             string        `json:"run_id,omitempty" form:"run_id"`
 }
+
+// 统计检测事件的正常范围和观测值
+type AnomalyBand struct {
+	Algo     string  `json:"algo"`
+	Observed float64 `json:"observed"`
+	Expected float64 `json:"expected"`
+	Lower    float64 `json:"lower"`
+	Upper    float64 `json:"upper"`
+	Score    float64 `json:"score"`
+}
+
 type EventModelData struct {
 	Id                string            `json:"id"`
 	Title             string            `json:"title"`
@@ -117,4 +162,5 @@ type UniqueryAccess interface {
 	GetMetricModelData(ctx context.Context, modelId string, query MetricModelQuery) (UniResponse, error)
 	GetEventModelData(ctx context.Context, query EventModelQueryRequest) (EventModelResponse, error)
 	GetObjectiveModelData(ctx context.Context, modelId string, query MetricModelQuery) (ObjectiveModelUniResponse, error)
+	GetViewData(ctx context.Context, viewID string, query ViewQuery) (ViewUniResponse, error)
 }
@@ -0,0 +1,556 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package logics
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"data-model-job/common"
+	cond "data-model-job/common/condition"
+	"data-model-job/interfaces"
+)
+
+var (
+	dqsOnce sync.Once
+	dqs     interfaces.DataQualityService
+)
+
+type dataQualityService struct {
+	appSetting *common.AppSetting
+	dqAccess   interfaces.DataQualityAccess
+	ddAccess   interfaces.DataDictAccess
+	uAccess    interfaces.UniqueryAccess
+	kAccess    interfaces.KafkaAccess
+	iBAccess   interfaces.IndexBaseAccess
+}
+
+func NewDataQualityService(appSetting *common.AppSetting) interfaces.DataQualityService {
+	dqsOnce.Do(func() {
+		dqs = &dataQualityService{
+			appSetting: appSetting,
+			dqAccess:   DQAccess,
+			ddAccess:   DDAccess,
+			uAccess:    UAccess,
+			kAccess:    KAccess,
+			iBAccess:   IBAccess,
+		}
+	})
+	return dqs
+}
+
+// 以规则创建者的身份通过 uniquery 查询视图数据完成一次检查. 检查结果按时间序列写入结果表,
+// 并回写规则的最近状态; 开启事件时, 状态由通过变为不通过产生事件, 恢复时产生清除事件
+func (dqService *dataQualityService) DataQualityRuleExecutor(ctx context.Context,
+	rule *interfaces.DataQualityRule) interfaces.DataQualityResult {
+
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, rule.Creator)
+
+	checkTime := time.Now().UnixMilli()
+	result := dqService.checkRule(ctx, rule, checkTime)
+	if result.Status == interfaces.DATA_QUALITY_STATUS_ERROR {
+		logger.Errorf("数据质量规则[%s]检查执行出错: %s", rule.RuleID, result.Error)
+	}
+
+	err := dqService.dqAccess.CreateDataQualityResult(ctx, result)
+	if err != nil {
+		logger.Errorf("数据质量规则[%s]写入检查结果失败[%s]", rule.RuleID, err.Error())
+	}
+
+	err = dqService.dqAccess.UpdateDataQualityRuleStatus(ctx, rule.RuleID, result.Status, checkTime)
+	if err != nil {
+		logger.Errorf("数据质量规则[%s]更新检查状态失败[%s]", rule.RuleID, err.Error())
+	}
+
+	// 执行出错时无法判断是否通过, 保留上一次的状态用于判断状态变化
+	if result.Status == interfaces.DATA_QUALITY_STATUS_ERROR {
+		return result
+	}
+
+	if rule.EmitEvent && rule.EventConfig != nil {
+		err = dqService.emitStatusEvent(ctx, rule, result)
+		if err != nil {
+			logger.Errorf("数据质量规则[%s]发送状态变化事件失败[%s]", rule.RuleID, err.Error())
+		}
+	}
+	rule.LastStatus = result.Status
+
+	return result
+}
+
+// 按规则类型执行检查, 出错时返回 error 状态的结果
+func (dqService *dataQualityService) checkRule(ctx context.Context, rule *interfaces.DataQualityRule,
+	checkTime int64) interfaces.DataQualityResult {
+
+	result := interfaces.DataQualityResult{
+		RuleID:    rule.RuleID,
+		ViewID:    rule.ViewID,
+		CheckTime: checkTime,
+		Details:   map[string]any{},
+	}
+
+	query, err := newDataQualityQuery(rule.Config, checkTime)
+	if err == nil {
+		switch rule.Kind {
+		case interfaces.DATA_QUALITY_KIND_NOT_NULL:
+			err = dqService.checkNotNull(ctx, rule, query, &result)
+		case interfaces.DATA_QUALITY_KIND_UNIQUE:
+			err = dqService.checkUnique(ctx, rule, query, &result)
+		case interfaces.DATA_QUALITY_KIND_ACCEPTED_VALUES:
+			err = dqService.checkAcceptedValues(ctx, rule, query, &result)
+		case interfaces.DATA_QUALITY_KIND_FRESHNESS:
+			err = dqService.checkFreshness(ctx, rule, query, &result)
+		case interfaces.DATA_QUALITY_KIND_ROW_COUNT:
+			err = dqService.checkRowCount(ctx, rule, query, &result)
+		case interfaces.DATA_QUALITY_KIND_CUSTOM:
+			err = dqService.checkRatio(ctx, rule, query, rule.Config.Condition, &result)
+		default:
+			err = fmt.Errorf("unsupported data quality rule kind '%s'", rule.Kind)
+		}
+	}
+
+	if err != nil {
+		result.Status = interfaces.DATA_QUALITY_STATUS_ERROR
+		result.Value = 0
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// 字段非空率
+func (dqService *dataQualityService) checkNotNull(ctx context.Context, rule *interfaces.DataQualityRule,
+	query interfaces.ViewQuery, result *interfaces.DataQualityResult) error {
+
+	notNullCond := &cond.CondCfg{
+		Name:      rule.Config.Fields[0],
+		Operation: cond.OperationNotNull,
+	}
+	return dqService.checkRatio(ctx, rule, query, notNullCond, result)
+}
+
+// 字段值在数据字典的取值范围内的占比. 不在范围内的行数由 not_in 过滤得到.
+// 空值不参与检查, 既不算达标也不算不达标, 总行数和不达标行数都只统计非空的行;
+// 空值由 not_null 规则检查. 显式过滤空值, 不依赖各数据源 not_in 对空值的处理
+func (dqService *dataQualityService) checkAcceptedValues(ctx context.Context, rule *interfaces.DataQualityRule,
+	query interfaces.ViewQuery, result *interfaces.DataQualityResult) error {
+
+	values, err := dqService.getAcceptedValues(ctx, rule.Config)
+	if err != nil {
+		return err
+	}
+
+	query.Filters = andCondition(query.Filters, &cond.CondCfg{
+		Name:      rule.Config.Fields[0],
+		Operation: cond.OperationNotNull,
+	})
+	total, err := dqService.countRows(ctx, rule.ViewID, query, nil)
+	if err != nil {
+		return err
+	}
+
+	var rejected int64
+	if total > 0 {
+		rejected, err = dqService.countRows(ctx, rule.ViewID, query, &cond.CondCfg{
+			Name:        rule.Config.Fields[0],
+			Operation:   cond.OperarionNotIn,
+			ValueOptCfg: cond.ValueOptCfg{ValueFrom: cond.ValueFrom_Const, Value: values},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	evaluateRatio(rule.Config, total, total-rejected, result)
+	result.Details["accepted_values"] = len(values)
+	return nil
+}
+
+// 满足条件的行的占比
+func (dqService *dataQualityService) checkRatio(ctx context.Context, rule *interfaces.DataQualityRule,
+	query interfaces.ViewQuery, matchCond *cond.CondCfg, result *interfaces.DataQualityResult) error {
+
+	total, err := dqService.countRows(ctx, rule.ViewID, query, nil)
+	if err != nil {
+		return err
+	}
+
+	var matched int64
+	if total > 0 {
+		matched, err = dqService.countRows(ctx, rule.ViewID, query, matchCond)
+		if err != nil {
+			return err
+		}
+	}
+
+	evaluateRatio(rule.Config, total, matched, result)
+	return nil
+}
+
+// 键集合的唯一性. 以键字段升序排序后用 search_after 翻页扫描, 重复的键相邻, 只需与上一行比较.
+// 最多扫描 sample_size 行, 数据超过 sample_size 行时只检查键最小的 sample_size 行, 不是随机抽样,
+// 键较大的数据中的重复不会被发现, 此时结果的 sampled 为 true
+func (dqService *dataQualityService) checkUnique(ctx context.Context, rule *interfaces.DataQualityRule,
+	query interfaces.ViewQuery, result *interfaces.DataQualityResult) error {
+
+	sampleSize := rule.Config.SampleSize
+	if sampleSize <= 0 {
+		sampleSize = interfaces.DEFAULT_DATA_QUALITY_SAMPLE_SIZE
+	}
+
+	query.UseSearchAfter = true
+	query.OutputFields = rule.Config.Fields
+	query.Sort = make([]*interfaces.ViewSort, 0, len(rule.Config.Fields))
+	for _, field := range rule.Config.Fields {
+		query.Sort = append(query.Sort, &interfaces.ViewSort{Field: field, Direction: interfaces.ASC_DIRECTION})
+	}
+
+	var (
+		scanned    int64
+		duplicated int64
+		prevKey    string
+		// 扫描到 sample_size 行时数据可能还没有扫完
+		sampled bool
+	)
+	for {
+		if scanned >= int64(sampleSize) {
+			sampled = true
+			break
+		}
+		query.Limit = min(sampleSize-int(scanned), interfaces.DEFAULT_DATA_QUALITY_SAMPLE_SIZE)
+
+		res, err := dqService.uAccess.GetViewData(ctx, rule.ViewID, query)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range res.Entries {
+			key := uniqueKeyOfEntry(entry, rule.Config.Fields)
+			if scanned > 0 && key == prevKey {
+				duplicated++
+			}
+			prevKey = key
+			scanned++
+		}
+
+		if len(res.Entries) < query.Limit || len(res.SearchAfter) == 0 {
+			break
+		}
+		query.SearchAfter = res.SearchAfter
+	}
+
+	evaluateRatio(rule.Config, scanned, scanned-duplicated, result)
+	result.Details["duplicated"] = duplicated
+	result.Details["sampled"] = sampled
+	return nil
+}
+
+// 时间字段的新鲜度. 取最新一条数据的时间与检查时间的差值, 单位毫秒
+func (dqService *dataQualityService) checkFreshness(ctx context.Context, rule *interfaces.DataQualityRule,
+	query interfaces.ViewQuery, result *interfaces.DataQualityResult) error {
+
+	maxDelay, err := common.ParseDuration(rule.Config.MaxDelay, common.DurationDayHourMinuteRE, true)
+	if err != nil {
+		return err
+	}
+
+	field := rule.Config.Fields[0]
+	query.Limit = 1
+	query.OutputFields = []string{field}
+	query.Sort = []*interfaces.ViewSort{{Field: field, Direction: interfaces.DESC_DIRECTION}}
+
+	res, err := dqService.uAccess.GetViewData(ctx, rule.ViewID, query)
+	if err != nil {
+		return err
+	}
+
+	result.Details["max_delay"] = common.DurationMilliseconds(maxDelay)
+	if len(res.Entries) == 0 {
+		// 没有数据视为不新鲜
+		result.Status = interfaces.DATA_QUALITY_STATUS_FAILED
+		result.Details["latest_time"] = nil
+		return nil
+	}
+
+	latest, err := parseTimeValue(res.Entries[0][field])
+	if err != nil {
+		return fmt.Errorf("the value of field '%s' is not a valid time, %v", field, err)
+	}
+
+	delay := result.CheckTime - latest
+	result.Value = float64(delay)
+	result.Details["latest_time"] = latest
+	if delay <= common.DurationMilliseconds(maxDelay) {
+		result.Status = interfaces.DATA_QUALITY_STATUS_PASSED
+	} else {
+		result.Status = interfaces.DATA_QUALITY_STATUS_FAILED
+	}
+	return nil
+}
+
+// 行数上下限
+func (dqService *dataQualityService) checkRowCount(ctx context.Context, rule *interfaces.DataQualityRule,
+	query interfaces.ViewQuery, result *interfaces.DataQualityResult) error {
+
+	total, err := dqService.countRows(ctx, rule.ViewID, query, nil)
+	if err != nil {
+		return err
+	}
+
+	result.Value = float64(total)
+	result.Status = interfaces.DATA_QUALITY_STATUS_PASSED
+	if rule.Config.MinCount != nil {
+		result.Details["min_count"] = *rule.Config.MinCount
+		if total < *rule.Config.MinCount {
+			result.Status = interfaces.DATA_QUALITY_STATUS_FAILED
+		}
+	}
+	if rule.Config.MaxCount != nil {
+		result.Details["max_count"] = *rule.Config.MaxCount
+		if total > *rule.Config.MaxCount {
+			result.Status = interfaces.DATA_QUALITY_STATUS_FAILED
+		}
+	}
+	return nil
+}
+
+// 查询检查范围内满足附加条件的行数
+func (dqService *dataQualityService) countRows(ctx context.Context, viewID string, query interfaces.ViewQuery,
+	extraCond *cond.CondCfg) (int64, error) {
+
+	query.Filters = andCondition(query.Filters, extraCond)
+	query.Limit = 1
+	query.NeedTotal = true
+
+	res, err := dqService.uAccess.GetViewData(ctx, viewID, query)
+	if err != nil {
+		return 0, err
+	}
+	if res.TotalCount == nil {
+		return 0, fmt.Errorf("the total count of view %s is not returned", viewID)
+	}
+	return *res.TotalCount, nil
+}
+
+// kv 字典取 key, 维度字典取 dict_key 指定的维度
+func (dqService *dataQualityService) getAcceptedValues(ctx context.Context, cfg interfaces.DataQualityRuleConfig) ([]string, error) {
+	dict, err := dqService.ddAccess.GetDataDict(ctx, cfg.DictID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := cfg.DictKey
+	if key == "" && len(dict.Dimension.Keys) > 0 {
+		key = dict.Dimension.Keys[0].Name
+	}
+	if key == "" {
+		return nil, fmt.Errorf("the key of data dict %s is not specified", cfg.DictID)
+	}
+
+	values := make([]string, 0, len(dict.DictItems))
+	seen := make(map[string]struct{}, len(dict.DictItems))
+	for _, item := range dict.DictItems {
+		v, ok := item[key]
+		if !ok {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		values = append(values, v)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("data dict %s has no values of key '%s'", cfg.DictID, key)
+	}
+
+	return values, nil
+}
+
+// 状态由非不通过变为不通过时产生事件, 由不通过变为通过时产生清除事件
+func (dqService *dataQualityService) emitStatusEvent(ctx context.Context, rule *interfaces.DataQualityRule,
+	result interfaces.DataQualityResult) error {
+
+	prevFailed := rule.LastStatus == interfaces.DATA_QUALITY_STATUS_FAILED
+	failed := result.Status == interfaces.DATA_QUALITY_STATUS_FAILED
+	if prevFailed == failed {
+		return nil
+	}
+
+	indexBases, err := dqService.iBAccess.GetIndexBasesByTypes(ctx, []string{rule.EventConfig.IndexBase})
+	if err != nil {
+		return err
+	}
+	if len(indexBases) != 1 {
+		return fmt.Errorf("数据质量规则[%s]事件的索引库类型[%s]对应的索引库数量不等于1,为[%d]",
+			rule.RuleID, rule.EventConfig.IndexBase, len(indexBases))
+	}
+
+	level := rule.EventConfig.Level
+	if level <= 0 || level >= interfaces.DATA_QUALITY_CLEARED_LEVEL {
+		level = interfaces.DEFAULT_DATA_QUALITY_LEVEL
+	}
+	if !failed {
+		level = interfaces.DATA_QUALITY_CLEARED_LEVEL
+	}
+	event := newDataQualityEvent(rule, result, level)
+
+	topic := fmt.Sprintf(interfaces.MODEL_PERSIST_INPUT, dqService.appSetting.MQSetting.Tenant)
+	messages := make([]*kafka.Message, 0, 1)
+	err = eventDataToKafkaMessages(topic, []interfaces.EventModelData{event}, indexBases[0], &messages)
+	if err != nil {
+		return err
+	}
+
+	producer, err := dqService.kAccess.NewTrxProducer(fmt.Sprintf("%s-%s", rule.RuleID, topic))
+	if err != nil {
+		return err
+	}
+	defer producer.Close()
+
+	return dqService.kAccess.DoProduceMsgToKafka(producer, messages)
+}
+
+// 组装数据质量事件, 数据源为规则所属的视图
+func newDataQualityEvent(rule *interfaces.DataQualityRule, result interfaces.DataQualityResult,
+	level int) interfaces.EventModelData {
+
+	md5Hasher := md5.New()
+	md5Hasher.Write([]byte(fmt.Sprintf("%s,%d,%d", rule.RuleID, level, result.CheckTime)))
+	eventID := hex.EncodeToString(md5Hasher.Sum(nil))
+
+	message := fmt.Sprintf("数据质量规则[%s]检查不通过: 类型[%s], 检查值 %v", rule.RuleName, rule.Kind, result.Value)
+	if level == interfaces.DATA_QUALITY_CLEARED_LEVEL {
+		message = fmt.Sprintf("数据质量规则[%s]检查已恢复通过", rule.RuleName)
+	}
+
+	triggerData := map[string]any{"value": result.Value}
+	for k, v := range result.Details {
+		triggerData[k] = v
+	}
+
+	return interfaces.EventModelData{
+		Id:           eventID,
+		Title:        rule.RuleName,
+		EventModelId: rule.RuleID,
+		EventType:    interfaces.DATA_QUALITY_EVENT_TYPE,
+		Level:        level,
+		GenerateType: interfaces.GENERATE_TYPE_FOR_BATCH,
+		LevelName:    interfaces.BURN_RATE_EVENT_LEVEL_NAME[level],
+		Message:      message,
+		Context: interfaces.Context{
+			Score: result.Value,
+			Level: level,
+		},
+		TriggerTime:    result.CheckTime,
+		TriggerData:    interfaces.Records{triggerData},
+		Tags:           rule.Tags,
+		EventModelName: rule.RuleName,
+		CreateTime:     result.CheckTime,
+		DataSource:     []string{rule.ViewID},
+		DataSourceType: interfaces.DATA_VIEW,
+		Labels: map[string]string{
+			"rule_id":   rule.RuleID,
+			"rule_kind": rule.Kind,
+			"view_id":   rule.ViewID,
+		},
+		DetectType: interfaces.DATA_QUALITY_DETECT_TYPE,
+		DetectAlgo: rule.Kind,
+	}
+}
+
+// 检查范围: 过滤条件和以检查时间为终点的时间窗口
+func newDataQualityQuery(cfg interfaces.DataQualityRuleConfig, checkTime int64) (interfaces.ViewQuery, error) {
+	query := interfaces.ViewQuery{
+		Filters: cfg.Filters,
+	}
+
+	if cfg.TimeWindow != "" {
+		window, err := common.ParseDuration(cfg.TimeWindow, common.DurationDayHourMinuteRE, true)
+		if err != nil {
+			return query, err
+		}
+		query.Start = checkTime - common.DurationMilliseconds(window)
+		query.End = checkTime
+	}
+
+	return query, nil
+}
+
+// 根据达标行数和总行数计算占比, 与 min_ratio 比较. 没有数据时视为通过
+func evaluateRatio(cfg interfaces.DataQualityRuleConfig, total, matched int64, result *interfaces.DataQualityResult) {
+	minRatio := 1.0
+	if cfg.MinRatio != nil {
+		minRatio = *cfg.MinRatio
+	}
+
+	ratio := 1.0
+	if total > 0 {
+		ratio = float64(matched) / float64(total)
+	}
+
+	result.Value = ratio
+	result.Details["total"] = total
+	result.Details["matched"] = matched
+	result.Details["min_ratio"] = minRatio
+	if ratio >= minRatio {
+		result.Status = interfaces.DATA_QUALITY_STATUS_PASSED
+	} else {
+		result.Status = interfaces.DATA_QUALITY_STATUS_FAILED
+	}
+}
+
+func andCondition(base, extra *cond.CondCfg) *cond.CondCfg {
+	if base == nil {
+		return extra
+	}
+	if extra == nil {
+		return base
+	}
+	return &cond.CondCfg{
+		Operation: cond.OperationAnd,
+		SubConds:  []*cond.CondCfg{base, extra},
+	}
+}
+
+// 多个键字段的值拼接为一个键, 空值也参与比较
+func uniqueKeyOfEntry(entry map[string]any, fields []string) string {
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		values[i] = fmt.Sprintf("%v", entry[field])
+	}
+	return strings.Join(values, "\x1f")
+}
+
+// 时间字段的值可能是毫秒时间戳或 RFC3339 格式的字符串
+func parseTimeValue(v any) (int64, error) {
+	switch val := v.(type) {
+	case float64:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	case string:
+		if ms, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return ms, nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, val)
+		if err != nil {
+			return 0, err
+		}
+		return t.UnixMilli(), nil
+	default:
+		return 0, fmt.Errorf("unsupported time value %v", v)
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package logics
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model-job/common"
+	cond "data-model-job/common/condition"
+	"data-model-job/interfaces"
+	dmock "data-model-job/interfaces/mock"
+)
+
+func MockNewDataQualityService(appSetting *common.AppSetting, dqAccess interfaces.DataQualityAccess,
+	ddAccess interfaces.DataDictAccess, uAccess interfaces.UniqueryAccess, kAccess interfaces.KafkaAccess,
+	iBAccess interfaces.IndexBaseAccess) *dataQualityService {
+
+	return &dataQualityService{
+		appSetting: appSetting,
+		dqAccess:   dqAccess,
+		ddAccess:   ddAccess,
+		uAccess:    uAccess,
+		kAccess:    kAccess,
+		iBAccess:   iBAccess,
+	}
+}
+
+func viewTotal(total int64) interfaces.ViewUniResponse {
+	return interfaces.ViewUniResponse{TotalCount: &total}
+}
+
+func Test_DataQualityService_DataQualityRuleExecutor(t *testing.T) {
+	Convey("Test DataQualityRuleExecutor", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		dqaMock := dmock.NewMockDataQualityAccess(mockCtrl)
+		ddaMock := dmock.NewMockDataDictAccess(mockCtrl)
+		uaMock := dmock.NewMockUniqueryAccess(mockCtrl)
+		kaMock := dmock.NewMockKafkaAccess(mockCtrl)
+		ibaMock := dmock.NewMockIndexBaseAccess(mockCtrl)
+		dqsMock := MockNewDataQualityService(appSetting, dqaMock, ddaMock, uaMock, kaMock, ibaMock)
+
+		producer := &kafka.Producer{}
+		patch := ApplyMethod(reflect.TypeOf(producer), "Close",
+			func(*kafka.Producer) {
+				// nothing to do
+			},
+		)
+		defer patch.Reset()
+
+		minRatio := 0.9
+		rule := &interfaces.DataQualityRule{
+			RuleID:   "r1",
+			RuleName: "rule1",
+			ViewID:   "v1",
+			Kind:     interfaces.DATA_QUALITY_KIND_NOT_NULL,
+			Config: interfaces.DataQualityRuleConfig{
+				Fields:   []string{"a"},
+				MinRatio: &minRatio,
+			},
+			EmitEvent:   true,
+			EventConfig: &interfaces.DataQualityEventCfg{IndexBase: "event_base", Level: 2},
+		}
+
+		Convey("Error status, caused by uniquery error, keep last status", func() {
+			rule.LastStatus = interfaces.DATA_QUALITY_STATUS_PASSED
+			uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).
+				Return(interfaces.ViewUniResponse{}, fmt.Errorf("error"))
+			dqaMock.EXPECT().CreateDataQualityResult(gomock.Any(), gomock.Any()).Return(nil)
+			dqaMock.EXPECT().UpdateDataQualityRuleStatus(gomock.Any(), "r1", interfaces.DATA_QUALITY_STATUS_ERROR,
+				gomock.Any()).Return(nil)
+
+			result := dqsMock.DataQualityRuleExecutor(testCtx, rule)
+			So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_ERROR)
+			So(result.Error, ShouldEqual, "error")
+			So(rule.LastStatus, ShouldEqual, interfaces.DATA_QUALITY_STATUS_PASSED)
+		})
+
+		Convey("Failed, emit event when status changes to failed", func() {
+			rule.LastStatus = interfaces.DATA_QUALITY_STATUS_PASSED
+			gomock.InOrder(
+				uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).Return(viewTotal(10), nil),
+				uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).
+					DoAndReturn(func(_ any, _ string, query interfaces.ViewQuery) (interfaces.ViewUniResponse, error) {
+						So(query.Filters.Operation, ShouldEqual, cond.OperationNotNull)
+						So(query.Filters.Name, ShouldEqual, "a")
+						return viewTotal(8), nil
+					}),
+			)
+			dqaMock.EXPECT().CreateDataQualityResult(gomock.Any(), gomock.Any()).Return(nil)
+			dqaMock.EXPECT().UpdateDataQualityRuleStatus(gomock.Any(), "r1", interfaces.DATA_QUALITY_STATUS_FAILED,
+				gomock.Any()).Return(nil)
+			ibaMock.EXPECT().GetIndexBasesByTypes(gomock.Any(), []string{"event_base"}).
+				Return([]interfaces.IndexBase{{SimpleIndexBase: interfaces.SimpleIndexBase{BaseType: "event_base"}}}, nil)
+			kaMock.EXPECT().NewTrxProducer(gomock.Any()).Return(producer, nil)
+			kaMock.EXPECT().DoProduceMsgToKafka(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(p *kafka.Producer, messages []*kafka.Message) error {
+					So(len(messages), ShouldEqual, 1)
+					return nil
+				})
+
+			result := dqsMock.DataQualityRuleExecutor(testCtx, rule)
+			So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_FAILED)
+			So(result.Value, ShouldEqual, 0.8)
+			So(rule.LastStatus, ShouldEqual, interfaces.DATA_QUALITY_STATUS_FAILED)
+		})
+
+		Convey("Failed again, no event emitted", func() {
+			rule.LastStatus = interfaces.DATA_QUALITY_STATUS_FAILED
+			uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).Return(viewTotal(10), nil)
+			uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).Return(viewTotal(8), nil)
+			dqaMock.EXPECT().CreateDataQualityResult(gomock.Any(), gomock.Any()).Return(nil)
+			dqaMock.EXPECT().UpdateDataQualityRuleStatus(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any()).Return(nil)
+
+			result := dqsMock.DataQualityRuleExecutor(testCtx, rule)
+			So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_FAILED)
+		})
+
+		Convey("Passed, emit cleared event when recovered", func() {
+			rule.LastStatus = interfaces.DATA_QUALITY_STATUS_FAILED
+			uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).Return(viewTotal(10), nil)
+			uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).Return(viewTotal(10), nil)
+			dqaMock.EXPECT().CreateDataQualityResult(gomock.Any(), gomock.Any()).Return(nil)
+			dqaMock.EXPECT().UpdateDataQualityRuleStatus(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any()).Return(nil)
+			ibaMock.EXPECT().GetIndexBasesByTypes(gomock.Any(), gomock.Any()).
+				Return([]interfaces.IndexBase{{SimpleIndexBase: interfaces.SimpleIndexBase{BaseType: "event_base"}}}, nil)
+			kaMock.EXPECT().NewTrxProducer(gomock.Any()).Return(producer, nil)
+			kaMock.EXPECT().DoProduceMsgToKafka(gomock.Any(), gomock.Any()).Return(nil)
+
+			result := dqsMock.DataQualityRuleExecutor(testCtx, rule)
+			So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_PASSED)
+			So(rule.LastStatus, ShouldEqual, interfaces.DATA_QUALITY_STATUS_PASSED)
+		})
+	})
+}
+
+func Test_DataQualityService_checkRule(t *testing.T) {
+	Convey("Test checkRule", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		ddaMock := dmock.NewMockDataDictAccess(mockCtrl)
+		uaMock := dmock.NewMockUniqueryAccess(mockCtrl)
+		dqsMock := MockNewDataQualityService(appSetting, nil, ddaMock, uaMock, nil, nil)
+
+		checkTime := int64(1699336800000)
+
+		Convey("Unsupported kind", func() {
+			rule := &interfaces.DataQualityRule{RuleID: "r1", ViewID: "v1", Kind: "xxx"}
+
+			result := dqsMock.checkRule(testCtx, rule, checkTime)
+			So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_ERROR)
+		})
+
+		Convey("Row count with time window, out of bounds", func() {
+			minCount, maxCount := int64(1), int64(5)
+			rule := &interfaces.DataQualityRule{RuleID: "r1", ViewID: "v1", Kind: interfaces.DATA_QUALITY_KIND_ROW_COUNT,
+				Config: interfaces.DataQualityRuleConfig{TimeWindow: "1h", MinCount: &minCount, MaxCount: &maxCount}}
+			uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).
+				DoAndReturn(func(_ any, _ string, query interfaces.ViewQuery) (interfaces.ViewUniResponse, error) {
+					So(query.Start, ShouldEqual, checkTime-3600000)
+					So(query.End, ShouldEqual, checkTime)
+					So(query.NeedTotal, ShouldBeTrue)
+					return viewTotal(6), nil
+				})
+
+			result := dqsMock.checkRule(testCtx, rule, checkTime)
+			So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_FAILED)
+			So(result.Value, ShouldEqual, 6)
+		})
+
+		Convey("Row count, total count not returned", func() {
+			rule := &interfaces.DataQualityRule{RuleID: "r1", ViewID: "v1", Kind: interfaces.DATA_QUALITY_KIND_ROW_COUNT}
+			uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).Return(interfaces.ViewUniResponse{}, nil)
+
+			result := dqsMock.checkRule(testCtx, rule, checkTime)
+			So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_ERROR)
+		})
+
+		Convey("Unique, with duplicated keys across pages", func() {
+			rule := &interfaces.DataQualityRule{RuleID: "r1", ViewID: "v1", Kind: interfaces.DATA_QUALITY_KIND_UNIQUE,
+				Config: interfaces.DataQualityRuleConfig{Fields: []string{"a", "b"}, SampleSize: 4}}
+			gomock.InOrder(
+				uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).
+					DoAndReturn(func(_ any, _ string, query interfaces.ViewQuery) (interfaces.ViewUniResponse, error) {
+						So(query.Limit, ShouldEqual, 4)
+						So(query.UseSearchAfter, ShouldBeTrue)
+						So(len(query.Sort), ShouldEqual, 2)
+						return interfaces.ViewUniResponse{
+							Entries: []map[string]any{
+								{"a": "1", "b": "x"}, {"a": "1", "b": "y"}, {"a": "1", "b": "y"}, {"a": "2", "b": "x"},
+							},
+							SearchAfter: []any{"2", "x"},
+						}, nil
+					}),
+			)
+
+			result := dqsMock.checkRule(testCtx, rule, checkTime)
+			So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_FAILED)
+			So(result.Value, ShouldEqual, 0.75)
+			So(result.Details["duplicated"], ShouldEqual, 1)
+			So(result.Details["sampled"], ShouldBeTrue)
+		})
+
+		Convey("Unique, duplicated key spans two pages", func() {
+			rule := &interfaces.DataQualityRule{RuleID: "r1", ViewID: "v1", Kind: interfaces.DATA_QUALITY_KIND_UNIQUE,
+				Config: interfaces.DataQualityRuleConfig{Fields: []string{"a"}, SampleSize: 20000}}
+			gomock.InOrder(
+				uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).
+					DoAndReturn(func(_ any, _ string, query interfaces.ViewQuery) (interfaces.ViewUniResponse, error) {
+						So(query.Limit, ShouldEqual, interfaces.DEFAULT_DATA_QUALITY_SAMPLE_SIZE)
+						entries := make([]map[string]any, 0, query.Limit)
+						for i := 0; i < query.Limit; i++ {
+							entries = append(entries, map[string]any{"a": i})
+						}
+						return interfaces.ViewUniResponse{Entries: entries, SearchAfter: []any{query.Limit - 1}}, nil
+					}),
+				uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).
+					DoAndReturn(func(_ any, _ string, query interfaces.ViewQuery) (interfaces.ViewUniResponse, error) {
+						So(query.SearchAfter, ShouldResemble, []any{interfaces.DEFAULT_DATA_QUALITY_SAMPLE_SIZE - 1})
+						// 上一页的最后一个键在本页开头重复出现
+						return interfaces.ViewUniResponse{
+							Entries: []map[string]any{{"a": interfaces.DEFAULT_DATA_QUALITY_SAMPLE_SIZE - 1}, {"a": "z"}},
+						}, nil
+					}),
+			)
+
+			result := dqsMock.checkRule(testCtx, rule, checkTime)
+			So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_FAILED)
+			So(result.Details["total"], ShouldEqual, interfaces.DEFAULT_DATA_QUALITY_SAMPLE_SIZE+2)
+			So(result.Details["duplicated"], ShouldEqual, 1)
+			So(result.Details["sampled"], ShouldBeFalse)
+		})
+
+		Convey("Accepted values from data dict", func() {
+			rule := &interfaces.DataQualityRule{RuleID: "r1", ViewID: "v1", Kind: interfaces.DATA_QUALITY_KIND_ACCEPTED_VALUES,
+				Config: interfaces.DataQualityRuleConfig{Fields: []string{"a"}, DictID: "d1"}}
+			ddaMock.EXPECT().GetDataDict(gomock.Any(), "d1").Return(interfaces.DataDict{
+				DictItems: []map[string]string{{"key": "k1"}, {"key": "k2"}, {"key": "k1"}},
+				Dimension: interfaces.Dimension{Keys: []interfaces.DimensionItem{{Name: "key"}}},
+			}, nil)
+			gomock.InOrder(
+				uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).
+					DoAndReturn(func(_ any, _ string, query interfaces.ViewQuery) (interfaces.ViewUniResponse, error) {
+						// 空值不参与检查, 总行数只统计非空的行
+						So(query.Filters.Name, ShouldEqual, "a")
+						So(query.Filters.Operation, ShouldEqual, cond.OperationNotNull)
+						return viewTotal(4), nil
+					}),
+				uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).
+					DoAndReturn(func(_ any, _ string, query interfaces.ViewQuery) (interfaces.ViewUniResponse, error) {
+						So(query.Filters.Operation, ShouldEqual, cond.OperationAnd)
+						So(query.Filters.SubConds[0].Operation, ShouldEqual, cond.OperationNotNull)
+						So(query.Filters.SubConds[1].Operation, ShouldEqual, cond.OperarionNotIn)
+						So(query.Filters.SubConds[1].Value, ShouldResemble, []string{"k1", "k2"})
+						return viewTotal(0), nil
+					}),
+			)
+
+			result := dqsMock.checkRule(testCtx, rule, checkTime)
+			So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_PASSED)
+			So(result.Value, ShouldEqual, 1)
+		})
+
+		Convey("Custom condition ANDed with filters", func() {
+			filters := &cond.CondCfg{Name: "b", Operation: "==", ValueOptCfg: cond.ValueOptCfg{Value: "x"}}
+			condition := &cond.CondCfg{Name: "a", Operation: ">", ValueOptCfg: cond.ValueOptCfg{Value: 0}}
+			rule := &interfaces.DataQualityRule{RuleID: "r1", ViewID: "v1", Kind: interfaces.DATA_QUALITY_KIND_CUSTOM,
+				Config: interfaces.DataQualityRuleConfig{Filters: filters, Condition: condition}}
+			gomock.InOrder(
+				uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).Return(viewTotal(0), nil),
+			)
+
+			result := dqsMock.checkRule(testCtx, rule, checkTime)
+			So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_PASSED)
+			So(andCondition(filters, condition).SubConds, ShouldResemble, []*cond.CondCfg{filters, condition})
+		})
+
+		Convey("Freshness", func() {
+			rule := &interfaces.DataQualityRule{RuleID: "r1", ViewID: "v1", Kind: interfaces.DATA_QUALITY_KIND_FRESHNESS,
+				Config: interfaces.DataQualityRuleConfig{Fields: []string{"@timestamp"}, MaxDelay: "10m"}}
+
+			Convey("no data", func() {
+				uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).Return(interfaces.ViewUniResponse{}, nil)
+
+				result := dqsMock.checkRule(testCtx, rule, checkTime)
+				So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_FAILED)
+			})
+
+			Convey("latest data is fresh", func() {
+				uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).
+					DoAndReturn(func(_ any, _ string, query interfaces.ViewQuery) (interfaces.ViewUniResponse, error) {
+						So(query.Sort[0].Direction, ShouldEqual, interfaces.DESC_DIRECTION)
+						return interfaces.ViewUniResponse{
+							Entries: []map[string]any{{"@timestamp": float64(checkTime - 60000)}},
+						}, nil
+					})
+
+				result := dqsMock.checkRule(testCtx, rule, checkTime)
+				So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_PASSED)
+				So(result.Value, ShouldEqual, 60000)
+			})
+
+			Convey("latest data is stale", func() {
+				uaMock.EXPECT().GetViewData(gomock.Any(), "v1", gomock.Any()).Return(interfaces.ViewUniResponse{
+					Entries: []map[string]any{{"@timestamp": "2023-11-07T05:00:00Z"}},
+				}, nil)
+
+				result := dqsMock.checkRule(testCtx, rule, checkTime)
+				So(result.Status, ShouldEqual, interfaces.DATA_QUALITY_STATUS_FAILED)
+				So(result.Value, ShouldEqual, 3600000)
+			})
+		})
+	})
+}
@@ -8,12 +8,17 @@ package logics
 import "data-model-job/interfaces"
 
 var (
-	EMAccess interfaces.EventModelAccess
-	IBAccess interfaces.IndexBaseAccess
-	JAccess  interfaces.JobAccess
-	KAccess  interfaces.KafkaAccess
-	MMAccess interfaces.MetricModelAccess
-	UAccess  interfaces.UniqueryAccess
+	DDAccess  interfaces.DataDictAccess
+	DQAccess  interfaces.DataQualityAccess
+	EMAccess  interfaces.EventModelAccess
+	IBAccess  interfaces.IndexBaseAccess
+	JAccess   interfaces.JobAccess
+	JLAccess  interfaces.JobLeaseAccess
+	KAccess   interfaces.KafkaAccess
+	MMAccess  interfaces.MetricModelAccess
+	MTBAccess interfaces.MetricTaskBackfillAccess
+	OAAccess  interfaces.ObjectiveAlertAccess
+	UAccess   interfaces.UniqueryAccess
 )
 
 func SetJobAccess(jAccess interfaces.JobAccess) {
@@ -39,3 +44,23 @@ func SetMetricModelAccess(mmAccess interfaces.MetricModelAccess) {
 func SetEventModelAccess(emAccess interfaces.EventModelAccess) {
 	EMAccess = emAccess
 }
+
+func SetObjectiveAlertAccess(oaAccess interfaces.ObjectiveAlertAccess) {
+	OAAccess = oaAccess
+}
+
+func SetJobLeaseAccess(jlAccess interfaces.JobLeaseAccess) {
+	JLAccess = jlAccess
+}
+
+func SetMetricTaskBackfillAccess(mtbAccess interfaces.MetricTaskBackfillAccess) {
+	MTBAccess = mtbAccess
+}
+
+func SetDataDictAccess(ddAccess interfaces.DataDictAccess) {
+	DDAccess = ddAccess
+}
+
+func SetDataQualityAccess(dqAccess interfaces.DataQualityAccess) {
+	DQAccess = dqAccess
+}
@@ -183,6 +183,12 @@ func (etService *eventTaskService) eventDataTransferToMessage(events []interface
 	indexbase interfaces.IndexBase, messages *[]*kafka.Message) error {
 
 	topic := fmt.Sprintf(interfaces.MODEL_PERSIST_INPUT, etService.appSetting.MQSetting.Tenant)
+	return eventDataToKafkaMessages(topic, events, indexbase, messages)
+}
+
+// 事件数据组装为写入 topic 的 kafka message，事件模型任务与目标模型燃烧率告警共用
+func eventDataToKafkaMessages(topic string, events []interfaces.EventModelData,
+	indexbase interfaces.IndexBase, messages *[]*kafka.Message) error {
 
 	for _, event := range events {
 		if event.GenerateType == "" {
@@ -0,0 +1,180 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package logics
+
+import (
+	"context"
+	"time"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+
+	"data-model-job/interfaces"
+)
+
+var (
+	DEFAULT_LEASE_TTL = 30 * time.Second
+)
+
+// 定时续约本副本持有的任务租约, 并同步指标类、事件和数据质量规则的定时任务及补数任务
+// 1. 续约失败的任务说明已由其他副本接管, 在本副本停止运行
+// 2. 同步时只启动没有存活副本持有的任务, 失联副本的任务在其租约过期后被接管
+func (jService *jobService) KeepJobLeases(interval time.Duration) {
+	logger.Infof("Keep job leases as replica %s, interval: %s, ttl: %s", jService.replicaID, interval, jService.leaseTTL)
+
+	for {
+		jService.renewJobLeases()
+
+		// 恢复或同步指标类（指标、目标）的任务
+		jService.recoverMetricJobs()
+
+		// 恢复或同步事件的定时任务
+		jService.recoverEventJobs()
+
+		// 恢复或同步数据质量规则的定时检查
+		jService.recoverQualityJobs()
+
+		// 领取待执行的补数任务
+		jService.recoverBackfills()
+
+		time.Sleep(interval)
+	}
+}
+
+// 获取或续约任务租约, 返回本副本是否持有该任务
+func (jService *jobService) acquireJobLease(ctx context.Context, jobID string) (bool, error) {
+	now := time.Now()
+	expireTime := now.Add(jService.leaseTTL).UnixMilli()
+
+	owned, err := jService.jlAccess.AcquireJobLease(ctx, interfaces.JobLease{
+		JobID:      jobID,
+		Owner:      jService.replicaID,
+		ExpireTime: expireTime,
+		UpdateTime: now.UnixMilli(),
+	})
+	if err != nil {
+		logger.Errorf("Acquire lease of job %s failed, %v", jobID, err)
+		return false, err
+	}
+
+	if owned {
+		jService.leases.Store(jobID, expireTime)
+	} else {
+		jService.leases.Delete(jobID)
+	}
+
+	return owned, nil
+}
+
+// 释放本副本持有的任务租约
+func (jService *jobService) releaseJobLease(ctx context.Context, jobID string) {
+	jService.leases.Delete(jobID)
+
+	err := jService.jlAccess.ReleaseJobLease(ctx, jobID, jService.replicaID)
+	if err != nil {
+		// 释放失败时等待租约自然过期
+		logger.Errorf("Release lease of job %s failed, %v", jobID, err)
+	}
+}
+
+// 续约本副本运行中的任务的租约
+func (jService *jobService) renewJobLeases() {
+	ctx := context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+
+	for _, jobID := range jService.localJobIDs() {
+		owned, err := jService.acquireJobLease(ctx, jobID)
+		if err != nil {
+			// 续约出错时, 本地记录的租约未过期则继续运行, 过期后停止, 避免与接管的副本重复运行
+			if expireTime, ok := jService.leases.Load(jobID); ok && expireTime.(int64) > time.Now().UnixMilli() {
+				continue
+			}
+		} else if owned {
+			continue
+		}
+
+		logger.Warnf("Lease of job %s is lost, stop it on replica %s", jobID, jService.replicaID)
+		jService.releaseLocalJob(jobID)
+	}
+}
+
+// 过滤掉由其他存活副本持有的任务. 仅用于减少无效的租约竞争, 是否持有以获取租约的结果为准
+func (jService *jobService) filterClaimableJobs(ctx context.Context, jobs []*interfaces.JobInfo) []*interfaces.JobInfo {
+	if len(jobs) == 0 {
+		return jobs
+	}
+
+	leases, err := jService.jlAccess.ListJobLeases(ctx)
+	if err != nil {
+		logger.Errorf("List job leases failed, %v", err)
+		return jobs
+	}
+
+	now := time.Now().UnixMilli()
+	ownedByOthers := make(map[string]struct{})
+	for _, lease := range leases {
+		if lease.Owner != jService.replicaID && lease.ExpireTime >= now {
+			ownedByOthers[lease.JobID] = struct{}{}
+		}
+	}
+
+	claimableJobs := make([]*interfaces.JobInfo, 0, len(jobs))
+	for _, jobInfo := range jobs {
+		if _, ok := ownedByOthers[jobInfo.JobId]; ok {
+			continue
+		}
+		claimableJobs = append(claimableJobs, jobInfo)
+	}
+
+	return claimableJobs
+}
+
+// 本副本内存中的全部任务id
+func (jService *jobService) localJobIDs() []string {
+	jobIDs := make([]string, 0)
+	jService.jobMap.Range(func(jobID, _ any) bool {
+		jobIDs = append(jobIDs, jobID.(string))
+		return true
+	})
+
+	jService.scheduler.mu.Lock()
+	for jobID := range jService.scheduler.jobs {
+		jobIDs = append(jobIDs, jobID)
+	}
+	jService.scheduler.mu.Unlock()
+
+	jService.backfills.Range(func(backfillID, _ any) bool {
+		jobIDs = append(jobIDs, backfillID.(string))
+		return true
+	})
+
+	return jobIDs
+}
+
+// 仅在本副本停止任务, 不删除任务的topic和消费组, 也不释放租约, 由接管的副本继续运行
+func (jService *jobService) releaseLocalJob(jobID string) {
+	jService.leases.Delete(jobID)
+
+	// 补数任务停止后由接管的副本从检查点继续执行
+	if cancel, ok := jService.backfills.LoadAndDelete(jobID); ok {
+		cancel.(context.CancelFunc)()
+		return
+	}
+
+	jService.scheduler.mu.Lock()
+	jobInfo, exists := jService.scheduler.jobs[jobID]
+	jService.scheduler.mu.Unlock()
+	if exists {
+		if err := jService.deleteScheduleJob(*jobInfo); err != nil {
+			logger.Errorf("Release schedule job %s failed, %v", jobID, err)
+		}
+		return
+	}
+
+	if job, ok := jService.jobMap.Load(jobID); ok {
+		job.(*Job).stopTasks()
+		jService.removeJob(jobID)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package logics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model-job/interfaces"
+	dmock "data-model-job/interfaces/mock"
+)
+
+// 内存中的任务租约, 与数据库实现的持有和过期语义一致, 用于模拟多个副本共享租约
+type memoryJobLeaseAccess struct {
+	mu     sync.Mutex
+	leases map[string]interfaces.JobLease
+	err    error
+}
+
+func newMemoryJobLeaseAccess() *memoryJobLeaseAccess {
+	return &memoryJobLeaseAccess{
+		leases: make(map[string]interfaces.JobLease),
+	}
+}
+
+func (m *memoryJobLeaseAccess) AcquireJobLease(ctx context.Context, lease interfaces.JobLease) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return false, m.err
+	}
+
+	current, ok := m.leases[lease.JobID]
+	if ok && current.Owner != lease.Owner && current.ExpireTime >= lease.UpdateTime {
+		return false, nil
+	}
+
+	m.leases[lease.JobID] = lease
+	return true, nil
+}
+
+func (m *memoryJobLeaseAccess) ReleaseJobLease(ctx context.Context, jobID string, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	if current, ok := m.leases[jobID]; ok && current.Owner == owner {
+		delete(m.leases, jobID)
+	}
+	return nil
+}
+
+func (m *memoryJobLeaseAccess) ListJobLeases(ctx context.Context) ([]interfaces.JobLease, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	leases := make([]interfaces.JobLease, 0, len(m.leases))
+	for _, lease := range m.leases {
+		leases = append(leases, lease)
+	}
+	return leases, nil
+}
+
+// 使租约立即过期, 模拟持有者失联
+func (m *memoryJobLeaseAccess) expire(jobID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if lease, ok := m.leases[jobID]; ok {
+		lease.ExpireTime = time.Now().Add(-time.Second).UnixMilli()
+		m.leases[jobID] = lease
+	}
+}
+
+func (m *memoryJobLeaseAccess) owner(jobID string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.leases[jobID].Owner
+}
+
+// 两个副本共享同一个租约存储
+func mockNewReplicas(t *testing.T) (*jobService, *jobService, *memoryJobLeaseAccess) {
+	mockCtl := gomock.NewController(t)
+
+	jaMock := dmock.NewMockJobAccess(mockCtl)
+	kaMock := dmock.NewMockKafkaAccess(mockCtl)
+	dvsMock := dmock.NewMockDataViewService(mockCtl)
+
+	leaseAccess := newMemoryJobLeaseAccess()
+
+	replicaA := MockNewJobService(jaMock, kaMock, dvsMock)
+	replicaA.jlAccess = leaseAccess
+	replicaA.replicaID = "replica-a"
+
+	replicaB := MockNewJobService(jaMock, kaMock, dvsMock)
+	replicaB.jlAccess = leaseAccess
+	replicaB.replicaID = "replica-b"
+
+	return replicaA, replicaB, leaseAccess
+}
+
+func newScheduleJobInfo(jobID string) *interfaces.JobInfo {
+	return &interfaces.JobInfo{
+		JobId:   jobID,
+		JobType: interfaces.JOB_TYPE_SCHEDULE,
+		Schedule: interfaces.Schedule{
+			Type:       interfaces.SCHEDULE_TYPE_CRON,
+			Expression: "0 * * * * ?",
+		},
+	}
+}
+
+func Test_JobService_JobLease_StartJob(t *testing.T) {
+	Convey("Test job lease when start job on replicas", t, func() {
+		replicaA, replicaB, leaseAccess := mockNewReplicas(t)
+
+		jobInfo := newScheduleJobInfo("1a")
+
+		Convey("only one replica runs the job", func() {
+			err := replicaA.StartJob(testCtx, jobInfo)
+			So(err, ShouldBeNil)
+
+			err = replicaB.StartJob(testCtx, jobInfo)
+			So(err, ShouldBeNil)
+
+			So(replicaA.scheduler.jobs, ShouldContainKey, "1a")
+			So(replicaB.scheduler.jobs, ShouldNotContainKey, "1a")
+			So(leaseAccess.owner("1a"), ShouldEqual, "replica-a")
+		})
+
+		Convey("start job again on the owner renews the lease", func() {
+			err := replicaA.StartJob(testCtx, jobInfo)
+			So(err, ShouldBeNil)
+
+			owned, err := replicaA.acquireJobLease(testCtx, "1a")
+			So(err, ShouldBeNil)
+			So(owned, ShouldBeTrue)
+		})
+
+		Convey("acquire lease failed", func() {
+			leaseAccess.err = errors.New("error")
+
+			err := replicaA.StartJob(testCtx, jobInfo)
+			So(err, ShouldNotBeNil)
+			So(replicaA.scheduler.jobs, ShouldNotContainKey, "1a")
+		})
+
+		Convey("stop job releases the lease", func() {
+			err := replicaA.StartJob(testCtx, jobInfo)
+			So(err, ShouldBeNil)
+
+			err = replicaA.StopJob(testCtx, jobInfo.JobId)
+			So(err, ShouldBeNil)
+			So(leaseAccess.owner("1a"), ShouldEqual, "")
+
+			err = replicaB.StartJob(testCtx, jobInfo)
+			So(err, ShouldBeNil)
+			So(replicaB.scheduler.jobs, ShouldContainKey, "1a")
+			So(leaseAccess.owner("1a"), ShouldEqual, "replica-b")
+		})
+	})
+}
+
+func Test_JobService_JobLease_Failover(t *testing.T) {
+	Convey("Test job failover when lease expired", t, func() {
+		replicaA, replicaB, leaseAccess := mockNewReplicas(t)
+
+		jobInfo := newScheduleJobInfo("1a")
+
+		err := replicaA.StartJob(testCtx, jobInfo)
+		So(err, ShouldBeNil)
+
+		Convey("lease not expired, the other replica can not take over", func() {
+			jobs := replicaB.filterClaimableJobs(testCtx, []*interfaces.JobInfo{jobInfo})
+			So(jobs, ShouldBeEmpty)
+		})
+
+		Convey("lease expired, the other replica takes over and the old owner stops", func() {
+			leaseAccess.expire("1a")
+
+			jobs := replicaB.filterClaimableJobs(testCtx, []*interfaces.JobInfo{jobInfo})
+			So(len(jobs), ShouldEqual, 1)
+
+			err := replicaB.StartJob(testCtx, jobs[0])
+			So(err, ShouldBeNil)
+			So(replicaB.scheduler.jobs, ShouldContainKey, "1a")
+			So(leaseAccess.owner("1a"), ShouldEqual, "replica-b")
+
+			replicaA.renewJobLeases()
+			So(replicaA.scheduler.jobs, ShouldNotContainKey, "1a")
+			So(leaseAccess.owner("1a"), ShouldEqual, "replica-b")
+		})
+	})
+}
+
+func Test_JobService_RenewJobLeases(t *testing.T) {
+	Convey("Test jobService renewJobLeases", t, func() {
+		replicaA, _, leaseAccess := mockNewReplicas(t)
+
+		jobInfo := newScheduleJobInfo("1a")
+
+		err := replicaA.StartJob(testCtx, jobInfo)
+		So(err, ShouldBeNil)
+
+		Convey("renew succeed", func() {
+			leaseAccess.expire("1a")
+
+			replicaA.renewJobLeases()
+			So(replicaA.scheduler.jobs, ShouldContainKey, "1a")
+			So(leaseAccess.owner("1a"), ShouldEqual, "replica-a")
+		})
+
+		Convey("renew failed, keep the job before local lease expired", func() {
+			leaseAccess.err = errors.New("error")
+
+			replicaA.renewJobLeases()
+			So(replicaA.scheduler.jobs, ShouldContainKey, "1a")
+		})
+
+		Convey("renew failed, stop the job after local lease expired", func() {
+			leaseAccess.err = errors.New("error")
+			replicaA.leases.Store("1a", time.Now().Add(-time.Second).UnixMilli())
+
+			replicaA.renewJobLeases()
+			So(replicaA.scheduler.jobs, ShouldNotContainKey, "1a")
+		})
+	})
+}
+
+func Test_JobService_FilterClaimableJobs(t *testing.T) {
+	Convey("Test jobService filterClaimableJobs", t, func() {
+		replicaA, replicaB, leaseAccess := mockNewReplicas(t)
+
+		jobs := []*interfaces.JobInfo{{JobId: "1a"}, {JobId: "1b"}, {JobId: "1c"}}
+
+		_, _ = replicaA.acquireJobLease(testCtx, "1a")
+		_, _ = replicaB.acquireJobLease(testCtx, "1b")
+
+		Convey("skip jobs owned by other live replicas", func() {
+			claimableJobs := replicaA.filterClaimableJobs(testCtx, jobs)
+			So(len(claimableJobs), ShouldEqual, 2)
+			So(claimableJobs[0].JobId, ShouldEqual, "1a")
+			So(claimableJobs[1].JobId, ShouldEqual, "1c")
+		})
+
+		Convey("list leases failed, return all jobs", func() {
+			leaseAccess.err = errors.New("error")
+
+			claimableJobs := replicaA.filterClaimableJobs(testCtx, jobs)
+			So(len(claimableJobs), ShouldEqual, 3)
+		})
+	})
+}
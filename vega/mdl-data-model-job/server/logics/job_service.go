@@ -46,15 +46,29 @@ type jobError struct {
 
 type jobService struct {
 	appSetting *common.AppSetting
+	dqAccess   interfaces.DataQualityAccess
+	dqService  interfaces.DataQualityService
 	dvService  interfaces.DataViewService
 	etService  interfaces.EventTaskService
 	jAccess    interfaces.JobAccess
+	jlAccess   interfaces.JobLeaseAccess
 	kAccess    interfaces.KafkaAccess
+	mtbAccess  interfaces.MetricTaskBackfillAccess
 	mtService  interfaces.MetricTaskService
 	jobMap     sync.Map
 	scheduler  *Scheduler
 	// job发生错误后将err写入errChan
 	errChan chan jobError
+
+	// 当前副本标识及任务租约有效期
+	replicaID string
+	leaseTTL  time.Duration
+	// 本副本持有的租约, jobID -> 过期时间(毫秒)
+	leases sync.Map
+	// 数据库中全部的事件任务, 用于触发不在本副本运行的下游依赖任务
+	eventJobs map[string]*interfaces.JobInfo
+	// 本副本运行中的补数任务, backfillID -> context.CancelFunc
+	backfills sync.Map
 }
 
 func NewJobService(appSetting *common.AppSetting) interfaces.JobService {
@@ -67,20 +81,39 @@ func NewJobService(appSetting *common.AppSetting) interfaces.JobService {
 		PackagePoolSize = appSetting.ServerSetting.PackagePoolSize
 		PackagePool, _ = ants.NewPool(PackagePoolSize, ants.WithPreAlloc(true), ants.WithNonblocking(false))
 
+		leaseTTL := time.Duration(appSetting.ServerSetting.LeaseTTLSec) * time.Second
+		if leaseTTL <= 0 {
+			leaseTTL = DEFAULT_LEASE_TTL
+		}
+		leaseRenewInterval := time.Duration(appSetting.ServerSetting.LeaseRenewIntervalSec) * time.Second
+		if leaseRenewInterval <= 0 || leaseRenewInterval >= leaseTTL {
+			leaseRenewInterval = leaseTTL / 3
+		}
+
 		jService = &jobService{
 			appSetting: appSetting,
 			dvService:  NewDataViewService(appSetting),
 			mtService:  NewMetricTaskService(appSetting),
 			etService:  NewEventTaskService(appSetting),
+			dqService:  NewDataQualityService(appSetting),
+			dqAccess:   DQAccess,
 			jAccess:    JAccess,
+			jlAccess:   JLAccess,
 			kAccess:    KAccess,
+			mtbAccess:  MTBAccess,
 			errChan:    make(chan jobError, 100),
 			scheduler:  NewScheduler(),
+			replicaID:  appSetting.ReplicaID,
+			leaseTTL:   leaseTTL,
+			eventJobs:  make(map[string]*interfaces.JobInfo),
 		}
 
 		// 任务自动恢复协程
 		go jService.WatchJob(appSetting.ServerSetting.WatchJobsIntervalMin * time.Minute)
 
+		// 任务租约续约协程, 同时同步指标类和事件的定时任务
+		go jService.KeepJobLeases(leaseRenewInterval)
+
 		// 开启一个协程监听job的errChan，如果监听到错误，则停止运行此job，并更新状态为失败状态
 		go jService.ListenToErrChan()
 	})
@@ -117,11 +150,25 @@ func newJob(jobInfo *interfaces.JobInfo) *Job {
 
 // 启动job
 func (jService *jobService) StartJob(ctx context.Context, jobInfo *interfaces.JobInfo) error {
+	// 任务由其他存活的副本持有时, 不在本副本启动
+	owned, err := jService.acquireJobLease(ctx, jobInfo.JobId)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		logger.Infof("Job %s is owned by another replica, skip starting it on replica %s", jobInfo.JobId, jService.replicaID)
+		return nil
+	}
+
 	// 以下是创建的是视图的job，视图的job有很多字段是指标、目标不用的，所以要根据 type 来进行不同的操作，构建不同的job类型
 	// 指标模型提交的jobInfo中包含了持久化task表中的内容和信息，所以直接把JobConfig反序列化为MetricTask.
 	if jobInfo.JobType == interfaces.JOB_TYPE_SCHEDULE {
 		// 指标类任务,按调度策略，生成不同的调度
-		return jService.addScheduleJob(jobInfo)
+		err = jService.addScheduleJob(jobInfo)
+		if err != nil {
+			jService.releaseJobLease(ctx, jobInfo.JobId)
+		}
+		return err
 	}
 
 	if _, ok := jService.jobMap.Load(jobInfo.JobId); ok {
@@ -135,7 +182,7 @@ func (jService *jobService) StartJob(ctx context.Context, jobInfo *interfaces.Jo
 	// 将job添加到内存的全局jobMap里
 	jService.addJob(dmJob.JobId, dmJob)
 
-	err := jService.prepareJob(ctx, dmJob)
+	err = jService.prepareJob(ctx, dmJob)
 	if err != nil {
 		logger.Errorf("Prepare job failed, %v", err)
 		updateJobStatusErr := jService.updateJobStatus(dmJob, interfaces.JobStatus_Error, err.Error())
@@ -166,8 +213,9 @@ func (jService *jobService) UpdateJob(ctx context.Context, jobInfo *interfaces.J
 
 	job, ok := jService.jobMap.Load(jobInfo.JobId)
 	if !ok {
-		logger.Errorf("Update a job %d that does not exist in memory", jobInfo.JobId)
-		return fmt.Errorf("update a job that does not exist in memory")
+		// 任务不在本副本运行时, 由持有租约的副本在同步时更新; 没有副本持有时在本副本启动
+		logger.Infof("Update a job %s that does not exist in memory, try to start it", jobInfo.JobId)
+		return jService.StartJob(ctx, jobInfo)
 	}
 
 	dmJob := job.(*Job)
@@ -212,7 +260,13 @@ func (jService *jobService) StopJob(ctx context.Context, jobId string) error {
 		task, exists := jService.scheduler.jobs[jobId]
 		if exists {
 			if task.JobType == interfaces.JOB_TYPE_SCHEDULE {
-				return jService.deleteScheduleJob(*task)
+				err := jService.deleteScheduleJob(*task)
+				if err != nil {
+					return err
+				}
+
+				jService.releaseJobLease(ctx, jobId)
+				return nil
 			}
 		}
 	}
@@ -245,6 +299,7 @@ func (jService *jobService) StopJob(ctx context.Context, jobId string) error {
 
 	// 从内存中移除job
 	jService.removeJob(jobId)
+	jService.releaseJobLease(ctx, jobId)
 
 	logger.Debugf("Stopped job %d", dmJob.JobId)
 	return nil
@@ -252,6 +307,7 @@ func (jService *jobService) StopJob(ctx context.Context, jobId string) error {
 
 // 服务重启时自动恢复正常和失败的任务
 // 每隔10分钟自动恢复失败的任务
+// 指标类和事件的定时任务随租约续约同步, 见 KeepJobLeases
 func (jService *jobService) WatchJob(interval time.Duration) {
 	logger.Infof("Auto recover jobs, interval: %s", interval)
 
@@ -261,12 +317,6 @@ func (jService *jobService) WatchJob(interval time.Duration) {
 		jService.recoverJobs()
 		jService.watchJobsTopic()
 
-		// 恢复或同步指标类（指标、目标）的任务
-		jService.recoverMetricJobs()
-
-		// 恢复或同步事件的定时任务
-		jService.recoverEventJobs()
-
 		time.Sleep(interval)
 	}
 }
@@ -535,6 +585,7 @@ func (jService *jobService) recoverJobs() {
 	if err != nil {
 		return
 	}
+	jobsToCreate = jService.filterClaimableJobs(ctx, jobsToCreate)
 
 	logger.Debugf("Recover: %d jobs need to create", len(jobsToCreate))
 	logger.Debugf("Recover: %d jobs need to update", len(jobsToUpdate))
@@ -819,6 +870,7 @@ func (jService *jobService) recoverMetricJobs() {
 		logger.Errorf("Metric Recover: syncMemoryMetricJobBasedOnDB failed, %s", err.Error())
 		return
 	}
+	jobsToCreate = jService.filterClaimableJobs(ctx, jobsToCreate)
 
 	logger.Debugf("Metric Recover: %d jobs need to create", len(jobsToCreate))
 	logger.Debugf("Metric Recover: %d jobs need to update", len(jobsToUpdate))
@@ -947,6 +999,7 @@ func (jService *jobService) recoverEventJobs() {
 	if err != nil {
 		return
 	}
+	jobsToCreate = jService.filterClaimableJobs(ctx, jobsToCreate)
 
 	logger.Debugf("Event Recover: %d jobs need to create", len(jobsToCreate))
 	logger.Debugf("Event Recover: %d jobs need to update", len(jobsToUpdate))
@@ -1011,6 +1064,14 @@ func (jService *jobService) syncMemoryEventJobBasedOnDB() (jobsToCreate, jobsToU
 	jService.scheduler.mu.Lock()
 	defer jService.scheduler.mu.Unlock()
 
+	// 记录全部事件任务, 下游依赖任务可能由其他副本持有
+	eventJobs := make(map[string]*interfaces.JobInfo, len(jobsMap))
+	for jobId, jobInfo := range jobsMap {
+		jobInfo := jobInfo
+		eventJobs[jobId] = &jobInfo
+	}
+	jService.eventJobs = eventJobs
+
 	for jobId, jobInfoInMem := range jService.scheduler.jobs {
 		logger.Debugf("Recover: Event job %d in memory, jobStatus %s", jobInfoInMem.JobId, jobInfoInMem.JobStatus)
 
@@ -1052,6 +1113,88 @@ func (jService *jobService) syncMemoryEventJobBasedOnDB() (jobsToCreate, jobsToU
 	return jobsToCreate, jobsToUpdate, jobsToDelete, nil
 }
 
+// 恢复或同步数据质量规则的定时任务
+func (jService *jobService) recoverQualityJobs() {
+	ctx := context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+
+	jobsToCreate, jobsToUpdate, jobsToDelete, err := jService.syncMemoryQualityJobBasedOnDB(ctx)
+	if err != nil {
+		return
+	}
+	jobsToCreate = jService.filterClaimableJobs(ctx, jobsToCreate)
+
+	logger.Debugf("Quality Recover: %d jobs need to create", len(jobsToCreate))
+	logger.Debugf("Quality Recover: %d jobs need to update", len(jobsToUpdate))
+	logger.Debugf("Quality Recover: %d jobs need to delete", len(jobsToDelete))
+
+	for _, jobInfo := range jobsToCreate {
+		if err := jService.StartJob(ctx, jobInfo); err != nil {
+			logger.Errorf("Quality Recover: create job %s failed, %s", jobInfo.JobId, err.Error())
+		}
+	}
+
+	for _, jobInfo := range jobsToUpdate {
+		if err := jService.UpdateJob(ctx, jobInfo); err != nil {
+			logger.Errorf("Quality Recover: update job %s failed, %s", jobInfo.JobId, err.Error())
+		}
+	}
+
+	for _, jobInfo := range jobsToDelete {
+		if err := jService.StopJob(ctx, jobInfo.JobId); err != nil {
+			logger.Errorf("Quality Recover: delete job %s failed, %s", jobInfo.JobId, err.Error())
+		}
+	}
+}
+
+// 同步内存和数据库的数据质量规则, 以数据库为准. 规则的任何修改都会更新 update_time,
+// 所以用 update_time 判断规则是否变化
+func (jService *jobService) syncMemoryQualityJobBasedOnDB(ctx context.Context) (jobsToCreate, jobsToUpdate,
+	jobsToDelete []*interfaces.JobInfo, err error) {
+
+	jobs, err := jService.dqAccess.ListDataQualityJobs(ctx)
+	if err != nil {
+		logger.Errorf("Recover: list data quality jobs failed, %v", err)
+		return nil, nil, nil, err
+	}
+	logger.Debugf("Recover: there are %d data quality jobs in db", len(jobs))
+
+	jobsMap := make(map[string]interfaces.JobInfo)
+	for _, v := range jobs {
+		jobsMap[v.JobId] = v
+	}
+
+	jobsToCreate = make([]*interfaces.JobInfo, 0)
+	jobsToUpdate = make([]*interfaces.JobInfo, 0)
+	jobsToDelete = make([]*interfaces.JobInfo, 0)
+
+	jService.scheduler.mu.Lock()
+	defer jService.scheduler.mu.Unlock()
+
+	for jobId, jobInfoInMem := range jService.scheduler.jobs {
+		if jobInfoInMem.ModuleType != interfaces.MODULE_TYPE_DATA_QUALITY_RULE {
+			continue
+		}
+
+		jobInfoInDB, ok := jobsMap[jobId]
+		if !ok {
+			// 规则已删除或停用
+			jobsToDelete = append(jobsToDelete, jobInfoInMem)
+		} else if jobInfoInDB.UpdateTime != jobInfoInMem.UpdateTime {
+			logger.Infof("Data quality job %s is in both memory and DB, rule in DB is %s", jobId, jobInfoInDB.QualityRule)
+			jobsToUpdate = append(jobsToUpdate, &jobInfoInDB)
+		}
+	}
+
+	for _, jobInfo := range jobsMap {
+		jobInfo := jobInfo
+		if _, ok := jService.scheduler.jobs[jobInfo.JobId]; !ok {
+			jobsToCreate = append(jobsToCreate, &jobInfo)
+		}
+	}
+
+	return jobsToCreate, jobsToUpdate, jobsToDelete, nil
+}
+
 // 比较两个指标类的任务的配置是否相等
 func compareMetricJobConfig(job1, job2 *interfaces.MetricTask) (bool, error) {
 	// 比较 taskName
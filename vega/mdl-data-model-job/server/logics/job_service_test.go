@@ -36,11 +36,15 @@ func MockNewJobService(jaMock interfaces.JobAccess, kaMock interfaces.KafkaAcces
 			},
 		},
 		jAccess:   jaMock,
+		jlAccess:  newMemoryJobLeaseAccess(),
 		kAccess:   kaMock,
 		dvService: dvsMock,
 		jobMap:    sync.Map{},
 		errChan:   make(chan jobError, 1),
 		scheduler: NewScheduler(),
+		replicaID: "replica-a",
+		leaseTTL:  DEFAULT_LEASE_TTL,
+		eventJobs: make(map[string]*interfaces.JobInfo),
 	}
 
 	return job
@@ -132,9 +136,32 @@ func Test_JobService_UpdateJob(t *testing.T) {
 			JobInfo: jobInfo,
 		}
 
-		Convey("job not in memory", func() {
+		Convey("job not in memory and owned by another replica", func() {
+			_, _ = jsMock.jlAccess.AcquireJobLease(testCtx, interfaces.JobLease{
+				JobID:      jobId,
+				Owner:      "replica-b",
+				ExpireTime: time.Now().Add(time.Minute).UnixMilli(),
+				UpdateTime: time.Now().UnixMilli(),
+			})
+
+			err := jsMock.UpdateJob(testCtx, jobInfo)
+			So(err, ShouldBeNil)
+			_, ok := jsMock.jobMap.Load(jobId)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("job not in memory, start it on this replica", func() {
+			patches := ApplyPrivateMethod(jsMock, "prepareJob", func(context.Context, *Job) error {
+				return errors.New("error")
+			})
+			defer patches.Reset()
+
+			jaMock.EXPECT().UpdateJobStatus(gomock.Any()).Return(nil)
+
 			err := jsMock.UpdateJob(testCtx, jobInfo)
 			So(err, ShouldNotBeNil)
+			_, ok := jsMock.jobMap.Load(jobId)
+			So(ok, ShouldBeTrue)
 		})
 
 		Convey("prepare job failed", func() {
@@ -695,6 +722,14 @@ func Test_JobService_recoverJobs(t *testing.T) {
 			},
 		}
 
+		// 待更新的job由其他副本持有, 本副本不接管
+		_, _ = jsMock.jlAccess.AcquireJobLease(testCtx, interfaces.JobLease{
+			JobID:      "3a",
+			Owner:      "replica-b",
+			ExpireTime: time.Now().Add(time.Minute).UnixMilli(),
+			UpdateTime: time.Now().UnixMilli(),
+		})
+
 		Convey("syncMemoryJobBasedOnDB failed", func() {
 			patches := ApplyPrivateMethod(jsMock, "syncMemoryJobBasedOnDB",
 				func() ([]*interfaces.JobInfo, []*interfaces.JobInfo, []*Job, error) {
@@ -953,6 +988,49 @@ func Test_JobService_syncMemoryJobBasedOnDB(t *testing.T) {
 	})
 }
 
+func Test_JobService_syncMemoryQualityJobBasedOnDB(t *testing.T) {
+	Convey("Test jobService syncMemoryQualityJobBasedOnDB", t, func() {
+		mockCtl := gomock.NewController(t)
+		defer mockCtl.Finish()
+
+		jaMock := dmock.NewMockJobAccess(mockCtl)
+		kaMock := dmock.NewMockKafkaAccess(mockCtl)
+		dqaMock := dmock.NewMockDataQualityAccess(mockCtl)
+		jsMock := MockNewJobService(jaMock, kaMock, nil)
+		jsMock.dqAccess = dqaMock
+
+		jsMock.scheduler.jobs["r1"] = &interfaces.JobInfo{JobId: "r1",
+			ModuleType: interfaces.MODULE_TYPE_DATA_QUALITY_RULE, UpdateTime: 1000}
+		jsMock.scheduler.jobs["r2"] = &interfaces.JobInfo{JobId: "r2",
+			ModuleType: interfaces.MODULE_TYPE_DATA_QUALITY_RULE, UpdateTime: 1000}
+		jsMock.scheduler.jobs["m1"] = &interfaces.JobInfo{JobId: "m1",
+			ModuleType: interfaces.MODULE_TYPE_METRIC_MODEL}
+
+		Convey("list data quality jobs failed", func() {
+			dqaMock.EXPECT().ListDataQualityJobs(gomock.Any()).Return(nil, errors.New("error"))
+
+			_, _, _, err := jsMock.syncMemoryQualityJobBasedOnDB(testCtx)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("create, update and delete quality jobs, other jobs untouched", func() {
+			dqaMock.EXPECT().ListDataQualityJobs(gomock.Any()).Return([]interfaces.JobInfo{
+				{JobId: "r1", ModuleType: interfaces.MODULE_TYPE_DATA_QUALITY_RULE, UpdateTime: 2000},
+				{JobId: "r3", ModuleType: interfaces.MODULE_TYPE_DATA_QUALITY_RULE, UpdateTime: 1000},
+			}, nil)
+
+			jobsToCreate, jobsToUpdate, jobsToDelete, err := jsMock.syncMemoryQualityJobBasedOnDB(testCtx)
+			So(err, ShouldBeNil)
+			So(len(jobsToCreate), ShouldEqual, 1)
+			So(jobsToCreate[0].JobId, ShouldEqual, "r3")
+			So(len(jobsToUpdate), ShouldEqual, 1)
+			So(jobsToUpdate[0].JobId, ShouldEqual, "r1")
+			So(len(jobsToDelete), ShouldEqual, 1)
+			So(jobsToDelete[0].JobId, ShouldEqual, "r2")
+		})
+	})
+}
+
 func Test_JobService_WatchJobsTopic(t *testing.T) {
 	Convey("Test jobService watchJobsTopic", t, func() {
 		mockCtl := gomock.NewController(t)
@@ -0,0 +1,271 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package logics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+
+	"data-model-job/interfaces"
+)
+
+var (
+	DEFAULT_BACKFILL_CHUNK_SIZE  = 12
+	DEFAULT_BACKFILL_INTERVAL    = time.Second
+	DEFAULT_BACKFILL_MAX_RUNNING = 2
+
+	errBackfillCanceled = errors.New("backfill canceled")
+)
+
+// 执行补数任务. 从检查点开始按批次重新计算 [start, end] 内的计划时间点, 每批写入 kafka 后记录检查点和进度
+// 1. 文档 id 由步长点和序列确定, 重新计算的数据覆盖已持久化的数据, 重复执行同一批次不会产生重复数据
+// 2. 每批开始前检查补数任务是否已被取消, 已写入的数据不回滚
+// 3. ctx 取消(租约丢失或副本停止)时不更新状态, 由接管的副本从检查点继续执行
+// 4. 不更新持久化任务的计划时间, 也不做燃烧率告警评估, 与定时执行互不影响
+func (mtService *metricTaskService) MetricTaskBackfillExecutor(ctx context.Context, metricTask interfaces.MetricTask,
+	backfill interfaces.MetricTaskBackfill) error {
+
+	// accountInfo 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, metricTask.Creator)
+
+	err := mtService.executBackfill(ctx, metricTask, &backfill)
+	if errors.Is(err, errBackfillCanceled) {
+		logger.Infof("Backfill %s of task %s is canceled at %d", backfill.ID, metricTask.TaskID, backfill.Checkpoint)
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	backfill.Status = interfaces.BACKFILL_STATUS_SUCCEEDED
+	backfill.StatusDetails = ""
+	if err != nil {
+		logger.Errorf("Backfill %s of task %s failed, %s", backfill.ID, metricTask.TaskID, err.Error())
+		backfill.Status = interfaces.BACKFILL_STATUS_FAILED
+		backfill.StatusDetails = err.Error()
+	}
+	backfill.UpdateTime = time.Now().UnixMilli()
+
+	_, updateErr := mtService.mtbAccess.UpdateBackfillProgress(ctx, backfill)
+	if updateErr != nil {
+		if err != nil {
+			return fmt.Errorf("%s:%s", err.Error(), updateErr.Error())
+		}
+		return updateErr
+	}
+	return err
+}
+
+func (mtService *metricTaskService) executBackfill(ctx context.Context, task interfaces.MetricTask,
+	backfill *interfaces.MetricTaskBackfill) error {
+
+	// 只补指定的步长
+	task.Steps = backfill.Steps
+
+	indexBases, err := mtService.iBAccess.GetIndexBasesByTypes(ctx, []string{task.IndexBase})
+	if err != nil {
+		return err
+	}
+	if len(indexBases) != 1 {
+		return fmt.Errorf("补数任务[%s]的索引库类型[%s]对应的索引库数量不等于1,为[%d]", backfill.ID, task.IndexBase, len(indexBases))
+	}
+
+	steps, err := parseTaskSteps(task.Steps)
+	if err != nil {
+		return err
+	}
+	offset, err := getTimeZoneOffset()
+	if err != nil {
+		return err
+	}
+
+	chunkSize := mtService.appSetting.ServerSetting.BackfillChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DEFAULT_BACKFILL_CHUNK_SIZE
+	}
+	interval := time.Duration(mtService.appSetting.ServerSetting.BackfillIntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = DEFAULT_BACKFILL_INTERVAL
+	}
+
+	// 补数使用独立的 uniqueId, 避免与定时执行的事务生产者互相踢出
+	topic := fmt.Sprintf(interfaces.MODEL_PERSIST_INPUT, mtService.appSetting.MQSetting.Tenant)
+	uniqueId := fmt.Sprintf("%s-%s", backfill.ID, topic)
+
+	planTime := backfill.Checkpoint
+	for planTime <= backfill.End {
+		// 每批开始前记录检查点, 同时感知补数任务是否已被取消
+		backfill.Status = interfaces.BACKFILL_STATUS_RUNNING
+		backfill.Checkpoint = planTime
+		backfill.UpdateTime = time.Now().UnixMilli()
+		active, err := mtService.mtbAccess.UpdateBackfillProgress(ctx, *backfill)
+		if err != nil {
+			return fmt.Errorf("补数任务[%s], 更新进度失败[%s]. ", backfill.ID, err.Error())
+		}
+		if !active {
+			return errBackfillCanceled
+		}
+
+		messages := make([]*kafka.Message, 0)
+		points := 0
+		for ; points < chunkSize && planTime <= backfill.End; points++ {
+			switch task.ModuleType {
+			case interfaces.MODULE_TYPE_OBJECTIVE_MODEL:
+				_, err = mtService.buildObjectiveMessages(ctx, task, planTime, steps, offset, indexBases[0], &messages)
+			default:
+				err = mtService.buildMetricMessages(ctx, task, planTime, steps, offset, indexBases[0], &messages)
+			}
+			if err != nil {
+				return err
+			}
+			planTime += interfaces.MIN_STEP
+		}
+
+		if len(messages) > 0 {
+			err = mtService.produceToKafka(uniqueId, messages)
+			if err != nil {
+				return fmt.Errorf("补数任务[%s], 把数据发送到 kafka 失败[%s]. ", backfill.ID, err.Error())
+			}
+		}
+		backfill.Checkpoint = planTime
+		backfill.DonePoints += int64(points)
+		logger.Debugf("补数任务[%s]完成[%d/%d]个时间点, 本批发送[%d]条数据到kafka", backfill.ID,
+			backfill.DonePoints, backfill.TotalPoints, len(messages))
+
+		if planTime > backfill.End {
+			break
+		}
+
+		// 批次之间限流, 避免补数挤占定时任务的查询资源
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	return nil
+}
+
+// 领取待执行和执行中的补数任务. 补数任务与定时任务一样通过租约保证只在一个副本执行,
+// 持有者失联后由其他副本从检查点继续执行. 每个副本同时执行的补数任务数受 BackfillMaxRunning 限制
+func (jService *jobService) recoverBackfills() {
+	ctx := context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+
+	backfills, err := jService.mtbAccess.ListActiveBackfills(ctx)
+	if err != nil {
+		logger.Errorf("Backfill Recover: list active backfills failed, %v", err)
+		return
+	}
+
+	running := 0
+	jService.backfills.Range(func(_, _ any) bool {
+		running++
+		return true
+	})
+	maxRunning := jService.appSetting.ServerSetting.BackfillMaxRunning
+	if maxRunning <= 0 {
+		maxRunning = DEFAULT_BACKFILL_MAX_RUNNING
+	}
+
+	candidates := make([]*interfaces.JobInfo, 0)
+	backfillMap := make(map[string]interfaces.MetricTaskBackfill)
+	for _, backfill := range backfills {
+		if _, ok := jService.backfills.Load(backfill.ID); ok {
+			continue
+		}
+		candidates = append(candidates, &interfaces.JobInfo{JobId: backfill.ID})
+		backfillMap[backfill.ID] = backfill
+	}
+	candidates = jService.filterClaimableJobs(ctx, candidates)
+	if len(candidates) == 0 || running >= maxRunning {
+		return
+	}
+
+	// 补数任务使用持久化任务的配置执行
+	tasks, err := jService.listMetricTasks()
+	if err != nil {
+		logger.Errorf("Backfill Recover: list metric tasks failed, %v", err)
+		return
+	}
+
+	for _, candidate := range candidates {
+		if running >= maxRunning {
+			break
+		}
+
+		backfill := backfillMap[candidate.JobId]
+		owned, err := jService.acquireJobLease(ctx, backfill.ID)
+		if err != nil || !owned {
+			continue
+		}
+
+		task, ok := tasks[backfill.TaskID]
+		if !ok {
+			// 持久化任务已删除, 补数任务无法继续执行
+			backfill.Status = interfaces.BACKFILL_STATUS_FAILED
+			backfill.StatusDetails = fmt.Sprintf("metric task %s not found", backfill.TaskID)
+			backfill.UpdateTime = time.Now().UnixMilli()
+			if _, err := jService.mtbAccess.UpdateBackfillProgress(ctx, backfill); err != nil {
+				logger.Errorf("Backfill Recover: update backfill %s failed, %v", backfill.ID, err)
+			}
+			jService.releaseJobLease(ctx, backfill.ID)
+			continue
+		}
+
+		jService.startBackfill(task, backfill)
+		running++
+	}
+}
+
+// 在本副本启动补数任务, 结束后释放租约
+func (jService *jobService) startBackfill(task interfaces.MetricTask, backfill interfaces.MetricTaskBackfill) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage))
+	jService.backfills.Store(backfill.ID, cancel)
+
+	logger.Infof("Start backfill %s of task %s on replica %s, checkpoint: %d", backfill.ID, task.TaskID,
+		jService.replicaID, backfill.Checkpoint)
+
+	go func() {
+		defer func() {
+			cancel()
+			jService.backfills.Delete(backfill.ID)
+			// 租约已被其他副本接管时不会被释放
+			jService.releaseJobLease(context.Background(), backfill.ID)
+		}()
+
+		err := jService.mtService.MetricTaskBackfillExecutor(ctx, task, backfill)
+		if err != nil {
+			logger.Errorf("Backfill %s stopped on replica %s, %v", backfill.ID, jService.replicaID, err)
+		}
+	}()
+}
+
+// 数据库中全部的指标、目标模型的持久化任务, taskID -> 任务
+func (jService *jobService) listMetricTasks() (map[string]interfaces.MetricTask, error) {
+	jobs, err := jService.jAccess.ListMetricJobs()
+	if err != nil {
+		return nil, err
+	}
+	objectiveJobs, err := jService.jAccess.ListObjectiveJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make(map[string]interfaces.MetricTask)
+	for _, jobInfo := range append(jobs, objectiveJobs...) {
+		if jobInfo.MetricTask != nil {
+			tasks[jobInfo.MetricTask.TaskID] = *jobInfo.MetricTask
+		}
+	}
+	return tasks, nil
+}
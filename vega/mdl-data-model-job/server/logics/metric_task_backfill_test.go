@@ -0,0 +1,265 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package logics
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model-job/common"
+	"data-model-job/interfaces"
+	dmock "data-model-job/interfaces/mock"
+)
+
+var (
+	// 2023-11-07 06:00:00 UTC, 按 5m 对齐
+	testBackfillStart int64 = 1699336800000
+
+	testBackfill = interfaces.MetricTaskBackfill{
+		ID:          "b1",
+		TaskID:      "1a",
+		ModelID:     "1",
+		ModuleType:  interfaces.MODULE_TYPE_METRIC_MODEL,
+		Start:       testBackfillStart,
+		End:         testBackfillStart + 2*interfaces.MIN_STEP,
+		Steps:       []string{"5m"},
+		Status:      interfaces.BACKFILL_STATUS_PENDING,
+		Checkpoint:  testBackfillStart,
+		TotalPoints: 3,
+	}
+)
+
+// 等待异步的补数任务达到预期状态
+func waitFor(cond func() bool) bool {
+	for i := 0; i < 200; i++ {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
+func Test_MetricTaskService_MetricTaskBackfillExecutor(t *testing.T) {
+	Convey("Test MetricTaskBackfillExecutor", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		appSetting.ServerSetting.BackfillChunkSize = 2
+		appSetting.ServerSetting.BackfillIntervalMs = 1
+		mmaMock := dmock.NewMockMetricModelAccess(mockCtrl)
+		uaMock := dmock.NewMockUniqueryAccess(mockCtrl)
+		ibaMock := dmock.NewMockIndexBaseAccess(mockCtrl)
+		kaMock := dmock.NewMockKafkaAccess(mockCtrl)
+		mtbaMock := dmock.NewMockMetricTaskBackfillAccess(mockCtrl)
+		mtsMock := MockNewMetricTaskService(appSetting, mmaMock, uaMock, kaMock, ibaMock)
+		mtsMock.mtbAccess = mtbaMock
+
+		producer := &kafka.Producer{}
+		patch := ApplyMethod(reflect.TypeOf(producer), "Close",
+			func(*kafka.Producer) {
+				// nothing to do
+			},
+		)
+		defer patch.Reset()
+
+		// 记录每次更新的进度
+		updates := make([]interfaces.MetricTaskBackfill, 0)
+		recordUpdate := func(active bool) func(context.Context, interfaces.MetricTaskBackfill) (bool, error) {
+			return func(ctx context.Context, backfill interfaces.MetricTaskBackfill) (bool, error) {
+				updates = append(updates, backfill)
+				return active, nil
+			}
+		}
+
+		ibaMock.EXPECT().GetIndexBasesByTypes(gomock.Any(), gomock.Any()).AnyTimes().
+			Return([]interfaces.IndexBase{{SimpleIndexBase: interfaces.SimpleIndexBase{BaseType: "1"}, DataType: "1"}}, nil)
+
+		Convey("succeed, overwrite data chunk by chunk without touching plan time", func() {
+			uaMock.EXPECT().GetMetricModelData(gomock.Any(), gomock.Any(), gomock.Any()).Times(6).
+				Return(uniqueryData, nil)
+			uniqueIds := make([]string, 0)
+			kaMock.EXPECT().NewTrxProducer(gomock.Any()).Times(2).DoAndReturn(func(uniqueId string) (*kafka.Producer, error) {
+				uniqueIds = append(uniqueIds, uniqueId)
+				return producer, nil
+			})
+			kaMock.EXPECT().DoProduceMsgToKafka(gomock.Any(), gomock.Any()).Times(2).Return(nil)
+			mtbaMock.EXPECT().UpdateBackfillProgress(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(recordUpdate(true))
+
+			err := mtsMock.MetricTaskBackfillExecutor(testCtx, task, testBackfill)
+			So(err, ShouldBeNil)
+
+			So(strings.HasPrefix(uniqueIds[0], "b1-"), ShouldBeTrue)
+			So(updates[0].Status, ShouldEqual, interfaces.BACKFILL_STATUS_RUNNING)
+			So(updates[0].Checkpoint, ShouldEqual, testBackfillStart)
+			So(updates[1].Status, ShouldEqual, interfaces.BACKFILL_STATUS_RUNNING)
+			So(updates[1].Checkpoint, ShouldEqual, testBackfillStart+2*interfaces.MIN_STEP)
+			So(updates[1].DonePoints, ShouldEqual, 2)
+			So(updates[2].Status, ShouldEqual, interfaces.BACKFILL_STATUS_SUCCEEDED)
+			So(updates[2].DonePoints, ShouldEqual, 3)
+		})
+
+		Convey("resume from checkpoint", func() {
+			backfill := testBackfill
+			backfill.Checkpoint = testBackfillStart + 2*interfaces.MIN_STEP
+			backfill.DonePoints = 2
+
+			uaMock.EXPECT().GetMetricModelData(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+				Return(interfaces.UniResponse{}, nil)
+			mtbaMock.EXPECT().UpdateBackfillProgress(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(recordUpdate(true))
+
+			err := mtsMock.MetricTaskBackfillExecutor(testCtx, task, backfill)
+			So(err, ShouldBeNil)
+			So(updates[1].Status, ShouldEqual, interfaces.BACKFILL_STATUS_SUCCEEDED)
+			So(updates[1].DonePoints, ShouldEqual, 3)
+		})
+
+		Convey("stop before the next chunk when canceled", func() {
+			mtbaMock.EXPECT().UpdateBackfillProgress(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(recordUpdate(false))
+
+			err := mtsMock.MetricTaskBackfillExecutor(testCtx, task, testBackfill)
+			So(err, ShouldBeNil)
+			So(len(updates), ShouldEqual, 1)
+		})
+
+		Convey("failed, cause by GetMetricModelData error", func() {
+			uaMock.EXPECT().GetMetricModelData(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(interfaces.UniResponse{}, fmt.Errorf("error"))
+			mtbaMock.EXPECT().UpdateBackfillProgress(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(recordUpdate(true))
+
+			err := mtsMock.MetricTaskBackfillExecutor(testCtx, task, testBackfill)
+			So(err, ShouldNotBeNil)
+			So(updates[1].Status, ShouldEqual, interfaces.BACKFILL_STATUS_FAILED)
+			So(updates[1].StatusDetails, ShouldEqual, err.Error())
+		})
+
+		Convey("failed, cause by UpdateBackfillProgress error", func() {
+			mtbaMock.EXPECT().UpdateBackfillProgress(gomock.Any(), gomock.Any()).Times(2).
+				Return(false, fmt.Errorf("error"))
+
+			err := mtsMock.MetricTaskBackfillExecutor(testCtx, task, testBackfill)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("keep status when context canceled", func() {
+			ctx, cancel := context.WithCancel(testCtx)
+			uaMock.EXPECT().GetMetricModelData(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, modelID string, query interfaces.MetricModelQuery) (interfaces.UniResponse, error) {
+					cancel()
+					return interfaces.UniResponse{}, ctx.Err()
+				})
+			mtbaMock.EXPECT().UpdateBackfillProgress(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(recordUpdate(true))
+
+			err := mtsMock.MetricTaskBackfillExecutor(ctx, task, testBackfill)
+			So(err, ShouldEqual, context.Canceled)
+			So(len(updates), ShouldEqual, 1)
+		})
+	})
+}
+
+func Test_JobService_RecoverBackfills(t *testing.T) {
+	Convey("Test jobService recoverBackfills", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		replicaA, replicaB, leaseAccess := mockNewReplicas(t)
+		mtbaMock := dmock.NewMockMetricTaskBackfillAccess(mockCtrl)
+		mtsMock := dmock.NewMockMetricTaskService(mockCtrl)
+		jaMock := dmock.NewMockJobAccess(mockCtrl)
+		for _, replica := range []*jobService{replicaA, replicaB} {
+			replica.mtbAccess = mtbaMock
+			replica.mtService = mtsMock
+			replica.jAccess = jaMock
+		}
+
+		metricTask := task
+		metricTask.TaskID = "1a"
+		jaMock.EXPECT().ListMetricJobs().AnyTimes().
+			Return([]interfaces.JobInfo{{JobId: "1a", MetricTask: &metricTask}}, nil)
+		jaMock.EXPECT().ListObjectiveJobs().AnyTimes().Return([]interfaces.JobInfo{}, nil)
+
+		// 执行中的补数任务在 ctx 取消前不会结束
+		started := make(chan string, 2)
+		runUntilCanceled := func(ctx context.Context, task interfaces.MetricTask, backfill interfaces.MetricTaskBackfill) error {
+			started <- backfill.ID
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		Convey("only one replica runs the backfill, and releases the lease after stopped", func() {
+			mtbaMock.EXPECT().ListActiveBackfills(gomock.Any()).Times(2).
+				Return([]interfaces.MetricTaskBackfill{testBackfill}, nil)
+			mtsMock.EXPECT().MetricTaskBackfillExecutor(gomock.Any(), metricTask, testBackfill).
+				Times(1).DoAndReturn(runUntilCanceled)
+
+			replicaA.recoverBackfills()
+			So(<-started, ShouldEqual, "b1")
+			So(leaseAccess.owner("b1"), ShouldEqual, "replica-a")
+			So(replicaA.localJobIDs(), ShouldContain, "b1")
+
+			replicaB.recoverBackfills()
+			_, ok := replicaB.backfills.Load("b1")
+			So(ok, ShouldBeFalse)
+
+			replicaA.releaseLocalJob("b1")
+			So(waitFor(func() bool { return leaseAccess.owner("b1") == "" }), ShouldBeTrue)
+			_, ok = replicaA.backfills.Load("b1")
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("limit the number of running backfills", func() {
+			replicaA.appSetting.ServerSetting.BackfillMaxRunning = 1
+			backfill2 := testBackfill
+			backfill2.ID = "b2"
+
+			mtbaMock.EXPECT().ListActiveBackfills(gomock.Any()).Times(2).
+				Return([]interfaces.MetricTaskBackfill{testBackfill, backfill2}, nil)
+			mtsMock.EXPECT().MetricTaskBackfillExecutor(gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(1).DoAndReturn(runUntilCanceled)
+
+			replicaA.recoverBackfills()
+			So(<-started, ShouldEqual, "b1")
+
+			replicaA.recoverBackfills()
+			So(leaseAccess.owner("b2"), ShouldEqual, "")
+
+			replicaA.releaseLocalJob("b1")
+			So(waitFor(func() bool { return leaseAccess.owner("b1") == "" }), ShouldBeTrue)
+		})
+
+		Convey("mark the backfill failed when its task is deleted", func() {
+			backfill := testBackfill
+			backfill.TaskID = "deleted"
+
+			mtbaMock.EXPECT().ListActiveBackfills(gomock.Any()).Return([]interfaces.MetricTaskBackfill{backfill}, nil)
+			mtbaMock.EXPECT().UpdateBackfillProgress(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, backfill interfaces.MetricTaskBackfill) (bool, error) {
+					So(backfill.Status, ShouldEqual, interfaces.BACKFILL_STATUS_FAILED)
+					return true, nil
+				})
+
+			replicaA.recoverBackfills()
+			So(leaseAccess.owner("b1"), ShouldEqual, "")
+		})
+
+		Convey("list active backfills failed", func() {
+			mtbaMock.EXPECT().ListActiveBackfills(gomock.Any()).Return(nil, fmt.Errorf("error"))
+
+			replicaA.recoverBackfills()
+			So(replicaA.localJobIDs(), ShouldBeEmpty)
+		})
+	})
+}
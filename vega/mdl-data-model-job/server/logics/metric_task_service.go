@@ -36,6 +36,8 @@ type metricTaskService struct {
 	uAccess    interfaces.UniqueryAccess
 	kAccess    interfaces.KafkaAccess
 	iBAccess   interfaces.IndexBaseAccess
+	oaAccess   interfaces.ObjectiveAlertAccess
+	mtbAccess  interfaces.MetricTaskBackfillAccess
 }
 
 func NewMetricTaskService(appSetting *common.AppSetting) interfaces.MetricTaskService {
@@ -47,6 +49,8 @@ func NewMetricTaskService(appSetting *common.AppSetting) interfaces.MetricTaskSe
 			uAccess:    UAccess,
 			kAccess:    KAccess,
 			iBAccess:   IBAccess,
+			oaAccess:   OAAccess,
+			mtbAccess:  MTBAccess,
 		}
 
 	})
@@ -120,78 +124,26 @@ func (mtService *metricTaskService) executTask(ctx context.Context, task interfa
 		return "", fmt.Errorf("指标模型任务的计划时间【%v】不合法, 期望为计划时间长度为1", taskPlanTime)
 	}
 
-	steps := make([]int64, 0)
-	for _, stepStr := range task.Steps {
-		// 解析步长为具体毫秒
-		step, err := common.ParseDuration(stepStr, common.DurationDayHourMinuteRE, true)
-		if err != nil {
-			logger.Errorf("Failed to parse schedule duration, err: %s", err.Error())
-			return "", fmt.Errorf("failed to parse schedule duration, err: %s", err.Error())
-		}
-		steps = append(steps, int64(step/(time.Millisecond/time.Nanosecond)))
+	steps, err := parseTaskSteps(task.Steps)
+	if err != nil {
+		return "", err
 	}
 
 	ts := time.Now().UnixNano() / int64(time.Millisecond/time.Nanosecond)
 	// 所以对plan_time按任务的steps的5m修正，对now按步长修正
 	fixedPlanTime := int64(math.Floor(float64(taskPlanTime)/float64(interfaces.MIN_STEP))) * interfaces.MIN_STEP
-	timeZone := os.Getenv("TZ")
-	if timeZone == "" {
-		timeZone = interfaces.DEFAULT_TIME_ZONE
-	}
-	location, err := time.LoadLocation(timeZone)
+	offset, err := getTimeZoneOffset()
 	if err != nil {
-		// 记录异常日志
-		logger.Errorf("LoadLocation error: %s", err.Error())
-		return "", fmt.Errorf("LoadLocation error: %s", err.Error())
+		return "", err
 	}
-	_, offset := time.Now().In(location).Zone()
 
 	msgTotal := 0
 	var planTime int64
 	for planTime = fixedPlanTime; planTime <= ts; {
 		messages := make([]*kafka.Message, 0)
-		for i, stepStr := range task.Steps {
-			// 判断当前步长点是否能被step整除。 用plan_time偏移到utc时间的除。
-			p := planTime + int64(offset*1000)
-			if p%steps[i] != 0 {
-				// 跳过，遍历下一个step
-				logger.Debugf("跳过：指标模型任务[%s]当前计算时间点plantime:%d. 当前时间now:%d, 执行步长为%s", task.TaskID, planTime, ts, stepStr)
-				continue
-			}
-
-			// 确定 look_back_delta。因为在管理端，语言是 promql 时，校验了时间窗口应为空
-			lookBackDeltas := make([]string, 0)
-			if len(task.TimeWindows) == 0 {
-				// promql 时，用任务的 step 作为即时查询的 look_back_delta
-				lookBackDeltas = append(lookBackDeltas, stepStr)
-			} else {
-				// dsl 时，用时间窗口 timw_window 作为即时查询的 look_back_delta
-				lookBackDeltas = append(lookBackDeltas, task.TimeWindows...)
-			}
-
-			// 遍历 look_back_delta，查询数据并组装kafka message
-			for _, lookBackDelta := range lookBackDeltas {
-				// 请求uniquery，即时查询，
-
-				metricData, err := mtService.uAccess.GetMetricModelData(ctx, task.ModelID, interfaces.MetricModelQuery{
-					IsInstantQuery: true,
-					Time:           planTime,
-					LookBackDelta:  lookBackDelta,
-				})
-				logger.Debugf("指标模型任务[%s], 当前step【%s】查询的步长点为【%d】", task.TaskID, stepStr, planTime)
-				if err != nil {
-					return "", fmt.Errorf("指标模型任务[%s], 查询参数: time=%d,look_back_delta=%s, 获取指标数据失败[%s]. ", task.TaskID, planTime, lookBackDelta, err.Error())
-				}
-
-				//  组装kafka messages
-				if len(metricData.Datas) > 0 {
-					// 3.2.数据转换为 metric 数据格式. category, 任务名称，时间窗口
-					err = mtService.metricDataTransferToMessage(planTime-steps[i], metricData, lookBackDelta, task, stepStr, indexBases[0], &messages)
-					if err != nil {
-						return "", fmt.Errorf("指标模型任务[%s], 时间窗口[%s]把数据转换为 kafka massage 失败[%s]. ", task.TaskID, lookBackDelta, err.Error())
-					}
-				}
-			}
+		err = mtService.buildMetricMessages(ctx, task, planTime, steps, offset, indexBases[0], &messages)
+		if err != nil {
+			return "", err
 		}
 		planTime += interfaces.MIN_STEP
 
@@ -241,74 +193,33 @@ func (mtService *metricTaskService) executObjectiveTask(ctx context.Context, tas
 		return "", fmt.Errorf("目标模型任务[%s]的计划时间【%v】不合法, 期望为计划时间长度为1", task.TaskID, taskPlanTime)
 	}
 
-	steps := make([]int64, 0)
-	for _, stepStr := range task.Steps {
-		// 解析步长为具体毫秒
-		step, err := common.ParseDuration(stepStr, common.DurationDayHourMinuteRE, true)
-		if err != nil {
-			logger.Errorf("Failed to parse schedule duration, err: %s", err.Error())
-			return "", fmt.Errorf("failed to parse schedule duration, err: %s", err.Error())
-		}
-		steps = append(steps, int64(step/(time.Millisecond/time.Nanosecond)))
+	steps, err := parseTaskSteps(task.Steps)
+	if err != nil {
+		return "", err
 	}
 
 	ts := time.Now().UnixNano() / int64(time.Millisecond/time.Nanosecond)
 	// 所以对plan_time按任务的steps的5m修正，对now按步长修正
 	fixedPlanTime := int64(math.Floor(float64(taskPlanTime)/float64(interfaces.MIN_STEP))) * interfaces.MIN_STEP
-	timeZone := os.Getenv("TZ")
-	if timeZone == "" {
-		timeZone = interfaces.DEFAULT_TIME_ZONE
-	}
-	location, err := time.LoadLocation(timeZone)
+	offset, err := getTimeZoneOffset()
 	if err != nil {
-		// 记录异常日志
-		logger.Errorf("LoadLocation error: %s", err.Error())
-		return "", fmt.Errorf("LoadLocation error: %s", err.Error())
+		return "", err
 	}
-	_, offset := time.Now().In(location).Zone()
 
 	msgTotal := 0
+	// 最近一次查询到的目标模型及其计算时间点，用于燃烧率告警评估
+	var lastModel *interfaces.ObjectiveModel
+	var lastEvalTime int64
 	var planTime int64
 	for planTime = fixedPlanTime; planTime <= ts; {
 		messages := make([]*kafka.Message, 0)
-		for i, stepStr := range task.Steps {
-			// 判断当前步长点是否能被step整除。 用plan_time偏移到utc时间的除。
-			p := planTime + int64(offset*1000)
-			if p%steps[i] != 0 {
-				// 跳过，遍历下一个step
-				logger.Debugf("跳过：目标模型任务[%s]当前计算时间点plantime:%d. 当前时间now:%d, 执行步长为%s", task.TaskID, planTime, ts, stepStr)
-				continue
-			}
-
-			// 确定 look_back_delta。因为在管理端，语言是 promql 时，校验了时间窗口应为空
-			lookBackDelta := stepStr
-			if len(task.TimeWindows) == 1 {
-				// slo 时，用时间窗口 timw_window（在创建SLO目标模型时把周期赋值到任务的time_window中了）作为即时查询的 look_back_delta
-				lookBackDelta = task.TimeWindows[0]
-			}
-
-			// 用任务的 step 作为即时查询的 look_back_delta
-			// 查询数据并组装kafka message
-			// 请求uniquery，即时查询，
-			objectiveData, err := mtService.uAccess.GetObjectiveModelData(ctx, task.ModelID, interfaces.MetricModelQuery{
-				IsInstantQuery: true,
-				Time:           planTime,
-				LookBackDelta:  lookBackDelta, // 对于slo来说，此处是目标模型配置的周期。kpi就用自己的step。
-			})
-			logger.Debugf("查询的目标模型为【%s】,当前step【%s】查询的步长点为【%d】,look_back_delta=%s", task.ModelID, stepStr, planTime, lookBackDelta)
-			if err != nil {
-				return "", fmt.Errorf("目标模型任务[%s], 查询参数: time=%d,look_back_delta=%s, 获取指标数据失败[%s]. ", task.TaskID, planTime, lookBackDelta, err.Error())
-			}
-
-			//  组装kafka messages
-			if objectiveData.Datas != nil {
-				// 3.2.数据转换为 metric 数据格式. category, 任务名称，时间窗口
-				err = mtService.objectiveDataTransferToMessage(planTime-steps[i], objectiveData, task, stepStr, indexBases[0], &messages)
-				if err != nil {
-					return "", fmt.Errorf("目标模型任务[%s], 时间窗口[%s]把数据转换为 kafka massage 失败[%s]. ", task.TaskID, stepStr, err.Error())
-				}
-			}
-
+		model, err := mtService.buildObjectiveMessages(ctx, task, planTime, steps, offset, indexBases[0], &messages)
+		if err != nil {
+			return "", err
+		}
+		if model != nil {
+			lastModel = model
+			lastEvalTime = planTime
 		}
 		planTime += interfaces.MIN_STEP
 
@@ -332,6 +243,14 @@ func (mtService *metricTaskService) executObjectiveTask(ctx context.Context, tas
 		}
 	}
 
+	// 燃烧率告警评估失败不影响持久化任务的结果，只记录日志
+	if lastModel != nil {
+		err = mtService.evaluateBurnRateAlerts(ctx, task, *lastModel, lastEvalTime)
+		if err != nil {
+			logger.Errorf("目标模型任务[%s]燃烧率告警评估失败: %s", task.TaskID, err.Error())
+		}
+	}
+
 	logger.Debugf("service: 目标模型[%s]执行完成. 共发送[%d]条数据到kafka。", task.TaskID, msgTotal)
 	return fmt.Sprintf("service: 目标模型[%s]执行完成. 共发送[%d]条数据到kafka。", task.TaskID, msgTotal), nil
 }
@@ -344,6 +263,11 @@ func (mtService *metricTaskService) flushToKafka(task interfaces.MetricTask,
 	// 4.1 创建生产者,穿一个uniqId
 	topic := fmt.Sprintf(interfaces.MODEL_PERSIST_INPUT, mtService.appSetting.MQSetting.Tenant)
 	uniqueId := fmt.Sprintf("%s-%s", task.TaskID, topic)
+	return mtService.produceToKafka(uniqueId, messages)
+}
+
+// 使用事务生产者发送数据. uniqueId 相同的生产者会互相隔离, 不同来源的写入需使用不同的 uniqueId
+func (mtService *metricTaskService) produceToKafka(uniqueId string, messages []*kafka.Message) error {
 	producer, err := mtService.kAccess.NewTrxProducer(uniqueId)
 	if err != nil {
 		return err
@@ -358,6 +282,135 @@ func (mtService *metricTaskService) flushToKafka(task interfaces.MetricTask,
 	return nil
 }
 
+// 计算一个计划时间点上各步长的指标数据, 并转换为 kafka message
+func (mtService *metricTaskService) buildMetricMessages(ctx context.Context, task interfaces.MetricTask, planTime int64,
+	steps []int64, offset int, indexBase interfaces.IndexBase, messages *[]*kafka.Message) error {
+
+	for i, stepStr := range task.Steps {
+		// 判断当前步长点是否能被step整除。 用plan_time偏移到utc时间的除。
+		p := planTime + int64(offset*1000)
+		if p%steps[i] != 0 {
+			// 跳过，遍历下一个step
+			logger.Debugf("跳过：指标模型任务[%s]当前计算时间点plantime:%d. 执行步长为%s", task.TaskID, planTime, stepStr)
+			continue
+		}
+
+		// 确定 look_back_delta。因为在管理端，语言是 promql 时，校验了时间窗口应为空
+		lookBackDeltas := make([]string, 0)
+		if len(task.TimeWindows) == 0 {
+			// promql 时，用任务的 step 作为即时查询的 look_back_delta
+			lookBackDeltas = append(lookBackDeltas, stepStr)
+		} else {
+			// dsl 时，用时间窗口 timw_window 作为即时查询的 look_back_delta
+			lookBackDeltas = append(lookBackDeltas, task.TimeWindows...)
+		}
+
+		// 遍历 look_back_delta，查询数据并组装kafka message
+		for _, lookBackDelta := range lookBackDeltas {
+			// 请求uniquery，即时查询，
+
+			metricData, err := mtService.uAccess.GetMetricModelData(ctx, task.ModelID, interfaces.MetricModelQuery{
+				IsInstantQuery: true,
+				Time:           planTime,
+				LookBackDelta:  lookBackDelta,
+			})
+			logger.Debugf("指标模型任务[%s], 当前step【%s】查询的步长点为【%d】", task.TaskID, stepStr, planTime)
+			if err != nil {
+				return fmt.Errorf("指标模型任务[%s], 查询参数: time=%d,look_back_delta=%s, 获取指标数据失败[%s]. ", task.TaskID, planTime, lookBackDelta, err.Error())
+			}
+
+			//  组装kafka messages
+			if len(metricData.Datas) > 0 {
+				// 3.2.数据转换为 metric 数据格式. category, 任务名称，时间窗口
+				err = mtService.metricDataTransferToMessage(planTime-steps[i], metricData, lookBackDelta, task, stepStr, indexBase, messages)
+				if err != nil {
+					return fmt.Errorf("指标模型任务[%s], 时间窗口[%s]把数据转换为 kafka massage 失败[%s]. ", task.TaskID, lookBackDelta, err.Error())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// 计算一个计划时间点上各步长的目标数据, 并转换为 kafka message. 返回最近一次查询到的目标模型
+func (mtService *metricTaskService) buildObjectiveMessages(ctx context.Context, task interfaces.MetricTask, planTime int64,
+	steps []int64, offset int, indexBase interfaces.IndexBase, messages *[]*kafka.Message) (*interfaces.ObjectiveModel, error) {
+
+	var lastModel *interfaces.ObjectiveModel
+	for i, stepStr := range task.Steps {
+		// 判断当前步长点是否能被step整除。 用plan_time偏移到utc时间的除。
+		p := planTime + int64(offset*1000)
+		if p%steps[i] != 0 {
+			// 跳过，遍历下一个step
+			logger.Debugf("跳过：目标模型任务[%s]当前计算时间点plantime:%d. 执行步长为%s", task.TaskID, planTime, stepStr)
+			continue
+		}
+
+		// 确定 look_back_delta。因为在管理端，语言是 promql 时，校验了时间窗口应为空
+		lookBackDelta := stepStr
+		if len(task.TimeWindows) == 1 {
+			// slo 时，用时间窗口 timw_window（在创建SLO目标模型时把周期赋值到任务的time_window中了）作为即时查询的 look_back_delta
+			lookBackDelta = task.TimeWindows[0]
+		}
+
+		// 用任务的 step 作为即时查询的 look_back_delta
+		// 查询数据并组装kafka message
+		// 请求uniquery，即时查询，
+		objectiveData, err := mtService.uAccess.GetObjectiveModelData(ctx, task.ModelID, interfaces.MetricModelQuery{
+			IsInstantQuery: true,
+			Time:           planTime,
+			LookBackDelta:  lookBackDelta, // 对于slo来说，此处是目标模型配置的周期。kpi就用自己的step。
+		})
+		logger.Debugf("查询的目标模型为【%s】,当前step【%s】查询的步长点为【%d】,look_back_delta=%s", task.ModelID, stepStr, planTime, lookBackDelta)
+		if err != nil {
+			return nil, fmt.Errorf("目标模型任务[%s], 查询参数: time=%d,look_back_delta=%s, 获取指标数据失败[%s]. ", task.TaskID, planTime, lookBackDelta, err.Error())
+		}
+
+		lastModel = &objectiveData.Model
+
+		//  组装kafka messages
+		if objectiveData.Datas != nil {
+			// 3.2.数据转换为 metric 数据格式. category, 任务名称，时间窗口
+			err = mtService.objectiveDataTransferToMessage(planTime-steps[i], objectiveData, task, stepStr, indexBase, messages)
+			if err != nil {
+				return nil, fmt.Errorf("目标模型任务[%s], 时间窗口[%s]把数据转换为 kafka massage 失败[%s]. ", task.TaskID, stepStr, err.Error())
+			}
+		}
+	}
+	return lastModel, nil
+}
+
+// 解析任务的步长为毫秒
+func parseTaskSteps(stepStrs []string) ([]int64, error) {
+	steps := make([]int64, 0, len(stepStrs))
+	for _, stepStr := range stepStrs {
+		// 解析步长为具体毫秒
+		step, err := common.ParseDuration(stepStr, common.DurationDayHourMinuteRE, true)
+		if err != nil {
+			logger.Errorf("Failed to parse schedule duration, err: %s", err.Error())
+			return nil, fmt.Errorf("failed to parse schedule duration, err: %s", err.Error())
+		}
+		steps = append(steps, int64(step/(time.Millisecond/time.Nanosecond)))
+	}
+	return steps, nil
+}
+
+// 当前时区相对 utc 的偏移秒数, 用于判断计划时间点是否落在步长上
+func getTimeZoneOffset() (int, error) {
+	timeZone := os.Getenv("TZ")
+	if timeZone == "" {
+		timeZone = interfaces.DEFAULT_TIME_ZONE
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		// 记录异常日志
+		logger.Errorf("LoadLocation error: %s", err.Error())
+		return 0, fmt.Errorf("LoadLocation error: %s", err.Error())
+	}
+	_, offset := time.Now().In(location).Zone()
+	return offset, nil
+}
+
 // 根据时间窗口和计划执行时间、当前时间来计算查询数据的start，end和step
 // func getStartEndTime(window string, planTime int64, endT time.Time) (int64, int64, string, error) {
 // 	// planTime / window * window, now / window * window
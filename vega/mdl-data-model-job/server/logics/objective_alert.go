@@ -0,0 +1,319 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package logics
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"data-model-job/interfaces"
+)
+
+// 某个窗口下某个序列的燃烧率
+type seriesBurnRate struct {
+	labels   map[string]string
+	burnRate float64
+}
+
+// 多窗口多燃烧率告警评估。在目标模型任务的持久化完成后，对最新的计算时间点做一次评估，
+// 长短窗口的燃烧率同时达到策略阈值时产生事件，恢复时产生清除事件，告警状态写入 t_objective_alert
+func (mtService *metricTaskService) evaluateBurnRateAlerts(ctx context.Context, task interfaces.MetricTask,
+	model interfaces.ObjectiveModel, evalTime int64) error {
+
+	if model.ObjectiveType != interfaces.SLO {
+		return nil
+	}
+	model.ModelID = task.ModelID
+
+	sloObjective, err := parseSLOObjective(model.ObjectiveConfig)
+	if err != nil {
+		return fmt.Errorf("目标模型[%s]的配置解析失败[%s]. ", task.ModelID, err.Error())
+	}
+	if sloObjective.BurnRateAlert == nil || len(sloObjective.BurnRateAlert.Policies) == 0 {
+		return nil
+	}
+	alertConfig := sloObjective.BurnRateAlert
+
+	indexBases, err := mtService.iBAccess.GetIndexBasesByTypes(ctx, []string{alertConfig.IndexBase})
+	if err != nil {
+		return err
+	}
+	if len(indexBases) != 1 {
+		return fmt.Errorf("目标模型[%s]燃烧率告警的索引库类型[%s]对应的索引库数量不等于1,为[%d]",
+			task.ModelID, alertConfig.IndexBase, len(indexBases))
+	}
+
+	// 每个窗口只查询一次，策略之间共用
+	burnRates := make(map[string]map[string]seriesBurnRate)
+	for _, policy := range alertConfig.Policies {
+		for _, window := range []string{policy.LongWindow, policy.ShortWindow} {
+			if _, ok := burnRates[window]; ok {
+				continue
+			}
+			windowRates, err := mtService.queryBurnRates(ctx, task.ModelID, evalTime, window)
+			if err != nil {
+				return err
+			}
+			burnRates[window] = windowRates
+		}
+	}
+
+	prevAlerts, err := mtService.oaAccess.ListObjectiveAlerts(ctx, task.ModelID)
+	if err != nil {
+		return fmt.Errorf("目标模型[%s]获取燃烧率告警状态失败[%s]. ", task.ModelID, err.Error())
+	}
+
+	alerts, events := mergeBurnRateAlerts(model, alertConfig.Policies, burnRates, prevAlerts, evalTime)
+
+	if len(events) > 0 {
+		topic := fmt.Sprintf(interfaces.MODEL_PERSIST_INPUT, mtService.appSetting.MQSetting.Tenant)
+		messages := make([]*kafka.Message, 0, len(events))
+		err = eventDataToKafkaMessages(topic, events, indexBases[0], &messages)
+		if err != nil {
+			return fmt.Errorf("目标模型[%s]燃烧率告警事件转换为 kafka massage 失败[%s]. ", task.ModelID, err.Error())
+		}
+
+		err = mtService.flushToKafka(task, messages)
+		if err != nil {
+			return fmt.Errorf("目标模型[%s]燃烧率告警事件发送到 kafka 失败[%s]. ", task.ModelID, err.Error())
+		}
+	}
+
+	err = mtService.oaAccess.ReplaceObjectiveAlerts(ctx, task.ModelID, alerts)
+	if err != nil {
+		return fmt.Errorf("目标模型[%s]更新燃烧率告警状态失败[%s]. ", task.ModelID, err.Error())
+	}
+
+	logger.Debugf("目标模型[%s]燃烧率告警评估完成, 评估时间[%d], 告警状态[%d]条, 产生事件[%d]条",
+		task.ModelID, evalTime, len(alerts), len(events))
+	return nil
+}
+
+// 以窗口作为 look_back_delta 即时查询目标模型，取每个序列最后一个有效的燃烧率。
+// promql 指标的区间由表达式决定，不随 look_back_delta 变化，配置燃烧率告警时数据模型已拒绝 promql 的 good 和 total 指标
+func (mtService *metricTaskService) queryBurnRates(ctx context.Context, modelID string, evalTime int64,
+	window string) (map[string]seriesBurnRate, error) {
+
+	objectiveData, err := mtService.uAccess.GetObjectiveModelData(ctx, modelID, interfaces.MetricModelQuery{
+		IsInstantQuery: true,
+		Time:           evalTime,
+		LookBackDelta:  window,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("目标模型[%s], 查询参数: time=%d,look_back_delta=%s, 获取燃烧率失败[%s]. ",
+			modelID, evalTime, window, err.Error())
+	}
+
+	var sloDatas []interfaces.SLOObjectiveData
+	if objectiveData.Datas != nil {
+		jsonData, err := json.Marshal(objectiveData.Datas)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(jsonData, &sloDatas)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rates := make(map[string]seriesBurnRate)
+	for _, data := range sloDatas {
+		for i := len(data.BurnRate) - 1; i >= 0; i-- {
+			// 燃烧率以百分比返回，100 表示 1 倍
+			v, ok := data.BurnRate[i].(float64)
+			if !ok {
+				continue
+			}
+			rates[seriesIDOfLabels(data.Labels)] = seriesBurnRate{labels: data.Labels, burnRate: v / 100}
+			break
+		}
+	}
+	return rates, nil
+}
+
+// 合并本轮评估结果与上一轮的告警状态，返回新的告警状态和需要发送的事件
+func mergeBurnRateAlerts(model interfaces.ObjectiveModel, policies []interfaces.BurnRatePolicy,
+	burnRates map[string]map[string]seriesBurnRate, prevAlerts []interfaces.ObjectiveAlert,
+	evalTime int64) ([]interfaces.ObjectiveAlert, []interfaces.EventModelData) {
+
+	prevMap := make(map[string]interfaces.ObjectiveAlert)
+	for _, alert := range prevAlerts {
+		prevMap[alert.PolicyName+"/"+alert.SeriesID] = alert
+	}
+
+	alerts := make([]interfaces.ObjectiveAlert, 0)
+	events := make([]interfaces.EventModelData, 0)
+	for _, policy := range policies {
+		longRates := burnRates[policy.LongWindow]
+		shortRates := burnRates[policy.ShortWindow]
+
+		// 本轮有数据的序列和上一轮处于告警中的序列都需要评估
+		seriesIDs := make([]string, 0, len(longRates))
+		for seriesID := range longRates {
+			seriesIDs = append(seriesIDs, seriesID)
+		}
+		for _, alert := range prevAlerts {
+			if alert.PolicyName != policy.Name || alert.Status != interfaces.OBJECTIVE_ALERT_STATUS_FIRING {
+				continue
+			}
+			if _, ok := longRates[alert.SeriesID]; !ok {
+				seriesIDs = append(seriesIDs, alert.SeriesID)
+			}
+		}
+		sort.Strings(seriesIDs)
+
+		for _, seriesID := range seriesIDs {
+			prev, hasPrev := prevMap[policy.Name+"/"+seriesID]
+			long, hasLong := longRates[seriesID]
+			short, hasShort := shortRates[seriesID]
+			firing := hasLong && hasShort && long.burnRate >= policy.BurnRate && short.burnRate >= policy.BurnRate
+
+			alert := interfaces.ObjectiveAlert{
+				ModelID:       model.ModelID,
+				PolicyName:    policy.Name,
+				SeriesID:      seriesID,
+				Labels:        long.labels,
+				Status:        interfaces.OBJECTIVE_ALERT_STATUS_RESOLVED,
+				Level:         policy.Level,
+				Threshold:     policy.BurnRate,
+				LongBurnRate:  long.burnRate,
+				ShortBurnRate: short.burnRate,
+				LastEvalTime:  evalTime,
+			}
+			if !hasLong {
+				// 序列已经没有数据，沿用上一轮的标签
+				alert.Labels = prev.Labels
+			}
+			if hasPrev {
+				alert.ActiveSince = prev.ActiveSince
+				alert.LastEventID = prev.LastEventID
+				alert.LastEventTime = prev.LastEventTime
+			}
+			prevFiring := hasPrev && prev.Status == interfaces.OBJECTIVE_ALERT_STATUS_FIRING
+
+			switch {
+			case firing && !prevFiring:
+				alert.Status = interfaces.OBJECTIVE_ALERT_STATUS_FIRING
+				alert.ActiveSince = evalTime
+				event := newBurnRateEvent(model, policy, alert, policy.Level, "")
+				alert.LastEventID = event.Id
+				alert.LastEventTime = evalTime
+				events = append(events, event)
+			case firing:
+				alert.Status = interfaces.OBJECTIVE_ALERT_STATUS_FIRING
+			case prevFiring:
+				event := newBurnRateEvent(model, policy, alert, interfaces.BURN_RATE_CLEARED_LEVEL, prev.LastEventID)
+				alert.LastEventID = event.Id
+				alert.LastEventTime = evalTime
+				events = append(events, event)
+			}
+
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return alerts, events
+}
+
+// 组装燃烧率告警事件，清除事件通过 pre_order_event 关联触发时的事件
+func newBurnRateEvent(model interfaces.ObjectiveModel, policy interfaces.BurnRatePolicy,
+	alert interfaces.ObjectiveAlert, level int, preEventID string) interfaces.EventModelData {
+
+	labels := make(map[string]string, len(alert.Labels)+3)
+	for k, v := range alert.Labels {
+		labels[k] = v
+	}
+	labels["policy_name"] = policy.Name
+	labels["long_window"] = policy.LongWindow
+	labels["short_window"] = policy.ShortWindow
+
+	md5Hasher := md5.New()
+	md5Hasher.Write([]byte(fmt.Sprintf("%s,%s,%s,%d,%d", model.ModelID, policy.Name, alert.SeriesID, level, alert.LastEvalTime)))
+	eventID := hex.EncodeToString(md5Hasher.Sum(nil))
+
+	message := fmt.Sprintf("目标模型[%s]错误预算燃烧率告警[%s]: 长窗口[%s]燃烧率 %.2f, 短窗口[%s]燃烧率 %.2f, 阈值 %.2f",
+		model.ModelName, policy.Name, policy.LongWindow, alert.LongBurnRate, policy.ShortWindow, alert.ShortBurnRate, policy.BurnRate)
+	if level == interfaces.BURN_RATE_CLEARED_LEVEL {
+		message = fmt.Sprintf("目标模型[%s]错误预算燃烧率告警[%s]已恢复", model.ModelName, policy.Name)
+	}
+
+	return interfaces.EventModelData{
+		Id:           eventID,
+		Title:        fmt.Sprintf("%s-%s", model.ModelName, policy.Name),
+		EventModelId: model.ModelID,
+		EventType:    interfaces.BURN_RATE_EVENT_TYPE,
+		Level:        level,
+		GenerateType: interfaces.GENERATE_TYPE_FOR_BATCH,
+		LevelName:    interfaces.BURN_RATE_EVENT_LEVEL_NAME[level],
+		Message:      message,
+		Context: interfaces.Context{
+			Score:         alert.LongBurnRate,
+			Level:         level,
+			PreOrderEvent: interfaces.PreOrderEvent{Level: policy.Level, Id: preEventID},
+		},
+		TriggerTime: alert.LastEvalTime,
+		TriggerData: interfaces.Records{
+			{
+				"long_burn_rate":  alert.LongBurnRate,
+				"short_burn_rate": alert.ShortBurnRate,
+				"threshold":       policy.BurnRate,
+			},
+		},
+		Tags:           model.Tags,
+		EventModelName: model.ModelName,
+		CreateTime:     alert.LastEvalTime,
+		DataSource:     []string{model.ModelID},
+		DataSourceName: []string{model.ModelName},
+		DataSourceType: interfaces.MODULE_TYPE_OBJECTIVE_MODEL,
+		Labels:         labels,
+		DetectType:     interfaces.BURN_RATE_DETECT_TYPE,
+		DetectAlgo:     interfaces.BURN_RATE_DETECT_ALGO,
+	}
+}
+
+// 目标模型配置是 any，转换为 SLO 配置
+func parseSLOObjective(objectiveConfig any) (interfaces.SLOObjective, error) {
+	var sloObjective interfaces.SLOObjective
+	if objectiveConfig == nil {
+		return sloObjective, nil
+	}
+	jsonData, err := json.Marshal(objectiveConfig)
+	if err != nil {
+		return sloObjective, err
+	}
+	err = json.Unmarshal(jsonData, &sloObjective)
+	return sloObjective, err
+}
+
+// 序列标识，按标签名排序后取 md5
+func seriesIDOfLabels(labels map[string]string) string {
+	labelNames := make([]string, 0, len(labels))
+	for k := range labels {
+		labelNames = append(labelNames, k)
+	}
+	sort.Strings(labelNames)
+
+	var builder strings.Builder
+	for i, ln := range labelNames {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(ln)
+		builder.WriteString("=")
+		builder.WriteString(labels[ln])
+	}
+	md5Hasher := md5.New()
+	md5Hasher.Write([]byte(builder.String()))
+	return hex.EncodeToString(md5Hasher.Sum(nil))
+}
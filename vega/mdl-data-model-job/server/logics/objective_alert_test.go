@@ -0,0 +1,229 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package logics
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model-job/common"
+	"data-model-job/interfaces"
+	dmock "data-model-job/interfaces/mock"
+)
+
+var (
+	burnRateModel = interfaces.ObjectiveModel{
+		ModelName:     "slo1",
+		ObjectiveType: interfaces.SLO,
+		ObjectiveConfig: map[string]any{
+			"objective": 99.9,
+			"period":    30,
+			"burn_rate_alert": map[string]any{
+				"index_base": "alert_base",
+				"policies": []any{
+					map[string]any{
+						"name": "page", "long_window": "1h", "short_window": "5m", "burn_rate": 14.4, "level": 1,
+					},
+				},
+			},
+		},
+	}
+
+	burnRateLabels = map[string]string{"service": "a"}
+)
+
+func burnRateResponse(burnRate any) interfaces.ObjectiveModelUniResponse {
+	return interfaces.ObjectiveModelUniResponse{
+		Model: burnRateModel,
+		Datas: []interfaces.SLOObjectiveData{
+			{
+				Labels:   burnRateLabels,
+				BurnRate: []any{burnRate},
+			},
+		},
+	}
+}
+
+func Test_ObjectiveAlert_MergeBurnRateAlerts(t *testing.T) {
+	Convey("Test mergeBurnRateAlerts", t, func() {
+		model := interfaces.ObjectiveModel{ModelID: "1", ModelName: "slo1"}
+		policies := []interfaces.BurnRatePolicy{
+			{Name: "page", LongWindow: "1h", ShortWindow: "5m", BurnRate: 14.4, Level: 1},
+		}
+		seriesID := seriesIDOfLabels(burnRateLabels)
+		rates := func(long, short float64) map[string]map[string]seriesBurnRate {
+			return map[string]map[string]seriesBurnRate{
+				"1h": {seriesID: {labels: burnRateLabels, burnRate: long}},
+				"5m": {seriesID: {labels: burnRateLabels, burnRate: short}},
+			}
+		}
+
+		Convey("fire when both windows reach the threshold", func() {
+			alerts, events := mergeBurnRateAlerts(model, policies, rates(15, 20), nil, 1000)
+			So(len(alerts), ShouldEqual, 1)
+			So(alerts[0].Status, ShouldEqual, interfaces.OBJECTIVE_ALERT_STATUS_FIRING)
+			So(alerts[0].ActiveSince, ShouldEqual, 1000)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].Level, ShouldEqual, 1)
+			So(events[0].Labels["policy_name"], ShouldEqual, "page")
+			So(events[0].DetectType, ShouldEqual, interfaces.BURN_RATE_DETECT_TYPE)
+			So(alerts[0].LastEventID, ShouldEqual, events[0].Id)
+		})
+
+		Convey("not fire when only the long window reaches the threshold", func() {
+			alerts, events := mergeBurnRateAlerts(model, policies, rates(15, 2), nil, 1000)
+			So(len(alerts), ShouldEqual, 1)
+			So(alerts[0].Status, ShouldEqual, interfaces.OBJECTIVE_ALERT_STATUS_RESOLVED)
+			So(len(events), ShouldEqual, 0)
+		})
+
+		Convey("no new event while still firing", func() {
+			prev := []interfaces.ObjectiveAlert{{
+				PolicyName: "page", SeriesID: seriesID, Status: interfaces.OBJECTIVE_ALERT_STATUS_FIRING,
+				ActiveSince: 500, LastEventID: "e1", LastEventTime: 500,
+			}}
+			alerts, events := mergeBurnRateAlerts(model, policies, rates(15, 20), prev, 1000)
+			So(len(events), ShouldEqual, 0)
+			So(alerts[0].ActiveSince, ShouldEqual, 500)
+			So(alerts[0].LastEventID, ShouldEqual, "e1")
+		})
+
+		Convey("emit cleared event when recovered", func() {
+			prev := []interfaces.ObjectiveAlert{{
+				PolicyName: "page", SeriesID: seriesID, Status: interfaces.OBJECTIVE_ALERT_STATUS_FIRING,
+				ActiveSince: 500, LastEventID: "e1", LastEventTime: 500,
+			}}
+			alerts, events := mergeBurnRateAlerts(model, policies, rates(1, 1), prev, 1000)
+			So(alerts[0].Status, ShouldEqual, interfaces.OBJECTIVE_ALERT_STATUS_RESOLVED)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].Level, ShouldEqual, interfaces.BURN_RATE_CLEARED_LEVEL)
+			So(events[0].Context.PreOrderEvent.Id, ShouldEqual, "e1")
+		})
+
+		Convey("resolve firing series without data and drop resolved ones", func() {
+			prev := []interfaces.ObjectiveAlert{
+				{PolicyName: "page", SeriesID: "gone", Labels: map[string]string{"service": "b"},
+					Status: interfaces.OBJECTIVE_ALERT_STATUS_FIRING, LastEventID: "e2"},
+				{PolicyName: "page", SeriesID: "old", Status: interfaces.OBJECTIVE_ALERT_STATUS_RESOLVED},
+			}
+			alerts, events := mergeBurnRateAlerts(model, policies, map[string]map[string]seriesBurnRate{}, prev, 1000)
+			So(len(alerts), ShouldEqual, 1)
+			So(alerts[0].SeriesID, ShouldEqual, "gone")
+			So(alerts[0].Labels, ShouldResemble, map[string]string{"service": "b"})
+			So(alerts[0].Status, ShouldEqual, interfaces.OBJECTIVE_ALERT_STATUS_RESOLVED)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].Level, ShouldEqual, interfaces.BURN_RATE_CLEARED_LEVEL)
+		})
+	})
+}
+
+func Test_ObjectiveAlert_EvaluateBurnRateAlerts(t *testing.T) {
+	Convey("Test evaluateBurnRateAlerts", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		mmaMock := dmock.NewMockMetricModelAccess(mockCtrl)
+		uaMock := dmock.NewMockUniqueryAccess(mockCtrl)
+		ibaMock := dmock.NewMockIndexBaseAccess(mockCtrl)
+		kaMock := dmock.NewMockKafkaAccess(mockCtrl)
+		oaaMock := dmock.NewMockObjectiveAlertAccess(mockCtrl)
+		mtsMock := MockNewMetricTaskService(appSetting, mmaMock, uaMock, kaMock, ibaMock)
+		mtsMock.oaAccess = oaaMock
+
+		producer := &kafka.Producer{}
+		patch := ApplyMethod(reflect.TypeOf(producer), "Close",
+			func(*kafka.Producer) {
+				// nothing to do
+			},
+		)
+		defer patch.Reset()
+
+		objectiveTask := task
+		objectiveTask.ModuleType = interfaces.MODULE_TYPE_OBJECTIVE_MODEL
+
+		Convey("skip when model is not slo or has no burn rate alert", func() {
+			err := mtsMock.evaluateBurnRateAlerts(testCtx, objectiveTask,
+				interfaces.ObjectiveModel{ObjectiveType: interfaces.KPI}, 1000)
+			So(err, ShouldBeNil)
+
+			err = mtsMock.evaluateBurnRateAlerts(testCtx, objectiveTask,
+				interfaces.ObjectiveModel{ObjectiveType: interfaces.SLO, ObjectiveConfig: map[string]any{"objective": 99}}, 1000)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("failed, cause by GetIndexBasesByTypes error", func() {
+			ibaMock.EXPECT().GetIndexBasesByTypes(gomock.Any(), []string{"alert_base"}).
+				Return(nil, fmt.Errorf("error"))
+
+			err := mtsMock.evaluateBurnRateAlerts(testCtx, objectiveTask, burnRateModel, 1000)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("failed, cause by GetObjectiveModelData error", func() {
+			ibaMock.EXPECT().GetIndexBasesByTypes(gomock.Any(), gomock.Any()).
+				Return([]interfaces.IndexBase{{SimpleIndexBase: interfaces.SimpleIndexBase{BaseType: "alert_base"}}}, nil)
+			uaMock.EXPECT().GetObjectiveModelData(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(interfaces.ObjectiveModelUniResponse{}, fmt.Errorf("error"))
+
+			err := mtsMock.evaluateBurnRateAlerts(testCtx, objectiveTask, burnRateModel, 1000)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("failed, cause by ListObjectiveAlerts error", func() {
+			ibaMock.EXPECT().GetIndexBasesByTypes(gomock.Any(), gomock.Any()).
+				Return([]interfaces.IndexBase{{SimpleIndexBase: interfaces.SimpleIndexBase{BaseType: "alert_base"}}}, nil)
+			uaMock.EXPECT().GetObjectiveModelData(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+				Return(burnRateResponse(float64(1500)), nil)
+			oaaMock.EXPECT().ListObjectiveAlerts(gomock.Any(), objectiveTask.ModelID).Return(nil, fmt.Errorf("error"))
+
+			err := mtsMock.evaluateBurnRateAlerts(testCtx, objectiveTask, burnRateModel, 1000)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("success, fire and send event", func() {
+			ibaMock.EXPECT().GetIndexBasesByTypes(gomock.Any(), gomock.Any()).
+				Return([]interfaces.IndexBase{{SimpleIndexBase: interfaces.SimpleIndexBase{BaseType: "alert_base"}}}, nil)
+			uaMock.EXPECT().GetObjectiveModelData(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+				Return(burnRateResponse(float64(1500)), nil)
+			oaaMock.EXPECT().ListObjectiveAlerts(gomock.Any(), gomock.Any()).Return([]interfaces.ObjectiveAlert{}, nil)
+			kaMock.EXPECT().NewTrxProducer(gomock.Any()).Return(producer, nil)
+			kaMock.EXPECT().DoProduceMsgToKafka(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(p *kafka.Producer, messages []*kafka.Message) error {
+					So(len(messages), ShouldEqual, 1)
+					return nil
+				})
+			oaaMock.EXPECT().ReplaceObjectiveAlerts(gomock.Any(), objectiveTask.ModelID, gomock.Any()).
+				DoAndReturn(func(_ any, _ string, alerts []interfaces.ObjectiveAlert) error {
+					So(len(alerts), ShouldEqual, 1)
+					So(alerts[0].Status, ShouldEqual, interfaces.OBJECTIVE_ALERT_STATUS_FIRING)
+					So(alerts[0].LongBurnRate, ShouldEqual, 15)
+					return nil
+				})
+
+			err := mtsMock.evaluateBurnRateAlerts(testCtx, objectiveTask, burnRateModel, 1000)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("success, skip invalid burn rate values", func() {
+			ibaMock.EXPECT().GetIndexBasesByTypes(gomock.Any(), gomock.Any()).
+				Return([]interfaces.IndexBase{{SimpleIndexBase: interfaces.SimpleIndexBase{BaseType: "alert_base"}}}, nil)
+			uaMock.EXPECT().GetObjectiveModelData(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+				Return(burnRateResponse("NaN"), nil)
+			oaaMock.EXPECT().ListObjectiveAlerts(gomock.Any(), gomock.Any()).Return([]interfaces.ObjectiveAlert{}, nil)
+			oaaMock.EXPECT().ReplaceObjectiveAlerts(gomock.Any(), gomock.Any(), []interfaces.ObjectiveAlert{}).Return(nil)
+
+			err := mtsMock.evaluateBurnRateAlerts(testCtx, objectiveTask, burnRateModel, 1000)
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -137,6 +137,8 @@ func (jService *jobService) updateScheduleJob(newJob interfaces.JobInfo) error {
 	job.DataView = newJob.DataView
 	job.MetricTask = newJob.MetricTask
 	job.EventTask = newJob.EventTask
+	job.QualityRule = newJob.QualityRule
+	job.UpdateTime = newJob.UpdateTime
 	job.Schedule = newJob.Schedule
 
 	// 重新启动任务
@@ -203,6 +205,14 @@ func (sjService *jobService) executeJob(jobInfo *interfaces.JobInfo) {
 	case interfaces.MODULE_TYPE_EVENT_MODEL:
 		// 事件类任务调用EventTaskExecutor
 		sjService.executeEventJob(jobInfo)
+
+	case interfaces.MODULE_TYPE_DATA_QUALITY_RULE:
+		// 数据质量规则调用DataQualityRuleExecutor
+		if jobInfo.QualityRule != nil {
+			sjService.dqService.DataQualityRuleExecutor(context.Background(), jobInfo.QualityRule)
+		} else {
+			logger.Errorf("Executing data quality rule : %s failed, because of quality rule is empty.", jobInfo.JobId)
+		}
 	}
 }
 
@@ -217,7 +227,13 @@ func (sjService *jobService) executeEventJob(jobInfo *interfaces.JobInfo) {
 
 	// 当前任务执行完成后，触发依赖任务
 	for _, depID := range jobInfo.EventTask.DownstreamDependentTask {
+		sjService.scheduler.mu.Lock()
 		depJob, exists := sjService.scheduler.jobs[depID]
+		if !exists {
+			// 下游任务由其他副本持有时, 仍由上游任务所在的副本触发
+			depJob, exists = sjService.eventJobs[depID]
+		}
+		sjService.scheduler.mu.Unlock()
 		if !exists {
 			logger.Infof("Dependency task %s not found", depID)
 			continue
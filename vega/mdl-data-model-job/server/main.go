@@ -101,11 +101,16 @@ func main() {
 	logger.Infof("Server Start By Port:%d", appSetting.ServerSetting.HttpPort)
 
 	logics.SetJobAccess(access.NewJobAccess(appSetting))
+	logics.SetJobLeaseAccess(access.NewJobLeaseAccess(appSetting))
+	logics.SetMetricTaskBackfillAccess(access.NewMetricTaskBackfillAccess(appSetting))
 	logics.SetKafkaAccess(access.NewKafkaAccess(appSetting))
 	logics.SetIndexBaseAccess(access.NewIndexBaseAccess(appSetting))
 	logics.SetMetricModelAccess(access.NewMetricModelAccess(appSetting))
 	logics.SetUniqueryAccess(access.NewUniqueryAccess(appSetting))
 	logics.SetEventModelAccess(access.NewEventModelAccess(appSetting))
+	logics.SetObjectiveAlertAccess(access.NewObjectiveAlertAccess(appSetting))
+	logics.SetDataDictAccess(access.NewDataDictAccess(appSetting))
+	logics.SetDataQualityAccess(access.NewDataQualityAccess(appSetting))
 
 	server := &mgrService{
 		appSetting:  appSetting,
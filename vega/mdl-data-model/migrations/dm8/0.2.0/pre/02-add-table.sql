@@ -0,0 +1,23 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+SET SCHEMA adp;
+
+CREATE TABLE IF NOT EXISTS t_objective_alert (
+  f_model_id VARCHAR(40 CHAR) NOT NULL,
+  f_policy_name VARCHAR(40 CHAR) NOT NULL,
+  f_series_id VARCHAR(40 CHAR) NOT NULL,
+  f_labels TEXT NOT NULL,
+  f_status VARCHAR(20 CHAR) NOT NULL DEFAULT '',
+  f_level INT NOT NULL DEFAULT 0,
+  f_threshold DOUBLE NOT NULL DEFAULT 0,
+  f_long_burn_rate DOUBLE NOT NULL DEFAULT 0,
+  f_short_burn_rate DOUBLE NOT NULL DEFAULT 0,
+  f_active_since BIGINT NOT NULL DEFAULT 0,
+  f_last_eval_time BIGINT NOT NULL DEFAULT 0,
+  f_last_event_id VARCHAR(40 CHAR) NOT NULL DEFAULT '',
+  f_last_event_time BIGINT NOT NULL DEFAULT 0,
+  CLUSTER PRIMARY KEY (f_model_id, f_policy_name, f_series_id)
+);
@@ -0,0 +1,14 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+SET SCHEMA adp;
+
+CREATE TABLE IF NOT EXISTS t_job_lease (
+  f_job_id VARCHAR(40 CHAR) NOT NULL,
+  f_owner VARCHAR(128 CHAR) NOT NULL DEFAULT '',
+  f_expire_time BIGINT NOT NULL DEFAULT 0,
+  f_update_time BIGINT NOT NULL DEFAULT 0,
+  CLUSTER PRIMARY KEY (f_job_id)
+);
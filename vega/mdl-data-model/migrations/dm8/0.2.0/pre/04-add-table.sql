@@ -0,0 +1,28 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+SET SCHEMA adp;
+
+CREATE TABLE IF NOT EXISTS t_metric_task_backfill (
+  f_id VARCHAR(40 CHAR) NOT NULL,
+  f_task_id VARCHAR(40 CHAR) NOT NULL,
+  f_model_id VARCHAR(40 CHAR) NOT NULL,
+  f_module_type VARCHAR(20 CHAR) NOT NULL DEFAULT '',
+  f_start BIGINT NOT NULL DEFAULT 0,
+  f_end BIGINT NOT NULL DEFAULT 0,
+  f_steps VARCHAR(255 CHAR) NOT NULL DEFAULT '[]',
+  f_status VARCHAR(20 CHAR) NOT NULL DEFAULT '',
+  f_status_details TEXT DEFAULT NULL,
+  f_checkpoint BIGINT NOT NULL DEFAULT 0,
+  f_total_points BIGINT NOT NULL DEFAULT 0,
+  f_done_points BIGINT NOT NULL DEFAULT 0,
+  f_create_time BIGINT NOT NULL DEFAULT 0,
+  f_update_time BIGINT NOT NULL DEFAULT 0,
+  f_creator VARCHAR(40 CHAR) NOT NULL DEFAULT '',
+  f_creator_type VARCHAR(20 CHAR) NOT NULL DEFAULT '',
+  CLUSTER PRIMARY KEY (f_id)
+);
+
+CREATE INDEX IF NOT EXISTS t_metric_task_backfill_idx_f_model_id ON t_metric_task_backfill(f_model_id);
@@ -0,0 +1,45 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+SET SCHEMA adp;
+
+CREATE TABLE IF NOT EXISTS t_data_quality_rule (
+  f_rule_id VARCHAR(40 CHAR) NOT NULL,
+  f_rule_name VARCHAR(255 CHAR) NOT NULL,
+  f_view_id VARCHAR(40 CHAR) NOT NULL,
+  f_kind VARCHAR(40 CHAR) NOT NULL,
+  f_config TEXT NOT NULL,
+  f_schedule VARCHAR(255 CHAR) NOT NULL,
+  f_enabled TINYINT NOT NULL DEFAULT 1,
+  f_emit_event TINYINT NOT NULL DEFAULT 0,
+  f_event_config VARCHAR(1024 CHAR) DEFAULT NULL,
+  f_tags VARCHAR(255 CHAR) NOT NULL DEFAULT '',
+  f_comment VARCHAR(255 CHAR) NOT NULL DEFAULT '',
+  f_create_time BIGINT NOT NULL DEFAULT 0,
+  f_update_time BIGINT NOT NULL DEFAULT 0,
+  f_creator VARCHAR(40 CHAR) NOT NULL DEFAULT '',
+  f_creator_type VARCHAR(20 CHAR) NOT NULL DEFAULT '',
+  f_updater VARCHAR(40 CHAR) NOT NULL DEFAULT '',
+  f_updater_type VARCHAR(20 CHAR) NOT NULL DEFAULT '',
+  f_last_status VARCHAR(20 CHAR) NOT NULL DEFAULT '',
+  f_last_check_time BIGINT NOT NULL DEFAULT 0,
+  CLUSTER PRIMARY KEY (f_rule_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS t_data_quality_rule_uk_f_rule_name ON t_data_quality_rule(f_rule_name, f_view_id);
+CREATE INDEX IF NOT EXISTS t_data_quality_rule_idx_f_view_id ON t_data_quality_rule(f_view_id);
+
+CREATE TABLE IF NOT EXISTS t_data_quality_result (
+  f_rule_id VARCHAR(40 CHAR) NOT NULL,
+  f_view_id VARCHAR(40 CHAR) NOT NULL,
+  f_check_time BIGINT NOT NULL DEFAULT 0,
+  f_status VARCHAR(20 CHAR) NOT NULL DEFAULT '',
+  f_value DOUBLE NOT NULL DEFAULT 0,
+  f_details TEXT DEFAULT NULL,
+  f_error TEXT DEFAULT NULL,
+  CLUSTER PRIMARY KEY (f_rule_id, f_check_time)
+);
+
+CREATE INDEX IF NOT EXISTS t_data_quality_result_idx_f_view_id ON t_data_quality_result(f_view_id);
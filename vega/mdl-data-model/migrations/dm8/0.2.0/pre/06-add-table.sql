@@ -0,0 +1,23 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+SET SCHEMA adp;
+
+CREATE TABLE IF NOT EXISTS t_lineage_edge (
+  f_edge_id VARCHAR(40 CHAR) NOT NULL,
+  f_source_type VARCHAR(40 CHAR) NOT NULL,
+  f_source_id VARCHAR(255 CHAR) NOT NULL,
+  f_target_type VARCHAR(40 CHAR) NOT NULL,
+  f_target_id VARCHAR(255 CHAR) NOT NULL,
+  f_fields TEXT DEFAULT NULL,
+  f_owner_type VARCHAR(40 CHAR) NOT NULL,
+  f_owner_id VARCHAR(255 CHAR) NOT NULL,
+  f_update_time BIGINT NOT NULL DEFAULT 0,
+  CLUSTER PRIMARY KEY (f_edge_id)
+);
+
+CREATE INDEX IF NOT EXISTS t_lineage_edge_idx_f_source ON t_lineage_edge(f_source_type, f_source_id);
+CREATE INDEX IF NOT EXISTS t_lineage_edge_idx_f_target ON t_lineage_edge(f_target_type, f_target_id);
+CREATE INDEX IF NOT EXISTS t_lineage_edge_idx_f_owner ON t_lineage_edge(f_owner_type, f_owner_id);
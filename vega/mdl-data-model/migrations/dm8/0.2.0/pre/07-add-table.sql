@@ -0,0 +1,19 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+SET SCHEMA adp;
+
+CREATE TABLE IF NOT EXISTS t_model_revision (
+  f_model_type VARCHAR(40 CHAR) NOT NULL,
+  f_model_id VARCHAR(40 CHAR) NOT NULL,
+  f_revision INT NOT NULL,
+  f_model_name VARCHAR(255 CHAR) NOT NULL DEFAULT '',
+  f_content TEXT NOT NULL,
+  f_comment VARCHAR(255 CHAR) NOT NULL DEFAULT '',
+  f_creator VARCHAR(40 CHAR) NOT NULL DEFAULT '',
+  f_creator_type VARCHAR(20 CHAR) NOT NULL DEFAULT '',
+  f_create_time BIGINT NOT NULL DEFAULT 0,
+  CLUSTER PRIMARY KEY (f_model_type, f_model_id, f_revision)
+);
@@ -0,0 +1,23 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+USE adp;
+
+CREATE TABLE IF NOT EXISTS t_objective_alert (
+  f_model_id varchar(40) NOT NULL COMMENT '目标模型 id',
+  f_policy_name varchar(40) NOT NULL COMMENT '燃烧率告警策略名称',
+  f_series_id varchar(40) NOT NULL COMMENT '序列标识',
+  f_labels text NOT NULL COMMENT '序列标签',
+  f_status varchar(20) NOT NULL DEFAULT '' COMMENT '告警状态',
+  f_level int(11) NOT NULL DEFAULT 0 COMMENT '事件级别',
+  f_threshold double NOT NULL DEFAULT 0 COMMENT '燃烧率阈值',
+  f_long_burn_rate double NOT NULL DEFAULT 0 COMMENT '长窗口燃烧率',
+  f_short_burn_rate double NOT NULL DEFAULT 0 COMMENT '短窗口燃烧率',
+  f_active_since bigint(20) NOT NULL DEFAULT 0 COMMENT '开始触发时间',
+  f_last_eval_time bigint(20) NOT NULL DEFAULT 0 COMMENT '最近评估时间',
+  f_last_event_id varchar(40) NOT NULL DEFAULT '' COMMENT '最近一次产生的事件 id',
+  f_last_event_time bigint(20) NOT NULL DEFAULT 0 COMMENT '最近一次产生事件的时间',
+  PRIMARY KEY (f_model_id, f_policy_name, f_series_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin COMMENT = '目标模型燃烧率告警状态';
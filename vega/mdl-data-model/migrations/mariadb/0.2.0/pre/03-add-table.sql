@@ -0,0 +1,14 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+USE adp;
+
+CREATE TABLE IF NOT EXISTS t_job_lease (
+  f_job_id varchar(40) NOT NULL COMMENT '任务 id',
+  f_owner varchar(128) NOT NULL DEFAULT '' COMMENT '持有租约的 data-model-job 副本',
+  f_expire_time bigint(20) NOT NULL DEFAULT 0 COMMENT '租约过期时间',
+  f_update_time bigint(20) NOT NULL DEFAULT 0 COMMENT '租约续约时间',
+  PRIMARY KEY (f_job_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin COMMENT = 'data-model-job 任务租约';
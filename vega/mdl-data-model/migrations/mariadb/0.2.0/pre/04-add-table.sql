@@ -0,0 +1,27 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+USE adp;
+
+CREATE TABLE IF NOT EXISTS t_metric_task_backfill (
+  f_id varchar(40) NOT NULL COMMENT '补数任务 id',
+  f_task_id varchar(40) NOT NULL COMMENT '持久化任务 id',
+  f_model_id varchar(40) NOT NULL COMMENT '模型 id',
+  f_module_type varchar(20) NOT NULL DEFAULT '' COMMENT '模块类型',
+  f_start bigint(20) NOT NULL DEFAULT 0 COMMENT '补数开始时间',
+  f_end bigint(20) NOT NULL DEFAULT 0 COMMENT '补数结束时间',
+  f_steps varchar(255) NOT NULL DEFAULT '[]' COMMENT '补数步长',
+  f_status varchar(20) NOT NULL DEFAULT '' COMMENT '补数状态',
+  f_status_details text DEFAULT NULL COMMENT '状态详情',
+  f_checkpoint bigint(20) NOT NULL DEFAULT 0 COMMENT '下一个待执行的计划时间',
+  f_total_points bigint(20) NOT NULL DEFAULT 0 COMMENT '总计划时间点数',
+  f_done_points bigint(20) NOT NULL DEFAULT 0 COMMENT '已完成计划时间点数',
+  f_create_time bigint(20) NOT NULL DEFAULT 0 COMMENT '创建时间',
+  f_update_time bigint(20) NOT NULL DEFAULT 0 COMMENT '更新时间',
+  f_creator varchar(40) NOT NULL DEFAULT '' COMMENT '创建者id',
+  f_creator_type varchar(20) NOT NULL DEFAULT '' COMMENT '创建者类型',
+  PRIMARY KEY (f_id),
+  KEY idx_f_model_id (f_model_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin COMMENT = '持久化任务的补数任务';
@@ -0,0 +1,43 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+USE adp;
+
+CREATE TABLE IF NOT EXISTS t_data_quality_rule (
+  f_rule_id varchar(40) NOT NULL COMMENT '数据质量规则 id',
+  f_rule_name varchar(255) NOT NULL COMMENT '数据质量规则名称',
+  f_view_id varchar(40) NOT NULL COMMENT '视图 id',
+  f_kind varchar(40) NOT NULL COMMENT '规则类型',
+  f_config text NOT NULL COMMENT '检查配置',
+  f_schedule varchar(255) NOT NULL COMMENT '调度配置',
+  f_enabled tinyint(2) NOT NULL DEFAULT 1 COMMENT '是否启用: 0 停用, 1 启用',
+  f_emit_event tinyint(2) NOT NULL DEFAULT 0 COMMENT '检查状态变化时是否发送事件',
+  f_event_config varchar(1024) DEFAULT NULL COMMENT '事件配置',
+  f_tags varchar(255) NOT NULL DEFAULT '' COMMENT '标签',
+  f_comment varchar(255) NOT NULL DEFAULT '' COMMENT '备注',
+  f_create_time bigint(20) NOT NULL DEFAULT 0 COMMENT '创建时间',
+  f_update_time bigint(20) NOT NULL DEFAULT 0 COMMENT '更新时间',
+  f_creator varchar(40) NOT NULL DEFAULT '' COMMENT '创建者id',
+  f_creator_type varchar(20) NOT NULL DEFAULT '' COMMENT '创建者类型',
+  f_updater varchar(40) NOT NULL DEFAULT '' COMMENT '更新者id',
+  f_updater_type varchar(20) NOT NULL DEFAULT '' COMMENT '更新者类型',
+  f_last_status varchar(20) NOT NULL DEFAULT '' COMMENT '最近一次检查状态',
+  f_last_check_time bigint(20) NOT NULL DEFAULT 0 COMMENT '最近一次检查时间',
+  PRIMARY KEY (f_rule_id),
+  UNIQUE KEY uk_f_rule_name (f_rule_name, f_view_id),
+  KEY idx_f_view_id (f_view_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin COMMENT = '数据质量规则';
+
+CREATE TABLE IF NOT EXISTS t_data_quality_result (
+  f_rule_id varchar(40) NOT NULL COMMENT '数据质量规则 id',
+  f_view_id varchar(40) NOT NULL COMMENT '视图 id',
+  f_check_time bigint(20) NOT NULL DEFAULT 0 COMMENT '检查时间',
+  f_status varchar(20) NOT NULL DEFAULT '' COMMENT '检查状态',
+  f_value double NOT NULL DEFAULT 0 COMMENT '检查值',
+  f_details text DEFAULT NULL COMMENT '检查详情',
+  f_error text DEFAULT NULL COMMENT '执行出错信息',
+  PRIMARY KEY (f_rule_id, f_check_time),
+  KEY idx_f_view_id (f_view_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin COMMENT = '数据质量检查结果';
@@ -0,0 +1,22 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+USE adp;
+
+CREATE TABLE IF NOT EXISTS t_lineage_edge (
+  f_edge_id varchar(40) NOT NULL COMMENT '血缘边 id',
+  f_source_type varchar(40) NOT NULL COMMENT '上游节点类型',
+  f_source_id varchar(255) NOT NULL COMMENT '上游节点 id',
+  f_target_type varchar(40) NOT NULL COMMENT '下游节点类型',
+  f_target_id varchar(255) NOT NULL COMMENT '下游节点 id',
+  f_fields text DEFAULT NULL COMMENT '字段级映射',
+  f_owner_type varchar(40) NOT NULL COMMENT '登记血缘的模型类型',
+  f_owner_id varchar(255) NOT NULL COMMENT '登记血缘的模型 id',
+  f_update_time bigint(20) NOT NULL DEFAULT 0 COMMENT '更新时间',
+  PRIMARY KEY (f_edge_id),
+  KEY idx_f_source (f_source_type, f_source_id),
+  KEY idx_f_target (f_target_type, f_target_id),
+  KEY idx_f_owner (f_owner_type, f_owner_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin COMMENT = '血缘关系';
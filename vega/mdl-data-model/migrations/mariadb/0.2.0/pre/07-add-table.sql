@@ -0,0 +1,19 @@
+-- Copyright The kweaver.ai Authors.
+--
+-- Licensed under the Apache License, Version 2.0.
+-- See the LICENSE file in the project root for details.
+
+USE adp;
+
+CREATE TABLE IF NOT EXISTS t_model_revision (
+  f_model_type varchar(40) NOT NULL COMMENT '模型类型',
+  f_model_id varchar(40) NOT NULL COMMENT '模型 id',
+  f_revision int(11) NOT NULL COMMENT '版本号, 从 1 开始递增',
+  f_model_name varchar(255) NOT NULL DEFAULT '' COMMENT '保存时的模型名称',
+  f_content longtext NOT NULL COMMENT '模型定义快照',
+  f_comment varchar(255) NOT NULL DEFAULT '' COMMENT '版本说明',
+  f_creator varchar(40) NOT NULL DEFAULT '' COMMENT '创建者id',
+  f_creator_type varchar(20) NOT NULL DEFAULT '' COMMENT '创建者类型',
+  f_create_time bigint(20) NOT NULL DEFAULT 0 COMMENT '创建时间',
+  PRIMARY KEY (f_model_type, f_model_id, f_revision)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin COMMENT = '模型历史版本';
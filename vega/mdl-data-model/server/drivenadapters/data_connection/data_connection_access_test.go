@@ -807,10 +807,10 @@ func Test_DataConnectonAccess_DataConnectionExtendSQLBuilder(t *testing.T) {
 		})
 
 		Convey("application_scope is not empty array", func() {
-			expectedArgs := []any{"test", "\"tag_1\"", "tingyun"}
+			expectedArgs := []any{"test", "\"tag_1\"", "tingyun", "jaeger"}
 			expectedStr := "SELECT f_connection_id, f_connection_name, f_tags, f_comment," +
 				" f_create_time, f_update_time, f_data_source_type FROM " + DATA_CONNECTION_TABLE_NAME +
-				" WHERE f_connection_name = ? AND instr(f_tags, ?) > 0 AND f_data_source_type IN (?)"
+				" WHERE f_connection_name = ? AND instr(f_tags, ?) > 0 AND f_data_source_type IN (?,?)"
 
 			listQueryParams := interfaces.DataConnectionListQueryParams{
 				ApplicationScope: []string{"trace_model"},
@@ -0,0 +1,654 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package data_view
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	libCommon "github.com/kweaver-ai/kweaver-go-lib/common"
+	libdb "github.com/kweaver-ai/kweaver-go-lib/db"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"data-model/common"
+	"data-model/interfaces"
+)
+
+const (
+	DATA_QUALITY_RULE_TABLE_NAME   = "t_data_quality_rule"
+	DATA_QUALITY_RESULT_TABLE_NAME = "t_data_quality_result"
+)
+
+var (
+	dqrAccessOnce sync.Once
+	dqrAccess     interfaces.DataQualityRuleAccess
+
+	dataQualityRuleColumns = []string{
+		"f_rule_id",
+		"f_rule_name",
+		"f_view_id",
+		"f_kind",
+		"f_config",
+		"f_schedule",
+		"f_enabled",
+		"f_emit_event",
+		"f_event_config",
+		"f_tags",
+		"f_comment",
+		"f_create_time",
+		"f_update_time",
+		"f_creator",
+		"f_creator_type",
+		"f_updater",
+		"f_updater_type",
+		"f_last_status",
+		"f_last_check_time",
+	}
+)
+
+type dataQualityRuleAccess struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+}
+
+func NewDataQualityRuleAccess(appSetting *common.AppSetting) interfaces.DataQualityRuleAccess {
+	dqrAccessOnce.Do(func() {
+		dqrAccess = &dataQualityRuleAccess{
+			appSetting: appSetting,
+			db:         libdb.NewDB(&appSetting.DBSetting),
+		}
+	})
+
+	return dqrAccess
+}
+
+// 创建数据质量规则
+func (dqra *dataQualityRuleAccess) CreateDataQualityRules(ctx context.Context, rules []*interfaces.DataQualityRule) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Insert data quality rules into DB", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+	)
+
+	builder := sq.Insert(DATA_QUALITY_RULE_TABLE_NAME).Columns(dataQualityRuleColumns...)
+	for _, rule := range rules {
+		configBytes, scheduleBytes, eventConfigBytes, err := marshalDataQualityRule(rule)
+		if err != nil {
+			errDetails := fmt.Sprintf("Marshal data quality rule failed, %s", err.Error())
+			logger.Error(errDetails)
+			o11y.Error(ctx, errDetails)
+			span.SetStatus(codes.Error, "Marshal data quality rule failed")
+
+			return err
+		}
+
+		builder = builder.Values(
+			rule.RuleID,
+			rule.RuleName,
+			rule.ViewID,
+			rule.Kind,
+			configBytes,
+			scheduleBytes,
+			rule.Enabled,
+			rule.EmitEvent,
+			eventConfigBytes,
+			libCommon.TagSlice2TagString(rule.Tags),
+			rule.Comment,
+			rule.CreateTime,
+			rule.UpdateTime,
+			rule.Creator.ID,
+			rule.Creator.Type,
+			rule.Updater.ID,
+			rule.Updater.Type,
+			rule.LastStatus,
+			rule.LastCheckTime,
+		)
+	}
+
+	sqlStr, args, err := builder.ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'create data quality rules' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for creating data quality rules is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	_, err = dqra.db.Exec(sqlStr, args...)
+	if err != nil {
+		errDetails := fmt.Sprintf("Insert data quality rules failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Insert data quality rules failed")
+
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// 修改数据质量规则, 所属视图和最近一次检查状态不在此处修改
+func (dqra *dataQualityRuleAccess) UpdateDataQualityRule(ctx context.Context, rule *interfaces.DataQualityRule) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Update a data quality rule from DB", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("rule_id").String(rule.RuleID),
+	)
+
+	configBytes, scheduleBytes, eventConfigBytes, err := marshalDataQualityRule(rule)
+	if err != nil {
+		errDetails := fmt.Sprintf("Marshal data quality rule failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Marshal data quality rule failed")
+
+		return err
+	}
+
+	updateMap := map[string]any{
+		"f_rule_name":    rule.RuleName,
+		"f_kind":         rule.Kind,
+		"f_config":       configBytes,
+		"f_schedule":     scheduleBytes,
+		"f_enabled":      rule.Enabled,
+		"f_emit_event":   rule.EmitEvent,
+		"f_event_config": eventConfigBytes,
+		"f_tags":         libCommon.TagSlice2TagString(rule.Tags),
+		"f_comment":      rule.Comment,
+		"f_update_time":  rule.UpdateTime,
+		"f_updater":      rule.Updater.ID,
+		"f_updater_type": rule.Updater.Type,
+	}
+	sqlStr, args, err := sq.Update(DATA_QUALITY_RULE_TABLE_NAME).
+		SetMap(updateMap).
+		Where(sq.Eq{"f_rule_id": rule.RuleID}).
+		ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'update a data quality rule' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for updating a data quality rule is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	_, err = dqra.db.Exec(sqlStr, args...)
+	if err != nil {
+		errDetails := fmt.Sprintf("Update a data quality rule failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Update data quality rule failed")
+
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// 按 id 批量获取数据质量规则
+func (dqra *dataQualityRuleAccess) GetDataQualityRules(ctx context.Context, ruleIDs []string) ([]*interfaces.DataQualityRule, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Get data quality rules from DB", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("rule_ids").String(strings.Join(ruleIDs, ",")),
+	)
+
+	sqlStr, args, err := sq.Select(dataQualityRuleColumns...).
+		From(DATA_QUALITY_RULE_TABLE_NAME).
+		Where(sq.Eq{"f_rule_id": ruleIDs}).
+		ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'get data quality rules' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return nil, err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for getting data quality rules is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	rules, err := dqra.queryDataQualityRules(sqlStr, args)
+	if err != nil {
+		errDetails := fmt.Sprintf("Get data quality rules failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Get data quality rules failed")
+
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return rules, nil
+}
+
+// 查询数据质量规则列表
+func (dqra *dataQualityRuleAccess) ListDataQualityRules(ctx context.Context,
+	query *interfaces.ListDataQualityRuleQueryParams) ([]*interfaces.DataQualityRule, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: List data quality rules from DB",
+		trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("name_pattern").String(query.NamePattern),
+		attr.Key("view_ids").String(strings.Join(query.ViewIDs, ",")),
+		attr.Key("kind").String(query.Kind),
+		attr.Key("tag").String(query.Tag),
+		attr.Key("sort").String(query.Sort),
+		attr.Key("direction").String(query.Direction),
+	)
+
+	builder := sq.Select(dataQualityRuleColumns...).From(DATA_QUALITY_RULE_TABLE_NAME)
+	builder = buildDataQualityRuleListQuerySQL(query, builder)
+	if query.Sort != "" {
+		builder = builder.OrderBy(fmt.Sprintf("%s %s", query.Sort, query.Direction))
+	}
+
+	// 接入权限后不在数据库查询时分页，需从数据库中获取所有对象
+	sqlStr, args, err := builder.ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'list data quality rules' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return nil, err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for listing data quality rules is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	rules, err := dqra.queryDataQualityRules(sqlStr, args)
+	if err != nil {
+		errDetails := fmt.Sprintf("List data quality rules failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "List data quality rules failed")
+
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return rules, nil
+}
+
+// 删除数据质量规则及其检查结果
+func (dqra *dataQualityRuleAccess) DeleteDataQualityRules(ctx context.Context, tx *sql.Tx, ruleIDs []string) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Delete data quality rules from DB", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("rule_ids").String(strings.Join(ruleIDs, ",")),
+	)
+
+	err := dqra.deleteDataQualityRulesBy(ctx, tx, sq.Eq{"f_rule_id": ruleIDs})
+	if err != nil {
+		span.SetStatus(codes.Error, "Delete data quality rules failed")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// 删除数据视图下的所有数据质量规则及其检查结果, 随视图删除一起在事务内执行
+func (dqra *dataQualityRuleAccess) DeleteDataQualityRulesByViewIDs(ctx context.Context, tx *sql.Tx, viewIDs []string) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Delete data quality rules by view IDs from DB", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("view_ids").String(strings.Join(viewIDs, ",")),
+	)
+
+	err := dqra.deleteDataQualityRulesBy(ctx, tx, sq.Eq{"f_view_id": viewIDs})
+	if err != nil {
+		span.SetStatus(codes.Error, "Delete data quality rules failed")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// 按名称和视图检查数据质量规则是否存在
+func (dqra *dataQualityRuleAccess) CheckDataQualityRuleExistByName(ctx context.Context, ruleName, viewID string) (bool, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Check data quality rule exist by name", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("rule_name").String(ruleName),
+		attr.Key("view_id").String(viewID),
+	)
+
+	sqlStr, args, err := sq.Select("f_rule_id").
+		From(DATA_QUALITY_RULE_TABLE_NAME).
+		Where(sq.Eq{
+			"f_rule_name": ruleName,
+			"f_view_id":   viewID,
+		}).
+		ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'check data quality rule exist by name' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return false, err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for checking data quality rule exists by name is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	var ruleID string
+	err = dqra.db.QueryRow(sqlStr, args...).Scan(&ruleID)
+	if err == sql.ErrNoRows {
+		span.SetAttributes(attr.Key("no_rows").Bool(true))
+		span.SetStatus(codes.Ok, "")
+
+		return false, nil
+	}
+	if err != nil {
+		errDetails := fmt.Sprintf("Row scan failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Row scan failed")
+
+		return false, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return true, nil
+}
+
+// 查询规则的检查结果, 按检查时间倒序
+func (dqra *dataQualityRuleAccess) ListDataQualityResults(ctx context.Context,
+	query *interfaces.ListDataQualityResultQueryParams) ([]*interfaces.DataQualityResult, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: List data quality results from DB", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("rule_id").String(query.RuleID),
+		attr.Key("start").Int64(query.Start),
+		attr.Key("end").Int64(query.End),
+		attr.Key("status").String(query.Status),
+		attr.Key("limit").Int(query.Limit),
+	)
+
+	builder := sq.Select(
+		"f_rule_id",
+		"f_view_id",
+		"f_check_time",
+		"f_status",
+		"f_value",
+		"f_details",
+		"f_error",
+	).
+		From(DATA_QUALITY_RESULT_TABLE_NAME).
+		Where(sq.Eq{"f_rule_id": query.RuleID})
+
+	if query.Start > 0 {
+		builder = builder.Where(sq.GtOrEq{"f_check_time": query.Start})
+	}
+	if query.End > 0 {
+		builder = builder.Where(sq.LtOrEq{"f_check_time": query.End})
+	}
+	if query.Status != "" {
+		builder = builder.Where(sq.Eq{"f_status": query.Status})
+	}
+	builder = builder.OrderBy("f_check_time desc")
+	if query.Limit > 0 {
+		builder = builder.Limit(uint64(query.Limit))
+	}
+
+	sqlStr, args, err := builder.ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'list data quality results' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return nil, err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for listing data quality results is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	rows, err := dqra.db.Query(sqlStr, args...)
+	if err != nil {
+		errDetails := fmt.Sprintf("List data quality results failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "List data quality results failed")
+
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*interfaces.DataQualityResult, 0)
+	for rows.Next() {
+		var detailsBytes []byte
+		result := &interfaces.DataQualityResult{}
+		err = rows.Scan(
+			&result.RuleID,
+			&result.ViewID,
+			&result.CheckTime,
+			&result.Status,
+			&result.Value,
+			&detailsBytes,
+			&result.Error,
+		)
+		if err != nil {
+			errDetails := fmt.Sprintf("Row scan failed, %s", err.Error())
+			logger.Error(errDetails)
+			o11y.Error(ctx, errDetails)
+			span.SetStatus(codes.Error, "Row scan failed")
+
+			return nil, err
+		}
+
+		if len(detailsBytes) > 0 {
+			err = sonic.Unmarshal(detailsBytes, &result.Details)
+			if err != nil {
+				errDetails := fmt.Sprintf("Unmarshal result details failed, %s", err.Error())
+				logger.Error(errDetails)
+				o11y.Error(ctx, errDetails)
+				span.SetStatus(codes.Error, "Unmarshal result details failed")
+
+				return nil, err
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return results, nil
+}
+
+// 按条件删除规则, 同时删除规则的检查结果
+func (dqra *dataQualityRuleAccess) deleteDataQualityRulesBy(ctx context.Context, tx *sql.Tx, cond sq.Eq) error {
+	for _, tableName := range []string{DATA_QUALITY_RESULT_TABLE_NAME, DATA_QUALITY_RULE_TABLE_NAME} {
+		sqlStr, args, err := sq.Delete(tableName).Where(cond).ToSql()
+		if err != nil {
+			errDetails := fmt.Sprintf("Generate 'delete %s' sql stmt failed, %s", tableName, err.Error())
+			logger.Error(errDetails)
+			o11y.Error(ctx, errDetails)
+
+			return err
+		}
+
+		sqlStmt := fmt.Sprintf("Sql stmt for deleting %s is '%s'", tableName, sqlStr)
+		logger.Debug(sqlStmt)
+		o11y.Info(ctx, sqlStmt)
+
+		if tx == nil {
+			_, err = dqra.db.Exec(sqlStr, args...)
+		} else {
+			_, err = tx.Exec(sqlStr, args...)
+		}
+		if err != nil {
+			errDetails := fmt.Sprintf("Delete %s failed, %s", tableName, err.Error())
+			logger.Error(errDetails)
+			o11y.Error(ctx, errDetails)
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (dqra *dataQualityRuleAccess) queryDataQualityRules(sqlStr string, args []any) ([]*interfaces.DataQualityRule, error) {
+	rows, err := dqra.db.Query(sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make([]*interfaces.DataQualityRule, 0)
+	for rows.Next() {
+		var tagsStr string
+		var configBytes, scheduleBytes, eventConfigBytes []byte
+		rule := &interfaces.DataQualityRule{}
+		err = rows.Scan(
+			&rule.RuleID,
+			&rule.RuleName,
+			&rule.ViewID,
+			&rule.Kind,
+			&configBytes,
+			&scheduleBytes,
+			&rule.Enabled,
+			&rule.EmitEvent,
+			&eventConfigBytes,
+			&tagsStr,
+			&rule.Comment,
+			&rule.CreateTime,
+			&rule.UpdateTime,
+			&rule.Creator.ID,
+			&rule.Creator.Type,
+			&rule.Updater.ID,
+			&rule.Updater.Type,
+			&rule.LastStatus,
+			&rule.LastCheckTime,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		rule.Tags = libCommon.TagString2TagSlice(tagsStr)
+
+		err = sonic.Unmarshal(configBytes, &rule.Config)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal config failed, %s", err.Error())
+		}
+
+		err = sonic.Unmarshal(scheduleBytes, &rule.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal schedule failed, %s", err.Error())
+		}
+
+		if len(eventConfigBytes) > 0 {
+			err = sonic.Unmarshal(eventConfigBytes, &rule.EventConfig)
+			if err != nil {
+				return nil, fmt.Errorf("unmarshal event config failed, %s", err.Error())
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func marshalDataQualityRule(rule *interfaces.DataQualityRule) ([]byte, []byte, []byte, error) {
+	configBytes, err := sonic.Marshal(rule.Config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	scheduleBytes, err := sonic.Marshal(rule.Schedule)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var eventConfigBytes []byte
+	if rule.EventConfig != nil {
+		eventConfigBytes, err = sonic.Marshal(rule.EventConfig)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return configBytes, scheduleBytes, eventConfigBytes, nil
+}
+
+// 拼接列表查询sql语句
+func buildDataQualityRuleListQuerySQL(query *interfaces.ListDataQualityRuleQueryParams, builder sq.SelectBuilder) sq.SelectBuilder {
+	if query.NamePattern != "" {
+		builder = builder.Where(sq.Expr("instr(f_rule_name, ?) > 0", query.NamePattern))
+	}
+
+	if len(query.ViewIDs) > 0 {
+		builder = builder.Where(sq.Eq{"f_view_id": query.ViewIDs})
+	}
+
+	if query.Kind != "" {
+		builder = builder.Where(sq.Eq{"f_kind": query.Kind})
+	}
+
+	// 格式为: %"tagname"%
+	if query.Tag != "" {
+		builder = builder.Where(sq.Expr("instr(f_tags, ?) > 0", `"`+query.Tag+`"`))
+	}
+
+	return builder
+}
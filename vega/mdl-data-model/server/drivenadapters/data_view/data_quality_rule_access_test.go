@@ -0,0 +1,268 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package data_view
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	"data-model/interfaces"
+)
+
+func MockNewDataQualityRuleAccess(appSetting *common.AppSetting) (*dataQualityRuleAccess, sqlmock.Sqlmock) {
+	db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	dqra := &dataQualityRuleAccess{
+		appSetting: appSetting,
+		db:         db,
+	}
+	return dqra, smock
+}
+
+func mockDataQualityRuleRow(smock sqlmock.Sqlmock) *sqlmock.Rows {
+	return smock.NewRows(dataQualityRuleColumns).AddRow(
+		"r1", "not null", "v1", interfaces.DATA_QUALITY_KIND_NOT_NULL,
+		[]byte(`{"fields":["a"],"min_ratio":0.9}`), []byte(`{"type":"FIX_RATE","expression":"5m"}`),
+		true, true, []byte(`{"index_base":"quality","level":2}`),
+		`"t1"`, "", testNow, testNow, "u1", "user", "u1", "user",
+		interfaces.DATA_QUALITY_STATUS_PASSED, testNow,
+	)
+}
+
+func Test_DataQualityRuleAccess_CreateDataQualityRules(t *testing.T) {
+	Convey("Test CreateDataQualityRules", t, func() {
+		appSetting := &common.AppSetting{}
+		dqra, smock := MockNewDataQualityRuleAccess(appSetting)
+
+		sqlStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", DATA_QUALITY_RULE_TABLE_NAME,
+			strings.Join(dataQualityRuleColumns, ","),
+			strings.TrimSuffix(strings.Repeat("?,", len(dataQualityRuleColumns)), ","))
+
+		minRatio := 0.9
+		rules := []*interfaces.DataQualityRule{{
+			RuleID:   "r1",
+			RuleName: "not null",
+			ViewID:   "v1",
+			Kind:     interfaces.DATA_QUALITY_KIND_NOT_NULL,
+			Config: interfaces.DataQualityRuleConfig{
+				Fields:   []string{"a"},
+				MinRatio: &minRatio,
+			},
+			Schedule: interfaces.Schedule{Type: interfaces.SCHEDULE_TYPE_FIXED, Expression: "5m"},
+			Enabled:  true,
+		}}
+
+		Convey("Create failed, caused by exec sql error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(sqlStr).WithArgs().WillReturnError(expectedErr)
+
+			err := dqra.CreateDataQualityRules(testCtx, rules)
+			So(err, ShouldResemble, expectedErr)
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+
+		Convey("Create succeed", func() {
+			smock.ExpectExec(sqlStr).WithArgs().WillReturnResult(sqlmock.NewResult(1, 1))
+
+			err := dqra.CreateDataQualityRules(testCtx, rules)
+			So(err, ShouldBeNil)
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	})
+}
+
+func Test_DataQualityRuleAccess_GetDataQualityRules(t *testing.T) {
+	Convey("Test GetDataQualityRules", t, func() {
+		appSetting := &common.AppSetting{}
+		dqra, smock := MockNewDataQualityRuleAccess(appSetting)
+
+		sqlStr := fmt.Sprintf("SELECT %s FROM %s WHERE f_rule_id IN (?)",
+			strings.Join(dataQualityRuleColumns, ", "), DATA_QUALITY_RULE_TABLE_NAME)
+
+		Convey("Get failed, caused by query error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(sqlStr).WithArgs().WillReturnError(expectedErr)
+
+			rules, err := dqra.GetDataQualityRules(testCtx, []string{"r1"})
+			So(rules, ShouldBeNil)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Get failed, caused by invalid config", func() {
+			rows := smock.NewRows(dataQualityRuleColumns).AddRow(
+				"r1", "not null", "v1", interfaces.DATA_QUALITY_KIND_NOT_NULL,
+				[]byte(`{`), []byte(`{}`), true, false, nil,
+				"", "", testNow, testNow, "", "", "", "", "", 0,
+			)
+			smock.ExpectQuery(sqlStr).WithArgs().WillReturnRows(rows)
+
+			rules, err := dqra.GetDataQualityRules(testCtx, []string{"r1"})
+			So(rules, ShouldBeNil)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Get succeed", func() {
+			smock.ExpectQuery(sqlStr).WithArgs().WillReturnRows(mockDataQualityRuleRow(smock))
+
+			rules, err := dqra.GetDataQualityRules(testCtx, []string{"r1"})
+			So(err, ShouldBeNil)
+			So(len(rules), ShouldEqual, 1)
+			So(rules[0].Config.Fields, ShouldResemble, []string{"a"})
+			So(*rules[0].Config.MinRatio, ShouldEqual, 0.9)
+			So(rules[0].Schedule.Expression, ShouldEqual, "5m")
+			So(rules[0].EventConfig, ShouldResemble, &interfaces.DataQualityEventCfg{IndexBase: "quality", Level: 2})
+			So(rules[0].Tags, ShouldResemble, []string{"t1"})
+			So(rules[0].LastStatus, ShouldEqual, interfaces.DATA_QUALITY_STATUS_PASSED)
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	})
+}
+
+func Test_DataQualityRuleAccess_ListDataQualityRules(t *testing.T) {
+	Convey("Test ListDataQualityRules", t, func() {
+		appSetting := &common.AppSetting{}
+		dqra, smock := MockNewDataQualityRuleAccess(appSetting)
+
+		sqlStr := fmt.Sprintf("SELECT %s FROM %s WHERE f_view_id IN (?,?) AND f_kind = ? ORDER BY f_update_time desc",
+			strings.Join(dataQualityRuleColumns, ", "), DATA_QUALITY_RULE_TABLE_NAME)
+
+		query := &interfaces.ListDataQualityRuleQueryParams{
+			ViewIDs: []string{"v1", "v2"},
+			Kind:    interfaces.DATA_QUALITY_KIND_NOT_NULL,
+			PaginationQueryParameters: interfaces.PaginationQueryParameters{
+				Sort:      "f_update_time",
+				Direction: interfaces.DESC_DIRECTION,
+			},
+		}
+
+		Convey("List succeed", func() {
+			smock.ExpectQuery(sqlStr).WithArgs("v1", "v2", interfaces.DATA_QUALITY_KIND_NOT_NULL).
+				WillReturnRows(mockDataQualityRuleRow(smock))
+
+			rules, err := dqra.ListDataQualityRules(testCtx, query)
+			So(err, ShouldBeNil)
+			So(len(rules), ShouldEqual, 1)
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	})
+}
+
+func Test_DataQualityRuleAccess_DeleteDataQualityRulesByViewIDs(t *testing.T) {
+	Convey("Test DeleteDataQualityRulesByViewIDs", t, func() {
+		appSetting := &common.AppSetting{}
+		dqra, smock := MockNewDataQualityRuleAccess(appSetting)
+
+		resultSqlStr := fmt.Sprintf("DELETE FROM %s WHERE f_view_id IN (?)", DATA_QUALITY_RESULT_TABLE_NAME)
+		ruleSqlStr := fmt.Sprintf("DELETE FROM %s WHERE f_view_id IN (?)", DATA_QUALITY_RULE_TABLE_NAME)
+
+		Convey("Delete failed, caused by exec sql error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectBegin()
+			smock.ExpectExec(resultSqlStr).WithArgs("v1").WillReturnError(expectedErr)
+
+			tx, _ := dqra.db.Begin()
+			err := dqra.DeleteDataQualityRulesByViewIDs(testCtx, tx, []string{"v1"})
+			So(err, ShouldResemble, expectedErr)
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+
+		Convey("Delete succeed", func() {
+			smock.ExpectBegin()
+			smock.ExpectExec(resultSqlStr).WithArgs("v1").WillReturnResult(sqlmock.NewResult(0, 10))
+			smock.ExpectExec(ruleSqlStr).WithArgs("v1").WillReturnResult(sqlmock.NewResult(0, 2))
+
+			tx, _ := dqra.db.Begin()
+			err := dqra.DeleteDataQualityRulesByViewIDs(testCtx, tx, []string{"v1"})
+			So(err, ShouldBeNil)
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	})
+}
+
+func Test_DataQualityRuleAccess_CheckDataQualityRuleExistByName(t *testing.T) {
+	Convey("Test CheckDataQualityRuleExistByName", t, func() {
+		appSetting := &common.AppSetting{}
+		dqra, smock := MockNewDataQualityRuleAccess(appSetting)
+
+		sqlStr := fmt.Sprintf("SELECT f_rule_id FROM %s WHERE f_rule_name = ? AND f_view_id = ?",
+			DATA_QUALITY_RULE_TABLE_NAME)
+
+		Convey("Not exist", func() {
+			smock.ExpectQuery(sqlStr).WithArgs("a", "v1").WillReturnError(sql.ErrNoRows)
+
+			exist, err := dqra.CheckDataQualityRuleExistByName(testCtx, "a", "v1")
+			So(exist, ShouldBeFalse)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Exist", func() {
+			rows := smock.NewRows([]string{"f_rule_id"}).AddRow("r1")
+			smock.ExpectQuery(sqlStr).WithArgs("a", "v1").WillReturnRows(rows)
+
+			exist, err := dqra.CheckDataQualityRuleExistByName(testCtx, "a", "v1")
+			So(exist, ShouldBeTrue)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func Test_DataQualityRuleAccess_ListDataQualityResults(t *testing.T) {
+	Convey("Test ListDataQualityResults", t, func() {
+		appSetting := &common.AppSetting{}
+		dqra, smock := MockNewDataQualityRuleAccess(appSetting)
+
+		sqlStr := fmt.Sprintf("SELECT f_rule_id, f_view_id, f_check_time, f_status, f_value, f_details, f_error "+
+			"FROM %s WHERE f_rule_id = ? AND f_check_time >= ? AND f_status = ? ORDER BY f_check_time desc LIMIT 10",
+			DATA_QUALITY_RESULT_TABLE_NAME)
+
+		query := &interfaces.ListDataQualityResultQueryParams{
+			RuleID: "r1",
+			Start:  testNow,
+			Status: interfaces.DATA_QUALITY_STATUS_FAILED,
+			Limit:  10,
+		}
+
+		Convey("List succeed", func() {
+			rows := smock.NewRows([]string{"f_rule_id", "f_view_id", "f_check_time", "f_status", "f_value", "f_details", "f_error"}).
+				AddRow("r1", "v1", testNow, interfaces.DATA_QUALITY_STATUS_FAILED, 0.5, []byte(`{"total":10}`), "")
+			smock.ExpectQuery(sqlStr).WithArgs("r1", testNow, interfaces.DATA_QUALITY_STATUS_FAILED).WillReturnRows(rows)
+
+			results, err := dqra.ListDataQualityResults(testCtx, query)
+			So(err, ShouldBeNil)
+			So(len(results), ShouldEqual, 1)
+			So(results[0].Value, ShouldEqual, 0.5)
+			So(results[0].Details, ShouldResemble, map[string]any{"total": float64(10)})
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	})
+}
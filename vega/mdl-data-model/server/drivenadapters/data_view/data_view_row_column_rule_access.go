@@ -70,6 +70,7 @@ func (dva *dataViewRowColumnRuleAccess) CreateDataViewRowColumnRules(ctx context
 			"f_tags",
 			"f_comment",
 			"f_fields",
+			"f_field_masks",
 			"f_row_filters",
 			"f_create_time",
 			"f_update_time",
@@ -92,6 +93,16 @@ func (dva *dataViewRowColumnRuleAccess) CreateDataViewRowColumnRules(ctx context
 			return err
 		}
 
+		fieldMasksBytes, err := sonic.Marshal(rule.FieldMasks)
+		if err != nil {
+			errDetails := fmt.Sprintf("Marshal field masks failed, %s", err.Error())
+			logger.Error(errDetails)
+			o11y.Error(ctx, errDetails)
+			span.SetStatus(codes.Error, "Marshal field masks failed")
+
+			return err
+		}
+
 		rowFiltersBytes, err := sonic.Marshal(rule.RowFilters)
 		if err != nil {
 			errDetails := fmt.Sprintf("Marshal row filters failed, %s", err.Error())
@@ -109,6 +120,7 @@ func (dva *dataViewRowColumnRuleAccess) CreateDataViewRowColumnRules(ctx context
 			tagsStr,
 			rule.Comment,
 			fieldsBytes,
+			fieldMasksBytes,
 			rowFiltersBytes,
 			rule.CreateTime,
 			rule.UpdateTime,
@@ -214,6 +226,16 @@ func (dva *dataViewRowColumnRuleAccess) UpdateDataViewRowColumnRule(ctx context.
 		return err
 	}
 
+	fieldMasksBytes, err := sonic.Marshal(rule.FieldMasks)
+	if err != nil {
+		errDetails := fmt.Sprintf("Marshal field masks failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Marshal field masks failed")
+
+		return err
+	}
+
 	rowFiltersBytes, err := sonic.Marshal(rule.RowFilters)
 	if err != nil {
 		errDetails := fmt.Sprintf("Marshal row filters failed, %s", err.Error())
@@ -230,6 +252,7 @@ func (dva *dataViewRowColumnRuleAccess) UpdateDataViewRowColumnRule(ctx context.
 		"f_tags":         tagsStr,
 		"f_comment":      rule.Comment,
 		"f_fields":       fieldsBytes,
+		"f_field_masks":  fieldMasksBytes,
 		"f_row_filters":  rowFiltersBytes,
 		"f_update_time":  rule.UpdateTime,
 		"f_updater":      rule.Updater.ID,
@@ -284,6 +307,7 @@ func (dva *dataViewRowColumnRuleAccess) GetDataViewRowColumnRules(ctx context.Co
 		"f_tags",
 		"f_comment",
 		"f_fields",
+		"f_field_masks",
 		"f_row_filters",
 		"f_create_time",
 		"f_update_time",
@@ -322,7 +346,7 @@ func (dva *dataViewRowColumnRuleAccess) GetDataViewRowColumnRules(ctx context.Co
 	rules := make([]*interfaces.DataViewRowColumnRule, 0)
 	for rows.Next() {
 		var tagsStr string
-		var fieldsBytes, rowFiltersBytes []byte
+		var fieldsBytes, fieldMasksBytes, rowFiltersBytes []byte
 		rule := &interfaces.DataViewRowColumnRule{}
 		err = rows.Scan(
 			&rule.RuleID,
@@ -331,6 +355,7 @@ func (dva *dataViewRowColumnRuleAccess) GetDataViewRowColumnRules(ctx context.Co
 			&tagsStr,
 			&rule.Comment,
 			&fieldsBytes,
+			&fieldMasksBytes,
 			&rowFiltersBytes,
 			&rule.CreateTime,
 			&rule.UpdateTime,
@@ -360,6 +385,19 @@ func (dva *dataViewRowColumnRuleAccess) GetDataViewRowColumnRules(ctx context.Co
 			return nil, err
 		}
 
+		// 升级前创建的规则没有脱敏配置
+		if len(fieldMasksBytes) > 0 {
+			err = sonic.Unmarshal(fieldMasksBytes, &rule.FieldMasks)
+			if err != nil {
+				errDetails := fmt.Sprintf("Unmarshal field masks failed, %s", err.Error())
+				logger.Error(errDetails)
+				o11y.Error(ctx, errDetails)
+				span.SetStatus(codes.Error, "Unmarshal field masks failed")
+
+				return nil, err
+			}
+		}
+
 		err = sonic.Unmarshal(rowFiltersBytes, &rule.RowFilters)
 		if err != nil {
 			errDetails := fmt.Sprintf("Unmarshal row filters failed, %s", err.Error())
@@ -407,6 +445,7 @@ func (dva *dataViewRowColumnRuleAccess) ListDataViewRowColumnRules(ctx context.C
 		"f_tags",
 		"f_comment",
 		"f_fields",
+		"f_field_masks",
 		"f_row_filters",
 		"f_create_time",
 		"f_update_time",
@@ -465,7 +504,7 @@ func (dva *dataViewRowColumnRuleAccess) ListDataViewRowColumnRules(ctx context.C
 
 	for rows.Next() {
 		var tagsStr string
-		var fieldsBytes, rowFiltersBytes []byte
+		var fieldsBytes, fieldMasksBytes, rowFiltersBytes []byte
 		rule := &interfaces.DataViewRowColumnRule{}
 		err := rows.Scan(
 			&rule.RuleID,
@@ -474,6 +513,7 @@ func (dva *dataViewRowColumnRuleAccess) ListDataViewRowColumnRules(ctx context.C
 			&tagsStr,
 			&rule.Comment,
 			&fieldsBytes,
+			&fieldMasksBytes,
 			&rowFiltersBytes,
 			&rule.CreateTime,
 			&rule.UpdateTime,
@@ -503,6 +543,19 @@ func (dva *dataViewRowColumnRuleAccess) ListDataViewRowColumnRules(ctx context.C
 			return nil, err
 		}
 
+		// 升级前创建的规则没有脱敏配置
+		if len(fieldMasksBytes) > 0 {
+			err = sonic.Unmarshal(fieldMasksBytes, &rule.FieldMasks)
+			if err != nil {
+				errDetails := fmt.Sprintf("Unmarshal field masks failed, %s", err.Error())
+				logger.Error(errDetails)
+				o11y.Error(ctx, errDetails)
+				span.SetStatus(codes.Error, "Unmarshal field masks failed")
+
+				return nil, err
+			}
+		}
+
 		err = sonic.Unmarshal(rowFiltersBytes, &rule.RowFilters)
 		if err != nil {
 			errDetails := fmt.Sprintf("Unmarshal row filters failed, %s", err.Error())
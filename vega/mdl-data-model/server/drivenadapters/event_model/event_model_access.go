@@ -65,6 +65,7 @@ func (ema *eventModelAccess) CreateEventModels(tx *sql.Tx, ems []interfaces.Even
 			"f_formula",
 			"f_detect_algo",
 			"f_detect_analysis_algo",
+			"f_statistical_config",
 			"f_create_time",
 			"f_update_time")
 	AggregateRuleQuery := sq.Insert(AGGREGATE_RULE_TABLE_NAME).
@@ -114,6 +115,7 @@ func (ema *eventModelAccess) CreateEventModels(tx *sql.Tx, ems []interfaces.Even
 			atomicFlag = true
 			formulaStr, _ := sonic.Marshal(em.DetectRule.Formula)
 			analysisAlgo, _ := sonic.Marshal(em.DetectRule.AnalysisAlgo)
+			statisticalConfig, _ := sonic.Marshal(em.DetectRule.StatisticalConfig)
 			DetectRuleQuery = DetectRuleQuery.Values(
 				em.DetectRule.DetectRuleID,
 				em.DetectRule.Type,
@@ -121,6 +123,7 @@ func (ema *eventModelAccess) CreateEventModels(tx *sql.Tx, ems []interfaces.Even
 				formulaStr,
 				em.DetectRule.DetectAlgo,
 				analysisAlgo,
+				statisticalConfig,
 				em.DetectRule.CreateTime,
 				em.DetectRule.UpdateTime)
 		} else {
@@ -289,6 +292,7 @@ func (ema *eventModelAccess) GetEventModelByID(modelID string) (interfaces.Event
 			"f_formula",
 			"COALESCE(f_detect_algo,'')",
 			"COALESCE(f_detect_analysis_algo,'{}')",
+			"COALESCE(f_statistical_config,'null')",
 			"f_rule_priority",
 			"f_create_time",
 			"f_update_time",
@@ -299,6 +303,7 @@ func (ema *eventModelAccess) GetEventModelByID(modelID string) (interfaces.Event
 		dr := interfaces.DetectRule{}
 		var formulaStr string
 		var analysisAlgoStr string
+		var statisticalConfigStr string
 
 		drows := RQuery.QueryRow()
 		err = drows.Scan(
@@ -307,6 +312,7 @@ func (ema *eventModelAccess) GetEventModelByID(modelID string) (interfaces.Event
 			&formulaStr,
 			&dr.DetectAlgo,
 			&analysisAlgoStr,
+			&statisticalConfigStr,
 			&dr.Priority,
 			&dr.CreateTime,
 			&dr.UpdateTime)
@@ -323,6 +329,12 @@ func (ema *eventModelAccess) GetEventModelByID(modelID string) (interfaces.Event
 			logger.Errorf("event detect analysis algo parse failed,param: %v", analysisAlgoStr)
 			return interfaces.EventModel{}, err
 		}
+
+		err = sonic.Unmarshal([]byte(statisticalConfigStr), &dr.StatisticalConfig)
+		if err != nil {
+			logger.Errorf("event detect statistical config parse failed,param: %v", statisticalConfigStr)
+			return interfaces.EventModel{}, err
+		}
 		//NOTE 将检测规则嵌入事件模型
 
 		em.DetectRule = dr
@@ -400,12 +412,14 @@ func (ema *eventModelAccess) UpdateEventModel(tx *sql.Tx, em interfaces.EventMod
 	// RunWith(ema.db)
 	formulaStr, _ := sonic.Marshal(em.DetectRule.Formula)
 	detectAnalysisAlgo, _ := sonic.Marshal(em.DetectRule.AnalysisAlgo)
+	statisticalConfig, _ := sonic.Marshal(em.DetectRule.StatisticalConfig)
 
 	drule_sql, drule_args, _ := sq.Update(DETECT_RULE_TABLE_NAME).
 		Set("f_detect_rule_type", em.DetectRule.Type).
 		Set("f_formula", formulaStr).
 		Set("f_detect_algo", em.DetectRule.DetectAlgo).
 		Set("f_detect_analysis_algo", detectAnalysisAlgo).
+		Set("f_statistical_config", statisticalConfig).
 		Set("f_update_time", em.DetectRule.UpdateTime).
 		Where(sq.Eq{"f_detect_rule_id": em.DetectRule.DetectRuleID}).ToSql()
 
@@ -516,6 +530,7 @@ func (ema *eventModelAccess) QueryEventModels(ctx context.Context, params interf
 		"f_is_custom",
 		"f_enable_subscribe",
 		"COALESCE(f_detect_analysis_algo,'{}')",
+		"COALESCE(f_statistical_config,'null')",
 		"COALESCE(f_aggregate_analysis_algo,'{}')",
 		"f_status",
 		fmt.Sprintf("%s.f_create_time", EVENT_MODEL_TABLE_NAME),
@@ -631,6 +646,7 @@ func (ema *eventModelAccess) QueryEventModels(ctx context.Context, params interf
 		var DownstreamDependentModel string
 		var DownstreamDependentTask string
 		var DetectAnalysisAlgo string
+		var StatisticalConfig string
 		var AggregateAnalysisAlgo string
 		var scheduleBytes, storageConfigBytes, dispatchConfigBytes, executeParameterBytes []byte
 
@@ -656,6 +672,7 @@ func (ema *eventModelAccess) QueryEventModels(ctx context.Context, params interf
 			&em.IsCustom,
 			&em.EnableSubscribe,
 			&DetectAnalysisAlgo,
+			&StatisticalConfig,
 			&AggregateAnalysisAlgo,
 			&em.Status,
 			&em.CreateTime,
@@ -737,6 +754,13 @@ func (ema *eventModelAccess) QueryEventModels(ctx context.Context, params interf
 			return eventModels, err
 		}
 
+		err = sonic.Unmarshal([]byte(StatisticalConfig), &em.DetectRule.StatisticalConfig)
+		if err != nil {
+			logger.Errorf("detect rule statistical config parse failed,%v", err.Error())
+			logger.Errorf("detect rule statistical config parse failed,StatisticalConfig:%v", StatisticalConfig)
+			return eventModels, err
+		}
+
 		err = sonic.Unmarshal([]byte(AggregateAnalysisAlgo), &em.AggregateRule.AnalysisAlgo)
 		if err != nil {
 			//TODO ADD LOG
@@ -227,11 +227,11 @@ func Test_EventModelAccess_GetEventModelByID(t *testing.T) {
 
 		rule_rows := sqlmock.NewRows([]string{
 			"f_detect_rule_id", "f_detect_rule_type", "f_formula", "f_detect_algo",
-			"f_detect_analysis_algo", "f_rule_priority", "f_create_time", "f_update_time",
+			"f_detect_analysis_algo", "f_statistical_config", "f_rule_priority", "f_create_time", "f_update_time",
 		}).AddRow(
 			"1", "range_detect", `[{"level":1,"filter":{"logic_operator":"","filter_express":{"name":"cpu利用率","value":[0.9,0.97],"operation":"range"},"children":[]}},
 		{"level":2,"filter":{"logic_operator":"","filter_express":{"name":"cpu利用率","value":[0.85,0.9],"operation":"range"},"children":[]}}]`, "",
-			"{}", 99, testUpdateTime, testUpdateTime)
+			"{}", "null", 99, testUpdateTime, testUpdateTime)
 
 		model_sql := fmt.Sprintf("SELECT f_event_model_id, f_event_model_name, f_event_model_type, "+
 			"f_event_model_tags, f_event_model_comment, f_data_source_type, f_data_source, f_detect_rule_id, "+
@@ -240,7 +240,8 @@ func Test_EventModelAccess_GetEventModelByID(t *testing.T) {
 			"FROM %s WHERE f_event_model_id = ?", EVENT_MODEL_TABLE_NAME)
 		//NOTE: 注意空格不对也会报错
 		rule_sql := fmt.Sprintf("SELECT f_detect_rule_id, f_detect_rule_type, f_formula, "+
-			"COALESCE(f_detect_algo,''), COALESCE(f_detect_analysis_algo,'{}'), f_rule_priority, "+
+			"COALESCE(f_detect_algo,''), COALESCE(f_detect_analysis_algo,'{}'), "+
+			"COALESCE(f_statistical_config,'null'), f_rule_priority, "+
 			"f_create_time, f_update_time FROM %s WHERE f_detect_rule_id = ?", DETECT_RULE_TABLE_NAME)
 
 		modelID := "1"
@@ -326,8 +327,8 @@ func Test_EventModelAccess_CreateEventModel(t *testing.T) {
 			"f_is_active,f_is_custom,f_enable_subscribe,f_status,f_downstream_dependent_model,"+
 			"f_creator,f_creator_type,f_create_time,f_update_time) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)", EVENT_MODEL_TABLE_NAME)
 		rule_sql := fmt.Sprintf("INSERT INTO %s (f_detect_rule_id,f_detect_rule_type,f_rule_priority,"+
-			"f_formula,f_detect_algo,f_detect_analysis_algo,f_create_time,f_update_time) "+
-			"VALUES (?,?,?,?,?,?,?,?)", DETECT_RULE_TABLE_NAME)
+			"f_formula,f_detect_algo,f_detect_analysis_algo,f_statistical_config,f_create_time,f_update_time) "+
+			"VALUES (?,?,?,?,?,?,?,?,?)", DETECT_RULE_TABLE_NAME)
 
 		Convey("CreateModel Success \n", func() {
 			smock.ExpectBegin()
@@ -388,7 +389,7 @@ func Test_EventModelAccess_UpdateEventModel(t *testing.T) {
 			f_status = ?, f_update_time = ?, f_downstream_dependent_model = ? WHERE f_event_model_id = ?`, EVENT_MODEL_TABLE_NAME)
 
 		rule_sql := fmt.Sprintf(`UPDATE %s SET f_detect_rule_type = ?, f_formula = ?, f_detect_algo = ?, 
-			f_detect_analysis_algo = ?, f_update_time = ? WHERE f_detect_rule_id = ?`, DETECT_RULE_TABLE_NAME)
+			f_detect_analysis_algo = ?, f_statistical_config = ?, f_update_time = ? WHERE f_detect_rule_id = ?`, DETECT_RULE_TABLE_NAME)
 
 		Convey("update Success \n", func() {
 			smock.ExpectBegin()
@@ -482,7 +483,7 @@ func Test_EventModelAccess_QueryEventModels(t *testing.T) {
 			"COALESCE(f_aggregate_rule_type, '')", "COALESCE(f_aggregate_algo,'')",
 			"COALESCE(f_group_fields,'')", "f_default_time_window", "f_is_active", "f_is_custom",
 			"f_enable_subscribe", "COALESCE(f_detect_analysis_algo,'{}')",
-			"COALESCE(f_aggregate_analysis_algo,'{}')", "f_status", "event_models.f_create_time",
+			"COALESCE(f_statistical_config,'null')", "COALESCE(f_aggregate_analysis_algo,'{}')", "f_status", "event_models.f_create_time",
 			"event_models.f_update_time", "event_model_detect_rules.f_create_time",
 			"event_model_detect_rules.f_update_time", "event_model_aggregate_rules.f_create_time",
 			"event_model_aggregate_rules.f_update_time", "f_task_id", "f_model_id", "f_storage_config",
@@ -491,7 +492,7 @@ func Test_EventModelAccess_QueryEventModels(t *testing.T) {
 			"t_event_model_task.f_create_time", "t_event_model_task.f_update_time"},
 		).AddRow(
 			"1", "测试中的名称", "atomic", "xx1,xx2", "comment", "metric_model", "[\"1\"]", "", "", "", "", "", "",
-			"", "", "", "{\"interval\":5,\"unit\":\"m\"}", 1, 1, 0, "{}", "{}", 1, testUpdateTime, testUpdateTime,
+			"", "", "", "{\"interval\":5,\"unit\":\"m\"}", 1, 1, 0, "{}", "null", "{}", 1, testUpdateTime, testUpdateTime,
 			0, 0, 0, 0, eventTask.TaskID, eventTask.ModelID, storageConfigBytes, eventScheduleBytes,
 			dispatchConfigBytes, executeParameterBytes, eventTask.TaskStatus, eventTask.ErrorDetails, "",
 			eventTask.StatusUpdateTime, eventTask.ScheduleSyncStatus, eventTask.CreateTime, eventTask.UpdateTime)
@@ -503,7 +504,7 @@ func Test_EventModelAccess_QueryEventModels(t *testing.T) {
 			"COALESCE(f_aggregate_rule_type, ''), COALESCE(f_aggregate_algo,''), "+
 			"COALESCE(f_group_fields,''), f_default_time_window, f_is_active, f_is_custom, "+
 			"f_enable_subscribe, COALESCE(f_detect_analysis_algo,'{}'), "+
-			"COALESCE(f_aggregate_analysis_algo,'{}'), f_status, t_event_models.f_create_time, "+
+			"COALESCE(f_statistical_config,'null'), COALESCE(f_aggregate_analysis_algo,'{}'), f_status, t_event_models.f_create_time, "+
 			"t_event_models.f_update_time, COALESCE(t_event_model_detect_rules.f_create_time,0), "+
 			"COALESCE(t_event_model_detect_rules.f_update_time,0), "+
 			"COALESCE(t_event_model_aggregate_rules.f_create_time,0), "+
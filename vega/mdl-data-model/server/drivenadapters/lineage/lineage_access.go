@@ -0,0 +1,328 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package lineage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	libdb "github.com/kweaver-ai/kweaver-go-lib/db"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/rs/xid"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"data-model/common"
+	"data-model/interfaces"
+)
+
+const (
+	LINEAGE_EDGE_TABLE_NAME = "t_lineage_edge"
+)
+
+var (
+	lAccessOnce sync.Once
+	lAccess     interfaces.LineageAccess
+
+	lineageEdgeColumns = []string{
+		"f_edge_id",
+		"f_source_type",
+		"f_source_id",
+		"f_target_type",
+		"f_target_id",
+		"f_fields",
+		"f_owner_type",
+		"f_owner_id",
+		"f_update_time",
+	}
+)
+
+type lineageAccess struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+}
+
+func NewLineageAccess(appSetting *common.AppSetting) interfaces.LineageAccess {
+	lAccessOnce.Do(func() {
+		lAccess = &lineageAccess{
+			appSetting: appSetting,
+			db:         libdb.NewDB(&appSetting.DBSetting),
+		}
+	})
+
+	return lAccess
+}
+
+// 先删除 owner 登记的血缘边再写入新的边, 删除和写入应在同一事务内执行
+func (la *lineageAccess) ReplaceLineageEdges(ctx context.Context, tx *sql.Tx, owner interfaces.LineageNode,
+	edges []*interfaces.LineageEdge) error {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Replace lineage edges", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("owner_type").String(owner.Type),
+		attr.Key("owner_id").String(owner.ID),
+	)
+
+	err := la.deleteLineageEdgesByOwners(ctx, tx, owner.Type, []string{owner.ID})
+	if err != nil {
+		span.SetStatus(codes.Error, "Delete lineage edges failed")
+		return err
+	}
+
+	if len(edges) == 0 {
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+
+	updateTime := time.Now().UnixMilli()
+	builder := sq.Insert(LINEAGE_EDGE_TABLE_NAME).Columns(lineageEdgeColumns...)
+	for _, edge := range edges {
+		if edge.ID == "" {
+			edge.ID = xid.New().String()
+		}
+		edge.Owner = owner
+		edge.UpdateTime = updateTime
+
+		fieldsStr := ""
+		if len(edge.Fields) > 0 {
+			fieldsStr, err = sonic.MarshalString(edge.Fields)
+			if err != nil {
+				errDetails := fmt.Sprintf("Marshal fields of lineage edge failed, %s", err.Error())
+				logger.Error(errDetails)
+				o11y.Error(ctx, errDetails)
+				span.SetStatus(codes.Error, "Marshal lineage edge failed")
+
+				return err
+			}
+		}
+
+		builder = builder.Values(
+			edge.ID,
+			edge.Source.Type,
+			edge.Source.ID,
+			edge.Target.Type,
+			edge.Target.ID,
+			fieldsStr,
+			edge.Owner.Type,
+			edge.Owner.ID,
+			edge.UpdateTime,
+		)
+	}
+
+	sqlStr, args, err := builder.ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'insert lineage edges' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for inserting lineage edges is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	if tx == nil {
+		_, err = la.db.Exec(sqlStr, args...)
+	} else {
+		_, err = tx.Exec(sqlStr, args...)
+	}
+	if err != nil {
+		errDetails := fmt.Sprintf("Insert lineage edges failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Insert lineage edges failed")
+
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// 删除模型登记的血缘边, 随模型删除一起在事务内执行
+func (la *lineageAccess) DeleteLineageEdgesByOwners(ctx context.Context, tx *sql.Tx, ownerType string, ownerIDs []string) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Delete lineage edges by owners", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("owner_type").String(ownerType),
+		attr.Key("owner_ids").String(strings.Join(ownerIDs, ",")),
+	)
+
+	err := la.deleteLineageEdgesByOwners(ctx, tx, ownerType, ownerIDs)
+	if err != nil {
+		span.SetStatus(codes.Error, "Delete lineage edges failed")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// 获取与节点相连的血缘边
+func (la *lineageAccess) ListLineageEdgesByNodes(ctx context.Context, direction string,
+	nodes []interfaces.LineageNode) ([]*interfaces.LineageEdge, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: List lineage edges by nodes", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("direction").String(direction),
+	)
+
+	edges := make([]*interfaces.LineageEdge, 0)
+	if len(nodes) == 0 {
+		span.SetStatus(codes.Ok, "")
+		return edges, nil
+	}
+
+	// 上游查询以节点为下游的边, 下游查询以节点为上游的边
+	typeColumn, idColumn := "f_source_type", "f_source_id"
+	if direction == interfaces.LINEAGE_DIRECTION_UPSTREAM {
+		typeColumn, idColumn = "f_target_type", "f_target_id"
+	}
+
+	// 按节点类型分组, 每种类型一个条件
+	nodeTypes := make([]string, 0)
+	nodeIDsMap := make(map[string][]string)
+	for _, node := range nodes {
+		if _, ok := nodeIDsMap[node.Type]; !ok {
+			nodeTypes = append(nodeTypes, node.Type)
+		}
+		nodeIDsMap[node.Type] = append(nodeIDsMap[node.Type], node.ID)
+	}
+	conds := sq.Or{}
+	for _, nodeType := range nodeTypes {
+		conds = append(conds, sq.Eq{
+			typeColumn: nodeType,
+			idColumn:   nodeIDsMap[nodeType],
+		})
+	}
+
+	sqlStr, args, err := sq.Select(lineageEdgeColumns...).
+		From(LINEAGE_EDGE_TABLE_NAME).
+		Where(conds).
+		ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'list lineage edges' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return nil, err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for listing lineage edges is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	rows, err := la.db.Query(sqlStr, args...)
+	if err != nil {
+		errDetails := fmt.Sprintf("List lineage edges failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "List lineage edges failed")
+
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fieldsStr sql.NullString
+		edge := &interfaces.LineageEdge{}
+		err = rows.Scan(
+			&edge.ID,
+			&edge.Source.Type,
+			&edge.Source.ID,
+			&edge.Target.Type,
+			&edge.Target.ID,
+			&fieldsStr,
+			&edge.Owner.Type,
+			&edge.Owner.ID,
+			&edge.UpdateTime,
+		)
+		if err != nil {
+			errDetails := fmt.Sprintf("Row scan failed, %s", err.Error())
+			logger.Error(errDetails)
+			o11y.Error(ctx, errDetails)
+			span.SetStatus(codes.Error, "Row scan failed")
+
+			return nil, err
+		}
+
+		edge.Fields = make([]interfaces.LineageFieldMapping, 0)
+		if fieldsStr.Valid && fieldsStr.String != "" {
+			err = sonic.UnmarshalString(fieldsStr.String, &edge.Fields)
+			if err != nil {
+				errDetails := fmt.Sprintf("Unmarshal fields of lineage edge '%s' failed, %s", edge.ID, err.Error())
+				logger.Error(errDetails)
+				o11y.Error(ctx, errDetails)
+				span.SetStatus(codes.Error, "Unmarshal lineage edge failed")
+
+				return nil, err
+			}
+		}
+
+		edges = append(edges, edge)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return edges, nil
+}
+
+func (la *lineageAccess) deleteLineageEdgesByOwners(ctx context.Context, tx *sql.Tx, ownerType string, ownerIDs []string) error {
+	sqlStr, args, err := sq.Delete(LINEAGE_EDGE_TABLE_NAME).
+		Where(sq.Eq{
+			"f_owner_type": ownerType,
+			"f_owner_id":   ownerIDs,
+		}).
+		ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'delete lineage edges' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+
+		return err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for deleting lineage edges is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	if tx == nil {
+		_, err = la.db.Exec(sqlStr, args...)
+	} else {
+		_, err = tx.Exec(sqlStr, args...)
+	}
+	if err != nil {
+		errDetails := fmt.Sprintf("Delete lineage edges failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+
+		return err
+	}
+
+	return nil
+}
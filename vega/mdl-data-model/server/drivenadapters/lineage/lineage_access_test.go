@@ -0,0 +1,202 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package lineage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	"data-model/interfaces"
+)
+
+var (
+	testCtx = context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+)
+
+func MockNewLineageAccess(appSetting *common.AppSetting) (*lineageAccess, sqlmock.Sqlmock) {
+	db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	la := &lineageAccess{
+		appSetting: appSetting,
+		db:         db,
+	}
+	return la, smock
+}
+
+func Test_LineageAccess_ReplaceLineageEdges(t *testing.T) {
+	Convey("Test ReplaceLineageEdges", t, func() {
+		appSetting := &common.AppSetting{}
+		la, smock := MockNewLineageAccess(appSetting)
+
+		owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_METRIC_MODEL, ID: "m1"}
+		deleteSqlStr := fmt.Sprintf("DELETE FROM %s WHERE f_owner_id IN (?) AND f_owner_type = ?", LINEAGE_EDGE_TABLE_NAME)
+		insertSqlStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?,?,?,?,?,?,?,?,?)",
+			LINEAGE_EDGE_TABLE_NAME, strings.Join(lineageEdgeColumns, ","))
+
+		edges := []*interfaces.LineageEdge{
+			{
+				Source: interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, ID: "v1"},
+				Target: owner,
+				Fields: []interfaces.LineageFieldMapping{{SourceField: "a", TargetField: "a"}},
+			},
+		}
+
+		Convey("Replace failed, caused by delete error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectBegin()
+			smock.ExpectExec(deleteSqlStr).WithArgs("m1", owner.Type).WillReturnError(expectedErr)
+
+			tx, _ := la.db.Begin()
+			err := la.ReplaceLineageEdges(testCtx, tx, owner, edges)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Replace failed, caused by insert error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectBegin()
+			smock.ExpectExec(deleteSqlStr).WithArgs("m1", owner.Type).WillReturnResult(sqlmock.NewResult(0, 1))
+			smock.ExpectExec(insertSqlStr).WillReturnError(expectedErr)
+
+			tx, _ := la.db.Begin()
+			err := la.ReplaceLineageEdges(testCtx, tx, owner, edges)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Replace succeed without edges", func() {
+			smock.ExpectBegin()
+			smock.ExpectExec(deleteSqlStr).WithArgs("m1", owner.Type).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			tx, _ := la.db.Begin()
+			err := la.ReplaceLineageEdges(testCtx, tx, owner, nil)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Replace succeed", func() {
+			smock.ExpectBegin()
+			smock.ExpectExec(deleteSqlStr).WithArgs("m1", owner.Type).WillReturnResult(sqlmock.NewResult(0, 1))
+			smock.ExpectExec(insertSqlStr).WithArgs(sqlmock.AnyArg(), "data_view", "v1", "metric_model", "m1",
+				`[{"source_field":"a","target_field":"a"}]`, "metric_model", "m1", sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			tx, _ := la.db.Begin()
+			err := la.ReplaceLineageEdges(testCtx, tx, owner, edges)
+			So(err, ShouldBeNil)
+			So(edges[0].ID, ShouldNotBeEmpty)
+			So(edges[0].Owner, ShouldResemble, owner)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_LineageAccess_DeleteLineageEdgesByOwners(t *testing.T) {
+	Convey("Test DeleteLineageEdgesByOwners", t, func() {
+		appSetting := &common.AppSetting{}
+		la, smock := MockNewLineageAccess(appSetting)
+
+		sqlStr := fmt.Sprintf("DELETE FROM %s WHERE f_owner_id IN (?,?) AND f_owner_type = ?", LINEAGE_EDGE_TABLE_NAME)
+
+		Convey("Delete failed", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(sqlStr).WithArgs("v1", "v2", "data_view").WillReturnError(expectedErr)
+
+			err := la.DeleteLineageEdgesByOwners(testCtx, nil, interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, []string{"v1", "v2"})
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Delete succeed", func() {
+			smock.ExpectExec(sqlStr).WithArgs("v1", "v2", "data_view").WillReturnResult(sqlmock.NewResult(0, 3))
+
+			err := la.DeleteLineageEdgesByOwners(testCtx, nil, interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, []string{"v1", "v2"})
+			So(err, ShouldBeNil)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_LineageAccess_ListLineageEdgesByNodes(t *testing.T) {
+	Convey("Test ListLineageEdgesByNodes", t, func() {
+		appSetting := &common.AppSetting{}
+		la, smock := MockNewLineageAccess(appSetting)
+
+		nodes := []interfaces.LineageNode{
+			{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, ID: "v1"},
+			{Type: interfaces.LINEAGE_NODE_TYPE_METRIC_MODEL, ID: "m1"},
+			{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, ID: "v2"},
+		}
+		downstreamSqlStr := fmt.Sprintf("SELECT %s FROM %s WHERE (f_source_id IN (?,?) AND f_source_type = ? "+
+			"OR f_source_id IN (?) AND f_source_type = ?)", strings.Join(lineageEdgeColumns, ", "), LINEAGE_EDGE_TABLE_NAME)
+		upstreamSqlStr := fmt.Sprintf("SELECT %s FROM %s WHERE (f_target_id IN (?) AND f_target_type = ?)",
+			strings.Join(lineageEdgeColumns, ", "), LINEAGE_EDGE_TABLE_NAME)
+
+		Convey("List succeed without nodes", func() {
+			edges, err := la.ListLineageEdgesByNodes(testCtx, interfaces.LINEAGE_DIRECTION_DOWNSTREAM, nil)
+			So(err, ShouldBeNil)
+			So(len(edges), ShouldEqual, 0)
+		})
+
+		Convey("List failed, caused by query error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(downstreamSqlStr).WithArgs("v1", "v2", "data_view", "m1", "metric_model").
+				WillReturnError(expectedErr)
+
+			_, err := la.ListLineageEdgesByNodes(testCtx, interfaces.LINEAGE_DIRECTION_DOWNSTREAM, nodes)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("List failed, caused by unmarshal fields error", func() {
+			rows := sqlmock.NewRows(lineageEdgeColumns).
+				AddRow("e1", "data_view", "v1", "metric_model", "m2", "[{", "metric_model", "m2", 1000)
+			smock.ExpectQuery(downstreamSqlStr).WithArgs("v1", "v2", "data_view", "m1", "metric_model").
+				WillReturnRows(rows)
+
+			_, err := la.ListLineageEdgesByNodes(testCtx, interfaces.LINEAGE_DIRECTION_DOWNSTREAM, nodes)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("List downstream succeed", func() {
+			rows := sqlmock.NewRows(lineageEdgeColumns).
+				AddRow("e1", "data_view", "v1", "metric_model", "m2", `[{"source_field":"a","target_field":"a"}]`,
+					"metric_model", "m2", 1000).
+				AddRow("e2", "metric_model", "m1", "objective_model", "o1", nil, "objective_model", "o1", 1000)
+			smock.ExpectQuery(downstreamSqlStr).WithArgs("v1", "v2", "data_view", "m1", "metric_model").
+				WillReturnRows(rows)
+
+			edges, err := la.ListLineageEdgesByNodes(testCtx, interfaces.LINEAGE_DIRECTION_DOWNSTREAM, nodes)
+			So(err, ShouldBeNil)
+			So(len(edges), ShouldEqual, 2)
+			So(edges[0].Fields, ShouldResemble, []interfaces.LineageFieldMapping{{SourceField: "a", TargetField: "a"}})
+			So(edges[1].Source, ShouldResemble, interfaces.LineageNode{Type: "metric_model", ID: "m1"})
+			So(edges[1].Fields, ShouldResemble, []interfaces.LineageFieldMapping{})
+		})
+
+		Convey("List upstream succeed", func() {
+			rows := sqlmock.NewRows(lineageEdgeColumns)
+			smock.ExpectQuery(upstreamSqlStr).WithArgs("o1", "objective_model").WillReturnRows(rows)
+
+			edges, err := la.ListLineageEdgesByNodes(testCtx, interfaces.LINEAGE_DIRECTION_UPSTREAM,
+				[]interfaces.LineageNode{{Type: interfaces.LINEAGE_NODE_TYPE_OBJECTIVE_MODEL, ID: "o1"}})
+			So(err, ShouldBeNil)
+			So(len(edges), ShouldEqual, 0)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
@@ -401,6 +401,16 @@ func (mma *metricModelAccess) GetMetricModelByModelID(ctx context.Context, model
 		}
 		metricModel.FormulaConfig = derivedConfig
 	}
+	// RED指标
+	if metricModel.QueryType == interfaces.RED && formulaConfigBytes != nil {
+		var redConfig interfaces.REDConfig
+		err = sonic.Unmarshal(formulaConfigBytes, &redConfig)
+		if err != nil {
+			logger.Errorf("Failed to unmarshal Formula Config after getting metric model, err: %v", err.Error())
+			return interfaces.MetricModel{}, false, err
+		}
+		metricModel.FormulaConfig = redConfig
+	}
 	// 反序列化排序字段
 	if orderByBytes != nil {
 		err = sonic.Unmarshal(orderByBytes, &metricModel.OrderByFields)
@@ -576,6 +586,16 @@ func (mma *metricModelAccess) GetMetricModelsByModelIDs(ctx context.Context, mod
 			}
 			metricModel.FormulaConfig = derivedConfig
 		}
+		// RED指标
+		if metricModel.QueryType == interfaces.RED && formulaConfigBytes != nil {
+			var redConfig interfaces.REDConfig
+			err = sonic.Unmarshal(formulaConfigBytes, &redConfig)
+			if err != nil {
+				logger.Errorf("Failed to unmarshal Formula Config after getting metric model, err: %v", err.Error())
+				return metricModels, err
+			}
+			metricModel.FormulaConfig = redConfig
+		}
 		// 反序列化排序字段
 		if orderByBytes != nil {
 			err = sonic.Unmarshal(orderByBytes, &metricModel.OrderByFields)
@@ -1015,6 +1035,16 @@ func (mma *metricModelAccess) ListSimpleMetricModels(ctx context.Context, models
 			}
 			simpleMetricModel.FormulaConfig = derivedConfig
 		}
+		// RED指标
+		if simpleMetricModel.QueryType == interfaces.RED && formulaConfigBytes != nil {
+			var redConfig interfaces.REDConfig
+			err = sonic.Unmarshal(formulaConfigBytes, &redConfig)
+			if err != nil {
+				logger.Errorf("Failed to unmarshal Formula Config after getting metric model, err: %v", err.Error())
+				return simpleMetricModels, err
+			}
+			simpleMetricModel.FormulaConfig = redConfig
+		}
 		// 反序列化排序字段
 		if orderByBytes != nil {
 			err = sonic.Unmarshal(orderByBytes, &simpleMetricModel.OrderByFields)
@@ -1524,6 +1554,16 @@ func (mma *metricModelAccess) GetMetricModelsByGroupID(ctx context.Context, grou
 			}
 			metricModel.FormulaConfig = derivedConfig
 		}
+		// RED指标
+		if metricModel.QueryType == interfaces.RED && formulaConfigBytes != nil {
+			var redConfig interfaces.REDConfig
+			err = sonic.Unmarshal(formulaConfigBytes, &redConfig)
+			if err != nil {
+				logger.Errorf("Failed to unmarshal Formula Config after getting metric model, err: %v", err.Error())
+				return metricModels, err
+			}
+			metricModel.FormulaConfig = redConfig
+		}
 
 		// 反序列化排序字段
 		if orderByBytes != nil {
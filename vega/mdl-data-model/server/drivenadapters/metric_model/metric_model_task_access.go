@@ -28,6 +28,7 @@ import (
 
 const (
 	METRIC_MODEL_TASK_TABLE_NAME = "t_metric_model_task"
+	JOB_LEASE_TABLE_NAME         = "t_job_lease"
 )
 
 var (
@@ -392,6 +393,59 @@ func (mmta *metricModelTaskAccess) GetMetricTasksByTaskIDs(ctx context.Context,
 	return tasks, nil
 }
 
+// 按任务id批量获取运行任务的 data-model-job 副本, 只返回未过期的租约
+func (mmta *metricModelTaskAccess) GetMetricTaskOwners(ctx context.Context, taskIDs []string) (map[string]string, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "Select metric model task owners", trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()))
+	defer span.End()
+
+	owners := make(map[string]string)
+
+	sqlStr, vals, err := sq.Select(
+		"f_job_id",
+		"f_owner").
+		From(JOB_LEASE_TABLE_NAME).
+		Where(sq.Eq{"f_job_id": taskIDs}).
+		Where(sq.GtOrEq{"f_expire_time": time.Now().UnixMilli()}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Failed to build the sql of select task owners, error: %s", err.Error())
+		o11y.Error(ctx, fmt.Sprintf("Failed to build the sql of select task owners, error: %s", err.Error()))
+		span.SetStatus(codes.Error, "Build sql failed ")
+		return owners, err
+	}
+
+	// 记录处理的 sql 字符串
+	o11y.Info(ctx, fmt.Sprintf("查询指标模型任务所在副本的 sql 语句: %s; taskIDs: %v", sqlStr, taskIDs))
+	rows, err := mmta.db.Query(sqlStr, vals...)
+	if err != nil {
+		logger.Errorf("list data error: %v\n", err)
+		span.SetStatus(codes.Error, "List data error")
+		o11y.Error(ctx, fmt.Sprintf("List data error: %v", err))
+
+		return owners, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, owner string
+		err := rows.Scan(&taskID, &owner)
+		if err != nil {
+			logger.Errorf("row scan failed, err: %v \n", err)
+			span.SetStatus(codes.Error, "Row scan error")
+			o11y.Error(ctx, fmt.Sprintf("Row scan error: %v", err))
+			return owners, err
+		}
+
+		owners[taskID] = owner
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return owners, nil
+}
+
 // 按模型id批量获取任务信息
 func (mmta *metricModelTaskAccess) GetMetricTasksByModelIDs(ctx context.Context, modelIDs []string) (map[string]interfaces.MetricTask, error) {
 	ctx, span := ar_trace.Tracer.Start(ctx, "Select metric model tasks", trace.WithSpanKind(trace.SpanKindClient))
@@ -221,6 +221,54 @@ func Test_MetricModelTaskAccess_GetMetricTaskIDsByModelIDs(t *testing.T) {
 	})
 }
 
+func Test_MetricModelTaskAccess_GetMetricTaskOwners(t *testing.T) {
+	Convey("test GetMetricTaskOwners\n", t, func() {
+		appSetting := &common.AppSetting{}
+		mmta, smock := MockNewMetricModelTaskAccess(appSetting)
+
+		sqlStr := fmt.Sprintf("SELECT f_job_id, f_owner FROM %s WHERE f_job_id IN (?) AND f_expire_time >= ?",
+			JOB_LEASE_TABLE_NAME)
+
+		Convey("GetMetricTaskOwners Success \n", func() {
+			rows := sqlmock.NewRows([]string{"f_job_id", "f_owner"}).AddRow("1", "data-model-job-0")
+			smock.ExpectQuery(sqlStr).WithArgs("1", sqlmock.AnyArg()).WillReturnRows(rows)
+
+			owners, err := mmta.GetMetricTaskOwners(testCtx, []string{"1"})
+			So(err, ShouldBeNil)
+			So(owners, ShouldResemble, map[string]string{"1": "data-model-job-0"})
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+
+		Convey("Get failed, caused by query error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(sqlStr).WithArgs("1", sqlmock.AnyArg()).WillReturnError(expectedErr)
+
+			owners, err := mmta.GetMetricTaskOwners(testCtx, []string{"1"})
+			So(owners, ShouldResemble, map[string]string{})
+			So(err, ShouldResemble, expectedErr)
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+
+		Convey("Get failed, caused by the scan error", func() {
+			rows := sqlmock.NewRows([]string{"f_job_id"}).AddRow("1")
+			smock.ExpectQuery(sqlStr).WithArgs("1", sqlmock.AnyArg()).WillReturnRows(rows)
+
+			_, err := mmta.GetMetricTaskOwners(testCtx, []string{"1"})
+			So(err, ShouldNotBeNil)
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	})
+}
+
 func Test_MetricModelTaskAccess_UpdateMetricTask(t *testing.T) {
 	Convey("test UpdateMetricTask\n", t, func() {
 		appSetting := &common.AppSetting{}
@@ -0,0 +1,303 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package metric_model
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	libdb "github.com/kweaver-ai/kweaver-go-lib/db"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"data-model/common"
+	"data-model/interfaces"
+)
+
+const (
+	METRIC_TASK_BACKFILL_TABLE_NAME = "t_metric_task_backfill"
+)
+
+var (
+	mtbAccessOnce sync.Once
+	mtbAccess     interfaces.MetricTaskBackfillAccess
+)
+
+type metricTaskBackfillAccess struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+}
+
+func NewMetricTaskBackfillAccess(appSetting *common.AppSetting) interfaces.MetricTaskBackfillAccess {
+	mtbAccessOnce.Do(func() {
+		mtbAccess = &metricTaskBackfillAccess{
+			appSetting: appSetting,
+			db:         libdb.NewDB(&appSetting.DBSetting),
+		}
+	})
+
+	return mtbAccess
+}
+
+// 创建补数任务
+func (mtba *metricTaskBackfillAccess) CreateMetricTaskBackfill(ctx context.Context, backfill interfaces.MetricTaskBackfill) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "Insert into metric task backfill", trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()))
+	defer span.End()
+
+	stepsBytes, err := sonic.Marshal(backfill.Steps)
+	if err != nil {
+		logger.Errorf("Failed to marshal steps, err: %v", err.Error())
+		return err
+	}
+
+	sqlStr, args, err := sq.Insert(METRIC_TASK_BACKFILL_TABLE_NAME).
+		Columns(
+			"f_id",
+			"f_task_id",
+			"f_model_id",
+			"f_module_type",
+			"f_start",
+			"f_end",
+			"f_steps",
+			"f_status",
+			"f_status_details",
+			"f_checkpoint",
+			"f_total_points",
+			"f_done_points",
+			"f_create_time",
+			"f_update_time",
+			"f_creator",
+			"f_creator_type",
+		).
+		Values(
+			backfill.ID,
+			backfill.TaskID,
+			backfill.ModelID,
+			backfill.ModuleType,
+			backfill.Start,
+			backfill.End,
+			stepsBytes,
+			backfill.Status,
+			backfill.StatusDetails,
+			backfill.Checkpoint,
+			backfill.TotalPoints,
+			backfill.DonePoints,
+			backfill.CreateTime,
+			backfill.UpdateTime,
+			backfill.Creator.ID,
+			backfill.Creator.Type,
+		).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Failed to build the sql of insert metric task backfill, error: %s", err.Error())
+		o11y.Error(ctx, fmt.Sprintf("Failed to build the sql of insert metric task backfill, error: %s", err.Error()))
+		span.SetStatus(codes.Error, "Build sql failed ")
+		return err
+	}
+
+	// 记录处理的 sql 字符串
+	o11y.Info(ctx, fmt.Sprintf("创建补数任务的 sql 语句: %s", sqlStr))
+
+	_, err = mtba.db.Exec(sqlStr, args...)
+	if err != nil {
+		logger.Errorf("insert data error: %v\n", err)
+		span.SetStatus(codes.Error, "Insert data error")
+		o11y.Error(ctx, fmt.Sprintf("Insert data error: %v ", err))
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// 按id获取补数任务
+func (mtba *metricTaskBackfillAccess) GetMetricTaskBackfill(ctx context.Context, backfillID string) (interfaces.MetricTaskBackfill, bool, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "Select metric task backfill", trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()))
+	defer span.End()
+
+	backfills, err := mtba.listMetricTaskBackfills(ctx, sq.Eq{"f_id": backfillID})
+	if err != nil {
+		span.SetStatus(codes.Error, "Select metric task backfill failed")
+		return interfaces.MetricTaskBackfill{}, false, err
+	}
+	if len(backfills) == 0 {
+		span.SetStatus(codes.Ok, "")
+		return interfaces.MetricTaskBackfill{}, false, nil
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return backfills[0], true, nil
+}
+
+// 按模型id、状态查询补数任务列表，按创建时间倒序
+func (mtba *metricTaskBackfillAccess) ListMetricTaskBackfills(ctx context.Context,
+	params interfaces.MetricTaskBackfillQueryParams) ([]interfaces.MetricTaskBackfill, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "List metric task backfills", trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()))
+	defer span.End()
+
+	conds := sq.And{}
+	if params.ModelID != "" {
+		conds = append(conds, sq.Eq{"f_model_id": params.ModelID})
+	}
+	if params.Status != "" {
+		conds = append(conds, sq.Eq{"f_status": params.Status})
+	}
+
+	backfills, err := mtba.listMetricTaskBackfills(ctx, conds)
+	if err != nil {
+		span.SetStatus(codes.Error, "List metric task backfills failed")
+		return backfills, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return backfills, nil
+}
+
+// 取消未结束的补数任务，由 data-model-job 在执行下一批前感知
+func (mtba *metricTaskBackfillAccess) CancelMetricTaskBackfill(ctx context.Context, backfillID string, updateTime int64) (bool, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, fmt.Sprintf("Cancel metric task backfill[%s]", backfillID), trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()))
+	defer span.End()
+
+	sqlStr, args, err := sq.Update(METRIC_TASK_BACKFILL_TABLE_NAME).
+		Set("f_status", interfaces.BACKFILL_STATUS_CANCELED).
+		Set("f_update_time", updateTime).
+		Where(sq.Eq{"f_id": backfillID}).
+		Where(sq.Eq{"f_status": []string{interfaces.BACKFILL_STATUS_PENDING, interfaces.BACKFILL_STATUS_RUNNING}}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Failed to build the sql of cancel metric task backfill, error: %s", err.Error())
+		o11y.Error(ctx, fmt.Sprintf("Failed to build the sql of cancel metric task backfill, error: %s", err.Error()))
+		span.SetStatus(codes.Error, "Build sql failed ")
+		return false, err
+	}
+
+	// 记录处理的 sql 字符串
+	o11y.Info(ctx, fmt.Sprintf("取消补数任务的 sql 语句: %s", sqlStr))
+
+	ret, err := mtba.db.Exec(sqlStr, args...)
+	if err != nil {
+		logger.Errorf("update metric task backfill error: %v\n", err)
+		span.SetStatus(codes.Error, "Update data error")
+		o11y.Error(ctx, fmt.Sprintf("Update data error: %v ", err))
+		return false, err
+	}
+
+	rowsAffected, err := ret.RowsAffected()
+	if err != nil {
+		logger.Errorf("Get RowsAffected error: %v\n", err)
+		span.SetStatus(codes.Error, "Get RowsAffected error")
+		o11y.Error(ctx, fmt.Sprintf("Get RowsAffected error: %v ", err))
+		return false, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return rowsAffected > 0, nil
+}
+
+func (mtba *metricTaskBackfillAccess) listMetricTaskBackfills(ctx context.Context, cond sq.Sqlizer) ([]interfaces.MetricTaskBackfill, error) {
+	backfills := make([]interfaces.MetricTaskBackfill, 0)
+
+	sqlStr, args, err := sq.Select(
+		"f_id",
+		"f_task_id",
+		"f_model_id",
+		"f_module_type",
+		"f_start",
+		"f_end",
+		"f_steps",
+		"f_status",
+		"f_status_details",
+		"f_checkpoint",
+		"f_total_points",
+		"f_done_points",
+		"f_create_time",
+		"f_update_time",
+		"f_creator",
+		"f_creator_type").
+		From(METRIC_TASK_BACKFILL_TABLE_NAME).
+		Where(cond).
+		OrderBy("f_create_time DESC").
+		ToSql()
+	if err != nil {
+		logger.Errorf("Failed to build the sql of select metric task backfills, error: %s", err.Error())
+		o11y.Error(ctx, fmt.Sprintf("Failed to build the sql of select metric task backfills, error: %s", err.Error()))
+		return backfills, err
+	}
+
+	// 记录处理的 sql 字符串
+	o11y.Info(ctx, fmt.Sprintf("查询补数任务的 sql 语句: %s", sqlStr))
+
+	rows, err := mtba.db.Query(sqlStr, args...)
+	if err != nil {
+		logger.Errorf("list data error: %v\n", err)
+		o11y.Error(ctx, fmt.Sprintf("List data error: %v", err))
+		return backfills, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stepsBytes []byte
+		backfill := interfaces.MetricTaskBackfill{}
+		err := rows.Scan(
+			&backfill.ID,
+			&backfill.TaskID,
+			&backfill.ModelID,
+			&backfill.ModuleType,
+			&backfill.Start,
+			&backfill.End,
+			&stepsBytes,
+			&backfill.Status,
+			&backfill.StatusDetails,
+			&backfill.Checkpoint,
+			&backfill.TotalPoints,
+			&backfill.DonePoints,
+			&backfill.CreateTime,
+			&backfill.UpdateTime,
+			&backfill.Creator.ID,
+			&backfill.Creator.Type,
+		)
+		if err != nil {
+			logger.Errorf("row scan failed, err: %v \n", err)
+			o11y.Error(ctx, fmt.Sprintf("Row scan error: %v", err))
+			return backfills, err
+		}
+
+		err = sonic.Unmarshal(stepsBytes, &backfill.Steps)
+		if err != nil {
+			logger.Errorf("Failed to unmarshal steps of metric task backfill, err: %v", err.Error())
+			return backfills, err
+		}
+
+		if backfill.TotalPoints > 0 {
+			backfill.Progress = float64(backfill.DonePoints) / float64(backfill.TotalPoints)
+		}
+
+		backfills = append(backfills, backfill)
+	}
+
+	return backfills, nil
+}
@@ -0,0 +1,211 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package metric_model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	"data-model/interfaces"
+)
+
+var (
+	testBackfillColumns = []string{"f_id", "f_task_id", "f_model_id", "f_module_type", "f_start", "f_end",
+		"f_steps", "f_status", "f_status_details", "f_checkpoint", "f_total_points", "f_done_points",
+		"f_create_time", "f_update_time", "f_creator", "f_creator_type"}
+
+	testBackfillSelectStr = fmt.Sprintf("SELECT f_id, f_task_id, f_model_id, f_module_type, f_start, f_end, "+
+		"f_steps, f_status, f_status_details, f_checkpoint, f_total_points, f_done_points, f_create_time, "+
+		"f_update_time, f_creator, f_creator_type FROM %s", METRIC_TASK_BACKFILL_TABLE_NAME)
+
+	testBackfill = interfaces.MetricTaskBackfill{
+		ID:          "b1",
+		TaskID:      "1",
+		ModelID:     "1",
+		ModuleType:  interfaces.MODULE_TYPE_METRIC_MODEL,
+		Start:       1699300000000,
+		End:         1699336800000,
+		Steps:       []string{"5m"},
+		Status:      interfaces.BACKFILL_STATUS_PENDING,
+		Checkpoint:  1699299900000,
+		TotalPoints: 124,
+		CreateTime:  testUpdateTime,
+		UpdateTime:  testUpdateTime,
+		Creator:     interfaces.AccountInfo{ID: "u1", Type: "user"},
+	}
+)
+
+func MockNewMetricTaskBackfillAccess(appSetting *common.AppSetting) (*metricTaskBackfillAccess, sqlmock.Sqlmock) {
+	db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	mtba := &metricTaskBackfillAccess{
+		appSetting: appSetting,
+		db:         db,
+	}
+	return mtba, smock
+}
+
+func Test_MetricTaskBackfillAccess_CreateMetricTaskBackfill(t *testing.T) {
+	Convey("Test CreateMetricTaskBackfill", t, func() {
+		mtba, smock := MockNewMetricTaskBackfillAccess(&common.AppSetting{})
+
+		sqlStr := fmt.Sprintf("INSERT INTO %s (f_id,f_task_id,f_model_id,f_module_type,f_start,f_end,"+
+			"f_steps,f_status,f_status_details,f_checkpoint,f_total_points,f_done_points,f_create_time,"+
+			"f_update_time,f_creator,f_creator_type) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)",
+			METRIC_TASK_BACKFILL_TABLE_NAME)
+
+		Convey("Create failed, caused by exec error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(sqlStr).WithArgs().WillReturnError(expectedErr)
+
+			err := mtba.CreateMetricTaskBackfill(testCtx, testBackfill)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Create succeed", func() {
+			smock.ExpectExec(sqlStr).WithArgs().WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := mtba.CreateMetricTaskBackfill(testCtx, testBackfill)
+			So(err, ShouldBeNil)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_MetricTaskBackfillAccess_GetMetricTaskBackfill(t *testing.T) {
+	Convey("Test GetMetricTaskBackfill", t, func() {
+		mtba, smock := MockNewMetricTaskBackfillAccess(&common.AppSetting{})
+
+		sqlStr := testBackfillSelectStr + " WHERE f_id = ? ORDER BY f_create_time DESC"
+
+		Convey("Get failed, caused by query error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(sqlStr).WithArgs("b1").WillReturnError(expectedErr)
+
+			_, exist, err := mtba.GetMetricTaskBackfill(testCtx, "b1")
+			So(err, ShouldResemble, expectedErr)
+			So(exist, ShouldBeFalse)
+		})
+
+		Convey("Get failed, caused by unmarshal steps error", func() {
+			rows := sqlmock.NewRows(testBackfillColumns).AddRow("b1", "1", "1", interfaces.MODULE_TYPE_METRIC_MODEL,
+				1699300000000, 1699336800000, "[5m", interfaces.BACKFILL_STATUS_PENDING, "", 1699299900000,
+				124, 0, testUpdateTime, testUpdateTime, "u1", "user")
+			smock.ExpectQuery(sqlStr).WithArgs("b1").WillReturnRows(rows)
+
+			_, _, err := mtba.GetMetricTaskBackfill(testCtx, "b1")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Get succeed, backfill not found", func() {
+			smock.ExpectQuery(sqlStr).WithArgs("b1").WillReturnRows(sqlmock.NewRows(testBackfillColumns))
+
+			_, exist, err := mtba.GetMetricTaskBackfill(testCtx, "b1")
+			So(err, ShouldBeNil)
+			So(exist, ShouldBeFalse)
+		})
+
+		Convey("Get succeed", func() {
+			rows := sqlmock.NewRows(testBackfillColumns).AddRow("b1", "1", "1", interfaces.MODULE_TYPE_METRIC_MODEL,
+				1699300000000, 1699336800000, `["5m"]`, interfaces.BACKFILL_STATUS_RUNNING, "", 1699303200000,
+				124, 31, testUpdateTime, testUpdateTime, "u1", "user")
+			smock.ExpectQuery(sqlStr).WithArgs("b1").WillReturnRows(rows)
+
+			backfill, exist, err := mtba.GetMetricTaskBackfill(testCtx, "b1")
+			So(err, ShouldBeNil)
+			So(exist, ShouldBeTrue)
+			So(backfill.Steps, ShouldResemble, []string{"5m"})
+			So(backfill.DonePoints, ShouldEqual, 31)
+			So(backfill.Progress, ShouldEqual, 0.25)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_MetricTaskBackfillAccess_ListMetricTaskBackfills(t *testing.T) {
+	Convey("Test ListMetricTaskBackfills", t, func() {
+		mtba, smock := MockNewMetricTaskBackfillAccess(&common.AppSetting{})
+
+		Convey("List failed, caused by query error", func() {
+			sqlStr := testBackfillSelectStr + " WHERE (1=1) ORDER BY f_create_time DESC"
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(sqlStr).WillReturnError(expectedErr)
+
+			_, err := mtba.ListMetricTaskBackfills(testCtx, interfaces.MetricTaskBackfillQueryParams{})
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("List succeed, filter by model and status", func() {
+			sqlStr := testBackfillSelectStr + " WHERE (f_model_id = ? AND f_status = ?) ORDER BY f_create_time DESC"
+			rows := sqlmock.NewRows(testBackfillColumns).AddRow("b1", "1", "1", interfaces.MODULE_TYPE_METRIC_MODEL,
+				1699300000000, 1699336800000, `["5m"]`, interfaces.BACKFILL_STATUS_PENDING, "", 1699299900000,
+				124, 0, testUpdateTime, testUpdateTime, "u1", "user")
+			smock.ExpectQuery(sqlStr).WithArgs("1", interfaces.BACKFILL_STATUS_PENDING).WillReturnRows(rows)
+
+			backfills, err := mtba.ListMetricTaskBackfills(testCtx, interfaces.MetricTaskBackfillQueryParams{
+				ModelID: "1",
+				Status:  interfaces.BACKFILL_STATUS_PENDING,
+			})
+			So(err, ShouldBeNil)
+			So(backfills, ShouldResemble, []interfaces.MetricTaskBackfill{testBackfill})
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_MetricTaskBackfillAccess_CancelMetricTaskBackfill(t *testing.T) {
+	Convey("Test CancelMetricTaskBackfill", t, func() {
+		mtba, smock := MockNewMetricTaskBackfillAccess(&common.AppSetting{})
+
+		sqlStr := fmt.Sprintf("UPDATE %s SET f_status = ?, f_update_time = ? WHERE f_id = ? AND f_status IN (?,?)",
+			METRIC_TASK_BACKFILL_TABLE_NAME)
+		args := []driver.Value{interfaces.BACKFILL_STATUS_CANCELED, testUpdateTime, "b1",
+			interfaces.BACKFILL_STATUS_PENDING, interfaces.BACKFILL_STATUS_RUNNING}
+
+		Convey("Cancel failed, caused by exec error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(sqlStr).WithArgs(args...).WillReturnError(expectedErr)
+
+			canceled, err := mtba.CancelMetricTaskBackfill(testCtx, "b1", testUpdateTime)
+			So(err, ShouldResemble, expectedErr)
+			So(canceled, ShouldBeFalse)
+		})
+
+		Convey("Cancel nothing, backfill already finished", func() {
+			smock.ExpectExec(sqlStr).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			canceled, err := mtba.CancelMetricTaskBackfill(testCtx, "b1", testUpdateTime)
+			So(err, ShouldBeNil)
+			So(canceled, ShouldBeFalse)
+		})
+
+		Convey("Cancel succeed", func() {
+			smock.ExpectExec(sqlStr).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			canceled, err := mtba.CancelMetricTaskBackfill(testCtx, "b1", testUpdateTime)
+			So(err, ShouldBeNil)
+			So(canceled, ShouldBeTrue)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
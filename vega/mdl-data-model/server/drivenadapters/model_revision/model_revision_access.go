@@ -0,0 +1,402 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package model_revision
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	libdb "github.com/kweaver-ai/kweaver-go-lib/db"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"data-model/common"
+	"data-model/interfaces"
+)
+
+const (
+	MODEL_REVISION_TABLE_NAME = "t_model_revision"
+)
+
+var (
+	mrAccessOnce sync.Once
+	mrAccess     interfaces.ModelRevisionAccess
+
+	// 列表不查询模型定义快照
+	modelRevisionColumns = []string{
+		"f_model_type",
+		"f_model_id",
+		"f_revision",
+		"f_model_name",
+		"f_comment",
+		"f_creator",
+		"f_creator_type",
+		"f_create_time",
+	}
+)
+
+type modelRevisionAccess struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+}
+
+func NewModelRevisionAccess(appSetting *common.AppSetting) interfaces.ModelRevisionAccess {
+	mrAccessOnce.Do(func() {
+		mrAccess = &modelRevisionAccess{
+			appSetting: appSetting,
+			db:         libdb.NewDB(&appSetting.DBSetting),
+		}
+	})
+
+	return mrAccess
+}
+
+// 写入版本, 版本号为该模型当前最大版本号加一. 并发保存同一模型时由主键冲突使事务失败
+func (mra *modelRevisionAccess) CreateModelRevision(ctx context.Context, tx *sql.Tx, revision *interfaces.ModelRevision) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Create model revision", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("model_type").String(revision.ModelType),
+		attr.Key("model_id").String(revision.ModelID),
+	)
+
+	sqlStr, args, err := sq.Select("COALESCE(MAX(f_revision), 0)").
+		From(MODEL_REVISION_TABLE_NAME).
+		Where(sq.Eq{
+			"f_model_type": revision.ModelType,
+			"f_model_id":   revision.ModelID,
+		}).
+		ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'get max model revision' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for getting max model revision is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	var maxRevision int
+	if tx == nil {
+		err = mra.db.QueryRow(sqlStr, args...).Scan(&maxRevision)
+	} else {
+		err = tx.QueryRow(sqlStr, args...).Scan(&maxRevision)
+	}
+	if err != nil {
+		errDetails := fmt.Sprintf("Get max model revision failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Get max model revision failed")
+
+		return err
+	}
+	revision.Revision = maxRevision + 1
+
+	sqlStr, args, err = sq.Insert(MODEL_REVISION_TABLE_NAME).
+		Columns(append(modelRevisionColumns, "f_content")...).
+		Values(
+			revision.ModelType,
+			revision.ModelID,
+			revision.Revision,
+			revision.ModelName,
+			revision.Comment,
+			revision.Creator.ID,
+			revision.Creator.Type,
+			revision.CreateTime,
+			revision.Content,
+		).
+		ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'insert model revision' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return err
+	}
+
+	sqlStmt = fmt.Sprintf("Sql stmt for inserting model revision is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	if tx == nil {
+		_, err = mra.db.Exec(sqlStr, args...)
+	} else {
+		_, err = tx.Exec(sqlStr, args...)
+	}
+	if err != nil {
+		errDetails := fmt.Sprintf("Insert model revision failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Insert model revision failed")
+
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// 分页查询模型的版本列表
+func (mra *modelRevisionAccess) ListModelRevisions(ctx context.Context,
+	query interfaces.ModelRevisionsQueryParams) ([]*interfaces.ModelRevision, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: List model revisions", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("model_type").String(query.ModelType),
+		attr.Key("model_id").String(query.ModelID),
+		attr.Key("sort").String(query.Sort),
+		attr.Key("direction").String(query.Direction),
+	)
+
+	builder := sq.Select(modelRevisionColumns...).
+		From(MODEL_REVISION_TABLE_NAME).
+		Where(sq.Eq{
+			"f_model_type": query.ModelType,
+			"f_model_id":   query.ModelID,
+		})
+	if query.Sort != "" {
+		builder = builder.OrderBy(fmt.Sprintf("%s %s", query.Sort, query.Direction))
+	}
+	if query.Limit != -1 {
+		builder = builder.Offset(uint64(query.Offset)).Limit(uint64(query.Limit))
+	}
+
+	sqlStr, args, err := builder.ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'list model revisions' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return nil, err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for listing model revisions is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	rows, err := mra.db.Query(sqlStr, args...)
+	if err != nil {
+		errDetails := fmt.Sprintf("List model revisions failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "List model revisions failed")
+
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]*interfaces.ModelRevision, 0)
+	for rows.Next() {
+		revision := &interfaces.ModelRevision{}
+		err = rows.Scan(
+			&revision.ModelType,
+			&revision.ModelID,
+			&revision.Revision,
+			&revision.ModelName,
+			&revision.Comment,
+			&revision.Creator.ID,
+			&revision.Creator.Type,
+			&revision.CreateTime,
+		)
+		if err != nil {
+			errDetails := fmt.Sprintf("Row scan failed, %s", err.Error())
+			logger.Error(errDetails)
+			o11y.Error(ctx, errDetails)
+			span.SetStatus(codes.Error, "Row scan failed")
+
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return revisions, nil
+}
+
+// 查询模型的版本总数
+func (mra *modelRevisionAccess) GetModelRevisionsTotal(ctx context.Context, query interfaces.ModelRevisionsQueryParams) (int, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Get model revisions total", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("model_type").String(query.ModelType),
+		attr.Key("model_id").String(query.ModelID),
+	)
+
+	sqlStr, args, err := sq.Select("COUNT(f_revision)").
+		From(MODEL_REVISION_TABLE_NAME).
+		Where(sq.Eq{
+			"f_model_type": query.ModelType,
+			"f_model_id":   query.ModelID,
+		}).
+		ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'get model revisions total' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return 0, err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for getting model revisions total is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	total := 0
+	err = mra.db.QueryRow(sqlStr, args...).Scan(&total)
+	if err != nil {
+		errDetails := fmt.Sprintf("Get model revisions total failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Get model revisions total failed")
+
+		return 0, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return total, nil
+}
+
+// 获取指定版本, 包含模型定义快照
+func (mra *modelRevisionAccess) GetModelRevision(ctx context.Context, modelType string, modelID string,
+	revision int) (*interfaces.ModelRevision, bool, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Get model revision", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("model_type").String(modelType),
+		attr.Key("model_id").String(modelID),
+		attr.Key("revision").Int(revision),
+	)
+
+	sqlStr, args, err := sq.Select(append(modelRevisionColumns, "f_content")...).
+		From(MODEL_REVISION_TABLE_NAME).
+		Where(sq.Eq{
+			"f_model_type": modelType,
+			"f_model_id":   modelID,
+			"f_revision":   revision,
+		}).
+		ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'get model revision' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return nil, false, err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for getting model revision is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	modelRevision := &interfaces.ModelRevision{}
+	err = mra.db.QueryRow(sqlStr, args...).Scan(
+		&modelRevision.ModelType,
+		&modelRevision.ModelID,
+		&modelRevision.Revision,
+		&modelRevision.ModelName,
+		&modelRevision.Comment,
+		&modelRevision.Creator.ID,
+		&modelRevision.Creator.Type,
+		&modelRevision.CreateTime,
+		&modelRevision.Content,
+	)
+	if err == sql.ErrNoRows {
+		span.SetAttributes(attr.Key("no_rows").Bool(true))
+		span.SetStatus(codes.Ok, "")
+
+		return nil, false, nil
+	}
+	if err != nil {
+		errDetails := fmt.Sprintf("Row scan failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Row scan failed")
+
+		return nil, false, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return modelRevision, true, nil
+}
+
+// 删除模型的所有版本, 随模型删除一起在事务内执行
+func (mra *modelRevisionAccess) DeleteModelRevisions(ctx context.Context, tx *sql.Tx, modelType string, modelIDs []string) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Delete model revisions", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("model_type").String(modelType),
+		attr.Key("model_ids").String(strings.Join(modelIDs, ",")),
+	)
+
+	sqlStr, args, err := sq.Delete(MODEL_REVISION_TABLE_NAME).
+		Where(sq.Eq{
+			"f_model_type": modelType,
+			"f_model_id":   modelIDs,
+		}).
+		ToSql()
+	if err != nil {
+		errDetails := fmt.Sprintf("Generate 'delete model revisions' sql stmt failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Generate sql stmt failed")
+
+		return err
+	}
+
+	sqlStmt := fmt.Sprintf("Sql stmt for deleting model revisions is '%s'", sqlStr)
+	logger.Debug(sqlStmt)
+	o11y.Info(ctx, sqlStmt)
+
+	if tx == nil {
+		_, err = mra.db.Exec(sqlStr, args...)
+	} else {
+		_, err = tx.Exec(sqlStr, args...)
+	}
+	if err != nil {
+		errDetails := fmt.Sprintf("Delete model revisions failed, %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		span.SetStatus(codes.Error, "Delete model revisions failed")
+
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
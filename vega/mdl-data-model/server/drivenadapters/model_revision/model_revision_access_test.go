@@ -0,0 +1,246 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package model_revision
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	"data-model/interfaces"
+)
+
+var (
+	testCtx = context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+)
+
+func MockNewModelRevisionAccess(appSetting *common.AppSetting) (*modelRevisionAccess, sqlmock.Sqlmock) {
+	db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	mra := &modelRevisionAccess{
+		appSetting: appSetting,
+		db:         db,
+	}
+	return mra, smock
+}
+
+func Test_ModelRevisionAccess_CreateModelRevision(t *testing.T) {
+	Convey("Test CreateModelRevision", t, func() {
+		appSetting := &common.AppSetting{}
+		mra, smock := MockNewModelRevisionAccess(appSetting)
+
+		maxSqlStr := fmt.Sprintf("SELECT COALESCE(MAX(f_revision), 0) FROM %s WHERE f_model_id = ? AND f_model_type = ?",
+			MODEL_REVISION_TABLE_NAME)
+		insertSqlStr := fmt.Sprintf("INSERT INTO %s (%s,f_content) VALUES (?,?,?,?,?,?,?,?,?)",
+			MODEL_REVISION_TABLE_NAME, strings.Join(modelRevisionColumns, ","))
+
+		revision := &interfaces.ModelRevision{
+			ModelType:  interfaces.REVISION_MODEL_TYPE_METRIC_MODEL,
+			ModelID:    "m1",
+			ModelName:  "cpu",
+			Comment:    "init",
+			Creator:    interfaces.AccountInfo{ID: "u1", Type: "user"},
+			CreateTime: 1000,
+			Content:    `{"id":"m1"}`,
+		}
+
+		Convey("Create failed, caused by get max revision error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectBegin()
+			smock.ExpectQuery(maxSqlStr).WithArgs("m1", "metric_model").WillReturnError(expectedErr)
+
+			tx, _ := mra.db.Begin()
+			err := mra.CreateModelRevision(testCtx, tx, revision)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Create failed, caused by insert error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectBegin()
+			smock.ExpectQuery(maxSqlStr).WithArgs("m1", "metric_model").
+				WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(2))
+			smock.ExpectExec(insertSqlStr).WillReturnError(expectedErr)
+
+			tx, _ := mra.db.Begin()
+			err := mra.CreateModelRevision(testCtx, tx, revision)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Create succeed", func() {
+			smock.ExpectBegin()
+			smock.ExpectQuery(maxSqlStr).WithArgs("m1", "metric_model").
+				WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(2))
+			smock.ExpectExec(insertSqlStr).
+				WithArgs("metric_model", "m1", 3, "cpu", "init", "u1", "user", int64(1000), `{"id":"m1"}`).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			tx, _ := mra.db.Begin()
+			err := mra.CreateModelRevision(testCtx, tx, revision)
+			So(err, ShouldBeNil)
+			So(revision.Revision, ShouldEqual, 3)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_ModelRevisionAccess_ListModelRevisions(t *testing.T) {
+	Convey("Test ListModelRevisions", t, func() {
+		appSetting := &common.AppSetting{}
+		mra, smock := MockNewModelRevisionAccess(appSetting)
+
+		query := interfaces.ModelRevisionsQueryParams{
+			PaginationQueryParameters: interfaces.PaginationQueryParameters{
+				Offset:    0,
+				Limit:     10,
+				Sort:      "f_revision",
+				Direction: interfaces.DESC_DIRECTION,
+			},
+			ModelType: interfaces.REVISION_MODEL_TYPE_DATA_VIEW,
+			ModelID:   "v1",
+		}
+		sqlStr := fmt.Sprintf("SELECT %s FROM %s WHERE f_model_id = ? AND f_model_type = ? "+
+			"ORDER BY f_revision desc LIMIT 10 OFFSET 0", strings.Join(modelRevisionColumns, ", "), MODEL_REVISION_TABLE_NAME)
+
+		Convey("List failed, caused by query error", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(sqlStr).WithArgs("v1", "data_view").WillReturnError(expectedErr)
+
+			_, err := mra.ListModelRevisions(testCtx, query)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("List succeed", func() {
+			rows := sqlmock.NewRows(modelRevisionColumns).
+				AddRow("data_view", "v1", 2, "view", "", "u1", "user", 2000).
+				AddRow("data_view", "v1", 1, "view", "init", "u1", "user", 1000)
+			smock.ExpectQuery(sqlStr).WithArgs("v1", "data_view").WillReturnRows(rows)
+
+			revisions, err := mra.ListModelRevisions(testCtx, query)
+			So(err, ShouldBeNil)
+			So(len(revisions), ShouldEqual, 2)
+			So(revisions[0].Revision, ShouldEqual, 2)
+			So(revisions[1].Creator, ShouldResemble, interfaces.AccountInfo{ID: "u1", Type: "user"})
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_ModelRevisionAccess_GetModelRevisionsTotal(t *testing.T) {
+	Convey("Test GetModelRevisionsTotal", t, func() {
+		appSetting := &common.AppSetting{}
+		mra, smock := MockNewModelRevisionAccess(appSetting)
+
+		query := interfaces.ModelRevisionsQueryParams{
+			ModelType: interfaces.REVISION_MODEL_TYPE_DATA_VIEW,
+			ModelID:   "v1",
+		}
+		sqlStr := fmt.Sprintf("SELECT COUNT(f_revision) FROM %s WHERE f_model_id = ? AND f_model_type = ?",
+			MODEL_REVISION_TABLE_NAME)
+
+		Convey("Get total failed", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(sqlStr).WithArgs("v1", "data_view").WillReturnError(expectedErr)
+
+			_, err := mra.GetModelRevisionsTotal(testCtx, query)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Get total succeed", func() {
+			smock.ExpectQuery(sqlStr).WithArgs("v1", "data_view").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+			total, err := mra.GetModelRevisionsTotal(testCtx, query)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 5)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_ModelRevisionAccess_GetModelRevision(t *testing.T) {
+	Convey("Test GetModelRevision", t, func() {
+		appSetting := &common.AppSetting{}
+		mra, smock := MockNewModelRevisionAccess(appSetting)
+
+		columns := append(modelRevisionColumns, "f_content")
+		sqlStr := fmt.Sprintf("SELECT %s FROM %s WHERE f_model_id = ? AND f_model_type = ? AND f_revision = ?",
+			strings.Join(columns, ", "), MODEL_REVISION_TABLE_NAME)
+
+		Convey("Get failed", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectQuery(sqlStr).WithArgs("o1", "objective_model", 1).WillReturnError(expectedErr)
+
+			_, _, err := mra.GetModelRevision(testCtx, interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL, "o1", 1)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Get succeed without rows", func() {
+			smock.ExpectQuery(sqlStr).WithArgs("o1", "objective_model", 1).WillReturnRows(sqlmock.NewRows(columns))
+
+			_, exist, err := mra.GetModelRevision(testCtx, interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL, "o1", 1)
+			So(err, ShouldBeNil)
+			So(exist, ShouldBeFalse)
+		})
+
+		Convey("Get succeed", func() {
+			rows := sqlmock.NewRows(columns).
+				AddRow("objective_model", "o1", 1, "slo", "init", "u1", "user", 1000, `{"id":"o1"}`)
+			smock.ExpectQuery(sqlStr).WithArgs("o1", "objective_model", 1).WillReturnRows(rows)
+
+			revision, exist, err := mra.GetModelRevision(testCtx, interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL, "o1", 1)
+			So(err, ShouldBeNil)
+			So(exist, ShouldBeTrue)
+			So(revision.Content, ShouldEqual, `{"id":"o1"}`)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_ModelRevisionAccess_DeleteModelRevisions(t *testing.T) {
+	Convey("Test DeleteModelRevisions", t, func() {
+		appSetting := &common.AppSetting{}
+		mra, smock := MockNewModelRevisionAccess(appSetting)
+
+		sqlStr := fmt.Sprintf("DELETE FROM %s WHERE f_model_id IN (?,?) AND f_model_type = ?", MODEL_REVISION_TABLE_NAME)
+
+		Convey("Delete failed", func() {
+			expectedErr := errors.New("some error")
+			smock.ExpectExec(sqlStr).WithArgs("m1", "m2", "metric_model").WillReturnError(expectedErr)
+
+			err := mra.DeleteModelRevisions(testCtx, nil, interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, []string{"m1", "m2"})
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Delete succeed", func() {
+			smock.ExpectExec(sqlStr).WithArgs("m1", "m2", "metric_model").WillReturnResult(sqlmock.NewResult(0, 4))
+
+			err := mra.DeleteModelRevisions(testCtx, nil, interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, []string{"m1", "m2"})
+			So(err, ShouldBeNil)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
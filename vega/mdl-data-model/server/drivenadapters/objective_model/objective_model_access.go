@@ -28,6 +28,7 @@ import (
 
 const (
 	OBJECTIVE_MODEL_TABLE_NAME = "t_objective_model"
+	OBJECTIVE_ALERT_TABLE_NAME = "t_objective_alert"
 )
 
 var (
@@ -440,6 +441,91 @@ func (oma *objectiveModelAccess) GetObjectiveModelsByModelIDs(ctx context.Contex
 	return objectiveModels, nil
 }
 
+// 获取目标模型的燃烧率告警状态，告警状态由 data-model-job 写入
+func (oma *objectiveModelAccess) ListObjectiveAlerts(ctx context.Context, modelID string) ([]interfaces.ObjectiveAlert, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "Select objective alerts", trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("model_id").String(modelID))
+	defer span.End()
+
+	alerts := make([]interfaces.ObjectiveAlert, 0)
+	sqlStr, vals, err := sq.Select(
+		"f_model_id",
+		"f_policy_name",
+		"f_series_id",
+		"f_labels",
+		"f_status",
+		"f_level",
+		"f_threshold",
+		"f_long_burn_rate",
+		"f_short_burn_rate",
+		"f_active_since",
+		"f_last_eval_time",
+		"f_last_event_id",
+		"f_last_event_time").
+		From(OBJECTIVE_ALERT_TABLE_NAME).
+		Where(sq.Eq{"f_model_id": modelID}).
+		OrderBy("f_policy_name", "f_series_id").
+		ToSql()
+	if err != nil {
+		logger.Errorf("Failed to build the sql of select objective alerts, error: %s", err.Error())
+
+		o11y.Error(ctx, fmt.Sprintf("Failed to build the sql of select objective alerts, error: %s", err.Error()))
+		span.SetStatus(codes.Error, "Build sql failed ")
+
+		return alerts, err
+	}
+
+	// 记录处理的 sql 字符串
+	o11y.Info(ctx, fmt.Sprintf("查询目标模型告警状态的 sql 语句: %s.", sqlStr))
+	rows, err := oma.db.Query(sqlStr, vals...)
+	if err != nil {
+		logger.Errorf("list data error: %v\n", err)
+		span.SetStatus(codes.Error, "List data error")
+		o11y.Error(ctx, fmt.Sprintf("List data error: %v", err))
+
+		return alerts, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		alert := interfaces.ObjectiveAlert{}
+		var labelsBytes []byte
+		err := rows.Scan(
+			&alert.ModelID,
+			&alert.PolicyName,
+			&alert.SeriesID,
+			&labelsBytes,
+			&alert.Status,
+			&alert.Level,
+			&alert.Threshold,
+			&alert.LongBurnRate,
+			&alert.ShortBurnRate,
+			&alert.ActiveSince,
+			&alert.LastEvalTime,
+			&alert.LastEventID,
+			&alert.LastEventTime,
+		)
+		if err != nil {
+			logger.Errorf("row scan failed, err: %v \n", err)
+			span.SetStatus(codes.Error, "Row scan error")
+			o11y.Error(ctx, fmt.Sprintf("Row scan error: %v", err))
+			return alerts, err
+		}
+
+		err = sonic.Unmarshal(labelsBytes, &alert.Labels)
+		if err != nil {
+			logger.Errorf("Failed to unmarshal labels after getting objective alert, err: %v", err.Error())
+			return alerts, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return alerts, nil
+}
+
 func (oma *objectiveModelAccess) UpdateObjectiveModel(ctx context.Context, tx *sql.Tx, objectiveModel interfaces.ObjectiveModel) error {
 	ctx, span := ar_trace.Tracer.Start(ctx, fmt.Sprintf("Update objective model[%s]", objectiveModel.ModelID), trace.WithSpanKind(trace.SpanKindClient))
 	span.SetAttributes(
@@ -554,6 +640,46 @@ func (oma *objectiveModelAccess) DeleteObjectiveModels(ctx context.Context, tx *
 	return RowsAffected, nil
 }
 
+// 删除目标模型的燃烧率告警状态，与模型在同一事务中删除
+func (oma *objectiveModelAccess) DeleteObjectiveAlerts(ctx context.Context, tx *sql.Tx, modelIDs []string) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "Delete objective alerts from db", trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("model_ids").String(fmt.Sprintf("%v", modelIDs)))
+	defer span.End()
+
+	if len(modelIDs) == 0 {
+		return nil
+	}
+
+	sqlStr, vals, err := sq.Delete(OBJECTIVE_ALERT_TABLE_NAME).
+		Where(sq.Eq{"f_model_id": modelIDs}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Failed to build the sql of delete objective alerts by model_id, error: %s", err.Error())
+
+		o11y.Error(ctx, fmt.Sprintf("Failed to build the sql of delete objective alerts by model_id, error: %s", err.Error()))
+		span.SetStatus(codes.Error, "Build sql failed ")
+		return err
+	}
+
+	// 记录处理的 sql 字符串
+	o11y.Info(ctx, fmt.Sprintf("删除目标模型告警状态的 sql 语句: %s; 删除的模型ids: %v", sqlStr, modelIDs))
+
+	_, err = tx.Exec(sqlStr, vals...)
+	if err != nil {
+		logger.Errorf("delete data error: %v\n", err)
+		span.SetStatus(codes.Error, "Delete data error")
+		o11y.Error(ctx, fmt.Sprintf("Delete data error: %v ", err))
+
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
 // 拼接 sql 过滤条件
 func processQueryCondition(modelsQuery interfaces.ObjectiveModelsQueryParams, subBuilder sq.SelectBuilder) sq.SelectBuilder {
 	if modelsQuery.Name != "" {
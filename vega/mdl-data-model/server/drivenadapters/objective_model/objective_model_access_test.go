@@ -579,3 +579,101 @@ func Test_ObjectiveModelAccess_DeleteObjectiveModels(t *testing.T) {
 		})
 	})
 }
+
+func Test_ObjectiveModelAccess_DeleteObjectiveAlerts(t *testing.T) {
+	Convey("Test DeleteObjectiveAlerts", t, func() {
+		appSetting := &common.AppSetting{}
+		oma, smock := MockNewObjectiveModelAccess(appSetting)
+
+		sqlStr := fmt.Sprintf("DELETE FROM %s WHERE f_model_id IN (?,?)", OBJECTIVE_ALERT_TABLE_NAME)
+
+		modelIDs := []string{"test-id-1", "test-id-2"}
+
+		Convey("When exec fails", func() {
+			smock.ExpectBegin()
+			expectedErr := errors.New("exec error")
+			smock.ExpectExec(sqlStr).WithArgs("test-id-1", "test-id-2").WillReturnError(expectedErr)
+
+			tx, _ := oma.db.Begin()
+			err := oma.DeleteObjectiveAlerts(testCtx, tx, modelIDs)
+			So(err, ShouldResemble, expectedErr)
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+
+		Convey("When delete succeeds", func() {
+			smock.ExpectBegin()
+			smock.ExpectExec(sqlStr).WithArgs("test-id-1", "test-id-2").WillReturnResult(sqlmock.NewResult(0, 3))
+
+			tx, _ := oma.db.Begin()
+			err := oma.DeleteObjectiveAlerts(testCtx, tx, modelIDs)
+			So(err, ShouldBeNil)
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	})
+}
+
+func Test_ObjectiveModelAccess_ListObjectiveAlerts(t *testing.T) {
+	Convey("Test ListObjectiveAlerts", t, func() {
+		appSetting := &common.AppSetting{}
+		oma, smock := MockNewObjectiveModelAccess(appSetting)
+
+		sqlStr := fmt.Sprintf("SELECT f_model_id, f_policy_name, f_series_id, f_labels, f_status, f_level, "+
+			"f_threshold, f_long_burn_rate, f_short_burn_rate, f_active_since, f_last_eval_time, f_last_event_id, "+
+			"f_last_event_time FROM %s WHERE f_model_id = ? ORDER BY f_policy_name, f_series_id", OBJECTIVE_ALERT_TABLE_NAME)
+		columns := []string{"f_model_id", "f_policy_name", "f_series_id", "f_labels", "f_status", "f_level",
+			"f_threshold", "f_long_burn_rate", "f_short_burn_rate", "f_active_since", "f_last_eval_time",
+			"f_last_event_id", "f_last_event_time"}
+
+		Convey("List succeed", func() {
+			rows := sqlmock.NewRows(columns).
+				AddRow("m1", "page", "s1", `{"app":"a"}`, interfaces.OBJECTIVE_ALERT_STATUS_FIRING, 1,
+					14.4, 20.5, 30.1, 1000, 2000, "e1", 1000)
+			smock.ExpectQuery(sqlStr).WithArgs("m1").WillReturnRows(rows)
+
+			alerts, err := oma.ListObjectiveAlerts(testCtx, "m1")
+			So(err, ShouldBeNil)
+			So(alerts, ShouldResemble, []interfaces.ObjectiveAlert{{
+				ModelID:       "m1",
+				PolicyName:    "page",
+				SeriesID:      "s1",
+				Labels:        map[string]string{"app": "a"},
+				Status:        interfaces.OBJECTIVE_ALERT_STATUS_FIRING,
+				Level:         1,
+				Threshold:     14.4,
+				LongBurnRate:  20.5,
+				ShortBurnRate: 30.1,
+				ActiveSince:   1000,
+				LastEvalTime:  2000,
+				LastEventID:   "e1",
+				LastEventTime: 1000,
+			}})
+
+			if err := smock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+
+		Convey("Query failed", func() {
+			smock.ExpectQuery(sqlStr).WithArgs("m1").WillReturnError(sqlmock.ErrCancelled)
+
+			_, err := oma.ListObjectiveAlerts(testCtx, "m1")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Unmarshal labels failed", func() {
+			rows := sqlmock.NewRows(columns).
+				AddRow("m1", "page", "s1", `{`, interfaces.OBJECTIVE_ALERT_STATUS_FIRING, 1,
+					14.4, 20.5, 30.1, 1000, 2000, "e1", 1000)
+			smock.ExpectQuery(sqlStr).WithArgs("m1").WillReturnRows(rows)
+
+			_, err := oma.ListObjectiveAlerts(testCtx, "m1")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -769,6 +769,16 @@ func (tma *traceModelAccess) processAfterGet(ctx context.Context, model interfac
 				return model, err
 			}
 			model.RelatedLogConfig = relatedLogConf
+		} else {
+			relatedLogConf := interfaces.RelatedLogConfigWithDataConnection{}
+			err := sonic.Unmarshal(model.RelatedLogConfigBytes, &relatedLogConf)
+			if err != nil {
+				errDetails := fmt.Sprintf("Failed to unmarshal relatedLogConfigBytes after getting trace models, err: %v", err.Error())
+				logger.Error(errDetails)
+				o11y.Error(ctx, errDetails)
+				return model, err
+			}
+			model.RelatedLogConfig = relatedLogConf
 		}
 	} else {
 		model.RelatedLogConfig = interfaces.RelatedLogConfigWithDataView{}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 
 	"github.com/bytedance/sonic"
@@ -123,6 +124,63 @@ func (ua *uniqueryAccess) CheckFormulaByUniquery(ctx context.Context, query inte
 	return true, "", nil
 }
 
+// 删除指标模型的查询结果缓存
+func (ua *uniqueryAccess) DeleteMetricModelCache(ctx context.Context, modelIDs []string) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: delete metric model result cache", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	modelIDsStr := strings.Join(modelIDs, ",")
+	urlStr := fmt.Sprintf("%s/metric-models/%s/cache", ua.uniqueryUrl, modelIDsStr)
+
+	o11y.AddAttrs4InternalHttp(span, o11y.TraceAttrs{
+		HttpUrl:         urlStr,
+		HttpMethod:      http.MethodDelete,
+		HttpContentType: rest.ContentTypeJson,
+	})
+
+	accountInfo := interfaces.AccountInfo{}
+	if ctx.Value(interfaces.ACCOUNT_INFO_KEY) != nil {
+		accountInfo = ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo)
+	}
+
+	headers := map[string]string{
+		interfaces.CONTENT_TYPE_NAME:        interfaces.CONTENT_TYPE_JSON,
+		interfaces.HTTP_HEADER_ACCOUNT_ID:   accountInfo.ID,
+		interfaces.HTTP_HEADER_ACCOUNT_TYPE: accountInfo.Type,
+	}
+
+	respCode, result, err := ua.httpClient.DeleteNoUnmarshal(ctx, urlStr, headers)
+	if err != nil {
+		errDetails := fmt.Sprintf("delete result cache of metric models '%s' failed, %s", modelIDsStr, err.Error())
+		logger.Error(errDetails)
+
+		o11y.Error(ctx, errDetails)
+		o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Http Delete Failed")
+
+		return err
+	}
+
+	if respCode != http.StatusNoContent {
+		var baseError rest.BaseError
+		if err := sonic.Unmarshal(result, &baseError); err != nil {
+			errDetails := fmt.Sprintf("Unmarshal BaseError failed: %s", err.Error())
+			logger.Error(errDetails)
+
+			o11y.Error(ctx, errDetails)
+			o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Unmarshal BaseError failed")
+
+			return err
+		}
+
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", baseError.Description, baseError.ErrorDetails))
+		o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Http status is not 204")
+		return fmt.Errorf("delete result cache of metric models '%s' failed, errDetails: %v", modelIDsStr, baseError.ErrorDetails)
+	}
+
+	o11y.AddHttpAttrs4Ok(span, respCode)
+	return nil
+}
+
 // 计算公式有效性检查
 func (ua *uniqueryAccess) BuildDataViewSql(ctx context.Context, view *interfaces.DataView) (string, error) {
 	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: build data view sql", trace.WithSpanKind(trace.SpanKindClient))
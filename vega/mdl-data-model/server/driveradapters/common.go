@@ -13,6 +13,7 @@ const (
 	OBJECT_TYPE_DATA_CONNECTION           = "data_connection"
 	OBJECT_TYPE_DATA_DICT                 = "data_dict"
 	OBJECT_TYPE_DATA_DICT_ITEM            = "data_dict_item"
+	OBJECT_TYPE_DATA_QUALITY_RULE         = "data_quality_rule"
 	OBJECT_TYPE_DATA_VIEW                 = "data_view"
 	OBJECT_TYPE_DATA_VIEW_GROUP           = "data_view_group"
 	OBJECT_TYPE_DATA_VIEW_ROW_COLUMN_RULE = "data_view_row_column_rule"
@@ -47,6 +48,14 @@ func GenerateDataDictItemAuditObject(id string, name string) audit.AuditObject {
 	}
 }
 
+func GenerateDataQualityRuleAuditObject(id string, name string) audit.AuditObject {
+	return audit.AuditObject{
+		Type: OBJECT_TYPE_DATA_QUALITY_RULE,
+		ID:   id,
+		Name: name,
+	}
+}
+
 func GenerateDataViewAuditObject(id string, name string) audit.AuditObject {
 	return audit.AuditObject{
 		Type: OBJECT_TYPE_DATA_VIEW,
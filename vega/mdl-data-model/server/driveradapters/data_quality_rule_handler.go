@@ -0,0 +1,635 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/audit"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+// 创建数据质量规则（外部）
+func (r *restHandler) CreateDataQualityRulesByEx(c *gin.Context) {
+	logger.Debug("Handler CreateDataQualityRulesByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Create data quality rules by ex", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.CreateDataQualityRules(c, visitor)
+}
+
+// 创建数据质量规则(内部)
+func (r *restHandler) CreateDataQualityRulesByIn(c *gin.Context) {
+	logger.Debug("Handler CreateDataQualityRulesByIn Start")
+	visitor := GenerateVisitor(c)
+	r.CreateDataQualityRules(c, visitor)
+}
+
+// 批量创建数据质量规则
+func (r *restHandler) CreateDataQualityRules(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler CreateDataQualityRules Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Create data quality rules", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	rules := []*interfaces.DataQualityRule{}
+	err := c.ShouldBindJSON(&rules)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_InvalidParameter_RequestBody).
+			WithErrorDetails("Binding paramter failed:" + err.Error())
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.CREATE, audit.TransforOperator(visitor),
+			GenerateDataQualityRuleAuditObject("", ""), &httpErr.BaseError)
+
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	if len(rules) == 0 {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_InvalidParameter_RequestBody).
+			WithErrorDetails("No data quality rule was passed in")
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.CREATE, audit.TransforOperator(visitor),
+			GenerateDataQualityRuleAuditObject("", ""), &httpErr.BaseError)
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	// 校验请求体中的规则 id 不重复, 规则名称在视图内不重复
+	idMap := make(map[string]struct{})
+	nameMap := make(map[string]struct{})
+	for _, rule := range rules {
+		var httpErr *rest.HTTPError
+		ukName := fmt.Sprintf("%s_%s", rule.RuleName, rule.ViewID)
+		if _, ok := idMap[rule.RuleID]; ok && rule.RuleID != "" {
+			httpErr = rest.NewHTTPError(ctx, http.StatusBadRequest, rest.PublicError_BadRequest).
+				WithErrorDetails(fmt.Sprintf("data quality rule ID '%s' already exists in the request body", rule.RuleID))
+		} else if _, ok := nameMap[ukName]; ok {
+			httpErr = rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_ExistByName).
+				WithErrorDetails(fmt.Sprintf("data quality rule name '%s' already exists within the view '%s' in the request body",
+					rule.RuleName, rule.ViewID))
+		} else if err := validateDataQualityRule(ctx, rule); err != nil {
+			httpErr = err.(*rest.HTTPError)
+		}
+
+		if httpErr != nil {
+			audit.NewWarnLogWithError(audit.OPERATION, audit.CREATE, audit.TransforOperator(visitor),
+				GenerateDataQualityRuleAuditObject(rule.RuleID, rule.RuleName), &httpErr.BaseError)
+
+			o11y.AddHttpAttrs4HttpError(span, httpErr)
+			rest.ReplyError(c, httpErr)
+			return
+		}
+
+		idMap[rule.RuleID] = struct{}{}
+		nameMap[ukName] = struct{}{}
+	}
+
+	ruleIDs, err := r.dqrs.CreateDataQualityRules(ctx, rules)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.CREATE, audit.TransforOperator(visitor),
+			GenerateDataQualityRuleAuditObject(rules[0].RuleID, rules[0].RuleName), &httpErr.BaseError)
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	for _, rule := range rules {
+		audit.NewInfoLog(audit.OPERATION, audit.CREATE, audit.TransforOperator(visitor),
+			GenerateDataQualityRuleAuditObject(rule.RuleID, rule.RuleName), "")
+	}
+
+	result := make([]any, 0, len(ruleIDs))
+	for _, ruleID := range ruleIDs {
+		result = append(result, map[string]any{"id": ruleID})
+	}
+
+	logger.Debug("Handler CreateDataQualityRules Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusCreated)
+	rest.ReplyOK(c, http.StatusCreated, result)
+}
+
+// 删除数据质量规则（外部）
+func (r *restHandler) DeleteDataQualityRulesByEx(c *gin.Context) {
+	logger.Debug("Handler DeleteDataQualityRulesByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Delete data quality rules by ex", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.DeleteDataQualityRules(c, visitor)
+}
+
+// 删除数据质量规则(内部)
+func (r *restHandler) DeleteDataQualityRulesByIn(c *gin.Context) {
+	logger.Debug("Handler DeleteDataQualityRulesByIn Start")
+	visitor := GenerateVisitor(c)
+	r.DeleteDataQualityRules(c, visitor)
+}
+
+// 批量删除数据质量规则
+func (r *restHandler) DeleteDataQualityRules(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler DeleteDataQualityRules Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Delete data quality rules", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	ruleIDs := common.StringToStringSlice(c.Param("rule_ids"))
+	if len(ruleIDs) == 0 {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_NullParameter_RuleID).
+			WithErrorDetails("rule_ids is required")
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.DELETE, audit.TransforOperator(visitor),
+			GenerateDataQualityRuleAuditObject("", ""), &httpErr.BaseError)
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	span.SetAttributes(attr.Key("rule_ids").String(strings.Join(ruleIDs, ",")))
+
+	// 获取规则名称用于记录审计日志, 同时校验规则存在
+	rules, err := r.dqrs.GetDataQualityRules(ctx, ruleIDs)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.DELETE, audit.TransforOperator(visitor),
+			GenerateDataQualityRuleAuditObject(strings.Join(ruleIDs, ","), ""), &httpErr.BaseError)
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	err = r.dqrs.DeleteDataQualityRules(ctx, ruleIDs)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.DELETE, audit.TransforOperator(visitor),
+			GenerateDataQualityRuleAuditObject(strings.Join(ruleIDs, ","), ""), &httpErr.BaseError)
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	for _, rule := range rules {
+		audit.NewWarnLog(audit.OPERATION, audit.DELETE, audit.TransforOperator(visitor),
+			GenerateDataQualityRuleAuditObject(rule.RuleID, rule.RuleName), audit.SUCCESS, "")
+	}
+
+	logger.Debug("Handler DeleteDataQualityRules Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusNoContent)
+	rest.ReplyOK(c, http.StatusNoContent, nil)
+}
+
+// 修改数据质量规则（外部）
+func (r *restHandler) UpdateDataQualityRuleByEx(c *gin.Context) {
+	logger.Debug("Handler UpdateDataQualityRuleByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Update a data quality rule by ex", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.UpdateDataQualityRule(c, visitor)
+}
+
+// 修改数据质量规则(内部)
+func (r *restHandler) UpdateDataQualityRuleByIn(c *gin.Context) {
+	logger.Debug("Handler UpdateDataQualityRuleByIn Start")
+	visitor := GenerateVisitor(c)
+	r.UpdateDataQualityRule(c, visitor)
+}
+
+// 修改数据质量规则
+func (r *restHandler) UpdateDataQualityRule(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler UpdateDataQualityRule Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Update a data quality rule", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	ruleID := c.Param("rule_id")
+	span.SetAttributes(attr.Key("rule_id").String(ruleID))
+
+	rule := &interfaces.DataQualityRule{}
+	err := c.ShouldBindJSON(rule)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_InvalidParameter_RequestBody).
+			WithErrorDetails("Binding paramter failed:" + err.Error())
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.UPDATE, audit.TransforOperator(visitor),
+			GenerateDataQualityRuleAuditObject(ruleID, ""), &httpErr.BaseError)
+
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	rule.RuleID = ruleID
+
+	err = validateDataQualityRule(ctx, rule)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.UPDATE, audit.TransforOperator(visitor),
+			GenerateDataQualityRuleAuditObject(ruleID, rule.RuleName), &httpErr.BaseError)
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	err = r.dqrs.UpdateDataQualityRule(ctx, rule)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.UPDATE, audit.TransforOperator(visitor),
+			GenerateDataQualityRuleAuditObject(ruleID, rule.RuleName), &httpErr.BaseError)
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	audit.NewInfoLog(audit.OPERATION, audit.UPDATE, audit.TransforOperator(visitor),
+		GenerateDataQualityRuleAuditObject(ruleID, rule.RuleName), "")
+
+	logger.Debug("Handler UpdateDataQualityRule Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusNoContent)
+	rest.ReplyOK(c, http.StatusNoContent, nil)
+}
+
+// 按 id 获取数据质量规则（外部）
+func (r *restHandler) GetDataQualityRulesByEx(c *gin.Context) {
+	logger.Debug("Handler GetDataQualityRulesByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Get data quality rules by ex", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.GetDataQualityRules(c, visitor)
+}
+
+// 按 id 获取数据质量规则(内部)
+func (r *restHandler) GetDataQualityRulesByIn(c *gin.Context) {
+	logger.Debug("Handler GetDataQualityRulesByIn Start")
+	visitor := GenerateVisitor(c)
+	r.GetDataQualityRules(c, visitor)
+}
+
+// 按 id 获取数据质量规则
+func (r *restHandler) GetDataQualityRules(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler GetDataQualityRules Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Get data quality rules", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	ruleIDs := common.StringToStringSlice(c.Param("rule_ids"))
+	if len(ruleIDs) == 0 {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_NullParameter_RuleID).
+			WithErrorDetails("rule_ids is required")
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	rules, err := r.dqrs.GetDataQualityRules(ctx, ruleIDs)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	logger.Debug("Handler GetDataQualityRules Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, rules)
+}
+
+// 分页获取数据质量规则列表（外部）
+func (r *restHandler) ListDataQualityRulesByEx(c *gin.Context) {
+	logger.Debug("Handler ListDataQualityRulesByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: List data quality rules by ex", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.ListDataQualityRules(c, visitor, false)
+}
+
+// 分页获取数据质量规则列表(内部)
+func (r *restHandler) ListDataQualityRulesByIn(c *gin.Context) {
+	logger.Debug("Handler ListDataQualityRulesByIn Start")
+	visitor := GenerateVisitor(c)
+	r.ListDataQualityRules(c, visitor, true)
+}
+
+// 分页获取数据质量规则列表
+func (r *restHandler) ListDataQualityRules(c *gin.Context, visitor rest.Visitor, isInnerRequest bool) {
+	logger.Debug("Handler ListDataQualityRules Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: List data quality rules", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	namePattern := c.Query("name_pattern")
+	viewIDs := common.StringToStringSlice(c.Query("view_id"))
+	kind := c.Query("kind")
+	tag := c.Query("tag")
+
+	offset := c.DefaultQuery("offset", interfaces.DEFAULT_OFFEST)
+	limit := c.DefaultQuery("limit", interfaces.DEFAULT_LIMIT)
+	sort := c.DefaultQuery("sort", "update_time")
+	direction := c.DefaultQuery("direction", interfaces.DESC_DIRECTION)
+
+	if kind != "" {
+		if _, ok := interfaces.DataQualityKindMap[kind]; !ok {
+			httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_InvalidParameter_Kind).
+				WithErrorDetails(fmt.Sprintf("Unsupported data quality rule kind '%s'", kind))
+			o11y.AddHttpAttrs4HttpError(span, httpErr)
+			rest.ReplyError(c, httpErr)
+			return
+		}
+	}
+
+	// 分页参数校验
+	pageParam, err := validatePaginationQueryParameters(ctx,
+		offset, limit, sort, direction, interfaces.DATA_QUALITY_RULE_SORT)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	params := &interfaces.ListDataQualityRuleQueryParams{
+		NamePattern:               namePattern,
+		ViewIDs:                   viewIDs,
+		Kind:                      kind,
+		Tag:                       strings.Trim(tag, " "),
+		IsInnerRequest:            isInnerRequest,
+		PaginationQueryParameters: pageParam,
+	}
+
+	rules, total, err := r.dqrs.ListDataQualityRules(ctx, params)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	result := map[string]any{
+		"entries":     rules,
+		"total_count": total,
+	}
+
+	logger.Debug("Handler ListDataQualityRules Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, result)
+}
+
+// 获取规则的检查结果历史（外部）
+func (r *restHandler) ListDataQualityResultsByEx(c *gin.Context) {
+	logger.Debug("Handler ListDataQualityResultsByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: List data quality results by ex", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.ListDataQualityResults(c, visitor)
+}
+
+// 获取规则的检查结果历史(内部)
+func (r *restHandler) ListDataQualityResultsByIn(c *gin.Context) {
+	logger.Debug("Handler ListDataQualityResultsByIn Start")
+	visitor := GenerateVisitor(c)
+	r.ListDataQualityResults(c, visitor)
+}
+
+// 获取规则的检查结果历史, 按检查时间倒序
+func (r *restHandler) ListDataQualityResults(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler ListDataQualityResults Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: List data quality results", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	// 与按 id 获取规则共用路由参数，此处只支持单个 id
+	ruleID := c.Param("rule_ids")
+	span.SetAttributes(attr.Key("rule_id").String(ruleID))
+
+	params := &interfaces.ListDataQualityResultQueryParams{
+		RuleID: ruleID,
+		Status: c.Query("status"),
+		Limit:  interfaces.DEFAULT_DATA_QUALITY_RESULTS_LIMIT,
+	}
+
+	var err error
+	for _, param := range []struct {
+		key    string
+		target *int64
+	}{{"start", &params.Start}, {"end", &params.End}} {
+		if value := c.Query(param.key); value != "" {
+			*param.target, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, rest.PublicError_BadRequest).
+					WithErrorDetails(fmt.Sprintf("Param '%s' should be a timestamp in milliseconds", param.key))
+				o11y.AddHttpAttrs4HttpError(span, httpErr)
+				rest.ReplyError(c, httpErr)
+				return
+			}
+		}
+	}
+
+	if value := c.Query("limit"); value != "" {
+		params.Limit, err = strconv.Atoi(value)
+		if err != nil || params.Limit < 1 || params.Limit > interfaces.MAX_LIMIT {
+			httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_InvalidParameter_Limit).
+				WithErrorDetails(fmt.Sprintf("Param 'limit' should be between 1 and %d", interfaces.MAX_LIMIT))
+			o11y.AddHttpAttrs4HttpError(span, httpErr)
+			rest.ReplyError(c, httpErr)
+			return
+		}
+	}
+
+	results, err := r.dqrs.ListDataQualityResults(ctx, params)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	result := map[string]any{"entries": results, "total_count": len(results)}
+
+	logger.Debug("Handler ListDataQualityResults Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, result)
+}
+
+// 获取数据视图的质量概况（外部）
+func (r *restHandler) GetDataQualitySummariesByEx(c *gin.Context) {
+	logger.Debug("Handler GetDataQualitySummariesByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Get data quality summaries by ex", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.GetDataQualitySummaries(c, visitor)
+}
+
+// 获取数据视图的质量概况(内部)
+func (r *restHandler) GetDataQualitySummariesByIn(c *gin.Context) {
+	logger.Debug("Handler GetDataQualitySummariesByIn Start")
+	visitor := GenerateVisitor(c)
+	r.GetDataQualitySummaries(c, visitor)
+}
+
+// 获取数据视图的质量概况, 供构建本体对象类时判断源视图是否可信
+func (r *restHandler) GetDataQualitySummaries(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler GetDataQualitySummaries Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Get data quality summaries", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	viewIDs := common.StringToStringSlice(c.Param("view_ids"))
+	if len(viewIDs) == 0 {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_NullParameter_ViewID).
+			WithErrorDetails("view_ids is required")
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	summaries, err := r.dqrs.GetDataQualitySummaries(ctx, viewIDs)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	logger.Debug("Handler GetDataQualitySummaries Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, summaries)
+}
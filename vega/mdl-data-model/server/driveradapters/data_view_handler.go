@@ -478,6 +478,20 @@ func (r *restHandler) UpdateDataView(c *gin.Context, visitor rest.Visitor) {
 	viewID := c.Param("view_id")
 	span.SetAttributes(attr.Key("view_id").String(viewID))
 
+	// 修改时可填写版本说明, 随修改后的定义记录到历史版本中
+	revisionComment := c.Query("revision_comment")
+	if err := validateRevisionComment(ctx, revisionComment); err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.UPDATE, audit.TransforOperator(visitor),
+			GenerateDataViewAuditObject(viewID, ""), &httpErr.BaseError)
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	ctx = context.WithValue(ctx, interfaces.REVISION_COMMENT_KEY, revisionComment)
+
 	viewInfo := &interfaces.DataView{}
 	err := c.ShouldBindJSON(&viewInfo)
 	if err != nil {
@@ -600,6 +614,16 @@ func (r *restHandler) GetDataViews(c *gin.Context, visitor rest.Visitor) {
 		return
 	}
 
+	// 可指定视图的历史版本, 用于复现报表
+	revision, err := validatePinnedRevision(ctx, c.Query("revision"), viewIDs)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	ctx = context.WithValue(ctx, interfaces.MODEL_REVISION_KEY, revision)
+
 	views, err := r.dvs.GetDataViews(ctx, viewIDs, includeDataScopeView)
 	if err != nil {
 		httpErr := err.(*rest.HTTPError)
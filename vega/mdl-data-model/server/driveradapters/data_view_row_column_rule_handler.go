@@ -145,6 +145,7 @@ func (r *restHandler) CreateDataViewRowColumnRules(c *gin.Context, visitor rest.
 			Tags:       reqBody[i].Tags,
 			Comment:    reqBody[i].Comment,
 			Fields:     reqBody[i].Fields,
+			FieldMasks: reqBody[i].FieldMasks,
 			RowFilters: reqBody[i].RowFilters,
 		}
 
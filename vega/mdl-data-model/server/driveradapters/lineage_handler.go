@@ -0,0 +1,170 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"go.opentelemetry.io/otel/trace"
+
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+// 外部服务登记血缘的请求体
+type lineageEdgesRequest struct {
+	Edges []*interfaces.LineageEdge `json:"edges"`
+}
+
+// 查询血缘图（外部）
+func (r *restHandler) GetLineageByEx(c *gin.Context) {
+	logger.Debug("Handler GetLineageByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Get lineage by ex", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.GetLineage(c, visitor)
+}
+
+// 查询血缘图（内部）
+func (r *restHandler) GetLineageByIn(c *gin.Context) {
+	logger.Debug("Handler GetLineageByIn Start")
+	visitor := GenerateVisitor(c)
+	r.GetLineage(c, visitor)
+}
+
+// 从起始节点展开上下游血缘, 用于修改或删除前的影响分析
+func (r *restHandler) GetLineage(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler GetLineage Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Get lineage", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	query, err := validateLineageQuery(ctx,
+		c.Query("node_type"),
+		c.Query("node_id"),
+		c.Query("field"),
+		c.DefaultQuery("direction", interfaces.LINEAGE_DIRECTION_BOTH),
+		c.DefaultQuery("depth", strconv.Itoa(interfaces.DEFAULT_LINEAGE_DEPTH)))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	graph, err := r.ls.GetLineage(ctx, query)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	logger.Debug("Handler GetLineage Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, graph)
+}
+
+// 外部服务登记其模型的血缘（内部）, 整体替换该模型已登记的边
+func (r *restHandler) ReplaceLineageEdgesByIn(c *gin.Context) {
+	logger.Debug("Handler ReplaceLineageEdgesByIn Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Replace lineage edges by in", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	visitor := GenerateVisitor(c)
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	req := lineageEdgesRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_InvalidParameter_RequestBody).
+			WithErrorDetails("Binding paramter failed:" + err.Error())
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	err = r.ls.ReplaceLineageEdges(ctx, getLineageOwner(c), req.Edges)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	logger.Debug("Handler ReplaceLineageEdgesByIn Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusNoContent)
+	rest.ReplyOK(c, http.StatusNoContent, nil)
+}
+
+// 外部服务删除其模型登记的血缘（内部）
+func (r *restHandler) DeleteLineageEdgesByIn(c *gin.Context) {
+	logger.Debug("Handler DeleteLineageEdgesByIn Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Delete lineage edges by in", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	visitor := GenerateVisitor(c)
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	err := r.ls.DeleteLineageEdges(ctx, getLineageOwner(c))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	logger.Debug("Handler DeleteLineageEdgesByIn Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusNoContent)
+	rest.ReplyOK(c, http.StatusNoContent, nil)
+}
+
+// owner_id 为通配路径参数, 本体的模型 id 形如 <kn_id>/<ot_id>
+func getLineageOwner(c *gin.Context) interfaces.LineageNode {
+	return interfaces.LineageNode{
+		Type: c.Param("owner_type"),
+		ID:   strings.Trim(c.Param("owner_id"), "/"),
+	}
+}
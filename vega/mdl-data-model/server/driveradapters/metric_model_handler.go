@@ -385,6 +385,20 @@ func (r *restHandler) UpdateMetricModel(c *gin.Context, visitor rest.Visitor) {
 	modelID := c.Param("model_id")
 	span.SetAttributes(attr.Key("model_id").String(modelID))
 
+	// 修改时可填写版本说明, 随修改后的定义记录到历史版本中
+	revisionComment := c.Query("revision_comment")
+	if err := validateRevisionComment(ctx, revisionComment); err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.UPDATE, audit.TransforOperator(visitor),
+			GenerateMetricModelAuditObject(modelID, ""), &httpErr.BaseError)
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	ctx = context.WithValue(ctx, interfaces.REVISION_COMMENT_KEY, revisionComment)
+
 	//接收绑定参数
 	metricModel := interfaces.MetricModel{}
 	err := c.ShouldBindJSON(&metricModel)
@@ -795,6 +809,16 @@ func (r *restHandler) GetMetricModels(c *gin.Context, visitor rest.Visitor) {
 		return
 	}
 
+	// 可指定模型的历史版本, 用于复现报表
+	revision, err := validatePinnedRevision(ctx, c.Query("revision"), modelIDs)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	ctx = context.WithValue(ctx, interfaces.MODEL_REVISION_KEY, revision)
+
 	// 获取指标模型的详细信息，根据 include_view 参数来判断是否包含数据视图的过滤条件
 	result, err := r.mms.GetMetricModels(ctx, modelIDs, includeView)
 	if err != nil {
@@ -1097,6 +1121,283 @@ func (r *restHandler) UpdateMetricTaskPlanTime(c *gin.Context, visitor rest.Visi
 	rest.ReplyOK(c, http.StatusNoContent, nil)
 }
 
+// 创建补数任务(内部)
+func (r *restHandler) CreateMetricTaskBackfillByIn(c *gin.Context) {
+	logger.Debug("Handler CreateMetricTaskBackfillByIn Start")
+	// 内部接口 user_id从header中取，跳过用户有效认证，后面在权限校验时就会校验这个用户是否有权限，无效用户无权限
+	// 自行构建一个visitor
+	visitor := GenerateVisitor(c)
+	r.CreateMetricTaskBackfill(c, visitor)
+}
+
+// 创建补数任务（外部）
+func (r *restHandler) CreateMetricTaskBackfillByEx(c *gin.Context) {
+	logger.Debug("Handler CreateMetricTaskBackfillByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"创建补数任务", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.CreateMetricTaskBackfill(c, visitor)
+}
+
+// 创建补数任务，按时间范围重新计算模型并覆盖已持久化的数据
+func (r *restHandler) CreateMetricTaskBackfill(c *gin.Context, visitor rest.Visitor) {
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"创建补数任务", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	// 设置 trace 的相关 api 的属性
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	//接收绑定参数
+	backfill := interfaces.MetricTaskBackfill{}
+	err := c.ShouldBindJSON(&backfill)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter).
+			WithErrorDetails("Binding Paramter Failed:" + err.Error())
+
+		// 记录异常日志
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+
+		// 设置 trace 的错误信息的 attributes
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	err = ValidateMetricTaskBackfill(ctx, backfill)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		// 设置 trace 的错误信息的 attributes
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	span.SetAttributes(attr.Key("model_id").String(backfill.ModelID))
+
+	backfillID, err := r.mmts.CreateMetricTaskBackfill(ctx, backfill)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		// 设置 trace 的错误信息的 attributes
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	result := map[string]string{"id": backfillID}
+
+	logger.Debug("Handler CreateMetricTaskBackfill Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusCreated)
+	rest.ReplyOK(c, http.StatusCreated, result)
+}
+
+// 按 id 获取补数任务(内部)
+func (r *restHandler) GetMetricTaskBackfillByIn(c *gin.Context) {
+	logger.Debug("Handler GetMetricTaskBackfillByIn Start")
+	// 内部接口 user_id从header中取，跳过用户有效认证，后面在权限校验时就会校验这个用户是否有权限，无效用户无权限
+	// 自行构建一个visitor
+	visitor := GenerateVisitor(c)
+	r.GetMetricTaskBackfill(c, visitor)
+}
+
+// 按 id 获取补数任务（外部）
+func (r *restHandler) GetMetricTaskBackfillByEx(c *gin.Context) {
+	logger.Debug("Handler GetMetricTaskBackfillByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"按id获取补数任务", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.GetMetricTaskBackfill(c, visitor)
+}
+
+// 按 id 获取补数任务，包含执行进度
+func (r *restHandler) GetMetricTaskBackfill(c *gin.Context, visitor rest.Visitor) {
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"按id获取补数任务", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	// 设置 trace 的相关 api 的属性
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	backfillID := c.Param("backfill_id")
+	span.SetAttributes(attr.Key("backfill_id").String(backfillID))
+
+	backfill, err := r.mmts.GetMetricTaskBackfill(ctx, backfillID)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		// 设置 trace 的错误信息的 attributes
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, backfill)
+}
+
+// 获取补数任务列表(内部)
+func (r *restHandler) ListMetricTaskBackfillsByIn(c *gin.Context) {
+	logger.Debug("Handler ListMetricTaskBackfillsByIn Start")
+	// 内部接口 user_id从header中取，跳过用户有效认证，后面在权限校验时就会校验这个用户是否有权限，无效用户无权限
+	// 自行构建一个visitor
+	visitor := GenerateVisitor(c)
+	r.ListMetricTaskBackfills(c, visitor)
+}
+
+// 获取补数任务列表（外部）
+func (r *restHandler) ListMetricTaskBackfillsByEx(c *gin.Context) {
+	logger.Debug("Handler ListMetricTaskBackfillsByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"获取补数任务列表", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.ListMetricTaskBackfills(c, visitor)
+}
+
+// 获取补数任务列表，支持按模型 id 和状态过滤
+func (r *restHandler) ListMetricTaskBackfills(c *gin.Context, visitor rest.Visitor) {
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"获取补数任务列表", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	// 设置 trace 的相关 api 的属性
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	params := interfaces.MetricTaskBackfillQueryParams{
+		ModelID: c.Query("model_id"),
+		Status:  c.Query("status"),
+	}
+	err := ValidateMetricTaskBackfillStatus(ctx, params.Status)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		// 设置 trace 的错误信息的 attributes
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	entries, err := r.mmts.ListMetricTaskBackfills(ctx, params)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		// 记录异常日志
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description,
+			httpErr.BaseError.ErrorDetails))
+
+		// 设置 trace 的错误信息的 attributes
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	result := map[string]any{
+		"entries":     entries,
+		"total_count": len(entries),
+	}
+
+	logger.Debug("Handler ListMetricTaskBackfills Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, result)
+}
+
+// 取消补数任务(内部)
+func (r *restHandler) CancelMetricTaskBackfillByIn(c *gin.Context) {
+	logger.Debug("Handler CancelMetricTaskBackfillByIn Start")
+	// 内部接口 user_id从header中取，跳过用户有效认证，后面在权限校验时就会校验这个用户是否有权限，无效用户无权限
+	// 自行构建一个visitor
+	visitor := GenerateVisitor(c)
+	r.CancelMetricTaskBackfill(c, visitor)
+}
+
+// 取消补数任务（外部）
+func (r *restHandler) CancelMetricTaskBackfillByEx(c *gin.Context) {
+	logger.Debug("Handler CancelMetricTaskBackfillByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"取消补数任务", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.CancelMetricTaskBackfill(c, visitor)
+}
+
+// 取消补数任务，执行中的补数任务在当前批次完成后停止，已覆盖的数据不回滚
+func (r *restHandler) CancelMetricTaskBackfill(c *gin.Context, visitor rest.Visitor) {
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"取消补数任务", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	// 设置 trace 的相关 api 的属性
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	backfillID := c.Param("backfill_id")
+	span.SetAttributes(attr.Key("backfill_id").String(backfillID))
+
+	err := r.mmts.CancelMetricTaskBackfill(ctx, backfillID)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		// 设置 trace 的错误信息的 attributes
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	logger.Debug("Handler CancelMetricTaskBackfill Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusNoContent)
+	rest.ReplyOK(c, http.StatusNoContent, nil)
+}
+
 // 按 id 获取指标模型所绑定的数据源的字段列表
 func (r *restHandler) GetMetricModelSourceFields(c *gin.Context) {
 	logger.Debug("Handler GetMetricModelSourceFields Start")
@@ -0,0 +1,264 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/bytedance/sonic"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/audit"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"go.opentelemetry.io/otel/trace"
+
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+// 导出模型包（外部）
+func (r *restHandler) ExportModelBundleByEx(c *gin.Context) {
+	logger.Debug("Handler ExportModelBundleByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Export model bundle by ex", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.ExportModelBundle(c, visitor)
+}
+
+// 导出模型包（内部）
+func (r *restHandler) ExportModelBundleByIn(c *gin.Context) {
+	logger.Debug("Handler ExportModelBundleByIn Start")
+	visitor := GenerateVisitor(c)
+	r.ExportModelBundle(c, visitor)
+}
+
+// 导出选中的视图、分组、指标模型、事件模型和目标模型, 依赖的对象一并导出
+func (r *restHandler) ExportModelBundle(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler ExportModelBundle Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Export model bundle", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	format := c.DefaultQuery("format", interfaces.MODEL_BUNDLE_FORMAT_JSON)
+	err := validateModelBundleFormat(ctx, format)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	req := interfaces.ModelBundleExportRequest{}
+	err = c.ShouldBindJSON(&req)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_InvalidParameter_RequestBody).
+			WithErrorDetails("Binding paramter failed:" + err.Error())
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	err = validateModelBundleExportRequest(ctx, &req)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	bundle, err := r.mbs.ExportModelBundle(ctx, &req)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	logger.Debug("Handler ExportModelBundle Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	if format == interfaces.MODEL_BUNDLE_FORMAT_JSON {
+		rest.ReplyOK(c, http.StatusOK, bundle)
+		return
+	}
+
+	// yaml 按 json 标签输出, 先转成通用结构
+	var content any
+	bundleBytes, err := sonic.Marshal(bundle)
+	if err == nil {
+		err = sonic.Unmarshal(bundleBytes, &content)
+	}
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusInternalServerError, rest.PublicError_InternalServerError).
+			WithErrorDetails(err.Error())
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	c.YAML(http.StatusOK, content)
+}
+
+// 导入模型包（外部）
+func (r *restHandler) ImportModelBundleByEx(c *gin.Context) {
+	logger.Debug("Handler ImportModelBundleByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Import model bundle by ex", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.ImportModelBundle(c, visitor)
+}
+
+// 导入模型包（内部）
+func (r *restHandler) ImportModelBundleByIn(c *gin.Context) {
+	logger.Debug("Handler ImportModelBundleByIn Start")
+	visitor := GenerateVisitor(c)
+	r.ImportModelBundle(c, visitor)
+}
+
+// 导入模型包, 请求体为 json 或 yaml. dry_run 为 true 时只返回导入计划
+func (r *restHandler) ImportModelBundle(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler ImportModelBundle Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Import model bundle", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	mode := c.DefaultQuery(interfaces.QueryParam_ImportMode, interfaces.MODEL_BUNDLE_IMPORT_MODE_CREATE_ONLY)
+	err := validateModelBundleImportMode(ctx, mode)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	dryRun, err := validateDryRun(ctx, c.DefaultQuery(interfaces.QueryParam_DryRun, "false"))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	bundle, err := bindModelBundle(c)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelBundle_InvalidParameter_Bundle).
+			WithErrorDetails("Binding paramter failed:" + err.Error())
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	err = validateModelBundle(ctx, bundle)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	result, err := r.mbs.ImportModelBundle(ctx, bundle, mode, dryRun)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.Error(ctx, fmt.Sprintf("Import model bundle failed: %s. %v", httpErr.BaseError.Description,
+			httpErr.BaseError.ErrorDetails))
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	if !dryRun {
+		for _, item := range result.Items {
+			generateAuditObject, ok := modelBundleAuditObjects[item.AssetType]
+			if !ok {
+				continue
+			}
+			switch item.Action {
+			case interfaces.MODEL_BUNDLE_ACTION_CREATE, interfaces.MODEL_BUNDLE_ACTION_RENAME:
+				audit.NewInfoLog(audit.OPERATION, audit.CREATE, audit.TransforOperator(visitor),
+					generateAuditObject(item.TargetID, item.TargetName), "")
+			case interfaces.MODEL_BUNDLE_ACTION_UPDATE:
+				audit.NewInfoLog(audit.OPERATION, audit.UPDATE, audit.TransforOperator(visitor),
+					generateAuditObject(item.TargetID, item.TargetName), "")
+			}
+		}
+	}
+
+	logger.Debug("Handler ImportModelBundle Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, result)
+}
+
+// 模型包中各类对象的审计对象
+var modelBundleAuditObjects = map[string]func(id string, name string) audit.AuditObject{
+	interfaces.MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW_GROUP:    GenerateDataViewGroupAuditObject,
+	interfaces.MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW:          GenerateDataViewAuditObject,
+	interfaces.MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL_GROUP: GenerateMetricModelGroupAuditObject,
+	interfaces.MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL:       GenerateMetricModelAuditObject,
+	interfaces.MODEL_BUNDLE_ASSET_TYPE_EVENT_MODEL:        GenerateEventModelAuditObject,
+	interfaces.MODEL_BUNDLE_ASSET_TYPE_OBJECTIVE_MODEL:    GenerateObjectiveModelAuditObject,
+}
+
+// 按 Content-Type 解析 json 或 yaml 格式的模型包, yaml 按 json 标签映射到结构体
+func bindModelBundle(c *gin.Context) (*interfaces.ModelBundle, error) {
+	bundle := &interfaces.ModelBundle{}
+
+	switch c.ContentType() {
+	case binding.MIMEYAML, binding.MIMEYAML2:
+		var content any
+		err := c.ShouldBindYAML(&content)
+		if err != nil {
+			return nil, err
+		}
+		contentBytes, err := sonic.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		err = sonic.Unmarshal(contentBytes, bundle)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		err := c.ShouldBindJSON(bundle)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bundle, nil
+}
@@ -0,0 +1,350 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"data-model/interfaces"
+)
+
+var (
+	// 查询版本的路由沿用模型详情路由的路径参数名
+	revisionModelIDParams = map[string]string{
+		interfaces.REVISION_MODEL_TYPE_METRIC_MODEL:    "model_ids",
+		interfaces.REVISION_MODEL_TYPE_DATA_VIEW:       "view_ids",
+		interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL: "model_ids",
+	}
+)
+
+// 查询模型的版本列表（外部）
+func (r *restHandler) ListModelRevisionsByEx(modelType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Debug("Handler ListModelRevisionsByEx Start")
+		ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+			"driver layer: List model revisions by ex", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		// 校验token
+		visitor, err := r.verifyOAuth(ctx, c)
+		if err != nil {
+			return
+		}
+		r.ListModelRevisions(c, visitor, modelType)
+	}
+}
+
+// 查询模型的版本列表（内部）
+func (r *restHandler) ListModelRevisionsByIn(modelType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Debug("Handler ListModelRevisionsByIn Start")
+		visitor := GenerateVisitor(c)
+		r.ListModelRevisions(c, visitor, modelType)
+	}
+}
+
+// 分页查询模型的版本列表, 默认按版本号倒序
+func (r *restHandler) ListModelRevisions(c *gin.Context, visitor rest.Visitor, modelType string) {
+	logger.Debug("Handler ListModelRevisions Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: List model revisions", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	modelID := c.Param(revisionModelIDParams[modelType])
+	span.SetAttributes(
+		attr.Key("model_type").String(modelType),
+		attr.Key("model_id").String(modelID),
+	)
+
+	pageParam, err := validatePaginationQueryParameters(ctx,
+		c.DefaultQuery("offset", interfaces.DEFAULT_OFFEST),
+		c.DefaultQuery("limit", interfaces.DEFAULT_LIMIT),
+		c.DefaultQuery("sort", "revision"),
+		c.DefaultQuery("direction", interfaces.DESC_DIRECTION),
+		interfaces.REVISION_SORT)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	revisions, total, err := r.mrs.ListModelRevisions(ctx, interfaces.ModelRevisionsQueryParams{
+		PaginationQueryParameters: pageParam,
+		ModelType:                 modelType,
+		ModelID:                   modelID,
+	})
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	result := map[string]any{
+		"entries":     revisions,
+		"total_count": total,
+	}
+
+	logger.Debug("Handler ListModelRevisions Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, result)
+}
+
+// 获取模型的指定版本（外部）
+func (r *restHandler) GetModelRevisionByEx(modelType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Debug("Handler GetModelRevisionByEx Start")
+		ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+			"driver layer: Get model revision by ex", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		// 校验token
+		visitor, err := r.verifyOAuth(ctx, c)
+		if err != nil {
+			return
+		}
+		r.GetModelRevision(c, visitor, modelType)
+	}
+}
+
+// 获取模型的指定版本（内部）
+func (r *restHandler) GetModelRevisionByIn(modelType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Debug("Handler GetModelRevisionByIn Start")
+		visitor := GenerateVisitor(c)
+		r.GetModelRevision(c, visitor, modelType)
+	}
+}
+
+// 获取模型的指定版本, 包含该版本的模型定义
+func (r *restHandler) GetModelRevision(c *gin.Context, visitor rest.Visitor, modelType string) {
+	logger.Debug("Handler GetModelRevision Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Get model revision", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	modelID := c.Param(revisionModelIDParams[modelType])
+	span.SetAttributes(
+		attr.Key("model_type").String(modelType),
+		attr.Key("model_id").String(modelID),
+	)
+
+	revision, err := validateRevision(ctx, c.Param("revision"))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	modelRevision, err := r.mrs.GetModelRevision(ctx, modelType, modelID, revision)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	logger.Debug("Handler GetModelRevision Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, modelRevision)
+}
+
+// 对比模型的两个版本（外部）
+func (r *restHandler) DiffModelRevisionsByEx(modelType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Debug("Handler DiffModelRevisionsByEx Start")
+		ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+			"driver layer: Diff model revisions by ex", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		// 校验token
+		visitor, err := r.verifyOAuth(ctx, c)
+		if err != nil {
+			return
+		}
+		r.DiffModelRevisions(c, visitor, modelType)
+	}
+}
+
+// 对比模型的两个版本（内部）
+func (r *restHandler) DiffModelRevisionsByIn(modelType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Debug("Handler DiffModelRevisionsByIn Start")
+		visitor := GenerateVisitor(c)
+		r.DiffModelRevisions(c, visitor, modelType)
+	}
+}
+
+// 对比模型的两个版本, from 和 to 为版本号
+func (r *restHandler) DiffModelRevisions(c *gin.Context, visitor rest.Visitor, modelType string) {
+	logger.Debug("Handler DiffModelRevisions Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Diff model revisions", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	modelID := c.Param(revisionModelIDParams[modelType])
+	span.SetAttributes(
+		attr.Key("model_type").String(modelType),
+		attr.Key("model_id").String(modelID),
+	)
+
+	fromRevision, err := validateRevision(ctx, c.Query("from"))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	toRevision, err := validateRevision(ctx, c.Query("to"))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	diff, err := r.mrs.DiffModelRevisions(ctx, modelType, modelID, fromRevision, toRevision)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	logger.Debug("Handler DiffModelRevisions Success")
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, diff)
+}
+
+// 回滚模型到指定版本（外部）
+func (r *restHandler) RollbackModelRevisionByEx(modelType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Debug("Handler RollbackModelRevisionByEx Start")
+		ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+			"driver layer: Rollback model revision by ex", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		// 校验token
+		visitor, err := r.verifyOAuth(ctx, c)
+		if err != nil {
+			return
+		}
+		r.RollbackModelRevision(c, visitor, modelType)
+	}
+}
+
+// 回滚模型到指定版本（内部）
+func (r *restHandler) RollbackModelRevisionByIn(modelType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Debug("Handler RollbackModelRevisionByIn Start")
+		visitor := GenerateVisitor(c)
+		r.RollbackModelRevision(c, visitor, modelType)
+	}
+}
+
+// 回滚模型到指定版本. 以版本快照作为请求体走一遍修改接口, 与普通修改使用相同的校验、权限和审计,
+// 回滚本身也会生成一个新版本
+func (r *restHandler) RollbackModelRevision(c *gin.Context, visitor rest.Visitor, modelType string) {
+	logger.Debug("Handler RollbackModelRevision Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver layer: Rollback model revision", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	// 回滚路由沿用修改路由的路径参数名
+	modelID := c.Param("model_id")
+	if modelType == interfaces.REVISION_MODEL_TYPE_DATA_VIEW {
+		modelID = c.Param("view_id")
+	}
+	span.SetAttributes(
+		attr.Key("model_type").String(modelType),
+		attr.Key("model_id").String(modelID),
+	)
+
+	revision, err := validateRevision(ctx, c.Param("revision"))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	modelRevision, err := r.mrs.GetModelRevision(ctx, modelType, modelID, revision)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	// 未填写版本说明时记录回滚来源
+	query := c.Request.URL.Query()
+	if query.Get("revision_comment") == "" {
+		query.Set("revision_comment", fmt.Sprintf("Rollback to revision %d", revision))
+		c.Request.URL.RawQuery = query.Encode()
+	}
+	c.Request.Body = io.NopCloser(strings.NewReader(modelRevision.Content))
+
+	switch modelType {
+	case interfaces.REVISION_MODEL_TYPE_METRIC_MODEL:
+		r.UpdateMetricModel(c, visitor)
+	case interfaces.REVISION_MODEL_TYPE_DATA_VIEW:
+		r.UpdateDataView(c, visitor)
+	case interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL:
+		r.UpdateObjectiveModel(c, visitor)
+	}
+
+	logger.Debug("Handler RollbackModelRevision End")
+}
@@ -0,0 +1,192 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	rmock "github.com/kweaver-ai/kweaver-go-lib/rest/mock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+	dmock "data-model/interfaces/mock"
+)
+
+func MockNewModelRevisionRestHandler(appSetting *common.AppSetting,
+	hydra rest.Hydra,
+	mrs interfaces.ModelRevisionService,
+	mms interfaces.MetricModelService,
+	dvs interfaces.DataViewService,
+	oms interfaces.ObjectiveModelService) (r *restHandler) {
+
+	r = &restHandler{
+		appSetting: appSetting,
+		hydra:      hydra,
+		mrs:        mrs,
+		mms:        mms,
+		dvs:        dvs,
+		oms:        oms,
+	}
+	return r
+}
+
+func Test_ModelRevisionRestHandler_RollbackModelRevision(t *testing.T) {
+	Convey("Test ModelRevisionHandler RollbackModelRevision\n", t, func() {
+		test := setGinMode()
+		defer test()
+
+		engine := gin.New()
+		engine.Use(gin.Recovery())
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		hydra := rmock.NewMockHydra(mockCtrl)
+		mrs := dmock.NewMockModelRevisionService(mockCtrl)
+		mms := dmock.NewMockMetricModelService(mockCtrl)
+		dvs := dmock.NewMockDataViewService(mockCtrl)
+		oms := dmock.NewMockObjectiveModelService(mockCtrl)
+
+		handler := MockNewModelRevisionRestHandler(appSetting, hydra, mrs, mms, dvs, oms)
+		handler.RegisterPublic(engine)
+
+		hydra.EXPECT().VerifyToken(gomock.Any(), gomock.Any()).AnyTimes().Return(rest.Visitor{}, nil)
+
+		common.PersistStepsMap = StepsMap
+
+		Convey("Rollback metric model with task succeed\n", func() {
+			// 版本快照中带有持久化任务, 回滚时任务随模型一起走修改接口
+			task := testTask
+			task.TimeWindows = nil
+			task.Steps = []string{"5m"}
+			snapshot := testMetricModel
+			snapshot.ModelID = "1"
+			snapshot.Task = &task
+			content, _ := sonic.MarshalString(snapshot)
+
+			mrs.EXPECT().GetModelRevision(gomock.Any(), interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, "1", 2).
+				Return(&interfaces.ModelRevision{Revision: 2, Content: content}, nil)
+			mms.EXPECT().GetMetricModelByModelID(gomock.Any(), "1").Return(testMetricModel, nil)
+			mms.EXPECT().UpdateMetricModel(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, tx *sql.Tx, model interfaces.MetricModel) error {
+					So(model.ModelID, ShouldEqual, "1")
+					So(model.Task, ShouldNotBeNil)
+					So(model.Task.Schedule, ShouldResemble, task.Schedule)
+					So(model.Task.Steps, ShouldResemble, task.Steps)
+					So(ctx.Value(interfaces.REVISION_COMMENT_KEY), ShouldEqual, "Rollback to revision 2")
+					return nil
+				})
+
+			url := "/api/mdl-data-model/v1/metric-models/1/revisions/2/rollback"
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			So(w.Result().StatusCode, ShouldEqual, http.StatusNoContent)
+		})
+
+		Convey("Rollback metric model failed, because the task in snapshot is invalid\n", func() {
+			// promql 模型的任务不允许配置时间窗口
+			task := testTask
+			snapshot := testMetricModel
+			snapshot.Task = &task
+			content, _ := sonic.MarshalString(snapshot)
+
+			mrs.EXPECT().GetModelRevision(gomock.Any(), interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, "1", 2).
+				Return(&interfaces.ModelRevision{Revision: 2, Content: content}, nil)
+			mms.EXPECT().GetMetricModelByModelID(gomock.Any(), "1").Return(testMetricModel, nil)
+
+			url := "/api/mdl-data-model/v1/metric-models/1/revisions/2/rollback"
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			So(w.Result().StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Rollback data view succeed\n", func() {
+			snapshot := interfaces.DataView{
+				SimpleDataView: interfaces.SimpleDataView{
+					ViewID:    "1",
+					ViewName:  "view1",
+					Type:      interfaces.ViewType_Atomic,
+					QueryType: interfaces.QueryType_DSL,
+				},
+			}
+			content, _ := sonic.MarshalString(snapshot)
+
+			mrs.EXPECT().GetModelRevision(gomock.Any(), interfaces.REVISION_MODEL_TYPE_DATA_VIEW, "1", 3).
+				Return(&interfaces.ModelRevision{Revision: 3, Content: content}, nil)
+			dvs.EXPECT().UpdateDataView(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, tx *sql.Tx, view *interfaces.DataView) error {
+					So(view.ViewID, ShouldEqual, "1")
+					So(view.ViewName, ShouldEqual, "view1")
+					So(ctx.Value(interfaces.REVISION_COMMENT_KEY), ShouldEqual, "restore")
+					return nil
+				})
+
+			url := "/api/mdl-data-model/v1/data-views/1/revisions/3/rollback?revision_comment=restore"
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			So(w.Result().StatusCode, ShouldEqual, http.StatusNoContent)
+		})
+
+		Convey("Rollback objective model succeed\n", func() {
+			content, _ := sonic.MarshalString(testObjectiveModel)
+
+			mrs.EXPECT().GetModelRevision(gomock.Any(), interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL, "1", 1).
+				Return(&interfaces.ModelRevision{Revision: 1, Content: content}, nil)
+			oms.EXPECT().CheckObjectiveModelExistByID(gomock.Any(), "1").Return("16", true, nil)
+			oms.EXPECT().UpdateObjectiveModel(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, tx *sql.Tx, model interfaces.ObjectiveModel) error {
+					So(model.ModelID, ShouldEqual, "1")
+					So(model.Task, ShouldNotBeNil)
+					return nil
+				})
+
+			url := "/api/mdl-data-model/v1/objective-models/1/revisions/1/rollback"
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			So(w.Result().StatusCode, ShouldEqual, http.StatusNoContent)
+		})
+
+		Convey("Rollback failed, because revision is invalid\n", func() {
+			url := "/api/mdl-data-model/v1/metric-models/1/revisions/v1/rollback"
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			So(w.Result().StatusCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Rollback failed, because revision not found\n", func() {
+			mrs.EXPECT().GetModelRevision(gomock.Any(), interfaces.REVISION_MODEL_TYPE_DATA_VIEW, "1", 5).
+				Return(nil, rest.NewHTTPError(testCtx, http.StatusNotFound, derrors.DataModel_ModelRevision_RevisionNotFound))
+
+			url := "/api/mdl-data-model/v1/data-views/1/revisions/5/rollback"
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			So(w.Result().StatusCode, ShouldEqual, http.StatusNotFound)
+		})
+	})
+}
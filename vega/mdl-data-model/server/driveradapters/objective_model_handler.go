@@ -394,6 +394,16 @@ func (r *restHandler) GetObjectiveModels(c *gin.Context, visitor rest.Visitor) {
 	//解析字符串 转换为数组
 	modelIDs := common.StringToStringSlice(modelIDsStr)
 
+	// 可指定模型的历史版本, 用于复现报表
+	revision, err := validatePinnedRevision(ctx, c.Query("revision"), modelIDs)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	ctx = context.WithValue(ctx, interfaces.MODEL_REVISION_KEY, revision)
+
 	// 获取目标模型的详细信息
 	result, err := r.oms.GetObjectiveModels(ctx, modelIDs)
 	if err != nil {
@@ -410,6 +420,65 @@ func (r *restHandler) GetObjectiveModels(c *gin.Context, visitor rest.Visitor) {
 	rest.ReplyOK(c, http.StatusOK, result)
 }
 
+// 获取目标模型的燃烧率告警状态（内部）
+func (r *restHandler) ListObjectiveAlertsByIn(c *gin.Context) {
+	logger.Debug("Handler ListObjectiveAlertsByIn Start")
+	visitor := GenerateVisitor(c)
+	r.ListObjectiveAlerts(c, visitor)
+}
+
+// 获取目标模型的燃烧率告警状态（外部）
+func (r *restHandler) ListObjectiveAlertsByEx(c *gin.Context) {
+	logger.Debug("Handler ListObjectiveAlertsByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"获取目标模型告警状态", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.ListObjectiveAlerts(c, visitor)
+}
+
+// 获取目标模型的燃烧率告警状态，每个策略下的每个序列一条
+func (r *restHandler) ListObjectiveAlerts(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler ListObjectiveAlerts Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"获取目标模型告警状态", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	// 设置 trace 的相关 api 的属性
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	// 与按 id 获取目标模型共用路由参数，此处只支持单个 id
+	modelID := c.Param("model_ids")
+	span.SetAttributes(attr.Key("model_id").String(modelID))
+
+	alerts, err := r.oms.ListObjectiveAlerts(ctx, modelID)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		// 设置 trace 的错误信息的 attributes
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	result := map[string]any{"entries": alerts, "total_count": len(alerts)}
+
+	span.SetStatus(codes.Ok, "")
+	logger.Debug("Handler ListObjectiveAlerts Success")
+	rest.ReplyOK(c, http.StatusOK, result)
+}
+
 // 修改目标模型(内部)
 func (r *restHandler) UpdateObjectiveModelByIn(c *gin.Context) {
 	logger.Debug("Handler UpdateObjectiveModelByIn Start")
@@ -455,6 +524,20 @@ func (r *restHandler) UpdateObjectiveModel(c *gin.Context, visitor rest.Visitor)
 	modelID := c.Param("model_id")
 	span.SetAttributes(attr.Key("model_id").String(modelID))
 
+	// 修改时可填写版本说明, 随修改后的定义记录到历史版本中
+	revisionComment := c.Query("revision_comment")
+	if err := validateRevisionComment(ctx, revisionComment); err != nil {
+		httpErr := err.(*rest.HTTPError)
+
+		audit.NewWarnLogWithError(audit.OPERATION, audit.UPDATE, audit.TransforOperator(visitor),
+			GenerateObjectiveModelAuditObject(modelID, ""), &httpErr.BaseError)
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	ctx = context.WithValue(ctx, interfaces.REVISION_COMMENT_KEY, revisionComment)
+
 	//接收绑定参数
 	objectiveModel := interfaces.ObjectiveModel{}
 	err := c.ShouldBindJSON(&objectiveModel)
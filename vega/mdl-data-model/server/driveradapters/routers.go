@@ -25,7 +25,10 @@ import (
 	"data-model/logics/data_dict"
 	"data-model/logics/data_view"
 	"data-model/logics/event_model"
+	"data-model/logics/lineage"
 	"data-model/logics/metric_model"
+	"data-model/logics/model_bundle"
+	"data-model/logics/model_revision"
 	"data-model/logics/objective_model"
 	"data-model/logics/trace_model"
 	"data-model/version"
@@ -42,14 +45,18 @@ type restHandler struct {
 	dcs        interfaces.DataConnectionService
 	dds        interfaces.DataDictService
 	ddis       interfaces.DataDictItemsService
+	dqrs       interfaces.DataQualityRuleService
 	dvs        interfaces.DataViewService
 	dvgs       interfaces.DataViewGroupService
 	dvms       interfaces.DataViewMonitorService
 	dvrcs      interfaces.DataViewRowColumnRuleService
 	ems        interfaces.EventModelService
+	ls         interfaces.LineageService
+	mbs        interfaces.ModelBundleService
 	mms        interfaces.MetricModelService
 	mmts       interfaces.MetricModelTaskService
 	mmgs       interfaces.MetricModelGroupService
+	mrs        interfaces.ModelRevisionService
 	oms        interfaces.ObjectiveModelService
 	tms        interfaces.TraceModelService
 }
@@ -61,14 +68,18 @@ func NewRestHandler(appSetting *common.AppSetting) RestHandler {
 		dcs:        data_connection.NewDataConnectionService(appSetting),
 		dds:        data_dict.NewDataDictService(appSetting),
 		ddis:       data_dict.NewDataDictItemService(appSetting),
+		dqrs:       data_view.NewDataQualityRuleService(appSetting),
 		dvs:        data_view.NewDataViewService(appSetting),
 		dvgs:       data_view.NewDataViewGroupService(appSetting),
 		dvms:       worker.NewDataViewMonitorService(appSetting),
 		dvrcs:      data_view.NewDataViewRowColumnRuleService(appSetting),
 		ems:        event_model.NewEventModelService(appSetting),
+		ls:         lineage.NewLineageService(appSetting),
+		mbs:        model_bundle.NewModelBundleService(appSetting),
 		mms:        metric_model.NewMetricModelService(appSetting),
 		mmts:       metric_model.NewMetricModelTaskService(appSetting),
 		mmgs:       metric_model.NewMetricModelGroupService(appSetting),
+		mrs:        model_revision.NewModelRevisionService(appSetting),
 		oms:        objective_model.NewObjectiveModelService(appSetting),
 		tms:        trace_model.NewTraceModelService(appSetting),
 	}
@@ -98,10 +109,20 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		apiV1.GET("/metric-models/:model_ids", r.GetMetricModelsByEx)
 		apiV1.GET("/metric-models/:model_ids/fields", r.GetMetricModelSourceFields)      // 路径参数用ids，实际上只支持单个
 		apiV1.GET("/metric-models/:model_ids/order_fields", r.GetMetricModelOrderFields) // 支持多个
+		// 指标模型版本
+		apiV1.GET("/metric-models/:model_ids/revisions", r.ListModelRevisionsByEx(interfaces.REVISION_MODEL_TYPE_METRIC_MODEL))
+		apiV1.GET("/metric-models/:model_ids/revisions/diff", r.DiffModelRevisionsByEx(interfaces.REVISION_MODEL_TYPE_METRIC_MODEL))
+		apiV1.GET("/metric-models/:model_ids/revisions/:revision", r.GetModelRevisionByEx(interfaces.REVISION_MODEL_TYPE_METRIC_MODEL))
+		apiV1.POST("/metric-models/:model_id/revisions/:revision/rollback", r.RollbackModelRevisionByEx(interfaces.REVISION_MODEL_TYPE_METRIC_MODEL))
 
 		// 指标模型持久化任务
 		apiV1.GET("/metric-tasks/:task_id", r.GetMetricTaskByEx)
 		apiV1.PUT("/metric-tasks/:task_id/attr", r.UpdateMetricTaskPlanTimeByEx)
+		// 持久化任务的补数任务
+		apiV1.POST("/metric-task-backfills", r.verifyJsonContentTypeMiddleWare(), r.CreateMetricTaskBackfillByEx)
+		apiV1.GET("/metric-task-backfills", r.ListMetricTaskBackfillsByEx)
+		apiV1.GET("/metric-task-backfills/:backfill_id", r.GetMetricTaskBackfillByEx)
+		apiV1.POST("/metric-task-backfills/:backfill_id/cancel", r.CancelMetricTaskBackfillByEx)
 
 		// event model
 		apiV1.POST("/event-models", r.verifyJsonContentTypeMiddleWare(), r.CreateEventModelByEx)                //新增事件模型
@@ -120,6 +141,11 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		apiV1.GET("/data-views", r.ListDataViewsByEx)
 		// 路径参数用view_id，实际上是批量接口，写view_ids gin框架会报错
 		apiV1.PUT("/data-views/:view_id/attrs/:fields", r.verifyJsonContentTypeMiddleWare(), r.UpdateDataViewAttrFields)
+		// 数据视图版本
+		apiV1.GET("/data-views/:view_ids/revisions", r.ListModelRevisionsByEx(interfaces.REVISION_MODEL_TYPE_DATA_VIEW))
+		apiV1.GET("/data-views/:view_ids/revisions/diff", r.DiffModelRevisionsByEx(interfaces.REVISION_MODEL_TYPE_DATA_VIEW))
+		apiV1.GET("/data-views/:view_ids/revisions/:revision", r.GetModelRevisionByEx(interfaces.REVISION_MODEL_TYPE_DATA_VIEW))
+		apiV1.POST("/data-views/:view_id/revisions/:revision/rollback", r.RollbackModelRevisionByEx(interfaces.REVISION_MODEL_TYPE_DATA_VIEW))
 		// 数据视图分组
 		apiV1.POST("/data-view-groups", r.verifyJsonContentTypeMiddleWare(), r.CreateDataViewGroup)
 		apiV1.DELETE("/data-view-groups/:group_id", r.DeleteDataViewGroup)
@@ -134,6 +160,22 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		apiV1.GET("/data-view-row-column-rules/:rule_ids", r.GetDataViewRowColumnRulesByEx)
 		apiV1.GET("/data-view-row-column-rules", r.ListDataViewRowColumnRulesByEx)
 
+		// 数据质量规则
+		apiV1.POST("/data-quality-rules", r.verifyJsonContentTypeMiddleWare(), r.CreateDataQualityRulesByEx)
+		apiV1.DELETE("/data-quality-rules/:rule_ids", r.DeleteDataQualityRulesByEx)
+		apiV1.PUT("/data-quality-rules/:rule_id", r.verifyJsonContentTypeMiddleWare(), r.UpdateDataQualityRuleByEx)
+		apiV1.GET("/data-quality-rules/:rule_ids", r.GetDataQualityRulesByEx)
+		apiV1.GET("/data-quality-rules", r.ListDataQualityRulesByEx)
+		apiV1.GET("/data-quality-rules/:rule_ids/results", r.ListDataQualityResultsByEx) // 路径参数用ids，实际上只支持单个
+		apiV1.GET("/data-views/:view_ids/data-quality", r.GetDataQualitySummariesByEx)
+
+		// 血缘与影响分析
+		apiV1.GET("/lineage", r.GetLineageByEx)
+
+		// 模型包导入导出, 导入支持 json 和 yaml 请求体
+		apiV1.POST("/model-bundles/export", r.verifyJsonContentTypeMiddleWare(), r.ExportModelBundleByEx)
+		apiV1.POST("/model-bundles/import", r.ImportModelBundleByEx)
+
 		// 扫描数据源
 		// apiV1.POST("/data-source-scan", r.ScanDataSource)
 		// 获取所有数据源，数据源信息包含扫描记录
@@ -172,8 +214,14 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		apiV1.POST("/objective-models", r.verifyJsonContentTypeMiddleWare(), r.CreateObjectiveModelsByEx)
 		apiV1.GET("/objective-models", r.ListObjectiveModelsByEx)
 		apiV1.GET("/objective-models/:model_ids", r.GetObjectiveModelsByEx)
+		apiV1.GET("/objective-models/:model_ids/alerts", r.ListObjectiveAlertsByEx)
 		apiV1.PUT("/objective-models/:model_id", r.verifyJsonContentTypeMiddleWare(), r.UpdateObjectiveModelByEx)
 		apiV1.DELETE("/objective-models/:model_ids", r.DeleteObjectiveModels)
+		// 目标模型版本
+		apiV1.GET("/objective-models/:model_ids/revisions", r.ListModelRevisionsByEx(interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL))
+		apiV1.GET("/objective-models/:model_ids/revisions/diff", r.DiffModelRevisionsByEx(interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL))
+		apiV1.GET("/objective-models/:model_ids/revisions/:revision", r.GetModelRevisionByEx(interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL))
+		apiV1.POST("/objective-models/:model_id/revisions/:revision/rollback", r.RollbackModelRevisionByEx(interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL))
 
 		// 临时代码，为防止因tag-mgmt服务不存在，而导致的前端报错503
 		apiV1.GET("/object-tags", r.GetObjectTag)
@@ -189,9 +237,18 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		apiInV1.PUT("/metric-models/:model_id", r.verifyJsonContentTypeMiddleWare(), r.UpdateMetricModelByIn)
 		apiInV1.GET("/metric-models", r.ListMetricModelsByIn)
 		apiInV1.GET("/metric-models/:model_ids", r.GetMetricModelsByIn)
+		apiInV1.GET("/metric-models/:model_ids/revisions", r.ListModelRevisionsByIn(interfaces.REVISION_MODEL_TYPE_METRIC_MODEL))
+		apiInV1.GET("/metric-models/:model_ids/revisions/diff", r.DiffModelRevisionsByIn(interfaces.REVISION_MODEL_TYPE_METRIC_MODEL))
+		apiInV1.GET("/metric-models/:model_ids/revisions/:revision", r.GetModelRevisionByIn(interfaces.REVISION_MODEL_TYPE_METRIC_MODEL))
+		apiInV1.POST("/metric-models/:model_id/revisions/:revision/rollback", r.RollbackModelRevisionByIn(interfaces.REVISION_MODEL_TYPE_METRIC_MODEL))
 		// 指标模型持久化任务
 		apiInV1.GET("/metric-tasks/:task_id", r.GetMetricTaskByIn)
 		apiInV1.PUT("/metric-tasks/:task_id/attr", r.UpdateMetricTaskPlanTimeByIn)
+		// 持久化任务的补数任务
+		apiInV1.POST("/metric-task-backfills", r.verifyJsonContentTypeMiddleWare(), r.CreateMetricTaskBackfillByIn)
+		apiInV1.GET("/metric-task-backfills", r.ListMetricTaskBackfillsByIn)
+		apiInV1.GET("/metric-task-backfills/:backfill_id", r.GetMetricTaskBackfillByIn)
+		apiInV1.POST("/metric-task-backfills/:backfill_id/cancel", r.CancelMetricTaskBackfillByIn)
 
 		// 数据视图
 		apiInV1.POST("/data-views", r.verifyJsonContentTypeMiddleWare(), r.HandleDataViewPostOverrideByIn)
@@ -199,6 +256,10 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		apiInV1.PUT("/data-views/:view_id", r.verifyJsonContentTypeMiddleWare(), r.UpdateDataViewByIn)
 		apiInV1.GET("/data-views/:view_ids", r.GetDataViewsByIn)
 		apiInV1.GET("/data-views", r.ListDataViewsByIn)
+		apiInV1.GET("/data-views/:view_ids/revisions", r.ListModelRevisionsByIn(interfaces.REVISION_MODEL_TYPE_DATA_VIEW))
+		apiInV1.GET("/data-views/:view_ids/revisions/diff", r.DiffModelRevisionsByIn(interfaces.REVISION_MODEL_TYPE_DATA_VIEW))
+		apiInV1.GET("/data-views/:view_ids/revisions/:revision", r.GetModelRevisionByIn(interfaces.REVISION_MODEL_TYPE_DATA_VIEW))
+		apiInV1.POST("/data-views/:view_id/revisions/:revision/rollback", r.RollbackModelRevisionByIn(interfaces.REVISION_MODEL_TYPE_DATA_VIEW))
 
 		// 数据视图行列权限
 		// apiInV1.POST("/data-view-row-column-rules", r.verifyJsonContentTypeMiddleWare(), r.CreateDataViewRowColumnRulesByIn)
@@ -207,11 +268,34 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		// apiInV1.GET("/data-view-row-column-rules/:rule_ids", r.GetDataViewRowColumnRulesByIn)
 		apiInV1.GET("/data-view-row-column-rules", r.ListDataViewRowColumnRulesByIn)
 
+		// 数据质量规则
+		apiInV1.POST("/data-quality-rules", r.verifyJsonContentTypeMiddleWare(), r.CreateDataQualityRulesByIn)
+		apiInV1.DELETE("/data-quality-rules/:rule_ids", r.DeleteDataQualityRulesByIn)
+		apiInV1.PUT("/data-quality-rules/:rule_id", r.verifyJsonContentTypeMiddleWare(), r.UpdateDataQualityRuleByIn)
+		apiInV1.GET("/data-quality-rules/:rule_ids", r.GetDataQualityRulesByIn)
+		apiInV1.GET("/data-quality-rules", r.ListDataQualityRulesByIn)
+		apiInV1.GET("/data-quality-rules/:rule_ids/results", r.ListDataQualityResultsByIn) // 路径参数用ids，实际上只支持单个
+		apiInV1.GET("/data-views/:view_ids/data-quality", r.GetDataQualitySummariesByIn)
+
+		// 血缘与影响分析, 本体等外部服务通过 owners 接口登记其模型的血缘
+		apiInV1.GET("/lineage", r.GetLineageByIn)
+		apiInV1.PUT("/lineage/owners/:owner_type/*owner_id", r.verifyJsonContentTypeMiddleWare(), r.ReplaceLineageEdgesByIn)
+		apiInV1.DELETE("/lineage/owners/:owner_type/*owner_id", r.DeleteLineageEdgesByIn)
+
+		// 模型包导入导出
+		apiInV1.POST("/model-bundles/export", r.verifyJsonContentTypeMiddleWare(), r.ExportModelBundleByIn)
+		apiInV1.POST("/model-bundles/import", r.ImportModelBundleByIn)
+
 		// 目标模型
 		apiInV1.POST("/objective-models", r.verifyJsonContentTypeMiddleWare(), r.CreateObjectiveModelsByIn)
 		apiInV1.GET("/objective-models", r.ListObjectiveModelsByIn)
 		apiInV1.GET("/objective-models/:model_ids", r.GetObjectiveModelsByIn)
+		apiInV1.GET("/objective-models/:model_ids/alerts", r.ListObjectiveAlertsByIn)
 		apiInV1.PUT("/objective-models/:model_id", r.verifyJsonContentTypeMiddleWare(), r.UpdateObjectiveModelByIn)
+		apiInV1.GET("/objective-models/:model_ids/revisions", r.ListModelRevisionsByIn(interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL))
+		apiInV1.GET("/objective-models/:model_ids/revisions/diff", r.DiffModelRevisionsByIn(interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL))
+		apiInV1.GET("/objective-models/:model_ids/revisions/:revision", r.GetModelRevisionByIn(interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL))
+		apiInV1.POST("/objective-models/:model_id/revisions/:revision/rollback", r.RollbackModelRevisionByIn(interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL))
 
 		// event model
 		apiInV1.POST("/event-models", r.verifyJsonContentTypeMiddleWare(), r.CreateEventModelByIn)                //新增事件模型
@@ -1124,8 +1124,28 @@ func convertRelatedLogConfig(ctx context.Context, reqModels []interfaces.TraceMo
 						derrors.DataModel_InternalError_UnmarshalDataFailed).WithErrorDetails(errDetails)
 				}
 				reqModels[i].RelatedLogConfig = conf
+			case interfaces.SOURCE_TYPE_DATA_CONNECTION:
+				// 基于数据连接的关联日志复用span的数据连接, 要求span也来自数据连接
+				if reqModels[i].SpanSourceType != interfaces.SOURCE_TYPE_DATA_CONNECTION {
+					errDetails := "related_log_source_type " + interfaces.SOURCE_TYPE_DATA_CONNECTION +
+						" is only allowed when span_source_type is " + interfaces.SOURCE_TYPE_DATA_CONNECTION
+					o11y.Error(ctx, errDetails)
+					return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_TraceModel_InvalidParameter_RelatedLogSourceType).
+						WithErrorDetails(errDetails)
+				}
+
+				conf := interfaces.RelatedLogConfigWithDataConnection{}
+				err = sonic.Unmarshal(b, &conf)
+				if err != nil {
+					errDetails := fmt.Sprintf("Field related_log_config cannot be unmarshaled to RelatedLogConfigWithDataConnection, err: %v", err.Error())
+					o11y.Error(ctx, errDetails)
+					return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+						derrors.DataModel_InternalError_UnmarshalDataFailed).WithErrorDetails(errDetails)
+				}
+				reqModels[i].RelatedLogConfig = conf
 			default:
-				errDetails := "related_log_source_type is invalid, valid related_log_source_type is " + interfaces.SOURCE_TYPE_DATA_VIEW
+				errDetails := "related_log_source_type is invalid, valid related_log_source_type is " + interfaces.SOURCE_TYPE_DATA_VIEW +
+					" or " + interfaces.SOURCE_TYPE_DATA_CONNECTION
 				o11y.Error(ctx, errDetails)
 				return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_TraceModel_InvalidParameter_RelatedLogSourceType).
 					WithErrorDetails(errDetails)
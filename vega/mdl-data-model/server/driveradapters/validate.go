@@ -55,6 +55,10 @@ var objectNameErrorCode = map[string][]string{
 		derrors.DataModel_DataViewGroup_NullParameter_GroupName,
 		derrors.DataModel_DataViewGroup_LengthExceeded_GroupName,
 	},
+	interfaces.MODULE_TYPE_DATA_QUALITY_RULE: {
+		derrors.DataModel_DataQualityRule_NullParameter_RuleName,
+		derrors.DataModel_DataQualityRule_LengthExceeded_RuleName,
+	},
 	interfaces.MODULE_TYPE_DATA_VIEW_ROW_COLUMN_RULE: {
 		derrors.DataModel_DataViewRowColumnRule_NullParameter_RuleName,
 		derrors.DataModel_DataViewRowColumnRule_LengthExceeded_RuleName,
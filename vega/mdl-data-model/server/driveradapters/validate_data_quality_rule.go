@@ -0,0 +1,196 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	libCommon "github.com/kweaver-ai/kweaver-go-lib/common"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+// 数据质量规则必要创建参数的非空校验和合法性校验
+func validateDataQualityRule(ctx context.Context, rule *interfaces.DataQualityRule) error {
+	// 校验规则名称
+	err := validateObjectName(ctx, rule.RuleName, interfaces.MODULE_TYPE_DATA_QUALITY_RULE)
+	if err != nil {
+		return err
+	}
+
+	if rule.ViewID == "" {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_NullParameter_ViewID).
+			WithErrorDetails("The data quality rule view id is null")
+	}
+
+	// 校验标签
+	err = validateObjectTags(ctx, rule.Tags)
+	if err != nil {
+		return err
+	}
+
+	// 去掉tag前后空格以及数组去重
+	rule.Tags = libCommon.TagSliceTransform(rule.Tags)
+
+	// 校验备注
+	err = validateObjectComment(ctx, rule.Comment)
+	if err != nil {
+		return err
+	}
+
+	if rule.Kind == "" {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_NullParameter_Kind).
+			WithErrorDetails("The data quality rule kind is null")
+	}
+	if _, ok := interfaces.DataQualityKindMap[rule.Kind]; !ok {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_InvalidParameter_Kind).
+			WithErrorDetails(fmt.Sprintf("Unsupported data quality rule kind '%s'", rule.Kind))
+	}
+
+	// 校验检查配置
+	err = validateDataQualityRuleConfig(ctx, rule.Kind, &rule.Config)
+	if err != nil {
+		return err
+	}
+
+	// 校验执行频率
+	err = validateDataQualityRuleSchedule(ctx, rule.Schedule)
+	if err != nil {
+		return err
+	}
+
+	// 发送事件时需要指定事件写入的索引库
+	if rule.EmitEvent && (rule.EventConfig == nil || rule.EventConfig.IndexBase == "") {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_InvalidParameter_EventConfig).
+			WithErrorDetails("event_config.index_base is required when emit_event is true")
+	}
+
+	return nil
+}
+
+// 按规则类型校验检查配置
+func validateDataQualityRuleConfig(ctx context.Context, kind string, cfg *interfaces.DataQualityRuleConfig) error {
+	invalidConfig := func(details string) error {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_InvalidParameter_Config).
+			WithErrorDetails(details)
+	}
+
+	// 字段个数
+	switch kind {
+	case interfaces.DATA_QUALITY_KIND_NOT_NULL, interfaces.DATA_QUALITY_KIND_ACCEPTED_VALUES,
+		interfaces.DATA_QUALITY_KIND_FRESHNESS:
+		if len(cfg.Fields) != 1 || cfg.Fields[0] == "" {
+			return invalidConfig(fmt.Sprintf("The %s rule requires exactly one field", kind))
+		}
+	case interfaces.DATA_QUALITY_KIND_UNIQUE:
+		if len(cfg.Fields) == 0 || len(cfg.Fields) > interfaces.MAX_DATA_QUALITY_UNIQUE_FIELDS {
+			return invalidConfig(fmt.Sprintf("The unique rule requires 1 to %d fields", interfaces.MAX_DATA_QUALITY_UNIQUE_FIELDS))
+		}
+		fieldMap := make(map[string]struct{}, len(cfg.Fields))
+		for _, field := range cfg.Fields {
+			if field == "" {
+				return invalidConfig("The unique rule field is null")
+			}
+			if _, ok := fieldMap[field]; ok {
+				return invalidConfig(fmt.Sprintf("The unique rule field '%s' is duplicated", field))
+			}
+			fieldMap[field] = struct{}{}
+		}
+	default:
+		if len(cfg.Fields) != 0 {
+			return invalidConfig(fmt.Sprintf("The %s rule does not accept fields", kind))
+		}
+	}
+
+	if cfg.MinRatio != nil && (*cfg.MinRatio < 0 || *cfg.MinRatio > 1) {
+		return invalidConfig("min_ratio should be between 0 and 1")
+	}
+
+	if cfg.SampleSize < 0 || cfg.SampleSize > interfaces.MAX_DATA_QUALITY_SAMPLE_SIZE {
+		return invalidConfig(fmt.Sprintf("sample_size should be between 0 and %d", interfaces.MAX_DATA_QUALITY_SAMPLE_SIZE))
+	}
+
+	// 检查范围
+	err := validateCond(ctx, cfg.Filters)
+	if err != nil {
+		return err
+	}
+	if cfg.TimeWindow != "" {
+		err = validateDuration(ctx, cfg.TimeWindow, common.DurationDayHourMinuteRE,
+			derrors.DataModel_DataQualityRule_InvalidParameter_Config, "time_window", true)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch kind {
+	case interfaces.DATA_QUALITY_KIND_ACCEPTED_VALUES:
+		if cfg.DictID == "" {
+			return invalidConfig("The accepted_values rule requires dict_id")
+		}
+	case interfaces.DATA_QUALITY_KIND_FRESHNESS:
+		if cfg.MaxDelay == "" {
+			return invalidConfig("The freshness rule requires max_delay")
+		}
+		err = validateDuration(ctx, cfg.MaxDelay, common.DurationDayHourMinuteRE,
+			derrors.DataModel_DataQualityRule_InvalidParameter_Config, "max_delay", true)
+		if err != nil {
+			return err
+		}
+	case interfaces.DATA_QUALITY_KIND_ROW_COUNT:
+		if cfg.MinCount == nil && cfg.MaxCount == nil {
+			return invalidConfig("The row_count rule requires min_count or max_count")
+		}
+		if (cfg.MinCount != nil && *cfg.MinCount < 0) || (cfg.MaxCount != nil && *cfg.MaxCount < 0) {
+			return invalidConfig("min_count and max_count should not be negative")
+		}
+		if cfg.MinCount != nil && cfg.MaxCount != nil && *cfg.MinCount > *cfg.MaxCount {
+			return invalidConfig("min_count should not be greater than max_count")
+		}
+	case interfaces.DATA_QUALITY_KIND_CUSTOM:
+		if cfg.Condition == nil {
+			return invalidConfig("The custom rule requires condition")
+		}
+		err = validateCond(ctx, cfg.Condition)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 执行频率与持久化任务的校验规则一致
+func validateDataQualityRuleSchedule(ctx context.Context, schedule interfaces.Schedule) error {
+	if schedule.Type != interfaces.SCHEDULE_TYPE_FIXED && schedule.Type != interfaces.SCHEDULE_TYPE_CRON {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_InvalidParameter_Schedule).
+			WithErrorDetails(fmt.Sprintf("Unsupported schedule type '%s'", schedule.Type))
+	}
+
+	if schedule.Expression == "" {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_InvalidParameter_Schedule).
+			WithErrorDetails("The schedule expression is null")
+	}
+
+	if schedule.Type == interfaces.SCHEDULE_TYPE_FIXED {
+		return validateDuration(ctx, schedule.Expression, common.DurationDayHourMinuteRE,
+			derrors.DataModel_DataQualityRule_InvalidParameter_Schedule, "schedule expression ", true)
+	}
+
+	// cron 表达式的校验。只支持6位，不支持年的指定。
+	_, err := CronParser.Parse(schedule.Expression)
+	if err != nil {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_InvalidParameter_Schedule).
+			WithErrorDetails(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"testing"
+
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+func Test_ValidateDataQualityRule(t *testing.T) {
+	Convey("Test validateDataQualityRule", t, func() {
+		newRule := func() *interfaces.DataQualityRule {
+			return &interfaces.DataQualityRule{
+				RuleName: "not null",
+				ViewID:   "v1",
+				Kind:     interfaces.DATA_QUALITY_KIND_NOT_NULL,
+				Config: interfaces.DataQualityRuleConfig{
+					Fields: []string{"a"},
+				},
+				Schedule: interfaces.Schedule{Type: interfaces.SCHEDULE_TYPE_FIXED, Expression: "5m"},
+				Tags:     []string{" t1 ", "t1"},
+			}
+		}
+
+		Convey("Validate failed, caused by empty view id", func() {
+			rule := newRule()
+			rule.ViewID = ""
+
+			err := validateDataQualityRule(testCtx, rule)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataQualityRule_NullParameter_ViewID)
+		})
+
+		Convey("Validate failed, caused by unsupported kind", func() {
+			rule := newRule()
+			rule.Kind = "xxx"
+
+			err := validateDataQualityRule(testCtx, rule)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataQualityRule_InvalidParameter_Kind)
+		})
+
+		Convey("Validate failed, caused by invalid schedule", func() {
+			rule := newRule()
+			rule.Schedule = interfaces.Schedule{Type: interfaces.SCHEDULE_TYPE_CRON, Expression: "* *"}
+
+			err := validateDataQualityRule(testCtx, rule)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataQualityRule_InvalidParameter_Schedule)
+		})
+
+		Convey("Validate failed, caused by emit event without index base", func() {
+			rule := newRule()
+			rule.EmitEvent = true
+
+			err := validateDataQualityRule(testCtx, rule)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataQualityRule_InvalidParameter_EventConfig)
+		})
+
+		Convey("Validate succeed", func() {
+			rule := newRule()
+
+			err := validateDataQualityRule(testCtx, rule)
+			So(err, ShouldBeNil)
+			So(rule.Tags, ShouldResemble, []string{"t1"})
+		})
+	})
+}
+
+func Test_ValidateDataQualityRuleConfig(t *testing.T) {
+	Convey("Test validateDataQualityRuleConfig", t, func() {
+		invalidCode := derrors.DataModel_DataQualityRule_InvalidParameter_Config
+		ratio := 1.5
+		minCount, maxCount := int64(10), int64(1)
+
+		cases := []struct {
+			name string
+			kind string
+			cfg  interfaces.DataQualityRuleConfig
+		}{
+			{"not_null without field", interfaces.DATA_QUALITY_KIND_NOT_NULL, interfaces.DataQualityRuleConfig{}},
+			{"unique with duplicate fields", interfaces.DATA_QUALITY_KIND_UNIQUE,
+				interfaces.DataQualityRuleConfig{Fields: []string{"a", "a"}}},
+			{"row_count with fields", interfaces.DATA_QUALITY_KIND_ROW_COUNT,
+				interfaces.DataQualityRuleConfig{Fields: []string{"a"}, MinCount: &minCount}},
+			{"min_ratio out of range", interfaces.DATA_QUALITY_KIND_NOT_NULL,
+				interfaces.DataQualityRuleConfig{Fields: []string{"a"}, MinRatio: &ratio}},
+			{"accepted_values without dict", interfaces.DATA_QUALITY_KIND_ACCEPTED_VALUES,
+				interfaces.DataQualityRuleConfig{Fields: []string{"a"}}},
+			{"freshness without max_delay", interfaces.DATA_QUALITY_KIND_FRESHNESS,
+				interfaces.DataQualityRuleConfig{Fields: []string{"a"}}},
+			{"row_count without bounds", interfaces.DATA_QUALITY_KIND_ROW_COUNT, interfaces.DataQualityRuleConfig{}},
+			{"row_count with min greater than max", interfaces.DATA_QUALITY_KIND_ROW_COUNT,
+				interfaces.DataQualityRuleConfig{MinCount: &minCount, MaxCount: &maxCount}},
+			{"custom without condition", interfaces.DATA_QUALITY_KIND_CUSTOM, interfaces.DataQualityRuleConfig{}},
+		}
+
+		for _, c := range cases {
+			Convey("Validate failed, caused by "+c.name, func() {
+				cfg := c.cfg
+				err := validateDataQualityRuleConfig(testCtx, c.kind, &cfg)
+				So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, invalidCode)
+			})
+		}
+
+		Convey("Validate succeed", func() {
+			cfg := interfaces.DataQualityRuleConfig{
+				Fields:     []string{"a", "b"},
+				TimeWindow: "1d",
+				SampleSize: 1000,
+			}
+			err := validateDataQualityRuleConfig(testCtx, interfaces.DATA_QUALITY_KIND_UNIQUE, &cfg)
+			So(err, ShouldBeNil)
+		})
+	})
+}
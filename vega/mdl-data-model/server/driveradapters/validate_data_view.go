@@ -20,6 +20,7 @@ import (
 	"data-model/common"
 	derrors "data-model/errors"
 	"data-model/interfaces"
+	dcond "data-model/interfaces/condition"
 	dtype "data-model/interfaces/data_type"
 )
 
@@ -314,8 +315,14 @@ func validateDataViewRowColumnRule(ctx context.Context, rule *interfaces.DataVie
 			WithErrorDetails("The data view row column rule fields is null")
 	}
 
+	// 校验字段脱敏配置
+	err = validateFieldMasks(ctx, rule)
+	if err != nil {
+		return err
+	}
+
 	// 校验行过滤条件
-	err = validateCond(ctx, rule.RowFilters)
+	err = validateRowFilters(ctx, rule.RowFilters)
 	if err != nil {
 		return err
 	}
@@ -323,6 +330,120 @@ func validateDataViewRowColumnRule(ctx context.Context, rule *interfaces.DataVie
 	return nil
 }
 
+// 校验字段脱敏配置, 脱敏的字段需在规则的列中, 每个字段只能配置一个脱敏方式
+func validateFieldMasks(ctx context.Context, rule *interfaces.DataViewRowColumnRule) error {
+	fieldsMap := make(map[string]struct{}, len(rule.Fields))
+	for _, field := range rule.Fields {
+		fieldsMap[field] = struct{}{}
+	}
+
+	maskedFields := make(map[string]struct{}, len(rule.FieldMasks))
+	for i := range rule.FieldMasks {
+		mask := &rule.FieldMasks[i]
+		if _, ok := fieldsMap[mask.Field]; !ok {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataViewRowColumnRule_InvalidParameter_FieldMasks).
+				WithErrorDetails(fmt.Sprintf("The masked field '%s' is not one of the rule fields", mask.Field))
+		}
+		if _, ok := maskedFields[mask.Field]; ok {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataViewRowColumnRule_InvalidParameter_FieldMasks).
+				WithErrorDetails(fmt.Sprintf("The field '%s' has more than one mask", mask.Field))
+		}
+		maskedFields[mask.Field] = struct{}{}
+
+		if _, ok := interfaces.FieldMaskTypeMap[mask.Type]; !ok {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataViewRowColumnRule_InvalidParameter_FieldMasks).
+				WithErrorDetails(fmt.Sprintf("The mask type of field '%s' should be one of partial, hash, nullify and bucket, but got '%s'",
+					mask.Field, mask.Type))
+		}
+
+		switch mask.Type {
+		case interfaces.FIELD_MASK_TYPE_PARTIAL:
+			if mask.KeepPrefix < 0 || mask.KeepSuffix < 0 {
+				return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataViewRowColumnRule_InvalidParameter_FieldMasks).
+					WithErrorDetails(fmt.Sprintf("The keep_prefix and keep_suffix of field '%s' can not be negative", mask.Field))
+			}
+			if mask.MaskChar == "" {
+				mask.MaskChar = interfaces.DEFAULT_FIELD_MASK_CHAR
+			}
+			if utf8.RuneCountInString(mask.MaskChar) != 1 {
+				return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataViewRowColumnRule_InvalidParameter_FieldMasks).
+					WithErrorDetails(fmt.Sprintf("The mask_char of field '%s' should be a single character", mask.Field))
+			}
+		case interfaces.FIELD_MASK_TYPE_BUCKET:
+			// 数值分桶和日期分桶二选一
+			if (mask.BucketSize > 0) == (mask.BucketUnit != "") {
+				return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataViewRowColumnRule_InvalidParameter_FieldMasks).
+					WithErrorDetails(fmt.Sprintf("Exactly one of positive bucket_size and bucket_unit should be set for field '%s'", mask.Field))
+			}
+			if mask.BucketUnit != "" {
+				if _, ok := interfaces.FieldMaskBucketUnitMap[mask.BucketUnit]; !ok {
+					return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataViewRowColumnRule_InvalidParameter_FieldMasks).
+						WithErrorDetails(fmt.Sprintf("The bucket_unit of field '%s' should be one of hour, day, month and year, but got '%s'",
+							mask.Field, mask.BucketUnit))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// 校验行过滤条件. 叶子条件的 value_from 可以为 user, 此时 value 为调用者的属性名,
+// 只支持等于、不等于、属于、不属于操作, 查询时由 uniquery 替换为调用者的属性值
+func validateRowFilters(ctx context.Context, cfg *interfaces.CondCfg) error {
+	if cfg == nil {
+		return nil
+	}
+
+	switch cfg.Operation {
+	case dcond.OperationAnd, dcond.OperationOr:
+		if len(cfg.SubConds) > dcond.MaxSubCondition {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataView_CountExceeded_Filters).
+				WithErrorDetails(fmt.Sprintf("The number of subConditions exceeds %d", dcond.MaxSubCondition))
+		}
+
+		for _, subCond := range cfg.SubConds {
+			err := validateRowFilters(ctx, subCond)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if cfg.ValueFrom != interfaces.ValueFrom_User {
+		return validateCond(ctx, cfg)
+	}
+
+	if cfg.Name == "" {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_NullParameter_FilterName)
+	}
+
+	switch cfg.Operation {
+	case dcond.OperationEq, dcond.OperationNotEq, dcond.OperationIn, dcond.OperationNotIn:
+	default:
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataViewRowColumnRule_InvalidParameter_RowFilters).
+			WithErrorDetails(fmt.Sprintf("[%s] operation does not support value_from type('%s')", cfg.Operation, cfg.ValueFrom))
+	}
+
+	attr, ok := cfg.Value.(string)
+	if !ok || !isValidUserAttr(attr) {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataViewRowColumnRule_InvalidParameter_RowFilters).
+			WithErrorDetails(fmt.Sprintf("The value of condition on field '%s' should be a user attribute, but got '%v'", cfg.Name, cfg.Value))
+	}
+
+	return nil
+}
+
+// 支持的用户属性, 以及 custom_attr.<key> 形式的自定义属性
+func isValidUserAttr(attr string) bool {
+	if _, ok := interfaces.UserAttrMap[attr]; ok {
+		return true
+	}
+
+	return strings.HasPrefix(attr, interfaces.USER_ATTR_CUSTOM_PREFIX) && len(attr) > len(interfaces.USER_ATTR_CUSTOM_PREFIX)
+}
+
 // 校验字段和字段特征
 func validateViewFields(ctx context.Context, viewFields []*interfaces.ViewField) error {
 	fieldsMap := make(map[string]*interfaces.ViewField)
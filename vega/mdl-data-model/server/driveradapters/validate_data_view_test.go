@@ -553,3 +553,123 @@ func Test_ValidateDataView_ValidateViewID(t *testing.T) {
 		})
 	})
 }
+
+func Test_ValidateDataView_ValidateFieldMasks(t *testing.T) {
+	Convey("Test validateFieldMasks", t, func() {
+		rule := &interfaces.DataViewRowColumnRule{
+			Fields: []string{"phone", "salary", "birthday"},
+		}
+
+		Convey("Validate failed, because masked field is not one of the rule fields", func() {
+			rule.FieldMasks = []interfaces.FieldMask{{Field: "id_card", Type: interfaces.FIELD_MASK_TYPE_HASH}}
+			err := validateFieldMasks(testCtx, rule)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Validate failed, because field has more than one mask", func() {
+			rule.FieldMasks = []interfaces.FieldMask{
+				{Field: "phone", Type: interfaces.FIELD_MASK_TYPE_HASH},
+				{Field: "phone", Type: interfaces.FIELD_MASK_TYPE_NULLIFY},
+			}
+			err := validateFieldMasks(testCtx, rule)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Validate failed, because mask type is invalid", func() {
+			rule.FieldMasks = []interfaces.FieldMask{{Field: "phone", Type: "encrypt"}}
+			err := validateFieldMasks(testCtx, rule)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Validate failed, because mask char is not a single character", func() {
+			rule.FieldMasks = []interfaces.FieldMask{{Field: "phone", Type: interfaces.FIELD_MASK_TYPE_PARTIAL, MaskChar: "**"}}
+			err := validateFieldMasks(testCtx, rule)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Validate failed, because both bucket_size and bucket_unit are set", func() {
+			rule.FieldMasks = []interfaces.FieldMask{{Field: "salary", Type: interfaces.FIELD_MASK_TYPE_BUCKET,
+				BucketSize: 1000, BucketUnit: interfaces.FIELD_MASK_BUCKET_UNIT_DAY}}
+			err := validateFieldMasks(testCtx, rule)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Validate failed, because bucket unit is invalid", func() {
+			rule.FieldMasks = []interfaces.FieldMask{{Field: "birthday", Type: interfaces.FIELD_MASK_TYPE_BUCKET, BucketUnit: "week"}}
+			err := validateFieldMasks(testCtx, rule)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Validate success, and mask char defaults to *", func() {
+			rule.FieldMasks = []interfaces.FieldMask{
+				{Field: "phone", Type: interfaces.FIELD_MASK_TYPE_PARTIAL, KeepPrefix: 3, KeepSuffix: 4},
+				{Field: "salary", Type: interfaces.FIELD_MASK_TYPE_BUCKET, BucketSize: 1000},
+				{Field: "birthday", Type: interfaces.FIELD_MASK_TYPE_BUCKET, BucketUnit: interfaces.FIELD_MASK_BUCKET_UNIT_YEAR},
+			}
+			err := validateFieldMasks(testCtx, rule)
+			So(err, ShouldBeNil)
+			So(rule.FieldMasks[0].MaskChar, ShouldEqual, interfaces.DEFAULT_FIELD_MASK_CHAR)
+		})
+	})
+}
+
+func Test_ValidateDataView_ValidateRowFilters(t *testing.T) {
+	Convey("Test validateRowFilters", t, func() {
+
+		Convey("Validate failed, because operation does not support user value", func() {
+			cfg := &interfaces.CondCfg{
+				Name:        "department",
+				Operation:   dcond.OperationLike,
+				ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_User, Value: interfaces.USER_ATTR_DEPARTMENT_ID},
+			}
+			err := validateRowFilters(testCtx, cfg)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Validate failed, because user attribute is unknown", func() {
+			cfg := &interfaces.CondCfg{
+				Operation: dcond.OperationAnd,
+				SubConds: []*interfaces.CondCfg{
+					{
+						Name:        "department",
+						Operation:   dcond.OperationEq,
+						ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_User, Value: "password"},
+					},
+				},
+			}
+			err := validateRowFilters(testCtx, cfg)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Validate failed, because const condition is invalid", func() {
+			cfg := &interfaces.CondCfg{
+				Name:        "region",
+				Operation:   dcond.OperationEq,
+				ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_User + "x", Value: "east"},
+			}
+			err := validateRowFilters(testCtx, cfg)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Validate success", func() {
+			cfg := &interfaces.CondCfg{
+				Operation: dcond.OperationOr,
+				SubConds: []*interfaces.CondCfg{
+					{
+						Name:        "department",
+						Operation:   dcond.OperationIn,
+						ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_User, Value: interfaces.USER_ATTR_DEPARTMENT_ID},
+					},
+					{
+						Name:      "region",
+						Operation: dcond.OperationEq,
+						ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_User,
+							Value: interfaces.USER_ATTR_CUSTOM_PREFIX + "region"},
+					},
+				},
+			}
+			err := validateRowFilters(testCtx, cfg)
+			So(err, ShouldBeNil)
+		})
+	})
+}
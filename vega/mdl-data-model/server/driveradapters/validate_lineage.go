@@ -0,0 +1,56 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+// 血缘查询参数校验
+func validateLineageQuery(ctx context.Context, nodeType, nodeID, field, direction, depthStr string) (*interfaces.LineageQuery, error) {
+	if _, ok := interfaces.LineageNodeTypeMap[nodeType]; !ok {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_Lineage_InvalidParameter_NodeType).
+			WithErrorDetails(fmt.Sprintf("Unsupported lineage node type '%s'", nodeType))
+	}
+
+	if nodeID == "" {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_Lineage_NullParameter_NodeID).
+			WithErrorDetails("The lineage node id is null")
+	}
+
+	if _, ok := interfaces.LineageDirectionMap[direction]; !ok {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_Lineage_InvalidParameter_Direction).
+			WithErrorDetails(fmt.Sprintf("The lineage direction must be one of upstream, downstream and both, but got '%s'", direction))
+	}
+
+	depth, err := strconv.Atoi(depthStr)
+	if err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_Lineage_InvalidParameter_Depth).
+			WithErrorDetails(err.Error())
+	}
+	if depth < 1 || depth > interfaces.MAX_LINEAGE_DEPTH {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_Lineage_InvalidParameter_Depth).
+			WithErrorDetails(fmt.Sprintf("The lineage depth must be in the range of [1, %d]", interfaces.MAX_LINEAGE_DEPTH))
+	}
+
+	return &interfaces.LineageQuery{
+		Node: interfaces.LineageNode{
+			Type: nodeType,
+			ID:   nodeID,
+		},
+		Field:     field,
+		Direction: direction,
+		Depth:     depth,
+	}, nil
+}
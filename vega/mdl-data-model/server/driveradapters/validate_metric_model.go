@@ -166,6 +166,33 @@ func ValidateMetricModel(ctx context.Context, metricModel *interfaces.MetricMode
 
 			// sql当前只支持日历间隔
 			metricModel.IsCalendarInterval = 1
+		} else if metricModel.QueryType == interfaces.RED {
+			// RED指标的配置在 formula_config 中, 数据源为链路模型
+			if metricModel.FormulaConfig == nil {
+				return false, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_NullParameter_FormulaConfig)
+			}
+			// 把 formula_config转成 REDConfig
+			var redConfig interfaces.REDConfig
+			jsonData, err := sonic.Marshal(metricModel.FormulaConfig)
+			if err != nil {
+				return false, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter_FormulaConfig).
+					WithErrorDetails(fmt.Sprintf("[%s]'s RED Config Marshal error: %s", metricModel.ModelName, err.Error()))
+			}
+			err = sonic.Unmarshal(jsonData, &redConfig)
+			if err != nil {
+				return false, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter_FormulaConfig).
+					WithErrorDetails(fmt.Sprintf("[%s]'s RED Config Unmarshal error: %s", metricModel.ModelName, err.Error()))
+			}
+			if _, ok := interfaces.RED_METRICS[redConfig.Metric]; !ok {
+				return false, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter_FormulaConfig).
+					WithErrorDetails(fmt.Sprintf("[%s]'s red metric %s is not one of [rate, errors, error_rate, duration_p50, duration_p95, duration_p99]",
+						metricModel.ModelName, redConfig.Metric))
+			}
+
+			// FormulaConfig 赋值为 REDConfig
+			metricModel.FormulaConfig = redConfig
+			metricModel.DataSource.Type = interfaces.DATA_SOURCE_TRACE_MODEL
+			metricModel.MeasureField = interfaces.VALUE_FIELD_NAME
 		} else {
 			// 非sql的数据源类型是数据视图data_view
 			// if metricModel.DataSource.Type != interfaces.DATA_SOURCE_DATA_VIEW {
@@ -310,8 +337,8 @@ func ValidateMetricModel(ctx context.Context, metricModel *interfaces.MetricMode
 		return false, err
 	}
 
-	// SQL 不支持配置任务，若接口设置了任务，则置空处理
-	if metricModel.QueryType == interfaces.SQL {
+	// SQL 和 RED 不支持配置任务，若接口设置了任务，则置空处理
+	if metricModel.QueryType == interfaces.SQL || metricModel.QueryType == interfaces.RED {
 		metricModel.Task = nil
 	}
 	// 校验任务信息
@@ -839,6 +866,14 @@ func ValidateObjectiveModel(ctx context.Context, objectiveModel *interfaces.Obje
 				return err
 			}
 		}
+
+		// 燃烧率告警配置校验
+		if sloObjective.BurnRateAlert != nil {
+			err = validBurnRateAlert(ctx, sloObjective.BurnRateAlert, *sloObjective.Period)
+			if err != nil {
+				return err
+			}
+		}
 		objectiveModel.ObjectiveConfig = sloObjective
 	} else {
 		// kpi
@@ -991,6 +1026,59 @@ func validStatusConfig(ctx context.Context, ranges []interfaces.Range, objective
 	return nil
 }
 
+// 校验燃烧率告警配置。窗口的单位支持 m、h、d，短窗口小于长窗口，长窗口不超过 slo 周期（天）
+func validBurnRateAlert(ctx context.Context, alert *interfaces.BurnRateAlertConfig, period int64) error {
+	if alert.IndexBase == "" {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert).
+			WithErrorDetails("The index base of burn rate alert is empty")
+	}
+	if len(alert.Policies) == 0 || len(alert.Policies) > interfaces.BURN_RATE_POLICY_TOTAL {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert).
+			WithErrorDetails(fmt.Sprintf("The number of burn rate policies must be between 1 and %d, actual is %d",
+				interfaces.BURN_RATE_POLICY_TOTAL, len(alert.Policies)))
+	}
+
+	periodV := time.Duration(period) * 24 * time.Hour
+	names := make(map[string]struct{}, len(alert.Policies))
+	for _, policy := range alert.Policies {
+		if policy.Name == "" {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert).
+				WithErrorDetails("The name of burn rate policy is empty")
+		}
+		if _, ok := names[policy.Name]; ok {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert).
+				WithErrorDetails(fmt.Sprintf("Burn rate policy [%s] is duplicated", policy.Name))
+		}
+		names[policy.Name] = struct{}{}
+
+		longWindow, err := common.ParseDuration(policy.LongWindow, common.DurationDayHourMinuteRE, true)
+		if err != nil {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert).
+				WithErrorDetails(fmt.Sprintf("Burn rate policy [%s]'s long window is invalid: %s", policy.Name, err.Error()))
+		}
+		shortWindow, err := common.ParseDuration(policy.ShortWindow, common.DurationDayHourMinuteRE, true)
+		if err != nil {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert).
+				WithErrorDetails(fmt.Sprintf("Burn rate policy [%s]'s short window is invalid: %s", policy.Name, err.Error()))
+		}
+		if shortWindow >= longWindow || longWindow > periodV {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert).
+				WithErrorDetails(fmt.Sprintf("Burn rate policy [%s]'s windows must satisfy short window < long window <= period, actual is %s, %s, %dd",
+					policy.Name, policy.ShortWindow, policy.LongWindow, period))
+		}
+
+		if policy.BurnRate <= 0 {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert).
+				WithErrorDetails(fmt.Sprintf("Burn rate policy [%s]'s burn rate must be greater than 0", policy.Name))
+		}
+		if policy.Level < 1 || policy.Level > 5 {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert).
+				WithErrorDetails(fmt.Sprintf("Burn rate policy [%s]'s level must be between 1 and 5, actual is %d", policy.Name, policy.Level))
+		}
+	}
+	return nil
+}
+
 // 目标模型持久化任务信息校验
 func validateObjectiveTask(ctx context.Context, task *interfaces.MetricTask) error {
 
@@ -1318,3 +1406,42 @@ func validateHavingCondition(ctx context.Context, havingCondition *interfaces.Co
 	}
 	return nil
 }
+
+// 补数任务创建参数校验，步长和时间点数依赖持久化任务，在 service 中校验
+func ValidateMetricTaskBackfill(ctx context.Context, backfill interfaces.MetricTaskBackfill) error {
+	if backfill.ModelID == "" {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter_Backfill).
+			WithErrorDetails("The model_id of backfill is null")
+	}
+
+	if backfill.Start <= 0 || backfill.End <= 0 {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter_Backfill).
+			WithErrorDetails("The start and end of backfill must be greater than 0")
+	}
+
+	if backfill.Start >= backfill.End {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter_Backfill).
+			WithErrorDetails(fmt.Sprintf("The start[%d] of backfill must be less than end[%d]", backfill.Start, backfill.End))
+	}
+
+	for _, step := range backfill.Steps {
+		_, err := common.ParseDuration(step, common.DurationDayHourMinuteRE, true)
+		if err != nil {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter_Backfill).
+				WithErrorDetails(fmt.Sprintf("The step[%s] of backfill is invalid, %v", step, err))
+		}
+	}
+	return nil
+}
+
+// 补数任务状态过滤参数校验
+func ValidateMetricTaskBackfillStatus(ctx context.Context, status string) error {
+	switch status {
+	case "", interfaces.BACKFILL_STATUS_PENDING, interfaces.BACKFILL_STATUS_RUNNING, interfaces.BACKFILL_STATUS_SUCCEEDED,
+		interfaces.BACKFILL_STATUS_FAILED, interfaces.BACKFILL_STATUS_CANCELED:
+		return nil
+	default:
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter_Backfill).
+			WithErrorDetails(fmt.Sprintf("The status[%s] of backfill is invalid", status))
+	}
+}
@@ -539,6 +539,48 @@ func Test_ValidateMetricModel_ValidateMetricModel(t *testing.T) {
 			})
 			So(res, ShouldEqual, nil)
 		})
+
+		Convey("Validate red metric model", func() {
+			redModel := func(formulaConfig any) *interfaces.MetricModel {
+				return &interfaces.MetricModel{
+					SimpleMetricModel: interfaces.SimpleMetricModel{
+						ModelName:     "a",
+						MetricType:    interfaces.ATOMIC_METRIC,
+						QueryType:     interfaces.RED,
+						FormulaConfig: formulaConfig,
+						UnitType:      interfaces.UNIT_NUM,
+						Unit:          "none",
+						MeasureName:   "__m.a",
+					},
+					DataSource: &interfaces.MetricDataSource{
+						ID: "tm1",
+					},
+					Task: &interfaces.MetricTask{TaskName: "t"},
+				}
+			}
+
+			Convey("failed, because formula config is null", func() {
+				_, res := ValidateMetricModel(testCtx, redModel(nil))
+				So(res.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+				So(res.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_MetricModel_NullParameter_FormulaConfig)
+			})
+
+			Convey("failed, because red metric is invalid", func() {
+				_, res := ValidateMetricModel(testCtx, redModel(map[string]any{"metric": "duration_p90"}))
+				So(res.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+				So(res.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_MetricModel_InvalidParameter_FormulaConfig)
+			})
+
+			Convey("succeed without formula", func() {
+				model := redModel(map[string]any{"metric": "error_rate", "service_name": "svc"})
+				_, res := ValidateMetricModel(testCtx, model)
+				So(res, ShouldBeNil)
+				So(model.FormulaConfig, ShouldResemble, interfaces.REDConfig{Metric: "error_rate", ServiceName: "svc"})
+				So(model.DataSource.Type, ShouldEqual, interfaces.DATA_SOURCE_TRACE_MODEL)
+				So(model.MeasureField, ShouldEqual, interfaces.VALUE_FIELD_NAME)
+				So(model.Task, ShouldBeNil)
+			})
+		})
 	})
 }
 
@@ -1616,3 +1658,38 @@ func Test_ValidateMetricModel_Cron2(t *testing.T) {
 	// 	}
 	// })
 }
+
+func Test_ValidateMetricModel_ValidateMetricTaskBackfill(t *testing.T) {
+	Convey("Test ValidateMetricTaskBackfill", t, func() {
+
+		Convey("Validate failed, because model id is null", func() {
+			err := ValidateMetricTaskBackfill(testCtx, interfaces.MetricTaskBackfill{Start: 1, End: 2})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_MetricModel_InvalidParameter_Backfill)
+		})
+
+		Convey("Validate failed, because start is not less than end", func() {
+			err := ValidateMetricTaskBackfill(testCtx, interfaces.MetricTaskBackfill{ModelID: "1", Start: 2, End: 2})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_MetricModel_InvalidParameter_Backfill)
+		})
+
+		Convey("Validate failed, because step is invalid", func() {
+			err := ValidateMetricTaskBackfill(testCtx, interfaces.MetricTaskBackfill{
+				ModelID: "1", Start: 1, End: 2, Steps: []string{"5x"},
+			})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_MetricModel_InvalidParameter_Backfill)
+		})
+
+		Convey("Validate succeed", func() {
+			err := ValidateMetricTaskBackfill(testCtx, interfaces.MetricTaskBackfill{
+				ModelID: "1", Start: 1, End: 2, Steps: []string{"5m", "1h"},
+			})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Validate status", func() {
+			So(ValidateMetricTaskBackfillStatus(testCtx, ""), ShouldBeNil)
+			So(ValidateMetricTaskBackfillStatus(testCtx, interfaces.BACKFILL_STATUS_RUNNING), ShouldBeNil)
+			So(ValidateMetricTaskBackfillStatus(testCtx, "done"), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+// 模型包格式校验
+func validateModelBundleFormat(ctx context.Context, format string) error {
+	switch format {
+	case interfaces.MODEL_BUNDLE_FORMAT_JSON, interfaces.MODEL_BUNDLE_FORMAT_YAML:
+		return nil
+	default:
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelBundle_InvalidParameter_Format).
+			WithErrorDetails(fmt.Sprintf("The format value can be '%s', '%s'",
+				interfaces.MODEL_BUNDLE_FORMAT_JSON, interfaces.MODEL_BUNDLE_FORMAT_YAML))
+	}
+}
+
+// 模型包导入模式校验
+func validateModelBundleImportMode(ctx context.Context, mode string) error {
+	switch mode {
+	case interfaces.MODEL_BUNDLE_IMPORT_MODE_CREATE_ONLY,
+		interfaces.MODEL_BUNDLE_IMPORT_MODE_OVERWRITE,
+		interfaces.MODEL_BUNDLE_IMPORT_MODE_RENAME:
+		return nil
+	default:
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelBundle_InvalidParameter_ImportMode).
+			WithErrorDetails(fmt.Sprintf("The import_mode value can be '%s', '%s', '%s'",
+				interfaces.MODEL_BUNDLE_IMPORT_MODE_CREATE_ONLY, interfaces.MODEL_BUNDLE_IMPORT_MODE_OVERWRITE,
+				interfaces.MODEL_BUNDLE_IMPORT_MODE_RENAME))
+	}
+}
+
+func validateDryRun(ctx context.Context, dryRunStr string) (bool, error) {
+	dryRun, err := strconv.ParseBool(dryRunStr)
+	if err != nil {
+		return false, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelBundle_InvalidParameter_DryRun).
+			WithErrorDetails(err.Error())
+	}
+	return dryRun, nil
+}
+
+// 导出时至少选择一个对象
+func validateModelBundleExportRequest(ctx context.Context, req *interfaces.ModelBundleExportRequest) error {
+	if len(req.DataViewIDs) == 0 && len(req.DataViewGroupIDs) == 0 &&
+		len(req.MetricModelIDs) == 0 && len(req.MetricModelGroupIDs) == 0 &&
+		len(req.EventModelIDs) == 0 && len(req.ObjectiveModelIDs) == 0 {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelBundle_NullParameter_ExportAssets)
+	}
+	return nil
+}
+
+// 模型包中同类对象的 id 不能为空且不能重复
+func validateModelBundleIDs(ctx context.Context, assetType string, ids []string) error {
+	idMap := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelBundle_InvalidParameter_Bundle).
+				WithErrorDetails(fmt.Sprintf("The id of %s is empty", assetType))
+		}
+		if _, ok := idMap[id]; ok {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelBundle_InvalidParameter_Bundle).
+				WithErrorDetails(fmt.Sprintf("The %s id '%s' is duplicated in the bundle", assetType, id))
+		}
+		idMap[id] = struct{}{}
+	}
+	return nil
+}
+
+// 模型包校验, 复用各模型创建时的校验, 同时把公式配置、目标配置等转换为对应的结构体
+func validateModelBundle(ctx context.Context, bundle *interfaces.ModelBundle) error {
+	if bundle.Version != interfaces.MODEL_BUNDLE_VERSION {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelBundle_UnsupportedVersion).
+			WithErrorDetails(fmt.Sprintf("The bundle version '%s' is not supported, expected is '%s'",
+				bundle.Version, interfaces.MODEL_BUNDLE_VERSION))
+	}
+
+	viewIDs := make([]string, 0, len(bundle.DataViews))
+	for _, view := range bundle.DataViews {
+		viewIDs = append(viewIDs, view.ViewID)
+	}
+	err := validateModelBundleIDs(ctx, interfaces.MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW, viewIDs)
+	if err != nil {
+		return err
+	}
+	for _, view := range bundle.DataViews {
+		err = ValidateDataView(ctx, view)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, group := range bundle.MetricModelGroups {
+		err = validateMetricModelGroup(ctx, group)
+		if err != nil {
+			return err
+		}
+	}
+
+	metricIDs := make([]string, 0, len(bundle.MetricModels))
+	for _, model := range bundle.MetricModels {
+		metricIDs = append(metricIDs, model.ModelID)
+	}
+	err = validateModelBundleIDs(ctx, interfaces.MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL, metricIDs)
+	if err != nil {
+		return err
+	}
+	for _, model := range bundle.MetricModels {
+		containTopHits, err := ValidateMetricModel(ctx, model)
+		if err != nil {
+			return err
+		}
+		model.IfContainTopHits = containTopHits
+	}
+
+	eventIDs := make([]string, 0, len(bundle.EventModels))
+	for _, model := range bundle.EventModels {
+		eventIDs = append(eventIDs, model.EventModelID)
+	}
+	err = validateModelBundleIDs(ctx, interfaces.MODEL_BUNDLE_ASSET_TYPE_EVENT_MODEL, eventIDs)
+	if err != nil {
+		return err
+	}
+	for _, model := range bundle.EventModels {
+		for _, tag := range model.EventModelTags {
+			if isInvalid := strings.ContainsAny(interfaces.NAME_INVALID_CHARACTER, tag); isInvalid {
+				return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.EventModel_InvalidParameter).
+					WithErrorDetails("Event model tag contains special characters, such as /:?\\\"<>|：？‘’“”！《》#[]{}%&*$^!=.''")
+			}
+		}
+		err = validateEventTask(ctx, model.Task)
+		if err != nil {
+			return err
+		}
+	}
+
+	objectiveIDs := make([]string, 0, len(bundle.ObjectiveModels))
+	for _, model := range bundle.ObjectiveModels {
+		objectiveIDs = append(objectiveIDs, model.ModelID)
+	}
+	err = validateModelBundleIDs(ctx, interfaces.MODEL_BUNDLE_ASSET_TYPE_OBJECTIVE_MODEL, objectiveIDs)
+	if err != nil {
+		return err
+	}
+	for _, model := range bundle.ObjectiveModels {
+		err = ValidateObjectiveModel(ctx, model)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
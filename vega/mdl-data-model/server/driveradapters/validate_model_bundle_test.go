@@ -0,0 +1,112 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"testing"
+
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+func Test_ValidateModelBundle_ValidateModelBundleFormat(t *testing.T) {
+	Convey("Test validateModelBundleFormat", t, func() {
+
+		Convey("Validate failed, because format is not supported", func() {
+			err := validateModelBundleFormat(testCtx, "xml")
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelBundle_InvalidParameter_Format)
+		})
+
+		Convey("Validate succeed", func() {
+			So(validateModelBundleFormat(testCtx, interfaces.MODEL_BUNDLE_FORMAT_JSON), ShouldBeNil)
+			So(validateModelBundleFormat(testCtx, interfaces.MODEL_BUNDLE_FORMAT_YAML), ShouldBeNil)
+		})
+	})
+}
+
+func Test_ValidateModelBundle_ValidateModelBundleImportMode(t *testing.T) {
+	Convey("Test validateModelBundleImportMode", t, func() {
+
+		Convey("Validate failed, because import mode is not supported", func() {
+			err := validateModelBundleImportMode(testCtx, interfaces.ImportMode_Normal)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelBundle_InvalidParameter_ImportMode)
+		})
+
+		Convey("Validate succeed", func() {
+			So(validateModelBundleImportMode(testCtx, interfaces.MODEL_BUNDLE_IMPORT_MODE_RENAME), ShouldBeNil)
+		})
+	})
+}
+
+func Test_ValidateModelBundle_ValidateDryRun(t *testing.T) {
+	Convey("Test validateDryRun", t, func() {
+
+		Convey("Validate failed, because dry_run is not a bool", func() {
+			_, err := validateDryRun(testCtx, "yes")
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelBundle_InvalidParameter_DryRun)
+		})
+
+		Convey("Validate succeed", func() {
+			dryRun, err := validateDryRun(testCtx, "true")
+			So(err, ShouldBeNil)
+			So(dryRun, ShouldBeTrue)
+		})
+	})
+}
+
+func Test_ValidateModelBundle_ValidateModelBundleExportRequest(t *testing.T) {
+	Convey("Test validateModelBundleExportRequest", t, func() {
+
+		Convey("Validate failed, because no asset is selected", func() {
+			err := validateModelBundleExportRequest(testCtx, &interfaces.ModelBundleExportRequest{})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelBundle_NullParameter_ExportAssets)
+		})
+
+		Convey("Validate succeed", func() {
+			err := validateModelBundleExportRequest(testCtx, &interfaces.ModelBundleExportRequest{
+				MetricModelGroupIDs: []string{"1"},
+			})
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func Test_ValidateModelBundle_ValidateModelBundle(t *testing.T) {
+	Convey("Test validateModelBundle", t, func() {
+
+		Convey("Validate failed, because version is not supported", func() {
+			err := validateModelBundle(testCtx, &interfaces.ModelBundle{Version: "0.9"})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelBundle_UnsupportedVersion)
+		})
+
+		Convey("Validate failed, because view id is duplicated", func() {
+			err := validateModelBundle(testCtx, &interfaces.ModelBundle{
+				Version: interfaces.MODEL_BUNDLE_VERSION,
+				DataViews: []*interfaces.DataView{
+					{SimpleDataView: interfaces.SimpleDataView{ViewID: "v1"}},
+					{SimpleDataView: interfaces.SimpleDataView{ViewID: "v1"}},
+				},
+			})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelBundle_InvalidParameter_Bundle)
+		})
+
+		Convey("Validate failed, because metric model id is empty", func() {
+			err := validateModelBundle(testCtx, &interfaces.ModelBundle{
+				Version:      interfaces.MODEL_BUNDLE_VERSION,
+				MetricModels: []*interfaces.MetricModel{{}},
+			})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelBundle_InvalidParameter_Bundle)
+		})
+
+		Convey("Validate succeed with empty bundle", func() {
+			err := validateModelBundle(testCtx, &interfaces.ModelBundle{Version: interfaces.MODEL_BUNDLE_VERSION})
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+// 版本号校验, 版本号从 1 开始
+func validateRevision(ctx context.Context, revisionStr string) (int, error) {
+	revision, err := strconv.Atoi(revisionStr)
+	if err != nil {
+		return 0, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelRevision_InvalidParameter_Revision).
+			WithErrorDetails(err.Error())
+	}
+	if revision < 1 {
+		return 0, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelRevision_InvalidParameter_Revision).
+			WithErrorDetails("The revision must be greater than 0")
+	}
+	return revision, nil
+}
+
+// 版本说明校验
+func validateRevisionComment(ctx context.Context, comment string) error {
+	if utf8.RuneCountInString(comment) > interfaces.REVISION_COMMENT_MAX_LENGTH {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelRevision_InvalidParameter_Comment).
+			WithErrorDetails(fmt.Sprintf("The length of the revision comment exceeds %d", interfaces.REVISION_COMMENT_MAX_LENGTH))
+	}
+	return nil
+}
+
+// 查询模型详情时可指定版本, 仅支持单个模型. 未指定时返回 0
+func validatePinnedRevision(ctx context.Context, revisionStr string, modelIDs []string) (int, error) {
+	if revisionStr == "" {
+		return 0, nil
+	}
+
+	revision, err := validateRevision(ctx, revisionStr)
+	if err != nil {
+		return 0, err
+	}
+	if len(modelIDs) != 1 {
+		return 0, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ModelRevision_InvalidParameter_Revision).
+			WithErrorDetails(fmt.Sprintf("The revision can only be specified for a single model, but got ids '%s'",
+				strings.Join(modelIDs, ",")))
+	}
+	return revision, nil
+}
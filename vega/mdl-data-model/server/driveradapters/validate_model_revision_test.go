@@ -0,0 +1,75 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+func Test_ValidateModelRevision_ValidateRevision(t *testing.T) {
+	Convey("Test validateRevision", t, func() {
+
+		Convey("Validate failed, because revision is not a number", func() {
+			_, err := validateRevision(testCtx, "v1")
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_InvalidParameter_Revision)
+		})
+
+		Convey("Validate failed, because revision is less than 1", func() {
+			_, err := validateRevision(testCtx, "0")
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_InvalidParameter_Revision)
+		})
+
+		Convey("Validate succeed", func() {
+			revision, err := validateRevision(testCtx, "3")
+			So(err, ShouldBeNil)
+			So(revision, ShouldEqual, 3)
+		})
+	})
+}
+
+func Test_ValidateModelRevision_ValidatePinnedRevision(t *testing.T) {
+	Convey("Test validatePinnedRevision", t, func() {
+
+		Convey("Revision not specified", func() {
+			revision, err := validatePinnedRevision(testCtx, "", []string{"1", "2"})
+			So(err, ShouldBeNil)
+			So(revision, ShouldEqual, 0)
+		})
+
+		Convey("Validate failed, because more than one model is requested", func() {
+			_, err := validatePinnedRevision(testCtx, "2", []string{"1", "2"})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_InvalidParameter_Revision)
+		})
+
+		Convey("Validate succeed", func() {
+			revision, err := validatePinnedRevision(testCtx, "2", []string{"1"})
+			So(err, ShouldBeNil)
+			So(revision, ShouldEqual, 2)
+		})
+	})
+}
+
+func Test_ValidateModelRevision_ValidateRevisionComment(t *testing.T) {
+	Convey("Test validateRevisionComment", t, func() {
+
+		Convey("Validate failed, because comment is too long", func() {
+			err := validateRevisionComment(testCtx, strings.Repeat("版", interfaces.REVISION_COMMENT_MAX_LENGTH+1))
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_InvalidParameter_Comment)
+		})
+
+		Convey("Validate succeed", func() {
+			err := validateRevisionComment(testCtx, strings.Repeat("版", interfaces.REVISION_COMMENT_MAX_LENGTH))
+			So(err, ShouldBeNil)
+		})
+	})
+}
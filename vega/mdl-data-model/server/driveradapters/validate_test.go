@@ -2046,3 +2046,43 @@ func Test_Validate_ValidateObjectiveModel(t *testing.T) {
 		})
 	})
 }
+
+func Test_Validate_ValidBurnRateAlert(t *testing.T) {
+	Convey("Test validBurnRateAlert", t, func() {
+		newAlert := func() *interfaces.BurnRateAlertConfig {
+			return &interfaces.BurnRateAlertConfig{
+				IndexBase: "slo_alert",
+				Policies: []interfaces.BurnRatePolicy{
+					{Name: "page", LongWindow: "1h", ShortWindow: "5m", BurnRate: 14.4, Level: 1},
+					{Name: "ticket", LongWindow: "6h", ShortWindow: "30m", BurnRate: 6, Level: 3},
+				},
+			}
+		}
+
+		Convey("Valid", func() {
+			So(validBurnRateAlert(testCtx, newAlert(), 30), ShouldBeNil)
+		})
+
+		cases := map[string]func(alert *interfaces.BurnRateAlertConfig){
+			"index base is empty":        func(a *interfaces.BurnRateAlertConfig) { a.IndexBase = "" },
+			"no policies":                func(a *interfaces.BurnRateAlertConfig) { a.Policies = nil },
+			"policy name is empty":       func(a *interfaces.BurnRateAlertConfig) { a.Policies[0].Name = "" },
+			"policy name is duplicated":  func(a *interfaces.BurnRateAlertConfig) { a.Policies[1].Name = "page" },
+			"long window is invalid":     func(a *interfaces.BurnRateAlertConfig) { a.Policies[0].LongWindow = "1x" },
+			"short window is invalid":    func(a *interfaces.BurnRateAlertConfig) { a.Policies[0].ShortWindow = "" },
+			"short window is not less":   func(a *interfaces.BurnRateAlertConfig) { a.Policies[0].ShortWindow = "1h" },
+			"long window exceeds period": func(a *interfaces.BurnRateAlertConfig) { a.Policies[0].LongWindow = "31d" },
+			"burn rate is 0":             func(a *interfaces.BurnRateAlertConfig) { a.Policies[0].BurnRate = 0 },
+			"level is cleared":           func(a *interfaces.BurnRateAlertConfig) { a.Policies[0].Level = 6 },
+		}
+		for name, modify := range cases {
+			Convey("Invalid when "+name, func() {
+				alert := newAlert()
+				modify(alert)
+				err := validBurnRateAlert(testCtx, alert, 30)
+				So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+				So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert)
+			})
+		}
+	})
+}
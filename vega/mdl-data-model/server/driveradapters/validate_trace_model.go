@@ -233,6 +233,19 @@ func validateSpanConfig(ctx context.Context, spanConf any, spanSourceType string
 		if err != nil {
 			return err
 		}
+
+		// 校验字段映射中的时间格式与耗时单位, 未配置字段名的项使用默认值
+		if mapping := conf.FieldMapping; mapping != nil {
+			if mapping.StartTime.FieldName != "" && !isValidTimeFormat(mapping.StartTime.FieldFormat) {
+				return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_TraceModel_InvalidBasicAttributeConfig_StartTime).
+					WithErrorDetails(fmt.Sprintf("The time format is invalid, valid time format is in %v", interfaces.VALID_TIME_FORMATS))
+			}
+
+			if mapping.Duration.FieldName != "" && !isValidDurationUnit(mapping.Duration.FieldUnit) {
+				return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_TraceModel_InvalidBasicAttributeConfig_Duration).
+					WithErrorDetails(fmt.Sprintf("The duration unit is invalid, valid duration unit is in %v", interfaces.VALID_DURATION_UNITS))
+			}
+		}
 	}
 
 	return nil
@@ -406,6 +419,20 @@ func validateRelatedLogConfig(ctx context.Context, relatedLogConf any, sourceTyp
 			}
 			m[fieldName] = struct{}{}
 		}
+	} else if sourceType == interfaces.SOURCE_TYPE_DATA_CONNECTION {
+		conf := relatedLogConf.(interfaces.RelatedLogConfigWithDataConnection)
+
+		// 1. 校验关联日志所在索引
+		if conf.LogIndex == "" {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_TraceModel_InvalidRelatedLogConfig_LogIndex).
+				WithErrorDetails("The log index is empty")
+		}
+
+		// 2. SpanID校验, trace_id和span_id未配置时使用OTel日志的默认字段
+		if len(conf.SpanID.FieldNames) > 1 {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_TraceModel_InvalidRelatedLogConfig_SpanID).
+				WithErrorDetails("Only one span id field is supported for the related log in data connection")
+		}
 	}
 	return nil
 }
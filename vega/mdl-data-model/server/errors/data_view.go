@@ -126,11 +126,32 @@ const (
 // 数据视图行列规则错误码
 const (
 	// 400
-	DataModel_DataViewRowColumnRule_ExistByName             = "DataModel.DataViewRowColumnRule.ExistByName"
-	DataModel_DataViewRowColumnRule_LengthExceeded_RuleName = "DataModel.DataViewRowColumnRule.LengthExceeded.RuleName"
-	DataModel_DataViewRowColumnRule_NullParameter_RuleID    = "DataModel.DataViewRowColumnRule.NullParameter.RuleID"
-	DataModel_DataViewRowColumnRule_NullParameter_RuleName  = "DataModel.DataViewRowColumnRule.NullParameter.RuleName"
-	DataModel_DataViewRowColumnRule_NullParameter_ViewID    = "DataModel.DataViewRowColumnRule.NullParameter.ViewID"
+	DataModel_DataViewRowColumnRule_ExistByName                 = "DataModel.DataViewRowColumnRule.ExistByName"
+	DataModel_DataViewRowColumnRule_InvalidParameter_FieldMasks = "DataModel.DataViewRowColumnRule.InvalidParameter.FieldMasks"
+	DataModel_DataViewRowColumnRule_InvalidParameter_RowFilters = "DataModel.DataViewRowColumnRule.InvalidParameter.RowFilters"
+	DataModel_DataViewRowColumnRule_LengthExceeded_RuleName     = "DataModel.DataViewRowColumnRule.LengthExceeded.RuleName"
+	DataModel_DataViewRowColumnRule_NullParameter_RuleID        = "DataModel.DataViewRowColumnRule.NullParameter.RuleID"
+	DataModel_DataViewRowColumnRule_NullParameter_RuleName      = "DataModel.DataViewRowColumnRule.NullParameter.RuleName"
+	DataModel_DataViewRowColumnRule_NullParameter_ViewID        = "DataModel.DataViewRowColumnRule.NullParameter.ViewID"
+)
+
+// 数据质量规则错误码
+const (
+	// 400
+	DataModel_DataQualityRule_ExistByName                  = "DataModel.DataQualityRule.ExistByName"
+	DataModel_DataQualityRule_InvalidParameter_Config      = "DataModel.DataQualityRule.InvalidParameter.Config"
+	DataModel_DataQualityRule_InvalidParameter_EventConfig = "DataModel.DataQualityRule.InvalidParameter.EventConfig"
+	DataModel_DataQualityRule_InvalidParameter_Kind        = "DataModel.DataQualityRule.InvalidParameter.Kind"
+	DataModel_DataQualityRule_InvalidParameter_Schedule    = "DataModel.DataQualityRule.InvalidParameter.Schedule"
+	DataModel_DataQualityRule_InvalidParameter_ViewID      = "DataModel.DataQualityRule.InvalidParameter.ViewID"
+	DataModel_DataQualityRule_LengthExceeded_RuleName      = "DataModel.DataQualityRule.LengthExceeded.RuleName"
+	DataModel_DataQualityRule_NullParameter_Kind           = "DataModel.DataQualityRule.NullParameter.Kind"
+	DataModel_DataQualityRule_NullParameter_RuleID         = "DataModel.DataQualityRule.NullParameter.RuleID"
+	DataModel_DataQualityRule_NullParameter_RuleName       = "DataModel.DataQualityRule.NullParameter.RuleName"
+	DataModel_DataQualityRule_NullParameter_ViewID         = "DataModel.DataQualityRule.NullParameter.ViewID"
+
+	// 404
+	DataModel_DataQualityRule_RuleNotFound = "DataModel.DataQualityRule.RuleNotFound"
 )
 
 var (
@@ -252,9 +273,28 @@ var (
 		// ---数据视图行列规则模块---
 		// 400
 		DataModel_DataViewRowColumnRule_ExistByName,
+		DataModel_DataViewRowColumnRule_InvalidParameter_FieldMasks,
+		DataModel_DataViewRowColumnRule_InvalidParameter_RowFilters,
 		DataModel_DataViewRowColumnRule_LengthExceeded_RuleName,
 		DataModel_DataViewRowColumnRule_NullParameter_RuleID,
 		DataModel_DataViewRowColumnRule_NullParameter_RuleName,
 		DataModel_DataViewRowColumnRule_NullParameter_ViewID,
+
+		// ---数据质量规则模块---
+		// 400
+		DataModel_DataQualityRule_ExistByName,
+		DataModel_DataQualityRule_InvalidParameter_Config,
+		DataModel_DataQualityRule_InvalidParameter_EventConfig,
+		DataModel_DataQualityRule_InvalidParameter_Kind,
+		DataModel_DataQualityRule_InvalidParameter_Schedule,
+		DataModel_DataQualityRule_InvalidParameter_ViewID,
+		DataModel_DataQualityRule_LengthExceeded_RuleName,
+		DataModel_DataQualityRule_NullParameter_Kind,
+		DataModel_DataQualityRule_NullParameter_RuleID,
+		DataModel_DataQualityRule_NullParameter_RuleName,
+		DataModel_DataQualityRule_NullParameter_ViewID,
+
+		// 404
+		DataModel_DataQualityRule_RuleNotFound,
 	}
 )
@@ -209,7 +209,10 @@ func init() {
 	rest.Register(dataConnectionErrCodeList)
 	rest.Register(dataDictErrCodeList)
 	rest.Register(dataViewErrCodeList)
+	rest.Register(lineageErrCodeList)
 	rest.Register(metricModelErrCodeList)
+	rest.Register(modelBundleErrCodeList)
+	rest.Register(modelRevisionErrCodeList)
 	rest.Register(objectiveModelErrCodeList)
 	rest.Register(traceModelErrCodeList)
 }
@@ -0,0 +1,42 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package errors 服务错误码
+package errors
+
+// 血缘错误码
+const (
+	// 400
+	DataModel_Lineage_InvalidParameter_Depth     = "DataModel.Lineage.InvalidParameter.Depth"
+	DataModel_Lineage_InvalidParameter_Direction = "DataModel.Lineage.InvalidParameter.Direction"
+	DataModel_Lineage_InvalidParameter_Edges     = "DataModel.Lineage.InvalidParameter.Edges"
+	DataModel_Lineage_InvalidParameter_NodeType  = "DataModel.Lineage.InvalidParameter.NodeType"
+	DataModel_Lineage_InvalidParameter_OwnerType = "DataModel.Lineage.InvalidParameter.OwnerType"
+	DataModel_Lineage_NullParameter_NodeID       = "DataModel.Lineage.NullParameter.NodeID"
+
+	// 500
+	DataModel_Lineage_InternalError_BeginTransactionFailed = "DataModel.Lineage.InternalError.BeginTransactionFailed"
+	DataModel_Lineage_InternalError_DeleteLineageFailed    = "DataModel.Lineage.InternalError.DeleteLineageFailed"
+	DataModel_Lineage_InternalError_GetLineageFailed       = "DataModel.Lineage.InternalError.GetLineageFailed"
+	DataModel_Lineage_InternalError_SaveLineageFailed      = "DataModel.Lineage.InternalError.SaveLineageFailed"
+)
+
+var (
+	lineageErrCodeList = []string{
+		// 400
+		DataModel_Lineage_InvalidParameter_Depth,
+		DataModel_Lineage_InvalidParameter_Direction,
+		DataModel_Lineage_InvalidParameter_Edges,
+		DataModel_Lineage_InvalidParameter_NodeType,
+		DataModel_Lineage_InvalidParameter_OwnerType,
+		DataModel_Lineage_NullParameter_NodeID,
+
+		// 500
+		DataModel_Lineage_InternalError_BeginTransactionFailed,
+		DataModel_Lineage_InternalError_DeleteLineageFailed,
+		DataModel_Lineage_InternalError_GetLineageFailed,
+		DataModel_Lineage_InternalError_SaveLineageFailed,
+	}
+)
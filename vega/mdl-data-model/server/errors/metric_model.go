@@ -10,6 +10,8 @@ const (
 	// 400
 	DataModel_MetricModel_AggregationNotExisted                  = "DataModel.MetricModel.AggregationNotExisted"
 	DataModel_MetricModel_AnalysisDimensionNotExisted            = "DataModel.MetricModel.AnalysisDimensionNotExisted"
+	DataModel_MetricModel_BackfillExisted                        = "DataModel.MetricModel.BackfillExisted"
+	DataModel_MetricModel_BackfillFinished                       = "DataModel.MetricModel.BackfillFinished"
 	DataModel_MetricModel_CombinationNameExisted                 = "DataModel.MetricModel.CombinationNameExisted"
 	DataModel_MetricModel_OrderByFieldNotExisted                 = "DataModel.MetricModel.OrderByFieldNotExisted"
 	DataModel_MetricModel_CountExceeded_TagTotal                 = "DataModel.MetricModel.CountExceeded.TagTotal"
@@ -26,6 +28,7 @@ const (
 	DataModel_MetricModel_GroupByFieldNotExisted                 = "DataModel.MetricModel.GroupByFieldNotExisted"
 	DataModel_MetricModel_IDExisted                              = "DataModel.MetricModel.IDExisted"
 	DataModel_MetricModel_InvalidParameter                       = "DataModel.MetricModel.InvalidParameter"
+	DataModel_MetricModel_InvalidParameter_Backfill              = "DataModel.MetricModel.InvalidParameter.Backfill"
 	DataModel_MetricModel_InvalidParameter_DateField             = "DataModel.MetricModel.InvalidParameter.DateField"
 	DataModel_MetricModel_InvalidParameter_DateFormat            = "DataModel.MetricModel.InvalidParameter.DateFormat"
 	DataModel_MetricModel_InvalidParameter_DataSourceType        = "DataModel.MetricModel.InvalidParameter.DataSourceType"
@@ -85,17 +88,21 @@ const (
 	DataModel_MetricModel_UnsupportHavingConditionOperation      = "DataModel.MetricModel.UnsupportHavingConditionOperation"
 
 	// 404
+	DataModel_MetricModel_BackfillNotFound    = "DataModel.MetricModel.BackfillNotFound"
 	DataModel_MetricModel_MetricModelNotFound = "DataModel.MetricModel.MetricModelNotFound"
 	DataModel_MetricModel_MetricTaskNotFound  = "DataModel.MetricModel.MetricTaskNotFound"
 
 	// 500
 	DataModel_MetricModel_InternalError                                  = "DataModel.MetricModel.InternalError"
 	DataModel_MetricModel_InternalError_BeginTransactionFailed           = "DataModel.MetricModel.InternalError.BeginTransactionFailed"
+	DataModel_MetricModel_InternalError_CancelBackfillFailed             = "DataModel.MetricModel.InternalError.CancelBackfillFailed"
 	DataModel_MetricModel_InternalError_CheckDuplicateMeasureNameFailed  = "DataModel.MetricModel.InternalError.CheckDuplicateMeasureNameFailed"
 	DataModel_MetricModel_InternalError_CheckFormulaFailed               = "DataModel.MetricModel.InternalError.CheckFormulaFailed"
 	DataModel_MetricModel_InternalError_CheckMetricModelTaskExistByName  = "DataModel.MetricModel.InternalError.CheckMetricModelTaskExistByName"
 	DataModel_MetricModel_InternalError_CheckModelIfExistFailed          = "DataModel.MetricModel.InternalError.CheckModelIfExistFailed"
+	DataModel_MetricModel_InternalError_CreateBackfillFailed             = "DataModel.MetricModel.InternalError.CreateBackfillFailed"
 	DataModel_MetricModel_InternalError_GenerateIDFailed                 = "DataModel.MetricModel.InternalError.GenerateIDFailed"
+	DataModel_MetricModel_InternalError_GetBackfillFailed                = "DataModel.MetricModel.InternalError.GetBackfillFailed"
 	DataModel_MetricModel_InternalError_GetDataViewByIDFailed            = "DataModel.MetricModel.InternalError.GetDataViewByIDFailed"
 	DataModel_MetricModel_InternalError_GetDataViewByNameFailed          = "DataModel.MetricModel.InternalError.GetDataViewByNameFailed"
 	DataModel_MetricModel_InternalError_GetDataViewQueryFiltersFailed    = "DataModel.MetricModel.InternalError.GetDataViewQueryFiltersFailed"
@@ -104,6 +111,7 @@ const (
 	DataModel_MetricModel_InternalError_GetModelByIDFailed               = "DataModel.MetricModel.InternalError.GetModelByIDFailed"
 	DataModel_MetricModel_InternalError_GetModelIDByNameFailed           = "DataModel.MetricModel.InternalError.GetModelIDByNameFailed"
 	DataModel_MetricModel_InternalError_GetSimpleIndexBasesByTypesFailed = "DataModel.MetricModel.InternalError.GetSimpleIndexBasesByTypesFailed"
+	DataModel_MetricModel_InternalError_ListBackfillsFailed              = "DataModel.MetricModel.InternalError.ListBackfillsFailed"
 	DataModel_MetricModel_InternalError_MeasureNameRuleCompileFailed     = "DataModel.MetricModel.InternalError.MeasureNameRuleCompileFailed"
 )
 
@@ -141,6 +149,8 @@ var (
 		// 400
 		DataModel_MetricModel_AggregationNotExisted,
 		DataModel_MetricModel_AnalysisDimensionNotExisted,
+		DataModel_MetricModel_BackfillExisted,
+		DataModel_MetricModel_BackfillFinished,
 		DataModel_MetricModel_IDExisted,
 		DataModel_MetricModel_CombinationNameExisted,
 		DataModel_MetricModel_OrderByFieldNotExisted,
@@ -157,6 +167,7 @@ var (
 		DataModel_MetricModel_DateFieldNotExisted,
 		DataModel_MetricModel_GroupByFieldNotExisted,
 		DataModel_MetricModel_InvalidParameter,
+		DataModel_MetricModel_InvalidParameter_Backfill,
 		DataModel_MetricModel_InvalidParameter_DateField,
 		DataModel_MetricModel_InvalidParameter_DateFormat,
 		DataModel_MetricModel_InvalidParameter_DataSourceType,
@@ -216,17 +227,21 @@ var (
 		DataModel_MetricModel_UnsupportHavingConditionOperation,
 
 		// 404
+		DataModel_MetricModel_BackfillNotFound,
 		DataModel_MetricModel_MetricModelNotFound,
 		DataModel_MetricModel_MetricTaskNotFound,
 
 		// 500
 		DataModel_MetricModel_InternalError,
 		DataModel_MetricModel_InternalError_BeginTransactionFailed,
+		DataModel_MetricModel_InternalError_CancelBackfillFailed,
 		DataModel_MetricModel_InternalError_CheckDuplicateMeasureNameFailed,
 		DataModel_MetricModel_InternalError_CheckFormulaFailed,
 		DataModel_MetricModel_InternalError_CheckMetricModelTaskExistByName,
 		DataModel_MetricModel_InternalError_CheckModelIfExistFailed,
+		DataModel_MetricModel_InternalError_CreateBackfillFailed,
 		DataModel_MetricModel_InternalError_GenerateIDFailed,
+		DataModel_MetricModel_InternalError_GetBackfillFailed,
 		DataModel_MetricModel_InternalError_GetDataViewByIDFailed,
 		DataModel_MetricModel_InternalError_GetDataViewByNameFailed,
 		DataModel_MetricModel_InternalError_GetDataViewQueryFiltersFailed,
@@ -235,6 +250,7 @@ var (
 		DataModel_MetricModel_InternalError_GetModelByIDFailed,
 		DataModel_MetricModel_InternalError_GetModelIDByNameFailed,
 		DataModel_MetricModel_InternalError_GetSimpleIndexBasesByTypesFailed,
+		DataModel_MetricModel_InternalError_ListBackfillsFailed,
 		DataModel_MetricModel_InternalError_MeasureNameRuleCompileFailed,
 
 		// ---指标模型分组模块---
@@ -0,0 +1,46 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package errors 服务错误码
+package errors
+
+// 模型包导入导出错误码
+const (
+	// 400
+	DataModel_ModelBundle_InvalidParameter_Bundle     = "DataModel.ModelBundle.InvalidParameter.Bundle"
+	DataModel_ModelBundle_InvalidParameter_DryRun     = "DataModel.ModelBundle.InvalidParameter.DryRun"
+	DataModel_ModelBundle_InvalidParameter_Format     = "DataModel.ModelBundle.InvalidParameter.Format"
+	DataModel_ModelBundle_InvalidParameter_ImportMode = "DataModel.ModelBundle.InvalidParameter.ImportMode"
+	DataModel_ModelBundle_NullParameter_ExportAssets  = "DataModel.ModelBundle.NullParameter.ExportAssets"
+	DataModel_ModelBundle_UnsupportedVersion          = "DataModel.ModelBundle.UnsupportedVersion"
+
+	// 403
+	DataModel_ModelBundle_DataSourceNotFound = "DataModel.ModelBundle.DataSourceNotFound"
+	DataModel_ModelBundle_ImportConflict     = "DataModel.ModelBundle.ImportConflict"
+
+	// 500
+	DataModel_ModelBundle_InternalError_GetDataSourcesFailed = "DataModel.ModelBundle.InternalError.GetDataSourcesFailed"
+	DataModel_ModelBundle_InternalError_RenameFailed         = "DataModel.ModelBundle.InternalError.RenameFailed"
+)
+
+var (
+	modelBundleErrCodeList = []string{
+		// 400
+		DataModel_ModelBundle_InvalidParameter_Bundle,
+		DataModel_ModelBundle_InvalidParameter_DryRun,
+		DataModel_ModelBundle_InvalidParameter_Format,
+		DataModel_ModelBundle_InvalidParameter_ImportMode,
+		DataModel_ModelBundle_NullParameter_ExportAssets,
+		DataModel_ModelBundle_UnsupportedVersion,
+
+		// 403
+		DataModel_ModelBundle_DataSourceNotFound,
+		DataModel_ModelBundle_ImportConflict,
+
+		// 500
+		DataModel_ModelBundle_InternalError_GetDataSourcesFailed,
+		DataModel_ModelBundle_InternalError_RenameFailed,
+	}
+)
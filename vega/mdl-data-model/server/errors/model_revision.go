@@ -0,0 +1,40 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package errors 服务错误码
+package errors
+
+// 模型历史版本错误码
+const (
+	// 400
+	DataModel_ModelRevision_InvalidParameter_Comment  = "DataModel.ModelRevision.InvalidParameter.Comment"
+	DataModel_ModelRevision_InvalidParameter_Revision = "DataModel.ModelRevision.InvalidParameter.Revision"
+
+	// 404
+	DataModel_ModelRevision_RevisionNotFound = "DataModel.ModelRevision.RevisionNotFound"
+
+	// 500
+	DataModel_ModelRevision_InternalError_DecodeRevisionFailed = "DataModel.ModelRevision.InternalError.DecodeRevisionFailed"
+	DataModel_ModelRevision_InternalError_DeleteRevisionFailed = "DataModel.ModelRevision.InternalError.DeleteRevisionFailed"
+	DataModel_ModelRevision_InternalError_GetRevisionFailed    = "DataModel.ModelRevision.InternalError.GetRevisionFailed"
+	DataModel_ModelRevision_InternalError_SaveRevisionFailed   = "DataModel.ModelRevision.InternalError.SaveRevisionFailed"
+)
+
+var (
+	modelRevisionErrCodeList = []string{
+		// 400
+		DataModel_ModelRevision_InvalidParameter_Comment,
+		DataModel_ModelRevision_InvalidParameter_Revision,
+
+		// 404
+		DataModel_ModelRevision_RevisionNotFound,
+
+		// 500
+		DataModel_ModelRevision_InternalError_DecodeRevisionFailed,
+		DataModel_ModelRevision_InternalError_DeleteRevisionFailed,
+		DataModel_ModelRevision_InternalError_GetRevisionFailed,
+		DataModel_ModelRevision_InternalError_SaveRevisionFailed,
+	}
+)
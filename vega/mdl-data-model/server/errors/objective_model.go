@@ -16,6 +16,7 @@ const (
 	DataModel_ObjectiveModel_Duplicated_ObjectiveModelName                = "DataModel.ObjectiveModel.Duplicated.ObjectiveModelName"
 	DataModel_ObjectiveModel_Duplicated_TaskStep                          = "DataModel.ObjectiveModel.Duplicated.TaskStep"
 	DataModel_ObjectiveModel_InvalidParameter                             = "DataModel.ObjectiveModel.InvalidParameter"
+	DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert               = "DataModel.ObjectiveModel.InvalidParameter.BurnRateAlert"
 	DataModel_ObjectiveModel_InvalidParameter_ComprehensiveWeight         = "DataModel.ObjectiveModel.InvalidParameter.ComprehensiveWeight"
 	DataModel_ObjectiveModel_InvalidParameter_Objective                   = "DataModel.ObjectiveModel.InvalidParameter.Objective"
 	DataModel_ObjectiveModel_InvalidParameter_ObjectiveConfig             = "DataModel.ObjectiveModel.InvalidParameter.ObjectiveConfig"
@@ -65,6 +66,7 @@ const (
 	DataModel_ObjectiveModel_InternalError_GenerateIDFailed                   = "DataModel.ObjectiveModel.InternalError.GenerateIDFailed"
 	DataModel_ObjectiveModel_InternalError_GetMetricTasksByModelIDsFailed     = "DataModel.ObjectiveModel.InternalError.GetMetricTasksByModelIDsFailed"
 	DataModel_ObjectiveModel_InternalError_GetObjectiveModelsByModelIDsFailed = "DataModel.ObjectiveModel.InternalError.GetObjectiveModelsByModelIDsFailed"
+	DataModel_ObjectiveModel_InternalError_ListObjectiveAlertsFailed          = "DataModel.ObjectiveModel.InternalError.ListObjectiveAlertsFailed"
 )
 
 var (
@@ -103,6 +105,7 @@ var (
 		DataModel_ObjectiveModel_CountExceeded_StatusTotal,
 		DataModel_ObjectiveModel_InvalidParameter_ComprehensiveWeight,
 		DataModel_ObjectiveModel_InvalidParameter_StatusRanges,
+		DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert,
 		DataModel_ObjectiveModel_Duplicated_TaskStep,
 		DataModel_ObjectiveModel_Duplicated_ObjectiveModelName,
 		DataModel_ObjectiveModel_ModelIDExisted,
@@ -125,5 +128,6 @@ var (
 		DataModel_ObjectiveModel_InternalError_GenerateIDFailed,
 		DataModel_ObjectiveModel_InternalError_GetMetricTasksByModelIDsFailed,
 		DataModel_ObjectiveModel_InternalError_GetObjectiveModelsByModelIDsFailed,
+		DataModel_ObjectiveModel_InternalError_ListObjectiveAlertsFailed,
 	}
 )
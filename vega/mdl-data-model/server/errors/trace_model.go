@@ -26,6 +26,7 @@ const (
 	DataModel_TraceModel_InvalidParameter_ModelIDs                = "DataModel.TraceModel.InvalidParameter.ModelIDs"
 	DataModel_TraceModel_InvalidParameter_RelatedLogSourceType    = "DataModel.TraceModel.InvalidParameter.RelatedLogSourceType"
 	DataModel_TraceModel_InvalidParameter_SpanSourceType          = "DataModel.TraceModel.InvalidParameter.SpanSourceType"
+	DataModel_TraceModel_InvalidRelatedLogConfig_LogIndex         = "DataModel.TraceModel.InvalidRelatedLogConfig.LogIndex"
 	DataModel_TraceModel_InvalidRelatedLogConfig_SpanID           = "DataModel.TraceModel.InvalidRelatedLogConfig.SpanID"
 	DataModel_TraceModel_InvalidRelatedLogConfig_TraceID          = "DataModel.TraceModel.InvalidRelatedLogConfig.TraceID"
 	DataModel_TraceModel_LengthExceeded_ModelName                 = "DataModel.TraceModel.LengthExceeded.ModelName"
@@ -70,6 +71,7 @@ var (
 		DataModel_TraceModel_InvalidParameter_ModelIDs,
 		DataModel_TraceModel_InvalidParameter_RelatedLogSourceType,
 		DataModel_TraceModel_InvalidParameter_SpanSourceType,
+		DataModel_TraceModel_InvalidRelatedLogConfig_LogIndex,
 		DataModel_TraceModel_InvalidRelatedLogConfig_SpanID,
 		DataModel_TraceModel_InvalidRelatedLogConfig_TraceID,
 		DataModel_TraceModel_LengthExceeded_ModelName,
@@ -24,6 +24,10 @@ const (
 	HTTP_HEADER_ACCOUNT_TYPE    = "x-account-type"
 
 	ACCOUNT_INFO_KEY contextKey = "x-account-info" // 避免直接使用string
+	// 查询时指定的模型历史版本, 未设置时查询当前版本
+	MODEL_REVISION_KEY contextKey = "x-model-revision"
+	// 保存模型时附带的版本说明
+	REVISION_COMMENT_KEY contextKey = "x-revision-comment"
 
 	ADMIN_ID   string = "266c6a42-6131-4d62-8f39-853e7093701c"
 	ADMIN_TYPE        = string(rest.VisitorType_User)
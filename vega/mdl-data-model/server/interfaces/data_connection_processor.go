@@ -12,6 +12,7 @@ import (
 const (
 	SOURCE_TYPE_ANYROBOT = "anyrobot"
 	SOURCE_TYPE_TINGYUN  = "tingyun"
+	SOURCE_TYPE_JAEGER   = "jaeger"
 
 	APPLICATION_OBJECT_LOG_GROUP   = "log_group"
 	APPLICATION_OBJECT_TRACE_MODEL = "trace_model"
@@ -21,10 +22,11 @@ var (
 	DataSourceType2ApplicationScope = map[string][]string{
 		SOURCE_TYPE_ANYROBOT: {APPLICATION_OBJECT_LOG_GROUP},
 		SOURCE_TYPE_TINGYUN:  {APPLICATION_OBJECT_TRACE_MODEL},
+		SOURCE_TYPE_JAEGER:   {APPLICATION_OBJECT_TRACE_MODEL},
 	}
 	ApplicationObject2DataSourceTypes = map[string][]string{
 		APPLICATION_OBJECT_LOG_GROUP:   {SOURCE_TYPE_ANYROBOT},
-		APPLICATION_OBJECT_TRACE_MODEL: {SOURCE_TYPE_TINGYUN},
+		APPLICATION_OBJECT_TRACE_MODEL: {SOURCE_TYPE_TINGYUN, SOURCE_TYPE_JAEGER},
 	}
 )
 
@@ -0,0 +1,170 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+	"database/sql"
+)
+
+const (
+	MODULE_TYPE_DATA_QUALITY_RULE = "data_quality_rule"
+
+	// 数据质量规则类型
+	DATA_QUALITY_KIND_NOT_NULL        = "not_null"        // 字段非空率
+	DATA_QUALITY_KIND_UNIQUE          = "unique"          // 键集合的唯一性
+	DATA_QUALITY_KIND_ACCEPTED_VALUES = "accepted_values" // 字段值在数据字典的取值范围内
+	DATA_QUALITY_KIND_FRESHNESS       = "freshness"       // 时间字段的新鲜度
+	DATA_QUALITY_KIND_ROW_COUNT       = "row_count"       // 行数上下限
+	DATA_QUALITY_KIND_CUSTOM          = "custom"          // 自定义过滤条件
+
+	// 最近一次检查的状态
+	DATA_QUALITY_STATUS_PASSED = "passed"
+	DATA_QUALITY_STATUS_FAILED = "failed"
+	DATA_QUALITY_STATUS_ERROR  = "error" // 检查执行出错, 无法判断是否通过
+
+	// 唯一性检查默认和最大的扫描行数
+	DEFAULT_DATA_QUALITY_SAMPLE_SIZE = 10000
+	MAX_DATA_QUALITY_SAMPLE_SIZE     = 1000000
+
+	// 唯一性检查的键字段数上限
+	MAX_DATA_QUALITY_UNIQUE_FIELDS = 10
+
+	// 检查结果列表默认返回条数
+	DEFAULT_DATA_QUALITY_RESULTS_LIMIT = 100
+)
+
+var (
+	DataQualityKindMap = map[string]struct{}{
+		DATA_QUALITY_KIND_NOT_NULL:        {},
+		DATA_QUALITY_KIND_UNIQUE:          {},
+		DATA_QUALITY_KIND_ACCEPTED_VALUES: {},
+		DATA_QUALITY_KIND_FRESHNESS:       {},
+		DATA_QUALITY_KIND_ROW_COUNT:       {},
+		DATA_QUALITY_KIND_CUSTOM:          {},
+	}
+
+	DATA_QUALITY_RULE_SORT = map[string]string{
+		"update_time": "f_update_time",
+		"name":        "f_rule_name",
+	}
+)
+
+// 数据质量规则, 挂在数据视图上, 由 data-model-job 按调度通过 uniquery 执行
+type DataQualityRule struct {
+	RuleID      string                `json:"id"`
+	RuleName    string                `json:"name"`
+	ViewID      string                `json:"view_id"`
+	ViewName    string                `json:"view_name,omitempty"`
+	Kind        string                `json:"kind"`
+	Config      DataQualityRuleConfig `json:"config"`
+	Schedule    Schedule              `json:"schedule"`
+	Enabled     bool                  `json:"enabled"`
+	EmitEvent   bool                  `json:"emit_event"`
+	EventConfig *DataQualityEventCfg  `json:"event_config,omitempty"`
+	Tags        []string              `json:"tags"`
+	Comment     string                `json:"comment"`
+	CreateTime  int64                 `json:"create_time"`
+	UpdateTime  int64                 `json:"update_time"`
+	Creator     AccountInfo           `json:"creator"`
+	Updater     AccountInfo           `json:"updater"`
+
+	// 最近一次检查的状态, 由 data-model-job 回写
+	LastStatus    string `json:"last_status"`
+	LastCheckTime int64  `json:"last_check_time"`
+}
+
+// 数据质量规则的检查配置, 不同类型使用不同的字段
+type DataQualityRuleConfig struct {
+	// not_null、accepted_values、freshness 为单个字段, unique 为键字段集合
+	Fields []string `json:"fields,omitempty"`
+	// 检查范围, 只检查满足条件且在时间窗口内的数据
+	Filters    *CondCfg `json:"filters,omitempty"`
+	TimeWindow string   `json:"time_window,omitempty"`
+
+	// not_null、unique、accepted_values、custom: 达标行的占比下限, 0-1, 默认 1
+	MinRatio *float64 `json:"min_ratio,omitempty"`
+	// unique: 最多扫描的行数. 按键字段升序扫描, 超过时只检查键最小的 sample_size 行, 不是随机抽样
+	SampleSize int `json:"sample_size,omitempty"`
+	// accepted_values: 取值来源的数据字典, kv 字典取 key, 维度字典取 dict_key 指定的维度. 空值不参与检查
+	DictID  string `json:"dict_id,omitempty"`
+	DictKey string `json:"dict_key,omitempty"`
+	// freshness: 最新数据距检查时间的最大延迟
+	MaxDelay string `json:"max_delay,omitempty"`
+	// row_count: 行数上下限
+	MinCount *int64 `json:"min_count,omitempty"`
+	MaxCount *int64 `json:"max_count,omitempty"`
+	// custom: 达标行需满足的条件
+	Condition *CondCfg `json:"condition,omitempty"`
+}
+
+// 检查状态变化时发送的事件配置, 事件写入 index_base 类型的索引库
+type DataQualityEventCfg struct {
+	IndexBase string `json:"index_base"`
+	Level     int    `json:"level"`
+}
+
+// 数据质量检查结果, 每次检查一条
+type DataQualityResult struct {
+	RuleID    string         `json:"rule_id"`
+	ViewID    string         `json:"view_id"`
+	CheckTime int64          `json:"check_time"`
+	Status    string         `json:"status"`
+	Value     float64        `json:"value"`
+	Details   map[string]any `json:"details"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// 数据视图的质量概况, 供构建本体对象类的使用方判断视图是否可信
+type DataQualitySummary struct {
+	ViewID        string                   `json:"view_id"`
+	Trusted       bool                     `json:"trusted"`
+	TotalCount    int                      `json:"total_count"`
+	PassedCount   int                      `json:"passed_count"`
+	FailedCount   int                      `json:"failed_count"`
+	UnknownCount  int                      `json:"unknown_count"` // 未启用、未执行或执行出错
+	LastCheckTime int64                    `json:"last_check_time"`
+	Rules         []DataQualityRuleSummary `json:"rules"`
+}
+
+type DataQualityRuleSummary struct {
+	RuleID        string `json:"id"`
+	RuleName      string `json:"name"`
+	Kind          string `json:"kind"`
+	Enabled       bool   `json:"enabled"`
+	LastStatus    string `json:"last_status"`
+	LastCheckTime int64  `json:"last_check_time"`
+}
+
+type ListDataQualityRuleQueryParams struct {
+	NamePattern    string
+	ViewIDs        []string
+	Kind           string
+	Tag            string
+	IsInnerRequest bool
+	PaginationQueryParameters
+}
+
+type ListDataQualityResultQueryParams struct {
+	RuleID string
+	Start  int64
+	End    int64
+	Status string
+	Limit  int
+}
+
+//go:generate mockgen -source ../interfaces/data_quality_rule_access.go -destination ../interfaces/mock/mock_data_quality_rule_access.go
+type DataQualityRuleAccess interface {
+	CreateDataQualityRules(ctx context.Context, rules []*DataQualityRule) error
+	UpdateDataQualityRule(ctx context.Context, rule *DataQualityRule) error
+	GetDataQualityRules(ctx context.Context, ruleIDs []string) ([]*DataQualityRule, error)
+	ListDataQualityRules(ctx context.Context, params *ListDataQualityRuleQueryParams) ([]*DataQualityRule, error)
+	DeleteDataQualityRules(ctx context.Context, tx *sql.Tx, ruleIDs []string) error
+	DeleteDataQualityRulesByViewIDs(ctx context.Context, tx *sql.Tx, viewIDs []string) error
+	CheckDataQualityRuleExistByName(ctx context.Context, ruleName, viewID string) (bool, error)
+
+	ListDataQualityResults(ctx context.Context, params *ListDataQualityResultQueryParams) ([]*DataQualityResult, error)
+}
@@ -0,0 +1,24 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+)
+
+//go:generate mockgen -source ../interfaces/data_quality_rule_service.go -destination ../interfaces/mock/mock_data_quality_rule_service.go
+type DataQualityRuleService interface {
+	CreateDataQualityRules(ctx context.Context, rules []*DataQualityRule) ([]string, error)
+	UpdateDataQualityRule(ctx context.Context, rule *DataQualityRule) error
+	GetDataQualityRules(ctx context.Context, ruleIDs []string) ([]*DataQualityRule, error)
+	ListDataQualityRules(ctx context.Context, params *ListDataQualityRuleQueryParams) ([]*DataQualityRule, int, error)
+	DeleteDataQualityRules(ctx context.Context, ruleIDs []string) error
+
+	// 获取规则的检查结果历史
+	ListDataQualityResults(ctx context.Context, params *ListDataQualityResultQueryParams) ([]*DataQualityResult, error)
+	// 获取数据视图的质量概况
+	GetDataQualitySummaries(ctx context.Context, viewIDs []string) ([]*DataQualitySummary, error)
+}
@@ -10,6 +10,58 @@ import (
 	"database/sql"
 )
 
+const (
+	// 字段脱敏方式
+	FIELD_MASK_TYPE_PARTIAL = "partial" // 部分遮盖, 保留前后若干位
+	FIELD_MASK_TYPE_HASH    = "hash"    // 哈希, 同一视图下相同的值脱敏结果相同
+	FIELD_MASK_TYPE_NULLIFY = "nullify" // 置空
+	FIELD_MASK_TYPE_BUCKET  = "bucket"  // 数值或日期分桶
+
+	// 日期分桶的粒度
+	FIELD_MASK_BUCKET_UNIT_HOUR  = "hour"
+	FIELD_MASK_BUCKET_UNIT_DAY   = "day"
+	FIELD_MASK_BUCKET_UNIT_MONTH = "month"
+	FIELD_MASK_BUCKET_UNIT_YEAR  = "year"
+
+	DEFAULT_FIELD_MASK_CHAR = "*"
+
+	// 行过滤条件中 value_from 为 user 时, value 为调用者的属性名, 查询时替换为属性值
+	ValueFrom_User = "user"
+
+	USER_ATTR_ID            = "id"
+	USER_ATTR_TYPE          = "type"
+	USER_ATTR_NAME          = "name"
+	USER_ATTR_DEPARTMENT_ID = "department_id"
+	USER_ATTR_ROLE          = "role"
+	USER_ATTR_EMAIL         = "email"
+	USER_ATTR_CUSTOM_PREFIX = "custom_attr."
+)
+
+var (
+	FieldMaskTypeMap = map[string]struct{}{
+		FIELD_MASK_TYPE_PARTIAL: {},
+		FIELD_MASK_TYPE_HASH:    {},
+		FIELD_MASK_TYPE_NULLIFY: {},
+		FIELD_MASK_TYPE_BUCKET:  {},
+	}
+
+	FieldMaskBucketUnitMap = map[string]struct{}{
+		FIELD_MASK_BUCKET_UNIT_HOUR:  {},
+		FIELD_MASK_BUCKET_UNIT_DAY:   {},
+		FIELD_MASK_BUCKET_UNIT_MONTH: {},
+		FIELD_MASK_BUCKET_UNIT_YEAR:  {},
+	}
+
+	UserAttrMap = map[string]struct{}{
+		USER_ATTR_ID:            {},
+		USER_ATTR_TYPE:          {},
+		USER_ATTR_NAME:          {},
+		USER_ATTR_DEPARTMENT_ID: {},
+		USER_ATTR_ROLE:          {},
+		USER_ATTR_EMAIL:         {},
+	}
+)
+
 // 行列规则结构体
 type DataViewRowColumnRule struct {
 	RuleID     string      `json:"id"`
@@ -23,12 +75,28 @@ type DataViewRowColumnRule struct {
 	Creator    AccountInfo `json:"creator"`
 	Updater    AccountInfo `json:"updater"`
 	Fields     []string    `json:"fields"`
+	FieldMasks []FieldMask `json:"field_masks"`
 	RowFilters *CondCfg    `json:"row_filters"`
 
 	// 操作权限
 	Operations []string `json:"operations,omitempty"`
 }
 
+// 字段脱敏策略, 字段需在规则的列中
+type FieldMask struct {
+	Field string `json:"field"`
+	Type  string `json:"type"`
+
+	// partial
+	KeepPrefix int    `json:"keep_prefix,omitempty"`
+	KeepSuffix int    `json:"keep_suffix,omitempty"`
+	MaskChar   string `json:"mask_char,omitempty"`
+
+	// bucket, 数值字段按 bucket_size 分桶, 日期字段按 bucket_unit 截断
+	BucketSize float64 `json:"bucket_size,omitempty"`
+	BucketUnit string  `json:"bucket_unit,omitempty"`
+}
+
 type ListRowColumnRuleQueryParams struct {
 	Name           string
 	NamePattern    string
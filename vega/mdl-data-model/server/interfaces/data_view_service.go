@@ -39,6 +39,12 @@ const (
 	DataScopeNodeType_Sql    = "sql"
 	DataScopeNodeType_Output = "output"
 
+	// 派生节点：对单个输入节点做聚合、窗口函数、行列转换
+	DataScopeNodeType_Aggregate = "aggregate"
+	DataScopeNodeType_Window    = "window"
+	DataScopeNodeType_Pivot     = "pivot"
+	DataScopeNodeType_Unpivot   = "unpivot"
+
 	// join的类型
 	JoinType_Inner     = "inner"
 	JoinType_Left      = "left"
@@ -122,6 +128,11 @@ var (
 		DataScopeNodeType_Union:  {},
 		DataScopeNodeType_Sql:    {},
 		DataScopeNodeType_Output: {},
+
+		DataScopeNodeType_Aggregate: {},
+		DataScopeNodeType_Window:    {},
+		DataScopeNodeType_Pivot:     {},
+		DataScopeNodeType_Unpivot:   {},
 	}
 
 	JoinTypeMap = map[string]struct{}{
@@ -40,6 +40,7 @@ var (
 	EVENT_MODEL_DATA_SOURCE_TYPE_FOR_DATE_VIEW     = "data_view"
 	DETECT_RULE_TYPE_FOR_RANGE_DETECT              = "range_detect"
 	DETECT_RULE_TYPE_FOR_STATUS_DETECT             = "status_detect"
+	DETECT_RULE_TYPE_FOR_STATISTICAL_DETECT        = "statistical_detect"
 	DEFAULT_INDEX_BASE                             = "dip_event_model_data"
 	DEFAULT_DATA_VIEW_NAME                         = "dip_event_model_data"
 	DEFAULT_DATA_VIEW_ID                           = "__dip_event_model_data"
@@ -66,6 +67,19 @@ var (
 		"BUSYOVER":              "BUSYOVER",
 		"SHARDING_BROADCAST":    "SHARDING_BROADCAST",
 	}
+
+	// 统计检测支持的检测算法和检测方向
+	StatisticalDetectAlgo = map[string]bool{
+		"zscore":   true,
+		"ewma":     true,
+		"mad":      true,
+		"seasonal": true,
+	}
+	StatisticalDirection = map[string]bool{
+		"both": true,
+		"up":   true,
+		"down": true,
+	}
 )
 
 type FilterExpress struct {
@@ -88,12 +102,25 @@ type FormulaItem struct {
 type DetectRule struct {
 	DetectRuleID string            `json:"id,omitempty"`
 	Priority     int               `json:"priority"`
-	Type         string            `json:"type" binding:"omitempty,required,oneof=range_detect status_detect agi_detect"`
+	Type         string            `json:"type" binding:"omitempty,required,oneof=range_detect status_detect agi_detect statistical_detect"`
 	Formula      []FormulaItem     `json:"formula" binding:"dive,omitempty"`
 	CreateTime   int64             `json:"create_time,omitempty" binding:"omitempty"`
 	UpdateTime   int64             `json:"update_time,omitempty" binding:"omitempty"`
 	DetectAlgo   string            `json:"detect_algo" binding:"omitempty,required_if=Type agi_detect"`
 	AnalysisAlgo map[string]string `json:"analysis_algo" binding:"omitempty"`
+	// 统计检测配置，仅 statistical_detect 类型使用
+	StatisticalConfig *StatisticalConfig `json:"statistical_config,omitempty"`
+}
+
+// 统计检测配置，未配置的参数由 uniquery 使用默认值
+type StatisticalConfig struct {
+	Step        string  `json:"step,omitempty"`
+	Sensitivity float64 `json:"sensitivity,omitempty"`
+	Window      int     `json:"window,omitempty"`
+	WarmUp      int     `json:"warm_up,omitempty"`
+	Alpha       float64 `json:"alpha,omitempty"`
+	Weeks       int     `json:"weeks,omitempty"`
+	Direction   string  `json:"direction,omitempty"`
 }
 
 type AggregateRule struct {
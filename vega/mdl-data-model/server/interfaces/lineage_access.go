@@ -0,0 +1,110 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+	"database/sql"
+)
+
+const (
+	// 血缘节点类型
+	LINEAGE_NODE_TYPE_CATALOG         = "catalog"         // vega-backend 数据目录
+	LINEAGE_NODE_TYPE_RESOURCE        = "resource"        // vega-backend 数据资源, 由 vega-backend 登记目录到资源的边
+	LINEAGE_NODE_TYPE_DATA_VIEW       = "data_view"       // 数据视图
+	LINEAGE_NODE_TYPE_DATA_VIEW_NODE  = "data_view_node"  // 自定义视图的算子节点, id 为 <view_id>/<node_id>
+	LINEAGE_NODE_TYPE_METRIC_MODEL    = "metric_model"    // 指标模型
+	LINEAGE_NODE_TYPE_OBJECTIVE_MODEL = "objective_model" // 目标模型
+	LINEAGE_NODE_TYPE_EVENT_MODEL     = "event_model"     // 事件模型
+	LINEAGE_NODE_TYPE_OBJECT_TYPE     = "object_type"     // 本体对象类, id 为 <kn_id>/<ot_id>
+	LINEAGE_NODE_TYPE_RELATION_TYPE   = "relation_type"   // 本体关系类, id 为 <kn_id>/<rt_id>
+
+	// 血缘查询方向
+	LINEAGE_DIRECTION_UPSTREAM   = "upstream"
+	LINEAGE_DIRECTION_DOWNSTREAM = "downstream"
+	LINEAGE_DIRECTION_BOTH       = "both"
+
+	// 血缘查询的默认和最大深度
+	DEFAULT_LINEAGE_DEPTH = 3
+	MAX_LINEAGE_DEPTH     = 10
+)
+
+var (
+	LineageNodeTypeMap = map[string]struct{}{
+		LINEAGE_NODE_TYPE_CATALOG:         {},
+		LINEAGE_NODE_TYPE_RESOURCE:        {},
+		LINEAGE_NODE_TYPE_DATA_VIEW:       {},
+		LINEAGE_NODE_TYPE_DATA_VIEW_NODE:  {},
+		LINEAGE_NODE_TYPE_METRIC_MODEL:    {},
+		LINEAGE_NODE_TYPE_OBJECTIVE_MODEL: {},
+		LINEAGE_NODE_TYPE_EVENT_MODEL:     {},
+		LINEAGE_NODE_TYPE_OBJECT_TYPE:     {},
+		LINEAGE_NODE_TYPE_RELATION_TYPE:   {},
+	}
+
+	// 可由外部服务登记血缘的节点类型, data-model 自身的模型在保存时自动登记
+	LineageExternalOwnerTypeMap = map[string]struct{}{
+		LINEAGE_NODE_TYPE_RESOURCE:      {},
+		LINEAGE_NODE_TYPE_OBJECT_TYPE:   {},
+		LINEAGE_NODE_TYPE_RELATION_TYPE: {},
+	}
+
+	LineageDirectionMap = map[string]struct{}{
+		LINEAGE_DIRECTION_UPSTREAM:   {},
+		LINEAGE_DIRECTION_DOWNSTREAM: {},
+		LINEAGE_DIRECTION_BOTH:       {},
+	}
+)
+
+type LineageNode struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// 字段级血缘, 上游节点的字段流向下游节点的字段
+type LineageFieldMapping struct {
+	SourceField string `json:"source_field"`
+	TargetField string `json:"target_field"`
+}
+
+// 血缘边, 由下游模型(owner)在保存时登记, 重新保存时整体替换
+type LineageEdge struct {
+	ID         string                `json:"id"`
+	Source     LineageNode           `json:"source"`
+	Target     LineageNode           `json:"target"`
+	Fields     []LineageFieldMapping `json:"fields"`
+	Owner      LineageNode           `json:"owner"`
+	UpdateTime int64                 `json:"update_time"`
+}
+
+type LineageQuery struct {
+	Node      LineageNode
+	Field     string
+	Direction string
+	Depth     int
+}
+
+type LineageGraph struct {
+	Nodes []*LineageGraphNode `json:"nodes"`
+	Edges []*LineageEdge      `json:"edges"`
+}
+
+type LineageGraphNode struct {
+	LineageNode
+	// 与起始节点的距离, 上游为负数, 下游为正数
+	Depth int `json:"depth"`
+	// 字段级查询时, 节点上关联的字段
+	Fields []string `json:"fields,omitempty"`
+}
+
+//go:generate mockgen -source ../interfaces/lineage_access.go -destination ../interfaces/mock/mock_lineage_access.go
+type LineageAccess interface {
+	// 替换 owner 登记的所有血缘边
+	ReplaceLineageEdges(ctx context.Context, tx *sql.Tx, owner LineageNode, edges []*LineageEdge) error
+	DeleteLineageEdgesByOwners(ctx context.Context, tx *sql.Tx, ownerType string, ownerIDs []string) error
+	// 按方向获取与节点相连的边, upstream 取以节点为下游的边, downstream 取以节点为上游的边
+	ListLineageEdgesByNodes(ctx context.Context, direction string, nodes []LineageNode) ([]*LineageEdge, error)
+}
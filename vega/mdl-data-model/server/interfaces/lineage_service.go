@@ -0,0 +1,28 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+	"database/sql"
+)
+
+//go:generate mockgen -source ../interfaces/lineage_service.go -destination ../interfaces/mock/mock_lineage_service.go
+type LineageService interface {
+	// 从起始节点按方向和深度展开血缘图, 指定字段时只沿包含该字段的边展开
+	GetLineage(ctx context.Context, query *LineageQuery) (*LineageGraph, error)
+
+	// 外部服务(如本体)登记或删除其模型的血缘
+	ReplaceLineageEdges(ctx context.Context, owner LineageNode, edges []*LineageEdge) error
+	DeleteLineageEdges(ctx context.Context, owner LineageNode) error
+
+	// data-model 内部模型在保存和删除的事务内维护血缘
+	SaveDataViewLineage(ctx context.Context, tx *sql.Tx, views []*DataView) error
+	SaveMetricModelLineage(ctx context.Context, tx *sql.Tx, models []*MetricModel) error
+	SaveObjectiveModelLineage(ctx context.Context, tx *sql.Tx, models []*ObjectiveModel) error
+	SaveEventModelLineage(ctx context.Context, tx *sql.Tx, models []*EventModel) error
+	DeleteModelLineage(ctx context.Context, tx *sql.Tx, ownerType string, ownerIDs []string) error
+}
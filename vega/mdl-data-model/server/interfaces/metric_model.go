@@ -31,6 +31,7 @@ const (
 	// 数据源类型
 	// DATA_SOURCE_DATA_VIEW       = "data_view"
 	// DATA_SOURCE_VEGA_LOGIC_VIEW = "vega_logic_view"
+	DATA_SOURCE_TRACE_MODEL = "trace_model"
 
 	// measure prefix
 	MEASURE_PREFIX = "__m."
@@ -48,6 +49,8 @@ const (
 	DSL        string = "dsl"
 	DSL_CONFIG string = "dsl_config"
 	SQL        string = "sql"
+	// 以链路模型为数据源的RED指标, data_source.id 为链路模型id
+	RED string = "red"
 
 	// promql dateField & metricField
 	PROMQL_DATEFIELD   string = "@timestamp"
@@ -129,6 +132,16 @@ const (
 
 	// xxl-job任务执行超时时间.单位是秒.设置大一点,在高基序列,高追溯时长的情况下,第一次任务触发的耗时比较大.设置为24h
 	XXL_JOB_TASK_EXEC_TIMEOUT int = 86400
+
+	// 持久化任务的最小计划时间间隔，5min，与 data-model-job 保持一致
+	METRIC_TASK_MIN_STEP int64 = 300000
+
+	// 补数任务状态
+	BACKFILL_STATUS_PENDING   = "pending"   // 待执行
+	BACKFILL_STATUS_RUNNING   = "running"   // 执行中
+	BACKFILL_STATUS_SUCCEEDED = "succeeded" // 执行成功
+	BACKFILL_STATUS_FAILED    = "failed"    // 执行失败
+	BACKFILL_STATUS_CANCELED  = "canceled"  // 已取消
 )
 
 var (
@@ -142,6 +155,16 @@ var (
 		"group_name": "f_group_name",
 	}
 
+	// 链路模型支持的RED指标
+	RED_METRICS = map[string]struct{}{
+		"rate":         {},
+		"errors":       {},
+		"error_rate":   {},
+		"duration_p50": {},
+		"duration_p95": {},
+		"duration_p99": {},
+	}
+
 	MEASURE_FIELD_TYPE = map[string]string{
 		"long":          "long",
 		"integer":       "integer",
@@ -351,6 +374,13 @@ type DerivedConfig struct {
 	ConditionStr      string             `json:"condition_str,omitempty"`
 }
 
+// RED指标配置项, 服务和操作为空时不过滤
+type REDConfig struct {
+	Metric      string `json:"metric"`
+	ServiceName string `json:"service_name,omitempty"`
+	Operation   string `json:"operation,omitempty"`
+}
+
 type DependMetricModel struct {
 	ID        string `json:"id"`
 	GroupName string `json:"group_name,omitempty"`
@@ -484,6 +514,7 @@ type MetricTask struct {
 	UpdateTime         int64       `json:"update_time"`
 	PlanTime           int64       `json:"plan_time"`
 	Creator            AccountInfo `json:"creator"`
+	Owner              string      `json:"owner,omitempty"` // 运行任务的 data-model-job 副本
 }
 
 type Schedule struct {
@@ -491,6 +522,32 @@ type Schedule struct {
 	Expression string `json:"expression"`
 }
 
+// 持久化任务的补数任务，按时间范围重新计算指标模型或目标模型并覆盖已持久化的数据
+type MetricTaskBackfill struct {
+	ID            string      `json:"id"`
+	TaskID        string      `json:"task_id"`
+	ModelID       string      `json:"model_id"`
+	ModuleType    string      `json:"module_type"`
+	Start         int64       `json:"start"`
+	End           int64       `json:"end"`
+	Steps         []string    `json:"steps"`
+	Status        string      `json:"status"`
+	StatusDetails string      `json:"status_details"`
+	Checkpoint    int64       `json:"checkpoint"` // 下一个待执行的计划时间点
+	TotalPoints   int64       `json:"total_points"`
+	DonePoints    int64       `json:"done_points"`
+	Progress      float64     `json:"progress"` // 已完成的时间点占比，0-1
+	CreateTime    int64       `json:"create_time"`
+	UpdateTime    int64       `json:"update_time"`
+	Creator       AccountInfo `json:"creator"`
+}
+
+// 补数任务列表查询参数
+type MetricTaskBackfillQueryParams struct {
+	ModelID string
+	Status  string
+}
+
 type TaskSyncStatus struct {
 	SyncStatus int
 	UpdateTime int64
@@ -503,7 +560,7 @@ func IsValidMetricType(m string) bool {
 }
 
 func IsValidQueryType(m string) bool {
-	return m == PROMQL || m == DSL || m == DSL_CONFIG || m == SQL
+	return m == PROMQL || m == DSL || m == DSL_CONFIG || m == SQL || m == RED
 }
 
 func IsValidUnitType(m string) bool {
@@ -15,6 +15,7 @@ type MetricModelTaskAccess interface {
 	CreateMetricTask(ctx context.Context, tx *sql.Tx, metricTasks MetricTask) error
 	GetMetricTaskIDsByModelIDs(ctx context.Context, modelIDs []string) ([]string, error)
 	GetMetricTasksByTaskIDs(ctx context.Context, taskIDs []string) ([]MetricTask, error)
+	GetMetricTaskOwners(ctx context.Context, taskIDs []string) (map[string]string, error)
 	GetMetricTasksByModelIDs(ctx context.Context, modelIDs []string) (map[string]MetricTask, error)
 	// GetProcessingMetricTasks(ctx context.Context) ([]MetricTask, error)
 	UpdateMetricTask(ctx context.Context, tx *sql.Tx, task MetricTask) error
@@ -25,4 +25,9 @@ type MetricModelTaskService interface {
 	DeleteMetricTaskByTaskIDs(ctx context.Context, tx *sql.Tx, taskIDs []string) error
 
 	CheckMetricModelTaskExistByName(ctx context.Context, task MetricTask, deleteTaskIDs []string) (bool, error)
+
+	CreateMetricTaskBackfill(ctx context.Context, backfill MetricTaskBackfill) (string, error)
+	GetMetricTaskBackfill(ctx context.Context, backfillID string) (MetricTaskBackfill, error)
+	ListMetricTaskBackfills(ctx context.Context, params MetricTaskBackfillQueryParams) ([]MetricTaskBackfill, error)
+	CancelMetricTaskBackfill(ctx context.Context, backfillID string) error
 }
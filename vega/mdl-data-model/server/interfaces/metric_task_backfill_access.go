@@ -0,0 +1,19 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+)
+
+//go:generate mockgen -source ../interfaces/metric_task_backfill_access.go -destination ../interfaces/mock/mock_metric_task_backfill_access.go
+type MetricTaskBackfillAccess interface {
+	CreateMetricTaskBackfill(ctx context.Context, backfill MetricTaskBackfill) error
+	GetMetricTaskBackfill(ctx context.Context, backfillID string) (MetricTaskBackfill, bool, error)
+	ListMetricTaskBackfills(ctx context.Context, params MetricTaskBackfillQueryParams) ([]MetricTaskBackfill, error)
+	// 只取消未结束的补数任务，返回是否取消成功
+	CancelMetricTaskBackfill(ctx context.Context, backfillID string, updateTime int64) (bool, error)
+}
@@ -99,6 +99,21 @@ func (mr *MockMetricModelTaskAccessMockRecorder) GetMetricTaskIDsByModelIDs(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricTaskIDsByModelIDs", reflect.TypeOf((*MockMetricModelTaskAccess)(nil).GetMetricTaskIDsByModelIDs), ctx, modelIDs)
 }
 
+// GetMetricTaskOwners mocks base method.
+func (m *MockMetricModelTaskAccess) GetMetricTaskOwners(ctx context.Context, taskIDs []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetricTaskOwners", ctx, taskIDs)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetricTaskOwners indicates an expected call of GetMetricTaskOwners.
+func (mr *MockMetricModelTaskAccessMockRecorder) GetMetricTaskOwners(ctx, taskIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricTaskOwners", reflect.TypeOf((*MockMetricModelTaskAccess)(nil).GetMetricTaskOwners), ctx, taskIDs)
+}
+
 // GetMetricTasksByModelIDs mocks base method.
 func (m *MockMetricModelTaskAccess) GetMetricTasksByModelIDs(ctx context.Context, modelIDs []string) (map[string]interfaces.MetricTask, error) {
 	m.ctrl.T.Helper()
@@ -41,6 +41,20 @@ func (m *MockMetricModelTaskService) EXPECT() *MockMetricModelTaskServiceMockRec
 	return m.recorder
 }
 
+// CancelMetricTaskBackfill mocks base method.
+func (m *MockMetricModelTaskService) CancelMetricTaskBackfill(ctx context.Context, backfillID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelMetricTaskBackfill", ctx, backfillID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelMetricTaskBackfill indicates an expected call of CancelMetricTaskBackfill.
+func (mr *MockMetricModelTaskServiceMockRecorder) CancelMetricTaskBackfill(ctx, backfillID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelMetricTaskBackfill", reflect.TypeOf((*MockMetricModelTaskService)(nil).CancelMetricTaskBackfill), ctx, backfillID)
+}
+
 // CheckMetricModelTaskExistByName mocks base method.
 func (m *MockMetricModelTaskService) CheckMetricModelTaskExistByName(ctx context.Context, task interfaces.MetricTask, deleteTaskIDs []string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +84,21 @@ func (mr *MockMetricModelTaskServiceMockRecorder) CreateMetricTask(ctx, tx, task
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMetricTask", reflect.TypeOf((*MockMetricModelTaskService)(nil).CreateMetricTask), ctx, tx, tasks)
 }
 
+// CreateMetricTaskBackfill mocks base method.
+func (m *MockMetricModelTaskService) CreateMetricTaskBackfill(ctx context.Context, backfill interfaces.MetricTaskBackfill) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMetricTaskBackfill", ctx, backfill)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMetricTaskBackfill indicates an expected call of CreateMetricTaskBackfill.
+func (mr *MockMetricModelTaskServiceMockRecorder) CreateMetricTaskBackfill(ctx, backfill interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMetricTaskBackfill", reflect.TypeOf((*MockMetricModelTaskService)(nil).CreateMetricTaskBackfill), ctx, backfill)
+}
+
 // DeleteMetricTaskByTaskIDs mocks base method.
 func (m *MockMetricModelTaskService) DeleteMetricTaskByTaskIDs(ctx context.Context, tx *sql.Tx, taskIDs []string) error {
 	m.ctrl.T.Helper()
@@ -84,6 +113,21 @@ func (mr *MockMetricModelTaskServiceMockRecorder) DeleteMetricTaskByTaskIDs(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMetricTaskByTaskIDs", reflect.TypeOf((*MockMetricModelTaskService)(nil).DeleteMetricTaskByTaskIDs), ctx, tx, taskIDs)
 }
 
+// GetMetricTaskBackfill mocks base method.
+func (m *MockMetricModelTaskService) GetMetricTaskBackfill(ctx context.Context, backfillID string) (interfaces.MetricTaskBackfill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetricTaskBackfill", ctx, backfillID)
+	ret0, _ := ret[0].(interfaces.MetricTaskBackfill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetricTaskBackfill indicates an expected call of GetMetricTaskBackfill.
+func (mr *MockMetricModelTaskServiceMockRecorder) GetMetricTaskBackfill(ctx, backfillID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricTaskBackfill", reflect.TypeOf((*MockMetricModelTaskService)(nil).GetMetricTaskBackfill), ctx, backfillID)
+}
+
 // GetMetricTaskIDsByModelIDs mocks base method.
 func (m *MockMetricModelTaskService) GetMetricTaskIDsByModelIDs(ctx context.Context, modelIDs []string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -129,6 +173,21 @@ func (mr *MockMetricModelTaskServiceMockRecorder) GetMetricTasksByTaskIDs(ctx, t
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetricTasksByTaskIDs", reflect.TypeOf((*MockMetricModelTaskService)(nil).GetMetricTasksByTaskIDs), ctx, taskIDs)
 }
 
+// ListMetricTaskBackfills mocks base method.
+func (m *MockMetricModelTaskService) ListMetricTaskBackfills(ctx context.Context, params interfaces.MetricTaskBackfillQueryParams) ([]interfaces.MetricTaskBackfill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMetricTaskBackfills", ctx, params)
+	ret0, _ := ret[0].([]interfaces.MetricTaskBackfill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMetricTaskBackfills indicates an expected call of ListMetricTaskBackfills.
+func (mr *MockMetricModelTaskServiceMockRecorder) ListMetricTaskBackfills(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMetricTaskBackfills", reflect.TypeOf((*MockMetricModelTaskService)(nil).ListMetricTaskBackfills), ctx, params)
+}
+
 // UpdateMetricTask mocks base method.
 func (m *MockMetricModelTaskService) UpdateMetricTask(ctx context.Context, tx *sql.Tx, task interfaces.MetricTask) error {
 	m.ctrl.T.Helper()
@@ -87,6 +87,20 @@ func (mr *MockObjectiveModelAccessMockRecorder) CreateObjectiveModel(ctx, tx, ob
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateObjectiveModel", reflect.TypeOf((*MockObjectiveModelAccess)(nil).CreateObjectiveModel), ctx, tx, objectiveModel)
 }
 
+// DeleteObjectiveAlerts mocks base method.
+func (m *MockObjectiveModelAccess) DeleteObjectiveAlerts(ctx context.Context, tx *sql.Tx, modelIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteObjectiveAlerts", ctx, tx, modelIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteObjectiveAlerts indicates an expected call of DeleteObjectiveAlerts.
+func (mr *MockObjectiveModelAccessMockRecorder) DeleteObjectiveAlerts(ctx, tx, modelIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObjectiveAlerts", reflect.TypeOf((*MockObjectiveModelAccess)(nil).DeleteObjectiveAlerts), ctx, tx, modelIDs)
+}
+
 // DeleteObjectiveModels mocks base method.
 func (m *MockObjectiveModelAccess) DeleteObjectiveModels(ctx context.Context, tx *sql.Tx, modelIDs []string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -132,6 +146,21 @@ func (mr *MockObjectiveModelAccessMockRecorder) GetObjectiveModelsTotal(ctx, mod
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectiveModelsTotal", reflect.TypeOf((*MockObjectiveModelAccess)(nil).GetObjectiveModelsTotal), ctx, modelsQuery)
 }
 
+// ListObjectiveAlerts mocks base method.
+func (m *MockObjectiveModelAccess) ListObjectiveAlerts(ctx context.Context, modelID string) ([]interfaces.ObjectiveAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObjectiveAlerts", ctx, modelID)
+	ret0, _ := ret[0].([]interfaces.ObjectiveAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListObjectiveAlerts indicates an expected call of ListObjectiveAlerts.
+func (mr *MockObjectiveModelAccessMockRecorder) ListObjectiveAlerts(ctx, modelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectiveAlerts", reflect.TypeOf((*MockObjectiveModelAccess)(nil).ListObjectiveAlerts), ctx, modelID)
+}
+
 // ListObjectiveModels mocks base method.
 func (m *MockObjectiveModelAccess) ListObjectiveModels(ctx context.Context, modelsQuery interfaces.ObjectiveModelsQueryParams) ([]interfaces.ObjectiveModel, error) {
 	m.ctrl.T.Helper()
@@ -134,6 +134,21 @@ func (mr *MockObjectiveModelServiceMockRecorder) ListObjectiveModelSrcs(ctx, par
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectiveModelSrcs", reflect.TypeOf((*MockObjectiveModelService)(nil).ListObjectiveModelSrcs), ctx, parameter)
 }
 
+// ListObjectiveAlerts mocks base method.
+func (m *MockObjectiveModelService) ListObjectiveAlerts(ctx context.Context, modelID string) ([]interfaces.ObjectiveAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObjectiveAlerts", ctx, modelID)
+	ret0, _ := ret[0].([]interfaces.ObjectiveAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListObjectiveAlerts indicates an expected call of ListObjectiveAlerts.
+func (mr *MockObjectiveModelServiceMockRecorder) ListObjectiveAlerts(ctx, modelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectiveAlerts", reflect.TypeOf((*MockObjectiveModelService)(nil).ListObjectiveAlerts), ctx, modelID)
+}
+
 // ListObjectiveModels mocks base method.
 func (m *MockObjectiveModelService) ListObjectiveModels(ctx context.Context, parameter interfaces.ObjectiveModelsQueryParams) ([]interfaces.ObjectiveModel, int, error) {
 	m.ctrl.T.Helper()
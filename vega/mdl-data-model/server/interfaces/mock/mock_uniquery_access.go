@@ -70,3 +70,17 @@ func (mr *MockUniqueryAccessMockRecorder) CheckFormulaByUniquery(ctx, query inte
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckFormulaByUniquery", reflect.TypeOf((*MockUniqueryAccess)(nil).CheckFormulaByUniquery), ctx, query)
 }
+
+// DeleteMetricModelCache mocks base method.
+func (m *MockUniqueryAccess) DeleteMetricModelCache(ctx context.Context, modelIDs []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMetricModelCache", ctx, modelIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMetricModelCache indicates an expected call of DeleteMetricModelCache.
+func (mr *MockUniqueryAccessMockRecorder) DeleteMetricModelCache(ctx, modelIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMetricModelCache", reflect.TypeOf((*MockUniqueryAccess)(nil).DeleteMetricModelCache), ctx, modelIDs)
+}
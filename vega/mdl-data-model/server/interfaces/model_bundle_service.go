@@ -0,0 +1,101 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+)
+
+const (
+	MODEL_BUNDLE_VERSION = "1.0"
+
+	MODEL_BUNDLE_FORMAT_JSON = "json"
+	MODEL_BUNDLE_FORMAT_YAML = "yaml"
+
+	// 导入模式: 存在冲突时报错、覆盖已有对象、冲突对象重命名后作为新对象导入
+	MODEL_BUNDLE_IMPORT_MODE_CREATE_ONLY = "create_only"
+	MODEL_BUNDLE_IMPORT_MODE_OVERWRITE   = "overwrite"
+	MODEL_BUNDLE_IMPORT_MODE_RENAME      = "rename"
+
+	QueryParam_DryRun = "dry_run"
+
+	MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW_GROUP    = "data_view_group"
+	MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW          = "data_view"
+	MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL_GROUP = "metric_model_group"
+	MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL       = "metric_model"
+	MODEL_BUNDLE_ASSET_TYPE_EVENT_MODEL        = "event_model"
+	MODEL_BUNDLE_ASSET_TYPE_OBJECTIVE_MODEL    = "objective_model"
+
+	// 导入计划中对象的处理方式, 分组按名称匹配, 已存在的直接复用
+	MODEL_BUNDLE_ACTION_CREATE   = "create"
+	MODEL_BUNDLE_ACTION_UPDATE   = "update"
+	MODEL_BUNDLE_ACTION_RENAME   = "rename"
+	MODEL_BUNDLE_ACTION_EXIST    = "exist"
+	MODEL_BUNDLE_ACTION_CONFLICT = "conflict"
+
+	// 冲突的属性
+	MODEL_BUNDLE_CONFLICT_ID           = "id"
+	MODEL_BUNDLE_CONFLICT_NAME         = "name"
+	MODEL_BUNDLE_CONFLICT_MEASURE_NAME = "measure_name"
+
+	// 重命名时追加的后缀, 已存在时依次追加序号
+	MODEL_BUNDLE_RENAME_SUFFIX = "_imported"
+)
+
+// 导出时选择的对象, 依赖的对象会一并导出
+type ModelBundleExportRequest struct {
+	DataViewIDs         []string `json:"data_view_ids"`
+	DataViewGroupIDs    []string `json:"data_view_group_ids"`
+	MetricModelIDs      []string `json:"metric_model_ids"`
+	MetricModelGroupIDs []string `json:"metric_model_group_ids"`
+	EventModelIDs       []string `json:"event_model_ids"`
+	ObjectiveModelIDs   []string `json:"objective_model_ids"`
+}
+
+// 模型包, 各类对象按依赖顺序排列
+type ModelBundle struct {
+	Version           string                   `json:"version"`
+	ExportTime        int64                    `json:"export_time"`
+	DataSources       []*ModelBundleDataSource `json:"data_sources"`
+	DataViewGroups    []*DataViewGroup         `json:"data_view_groups"`
+	DataViews         []*DataView              `json:"data_views"`
+	MetricModelGroups []*MetricModelGroup      `json:"metric_model_groups"`
+	MetricModels      []*MetricModel           `json:"metric_models"`
+	EventModels       []*EventModel            `json:"event_models"`
+	ObjectiveModels   []*ObjectiveModel        `json:"objective_models"`
+}
+
+// 原子视图依赖的数据连接, 只记录标识信息, 不导出连接配置
+type ModelBundleDataSource struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// 单个对象的导入计划
+type ModelBundleImportItem struct {
+	AssetType  string   `json:"asset_type"`
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Action     string   `json:"action"`
+	TargetID   string   `json:"target_id,omitempty"`
+	TargetName string   `json:"target_name,omitempty"`
+	Conflicts  []string `json:"conflicts,omitempty"`
+}
+
+type ModelBundleImportResult struct {
+	Mode               string                   `json:"mode"`
+	DryRun             bool                     `json:"dry_run"`
+	Items              []*ModelBundleImportItem `json:"items"`
+	MissingDataSources []*ModelBundleDataSource `json:"missing_data_sources"`
+}
+
+//go:generate mockgen -source ../interfaces/model_bundle_service.go -destination ../interfaces/mock/mock_model_bundle_service.go
+type ModelBundleService interface {
+	ExportModelBundle(ctx context.Context, req *ModelBundleExportRequest) (*ModelBundle, error)
+	// dryRun 时只返回导入计划; 否则存在冲突或缺少数据连接时报错, 不导入任何对象; 导入中途失败时回滚已导入的对象
+	ImportModelBundle(ctx context.Context, bundle *ModelBundle, mode string, dryRun bool) (*ModelBundleImportResult, error)
+}
@@ -0,0 +1,86 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+	"database/sql"
+)
+
+const (
+	// 记录历史版本的模型类型
+	REVISION_MODEL_TYPE_METRIC_MODEL    = "metric_model"
+	REVISION_MODEL_TYPE_DATA_VIEW       = "data_view"
+	REVISION_MODEL_TYPE_OBJECTIVE_MODEL = "objective_model"
+
+	// 版本差异类型
+	REVISION_CHANGE_ADDED    = "added"
+	REVISION_CHANGE_REMOVED  = "removed"
+	REVISION_CHANGE_MODIFIED = "modified"
+
+	REVISION_COMMENT_MAX_LENGTH = 255
+)
+
+var (
+	// 历史版本对应的权限资源类型
+	RevisionResourceTypeMap = map[string]string{
+		REVISION_MODEL_TYPE_METRIC_MODEL:    RESOURCE_TYPE_METRIC_MODEL,
+		REVISION_MODEL_TYPE_DATA_VIEW:       RESOURCE_TYPE_DATA_VIEW,
+		REVISION_MODEL_TYPE_OBJECTIVE_MODEL: RESOURCE_TYPE_OBJECTIVE_MODEL,
+	}
+
+	REVISION_SORT = map[string]string{
+		"revision":    "f_revision",
+		"create_time": "f_create_time",
+	}
+)
+
+// 模型历史版本, 每次保存模型时记录一份不可变的定义快照
+type ModelRevision struct {
+	ModelType  string      `json:"model_type"`
+	ModelID    string      `json:"model_id"`
+	Revision   int         `json:"revision"`
+	ModelName  string      `json:"model_name"`
+	Comment    string      `json:"comment"`
+	Creator    AccountInfo `json:"creator"`
+	CreateTime int64       `json:"create_time"`
+	// 模型定义的 json 快照, 列表中不返回
+	Content string `json:"-"`
+	// 详情中返回的模型定义
+	Model any `json:"model,omitempty"`
+}
+
+type ModelRevisionsQueryParams struct {
+	PaginationQueryParameters
+	ModelType string
+	ModelID   string
+}
+
+// 两个版本之间的差异, 路径形如 formula_config.group_by_fields[0]
+type RevisionChange struct {
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	OldValue any    `json:"old_value,omitempty"`
+	NewValue any    `json:"new_value,omitempty"`
+}
+
+type ModelRevisionDiff struct {
+	ModelType    string            `json:"model_type"`
+	ModelID      string            `json:"model_id"`
+	FromRevision int               `json:"from_revision"`
+	ToRevision   int               `json:"to_revision"`
+	Changes      []*RevisionChange `json:"changes"`
+}
+
+//go:generate mockgen -source ../interfaces/model_revision_access.go -destination ../interfaces/mock/mock_model_revision_access.go
+type ModelRevisionAccess interface {
+	// 写入版本, 版本号取当前最大版本号加一, 应与模型的保存在同一事务内执行
+	CreateModelRevision(ctx context.Context, tx *sql.Tx, revision *ModelRevision) error
+	ListModelRevisions(ctx context.Context, query ModelRevisionsQueryParams) ([]*ModelRevision, error)
+	GetModelRevisionsTotal(ctx context.Context, query ModelRevisionsQueryParams) (int, error)
+	GetModelRevision(ctx context.Context, modelType string, modelID string, revision int) (*ModelRevision, bool, error)
+	DeleteModelRevisions(ctx context.Context, tx *sql.Tx, modelType string, modelIDs []string) error
+}
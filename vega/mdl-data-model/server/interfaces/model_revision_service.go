@@ -0,0 +1,23 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+	"database/sql"
+)
+
+//go:generate mockgen -source ../interfaces/model_revision_service.go -destination ../interfaces/mock/mock_model_revision_service.go
+type ModelRevisionService interface {
+	ListModelRevisions(ctx context.Context, query ModelRevisionsQueryParams) ([]*ModelRevision, int, error)
+	// 获取版本详情, 模型定义已按模型类型反序列化
+	GetModelRevision(ctx context.Context, modelType string, modelID string, revision int) (*ModelRevision, error)
+	DiffModelRevisions(ctx context.Context, modelType string, modelID string, fromRevision int, toRevision int) (*ModelRevisionDiff, error)
+
+	// 模型创建和修改时在同一事务内记录版本, 删除模型时一并删除其版本
+	SaveModelRevision(ctx context.Context, tx *sql.Tx, modelType string, modelID string, modelName string, model any) error
+	DeleteModelRevisions(ctx context.Context, tx *sql.Tx, modelType string, modelIDs []string) error
+}
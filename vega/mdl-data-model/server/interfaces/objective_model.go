@@ -25,6 +25,12 @@ const (
 	ADDITIONAL_METRIC_TOTAL = 5
 	// 状态个数
 	STATUS_TOTAL = 10
+	// 燃烧率告警策略最大个数
+	BURN_RATE_POLICY_TOTAL = 5
+
+	// 燃烧率告警状态
+	OBJECTIVE_ALERT_STATUS_FIRING   = "firing"
+	OBJECTIVE_ALERT_STATUS_RESOLVED = "resolved"
 )
 
 var (
@@ -67,6 +73,39 @@ type SLOObjective struct {
 	GoodMetricModel  *BundleMetricModel     `json:"good_metric_model"`
 	TotalMetricModel *BundleMetricModel     `json:"total_metric_model"`
 	StatusConfig     *ObjectiveStatusConfig `json:"status_config"`
+	BurnRateAlert    *BurnRateAlertConfig   `json:"burn_rate_alert,omitempty"`
+}
+
+// SLO 多窗口多燃烧率告警配置，告警事件写入 index_base 对应的索引库
+type BurnRateAlertConfig struct {
+	IndexBase string           `json:"index_base"`
+	Policies  []BurnRatePolicy `json:"policies"`
+}
+
+// 燃烧率告警策略，长短两个窗口的燃烧率同时达到阈值时触发
+type BurnRatePolicy struct {
+	Name        string  `json:"name"`
+	LongWindow  string  `json:"long_window"`
+	ShortWindow string  `json:"short_window"`
+	BurnRate    float64 `json:"burn_rate"` // 燃烧率倍数，如 14.4 表示以 14.4 倍的速度消耗错误预算
+	Level       int     `json:"level"`     // 触发事件的级别，同事件模型的级别 1-5
+}
+
+// 目标模型的燃烧率告警状态，由 data-model-job 按序列和策略维护
+type ObjectiveAlert struct {
+	ModelID       string            `json:"model_id"`
+	PolicyName    string            `json:"policy_name"`
+	SeriesID      string            `json:"series_id"`
+	Labels        map[string]string `json:"labels"`
+	Status        string            `json:"status"`
+	Level         int               `json:"level"`
+	Threshold     float64           `json:"threshold"`
+	LongBurnRate  float64           `json:"long_burn_rate"`
+	ShortBurnRate float64           `json:"short_burn_rate"`
+	ActiveSince   int64             `json:"active_since"`
+	LastEvalTime  int64             `json:"last_eval_time"`
+	LastEventID   string            `json:"last_event_id"`
+	LastEventTime int64             `json:"last_event_time"`
 }
 
 type KPIObjective struct {
@@ -21,4 +21,7 @@ type ObjectiveModelAccess interface {
 	GetObjectiveModelsByModelIDs(ctx context.Context, modelIDs []string) ([]ObjectiveModel, error)
 	UpdateObjectiveModel(ctx context.Context, tx *sql.Tx, objectiveModel ObjectiveModel) error
 	DeleteObjectiveModels(ctx context.Context, tx *sql.Tx, modelIDs []string) (int64, error)
+
+	ListObjectiveAlerts(ctx context.Context, modelID string) ([]ObjectiveAlert, error)
+	DeleteObjectiveAlerts(ctx context.Context, tx *sql.Tx, modelIDs []string) error
 }
@@ -19,6 +19,7 @@ type ObjectiveModelService interface {
 	GetObjectiveModels(ctx context.Context, modelIDs []string) ([]ObjectiveModel, error)
 	UpdateObjectiveModel(ctx context.Context, tx *sql.Tx, objectiveModel ObjectiveModel) error
 	DeleteObjectiveModels(ctx context.Context, modelIDs []string) (int64, error)
+	ListObjectiveAlerts(ctx context.Context, modelID string) ([]ObjectiveAlert, error)
 
 	// 获取指标模型的资源实例列表
 	ListObjectiveModelSrcs(ctx context.Context, parameter ObjectiveModelsQueryParams) ([]Resource, int, error)
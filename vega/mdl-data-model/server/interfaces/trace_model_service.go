@@ -110,6 +110,7 @@ type SpanConfigWithDataView struct {
 
 type SpanConfigWithDataConnection struct {
 	DataConnection DataConnectionConfig `json:"data_connection"`
+	FieldMapping   *SpanFieldMapping    `json:"field_mapping,omitempty"`
 }
 
 // span关联日志配置
@@ -119,6 +120,27 @@ type RelatedLogConfigWithDataView struct {
 	SpanID   SpanIDConfig   `json:"span_id"`
 }
 
+// 基于span数据连接的关联日志配置, 日志与span存储在同一个数据连接中
+type RelatedLogConfigWithDataConnection struct {
+	LogIndex string        `json:"log_index"`
+	TraceID  TraceIDConfig `json:"trace_id"`
+	SpanID   SpanIDConfig  `json:"span_id"`
+}
+
+// 数据连接中span原始字段的映射配置, 未配置的项使用数据源的默认值
+type SpanFieldMapping struct {
+	SpanIndex    string          `json:"span_index"`
+	TraceID      string          `json:"trace_id"`
+	SpanID       string          `json:"span_id"`
+	ParentSpanID string          `json:"parent_span_id"`
+	Name         string          `json:"name"`
+	StartTime    StartTimeConfig `json:"start_time"`
+	Duration     DurationConfig  `json:"duration"`
+	ServiceName  string          `json:"service_name"`
+	KindTag      string          `json:"kind_tag"`
+	StatusTag    string          `json:"status_tag"`
+}
+
 // 数据视图配置
 type DataViewConfig struct {
 	ID   string `json:"id"`
@@ -36,6 +36,8 @@ type DataViewSql struct {
 type UniqueryAccess interface {
 	// 指标模型
 	CheckFormulaByUniquery(ctx context.Context, query MetricModelQuery) (bool, string, error)
+	// 删除指标模型在 uniquery 中的查询结果缓存
+	DeleteMetricModelCache(ctx context.Context, modelIDs []string) error
 
 	// 数据视图
 	BuildDataViewSql(ctx context.Context, view *DataView) (string, error)
@@ -0,0 +1,352 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package jaeger
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+var (
+	jcProcessorOnce sync.Once
+	jcProcessor     interfaces.DataConnectionProcessor
+)
+
+// Jaeger(OpenSearch存储)数据连接详细配置
+// access_token为username:password的base64编码, 供uniquery以Basic认证访问OpenSearch
+type jaegerDetailedConfig struct {
+	Address     string `json:"address"`
+	Protocol    string `json:"protocol"`
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	AccessToken string `json:"access_token,omitempty"`
+}
+
+type jaegerConnectionProcessor struct {
+	appSetting *common.AppSetting
+	httpClient rest.HTTPClient
+}
+
+func NewJaegerConnectionProcessor(appSetting *common.AppSetting) interfaces.DataConnectionProcessor {
+	jcProcessorOnce.Do(func() {
+		jcProcessor = &jaegerConnectionProcessor{
+			appSetting: appSetting,
+			httpClient: common.NewHTTPClient(),
+		}
+	})
+	return jcProcessor
+}
+
+func (jcp *jaegerConnectionProcessor) ValidateWhenCreate(ctx context.Context, conn *interfaces.DataConnection) (err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 处理待创建的数据连接")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	// 1. detailedConfig由any转为jaegerDetailedConfig
+	conf, err := jcp.any2JaegerDetailedConfig(ctx, conn.DataSourceConfig)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return err
+	}
+
+	// 2. 校验jaegerDetailedConfig
+	err = jcp.commonValidateWhenCreateAndUpdate(ctx, conf)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return err
+	}
+
+	// 3. password加密
+	conf.Password = common.EncryptPassword(conf.Password)
+	conf.AccessToken = ""
+
+	conn.DataSourceConfig = conf
+	return nil
+}
+
+func (jcp *jaegerConnectionProcessor) ValidateWhenUpdate(ctx context.Context, conn *interfaces.DataConnection, preConn *interfaces.DataConnection) (err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 处理待修改的数据连接")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	// 1. detailedConfig由any转为jaegerDetailedConfig
+	conf, err := jcp.any2JaegerDetailedConfig(ctx, conn.DataSourceConfig)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return err
+	}
+
+	preConf, err := jcp.any2JaegerDetailedConfig(ctx, preConn.DataSourceConfig)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return err
+	}
+
+	// 2. 校验jaegerDetailedConfig
+	err = jcp.commonValidateWhenCreateAndUpdate(ctx, conf)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return err
+	}
+
+	// 3. 如果传入了新的password, 则需要加密; 用户名未变且未传password时沿用原password
+	if conf.Password == "" {
+		if conf.Username == preConf.Username {
+			conf.Password = preConf.Password
+		}
+	} else {
+		conf.Password = common.EncryptPassword(conf.Password)
+	}
+	conf.AccessToken = ""
+
+	conn.DataSourceConfig = conf
+	return nil
+}
+
+func (jcp *jaegerConnectionProcessor) ComputeConfigMD5(ctx context.Context, conn *interfaces.DataConnection) (md5 string, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 计算详细配置的md5")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	// 1. detailedConfig由any转为jaegerDetailedConfig
+	conf, err := jcp.any2JaegerDetailedConfig(ctx, conn.DataSourceConfig)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return "", err
+	}
+
+	// 2. 生成config的md5
+	str := conf.Address + conf.Protocol + conf.Username + common.DecryptPassword(conf.Password)
+	return common.MD532Lower(str), nil
+}
+
+func (jcp *jaegerConnectionProcessor) GenerateAuthInfoAndStatus(ctx context.Context, conn *interfaces.DataConnection) (err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 生成auth_info和连接状态")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	// 1. detailedConfig由any转为jaegerDetailedConfig
+	conf, err := jcp.any2JaegerDetailedConfig(ctx, conn.DataSourceConfig)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return err
+	}
+
+	// 2. 生成access_token, 并校验连通性
+	conf.AccessToken = jcp.genAccessToken(conf)
+	err = jcp.ping(ctx, conf)
+	if err != nil {
+		errDetails := fmt.Sprintf("Verify the connectivity of jaeger storage failed, err: %v", err.Error())
+		logger.Error(errDetails)
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_DataConnection_InternalError_VerifyConnectivityFailed).WithErrorDetails(errDetails)
+	}
+
+	// 3. 更新conn和status, 并返回结果
+	conn.DataConnectionStatus.Status = "ok"
+	conn.DataConnectionStatus.DetectionTime = time.Now().UnixMilli()
+
+	conn.DataSourceConfig = conf
+	return nil
+}
+
+// Basic认证没有过期时间, 只在access_token缺失时补齐并回写
+func (jcp *jaegerConnectionProcessor) UpdateAuthInfoAndStatus(ctx context.Context, conn *interfaces.DataConnection) (needWriteBack bool, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 更新auth_info和连接状态")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	// 1. []byte转jaegerDetailedConfig
+	conf, err := jcp.any2JaegerDetailedConfig(ctx, conn.DataSourceConfig)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return false, err
+	}
+
+	// 2. 判断access_token是否需要补齐
+	accessToken := jcp.genAccessToken(conf)
+	needWriteBack = conf.AccessToken != accessToken
+	conf.AccessToken = accessToken
+
+	conn.DataSourceConfig = conf
+	return needWriteBack, nil
+}
+
+func (jcp *jaegerConnectionProcessor) HideAuthInfo(ctx context.Context, conn *interfaces.DataConnection) (err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 隐藏auth_info")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	conf, err := jcp.any2JaegerDetailedConfig(ctx, conn.DataSourceConfig)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return err
+	}
+
+	conf.Password = ""
+	conf.AccessToken = ""
+	conn.DataSourceConfig = conf
+
+	return nil
+}
+
+/*
+	私有方法
+*/
+
+func (jcp *jaegerConnectionProcessor) any2JaegerDetailedConfig(ctx context.Context, i any) (*jaegerDetailedConfig, error) {
+	switch t := i.(type) {
+	case *jaegerDetailedConfig:
+		return t, nil
+	case []byte:
+		conf := jaegerDetailedConfig{}
+		err := sonic.Unmarshal(t, &conf)
+		if err != nil {
+			errDetails := fmt.Sprintf("Field config cannot be unmarshaled to jaegerDetailedConfig, err: %v", err.Error())
+			logger.Error(errDetails)
+			return &conf, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+				derrors.DataModel_InternalError_UnmarshalDataFailed).WithErrorDetails(errDetails)
+		}
+		return &conf, nil
+	default:
+		b, err := sonic.Marshal(i)
+		if err != nil {
+			errDetails := fmt.Sprintf("Marshal field config with field type %v failed, err: %v", t, err.Error())
+			logger.Error(errDetails)
+			return &jaegerDetailedConfig{}, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+				derrors.DataModel_InternalError_MarshalDataFailed).WithErrorDetails(errDetails)
+		}
+
+		conf := jaegerDetailedConfig{}
+		err = sonic.Unmarshal(b, &conf)
+		if err != nil {
+			errDetails := fmt.Sprintf("Field config with field type %v cannot be unmarshaled to jaegerDetailedConfig, err: %v", t, err.Error())
+			logger.Error(errDetails)
+			return &conf, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+				derrors.DataModel_InternalError_UnmarshalDataFailed).WithErrorDetails(errDetails)
+		}
+		return &conf, nil
+	}
+}
+
+func (jcp *jaegerConnectionProcessor) commonValidateWhenCreateAndUpdate(ctx context.Context, conf *jaegerDetailedConfig) error {
+	// 2.1 校验address
+	if conf.Address == "" {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataConnection_NullParameter_Address)
+	}
+
+	// 2.2 校验protocol
+	if conf.Protocol != "http" && conf.Protocol != "https" {
+		errDetails := fmt.Sprintf("The protocol %v is invalid, valid protocol is http or https", conf.Protocol)
+		logger.Error(errDetails)
+		return rest.NewHTTPError(ctx, http.StatusBadRequest,
+			derrors.DataModel_DataConnection_InvalidParameter_Protocol).WithErrorDetails(errDetails)
+	}
+
+	// username和password为可选项, 未开启安全认证的OpenSearch无需填写
+	return nil
+}
+
+// 生成Basic认证所需的access_token, 未配置username时为空
+func (jcp *jaegerConnectionProcessor) genAccessToken(conf *jaegerDetailedConfig) string {
+	if conf.Username == "" {
+		return ""
+	}
+
+	password := common.DecryptPassword(conf.Password)
+	return base64.StdEncoding.EncodeToString([]byte(conf.Username + ":" + password))
+}
+
+func (jcp *jaegerConnectionProcessor) ping(ctx context.Context, conf *jaegerDetailedConfig) (err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven层: 校验与jaeger存储的连通性", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	url := fmt.Sprintf("%s://%s/_cluster/health", conf.Protocol, conf.Address)
+	headers := map[string]string{}
+	if conf.AccessToken != "" {
+		headers["Authorization"] = "Basic " + conf.AccessToken
+	}
+
+	span.SetAttributes(attr.Key("jaeger_url").String(url))
+
+	respCode, respBody, err := jcp.httpClient.GetNoUnmarshal(ctx, url, nil, headers)
+	if err != nil {
+		errDetails := fmt.Sprintf("Failed to ping jaeger storage: %s", err)
+		logger.Errorf(errDetails)
+		o11y.Error(ctx, errDetails)
+		return err
+	}
+
+	if respCode != http.StatusOK {
+		errDetails := fmt.Sprintf("Failed to ping jaeger storage: %s", string(respBody))
+		err := errors.New(errDetails)
+		o11y.Error(ctx, errDetails)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package jaeger
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	rmock "github.com/kweaver-ai/kweaver-go-lib/rest/mock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+var (
+	testCtx = context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+)
+
+func MockNewJaegerConnectionProcessor(appSetting *common.AppSetting,
+	httpClient rest.HTTPClient) *jaegerConnectionProcessor {
+	return &jaegerConnectionProcessor{
+		appSetting: appSetting,
+		httpClient: httpClient,
+	}
+}
+
+func Test_JaegerConnectionProcessor_ValidateWhenCreate(t *testing.T) {
+	Convey("Test ValidateWhenCreate", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		httpClient := rmock.NewMockHTTPClient(mockCtrl)
+		jcp := MockNewJaegerConnectionProcessor(&common.AppSetting{}, httpClient)
+
+		Convey("Validate failed, caused by the address is empty", func() {
+			conn := &interfaces.DataConnection{DataSourceConfig: map[string]any{"protocol": "http"}}
+			err := jcp.ValidateWhenCreate(testCtx, conn)
+			So(err, ShouldResemble, rest.NewHTTPError(testCtx, http.StatusBadRequest, derrors.DataModel_DataConnection_NullParameter_Address))
+		})
+
+		Convey("Validate failed, caused by the protocol is invalid", func() {
+			conn := &interfaces.DataConnection{DataSourceConfig: map[string]any{"address": "opensearch:9200", "protocol": "tcp"}}
+			err := jcp.ValidateWhenCreate(testCtx, conn)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataConnection_InvalidParameter_Protocol)
+		})
+
+		Convey("Validate succeed, and the password is encrypted", func() {
+			conn := &interfaces.DataConnection{DataSourceConfig: map[string]any{
+				"address": "opensearch:9200", "protocol": "https", "username": "admin", "password": "admin",
+			}}
+			err := jcp.ValidateWhenCreate(testCtx, conn)
+			So(err, ShouldBeNil)
+
+			conf := conn.DataSourceConfig.(*jaegerDetailedConfig)
+			So(conf.Password, ShouldNotEqual, "admin")
+			So(common.DecryptPassword(conf.Password), ShouldEqual, "admin")
+		})
+	})
+}
+
+func Test_JaegerConnectionProcessor_ValidateWhenUpdate(t *testing.T) {
+	Convey("Test ValidateWhenUpdate", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		httpClient := rmock.NewMockHTTPClient(mockCtrl)
+		jcp := MockNewJaegerConnectionProcessor(&common.AppSetting{}, httpClient)
+		preConn := &interfaces.DataConnection{DataSourceConfig: &jaegerDetailedConfig{
+			Address: "opensearch:9200", Protocol: "http", Username: "admin", Password: "encrypted",
+		}}
+
+		Convey("Keep the previous password when it is not passed", func() {
+			conn := &interfaces.DataConnection{DataSourceConfig: map[string]any{
+				"address": "opensearch:9201", "protocol": "http", "username": "admin",
+			}}
+			err := jcp.ValidateWhenUpdate(testCtx, conn, preConn)
+			So(err, ShouldBeNil)
+			So(conn.DataSourceConfig.(*jaegerDetailedConfig).Password, ShouldEqual, "encrypted")
+		})
+
+		Convey("Drop the previous password when the username is changed", func() {
+			conn := &interfaces.DataConnection{DataSourceConfig: map[string]any{
+				"address": "opensearch:9200", "protocol": "http", "username": "reader",
+			}}
+			err := jcp.ValidateWhenUpdate(testCtx, conn, preConn)
+			So(err, ShouldBeNil)
+			So(conn.DataSourceConfig.(*jaegerDetailedConfig).Password, ShouldEqual, "")
+		})
+	})
+}
+
+func Test_JaegerConnectionProcessor_GenerateAuthInfoAndStatus(t *testing.T) {
+	Convey("Test GenerateAuthInfoAndStatus", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		httpClient := rmock.NewMockHTTPClient(mockCtrl)
+		jcp := MockNewJaegerConnectionProcessor(&common.AppSetting{}, httpClient)
+		newConn := func() *interfaces.DataConnection {
+			return &interfaces.DataConnection{DataSourceConfig: &jaegerDetailedConfig{
+				Address: "opensearch:9200", Protocol: "http", Username: "admin", Password: common.EncryptPassword("admin"),
+			}}
+		}
+		expectedToken := base64.StdEncoding.EncodeToString([]byte("admin:admin"))
+
+		Convey("Generate failed, caused by the error from http client", func() {
+			httpClient.EXPECT().GetNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(0, nil, errors.New("some errors"))
+
+			err := jcp.GenerateAuthInfoAndStatus(testCtx, newConn())
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataConnection_InternalError_VerifyConnectivityFailed)
+		})
+
+		Convey("Generate failed, caused by the status code is not 200", func() {
+			httpClient.EXPECT().GetNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(http.StatusUnauthorized, []byte("unauthorized"), nil)
+
+			err := jcp.GenerateAuthInfoAndStatus(testCtx, newConn())
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataConnection_InternalError_VerifyConnectivityFailed)
+		})
+
+		Convey("Generate succeed", func() {
+			httpClient.EXPECT().GetNoUnmarshal(gomock.Any(), "http://opensearch:9200/_cluster/health", gomock.Any(),
+				map[string]string{"Authorization": "Basic " + expectedToken}).Return(http.StatusOK, []byte("{}"), nil)
+
+			conn := newConn()
+			err := jcp.GenerateAuthInfoAndStatus(testCtx, conn)
+			So(err, ShouldBeNil)
+			So(conn.Status, ShouldEqual, "ok")
+			So(conn.DataSourceConfig.(*jaegerDetailedConfig).AccessToken, ShouldEqual, expectedToken)
+		})
+	})
+}
+
+func Test_JaegerConnectionProcessor_UpdateAuthInfoAndStatus(t *testing.T) {
+	Convey("Test UpdateAuthInfoAndStatus", t, func() {
+		jcp := MockNewJaegerConnectionProcessor(&common.AppSetting{}, nil)
+		expectedToken := base64.StdEncoding.EncodeToString([]byte("admin:admin"))
+
+		Convey("Need write back when access_token is missing", func() {
+			conn := &interfaces.DataConnection{DataSourceConfig: []byte(`{"address":"opensearch:9200","protocol":"http",` +
+				`"username":"admin","password":"` + common.EncryptPassword("admin") + `"}`)}
+			needWriteBack, err := jcp.UpdateAuthInfoAndStatus(testCtx, conn)
+			So(err, ShouldBeNil)
+			So(needWriteBack, ShouldBeTrue)
+			So(conn.DataSourceConfig.(*jaegerDetailedConfig).AccessToken, ShouldEqual, expectedToken)
+		})
+
+		Convey("No need to write back when access_token is up to date", func() {
+			conn := &interfaces.DataConnection{DataSourceConfig: &jaegerDetailedConfig{
+				Address: "opensearch:9200", Protocol: "http", Username: "admin",
+				Password: common.EncryptPassword("admin"), AccessToken: expectedToken,
+			}}
+			needWriteBack, err := jcp.UpdateAuthInfoAndStatus(testCtx, conn)
+			So(err, ShouldBeNil)
+			So(needWriteBack, ShouldBeFalse)
+		})
+	})
+}
+
+func Test_JaegerConnectionProcessor_HideAuthInfo(t *testing.T) {
+	Convey("Test HideAuthInfo", t, func() {
+		jcp := MockNewJaegerConnectionProcessor(&common.AppSetting{}, nil)
+
+		Convey("Hide succeed", func() {
+			conn := &interfaces.DataConnection{DataSourceConfig: &jaegerDetailedConfig{
+				Address: "opensearch:9200", Protocol: "http", Username: "admin", Password: "p", AccessToken: "t",
+			}}
+			err := jcp.HideAuthInfo(testCtx, conn)
+			So(err, ShouldBeNil)
+
+			conf := conn.DataSourceConfig.(*jaegerDetailedConfig)
+			So(conf.Password, ShouldEqual, "")
+			So(conf.AccessToken, ShouldEqual, "")
+			So(conf.Username, ShouldEqual, "admin")
+		})
+	})
+}
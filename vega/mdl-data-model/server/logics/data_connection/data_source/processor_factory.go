@@ -16,6 +16,7 @@ import (
 	derrors "data-model/errors"
 	"data-model/interfaces"
 	"data-model/logics/data_connection/data_source/anyrobot"
+	"data-model/logics/data_connection/data_source/jaeger"
 	"data-model/logics/data_connection/data_source/tingyun"
 )
 
@@ -25,6 +26,8 @@ func NewDataConnectionProcessor(ctx context.Context, appSetting *common.AppSetti
 		return anyrobot.NewAnyRobotConnectionProcessor(appSetting), nil
 	case interfaces.SOURCE_TYPE_TINGYUN:
 		return tingyun.NewTingYunConnectionProcessor(appSetting), nil
+	case interfaces.SOURCE_TYPE_JAEGER:
+		return jaeger.NewJaegerConnectionProcessor(appSetting), nil
 	default:
 		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, derrors.DataModel_DataConnection_InternalError_InitDataConnectionProcessor).
 			WithErrorDetails(fmt.Sprintf("Invalid data_source_type: %v", dataSourceType))
@@ -0,0 +1,562 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package data_view
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"github.com/rs/xid"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+	"data-model/logics"
+	"data-model/logics/permission"
+)
+
+var (
+	dqrServiceOnce sync.Once
+	dqrService     interfaces.DataQualityRuleService
+)
+
+type dataQualityRuleService struct {
+	appSetting *common.AppSetting
+	ps         interfaces.PermissionService
+	dda        interfaces.DataDictAccess
+	dva        interfaces.DataViewAccess
+	dqra       interfaces.DataQualityRuleAccess
+}
+
+func NewDataQualityRuleService(appSetting *common.AppSetting) interfaces.DataQualityRuleService {
+	dqrServiceOnce.Do(func() {
+		dqrService = &dataQualityRuleService{
+			appSetting: appSetting,
+			ps:         permission.NewPermissionService(appSetting),
+			dda:        logics.DDA,
+			dva:        logics.DVA,
+			dqra:       logics.DQRA,
+		}
+	})
+
+	return dqrService
+}
+
+// 创建数据质量规则, 需要有所属视图的修改权限
+func (dqrs *dataQualityRuleService) CreateDataQualityRules(ctx context.Context, rules []*interfaces.DataQualityRule) ([]string, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Create data quality rules")
+	defer span.End()
+
+	viewIDs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		viewIDs = append(viewIDs, rule.ViewID)
+	}
+
+	err := dqrs.checkViewPermission(ctx, viewIDs, interfaces.OPERATION_TYPE_MODIFY)
+	if err != nil {
+		span.SetStatus(codes.Error, "Check permission failed")
+		return nil, err
+	}
+
+	accountInfo := interfaces.AccountInfo{}
+	if ctx.Value(interfaces.ACCOUNT_INFO_KEY) != nil {
+		accountInfo = ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo)
+	}
+
+	currentTime := time.Now().UnixMilli()
+	ruleIDs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		err = dqrs.validateCreateUpdateParams(ctx, rule)
+		if err != nil {
+			span.SetStatus(codes.Error, "Validate data quality rule failed")
+			return nil, err
+		}
+
+		exist, err := dqrs.dqra.CheckDataQualityRuleExistByName(ctx, rule.RuleName, rule.ViewID)
+		if err != nil {
+			span.SetStatus(codes.Error, "Check data quality rule exist by name failed")
+			return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+				rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+		}
+		if exist {
+			span.SetStatus(codes.Error, "Data quality rule name already exists")
+			return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_ExistByName).
+				WithErrorDetails(fmt.Sprintf("rule name %s already exists in view %s", rule.RuleName, rule.ViewID))
+		}
+
+		if rule.RuleID == "" {
+			rule.RuleID = xid.New().String()
+		}
+
+		rule.Creator = accountInfo
+		rule.Updater = accountInfo
+		rule.CreateTime = currentTime
+		rule.UpdateTime = currentTime
+		rule.LastStatus = ""
+		rule.LastCheckTime = 0
+
+		ruleIDs = append(ruleIDs, rule.RuleID)
+	}
+
+	err = dqrs.dqra.CreateDataQualityRules(ctx, rules)
+	if err != nil {
+		logger.Errorf("Create data quality rules error: %s", err.Error())
+		span.SetStatus(codes.Error, "Create data quality rules failed")
+
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return ruleIDs, nil
+}
+
+// 修改数据质量规则, 所属视图不允许变更
+func (dqrs *dataQualityRuleService) UpdateDataQualityRule(ctx context.Context, rule *interfaces.DataQualityRule) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Update a data quality rule")
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("rule_id").String(rule.RuleID),
+		attr.Key("rule_name").String(rule.RuleName),
+	)
+
+	oldRules, err := dqrs.getDataQualityRules(ctx, []string{rule.RuleID})
+	if err != nil {
+		span.SetStatus(codes.Error, "Get data quality rule failed")
+		return err
+	}
+	oldRule := oldRules[0]
+
+	if rule.ViewID != oldRule.ViewID {
+		errDetails := fmt.Sprintf("The view of data quality rule '%s' cannot be changed from '%s' to '%s'",
+			rule.RuleID, oldRule.ViewID, rule.ViewID)
+		logger.Error(errDetails)
+		span.SetStatus(codes.Error, errDetails)
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_InvalidParameter_ViewID).
+			WithErrorDetails(errDetails)
+	}
+
+	err = dqrs.checkViewPermission(ctx, []string{rule.ViewID}, interfaces.OPERATION_TYPE_MODIFY)
+	if err != nil {
+		span.SetStatus(codes.Error, "Check permission failed")
+		return err
+	}
+
+	err = dqrs.validateCreateUpdateParams(ctx, rule)
+	if err != nil {
+		span.SetStatus(codes.Error, "Validate data quality rule failed")
+		return err
+	}
+
+	if rule.RuleName != oldRule.RuleName {
+		exist, err := dqrs.dqra.CheckDataQualityRuleExistByName(ctx, rule.RuleName, rule.ViewID)
+		if err != nil {
+			span.SetStatus(codes.Error, "Check data quality rule exist by name failed")
+			return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+				rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+		}
+		if exist {
+			span.SetStatus(codes.Error, "Data quality rule name already exists")
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_ExistByName).
+				WithErrorDetails(fmt.Sprintf("rule name %s already exists in view %s", rule.RuleName, rule.ViewID))
+		}
+	}
+
+	accountInfo := interfaces.AccountInfo{}
+	if ctx.Value(interfaces.ACCOUNT_INFO_KEY) != nil {
+		accountInfo = ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo)
+	}
+
+	rule.Updater = accountInfo
+	rule.UpdateTime = time.Now().UnixMilli()
+	err = dqrs.dqra.UpdateDataQualityRule(ctx, rule)
+	if err != nil {
+		logger.Errorf("Update data quality rule error: %s", err.Error())
+		span.SetStatus(codes.Error, "Update data quality rule failed")
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// 按 id 获取数据质量规则, 需要有所属视图的查看权限
+func (dqrs *dataQualityRuleService) GetDataQualityRules(ctx context.Context, ruleIDs []string) ([]*interfaces.DataQualityRule, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Get data quality rules")
+	defer span.End()
+
+	span.SetAttributes(attr.Key("rule_ids").String(strings.Join(ruleIDs, ",")))
+
+	rules, err := dqrs.getDataQualityRules(ctx, ruleIDs)
+	if err != nil {
+		span.SetStatus(codes.Error, "Get data quality rules failed")
+		return nil, err
+	}
+
+	viewIDs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		viewIDs = append(viewIDs, rule.ViewID)
+	}
+
+	err = dqrs.checkViewPermission(ctx, viewIDs, interfaces.OPERATION_TYPE_VIEW_DETAIL)
+	if err != nil {
+		span.SetStatus(codes.Error, "Check permission failed")
+		return nil, err
+	}
+
+	err = dqrs.fillViewNames(ctx, rules)
+	if err != nil {
+		span.SetStatus(codes.Error, "Get view names failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return rules, nil
+}
+
+// 查询数据质量规则列表, 只返回有所属视图查看权限的规则
+func (dqrs *dataQualityRuleService) ListDataQualityRules(ctx context.Context,
+	params *interfaces.ListDataQualityRuleQueryParams) ([]*interfaces.DataQualityRule, int, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: List data quality rules")
+	defer span.End()
+
+	rules, err := dqrs.dqra.ListDataQualityRules(ctx, params)
+	if err != nil {
+		logger.Errorf("List data quality rules error: %s", err.Error())
+		span.SetStatus(codes.Error, "List data quality rules failed")
+		return nil, 0, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+	}
+
+	if len(rules) == 0 {
+		span.SetStatus(codes.Ok, "")
+		return rules, 0, nil
+	}
+
+	results := rules
+	if !params.IsInnerRequest {
+		viewIDs := make([]string, 0, len(rules))
+		for _, rule := range rules {
+			viewIDs = append(viewIDs, rule.ViewID)
+		}
+
+		matchResources, err := dqrs.ps.FilterResources(ctx, interfaces.RESOURCE_TYPE_DATA_VIEW, viewIDs,
+			[]string{interfaces.OPERATION_TYPE_VIEW_DETAIL}, false)
+		if err != nil {
+			span.SetStatus(codes.Error, "Filter resources failed")
+			return nil, 0, err
+		}
+
+		results = make([]*interfaces.DataQualityRule, 0, len(rules))
+		for _, rule := range rules {
+			if _, exist := matchResources[rule.ViewID]; exist {
+				results = append(results, rule)
+			}
+		}
+	}
+
+	total := len(results)
+	if params.Limit != -1 {
+		// 检查起始位置是否越界
+		if params.Offset < 0 || params.Offset >= total {
+			span.SetStatus(codes.Ok, "")
+			return []*interfaces.DataQualityRule{}, total, nil
+		}
+
+		end := params.Offset + params.Limit
+		if end > total {
+			end = total
+		}
+		results = results[params.Offset:end]
+	}
+
+	err = dqrs.fillViewNames(ctx, results)
+	if err != nil {
+		span.SetStatus(codes.Error, "Get view names failed")
+		return nil, 0, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return results, total, nil
+}
+
+// 删除数据质量规则及其检查结果
+func (dqrs *dataQualityRuleService) DeleteDataQualityRules(ctx context.Context, ruleIDs []string) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Delete data quality rules")
+	defer span.End()
+
+	span.SetAttributes(attr.Key("rule_ids").String(strings.Join(ruleIDs, ",")))
+
+	rules, err := dqrs.getDataQualityRules(ctx, ruleIDs)
+	if err != nil {
+		span.SetStatus(codes.Error, "Get data quality rules failed")
+		return err
+	}
+
+	viewIDs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		viewIDs = append(viewIDs, rule.ViewID)
+	}
+
+	err = dqrs.checkViewPermission(ctx, viewIDs, interfaces.OPERATION_TYPE_MODIFY)
+	if err != nil {
+		span.SetStatus(codes.Error, "Check permission failed")
+		return err
+	}
+
+	err = dqrs.dqra.DeleteDataQualityRules(ctx, nil, ruleIDs)
+	if err != nil {
+		logger.Errorf("Delete data quality rules error: %s", err.Error())
+		span.SetStatus(codes.Error, "Delete data quality rules failed")
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// 查询规则的检查结果历史
+func (dqrs *dataQualityRuleService) ListDataQualityResults(ctx context.Context,
+	params *interfaces.ListDataQualityResultQueryParams) ([]*interfaces.DataQualityResult, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: List data quality results")
+	defer span.End()
+
+	span.SetAttributes(attr.Key("rule_id").String(params.RuleID))
+
+	// 校验规则存在以及所属视图的查看权限
+	_, err := dqrs.GetDataQualityRules(ctx, []string{params.RuleID})
+	if err != nil {
+		span.SetStatus(codes.Error, "Get data quality rule failed")
+		return nil, err
+	}
+
+	results, err := dqrs.dqra.ListDataQualityResults(ctx, params)
+	if err != nil {
+		logger.Errorf("List data quality results error: %s", err.Error())
+		span.SetStatus(codes.Error, "List data quality results failed")
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return results, nil
+}
+
+// 获取数据视图的质量概况. 视图下至少有一条启用的规则, 且启用的规则最近一次检查都通过时, 视图视为可信
+func (dqrs *dataQualityRuleService) GetDataQualitySummaries(ctx context.Context, viewIDs []string) ([]*interfaces.DataQualitySummary, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Get data quality summaries")
+	defer span.End()
+
+	span.SetAttributes(attr.Key("view_ids").String(strings.Join(viewIDs, ",")))
+
+	viewMap, err := dqrs.dva.GetSimpleDataViewMapByIDs(ctx, viewIDs)
+	if err != nil {
+		logger.Errorf("Get data views error: %s", err.Error())
+		span.SetStatus(codes.Error, "Get data views failed")
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+	}
+	for _, viewID := range viewIDs {
+		if _, ok := viewMap[viewID]; !ok {
+			errDetails := fmt.Sprintf("The data view %s was not found", viewID)
+			logger.Error(errDetails)
+			span.SetStatus(codes.Error, errDetails)
+			return nil, rest.NewHTTPError(ctx, http.StatusNotFound, derrors.DataModel_DataView_DataViewNotFound).
+				WithErrorDetails(errDetails)
+		}
+	}
+
+	err = dqrs.checkViewPermission(ctx, viewIDs, interfaces.OPERATION_TYPE_VIEW_DETAIL)
+	if err != nil {
+		span.SetStatus(codes.Error, "Check permission failed")
+		return nil, err
+	}
+
+	rules, err := dqrs.dqra.ListDataQualityRules(ctx, &interfaces.ListDataQualityRuleQueryParams{
+		ViewIDs: viewIDs,
+		PaginationQueryParameters: interfaces.PaginationQueryParameters{
+			Sort:      "f_rule_name",
+			Direction: interfaces.ASC_DIRECTION,
+		},
+	})
+	if err != nil {
+		logger.Errorf("List data quality rules error: %s", err.Error())
+		span.SetStatus(codes.Error, "List data quality rules failed")
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+	}
+
+	rulesByView := make(map[string][]*interfaces.DataQualityRule, len(viewIDs))
+	for _, rule := range rules {
+		rulesByView[rule.ViewID] = append(rulesByView[rule.ViewID], rule)
+	}
+
+	summaries := make([]*interfaces.DataQualitySummary, 0, len(viewIDs))
+	for _, viewID := range viewIDs {
+		summaries = append(summaries, buildDataQualitySummary(viewID, rulesByView[viewID]))
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return summaries, nil
+}
+
+func buildDataQualitySummary(viewID string, rules []*interfaces.DataQualityRule) *interfaces.DataQualitySummary {
+	summary := &interfaces.DataQualitySummary{
+		ViewID:     viewID,
+		TotalCount: len(rules),
+		Rules:      make([]interfaces.DataQualityRuleSummary, 0, len(rules)),
+	}
+
+	// 停用的规则不参与可信判断
+	enabledCount := 0
+	for _, rule := range rules {
+		summary.Rules = append(summary.Rules, interfaces.DataQualityRuleSummary{
+			RuleID:        rule.RuleID,
+			RuleName:      rule.RuleName,
+			Kind:          rule.Kind,
+			Enabled:       rule.Enabled,
+			LastStatus:    rule.LastStatus,
+			LastCheckTime: rule.LastCheckTime,
+		})
+
+		if rule.LastCheckTime > summary.LastCheckTime {
+			summary.LastCheckTime = rule.LastCheckTime
+		}
+
+		if !rule.Enabled {
+			summary.UnknownCount++
+			continue
+		}
+
+		enabledCount++
+		switch rule.LastStatus {
+		case interfaces.DATA_QUALITY_STATUS_PASSED:
+			summary.PassedCount++
+		case interfaces.DATA_QUALITY_STATUS_FAILED:
+			summary.FailedCount++
+		default:
+			// 未执行过或执行出错
+			summary.UnknownCount++
+		}
+	}
+	summary.Trusted = enabledCount > 0 && summary.PassedCount == enabledCount
+
+	return summary
+}
+
+// 获取规则, 有不存在的规则时返回 404
+func (dqrs *dataQualityRuleService) getDataQualityRules(ctx context.Context, ruleIDs []string) ([]*interfaces.DataQualityRule, error) {
+	rules, err := dqrs.dqra.GetDataQualityRules(ctx, ruleIDs)
+	if err != nil {
+		logger.Errorf("Get data quality rules error: %s", err.Error())
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+	}
+
+	ruleMap := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		ruleMap[rule.RuleID] = struct{}{}
+	}
+	for _, ruleID := range ruleIDs {
+		if _, ok := ruleMap[ruleID]; !ok {
+			errDetails := fmt.Sprintf("The data quality rule %s was not found", ruleID)
+			logger.Error(errDetails)
+			o11y.Error(ctx, errDetails)
+			return nil, rest.NewHTTPError(ctx, http.StatusNotFound, derrors.DataModel_DataQualityRule_RuleNotFound).
+				WithErrorDetails(errDetails)
+		}
+	}
+
+	return rules, nil
+}
+
+// 校验对规则所属视图的操作权限
+func (dqrs *dataQualityRuleService) checkViewPermission(ctx context.Context, viewIDs []string, operation string) error {
+	matchResources, err := dqrs.ps.FilterResources(ctx, interfaces.RESOURCE_TYPE_DATA_VIEW, viewIDs,
+		[]string{interfaces.OPERATION_TYPE_VIEW_DETAIL, operation}, false)
+	if err != nil {
+		return err
+	}
+
+	// 请求的视图 id 可以重复, 过滤出来的是去重后的, 需逐个判断
+	for _, viewID := range viewIDs {
+		if _, exist := matchResources[viewID]; !exist {
+			return rest.NewHTTPError(ctx, http.StatusForbidden, rest.PublicError_Forbidden).
+				WithErrorDetails(fmt.Sprintf("Access denied: insufficient permissions for data view's %s operation", operation))
+		}
+	}
+
+	return nil
+}
+
+func (dqrs *dataQualityRuleService) fillViewNames(ctx context.Context, rules []*interfaces.DataQualityRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	viewIDs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		viewIDs = append(viewIDs, rule.ViewID)
+	}
+
+	viewMap, err := dqrs.dva.GetSimpleDataViewMapByIDs(ctx, viewIDs)
+	if err != nil {
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+	}
+
+	for _, rule := range rules {
+		if view, ok := viewMap[rule.ViewID]; ok {
+			rule.ViewName = view.ViewName
+		}
+	}
+
+	return nil
+}
+
+// 校验视图存在, accepted_values 规则引用的字典存在
+func (dqrs *dataQualityRuleService) validateCreateUpdateParams(ctx context.Context, rule *interfaces.DataQualityRule) error {
+	_, exist, err := dqrs.dva.CheckDataViewExistByID(ctx, nil, rule.ViewID)
+	if err != nil {
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+	}
+	if !exist {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataView_DataViewNotFound).
+			WithErrorDetails(fmt.Sprintf("view '%s' not found", rule.ViewID))
+	}
+
+	if rule.Kind == interfaces.DATA_QUALITY_KIND_ACCEPTED_VALUES {
+		dict, err := dqrs.dda.GetDataDictByID(ctx, rule.Config.DictID)
+		if err != nil {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataDict_DictNotFound).
+				WithErrorDetails(fmt.Sprintf("data dict '%s' not found", rule.Config.DictID))
+		}
+
+		// 维度字典需要指定取哪个维度的值
+		if dict.DictType == interfaces.DATA_DICT_TYPE_DIMENSION && rule.Config.DictKey == "" {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataQualityRule_InvalidParameter_Config).
+				WithErrorDetails("config.dict_key is required for dimension dict")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,272 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package data_view
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+	dmock "data-model/interfaces/mock"
+)
+
+func MockNewDataQualityRuleService(appSetting *common.AppSetting,
+	ps interfaces.PermissionService,
+	dda interfaces.DataDictAccess,
+	dva interfaces.DataViewAccess,
+	dqra interfaces.DataQualityRuleAccess) *dataQualityRuleService {
+
+	return &dataQualityRuleService{
+		appSetting: appSetting,
+		ps:         ps,
+		dda:        dda,
+		dva:        dva,
+		dqra:       dqra,
+	}
+}
+
+func Test_DataQualityRuleService_CreateDataQualityRules(t *testing.T) {
+	Convey("Test CreateDataQualityRules", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		psMock := dmock.NewMockPermissionService(mockCtrl)
+		ddaMock := dmock.NewMockDataDictAccess(mockCtrl)
+		dvaMock := dmock.NewMockDataViewAccess(mockCtrl)
+		dqraMock := dmock.NewMockDataQualityRuleAccess(mockCtrl)
+		dqrs := MockNewDataQualityRuleService(appSetting, psMock, ddaMock, dvaMock, dqraMock)
+
+		resrcs := map[string]interfaces.ResourceOps{
+			"v1": {ResourceID: "v1"},
+		}
+
+		newRules := func() []*interfaces.DataQualityRule {
+			return []*interfaces.DataQualityRule{{
+				RuleName: "dict check",
+				ViewID:   "v1",
+				Kind:     interfaces.DATA_QUALITY_KIND_ACCEPTED_VALUES,
+				Config: interfaces.DataQualityRuleConfig{
+					Fields: []string{"status"},
+					DictID: "d1",
+				},
+				LastStatus: interfaces.DATA_QUALITY_STATUS_PASSED,
+			}}
+		}
+
+		Convey("Create failed, caused by no permission", func() {
+			psMock.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(map[string]interfaces.ResourceOps{}, nil)
+
+			_, err := dqrs.CreateDataQualityRules(testCtx, newRules())
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Create failed, caused by view not found", func() {
+			psMock.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(resrcs, nil)
+			dvaMock.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("", false, nil)
+
+			_, err := dqrs.CreateDataQualityRules(testCtx, newRules())
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataView_DataViewNotFound)
+		})
+
+		Convey("Create failed, caused by dict not found", func() {
+			psMock.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(resrcs, nil)
+			dvaMock.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("view", true, nil)
+			ddaMock.EXPECT().GetDataDictByID(gomock.Any(), "d1").Return(interfaces.DataDict{}, errors.New("not found"))
+
+			_, err := dqrs.CreateDataQualityRules(testCtx, newRules())
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataDict_DictNotFound)
+		})
+
+		Convey("Create failed, caused by dimension dict without dict_key", func() {
+			psMock.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(resrcs, nil)
+			dvaMock.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("view", true, nil)
+			ddaMock.EXPECT().GetDataDictByID(gomock.Any(), "d1").
+				Return(interfaces.DataDict{DictType: interfaces.DATA_DICT_TYPE_DIMENSION}, nil)
+
+			_, err := dqrs.CreateDataQualityRules(testCtx, newRules())
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataQualityRule_InvalidParameter_Config)
+		})
+
+		Convey("Create failed, caused by name already exists", func() {
+			psMock.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(resrcs, nil)
+			dvaMock.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("view", true, nil)
+			ddaMock.EXPECT().GetDataDictByID(gomock.Any(), "d1").Return(interfaces.DataDict{}, nil)
+			dqraMock.EXPECT().CheckDataQualityRuleExistByName(gomock.Any(), "dict check", "v1").Return(true, nil)
+
+			_, err := dqrs.CreateDataQualityRules(testCtx, newRules())
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataQualityRule_ExistByName)
+		})
+
+		Convey("Create succeed", func() {
+			psMock.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(resrcs, nil)
+			dvaMock.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("view", true, nil)
+			ddaMock.EXPECT().GetDataDictByID(gomock.Any(), "d1").Return(interfaces.DataDict{}, nil)
+			dqraMock.EXPECT().CheckDataQualityRuleExistByName(gomock.Any(), "dict check", "v1").Return(false, nil)
+			dqraMock.EXPECT().CreateDataQualityRules(gomock.Any(), gomock.Any()).Return(nil)
+
+			rules := newRules()
+			ids, err := dqrs.CreateDataQualityRules(testCtx, rules)
+			So(err, ShouldBeNil)
+			So(len(ids), ShouldEqual, 1)
+			So(ids[0], ShouldEqual, rules[0].RuleID)
+			So(rules[0].LastStatus, ShouldEqual, "")
+			So(rules[0].CreateTime, ShouldEqual, rules[0].UpdateTime)
+		})
+	})
+}
+
+func Test_DataQualityRuleService_UpdateDataQualityRule(t *testing.T) {
+	Convey("Test UpdateDataQualityRule", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		psMock := dmock.NewMockPermissionService(mockCtrl)
+		ddaMock := dmock.NewMockDataDictAccess(mockCtrl)
+		dvaMock := dmock.NewMockDataViewAccess(mockCtrl)
+		dqraMock := dmock.NewMockDataQualityRuleAccess(mockCtrl)
+		dqrs := MockNewDataQualityRuleService(appSetting, psMock, ddaMock, dvaMock, dqraMock)
+
+		origin := &interfaces.DataQualityRule{
+			RuleID:   "r1",
+			RuleName: "a",
+			ViewID:   "v1",
+			Kind:     interfaces.DATA_QUALITY_KIND_ROW_COUNT,
+		}
+
+		Convey("Update failed, caused by rule not found", func() {
+			dqraMock.EXPECT().GetDataQualityRules(gomock.Any(), []string{"r1"}).Return([]*interfaces.DataQualityRule{}, nil)
+
+			err := dqrs.UpdateDataQualityRule(testCtx, &interfaces.DataQualityRule{RuleID: "r1"})
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Update failed, caused by view id changed", func() {
+			dqraMock.EXPECT().GetDataQualityRules(gomock.Any(), []string{"r1"}).
+				Return([]*interfaces.DataQualityRule{origin}, nil)
+
+			err := dqrs.UpdateDataQualityRule(testCtx, &interfaces.DataQualityRule{RuleID: "r1", ViewID: "v2"})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_DataQualityRule_InvalidParameter_ViewID)
+		})
+
+		Convey("Update succeed", func() {
+			dqraMock.EXPECT().GetDataQualityRules(gomock.Any(), []string{"r1"}).
+				Return([]*interfaces.DataQualityRule{origin}, nil)
+			psMock.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(map[string]interfaces.ResourceOps{"v1": {ResourceID: "v1"}}, nil)
+			dvaMock.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("view", true, nil)
+			dqraMock.EXPECT().UpdateDataQualityRule(gomock.Any(), gomock.Any()).Return(nil)
+
+			err := dqrs.UpdateDataQualityRule(testCtx, &interfaces.DataQualityRule{
+				RuleID:   "r1",
+				RuleName: "a",
+				ViewID:   "v1",
+				Kind:     interfaces.DATA_QUALITY_KIND_ROW_COUNT,
+			})
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func Test_DataQualityRuleService_GetDataQualitySummaries(t *testing.T) {
+	Convey("Test GetDataQualitySummaries", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		psMock := dmock.NewMockPermissionService(mockCtrl)
+		ddaMock := dmock.NewMockDataDictAccess(mockCtrl)
+		dvaMock := dmock.NewMockDataViewAccess(mockCtrl)
+		dqraMock := dmock.NewMockDataQualityRuleAccess(mockCtrl)
+		dqrs := MockNewDataQualityRuleService(appSetting, psMock, ddaMock, dvaMock, dqraMock)
+
+		viewMap := map[string]*interfaces.DataView{
+			"v1": {SimpleDataView: interfaces.SimpleDataView{ViewID: "v1"}},
+			"v2": {SimpleDataView: interfaces.SimpleDataView{ViewID: "v2"}},
+		}
+
+		Convey("Get failed, caused by view not found", func() {
+			dvaMock.EXPECT().GetSimpleDataViewMapByIDs(gomock.Any(), gomock.Any()).Return(viewMap, nil)
+
+			_, err := dqrs.GetDataQualitySummaries(testCtx, []string{"v1", "v3"})
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Get failed, caused by list rules error", func() {
+			dvaMock.EXPECT().GetSimpleDataViewMapByIDs(gomock.Any(), gomock.Any()).Return(viewMap, nil)
+			psMock.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(map[string]interfaces.ResourceOps{"v1": {ResourceID: "v1"}, "v2": {ResourceID: "v2"}}, nil)
+			dqraMock.EXPECT().ListDataQualityRules(gomock.Any(), gomock.Any()).Return(nil, errors.New("some error"))
+
+			_, err := dqrs.GetDataQualitySummaries(testCtx, []string{"v1", "v2"})
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("Get succeed", func() {
+			dvaMock.EXPECT().GetSimpleDataViewMapByIDs(gomock.Any(), gomock.Any()).Return(viewMap, nil)
+			psMock.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(map[string]interfaces.ResourceOps{"v1": {ResourceID: "v1"}, "v2": {ResourceID: "v2"}}, nil)
+			dqraMock.EXPECT().ListDataQualityRules(gomock.Any(), gomock.Any()).Return([]*interfaces.DataQualityRule{
+				{RuleID: "r1", ViewID: "v1", Enabled: true, LastStatus: interfaces.DATA_QUALITY_STATUS_PASSED},
+			}, nil)
+
+			summaries, err := dqrs.GetDataQualitySummaries(testCtx, []string{"v1", "v2"})
+			So(err, ShouldBeNil)
+			So(len(summaries), ShouldEqual, 2)
+			So(summaries[0].Trusted, ShouldBeTrue)
+			So(summaries[1].Trusted, ShouldBeFalse)
+			So(summaries[1].TotalCount, ShouldEqual, 0)
+		})
+	})
+}
+
+func Test_DataQualityRuleService_buildDataQualitySummary(t *testing.T) {
+	Convey("Test buildDataQualitySummary", t, func() {
+		Convey("Not trusted when a rule failed", func() {
+			summary := buildDataQualitySummary("v1", []*interfaces.DataQualityRule{
+				{RuleID: "r1", Enabled: true, LastStatus: interfaces.DATA_QUALITY_STATUS_PASSED, LastCheckTime: 1},
+				{RuleID: "r2", Enabled: true, LastStatus: interfaces.DATA_QUALITY_STATUS_FAILED, LastCheckTime: 3},
+				{RuleID: "r3", Enabled: true, LastStatus: interfaces.DATA_QUALITY_STATUS_ERROR, LastCheckTime: 2},
+			})
+			So(summary.Trusted, ShouldBeFalse)
+			So(summary.PassedCount, ShouldEqual, 1)
+			So(summary.FailedCount, ShouldEqual, 1)
+			So(summary.UnknownCount, ShouldEqual, 1)
+			So(summary.LastCheckTime, ShouldEqual, 3)
+			So(len(summary.Rules), ShouldEqual, 3)
+		})
+
+		Convey("Disabled rules do not affect trust", func() {
+			summary := buildDataQualitySummary("v1", []*interfaces.DataQualityRule{
+				{RuleID: "r1", Enabled: true, LastStatus: interfaces.DATA_QUALITY_STATUS_PASSED},
+				{RuleID: "r2", Enabled: false, LastStatus: interfaces.DATA_QUALITY_STATUS_FAILED},
+			})
+			So(summary.Trusted, ShouldBeTrue)
+			So(summary.UnknownCount, ShouldEqual, 1)
+		})
+
+		Convey("Not trusted without enabled rules", func() {
+			summary := buildDataQualitySummary("v1", []*interfaces.DataQualityRule{
+				{RuleID: "r1", Enabled: false},
+			})
+			So(summary.Trusted, ShouldBeFalse)
+		})
+	})
+}
@@ -778,6 +778,26 @@ func validateUnionNode(ctx context.Context, qType string, node *interfaces.DataS
 	return nil
 }
 
+// 校验派生节点（aggregate/window/pivot/unpivot），配置内容在 uniquery 中按输入节点字段校验
+func validateDerivedNode(ctx context.Context, node *interfaces.DataScopeNode, nodeMap map[string]struct{}) error {
+	if len(node.InputNodes) != 1 {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataView_InvalidParameter_DataScope).
+			WithErrorDetails(fmt.Sprintf("The data scope %s config is invalid, input_nodes must have exactly one node", node.Type))
+	}
+
+	if _, ok := nodeMap[node.InputNodes[0]]; !ok {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataView_InvalidParameter_DataScope).
+			WithErrorDetails(fmt.Sprintf("The data scope %s config is invalid, input_node '%s' is not exist", node.Type, node.InputNodes[0]))
+	}
+
+	if len(node.Config) == 0 {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataView_InvalidParameter_DataScope).
+			WithErrorDetails(fmt.Sprintf("The data scope %s config is invalid, config must be set", node.Type))
+	}
+
+	return nil
+}
+
 func validateSqlNode(ctx context.Context, node *interfaces.DataScopeNode, nodeMap map[string]struct{}) error {
 	// 输入节点不能为空
 	if len(node.InputNodes) == 0 {
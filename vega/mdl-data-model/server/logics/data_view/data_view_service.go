@@ -27,6 +27,8 @@ import (
 	"data-model/interfaces"
 	dtype "data-model/interfaces/data_type"
 	"data-model/logics"
+	"data-model/logics/lineage"
+	"data-model/logics/model_revision"
 	"data-model/logics/permission"
 )
 
@@ -40,11 +42,14 @@ type dataViewService struct {
 	ps         interfaces.PermissionService
 	dvrcrs     interfaces.DataViewRowColumnRuleService
 	db         *sql.DB
+	dqra       interfaces.DataQualityRuleAccess
 	dsa        interfaces.DataSourceAccess
 	dva        interfaces.DataViewAccess
 	iba        interfaces.IndexBaseAccess
 	dmja       interfaces.DataModelJobAccess
 	dvga       interfaces.DataViewGroupAccess
+	ls         interfaces.LineageService
+	mrs        interfaces.ModelRevisionService
 	ua         interfaces.UniqueryAccess
 }
 
@@ -55,11 +60,14 @@ func NewDataViewService(appSetting *common.AppSetting) interfaces.DataViewServic
 			ps:         permission.NewPermissionService(appSetting),
 			dvrcrs:     NewDataViewRowColumnRuleService(appSetting),
 			db:         logics.DB,
+			dqra:       logics.DQRA,
 			dsa:        logics.DSA,
 			dva:        logics.DVA,
 			iba:        logics.IBA,
 			dmja:       logics.DMJA,
 			dvga:       logics.DVGA,
+			ls:         lineage.NewLineageService(appSetting),
+			mrs:        model_revision.NewModelRevisionService(appSetting),
 			ua:         logics.UA,
 		}
 	})
@@ -218,6 +226,24 @@ func (dvs *dataViewService) CreateDataViews(ctx context.Context, views []*interf
 					derrors.DataModel_DataView_InternalError_CreateDataViewsFailed).WithErrorDetails(err.Error())
 			}
 
+			// 登记视图的血缘
+			err = dvs.ls.SaveDataViewLineage(ctx, tx, viewBatch)
+			if err != nil {
+				needRollback = true
+				span.SetStatus(codes.Error, "Save data views lineage failed")
+				return nil, err
+			}
+
+			// 记录视图的初始版本
+			for _, view := range viewBatch {
+				err = dvs.mrs.SaveModelRevision(ctx, tx, interfaces.REVISION_MODEL_TYPE_DATA_VIEW, view.ViewID, view.ViewName, view)
+				if err != nil {
+					needRollback = true
+					span.SetStatus(codes.Error, "Save data view revision failed")
+					return nil, err
+				}
+			}
+
 			resourcesBatch := createSrcs[i:end]
 			if len(resourcesBatch) == 0 {
 				continue
@@ -309,6 +335,32 @@ func (dvs *dataViewService) DeleteDataViews(ctx context.Context, viewIDs []strin
 		return err
 	}
 
+	// 删除视图下面的数据质量规则及检查结果
+	err = dvs.dqra.DeleteDataQualityRulesByViewIDs(ctx, tx, viewIDs)
+	if err != nil {
+		needRollback = true
+		logger.Errorf("Delete DataQualityRules error: %s", err.Error())
+		span.SetStatus(codes.Error, "Delete data quality rules failed")
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			rest.PublicError_InternalServerError).WithErrorDetails(err.Error())
+	}
+
+	// 删除视图登记的血缘
+	err = dvs.ls.DeleteModelLineage(ctx, tx, interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, viewIDs)
+	if err != nil {
+		needRollback = true
+		span.SetStatus(codes.Error, "Delete data views lineage failed")
+		return err
+	}
+
+	// 删除视图的历史版本
+	err = dvs.mrs.DeleteModelRevisions(ctx, tx, interfaces.REVISION_MODEL_TYPE_DATA_VIEW, viewIDs)
+	if err != nil {
+		needRollback = true
+		span.SetStatus(codes.Error, "Delete data views revisions failed")
+		return err
+	}
+
 	//  清除资源策略
 	err = dvs.ps.DeleteResources(ctx, interfaces.RESOURCE_TYPE_DATA_VIEW, viewIDs)
 	if err != nil {
@@ -461,6 +513,22 @@ func (dvs *dataViewService) UpdateDataView(ctx context.Context, tx *sql.Tx, view
 			derrors.DataModel_DataView_InternalError_UpdateDataViewFailed).WithErrorDetails(err.Error())
 	}
 
+	// 重新登记视图的血缘
+	err = dvs.ls.SaveDataViewLineage(ctx, tx, []*interfaces.DataView{view})
+	if err != nil {
+		needRollback = true
+		span.SetStatus(codes.Error, "Save data view lineage failed")
+		return err
+	}
+
+	// 记录修改后的版本
+	err = dvs.mrs.SaveModelRevision(ctx, tx, interfaces.REVISION_MODEL_TYPE_DATA_VIEW, view.ViewID, view.ViewName, view)
+	if err != nil {
+		needRollback = true
+		span.SetStatus(codes.Error, "Save data view revision failed")
+		return err
+	}
+
 	// 请求更新资源名称的接口，更新资源的名称
 	err = dvs.ps.UpdateResource(ctx, interfaces.Resource{
 		ID:   view.ViewID,
@@ -607,6 +675,22 @@ func (dvs *dataViewService) UpdateDataViewInternal(ctx context.Context, view *in
 			derrors.DataModel_DataView_InternalError_UpdateDataViewFailed).WithErrorDetails(err.Error())
 	}
 
+	// 重新登记视图的血缘
+	err = dvs.ls.SaveDataViewLineage(ctx, tx, []*interfaces.DataView{view})
+	if err != nil {
+		needRollback = true
+		span.SetStatus(codes.Error, "Save data view lineage failed")
+		return err
+	}
+
+	// 记录修改后的版本
+	err = dvs.mrs.SaveModelRevision(ctx, tx, interfaces.REVISION_MODEL_TYPE_DATA_VIEW, view.ViewID, view.ViewName, view)
+	if err != nil {
+		needRollback = true
+		span.SetStatus(codes.Error, "Save data view revision failed")
+		return err
+	}
+
 	// 对于删了源表又重新创建的视图，需要更新视图的状态为正常
 	if oldView.DeleteTime > 0 {
 		// 更新数据库的视图信息
@@ -717,6 +801,19 @@ func (dvs *dataViewService) GetDataViews(ctx context.Context, viewIDs []string,
 		}
 	}
 
+	// 指定了版本时用版本快照替换当前定义. 引用的视图取当前定义, 所以清除 context 中的版本
+	if revision, ok := ctx.Value(interfaces.MODEL_REVISION_KEY).(int); ok && revision > 0 {
+		ctx = context.WithValue(ctx, interfaces.MODEL_REVISION_KEY, 0)
+		if len(views) == 1 {
+			modelRevision, err := dvs.mrs.GetModelRevision(ctx, interfaces.REVISION_MODEL_TYPE_DATA_VIEW, views[0].ViewID, revision)
+			if err != nil {
+				span.SetStatus(codes.Error, "Get data view revision failed")
+				return nil, err
+			}
+			views[0] = modelRevision.Model.(*interfaces.DataView)
+		}
+	}
+
 	dataSources, err := dvs.dsa.ListDataSources(ctx)
 	if err != nil {
 		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, rest.PublicError_InternalServerError).
@@ -1603,6 +1700,17 @@ func (dvs *dataViewService) commonForCreateAndUpdate(ctx context.Context, view *
 				if err != nil {
 					return err
 				}
+			case interfaces.DataScopeNodeType_Aggregate, interfaces.DataScopeNodeType_Window,
+				interfaces.DataScopeNodeType_Pivot, interfaces.DataScopeNodeType_Unpivot:
+				if view.QueryType != interfaces.QueryType_SQL {
+					return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_DataView_InvalidParameter_DataScope).
+						WithErrorDetails(fmt.Sprintf("The %s node is only supported in sql query type", node.Type))
+				}
+
+				err := validateDerivedNode(ctx, node, nodeMap)
+				if err != nil {
+					return err
+				}
 			case interfaces.DataScopeNodeType_Output:
 				err := validateOutputNode(ctx, node, nodeMap)
 				if err != nil {
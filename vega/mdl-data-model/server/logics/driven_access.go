@@ -17,15 +17,19 @@ var (
 	DDA    interfaces.DataDictAccess
 	DDIA   interfaces.DataDictItemAccess
 	DMJA   interfaces.DataModelJobAccess
+	DQRA   interfaces.DataQualityRuleAccess
 	DSA    interfaces.DataSourceAccess
 	DVA    interfaces.DataViewAccess
 	DVGA   interfaces.DataViewGroupAccess
 	DVRCRA interfaces.DataViewRowColumnRuleAccess
 	EMA    interfaces.EventModelAccess
 	IBA    interfaces.IndexBaseAccess
+	LA     interfaces.LineageAccess
 	MMA    interfaces.MetricModelAccess
 	MMGA   interfaces.MetricModelGroupAccess
 	MMTA   interfaces.MetricModelTaskAccess
+	MTBA   interfaces.MetricTaskBackfillAccess
+	MRA    interfaces.ModelRevisionAccess
 	OMA    interfaces.ObjectiveModelAccess
 	PA     interfaces.PermissionAccess
 	SRA    interfaces.ScanRecordAccess
@@ -55,6 +59,10 @@ func SetDataModelJobAccess(dmja interfaces.DataModelJobAccess) {
 	DMJA = dmja
 }
 
+func SetDataQualityRuleAccess(dqra interfaces.DataQualityRuleAccess) {
+	DQRA = dqra
+}
+
 func SetDataSourceAccess(dsa interfaces.DataSourceAccess) {
 	DSA = dsa
 }
@@ -79,6 +87,10 @@ func SetIndexBaseAccess(iba interfaces.IndexBaseAccess) {
 	IBA = iba
 }
 
+func SetLineageAccess(la interfaces.LineageAccess) {
+	LA = la
+}
+
 func SetMetricModelAccess(mma interfaces.MetricModelAccess) {
 	MMA = mma
 }
@@ -91,6 +103,14 @@ func SetMetricModelTaskAccess(mmta interfaces.MetricModelTaskAccess) {
 	MMTA = mmta
 }
 
+func SetMetricTaskBackfillAccess(mtba interfaces.MetricTaskBackfillAccess) {
+	MTBA = mtba
+}
+
+func SetModelRevisionAccess(mra interfaces.ModelRevisionAccess) {
+	MRA = mra
+}
+
 func SetPermissionAccess(pa interfaces.PermissionAccess) {
 	PA = pa
 }
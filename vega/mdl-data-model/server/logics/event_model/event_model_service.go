@@ -25,6 +25,7 @@ import (
 	"data-model/interfaces"
 	"data-model/logics"
 	"data-model/logics/data_view"
+	"data-model/logics/lineage"
 	"data-model/logics/metric_model"
 	"data-model/logics/permission"
 )
@@ -44,6 +45,7 @@ type eventModelService struct {
 	dmja       interfaces.DataModelJobAccess
 	ema        interfaces.EventModelAccess
 	iba        interfaces.IndexBaseAccess
+	ls         interfaces.LineageService
 	mms        interfaces.MetricModelService
 	dvs        interfaces.DataViewService
 	db         *sql.DB
@@ -59,6 +61,7 @@ func NewEventModelService(appSetting *common.AppSetting) interfaces.EventModelSe
 			dvs:        data_view.NewDataViewService(appSetting),
 			ema:        logics.EMA,
 			iba:        logics.IBA,
+			ls:         lineage.NewLineageService(appSetting),
 			mms:        metric_model.NewMetricModelService(appSetting),
 			ps:         permission.NewPermissionService(appSetting),
 		}
@@ -215,6 +218,55 @@ func (ems *eventModelService) ValidateEventModelDetectRule(ctx context.Context,
 	return nil
 }
 
+// 统计检测只支持定期执行的指标模型数据源，检测算法和配置需合法
+func validateStatisticalDetect(ctx context.Context, dataSourceType string, enableSubscribe int,
+	detectRule interfaces.DetectRule) *rest.HTTPError {
+
+	if dataSourceType != interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_METRIC_MODEL {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.EventModel_InvalidParameter).
+			WithErrorDetails("statistical detect only supports metric model data source")
+	}
+	if enableSubscribe == 1 {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.EventModel_InvalidParameter).
+			WithErrorDetails("statistical detect does not support subscribe mode")
+	}
+
+	if !interfaces.StatisticalDetectAlgo[detectRule.DetectAlgo] {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.EventModel_InvalidParameter).
+			WithErrorDetails(fmt.Sprintf("statistical detect algo %q is not supported", detectRule.DetectAlgo))
+	}
+
+	cfg := detectRule.StatisticalConfig
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Step != "" {
+		step, err := common.ParseDuration(cfg.Step, common.DurationDayHourMinuteRE, true)
+		if err != nil || step <= 0 {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.EventModel_InvalidParameter).
+				WithErrorDetails(fmt.Sprintf("statistical config step %q is invalid", cfg.Step))
+		}
+	}
+	if cfg.Sensitivity < 0 || cfg.Window < 0 || cfg.WarmUp < 0 || cfg.Weeks < 0 {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.EventModel_InvalidParameter).
+			WithErrorDetails("statistical config sensitivity, window, warm_up and weeks must not be negative")
+	}
+	if cfg.Alpha < 0 || cfg.Alpha > 1 {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.EventModel_InvalidParameter).
+			WithErrorDetails("statistical config alpha must be in (0, 1]")
+	}
+	if cfg.Window > 0 && cfg.WarmUp > cfg.Window &&
+		(detectRule.DetectAlgo == "zscore" || detectRule.DetectAlgo == "mad") {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.EventModel_InvalidParameter).
+			WithErrorDetails("statistical config warm_up must not be greater than window")
+	}
+	if cfg.Direction != "" && !interfaces.StatisticalDirection[cfg.Direction] {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.EventModel_InvalidParameter).
+			WithErrorDetails(fmt.Sprintf("statistical config direction %q is not supported", cfg.Direction))
+	}
+	return nil
+}
+
 func (ems *eventModelService) ValidateEventModelAggregateRule(ctx context.Context, AggregateRule interfaces.AggregateRule, aggregateRuleType string) bool {
 	//NOTE: 此处需要提取出去重构，为以后扩展做准备
 	if common.In(AggregateRule.Type, []string{"healthy_compute", "group_aggregation"}) && common.In(AggregateRule.AggregateAlgo, []string{"MaxLevelMap", "SourceDataGroupAggregation", "EventDataGroupAggregation"}) {
@@ -353,6 +405,13 @@ func (ems *eventModelService) EventModelCreateValidate(ctx context.Context, even
 
 	//NOTE ：触发条件校验
 	if eventModel.EventModelType == "atomic" {
+		if eventModel.DetectRule.Type == interfaces.DETECT_RULE_TYPE_FOR_STATISTICAL_DETECT {
+			httpErr = validateStatisticalDetect(ctx, eventModel.DataSourceType, eventModel.EnableSubscribe, eventModel.DetectRule)
+			if httpErr != nil {
+				logger.Errorf("event_model statistical detect rule is illegal: %s,%v", eventModel.EventModelName, httpErr.BaseError.ErrorDetails)
+				return interfaces.EventModel{}, httpErr
+			}
+		}
 		if eventModel.DetectRule.Type != "agi_detect" {
 			httpErr = ems.ValidateEventModelDetectRule(ctx, eventModel.DetectRule, eventModel.DetectRule.Type)
 			if httpErr != nil {
@@ -523,6 +582,13 @@ func (ems *eventModelService) EventModelUpdateValidate(ctx context.Context, even
 
 	//NOTE ：触发条件校验
 	if eventModel.EventModelType == "atomic" {
+		if eventModel.DetectRule.Type == interfaces.DETECT_RULE_TYPE_FOR_STATISTICAL_DETECT {
+			httpErr = validateStatisticalDetect(ctx, eventModel.DataSourceType, eventModel.EnableSubscribe, eventModel.DetectRule)
+			if httpErr != nil {
+				logger.Errorf("event_model statistical detect rule is illegal: %s,%v", eventModel.EventModelName, httpErr.BaseError.ErrorDetails)
+				return httpErr
+			}
+		}
 		if eventModel.DetectRule.Type != "agi_detect" {
 			httpErr = ems.ValidateEventModelDetectRule(ctx, eventModel.DetectRule, eventModel.DetectRule.Type)
 			if httpErr != nil {
@@ -615,6 +681,16 @@ func (ems *eventModelService) CreateEventModels(ctx context.Context, eventModels
 		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, derrors.EventModel_InternalError).WithErrorDetails(err.Error())
 	}
 
+	// 登记模型的血缘
+	lineageModels := make([]*interfaces.EventModel, 0, len(eventModels))
+	for i := range eventModels {
+		lineageModels = append(lineageModels, &eventModels[i])
+	}
+	err = ems.ls.SaveEventModelLineage(ctx, tx, lineageModels)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now().UnixMilli()
 	resrcs := []interfaces.Resource{}
 	//2. 创建模型下的任务
@@ -796,6 +872,9 @@ func (ems *eventModelService) UpdateEventModel(ctx context.Context, emr interfac
 	if emr.DetectRule.AnalysisAlgo != nil {
 		em.DetectRule.AnalysisAlgo = emr.DetectRule.AnalysisAlgo
 	}
+	if emr.DetectRule.StatisticalConfig != nil {
+		em.DetectRule.StatisticalConfig = emr.DetectRule.StatisticalConfig
+	}
 
 	//NOTE 构造聚合规则
 	em.AggregateRule.UpdateTime = now
@@ -851,6 +930,12 @@ func (ems *eventModelService) UpdateEventModel(ctx context.Context, emr interfac
 		return rest.NewHTTPError(ctx, http.StatusInternalServerError, derrors.EventModel_InternalError).
 			WithErrorDetails(err.Error())
 	}
+
+	// 重新登记模型的血缘
+	err = ems.ls.SaveEventModelLineage(ctx, tx, []*interfaces.EventModel{&em})
+	if err != nil {
+		return err
+	}
 	//如果是启停则不需要修改任务。
 	if flag {
 		return nil
@@ -1174,6 +1259,12 @@ func (ems *eventModelService) DeleteEventModels(ctx context.Context, modelIDs []
 			derrors.EventModel_InternalError).WithErrorDetails(err.Error())
 	}
 
+	// 删除模型登记的血缘
+	err = ems.ls.DeleteModelLineage(ctx, tx, interfaces.LINEAGE_NODE_TYPE_EVENT_MODEL, modelIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	logger.Infof("Delete Event Model success:  request delete modelID is %v!", modelIDs)
 
 	// 物理删除事件模型下的持久化任务
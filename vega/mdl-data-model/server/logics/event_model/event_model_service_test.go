@@ -404,6 +404,52 @@ func Test_EventModelService_ValidateEventModelDetectRule(t *testing.T) {
 	}
 }
 
+func Test_ValidateStatisticalDetect(t *testing.T) {
+	statisticalModel := func(algo string, cfg *interfaces.StatisticalConfig) interfaces.EventModel {
+		return interfaces.EventModel{
+			DataSourceType: interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_METRIC_MODEL,
+			IsActive:       1,
+			DetectRule: interfaces.DetectRule{
+				Type:              interfaces.DETECT_RULE_TYPE_FOR_STATISTICAL_DETECT,
+				DetectAlgo:        algo,
+				StatisticalConfig: cfg,
+			},
+		}
+	}
+
+	subscribe := statisticalModel("zscore", nil)
+	subscribe.EnableSubscribe = 1
+	dataView := statisticalModel("zscore", nil)
+	dataView.DataSourceType = interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_DATE_VIEW
+
+	tests := []struct {
+		name  string
+		model interfaces.EventModel
+		legal bool
+	}{
+		{"default config", statisticalModel("seasonal", nil), true},
+		{"full config", statisticalModel("ewma", &interfaces.StatisticalConfig{
+			Step: "5m", Sensitivity: 2.5, Window: 30, WarmUp: 10, Alpha: 0.2, Direction: "up"}), true},
+		{"unsupported algo", statisticalModel("prophet", nil), false},
+		{"data view data source", dataView, false},
+		{"subscribe mode", subscribe, false},
+		{"invalid step", statisticalModel("zscore", &interfaces.StatisticalConfig{Step: "5x"}), false},
+		{"invalid alpha", statisticalModel("ewma", &interfaces.StatisticalConfig{Alpha: 1.5}), false},
+		{"warm up greater than window", statisticalModel("mad", &interfaces.StatisticalConfig{Window: 5, WarmUp: 10}), false},
+		{"invalid direction", statisticalModel("zscore", &interfaces.StatisticalConfig{Direction: "left"}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStatisticalDetect(context.TODO(), tt.model.DataSourceType, tt.model.EnableSubscribe, tt.model.DetectRule)
+			if tt.legal {
+				assert.Equal(t, (*rest.HTTPError)(nil), err)
+			} else {
+				assert.Equal(t, derrors.EventModel_InvalidParameter, err.BaseError.ErrorCode)
+			}
+		})
+	}
+}
+
 func Test_EventModelService_EventModelCreateValidate(t *testing.T) {
 	type fields struct {
 		appSetting *common.AppSetting
@@ -644,6 +690,9 @@ func Test_EventModelService_CreateEventModels(t *testing.T) {
 		dvs := dmock.NewMockDataViewService(mockCtrl)
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		ems, smock := MockNewEventModelService(appSetting, dmja, ema, mms, iba, dvs, ps)
+		ls := dmock.NewMockLineageService(mockCtrl)
+		ems.ls = ls
+		ls.EXPECT().SaveEventModelLineage(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 		// metricModel := interfaces.MetricModel{
 		// 	SimpleMetricModel: interfaces.SimpleMetricModel{
@@ -728,6 +777,9 @@ func Test_EventModelService_UpdateEventModel(t *testing.T) {
 		dvs := dmock.NewMockDataViewService(mockCtrl)
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		ems, smock := MockNewEventModelService(appSetting, dmja, ema, mms, iba, dvs, ps)
+		ls := dmock.NewMockLineageService(mockCtrl)
+		ems.ls = ls
+		ls.EXPECT().SaveEventModelLineage(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 		Convey("UpdateEventModel failed, caused by GetEventModelByID failed ", func() {
 			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
@@ -1027,6 +1079,9 @@ func Test_EventModelService_DeleteEventModels(t *testing.T) {
 		dvs := dmock.NewMockDataViewService(mockCtrl)
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		ems, smock := MockNewEventModelService(appSetting, dmja, ema, mms, iba, dvs, ps)
+		ls := dmock.NewMockLineageService(mockCtrl)
+		ems.ls = ls
+		ls.EXPECT().DeleteModelLineage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 		resrc := map[string]interfaces.ResourceOps{
 			"1": {
 				ResourceID: "1",
@@ -0,0 +1,310 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package lineage
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/mitchellh/mapstructure"
+
+	"data-model/common"
+	"data-model/interfaces"
+)
+
+// 按加入顺序收集血缘边, 同一对节点只保留一条边, 字段映射去重后合并
+type edgeBuilder struct {
+	owner interfaces.LineageNode
+	edges []*interfaces.LineageEdge
+	index map[string]*interfaces.LineageEdge
+	seen  map[string]struct{}
+}
+
+func newEdgeBuilder(owner interfaces.LineageNode) *edgeBuilder {
+	return &edgeBuilder{
+		owner: owner,
+		edges: make([]*interfaces.LineageEdge, 0),
+		index: make(map[string]*interfaces.LineageEdge),
+		seen:  make(map[string]struct{}),
+	}
+}
+
+func (b *edgeBuilder) add(source, target interfaces.LineageNode, fields ...interfaces.LineageFieldMapping) {
+	if source.ID == "" || target.ID == "" {
+		return
+	}
+
+	key := nodeKey(source) + "->" + nodeKey(target)
+	edge, ok := b.index[key]
+	if !ok {
+		edge = &interfaces.LineageEdge{
+			Source: source,
+			Target: target,
+			Fields: make([]interfaces.LineageFieldMapping, 0),
+			Owner:  b.owner,
+		}
+		b.index[key] = edge
+		b.edges = append(b.edges, edge)
+	}
+
+	for _, field := range fields {
+		if field.SourceField == "" || field.TargetField == "" {
+			continue
+		}
+		fieldKey := key + ":" + field.SourceField + "->" + field.TargetField
+		if _, ok := b.seen[fieldKey]; ok {
+			continue
+		}
+		b.seen[fieldKey] = struct{}{}
+		edge.Fields = append(edge.Fields, field)
+	}
+}
+
+func nodeKey(node interfaces.LineageNode) string {
+	return node.Type + ":" + node.ID
+}
+
+func dataViewNode(viewID string) interfaces.LineageNode {
+	return interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, ID: viewID}
+}
+
+// 自定义视图的算子节点 id 在视图内唯一, 血缘中加上视图 id 前缀
+func dataViewScopeNode(viewID, nodeID string) interfaces.LineageNode {
+	return interfaces.LineageNode{
+		Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW_NODE,
+		ID:   fmt.Sprintf("%s/%s", viewID, nodeID),
+	}
+}
+
+func metricModelNode(modelID string) interfaces.LineageNode {
+	return interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_METRIC_MODEL, ID: modelID}
+}
+
+// 原子视图绑定的是数据连接中的数据源表, 不对应 vega-backend 的资源, 不登记上游.
+// vega-backend 的目录 -> 资源由 vega-backend 按真实 id 登记
+// 自定义视图: 依赖的视图 -> 视图节点 -> ... -> 输出节点 -> 视图, 能确定来源的字段按字段映射
+func extractDataViewLineage(view *interfaces.DataView) []*interfaces.LineageEdge {
+	target := dataViewNode(view.ViewID)
+	builder := newEdgeBuilder(target)
+
+	if view.Type != interfaces.ViewType_Custom {
+		return builder.edges
+	}
+
+	nodesMap := make(map[string]*interfaces.DataScopeNode, len(view.DataScope))
+	for _, node := range view.DataScope {
+		nodesMap[node.ID] = node
+	}
+
+	for _, node := range view.DataScope {
+		current := dataViewScopeNode(view.ViewID, node.ID)
+
+		switch node.Type {
+		case interfaces.DataScopeNodeType_View:
+			var cfg interfaces.ViewNodeCfg
+			if err := mapstructure.Decode(node.Config, &cfg); err != nil {
+				logger.Warnf("Decode config of node '%s' in view '%s' failed, %v", node.ID, view.ViewID, err)
+				continue
+			}
+
+			// 视图节点的输出字段名即依赖视图的字段名
+			fields := make([]interfaces.LineageFieldMapping, 0, len(node.OutputFields))
+			for _, of := range node.OutputFields {
+				fields = append(fields, interfaces.LineageFieldMapping{SourceField: of.Name, TargetField: of.Name})
+			}
+			builder.add(dataViewNode(cfg.ViewID), current, fields...)
+
+		case interfaces.DataScopeNodeType_Union:
+			var cfg interfaces.UnionNodeCfg
+			if err := mapstructure.Decode(node.Config, &cfg); err != nil {
+				logger.Warnf("Decode config of node '%s' in view '%s' failed, %v", node.ID, view.ViewID, err)
+				continue
+			}
+
+			for i, inputID := range node.InputNodes {
+				fields := make([]interfaces.LineageFieldMapping, 0)
+				if i < len(cfg.UnionFields) {
+					for j, uf := range cfg.UnionFields[i] {
+						if uf.ValueFrom == "const" || j >= len(node.OutputFields) {
+							continue
+						}
+						if name, ok := resolveNodeField(nodesMap[inputID], uf.Field); ok {
+							fields = append(fields, interfaces.LineageFieldMapping{
+								SourceField: name,
+								TargetField: node.OutputFields[j].Name,
+							})
+						}
+					}
+				}
+				builder.add(dataViewScopeNode(view.ViewID, inputID), current, fields...)
+			}
+
+		default:
+			// join 的输出字段通过 src_node_id 指明来源节点, 单输入的节点来源即唯一的输入节点
+			fieldsMap := make(map[string][]interfaces.LineageFieldMapping, len(node.InputNodes))
+			for _, of := range node.OutputFields {
+				srcNodeID := of.SrcNodeID
+				if srcNodeID == "" && len(node.InputNodes) == 1 {
+					srcNodeID = node.InputNodes[0]
+				}
+				column := of.OriginalName
+				if column == "" {
+					column = of.Name
+				}
+				if name, ok := resolveNodeField(nodesMap[srcNodeID], column); ok {
+					fieldsMap[srcNodeID] = append(fieldsMap[srcNodeID], interfaces.LineageFieldMapping{
+						SourceField: name,
+						TargetField: of.Name,
+					})
+				}
+			}
+			for _, inputID := range node.InputNodes {
+				builder.add(dataViewScopeNode(view.ViewID, inputID), current, fieldsMap[inputID]...)
+			}
+		}
+
+		if node.Type == interfaces.DataScopeNodeType_Output {
+			fields := make([]interfaces.LineageFieldMapping, 0, len(view.Fields))
+			for _, field := range view.Fields {
+				fields = append(fields, interfaces.LineageFieldMapping{SourceField: field.Name, TargetField: field.Name})
+			}
+			builder.add(current, target, fields...)
+		}
+	}
+
+	return builder.edges
+}
+
+// 节点的列名对应的字段名. 视图节点 select 的是原始字段名, 其他节点的列名即字段名
+func resolveNodeField(node *interfaces.DataScopeNode, column string) (string, bool) {
+	if node == nil || column == "" {
+		return "", false
+	}
+
+	for _, of := range node.OutputFields {
+		if of.Name == column {
+			return of.Name, true
+		}
+		if node.Type == interfaces.DataScopeNodeType_View && of.OriginalName == column {
+			return of.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// 原子指标依赖视图, 用到的视图字段即指标的字段; 衍生指标依赖被衍生的指标; 复合指标依赖公式中引用的指标
+func extractMetricModelLineage(model *interfaces.MetricModel) []*interfaces.LineageEdge {
+	target := metricModelNode(model.ModelID)
+	builder := newEdgeBuilder(target)
+
+	switch model.MetricType {
+	case interfaces.ATOMIC_METRIC:
+		// RED指标的数据源是链路模型, 不依赖视图
+		if model.QueryType == interfaces.RED {
+			break
+		}
+		viewID := model.DataViewID
+		if model.DataSource != nil && model.DataSource.ID != "" {
+			viewID = model.DataSource.ID
+		}
+
+		fieldNames := []string{model.DateField, model.MeasureField}
+		for _, dim := range model.AnalysisDims {
+			fieldNames = append(fieldNames, dim.Name)
+		}
+		var sqlConfig *interfaces.SQLConfig
+		switch cfg := model.FormulaConfig.(type) {
+		case interfaces.SQLConfig:
+			sqlConfig = &cfg
+		case *interfaces.SQLConfig:
+			sqlConfig = cfg
+		}
+		if sqlConfig != nil {
+			fieldNames = append(fieldNames, sqlConfig.GroupByFields...)
+			if sqlConfig.AggrExpr != nil {
+				fieldNames = append(fieldNames, sqlConfig.AggrExpr.Field)
+			}
+		}
+
+		fields := make([]interfaces.LineageFieldMapping, 0, len(fieldNames))
+		for _, name := range fieldNames {
+			fields = append(fields, interfaces.LineageFieldMapping{SourceField: name, TargetField: name})
+		}
+		sort.Slice(fields, func(i, j int) bool {
+			return fields[i].SourceField < fields[j].SourceField
+		})
+		builder.add(dataViewNode(viewID), target, fields...)
+
+	case interfaces.DERIVED_METRIC:
+		var derivedConfig *interfaces.DerivedConfig
+		switch cfg := model.FormulaConfig.(type) {
+		case interfaces.DerivedConfig:
+			derivedConfig = &cfg
+		case *interfaces.DerivedConfig:
+			derivedConfig = cfg
+		}
+		if derivedConfig != nil && derivedConfig.DependMetricModel != nil {
+			builder.add(metricModelNode(derivedConfig.DependMetricModel.ID), target)
+		}
+
+	case interfaces.COMPOSITED_METRIC:
+		for _, modelID := range common.ExtractModelIDs(model.Formula) {
+			builder.add(metricModelNode(modelID), target)
+		}
+	}
+
+	return builder.edges
+}
+
+// 目标模型依赖 SLO 的 good/total 指标或 KPI 的综合计算、附加计算指标
+func extractObjectiveModelLineage(model *interfaces.ObjectiveModel) []*interfaces.LineageEdge {
+	target := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_OBJECTIVE_MODEL, ID: model.ModelID}
+	builder := newEdgeBuilder(target)
+
+	switch cfg := model.ObjectiveConfig.(type) {
+	case interfaces.SLOObjective:
+		for _, metric := range []*interfaces.BundleMetricModel{cfg.GoodMetricModel, cfg.TotalMetricModel} {
+			if metric != nil {
+				builder.add(metricModelNode(metric.ID), target)
+			}
+		}
+	case interfaces.KPIObjective:
+		for _, metric := range cfg.ComprehensiveMetricModels {
+			builder.add(metricModelNode(metric.ID), target)
+		}
+		for _, metric := range cfg.AdditionalMetricModels {
+			builder.add(metricModelNode(metric.ID), target)
+		}
+	}
+
+	return builder.edges
+}
+
+// 事件模型依赖 data_source 中的指标模型、视图或事件模型
+func extractEventModelLineage(model *interfaces.EventModel) []*interfaces.LineageEdge {
+	target := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_EVENT_MODEL, ID: model.EventModelID}
+	builder := newEdgeBuilder(target)
+
+	sourceType := ""
+	switch model.DataSourceType {
+	case interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_METRIC_MODEL:
+		sourceType = interfaces.LINEAGE_NODE_TYPE_METRIC_MODEL
+	case interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_DATE_VIEW:
+		sourceType = interfaces.LINEAGE_NODE_TYPE_DATA_VIEW
+	case interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_EVENT_MODEL:
+		sourceType = interfaces.LINEAGE_NODE_TYPE_EVENT_MODEL
+	default:
+		return builder.edges
+	}
+
+	for _, sourceID := range model.DataSource {
+		builder.add(interfaces.LineageNode{Type: sourceType, ID: sourceID}, target)
+	}
+
+	return builder.edges
+}
@@ -0,0 +1,413 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package lineage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+	"data-model/logics"
+	"data-model/logics/permission"
+)
+
+var (
+	lServiceOnce sync.Once
+	lService     interfaces.LineageService
+
+	// 血缘节点类型对应的权限资源类型, 查询时校验起始节点的查看权限
+	lineageResourceTypeMap = map[string]string{
+		interfaces.LINEAGE_NODE_TYPE_DATA_VIEW:       interfaces.RESOURCE_TYPE_DATA_VIEW,
+		interfaces.LINEAGE_NODE_TYPE_DATA_VIEW_NODE:  interfaces.RESOURCE_TYPE_DATA_VIEW,
+		interfaces.LINEAGE_NODE_TYPE_METRIC_MODEL:    interfaces.RESOURCE_TYPE_METRIC_MODEL,
+		interfaces.LINEAGE_NODE_TYPE_OBJECTIVE_MODEL: interfaces.RESOURCE_TYPE_OBJECTIVE_MODEL,
+		interfaces.LINEAGE_NODE_TYPE_EVENT_MODEL:     interfaces.RESOURCE_TYPE_EVENT_MODEL,
+	}
+)
+
+type lineageService struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+	la         interfaces.LineageAccess
+	ps         interfaces.PermissionService
+}
+
+func NewLineageService(appSetting *common.AppSetting) interfaces.LineageService {
+	lServiceOnce.Do(func() {
+		lService = &lineageService{
+			appSetting: appSetting,
+			db:         logics.DB,
+			la:         logics.LA,
+			ps:         permission.NewPermissionService(appSetting),
+		}
+	})
+
+	return lService
+}
+
+// 遍历的状态, field 为空表示整个节点受影响
+type lineageState struct {
+	node  interfaces.LineageNode
+	field string
+}
+
+// 查询血缘图
+func (ls *lineageService) GetLineage(ctx context.Context, query *interfaces.LineageQuery) (*interfaces.LineageGraph, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Get lineage")
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("node_type").String(query.Node.Type),
+		attr.Key("node_id").String(query.Node.ID),
+		attr.Key("direction").String(query.Direction),
+	)
+
+	if resourceType, ok := lineageResourceTypeMap[query.Node.Type]; ok {
+		resourceID := query.Node.ID
+		if query.Node.Type == interfaces.LINEAGE_NODE_TYPE_DATA_VIEW_NODE {
+			resourceID = strings.SplitN(resourceID, "/", 2)[0]
+		}
+		err := ls.ps.CheckPermission(ctx, interfaces.Resource{Type: resourceType, ID: resourceID},
+			[]string{interfaces.OPERATION_TYPE_VIEW_DETAIL})
+		if err != nil {
+			span.SetStatus(codes.Error, "Check permission failed")
+			return nil, err
+		}
+	}
+
+	graph := &interfaces.LineageGraph{
+		Nodes: []*interfaces.LineageGraphNode{{LineageNode: query.Node}},
+		Edges: make([]*interfaces.LineageEdge, 0),
+	}
+	graphNodes := map[string]*interfaces.LineageGraphNode{nodeKey(query.Node): graph.Nodes[0]}
+	graphEdges := make(map[string]struct{})
+
+	directions := []string{query.Direction}
+	if query.Direction == interfaces.LINEAGE_DIRECTION_BOTH {
+		directions = []string{interfaces.LINEAGE_DIRECTION_UPSTREAM, interfaces.LINEAGE_DIRECTION_DOWNSTREAM}
+	}
+
+	for _, direction := range directions {
+		err := ls.traverse(ctx, query, direction, graph, graphNodes, graphEdges)
+		if err != nil {
+			span.SetStatus(codes.Error, "Traverse lineage failed")
+			return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+				derrors.DataModel_Lineage_InternalError_GetLineageFailed).WithErrorDetails(err.Error())
+		}
+	}
+
+	for _, node := range graph.Nodes {
+		sort.Strings(node.Fields)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return graph, nil
+}
+
+// 按层展开血缘. 视图内部的算子节点不计入深度, 这样视图到视图的距离与是否为自定义视图无关
+func (ls *lineageService) traverse(ctx context.Context, query *interfaces.LineageQuery, direction string,
+	graph *interfaces.LineageGraph, graphNodes map[string]*interfaces.LineageGraphNode, graphEdges map[string]struct{}) error {
+
+	sign := 1
+	if direction == interfaces.LINEAGE_DIRECTION_UPSTREAM {
+		sign = -1
+	}
+
+	start := lineageState{node: query.Node, field: query.Field}
+	visited := map[lineageState]struct{}{start: {}}
+	current := []lineageState{start}
+
+	for level := 0; level < query.Depth && len(current) > 0; level++ {
+		next := make([]lineageState, 0)
+
+		for len(current) > 0 {
+			nodes := make([]interfaces.LineageNode, 0, len(current))
+			statesMap := make(map[string][]lineageState)
+			for _, state := range current {
+				key := nodeKey(state.node)
+				if _, ok := statesMap[key]; !ok {
+					nodes = append(nodes, state.node)
+				}
+				statesMap[key] = append(statesMap[key], state)
+			}
+
+			edges, err := ls.la.ListLineageEdgesByNodes(ctx, direction, nodes)
+			if err != nil {
+				return err
+			}
+
+			sameLevel := make([]lineageState, 0)
+			for _, edge := range edges {
+				near, far := edge.Source, edge.Target
+				if direction == interfaces.LINEAGE_DIRECTION_UPSTREAM {
+					near, far = edge.Target, edge.Source
+				}
+
+				propagated := false
+				for _, state := range statesMap[nodeKey(near)] {
+					for _, field := range propagateField(edge, direction, state.field) {
+						propagated = true
+
+						farState := lineageState{node: far, field: field}
+						if _, ok := visited[farState]; ok {
+							continue
+						}
+						visited[farState] = struct{}{}
+
+						depth := level + 1
+						if far.Type == interfaces.LINEAGE_NODE_TYPE_DATA_VIEW_NODE {
+							depth = level
+							sameLevel = append(sameLevel, farState)
+						} else {
+							next = append(next, farState)
+						}
+						addGraphNode(graph, graphNodes, far, sign*depth, field)
+					}
+				}
+
+				if _, ok := graphEdges[edge.ID]; propagated && !ok {
+					graphEdges[edge.ID] = struct{}{}
+					graph.Edges = append(graph.Edges, edge)
+				}
+			}
+
+			current = sameLevel
+		}
+
+		current = next
+	}
+
+	return nil
+}
+
+// 沿一条边传播字段. 整个节点受影响时下游整个节点受影响;
+// 指定字段时只沿包含该字段的映射传播, 边上没有字段映射时认为整个节点受影响
+func propagateField(edge *interfaces.LineageEdge, direction string, field string) []string {
+	if field == "" || len(edge.Fields) == 0 {
+		return []string{""}
+	}
+
+	fields := make([]string, 0)
+	for _, mapping := range edge.Fields {
+		if direction == interfaces.LINEAGE_DIRECTION_UPSTREAM {
+			if mapping.TargetField == field {
+				fields = append(fields, mapping.SourceField)
+			}
+		} else if mapping.SourceField == field {
+			fields = append(fields, mapping.TargetField)
+		}
+	}
+
+	return fields
+}
+
+func addGraphNode(graph *interfaces.LineageGraph, graphNodes map[string]*interfaces.LineageGraphNode,
+	node interfaces.LineageNode, depth int, field string) {
+
+	key := nodeKey(node)
+	graphNode, ok := graphNodes[key]
+	if !ok {
+		graphNode = &interfaces.LineageGraphNode{LineageNode: node, Depth: depth}
+		graphNodes[key] = graphNode
+		graph.Nodes = append(graph.Nodes, graphNode)
+	}
+
+	if field != "" && !common.IsContain(graphNode.Fields, field) {
+		graphNode.Fields = append(graphNode.Fields, field)
+	}
+}
+
+// 外部服务登记其模型的血缘, 边的下游须为该模型
+func (ls *lineageService) ReplaceLineageEdges(ctx context.Context, owner interfaces.LineageNode,
+	edges []*interfaces.LineageEdge) (err error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Replace lineage edges")
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("owner_type").String(owner.Type),
+		attr.Key("owner_id").String(owner.ID),
+	)
+
+	err = ls.validateExternalOwner(ctx, owner)
+	if err != nil {
+		span.SetStatus(codes.Error, "Validate lineage owner failed")
+		return err
+	}
+
+	for i, edge := range edges {
+		if edge == nil || edge.Target != owner {
+			span.SetStatus(codes.Error, "Invalid lineage edge")
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_Lineage_InvalidParameter_Edges).
+				WithErrorDetails(fmt.Sprintf("The target of edge[%d] should be the owner %s '%s'", i, owner.Type, owner.ID))
+		}
+		if _, ok := interfaces.LineageNodeTypeMap[edge.Source.Type]; !ok || edge.Source.ID == "" {
+			span.SetStatus(codes.Error, "Invalid lineage edge")
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_Lineage_InvalidParameter_Edges).
+				WithErrorDetails(fmt.Sprintf("The source of edge[%d] is invalid, type '%s', id '%s'",
+					i, edge.Source.Type, edge.Source.ID))
+		}
+	}
+
+	tx, err := ls.db.Begin()
+	if err != nil {
+		logger.Errorf("Begin transaction error: %s", err.Error())
+		span.SetStatus(codes.Error, "Begin transaction failed")
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_Lineage_InternalError_BeginTransactionFailed).WithErrorDetails(err.Error())
+	}
+	defer func() {
+		switch err {
+		case nil:
+			if commitErr := tx.Commit(); commitErr != nil {
+				logger.Errorf("ReplaceLineageEdges Transaction Commit Failed: %v", commitErr)
+				span.SetStatus(codes.Error, "Commit transaction failed")
+				err = rest.NewHTTPError(ctx, http.StatusInternalServerError,
+					derrors.DataModel_Lineage_InternalError_SaveLineageFailed).WithErrorDetails(commitErr.Error())
+			}
+		default:
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				logger.Errorf("ReplaceLineageEdges Transaction Rollback Error: %v", rollbackErr)
+			}
+		}
+	}()
+
+	err = ls.la.ReplaceLineageEdges(ctx, tx, owner, edges)
+	if err != nil {
+		span.SetStatus(codes.Error, "Replace lineage edges failed")
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_Lineage_InternalError_SaveLineageFailed).WithErrorDetails(err.Error())
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// 外部服务删除其模型时清理登记的血缘
+func (ls *lineageService) DeleteLineageEdges(ctx context.Context, owner interfaces.LineageNode) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Delete lineage edges")
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("owner_type").String(owner.Type),
+		attr.Key("owner_id").String(owner.ID),
+	)
+
+	err := ls.validateExternalOwner(ctx, owner)
+	if err != nil {
+		span.SetStatus(codes.Error, "Validate lineage owner failed")
+		return err
+	}
+
+	err = ls.la.DeleteLineageEdgesByOwners(ctx, nil, owner.Type, []string{owner.ID})
+	if err != nil {
+		span.SetStatus(codes.Error, "Delete lineage edges failed")
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_Lineage_InternalError_DeleteLineageFailed).WithErrorDetails(err.Error())
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+func (ls *lineageService) validateExternalOwner(ctx context.Context, owner interfaces.LineageNode) error {
+	if _, ok := interfaces.LineageExternalOwnerTypeMap[owner.Type]; !ok {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_Lineage_InvalidParameter_OwnerType).
+			WithErrorDetails(fmt.Sprintf("Lineage of '%s' can not be registered by other services", owner.Type))
+	}
+	if owner.ID == "" {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_Lineage_NullParameter_NodeID).
+			WithErrorDetails("The owner id is empty")
+	}
+
+	return nil
+}
+
+// 保存数据视图的血缘, 在视图创建或修改的事务内执行
+func (ls *lineageService) SaveDataViewLineage(ctx context.Context, tx *sql.Tx, views []*interfaces.DataView) error {
+	for _, view := range views {
+		err := ls.replaceModelLineage(ctx, tx, dataViewNode(view.ViewID), extractDataViewLineage(view))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 保存指标模型的血缘, 在指标模型创建或修改的事务内执行
+func (ls *lineageService) SaveMetricModelLineage(ctx context.Context, tx *sql.Tx, models []*interfaces.MetricModel) error {
+	for _, model := range models {
+		err := ls.replaceModelLineage(ctx, tx, metricModelNode(model.ModelID), extractMetricModelLineage(model))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 保存目标模型的血缘, 在目标模型创建或修改的事务内执行
+func (ls *lineageService) SaveObjectiveModelLineage(ctx context.Context, tx *sql.Tx, models []*interfaces.ObjectiveModel) error {
+	for _, model := range models {
+		owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_OBJECTIVE_MODEL, ID: model.ModelID}
+		err := ls.replaceModelLineage(ctx, tx, owner, extractObjectiveModelLineage(model))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 保存事件模型的血缘, 在事件模型创建或修改的事务内执行
+func (ls *lineageService) SaveEventModelLineage(ctx context.Context, tx *sql.Tx, models []*interfaces.EventModel) error {
+	for _, model := range models {
+		owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_EVENT_MODEL, ID: model.EventModelID}
+		err := ls.replaceModelLineage(ctx, tx, owner, extractEventModelLineage(model))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 删除模型登记的血缘. 以该模型为上游的边由下游模型登记, 保留下来用于影响分析
+func (ls *lineageService) DeleteModelLineage(ctx context.Context, tx *sql.Tx, ownerType string, ownerIDs []string) error {
+	if len(ownerIDs) == 0 {
+		return nil
+	}
+
+	err := ls.la.DeleteLineageEdgesByOwners(ctx, tx, ownerType, ownerIDs)
+	if err != nil {
+		logger.Errorf("Delete lineage of %s %v error: %s", ownerType, ownerIDs, err.Error())
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_Lineage_InternalError_DeleteLineageFailed).WithErrorDetails(err.Error())
+	}
+	return nil
+}
+
+func (ls *lineageService) replaceModelLineage(ctx context.Context, tx *sql.Tx, owner interfaces.LineageNode,
+	edges []*interfaces.LineageEdge) error {
+
+	err := ls.la.ReplaceLineageEdges(ctx, tx, owner, edges)
+	if err != nil {
+		logger.Errorf("Save lineage of %s '%s' error: %s", owner.Type, owner.ID, err.Error())
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_Lineage_InternalError_SaveLineageFailed).WithErrorDetails(err.Error())
+	}
+	return nil
+}
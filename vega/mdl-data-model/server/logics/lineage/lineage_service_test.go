@@ -0,0 +1,351 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package lineage
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+	dmock "data-model/interfaces/mock"
+)
+
+func MockNewLineageService(appSetting *common.AppSetting,
+	la interfaces.LineageAccess,
+	ps interfaces.PermissionService) (*lineageService, sqlmock.Sqlmock) {
+
+	db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	ls := &lineageService{
+		appSetting: appSetting,
+		db:         db,
+		la:         la,
+		ps:         ps,
+	}
+	return ls, smock
+}
+
+func newTestEdge(id string, source, target interfaces.LineageNode, fields ...interfaces.LineageFieldMapping) *interfaces.LineageEdge {
+	if fields == nil {
+		fields = []interfaces.LineageFieldMapping{}
+	}
+	return &interfaces.LineageEdge{
+		ID:     id,
+		Source: source,
+		Target: target,
+		Fields: fields,
+		Owner:  target,
+	}
+}
+
+func graphNodeDepths(graph *interfaces.LineageGraph) map[string]int {
+	res := make(map[string]int, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		res[nodeKey(node.LineageNode)] = node.Depth
+	}
+	return res
+}
+
+func Test_LineageService_GetLineage(t *testing.T) {
+	Convey("Test GetLineage", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		la := dmock.NewMockLineageAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		ls, _ := MockNewLineageService(appSetting, la, ps)
+
+		v1 := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, ID: "v1"}
+		v2 := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, ID: "v2"}
+		n1 := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW_NODE, ID: "v2/n1"}
+		n2 := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW_NODE, ID: "v2/n2"}
+		m1 := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_METRIC_MODEL, ID: "m1"}
+		m2 := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_METRIC_MODEL, ID: "m2"}
+		o1 := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_OBJECTIVE_MODEL, ID: "o1"}
+
+		// v1 -> v2/n1 -> v2/n2 -> v2 -> m1 -> o1, v1 -> m2
+		e1 := newTestEdge("e1", v1, n1,
+			interfaces.LineageFieldMapping{SourceField: "a", TargetField: "a"},
+			interfaces.LineageFieldMapping{SourceField: "b", TargetField: "b"})
+		e2 := newTestEdge("e2", n1, n2,
+			interfaces.LineageFieldMapping{SourceField: "a", TargetField: "x"},
+			interfaces.LineageFieldMapping{SourceField: "b", TargetField: "y"})
+		e3 := newTestEdge("e3", n2, v2,
+			interfaces.LineageFieldMapping{SourceField: "x", TargetField: "x"},
+			interfaces.LineageFieldMapping{SourceField: "y", TargetField: "y"})
+		e4 := newTestEdge("e4", v2, m1,
+			interfaces.LineageFieldMapping{SourceField: "y", TargetField: "y"})
+		e5 := newTestEdge("e5", m1, o1)
+		e6 := newTestEdge("e6", v1, m2,
+			interfaces.LineageFieldMapping{SourceField: "b", TargetField: "b"})
+
+		Convey("Get failed, caused by permission error", func() {
+			expectedErr := rest.NewHTTPError(testCtx, http.StatusForbidden, rest.PublicError_Forbidden)
+			ps.EXPECT().CheckPermission(gomock.Any(), interfaces.Resource{Type: interfaces.RESOURCE_TYPE_DATA_VIEW, ID: "v2"},
+				[]string{interfaces.OPERATION_TYPE_VIEW_DETAIL}).Return(expectedErr)
+
+			_, err := ls.GetLineage(testCtx, &interfaces.LineageQuery{
+				Node:      n1,
+				Direction: interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				Depth:     interfaces.DEFAULT_LINEAGE_DEPTH,
+			})
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Get failed, caused by list edges error", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(nil, errors.New("some error"))
+
+			_, err := ls.GetLineage(testCtx, &interfaces.LineageQuery{
+				Node:      v1,
+				Direction: interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				Depth:     interfaces.DEFAULT_LINEAGE_DEPTH,
+			})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_Lineage_InternalError_GetLineageFailed)
+		})
+
+		Convey("Get downstream, view nodes are not counted in depth", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				[]interfaces.LineageNode{v1}).Return([]*interfaces.LineageEdge{e1, e6}, nil)
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				[]interfaces.LineageNode{n1}).Return([]*interfaces.LineageEdge{e2}, nil)
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				[]interfaces.LineageNode{n2}).Return([]*interfaces.LineageEdge{e3}, nil)
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				[]interfaces.LineageNode{m2, v2}).Return([]*interfaces.LineageEdge{e4}, nil)
+
+			graph, err := ls.GetLineage(testCtx, &interfaces.LineageQuery{
+				Node:      v1,
+				Direction: interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				Depth:     2,
+			})
+			So(err, ShouldBeNil)
+			So(graphNodeDepths(graph), ShouldResemble, map[string]int{
+				"data_view:v1":         0,
+				"data_view_node:v2/n1": 0,
+				"data_view_node:v2/n2": 0,
+				"metric_model:m2":      1,
+				"data_view:v2":         1,
+				"metric_model:m1":      2,
+			})
+			So(len(graph.Edges), ShouldEqual, 5)
+		})
+
+		Convey("Get downstream of a field", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				[]interfaces.LineageNode{v1}).Return([]*interfaces.LineageEdge{e1, e6}, nil)
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				[]interfaces.LineageNode{n1}).Return([]*interfaces.LineageEdge{e2}, nil)
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				[]interfaces.LineageNode{n2}).Return([]*interfaces.LineageEdge{e3}, nil)
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				[]interfaces.LineageNode{v2}).Return([]*interfaces.LineageEdge{e4}, nil)
+
+			graph, err := ls.GetLineage(testCtx, &interfaces.LineageQuery{
+				Node:      v1,
+				Field:     "a",
+				Direction: interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				Depth:     interfaces.DEFAULT_LINEAGE_DEPTH,
+			})
+			So(err, ShouldBeNil)
+			// 字段 a 只流向 v2 的 x, 不影响 m1 和 m2
+			So(graphNodeDepths(graph), ShouldResemble, map[string]int{
+				"data_view:v1":         0,
+				"data_view_node:v2/n1": 0,
+				"data_view_node:v2/n2": 0,
+				"data_view:v2":         1,
+			})
+			So(graph.Nodes[3].Fields, ShouldResemble, []string{"x"})
+			So(len(graph.Edges), ShouldEqual, 3)
+		})
+
+		Convey("Get both directions of a metric model", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), interfaces.Resource{Type: interfaces.RESOURCE_TYPE_METRIC_MODEL, ID: "m1"},
+				gomock.Any()).Return(nil)
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), interfaces.LINEAGE_DIRECTION_UPSTREAM,
+				[]interfaces.LineageNode{m1}).Return([]*interfaces.LineageEdge{e4}, nil)
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), interfaces.LINEAGE_DIRECTION_DOWNSTREAM,
+				[]interfaces.LineageNode{m1}).Return([]*interfaces.LineageEdge{e5}, nil)
+
+			graph, err := ls.GetLineage(testCtx, &interfaces.LineageQuery{
+				Node:      m1,
+				Direction: interfaces.LINEAGE_DIRECTION_BOTH,
+				Depth:     1,
+			})
+			So(err, ShouldBeNil)
+			So(graphNodeDepths(graph), ShouldResemble, map[string]int{
+				"metric_model:m1":    0,
+				"data_view:v2":       -1,
+				"objective_model:o1": 1,
+			})
+			So(len(graph.Edges), ShouldEqual, 2)
+		})
+
+		Convey("Get upstream of an object type without permission check", func() {
+			ot := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE, ID: "kn1/ot1"}
+			la.EXPECT().ListLineageEdgesByNodes(gomock.Any(), interfaces.LINEAGE_DIRECTION_UPSTREAM,
+				[]interfaces.LineageNode{ot}).Return([]*interfaces.LineageEdge{}, nil)
+
+			graph, err := ls.GetLineage(testCtx, &interfaces.LineageQuery{
+				Node:      ot,
+				Direction: interfaces.LINEAGE_DIRECTION_UPSTREAM,
+				Depth:     interfaces.DEFAULT_LINEAGE_DEPTH,
+			})
+			So(err, ShouldBeNil)
+			So(len(graph.Nodes), ShouldEqual, 1)
+			So(len(graph.Edges), ShouldEqual, 0)
+		})
+	})
+}
+
+func Test_LineageService_ReplaceLineageEdges(t *testing.T) {
+	Convey("Test ReplaceLineageEdges", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		la := dmock.NewMockLineageAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		ls, smock := MockNewLineageService(appSetting, la, ps)
+
+		owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_OBJECT_TYPE, ID: "kn1/ot1"}
+		v1 := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_DATA_VIEW, ID: "v1"}
+
+		Convey("Replace failed, caused by invalid owner type", func() {
+			err := ls.ReplaceLineageEdges(testCtx, v1, nil)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_Lineage_InvalidParameter_OwnerType)
+		})
+
+		Convey("Replace failed, caused by edge target is not the owner", func() {
+			err := ls.ReplaceLineageEdges(testCtx, owner, []*interfaces.LineageEdge{newTestEdge("", owner, v1)})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_Lineage_InvalidParameter_Edges)
+		})
+
+		Convey("Replace failed, caused by invalid edge source", func() {
+			source := interfaces.LineageNode{Type: "unknown", ID: "x"}
+			err := ls.ReplaceLineageEdges(testCtx, owner, []*interfaces.LineageEdge{newTestEdge("", source, owner)})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_Lineage_InvalidParameter_Edges)
+		})
+
+		Convey("Replace failed, caused by access error", func() {
+			smock.ExpectBegin()
+			smock.ExpectRollback()
+			la.EXPECT().ReplaceLineageEdges(gomock.Any(), gomock.Any(), owner, gomock.Any()).
+				Return(errors.New("some error"))
+
+			err := ls.ReplaceLineageEdges(testCtx, owner, []*interfaces.LineageEdge{newTestEdge("", v1, owner)})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_Lineage_InternalError_SaveLineageFailed)
+		})
+
+		Convey("Replace succeed", func() {
+			smock.ExpectBegin()
+			smock.ExpectCommit()
+			la.EXPECT().ReplaceLineageEdges(gomock.Any(), gomock.Any(), owner, gomock.Any()).Return(nil)
+
+			err := ls.ReplaceLineageEdges(testCtx, owner, []*interfaces.LineageEdge{newTestEdge("", v1, owner)})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Replace succeed, vega-backend registers the catalog of a resource", func() {
+			resource := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_RESOURCE, ID: "r1"}
+			catalog := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_CATALOG, ID: "c1"}
+			smock.ExpectBegin()
+			smock.ExpectCommit()
+			la.EXPECT().ReplaceLineageEdges(gomock.Any(), gomock.Any(), resource, gomock.Len(1)).Return(nil)
+
+			err := ls.ReplaceLineageEdges(testCtx, resource, []*interfaces.LineageEdge{newTestEdge("", catalog, resource)})
+			So(err, ShouldBeNil)
+		})
+
+		if err := smock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func Test_LineageService_DeleteLineageEdges(t *testing.T) {
+	Convey("Test DeleteLineageEdges", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		la := dmock.NewMockLineageAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		ls, _ := MockNewLineageService(appSetting, la, ps)
+
+		owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_RELATION_TYPE, ID: "kn1/rt1"}
+
+		Convey("Delete failed, caused by empty owner id", func() {
+			err := ls.DeleteLineageEdges(testCtx, interfaces.LineageNode{Type: owner.Type})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_Lineage_NullParameter_NodeID)
+		})
+
+		Convey("Delete failed, caused by access error", func() {
+			la.EXPECT().DeleteLineageEdgesByOwners(gomock.Any(), (*sql.Tx)(nil), owner.Type, []string{owner.ID}).
+				Return(errors.New("some error"))
+
+			err := ls.DeleteLineageEdges(testCtx, owner)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_Lineage_InternalError_DeleteLineageFailed)
+		})
+
+		Convey("Delete succeed", func() {
+			la.EXPECT().DeleteLineageEdgesByOwners(gomock.Any(), (*sql.Tx)(nil), owner.Type, []string{owner.ID}).Return(nil)
+
+			err := ls.DeleteLineageEdges(testCtx, owner)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func Test_LineageService_SaveMetricModelLineage(t *testing.T) {
+	Convey("Test SaveMetricModelLineage", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		la := dmock.NewMockLineageAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		ls, _ := MockNewLineageService(appSetting, la, ps)
+
+		models := []*interfaces.MetricModel{
+			{
+				SimpleMetricModel: interfaces.SimpleMetricModel{
+					ModelID:    "m3",
+					MetricType: interfaces.COMPOSITED_METRIC,
+					Formula:    "{{m1}} + {{m2}}",
+				},
+			},
+		}
+
+		Convey("Save failed", func() {
+			la.EXPECT().ReplaceLineageEdges(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(errors.New("some error"))
+
+			err := ls.SaveMetricModelLineage(testCtx, nil, models)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_Lineage_InternalError_SaveLineageFailed)
+		})
+
+		Convey("Save succeed", func() {
+			la.EXPECT().ReplaceLineageEdges(gomock.Any(), gomock.Any(),
+				interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_METRIC_MODEL, ID: "m3"}, gomock.Len(2)).Return(nil)
+
+			err := ls.SaveMetricModelLineage(testCtx, nil, models)
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,295 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package lineage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/interfaces"
+)
+
+var (
+	testCtx = context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+)
+
+func edgeSummary(edges []*interfaces.LineageEdge) []string {
+	res := make([]string, 0, len(edges))
+	for _, edge := range edges {
+		res = append(res, nodeKey(edge.Source)+"->"+nodeKey(edge.Target))
+	}
+	return res
+}
+
+func Test_Lineage_ExtractDataViewLineage(t *testing.T) {
+	Convey("Test extractDataViewLineage", t, func() {
+		Convey("Atomic view has no upstream in lineage", func() {
+			// 原子视图的数据源不是 vega-backend 的资源, 不编造目录和资源节点
+			view := &interfaces.DataView{
+				SimpleDataView: interfaces.SimpleDataView{
+					ViewID:        "v1",
+					Type:          interfaces.ViewType_Atomic,
+					DataSourceID:  "c1",
+					TechnicalName: "orders",
+				},
+				Fields: []*interfaces.ViewField{
+					{Name: "order_id", OriginalName: "id"},
+					{Name: "amount"},
+				},
+			}
+
+			edges := extractDataViewLineage(view)
+			So(len(edges), ShouldEqual, 0)
+		})
+
+		Convey("Atomic view without data source", func() {
+			view := &interfaces.DataView{
+				SimpleDataView: interfaces.SimpleDataView{
+					ViewID: "v1",
+					Type:   interfaces.ViewType_Atomic,
+				},
+			}
+
+			edges := extractDataViewLineage(view)
+			So(len(edges), ShouldEqual, 0)
+		})
+
+		Convey("Custom view joins two views", func() {
+			view := &interfaces.DataView{
+				SimpleDataView: interfaces.SimpleDataView{
+					ViewID: "v3",
+					Type:   interfaces.ViewType_Custom,
+				},
+				Fields: []*interfaces.ViewField{{Name: "id"}, {Name: "user_name"}},
+				DataScope: []*interfaces.DataScopeNode{
+					{
+						ID:           "n1",
+						Type:         interfaces.DataScopeNodeType_View,
+						Config:       map[string]any{"view_id": "v1"},
+						OutputFields: []*interfaces.ViewField{{Name: "id", OriginalName: "f_id"}},
+					},
+					{
+						ID:           "n2",
+						Type:         interfaces.DataScopeNodeType_View,
+						Config:       map[string]any{"view_id": "v2"},
+						OutputFields: []*interfaces.ViewField{{Name: "name"}},
+					},
+					{
+						ID:         "n3",
+						Type:       interfaces.DataScopeNodeType_Join,
+						InputNodes: []string{"n1", "n2"},
+						OutputFields: []*interfaces.ViewField{
+							{Name: "id", SrcNodeID: "n1"},
+							{Name: "user_name", OriginalName: "name", SrcNodeID: "n2"},
+						},
+					},
+					{
+						ID:         "n4",
+						Type:       interfaces.DataScopeNodeType_Output,
+						InputNodes: []string{"n3"},
+						OutputFields: []*interfaces.ViewField{
+							{Name: "id"},
+							{Name: "user_name"},
+						},
+					},
+				},
+			}
+
+			edges := extractDataViewLineage(view)
+			So(edgeSummary(edges), ShouldResemble, []string{
+				"data_view:v1->data_view_node:v3/n1",
+				"data_view:v2->data_view_node:v3/n2",
+				"data_view_node:v3/n1->data_view_node:v3/n3",
+				"data_view_node:v3/n2->data_view_node:v3/n3",
+				"data_view_node:v3/n3->data_view_node:v3/n4",
+				"data_view_node:v3/n4->data_view:v3",
+			})
+			So(edges[3].Fields, ShouldResemble, []interfaces.LineageFieldMapping{
+				{SourceField: "name", TargetField: "user_name"},
+			})
+			So(edges[5].Owner, ShouldResemble, interfaces.LineageNode{Type: "data_view", ID: "v3"})
+		})
+
+		Convey("Custom view unions two views", func() {
+			view := &interfaces.DataView{
+				SimpleDataView: interfaces.SimpleDataView{
+					ViewID: "v3",
+					Type:   interfaces.ViewType_Custom,
+				},
+				DataScope: []*interfaces.DataScopeNode{
+					{
+						ID:           "n1",
+						Type:         interfaces.DataScopeNodeType_View,
+						Config:       map[string]any{"view_id": "v1"},
+						OutputFields: []*interfaces.ViewField{{Name: "a"}},
+					},
+					{
+						ID:           "n2",
+						Type:         interfaces.DataScopeNodeType_View,
+						Config:       map[string]any{"view_id": "v2"},
+						OutputFields: []*interfaces.ViewField{{Name: "b"}},
+					},
+					{
+						ID:         "n3",
+						Type:       interfaces.DataScopeNodeType_Union,
+						InputNodes: []string{"n1", "n2"},
+						Config: map[string]any{
+							"union_fields": []any{
+								[]any{map[string]any{"field": "a", "value_from": "field"}},
+								[]any{map[string]any{"field": "0", "value_from": "const"}},
+							},
+						},
+						OutputFields: []*interfaces.ViewField{{Name: "c"}},
+					},
+				},
+			}
+
+			edges := extractDataViewLineage(view)
+			So(edgeSummary(edges), ShouldResemble, []string{
+				"data_view:v1->data_view_node:v3/n1",
+				"data_view:v2->data_view_node:v3/n2",
+				"data_view_node:v3/n1->data_view_node:v3/n3",
+				"data_view_node:v3/n2->data_view_node:v3/n3",
+			})
+			So(edges[2].Fields, ShouldResemble, []interfaces.LineageFieldMapping{
+				{SourceField: "a", TargetField: "c"},
+			})
+			So(len(edges[3].Fields), ShouldEqual, 0)
+		})
+	})
+}
+
+func Test_Lineage_ExtractMetricModelLineage(t *testing.T) {
+	Convey("Test extractMetricModelLineage", t, func() {
+		Convey("Atomic metric depends on view fields", func() {
+			model := &interfaces.MetricModel{
+				SimpleMetricModel: interfaces.SimpleMetricModel{
+					ModelID:      "m1",
+					MetricType:   interfaces.ATOMIC_METRIC,
+					DataViewID:   "v1",
+					DateField:    "@timestamp",
+					MeasureField: "value",
+					AnalysisDims: []interfaces.Field{{Name: "host"}},
+					FormulaConfig: interfaces.SQLConfig{
+						AggrExpr:      &interfaces.AggrExpr{Field: "amount", Aggr: "sum"},
+						GroupByFields: []string{"host"},
+					},
+				},
+			}
+
+			edges := extractMetricModelLineage(model)
+			So(edgeSummary(edges), ShouldResemble, []string{"data_view:v1->metric_model:m1"})
+			So(edges[0].Fields, ShouldResemble, []interfaces.LineageFieldMapping{
+				{SourceField: "@timestamp", TargetField: "@timestamp"},
+				{SourceField: "amount", TargetField: "amount"},
+				{SourceField: "host", TargetField: "host"},
+				{SourceField: "value", TargetField: "value"},
+			})
+		})
+
+		Convey("Derived metric depends on its base metric", func() {
+			model := &interfaces.MetricModel{
+				SimpleMetricModel: interfaces.SimpleMetricModel{
+					ModelID:    "m2",
+					MetricType: interfaces.DERIVED_METRIC,
+					FormulaConfig: &interfaces.DerivedConfig{
+						DependMetricModel: &interfaces.DependMetricModel{ID: "m1"},
+					},
+				},
+			}
+
+			edges := extractMetricModelLineage(model)
+			So(edgeSummary(edges), ShouldResemble, []string{"metric_model:m1->metric_model:m2"})
+		})
+
+		Convey("Composite metric depends on metrics in formula", func() {
+			model := &interfaces.MetricModel{
+				SimpleMetricModel: interfaces.SimpleMetricModel{
+					ModelID:    "m3",
+					MetricType: interfaces.COMPOSITED_METRIC,
+					Formula:    "{{m1}} / {{ m2 }} + {{m1}}",
+				},
+			}
+
+			edges := extractMetricModelLineage(model)
+			So(edgeSummary(edges), ShouldResemble, []string{
+				"metric_model:m1->metric_model:m3",
+				"metric_model:m2->metric_model:m3",
+			})
+		})
+	})
+}
+
+func Test_Lineage_ExtractObjectiveModelLineage(t *testing.T) {
+	Convey("Test extractObjectiveModelLineage", t, func() {
+		Convey("SLO objective", func() {
+			model := &interfaces.ObjectiveModel{
+				ObjectiveModelInfo: interfaces.ObjectiveModelInfo{
+					ModelID: "o1",
+					ObjectiveConfig: interfaces.SLOObjective{
+						GoodMetricModel:  &interfaces.BundleMetricModel{ID: "m1"},
+						TotalMetricModel: &interfaces.BundleMetricModel{ID: "m2"},
+					},
+				},
+			}
+
+			edges := extractObjectiveModelLineage(model)
+			So(edgeSummary(edges), ShouldResemble, []string{
+				"metric_model:m1->objective_model:o1",
+				"metric_model:m2->objective_model:o1",
+			})
+		})
+
+		Convey("KPI objective", func() {
+			model := &interfaces.ObjectiveModel{
+				ObjectiveModelInfo: interfaces.ObjectiveModelInfo{
+					ModelID: "o2",
+					ObjectiveConfig: interfaces.KPIObjective{
+						ComprehensiveMetricModels: []interfaces.ComprehensiveMetricModel{{ID: "m1"}},
+						AdditionalMetricModels:    []interfaces.BundleMetricModel{{ID: "m3"}},
+					},
+				},
+			}
+
+			edges := extractObjectiveModelLineage(model)
+			So(edgeSummary(edges), ShouldResemble, []string{
+				"metric_model:m1->objective_model:o2",
+				"metric_model:m3->objective_model:o2",
+			})
+		})
+	})
+}
+
+func Test_Lineage_ExtractEventModelLineage(t *testing.T) {
+	Convey("Test extractEventModelLineage", t, func() {
+		Convey("Event model on metric models", func() {
+			model := &interfaces.EventModel{
+				EventModelID:   "e1",
+				DataSourceType: interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_METRIC_MODEL,
+				DataSource:     []string{"m1", "m2"},
+			}
+
+			edges := extractEventModelLineage(model)
+			So(edgeSummary(edges), ShouldResemble, []string{
+				"metric_model:m1->event_model:e1",
+				"metric_model:m2->event_model:e1",
+			})
+		})
+
+		Convey("Event model with unknown data source type", func() {
+			model := &interfaces.EventModel{
+				EventModelID: "e1",
+				DataSource:   []string{"m1"},
+			}
+
+			edges := extractEventModelLineage(model)
+			So(len(edges), ShouldEqual, 0)
+		})
+	})
+}
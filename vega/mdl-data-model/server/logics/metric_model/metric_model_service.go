@@ -24,8 +24,11 @@ import (
 	"data-model/common"
 	derrors "data-model/errors"
 	"data-model/interfaces"
+	dtype "data-model/interfaces/data_type"
 	"data-model/logics"
 	"data-model/logics/data_view"
+	"data-model/logics/lineage"
+	"data-model/logics/model_revision"
 	"data-model/logics/permission"
 )
 
@@ -44,6 +47,9 @@ type metricModelService struct {
 	mma        interfaces.MetricModelAccess
 	mmga       interfaces.MetricModelGroupAccess
 	mmts       interfaces.MetricModelTaskService
+	ls         interfaces.LineageService
+	tma        interfaces.TraceModelAccess
+	mrs        interfaces.ModelRevisionService
 	ua         interfaces.UniqueryAccess
 }
 
@@ -58,6 +64,9 @@ func NewMetricModelService(appSetting *common.AppSetting) interfaces.MetricModel
 			mma:        logics.MMA,
 			mmga:       logics.MMGA,
 			mmts:       NewMetricModelTaskService(appSetting),
+			ls:         lineage.NewLineageService(appSetting),
+			tma:        logics.TMA,
+			mrs:        model_revision.NewModelRevisionService(appSetting),
 			ps:         permission.NewPermissionService(appSetting),
 			ua:         logics.UA,
 		}
@@ -371,6 +380,20 @@ func (mms *metricModelService) CreateMetricModels(ctx context.Context, metricMod
 				WithErrorDetails(err.Error())
 		}
 
+		// 登记模型的血缘
+		err = mms.ls.SaveMetricModelLineage(ctx, tx, []*interfaces.MetricModel{model})
+		if err != nil {
+			span.SetStatus(codes.Error, "登记指标模型血缘失败")
+			return nil, err
+		}
+
+		// 记录模型的初始版本
+		err = mms.mrs.SaveModelRevision(ctx, tx, interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, model.ModelID, model.ModelName, model)
+		if err != nil {
+			span.SetStatus(codes.Error, "记录指标模型版本失败")
+			return nil, err
+		}
+
 		// 2. 创建模型下的任务
 		if model.Task != nil {
 			// 写任务表
@@ -511,6 +534,21 @@ func (mms *metricModelService) UpdateMetricModel(ctx context.Context, tx *sql.Tx
 			WithErrorDetails(err.Error())
 	}
 
+	// 重新登记模型的血缘
+	err = mms.ls.SaveMetricModelLineage(updateCtx, tx, []*interfaces.MetricModel{&metricModel})
+	if err != nil {
+		updateSpan.SetStatus(codes.Error, "登记指标模型血缘失败")
+		return err
+	}
+
+	// 记录修改后的版本
+	err = mms.mrs.SaveModelRevision(updateCtx, tx, interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, metricModel.ModelID,
+		metricModel.ModelName, &metricModel)
+	if err != nil {
+		updateSpan.SetStatus(codes.Error, "记录指标模型版本失败")
+		return err
+	}
+
 	// 按模型id去获取任务，判断模型下是否存在任务
 	// 若提交的task为空，模型下存在任务，则是删除操作；若提交的task为空，模型下不存在任务，do nothing
 	// 若提交的task不为空，模型下存在任务，则是更新操作；若提交的task不为空，模型下不存在任务，则是创建任务的操作。
@@ -642,6 +680,9 @@ func (mms *metricModelService) UpdateMetricModel(ctx context.Context, tx *sql.Tx
 		}
 	}
 
+	// 清理 uniquery 中该模型的查询结果缓存。缓存按模型更新时间区分版本，清理失败不影响修改
+	mms.deleteUniqueryCache(ctx, []string{metricModel.ModelID})
+
 	updateSpan.SetStatus(codes.Ok, "")
 	return nil
 }
@@ -768,16 +809,41 @@ func (mms *metricModelService) DeleteMetricModels(ctx context.Context, tx *sql.T
 			WithErrorDetails(err.Error())
 	}
 
+	// 删除模型登记的血缘
+	err = mms.ls.DeleteModelLineage(ctx, tx, interfaces.LINEAGE_NODE_TYPE_METRIC_MODEL, modelIDs)
+	if err != nil {
+		span.SetStatus(codes.Error, "删除指标模型血缘失败")
+		return 0, err
+	}
+
+	// 删除模型的历史版本
+	err = mms.mrs.DeleteModelRevisions(ctx, tx, interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, modelIDs)
+	if err != nil {
+		span.SetStatus(codes.Error, "删除指标模型版本失败")
+		return 0, err
+	}
+
 	//  清除资源策略
 	err = mms.ps.DeleteResources(ctx, interfaces.RESOURCE_TYPE_METRIC_MODEL, modelIDs)
 	if err != nil {
 		return 0, err
 	}
 
+	// 清理 uniquery 中这些模型的查询结果缓存
+	mms.deleteUniqueryCache(ctx, modelIDs)
+
 	span.SetStatus(codes.Ok, "")
 	return rowsAffect, nil
 }
 
+// 清理 uniquery 中指标模型的查询结果缓存。
+// uniquery 按模型更新时间校验缓存版本，这里只是提前释放旧版本的缓存，失败时只记录日志
+func (mms *metricModelService) deleteUniqueryCache(ctx context.Context, modelIDs []string) {
+	if err := mms.ua.DeleteMetricModelCache(ctx, modelIDs); err != nil {
+		logger.Warnf("Delete result cache of metric models %v failed, %s", modelIDs, err.Error())
+	}
+}
+
 // 分页查询指标模型
 // func (mms *metricModelService) ListMetricModels(ctx context.Context,
 // 	parameter interfaces.MetricModelsQueryParams) ([]interfaces.MetricModel, int, error) {
@@ -982,6 +1048,21 @@ func (mms *metricModelService) GetMetricModels(ctx context.Context, modelIDs []s
 		}
 	}
 
+	// 指定了版本时用版本快照替换当前定义, 任务等附加信息仍取当前的. 依赖的模型取当前定义, 所以清除 context 中的版本
+	if revision, ok := ctx.Value(interfaces.MODEL_REVISION_KEY).(int); ok && revision > 0 {
+		ctx = context.WithValue(ctx, interfaces.MODEL_REVISION_KEY, 0)
+		if len(metricModels) == 1 {
+			modelRevision, err := mms.mrs.GetModelRevision(ctx, interfaces.REVISION_MODEL_TYPE_METRIC_MODEL,
+				metricModels[0].ModelID, revision)
+			if err != nil {
+				span.SetStatus(codes.Error, "Get metric model revision failed")
+				span.End()
+				return mmfilters, err
+			}
+			metricModels[0] = *modelRevision.Model.(*interfaces.MetricModel)
+		}
+	}
+
 	// 根据模型id获取任务信息
 	modelTaskMap, err := mms.mmts.GetMetricTasksByModelIDs(ctx, mIDs)
 	if err != nil {
@@ -1025,6 +1106,38 @@ func (mms *metricModelService) GetMetricModels(ctx context.Context, modelIDs []s
 		}
 		switch model.MetricType {
 		case interfaces.ATOMIC_METRIC:
+			if model.QueryType == interfaces.RED {
+				// RED指标的数据源是链路模型, 字段集为RED指标的序列标签
+				traceModelName, exist, err := mms.getTraceModelName(ctx, model.DataSource.ID)
+				if err != nil {
+					dataViewSpan.SetStatus(codes.Error, "获取链路模型失败")
+					dataViewSpan.End()
+					return mmfilters, err
+				}
+				if !exist {
+					// 查询时，如果链路模型已经被删除，则数据源名称为空
+					o11y.Warn(ctx, fmt.Sprintf("Trace model %s not found", model.DataSource.ID))
+				} else {
+					model.DataSource.Name = traceModelName
+				}
+
+				fieldsMap := map[string]interfaces.Field{}
+				for _, field := range redLabelFields() {
+					fieldsMap[field.Name] = interfaces.Field{
+						Name:        field.Name,
+						DisplayName: field.DisplayName,
+						Type:        field.Type,
+						Comment:     &field.Comment,
+					}
+				}
+				model.FieldsMap = fieldsMap
+
+				mmfilters = append(mmfilters, interfaces.MetricModelWithFilters{
+					MetricModel: model,
+				})
+				break
+			}
+
 			// 原子指标翻译数据源，衍生指标翻译依赖的指标模型
 			dsTypes = append(dsTypes, model.DataSource.Type)
 			dvIDs = append(dvIDs, model.DataSource.ID)
@@ -1787,6 +1900,12 @@ func (mms *metricModelService) GetMetricModelSourceFields(ctx context.Context, m
 		return []*interfaces.ViewField{}, rest.NewHTTPError(ctx, http.StatusNotFound, derrors.DataModel_MetricModel_MetricModelNotFound)
 	}
 
+	// RED指标的数据源是链路模型, 字段列表为RED指标的序列标签
+	if model.QueryType == interfaces.RED {
+		span.SetStatus(codes.Ok, "")
+		return redLabelFields(), nil
+	}
+
 	// 获取数据源（数据视图）字段列表
 	dataViewQueryFilters, err := mms.dvs.GetDataView(ctx, model.DataSource.ID)
 	if err != nil {
@@ -1869,6 +1988,20 @@ func (mms *metricModelService) GetMetricModelsDetailByGroupID(ctx context.Contex
 		// 导入的时候只认id，不认名称。
 		switch model.MetricType {
 		case interfaces.ATOMIC_METRIC:
+			if model.QueryType == interfaces.RED {
+				traceModelName, exist, err := mms.getTraceModelName(ctx, model.DataSource.ID)
+				if err != nil {
+					span.SetStatus(codes.Error, fmt.Sprintf("Get trace model[%s] error", model.DataSource.ID))
+					return nil, err
+				}
+				if !exist {
+					o11y.Warn(ctx, fmt.Sprintf("Trace model %s not found", model.DataSource.ID))
+				} else {
+					model.DataSource.Name = traceModelName
+				}
+				break
+			}
+
 			viewName, exist, err := mms.dvs.CheckDataViewExistByID(ctx, nil, model.DataSource.ID)
 			if err != nil {
 				logger.Errorf("GetDataViewByID error: %s", err.Error())
@@ -2231,6 +2364,11 @@ func (mms *metricModelService) ListMetricModelSrcs(ctx context.Context, paramete
 func (mms *metricModelService) checkDepends(ctx context.Context, metricModel *interfaces.MetricModel) error {
 	switch metricModel.MetricType {
 	case interfaces.ATOMIC_METRIC:
+		// RED指标的数据源是链路模型, 不依赖数据视图
+		if metricModel.QueryType == interfaces.RED {
+			return mms.checkTraceModel(ctx, metricModel)
+		}
+
 		// 原子指标时的校验
 		viewCtx, viewSpan := ar_trace.Tracer.Start(ctx, fmt.Sprintf("获取数据视图[%s]信息", metricModel.DataSource.ID))
 		viewSpan.SetAttributes(attr.Key("data_view_id").String(metricModel.DataSource.ID))
@@ -2318,6 +2456,55 @@ func (mms *metricModelService) checkDepends(ctx context.Context, metricModel *in
 	return nil
 }
 
+// 校验RED指标依赖的链路模型存在且有数据查询权限, 并回填数据源名称
+func (mms *metricModelService) checkTraceModel(ctx context.Context, metricModel *interfaces.MetricModel) error {
+	traceModelName, exist, err := mms.getTraceModelName(ctx, metricModel.DataSource.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return rest.NewHTTPError(ctx, http.StatusNotFound, derrors.DataModel_TraceModel_TraceModelNotFound).
+			WithErrorDetails(fmt.Sprintf("The trace model whose id equal to %v was not found", metricModel.DataSource.ID))
+	}
+
+	err = mms.ps.CheckPermission(ctx, interfaces.Resource{
+		Type: interfaces.RESOURCE_TYPE_TRACE_MODEL,
+		ID:   metricModel.DataSource.ID,
+	}, []string{interfaces.OPERATION_TYPE_DATA_QUERY})
+	if err != nil {
+		return err
+	}
+
+	metricModel.DataSource.Type = interfaces.DATA_SOURCE_TRACE_MODEL
+	metricModel.DataSource.Name = traceModelName
+	return nil
+}
+
+// 按id获取链路模型名称
+func (mms *metricModelService) getTraceModelName(ctx context.Context, modelID string) (string, bool, error) {
+	modelMap, err := mms.tma.GetSimpleTraceModelMapByIDs(ctx, []string{modelID})
+	if err != nil {
+		logger.Errorf("GetSimpleTraceModelMapByIDs error: %s", err.Error())
+		return "", false, rest.NewHTTPError(ctx, http.StatusInternalServerError, derrors.DataModel_MetricModel_InternalError).
+			WithErrorDetails(err.Error())
+	}
+	traceModel, exist := modelMap[modelID]
+	return traceModel.Name, exist, nil
+}
+
+// RED指标的序列标签, 与 uniquery 返回的RED指标的标签一致
+func redLabelFields() []*interfaces.ViewField {
+	fields := make([]*interfaces.ViewField, 0, 3)
+	for _, name := range []string{"service_name", "operation", "metric"} {
+		fields = append(fields, &interfaces.ViewField{
+			Name:        name,
+			Type:        dtype.DataType_String,
+			DisplayName: name,
+		})
+	}
+	return fields
+}
+
 func (mms *metricModelService) validDerivedMetricModel(ctx context.Context, metricModel *interfaces.MetricModel) error {
 	// 衍生指标校验依赖的原子指标的存在性
 	dependModelMap, orderByFieldMap, err := mms.getOrderByFields(ctx, *metricModel)
@@ -2566,6 +2753,15 @@ func (mms *metricModelService) getMetricViewFieldsAndGroupFields(ctx context.Con
 // 原子指标：视图的字段集
 func (mms *metricModelService) getAtomicViewFieldsAndGroupFields(ctx context.Context,
 	model interfaces.MetricModel) (map[string]*interfaces.ViewField, map[string]interfaces.Field, error) {
+	// RED指标没有绑定视图, 原始字段集为RED指标的序列标签
+	if model.QueryType == interfaces.RED {
+		viewFields := map[string]*interfaces.ViewField{}
+		for _, field := range redLabelFields() {
+			viewFields[field.Name] = field
+		}
+		return viewFields, map[string]interfaces.Field{}, nil
+	}
+
 	// 获取视图信息
 	dataView, err := mms.dvs.GetDataView(ctx, model.DataSource.ID)
 	if err != nil {
@@ -524,6 +524,13 @@ func Test_MetricModelService_UpdateMetricModel(t *testing.T) {
 		iba := dmock.NewMockIndexBaseAccess(mockCtrl)
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		mms, smock := MockNewMetricModelService(appSetting, dmja, dvs, mma, mmga, ua, mmts, iba, ps)
+		ls := dmock.NewMockLineageService(mockCtrl)
+		mms.ls = ls
+		ls.EXPECT().SaveMetricModelLineage(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mrs := dmock.NewMockModelRevisionService(mockCtrl)
+		mms.mrs = mrs
+		mrs.EXPECT().SaveModelRevision(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		ua.EXPECT().DeleteMetricModelCache(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 		metricModel := interfaces.MetricModel{
 			SimpleMetricModel: interfaces.SimpleMetricModel{
@@ -1129,6 +1136,13 @@ func Test_MetricModelService_DeleteMetricModels(t *testing.T) {
 		iba := dmock.NewMockIndexBaseAccess(mockCtrl)
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		mms, smock := MockNewMetricModelService(appSetting, dmja, dvs, mma, mmga, ua, mmts, iba, ps)
+		ls := dmock.NewMockLineageService(mockCtrl)
+		mms.ls = ls
+		ls.EXPECT().DeleteModelLineage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mrs := dmock.NewMockModelRevisionService(mockCtrl)
+		mms.mrs = mrs
+		mrs.EXPECT().DeleteModelRevisions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		ua.EXPECT().DeleteMetricModelCache(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		resrc := map[string]interfaces.ResourceOps{
 			"0": {
 				ResourceID: "0",
@@ -2169,3 +2183,142 @@ func Test_MetricModelService_CheckVegaLogicView(t *testing.T) {
 	// 	})
 	// })
 }
+
+func Test_MetricModelService_CheckTraceModel(t *testing.T) {
+	Convey("Test checkTraceModel", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		dmja := dmock.NewMockDataModelJobAccess(mockCtrl)
+		dvs := dmock.NewMockDataViewService(mockCtrl)
+		mma := dmock.NewMockMetricModelAccess(mockCtrl)
+		mmga := dmock.NewMockMetricModelGroupAccess(mockCtrl)
+		ua := dmock.NewMockUniqueryAccess(mockCtrl)
+		mmts := dmock.NewMockMetricModelTaskService(mockCtrl)
+		iba := dmock.NewMockIndexBaseAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		tma := dmock.NewMockTraceModelAccess(mockCtrl)
+		mms, _ := MockNewMetricModelService(appSetting, dmja, dvs, mma, mmga, ua, mmts, iba, ps)
+		mms.tma = tma
+
+		metricModel := interfaces.MetricModel{
+			SimpleMetricModel: interfaces.SimpleMetricModel{
+				ModelName:     "red",
+				MetricType:    interfaces.ATOMIC_METRIC,
+				QueryType:     interfaces.RED,
+				FormulaConfig: interfaces.REDConfig{Metric: "rate"},
+			},
+			DataSource: &interfaces.MetricDataSource{
+				ID: "tm1",
+			},
+		}
+
+		Convey("Get trace model failed", func() {
+			tma.EXPECT().GetSimpleTraceModelMapByIDs(gomock.Any(), []string{"tm1"}).Return(nil, errors.New("error"))
+
+			err := mms.checkDepends(testCtx, &metricModel)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusInternalServerError)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_MetricModel_InternalError)
+		})
+
+		Convey("Trace model not found", func() {
+			tma.EXPECT().GetSimpleTraceModelMapByIDs(gomock.Any(), []string{"tm1"}).Return(map[string]interfaces.TraceModel{}, nil)
+
+			err := mms.checkDepends(testCtx, &metricModel)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_TraceModel_TraceModelNotFound)
+		})
+
+		Convey("No data query permission on trace model", func() {
+			tma.EXPECT().GetSimpleTraceModelMapByIDs(gomock.Any(), []string{"tm1"}).
+				Return(map[string]interfaces.TraceModel{"tm1": {ID: "tm1", Name: "trace"}}, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), interfaces.Resource{
+				Type: interfaces.RESOURCE_TYPE_TRACE_MODEL,
+				ID:   "tm1",
+			}, []string{interfaces.OPERATION_TYPE_DATA_QUERY}).
+				Return(rest.NewHTTPError(testCtx, http.StatusForbidden, rest.PublicError_Forbidden))
+
+			err := mms.checkDepends(testCtx, &metricModel)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Success without data view", func() {
+			tma.EXPECT().GetSimpleTraceModelMapByIDs(gomock.Any(), []string{"tm1"}).
+				Return(map[string]interfaces.TraceModel{"tm1": {ID: "tm1", Name: "trace"}}, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+			err := mms.checkDepends(testCtx, &metricModel)
+			So(err, ShouldBeNil)
+			So(metricModel.DataSource.Type, ShouldEqual, interfaces.DATA_SOURCE_TRACE_MODEL)
+			So(metricModel.DataSource.Name, ShouldEqual, "trace")
+		})
+	})
+}
+
+func Test_MetricModelService_GetREDMetricModels(t *testing.T) {
+	Convey("Test GetMetricModels with red metric model", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		dmja := dmock.NewMockDataModelJobAccess(mockCtrl)
+		dvs := dmock.NewMockDataViewService(mockCtrl)
+		mma := dmock.NewMockMetricModelAccess(mockCtrl)
+		mmga := dmock.NewMockMetricModelGroupAccess(mockCtrl)
+		ua := dmock.NewMockUniqueryAccess(mockCtrl)
+		mmts := dmock.NewMockMetricModelTaskService(mockCtrl)
+		iba := dmock.NewMockIndexBaseAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		tma := dmock.NewMockTraceModelAccess(mockCtrl)
+		mms, _ := MockNewMetricModelService(appSetting, dmja, dvs, mma, mmga, ua, mmts, iba, ps)
+		mms.tma = tma
+
+		metricModel := interfaces.MetricModel{
+			SimpleMetricModel: interfaces.SimpleMetricModel{
+				ModelID:       "0",
+				ModelName:     "red",
+				MetricType:    interfaces.ATOMIC_METRIC,
+				QueryType:     interfaces.RED,
+				FormulaConfig: interfaces.REDConfig{Metric: "duration_p95"},
+			},
+			DataSource: &interfaces.MetricDataSource{
+				Type: interfaces.DATA_SOURCE_TRACE_MODEL,
+				ID:   "tm1",
+			},
+		}
+		resrc := map[string]interfaces.ResourceOps{
+			"0": {
+				ResourceID: "0",
+			},
+		}
+
+		Convey("Data view is not requested", func() {
+			ps.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(resrc, nil)
+			mma.EXPECT().GetMetricModelsByModelIDs(gomock.Any(), gomock.Any()).Return([]interfaces.MetricModel{metricModel}, nil)
+			mmts.EXPECT().GetMetricTasksByModelIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
+			tma.EXPECT().GetSimpleTraceModelMapByIDs(gomock.Any(), []string{"tm1"}).
+				Return(map[string]interfaces.TraceModel{"tm1": {ID: "tm1", Name: "trace"}}, nil)
+
+			models, err := mms.GetMetricModels(testCtx, []string{"0"}, true)
+			So(err, ShouldBeNil)
+			So(len(models), ShouldEqual, 1)
+			So(models[0].DataSource.Name, ShouldEqual, "trace")
+			So(models[0].DataView, ShouldBeNil)
+			So(len(models[0].FieldsMap), ShouldEqual, 3)
+			So(models[0].FieldsMap["service_name"].Type, ShouldEqual, dtype.DataType_String)
+		})
+
+		Convey("Get trace model failed", func() {
+			ps.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(resrc, nil)
+			mma.EXPECT().GetMetricModelsByModelIDs(gomock.Any(), gomock.Any()).Return([]interfaces.MetricModel{metricModel}, nil)
+			mmts.EXPECT().GetMetricTasksByModelIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
+			tma.EXPECT().GetSimpleTraceModelMapByIDs(gomock.Any(), []string{"tm1"}).Return(nil, errors.New("error"))
+
+			_, err := mms.GetMetricModels(testCtx, []string{"0"}, false)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
@@ -10,9 +10,12 @@ import (
 	"database/sql"
 	"sync"
 
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
 	"data-model/common"
 	"data-model/interfaces"
 	"data-model/logics"
+	"data-model/logics/permission"
 )
 
 var (
@@ -23,6 +26,8 @@ var (
 type metricModelTaskService struct {
 	appSetting *common.AppSetting
 	mmta       interfaces.MetricModelTaskAccess
+	mtba       interfaces.MetricTaskBackfillAccess
+	ps         interfaces.PermissionService
 }
 
 func NewMetricModelTaskService(appSetting *common.AppSetting) interfaces.MetricModelTaskService {
@@ -30,6 +35,8 @@ func NewMetricModelTaskService(appSetting *common.AppSetting) interfaces.MetricM
 		mmtService = &metricModelTaskService{
 			appSetting: appSetting,
 			mmta:       logics.MMTA,
+			mtba:       logics.MTBA,
+			ps:         permission.NewPermissionService(appSetting),
 		}
 	})
 	return mmtService
@@ -62,7 +69,22 @@ func (mmts *metricModelTaskService) UpdateMetricTask(ctx context.Context, tx *sq
 
 // 按任务id批量获取任务信息
 func (mmts *metricModelTaskService) GetMetricTasksByTaskIDs(ctx context.Context, taskIDs []string) ([]interfaces.MetricTask, error) {
-	return mmts.mmta.GetMetricTasksByTaskIDs(ctx, taskIDs)
+	tasks, err := mmts.mmta.GetMetricTasksByTaskIDs(ctx, taskIDs)
+	if err != nil || len(tasks) == 0 {
+		return tasks, err
+	}
+
+	// 补充运行任务的副本, 查询失败不影响任务信息的返回
+	owners, err := mmts.mmta.GetMetricTaskOwners(ctx, taskIDs)
+	if err != nil {
+		logger.Warnf("Get owners of metric tasks %v failed, %v", taskIDs, err)
+		return tasks, nil
+	}
+	for i := range tasks {
+		tasks[i].Owner = owners[tasks[i].TaskID]
+	}
+
+	return tasks, nil
 }
 
 // 按模型id批量获取任务信息
@@ -6,6 +6,7 @@
 package metric_model
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -118,11 +119,39 @@ func Test_MetricTaskService_GetMetricTasksByTaskIDs(t *testing.T) {
 
 		Convey("GetMetricTasksByTaskIDs success", func() {
 			mmta.EXPECT().GetMetricTasksByTaskIDs(gomock.Any(), gomock.Any()).Return([]interfaces.MetricTask{task}, nil)
+			mmta.EXPECT().GetMetricTaskOwners(gomock.Any(), gomock.Any()).Return(map[string]string{}, nil)
 
 			actualTask, httpErr := mmts.GetMetricTasksByTaskIDs(testCtx, []string{"1"})
 			So(actualTask, ShouldResemble, []interfaces.MetricTask{task})
 			So(httpErr, ShouldBeNil)
 		})
+
+		Convey("GetMetricTasksByTaskIDs success with owner", func() {
+			mmta.EXPECT().GetMetricTasksByTaskIDs(gomock.Any(), gomock.Any()).Return([]interfaces.MetricTask{task}, nil)
+			mmta.EXPECT().GetMetricTaskOwners(gomock.Any(), gomock.Any()).
+				Return(map[string]string{task.TaskID: "data-model-job-0"}, nil)
+
+			actualTask, httpErr := mmts.GetMetricTasksByTaskIDs(testCtx, []string{task.TaskID})
+			So(httpErr, ShouldBeNil)
+			So(len(actualTask), ShouldEqual, 1)
+			So(actualTask[0].Owner, ShouldEqual, "data-model-job-0")
+		})
+
+		Convey("GetMetricTasksByTaskIDs success when get owners failed", func() {
+			mmta.EXPECT().GetMetricTasksByTaskIDs(gomock.Any(), gomock.Any()).Return([]interfaces.MetricTask{task}, nil)
+			mmta.EXPECT().GetMetricTaskOwners(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+			actualTask, httpErr := mmts.GetMetricTasksByTaskIDs(testCtx, []string{"1"})
+			So(actualTask, ShouldResemble, []interfaces.MetricTask{task})
+			So(httpErr, ShouldBeNil)
+		})
+
+		Convey("GetMetricTasksByTaskIDs failed", func() {
+			mmta.EXPECT().GetMetricTasksByTaskIDs(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+			_, httpErr := mmts.GetMetricTasksByTaskIDs(testCtx, []string{"1"})
+			So(httpErr, ShouldNotBeNil)
+		})
 	})
 }
 
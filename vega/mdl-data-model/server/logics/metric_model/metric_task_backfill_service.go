@@ -0,0 +1,201 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package metric_model
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"github.com/rs/xid"
+
+	derrors "data-model/errors"
+	"data-model/interfaces"
+)
+
+// 创建补数任务。补数任务由 data-model-job 按时间范围分批重新计算模型，并覆盖持久化任务已写入索引库的数据
+func (mmts *metricModelTaskService) CreateMetricTaskBackfill(ctx context.Context,
+	backfill interfaces.MetricTaskBackfill) (string, error) {
+
+	tasks, err := mmts.mmta.GetMetricTasksByModelIDs(ctx, []string{backfill.ModelID})
+	if err != nil {
+		return "", rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_MetricModel_InternalError_GetMetricTasksByModelIDsFailed).WithErrorDetails(err.Error())
+	}
+	task, ok := tasks[backfill.ModelID]
+	if !ok {
+		return "", rest.NewHTTPError(ctx, http.StatusNotFound, derrors.DataModel_MetricModel_MetricTaskNotFound).
+			WithErrorDetails(fmt.Sprintf("The model[%s] has no metric task", backfill.ModelID))
+	}
+
+	// 补数会覆盖已持久化的数据，需要有模型的修改权限
+	err = mmts.checkBackfillPermission(ctx, task.ModuleType, task.ModelID, interfaces.OPERATION_TYPE_MODIFY)
+	if err != nil {
+		return "", err
+	}
+
+	// 步长不填时补全部步长，填写时必须是持久化任务的步长
+	if len(backfill.Steps) == 0 {
+		backfill.Steps = task.Steps
+	}
+	taskSteps := make(map[string]bool, len(task.Steps))
+	for _, step := range task.Steps {
+		taskSteps[step] = true
+	}
+	for _, step := range backfill.Steps {
+		if !taskSteps[step] {
+			return "", rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter_Backfill).
+				WithErrorDetails(fmt.Sprintf("The step[%s] is not one of the metric task steps %v", step, task.Steps))
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	if backfill.End > now {
+		return "", rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter_Backfill).
+			WithErrorDetails("The end of backfill can not be later than now")
+	}
+
+	// 计划时间按最小步长对齐，与持久化任务的计划时间保持一致
+	checkpoint := backfill.Start - backfill.Start%interfaces.METRIC_TASK_MIN_STEP
+	totalPoints := (backfill.End-checkpoint)/interfaces.METRIC_TASK_MIN_STEP + 1
+	if float64(totalPoints) > interfaces.MAX_RETRACE_POINTS_NUM {
+		return "", rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_InvalidParameter_Backfill).
+			WithErrorDetails(fmt.Sprintf("The backfill data point can not exceed %v, actual is [%d]",
+				interfaces.MAX_RETRACE_POINTS_NUM, totalPoints))
+	}
+
+	// 同一个模型同时只允许一个未结束的补数任务，避免重复覆盖
+	backfills, err := mmts.mtba.ListMetricTaskBackfills(ctx, interfaces.MetricTaskBackfillQueryParams{
+		ModelID: backfill.ModelID,
+	})
+	if err != nil {
+		return "", rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_MetricModel_InternalError_ListBackfillsFailed).WithErrorDetails(err.Error())
+	}
+	for _, bf := range backfills {
+		if bf.Status == interfaces.BACKFILL_STATUS_PENDING || bf.Status == interfaces.BACKFILL_STATUS_RUNNING {
+			return "", rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_BackfillExisted).
+				WithErrorDetails(fmt.Sprintf("The backfill[%s] of model[%s] is %s", bf.ID, bf.ModelID, bf.Status))
+		}
+	}
+
+	accountInfo := interfaces.AccountInfo{}
+	if ctx.Value(interfaces.ACCOUNT_INFO_KEY) != nil {
+		accountInfo = ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo)
+	}
+
+	backfill.ID = xid.New().String()
+	backfill.TaskID = task.TaskID
+	backfill.ModuleType = task.ModuleType
+	backfill.Status = interfaces.BACKFILL_STATUS_PENDING
+	backfill.Checkpoint = checkpoint
+	backfill.TotalPoints = totalPoints
+	backfill.DonePoints = 0
+	backfill.CreateTime = now
+	backfill.UpdateTime = now
+	backfill.Creator = accountInfo
+
+	err = mmts.mtba.CreateMetricTaskBackfill(ctx, backfill)
+	if err != nil {
+		logger.Errorf("Create metric task backfill error: %s", err.Error())
+		return "", rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_MetricModel_InternalError_CreateBackfillFailed).WithErrorDetails(err.Error())
+	}
+
+	return backfill.ID, nil
+}
+
+// 按 id 获取补数任务
+func (mmts *metricModelTaskService) GetMetricTaskBackfill(ctx context.Context,
+	backfillID string) (interfaces.MetricTaskBackfill, error) {
+
+	backfill, exist, err := mmts.mtba.GetMetricTaskBackfill(ctx, backfillID)
+	if err != nil {
+		return backfill, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_MetricModel_InternalError_GetBackfillFailed).WithErrorDetails(err.Error())
+	}
+	if !exist {
+		return backfill, rest.NewHTTPError(ctx, http.StatusNotFound, derrors.DataModel_MetricModel_BackfillNotFound).
+			WithErrorDetails(fmt.Sprintf("Backfill[%s] not found", backfillID))
+	}
+
+	err = mmts.checkBackfillPermission(ctx, backfill.ModuleType, backfill.ModelID, interfaces.OPERATION_TYPE_VIEW_DETAIL)
+	if err != nil {
+		return interfaces.MetricTaskBackfill{}, err
+	}
+	return backfill, nil
+}
+
+// 获取补数任务列表，只返回有查看权限的模型的补数任务
+func (mmts *metricModelTaskService) ListMetricTaskBackfills(ctx context.Context,
+	params interfaces.MetricTaskBackfillQueryParams) ([]interfaces.MetricTaskBackfill, error) {
+
+	backfills, err := mmts.mtba.ListMetricTaskBackfills(ctx, params)
+	if err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_MetricModel_InternalError_ListBackfillsFailed).WithErrorDetails(err.Error())
+	}
+
+	entries := make([]interfaces.MetricTaskBackfill, 0, len(backfills))
+	for _, backfill := range backfills {
+		err = mmts.checkBackfillPermission(ctx, backfill.ModuleType, backfill.ModelID, interfaces.OPERATION_TYPE_VIEW_DETAIL)
+		if err != nil {
+			httpErr, ok := err.(*rest.HTTPError)
+			if ok && httpErr.HTTPCode == http.StatusForbidden {
+				continue
+			}
+			return nil, err
+		}
+		entries = append(entries, backfill)
+	}
+	return entries, nil
+}
+
+// 取消补数任务，已结束的补数任务不能取消
+func (mmts *metricModelTaskService) CancelMetricTaskBackfill(ctx context.Context, backfillID string) error {
+	backfill, exist, err := mmts.mtba.GetMetricTaskBackfill(ctx, backfillID)
+	if err != nil {
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_MetricModel_InternalError_GetBackfillFailed).WithErrorDetails(err.Error())
+	}
+	if !exist {
+		return rest.NewHTTPError(ctx, http.StatusNotFound, derrors.DataModel_MetricModel_BackfillNotFound).
+			WithErrorDetails(fmt.Sprintf("Backfill[%s] not found", backfillID))
+	}
+
+	err = mmts.checkBackfillPermission(ctx, backfill.ModuleType, backfill.ModelID, interfaces.OPERATION_TYPE_MODIFY)
+	if err != nil {
+		return err
+	}
+
+	canceled, err := mmts.mtba.CancelMetricTaskBackfill(ctx, backfillID, time.Now().UnixMilli())
+	if err != nil {
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_MetricModel_InternalError_CancelBackfillFailed).WithErrorDetails(err.Error())
+	}
+	if !canceled {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_MetricModel_BackfillFinished).
+			WithErrorDetails(fmt.Sprintf("Backfill[%s] is already finished", backfillID))
+	}
+	return nil
+}
+
+// 补数任务的权限跟随其所属的模型，目标模型的任务按目标模型校验
+func (mmts *metricModelTaskService) checkBackfillPermission(ctx context.Context, moduleType string,
+	modelID string, op string) error {
+
+	resourceType := interfaces.RESOURCE_TYPE_METRIC_MODEL
+	if moduleType == interfaces.MODULE_TYPE_OBJECTIVE_MODEL {
+		resourceType = interfaces.RESOURCE_TYPE_OBJECTIVE_MODEL
+	}
+	return mmts.ps.CheckPermission(ctx, interfaces.Resource{
+		Type: resourceType,
+		ID:   modelID,
+	}, []string{op})
+}
@@ -0,0 +1,291 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package metric_model
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+	dmock "data-model/interfaces/mock"
+)
+
+func mockNewBackfillService(mockCtrl *gomock.Controller) (*metricModelTaskService,
+	*dmock.MockMetricModelTaskAccess, *dmock.MockMetricTaskBackfillAccess, *dmock.MockPermissionService) {
+
+	mmta := dmock.NewMockMetricModelTaskAccess(mockCtrl)
+	mtba := dmock.NewMockMetricTaskBackfillAccess(mockCtrl)
+	ps := dmock.NewMockPermissionService(mockCtrl)
+
+	mmts := MockNewMetricModelTaskService(&common.AppSetting{}, mmta)
+	mmts.mtba = mtba
+	mmts.ps = ps
+	return mmts, mmta, mtba, ps
+}
+
+func Test_MetricTaskService_CreateMetricTaskBackfill(t *testing.T) {
+	Convey("Test CreateMetricTaskBackfill", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mmts, mmta, mtba, ps := mockNewBackfillService(mockCtrl)
+
+		ctx := context.WithValue(testCtx, interfaces.ACCOUNT_INFO_KEY,
+			interfaces.AccountInfo{ID: "u1", Type: "user"})
+		end := time.Now().Add(-time.Hour).UnixMilli()
+		backfill := interfaces.MetricTaskBackfill{
+			ModelID: "1",
+			Start:   end - 24*time.Hour.Milliseconds(),
+			End:     end,
+		}
+		backfillTask := interfaces.MetricTask{
+			TaskID:     "1",
+			ModuleType: interfaces.MODULE_TYPE_METRIC_MODEL,
+			ModelID:    "1",
+			Steps:      []string{"5m", "1h"},
+			IndexBase:  "base1",
+		}
+		tasks := map[string]interfaces.MetricTask{"1": backfillTask}
+
+		Convey("Create failed, caused by get task error", func() {
+			mmta.EXPECT().GetMetricTasksByModelIDs(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+			_, err := mmts.CreateMetricTaskBackfill(ctx, backfill)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual,
+				derrors.DataModel_MetricModel_InternalError_GetMetricTasksByModelIDsFailed)
+		})
+
+		Convey("Create failed, model has no task", func() {
+			mmta.EXPECT().GetMetricTasksByModelIDs(gomock.Any(), gomock.Any()).
+				Return(map[string]interfaces.MetricTask{}, nil)
+
+			_, err := mmts.CreateMetricTaskBackfill(ctx, backfill)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Create failed, no modify permission", func() {
+			mmta.EXPECT().GetMetricTasksByModelIDs(gomock.Any(), gomock.Any()).Return(tasks, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), interfaces.Resource{
+				Type: interfaces.RESOURCE_TYPE_METRIC_MODEL,
+				ID:   "1",
+			}, []string{interfaces.OPERATION_TYPE_MODIFY}).
+				Return(rest.NewHTTPError(ctx, http.StatusForbidden, rest.PublicError_Forbidden))
+
+			_, err := mmts.CreateMetricTaskBackfill(ctx, backfill)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Create failed, step is not one of the task steps", func() {
+			mmta.EXPECT().GetMetricTasksByModelIDs(gomock.Any(), gomock.Any()).Return(tasks, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+			backfill.Steps = []string{"1d"}
+			_, err := mmts.CreateMetricTaskBackfill(ctx, backfill)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual,
+				derrors.DataModel_MetricModel_InvalidParameter_Backfill)
+		})
+
+		Convey("Create failed, end is later than now", func() {
+			mmta.EXPECT().GetMetricTasksByModelIDs(gomock.Any(), gomock.Any()).Return(tasks, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+			backfill.End = time.Now().Add(time.Hour).UnixMilli()
+			_, err := mmts.CreateMetricTaskBackfill(ctx, backfill)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual,
+				derrors.DataModel_MetricModel_InvalidParameter_Backfill)
+		})
+
+		Convey("Create failed, too many data points", func() {
+			mmta.EXPECT().GetMetricTasksByModelIDs(gomock.Any(), gomock.Any()).Return(tasks, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+			backfill.Start = end - 60*24*time.Hour.Milliseconds()
+			_, err := mmts.CreateMetricTaskBackfill(ctx, backfill)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual,
+				derrors.DataModel_MetricModel_InvalidParameter_Backfill)
+		})
+
+		Convey("Create failed, an unfinished backfill existed", func() {
+			mmta.EXPECT().GetMetricTasksByModelIDs(gomock.Any(), gomock.Any()).Return(tasks, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mtba.EXPECT().ListMetricTaskBackfills(gomock.Any(), gomock.Any()).Return([]interfaces.MetricTaskBackfill{
+				{ID: "b0", ModelID: "1", Status: interfaces.BACKFILL_STATUS_SUCCEEDED},
+				{ID: "b1", ModelID: "1", Status: interfaces.BACKFILL_STATUS_RUNNING},
+			}, nil)
+
+			_, err := mmts.CreateMetricTaskBackfill(ctx, backfill)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_MetricModel_BackfillExisted)
+		})
+
+		Convey("Create failed, caused by insert error", func() {
+			mmta.EXPECT().GetMetricTasksByModelIDs(gomock.Any(), gomock.Any()).Return(tasks, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mtba.EXPECT().ListMetricTaskBackfills(gomock.Any(), gomock.Any()).Return(nil, nil)
+			mtba.EXPECT().CreateMetricTaskBackfill(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+
+			_, err := mmts.CreateMetricTaskBackfill(ctx, backfill)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual,
+				derrors.DataModel_MetricModel_InternalError_CreateBackfillFailed)
+		})
+
+		Convey("Create succeed, steps default to the task steps", func() {
+			mmta.EXPECT().GetMetricTasksByModelIDs(gomock.Any(), gomock.Any()).Return(tasks, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mtba.EXPECT().ListMetricTaskBackfills(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+			var created interfaces.MetricTaskBackfill
+			mtba.EXPECT().CreateMetricTaskBackfill(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, backfill interfaces.MetricTaskBackfill) error {
+					created = backfill
+					return nil
+				})
+
+			id, err := mmts.CreateMetricTaskBackfill(ctx, backfill)
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, created.ID)
+			So(created.TaskID, ShouldEqual, backfillTask.TaskID)
+			So(created.Steps, ShouldResemble, backfillTask.Steps)
+			So(created.Status, ShouldEqual, interfaces.BACKFILL_STATUS_PENDING)
+			So(created.Checkpoint%interfaces.METRIC_TASK_MIN_STEP, ShouldEqual, 0)
+			So(created.Checkpoint, ShouldBeLessThanOrEqualTo, backfill.Start)
+			So(created.TotalPoints, ShouldEqual, (backfill.End-created.Checkpoint)/interfaces.METRIC_TASK_MIN_STEP+1)
+			So(created.Creator, ShouldResemble, interfaces.AccountInfo{ID: "u1", Type: "user"})
+		})
+	})
+}
+
+func Test_MetricTaskService_GetMetricTaskBackfill(t *testing.T) {
+	Convey("Test GetMetricTaskBackfill", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mmts, _, mtba, ps := mockNewBackfillService(mockCtrl)
+		backfill := interfaces.MetricTaskBackfill{ID: "b1", ModelID: "1", ModuleType: interfaces.MODULE_TYPE_OBJECTIVE_MODEL}
+
+		Convey("Get failed, caused by access error", func() {
+			mtba.EXPECT().GetMetricTaskBackfill(gomock.Any(), "b1").Return(backfill, false, errors.New("error"))
+
+			_, err := mmts.GetMetricTaskBackfill(testCtx, "b1")
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual,
+				derrors.DataModel_MetricModel_InternalError_GetBackfillFailed)
+		})
+
+		Convey("Get failed, backfill not found", func() {
+			mtba.EXPECT().GetMetricTaskBackfill(gomock.Any(), "b1").Return(interfaces.MetricTaskBackfill{}, false, nil)
+
+			_, err := mmts.GetMetricTaskBackfill(testCtx, "b1")
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_MetricModel_BackfillNotFound)
+		})
+
+		Convey("Get succeed, check permission of objective model", func() {
+			mtba.EXPECT().GetMetricTaskBackfill(gomock.Any(), "b1").Return(backfill, true, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), interfaces.Resource{
+				Type: interfaces.RESOURCE_TYPE_OBJECTIVE_MODEL,
+				ID:   "1",
+			}, []string{interfaces.OPERATION_TYPE_VIEW_DETAIL}).Return(nil)
+
+			result, err := mmts.GetMetricTaskBackfill(testCtx, "b1")
+			So(err, ShouldBeNil)
+			So(result, ShouldResemble, backfill)
+		})
+	})
+}
+
+func Test_MetricTaskService_ListMetricTaskBackfills(t *testing.T) {
+	Convey("Test ListMetricTaskBackfills", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mmts, _, mtba, ps := mockNewBackfillService(mockCtrl)
+		backfills := []interfaces.MetricTaskBackfill{
+			{ID: "b1", ModelID: "1", ModuleType: interfaces.MODULE_TYPE_METRIC_MODEL},
+			{ID: "b2", ModelID: "2", ModuleType: interfaces.MODULE_TYPE_METRIC_MODEL},
+		}
+
+		Convey("List failed, caused by access error", func() {
+			mtba.EXPECT().ListMetricTaskBackfills(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+			_, err := mmts.ListMetricTaskBackfills(testCtx, interfaces.MetricTaskBackfillQueryParams{})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual,
+				derrors.DataModel_MetricModel_InternalError_ListBackfillsFailed)
+		})
+
+		Convey("List failed, caused by check permission error", func() {
+			mtba.EXPECT().ListMetricTaskBackfills(gomock.Any(), gomock.Any()).Return(backfills, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(rest.NewHTTPError(testCtx, http.StatusInternalServerError, derrors.DataModel_InternalError_CheckPermissionFailed))
+
+			_, err := mmts.ListMetricTaskBackfills(testCtx, interfaces.MetricTaskBackfillQueryParams{})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("List succeed, skip backfills without permission", func() {
+			mtba.EXPECT().ListMetricTaskBackfills(gomock.Any(), gomock.Any()).Return(backfills, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), interfaces.Resource{Type: interfaces.RESOURCE_TYPE_METRIC_MODEL, ID: "1"},
+				gomock.Any()).Return(nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), interfaces.Resource{Type: interfaces.RESOURCE_TYPE_METRIC_MODEL, ID: "2"},
+				gomock.Any()).Return(rest.NewHTTPError(testCtx, http.StatusForbidden, rest.PublicError_Forbidden))
+
+			entries, err := mmts.ListMetricTaskBackfills(testCtx, interfaces.MetricTaskBackfillQueryParams{})
+			So(err, ShouldBeNil)
+			So(entries, ShouldResemble, backfills[:1])
+		})
+	})
+}
+
+func Test_MetricTaskService_CancelMetricTaskBackfill(t *testing.T) {
+	Convey("Test CancelMetricTaskBackfill", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mmts, _, mtba, ps := mockNewBackfillService(mockCtrl)
+		backfill := interfaces.MetricTaskBackfill{ID: "b1", ModelID: "1", ModuleType: interfaces.MODULE_TYPE_METRIC_MODEL}
+
+		Convey("Cancel failed, backfill not found", func() {
+			mtba.EXPECT().GetMetricTaskBackfill(gomock.Any(), "b1").Return(interfaces.MetricTaskBackfill{}, false, nil)
+
+			err := mmts.CancelMetricTaskBackfill(testCtx, "b1")
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_MetricModel_BackfillNotFound)
+		})
+
+		Convey("Cancel failed, caused by update error", func() {
+			mtba.EXPECT().GetMetricTaskBackfill(gomock.Any(), "b1").Return(backfill, true, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), []string{interfaces.OPERATION_TYPE_MODIFY}).Return(nil)
+			mtba.EXPECT().CancelMetricTaskBackfill(gomock.Any(), "b1", gomock.Any()).Return(false, errors.New("error"))
+
+			err := mmts.CancelMetricTaskBackfill(testCtx, "b1")
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual,
+				derrors.DataModel_MetricModel_InternalError_CancelBackfillFailed)
+		})
+
+		Convey("Cancel failed, backfill already finished", func() {
+			mtba.EXPECT().GetMetricTaskBackfill(gomock.Any(), "b1").Return(backfill, true, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mtba.EXPECT().CancelMetricTaskBackfill(gomock.Any(), "b1", gomock.Any()).Return(false, nil)
+
+			err := mmts.CancelMetricTaskBackfill(testCtx, "b1")
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_MetricModel_BackfillFinished)
+		})
+
+		Convey("Cancel succeed", func() {
+			mtba.EXPECT().GetMetricTaskBackfill(gomock.Any(), "b1").Return(backfill, true, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mtba.EXPECT().CancelMetricTaskBackfill(gomock.Any(), "b1", gomock.Any()).Return(true, nil)
+
+			err := mmts.CancelMetricTaskBackfill(testCtx, "b1")
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,356 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package model_bundle
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"data-model/common"
+	"data-model/interfaces"
+)
+
+// 与 common.ExtractModelIDs 一致, 复合指标公式中 {{ }} 内为引用的指标模型 id
+var formulaModelIDRegexp = regexp.MustCompile(`\{\{\s*(.*?)\s*\}\}`)
+
+// 模型包中各对象依赖的其他对象 id
+type bundleDepends struct {
+	viewIDs   []string
+	metricIDs []string
+	eventIDs  []string
+}
+
+// 自定义视图依赖 view 类型节点引用的视图
+func dataViewDepends(view *interfaces.DataView) bundleDepends {
+	depends := bundleDepends{}
+	if view.Type != interfaces.ViewType_Custom {
+		return depends
+	}
+
+	for _, node := range view.DataScope {
+		if node.Type != interfaces.DataScopeNodeType_View {
+			continue
+		}
+		if viewID, ok := node.Config["view_id"].(string); ok && viewID != "" {
+			depends.viewIDs = append(depends.viewIDs, viewID)
+		}
+	}
+	return depends
+}
+
+// 原子指标依赖视图, 衍生指标依赖被衍生的指标, 复合指标依赖公式中引用的指标
+func metricModelDepends(model *interfaces.MetricModel) bundleDepends {
+	depends := bundleDepends{}
+
+	switch model.MetricType {
+	case interfaces.ATOMIC_METRIC:
+		// RED指标的数据源是链路模型, 链路模型不随模型包导出
+		if model.QueryType == interfaces.RED {
+			break
+		}
+		viewID := model.DataViewID
+		if model.DataSource != nil && model.DataSource.ID != "" {
+			viewID = model.DataSource.ID
+		}
+		if viewID != "" {
+			depends.viewIDs = append(depends.viewIDs, viewID)
+		}
+	case interfaces.DERIVED_METRIC:
+		if cfg, ok := model.FormulaConfig.(interfaces.DerivedConfig); ok && cfg.DependMetricModel != nil {
+			depends.metricIDs = append(depends.metricIDs, cfg.DependMetricModel.ID)
+		}
+	case interfaces.COMPOSITED_METRIC:
+		depends.metricIDs = append(depends.metricIDs, common.ExtractModelIDs(model.Formula)...)
+	}
+	return depends
+}
+
+// 事件模型依赖 data_source 中的指标模型、视图或事件模型, 以及下游依赖的事件模型
+func eventModelDepends(model *interfaces.EventModel) bundleDepends {
+	depends := bundleDepends{}
+
+	switch model.DataSourceType {
+	case interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_METRIC_MODEL:
+		depends.metricIDs = append(depends.metricIDs, model.DataSource...)
+	case interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_DATE_VIEW:
+		depends.viewIDs = append(depends.viewIDs, model.DataSource...)
+	case interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_EVENT_MODEL:
+		depends.eventIDs = append(depends.eventIDs, model.DataSource...)
+	}
+	depends.eventIDs = append(depends.eventIDs, model.DownstreamDependentModel...)
+	return depends
+}
+
+// 目标模型依赖 SLO 的 good/total 指标或 KPI 的综合计算、附加计算指标
+func objectiveModelDepends(model *interfaces.ObjectiveModel) bundleDepends {
+	depends := bundleDepends{}
+
+	switch cfg := model.ObjectiveConfig.(type) {
+	case interfaces.SLOObjective:
+		for _, metric := range []*interfaces.BundleMetricModel{cfg.GoodMetricModel, cfg.TotalMetricModel} {
+			if metric != nil && metric.ID != "" {
+				depends.metricIDs = append(depends.metricIDs, metric.ID)
+			}
+		}
+	case interfaces.KPIObjective:
+		for _, metric := range cfg.ComprehensiveMetricModels {
+			depends.metricIDs = append(depends.metricIDs, metric.ID)
+		}
+		for _, metric := range cfg.AdditionalMetricModels {
+			depends.metricIDs = append(depends.metricIDs, metric.ID)
+		}
+	}
+	return depends
+}
+
+// 按依赖排序, 被依赖的对象在前. 不在 ids 中的依赖忽略, 同层按 id 排序保证导出结果稳定
+func sortByDepends(ids []string, depends map[string][]string) []string {
+	sortedIDs := append([]string{}, ids...)
+	sort.Strings(sortedIDs)
+
+	inSet := make(map[string]struct{}, len(sortedIDs))
+	for _, id := range sortedIDs {
+		inSet[id] = struct{}{}
+	}
+
+	result := make([]string, 0, len(sortedIDs))
+	visited := make(map[string]bool, len(sortedIDs))
+	var visit func(id string)
+	visit = func(id string) {
+		if _, ok := visited[id]; ok {
+			// 正在访问的节点再次出现说明存在循环依赖, 直接跳过
+			return
+		}
+		visited[id] = false
+
+		dependIDs := append([]string{}, depends[id]...)
+		sort.Strings(dependIDs)
+		for _, dependID := range dependIDs {
+			if _, ok := inSet[dependID]; ok {
+				visit(dependID)
+			}
+		}
+
+		visited[id] = true
+		result = append(result, id)
+	}
+
+	for _, id := range sortedIDs {
+		visit(id)
+	}
+	return result
+}
+
+// 对模型包内的对象按依赖排序
+func sortBundle(bundle *interfaces.ModelBundle) {
+	viewMap := make(map[string]*interfaces.DataView, len(bundle.DataViews))
+	viewDepends := make(map[string][]string, len(bundle.DataViews))
+	viewIDs := make([]string, 0, len(bundle.DataViews))
+	for _, view := range bundle.DataViews {
+		viewMap[view.ViewID] = view
+		viewDepends[view.ViewID] = dataViewDepends(view).viewIDs
+		viewIDs = append(viewIDs, view.ViewID)
+	}
+	bundle.DataViews = bundle.DataViews[:0]
+	for _, viewID := range sortByDepends(viewIDs, viewDepends) {
+		bundle.DataViews = append(bundle.DataViews, viewMap[viewID])
+	}
+
+	metricMap := make(map[string]*interfaces.MetricModel, len(bundle.MetricModels))
+	metricDepends := make(map[string][]string, len(bundle.MetricModels))
+	metricIDs := make([]string, 0, len(bundle.MetricModels))
+	for _, model := range bundle.MetricModels {
+		metricMap[model.ModelID] = model
+		metricDepends[model.ModelID] = metricModelDepends(model).metricIDs
+		metricIDs = append(metricIDs, model.ModelID)
+	}
+	bundle.MetricModels = bundle.MetricModels[:0]
+	for _, modelID := range sortByDepends(metricIDs, metricDepends) {
+		bundle.MetricModels = append(bundle.MetricModels, metricMap[modelID])
+	}
+
+	eventMap := make(map[string]*interfaces.EventModel, len(bundle.EventModels))
+	eventDepends := make(map[string][]string, len(bundle.EventModels))
+	eventIDs := make([]string, 0, len(bundle.EventModels))
+	for _, model := range bundle.EventModels {
+		eventMap[model.EventModelID] = model
+		eventDepends[model.EventModelID] = eventModelDepends(model).eventIDs
+		eventIDs = append(eventIDs, model.EventModelID)
+	}
+	bundle.EventModels = bundle.EventModels[:0]
+	for _, modelID := range sortByDepends(eventIDs, eventDepends) {
+		bundle.EventModels = append(bundle.EventModels, eventMap[modelID])
+	}
+
+	sort.Slice(bundle.ObjectiveModels, func(i, j int) bool {
+		return bundle.ObjectiveModels[i].ModelID < bundle.ObjectiveModels[j].ModelID
+	})
+	sort.Slice(bundle.DataViewGroups, func(i, j int) bool {
+		return bundle.DataViewGroups[i].GroupName < bundle.DataViewGroups[j].GroupName
+	})
+	sort.Slice(bundle.MetricModelGroups, func(i, j int) bool {
+		return bundle.MetricModelGroups[i].GroupName < bundle.MetricModelGroups[j].GroupName
+	})
+	sort.Slice(bundle.DataSources, func(i, j int) bool {
+		return bundle.DataSources[i].ID < bundle.DataSources[j].ID
+	})
+}
+
+func mapID(idMap map[string]string, id string) string {
+	if newID, ok := idMap[id]; ok {
+		return newID
+	}
+	return id
+}
+
+func mapIDs(idMap map[string]string, ids []string) []string {
+	if ids == nil {
+		return nil
+	}
+	newIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		newIDs = append(newIDs, mapID(idMap, id))
+	}
+	return newIDs
+}
+
+// 重命名模式下对象 id 变化后, 替换模型包内其他对象对它的引用
+func rewriteBundleReferences(bundle *interfaces.ModelBundle, viewIDMap, metricIDMap, eventIDMap map[string]string) {
+	for _, view := range bundle.DataViews {
+		if view.Type != interfaces.ViewType_Custom {
+			continue
+		}
+		for _, node := range view.DataScope {
+			if node.Type != interfaces.DataScopeNodeType_View {
+				continue
+			}
+			if viewID, ok := node.Config["view_id"].(string); ok {
+				node.Config["view_id"] = mapID(viewIDMap, viewID)
+			}
+		}
+	}
+
+	for _, model := range bundle.MetricModels {
+		switch model.MetricType {
+		case interfaces.ATOMIC_METRIC:
+			model.DataViewID = mapID(viewIDMap, model.DataViewID)
+			if model.DataSource != nil {
+				model.DataSource.ID = mapID(viewIDMap, model.DataSource.ID)
+			}
+		case interfaces.DERIVED_METRIC:
+			if cfg, ok := model.FormulaConfig.(interfaces.DerivedConfig); ok && cfg.DependMetricModel != nil {
+				dependModel := *cfg.DependMetricModel
+				dependModel.ID = mapID(metricIDMap, dependModel.ID)
+				cfg.DependMetricModel = &dependModel
+				model.FormulaConfig = cfg
+			}
+		case interfaces.COMPOSITED_METRIC:
+			model.Formula = formulaModelIDRegexp.ReplaceAllStringFunc(model.Formula, func(match string) string {
+				modelID := strings.TrimSpace(formulaModelIDRegexp.FindStringSubmatch(match)[1])
+				if newID, ok := metricIDMap[modelID]; ok {
+					return fmt.Sprintf("{{%s}}", newID)
+				}
+				return match
+			})
+		}
+	}
+
+	for _, model := range bundle.EventModels {
+		switch model.DataSourceType {
+		case interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_METRIC_MODEL:
+			model.DataSource = mapIDs(metricIDMap, model.DataSource)
+		case interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_DATE_VIEW:
+			model.DataSource = mapIDs(viewIDMap, model.DataSource)
+		case interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_EVENT_MODEL:
+			model.DataSource = mapIDs(eventIDMap, model.DataSource)
+		}
+		model.DownstreamDependentModel = mapIDs(eventIDMap, model.DownstreamDependentModel)
+	}
+
+	for _, model := range bundle.ObjectiveModels {
+		switch cfg := model.ObjectiveConfig.(type) {
+		case interfaces.SLOObjective:
+			if cfg.GoodMetricModel != nil {
+				goodModel := *cfg.GoodMetricModel
+				goodModel.ID = mapID(metricIDMap, goodModel.ID)
+				cfg.GoodMetricModel = &goodModel
+			}
+			if cfg.TotalMetricModel != nil {
+				totalModel := *cfg.TotalMetricModel
+				totalModel.ID = mapID(metricIDMap, totalModel.ID)
+				cfg.TotalMetricModel = &totalModel
+			}
+			model.ObjectiveConfig = cfg
+		case interfaces.KPIObjective:
+			comprehensiveModels := make([]interfaces.ComprehensiveMetricModel, 0, len(cfg.ComprehensiveMetricModels))
+			for _, metric := range cfg.ComprehensiveMetricModels {
+				metric.ID = mapID(metricIDMap, metric.ID)
+				comprehensiveModels = append(comprehensiveModels, metric)
+			}
+			additionalModels := make([]interfaces.BundleMetricModel, 0, len(cfg.AdditionalMetricModels))
+			for _, metric := range cfg.AdditionalMetricModels {
+				metric.ID = mapID(metricIDMap, metric.ID)
+				additionalModels = append(additionalModels, metric)
+			}
+			cfg.ComprehensiveMetricModels = comprehensiveModels
+			cfg.AdditionalMetricModels = additionalModels
+			model.ObjectiveConfig = cfg
+		}
+	}
+}
+
+// 生成第 index 个重命名候选名称, 超出长度时截断原名称
+func renameCandidate(name string, index int, maxLength int) string {
+	suffix := interfaces.MODEL_BUNDLE_RENAME_SUFFIX
+	if index > 1 {
+		suffix = fmt.Sprintf("%s_%d", suffix, index)
+	}
+
+	runes := []rune(name)
+	if limit := maxLength - utf8.RuneCountInString(suffix); len(runes) > limit {
+		runes = runes[:max(limit, 0)]
+	}
+	return string(runes) + suffix
+}
+
+// 根据导入计划确定对象的处理方式
+func decideAction(mode string, id string, idExist bool, nameOwner string, measureOwner string) (string, []string) {
+	conflicts := make([]string, 0)
+	if idExist {
+		conflicts = append(conflicts, interfaces.MODEL_BUNDLE_CONFLICT_ID)
+	}
+	if nameOwner != "" {
+		conflicts = append(conflicts, interfaces.MODEL_BUNDLE_CONFLICT_NAME)
+	}
+	if measureOwner != "" {
+		conflicts = append(conflicts, interfaces.MODEL_BUNDLE_CONFLICT_MEASURE_NAME)
+	}
+	if len(conflicts) == 0 {
+		return interfaces.MODEL_BUNDLE_ACTION_CREATE, nil
+	}
+
+	switch mode {
+	case interfaces.MODEL_BUNDLE_IMPORT_MODE_OVERWRITE:
+		// 只有 id 已存在, 且同名对象、同度量名称的对象都是它自身时才能覆盖
+		if idExist && (nameOwner == "" || nameOwner == id) && (measureOwner == "" || measureOwner == id) {
+			return interfaces.MODEL_BUNDLE_ACTION_UPDATE, conflicts
+		}
+	case interfaces.MODEL_BUNDLE_IMPORT_MODE_RENAME:
+		return interfaces.MODEL_BUNDLE_ACTION_RENAME, conflicts
+	}
+	return interfaces.MODEL_BUNDLE_ACTION_CONFLICT, conflicts
+}
+
+// 导入模式对应各模型服务的导入模式, 重命名后已无冲突, 按普通模式创建
+func serviceImportMode(action string) string {
+	if action == interfaces.MODEL_BUNDLE_ACTION_UPDATE {
+		return interfaces.ImportMode_Overwrite
+	}
+	return interfaces.ImportMode_Normal
+}
@@ -0,0 +1,1055 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package model_bundle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"github.com/rs/xid"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+	"data-model/logics"
+	"data-model/logics/data_view"
+	"data-model/logics/event_model"
+	"data-model/logics/metric_model"
+	"data-model/logics/objective_model"
+)
+
+var (
+	mbServiceOnce sync.Once
+	mbService     interfaces.ModelBundleService
+)
+
+// 重命名时最多尝试的候选名称个数
+const maxRenameAttempts = 100
+
+type modelBundleService struct {
+	appSetting *common.AppSetting
+	dsa        interfaces.DataSourceAccess
+	ema        interfaces.EventModelAccess
+	dvs        interfaces.DataViewService
+	dvgs       interfaces.DataViewGroupService
+	mms        interfaces.MetricModelService
+	mmgs       interfaces.MetricModelGroupService
+	ems        interfaces.EventModelService
+	oms        interfaces.ObjectiveModelService
+}
+
+func NewModelBundleService(appSetting *common.AppSetting) interfaces.ModelBundleService {
+	mbServiceOnce.Do(func() {
+		mbService = &modelBundleService{
+			appSetting: appSetting,
+			dsa:        logics.DSA,
+			ema:        logics.EMA,
+			dvs:        data_view.NewDataViewService(appSetting),
+			dvgs:       data_view.NewDataViewGroupService(appSetting),
+			mms:        metric_model.NewMetricModelService(appSetting),
+			mmgs:       metric_model.NewMetricModelGroupService(appSetting),
+			ems:        event_model.NewEventModelService(appSetting),
+			oms:        objective_model.NewObjectiveModelService(appSetting),
+		}
+	})
+
+	return mbService
+}
+
+// 导入计划, 记录每个对象的处理方式以及重命名后 id 的映射
+type importPlan struct {
+	items   []*interfaces.ModelBundleImportItem
+	itemMap map[string]*interfaces.ModelBundleImportItem
+
+	viewIDMap   map[string]string
+	metricIDMap map[string]string
+	eventIDMap  map[string]string
+
+	// 重命名时度量名称冲突的指标, 清空后由指标模型服务按新 id 生成
+	resetMeasureNames map[string]struct{}
+	// 模型包内已占用的名称, 重命名时不能与之重复
+	usedNames map[string]struct{}
+}
+
+func newImportPlan() *importPlan {
+	return &importPlan{
+		items:             make([]*interfaces.ModelBundleImportItem, 0),
+		itemMap:           make(map[string]*interfaces.ModelBundleImportItem),
+		viewIDMap:         make(map[string]string),
+		metricIDMap:       make(map[string]string),
+		eventIDMap:        make(map[string]string),
+		resetMeasureNames: make(map[string]struct{}),
+		usedNames:         make(map[string]struct{}),
+	}
+}
+
+// 分组以名称作为 key, 其他对象以 id 作为 key
+func (p *importPlan) add(item *interfaces.ModelBundleImportItem, key string) {
+	p.items = append(p.items, item)
+	p.itemMap[item.AssetType+"/"+key] = item
+}
+
+func (p *importPlan) item(assetType string, key string) *interfaces.ModelBundleImportItem {
+	return p.itemMap[assetType+"/"+key]
+}
+
+func usedNameKey(assetType string, scope string, name string) string {
+	return fmt.Sprintf("%s/%s/%s", assetType, scope, name)
+}
+
+// 导出模型包, 选中对象依赖的视图、指标模型和事件模型一并导出
+func (mbs *modelBundleService) ExportModelBundle(ctx context.Context,
+	req *interfaces.ModelBundleExportRequest) (*interfaces.ModelBundle, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Export model bundle")
+	defer span.End()
+
+	views := make(map[string]*interfaces.DataView)
+	metrics := make(map[string]*interfaces.MetricModel)
+	events := make(map[string]*interfaces.EventModel)
+	objectives := make(map[string]*interfaces.ObjectiveModel)
+
+	pendingViews := append([]string{}, req.DataViewIDs...)
+	pendingMetrics := append([]string{}, req.MetricModelIDs...)
+	pendingEvents := append([]string{}, req.EventModelIDs...)
+
+	// 按分组导出时展开为分组下的对象
+	for _, groupID := range req.DataViewGroupIDs {
+		_, err := mbs.dvgs.GetDataViewGroupByID(ctx, groupID)
+		if err != nil {
+			span.SetStatus(codes.Error, "Get data view group failed")
+			return nil, err
+		}
+		groupViews, err := mbs.dvs.GetDataViewsByGroupID(ctx, groupID)
+		if err != nil {
+			span.SetStatus(codes.Error, "Get data views by group id failed")
+			return nil, err
+		}
+		for _, view := range groupViews {
+			pendingViews = append(pendingViews, view.ViewID)
+		}
+	}
+	for _, groupID := range req.MetricModelGroupIDs {
+		_, err := mbs.mmgs.GetMetricModelGroupByID(ctx, groupID)
+		if err != nil {
+			span.SetStatus(codes.Error, "Get metric model group failed")
+			return nil, err
+		}
+		groupModels, err := mbs.mms.GetMetricModelsByGroupID(ctx, groupID)
+		if err != nil {
+			span.SetStatus(codes.Error, "Get metric models by group id failed")
+			return nil, err
+		}
+		for _, model := range groupModels {
+			pendingMetrics = append(pendingMetrics, model.ModelID)
+		}
+	}
+
+	// 目标模型不会被其他对象依赖, 只需获取一次
+	if len(req.ObjectiveModelIDs) > 0 {
+		models, err := mbs.oms.GetObjectiveModels(ctx, common.DuplicateSlice(req.ObjectiveModelIDs))
+		if err != nil {
+			span.SetStatus(codes.Error, "Get objective models failed")
+			return nil, err
+		}
+		for i := range models {
+			model := models[i]
+			model.Operations = nil
+			objectives[model.ModelID] = &model
+			pendingMetrics = append(pendingMetrics, objectiveModelDepends(&model).metricIDs...)
+		}
+	}
+
+	// 逐层获取依赖, 直到没有新的对象
+	for len(pendingViews)+len(pendingMetrics)+len(pendingEvents) > 0 {
+		eventIDs := unfetchedIDs(pendingEvents, events)
+		pendingEvents = nil
+		for _, eventID := range eventIDs {
+			model, httpErr := mbs.ems.GetEventModelByID(ctx, eventID)
+			if httpErr != nil {
+				span.SetStatus(codes.Error, "Get event model failed")
+				return nil, httpErr
+			}
+			model.Operations = nil
+			events[eventID] = &model
+
+			depends := eventModelDepends(&model)
+			pendingViews = append(pendingViews, depends.viewIDs...)
+			pendingMetrics = append(pendingMetrics, depends.metricIDs...)
+			pendingEvents = append(pendingEvents, depends.eventIDs...)
+		}
+
+		metricIDs := unfetchedIDs(pendingMetrics, metrics)
+		pendingMetrics = nil
+		if len(metricIDs) > 0 {
+			models, err := mbs.mms.GetMetricModels(ctx, metricIDs, false)
+			if err != nil {
+				span.SetStatus(codes.Error, "Get metric models failed")
+				return nil, err
+			}
+			for i := range models {
+				model := models[i].MetricModel
+				model.Operations = nil
+				metrics[model.ModelID] = &model
+
+				depends := metricModelDepends(&model)
+				pendingViews = append(pendingViews, depends.viewIDs...)
+				pendingMetrics = append(pendingMetrics, depends.metricIDs...)
+			}
+		}
+
+		viewIDs := unfetchedIDs(pendingViews, views)
+		pendingViews = nil
+		if len(viewIDs) > 0 {
+			dataViews, err := mbs.dvs.GetDataViews(ctx, viewIDs, false)
+			if err != nil {
+				span.SetStatus(codes.Error, "Get data views failed")
+				return nil, err
+			}
+			for _, view := range dataViews {
+				view.Operations = nil
+				views[view.ViewID] = view
+				pendingViews = append(pendingViews, dataViewDepends(view).viewIDs...)
+			}
+		}
+	}
+
+	bundle := &interfaces.ModelBundle{
+		Version:           interfaces.MODEL_BUNDLE_VERSION,
+		ExportTime:        time.Now().UnixMilli(),
+		DataSources:       make([]*interfaces.ModelBundleDataSource, 0),
+		DataViewGroups:    make([]*interfaces.DataViewGroup, 0),
+		DataViews:         make([]*interfaces.DataView, 0, len(views)),
+		MetricModelGroups: make([]*interfaces.MetricModelGroup, 0),
+		MetricModels:      make([]*interfaces.MetricModel, 0, len(metrics)),
+		EventModels:       make([]*interfaces.EventModel, 0, len(events)),
+		ObjectiveModels:   make([]*interfaces.ObjectiveModel, 0, len(objectives)),
+	}
+
+	viewGroupNames := make(map[string]struct{})
+	dataSourceIDs := make(map[string]struct{})
+	for _, view := range views {
+		bundle.DataViews = append(bundle.DataViews, view)
+
+		if _, ok := viewGroupNames[view.GroupName]; view.GroupName != "" && !ok {
+			viewGroupNames[view.GroupName] = struct{}{}
+			bundle.DataViewGroups = append(bundle.DataViewGroups, &interfaces.DataViewGroup{
+				GroupID:   view.GroupID,
+				GroupName: view.GroupName,
+			})
+		}
+
+		if view.Type != interfaces.ViewType_Atomic || view.DataSourceID == "" ||
+			view.DataSourceID == interfaces.DataSourceID_IndexBase {
+			continue
+		}
+		if _, ok := dataSourceIDs[view.DataSourceID]; !ok {
+			dataSourceIDs[view.DataSourceID] = struct{}{}
+			bundle.DataSources = append(bundle.DataSources, &interfaces.ModelBundleDataSource{
+				ID:   view.DataSourceID,
+				Name: view.DataSourceName,
+				Type: view.DataSourceType,
+			})
+		}
+	}
+
+	metricGroupIDs := make(map[string]struct{})
+	for _, model := range metrics {
+		bundle.MetricModels = append(bundle.MetricModels, model)
+
+		if _, ok := metricGroupIDs[model.GroupID]; model.GroupName == "" || ok {
+			continue
+		}
+		metricGroupIDs[model.GroupID] = struct{}{}
+		// 分组备注需要单独获取
+		group, err := mbs.mmgs.GetMetricModelGroupByID(ctx, model.GroupID)
+		if err != nil {
+			span.SetStatus(codes.Error, "Get metric model group failed")
+			return nil, err
+		}
+		bundle.MetricModelGroups = append(bundle.MetricModelGroups, &interfaces.MetricModelGroup{
+			GroupID:   group.GroupID,
+			GroupName: group.GroupName,
+			Comment:   group.Comment,
+		})
+	}
+
+	for _, model := range events {
+		bundle.EventModels = append(bundle.EventModels, model)
+	}
+	for _, model := range objectives {
+		bundle.ObjectiveModels = append(bundle.ObjectiveModels, model)
+	}
+
+	sortBundle(bundle)
+
+	span.SetAttributes(
+		attr.Key("data_view_count").Int(len(bundle.DataViews)),
+		attr.Key("metric_model_count").Int(len(bundle.MetricModels)),
+		attr.Key("event_model_count").Int(len(bundle.EventModels)),
+		attr.Key("objective_model_count").Int(len(bundle.ObjectiveModels)),
+	)
+	span.SetStatus(codes.Ok, "")
+	return bundle, nil
+}
+
+// 去重并过滤掉已经获取过的 id
+func unfetchedIDs[T any](ids []string, fetched map[string]T) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range common.DuplicateSlice(ids) {
+		if _, ok := fetched[id]; !ok && id != "" {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// 导入模型包. 依次导入分组、视图、指标模型、事件模型和目标模型, 各对象单独创建, 导入中途失败时已导入的对象不回滚
+func (mbs *modelBundleService) ImportModelBundle(ctx context.Context, bundle *interfaces.ModelBundle,
+	mode string, dryRun bool) (*interfaces.ModelBundleImportResult, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Import model bundle")
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("mode").String(mode),
+		attr.Key("dry_run").Bool(dryRun),
+	)
+
+	sortBundle(bundle)
+
+	missingDataSources, err := mbs.checkDataSources(ctx, bundle)
+	if err != nil {
+		span.SetStatus(codes.Error, "Check data sources failed")
+		return nil, err
+	}
+
+	plan := newImportPlan()
+	for _, planFunc := range []func(context.Context, *interfaces.ModelBundle, string, *importPlan) error{
+		mbs.planDataViewGroups,
+		mbs.planDataViews,
+		mbs.planMetricModelGroups,
+		mbs.planMetricModels,
+		mbs.planEventModels,
+		mbs.planObjectiveModels,
+	} {
+		err = planFunc(ctx, bundle, mode, plan)
+		if err != nil {
+			span.SetStatus(codes.Error, "Plan model bundle import failed")
+			return nil, err
+		}
+	}
+
+	result := &interfaces.ModelBundleImportResult{
+		Mode:               mode,
+		DryRun:             dryRun,
+		Items:              plan.items,
+		MissingDataSources: missingDataSources,
+	}
+	if dryRun {
+		span.SetStatus(codes.Ok, "")
+		return result, nil
+	}
+
+	if len(missingDataSources) > 0 {
+		span.SetStatus(codes.Error, "Data sources not found")
+		return nil, rest.NewHTTPError(ctx, http.StatusForbidden, derrors.DataModel_ModelBundle_DataSourceNotFound).
+			WithErrorDetails(missingDataSources)
+	}
+
+	conflictItems := make([]*interfaces.ModelBundleImportItem, 0)
+	for _, item := range plan.items {
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_CONFLICT {
+			conflictItems = append(conflictItems, item)
+		}
+	}
+	if len(conflictItems) > 0 {
+		span.SetStatus(codes.Error, "Model bundle import conflict")
+		return nil, rest.NewHTTPError(ctx, http.StatusForbidden, derrors.DataModel_ModelBundle_ImportConflict).
+			WithErrorDetails(conflictItems)
+	}
+
+	rewriteBundleReferences(bundle, plan.viewIDMap, plan.metricIDMap, plan.eventIDMap)
+
+	applied, err := mbs.importAssets(ctx, bundle, plan)
+	if err != nil {
+		// 各模型服务各自提交事务, 失败时回滚已导入的对象, 避免只导入了一部分
+		mbs.rollbackAssets(ctx, applied)
+		span.SetStatus(codes.Error, "Import model bundle failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
+
+// 检查原子视图依赖的数据连接在当前环境中是否存在
+func (mbs *modelBundleService) checkDataSources(ctx context.Context,
+	bundle *interfaces.ModelBundle) ([]*interfaces.ModelBundleDataSource, error) {
+
+	required := make(map[string]*interfaces.ModelBundleDataSource)
+	for _, dataSource := range bundle.DataSources {
+		required[dataSource.ID] = dataSource
+	}
+	for _, view := range bundle.DataViews {
+		if view.Type != interfaces.ViewType_Atomic || view.DataSourceID == "" ||
+			view.DataSourceID == interfaces.DataSourceID_IndexBase {
+			continue
+		}
+		if _, ok := required[view.DataSourceID]; !ok {
+			required[view.DataSourceID] = &interfaces.ModelBundleDataSource{
+				ID:   view.DataSourceID,
+				Name: view.DataSourceName,
+				Type: view.DataSourceType,
+			}
+		}
+	}
+
+	missing := make([]*interfaces.ModelBundleDataSource, 0)
+	if len(required) == 0 {
+		return missing, nil
+	}
+
+	dataSources, err := mbs.dsa.ListDataSources(ctx)
+	if err != nil {
+		logger.Errorf("List data sources error: %s", err.Error())
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_ModelBundle_InternalError_GetDataSourcesFailed).WithErrorDetails(err.Error())
+	}
+
+	exist := make(map[string]struct{}, len(dataSources.Entries))
+	for _, dataSource := range dataSources.Entries {
+		exist[dataSource.ID] = struct{}{}
+	}
+	for id, dataSource := range required {
+		if _, ok := exist[id]; !ok {
+			missing = append(missing, dataSource)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].ID < missing[j].ID
+	})
+	return missing, nil
+}
+
+// 为冲突对象查找可用的名称, 既不能与已有对象重名, 也不能与模型包中的其他对象重名
+func (mbs *modelBundleService) findAvailableName(ctx context.Context, plan *importPlan, assetType string,
+	scope string, name string, maxLength int, exist func(name string) (bool, error)) (string, error) {
+
+	for i := 1; i <= maxRenameAttempts; i++ {
+		candidate := renameCandidate(name, i, maxLength)
+		key := usedNameKey(assetType, scope, candidate)
+		if _, ok := plan.usedNames[key]; ok {
+			continue
+		}
+
+		nameExist, err := exist(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !nameExist {
+			plan.usedNames[key] = struct{}{}
+			return candidate, nil
+		}
+	}
+
+	return "", rest.NewHTTPError(ctx, http.StatusInternalServerError, derrors.DataModel_ModelBundle_InternalError_RenameFailed).
+		WithErrorDetails(fmt.Sprintf("No available name found for %s '%s' after %d attempts", assetType, name, maxRenameAttempts))
+}
+
+// 分组按名称匹配, 已存在的直接复用
+func (mbs *modelBundleService) planDataViewGroups(ctx context.Context, bundle *interfaces.ModelBundle,
+	mode string, plan *importPlan) error {
+
+	for _, group := range bundle.DataViewGroups {
+		item := &interfaces.ModelBundleImportItem{
+			AssetType:  interfaces.MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW_GROUP,
+			ID:         group.GroupID,
+			Name:       group.GroupName,
+			Action:     interfaces.MODEL_BUNDLE_ACTION_CREATE,
+			TargetName: group.GroupName,
+		}
+
+		existGroup, exist, err := mbs.dvgs.CheckDataViewGroupExistByName(ctx, nil, group.GroupName, group.Builtin)
+		if err != nil {
+			return err
+		}
+		if exist {
+			item.Action = interfaces.MODEL_BUNDLE_ACTION_EXIST
+			item.TargetID = existGroup.GroupID
+		}
+		plan.add(item, group.GroupName)
+	}
+	return nil
+}
+
+func (mbs *modelBundleService) planDataViews(ctx context.Context, bundle *interfaces.ModelBundle,
+	mode string, plan *importPlan) error {
+
+	for _, view := range bundle.DataViews {
+		plan.usedNames[usedNameKey(interfaces.MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW, view.GroupName, view.ViewName)] = struct{}{}
+	}
+
+	for _, view := range bundle.DataViews {
+		_, idExist, err := mbs.dvs.CheckDataViewExistByID(ctx, nil, view.ViewID)
+		if err != nil {
+			return err
+		}
+		nameOwner, nameExist, err := mbs.dvs.CheckDataViewExistByName(ctx, nil, view.ViewName, view.GroupName)
+		if err != nil {
+			return err
+		}
+		if !nameExist {
+			nameOwner = ""
+		}
+
+		item := &interfaces.ModelBundleImportItem{
+			AssetType:  interfaces.MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW,
+			ID:         view.ViewID,
+			Name:       view.ViewName,
+			TargetID:   view.ViewID,
+			TargetName: view.ViewName,
+		}
+		item.Action, item.Conflicts = decideAction(mode, view.ViewID, idExist, nameOwner, "")
+
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_RENAME {
+			if idExist {
+				item.TargetID = xid.New().String()
+				plan.viewIDMap[view.ViewID] = item.TargetID
+			}
+			if nameExist {
+				groupName := view.GroupName
+				item.TargetName, err = mbs.findAvailableName(ctx, plan, item.AssetType, groupName, view.ViewName,
+					interfaces.MaxLength_ViewName, func(name string) (bool, error) {
+						_, exist, err := mbs.dvs.CheckDataViewExistByName(ctx, nil, name, groupName)
+						return exist, err
+					})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_CONFLICT {
+			item.TargetID, item.TargetName = "", ""
+		}
+		plan.add(item, view.ViewID)
+	}
+	return nil
+}
+
+func (mbs *modelBundleService) planMetricModelGroups(ctx context.Context, bundle *interfaces.ModelBundle,
+	mode string, plan *importPlan) error {
+
+	for _, group := range bundle.MetricModelGroups {
+		item := &interfaces.ModelBundleImportItem{
+			AssetType:  interfaces.MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL_GROUP,
+			ID:         group.GroupID,
+			Name:       group.GroupName,
+			Action:     interfaces.MODEL_BUNDLE_ACTION_CREATE,
+			TargetName: group.GroupName,
+		}
+
+		exist, err := mbs.mmgs.CheckMetricModelGroupExist(ctx, group.GroupName)
+		if err != nil {
+			return err
+		}
+		if exist {
+			item.Action = interfaces.MODEL_BUNDLE_ACTION_EXIST
+		}
+		plan.add(item, group.GroupName)
+	}
+	return nil
+}
+
+func (mbs *modelBundleService) planMetricModels(ctx context.Context, bundle *interfaces.ModelBundle,
+	mode string, plan *importPlan) error {
+
+	for _, model := range bundle.MetricModels {
+		plan.usedNames[usedNameKey(interfaces.MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL, model.GroupName, model.ModelName)] = struct{}{}
+	}
+
+	for _, model := range bundle.MetricModels {
+		_, idExist, err := mbs.mms.CheckMetricModelExistByID(ctx, model.ModelID)
+		if err != nil {
+			return err
+		}
+		nameOwner, nameExist, err := mbs.mms.CheckMetricModelExistByName(ctx, model.GroupName, model.ModelName)
+		if err != nil {
+			return err
+		}
+		if !nameExist {
+			nameOwner = ""
+		}
+		measureOwner := ""
+		if model.MeasureName != "" {
+			owner, measureExist, err := mbs.mms.CheckMetricModelByMeasureName(ctx, model.MeasureName)
+			if err != nil {
+				return err
+			}
+			if measureExist {
+				measureOwner = owner
+			}
+		}
+
+		item := &interfaces.ModelBundleImportItem{
+			AssetType:  interfaces.MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL,
+			ID:         model.ModelID,
+			Name:       model.ModelName,
+			TargetID:   model.ModelID,
+			TargetName: model.ModelName,
+		}
+		item.Action, item.Conflicts = decideAction(mode, model.ModelID, idExist, nameOwner, measureOwner)
+
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_RENAME {
+			if idExist {
+				item.TargetID = xid.New().String()
+				plan.metricIDMap[model.ModelID] = item.TargetID
+			}
+			if nameExist {
+				groupName := model.GroupName
+				item.TargetName, err = mbs.findAvailableName(ctx, plan, item.AssetType, groupName, model.ModelName,
+					interfaces.OBJECT_NAME_MAX_LENGTH, func(name string) (bool, error) {
+						_, exist, err := mbs.mms.CheckMetricModelExistByName(ctx, groupName, name)
+						return exist, err
+					})
+				if err != nil {
+					return err
+				}
+			}
+			if measureOwner != "" {
+				plan.resetMeasureNames[model.ModelID] = struct{}{}
+			}
+		}
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_CONFLICT {
+			item.TargetID, item.TargetName = "", ""
+		}
+		plan.add(item, model.ModelID)
+	}
+	return nil
+}
+
+func (mbs *modelBundleService) planEventModels(ctx context.Context, bundle *interfaces.ModelBundle,
+	mode string, plan *importPlan) error {
+
+	if len(bundle.EventModels) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(bundle.EventModels))
+	names := make([]string, 0, len(bundle.EventModels))
+	for _, model := range bundle.EventModels {
+		ids = append(ids, model.EventModelID)
+		names = append(names, model.EventModelName)
+		plan.usedNames[usedNameKey(interfaces.MODEL_BUNDLE_ASSET_TYPE_EVENT_MODEL, "", model.EventModelName)] = struct{}{}
+	}
+
+	existIDs, err := mbs.ema.GetEventModelMapByIDs(ids)
+	if err != nil {
+		logger.Errorf("Get event models by ids error: %s", err.Error())
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError, derrors.EventModel_InternalError).
+			WithErrorDetails(err.Error())
+	}
+	existNames, err := mbs.ema.GetEventModelMapByNames(names)
+	if err != nil {
+		logger.Errorf("Get event models by names error: %s", err.Error())
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError, derrors.EventModel_InternalError).
+			WithErrorDetails(err.Error())
+	}
+
+	for _, model := range bundle.EventModels {
+		_, idExist := existIDs[model.EventModelID]
+		nameOwner := existNames[model.EventModelName]
+
+		item := &interfaces.ModelBundleImportItem{
+			AssetType:  interfaces.MODEL_BUNDLE_ASSET_TYPE_EVENT_MODEL,
+			ID:         model.EventModelID,
+			Name:       model.EventModelName,
+			TargetID:   model.EventModelID,
+			TargetName: model.EventModelName,
+		}
+		item.Action, item.Conflicts = decideAction(mode, model.EventModelID, idExist, nameOwner, "")
+
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_RENAME {
+			if idExist {
+				item.TargetID = xid.New().String()
+				plan.eventIDMap[model.EventModelID] = item.TargetID
+			}
+			if nameOwner != "" {
+				item.TargetName, err = mbs.findAvailableName(ctx, plan, item.AssetType, "", model.EventModelName,
+					interfaces.OBJECT_NAME_MAX_LENGTH, func(name string) (bool, error) {
+						modelMap, err := mbs.ema.GetEventModelMapByNames([]string{name})
+						if err != nil {
+							return false, rest.NewHTTPError(ctx, http.StatusInternalServerError, derrors.EventModel_InternalError).
+								WithErrorDetails(err.Error())
+						}
+						return len(modelMap) > 0, nil
+					})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_CONFLICT {
+			item.TargetID, item.TargetName = "", ""
+		}
+		plan.add(item, model.EventModelID)
+	}
+	return nil
+}
+
+func (mbs *modelBundleService) planObjectiveModels(ctx context.Context, bundle *interfaces.ModelBundle,
+	mode string, plan *importPlan) error {
+
+	for _, model := range bundle.ObjectiveModels {
+		plan.usedNames[usedNameKey(interfaces.MODEL_BUNDLE_ASSET_TYPE_OBJECTIVE_MODEL, "", model.ModelName)] = struct{}{}
+	}
+
+	for _, model := range bundle.ObjectiveModels {
+		_, idExist, err := mbs.oms.CheckObjectiveModelExistByID(ctx, model.ModelID)
+		if err != nil {
+			return err
+		}
+		nameOwner, nameExist, err := mbs.oms.CheckObjectiveModelExistByName(ctx, model.ModelName)
+		if err != nil {
+			return err
+		}
+		if !nameExist {
+			nameOwner = ""
+		}
+
+		item := &interfaces.ModelBundleImportItem{
+			AssetType:  interfaces.MODEL_BUNDLE_ASSET_TYPE_OBJECTIVE_MODEL,
+			ID:         model.ModelID,
+			Name:       model.ModelName,
+			TargetID:   model.ModelID,
+			TargetName: model.ModelName,
+		}
+		item.Action, item.Conflicts = decideAction(mode, model.ModelID, idExist, nameOwner, "")
+
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_RENAME {
+			if idExist {
+				item.TargetID = xid.New().String()
+			}
+			if nameExist {
+				item.TargetName, err = mbs.findAvailableName(ctx, plan, item.AssetType, "", model.ModelName,
+					interfaces.OBJECT_NAME_MAX_LENGTH, func(name string) (bool, error) {
+						_, exist, err := mbs.oms.CheckObjectiveModelExistByName(ctx, name)
+						return exist, err
+					})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_CONFLICT {
+			item.TargetID, item.TargetName = "", ""
+		}
+		plan.add(item, model.ModelID)
+	}
+	return nil
+}
+
+// 已导入的对象, 新建的对象 previous 为空, 覆盖的对象 previous 为覆盖前的对象
+type appliedAsset struct {
+	assetType string
+	id        string
+	previous  any
+}
+
+// 按依赖顺序逐个导入, 被依赖的对象先创建. 返回已导入的对象, 出错时由调用方回滚
+func (mbs *modelBundleService) importAssets(ctx context.Context, bundle *interfaces.ModelBundle,
+	plan *importPlan) ([]appliedAsset, error) {
+
+	applied := make([]appliedAsset, 0, len(plan.items))
+
+	for _, group := range bundle.DataViewGroups {
+		item := plan.item(interfaces.MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW_GROUP, group.GroupName)
+		if item.Action != interfaces.MODEL_BUNDLE_ACTION_CREATE {
+			continue
+		}
+		groupID, err := mbs.dvgs.CreateDataViewGroup(ctx, nil, &interfaces.DataViewGroup{
+			GroupName: group.GroupName,
+			Builtin:   group.Builtin,
+		})
+		if err != nil {
+			return applied, err
+		}
+		item.TargetID = groupID
+		applied = append(applied, appliedAsset{assetType: item.AssetType, id: groupID})
+	}
+
+	for _, view := range bundle.DataViews {
+		item := plan.item(interfaces.MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW, view.ViewID)
+		var previous any
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_UPDATE {
+			views, err := mbs.dvs.GetDataViews(ctx, []string{item.TargetID}, false)
+			if err != nil {
+				return applied, err
+			}
+			if len(views) > 0 {
+				previous = views[0]
+			}
+		}
+
+		view.ViewID = item.TargetID
+		view.ViewName = item.TargetName
+		// 分组在当前环境中按名称匹配
+		view.GroupID = ""
+
+		_, err := mbs.dvs.CreateDataViews(ctx, []*interfaces.DataView{view}, serviceImportMode(item.Action), true)
+		if err != nil {
+			return applied, err
+		}
+		applied = append(applied, appliedAsset{assetType: item.AssetType, id: item.TargetID, previous: previous})
+	}
+
+	for _, group := range bundle.MetricModelGroups {
+		item := plan.item(interfaces.MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL_GROUP, group.GroupName)
+		if item.Action != interfaces.MODEL_BUNDLE_ACTION_CREATE {
+			continue
+		}
+		groupID, err := mbs.mmgs.CreateMetricModelGroup(ctx, interfaces.MetricModelGroup{
+			GroupName: group.GroupName,
+			Comment:   group.Comment,
+		})
+		if err != nil {
+			return applied, err
+		}
+		item.TargetID = groupID
+		applied = append(applied, appliedAsset{assetType: item.AssetType, id: groupID})
+	}
+
+	for _, model := range bundle.MetricModels {
+		item := plan.item(interfaces.MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL, model.ModelID)
+		var previous any
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_UPDATE {
+			models, err := mbs.mms.GetMetricModels(ctx, []string{item.TargetID}, false)
+			if err != nil {
+				return applied, err
+			}
+			if len(models) > 0 {
+				previous = &models[0].MetricModel
+			}
+		}
+
+		if _, ok := plan.resetMeasureNames[model.ModelID]; ok {
+			model.MeasureName = ""
+		}
+		model.ModelID = item.TargetID
+		model.ModelName = item.TargetName
+		model.GroupID = ""
+
+		_, err := mbs.mms.CreateMetricModels(ctx, []*interfaces.MetricModel{model}, serviceImportMode(item.Action))
+		if err != nil {
+			return applied, err
+		}
+		applied = append(applied, appliedAsset{assetType: item.AssetType, id: item.TargetID, previous: previous})
+	}
+
+	for _, model := range bundle.EventModels {
+		item := plan.item(interfaces.MODEL_BUNDLE_ASSET_TYPE_EVENT_MODEL, model.EventModelID)
+		var previous any
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_UPDATE {
+			prevModel, httpErr := mbs.ems.GetEventModelByID(ctx, item.TargetID)
+			if httpErr != nil {
+				return applied, httpErr
+			}
+			previous = &prevModel
+		}
+
+		err := mbs.importEventModel(ctx, model, item)
+		if err != nil {
+			return applied, err
+		}
+		applied = append(applied, appliedAsset{assetType: item.AssetType, id: item.TargetID, previous: previous})
+	}
+
+	for _, model := range bundle.ObjectiveModels {
+		item := plan.item(interfaces.MODEL_BUNDLE_ASSET_TYPE_OBJECTIVE_MODEL, model.ModelID)
+		var previous any
+		if item.Action == interfaces.MODEL_BUNDLE_ACTION_UPDATE {
+			models, err := mbs.oms.GetObjectiveModels(ctx, []string{item.TargetID})
+			if err != nil {
+				return applied, err
+			}
+			if len(models) > 0 {
+				previous = &models[0]
+			}
+		}
+
+		model.ModelID = item.TargetID
+		model.ModelName = item.TargetName
+
+		_, err := mbs.oms.CreateObjectiveModels(ctx, []*interfaces.ObjectiveModel{model}, serviceImportMode(item.Action))
+		if err != nil {
+			return applied, err
+		}
+		applied = append(applied, appliedAsset{assetType: item.AssetType, id: item.TargetID, previous: previous})
+	}
+
+	return applied, nil
+}
+
+// 按导入的相反顺序回滚: 新建的对象删除, 覆盖的对象恢复为覆盖前的对象. 回滚失败只记录日志, 不覆盖导入的错误
+func (mbs *modelBundleService) rollbackAssets(ctx context.Context, applied []appliedAsset) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		asset := applied[i]
+
+		var err error
+		if asset.previous == nil {
+			err = mbs.deleteAsset(ctx, asset)
+		} else {
+			err = mbs.restoreAsset(ctx, asset)
+		}
+		if err != nil {
+			logger.Errorf("Rollback model bundle import of %s[%s] failed: %v", asset.assetType, asset.id, err)
+		}
+	}
+}
+
+func (mbs *modelBundleService) deleteAsset(ctx context.Context, asset appliedAsset) error {
+	var err error
+	switch asset.assetType {
+	case interfaces.MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW_GROUP:
+		_, err = mbs.dvgs.DeleteDataViewGroup(ctx, asset.id, false)
+	case interfaces.MODEL_BUNDLE_ASSET_TYPE_DATA_VIEW:
+		err = mbs.dvs.DeleteDataViews(ctx, []string{asset.id})
+	case interfaces.MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL_GROUP:
+		_, _, err = mbs.mmgs.DeleteMetricModelGroup(ctx, asset.id, false)
+	case interfaces.MODEL_BUNDLE_ASSET_TYPE_METRIC_MODEL:
+		_, err = mbs.mms.DeleteMetricModels(ctx, nil, []string{asset.id})
+	case interfaces.MODEL_BUNDLE_ASSET_TYPE_EVENT_MODEL:
+		_, err = mbs.ems.DeleteEventModels(ctx, []string{asset.id})
+	case interfaces.MODEL_BUNDLE_ASSET_TYPE_OBJECTIVE_MODEL:
+		_, err = mbs.oms.DeleteObjectiveModels(ctx, []string{asset.id})
+	}
+	return err
+}
+
+func (mbs *modelBundleService) restoreAsset(ctx context.Context, asset appliedAsset) error {
+	var err error
+	switch previous := asset.previous.(type) {
+	case *interfaces.DataView:
+		_, err = mbs.dvs.CreateDataViews(ctx, []*interfaces.DataView{previous}, interfaces.ImportMode_Overwrite, false)
+	case *interfaces.MetricModel:
+		_, err = mbs.mms.CreateMetricModels(ctx, []*interfaces.MetricModel{previous}, interfaces.ImportMode_Overwrite)
+	case *interfaces.EventModel:
+		err = mbs.importEventModel(ctx, previous, &interfaces.ModelBundleImportItem{
+			AssetType:  asset.assetType,
+			Action:     interfaces.MODEL_BUNDLE_ACTION_UPDATE,
+			TargetID:   previous.EventModelID,
+			TargetName: previous.EventModelName,
+		})
+	case *interfaces.ObjectiveModel:
+		_, err = mbs.oms.CreateObjectiveModels(ctx, []*interfaces.ObjectiveModel{previous}, interfaces.ImportMode_Overwrite)
+	}
+	return err
+}
+
+// 事件模型服务没有导入模式, 覆盖时走修改流程, 创建时与创建接口一样补充规则 id 和任务信息
+func (mbs *modelBundleService) importEventModel(ctx context.Context, model *interfaces.EventModel,
+	item *interfaces.ModelBundleImportItem) error {
+
+	if item.Action == interfaces.MODEL_BUNDLE_ACTION_UPDATE {
+		req := interfaces.EventModelUpateRequest{
+			EventModelRequest: interfaces.EventModelRequest{
+				EventModelName:      model.EventModelName,
+				EventModelType:      model.EventModelType,
+				EventModelTags:      model.EventModelTags,
+				DataSourceType:      model.DataSourceType,
+				DataSource:          model.DataSource,
+				DataSourceName:      model.DataSourceName,
+				DataSourceGroupName: model.DataSourceGroupName,
+				DetectRule:          model.DetectRule,
+				AggregateRule:       model.AggregateRule,
+				EventModelComment:   model.EventModelComment,
+				DefaultTimeWindow:   model.DefaultTimeWindow,
+				EventTaskRequest: interfaces.EventTaskRequest{
+					Schedule:         model.Task.Schedule,
+					ExecuteParameter: model.Task.ExecuteParameter,
+					StorageConfig:    model.Task.StorageConfig,
+					DispatchConfig:   model.Task.DispatchConfig,
+				},
+				DownstreamDependentModel: model.DownstreamDependentModel,
+			},
+			EventModelID:    item.TargetID,
+			IsActive:        model.IsActive,
+			EnableSubscribe: model.EnableSubscribe,
+			Status:          model.Status,
+		}
+
+		if httpErr := mbs.ems.EventModelUpdateValidate(ctx, req); httpErr != nil {
+			return httpErr
+		}
+		return mbs.ems.UpdateEventModel(ctx, req)
+	}
+
+	accountInfo := interfaces.AccountInfo{}
+	if ctx.Value(interfaces.ACCOUNT_INFO_KEY) != nil {
+		accountInfo = ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo)
+	}
+
+	// 下游依赖的事件模型已先导入, 按模型 id 获取其任务 id
+	downstreamTasks := make([]string, 0, len(model.DownstreamDependentModel))
+	for _, modelID := range model.DownstreamDependentModel {
+		task, _, err := mbs.ems.GetEventTaskByModelID(ctx, modelID)
+		if err != nil {
+			logger.Errorf("Find task id failed due to %v by event model id: %s", err, modelID)
+			continue
+		}
+		downstreamTasks = append(downstreamTasks, task.TaskID)
+	}
+
+	now := time.Now().UnixMilli()
+	em := *model
+	em.EventModelID = item.TargetID
+	em.EventModelName = item.TargetName
+	ruleID := xid.New().String()
+	if em.EventModelType == interfaces.EVENT_MODEL_TYPE_ATOMIC {
+		em.DetectRule.DetectRuleID = ruleID
+	} else {
+		em.AggregateRule.AggregateRuleID = ruleID
+	}
+	em.Task = interfaces.EventTask{
+		Schedule:                model.Task.Schedule,
+		ExecuteParameter:        model.Task.ExecuteParameter,
+		StorageConfig:           model.Task.StorageConfig,
+		DispatchConfig:          model.Task.DispatchConfig,
+		DownstreamDependentTask: downstreamTasks,
+		Creator:                 accountInfo,
+	}
+	if em.Task.StorageConfig.DataViewID == "" {
+		em.Task.StorageConfig.DataViewID = fmt.Sprintf("__%s", em.Task.StorageConfig.IndexBase)
+	}
+	em.IsCustom = 1
+	em.Creator = accountInfo
+	em.CreateTime = now
+	em.UpdateTime = now
+	em.DetectRule.CreateTime = now
+	em.DetectRule.UpdateTime = now
+	em.AggregateRule.CreateTime = now
+	em.AggregateRule.UpdateTime = now
+
+	validModel, httpErr := mbs.ems.EventModelCreateValidate(ctx, em)
+	if httpErr != nil {
+		return httpErr
+	}
+	em.DataSource = validModel.DataSource
+	em.IsActive = validModel.IsActive
+	em.Status = validModel.Status
+
+	_, err := mbs.ems.CreateEventModels(ctx, []interfaces.EventModel{em})
+	return err
+}
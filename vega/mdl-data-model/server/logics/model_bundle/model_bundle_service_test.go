@@ -0,0 +1,220 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package model_bundle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+	dmock "data-model/interfaces/mock"
+)
+
+var (
+	testCtx = context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+)
+
+func MockNewModelBundleService(appSetting *common.AppSetting,
+	dsa interfaces.DataSourceAccess,
+	dvs interfaces.DataViewService,
+	dvgs interfaces.DataViewGroupService) *modelBundleService {
+
+	return &modelBundleService{
+		appSetting: appSetting,
+		dsa:        dsa,
+		dvs:        dvs,
+		dvgs:       dvgs,
+	}
+}
+
+func Test_ModelBundleService_ImportModelBundle(t *testing.T) {
+	Convey("Test ImportModelBundle", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		dsa := dmock.NewMockDataSourceAccess(mockCtrl)
+		dvs := dmock.NewMockDataViewService(mockCtrl)
+		dvgs := dmock.NewMockDataViewGroupService(mockCtrl)
+		mbs := MockNewModelBundleService(appSetting, dsa, dvs, dvgs)
+
+		newBundle := func() *interfaces.ModelBundle {
+			return &interfaces.ModelBundle{
+				Version: interfaces.MODEL_BUNDLE_VERSION,
+				DataViewGroups: []*interfaces.DataViewGroup{
+					{GroupID: "g1", GroupName: "group1"},
+				},
+				DataViews: []*interfaces.DataView{
+					{SimpleDataView: interfaces.SimpleDataView{
+						ViewID:         "v1",
+						ViewName:       "view1",
+						GroupName:      "group1",
+						Type:           interfaces.ViewType_Atomic,
+						DataSourceID:   "ds1",
+						DataSourceName: "mysql1",
+						DataSourceType: "mysql",
+					}},
+				},
+			}
+		}
+
+		Convey("Failed when list data sources failed", func() {
+			dsa.EXPECT().ListDataSources(gomock.Any()).Return(nil, errors.New("error"))
+
+			result, err := mbs.ImportModelBundle(testCtx, newBundle(), interfaces.MODEL_BUNDLE_IMPORT_MODE_CREATE_ONLY, true)
+			So(result, ShouldBeNil)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusInternalServerError)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelBundle_InternalError_GetDataSourcesFailed)
+		})
+
+		Convey("Dry run reports conflicts and missing data sources", func() {
+			dsa.EXPECT().ListDataSources(gomock.Any()).Return(&interfaces.ListDataSourcesResult{}, nil)
+			dvgs.EXPECT().CheckDataViewGroupExistByName(gomock.Any(), gomock.Any(), "group1", false).
+				Return(&interfaces.DataViewGroup{GroupID: "g2", GroupName: "group1"}, true, nil)
+			dvs.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("view1", true, nil)
+			dvs.EXPECT().CheckDataViewExistByName(gomock.Any(), gomock.Any(), "view1", "group1").Return("v2", true, nil)
+
+			result, err := mbs.ImportModelBundle(testCtx, newBundle(), interfaces.MODEL_BUNDLE_IMPORT_MODE_OVERWRITE, true)
+			So(err, ShouldBeNil)
+			So(result.DryRun, ShouldBeTrue)
+			So(result.MissingDataSources, ShouldResemble, []*interfaces.ModelBundleDataSource{
+				{ID: "ds1", Name: "mysql1", Type: "mysql"},
+			})
+			So(len(result.Items), ShouldEqual, 2)
+			So(result.Items[0].Action, ShouldEqual, interfaces.MODEL_BUNDLE_ACTION_EXIST)
+			So(result.Items[0].TargetID, ShouldEqual, "g2")
+			So(result.Items[1].Action, ShouldEqual, interfaces.MODEL_BUNDLE_ACTION_CONFLICT)
+			So(result.Items[1].Conflicts, ShouldResemble, []string{interfaces.MODEL_BUNDLE_CONFLICT_ID, interfaces.MODEL_BUNDLE_CONFLICT_NAME})
+		})
+
+		Convey("Dry run in rename mode plans new id and name", func() {
+			dsa.EXPECT().ListDataSources(gomock.Any()).Return(&interfaces.ListDataSourcesResult{
+				Entries: []*interfaces.DataSource{{ID: "ds1"}},
+			}, nil)
+			dvgs.EXPECT().CheckDataViewGroupExistByName(gomock.Any(), gomock.Any(), "group1", false).
+				Return(nil, false, nil)
+			dvs.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("view1", true, nil)
+			dvs.EXPECT().CheckDataViewExistByName(gomock.Any(), gomock.Any(), "view1", "group1").Return("v1", true, nil)
+			dvs.EXPECT().CheckDataViewExistByName(gomock.Any(), gomock.Any(), "view1_imported", "group1").Return("v3", true, nil)
+			dvs.EXPECT().CheckDataViewExistByName(gomock.Any(), gomock.Any(), "view1_imported_2", "group1").Return("", false, nil)
+
+			result, err := mbs.ImportModelBundle(testCtx, newBundle(), interfaces.MODEL_BUNDLE_IMPORT_MODE_RENAME, true)
+			So(err, ShouldBeNil)
+			So(len(result.MissingDataSources), ShouldEqual, 0)
+			So(result.Items[0].Action, ShouldEqual, interfaces.MODEL_BUNDLE_ACTION_CREATE)
+			So(result.Items[1].Action, ShouldEqual, interfaces.MODEL_BUNDLE_ACTION_RENAME)
+			So(result.Items[1].TargetID, ShouldNotEqual, "v1")
+			So(result.Items[1].TargetName, ShouldEqual, "view1_imported_2")
+		})
+
+		Convey("Failed when data sources are missing", func() {
+			dsa.EXPECT().ListDataSources(gomock.Any()).Return(&interfaces.ListDataSourcesResult{}, nil)
+			dvgs.EXPECT().CheckDataViewGroupExistByName(gomock.Any(), gomock.Any(), "group1", false).
+				Return(nil, false, nil)
+			dvs.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("", false, nil)
+			dvs.EXPECT().CheckDataViewExistByName(gomock.Any(), gomock.Any(), "view1", "group1").Return("", false, nil)
+
+			result, err := mbs.ImportModelBundle(testCtx, newBundle(), interfaces.MODEL_BUNDLE_IMPORT_MODE_CREATE_ONLY, false)
+			So(result, ShouldBeNil)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusForbidden)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelBundle_DataSourceNotFound)
+		})
+
+		Convey("Failed when import conflicts", func() {
+			dsa.EXPECT().ListDataSources(gomock.Any()).Return(&interfaces.ListDataSourcesResult{
+				Entries: []*interfaces.DataSource{{ID: "ds1"}},
+			}, nil)
+			dvgs.EXPECT().CheckDataViewGroupExistByName(gomock.Any(), gomock.Any(), "group1", false).
+				Return(nil, false, nil)
+			dvs.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("view1", true, nil)
+			dvs.EXPECT().CheckDataViewExistByName(gomock.Any(), gomock.Any(), "view1", "group1").Return("v1", true, nil)
+
+			result, err := mbs.ImportModelBundle(testCtx, newBundle(), interfaces.MODEL_BUNDLE_IMPORT_MODE_CREATE_ONLY, false)
+			So(result, ShouldBeNil)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusForbidden)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelBundle_ImportConflict)
+		})
+
+		Convey("Roll back imported assets when a later asset fails", func() {
+			bundle := newBundle()
+			bundle.DataViews = append(bundle.DataViews, &interfaces.DataView{SimpleDataView: interfaces.SimpleDataView{
+				ViewID:         "v2",
+				ViewName:       "view2",
+				GroupName:      "group1",
+				Type:           interfaces.ViewType_Atomic,
+				DataSourceID:   "ds1",
+				DataSourceName: "mysql1",
+				DataSourceType: "mysql",
+			}})
+			previousView := &interfaces.DataView{SimpleDataView: interfaces.SimpleDataView{ViewID: "v1", ViewName: "view1"}}
+			createErr := rest.NewHTTPError(testCtx, http.StatusInternalServerError, derrors.DataModel_DataView_InternalError_CreateDataViewsFailed)
+
+			dsa.EXPECT().ListDataSources(gomock.Any()).Return(&interfaces.ListDataSourcesResult{
+				Entries: []*interfaces.DataSource{{ID: "ds1"}},
+			}, nil)
+			dvgs.EXPECT().CheckDataViewGroupExistByName(gomock.Any(), gomock.Any(), "group1", false).
+				Return(nil, false, nil)
+			dvs.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("view1", true, nil)
+			dvs.EXPECT().CheckDataViewExistByName(gomock.Any(), gomock.Any(), "view1", "group1").Return("v1", true, nil)
+			dvs.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v2").Return("", false, nil)
+			dvs.EXPECT().CheckDataViewExistByName(gomock.Any(), gomock.Any(), "view2", "group1").Return("", false, nil)
+
+			gomock.InOrder(
+				dvgs.EXPECT().CreateDataViewGroup(gomock.Any(), gomock.Any(), gomock.Any()).Return("g2", nil),
+				dvs.EXPECT().GetDataViews(gomock.Any(), []string{"v1"}, false).Return([]*interfaces.DataView{previousView}, nil),
+				dvs.EXPECT().CreateDataViews(gomock.Any(), gomock.Any(), interfaces.ImportMode_Overwrite, true).Return([]string{"v1"}, nil),
+				dvs.EXPECT().CreateDataViews(gomock.Any(), gomock.Any(), interfaces.ImportMode_Normal, true).Return(nil, createErr),
+				// 回滚: 覆盖的视图恢复为覆盖前的视图, 新建的分组删除
+				dvs.EXPECT().CreateDataViews(gomock.Any(), []*interfaces.DataView{previousView}, interfaces.ImportMode_Overwrite, false).
+					Return([]string{"v1"}, nil),
+				dvgs.EXPECT().DeleteDataViewGroup(gomock.Any(), "g2", false).Return(nil, nil),
+			)
+
+			result, err := mbs.ImportModelBundle(testCtx, bundle, interfaces.MODEL_BUNDLE_IMPORT_MODE_OVERWRITE, false)
+			So(result, ShouldBeNil)
+			So(err, ShouldEqual, createErr)
+		})
+
+		Convey("Delete created assets even if rollback of one asset fails", func() {
+			dsa.EXPECT().ListDataSources(gomock.Any()).Return(&interfaces.ListDataSourcesResult{
+				Entries: []*interfaces.DataSource{{ID: "ds1"}},
+			}, nil)
+			dvgs.EXPECT().CheckDataViewGroupExistByName(gomock.Any(), gomock.Any(), "group1", false).
+				Return(nil, false, nil)
+			dvs.EXPECT().CheckDataViewExistByID(gomock.Any(), gomock.Any(), "v1").Return("", false, nil)
+			dvs.EXPECT().CheckDataViewExistByName(gomock.Any(), gomock.Any(), "view1", "group1").Return("", false, nil)
+
+			mms := dmock.NewMockMetricModelService(mockCtrl)
+			mbs.mms = mms
+			bundle := newBundle()
+			bundle.MetricModels = []*interfaces.MetricModel{
+				{SimpleMetricModel: interfaces.SimpleMetricModel{ModelID: "m1", ModelName: "metric1", MetricType: interfaces.ATOMIC_METRIC}},
+			}
+			mms.EXPECT().CheckMetricModelExistByID(gomock.Any(), "m1").Return("", false, nil)
+			mms.EXPECT().CheckMetricModelExistByName(gomock.Any(), "", "metric1").Return("", false, nil)
+
+			gomock.InOrder(
+				dvgs.EXPECT().CreateDataViewGroup(gomock.Any(), gomock.Any(), gomock.Any()).Return("g2", nil),
+				dvs.EXPECT().CreateDataViews(gomock.Any(), gomock.Any(), interfaces.ImportMode_Normal, true).Return([]string{"v1"}, nil),
+				mms.EXPECT().CreateMetricModels(gomock.Any(), gomock.Any(), interfaces.ImportMode_Normal).Return(nil, errors.New("error")),
+				dvs.EXPECT().DeleteDataViews(gomock.Any(), []string{"v1"}).Return(errors.New("error")),
+				dvgs.EXPECT().DeleteDataViewGroup(gomock.Any(), "g2", false).Return(nil, nil),
+			)
+
+			result, err := mbs.ImportModelBundle(testCtx, bundle, interfaces.MODEL_BUNDLE_IMPORT_MODE_CREATE_ONLY, false)
+			So(result, ShouldBeNil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,192 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package model_bundle
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/interfaces"
+)
+
+func Test_ModelBundle_SortByDepends(t *testing.T) {
+	Convey("Test sortByDepends", t, func() {
+		Convey("Depended ids come first", func() {
+			ids := []string{"c", "b", "a"}
+			depends := map[string][]string{
+				"a": {"c"},
+				"c": {"b"},
+			}
+			So(sortByDepends(ids, depends), ShouldResemble, []string{"b", "c", "a"})
+		})
+
+		Convey("Depends not in ids are ignored", func() {
+			ids := []string{"b", "a"}
+			depends := map[string][]string{
+				"a": {"x"},
+			}
+			So(sortByDepends(ids, depends), ShouldResemble, []string{"a", "b"})
+		})
+
+		Convey("Cyclic depends do not loop", func() {
+			ids := []string{"a", "b"}
+			depends := map[string][]string{
+				"a": {"b"},
+				"b": {"a"},
+			}
+			So(sortByDepends(ids, depends), ShouldResemble, []string{"b", "a"})
+		})
+	})
+}
+
+func Test_ModelBundle_SortBundle(t *testing.T) {
+	Convey("Test sortBundle", t, func() {
+		bundle := &interfaces.ModelBundle{
+			DataViews: []*interfaces.DataView{
+				{
+					SimpleDataView: interfaces.SimpleDataView{ViewID: "v1", Type: interfaces.ViewType_Custom},
+					DataScope: []*interfaces.DataScopeNode{
+						{Type: interfaces.DataScopeNodeType_View, Config: map[string]any{"view_id": "v2"}},
+					},
+				},
+				{SimpleDataView: interfaces.SimpleDataView{ViewID: "v2", Type: interfaces.ViewType_Atomic}},
+			},
+			MetricModels: []*interfaces.MetricModel{
+				{SimpleMetricModel: interfaces.SimpleMetricModel{ModelID: "m1",
+					MetricType: interfaces.COMPOSITED_METRIC, Formula: "{{m2}} / {{ m3 }}"}},
+				{SimpleMetricModel: interfaces.SimpleMetricModel{ModelID: "m2", MetricType: interfaces.ATOMIC_METRIC}},
+				{SimpleMetricModel: interfaces.SimpleMetricModel{ModelID: "m3", MetricType: interfaces.ATOMIC_METRIC}},
+			},
+		}
+
+		sortBundle(bundle)
+		So(bundle.DataViews[0].ViewID, ShouldEqual, "v2")
+		So(bundle.DataViews[1].ViewID, ShouldEqual, "v1")
+		So(bundle.MetricModels[0].ModelID, ShouldEqual, "m2")
+		So(bundle.MetricModels[1].ModelID, ShouldEqual, "m3")
+		So(bundle.MetricModels[2].ModelID, ShouldEqual, "m1")
+	})
+}
+
+func Test_ModelBundle_RewriteBundleReferences(t *testing.T) {
+	Convey("Test rewriteBundleReferences", t, func() {
+		bundle := &interfaces.ModelBundle{
+			DataViews: []*interfaces.DataView{
+				{
+					SimpleDataView: interfaces.SimpleDataView{ViewID: "v2", Type: interfaces.ViewType_Custom},
+					DataScope: []*interfaces.DataScopeNode{
+						{Type: interfaces.DataScopeNodeType_View, Config: map[string]any{"view_id": "v1"}},
+					},
+				},
+			},
+			MetricModels: []*interfaces.MetricModel{
+				{
+					SimpleMetricModel: interfaces.SimpleMetricModel{ModelID: "m1",
+						MetricType: interfaces.ATOMIC_METRIC, DataViewID: "v1"},
+					DataSource: &interfaces.MetricDataSource{ID: "v1"},
+				},
+				{SimpleMetricModel: interfaces.SimpleMetricModel{ModelID: "m2",
+					MetricType: interfaces.DERIVED_METRIC,
+					FormulaConfig: interfaces.DerivedConfig{
+						DependMetricModel: &interfaces.DependMetricModel{ID: "m1"},
+					}}},
+				{SimpleMetricModel: interfaces.SimpleMetricModel{ModelID: "m3",
+					MetricType: interfaces.COMPOSITED_METRIC, Formula: "{{ m1 }} + {{m9}}"}},
+			},
+			EventModels: []*interfaces.EventModel{
+				{
+					EventModelID:             "e1",
+					DataSourceType:           interfaces.EVENT_MODEL_DATA_SOURCE_TYPE_FOR_METRIC_MODEL,
+					DataSource:               []string{"m1"},
+					DownstreamDependentModel: []string{"e2"},
+				},
+			},
+			ObjectiveModels: []*interfaces.ObjectiveModel{
+				{
+					ObjectiveModelInfo: interfaces.ObjectiveModelInfo{
+						ModelID: "o1",
+						ObjectiveConfig: interfaces.SLOObjective{
+							GoodMetricModel:  &interfaces.BundleMetricModel{ID: "m1"},
+							TotalMetricModel: &interfaces.BundleMetricModel{ID: "m9"},
+						},
+					},
+				},
+			},
+		}
+		viewIDMap := map[string]string{"v1": "nv1"}
+		metricIDMap := map[string]string{"m1": "nm1"}
+		eventIDMap := map[string]string{"e2": "ne2"}
+
+		rewriteBundleReferences(bundle, viewIDMap, metricIDMap, eventIDMap)
+
+		So(bundle.DataViews[0].DataScope[0].Config["view_id"], ShouldEqual, "nv1")
+		So(bundle.MetricModels[0].DataViewID, ShouldEqual, "nv1")
+		So(bundle.MetricModels[0].DataSource.ID, ShouldEqual, "nv1")
+		So(bundle.MetricModels[1].FormulaConfig.(interfaces.DerivedConfig).DependMetricModel.ID, ShouldEqual, "nm1")
+		So(bundle.MetricModels[2].Formula, ShouldEqual, "{{nm1}} + {{m9}}")
+		So(bundle.EventModels[0].DataSource, ShouldResemble, []string{"nm1"})
+		So(bundle.EventModels[0].DownstreamDependentModel, ShouldResemble, []string{"ne2"})
+
+		slo := bundle.ObjectiveModels[0].ObjectiveConfig.(interfaces.SLOObjective)
+		So(slo.GoodMetricModel.ID, ShouldEqual, "nm1")
+		So(slo.TotalMetricModel.ID, ShouldEqual, "m9")
+	})
+}
+
+func Test_ModelBundle_RenameCandidate(t *testing.T) {
+	Convey("Test renameCandidate", t, func() {
+		Convey("First candidate only appends suffix", func() {
+			So(renameCandidate("cpu", 1, 40), ShouldEqual, "cpu_imported")
+		})
+
+		Convey("Later candidates append index", func() {
+			So(renameCandidate("cpu", 3, 40), ShouldEqual, "cpu_imported_3")
+		})
+
+		Convey("Name is truncated by runes when too long", func() {
+			So(renameCandidate("指标模型名称", 2, 14), ShouldEqual, "指标模_imported_2")
+		})
+	})
+}
+
+func Test_ModelBundle_DecideAction(t *testing.T) {
+	Convey("Test decideAction", t, func() {
+		Convey("No conflict creates", func() {
+			action, conflicts := decideAction(interfaces.MODEL_BUNDLE_IMPORT_MODE_CREATE_ONLY, "m1", false, "", "")
+			So(action, ShouldEqual, interfaces.MODEL_BUNDLE_ACTION_CREATE)
+			So(conflicts, ShouldBeNil)
+		})
+
+		Convey("Create only reports conflicts", func() {
+			action, conflicts := decideAction(interfaces.MODEL_BUNDLE_IMPORT_MODE_CREATE_ONLY, "m1", true, "m1", "")
+			So(action, ShouldEqual, interfaces.MODEL_BUNDLE_ACTION_CONFLICT)
+			So(conflicts, ShouldResemble, []string{interfaces.MODEL_BUNDLE_CONFLICT_ID, interfaces.MODEL_BUNDLE_CONFLICT_NAME})
+		})
+
+		Convey("Overwrite updates when conflicts belong to the same id", func() {
+			action, _ := decideAction(interfaces.MODEL_BUNDLE_IMPORT_MODE_OVERWRITE, "m1", true, "m1", "m1")
+			So(action, ShouldEqual, interfaces.MODEL_BUNDLE_ACTION_UPDATE)
+		})
+
+		Convey("Overwrite conflicts when name belongs to another id", func() {
+			action, conflicts := decideAction(interfaces.MODEL_BUNDLE_IMPORT_MODE_OVERWRITE, "m1", true, "m2", "")
+			So(action, ShouldEqual, interfaces.MODEL_BUNDLE_ACTION_CONFLICT)
+			So(conflicts, ShouldResemble, []string{interfaces.MODEL_BUNDLE_CONFLICT_ID, interfaces.MODEL_BUNDLE_CONFLICT_NAME})
+		})
+
+		Convey("Overwrite conflicts when id does not exist", func() {
+			action, _ := decideAction(interfaces.MODEL_BUNDLE_IMPORT_MODE_OVERWRITE, "m1", false, "m2", "")
+			So(action, ShouldEqual, interfaces.MODEL_BUNDLE_ACTION_CONFLICT)
+		})
+
+		Convey("Rename renames on any conflict", func() {
+			action, conflicts := decideAction(interfaces.MODEL_BUNDLE_IMPORT_MODE_RENAME, "m1", false, "", "m2")
+			So(action, ShouldEqual, interfaces.MODEL_BUNDLE_ACTION_RENAME)
+			So(conflicts, ShouldResemble, []string{interfaces.MODEL_BUNDLE_CONFLICT_MEASURE_NAME})
+		})
+	})
+}
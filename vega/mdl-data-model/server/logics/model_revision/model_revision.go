@@ -0,0 +1,179 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package model_revision
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/bytedance/sonic"
+
+	"data-model/interfaces"
+)
+
+var (
+	// 每次保存都会变化的字段, 对比版本时忽略
+	revisionIgnoredPaths = map[string]struct{}{
+		"create_time": {},
+		"creator":     {},
+		"update_time": {},
+		"updater":     {},
+	}
+)
+
+// 按模型类型反序列化版本快照. FormulaConfig 和 ObjectiveConfig 的具体类型与数据库读取时保持一致
+func decodeRevisionModel(modelType string, content string) (any, error) {
+	switch modelType {
+	case interfaces.REVISION_MODEL_TYPE_METRIC_MODEL:
+		model := &interfaces.MetricModel{}
+		err := sonic.UnmarshalString(content, model)
+		if err != nil {
+			return nil, err
+		}
+		if model.FormulaConfig == nil {
+			return model, nil
+		}
+
+		configBytes, err := sonic.Marshal(model.FormulaConfig)
+		if err != nil {
+			return nil, err
+		}
+		if model.QueryType == interfaces.SQL {
+			var sqlConfig interfaces.SQLConfig
+			err = sonic.Unmarshal(configBytes, &sqlConfig)
+			if err != nil {
+				return nil, err
+			}
+			model.FormulaConfig = sqlConfig
+		}
+		if model.MetricType == interfaces.DERIVED_METRIC {
+			var derivedConfig interfaces.DerivedConfig
+			err = sonic.Unmarshal(configBytes, &derivedConfig)
+			if err != nil {
+				return nil, err
+			}
+			model.FormulaConfig = derivedConfig
+		}
+		return model, nil
+
+	case interfaces.REVISION_MODEL_TYPE_DATA_VIEW:
+		view := &interfaces.DataView{}
+		err := sonic.UnmarshalString(content, view)
+		if err != nil {
+			return nil, err
+		}
+		return view, nil
+
+	case interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL:
+		model := &interfaces.ObjectiveModel{}
+		err := sonic.UnmarshalString(content, model)
+		if err != nil {
+			return nil, err
+		}
+
+		configBytes, err := sonic.Marshal(model.ObjectiveConfig)
+		if err != nil {
+			return nil, err
+		}
+		if model.ObjectiveType == interfaces.SLO {
+			var sloObjective interfaces.SLOObjective
+			err = sonic.Unmarshal(configBytes, &sloObjective)
+			if err != nil {
+				return nil, err
+			}
+			model.ObjectiveConfig = sloObjective
+		} else {
+			var kpiObjective interfaces.KPIObjective
+			err = sonic.Unmarshal(configBytes, &kpiObjective)
+			if err != nil {
+				return nil, err
+			}
+			model.ObjectiveConfig = kpiObjective
+		}
+		return model, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported revision model type '%s'", modelType)
+	}
+}
+
+// 将两个版本的 json 展开为叶子路径后逐一比较, 结果按路径排序
+func diffRevisionContents(from any, to any) []*interfaces.RevisionChange {
+	fromValues := make(map[string]any)
+	flattenRevisionContent("", from, fromValues)
+	toValues := make(map[string]any)
+	flattenRevisionContent("", to, toValues)
+
+	paths := make([]string, 0, len(fromValues)+len(toValues))
+	for path := range fromValues {
+		paths = append(paths, path)
+	}
+	for path := range toValues {
+		if _, ok := fromValues[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	changes := make([]*interfaces.RevisionChange, 0)
+	for _, path := range paths {
+		oldValue, inFrom := fromValues[path]
+		newValue, inTo := toValues[path]
+		switch {
+		case !inFrom:
+			changes = append(changes, &interfaces.RevisionChange{
+				Path:     path,
+				Type:     interfaces.REVISION_CHANGE_ADDED,
+				NewValue: newValue,
+			})
+		case !inTo:
+			changes = append(changes, &interfaces.RevisionChange{
+				Path:     path,
+				Type:     interfaces.REVISION_CHANGE_REMOVED,
+				OldValue: oldValue,
+			})
+		case !reflect.DeepEqual(oldValue, newValue):
+			changes = append(changes, &interfaces.RevisionChange{
+				Path:     path,
+				Type:     interfaces.REVISION_CHANGE_MODIFIED,
+				OldValue: oldValue,
+				NewValue: newValue,
+			})
+		}
+	}
+	return changes
+}
+
+// 展开 json, 对象用 . 连接, 数组用 [i]. 空对象和空数组本身作为叶子, 以便识别从无到有的变化
+func flattenRevisionContent(path string, value any, values map[string]any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) == 0 && path != "" {
+			values[path] = v
+			return
+		}
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			} else if _, ok := revisionIgnoredPaths[key]; ok {
+				continue
+			}
+			flattenRevisionContent(childPath, child, values)
+		}
+	case []any:
+		if len(v) == 0 {
+			values[path] = v
+			return
+		}
+		for i, child := range v {
+			flattenRevisionContent(fmt.Sprintf("%s[%d]", path, i), child, values)
+		}
+	default:
+		values[path] = v
+	}
+}
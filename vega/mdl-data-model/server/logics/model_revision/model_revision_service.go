@@ -0,0 +1,254 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package model_revision
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+	"data-model/logics"
+	"data-model/logics/permission"
+)
+
+var (
+	mrServiceOnce sync.Once
+	mrService     interfaces.ModelRevisionService
+)
+
+type modelRevisionService struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+	mra        interfaces.ModelRevisionAccess
+	ps         interfaces.PermissionService
+}
+
+func NewModelRevisionService(appSetting *common.AppSetting) interfaces.ModelRevisionService {
+	mrServiceOnce.Do(func() {
+		mrService = &modelRevisionService{
+			appSetting: appSetting,
+			db:         logics.DB,
+			mra:        logics.MRA,
+			ps:         permission.NewPermissionService(appSetting),
+		}
+	})
+
+	return mrService
+}
+
+// 分页查询模型的版本列表
+func (mrs *modelRevisionService) ListModelRevisions(ctx context.Context,
+	query interfaces.ModelRevisionsQueryParams) ([]*interfaces.ModelRevision, int, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: List model revisions")
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("model_type").String(query.ModelType),
+		attr.Key("model_id").String(query.ModelID),
+	)
+
+	err := mrs.checkViewPermission(ctx, query.ModelType, query.ModelID)
+	if err != nil {
+		span.SetStatus(codes.Error, "Check permission failed")
+		return nil, 0, err
+	}
+
+	total, err := mrs.mra.GetModelRevisionsTotal(ctx, query)
+	if err != nil {
+		logger.Errorf("Get revisions total of %s '%s' error: %s", query.ModelType, query.ModelID, err.Error())
+		span.SetStatus(codes.Error, "Get model revisions total failed")
+
+		return nil, 0, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_ModelRevision_InternalError_GetRevisionFailed).WithErrorDetails(err.Error())
+	}
+
+	revisions, err := mrs.mra.ListModelRevisions(ctx, query)
+	if err != nil {
+		logger.Errorf("List revisions of %s '%s' error: %s", query.ModelType, query.ModelID, err.Error())
+		span.SetStatus(codes.Error, "List model revisions failed")
+
+		return nil, 0, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_ModelRevision_InternalError_GetRevisionFailed).WithErrorDetails(err.Error())
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return revisions, total, nil
+}
+
+// 获取版本详情, 模型定义按模型类型反序列化后放入 Model
+func (mrs *modelRevisionService) GetModelRevision(ctx context.Context, modelType string, modelID string,
+	revision int) (*interfaces.ModelRevision, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Get model revision")
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("model_type").String(modelType),
+		attr.Key("model_id").String(modelID),
+		attr.Key("revision").Int(revision),
+	)
+
+	err := mrs.checkViewPermission(ctx, modelType, modelID)
+	if err != nil {
+		span.SetStatus(codes.Error, "Check permission failed")
+		return nil, err
+	}
+
+	modelRevision, err := mrs.getModelRevision(ctx, modelType, modelID, revision)
+	if err != nil {
+		span.SetStatus(codes.Error, "Get model revision failed")
+		return nil, err
+	}
+
+	modelRevision.Model, err = decodeRevisionModel(modelType, modelRevision.Content)
+	if err != nil {
+		logger.Errorf("Decode revision %d of %s '%s' error: %s", revision, modelType, modelID, err.Error())
+		span.SetStatus(codes.Error, "Decode model revision failed")
+
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_ModelRevision_InternalError_DecodeRevisionFailed).WithErrorDetails(err.Error())
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return modelRevision, nil
+}
+
+// 对比两个版本的模型定义
+func (mrs *modelRevisionService) DiffModelRevisions(ctx context.Context, modelType string, modelID string,
+	fromRevision int, toRevision int) (*interfaces.ModelRevisionDiff, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: Diff model revisions")
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("model_type").String(modelType),
+		attr.Key("model_id").String(modelID),
+		attr.Key("from_revision").Int(fromRevision),
+		attr.Key("to_revision").Int(toRevision),
+	)
+
+	err := mrs.checkViewPermission(ctx, modelType, modelID)
+	if err != nil {
+		span.SetStatus(codes.Error, "Check permission failed")
+		return nil, err
+	}
+
+	contents := make([]any, 0, 2)
+	for _, revision := range []int{fromRevision, toRevision} {
+		modelRevision, err := mrs.getModelRevision(ctx, modelType, modelID, revision)
+		if err != nil {
+			span.SetStatus(codes.Error, "Get model revision failed")
+			return nil, err
+		}
+
+		var content any
+		err = sonic.UnmarshalString(modelRevision.Content, &content)
+		if err != nil {
+			logger.Errorf("Decode revision %d of %s '%s' error: %s", revision, modelType, modelID, err.Error())
+			span.SetStatus(codes.Error, "Decode model revision failed")
+
+			return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+				derrors.DataModel_ModelRevision_InternalError_DecodeRevisionFailed).WithErrorDetails(err.Error())
+		}
+		contents = append(contents, content)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return &interfaces.ModelRevisionDiff{
+		ModelType:    modelType,
+		ModelID:      modelID,
+		FromRevision: fromRevision,
+		ToRevision:   toRevision,
+		Changes:      diffRevisionContents(contents[0], contents[1]),
+	}, nil
+}
+
+// 记录模型的一个版本, 在模型创建或修改的事务内执行. 版本说明从 context 中获取
+func (mrs *modelRevisionService) SaveModelRevision(ctx context.Context, tx *sql.Tx, modelType string,
+	modelID string, modelName string, model any) error {
+
+	content, err := sonic.MarshalString(model)
+	if err != nil {
+		logger.Errorf("Marshal revision of %s '%s' error: %s", modelType, modelID, err.Error())
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_ModelRevision_InternalError_SaveRevisionFailed).WithErrorDetails(err.Error())
+	}
+
+	accountInfo := interfaces.AccountInfo{}
+	if ctx.Value(interfaces.ACCOUNT_INFO_KEY) != nil {
+		accountInfo = ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo)
+	}
+	comment, _ := ctx.Value(interfaces.REVISION_COMMENT_KEY).(string)
+
+	err = mrs.mra.CreateModelRevision(ctx, tx, &interfaces.ModelRevision{
+		ModelType:  modelType,
+		ModelID:    modelID,
+		ModelName:  modelName,
+		Comment:    comment,
+		Creator:    accountInfo,
+		CreateTime: time.Now().UnixMilli(),
+		Content:    content,
+	})
+	if err != nil {
+		logger.Errorf("Save revision of %s '%s' error: %s", modelType, modelID, err.Error())
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_ModelRevision_InternalError_SaveRevisionFailed).WithErrorDetails(err.Error())
+	}
+	return nil
+}
+
+// 删除模型的所有版本, 在模型删除的事务内执行
+func (mrs *modelRevisionService) DeleteModelRevisions(ctx context.Context, tx *sql.Tx, modelType string, modelIDs []string) error {
+	if len(modelIDs) == 0 {
+		return nil
+	}
+
+	err := mrs.mra.DeleteModelRevisions(ctx, tx, modelType, modelIDs)
+	if err != nil {
+		logger.Errorf("Delete revisions of %s %v error: %s", modelType, modelIDs, err.Error())
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_ModelRevision_InternalError_DeleteRevisionFailed).WithErrorDetails(err.Error())
+	}
+	return nil
+}
+
+// 查看版本需要模型的查看权限
+func (mrs *modelRevisionService) checkViewPermission(ctx context.Context, modelType string, modelID string) error {
+	return mrs.ps.CheckPermission(ctx, interfaces.Resource{
+		Type: interfaces.RevisionResourceTypeMap[modelType],
+		ID:   modelID,
+	}, []string{interfaces.OPERATION_TYPE_VIEW_DETAIL})
+}
+
+func (mrs *modelRevisionService) getModelRevision(ctx context.Context, modelType string, modelID string,
+	revision int) (*interfaces.ModelRevision, error) {
+
+	modelRevision, exist, err := mrs.mra.GetModelRevision(ctx, modelType, modelID, revision)
+	if err != nil {
+		logger.Errorf("Get revision %d of %s '%s' error: %s", revision, modelType, modelID, err.Error())
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_ModelRevision_InternalError_GetRevisionFailed).WithErrorDetails(err.Error())
+	}
+	if !exist {
+		return nil, rest.NewHTTPError(ctx, http.StatusNotFound, derrors.DataModel_ModelRevision_RevisionNotFound).
+			WithErrorDetails(fmt.Sprintf("The revision %d of %s '%s' not found", revision, modelType, modelID))
+	}
+	return modelRevision, nil
+}
@@ -0,0 +1,253 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package model_revision
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	derrors "data-model/errors"
+	"data-model/interfaces"
+	dmock "data-model/interfaces/mock"
+)
+
+var (
+	testCtx = context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+)
+
+func MockNewModelRevisionService(appSetting *common.AppSetting,
+	mra interfaces.ModelRevisionAccess,
+	ps interfaces.PermissionService) (*modelRevisionService, sqlmock.Sqlmock) {
+
+	db, smock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	mrs := &modelRevisionService{
+		appSetting: appSetting,
+		db:         db,
+		mra:        mra,
+		ps:         ps,
+	}
+	return mrs, smock
+}
+
+func Test_ModelRevisionService_ListModelRevisions(t *testing.T) {
+	Convey("Test ListModelRevisions", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		mra := dmock.NewMockModelRevisionAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		mrs, _ := MockNewModelRevisionService(appSetting, mra, ps)
+
+		query := interfaces.ModelRevisionsQueryParams{
+			ModelType: interfaces.REVISION_MODEL_TYPE_METRIC_MODEL,
+			ModelID:   "m1",
+		}
+
+		Convey("List failed, caused by permission denied", func() {
+			expectedErr := rest.NewHTTPError(testCtx, http.StatusForbidden, rest.PublicError_Forbidden)
+			ps.EXPECT().CheckPermission(gomock.Any(), interfaces.Resource{Type: interfaces.RESOURCE_TYPE_METRIC_MODEL, ID: "m1"},
+				[]string{interfaces.OPERATION_TYPE_VIEW_DETAIL}).Return(expectedErr)
+
+			_, _, err := mrs.ListModelRevisions(testCtx, query)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("List failed, caused by get total error", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mra.EXPECT().GetModelRevisionsTotal(gomock.Any(), query).Return(0, errors.New("some error"))
+
+			_, _, err := mrs.ListModelRevisions(testCtx, query)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_InternalError_GetRevisionFailed)
+		})
+
+		Convey("List failed, caused by list error", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mra.EXPECT().GetModelRevisionsTotal(gomock.Any(), query).Return(2, nil)
+			mra.EXPECT().ListModelRevisions(gomock.Any(), query).Return(nil, errors.New("some error"))
+
+			_, _, err := mrs.ListModelRevisions(testCtx, query)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_InternalError_GetRevisionFailed)
+		})
+
+		Convey("List succeed", func() {
+			revisions := []*interfaces.ModelRevision{{Revision: 2}, {Revision: 1}}
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mra.EXPECT().GetModelRevisionsTotal(gomock.Any(), query).Return(2, nil)
+			mra.EXPECT().ListModelRevisions(gomock.Any(), query).Return(revisions, nil)
+
+			res, total, err := mrs.ListModelRevisions(testCtx, query)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 2)
+			So(res, ShouldResemble, revisions)
+		})
+	})
+}
+
+func Test_ModelRevisionService_GetModelRevision(t *testing.T) {
+	Convey("Test GetModelRevision", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		mra := dmock.NewMockModelRevisionAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		mrs, _ := MockNewModelRevisionService(appSetting, mra, ps)
+
+		Convey("Get failed, caused by revision not found", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mra.EXPECT().GetModelRevision(gomock.Any(), interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, "m1", 3).
+				Return(nil, false, nil)
+
+			_, err := mrs.GetModelRevision(testCtx, interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, "m1", 3)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_RevisionNotFound)
+		})
+
+		Convey("Get failed, caused by access error", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mra.EXPECT().GetModelRevision(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(nil, false, errors.New("some error"))
+
+			_, err := mrs.GetModelRevision(testCtx, interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, "m1", 3)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_InternalError_GetRevisionFailed)
+		})
+
+		Convey("Get failed, caused by decode error", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mra.EXPECT().GetModelRevision(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&interfaces.ModelRevision{Content: "{"}, true, nil)
+
+			_, err := mrs.GetModelRevision(testCtx, interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, "m1", 3)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_InternalError_DecodeRevisionFailed)
+		})
+
+		Convey("Get succeed", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mra.EXPECT().GetModelRevision(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&interfaces.ModelRevision{
+					Revision: 3,
+					Content:  `{"id":"m1","query_type":"sql","formula_config":{"group_by_fields":["host"]}}`,
+				}, true, nil)
+
+			revision, err := mrs.GetModelRevision(testCtx, interfaces.REVISION_MODEL_TYPE_METRIC_MODEL, "m1", 3)
+			So(err, ShouldBeNil)
+			model := revision.Model.(*interfaces.MetricModel)
+			So(model.ModelID, ShouldEqual, "m1")
+			So(model.FormulaConfig.(interfaces.SQLConfig).GroupByFields, ShouldResemble, []string{"host"})
+		})
+	})
+}
+
+func Test_ModelRevisionService_DiffModelRevisions(t *testing.T) {
+	Convey("Test DiffModelRevisions", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		mra := dmock.NewMockModelRevisionAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		mrs, _ := MockNewModelRevisionService(appSetting, mra, ps)
+
+		Convey("Diff failed, caused by to revision not found", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mra.EXPECT().GetModelRevision(gomock.Any(), interfaces.REVISION_MODEL_TYPE_DATA_VIEW, "v1", 1).
+				Return(&interfaces.ModelRevision{Content: `{}`}, true, nil)
+			mra.EXPECT().GetModelRevision(gomock.Any(), interfaces.REVISION_MODEL_TYPE_DATA_VIEW, "v1", 2).
+				Return(nil, false, nil)
+
+			_, err := mrs.DiffModelRevisions(testCtx, interfaces.REVISION_MODEL_TYPE_DATA_VIEW, "v1", 1, 2)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_RevisionNotFound)
+		})
+
+		Convey("Diff succeed", func() {
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mra.EXPECT().GetModelRevision(gomock.Any(), interfaces.REVISION_MODEL_TYPE_DATA_VIEW, "v1", 1).
+				Return(&interfaces.ModelRevision{Content: `{"name":"a","update_time":1}`}, true, nil)
+			mra.EXPECT().GetModelRevision(gomock.Any(), interfaces.REVISION_MODEL_TYPE_DATA_VIEW, "v1", 2).
+				Return(&interfaces.ModelRevision{Content: `{"name":"b","update_time":2}`}, true, nil)
+
+			diff, err := mrs.DiffModelRevisions(testCtx, interfaces.REVISION_MODEL_TYPE_DATA_VIEW, "v1", 1, 2)
+			So(err, ShouldBeNil)
+			So(diff.Changes, ShouldResemble, []*interfaces.RevisionChange{
+				{Path: "name", Type: interfaces.REVISION_CHANGE_MODIFIED, OldValue: "a", NewValue: "b"},
+			})
+		})
+	})
+}
+
+func Test_ModelRevisionService_SaveModelRevision(t *testing.T) {
+	Convey("Test SaveModelRevision", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		mra := dmock.NewMockModelRevisionAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		mrs, _ := MockNewModelRevisionService(appSetting, mra, ps)
+
+		accountInfo := interfaces.AccountInfo{ID: "u1", Type: "user"}
+		ctx := context.WithValue(testCtx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+		ctx = context.WithValue(ctx, interfaces.REVISION_COMMENT_KEY, "rename")
+
+		Convey("Save failed", func() {
+			mra.EXPECT().CreateModelRevision(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+
+			err := mrs.SaveModelRevision(ctx, nil, interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL, "o1", "slo",
+				&interfaces.ObjectiveModel{})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_InternalError_SaveRevisionFailed)
+		})
+
+		Convey("Save succeed", func() {
+			mra.EXPECT().CreateModelRevision(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, tx any, revision *interfaces.ModelRevision) error {
+					So(revision.ModelID, ShouldEqual, "o1")
+					So(revision.ModelName, ShouldEqual, "slo")
+					So(revision.Comment, ShouldEqual, "rename")
+					So(revision.Creator, ShouldResemble, accountInfo)
+					So(revision.Content, ShouldContainSubstring, `"name":"slo"`)
+					return nil
+				})
+
+			model := &interfaces.ObjectiveModel{ObjectiveModelInfo: interfaces.ObjectiveModelInfo{ModelID: "o1", ModelName: "slo"}}
+			err := mrs.SaveModelRevision(ctx, nil, interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL, "o1", "slo", model)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func Test_ModelRevisionService_DeleteModelRevisions(t *testing.T) {
+	Convey("Test DeleteModelRevisions", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		mra := dmock.NewMockModelRevisionAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		mrs, _ := MockNewModelRevisionService(appSetting, mra, ps)
+
+		Convey("Delete succeed without ids", func() {
+			err := mrs.DeleteModelRevisions(testCtx, nil, interfaces.REVISION_MODEL_TYPE_DATA_VIEW, nil)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Delete failed", func() {
+			mra.EXPECT().DeleteModelRevisions(gomock.Any(), gomock.Any(), interfaces.REVISION_MODEL_TYPE_DATA_VIEW,
+				[]string{"v1"}).Return(errors.New("some error"))
+
+			err := mrs.DeleteModelRevisions(testCtx, nil, interfaces.REVISION_MODEL_TYPE_DATA_VIEW, []string{"v1"})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ModelRevision_InternalError_DeleteRevisionFailed)
+		})
+	})
+}
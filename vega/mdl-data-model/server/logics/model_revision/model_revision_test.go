@@ -0,0 +1,76 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package model_revision
+
+import (
+	"testing"
+
+	"github.com/bytedance/sonic"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/interfaces"
+)
+
+func Test_ModelRevision_DecodeRevisionModel(t *testing.T) {
+	Convey("Test decodeRevisionModel", t, func() {
+		Convey("Derived metric model", func() {
+			model, err := decodeRevisionModel(interfaces.REVISION_MODEL_TYPE_METRIC_MODEL,
+				`{"id":"m2","metric_type":"derived","formula_config":{"depend_metric_model":{"id":"m1"}}}`)
+			So(err, ShouldBeNil)
+			config := model.(*interfaces.MetricModel).FormulaConfig.(interfaces.DerivedConfig)
+			So(config.DependMetricModel.ID, ShouldEqual, "m1")
+		})
+
+		Convey("KPI objective model", func() {
+			model, err := decodeRevisionModel(interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL,
+				`{"id":"o1","objective_type":"kpi","objective_config":{"additional_metric_models":[{"id":"m3"}]}}`)
+			So(err, ShouldBeNil)
+			config := model.(*interfaces.ObjectiveModel).ObjectiveConfig.(interfaces.KPIObjective)
+			So(config.AdditionalMetricModels[0].ID, ShouldEqual, "m3")
+		})
+
+		Convey("Data view", func() {
+			model, err := decodeRevisionModel(interfaces.REVISION_MODEL_TYPE_DATA_VIEW, `{"id":"v1","name":"orders"}`)
+			So(err, ShouldBeNil)
+			So(model.(*interfaces.DataView).ViewName, ShouldEqual, "orders")
+		})
+
+		Convey("Unsupported model type", func() {
+			_, err := decodeRevisionModel("event_model", `{}`)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_ModelRevision_DiffRevisionContents(t *testing.T) {
+	Convey("Test diffRevisionContents", t, func() {
+		var from, to any
+		_ = sonic.UnmarshalString(`{
+			"name": "cpu",
+			"tags": [],
+			"formula_config": {"group_by_fields": ["host", "pod"], "aggr_expr": {"field": "a", "aggr": "sum"}},
+			"update_time": 1,
+			"creator": {"id": "u1"}
+		}`, &from)
+		_ = sonic.UnmarshalString(`{
+			"name": "cpu",
+			"tags": ["t1"],
+			"formula_config": {"group_by_fields": ["host"], "aggr_expr": {"field": "a", "aggr": "avg"}},
+			"comment": "new",
+			"update_time": 2,
+			"creator": {"id": "u2"}
+		}`, &to)
+
+		changes := diffRevisionContents(from, to)
+		So(changes, ShouldResemble, []*interfaces.RevisionChange{
+			{Path: "comment", Type: interfaces.REVISION_CHANGE_ADDED, NewValue: "new"},
+			{Path: "formula_config.aggr_expr.aggr", Type: interfaces.REVISION_CHANGE_MODIFIED, OldValue: "sum", NewValue: "avg"},
+			{Path: "formula_config.group_by_fields[1]", Type: interfaces.REVISION_CHANGE_REMOVED, OldValue: "pod"},
+			{Path: "tags", Type: interfaces.REVISION_CHANGE_REMOVED, OldValue: []any{}},
+			{Path: "tags[0]", Type: interfaces.REVISION_CHANGE_ADDED, NewValue: "t1"},
+		})
+	})
+}
@@ -25,7 +25,9 @@ import (
 	derrors "data-model/errors"
 	"data-model/interfaces"
 	"data-model/logics"
+	"data-model/logics/lineage"
 	"data-model/logics/metric_model"
+	"data-model/logics/model_revision"
 	"data-model/logics/permission"
 )
 
@@ -39,8 +41,10 @@ type objectiveModelService struct {
 	db         *sql.DB
 	dmja       interfaces.DataModelJobAccess
 	iba        interfaces.IndexBaseAccess
+	ls         interfaces.LineageService
 	mms        interfaces.MetricModelService
 	mmts       interfaces.MetricModelTaskService
+	mrs        interfaces.ModelRevisionService
 	oma        interfaces.ObjectiveModelAccess
 	ps         interfaces.PermissionService
 }
@@ -52,8 +56,10 @@ func NewObjectiveModelService(appSetting *common.AppSetting) interfaces.Objectiv
 			db:         logics.DB,
 			dmja:       logics.DMJA,
 			iba:        logics.IBA,
+			ls:         lineage.NewLineageService(appSetting),
 			mms:        metric_model.NewMetricModelService(appSetting),
 			mmts:       metric_model.NewMetricModelTaskService(appSetting),
+			mrs:        model_revision.NewModelRevisionService(appSetting),
 			oma:        logics.OMA,
 			ps:         permission.NewPermissionService(appSetting),
 		}
@@ -121,6 +127,11 @@ func (oms *objectiveModelService) CreateObjectiveModel(ctx context.Context, obje
 		if err != nil {
 			return "", err
 		}
+		// 燃烧率告警事件写入的索引库需存在，且 good 和 total 指标的统计窗口能随告警窗口变化
+		err = oms.checkBurnRateAlert(ctx, oConfig)
+		if err != nil {
+			return "", err
+		}
 		timeWindow = fmt.Sprintf("%dd", *oConfig.Period)
 	case interfaces.KPIObjective:
 		// kpi 校验综合计算指标模型和附加计算的指标模型的存在性
@@ -227,6 +238,21 @@ func (oms *objectiveModelService) CreateObjectiveModel(ctx context.Context, obje
 			WithErrorDetails(err.Error())
 	}
 
+	// 登记模型的血缘
+	err = oms.ls.SaveObjectiveModelLineage(ctx, tx, []*interfaces.ObjectiveModel{&objectiveModel})
+	if err != nil {
+		span.SetStatus(codes.Error, "登记目标模型血缘失败")
+		return "", err
+	}
+
+	// 记录模型的初始版本
+	err = oms.mrs.SaveModelRevision(ctx, tx, interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL, objectiveModel.ModelID,
+		objectiveModel.ModelName, &objectiveModel)
+	if err != nil {
+		span.SetStatus(codes.Error, "记录目标模型版本失败")
+		return "", err
+	}
+
 	// 2. 创建模型下的任务
 	err = oms.mmts.CreateMetricTask(ctx, tx, *objectiveModel.Task)
 	if err != nil {
@@ -343,6 +369,21 @@ func (oms *objectiveModelService) CreateObjectiveModels(ctx context.Context, obj
 				WithErrorDetails(err.Error())
 		}
 
+		// 登记模型的血缘
+		err = oms.ls.SaveObjectiveModelLineage(ctx, tx, []*interfaces.ObjectiveModel{objectiveModel})
+		if err != nil {
+			span.SetStatus(codes.Error, "登记目标模型血缘失败")
+			return nil, err
+		}
+
+		// 记录模型的初始版本
+		err = oms.mrs.SaveModelRevision(ctx, tx, interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL, objectiveModel.ModelID,
+			objectiveModel.ModelName, objectiveModel)
+		if err != nil {
+			span.SetStatus(codes.Error, "记录目标模型版本失败")
+			return nil, err
+		}
+
 		accountInfo := interfaces.AccountInfo{}
 		if ctx.Value(interfaces.ACCOUNT_INFO_KEY) != nil {
 			accountInfo = ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo)
@@ -596,6 +637,21 @@ func (oms *objectiveModelService) GetObjectiveModels(ctx context.Context, modelI
 		}
 	}
 
+	// 指定了版本时用版本快照替换当前定义, 任务仍取当前的. 引用的指标模型取当前定义, 所以清除 context 中的版本
+	if revision, ok := ctx.Value(interfaces.MODEL_REVISION_KEY).(int); ok && revision > 0 {
+		ctx = context.WithValue(ctx, interfaces.MODEL_REVISION_KEY, 0)
+		if len(objectiveModelArr) == 1 {
+			modelRevision, err := oms.mrs.GetModelRevision(ctx, interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL,
+				objectiveModelArr[0].ModelID, revision)
+			if err != nil {
+				span.SetStatus(codes.Error, "Get objective model revision failed")
+				span.End()
+				return nil, err
+			}
+			objectiveModelArr[0] = *modelRevision.Model.(*interfaces.ObjectiveModel)
+		}
+	}
+
 	// 根据模型id获取任务信息
 	modelTaskMap, err := oms.mmts.GetMetricTasksByModelIDs(ctx, modelIDs)
 	if err != nil {
@@ -738,6 +794,11 @@ func (oms *objectiveModelService) UpdateObjectiveModel(ctx context.Context, tx *
 		if err != nil {
 			return err
 		}
+		// 燃烧率告警事件写入的索引库需存在，且 good 和 total 指标的统计窗口能随告警窗口变化
+		err = oms.checkBurnRateAlert(ctx, oConfig)
+		if err != nil {
+			return err
+		}
 		timeWindow = fmt.Sprintf("%dd", *oConfig.Period)
 	case interfaces.KPIObjective:
 		// kpi 校验综合计算指标模型和附加计算的指标模型的存在性
@@ -815,6 +876,21 @@ func (oms *objectiveModelService) UpdateObjectiveModel(ctx context.Context, tx *
 			WithErrorDetails(err.Error())
 	}
 
+	// 重新登记模型的血缘
+	err = oms.ls.SaveObjectiveModelLineage(updateCtx, tx, []*interfaces.ObjectiveModel{&objectiveModel})
+	if err != nil {
+		updateSpan.SetStatus(codes.Error, "登记目标模型血缘失败")
+		return err
+	}
+
+	// 记录修改后的版本
+	err = oms.mrs.SaveModelRevision(updateCtx, tx, interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL, objectiveModel.ModelID,
+		objectiveModel.ModelName, &objectiveModel)
+	if err != nil {
+		updateSpan.SetStatus(codes.Error, "记录目标模型版本失败")
+		return err
+	}
+
 	// 更新操作
 	if timeWindow != "" {
 		objectiveModel.Task.TimeWindows = []string{timeWindow}
@@ -952,6 +1028,15 @@ func (oms *objectiveModelService) DeleteObjectiveModels(ctx context.Context, mod
 		o11y.Warn(ctx, fmt.Sprintf("Delete models number %v not equal requerst models number %v!", rowsAffect, len(modelIDs)))
 	}
 
+	// 删除模型的燃烧率告警状态
+	err = oms.oma.DeleteObjectiveAlerts(ctx, tx, modelIDs)
+	if err != nil {
+		logger.Errorf("DeleteObjectiveAlerts error: %s", err.Error())
+		span.SetStatus(codes.Error, "删除目标模型告警状态失败")
+		return 0, rest.NewHTTPError(ctx, http.StatusInternalServerError, derrors.DataModel_ObjectiveModel_InternalError).
+			WithErrorDetails(err.Error())
+	}
+
 	// 删除指标模型下的任务
 	err = oms.mmts.DeleteMetricTaskByTaskIDs(ctx, tx, taskIDs)
 	if err != nil {
@@ -961,6 +1046,20 @@ func (oms *objectiveModelService) DeleteObjectiveModels(ctx context.Context, mod
 			WithErrorDetails(err.Error())
 	}
 
+	// 删除模型登记的血缘
+	err = oms.ls.DeleteModelLineage(ctx, tx, interfaces.LINEAGE_NODE_TYPE_OBJECTIVE_MODEL, modelIDs)
+	if err != nil {
+		span.SetStatus(codes.Error, "删除目标模型血缘失败")
+		return 0, err
+	}
+
+	// 删除模型的历史版本
+	err = oms.mrs.DeleteModelRevisions(ctx, tx, interfaces.REVISION_MODEL_TYPE_OBJECTIVE_MODEL, modelIDs)
+	if err != nil {
+		span.SetStatus(codes.Error, "删除目标模型版本失败")
+		return 0, err
+	}
+
 	//  清除资源策略
 	err = oms.ps.DeleteResources(ctx, interfaces.RESOURCE_TYPE_OBJECTIVE_MODEL, modelIDs)
 	if err != nil {
@@ -971,6 +1070,43 @@ func (oms *objectiveModelService) DeleteObjectiveModels(ctx context.Context, mod
 	return rowsAffect, nil
 }
 
+// 获取目标模型的燃烧率告警状态
+func (oms *objectiveModelService) ListObjectiveAlerts(ctx context.Context, modelID string) ([]interfaces.ObjectiveAlert, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, fmt.Sprintf("查询目标模型[%s]的告警状态", modelID))
+	span.SetAttributes(attr.Key("model_id").String(modelID))
+	defer span.End()
+
+	_, exist, err := oms.CheckObjectiveModelExistByID(ctx, modelID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		span.SetStatus(codes.Error, fmt.Sprintf("Objective model [%s] not found", modelID))
+		return nil, rest.NewHTTPError(ctx, http.StatusNotFound, derrors.DataModel_ObjectiveModel_ObjectiveModelNotFound).
+			WithErrorDetails(fmt.Sprintf("Objective model [%s] not found", modelID))
+	}
+
+	err = oms.ps.CheckPermission(ctx, interfaces.Resource{
+		Type: interfaces.RESOURCE_TYPE_OBJECTIVE_MODEL,
+		ID:   modelID,
+	}, []string{interfaces.OPERATION_TYPE_VIEW_DETAIL})
+	if err != nil {
+		return nil, err
+	}
+
+	alerts, err := oms.oma.ListObjectiveAlerts(ctx, modelID)
+	if err != nil {
+		logger.Errorf("ListObjectiveAlerts error: %s", err.Error())
+		span.SetStatus(codes.Error, fmt.Sprintf("List objective model[%s]'s alerts error: %v", modelID, err))
+
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			derrors.DataModel_ObjectiveModel_InternalError_ListObjectiveAlertsFailed).WithErrorDetails(err.Error())
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return alerts, nil
+}
+
 func (oms *objectiveModelService) checkMetricModelExists(ctx context.Context, metricIDs []string) error {
 	res, err := oms.mms.GetMetricModelSimpleInfosByIDs(ctx, metricIDs)
 	if err != nil {
@@ -1007,6 +1143,36 @@ func (oms *objectiveModelService) checkMetricModelExists(ctx context.Context, me
 	return nil
 }
 
+// 燃烧率告警按 look_back_delta 查询各个窗口的燃烧率。promql 指标的统计区间由表达式中的区间选择器决定，
+// 不随 look_back_delta 变化，长短窗口会得到相同的燃烧率，因此 good 或 total 为 promql 指标时不支持燃烧率告警
+func (oms *objectiveModelService) checkBurnRateAlert(ctx context.Context, oConfig interfaces.SLOObjective) error {
+	if oConfig.BurnRateAlert == nil {
+		return nil
+	}
+
+	err := oms.checkIndexBase(ctx, oConfig.BurnRateAlert.IndexBase)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range []string{oConfig.GoodMetricModel.ID, oConfig.TotalMetricModel.ID} {
+		metricModel, err := oms.mms.GetMetricModelByModelID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if metricModel.QueryType == interfaces.PROMQL {
+			errDetails := fmt.Sprintf("The burn rate alert does not support promql metric model [%s], "+
+				"its range is fixed by the range selector and does not change with the alert window", metricModel.ModelName)
+			logger.Error(errDetails)
+			o11y.Error(ctx, errDetails)
+
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert).
+				WithErrorDetails(errDetails)
+		}
+	}
+	return nil
+}
+
 func (oms *objectiveModelService) checkIndexBase(ctx context.Context, indexBaseType string) error {
 	// 校验 请求体中的数据视图是否存在
 	ctx, span := ar_trace.Tracer.Start(ctx, fmt.Sprintf("获取索引库[%s]信息", indexBaseType))
@@ -1052,6 +1218,11 @@ func (oms *objectiveModelService) processForCreate(ctx context.Context, objectiv
 		if err != nil {
 			return err
 		}
+		// 燃烧率告警事件写入的索引库需存在，且 good 和 total 指标的统计窗口能随告警窗口变化
+		err = oms.checkBurnRateAlert(ctx, oConfig)
+		if err != nil {
+			return err
+		}
 		timeWindow = fmt.Sprintf("%dd", *oConfig.Period)
 	case interfaces.KPIObjective:
 		// kpi 校验综合计算指标模型和附加计算的指标模型的存在性
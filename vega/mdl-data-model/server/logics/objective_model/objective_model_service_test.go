@@ -148,6 +148,12 @@ func Test_ObjectiveModelService_CreateObjectiveModel(t *testing.T) {
 		iba := dmock.NewMockIndexBaseAccess(mockCtrl)
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		oms, smock := MockNewObjectiveModelService(appSetting, dmja, mms, oma, mmts, iba, ps)
+		ls := dmock.NewMockLineageService(mockCtrl)
+		oms.ls = ls
+		ls.EXPECT().SaveObjectiveModelLineage(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mrs := dmock.NewMockModelRevisionService(mockCtrl)
+		oms.mrs = mrs
+		mrs.EXPECT().SaveModelRevision(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 		Convey("When check metric model exists fails", func() {
 			objective := float64(99)
@@ -976,6 +982,12 @@ func Test_ObjectiveModelService_UpdateObjectiveModel(t *testing.T) {
 		iba := dmock.NewMockIndexBaseAccess(mockCtrl)
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		oms, smock := MockNewObjectiveModelService(appSetting, dmja, mms, oma, mmts, iba, ps)
+		ls := dmock.NewMockLineageService(mockCtrl)
+		oms.ls = ls
+		ls.EXPECT().SaveObjectiveModelLineage(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mrs := dmock.NewMockModelRevisionService(mockCtrl)
+		oms.mrs = mrs
+		mrs.EXPECT().SaveModelRevision(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 		Convey("When GetMetricModelSimpleInfosByIDs fails for SLOObjective", func() {
 			objective := float64(99)
@@ -1246,6 +1258,12 @@ func Test_ObjectiveModelService_DeleteObjectiveModels(t *testing.T) {
 		iba := dmock.NewMockIndexBaseAccess(mockCtrl)
 		ps := dmock.NewMockPermissionService(mockCtrl)
 		oms, smock := MockNewObjectiveModelService(appSetting, dmja, mms, oma, mmts, iba, ps)
+		ls := dmock.NewMockLineageService(mockCtrl)
+		oms.ls = ls
+		ls.EXPECT().DeleteModelLineage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		mrs := dmock.NewMockModelRevisionService(mockCtrl)
+		oms.mrs = mrs
+		mrs.EXPECT().DeleteModelRevisions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 		resrc := map[string]interfaces.ResourceOps{
 			"test-id": {
 				ResourceID: "test-id",
@@ -1292,6 +1310,23 @@ func Test_ObjectiveModelService_DeleteObjectiveModels(t *testing.T) {
 			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ObjectiveModel_InternalError)
 		})
 
+		Convey("When DeleteObjectiveAlerts fails", func() {
+			modelIDs := []string{"test-id"}
+
+			ps.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(resrc, nil)
+			mmts.EXPECT().GetMetricTaskIDsByModelIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
+			smock.ExpectBegin()
+			oma.EXPECT().DeleteObjectiveModels(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
+			oma.EXPECT().DeleteObjectiveAlerts(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("delete objective alerts error"))
+			smock.ExpectRollback()
+
+			_, err := oms.DeleteObjectiveModels(testCtx, modelIDs)
+			So(err, ShouldNotBeNil)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusInternalServerError)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ObjectiveModel_InternalError)
+		})
+
 		Convey("When DeleteMetricTaskByTaskIDs fails", func() {
 			modelIDs := []string{"test-id"}
 			taskIDs := []string{"1"}
@@ -1300,6 +1335,7 @@ func Test_ObjectiveModelService_DeleteObjectiveModels(t *testing.T) {
 				Return(resrc, nil)
 			smock.ExpectBegin()
 			oma.EXPECT().DeleteObjectiveModels(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), nil)
+			oma.EXPECT().DeleteObjectiveAlerts(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			mmts.EXPECT().GetMetricTaskIDsByModelIDs(gomock.Any(), gomock.Any()).Return(taskIDs, nil)
 			dmja.EXPECT().StopJobs(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 			mmts.EXPECT().DeleteMetricTaskByTaskIDs(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("set task sync status error"))
@@ -1320,6 +1356,7 @@ func Test_ObjectiveModelService_DeleteObjectiveModels(t *testing.T) {
 			ps.EXPECT().DeleteResources(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			smock.ExpectBegin()
 			oma.EXPECT().DeleteObjectiveModels(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil)
+			oma.EXPECT().DeleteObjectiveAlerts(gomock.Any(), gomock.Any(), []string{"test-id"}).Return(nil)
 			mmts.EXPECT().GetMetricTaskIDsByModelIDs(gomock.Any(), gomock.Any()).Return(taskIDs, nil)
 			dmja.EXPECT().StopJobs(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 			mmts.EXPECT().DeleteMetricTaskByTaskIDs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
@@ -1331,3 +1368,122 @@ func Test_ObjectiveModelService_DeleteObjectiveModels(t *testing.T) {
 		})
 	})
 }
+
+func Test_ObjectiveModelService_ListObjectiveAlerts(t *testing.T) {
+	Convey("Test ListObjectiveAlerts", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		dmja := dmock.NewMockDataModelJobAccess(mockCtrl)
+		oma := dmock.NewMockObjectiveModelAccess(mockCtrl)
+		mms := dmock.NewMockMetricModelService(mockCtrl)
+		mmts := dmock.NewMockMetricModelTaskService(mockCtrl)
+		iba := dmock.NewMockIndexBaseAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		oms, _ := MockNewObjectiveModelService(appSetting, dmja, mms, oma, mmts, iba, ps)
+
+		Convey("List succeed", func() {
+			alerts := []interfaces.ObjectiveAlert{{ModelID: "m1", PolicyName: "page", Status: interfaces.OBJECTIVE_ALERT_STATUS_FIRING}}
+			oma.EXPECT().CheckObjectiveModelExistByID(gomock.Any(), "m1").Return("model-1", true, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			oma.EXPECT().ListObjectiveAlerts(gomock.Any(), "m1").Return(alerts, nil)
+
+			result, err := oms.ListObjectiveAlerts(testCtx, "m1")
+			So(err, ShouldBeNil)
+			So(result, ShouldResemble, alerts)
+		})
+
+		Convey("Model not found", func() {
+			oma.EXPECT().CheckObjectiveModelExistByID(gomock.Any(), "m1").Return("", false, nil)
+
+			_, err := oms.ListObjectiveAlerts(testCtx, "m1")
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Permission denied", func() {
+			oma.EXPECT().CheckObjectiveModelExistByID(gomock.Any(), "m1").Return("model-1", true, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(rest.NewHTTPError(testCtx, http.StatusForbidden, rest.PublicError_Forbidden))
+
+			_, err := oms.ListObjectiveAlerts(testCtx, "m1")
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("List failed", func() {
+			oma.EXPECT().CheckObjectiveModelExistByID(gomock.Any(), "m1").Return("model-1", true, nil)
+			ps.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			oma.EXPECT().ListObjectiveAlerts(gomock.Any(), "m1").Return(nil, errors.New("db error"))
+
+			_, err := oms.ListObjectiveAlerts(testCtx, "m1")
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ObjectiveModel_InternalError_ListObjectiveAlertsFailed)
+		})
+	})
+}
+
+func Test_ObjectiveModelService_CheckBurnRateAlert(t *testing.T) {
+	Convey("Test checkBurnRateAlert", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		dmja := dmock.NewMockDataModelJobAccess(mockCtrl)
+		oma := dmock.NewMockObjectiveModelAccess(mockCtrl)
+		mms := dmock.NewMockMetricModelService(mockCtrl)
+		mmts := dmock.NewMockMetricModelTaskService(mockCtrl)
+		iba := dmock.NewMockIndexBaseAccess(mockCtrl)
+		ps := dmock.NewMockPermissionService(mockCtrl)
+		oms, _ := MockNewObjectiveModelService(appSetting, dmja, mms, oma, mmts, iba, ps)
+
+		oConfig := interfaces.SLOObjective{
+			GoodMetricModel:  &interfaces.BundleMetricModel{ID: "good"},
+			TotalMetricModel: &interfaces.BundleMetricModel{ID: "total"},
+			BurnRateAlert: &interfaces.BurnRateAlertConfig{
+				IndexBase: "slo_alert",
+				Policies: []interfaces.BurnRatePolicy{
+					{Name: "page", LongWindow: "1h", ShortWindow: "5m", BurnRate: 14.4, Level: 1},
+				},
+			},
+		}
+
+		Convey("When burn rate alert is not set", func() {
+			err := oms.checkBurnRateAlert(testCtx, interfaces.SLOObjective{})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("When good and total are dsl metric models", func() {
+			iba.EXPECT().GetSimpleIndexBasesByTypes(gomock.Any(), []string{"slo_alert"}).
+				Return([]interfaces.SimpleIndexBase{}, nil)
+			mms.EXPECT().GetMetricModelByModelID(gomock.Any(), "good").
+				Return(interfaces.MetricModel{SimpleMetricModel: interfaces.SimpleMetricModel{ModelName: "good", QueryType: interfaces.DSL}}, nil)
+			mms.EXPECT().GetMetricModelByModelID(gomock.Any(), "total").
+				Return(interfaces.MetricModel{SimpleMetricModel: interfaces.SimpleMetricModel{ModelName: "total", QueryType: interfaces.DSL}}, nil)
+
+			err := oms.checkBurnRateAlert(testCtx, oConfig)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("When total is a promql metric model", func() {
+			iba.EXPECT().GetSimpleIndexBasesByTypes(gomock.Any(), []string{"slo_alert"}).
+				Return([]interfaces.SimpleIndexBase{}, nil)
+			mms.EXPECT().GetMetricModelByModelID(gomock.Any(), "good").
+				Return(interfaces.MetricModel{SimpleMetricModel: interfaces.SimpleMetricModel{ModelName: "good", QueryType: interfaces.DSL}}, nil)
+			mms.EXPECT().GetMetricModelByModelID(gomock.Any(), "total").
+				Return(interfaces.MetricModel{SimpleMetricModel: interfaces.SimpleMetricModel{ModelName: "total", QueryType: interfaces.PROMQL}}, nil)
+
+			err := oms.checkBurnRateAlert(testCtx, oConfig)
+			So(err, ShouldNotBeNil)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, derrors.DataModel_ObjectiveModel_InvalidParameter_BurnRateAlert)
+		})
+
+		Convey("When index base query fails", func() {
+			iba.EXPECT().GetSimpleIndexBasesByTypes(gomock.Any(), gomock.Any()).
+				Return(nil, errors.New("error"))
+
+			err := oms.checkBurnRateAlert(testCtx, oConfig)
+			So(err, ShouldNotBeNil)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
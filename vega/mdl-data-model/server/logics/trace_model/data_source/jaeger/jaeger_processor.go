@@ -0,0 +1,143 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package jaeger
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"go.opentelemetry.io/otel/codes"
+
+	"data-model/common"
+	"data-model/interfaces"
+	dtype "data-model/interfaces/data_type"
+)
+
+var (
+	jtProcessorOnce sync.Once
+	jtProcessor     interfaces.TraceModelProcessor
+
+	// Jaeger-on-OpenSearch span字段信息
+	jaegerSpanFieldInfo = []interfaces.TraceModelField{
+		{
+			Name: "duration",
+			Type: dtype.DataType_Integer,
+		},
+		{
+			Name: "flags",
+			Type: dtype.DataType_Integer,
+		},
+		{
+			Name: "logs",
+			Type: dtype.DataType_Text,
+		},
+		{
+			Name: "operationName",
+			Type: dtype.DataType_Text,
+		},
+		{
+			Name: "parentSpanID",
+			Type: dtype.DataType_Text,
+		},
+		{
+			Name: "process.serviceName",
+			Type: dtype.DataType_Text,
+		},
+		{
+			Name: "process.tags",
+			Type: dtype.DataType_Text,
+		},
+		{
+			Name: "references",
+			Type: dtype.DataType_Text,
+		},
+		{
+			Name: "spanID",
+			Type: dtype.DataType_Text,
+		},
+		{
+			Name: "startTime",
+			Type: dtype.DataType_Integer,
+		},
+		{
+			Name: "startTimeMillis",
+			Type: dtype.DataType_Integer,
+		},
+		{
+			Name: "tags",
+			Type: dtype.DataType_Text,
+		},
+		{
+			Name: "traceID",
+			Type: dtype.DataType_Text,
+		},
+	}
+)
+
+type jaegerTraceProcessor struct {
+	appSetting *common.AppSetting
+}
+
+func NewJaegerTraceProcessor(appSetting *common.AppSetting) interfaces.TraceModelProcessor {
+	jtProcessorOnce.Do(func() {
+		jtProcessor = &jaegerTraceProcessor{
+			appSetting: appSetting,
+		}
+	})
+	return jtProcessor
+}
+
+func (jtp *jaegerTraceProcessor) GetSpanFieldInfo(ctx context.Context,
+	model interfaces.TraceModel) (fieldInfos []interfaces.TraceModelField, err error) {
+
+	_, span := ar_trace.Tracer.Start(ctx, "logic层: 查询Span字段信息")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	return append(append([]interfaces.TraceModelField{}, interfaces.SPAN_METADATA...), jaegerSpanFieldInfo...), nil
+}
+
+// 关联日志的原始字段由日志索引决定, 这里只返回用于关联的trace_id与span_id字段
+func (jtp *jaegerTraceProcessor) GetRelatedLogFieldInfo(ctx context.Context, model interfaces.TraceModel) (fieldInfos []interfaces.TraceModelField, err error) {
+	_, span := ar_trace.Tracer.Start(ctx, "logic层: 查询Span关联日志字段信息")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	relatedLogConf, ok := model.RelatedLogConfig.(interfaces.RelatedLogConfigWithDataConnection)
+	if !ok {
+		return []interfaces.TraceModelField(nil), nil
+	}
+
+	fieldInfos = append([]interfaces.TraceModelField{}, interfaces.RELATED_LOG_METADATA...)
+	if relatedLogConf.TraceID.FieldName != "" {
+		fieldInfos = append(fieldInfos, interfaces.TraceModelField{
+			Name: relatedLogConf.TraceID.FieldName,
+			Type: dtype.DataType_Text,
+		})
+	}
+
+	for _, fieldName := range relatedLogConf.SpanID.FieldNames {
+		fieldInfos = append(fieldInfos, interfaces.TraceModelField{
+			Name: fieldName,
+			Type: dtype.DataType_Text,
+		})
+	}
+
+	return fieldInfos, nil
+}
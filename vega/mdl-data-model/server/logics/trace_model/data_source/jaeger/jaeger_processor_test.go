@@ -0,0 +1,65 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package jaeger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"data-model/common"
+	"data-model/interfaces"
+)
+
+var (
+	testCtx = context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+)
+
+func MockNewJaegerTraceProcessor(appSetting *common.AppSetting) *jaegerTraceProcessor {
+	return &jaegerTraceProcessor{
+		appSetting: appSetting,
+	}
+}
+
+func Test_JaegerTraceProcessor_GetSpanFieldInfo(t *testing.T) {
+	Convey("Test GetSpanFieldInfo", t, func() {
+		jtp := MockNewJaegerTraceProcessor(&common.AppSetting{})
+
+		Convey("Get succeed", func() {
+			fieldInfos, err := jtp.GetSpanFieldInfo(testCtx, interfaces.TraceModel{})
+			So(err, ShouldBeNil)
+			So(len(fieldInfos), ShouldEqual, len(interfaces.SPAN_METADATA)+len(jaegerSpanFieldInfo))
+		})
+	})
+}
+
+func Test_JaegerTraceProcessor_GetRelatedLogFieldInfo(t *testing.T) {
+	Convey("Test GetRelatedLogFieldInfo", t, func() {
+		jtp := MockNewJaegerTraceProcessor(&common.AppSetting{})
+
+		Convey("Get nothing when related log is not in data connection", func() {
+			fieldInfos, err := jtp.GetRelatedLogFieldInfo(testCtx, interfaces.TraceModel{})
+			So(err, ShouldBeNil)
+			So(fieldInfos, ShouldBeNil)
+		})
+
+		Convey("Get succeed", func() {
+			model := interfaces.TraceModel{
+				RelatedLogConfig: interfaces.RelatedLogConfigWithDataConnection{
+					LogIndex: "otel-logs-*",
+					TraceID:  interfaces.TraceIDConfig{FieldName: "traceId"},
+					SpanID:   interfaces.SpanIDConfig{FieldNames: []string{"spanId"}},
+				},
+			}
+			fieldInfos, err := jtp.GetRelatedLogFieldInfo(testCtx, model)
+			So(err, ShouldBeNil)
+			So(len(fieldInfos), ShouldEqual, len(interfaces.RELATED_LOG_METADATA)+2)
+			So(fieldInfos[len(fieldInfos)-1].Name, ShouldEqual, "spanId")
+		})
+	})
+}
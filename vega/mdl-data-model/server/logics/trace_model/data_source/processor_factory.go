@@ -16,6 +16,7 @@ import (
 	derrors "data-model/errors"
 	"data-model/interfaces"
 	"data-model/logics/trace_model/data_source/data_view"
+	"data-model/logics/trace_model/data_source/jaeger"
 	"data-model/logics/trace_model/data_source/tingyun"
 )
 
@@ -25,6 +26,8 @@ func NewTraceModelProcessor(ctx context.Context, appSetting *common.AppSetting,
 		return data_view.NewDataViewTraceProcessor(appSetting), nil
 	case interfaces.SOURCE_TYPE_TINGYUN:
 		return tingyun.NewTingYunTraceProcessor(appSetting), nil
+	case interfaces.SOURCE_TYPE_JAEGER:
+		return jaeger.NewJaegerTraceProcessor(appSetting), nil
 	default:
 		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, derrors.DataModel_TraceModel_InternalError_InitTraceModelProcessor).
 			WithErrorDetails(fmt.Sprintf("Invalid data_source_type: %v", dataSourceType))
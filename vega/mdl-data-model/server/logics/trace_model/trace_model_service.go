@@ -1315,7 +1315,7 @@ func (tms *traceModelService) getUnderlyingDataSourceType(ctx context.Context, q
 		return interfaces.SOURCE_TYPE_DATA_VIEW, nil
 	} else if queryCategory == interfaces.QUERY_CATEGORY_RELATED_LOG && model.RelatedLogSourceType == interfaces.SOURCE_TYPE_DATA_VIEW {
 		return interfaces.SOURCE_TYPE_DATA_VIEW, nil
-	} else { // queryCategory == interfaces.QUERY_CATEGORY_SPAN && model.SpanSourceType == interfaces.SOURCE_TYPE_DATA_CONNECTION
+	} else { // 基于数据连接的span与关联日志, 底层数据源均为span所在数据连接的类型
 		spanConfig := model.SpanConfig.(interfaces.SpanConfigWithDataConnection)
 		underlyingSourceType, isExist, err := tms.dcs.GetDataConnectionSourceType(ctx, spanConfig.DataConnection.ID)
 		if err != nil {
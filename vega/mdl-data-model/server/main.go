@@ -31,7 +31,9 @@ import (
 	"data-model/drivenadapters/data_view"
 	"data-model/drivenadapters/event_model"
 	"data-model/drivenadapters/index_base"
+	"data-model/drivenadapters/lineage"
 	"data-model/drivenadapters/metric_model"
+	"data-model/drivenadapters/model_revision"
 	"data-model/drivenadapters/objective_model"
 	"data-model/drivenadapters/permission"
 	"data-model/drivenadapters/scan_record"
@@ -127,15 +129,19 @@ func main() {
 	logics.SetDataDictAccess(data_dict.NewDataDictAccess(appSetting))
 	logics.SetDataDictItemAccess(data_dict.NewDictItemAccess(appSetting))
 	logics.SetDataModelJobAccess(data_model_job.NewDataModelJobAccess(appSetting))
+	logics.SetDataQualityRuleAccess(data_view.NewDataQualityRuleAccess(appSetting))
 	logics.SetDataSourceAccess(vega.NewDataSourceAccess(appSetting))
 	logics.SetDataViewAccess(data_view.NewDataViewAccess(appSetting))
 	logics.SetDataViewGroupAccess(data_view.NewDataViewGroupAccess(appSetting))
 	logics.SetDataViewRowColumnRuleAccess(data_view.NewDataViewRowColumnRuleAccess(appSetting))
 	logics.SetEventModelAccess(event_model.NewEventModelAccess(appSetting))
 	logics.SetIndexBaseAccess(index_base.NewIndexBaseAccess(appSetting))
+	logics.SetLineageAccess(lineage.NewLineageAccess(appSetting))
 	logics.SetMetricModelAccess(metric_model.NewMetricModelAccess(appSetting))
 	logics.SetMetricModelGroupAccess(metric_model.NewMetricModelGroupAccess(appSetting))
 	logics.SetMetricModelTaskAccess(metric_model.NewMetricModelTaskAccess(appSetting))
+	logics.SetMetricTaskBackfillAccess(metric_model.NewMetricTaskBackfillAccess(appSetting))
+	logics.SetModelRevisionAccess(model_revision.NewModelRevisionAccess(appSetting))
 	logics.SetObjectiveModelAccess(objective_model.NewObjectiveModelAccess(appSetting))
 	logics.SetPermissionAccess(permission.NewPermissionAccess(appSetting))
 	logics.SetScanRecordAccess(scan_record.NewScanRecordAccess(appSetting))
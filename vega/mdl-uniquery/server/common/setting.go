@@ -53,6 +53,98 @@ type PromqlSetting struct {
 	MaxSearchSeriesSize int           `mapstructure:"maxSearchSeriesSize"`
 }
 
+// 异步导出配置项.
+// Store: 导出任务的存储，memory 或 redis，为空时使用 memory；多实例部署时需使用 redis
+// FileDir: 导出文件的存放目录，多实例部署时需挂载各实例共享的存储卷
+// MaxRunningJobs: 同时运行的导出任务数，超出的任务排队等待
+// Retention: 任务结束后导出文件的保留时长
+// MaxRows: 单个任务最多导出的行数，0 表示不限制
+type ExportSetting struct {
+	Store          string        `mapstructure:"store"`
+	FileDir        string        `mapstructure:"fileDir"`
+	MaxRunningJobs int           `mapstructure:"maxRunningJobs"`
+	Retention      time.Duration `mapstructure:"retention"`
+	MaxRows        int64         `mapstructure:"maxRows"`
+}
+
+// 行列规则的字段脱敏配置项.
+// HashKey: 哈希脱敏使用的 HMAC 密钥，环境变量 FIELD_MASK_HASH_KEY 优先; 为空时哈希脱敏的字段输出 null
+type FieldMaskSetting struct {
+	HashKey string `mapstructure:"hashKey"`
+}
+
+// 指标查询结果缓存配置项.
+// Enabled: 是否开启缓存
+// Store: 缓存存储，memory 或 redis，为空时使用 memory；多实例部署时建议使用 redis
+// TTL: 缓存条目的生存时间
+// CompleteDelay: 数据写入延迟，结束时间距当前不足该时长的时间桶视为未完成，不缓存
+// MaxSeries: 单个查询可缓存的最大序列数，超出时不缓存
+type ResultCacheSetting struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Store         string        `mapstructure:"store"`
+	TTL           time.Duration `mapstructure:"ttl"`
+	CompleteDelay time.Duration `mapstructure:"completeDelay"`
+	MaxSeries     int           `mapstructure:"maxSeries"`
+}
+
+// 事件生命周期配置项.
+// Enabled: 是否开启事件的去重、静默和通知路由
+// Store: 事件状态、静默规则和通知路由的存储，memory 或 redis，为空时使用 memory；memory 只在实例内去重，多实例部署时必须使用 redis
+// SuppressWindow: 抑制窗口，同一去重 key 的事件在窗口内只持久化和通知一次
+// ResolveTimeout: 事件超过该时长未再出现时视为已恢复，0 表示只能手动恢复
+// Retention: 事件状态的保留时长
+// NotifyRetries: 通知发送失败的重试次数
+// NotifyRetryBackoff: 首次重试的等待时长，之后逐次翻倍
+// NotifyTimeout: 单次通知发送的超时时间
+// SMTP: 邮件通知的发件服务器
+// Routes: 配置的通知路由规则，按顺序匹配，先于通过接口创建的路由
+type EventLifecycleSetting struct {
+	Enabled            bool                `mapstructure:"enabled"`
+	Store              string              `mapstructure:"store"`
+	SuppressWindow     time.Duration       `mapstructure:"suppressWindow"`
+	ResolveTimeout     time.Duration       `mapstructure:"resolveTimeout"`
+	Retention          time.Duration       `mapstructure:"retention"`
+	NotifyRetries      int                 `mapstructure:"notifyRetries"`
+	NotifyRetryBackoff time.Duration       `mapstructure:"notifyRetryBackoff"`
+	NotifyTimeout      time.Duration       `mapstructure:"notifyTimeout"`
+	SMTP               SMTPSetting         `mapstructure:"smtp"`
+	Routes             []EventRouteSetting `mapstructure:"routes"`
+}
+
+// 邮件通知的发件服务器配置项
+type SMTPSetting struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
+// 事件通知路由配置项.
+// EventModelIDs、Levels、Labels 为匹配条件，为空表示不限制，Labels 按标签值相等匹配；
+// Channel 为通知渠道：webhook、email 或 kafka；
+// Continue 为 true 时匹配后继续匹配后续路由，否则停止
+type EventRouteSetting struct {
+	Name          string            `mapstructure:"name"`
+	EventModelIDs []string          `mapstructure:"eventModelIds"`
+	Levels        []int             `mapstructure:"levels"`
+	Labels        map[string]string `mapstructure:"labels"`
+	Channel       string            `mapstructure:"channel"`
+	WebhookURL    string            `mapstructure:"webhookUrl"`
+	Headers       map[string]string `mapstructure:"headers"`
+	EmailTo       []string          `mapstructure:"emailTo"`
+	KafkaTopic    string            `mapstructure:"kafkaTopic"`
+	Continue      bool              `mapstructure:"continue"`
+}
+
+// Redis 配置项
+type RedisSetting struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
 type ThirdParty struct {
 	TingYunMaxTimePeriod int64 `mapstructure:"tingYunMaxTimePeriod"`
 }
@@ -76,19 +168,24 @@ type KafkaSetting struct {
 
 // app配置项
 type AppSetting struct {
-	ServerSetting        ServerSetting             `mapstructure:"server"`
-	LogSetting           logger.LogSetting         `mapstructure:"log"`
-	PoolSetting          PoolSetting               `mapstructure:"pool"`
-	PromqlSetting        PromqlSetting             `mapstructure:"promql"`
-	ThirdParty           ThirdParty                `mapstructure:"thirdParty"`
-	KafkaSetting         KafkaSetting              `mapstructure:"kafka"`
-	ObservabilitySetting o11y.ObservabilitySetting `mapstructure:"observability"`
-	DepServices          map[string]map[string]any `mapstructure:"depServices"`
+	ServerSetting         ServerSetting             `mapstructure:"server"`
+	LogSetting            logger.LogSetting         `mapstructure:"log"`
+	PoolSetting           PoolSetting               `mapstructure:"pool"`
+	PromqlSetting         PromqlSetting             `mapstructure:"promql"`
+	ExportSetting         ExportSetting             `mapstructure:"export"`
+	ResultCacheSetting    ResultCacheSetting        `mapstructure:"resultCache"`
+	FieldMaskSetting      FieldMaskSetting          `mapstructure:"fieldMask"`
+	EventLifecycleSetting EventLifecycleSetting     `mapstructure:"eventLifecycle"`
+	ThirdParty            ThirdParty                `mapstructure:"thirdParty"`
+	KafkaSetting          KafkaSetting              `mapstructure:"kafka"`
+	ObservabilitySetting  o11y.ObservabilitySetting `mapstructure:"observability"`
+	DepServices           map[string]map[string]any `mapstructure:"depServices"`
 
 	DBSetting         libdb.DBSetting
 	MQSetting         libmq.MQSetting
 	OpenSearchSetting rest.OpenSearchClientConfig
 	HydraAdminSetting rest.HydraAdminSetting
+	RedisSetting      RedisSetting
 
 	DataManagerUrl        string
 	SearchUrl             string
@@ -105,6 +202,7 @@ type AppSetting struct {
 	VegaViewUrl           string
 	// permission url
 	PermissionUrl string
+	UserMgmtUrl   string
 }
 
 const (
@@ -116,6 +214,7 @@ const (
 	rdsServiceName        string = "rds"
 	mqServiceName         string = "mq"
 	opensearchServiceName string = "opensearch"
+	redisServiceName      string = "redis"
 
 	dataManagerServiceName        string = "data-manager"
 	dataModelServiceName          string = "data-model"
@@ -123,6 +222,7 @@ const (
 	indexBaseServiceName          string = "index-base"
 	permissionServiceName         string = "authorization-private"
 	searchServiceName             string = "search"
+	userMgmtServiceName           string = "user-management"
 	vegaDataConnectionServiceName string = "data-connection"
 	vegaGatewayProServiceName     string = "vega-gateway-pro"
 	vegaGatewayServiceName        string = "vega-gateway"
@@ -201,6 +301,11 @@ func loadSetting(vp *viper.Viper) {
 	}
 	APP_LOCATION = loc
 
+	// 哈希脱敏密钥优先从环境变量读取，由 secret 注入，不写在配置文件中
+	if hashKey := os.Getenv("FIELD_MASK_HASH_KEY"); hashKey != "" {
+		appSetting.FieldMaskSetting.HashKey = hashKey
+	}
+
 	SetLogSetting(appSetting.LogSetting)
 
 	SetDBSetting()
@@ -209,6 +314,13 @@ func loadSetting(vp *viper.Viper) {
 
 	SetOpenSearchSetting()
 
+	// 结果缓存、事件生命周期或导出任务使用 redis 时才依赖 redis 服务
+	if (appSetting.ResultCacheSetting.Enabled && appSetting.ResultCacheSetting.Store == "redis") ||
+		(appSetting.EventLifecycleSetting.Enabled && appSetting.EventLifecycleSetting.Store == "redis") ||
+		appSetting.ExportSetting.Store == "redis" {
+		SetRedisSetting()
+	}
+
 	SetDataConnGatewaySetting()
 
 	SetVegaGatewayProSetting()
@@ -229,6 +341,8 @@ func loadSetting(vp *viper.Viper) {
 
 	SetPermissionSetting()
 
+	SetUserMgmtSetting()
+
 	SetVegaDatSourceSetting()
 
 	SetVegaGatewaySetting()
@@ -449,6 +563,20 @@ func SetVegaDatSourceSetting() {
 	appSetting.DataConnDataSourceUrl = fmt.Sprintf("%s://%s:%d/api/internal/data-connection/v1/datasource", protocol, host, port)
 }
 
+func SetRedisSetting() {
+	setting, ok := appSetting.DepServices[redisServiceName]
+	if !ok {
+		logger.Fatalf("service %s not found in depServices", redisServiceName)
+	}
+
+	appSetting.RedisSetting = RedisSetting{
+		Host:     setting["host"].(string),
+		Port:     setting["port"].(int),
+		Username: setting["username"].(string),
+		Password: setting["password"].(string),
+	}
+}
+
 func SetDataConnGatewaySetting() {
 	setting, ok := appSetting.DepServices[vegaDataConnectionServiceName]
 	if !ok {
@@ -500,3 +628,16 @@ func SetPermissionSetting() {
 
 	appSetting.PermissionUrl = fmt.Sprintf("%s://%s:%d/api/authorization/v1", protocol, host, port)
 }
+
+func SetUserMgmtSetting() {
+	setting, ok := appSetting.DepServices[userMgmtServiceName]
+	if !ok {
+		logger.Fatalf("service %s not found in depServices", userMgmtServiceName)
+	}
+
+	protocol := setting["protocol"].(string)
+	host := setting["host"].(string)
+	port := setting["port"].(int)
+
+	appSetting.UserMgmtUrl = fmt.Sprintf("%s://%s:%d", protocol, host, port)
+}
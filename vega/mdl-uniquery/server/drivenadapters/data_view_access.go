@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"sync"
 
 	"github.com/bytedance/sonic"
@@ -57,6 +58,10 @@ func (dva *dataViewAccess) GetDataViewsByIDs(ctx context.Context, ids string, in
 	if includeDataScopeView {
 		queryValues.Add("include_data_scope_views", "true")
 	}
+	// 查询固定版本时获取该版本的视图定义. 指标模型依赖的视图不在固定范围内, 取当前定义
+	if revision := interfaces.GetPinnedRevision(ctx, ids); revision > 0 {
+		queryValues.Add(interfaces.QueryParam_Revision, strconv.Itoa(revision))
+	}
 
 	o11y.AddAttrs4InternalHttp(span, o11y.TraceAttrs{
 		HttpUrl:         httpUrl,
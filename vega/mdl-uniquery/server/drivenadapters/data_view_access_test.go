@@ -6,8 +6,10 @@
 package drivenadapters
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/bytedance/sonic"
@@ -133,6 +135,41 @@ func TestGetDataViewsByIDs(t *testing.T) {
 			So(err, ShouldBeNil)
 		})
 
+		Convey("get the pinned revision of the requested view", func() {
+			ctx := context.WithValue(testCtx, interfaces.MODEL_REVISION_KEY, interfaces.ModelRevisionPin{
+				ModelID:  "1",
+				Revision: 3,
+			})
+			bytes, _ := sonic.Marshal([]*interfaces.DataView{{ViewID: "1"}})
+			mockHttpClient.EXPECT().GetNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, url string, queryValues url.Values, headers map[string]string) (int, []byte, error) {
+					So(queryValues.Get(interfaces.QueryParam_Revision), ShouldEqual, "3")
+					return http.StatusOK, bytes, nil
+				})
+
+			views, err := dvaMock.GetDataViewsByIDs(ctx, "1", false)
+
+			So(len(views), ShouldEqual, 1)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("the revision pinned on a metric model is not applied to its data view", func() {
+			ctx := context.WithValue(testCtx, interfaces.MODEL_REVISION_KEY, interfaces.ModelRevisionPin{
+				ModelID:  "metric1",
+				Revision: 3,
+			})
+			bytes, _ := sonic.Marshal([]*interfaces.DataView{{ViewID: "1"}})
+			mockHttpClient.EXPECT().GetNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, url string, queryValues url.Values, headers map[string]string) (int, []byte, error) {
+					So(queryValues.Has(interfaces.QueryParam_Revision), ShouldBeFalse)
+					return http.StatusOK, bytes, nil
+				})
+
+			views, err := dvaMock.GetDataViewsByIDs(ctx, "1", false)
+
+			So(len(views), ShouldEqual, 1)
+			So(err, ShouldBeNil)
+		})
 	})
 }
 
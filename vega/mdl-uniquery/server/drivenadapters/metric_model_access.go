@@ -10,6 +10,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -77,8 +79,13 @@ func (mma *metricModelAccess) GetMetricModel(ctx context.Context, modelId string
 		interfaces.HTTP_HEADER_ACCOUNT_ID:   accountInfo.ID,
 		interfaces.HTTP_HEADER_ACCOUNT_TYPE: accountInfo.Type,
 	}
+	// 查询固定版本时获取该版本的模型定义
+	var queryValues url.Values
+	if revision := interfaces.GetPinnedRevision(ctx, modelId); revision > 0 {
+		queryValues = url.Values{interfaces.QueryParam_Revision: []string{strconv.Itoa(revision)}}
+	}
 	// httpClient 的请求新增参数支持上下文的处理请求的函数
-	respCode, result, err = mma.httpClient.GetNoUnmarshal(ctx, httpUrl, nil, metricModelHeaders)
+	respCode, result, err = mma.httpClient.GetNoUnmarshal(ctx, httpUrl, queryValues, metricModelHeaders)
 
 	var emptyModel []interfaces.MetricModel
 	if err != nil {
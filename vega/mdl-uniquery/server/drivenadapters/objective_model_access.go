@@ -10,6 +10,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"sync"
 
 	"github.com/bytedance/sonic"
@@ -65,8 +67,13 @@ func (oma *objectiveModelAccess) GetObjectiveModel(ctx context.Context, modelId
 		interfaces.HTTP_HEADER_ACCOUNT_ID:   accountInfo.ID,
 		interfaces.HTTP_HEADER_ACCOUNT_TYPE: accountInfo.Type,
 	}
+	// 查询固定版本时获取该版本的模型定义
+	var queryValues url.Values
+	if revision := interfaces.GetPinnedRevision(ctx, modelId); revision > 0 {
+		queryValues = url.Values{interfaces.QueryParam_Revision: []string{strconv.Itoa(revision)}}
+	}
 	// httpClient 的请求新增参数支持上下文的处理请求的函数
-	respCode, result, err := oma.httpClient.GetNoUnmarshal(ctx, httpUrl, nil, headers)
+	respCode, result, err := oma.httpClient.GetNoUnmarshal(ctx, httpUrl, queryValues, headers)
 
 	var emptyModel interfaces.ObjectiveModel
 	if err != nil {
@@ -321,6 +321,16 @@ func (tma *traceModelAccess) commonProcessTraceModel(ctx context.Context, model
 				return model, err
 			}
 			model.RelatedLogConfig = relatedLogConf
+		case interfaces.SOURCE_TYPE_DATA_CONNECTION:
+			relatedLogConf := interfaces.RelatedLogConfigWithDataConnection{}
+			err = sonic.Unmarshal(b, &relatedLogConf)
+			if err != nil {
+				errDetails := fmt.Sprintf("Field related_log_config cannot be unmarshaled to RelatedLogConfigWithDataConnection, err: %v", err.Error())
+				logger.Error(errDetails)
+				o11y.Error(ctx, errDetails)
+				return model, err
+			}
+			model.RelatedLogConfig = relatedLogConf
 		default:
 			errDetails := fmt.Sprintf("Invalid related_log_source_type: %s", model.RelatedLogSourceType)
 			logger.Error(errDetails)
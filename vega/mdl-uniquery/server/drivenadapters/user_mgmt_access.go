@@ -0,0 +1,131 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package drivenadapters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"uniquery/common"
+	"uniquery/interfaces"
+)
+
+var (
+	umAccessOnce sync.Once
+	umAccess     interfaces.UserMgmtAccess
+)
+
+type userMgmtAccess struct {
+	appSetting *common.AppSetting
+	httpClient rest.HTTPClient
+}
+
+// 用户管理服务返回的用户信息
+type userInfo struct {
+	Name       string `json:"name"`
+	ParentDeps [][]struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"parent_deps"`
+	Roles      []string       `json:"roles"`
+	Email      string         `json:"email"`
+	CustomAttr map[string]any `json:"custom_attr"`
+}
+
+func NewUserMgmtAccess(appSetting *common.AppSetting) interfaces.UserMgmtAccess {
+	umAccessOnce.Do(func() {
+		umAccess = &userMgmtAccess{
+			appSetting: appSetting,
+			httpClient: common.NewHTTPClient(),
+		}
+	})
+	return umAccess
+}
+
+// 获取用户属性. 部门取每条部门路径的最后一级, 即用户的直属部门
+func (uma *userMgmtAccess) GetUserAttributes(ctx context.Context, userID string) (map[string]any, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "driven layer: Get user attributes from user-management service", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	httpUrl := fmt.Sprintf("%s/api/user-management/v1/users/%s/name,parent_deps,roles,email,custom_attr",
+		uma.appSetting.UserMgmtUrl, url.PathEscape(userID))
+
+	span.SetAttributes(attr.Key("user_id").String(userID))
+	o11y.AddAttrs4InternalHttp(span, o11y.TraceAttrs{
+		HttpUrl:         httpUrl,
+		HttpMethod:      http.MethodGet,
+		HttpContentType: rest.ContentTypeJson,
+	})
+
+	headers := map[string]string{
+		interfaces.CONTENT_TYPE_NAME: interfaces.CONTENT_TYPE_JSON,
+	}
+
+	respCode, respData, err := uma.httpClient.GetNoUnmarshal(ctx, httpUrl, nil, headers)
+	if err != nil {
+		errDetails := fmt.Sprintf("GetUserAttributes http request failed: %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Http get user attributes failed")
+
+		return nil, fmt.Errorf("get request method failed: %s", err)
+	}
+
+	if respCode != http.StatusOK {
+		logger.Errorf("get user attributes failed: %s", respData)
+		o11y.Error(ctx, fmt.Sprintf("get user attributes failed: %s", respData))
+		o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Http status code is not 200")
+		return nil, fmt.Errorf("GetUserAttributes failed, status code is %d", respCode)
+	}
+
+	var users []userInfo
+	if err = sonic.Unmarshal(respData, &users); err != nil {
+		logger.Errorf("Unmarshal user info failed: %s", err)
+		o11y.Error(ctx, err.Error())
+		o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "Unmarshal user info failed")
+		return nil, err
+	}
+	if len(users) == 0 {
+		o11y.AddHttpAttrs4Error(span, respCode, "InternalError", "User not found")
+		return nil, fmt.Errorf("user %s not found", userID)
+	}
+
+	user := users[0]
+	departmentIDs := make([]any, 0, len(user.ParentDeps))
+	for _, deps := range user.ParentDeps {
+		if len(deps) > 0 {
+			departmentIDs = append(departmentIDs, deps[len(deps)-1].ID)
+		}
+	}
+	roles := make([]any, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roles = append(roles, role)
+	}
+
+	attrs := map[string]any{
+		interfaces.USER_ATTR_NAME:          user.Name,
+		interfaces.USER_ATTR_EMAIL:         user.Email,
+		interfaces.USER_ATTR_DEPARTMENT_ID: departmentIDs,
+		interfaces.USER_ATTR_ROLE:          roles,
+	}
+	for k, v := range user.CustomAttr {
+		attrs[interfaces.USER_ATTR_CUSTOM_PREFIX+k] = v
+	}
+
+	o11y.AddHttpAttrs4Ok(span, respCode)
+	return attrs, nil
+}
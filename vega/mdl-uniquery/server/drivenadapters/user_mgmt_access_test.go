@@ -0,0 +1,73 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package drivenadapters
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	rmock "github.com/kweaver-ai/kweaver-go-lib/rest/mock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/common"
+	"uniquery/interfaces"
+)
+
+func TestGetUserAttributes(t *testing.T) {
+	Convey("Test GetUserAttributes", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockHttpClient := rmock.NewMockHTTPClient(mockCtrl)
+		uma := &userMgmtAccess{
+			appSetting: &common.AppSetting{UserMgmtUrl: "http://user-management:30980"},
+			httpClient: mockHttpClient,
+		}
+
+		Convey("GetUserAttributes success", func() {
+			respData := []byte(`[{"name":"张三","email":"a@b.com","roles":["normal_user"],
+				"parent_deps":[[{"id":"root","name":"总部"},{"id":"d1","name":"研发部"}],[{"id":"d2","name":"测试部"}]],
+				"custom_attr":{"region":"east"}}]`)
+			mockHttpClient.EXPECT().GetNoUnmarshal(gomock.Any(),
+				"http://user-management:30980/api/user-management/v1/users/u1/name,parent_deps,roles,email,custom_attr",
+				gomock.Any(), gomock.Any()).Return(http.StatusOK, respData, nil)
+
+			attrs, err := uma.GetUserAttributes(testCtx, "u1")
+			So(err, ShouldBeNil)
+			So(attrs[interfaces.USER_ATTR_NAME], ShouldEqual, "张三")
+			So(attrs[interfaces.USER_ATTR_EMAIL], ShouldEqual, "a@b.com")
+			So(attrs[interfaces.USER_ATTR_DEPARTMENT_ID], ShouldResemble, []any{"d1", "d2"})
+			So(attrs[interfaces.USER_ATTR_ROLE], ShouldResemble, []any{"normal_user"})
+			So(attrs["custom_attr.region"], ShouldEqual, "east")
+		})
+
+		Convey("GetUserAttributes request failed", func() {
+			mockHttpClient.EXPECT().GetNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any()).Return(http.StatusOK, nil, fmt.Errorf("method failed"))
+
+			_, err := uma.GetUserAttributes(testCtx, "u1")
+			So(err, ShouldResemble, fmt.Errorf("get request method failed: method failed"))
+		})
+
+		Convey("GetUserAttributes status code is not 200", func() {
+			mockHttpClient.EXPECT().GetNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any()).Return(http.StatusNotFound, []byte(`{}`), nil)
+
+			_, err := uma.GetUserAttributes(testCtx, "u1")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("GetUserAttributes user not found", func() {
+			mockHttpClient.EXPECT().GetNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any()).Return(http.StatusOK, []byte(`[]`), nil)
+
+			_, err := uma.GetUserAttributes(testCtx, "u1")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -225,6 +225,21 @@ func (r *restHandler) GetViewData(c *gin.Context, visitor rest.Visitor) {
 
 	idsArr := convert.StringToStringSlice(ids)
 
+	// revision 按视图的历史版本查询, 用于复现历史报表
+	revision, err := validatePinnedRevision(ctx, c.Query(interfaces.QueryParam_Revision), idsArr)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	if revision > 0 {
+		ctx = context.WithValue(ctx, interfaces.MODEL_REVISION_KEY, interfaces.ModelRevisionPin{
+			ModelID:  idsArr[0],
+			Revision: revision,
+		})
+	}
+
 	// 单个查询
 	if len(idsArr) == 1 {
 		query := interfaces.DataViewQueryV2{}
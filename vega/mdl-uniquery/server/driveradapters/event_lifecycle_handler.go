@@ -0,0 +1,459 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"go.opentelemetry.io/otel/trace"
+
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+)
+
+// 变更事件状态（外部）
+func (r *restHandler) UpdateEventStatusByEx(c *gin.Context) {
+	logger.Debug("Handler UpdateEventStatusByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Update event status",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.UpdateEventStatus(c, visitor)
+}
+
+// 变更事件状态（内部）
+func (r *restHandler) UpdateEventStatusByIn(c *gin.Context) {
+	logger.Debug("Handler UpdateEventStatusByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.UpdateEventStatus(c, visitor)
+}
+
+// 确认或恢复事件，返回变更后的生命周期
+func (r *restHandler) UpdateEventStatus(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler UpdateEventStatus Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Update event status", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	query := interfaces.EventDetailsQueryReq{}
+	req := interfaces.EventStatusUpdateRequest{}
+	err := c.ShouldBindUri(&query)
+	if err == nil {
+		err = c.ShouldBindJSON(&req)
+	}
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventModel_InvalidParameter).
+			WithErrorDetails("Binding Parameter Failed:" + err.Error())
+
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	lifecycle, err := r.eService.UpdateEventStatus(ctx, query, &req)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, lifecycle)
+}
+
+// 创建静默规则（外部）
+func (r *restHandler) CreateEventSilenceByEx(c *gin.Context) {
+	logger.Debug("Handler CreateEventSilenceByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Create event silence",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.CreateEventSilence(c, visitor)
+}
+
+// 创建静默规则（内部）
+func (r *restHandler) CreateEventSilenceByIn(c *gin.Context) {
+	logger.Debug("Handler CreateEventSilenceByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.CreateEventSilence(c, visitor)
+}
+
+// 创建静默规则
+func (r *restHandler) CreateEventSilence(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler CreateEventSilence Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Create event silence", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	req := interfaces.EventSilenceRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_InvalidParameter_RequestBody).
+			WithErrorDetails("Binding Parameter Failed:" + err.Error())
+
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	silence, err := r.elService.CreateSilence(ctx, &req)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusCreated)
+	rest.ReplyOK(c, http.StatusCreated, silence)
+}
+
+// 查询静默规则（外部）
+func (r *restHandler) ListEventSilencesByEx(c *gin.Context) {
+	logger.Debug("Handler ListEventSilencesByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: List event silences",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.ListEventSilences(c, visitor)
+}
+
+// 查询静默规则（内部）
+func (r *restHandler) ListEventSilencesByIn(c *gin.Context) {
+	logger.Debug("Handler ListEventSilencesByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.ListEventSilences(c, visitor)
+}
+
+// 查询事件模型未过期的静默规则
+func (r *restHandler) ListEventSilences(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler ListEventSilences Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: List event silences", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	eventModelID := c.Query("event_model_id")
+	if eventModelID == "" {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventModel_InvalidParameter).
+			WithErrorDetails("The event_model_id is required")
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	silences, err := r.elService.ListSilences(ctx, eventModelID)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	result := map[string]any{
+		"entries":     silences,
+		"total_count": len(silences),
+	}
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, result)
+}
+
+// 删除静默规则（外部）
+func (r *restHandler) DeleteEventSilenceByEx(c *gin.Context) {
+	logger.Debug("Handler DeleteEventSilenceByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Delete event silence",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.DeleteEventSilence(c, visitor)
+}
+
+// 删除静默规则（内部）
+func (r *restHandler) DeleteEventSilenceByIn(c *gin.Context) {
+	logger.Debug("Handler DeleteEventSilenceByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.DeleteEventSilence(c, visitor)
+}
+
+// 删除静默规则，删除后匹配的事件恢复通知
+func (r *restHandler) DeleteEventSilence(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler DeleteEventSilence Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Delete event silence", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	err := r.elService.DeleteSilence(ctx, c.Param("silence_id"))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusNoContent)
+	rest.ReplyOK(c, http.StatusNoContent, nil)
+}
+
+// 创建通知路由（外部）
+func (r *restHandler) CreateEventRouteByEx(c *gin.Context) {
+	logger.Debug("Handler CreateEventRouteByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Create event route",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.CreateEventRoute(c, visitor)
+}
+
+// 创建通知路由（内部）
+func (r *restHandler) CreateEventRouteByIn(c *gin.Context) {
+	logger.Debug("Handler CreateEventRouteByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.CreateEventRoute(c, visitor)
+}
+
+// 创建通知路由
+func (r *restHandler) CreateEventRoute(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler CreateEventRoute Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Create event route", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	req := interfaces.EventRouteRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_InvalidParameter_RequestBody).
+			WithErrorDetails("Binding Parameter Failed:" + err.Error())
+
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	route, err := r.elService.CreateRoute(ctx, &req)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusCreated)
+	rest.ReplyOK(c, http.StatusCreated, route)
+}
+
+// 查询通知路由（外部）
+func (r *restHandler) ListEventRoutesByEx(c *gin.Context) {
+	logger.Debug("Handler ListEventRoutesByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: List event routes",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.ListEventRoutes(c, visitor)
+}
+
+// 查询通知路由（内部）
+func (r *restHandler) ListEventRoutesByIn(c *gin.Context) {
+	logger.Debug("Handler ListEventRoutesByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.ListEventRoutes(c, visitor)
+}
+
+// 查询作用于事件模型的通知路由，配置文件中的路由不在结果中
+func (r *restHandler) ListEventRoutes(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler ListEventRoutes Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: List event routes", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	eventModelID := c.Query("event_model_id")
+	if eventModelID == "" {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventModel_InvalidParameter).
+			WithErrorDetails("The event_model_id is required")
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	routes, err := r.elService.ListRoutes(ctx, eventModelID)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	result := map[string]any{
+		"entries":     routes,
+		"total_count": len(routes),
+	}
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, result)
+}
+
+// 修改通知路由（外部）
+func (r *restHandler) UpdateEventRouteByEx(c *gin.Context) {
+	logger.Debug("Handler UpdateEventRouteByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Update event route",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.UpdateEventRoute(c, visitor)
+}
+
+// 修改通知路由（内部）
+func (r *restHandler) UpdateEventRouteByIn(c *gin.Context) {
+	logger.Debug("Handler UpdateEventRouteByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.UpdateEventRoute(c, visitor)
+}
+
+// 修改通知路由，请求体为完整的路由定义
+func (r *restHandler) UpdateEventRoute(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler UpdateEventRoute Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Update event route", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	req := interfaces.EventRouteRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_InvalidParameter_RequestBody).
+			WithErrorDetails("Binding Parameter Failed:" + err.Error())
+
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	route, err := r.elService.UpdateRoute(ctx, c.Param("route_id"), &req)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, route)
+}
+
+// 删除通知路由（外部）
+func (r *restHandler) DeleteEventRouteByEx(c *gin.Context) {
+	logger.Debug("Handler DeleteEventRouteByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Delete event route",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.DeleteEventRoute(c, visitor)
+}
+
+// 删除通知路由（内部）
+func (r *restHandler) DeleteEventRouteByIn(c *gin.Context) {
+	logger.Debug("Handler DeleteEventRouteByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.DeleteEventRoute(c, visitor)
+}
+
+// 删除通知路由
+func (r *restHandler) DeleteEventRoute(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler DeleteEventRoute Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Delete event route", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	err := r.elService.DeleteRoute(ctx, c.Param("route_id"))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusNoContent)
+	rest.ReplyOK(c, http.StatusNoContent, nil)
+}
@@ -0,0 +1,269 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"go.opentelemetry.io/otel/trace"
+
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+)
+
+// 创建导出任务（外部）
+func (r *restHandler) CreateExportJobByEx(c *gin.Context) {
+	logger.Debug("Handler CreateExportJobByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Create export job",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.CreateExportJob(c, visitor)
+}
+
+// 创建导出任务（内部）
+func (r *restHandler) CreateExportJobByIn(c *gin.Context) {
+	logger.Debug("Handler CreateExportJobByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.CreateExportJob(c, visitor)
+}
+
+// 创建导出任务
+func (r *restHandler) CreateExportJob(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler CreateExportJob Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Create export job", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	req := interfaces.ExportJobRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_InvalidParameter_RequestBody).
+			WithErrorDetails("Binding Parameter Failed:" + err.Error())
+
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	err = validateExportJobRequest(ctx, &req)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	job, err := r.exportService.CreateExportJob(ctx, &req)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusAccepted)
+	rest.ReplyOK(c, http.StatusAccepted, job)
+}
+
+// 查询导出任务（外部）
+func (r *restHandler) GetExportJobByEx(c *gin.Context) {
+	logger.Debug("Handler GetExportJobByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Get export job",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.GetExportJob(c, visitor)
+}
+
+// 查询导出任务（内部）
+func (r *restHandler) GetExportJobByIn(c *gin.Context) {
+	logger.Debug("Handler GetExportJobByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.GetExportJob(c, visitor)
+}
+
+// 查询导出任务的状态和进度
+func (r *restHandler) GetExportJob(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler GetExportJob Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Get export job", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	job, err := r.exportService.GetExportJob(ctx, c.Param("job_id"))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOK(c, http.StatusOK, job)
+}
+
+// 下载导出文件（外部）
+func (r *restHandler) DownloadExportFileByEx(c *gin.Context) {
+	logger.Debug("Handler DownloadExportFileByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Download export file",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.DownloadExportFile(c, visitor)
+}
+
+// 下载导出文件（内部）
+func (r *restHandler) DownloadExportFileByIn(c *gin.Context) {
+	logger.Debug("Handler DownloadExportFileByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.DownloadExportFile(c, visitor)
+}
+
+// 下载导出文件，支持 Range 请求断点续传
+func (r *restHandler) DownloadExportFile(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler DownloadExportFile Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Download export file", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	job, file, err := r.exportService.OpenExportFile(ctx, c.Param("job_id"))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", interfaces.ExportFormatContentTypeMap[job.Format])
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": job.FileName}))
+	http.ServeContent(c.Writer, c.Request, job.FileName, time.UnixMilli(job.FinishTime), file)
+
+	o11y.AddHttpAttrs4Ok(span, c.Writer.Status())
+}
+
+// 删除导出任务（外部）
+func (r *restHandler) DeleteExportJobByEx(c *gin.Context) {
+	logger.Debug("Handler DeleteExportJobByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Delete export job",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.DeleteExportJob(c, visitor)
+}
+
+// 删除导出任务（内部）
+func (r *restHandler) DeleteExportJobByIn(c *gin.Context) {
+	logger.Debug("Handler DeleteExportJobByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.DeleteExportJob(c, visitor)
+}
+
+// 删除导出任务，运行中的任务会被取消
+func (r *restHandler) DeleteExportJob(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler DeleteExportJob Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver layer: Delete export job", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	ctx = withAccountInfo(ctx, visitor)
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	err := r.exportService.DeleteExportJob(ctx, c.Param("job_id"))
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusNoContent)
+	rest.ReplyOK(c, http.StatusNoContent, nil)
+}
+
+// 校验导出任务的查询条件，与对应的数据查询接口一致
+func validateExportJobRequest(ctx context.Context, req *interfaces.ExportJobRequest) error {
+	switch req.SourceType {
+	case interfaces.ExportSourceType_DataView:
+		if req.ViewQuery == nil {
+			req.ViewQuery = &interfaces.DataViewQueryV2{}
+		}
+		setDefaultValues(&req.ViewQuery.ViewQueryCommonParams)
+		return ValidateDataViewQueryV2(ctx, req.ViewQuery)
+
+	case interfaces.ExportSourceType_MetricModel:
+		if req.MetricQuery == nil {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_Export_InvalidParameter_SourceQuery).
+				WithErrorDetails("The metric_query is required when export metric model data")
+		}
+
+		queryParam, err := validateMetricModelQueryParameters(ctx, interfaces.DEFAULT_OFFEST, interfaces.DEFAULT_SERIES_LIMIT,
+			interfaces.DEFAULT_IGNORING_MEMORY_CACHE, interfaces.DEFAULT_IGNORING_STORE_CACHE, "false",
+			interfaces.DEFAULT_FILL_NULL, interfaces.DEFAULT_INCLUDE_MODEL)
+		if err != nil {
+			return err
+		}
+		req.MetricQuery.MetricModelQueryParameters = queryParam
+		req.MetricQuery.MetricModelID = req.SourceID
+		if req.MetricQuery.FilterMode == "" {
+			req.MetricQuery.FilterMode = interfaces.FILTER_MODE_NORMAL
+		}
+		return validateMetricModelData(ctx, req.MetricQuery)
+	}
+	return nil
+}
+
+func withAccountInfo(ctx context.Context, visitor rest.Visitor) context.Context {
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	return context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+}
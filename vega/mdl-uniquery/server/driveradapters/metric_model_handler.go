@@ -296,6 +296,31 @@ func (r *restHandler) GetMetricModelData(c *gin.Context, visitor rest.Visitor) {
 	// 过滤模式选择默认是normal
 	filterMode := c.DefaultQuery("filter_mode", interfaces.FILTER_MODE_NORMAL)
 
+	// revision 查询指标模型的历史版本, 缓存和持久化数据按当前版本生成, 查询历史版本时不使用.
+	// 只固定指标模型本身的定义, 其依赖的数据视图、被引用的指标模型仍按当前版本读取
+	revision, err := validatePinnedRevision(ctx, c.Query(interfaces.QueryParam_Revision), modelIDs)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		// 设置 trace 的错误信息的 attributes
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		// 记录异常日志
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description,
+			httpErr.BaseError.ErrorDetails))
+
+		rest.ReplyError(c, httpErr)
+
+		attrs = append(attrs, attribute.Int("status_code", httpErr.HTTPCode))
+		return
+	}
+	if revision > 0 {
+		ctx = context.WithValue(ctx, interfaces.MODEL_REVISION_KEY, interfaces.ModelRevisionPin{
+			ModelID:  modelIDs[0],
+			Revision: revision,
+		})
+		ignoringMemoryCache = "true"
+		ignoringStoreCache = "true"
+	}
+
 	// 校验查询参数
 	queryParam, err := validateMetricModelQueryParameters(ctx, offset, limit,
 		ignoringMemoryCache, ignoringStoreCache, ignoringHCTSStr, fillNullStr, includeModel)
@@ -684,3 +709,37 @@ func (r *restHandler) GetMetricModelLabels(c *gin.Context) {
 	rest.ReplyOK(c, http.StatusOK, result)
 	attrs = append(attrs, attribute.Int("status_code", http.StatusOK))
 }
+
+// 删除指标模型的查询结果缓存(内部)，指标模型更新或删除后由 data-model 调用
+func (r *restHandler) DeleteMetricModelCacheByIn(c *gin.Context) {
+	logger.Debug("Handler DeleteMetricModelCacheByIn Start")
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "删除指标模型的查询结果缓存 API", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	modelIDs := convert.StringToStringSlice(c.Param("model_ids"))
+	span.SetAttributes(attribute.Key("model_ids").StringSlice(modelIDs))
+
+	if len(modelIDs) == 0 {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_MetricModel_InvalidParameter).
+			WithErrorDetails("metric model id is empty")
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	err := r.rCache.DeleteModelCache(ctx, modelIDs)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_MetricModel_InternalError_DeleteResultCacheFailed).
+			WithErrorDetails(err.Error())
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+		rest.ReplyError(c, httpErr)
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusNoContent)
+	rest.ReplyOK(c, http.StatusNoContent, nil)
+}
@@ -250,6 +250,31 @@ func (r *restHandler) GetObjectiveModelData(c *gin.Context, visitor rest.Visitor
 	// include_metrics 请求需要的指标
 	includeMetrics := c.QueryArray("include_metrics")
 
+	// revision 查询目标模型的历史版本, 缓存和持久化数据按当前版本生成, 查询历史版本时不使用.
+	// 只固定目标模型本身的定义, 其引用的指标模型仍按当前版本读取
+	revision, err := validatePinnedRevision(ctx, c.Query(interfaces.QueryParam_Revision), []string{modelID})
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		// 设置 trace 的错误信息的 attributes
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		// 记录异常日志
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description,
+			httpErr.BaseError.ErrorDetails))
+
+		rest.ReplyError(c, httpErr)
+
+		attrs = append(attrs, attribute.Int("status_code", httpErr.HTTPCode))
+		return
+	}
+	if revision > 0 {
+		ctx = context.WithValue(ctx, interfaces.MODEL_REVISION_KEY, interfaces.ModelRevisionPin{
+			ModelID:  modelID,
+			Revision: revision,
+		})
+		ignoringMemoryCache = "true"
+		ignoringStoreCache = "true"
+	}
+
 	queryParam, err := validateObjectiveModelQueryParameters(ctx, ignoringMemoryCache, ignoringStoreCache,
 		includeModel, includeMetrics)
 	if err != nil {
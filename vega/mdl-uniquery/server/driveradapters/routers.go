@@ -25,10 +25,13 @@ import (
 	"uniquery/logics/data_view"
 	"uniquery/logics/dsl"
 	"uniquery/logics/event"
+	"uniquery/logics/event_lifecycle"
+	"uniquery/logics/export"
 	"uniquery/logics/log_group"
 	"uniquery/logics/metric_model"
 	"uniquery/logics/objective_model"
 	"uniquery/logics/promql"
+	"uniquery/logics/result_cache"
 	utrace "uniquery/logics/trace"
 	"uniquery/logics/trace_model"
 	"uniquery/version"
@@ -76,10 +79,13 @@ type restHandler struct {
 	dvService     interfaces.DataViewService
 	dslService    interfaces.DslService
 	eService      interfaces.EventService
+	elService     interfaces.EventLifecycleService
+	exportService interfaces.ExportService
 	lgService     interfaces.LogGroupService
 	mmService     interfaces.MetricModelService
 	omService     interfaces.ObjectiveModelService
 	promqlService interfaces.PromQLService
+	rCache        interfaces.ResultCache
 	tService      interfaces.TraceService
 	tmService     interfaces.TraceModelService
 
@@ -97,10 +103,13 @@ func NewRestHandler(appSetting *common.AppSetting) RestHandler {
 		dvService:     data_view.NewDataViewService(appSetting),
 		dslService:    dsl.NewDslService(appSetting),
 		eService:      event.NewEventService(appSetting),
+		elService:     event_lifecycle.NewEventLifecycleService(appSetting),
+		exportService: export.NewExportService(appSetting, mmService),
 		lgService:     log_group.NewLogGroupService(appSetting),
 		mmService:     mmService,
 		omService:     objective_model.NewobjectiveModelService(appSetting),
 		promqlService: promql.NewPromQLService(appSetting, mmService),
+		rCache:        result_cache.NewResultCache(appSetting),
 		tService:      utrace.NewTraceService(appSetting),
 		tmService:     trace_model.NewTraceModelService(appSetting),
 	}
@@ -173,6 +182,12 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		apiV1.POST("/data-views/:view_ids", r.verifyJsonContentTypeMiddleWare(), r.GetViewDataByEx)
 		apiV1.POST("/data-view-pits", r.verifyJsonContentTypeMiddleWare(), r.DeleteDataViewPitsByEx)
 
+		// 视图和指标数据的异步导出接口
+		apiV1.POST("/export-jobs", r.verifyJsonContentTypeMiddleWare(), r.CreateExportJobByEx)
+		apiV1.GET("/export-jobs/:job_id", r.GetExportJobByEx)
+		apiV1.GET("/export-jobs/:job_id/file", r.DownloadExportFileByEx)
+		apiV1.DELETE("/export-jobs/:job_id", r.DeleteExportJobByEx)
+
 		// 链路查询接口
 		// (1) 预览span列表
 		apiV1.POST("/simulate-traces/:trace_id/spans", r.verifyJsonContentTypeMiddleWare(), r.PreviewSpanListByEx)
@@ -190,6 +205,10 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		apiV1.POST("/simulate-traces/:trace_id/spans/:span_id/related-logs", r.verifyJsonContentTypeMiddleWare(), r.PreviewSpanRelatedLogListByEx)
 		// (8) 查询span的关联日志列表
 		apiV1.POST("/trace-models/:trace_model_id/traces/:trace_id/spans/:span_id/related-logs", r.GetSpanRelatedLogListByEx)
+		// (9) 查询服务依赖拓扑
+		apiV1.POST("/trace-models/:trace_model_id/service-dependencies", r.verifyJsonContentTypeMiddleWare(), r.GetServiceDependenciesByEx)
+		// (10) 查询操作的RED指标
+		apiV1.POST("/trace-models/:trace_model_id/red-metrics", r.verifyJsonContentTypeMiddleWare(), r.GetREDMetricsByEx)
 
 		// 目标模型的指标查询接口
 		apiV1.POST("/objective-models", r.verifyJsonContentTypeMiddleWare(), r.ObjectiveSimulateByEx)
@@ -198,6 +217,18 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		// 事件模型的数据查询接口
 		apiV1.POST("/events", r.QueryByEx)
 		apiV1.GET("/event-models/:event_model_id/events/:event_id", r.QuerySingleEventByEventIdByEx)
+		apiV1.PUT("/event-models/:event_model_id/events/:event_id/status", r.verifyJsonContentTypeMiddleWare(), r.UpdateEventStatusByEx)
+
+		// 事件的静默规则接口
+		apiV1.POST("/event-silences", r.verifyJsonContentTypeMiddleWare(), r.CreateEventSilenceByEx)
+		apiV1.GET("/event-silences", r.ListEventSilencesByEx)
+		apiV1.DELETE("/event-silences/:silence_id", r.DeleteEventSilenceByEx)
+
+		// 事件的通知路由接口
+		apiV1.POST("/event-routes", r.verifyJsonContentTypeMiddleWare(), r.CreateEventRouteByEx)
+		apiV1.GET("/event-routes", r.ListEventRoutesByEx)
+		apiV1.PUT("/event-routes/:route_id", r.verifyJsonContentTypeMiddleWare(), r.UpdateEventRouteByEx)
+		apiV1.DELETE("/event-routes/:route_id", r.DeleteEventRouteByEx)
 	}
 
 	apiInV1 := c.Group("/api/mdl-uniquery/in/v1")
@@ -205,12 +236,20 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		// 指标查询接口
 		apiInV1.POST("/metric-model", r.verifyJsonContentTypeMiddleWare(), r.SimulateByIn)
 		apiInV1.POST("/metric-models/:model_ids", r.verifyJsonContentTypeMiddleWare(), r.GetMetricModelDataByIn)
+		// 删除指标模型的查询结果缓存
+		apiInV1.DELETE("/metric-models/:model_ids/cache", r.DeleteMetricModelCacheByIn)
 
 		// 视图查询接口
 		apiInV1.POST("/data-views", r.verifyJsonContentTypeMiddleWare(), r.ViewSimulateByIn)
 		apiInV1.POST("/data-views/:view_ids", r.verifyJsonContentTypeMiddleWare(), r.GetViewDataByIn)
 		apiInV1.POST("/data-view-pits", r.verifyJsonContentTypeMiddleWare(), r.DeleteDataViewPitsByIn)
 
+		// 视图和指标数据的异步导出接口
+		apiInV1.POST("/export-jobs", r.verifyJsonContentTypeMiddleWare(), r.CreateExportJobByIn)
+		apiInV1.GET("/export-jobs/:job_id", r.GetExportJobByIn)
+		apiInV1.GET("/export-jobs/:job_id/file", r.DownloadExportFileByIn)
+		apiInV1.DELETE("/export-jobs/:job_id", r.DeleteExportJobByIn)
+
 		// 目标模型的指标查询接口
 		apiInV1.POST("/objective-models", r.verifyJsonContentTypeMiddleWare(), r.ObjectiveSimulateByIn)
 		apiInV1.POST("/objective-models/:model_id", r.verifyJsonContentTypeMiddleWare(), r.GetObjectiveModelDataByIn)
@@ -218,6 +257,18 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		// 事件模型的数据查询接口
 		apiInV1.POST("/events", r.QueryByIn)
 		apiInV1.GET("/event-models/:event_model_id/events/:event_id", r.QuerySingleEventByEventIdByIn)
+		apiInV1.PUT("/event-models/:event_model_id/events/:event_id/status", r.verifyJsonContentTypeMiddleWare(), r.UpdateEventStatusByIn)
+
+		// 事件的静默规则接口
+		apiInV1.POST("/event-silences", r.verifyJsonContentTypeMiddleWare(), r.CreateEventSilenceByIn)
+		apiInV1.GET("/event-silences", r.ListEventSilencesByIn)
+		apiInV1.DELETE("/event-silences/:silence_id", r.DeleteEventSilenceByIn)
+
+		// 事件的通知路由接口
+		apiInV1.POST("/event-routes", r.verifyJsonContentTypeMiddleWare(), r.CreateEventRouteByIn)
+		apiInV1.GET("/event-routes", r.ListEventRoutesByIn)
+		apiInV1.PUT("/event-routes/:route_id", r.verifyJsonContentTypeMiddleWare(), r.UpdateEventRouteByIn)
+		apiInV1.DELETE("/event-routes/:route_id", r.DeleteEventRouteByIn)
 
 		// 链路查询内部接口
 		// (1) 预览span列表
@@ -236,6 +287,10 @@ func (r *restHandler) RegisterPublic(c *gin.Engine) {
 		apiInV1.POST("/simulate-traces/:trace_id/spans/:span_id/related-logs", r.verifyJsonContentTypeMiddleWare(), r.PreviewSpanRelatedLogListByIn)
 		// (8) 查询span的关联日志列表
 		apiInV1.POST("/trace-models/:trace_model_id/traces/:trace_id/spans/:span_id/related-logs", r.GetSpanRelatedLogListByIn)
+		// (9) 查询服务依赖拓扑
+		apiInV1.POST("/trace-models/:trace_model_id/service-dependencies", r.verifyJsonContentTypeMiddleWare(), r.GetServiceDependenciesByIn)
+		// (10) 查询操作的RED指标
+		apiInV1.POST("/trace-models/:trace_model_id/red-metrics", r.verifyJsonContentTypeMiddleWare(), r.GetREDMetricsByIn)
 	}
 
 	// promql api 遵循prometheus api规则开放对应api给grafana
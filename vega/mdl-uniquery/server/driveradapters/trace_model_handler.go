@@ -1014,3 +1014,251 @@ func (r *restHandler) GetSpanRelatedLogList(c *gin.Context, visitor rest.Visitor
 		X_REQUEST_TOOK: time.Since(start).String(),
 	})
 }
+
+func (r *restHandler) GetServiceDependenciesByEx(c *gin.Context) {
+	logger.Debug("Handler GetServiceDependenciesByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver层: 查询服务依赖拓扑", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.GetServiceDependencies(c, visitor)
+}
+
+func (r *restHandler) GetServiceDependenciesByIn(c *gin.Context) {
+	logger.Debug("Handler GetServiceDependenciesByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.GetServiceDependencies(c, visitor)
+}
+
+// 查询服务依赖拓扑
+func (r *restHandler) GetServiceDependencies(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler GetServiceDependencies Start")
+	start := time.Now()
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver层: 查询服务依赖拓扑", trace.WithSpanKind(trace.SpanKindServer))
+	defer func() {
+		span.End()
+		logger.Debug("Handler GetServiceDependencies End")
+	}()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	// 设置与API相关的Attributes
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	// 1. check重载请求头
+	method := c.GetHeader(interfaces.HTTP_HEADER_METHOD_OVERRIDE)
+	if method != http.MethodGet {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_InvalidParameter_OverrideMethod)
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, httpErr, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	// 2. 获取并校验url上的trace_model_id
+	modelID := c.Param("trace_model_id")
+	if modelID == "" {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_TraceModel_InvalidParameter_ModelID).
+			WithErrorDetails("No invalid trace model id was passed in")
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, httpErr, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	// 3. 接收并校验request body
+	queryParams := interfaces.TraceAnalyticsQueryParams{}
+	err := c.ShouldBindJSON(&queryParams)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_InvalidParameter_RequestBody).
+			WithErrorDetails("Binding paramter failed: " + err.Error())
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, httpErr, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	err = validateTraceAnalyticsQueryParams(ctx, queryParams)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, err, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	// 4. 根据modelID查询链路模型对象
+	model, err := r.tmService.GetTraceModelByID(ctx, modelID)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, err, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	// 5. 调用logic层, 查询服务依赖拓扑
+	graph, err := r.tmService.GetServiceDependencies(ctx, model, queryParams)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, err, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOkWithHeaders(c, http.StatusOK, graph, map[string]string{
+		X_REQUEST_TOOK: time.Since(start).String(),
+	})
+}
+
+func (r *restHandler) GetREDMetricsByEx(c *gin.Context) {
+	logger.Debug("Handler GetREDMetricsByEx Start")
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c),
+		"driver层: 查询操作的RED指标", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	// 校验token
+	visitor, err := r.verifyOAuth(ctx, c)
+	if err != nil {
+		return
+	}
+	r.GetREDMetrics(c, visitor)
+}
+
+func (r *restHandler) GetREDMetricsByIn(c *gin.Context) {
+	logger.Debug("Handler GetREDMetricsByIn Start")
+
+	visitor := GenerateVisitor(c)
+	r.GetREDMetrics(c, visitor)
+}
+
+// 查询操作的RED指标
+func (r *restHandler) GetREDMetrics(c *gin.Context, visitor rest.Visitor) {
+	logger.Debug("Handler GetREDMetrics Start")
+	start := time.Now()
+
+	ctx, span := ar_trace.Tracer.Start(rest.GetLanguageCtx(c), "driver层: 查询操作的RED指标", trace.WithSpanKind(trace.SpanKindServer))
+	defer func() {
+		span.End()
+		logger.Debug("Handler GetREDMetrics End")
+	}()
+
+	accountInfo := interfaces.AccountInfo{
+		ID:   visitor.ID,
+		Type: string(visitor.Type),
+	}
+	// accountID 存入 context 中
+	ctx = context.WithValue(ctx, interfaces.ACCOUNT_INFO_KEY, accountInfo)
+
+	// 设置与API相关的Attributes
+	o11y.AddHttpAttrs4API(span, o11y.GetAttrsByGinCtx(c))
+
+	// 1. check重载请求头
+	method := c.GetHeader(interfaces.HTTP_HEADER_METHOD_OVERRIDE)
+	if method != http.MethodGet {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_InvalidParameter_OverrideMethod)
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, httpErr, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	// 2. 获取并校验url上的trace_model_id
+	modelID := c.Param("trace_model_id")
+	if modelID == "" {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_TraceModel_InvalidParameter_ModelID).
+			WithErrorDetails("No invalid trace model id was passed in")
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, httpErr, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	// 3. 接收并校验request body
+	queryParams := interfaces.REDMetricsQueryParams{}
+	err := c.ShouldBindJSON(&queryParams)
+	if err != nil {
+		httpErr := rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_InvalidParameter_RequestBody).
+			WithErrorDetails("Binding paramter failed: " + err.Error())
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, httpErr, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	queryParams, err = validateREDMetricsQueryParams(ctx, queryParams)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.Error(ctx, fmt.Sprintf("%s. %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails))
+
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, err, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	// 4. 根据modelID查询链路模型对象
+	model, err := r.tmService.GetTraceModelByID(ctx, modelID)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, err, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	// 5. 调用logic层, 查询RED指标
+	result, err := r.tmService.GetREDMetrics(ctx, model, queryParams)
+	if err != nil {
+		httpErr := err.(*rest.HTTPError)
+		o11y.AddHttpAttrs4HttpError(span, httpErr)
+		rest.ReplyErrorWithHeaders(c, err, map[string]string{
+			X_REQUEST_TOOK: time.Since(start).String(),
+		})
+		return
+	}
+
+	o11y.AddHttpAttrs4Ok(span, http.StatusOK)
+	rest.ReplyOkWithHeaders(c, http.StatusOK, result, map[string]string{
+		X_REQUEST_TOOK: time.Since(start).String(),
+	})
+}
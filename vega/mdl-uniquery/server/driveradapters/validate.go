@@ -253,6 +253,26 @@ func ValidateHeaderMethodOverride(ctx context.Context, headerMethod string) erro
 	return nil
 }
 
+// 校验查询固定的模型版本, 仅支持单个模型, 未指定时返回 0
+func validatePinnedRevision(ctx context.Context, revisionStr string, modelIDs []string) (int, error) {
+	if revisionStr == "" {
+		return 0, nil
+	}
+
+	revision, err := strconv.Atoi(revisionStr)
+	if err != nil || revision < 1 {
+		return 0, rest.NewHTTPError(ctx, http.StatusBadRequest, rest.PublicError_BadRequest).
+			WithErrorDetails(fmt.Sprintf("The value of param '%s' should be a positive integer, but got '%s'",
+				interfaces.QueryParam_Revision, revisionStr))
+	}
+	if len(modelIDs) != 1 {
+		return 0, rest.NewHTTPError(ctx, http.StatusBadRequest, rest.PublicError_BadRequest).
+			WithErrorDetails(fmt.Sprintf("The param '%s' can only be specified for a single model, but got ids '%s'",
+				interfaces.QueryParam_Revision, strings.Join(modelIDs, ",")))
+	}
+	return revision, nil
+}
+
 // 指标模型必要参数的非空校验
 func ValidateMetricModelSimulate(ctx context.Context, query *interfaces.MetricModelQuery) error {
 
@@ -355,8 +375,12 @@ func ValidateMetricModelSimulate(ctx context.Context, query *interfaces.MetricMo
 			// 	return rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_MetricModel_InvalidParameter_DataSourceType)
 			// }
 		} else {
-			// 计算公式非空
-			if query.QueryType != interfaces.DSL_CONFIG && query.Formula == "" {
+			// 计算公式非空, red 的指标配置在 formula_config 中
+			if query.QueryType == interfaces.RED {
+				if query.FormulaConfig == nil {
+					return rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_MetricModel_NullParameter_FormulaConfig)
+				}
+			} else if query.QueryType != interfaces.DSL_CONFIG && query.Formula == "" {
 				return rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_MetricModel_NullParameter_Formula)
 			}
 			// 非sql的数据源类型是数据视图data_view
@@ -1579,6 +1603,63 @@ func validateParamsWhenGetRelatedLogList(ctx context.Context, params interfaces.
 	return params, nil
 }
 
+// 基于链路模型查询的校验函数(3): 链路分析时间范围的校验
+func validateTraceAnalyticsQueryParams(ctx context.Context, params interfaces.TraceAnalyticsQueryParams) error {
+	if params.Start <= 0 {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_InvalidParameter_Start).
+			WithErrorDetails("The start must be a positive unix timestamp in milliseconds")
+	}
+
+	if params.End <= 0 || params.End < params.Start {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_InvalidParameter_End).
+			WithErrorDetails("The end must be a unix timestamp in milliseconds and not less than the start")
+	}
+
+	return nil
+}
+
+// 基于链路模型查询的校验函数(4): RED指标查询参数的校验
+func validateREDMetricsQueryParams(ctx context.Context, params interfaces.REDMetricsQueryParams) (interfaces.REDMetricsQueryParams, error) {
+	// 1. 校验时间范围
+	err := validateTraceAnalyticsQueryParams(ctx, params.TraceAnalyticsQueryParams)
+	if err != nil {
+		return params, err
+	}
+
+	// 2. 校验步长, 并限制每条序列的点数
+	if params.Step == "" {
+		params.Step = interfaces.DEFAULT_TRACE_ANALYTICS_STEP
+	}
+
+	stepT, err := convert.ParseDuration(params.Step)
+	if err != nil {
+		return params, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_TraceModel_InvalidParameter_Step).
+			WithErrorDetails(err.Error())
+	}
+
+	params.StepMs = stepT.Milliseconds()
+	if params.StepMs <= 0 {
+		return params, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_TraceModel_InvalidParameter_Step).
+			WithErrorDetails("The step must be at least 1ms")
+	}
+
+	if (params.End-params.Start)/params.StepMs > interfaces.MAX_TRACE_ANALYTICS_POINTS {
+		return params, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_TraceModel_InvalidParameter_Step).
+			WithErrorDetails(fmt.Sprintf("Exceeded maximum resolution of %d points per timeseries. Try decreasing the query resolution (?step=XX)",
+				interfaces.MAX_TRACE_ANALYTICS_POINTS))
+	}
+
+	// 3. 校验指标名
+	for _, metric := range params.Metrics {
+		if _, ok := interfaces.RED_METRICS[metric]; !ok {
+			return params, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_TraceModel_InvalidParameter_Metrics).
+				WithErrorDetails(fmt.Sprintf("The metric %s is not one of [rate, errors, error_rate, duration_p50, duration_p95, duration_p99]", metric))
+		}
+	}
+
+	return params, nil
+}
+
 // 目标模型数据预览参数校验
 func ValidateObjectiveModelSimulate(ctx context.Context, query *interfaces.ObjectiveModelQuery) error {
 	// 校验指标类型非空
@@ -2673,6 +2673,58 @@ func TestValidateParamsWhenGetRelatedLogList(t *testing.T) {
 	})
 }
 
+func TestValidateREDMetricsQueryParams(t *testing.T) {
+	Convey("Test validateREDMetricsQueryParams", t, func() {
+		timeRange := interfaces.TraceAnalyticsQueryParams{Start: 1700000000000, End: 1700003600000}
+
+		Convey("Validate failed, caused by the invalid start", func() {
+			_, err := validateREDMetricsQueryParams(testCtx, interfaces.REDMetricsQueryParams{})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_InvalidParameter_Start)
+		})
+
+		Convey("Validate failed, caused by the end is less than start", func() {
+			_, err := validateREDMetricsQueryParams(testCtx, interfaces.REDMetricsQueryParams{
+				TraceAnalyticsQueryParams: interfaces.TraceAnalyticsQueryParams{Start: 2, End: 1},
+			})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_InvalidParameter_End)
+		})
+
+		Convey("Validate failed, caused by the invalid step", func() {
+			_, err := validateREDMetricsQueryParams(testCtx, interfaces.REDMetricsQueryParams{
+				TraceAnalyticsQueryParams: timeRange,
+				Step:                      "abc",
+			})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_InvalidParameter_Step)
+		})
+
+		Convey("Validate failed, caused by too many points", func() {
+			_, err := validateREDMetricsQueryParams(testCtx, interfaces.REDMetricsQueryParams{
+				TraceAnalyticsQueryParams: timeRange,
+				Step:                      "100ms",
+			})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_InvalidParameter_Step)
+		})
+
+		Convey("Validate failed, caused by the invalid metric", func() {
+			_, err := validateREDMetricsQueryParams(testCtx, interfaces.REDMetricsQueryParams{
+				TraceAnalyticsQueryParams: timeRange,
+				Metrics:                   []string{"latency"},
+			})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_InvalidParameter_Metrics)
+		})
+
+		Convey("Validate succeed with default step", func() {
+			params, err := validateREDMetricsQueryParams(testCtx, interfaces.REDMetricsQueryParams{
+				TraceAnalyticsQueryParams: timeRange,
+				Metrics:                   []string{interfaces.RED_METRIC_RATE},
+			})
+			So(err, ShouldBeNil)
+			So(params.Step, ShouldEqual, interfaces.DEFAULT_TRACE_ANALYTICS_STEP)
+			So(params.StepMs, ShouldEqual, 60000)
+		})
+	})
+}
+
 func TestValidateSearchAfterAndPit(t *testing.T) {
 	Convey("Test validateSearchAfterAndPit", t, func() {
 
@@ -3281,3 +3333,30 @@ func TestValidateRequestMetrics(t *testing.T) {
 		})
 	})
 }
+
+func TestValidatePinnedRevision(t *testing.T) {
+	Convey("Test validatePinnedRevision", t, func() {
+
+		Convey("Revision not specified", func() {
+			revision, err := validatePinnedRevision(testCtx, "", []string{"1", "2"})
+			So(err, ShouldBeNil)
+			So(revision, ShouldEqual, 0)
+		})
+
+		Convey("Revision is not a positive integer", func() {
+			_, err := validatePinnedRevision(testCtx, "0", []string{"1"})
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Revision specified for more than one model", func() {
+			_, err := validatePinnedRevision(testCtx, "2", []string{"1", "2"})
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Validate succeed", func() {
+			revision, err := validatePinnedRevision(testCtx, "2", []string{"1"})
+			So(err, ShouldBeNil)
+			So(revision, ShouldEqual, 2)
+		})
+	})
+}
@@ -33,7 +33,8 @@ const (
 	Uniquery_DataView_UnsupportDataSourceType             = "Uniquery.DataView.UnsupportDataSourceType"
 
 	// 403
-	Uniquery_DataView_InvalidFieldPermission_Sort = "Uniquery.DataView.InvalidFieldPermission.Sort"
+	Uniquery_DataView_InvalidFieldPermission_MaskedField = "Uniquery.DataView.InvalidFieldPermission.MaskedField"
+	Uniquery_DataView_InvalidFieldPermission_Sort        = "Uniquery.DataView.InvalidFieldPermission.Sort"
 
 	// 404
 	Uniquery_DataView_DataViewNotFound                 = "Uniquery.DataView.DataViewNotFound"
@@ -54,6 +55,7 @@ const (
 	Uniquery_DataView_InternalError_GetSearchAfterValueFailed      = "Uniquery.DataView.InternalError.GetSearchAfterValueFailed"
 	Uniquery_DataView_InternalError_GetScrollIdFailed              = "Uniquery.DataView.InternalError.GetScrollIdFailed"
 	Uniquery_DataView_InternalError_GetTotalFailed                 = "Uniquery.DataView.InternalError.GetTotalFailed"
+	Uniquery_DataView_InternalError_GetUserAttributesFailed        = "Uniquery.DataView.InternalError.GetUserAttributesFailed"
 	Uniquery_DataView_InternalError_InvalidReferenceView           = "Uniquery.DataView.InternalError.InvalidReferenceView"
 	Uniquery_DataView_InternalError_LoadIndexShardsFailed          = "Uniquery.DataView.InternalError.LoadIndexShardsFailed"
 	Uniquery_DataView_InternalError_MarshalFailed                  = "Uniquery.DataView.InternalError.MarshalFailed"
@@ -92,6 +94,7 @@ var (
 		Uniquery_DataView_UnsupportDataSourceType,
 
 		// 403
+		Uniquery_DataView_InvalidFieldPermission_MaskedField,
 		Uniquery_DataView_InvalidFieldPermission_Sort,
 
 		// 404
@@ -112,6 +115,7 @@ var (
 		Uniquery_DataView_InternalError_GetSearchAfterValueFailed,
 		Uniquery_DataView_InternalError_GetScrollIdFailed,
 		Uniquery_DataView_InternalError_GetTotalFailed,
+		Uniquery_DataView_InternalError_GetUserAttributesFailed,
 		Uniquery_DataView_InternalError_InvalidReferenceView,
 		Uniquery_DataView_InternalError_LoadIndexShardsFailed,
 		Uniquery_DataView_InternalError_MarshalFailed,
@@ -111,7 +111,9 @@ func init() {
 	locale.Register()
 	rest.Register(errCodeList)
 	rest.Register(dataViewErrCodeList)
+	rest.Register(eventLifecycleErrCodeList)
 	rest.Register(eventModelErrCodeList)
+	rest.Register(exportErrCodeList)
 	rest.Register(logGroupErrCodeList)
 	rest.Register(metricModelErrCodeList)
 	rest.Register(objectiveModelErrCodeList)
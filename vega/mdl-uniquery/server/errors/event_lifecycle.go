@@ -0,0 +1,47 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package errors
+
+// 事件生命周期
+const (
+	// 400
+	Uniquery_EventLifecycle_NotEnabled                 = "Uniquery.EventLifecycle.NotEnabled"
+	Uniquery_EventLifecycle_InvalidParameter_Matcher   = "Uniquery.EventLifecycle.InvalidParameter.Matcher"
+	Uniquery_EventLifecycle_InvalidParameter_TimeRange = "Uniquery.EventLifecycle.InvalidParameter.TimeRange"
+	Uniquery_EventLifecycle_InvalidParameter_Route     = "Uniquery.EventLifecycle.InvalidParameter.Route"
+
+	// 404
+	Uniquery_EventLifecycle_LifecycleNotFound = "Uniquery.EventLifecycle.LifecycleNotFound"
+	Uniquery_EventLifecycle_SilenceNotFound   = "Uniquery.EventLifecycle.SilenceNotFound"
+	Uniquery_EventLifecycle_RouteNotFound     = "Uniquery.EventLifecycle.RouteNotFound"
+
+	// 409
+	Uniquery_EventLifecycle_InvalidTransition = "Uniquery.EventLifecycle.InvalidTransition"
+
+	// 500
+	Uniquery_EventLifecycle_InternalError_StoreFailed = "Uniquery.EventLifecycle.InternalError.StoreFailed"
+)
+
+var (
+	eventLifecycleErrCodeList = []string{
+		// 400
+		Uniquery_EventLifecycle_NotEnabled,
+		Uniquery_EventLifecycle_InvalidParameter_Matcher,
+		Uniquery_EventLifecycle_InvalidParameter_TimeRange,
+		Uniquery_EventLifecycle_InvalidParameter_Route,
+
+		// 404
+		Uniquery_EventLifecycle_LifecycleNotFound,
+		Uniquery_EventLifecycle_SilenceNotFound,
+		Uniquery_EventLifecycle_RouteNotFound,
+
+		// 409
+		Uniquery_EventLifecycle_InvalidTransition,
+
+		// 500
+		Uniquery_EventLifecycle_InternalError_StoreFailed,
+	}
+)
@@ -0,0 +1,45 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package errors
+
+// 异步导出
+const (
+	// 400
+	Uniquery_Export_InvalidParameter_FileName    = "Uniquery.Export.InvalidParameter.FileName"
+	Uniquery_Export_InvalidParameter_SourceQuery = "Uniquery.Export.InvalidParameter.SourceQuery"
+
+	// 404
+	Uniquery_Export_JobNotFound = "Uniquery.Export.JobNotFound"
+
+	// 409
+	Uniquery_Export_JobNotCompleted = "Uniquery.Export.JobNotCompleted"
+
+	// 500
+	Uniquery_Export_InternalError_CreateFileFailed = "Uniquery.Export.InternalError.CreateFileFailed"
+	Uniquery_Export_InternalError_DeleteFileFailed = "Uniquery.Export.InternalError.DeleteFileFailed"
+	Uniquery_Export_InternalError_OpenFileFailed   = "Uniquery.Export.InternalError.OpenFileFailed"
+	Uniquery_Export_InternalError_StoreFailed      = "Uniquery.Export.InternalError.StoreFailed"
+)
+
+var (
+	exportErrCodeList = []string{
+		// 400
+		Uniquery_Export_InvalidParameter_FileName,
+		Uniquery_Export_InvalidParameter_SourceQuery,
+
+		// 404
+		Uniquery_Export_JobNotFound,
+
+		// 409
+		Uniquery_Export_JobNotCompleted,
+
+		// 500
+		Uniquery_Export_InternalError_CreateFileFailed,
+		Uniquery_Export_InternalError_DeleteFileFailed,
+		Uniquery_Export_InternalError_OpenFileFailed,
+		Uniquery_Export_InternalError_StoreFailed,
+	}
+)
@@ -69,6 +69,7 @@ const (
 
 	// 500
 	Uniquery_MetricModel_InternalError                               = "Uniquery.MetricModel.InternalError"
+	Uniquery_MetricModel_InternalError_DeleteResultCacheFailed       = "Uniquery.MetricModel.InternalError.DeleteResultCacheFailed"
 	Uniquery_MetricModel_InternalError_ExecPromQLFailed              = "Uniquery.MetricModel.InternalError.ExecPromQLFailed"
 	Uniquery_MetricModel_InternalError_FetchDatasFromVegaFailed      = "Uniquery.MetricModel.InternalError.FetchDatasFromVegaFailed"
 	Uniquery_MetricModel_InternalError_GetFieldsFailed               = "Uniquery.MetricModel.InternalError.GetFieldsFailed"
@@ -150,6 +151,7 @@ var (
 
 		// 500
 		Uniquery_MetricModel_InternalError,
+		Uniquery_MetricModel_InternalError_DeleteResultCacheFailed,
 		Uniquery_MetricModel_InternalError_ExecPromQLFailed,
 		Uniquery_MetricModel_InternalError_FetchDatasFromVegaFailed,
 		Uniquery_MetricModel_InternalError_GetFieldsFailed,
@@ -9,7 +9,11 @@ package errors
 // 链路模型
 const (
 	// 400
-	Uniquery_TraceModel_InvalidParameter_ModelID = "Uniquery.TraceModel.InvalidParameter.ModelID"
+	Uniquery_TraceModel_InvalidParameter_ModelID  = "Uniquery.TraceModel.InvalidParameter.ModelID"
+	Uniquery_TraceModel_InvalidParameter_Step     = "Uniquery.TraceModel.InvalidParameter.Step"
+	Uniquery_TraceModel_InvalidParameter_Metrics  = "Uniquery.TraceModel.InvalidParameter.Metrics"
+	Uniquery_TraceModel_UnsupportedTraceAnalytics = "Uniquery.TraceModel.UnsupportedTraceAnalytics"
+	Uniquery_TraceModel_SpanScanLimitExceeded     = "Uniquery.TraceModel.SpanScanLimitExceeded"
 
 	// 404
 	Uniquery_TraceModel_TraceModelNotFound = "Uniquery.TraceModel.TraceModelNotFound"
@@ -26,12 +30,18 @@ const (
 	Uniquery_TraceModel_InternalError_ProcessDataConnectionFailed      = "Uniquery.TraceModel.InternalError.ProcessDataConnectionFailed"
 	Uniquery_TraceModel_InternalError_GetTingYunTraceListFailed        = "Uniquery.TraceModel.InternalError.GetTingYunTraceListFailed"
 	Uniquery_TraceModel_InternalError_GetTingYunTraceDetailFailed      = "Uniquery.TraceModel.InternalError.GetTingYunTraceDetailFailed"
+	Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed         = "Uniquery.TraceModel.InternalError.QueryJaegerStorageFailed"
+	Uniquery_TraceModel_InternalError_ParseSpanStatsFailed             = "Uniquery.TraceModel.InternalError.ParseSpanStatsFailed"
 )
 
 var (
 	traceModelErrCodeList = []string{
 		// 400
+		Uniquery_TraceModel_InvalidParameter_Metrics,
 		Uniquery_TraceModel_InvalidParameter_ModelID,
+		Uniquery_TraceModel_InvalidParameter_Step,
+		Uniquery_TraceModel_UnsupportedTraceAnalytics,
+		Uniquery_TraceModel_SpanScanLimitExceeded,
 
 		// 404
 		Uniquery_TraceModel_SpanNotFound,
@@ -44,7 +54,9 @@ var (
 		Uniquery_TraceModel_InternalError_GetTingYunTraceListFailed,
 		Uniquery_TraceModel_InternalError_GetTraceModelByIDFailed,
 		Uniquery_TraceModel_InternalError_GetUnderlyingDataSouceTypeFailed,
+		Uniquery_TraceModel_InternalError_ParseSpanStatsFailed,
 		Uniquery_TraceModel_InternalError_ProcessDataConnectionFailed,
+		Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed,
 		Uniquery_TraceModel_InternalError_SimulateCreateTraceModelFailed,
 		Uniquery_TraceModel_InternalError_SimulateUpdateTraceModelFailed,
 		Uniquery_TraceModel_InternalError_SplitSpanIDFailed,
@@ -6,6 +6,7 @@
 package interfaces
 
 import (
+	"context"
 	"math"
 	"strings"
 
@@ -24,7 +25,11 @@ const (
 	HTTP_HEADER_ACCOUNT_ID      = "x-account-id"
 	HTTP_HEADER_ACCOUNT_TYPE    = "x-account-type"
 
-	ACCOUNT_INFO_KEY contextKey = "x-account-info" // 避免直接使用string
+	ACCOUNT_INFO_KEY   contextKey = "x-account-info" // 避免直接使用string
+	MODEL_REVISION_KEY contextKey = "x-model-revision"
+
+	// 查询指定版本的模型定义
+	QueryParam_Revision = "revision"
 
 	ADMIN_ID   string = "266c6a42-6131-4d62-8f39-853e7093701c"
 	ADMIN_TYPE        = string(rest.VisitorType_User)
@@ -46,6 +51,21 @@ var (
 	Special = strings.NewReplacer(`\`, `\\\\`, `'`, `\'`, `%`, `\%`, `_`, `\_`)
 )
 
+// 查询时固定的模型版本, 只作用于请求的模型本身, 其依赖的模型仍使用当前版本
+type ModelRevisionPin struct {
+	ModelID  string
+	Revision int
+}
+
+// 指定模型的固定版本, 未固定时返回 0
+func GetPinnedRevision(ctx context.Context, modelID string) int {
+	pin, ok := ctx.Value(MODEL_REVISION_KEY).(ModelRevisionPin)
+	if !ok || pin.ModelID != modelID {
+		return 0
+	}
+	return pin.Revision
+}
+
 type AccountInfo struct {
 	ID   string `json:"id"`
 	Type string `json:"type"`
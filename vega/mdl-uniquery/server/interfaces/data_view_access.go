@@ -122,6 +122,74 @@ type SQLNodeCfg struct {
 	SQLExpression string `json:"sql_expression" mapstructure:"sql_expression"`
 }
 
+// 节点类型为aggregate的节点配置，输出字段为分组字段和聚合结果字段
+type AggregateNodeCfg struct {
+	GroupBy      []string       `json:"group_by" mapstructure:"group_by"` // 传递 name
+	Aggregations []*Aggregation `json:"aggregations" mapstructure:"aggregations"`
+	Having       *cond.CondCfg  `json:"having,omitempty" mapstructure:"having"` // 聚合后过滤，字段为输出字段
+}
+
+type Aggregation struct {
+	Function string `json:"function" mapstructure:"function"`
+	Field    string `json:"field" mapstructure:"field"` // 传递 name，count 可以为 *
+	Alias    string `json:"alias" mapstructure:"alias"` // 输出字段的 name
+}
+
+// 节点类型为window的节点配置，输出字段为输入节点的全部字段和窗口函数结果字段
+type WindowNodeCfg struct {
+	PartitionBy []string          `json:"partition_by" mapstructure:"partition_by"` // 传递 name
+	OrderBy     []*WindowOrder    `json:"order_by" mapstructure:"order_by"`
+	Frame       *WindowFrame      `json:"frame,omitempty" mapstructure:"frame"`
+	Functions   []*WindowFunction `json:"functions" mapstructure:"functions"`
+	Filters     *cond.CondCfg     `json:"filters,omitempty" mapstructure:"filters"` // 窗口计算后过滤，例如 row_number 为 1 实现去重
+}
+
+type WindowOrder struct {
+	Field     string `json:"field" mapstructure:"field"`         // 传递 name
+	Direction string `json:"direction" mapstructure:"direction"` // asc 或 desc
+}
+
+type WindowFrame struct {
+	Unit  string            `json:"unit" mapstructure:"unit"` // rows 或 range
+	Start *WindowFrameBound `json:"start" mapstructure:"start"`
+	End   *WindowFrameBound `json:"end" mapstructure:"end"`
+}
+
+type WindowFrameBound struct {
+	Type   string `json:"type" mapstructure:"type"`
+	Offset int    `json:"offset,omitempty" mapstructure:"offset"` // type 为 preceding 或 following 时的行数或范围
+}
+
+type WindowFunction struct {
+	Function string `json:"function" mapstructure:"function"`
+	Field    string `json:"field,omitempty" mapstructure:"field"`   // 传递 name，排名函数不需要
+	Offset   int    `json:"offset,omitempty" mapstructure:"offset"` // lag、lead 的偏移行数，默认 1
+	Alias    string `json:"alias" mapstructure:"alias"`             // 输出字段的 name
+}
+
+// 节点类型为pivot的节点配置，pivot_field 的每个取值聚合为一列
+type PivotNodeCfg struct {
+	GroupBy    []string      `json:"group_by" mapstructure:"group_by"`       // 传递 name
+	PivotField string        `json:"pivot_field" mapstructure:"pivot_field"` // 取值作为列的字段
+	ValueField string        `json:"value_field" mapstructure:"value_field"` // 聚合的字段
+	Function   string        `json:"function" mapstructure:"function"`
+	Values     []*PivotValue `json:"values" mapstructure:"values"`
+}
+
+type PivotValue struct {
+	Value string `json:"value" mapstructure:"value"`
+	Alias string `json:"alias" mapstructure:"alias"` // 输出字段的 name
+}
+
+// 节点类型为unpivot的节点配置，columns 中的每一列转为一行
+type UnpivotNodeCfg struct {
+	KeepFields   []string `json:"keep_fields" mapstructure:"keep_fields"` // 保留的字段，传递 name
+	Columns      []string `json:"columns" mapstructure:"columns"`         // 转为行的字段，类型需一致
+	NameField    string   `json:"name_field" mapstructure:"name_field"`   // 存放列名的输出字段 name
+	ValueField   string   `json:"value_field" mapstructure:"value_field"` // 存放列值的输出字段 name
+	IncludeNulls bool     `json:"include_nulls" mapstructure:"include_nulls"`
+}
+
 type ExcelConfig struct {
 	SheetName        string `json:"sheet_name"`          // sheet页，逗号分隔
 	StartCell        string `json:"start_cell"`          // 起始单元格
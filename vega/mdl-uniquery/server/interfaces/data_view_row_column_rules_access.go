@@ -11,6 +11,32 @@ import (
 	cond "uniquery/common/condition"
 )
 
+const (
+	// 字段脱敏方式
+	FIELD_MASK_TYPE_PARTIAL = "partial"
+	FIELD_MASK_TYPE_HASH    = "hash"
+	FIELD_MASK_TYPE_NULLIFY = "nullify"
+	FIELD_MASK_TYPE_BUCKET  = "bucket"
+
+	// 日期分桶的时间单位
+	FIELD_MASK_BUCKET_UNIT_HOUR  = "hour"
+	FIELD_MASK_BUCKET_UNIT_DAY   = "day"
+	FIELD_MASK_BUCKET_UNIT_MONTH = "month"
+	FIELD_MASK_BUCKET_UNIT_YEAR  = "year"
+
+	DEFAULT_FIELD_MASK_CHAR = "*"
+
+	// 行过滤条件中可引用的用户属性, value_from 为 user 时 value 为属性名
+	USER_ATTR_ID            = "id"
+	USER_ATTR_TYPE          = "type"
+	USER_ATTR_NAME          = "name"
+	USER_ATTR_DEPARTMENT_ID = "department_id"
+	USER_ATTR_ROLE          = "role"
+	USER_ATTR_EMAIL         = "email"
+	// 自定义属性, 如 custom_attr.region
+	USER_ATTR_CUSTOM_PREFIX = "custom_attr."
+)
+
 // 行列规则结构体
 type DataViewRowColumnRule struct {
 	RuleID     string        `json:"id"`
@@ -19,6 +45,7 @@ type DataViewRowColumnRule struct {
 	Tags       []string      `json:"tags"`
 	Comment    string        `json:"comment"`
 	Fields     []string      `json:"fields"`
+	FieldMasks []*FieldMask  `json:"field_masks"`
 	RowFilters *cond.CondCfg `json:"row_filters"`
 	// CreateTime int64         `json:"create_time"`
 	// UpdateTime int64         `json:"update_time"`
@@ -29,6 +56,21 @@ type DataViewRowColumnRule struct {
 	Operations []string `json:"operations"`
 }
 
+// 字段脱敏配置, 脱敏字段必须在规则的 fields 里
+// partial: 保留前 KeepPrefix 个和后 KeepSuffix 个字符, 其余用 MaskChar 替换
+// hash: 替换为哈希值, 相同的值哈希后仍相同, 可用于关联分析
+// nullify: 替换为 null
+// bucket: 数值按 BucketSize 向下取整, 日期按 BucketUnit 截断
+type FieldMask struct {
+	Field      string  `json:"field"`
+	Type       string  `json:"type"`
+	KeepPrefix int     `json:"keep_prefix,omitempty"`
+	KeepSuffix int     `json:"keep_suffix,omitempty"`
+	MaskChar   string  `json:"mask_char,omitempty"`
+	BucketSize float64 `json:"bucket_size,omitempty"`
+	BucketUnit string  `json:"bucket_unit,omitempty"`
+}
+
 type ListRowColumnRulesResult struct {
 	Entries    []*DataViewRowColumnRule `json:"entries"`
 	TotalCount int                      `json:"total_count"`
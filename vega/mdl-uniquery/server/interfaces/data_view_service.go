@@ -23,11 +23,15 @@ const (
 	QueryType_SQL       = "SQL"
 	QueryType_IndexBase = "IndexBase"
 
-	DataScopeNodeType_View   = "view"
-	DataScopeNodeType_Join   = "join"
-	DataScopeNodeType_Union  = "union"
-	DataScopeNodeType_Sql    = "sql"
-	DataScopeNodeType_Output = "output"
+	DataScopeNodeType_View      = "view"
+	DataScopeNodeType_Join      = "join"
+	DataScopeNodeType_Union     = "union"
+	DataScopeNodeType_Sql       = "sql"
+	DataScopeNodeType_Output    = "output"
+	DataScopeNodeType_Aggregate = "aggregate"
+	DataScopeNodeType_Window    = "window"
+	DataScopeNodeType_Pivot     = "pivot"
+	DataScopeNodeType_Unpivot   = "unpivot"
 
 	// join的类型
 	JoinType_Inner     = "inner"
@@ -38,6 +42,37 @@ const (
 	// union的类型
 	UnionType_All      = "all"
 	UnionType_Distinct = "distinct"
+
+	// 聚合函数，用于 aggregate 和 pivot 节点，sum 以后的也可用于 window 节点
+	AggregateFunc_Count         = "count"
+	AggregateFunc_CountDistinct = "count_distinct"
+	AggregateFunc_Sum           = "sum"
+	AggregateFunc_Avg           = "avg"
+	AggregateFunc_Max           = "max"
+	AggregateFunc_Min           = "min"
+
+	// 窗口函数
+	WindowFunc_RowNumber  = "row_number"
+	WindowFunc_Rank       = "rank"
+	WindowFunc_DenseRank  = "dense_rank"
+	WindowFunc_Lag        = "lag"
+	WindowFunc_Lead       = "lead"
+	WindowFunc_FirstValue = "first_value"
+	WindowFunc_LastValue  = "last_value"
+
+	// 窗口帧的单位和边界
+	WindowFrameUnit_Rows  = "rows"
+	WindowFrameUnit_Range = "range"
+
+	WindowFrameBound_UnboundedPreceding = "unbounded_preceding"
+	WindowFrameBound_Preceding          = "preceding"
+	WindowFrameBound_CurrentRow         = "current_row"
+	WindowFrameBound_Following          = "following"
+	WindowFrameBound_UnboundedFollowing = "unbounded_following"
+
+	// 排序方向
+	SortDirection_Asc  = "asc"
+	SortDirection_Desc = "desc"
 )
 
 // 字段范围
@@ -101,6 +136,47 @@ var (
 		UnionType_All:      {},
 		UnionType_Distinct: {},
 	}
+
+	// 由单个输入节点派生字段的节点类型
+	DerivedNodeTypeMap = map[string]struct{}{
+		DataScopeNodeType_Aggregate: {},
+		DataScopeNodeType_Window:    {},
+		DataScopeNodeType_Pivot:     {},
+		DataScopeNodeType_Unpivot:   {},
+	}
+
+	AggregateFuncMap = map[string]struct{}{
+		AggregateFunc_Count:         {},
+		AggregateFunc_CountDistinct: {},
+		AggregateFunc_Sum:           {},
+		AggregateFunc_Avg:           {},
+		AggregateFunc_Max:           {},
+		AggregateFunc_Min:           {},
+	}
+
+	WindowFuncMap = map[string]struct{}{
+		WindowFunc_RowNumber:  {},
+		WindowFunc_Rank:       {},
+		WindowFunc_DenseRank:  {},
+		WindowFunc_Lag:        {},
+		WindowFunc_Lead:       {},
+		WindowFunc_FirstValue: {},
+		WindowFunc_LastValue:  {},
+		AggregateFunc_Count:   {},
+		AggregateFunc_Sum:     {},
+		AggregateFunc_Avg:     {},
+		AggregateFunc_Max:     {},
+		AggregateFunc_Min:     {},
+	}
+
+	// 窗口帧边界的先后顺序，起始边界不能晚于结束边界
+	WindowFrameBoundOrder = map[string]int{
+		WindowFrameBound_UnboundedPreceding: 0,
+		WindowFrameBound_Preceding:          1,
+		WindowFrameBound_CurrentRow:         2,
+		WindowFrameBound_Following:          3,
+		WindowFrameBound_UnboundedFollowing: 4,
+	}
 )
 
 // 视图查询外部接口统一返回结构
@@ -198,6 +274,8 @@ type DataViewService interface {
 	// GetDataViewIDByName(ctx context.Context, viewName string) (string, error)
 	RetrieveSingleViewData(ctx context.Context, viewID string, query *DataViewQueryV1) (*ViewInternalResponse, error)
 	CountMultiFields(ctx context.Context, viewID string, query *DataViewQueryV1, fields []string, sep string) (map[string]int64, error)
+	AggregateViewData(ctx context.Context, viewID string, query *DataViewQueryV1, aggs map[string]any) ([]byte, error)
+	MaskViewData(ctx context.Context, viewID string, entries []map[string]any) error
 	LoadIndexShards(ctx context.Context, indices string) ([]byte, int, error)
 	GetIndices(ctx context.Context, baseTypes []string, start int64, end int64) ([]*IndexShards, []string, int, error)
 	GetDataViewByID(ctx context.Context, viewID string, includeDataScopeView bool) (*DataView, error)
@@ -0,0 +1,175 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+)
+
+const (
+	// 事件状态：firing 触发中，acknowledged 已确认，resolved 已恢复
+	EventStatus_Firing       = "firing"
+	EventStatus_Acknowledged = "acknowledged"
+	EventStatus_Resolved     = "resolved"
+
+	// 事件状态、静默规则和通知路由的存储类型
+	EventLifecycleStore_Memory = "memory"
+	EventLifecycleStore_Redis  = "redis"
+
+	// 通知渠道
+	NotifyChannel_Webhook = "webhook"
+	NotifyChannel_Email   = "email"
+	NotifyChannel_Kafka   = "kafka"
+
+	// 标签匹配方式
+	LabelMatch_Equal    = "="
+	LabelMatch_NotEqual = "!="
+	LabelMatch_Regex    = "=~"
+	LabelMatch_NotRegex = "!~"
+
+	// 系统自动变更状态时的操作人
+	EVENT_OPERATOR_SYSTEM = "system"
+	// 单个事件保留的状态变更记录条数
+	EVENT_TRANSITION_MAX_SIZE = 20
+)
+
+// 事件的生命周期。
+// 同一事件模型、相同标签的事件使用同一个去重 key，共享一个生命周期：
+// 首次出现时为 firing，确认后为 acknowledged，恢复后为 resolved，恢复后再出现则重新进入 firing
+type EventLifecycle struct {
+	DedupKey      string            `json:"dedup_key"`
+	EventModelID  string            `json:"event_model_id"`
+	Labels        map[string]string `json:"labels"`
+	Status        string            `json:"status"`
+	Level         int               `json:"level"`
+	FirstEventID  string            `json:"first_event_id"`
+	LastEventID   string            `json:"last_event_id"`
+	FirstTime     int64             `json:"first_time"`
+	LastTime      int64             `json:"last_time"`
+	Count         int64             `json:"count"`
+	LastEmitTime  int64             `json:"last_emit_time"`
+	SuppressCount int64             `json:"suppress_count"`
+	SilencedBy    []string          `json:"silenced_by,omitempty"`
+	Transitions   []EventTransition `json:"transitions"`
+}
+
+// 事件的状态变更记录
+type EventTransition struct {
+	From     string `json:"from,omitempty"`
+	To       string `json:"to"`
+	Operator string `json:"operator"`
+	Comment  string `json:"comment,omitempty"`
+	Time     int64  `json:"time"`
+}
+
+// 变更事件状态的请求体
+type EventStatusUpdateRequest struct {
+	Status  string `json:"status" binding:"required,oneof=acknowledged resolved"`
+	Comment string `json:"comment"`
+}
+
+// 标签匹配条件，Operator 为空时按相等匹配
+type LabelMatcher struct {
+	Name     string `json:"name" binding:"required"`
+	Value    string `json:"value"`
+	Operator string `json:"operator" binding:"omitempty,oneof== != =~ !~"`
+}
+
+// 静默规则，生效期间匹配的事件仍然记录状态，但不发送通知
+type EventSilence struct {
+	ID           string         `json:"id"`
+	EventModelID string         `json:"event_model_id"`
+	Matchers     []LabelMatcher `json:"matchers"`
+	StartTime    int64          `json:"start_time"`
+	EndTime      int64          `json:"end_time"`
+	Comment      string         `json:"comment"`
+	Creator      AccountInfo    `json:"creator"`
+	CreateTime   int64          `json:"create_time"`
+}
+
+// 创建静默规则的请求体，StartTime 为空时立即生效
+type EventSilenceRequest struct {
+	EventModelID string         `json:"event_model_id" binding:"required"`
+	Matchers     []LabelMatcher `json:"matchers" binding:"required,min=1,dive"`
+	StartTime    int64          `json:"start_time" binding:"omitempty,gte=0"`
+	EndTime      int64          `json:"end_time" binding:"required,gt=0"`
+	Comment      string         `json:"comment"`
+}
+
+// 通过接口管理的通知路由，匹配条件和渠道参数与配置的路由相同。
+// 路由只作用于 EventModelIDs 中的事件模型，在配置的路由之后按创建时间先后匹配
+type EventRoute struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	EventModelIDs []string          `json:"event_model_ids"`
+	Levels        []int             `json:"levels"`
+	Labels        map[string]string `json:"labels"`
+	Channel       string            `json:"channel"`
+	WebhookURL    string            `json:"webhook_url,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	EmailTo       []string          `json:"email_to,omitempty"`
+	KafkaTopic    string            `json:"kafka_topic,omitempty"`
+	Continue      bool              `json:"continue"`
+	Creator       AccountInfo       `json:"creator"`
+	CreateTime    int64             `json:"create_time"`
+	UpdateTime    int64             `json:"update_time"`
+}
+
+// 创建或修改通知路由的请求体
+type EventRouteRequest struct {
+	Name          string            `json:"name" binding:"required"`
+	EventModelIDs []string          `json:"event_model_ids" binding:"required,min=1"`
+	Levels        []int             `json:"levels"`
+	Labels        map[string]string `json:"labels"`
+	Channel       string            `json:"channel" binding:"required,oneof=webhook email kafka"`
+	WebhookURL    string            `json:"webhook_url"`
+	Headers       map[string]string `json:"headers"`
+	EmailTo       []string          `json:"email_to"`
+	KafkaTopic    string            `json:"kafka_topic"`
+	Continue      bool              `json:"continue"`
+}
+
+// 发送到通知渠道的内容
+type EventNotification struct {
+	Route          string            `json:"route"`
+	Status         string            `json:"status"`
+	DedupKey       string            `json:"dedup_key"`
+	EventID        string            `json:"event_id"`
+	EventModelID   string            `json:"event_model_id"`
+	EventModelName string            `json:"event_model_name"`
+	Title          string            `json:"title"`
+	Level          int               `json:"level"`
+	LevelName      string            `json:"level_name"`
+	Message        string            `json:"message"`
+	Labels         map[string]string `json:"labels"`
+	TriggerTime    int64             `json:"trigger_time"`
+	FirstTime      int64             `json:"first_time"`
+	Count          int64             `json:"count"`
+	Operator       string            `json:"operator,omitempty"`
+	Comment        string            `json:"comment,omitempty"`
+}
+
+//go:generate mockgen -source ../interfaces/event_lifecycle.go -destination ../interfaces/mock/mock_event_lifecycle.go
+type EventLifecycleService interface {
+	// 是否开启了事件生命周期
+	Enabled() bool
+	// 按去重 key 更新事件状态，返回需要持久化的事件，抑制窗口内重复的事件被丢弃；
+	// 未被静默的事件按路由规则异步发送通知
+	Process(ctx context.Context, em EventModel, events IEvents) IEvents
+	// 查询事件所属的生命周期，不存在时返回 nil
+	GetLifecycle(ctx context.Context, eventModelID string, labels map[string]string) (*EventLifecycle, error)
+	// 变更事件状态
+	UpdateStatus(ctx context.Context, event IEvent, req *EventStatusUpdateRequest) (*EventLifecycle, error)
+
+	CreateSilence(ctx context.Context, req *EventSilenceRequest) (*EventSilence, error)
+	ListSilences(ctx context.Context, eventModelID string) ([]*EventSilence, error)
+	DeleteSilence(ctx context.Context, silenceID string) error
+
+	CreateRoute(ctx context.Context, req *EventRouteRequest) (*EventRoute, error)
+	ListRoutes(ctx context.Context, eventModelID string) ([]*EventRoute, error)
+	UpdateRoute(ctx context.Context, routeID string, req *EventRouteRequest) (*EventRoute, error)
+	DeleteRoute(ctx context.Context, routeID string) error
+}
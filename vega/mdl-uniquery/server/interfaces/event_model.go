@@ -23,6 +23,18 @@ const (
 
 	DEFAULT_SUBSCRIBE_TOPIC = "%s.mdl.view"
 	ATOMIC_EVENT_DATA_TOPIC = "%s.mdl.atomic_event"
+
+	// 统计检测类型及其检测算法
+	DETECT_RULE_TYPE_STATISTICAL = "statistical_detect"
+	STATISTICAL_ALGO_ZSCORE      = "zscore"
+	STATISTICAL_ALGO_EWMA        = "ewma"
+	STATISTICAL_ALGO_MAD         = "mad"
+	STATISTICAL_ALGO_SEASONAL    = "seasonal"
+
+	// 统计检测的检测方向
+	STATISTICAL_DIRECTION_BOTH = "both"
+	STATISTICAL_DIRECTION_UP   = "up"
+	STATISTICAL_DIRECTION_DOWN = "down"
 )
 
 var (
@@ -105,6 +117,26 @@ type DetectRule struct {
 	UpdateTime   int64             `json:"update_time,omitempty" binding:"omitempty"`
 	DetectAlgo   string            `json:"detect_algo"`
 	AnalysisAlgo map[string]string `json:"analysis_algo" binding:"omitempty"`
+	// 统计检测配置，仅 statistical_detect 类型使用
+	StatisticalConfig *StatisticalConfig `json:"statistical_config,omitempty"`
+}
+
+// 统计检测配置，未配置的参数使用默认值
+type StatisticalConfig struct {
+	// 指标序列的步长
+	Step string `json:"step,omitempty"`
+	// 灵敏度，正常范围为基线值 ± sensitivity * 离散程度
+	Sensitivity float64 `json:"sensitivity,omitempty"`
+	// 滚动窗口的点数，zscore、mad 以此计算基线，ewma 以此作为回看的历史点数
+	Window int `json:"window,omitempty"`
+	// 预热样本数，基线样本数不足时不做判定
+	WarmUp int `json:"warm_up,omitempty"`
+	// ewma 的平滑系数
+	Alpha float64 `json:"alpha,omitempty"`
+	// seasonal 回看的周数
+	Weeks int `json:"weeks,omitempty"`
+	// 检测方向：both、up、down
+	Direction string `json:"direction,omitempty"`
 }
 
 type FormulaItem struct {
@@ -150,6 +182,8 @@ type Records []map[string]any
 
 type SourceRecords struct {
 	Records Records `json:"source_records" form:"source_records"`
+	// 统计检测时，早于此时间的记录只作为基线，不产生事件
+	DetectStart int64 `json:"-"`
 }
 
 type Record map[string]any
@@ -234,6 +268,8 @@ type BaseEvent struct {
 	// GraphName string `json:"graph_name"`
 	//NOTE: 接收索引文档id
 	DocId string `json:"__id,omitempty"`
+	// 事件的生命周期，开启事件生命周期后查询时返回
+	Lifecycle *EventLifecycle `json:"lifecycle,omitempty"`
 }
 
 type EventContext struct {
@@ -254,6 +290,18 @@ type EventContext struct {
 	// RunId             string         `json:"run_id,omitempty" form:"run_id"`
 	// Suggestion        map[string]any `json:"suggestion,omitempty"`
 	IncidentId string `json:"incident_id,omitempty"`
+	// 统计检测事件的正常范围和观测值
+	Anomaly *AnomalyBand `json:"anomaly,omitempty"`
+}
+
+// 统计检测计算出的正常范围
+type AnomalyBand struct {
+	Algo     string  `json:"algo"`
+	Observed float64 `json:"observed"`
+	Expected float64 `json:"expected"`
+	Lower    float64 `json:"lower"`
+	Upper    float64 `json:"upper"`
+	Score    float64 `json:"score"`
 }
 
 type PreOrderEvent struct {
@@ -13,6 +13,7 @@ import (
 type EventService interface {
 	Query(ctx context.Context, queryReq EventQueryReq) (int, any, []Records, error)
 	QuerySingleEventByEventId(ctx context.Context, query EventDetailsQueryReq) (IEvent, error)
+	UpdateEventStatus(ctx context.Context, query EventDetailsQueryReq, req *EventStatusUpdateRequest) (*EventLifecycle, error)
 }
 
 type EventEngine interface {
@@ -0,0 +1,96 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+	"os"
+)
+
+const (
+	// 导出的数据来源
+	ExportSourceType_DataView    = "data_view"
+	ExportSourceType_MetricModel = "metric_model"
+
+	// 导出文件格式
+	ExportFormat_CSV     = "csv"
+	ExportFormat_NDJSON  = "ndjson"
+	ExportFormat_Parquet = "parquet"
+
+	// 导出任务的存储
+	ExportJobStore_Memory = "memory"
+	ExportJobStore_Redis  = "redis"
+
+	// 导出任务状态
+	ExportJobStatus_Pending   = "pending"
+	ExportJobStatus_Running   = "running"
+	ExportJobStatus_Completed = "completed"
+	ExportJobStatus_Failed    = "failed"
+	ExportJobStatus_Canceled  = "canceled"
+
+	// 视图导出每页拉取的条数
+	EXPORT_PAGE_SIZE = SearchAfter_Limit
+	// 索引库视图导出时 pit 的保留时间
+	EXPORT_PIT_KEEP_ALIVE = "10m"
+
+	// 指标导出时的时间列和值列
+	ExportMetricColumn_Time  = "__time"
+	ExportMetricColumn_Value = "__value"
+)
+
+var (
+	ExportFormatContentTypeMap = map[string]string{
+		ExportFormat_CSV:     "text/csv; charset=utf-8",
+		ExportFormat_NDJSON:  "application/x-ndjson",
+		ExportFormat_Parquet: "application/vnd.apache.parquet",
+	}
+)
+
+// 创建导出任务请求体
+type ExportJobRequest struct {
+	SourceType string `json:"source_type" binding:"required,oneof=data_view metric_model"`
+	SourceID   string `json:"source_id" binding:"required"`
+	Format     string `json:"format" binding:"required,oneof=csv ndjson parquet"`
+	FileName   string `json:"file_name"`
+
+	// 视图导出的查询条件，分页参数由导出任务控制
+	ViewQuery *DataViewQueryV2 `json:"view_query,omitempty"`
+	// 指标模型导出的查询条件
+	MetricQuery *MetricModelQuery `json:"metric_query,omitempty"`
+}
+
+// 导出任务
+type ExportJob struct {
+	ID         string `json:"id"`
+	SourceType string `json:"source_type"`
+	SourceID   string `json:"source_id"`
+	Format     string `json:"format"`
+	FileName   string `json:"file_name"`
+	Status     string `json:"status"`
+	// 已导出的行数，total_rows 为查询返回的总数，未知时不返回
+	ExportedRows int64   `json:"exported_rows"`
+	TotalRows    *int64  `json:"total_rows,omitempty"`
+	Progress     float64 `json:"progress"`
+	FileSize     int64   `json:"file_size"`
+	Error        string  `json:"error,omitempty"`
+	CreateTime   int64   `json:"create_time"`
+	UpdateTime   int64   `json:"update_time"`
+	FinishTime   int64   `json:"finish_time,omitempty"`
+	ExpireTime   int64   `json:"expire_time,omitempty"`
+
+	Creator  AccountInfo `json:"-"`
+	FilePath string      `json:"-"`
+}
+
+//go:generate mockgen -source ../interfaces/export_service.go -destination ../interfaces/mock/mock_export_service.go
+type ExportService interface {
+	CreateExportJob(ctx context.Context, req *ExportJobRequest) (*ExportJob, error)
+	GetExportJob(ctx context.Context, jobID string) (*ExportJob, error)
+	// 打开已完成任务的导出文件，由调用方关闭
+	OpenExportFile(ctx context.Context, jobID string) (*ExportJob, *os.File, error)
+	// 删除任务，运行中的任务会被取消
+	DeleteExportJob(ctx context.Context, jobID string) error
+}
@@ -39,6 +39,8 @@ const (
 	DSL        string = "dsl"
 	DSL_CONFIG string = "dsl_config"
 	SQL        string = "sql"
+	// 以链路模型为数据源的RED指标, data_source.id 为链路模型id
+	RED string = "red"
 
 	// 数据源类型
 	// DATA_SOURCE_DATA_VIEW       = "data_view"
@@ -372,6 +374,13 @@ type DerivedConfig struct {
 	ConditionStr      string             `json:"condition_str,omitempty"`
 }
 
+// RED指标配置项, 服务和操作为空时不过滤
+type REDConfig struct {
+	Metric      string `json:"metric"`
+	ServiceName string `json:"service_name,omitempty"`
+	Operation   string `json:"operation,omitempty"`
+}
+
 type DependMetricModel struct {
 	ID        string `json:"id"`
 	GroupName string `json:"group_name,omitempty"`
@@ -508,7 +517,7 @@ func IsValidMetricType(m string) bool {
 }
 
 func IsValidQueryType(m string) bool {
-	return m == PROMQL || m == DSL || m == DSL_CONFIG || m == SQL
+	return m == PROMQL || m == DSL || m == DSL_CONFIG || m == SQL || m == RED
 }
 
 func IsPersistMetric(metricName string) bool {
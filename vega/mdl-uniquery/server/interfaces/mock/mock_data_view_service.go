@@ -42,6 +42,21 @@ func (m *MockDataViewService) EXPECT() *MockDataViewServiceMockRecorder {
 	return m.recorder
 }
 
+// AggregateViewData mocks base method.
+func (m *MockDataViewService) AggregateViewData(ctx context.Context, viewID string, query *interfaces.DataViewQueryV1, aggs map[string]any) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregateViewData", ctx, viewID, query, aggs)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AggregateViewData indicates an expected call of AggregateViewData.
+func (mr *MockDataViewServiceMockRecorder) AggregateViewData(ctx, viewID, query, aggs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateViewData", reflect.TypeOf((*MockDataViewService)(nil).AggregateViewData), ctx, viewID, query, aggs)
+}
+
 // BuildViewQuery4MetricModel mocks base method.
 func (m *MockDataViewService) BuildViewQuery4MetricModel(ctx context.Context, start, end int64, view *interfaces.DataView) (interfaces.ViewQuery4Metric, error) {
 	m.ctrl.T.Helper()
@@ -182,6 +197,20 @@ func (mr *MockDataViewServiceMockRecorder) LoadIndexShards(ctx, indices interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadIndexShards", reflect.TypeOf((*MockDataViewService)(nil).LoadIndexShards), ctx, indices)
 }
 
+// MaskViewData mocks base method.
+func (m *MockDataViewService) MaskViewData(ctx context.Context, viewID string, entries []map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaskViewData", ctx, viewID, entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MaskViewData indicates an expected call of MaskViewData.
+func (mr *MockDataViewServiceMockRecorder) MaskViewData(ctx, viewID, entries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaskViewData", reflect.TypeOf((*MockDataViewService)(nil).MaskViewData), ctx, viewID, entries)
+}
+
 // RetrieveSingleViewData mocks base method.
 func (m *MockDataViewService) RetrieveSingleViewData(ctx context.Context, viewID string, query *interfaces.DataViewQueryV1) (*interfaces.ViewInternalResponse, error) {
 	m.ctrl.T.Helper()
@@ -72,6 +72,21 @@ func (mr *MockEventServiceMockRecorder) QuerySingleEventByEventId(ctx, query int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySingleEventByEventId", reflect.TypeOf((*MockEventService)(nil).QuerySingleEventByEventId), ctx, query)
 }
 
+// UpdateEventStatus mocks base method.
+func (m *MockEventService) UpdateEventStatus(ctx context.Context, query interfaces.EventDetailsQueryReq, req *interfaces.EventStatusUpdateRequest) (*interfaces.EventLifecycle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEventStatus", ctx, query, req)
+	ret0, _ := ret[0].(*interfaces.EventLifecycle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEventStatus indicates an expected call of UpdateEventStatus.
+func (mr *MockEventServiceMockRecorder) UpdateEventStatus(ctx, query, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventStatus", reflect.TypeOf((*MockEventService)(nil).UpdateEventStatus), ctx, query, req)
+}
+
 // MockEventEngine is a mock of EventEngine interface.
 type MockEventEngine struct {
 	ctrl     *gomock.Controller
@@ -117,3 +117,32 @@ func (mr *MockTraceModelAdapterMockRecorder) GetSpanRelatedLogList(ctx, model, p
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpanRelatedLogList", reflect.TypeOf((*MockTraceModelAdapter)(nil).GetSpanRelatedLogList), ctx, model, params)
 }
+
+// GetSpanStats mocks base method.
+func (m *MockTraceModelAdapter) GetSpanStats(ctx context.Context, model interfaces.TraceModel, params interfaces.SpanStatsQueryParams) ([]interfaces.SpanStatsBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpanStats", ctx, model, params)
+	ret0, _ := ret[0].([]interfaces.SpanStatsBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpanStats indicates an expected call of GetSpanStats.
+func (mr *MockTraceModelAdapterMockRecorder) GetSpanStats(ctx, model, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpanStats", reflect.TypeOf((*MockTraceModelAdapter)(nil).GetSpanStats), ctx, model, params)
+}
+
+// ScanBriefSpans mocks base method.
+func (m *MockTraceModelAdapter) ScanBriefSpans(ctx context.Context, model interfaces.TraceModel, params interfaces.TraceAnalyticsQueryParams, handle func([]*interfaces.BriefSpan_) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanBriefSpans", ctx, model, params, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScanBriefSpans indicates an expected call of ScanBriefSpans.
+func (mr *MockTraceModelAdapterMockRecorder) ScanBriefSpans(ctx, model, params, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanBriefSpans", reflect.TypeOf((*MockTraceModelAdapter)(nil).ScanBriefSpans), ctx, model, params, handle)
+}
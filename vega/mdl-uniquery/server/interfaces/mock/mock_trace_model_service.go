@@ -40,6 +40,36 @@ func (m *MockTraceModelService) EXPECT() *MockTraceModelServiceMockRecorder {
 	return m.recorder
 }
 
+// GetREDMetrics mocks base method.
+func (m *MockTraceModelService) GetREDMetrics(ctx context.Context, model interfaces.TraceModel, params interfaces.REDMetricsQueryParams) (interfaces.MetricModelUniResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetREDMetrics", ctx, model, params)
+	ret0, _ := ret[0].(interfaces.MetricModelUniResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetREDMetrics indicates an expected call of GetREDMetrics.
+func (mr *MockTraceModelServiceMockRecorder) GetREDMetrics(ctx, model, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetREDMetrics", reflect.TypeOf((*MockTraceModelService)(nil).GetREDMetrics), ctx, model, params)
+}
+
+// GetServiceDependencies mocks base method.
+func (m *MockTraceModelService) GetServiceDependencies(ctx context.Context, model interfaces.TraceModel, params interfaces.TraceAnalyticsQueryParams) (interfaces.ServiceDependencyGraph, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceDependencies", ctx, model, params)
+	ret0, _ := ret[0].(interfaces.ServiceDependencyGraph)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceDependencies indicates an expected call of GetServiceDependencies.
+func (mr *MockTraceModelServiceMockRecorder) GetServiceDependencies(ctx, model, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceDependencies", reflect.TypeOf((*MockTraceModelService)(nil).GetServiceDependencies), ctx, model, params)
+}
+
 // GetSpan mocks base method.
 func (m *MockTraceModelService) GetSpan(ctx context.Context, model interfaces.TraceModel, params interfaces.SpanQueryParams) (interfaces.SpanDetail, error) {
 	m.ctrl.T.Helper()
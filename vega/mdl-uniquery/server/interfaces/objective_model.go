@@ -96,6 +96,21 @@ type SLOObjective struct {
 	GoodMetricModel  *BundleMetricModel     `json:"good_metric_model"`
 	TotalMetricModel *BundleMetricModel     `json:"total_metric_model"`
 	StatusConfig     *ObjectiveStatusConfig `json:"status_config"`
+	BurnRateAlert    *BurnRateAlertConfig   `json:"burn_rate_alert,omitempty"`
+}
+
+// SLO 多窗口多燃烧率告警配置，由 data-model-job 评估
+type BurnRateAlertConfig struct {
+	IndexBase string           `json:"index_base"`
+	Policies  []BurnRatePolicy `json:"policies"`
+}
+
+type BurnRatePolicy struct {
+	Name        string  `json:"name"`
+	LongWindow  string  `json:"long_window"`
+	ShortWindow string  `json:"short_window"`
+	BurnRate    float64 `json:"burn_rate"`
+	Level       int     `json:"level"`
 }
 
 type KPIObjective struct {
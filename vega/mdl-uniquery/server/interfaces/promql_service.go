@@ -26,7 +26,10 @@ const (
 	MAX_OVER_TIME                = "max_over_time"
 	MIN_OVER_TIME                = "min_over_time"
 	COUNT_OVER_TIME              = "count_over_time"
+	PRESENT_OVER_TIME            = "present_over_time"
+	ABSENT_OVER_TIME             = "absent_over_time"
 	DELTA_AGG                    = "delta"
+	RAW_SAMPLES_AGG              = "raw_samples" // 按毫秒分桶取回原始样本点，用于在原始样本点上计算的区间向量函数
 	CUMULATIVE_SUM               = "cumulative_sum"
 	HISTOGRAM_QUANTILE           = "histogram_quantile"
 	K_MINUTE_DOWNTIME            = "continuous_k_minute_downtime"
@@ -46,6 +49,9 @@ const (
 	SHARD_ROUTING_2H             = 2 * time.Hour
 	DEFAULT_LOOK_BACK_DELTA_STR  = "5m"
 	DEFAULT_STEP_DIVISOR         = 5 * time.Minute
+	DEFAULT_SUBQUERY_STEP        = time.Minute      // 子查询未指定步长时的默认步长，与 prometheus 默认的 evaluation_interval 一致
+	DEFAULT_SCRAPE_INTERVAL      = 15 * time.Second // 常用的采集间隔，按原始样本点查询时用于估算每个序列的数据点数
+	MAX_RAW_SAMPLES_PER_SERIES   = 10000            // 按原始样本点查询时，按常用采集间隔估算的每个序列最大样本点数
 	KMINUTE_DOWNTTIME_STEP       = 60000
 )
 
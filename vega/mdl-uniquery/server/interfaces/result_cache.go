@@ -0,0 +1,59 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+)
+
+const (
+	// 结果缓存的存储类型
+	ResultCacheStore_Memory = "memory"
+	ResultCacheStore_Redis  = "redis"
+
+	// 结果缓存的业务范围
+	ResultCacheScope_MetricModel = "metric_model"
+	ResultCacheScope_PromQL      = "promql"
+)
+
+// 结果缓存的 key。
+// ModelID 为空表示与指标模型无关（如直接执行的 promql），Version 为模型的更新时间，
+// 版本不一致的缓存视为失效；Hash 为规范化后的查询条件（含步长、过滤条件）的摘要
+type ResultCacheKey struct {
+	Scope   string
+	ModelID string
+	Version int64
+	Hash    string
+}
+
+// 缓存的序列，Times 为按步长对齐的时间桶，与 Values 一一对应
+type CachedSeries struct {
+	Labels   map[string]string `json:"labels"`
+	Times    []int64           `json:"times"`
+	TimeStrs []string          `json:"time_strs,omitempty"`
+	Values   []any             `json:"values"`
+}
+
+// 按时间范围查询数据。start、end 与原请求的语义相同，由调用方按步长对齐；
+// cacheable 为 false 时本次结果不写入缓存（如实际执行的步长与请求不一致）
+type RangeFetchFunc func(ctx context.Context, start, end int64) (series []*CachedSeries, cacheable bool, err error)
+
+// 按步长对齐的时间范围
+type CacheRange struct {
+	Start      int64 // 请求的开始时间
+	End        int64 // 请求的结束时间
+	FixedStart int64 // 对齐后的第一个时间桶
+	FixedEnd   int64 // 对齐后的最后一个时间桶
+	Step       int64 // 步长，单位毫秒
+}
+
+//go:generate mockgen -source ../interfaces/result_cache.go -destination ../interfaces/mock/mock_result_cache.go
+type ResultCache interface {
+	// 查询时间范围内的数据，已完成的时间桶从缓存读取，只对缓存缺失的首尾区间调用 fetch
+	RangeQuery(ctx context.Context, key ResultCacheKey, r CacheRange, fetch RangeFetchFunc) ([]*CachedSeries, error)
+	// 删除指标模型的全部缓存
+	DeleteModelCache(ctx context.Context, modelIDs []string) error
+}
@@ -11,6 +11,7 @@ import (
 
 const (
 	SOURCE_TYPE_TINGYUN string = "tingyun"
+	SOURCE_TYPE_JAEGER  string = "jaeger"
 )
 
 //go:generate mockgen -source ../interfaces/trace_model_adapter.go -destination ../interfaces/mock/mock_trace_model_adapter.go
@@ -20,4 +21,7 @@ type TraceModelAdapter interface {
 	GetSpanMap(ctx context.Context, model TraceModel, params TraceQueryParams) (map[string]*BriefSpan_, map[string]SpanDetail, error)
 	GetRelatedLogCountMap(ctx context.Context, model TraceModel, params TraceQueryParams) (map[string]int64, error)
 	GetSpanRelatedLogList(ctx context.Context, model TraceModel, params RelatedLogListQueryParams) ([]RelatedLogListEntry, int64, error)
+	GetSpanStats(ctx context.Context, model TraceModel, params SpanStatsQueryParams) ([]SpanStatsBucket, error)
+	// ScanBriefSpans 按 trace_id 排序分页拉取时间范围内的全部 span, 同一 trace 的 span 连续出现, 每页调用一次 handle
+	ScanBriefSpans(ctx context.Context, model TraceModel, params TraceAnalyticsQueryParams, handle func(briefSpans []*BriefSpan_) error) error
 }
@@ -43,6 +43,20 @@ const (
 	SPAN_STATUS_ERROR = "error"
 	SPAN_STATUS_UNSET = "unset"
 	DEFAULT_SEPARATOR = "$_$"
+
+	// 链路分析
+	DEFAULT_TRACE_ANALYTICS_STEP    = "1m"
+	MAX_TRACE_ANALYTICS_POINTS      = 11000
+	MAX_TRACE_ANALYTICS_BUCKET_SIZE = 1000
+	// 服务依赖拓扑单次最多扫描的span数, 超过时需缩小时间范围
+	MAX_DEPENDENCY_SCAN_SPANS = 1000000
+
+	RED_METRIC_RATE         = "rate"
+	RED_METRIC_ERRORS       = "errors"
+	RED_METRIC_ERROR_RATE   = "error_rate"
+	RED_METRIC_DURATION_P50 = "duration_p50"
+	RED_METRIC_DURATION_P95 = "duration_p95"
+	RED_METRIC_DURATION_P99 = "duration_p99"
 )
 
 var (
@@ -73,6 +87,15 @@ var (
 		"error": SPAN_STATUS_ERROR,
 		"unset": SPAN_STATUS_UNSET,
 	}
+
+	RED_METRICS = map[string]struct{}{
+		RED_METRIC_RATE:         {},
+		RED_METRIC_ERRORS:       {},
+		RED_METRIC_ERROR_RATE:   {},
+		RED_METRIC_DURATION_P50: {},
+		RED_METRIC_DURATION_P95: {},
+		RED_METRIC_DURATION_P99: {},
+	}
 )
 
 type SpanListPreviewParams struct {
@@ -180,6 +203,7 @@ type TraceStatisticData struct {
 
 type BriefSpan_ struct {
 	Key             string        `json:"key"`
+	TraceID         string        `json:"-"`
 	Name            string        `json:"__name"`
 	SpanID          string        `json:"__span_id"`
 	ParentSpanID    string        `json:"-"`
@@ -200,6 +224,74 @@ type AbstractRelatedLog struct {
 
 type RelatedLogListEntry map[string]any
 
+// 链路分析查询参数, start与end为毫秒时间戳
+type TraceAnalyticsQueryParams struct {
+	Start       int64  `json:"start"`
+	End         int64  `json:"end"`
+	ServiceName string `json:"service_name"`
+}
+
+// RED指标查询参数
+type REDMetricsQueryParams struct {
+	TraceAnalyticsQueryParams
+	Operation string   `json:"operation"`
+	Step      string   `json:"step"`
+	Metrics   []string `json:"metrics"`
+	StepMs    int64    `json:"-"`
+	// 即时查询时整个时间范围只计算一个点, StepMs 为时间范围的长度
+	IsInstantQuery bool `json:"-"`
+}
+
+// span聚合统计参数, Interval为0时不按时间分桶
+type SpanStatsQueryParams struct {
+	TraceAnalyticsQueryParams
+	Operation        string
+	GroupByOperation bool
+	Interval         int64
+}
+
+// span聚合统计结果, 时延单位均为微秒
+type SpanStatsBucket struct {
+	ServiceName string
+	Operation   string
+	Time        int64
+	Count       int64
+	ErrorCount  int64
+	LatencyStats
+}
+
+type LatencyStats struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// 服务依赖拓扑
+type ServiceDependencyGraph struct {
+	Start     int64                   `json:"start"`
+	End       int64                   `json:"end"`
+	Nodes     []ServiceNode           `json:"nodes"`
+	Edges     []ServiceDependencyEdge `json:"edges"`
+	SpanCount int64                   `json:"span_count"`
+}
+
+type ServiceNode struct {
+	ServiceName string       `json:"service_name"`
+	CallCount   int64        `json:"call_count"`
+	ErrorCount  int64        `json:"error_count"`
+	ErrorRate   float64      `json:"error_rate"`
+	Latency     LatencyStats `json:"latency"`
+}
+
+type ServiceDependencyEdge struct {
+	Source     string       `json:"source"`
+	Target     string       `json:"target"`
+	CallCount  int64        `json:"call_count"`
+	ErrorCount int64        `json:"error_count"`
+	ErrorRate  float64      `json:"error_rate"`
+	Latency    LatencyStats `json:"latency"`
+}
+
 // type RelatedLogListEntry struct {
 // 	AbstractRelatedLog `json:"model_data"`
 // 	RawData            map[string]interface{} `json:"raw_data"`
@@ -211,6 +303,8 @@ type TraceModelService interface {
 	GetTrace(ctx context.Context, model TraceModel, params TraceQueryParams) (TraceDetail_, error)
 	GetSpan(ctx context.Context, model TraceModel, params SpanQueryParams) (SpanDetail, error)
 	GetSpanRelatedLogList(ctx context.Context, model TraceModel, params RelatedLogListQueryParams) ([]RelatedLogListEntry, int64, error)
+	GetServiceDependencies(ctx context.Context, model TraceModel, params TraceAnalyticsQueryParams) (ServiceDependencyGraph, error)
+	GetREDMetrics(ctx context.Context, model TraceModel, params REDMetricsQueryParams) (MetricModelUniResponse, error)
 
 	GetTraceModelByID(ctx context.Context, modelID string) (TraceModel, error)
 	SimulateCreateTraceModel(ctx context.Context, model TraceModel) (TraceModel, error)
@@ -0,0 +1,16 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+)
+
+//go:generate mockgen -source ../interfaces/user_mgmt_access.go -destination ../interfaces/mock/mock_user_mgmt_access.go
+type UserMgmtAccess interface {
+	// 获取用户属性, 属性名见 USER_ATTR_*, 部门和角色为多值属性
+	GetUserAttributes(ctx context.Context, userID string) (map[string]any, error)
+}
@@ -142,6 +142,7 @@ func validateDataScope(ctx context.Context, dvs *dataViewService, view *interfac
 	baseTypeViewMap := make(map[string]string) // 视图 id 和索引库的映射
 	dataScopeViewQueryType := make(map[string]struct{})
 	dataScopeViewDataSourceID := make(map[string]struct{})
+	derivedNodes := make([]*interfaces.DataScopeNode, 0)
 
 	for _, node := range view.DataScope {
 		switch node.Type {
@@ -196,12 +197,33 @@ func validateDataScope(ctx context.Context, dvs *dataViewService, view *interfac
 				view.Fields = node.OutputFields
 			}
 
+		case interfaces.DataScopeNodeType_Aggregate, interfaces.DataScopeNodeType_Window,
+			interfaces.DataScopeNodeType_Pivot, interfaces.DataScopeNodeType_Unpivot:
+			if view.QueryType != interfaces.QueryType_SQL {
+				return nil, nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_DataView_InvalidParameter_DataScope).
+					WithErrorDetails(fmt.Sprintf("The %s node is only supported in sql query type", node.Type))
+			}
+
+			err := validateDerivedNodeInput(ctx, node, nodeMap)
+			if err != nil {
+				return nil, nil, err
+			}
+			derivedNodes = append(derivedNodes, node)
+
 		default:
 			return nil, nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_DataView_InvalidParameter_DataScope).
 				WithErrorDetails("the data scope node type is invalid")
 		}
 	}
 
+	// 派生节点的输出字段依赖输入节点，全部节点校验完后再按依赖顺序推导
+	if len(derivedNodes) > 0 {
+		err := resolveDerivedNodes(ctx, view, derivedNodes)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if len(dataScopeViewQueryType) != 1 {
 		return nil, nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_DataView_InvalidParameter_DataScope).
 			WithErrorDetails("the source view of the custom view must have the same query type")
@@ -524,6 +546,134 @@ func validateOutputNode(ctx context.Context, node *interfaces.DataScopeNode, nod
 	return nil
 }
 
+// aggregate、window、pivot、unpivot 节点只能有一个输入节点
+func validateDerivedNodeInput(ctx context.Context, node *interfaces.DataScopeNode, nodeMap map[string]struct{}) error {
+	if len(node.InputNodes) != 1 {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_DataView_InvalidParameter_DataScope).
+			WithErrorDetails(fmt.Sprintf("The %s node must have one input node", node.Type))
+	}
+
+	inputNode := node.InputNodes[0]
+	if _, ok := nodeMap[inputNode]; !ok {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_DataView_InvalidParameter_DataScope).
+			WithErrorDetails(fmt.Sprintf("The %s node input_node '%s' is not exist", node.Type, inputNode))
+	}
+
+	return nil
+}
+
+// 按依赖顺序推导派生节点的输出字段，校验聚合和窗口计算后的过滤条件，并补全以派生节点为输入的输出节点的字段类型
+func resolveDerivedNodes(ctx context.Context, view *interfaces.DataView, derivedNodes []*interfaces.DataScopeNode) error {
+	nodes := make(map[string]*interfaces.DataScopeNode, len(view.DataScope))
+	for _, node := range view.DataScope {
+		nodes[node.ID] = node
+	}
+
+	resolved := make(map[string][]*cond.ViewField)
+	resolving := make(map[string]struct{})
+	var resolve func(node *interfaces.DataScopeNode) ([]*cond.ViewField, error)
+	resolve = func(node *interfaces.DataScopeNode) ([]*cond.ViewField, error) {
+		if fields, ok := resolved[node.ID]; ok {
+			return fields, nil
+		}
+		if _, ok := interfaces.DerivedNodeTypeMap[node.Type]; !ok {
+			if node.Type == interfaces.DataScopeNodeType_View {
+				return typedViewNodeFields(node), nil
+			}
+			return node.OutputFields, nil
+		}
+
+		if _, ok := resolving[node.ID]; ok {
+			return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_DataView_InvalidParameter_DataScope).
+				WithErrorDetails(fmt.Sprintf("The data scope has a cycle at node '%s'", node.ID))
+		}
+		resolving[node.ID] = struct{}{}
+
+		inputFields, err := resolve(nodes[node.InputNodes[0]])
+		if err != nil {
+			return nil, err
+		}
+		fields, err := deriveNodeFields(node, inputFields)
+		if err != nil {
+			return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_DataView_InvalidParameter_DataScope).
+				WithErrorDetails(fmt.Sprintf("The data scope %s node '%s' config is invalid, %v", node.Type, node.ID, err))
+		}
+
+		// 过滤条件的字段为节点的输出字段
+		fieldsMap := make(map[string]*cond.ViewField, len(fields))
+		for _, field := range fields {
+			fieldsMap[field.Name] = field
+		}
+		httpErr := validateCond(ctx, derivedNodeFilters(node), fieldsMap)
+		if httpErr != nil {
+			return nil, httpErr
+		}
+
+		node.OutputFields = fields
+		resolved[node.ID] = fields
+		return fields, nil
+	}
+
+	for _, node := range derivedNodes {
+		if _, err := resolve(node); err != nil {
+			return err
+		}
+	}
+
+	// 输出节点的字段需在派生节点的输出字段里，未传类型时使用推导的类型
+	for _, node := range view.DataScope {
+		if node.Type != interfaces.DataScopeNodeType_Output {
+			continue
+		}
+		fields, ok := resolved[node.InputNodes[0]]
+		if !ok {
+			continue
+		}
+
+		fieldsMap := make(map[string]*cond.ViewField, len(fields))
+		for _, field := range fields {
+			fieldsMap[field.Name] = field
+		}
+		for _, of := range node.OutputFields {
+			field, ok := fieldsMap[of.Name]
+			if !ok {
+				return rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_DataView_InvalidParameter_DataScope).
+					WithErrorDetails(fmt.Sprintf("The output node field '%s' is not in the input node fields", of.Name))
+			}
+			if of.Type == "" {
+				of.Type = field.Type
+			}
+			if of.OriginalName == "" {
+				of.OriginalName = field.OriginalName
+			}
+		}
+		for _, vf := range view.Fields {
+			if field, ok := fieldsMap[vf.Name]; ok && vf.Type == "" {
+				vf.Type = field.Type
+			}
+		}
+	}
+
+	return nil
+}
+
+// 派生节点计算后的过滤条件，aggregate 节点为 having，window 节点为 filters
+func derivedNodeFilters(node *interfaces.DataScopeNode) *cond.CondCfg {
+	switch node.Type {
+	case interfaces.DataScopeNodeType_Aggregate:
+		var cfg interfaces.AggregateNodeCfg
+		if err := mapstructure.Decode(node.Config, &cfg); err == nil {
+			return cfg.Having
+		}
+	case interfaces.DataScopeNodeType_Window:
+		var cfg interfaces.WindowNodeCfg
+		if err := mapstructure.Decode(node.Config, &cfg); err == nil {
+			return cfg.Filters
+		}
+	}
+	return nil
+}
+
 // 相比handler层的校验，补充对过滤条件字段类型的校验
 // 后续扩充对字段类型和输入字段值是否匹配的校验
 func validateCond(ctx context.Context, cfg *cond.CondCfg, fieldsMap map[string]*cond.ViewField) error {
@@ -52,6 +52,7 @@ type multiFieldStatsDSLParas struct {
 	timeFilterStr   string
 	aggTermStr      string
 	scriptFilterStr string
+	rowFilterStr    string
 }
 
 type Bucket struct {
@@ -68,6 +69,7 @@ type dataViewService struct {
 	osAccess    interfaces.OpenSearchAccess
 	vdsAccess   interfaces.VegaDataSourceAccess
 	vgAccess    interfaces.VegaGatewayAccess
+	umAccess    interfaces.UserMgmtAccess
 	ps          interfaces.PermissionService
 }
 
@@ -81,6 +83,7 @@ func NewDataViewService(appSetting *common.AppSetting) interfaces.DataViewServic
 			osAccess:    logics.OSAccess,
 			vdsAccess:   logics.VDSAccess,
 			vgAccess:    logics.VGAccess,
+			umAccess:    logics.UMAccess,
 			ps:          permission.NewPermissionService(appSetting),
 		}
 
@@ -465,44 +468,21 @@ func (dvs *dataViewService) GetSingleViewData(ctx context.Context, viewID string
 
 	span.SetAttributes(attr.Key("view_id").String(viewID))
 
-	// 决策当前视图id的数据查询权限
-	hasPermission, err := dvs.ps.CheckPermissionWithResult(ctx, interfaces.Resource{
-		ID:   viewID,
-		Type: interfaces.RESOURCE_TYPE_DATA_VIEW,
-	}, []string{interfaces.OPERATION_TYPE_DATA_QUERY})
-
+	// 有data_query权限时查询视图的全量数据，否则按当前用户具有rule_apply权限的行列规则查询
+	rowColumnRules, err := dvs.getEffectiveRowColumnRules(ctx, viewID)
 	if err != nil {
+		span.SetStatus(codes.Error, "Get effective row column rules failed")
 		return nil, err
 	}
-
-	// 如果有data_query权限，返回视图的全量数据
-	// 如果没有data_query权限，则获取视图下的所有行列规则，
-	// 决策当前用户具有rule_apply权限的规则，执行规则过滤查询
-	if !hasPermission {
-		// 获取视图下的所有行列规则
-		rowColumnRules, err := dvs.dvrcrAccess.GetRulesByViewID(ctx, viewID)
-		if err != nil {
-			span.SetStatus(codes.Error, "Get row column rules by view ID failed")
-			return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, rest.PublicError_InternalServerError).
-				WithErrorDetails(err.Error())
-		}
-
-		// 过滤视图下的行列规则，返回当前用户具有rule_apply权限的规则
-		filteredRules, httpErr := dvs.FilterRowColumnRules(ctx, rowColumnRules)
+	if len(rowColumnRules) > 0 {
+		httpErr := checkMaskedFieldsUsage(ctx, query, mergeFieldMasks(rowColumnRules))
 		if httpErr != nil {
-			span.SetStatus(codes.Error, "Filter row column rules failed")
+			span.SetStatus(codes.Error, "Masked fields are used in filters or sort")
 			return nil, httpErr
 		}
 
-		if len(filteredRules) == 0 {
-			errDetails := fmt.Sprintf("Neither data query permission nor row column rules with rule_apply permission for view ID %s", viewID)
-			span.SetStatus(codes.Error, errDetails)
-			return nil, rest.NewHTTPError(ctx, http.StatusForbidden, rest.PublicError_Forbidden).
-				WithErrorDetails(errDetails)
-		}
-
 		// 设置查询参数中的行列规则
-		query.SetRowColumnRules(filteredRules)
+		query.SetRowColumnRules(rowColumnRules)
 	}
 
 	// data-model服务会检查基础权限(data_view_id,'view_detail')
@@ -527,6 +507,13 @@ func (dvs *dataViewService) GetSingleViewData(ctx context.Context, viewID string
 		return nil, httpErr
 	}
 
+	// 按行列规则对输出字段脱敏
+	httpErr = dvs.maskEntries(ctx, res.Entries, rowColumnRules, view.FieldsMap, query.GetCommonParams().Format)
+	if httpErr != nil {
+		span.SetStatus(codes.Error, "Mask view data failed")
+		return nil, httpErr
+	}
+
 	span.SetStatus(codes.Ok, "")
 	return res, nil
 }
@@ -2084,6 +2071,10 @@ func (dvs *dataViewService) CountMultiFields(ctx context.Context, viewID string,
 		return nil, err
 	}
 
+	// 脱敏配置按视图字段名匹配, 保留追加 keyword 后缀前的字段名
+	fieldNames := make([]string, len(fields))
+	copy(fieldNames, fields)
+
 	for i, field := range fields {
 		v, ok := view.FieldsMap[field]
 		if !ok {
@@ -2097,7 +2088,17 @@ func (dvs *dataViewService) CountMultiFields(ctx context.Context, viewID string,
 		fields[i] = field
 	}
 
-	// 3. 获取数据视图底层的索引, 暂时仅支持元数据视图的 DSL 视图
+	// 3. 统计字段需在行列规则的列范围内, 行过滤条件追加到查询中
+	rowColumnRules, err := dvs.getEffectiveRowColumnRules(ctx, viewID)
+	if err != nil {
+		return nil, err
+	}
+	rowFilterStr, masks, err := buildRowColumnRulesAggFilter(ctx, rowColumnRules, view, fieldNames, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 获取数据视图底层的索引, 暂时仅支持元数据视图的 DSL 视图
 	indices, err := dvs.acquireIndices(ctx, view.DataSourceID, query.Start, query.End)
 	if err != nil {
 		o11y.Error(ctx, err.Error())
@@ -2110,31 +2111,32 @@ func (dvs *dataViewService) CountMultiFields(ctx context.Context, viewID string,
 		return map[string]int64{}, nil
 	}
 
-	// 4. prepare dsl parameters
+	// 5. prepare dsl parameters
 	paras, err := dvs.prepareDSLParas(ctx, query, view, fields)
 	if err != nil {
 		o11y.Error(ctx, err.Error())
 		return nil, err
 	}
+	paras.rowFilterStr = rowFilterStr
 
-	// 5. 查找结果
+	// 6. 查找结果
 	stats := make(map[string]int64)
 	for {
-		// 5.1 转成dsl
+		// 6.1 转成dsl
 		dsl, err := dvs.generateMultiFieldStatsDSL(ctx, paras, fields)
 		if err != nil {
 			o11y.Error(ctx, err.Error())
 			return nil, err
 		}
 
-		// 5.2 调用SearchSubmit 查询
+		// 6.2 调用SearchSubmit 查询
 		resBytes, _, err := dvs.osAccess.SearchSubmit(ctx, dsl, indices, 0, interfaces.DEFAULT_PREFERENCE, false)
 		if err != nil {
 			return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
 				uerrors.Uniquery_InternalError_SearchSubmitFailed).WithErrorDetails(err.Error())
 		}
 
-		// 5.3 处理返回结果, 更新
+		// 6.3 处理返回结果, 更新
 		resJson := string(resBytes)
 		bucketStr := gjson.Get(resJson, "aggregations.group_by_fields.buckets").String()
 
@@ -2147,27 +2149,32 @@ func (dvs *dataViewService) CountMultiFields(ctx context.Context, viewID string,
 		}
 
 		for _, bucket := range buckets {
+			keys := []any{bucket.Key}
 			if keyArray, ok := bucket.Key.([]any); ok {
-				vals := make([]string, 0)
-				for i := 0; i < len(keyArray); i++ {
-					vals = append(vals, fmt.Sprint(keyArray[i]))
-				}
+				keys = keyArray
+			}
 
-				spanID := strings.Join(vals, sep)
-				stats[spanID] = bucket.DocCount
-			} else {
-				spanID := fmt.Sprint(bucket.Key)
-				stats[spanID] = bucket.DocCount
+			vals := make([]string, 0, len(keys))
+			for i, key := range keys {
+				if i < len(fieldNames) {
+					if mask, ok := masks[fieldNames[i]]; ok {
+						key = maskValue(key, mask, dvs.appSetting.FieldMaskSetting.HashKey)
+					}
+				}
+				vals = append(vals, fmt.Sprint(key))
 			}
+
+			// 脱敏后不同的原始值可能得到相同的 key, 计数累加
+			stats[strings.Join(vals, sep)] += bucket.DocCount
 		}
 
-		// 5.4 判断是否需要继续分页查询
+		// 6.4 判断是否需要继续分页查询
 		length := len(buckets)
 		if length < interfaces.MAX_SEARCH_RELATED_LOGS_BUCKET {
 			break
 		}
 
-		// 5.5 找到criticalKey
+		// 6.5 找到criticalKey
 		criticalKey := ""
 		if len(fields) == 1 {
 			criticalKey = fmt.Sprint(buckets[length-1].Key)
@@ -2175,7 +2182,7 @@ func (dvs *dataViewService) CountMultiFields(ctx context.Context, viewID string,
 			criticalKey = buckets[length-1].KeyAsString
 		}
 
-		// 5.6 构造script, 并更新paras.scriptFilterStr
+		// 6.6 构造script, 并更新paras.scriptFilterStr
 		str := ""
 		for i, field := range fields {
 			if i == len(fields) {
@@ -2200,6 +2207,92 @@ func (dvs *dataViewService) CountMultiFields(ctx context.Context, viewID string,
 	return stats, nil
 }
 
+// 基于视图的过滤条件与时间范围执行聚合查询, 返回opensearch的原始响应
+func (dvs *dataViewService) AggregateViewData(ctx context.Context, viewID string, query *interfaces.DataViewQueryV1, aggs map[string]any) (resBytes []byte, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic layer: aggregate view data")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	// 1. parseQuery
+	view, err := dvs.parseQuery(ctx, viewID)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return nil, err
+	}
+
+	// 2. 聚合引用的字段需在行列规则的列范围内且未脱敏, 行过滤条件追加到查询中
+	rowColumnRules, err := dvs.getEffectiveRowColumnRules(ctx, viewID)
+	if err != nil {
+		return nil, err
+	}
+	rowFilterStr, _, err := buildRowColumnRulesAggFilter(ctx, rowColumnRules, view, nil, aggs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 获取数据视图底层的索引, 暂时仅支持元数据视图的 DSL 视图
+	indices, err := dvs.acquireIndices(ctx, view.DataSourceID, query.Start, query.End)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return nil, err
+	}
+
+	// 如果索引列表为空，则返回空数据
+	if len(indices) == 0 {
+		span.SetStatus(codes.Ok, "No indices found")
+		return nil, nil
+	}
+
+	// 4. 复用多字段统计的过滤条件, 拼接聚合查询的dsl
+	paras, err := dvs.prepareDSLParas(ctx, query, view, nil)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return nil, err
+	}
+	paras.rowFilterStr = rowFilterStr
+
+	filters := make([]any, 0, 3)
+	for _, filterStr := range []string{paras.baseFilterStr, paras.timeFilterStr, paras.rowFilterStr} {
+		if filterStr == "" {
+			continue
+		}
+
+		var filter any
+		err = sonic.UnmarshalString(filterStr, &filter)
+		if err != nil {
+			o11y.Error(ctx, err.Error())
+			return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+				uerrors.Uniquery_DataView_InternalError_UnmarshalFailed).WithErrorDetails(err.Error())
+		}
+		filters = append(filters, filter)
+	}
+
+	dsl := map[string]any{
+		"size": 0,
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter": filters,
+			},
+		},
+		"aggs": aggs,
+	}
+
+	// 5. 调用SearchSubmit 查询
+	resBytes, _, err = dvs.osAccess.SearchSubmit(ctx, dsl, indices, 0, interfaces.DEFAULT_PREFERENCE, false)
+	if err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+			uerrors.Uniquery_InternalError_SearchSubmitFailed).WithErrorDetails(err.Error())
+	}
+
+	return resBytes, nil
+}
+
 func (dvs *dataViewService) parseQuery(ctx context.Context, viewID string) (*interfaces.DataView, error) {
 	// 1. 获取视图对象信息
 	view, err := dvs.GetDataViewByID(ctx, viewID, false)
@@ -2345,6 +2438,10 @@ func (dvs *dataViewService) generateMultiFieldStatsDSL(ctx context.Context, para
 		s = append(s, paras.timeFilterStr)
 	}
 
+	if paras.rowFilterStr != "" {
+		s = append(s, paras.rowFilterStr)
+	}
+
 	var dslStr string
 	// todo: 适配1.x版本的opensearch, 不支持multi_terms
 	if len(fields) == 1 {
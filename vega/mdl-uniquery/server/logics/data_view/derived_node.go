@@ -0,0 +1,411 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package data_view
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mitchellh/mapstructure"
+
+	cond "uniquery/common/condition"
+	"uniquery/interfaces"
+	dtype "uniquery/interfaces/data_type"
+)
+
+// aggregate、window、pivot、unpivot 节点只有一个输入节点，输出字段由节点配置和输入节点的字段推导得到。
+// 派生节点的 SQL 以字段 name 作为列名，所以输出字段的 original_name 与 name 相同。
+
+// deriveNodeFields 推导派生节点的输出字段，inputFields 为输入节点的输出字段
+func deriveNodeFields(node *interfaces.DataScopeNode, inputFields []*cond.ViewField) ([]*cond.ViewField, error) {
+	inputFieldsMap := make(map[string]*cond.ViewField, len(inputFields))
+	for _, field := range inputFields {
+		inputFieldsMap[field.Name] = field
+	}
+
+	switch node.Type {
+	case interfaces.DataScopeNodeType_Aggregate:
+		var cfg interfaces.AggregateNodeCfg
+		if err := mapstructure.Decode(node.Config, &cfg); err != nil {
+			return nil, fmt.Errorf("decode aggregate config failed, %v", err)
+		}
+		return deriveAggregateFields(&cfg, inputFieldsMap)
+	case interfaces.DataScopeNodeType_Window:
+		var cfg interfaces.WindowNodeCfg
+		if err := mapstructure.Decode(node.Config, &cfg); err != nil {
+			return nil, fmt.Errorf("decode window config failed, %v", err)
+		}
+		return deriveWindowFields(&cfg, inputFields, inputFieldsMap)
+	case interfaces.DataScopeNodeType_Pivot:
+		var cfg interfaces.PivotNodeCfg
+		if err := mapstructure.Decode(node.Config, &cfg); err != nil {
+			return nil, fmt.Errorf("decode pivot config failed, %v", err)
+		}
+		return derivePivotFields(&cfg, inputFieldsMap)
+	case interfaces.DataScopeNodeType_Unpivot:
+		var cfg interfaces.UnpivotNodeCfg
+		if err := mapstructure.Decode(node.Config, &cfg); err != nil {
+			return nil, fmt.Errorf("decode unpivot config failed, %v", err)
+		}
+		return deriveUnpivotFields(&cfg, inputFieldsMap)
+	default:
+		return nil, fmt.Errorf("node type %s is not a derived node type", node.Type)
+	}
+}
+
+// typedViewNodeFields 视图节点的输出字段，未传字段类型时取来源视图中的字段类型
+func typedViewNodeFields(node *interfaces.DataScopeNode) []*cond.ViewField {
+	var cfg interfaces.ViewNodeCfg
+	if err := mapstructure.Decode(node.Config, &cfg); err != nil || cfg.View == nil {
+		return node.OutputFields
+	}
+
+	viewFieldsMap := make(map[string]*cond.ViewField, len(cfg.View.Fields))
+	for _, field := range cfg.View.Fields {
+		viewFieldsMap[field.Name] = field
+	}
+
+	fields := make([]*cond.ViewField, 0, len(node.OutputFields))
+	for _, of := range node.OutputFields {
+		if vf, ok := viewFieldsMap[of.Name]; ok && of.Type == "" {
+			field := *of
+			field.Type = vf.Type
+			of = &field
+		}
+		fields = append(fields, of)
+	}
+	return fields
+}
+
+// outputFieldSet 校验派生节点的输出字段 name 不重复
+type outputFieldSet struct {
+	fields []*cond.ViewField
+	names  map[string]struct{}
+}
+
+func newOutputFieldSet() *outputFieldSet {
+	return &outputFieldSet{names: make(map[string]struct{})}
+}
+
+func (s *outputFieldSet) add(field *cond.ViewField) error {
+	if field.Name == "" {
+		return fmt.Errorf("output field name must be set")
+	}
+	if _, ok := s.names[field.Name]; ok {
+		return fmt.Errorf("output field name '%s' is repeated", field.Name)
+	}
+	s.names[field.Name] = struct{}{}
+	s.fields = append(s.fields, field)
+	return nil
+}
+
+// passThroughField 原样输出的输入字段
+func passThroughField(src *cond.ViewField) *cond.ViewField {
+	return &cond.ViewField{
+		Name:              src.Name,
+		Type:              src.Type,
+		Comment:           src.Comment,
+		DisplayName:       src.DisplayName,
+		OriginalName:      src.Name,
+		DataLength:        src.DataLength,
+		DataAccuracy:      src.DataAccuracy,
+		BusinessTimestamp: src.BusinessTimestamp,
+	}
+}
+
+// computedField 计算得到的字段
+func computedField(name, fieldType string) *cond.ViewField {
+	return &cond.ViewField{
+		Name:         name,
+		Type:         fieldType,
+		DisplayName:  name,
+		OriginalName: name,
+	}
+}
+
+// lookupField 在输入字段中查找字段，what 用于错误信息
+func lookupField(inputFieldsMap map[string]*cond.ViewField, name, what string) (*cond.ViewField, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%s must be set", what)
+	}
+	field, ok := inputFieldsMap[name]
+	if !ok {
+		return nil, fmt.Errorf("%s '%s' is not in the input node fields", what, name)
+	}
+	return field, nil
+}
+
+// aggregateFieldType 聚合结果的字段类型，字段类型未知时只推导与字段类型无关的结果类型
+func aggregateFieldType(function string, src *cond.ViewField) (string, error) {
+	switch function {
+	case interfaces.AggregateFunc_Count, interfaces.AggregateFunc_CountDistinct,
+		interfaces.WindowFunc_RowNumber, interfaces.WindowFunc_Rank, interfaces.WindowFunc_DenseRank:
+		return dtype.DataType_Integer, nil
+	case interfaces.AggregateFunc_Sum, interfaces.AggregateFunc_Avg:
+		if src.Type != "" && !dtype.DataType_IsNumber(src.Type) {
+			return "", fmt.Errorf("function %s does not support field '%s' of type %s", function, src.Name, src.Type)
+		}
+		if function == interfaces.AggregateFunc_Avg {
+			return dtype.DataType_Float, nil
+		}
+		return src.Type, nil
+	default:
+		// max、min 以及取值的窗口函数，结果类型与字段一致
+		return src.Type, nil
+	}
+}
+
+func deriveAggregateFields(cfg *interfaces.AggregateNodeCfg, inputFieldsMap map[string]*cond.ViewField) ([]*cond.ViewField, error) {
+	if len(cfg.GroupBy) == 0 && len(cfg.Aggregations) == 0 {
+		return nil, fmt.Errorf("group_by or aggregations must be set")
+	}
+
+	outputs := newOutputFieldSet()
+	for _, name := range cfg.GroupBy {
+		field, err := lookupField(inputFieldsMap, name, "group_by field")
+		if err != nil {
+			return nil, err
+		}
+		if err := outputs.add(passThroughField(field)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, agg := range cfg.Aggregations {
+		if _, ok := interfaces.AggregateFuncMap[agg.Function]; !ok {
+			return nil, fmt.Errorf("aggregation function '%s' is invalid", agg.Function)
+		}
+
+		fieldType := dtype.DataType_Integer
+		// count 不传字段或字段为 * 时统计行数
+		if !(agg.Function == interfaces.AggregateFunc_Count && (agg.Field == "" || agg.Field == "*")) {
+			field, err := lookupField(inputFieldsMap, agg.Field, "aggregation field")
+			if err != nil {
+				return nil, err
+			}
+			fieldType, err = aggregateFieldType(agg.Function, field)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err := outputs.add(computedField(agg.Alias, fieldType)); err != nil {
+			return nil, err
+		}
+	}
+	return outputs.fields, nil
+}
+
+func deriveWindowFields(cfg *interfaces.WindowNodeCfg, inputFields []*cond.ViewField,
+	inputFieldsMap map[string]*cond.ViewField) ([]*cond.ViewField, error) {
+
+	if len(cfg.Functions) == 0 {
+		return nil, fmt.Errorf("window functions must be set")
+	}
+	for _, name := range cfg.PartitionBy {
+		if _, err := lookupField(inputFieldsMap, name, "partition_by field"); err != nil {
+			return nil, err
+		}
+	}
+	for _, order := range cfg.OrderBy {
+		if _, err := lookupField(inputFieldsMap, order.Field, "order_by field"); err != nil {
+			return nil, err
+		}
+		if order.Direction != "" && order.Direction != interfaces.SortDirection_Asc && order.Direction != interfaces.SortDirection_Desc {
+			return nil, fmt.Errorf("order_by direction '%s' is invalid, must be asc or desc", order.Direction)
+		}
+	}
+	if err := validateWindowFrame(cfg.Frame, len(cfg.OrderBy)); err != nil {
+		return nil, err
+	}
+
+	// 窗口节点输出输入节点的全部字段
+	outputs := newOutputFieldSet()
+	for _, field := range inputFields {
+		if err := outputs.add(passThroughField(field)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, fn := range cfg.Functions {
+		if _, ok := interfaces.WindowFuncMap[fn.Function]; !ok {
+			return nil, fmt.Errorf("window function '%s' is invalid", fn.Function)
+		}
+
+		fieldType := dtype.DataType_Integer
+		switch fn.Function {
+		case interfaces.WindowFunc_RowNumber, interfaces.WindowFunc_Rank, interfaces.WindowFunc_DenseRank:
+			if len(cfg.OrderBy) == 0 {
+				return nil, fmt.Errorf("window function %s requires order_by", fn.Function)
+			}
+		case interfaces.AggregateFunc_Count:
+			if fn.Field != "" && fn.Field != "*" {
+				if _, err := lookupField(inputFieldsMap, fn.Field, "window function field"); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			if (fn.Function == interfaces.WindowFunc_Lag || fn.Function == interfaces.WindowFunc_Lead) && len(cfg.OrderBy) == 0 {
+				return nil, fmt.Errorf("window function %s requires order_by", fn.Function)
+			}
+			if fn.Offset < 0 {
+				return nil, fmt.Errorf("window function %s offset must not be negative", fn.Function)
+			}
+			field, err := lookupField(inputFieldsMap, fn.Field, "window function field")
+			if err != nil {
+				return nil, err
+			}
+			fieldType, err = aggregateFieldType(fn.Function, field)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err := outputs.add(computedField(fn.Alias, fieldType)); err != nil {
+			return nil, err
+		}
+	}
+	return outputs.fields, nil
+}
+
+// validateWindowFrame 校验窗口帧，起始边界不能晚于结束边界
+func validateWindowFrame(frame *interfaces.WindowFrame, orderByCount int) error {
+	if frame == nil {
+		return nil
+	}
+	if frame.Unit != interfaces.WindowFrameUnit_Rows && frame.Unit != interfaces.WindowFrameUnit_Range {
+		return fmt.Errorf("window frame unit '%s' is invalid, must be rows or range", frame.Unit)
+	}
+	if frame.Start == nil || frame.End == nil {
+		return fmt.Errorf("window frame start and end must be set")
+	}
+
+	for _, bound := range []*interfaces.WindowFrameBound{frame.Start, frame.End} {
+		if _, ok := interfaces.WindowFrameBoundOrder[bound.Type]; !ok {
+			return fmt.Errorf("window frame bound type '%s' is invalid", bound.Type)
+		}
+		if bound.Offset < 0 {
+			return fmt.Errorf("window frame bound offset must not be negative")
+		}
+		// range 按排序字段的值计算偏移，只能有一个排序字段
+		if frame.Unit == interfaces.WindowFrameUnit_Range && orderByCount != 1 &&
+			(bound.Type == interfaces.WindowFrameBound_Preceding || bound.Type == interfaces.WindowFrameBound_Following) {
+			return fmt.Errorf("window frame of range with offset requires exactly one order_by field")
+		}
+	}
+
+	if frame.Start.Type == interfaces.WindowFrameBound_UnboundedFollowing {
+		return fmt.Errorf("window frame start can not be unbounded_following")
+	}
+	if frame.End.Type == interfaces.WindowFrameBound_UnboundedPreceding {
+		return fmt.Errorf("window frame end can not be unbounded_preceding")
+	}
+
+	startOrder := interfaces.WindowFrameBoundOrder[frame.Start.Type]
+	endOrder := interfaces.WindowFrameBoundOrder[frame.End.Type]
+	if startOrder > endOrder ||
+		(frame.Start.Type == interfaces.WindowFrameBound_Preceding && frame.End.Type == interfaces.WindowFrameBound_Preceding &&
+			frame.Start.Offset < frame.End.Offset) ||
+		(frame.Start.Type == interfaces.WindowFrameBound_Following && frame.End.Type == interfaces.WindowFrameBound_Following &&
+			frame.Start.Offset > frame.End.Offset) {
+		return fmt.Errorf("window frame start must not be after end")
+	}
+	return nil
+}
+
+func derivePivotFields(cfg *interfaces.PivotNodeCfg, inputFieldsMap map[string]*cond.ViewField) ([]*cond.ViewField, error) {
+	pivotField, err := lookupField(inputFieldsMap, cfg.PivotField, "pivot_field")
+	if err != nil {
+		return nil, err
+	}
+	valueField, err := lookupField(inputFieldsMap, cfg.ValueField, "value_field")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := interfaces.AggregateFuncMap[cfg.Function]; !ok {
+		return nil, fmt.Errorf("pivot function '%s' is invalid", cfg.Function)
+	}
+	valueType, err := aggregateFieldType(cfg.Function, valueField)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Values) == 0 {
+		return nil, fmt.Errorf("pivot values must be set")
+	}
+
+	outputs := newOutputFieldSet()
+	for _, name := range cfg.GroupBy {
+		field, err := lookupField(inputFieldsMap, name, "group_by field")
+		if err != nil {
+			return nil, err
+		}
+		if err := outputs.add(passThroughField(field)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pv := range cfg.Values {
+		// 数值类型的字段按数值比较，取值必须为数值
+		if dtype.DataType_IsNumber(pivotField.Type) {
+			if _, err := strconv.ParseFloat(pv.Value, 64); err != nil {
+				return nil, fmt.Errorf("pivot value '%s' is not a number", pv.Value)
+			}
+		}
+		if err := outputs.add(computedField(pv.Alias, valueType)); err != nil {
+			return nil, err
+		}
+	}
+	return outputs.fields, nil
+}
+
+func deriveUnpivotFields(cfg *interfaces.UnpivotNodeCfg, inputFieldsMap map[string]*cond.ViewField) ([]*cond.ViewField, error) {
+	if len(cfg.Columns) == 0 {
+		return nil, fmt.Errorf("unpivot columns must be set")
+	}
+
+	valueType := ""
+	columns := make(map[string]struct{}, len(cfg.Columns))
+	for _, name := range cfg.Columns {
+		field, err := lookupField(inputFieldsMap, name, "unpivot column")
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("unpivot column '%s' is repeated", name)
+		}
+		columns[name] = struct{}{}
+
+		// 转为行的列合并为一个值字段，类型必须一致
+		if field.Type != "" {
+			if valueType != "" && valueType != field.Type {
+				return nil, fmt.Errorf("unpivot columns must have the same type, got %s and %s", valueType, field.Type)
+			}
+			valueType = field.Type
+		}
+	}
+
+	outputs := newOutputFieldSet()
+	for _, name := range cfg.KeepFields {
+		field, err := lookupField(inputFieldsMap, name, "keep field")
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("keep field '%s' can not be an unpivot column", name)
+		}
+		if err := outputs.add(passThroughField(field)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := outputs.add(computedField(cfg.NameField, dtype.DataType_String)); err != nil {
+		return nil, err
+	}
+	if err := outputs.add(computedField(cfg.ValueField, valueType)); err != nil {
+		return nil, err
+	}
+	return outputs.fields, nil
+}
@@ -0,0 +1,360 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package data_view
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	cond "uniquery/common/condition"
+	vopt "uniquery/common/value_opt"
+	"uniquery/interfaces"
+	dtype "uniquery/interfaces/data_type"
+)
+
+// 来源视图 orders 的视图节点，输出字段未传类型
+func newOrdersViewNode() *interfaces.DataScopeNode {
+	return &interfaces.DataScopeNode{
+		ID:   "node_view",
+		Type: interfaces.DataScopeNodeType_View,
+		Config: map[string]any{
+			"view_id": "orders",
+			"view": &interfaces.DataView{
+				ViewID:        "orders",
+				MetaTableName: `c."s"."orders"`,
+				Fields: []*cond.ViewField{
+					{Name: "region", OriginalName: "region_code", Type: dtype.DataType_String},
+					{Name: "user", OriginalName: "user_id", Type: dtype.DataType_String},
+					{Name: "amount", OriginalName: "amount", Type: dtype.DataType_Decimal},
+					{Name: "q1", OriginalName: "q1", Type: dtype.DataType_Integer},
+					{Name: "q2", OriginalName: "q2", Type: dtype.DataType_Integer},
+					{Name: "ts", OriginalName: "ts", Type: dtype.DataType_Timestamp},
+				},
+			},
+		},
+		OutputFields: []*cond.ViewField{
+			{Name: "region", OriginalName: "region_code"},
+			{Name: "user", OriginalName: "user_id"},
+			{Name: "amount", OriginalName: "amount"},
+			{Name: "q1", OriginalName: "q1"},
+			{Name: "q2", OriginalName: "q2"},
+			{Name: "ts", OriginalName: "ts"},
+		},
+	}
+}
+
+func fieldTypes(fields []*cond.ViewField) map[string]string {
+	types := make(map[string]string, len(fields))
+	for _, field := range fields {
+		types[field.Name] = field.Type
+	}
+	return types
+}
+
+func TestDeriveNodeFields(t *testing.T) {
+	Convey("Test deriveNodeFields", t, func() {
+		inputFields := typedViewNodeFields(newOrdersViewNode())
+
+		Convey("view node fields are typed from the source view", func() {
+			So(inputFields[0].Type, ShouldEqual, dtype.DataType_String)
+			So(inputFields[2].Type, ShouldEqual, dtype.DataType_Decimal)
+		})
+
+		Convey("aggregate", func() {
+			node := &interfaces.DataScopeNode{Type: interfaces.DataScopeNodeType_Aggregate, Config: map[string]any{
+				"group_by": []string{"region"},
+				"aggregations": []map[string]any{
+					{"function": "count", "alias": "cnt"},
+					{"function": "count_distinct", "field": "user", "alias": "users"},
+					{"function": "sum", "field": "amount", "alias": "total"},
+					{"function": "avg", "field": "q1", "alias": "avg_q1"},
+					{"function": "max", "field": "ts", "alias": "last_ts"},
+				},
+			}}
+			fields, err := deriveNodeFields(node, inputFields)
+			So(err, ShouldBeNil)
+			So(fieldTypes(fields), ShouldResemble, map[string]string{
+				"region":  dtype.DataType_String,
+				"cnt":     dtype.DataType_Integer,
+				"users":   dtype.DataType_Integer,
+				"total":   dtype.DataType_Decimal,
+				"avg_q1":  dtype.DataType_Float,
+				"last_ts": dtype.DataType_Timestamp,
+			})
+			// 派生节点以 name 作为列名
+			So(fields[0].OriginalName, ShouldEqual, "region")
+		})
+
+		Convey("aggregate with invalid config", func() {
+			cases := []map[string]any{
+				{},
+				{"group_by": []string{"not_exist"}},
+				{"aggregations": []map[string]any{{"function": "median", "field": "amount", "alias": "m"}}},
+				{"aggregations": []map[string]any{{"function": "sum", "field": "region", "alias": "s"}}},
+				{"aggregations": []map[string]any{{"function": "sum", "field": "amount"}}},
+				{"group_by": []string{"region"}, "aggregations": []map[string]any{{"function": "count", "alias": "region"}}},
+			}
+			for _, c := range cases {
+				_, err := deriveNodeFields(&interfaces.DataScopeNode{Type: interfaces.DataScopeNodeType_Aggregate, Config: c}, inputFields)
+				So(err, ShouldNotBeNil)
+			}
+		})
+
+		Convey("window", func() {
+			node := &interfaces.DataScopeNode{Type: interfaces.DataScopeNodeType_Window, Config: map[string]any{
+				"partition_by": []string{"user"},
+				"order_by":     []map[string]any{{"field": "ts", "direction": "desc"}},
+				"functions": []map[string]any{
+					{"function": "row_number", "alias": "rn"},
+					{"function": "lag", "field": "amount", "alias": "prev_amount"},
+					{"function": "avg", "field": "amount", "alias": "avg_amount"},
+				},
+			}}
+			fields, err := deriveNodeFields(node, inputFields)
+			So(err, ShouldBeNil)
+			So(len(fields), ShouldEqual, len(inputFields)+3)
+			types := fieldTypes(fields)
+			So(types["ts"], ShouldEqual, dtype.DataType_Timestamp)
+			So(types["rn"], ShouldEqual, dtype.DataType_Integer)
+			So(types["prev_amount"], ShouldEqual, dtype.DataType_Decimal)
+			So(types["avg_amount"], ShouldEqual, dtype.DataType_Float)
+		})
+
+		Convey("window with invalid config", func() {
+			rowNumber := []map[string]any{{"function": "row_number", "alias": "rn"}}
+			sum := []map[string]any{{"function": "sum", "field": "amount", "alias": "s"}}
+			orderByTs := []map[string]any{{"field": "ts"}}
+			cases := []map[string]any{
+				{"functions": []map[string]any{}},
+				{"functions": rowNumber},
+				{"functions": rowNumber, "order_by": []map[string]any{{"field": "ts", "direction": "up"}}},
+				{"functions": rowNumber, "order_by": orderByTs, "partition_by": []string{"not_exist"}},
+				{"functions": []map[string]any{{"function": "rn", "alias": "rn"}}, "order_by": orderByTs},
+				{"functions": []map[string]any{{"function": "row_number", "alias": "ts"}}, "order_by": orderByTs},
+				{"functions": []map[string]any{{"function": "lead", "field": "amount", "offset": -1, "alias": "x"}}, "order_by": orderByTs},
+				{"functions": sum, "frame": map[string]any{"unit": "groups",
+					"start": map[string]any{"type": "unbounded_preceding"}, "end": map[string]any{"type": "current_row"}}},
+				{"functions": sum, "frame": map[string]any{"unit": "rows",
+					"start": map[string]any{"type": "current_row"}, "end": map[string]any{"type": "preceding", "offset": 1}}},
+				{"functions": sum, "frame": map[string]any{"unit": "rows",
+					"start": map[string]any{"type": "preceding", "offset": 1}, "end": map[string]any{"type": "preceding", "offset": 2}}},
+				{"functions": sum, "frame": map[string]any{"unit": "range",
+					"start": map[string]any{"type": "preceding", "offset": 1}, "end": map[string]any{"type": "current_row"}}},
+			}
+			for _, c := range cases {
+				_, err := deriveNodeFields(&interfaces.DataScopeNode{Type: interfaces.DataScopeNodeType_Window, Config: c}, inputFields)
+				So(err, ShouldNotBeNil)
+			}
+		})
+
+		Convey("pivot", func() {
+			node := &interfaces.DataScopeNode{Type: interfaces.DataScopeNodeType_Pivot, Config: map[string]any{
+				"group_by":    []string{"user"},
+				"pivot_field": "region",
+				"value_field": "amount",
+				"function":    "sum",
+				"values":      []map[string]any{{"value": "east", "alias": "east_amount"}, {"value": "west", "alias": "west_amount"}},
+			}}
+			fields, err := deriveNodeFields(node, inputFields)
+			So(err, ShouldBeNil)
+			So(fieldTypes(fields), ShouldResemble, map[string]string{
+				"user":        dtype.DataType_String,
+				"east_amount": dtype.DataType_Decimal,
+				"west_amount": dtype.DataType_Decimal,
+			})
+
+			// 数值类型的 pivot 字段取值必须为数值
+			node.Config["pivot_field"] = "q1"
+			_, err = deriveNodeFields(node, inputFields)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("unpivot", func() {
+			node := &interfaces.DataScopeNode{Type: interfaces.DataScopeNodeType_Unpivot, Config: map[string]any{
+				"keep_fields": []string{"user"},
+				"columns":     []string{"q1", "q2"},
+				"name_field":  "quarter",
+				"value_field": "qty",
+			}}
+			fields, err := deriveNodeFields(node, inputFields)
+			So(err, ShouldBeNil)
+			So(fieldTypes(fields), ShouldResemble, map[string]string{
+				"user":    dtype.DataType_String,
+				"quarter": dtype.DataType_String,
+				"qty":     dtype.DataType_Integer,
+			})
+
+			// 转为行的列类型必须一致
+			node.Config["columns"] = []string{"q1", "amount"}
+			_, err = deriveNodeFields(node, inputFields)
+			So(err, ShouldNotBeNil)
+
+			node.Config["columns"] = []string{"q1", "q2"}
+			node.Config["keep_fields"] = []string{"q1"}
+			_, err = deriveNodeFields(node, inputFields)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestResolveDerivedNodes(t *testing.T) {
+	Convey("Test resolveDerivedNodes", t, func() {
+		ctx := context.Background()
+		aggNode := &interfaces.DataScopeNode{
+			ID:         "node_agg",
+			Type:       interfaces.DataScopeNodeType_Aggregate,
+			InputNodes: []string{"node_view"},
+			Config: map[string]any{
+				"group_by":     []string{"region"},
+				"aggregations": []map[string]any{{"function": "sum", "field": "amount", "alias": "total"}},
+			},
+			// 用户传入的字段会被推导的字段覆盖
+			OutputFields: []*cond.ViewField{{Name: "whatever"}},
+		}
+		outputNode := &interfaces.DataScopeNode{
+			ID:           "node_output",
+			Type:         interfaces.DataScopeNodeType_Output,
+			InputNodes:   []string{"node_agg"},
+			OutputFields: []*cond.ViewField{{Name: "region", DisplayName: "区域"}, {Name: "total", DisplayName: "总额"}},
+		}
+		// 节点顺序不是依赖顺序
+		view := &interfaces.DataView{
+			QueryType: interfaces.QueryType_SQL,
+			DataScope: []*interfaces.DataScopeNode{outputNode, aggNode, newOrdersViewNode()},
+		}
+		view.Fields = outputNode.OutputFields
+
+		Convey("output fields are typed", func() {
+			err := resolveDerivedNodes(ctx, view, []*interfaces.DataScopeNode{aggNode})
+			So(err, ShouldBeNil)
+			So(fieldTypes(aggNode.OutputFields), ShouldResemble, map[string]string{
+				"region": dtype.DataType_String,
+				"total":  dtype.DataType_Decimal,
+			})
+			So(view.Fields[0].Type, ShouldEqual, dtype.DataType_String)
+			So(view.Fields[1].Type, ShouldEqual, dtype.DataType_Decimal)
+			So(view.Fields[1].OriginalName, ShouldEqual, "total")
+		})
+
+		Convey("having field must be an output field", func() {
+			aggNode.Config["having"] = map[string]any{"field": "amount", "operation": "gt", "value": 10, "value_from": "const"}
+			err := resolveDerivedNodes(ctx, view, []*interfaces.DataScopeNode{aggNode})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("output field must be a derived field", func() {
+			outputNode.OutputFields = append(outputNode.OutputFields, &cond.ViewField{Name: "amount"})
+			err := resolveDerivedNodes(ctx, view, []*interfaces.DataScopeNode{aggNode})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("cycle", func() {
+			aggNode.InputNodes = []string{"node_agg"}
+			err := resolveDerivedNodes(ctx, view, []*interfaces.DataScopeNode{aggNode})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestBuildDerivedNodeSQL(t *testing.T) {
+	Convey("Test build derived node sql", t, func() {
+		ctx := context.Background()
+		build := func(node *interfaces.DataScopeNode) string {
+			node.ID = "node_derived"
+			node.InputNodes = []string{"node_view"}
+			generator := NewSQLGenerator([]*interfaces.DataScopeNode{newOrdersViewNode(), node})
+			sql, err := generator.buildNodeSQL(ctx, node.ID)
+			So(err, ShouldBeNil)
+			return sql
+		}
+		viewSQL := `SELECT "region_code", "user_id", "amount", "q1", "q2", "ts" FROM c."s"."orders" `
+
+		Convey("aggregate", func() {
+			sql := build(&interfaces.DataScopeNode{Type: interfaces.DataScopeNodeType_Aggregate, Config: map[string]any{
+				"group_by": []string{"region"},
+				"aggregations": []map[string]any{
+					{"function": "count", "alias": "cnt"},
+					{"function": "count_distinct", "field": "user", "alias": "users"},
+				},
+			}})
+			So(sql, ShouldEqual, `SELECT "region_code" AS "region", COUNT(*) AS "cnt", COUNT(DISTINCT "user_id") AS "users" `+
+				`FROM (`+viewSQL+`) AS agg GROUP BY "region_code"`)
+		})
+
+		Convey("aggregate with having", func() {
+			sql := build(&interfaces.DataScopeNode{Type: interfaces.DataScopeNodeType_Aggregate, Config: map[string]any{
+				"group_by":     []string{"region"},
+				"aggregations": []map[string]any{{"function": "sum", "field": "amount", "alias": "total"}},
+				"having":       &cond.CondCfg{Name: "total", Operation: cond.OperationGt, ValueOptCfg: vopt.ValueOptCfg{ValueFrom: vopt.ValueFrom_Const, Value: 100}},
+			}})
+			So(sql, ShouldStartWith, `SELECT * FROM (SELECT "region_code" AS "region", SUM("amount") AS "total" FROM (`)
+			So(sql, ShouldContainSubstring, `) AS agg_result WHERE "total" > 100`)
+		})
+
+		Convey("window dedup", func() {
+			sql := build(&interfaces.DataScopeNode{Type: interfaces.DataScopeNodeType_Window, Config: map[string]any{
+				"partition_by": []string{"user"},
+				"order_by":     []map[string]any{{"field": "ts", "direction": "desc"}},
+				"frame": map[string]any{"unit": "rows",
+					"start": map[string]any{"type": "preceding", "offset": 2}, "end": map[string]any{"type": "current_row"}},
+				"functions": []map[string]any{
+					{"function": "row_number", "alias": "rn"},
+					{"function": "lag", "field": "amount", "alias": "prev"},
+					{"function": "sum", "field": "amount", "alias": "moving"},
+				},
+				"filters": &cond.CondCfg{Name: "rn", Operation: cond.OperationEq, ValueOptCfg: vopt.ValueOptCfg{ValueFrom: vopt.ValueFrom_Const, Value: 1}},
+			}})
+			over := `PARTITION BY "user_id" ORDER BY "ts" DESC`
+			So(sql, ShouldEqual, `SELECT * FROM (SELECT "region_code" AS "region", "user_id" AS "user", "amount" AS "amount", `+
+				`"q1" AS "q1", "q2" AS "q2", "ts" AS "ts", ROW_NUMBER() OVER (`+over+`) AS "rn", LAG("amount", 1) OVER (`+over+`) AS "prev", `+
+				`SUM("amount") OVER (`+over+` ROWS BETWEEN 2 PRECEDING AND CURRENT ROW) AS "moving" `+
+				`FROM (`+viewSQL+`) AS win) AS win_result WHERE "rn" = 1`)
+		})
+
+		Convey("pivot", func() {
+			sql := build(&interfaces.DataScopeNode{Type: interfaces.DataScopeNodeType_Pivot, Config: map[string]any{
+				"group_by":    []string{"user"},
+				"pivot_field": "region",
+				"value_field": "amount",
+				"function":    "sum",
+				"values":      []map[string]any{{"value": "east", "alias": "east"}, {"value": "o'west", "alias": "west"}},
+			}})
+			So(sql, ShouldEqual, `SELECT "user_id" AS "user", SUM(CASE WHEN "region_code" = 'east' THEN "amount" END) AS "east", `+
+				`SUM(CASE WHEN "region_code" = 'o''west' THEN "amount" END) AS "west" FROM (`+viewSQL+`) AS pvt GROUP BY "user_id"`)
+		})
+
+		Convey("unpivot", func() {
+			sql := build(&interfaces.DataScopeNode{Type: interfaces.DataScopeNodeType_Unpivot, Config: map[string]any{
+				"keep_fields": []string{"user"},
+				"columns":     []string{"q1", "q2"},
+				"name_field":  "quarter",
+				"value_field": "qty",
+			}})
+			So(sql, ShouldEqual, `SELECT "user_id" AS "user", 'q1' AS "quarter", "q1" AS "qty" FROM (`+viewSQL+`) AS t1 WHERE "q1" IS NOT NULL`+
+				` UNION ALL SELECT "user_id" AS "user", 'q2' AS "quarter", "q2" AS "qty" FROM (`+viewSQL+`) AS t2 WHERE "q2" IS NOT NULL`)
+		})
+
+		Convey("derived node as input of another derived node", func() {
+			agg := &interfaces.DataScopeNode{ID: "node_agg", Type: interfaces.DataScopeNodeType_Aggregate, InputNodes: []string{"node_view"},
+				Config: map[string]any{
+					"group_by":     []string{"region"},
+					"aggregations": []map[string]any{{"function": "sum", "field": "amount", "alias": "total"}},
+				}}
+			win := &interfaces.DataScopeNode{ID: "node_win", Type: interfaces.DataScopeNodeType_Window, InputNodes: []string{"node_agg"},
+				Config: map[string]any{
+					"order_by":  []map[string]any{{"field": "total", "direction": "desc"}},
+					"functions": []map[string]any{{"function": "rank", "alias": "rk"}},
+				}}
+			generator := NewSQLGenerator([]*interfaces.DataScopeNode{newOrdersViewNode(), agg, win})
+			sql, err := generator.buildNodeSQL(ctx, "node_win")
+			So(err, ShouldBeNil)
+			So(sql, ShouldStartWith, `SELECT "region" AS "region", "total" AS "total", RANK() OVER (ORDER BY "total" DESC) AS "rk" FROM (SELECT`)
+			So(fieldTypes(win.OutputFields)["total"], ShouldEqual, dtype.DataType_Decimal)
+		})
+	})
+}
@@ -0,0 +1,583 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package data_view
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+
+	cond "uniquery/common/condition"
+	vopt "uniquery/common/value_opt"
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+	dtype "uniquery/interfaces/data_type"
+)
+
+// 日期分桶支持的时间格式, 分桶后按原格式输出
+var bucketTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// 获取访问者查询视图数据时生效的行列规则, 视图数据的各个查询入口共用.
+// 1. 有 data_query 权限时返回 nil，查询视图的全量数据
+// 2. 否则返回访问者具有 rule_apply 权限且用户属性已解析的规则，没有可用的规则时返回 403
+func (dvs *dataViewService) getEffectiveRowColumnRules(ctx context.Context, viewID string) ([]*interfaces.DataViewRowColumnRule, error) {
+	hasPermission, rules, err := dvs.getApplicableRowColumnRules(ctx, viewID)
+	if err != nil {
+		return nil, err
+	}
+	if hasPermission {
+		return nil, nil
+	}
+
+	if len(rules) == 0 {
+		return nil, rest.NewHTTPError(ctx, http.StatusForbidden, rest.PublicError_Forbidden).
+			WithErrorDetails(fmt.Sprintf("Neither data query permission nor row column rules with rule_apply permission for view ID %s", viewID))
+	}
+
+	// 替换行过滤条件中引用的用户属性，属性无法解析的规则不生效
+	rules, err = dvs.resolveUserAttrRowFilters(ctx, rules)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, rest.NewHTTPError(ctx, http.StatusForbidden, rest.PublicError_Forbidden).
+			WithErrorDetails(fmt.Sprintf("The user attributes referenced by the row column rules of view ID %s are not resolved", viewID))
+	}
+
+	return rules, nil
+}
+
+// 决策访问者在视图上的 data_query 权限, 没有权限时返回访问者具有 rule_apply 权限的行列规则
+func (dvs *dataViewService) getApplicableRowColumnRules(ctx context.Context, viewID string) (bool, []*interfaces.DataViewRowColumnRule, error) {
+	hasPermission, err := dvs.ps.CheckPermissionWithResult(ctx, interfaces.Resource{
+		ID:   viewID,
+		Type: interfaces.RESOURCE_TYPE_DATA_VIEW,
+	}, []string{interfaces.OPERATION_TYPE_DATA_QUERY})
+	if err != nil {
+		return false, nil, err
+	}
+	if hasPermission {
+		return true, nil, nil
+	}
+
+	// 获取视图下的所有行列规则
+	rowColumnRules, err := dvs.dvrcrAccess.GetRulesByViewID(ctx, viewID)
+	if err != nil {
+		return false, nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, rest.PublicError_InternalServerError).
+			WithErrorDetails(err.Error())
+	}
+
+	// 过滤视图下的行列规则，返回当前用户具有rule_apply权限的规则
+	filteredRules, err := dvs.FilterRowColumnRules(ctx, rowColumnRules)
+	if err != nil {
+		return false, nil, err
+	}
+	return false, filteredRules, nil
+}
+
+// MaskViewData 按访问者在视图上生效的行列规则对平铺格式的数据脱敏, 供基于视图数据的其他模块(如指标导出)输出前调用.
+// 只处理脱敏，有 data_query 权限或没有可用的行列规则时不修改数据
+func (dvs *dataViewService) MaskViewData(ctx context.Context, viewID string, entries []map[string]any) error {
+	hasPermission, rules, err := dvs.getApplicableRowColumnRules(ctx, viewID)
+	if err != nil || hasPermission {
+		return err
+	}
+
+	return dvs.maskEntries(ctx, entries, rules, nil, interfaces.Format_Flat)
+}
+
+// 按行列规则对输出数据脱敏，哈希脱敏使用服务端配置的密钥
+func (dvs *dataViewService) maskEntries(ctx context.Context, entries []map[string]any,
+	rules []*interfaces.DataViewRowColumnRule, fieldsMap map[string]*cond.ViewField, format string) error {
+
+	masks := mergeFieldMasks(rules)
+	if len(masks) == 0 {
+		return nil
+	}
+
+	hashKey := dvs.appSetting.FieldMaskSetting.HashKey
+	for _, entry := range entries {
+		if err := maskData(entry, masks, fieldsMap, format, hashKey); err != nil {
+			return rest.NewHTTPError(ctx, http.StatusInternalServerError, rest.PublicError_InternalServerError).
+				WithErrorDetails(fmt.Sprintf("Mask view data failed, %s", err.Error()))
+		}
+	}
+	return nil
+}
+
+// 聚合统计类查询使用的行列规则.
+// 1. 分组字段和聚合中引用的字段必须在规则的列范围内, 统计结果不能绕过列权限
+// 2. 行过滤条件转成 dsl 的过滤子句，没有行过滤条件时返回空字符串
+// 3. 返回字段的脱敏配置, 由调用方对分组结果的 key 脱敏
+func buildRowColumnRulesAggFilter(ctx context.Context, rules []*interfaces.DataViewRowColumnRule,
+	view *interfaces.DataView, fields []string, aggs map[string]any) (string, map[string]*interfaces.FieldMask, error) {
+
+	if len(rules) == 0 {
+		return "", nil, nil
+	}
+
+	dsl, _, allowedFields, err := addRowColumnRulesToDSL(ctx, interfaces.DSLCfg{}, rules, view)
+	if err != nil {
+		return "", nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_DataView_InternalError_ConvertToDSLFailed).
+			WithErrorDetails(err.Error())
+	}
+
+	for _, field := range fields {
+		if _, ok := allowedFields[field]; !ok {
+			return "", nil, rest.NewHTTPError(ctx, http.StatusForbidden, rest.PublicError_Forbidden).
+				WithErrorDetails(fmt.Sprintf("The field '%s' is not allowed by the row column rules", field))
+		}
+	}
+
+	masks := mergeFieldMasks(rules)
+	if len(aggs) > 0 {
+		if err := checkAggsFieldsUsage(ctx, aggs, allowedFields, masks); err != nil {
+			return "", nil, err
+		}
+	}
+
+	filters := append(dsl.Query.Bool.Filter, dsl.Query.Bool.Must...)
+	if len(filters) == 0 {
+		return "", masks, nil
+	}
+	filterStr, err := sonic.MarshalString(filters[0])
+	if err != nil {
+		return "", nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_DataView_InternalError_MarshalFailed).
+			WithErrorDetails(err.Error())
+	}
+	return filterStr, masks, nil
+}
+
+// 检查聚合查询引用的字段: 只能引用规则列范围内且未脱敏的字段，聚合结果中的值无法逐条脱敏.
+// 脚本和返回原始文档的聚合可以读取任意字段，有行列规则时不允许使用
+func checkAggsFieldsUsage(ctx context.Context, aggs any, allowedFields map[string]*cond.ViewField,
+	masks map[string]*interfaces.FieldMask) error {
+
+	switch v := aggs.(type) {
+	case map[string]any:
+		for key, value := range v {
+			switch key {
+			case "script", "top_hits", "scripted_metric":
+				return rest.NewHTTPError(ctx, http.StatusForbidden, rest.PublicError_Forbidden).
+					WithErrorDetails(fmt.Sprintf("The aggregation '%s' is not allowed by the row column rules", key))
+			case "field":
+				field, _ := value.(string)
+				field = strings.TrimSuffix(field, "."+dtype.KEYWORD_SUFFIX)
+				if _, ok := masks[field]; ok {
+					return rest.NewHTTPError(ctx, http.StatusForbidden, uerrors.Uniquery_DataView_InvalidFieldPermission_MaskedField).
+						WithErrorDetails(fmt.Sprintf("The masked field '%s' can not be used in aggregations", field))
+				}
+				if _, ok := allowedFields[field]; !ok {
+					return rest.NewHTTPError(ctx, http.StatusForbidden, rest.PublicError_Forbidden).
+						WithErrorDetails(fmt.Sprintf("The field '%s' is not allowed by the row column rules", field))
+				}
+			default:
+				if err := checkAggsFieldsUsage(ctx, value, allowedFields, masks); err != nil {
+					return err
+				}
+			}
+		}
+	case []any:
+		for _, value := range v {
+			if err := checkAggsFieldsUsage(ctx, value, allowedFields, masks); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 把行过滤条件中引用的用户属性替换为当前访问者的属性值.
+// 1. id 和 type 来自访问者账户信息，其他属性从用户管理服务获取，只获取一次
+// 2. 引用的属性不存在或为空时，该规则不生效，避免属性缺失时放开数据
+// 3. 返回新的规则列表，不修改原规则
+func (dvs *dataViewService) resolveUserAttrRowFilters(ctx context.Context,
+	rules []*interfaces.DataViewRowColumnRule) ([]*interfaces.DataViewRowColumnRule, error) {
+
+	accountInfo := interfaces.AccountInfo{}
+	if ctx.Value(interfaces.ACCOUNT_INFO_KEY) != nil {
+		accountInfo = ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo)
+	}
+
+	var userAttrs map[string]any
+	getAttr := func(name string) (any, error) {
+		switch name {
+		case interfaces.USER_ATTR_ID:
+			return accountInfo.ID, nil
+		case interfaces.USER_ATTR_TYPE:
+			return accountInfo.Type, nil
+		}
+
+		// 应用账户没有用户属性
+		if accountInfo.Type != interfaces.ACCESSOR_TYPE_USER || accountInfo.ID == "" {
+			return nil, nil
+		}
+		if userAttrs == nil {
+			attrs, err := dvs.umAccess.GetUserAttributes(ctx, accountInfo.ID)
+			if err != nil {
+				return nil, err
+			}
+			userAttrs = attrs
+		}
+		return userAttrs[name], nil
+	}
+
+	results := make([]*interfaces.DataViewRowColumnRule, 0, len(rules))
+	for _, rule := range rules {
+		rowFilters, ok, err := resolveUserAttrCond(rule.RowFilters, getAttr)
+		if err != nil {
+			return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError,
+				uerrors.Uniquery_DataView_InternalError_GetUserAttributesFailed).WithErrorDetails(err.Error())
+		}
+		if !ok {
+			logger.Infof("Row column rule %s is skipped, the user attributes it references are not resolved", rule.RuleID)
+			continue
+		}
+
+		newRule := *rule
+		newRule.RowFilters = rowFilters
+		results = append(results, &newRule)
+	}
+
+	return results, nil
+}
+
+// 复制过滤条件并替换其中的用户属性, 属性无法解析时返回 false
+func resolveUserAttrCond(cfg *cond.CondCfg, getAttr func(name string) (any, error)) (*cond.CondCfg, bool, error) {
+	if cfg == nil {
+		return nil, true, nil
+	}
+
+	newCfg := *cfg
+	if len(cfg.SubConds) > 0 {
+		newCfg.SubConds = make([]*cond.CondCfg, 0, len(cfg.SubConds))
+		for _, subCond := range cfg.SubConds {
+			newSubCond, ok, err := resolveUserAttrCond(subCond, getAttr)
+			if err != nil || !ok {
+				return nil, ok, err
+			}
+			newCfg.SubConds = append(newCfg.SubConds, newSubCond)
+		}
+	}
+
+	if cfg.ValueFrom != vopt.ValueFrom_User {
+		return &newCfg, true, nil
+	}
+
+	attrName, _ := cfg.Value.(string)
+	attrValue, err := getAttr(attrName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var values []any
+	switch v := attrValue.(type) {
+	case nil:
+	case string:
+		if v != "" {
+			values = []any{v}
+		}
+	case []any:
+		values = v
+	case []string:
+		for _, s := range v {
+			values = append(values, s)
+		}
+	default:
+		values = []any{v}
+	}
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+
+	newCfg.ValueFrom = vopt.ValueFrom_Const
+	switch cfg.Operation {
+	case cond.OperationEq, cond.OperationNotEq:
+		if len(values) == 1 {
+			newCfg.Value = values[0]
+			break
+		}
+		// 多值属性(如所属部门)等于任一值即可
+		if cfg.Operation == cond.OperationEq {
+			newCfg.Operation = cond.OperationIn
+		} else {
+			newCfg.Operation = cond.OperationNotIn
+		}
+		newCfg.Value = values
+	default:
+		newCfg.Value = values
+	}
+
+	return &newCfg, true, nil
+}
+
+// 合并行列规则的字段脱敏配置, field -> 脱敏配置.
+// 行列规则之间是 or 的关系，只要有一个规则不对字段脱敏，该字段就不脱敏; 多个规则都脱敏时使用第一个规则的配置
+func mergeFieldMasks(rules []*interfaces.DataViewRowColumnRule) map[string]*interfaces.FieldMask {
+	masks := map[string]*interfaces.FieldMask{}
+	unmasked := map[string]bool{}
+	for _, rule := range rules {
+		ruleMasks := map[string]*interfaces.FieldMask{}
+		for _, mask := range rule.FieldMasks {
+			if mask != nil {
+				ruleMasks[mask.Field] = mask
+			}
+		}
+
+		for _, field := range rule.Fields {
+			mask, ok := ruleMasks[field]
+			if !ok {
+				unmasked[field] = true
+				continue
+			}
+			if _, exist := masks[field]; !exist {
+				masks[field] = mask
+			}
+		}
+	}
+
+	for field := range unmasked {
+		delete(masks, field)
+	}
+	return masks
+}
+
+// 脱敏字段不能用于过滤和排序，避免通过过滤条件推断出原始值
+func checkMaskedFieldsUsage(ctx context.Context, query interfaces.ViewQueryInterface,
+	masks map[string]*interfaces.FieldMask) error {
+
+	if len(masks) == 0 {
+		return nil
+	}
+
+	if field, ok := findMaskedFieldInCond(query.GetGlobalFilters(), masks); ok {
+		return rest.NewHTTPError(ctx, http.StatusForbidden, uerrors.Uniquery_DataView_InvalidFieldPermission_MaskedField).
+			WithErrorDetails(fmt.Sprintf("The masked field '%s' can not be used in filters", field))
+	}
+
+	for _, sort := range query.GetSortParams() {
+		if _, ok := masks[sort.Field]; ok {
+			return rest.NewHTTPError(ctx, http.StatusForbidden, uerrors.Uniquery_DataView_InvalidFieldPermission_MaskedField).
+				WithErrorDetails(fmt.Sprintf("The masked field '%s' can not be used in sort", sort.Field))
+		}
+	}
+
+	return nil
+}
+
+func findMaskedFieldInCond(cfg *cond.CondCfg, masks map[string]*interfaces.FieldMask) (string, bool) {
+	if cfg == nil {
+		return "", false
+	}
+
+	for _, subCond := range cfg.SubConds {
+		if field, ok := findMaskedFieldInCond(subCond, masks); ok {
+			return field, true
+		}
+	}
+
+	if cfg.Operation == cond.OperationMultiMatch {
+		// 不指定字段或指定 * 时会匹配全部字段
+		fields, _ := cfg.RemainCfg["fields"].([]any)
+		if len(fields) == 0 {
+			return cond.AllField, true
+		}
+		for _, f := range fields {
+			name, _ := f.(string)
+			if _, ok := masks[name]; ok || name == cond.AllField {
+				return name, true
+			}
+		}
+		return "", false
+	}
+
+	if _, ok := masks[cfg.Name]; ok || cfg.Name == cond.AllField {
+		return cfg.Name, true
+	}
+	return "", false
+}
+
+// 对一条输出数据做字段脱敏. 平铺格式下字段名即为 key，原始格式下按字段路径取值
+func maskData(data map[string]any, masks map[string]*interfaces.FieldMask,
+	fieldsMap map[string]*cond.ViewField, format string, hashKey string) error {
+
+	for name, mask := range masks {
+		if format == interfaces.Format_Flat {
+			if value, ok := data[name]; ok {
+				data[name] = maskValue(value, mask, hashKey)
+			}
+			continue
+		}
+
+		field, ok := fieldsMap[name]
+		if !ok || len(field.Path) == 0 {
+			continue
+		}
+		values, isSliceValue, err := getData(data, field)
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		masked := make([]any, 0, len(values))
+		for _, value := range values {
+			masked = append(masked, maskValue(value, mask, hashKey))
+		}
+		err = setData(field, data, masked, isSliceValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 对单个值脱敏，数组逐个元素脱敏。无法按配置处理的值置为 null
+func maskValue(value any, mask *interfaces.FieldMask, hashKey string) any {
+	if value == nil {
+		return nil
+	}
+	if values, ok := value.([]any); ok {
+		masked := make([]any, 0, len(values))
+		for _, v := range values {
+			masked = append(masked, maskValue(v, mask, hashKey))
+		}
+		return masked
+	}
+
+	switch mask.Type {
+	case interfaces.FIELD_MASK_TYPE_PARTIAL:
+		return maskPartial(fmt.Sprint(value), mask)
+	case interfaces.FIELD_MASK_TYPE_HASH:
+		// 未配置密钥时无法安全地哈希, 按置空处理
+		if hashKey == "" {
+			return nil
+		}
+		h := hmac.New(sha256.New, []byte(hashKey))
+		h.Write([]byte(fmt.Sprint(value)))
+		return hex.EncodeToString(h.Sum(nil))
+	case interfaces.FIELD_MASK_TYPE_BUCKET:
+		if mask.BucketSize > 0 {
+			return bucketNumber(value, mask.BucketSize)
+		}
+		return bucketTime(value, mask.BucketUnit)
+	default:
+		return nil
+	}
+}
+
+// 保留前后若干字符，其余替换为脱敏字符; 字符串不长于保留的字符数时全部替换
+func maskPartial(s string, mask *interfaces.FieldMask) string {
+	maskChar := mask.MaskChar
+	if maskChar == "" {
+		maskChar = interfaces.DEFAULT_FIELD_MASK_CHAR
+	}
+
+	runes := []rune(s)
+	n := len(runes)
+	if n <= mask.KeepPrefix+mask.KeepSuffix {
+		return strings.Repeat(maskChar, n)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(string(runes[:mask.KeepPrefix]))
+	sb.WriteString(strings.Repeat(maskChar, n-mask.KeepPrefix-mask.KeepSuffix))
+	sb.WriteString(string(runes[n-mask.KeepSuffix:]))
+	return sb.String()
+}
+
+// 数值按桶大小向下取整，整数输入且结果为整数时输出整数
+func bucketNumber(value any, size float64) any {
+	var f float64
+	isInt := false
+	switch v := value.(type) {
+	case int64:
+		f, isInt = float64(v), true
+	case int:
+		f, isInt = float64(v), true
+	case float64:
+		f = v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			f, isInt = float64(i), true
+		} else if fv, err := v.Float64(); err == nil {
+			f = fv
+		} else {
+			return nil
+		}
+	case string:
+		fv, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil
+		}
+		f = fv
+	default:
+		return nil
+	}
+
+	bucket := math.Floor(f/size) * size
+	if isInt && bucket == math.Trunc(bucket) {
+		return int64(bucket)
+	}
+	return bucket
+}
+
+// 日期按时间单位截断. 字符串按原格式输出，毫秒时间戳输出毫秒时间戳
+func bucketTime(value any, unit string) any {
+	switch v := value.(type) {
+	case string:
+		for _, layout := range bucketTimeLayouts {
+			t, err := time.Parse(layout, v)
+			if err == nil {
+				return truncateTime(t, unit).Format(layout)
+			}
+		}
+		return nil
+	case int64:
+		return truncateTime(time.UnixMilli(v), unit).UnixMilli()
+	case json.Number:
+		ms, err := v.Int64()
+		if err != nil {
+			return nil
+		}
+		return truncateTime(time.UnixMilli(ms), unit).UnixMilli()
+	case float64:
+		return truncateTime(time.UnixMilli(int64(v)), unit).UnixMilli()
+	default:
+		return nil
+	}
+}
+
+func truncateTime(t time.Time, unit string) time.Time {
+	switch unit {
+	case interfaces.FIELD_MASK_BUCKET_UNIT_HOUR:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case interfaces.FIELD_MASK_BUCKET_UNIT_DAY:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	case interfaces.FIELD_MASK_BUCKET_UNIT_MONTH:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	}
+}
@@ -0,0 +1,445 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package data_view
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/common"
+	cond "uniquery/common/condition"
+	vopt "uniquery/common/value_opt"
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+	dtype "uniquery/interfaces/data_type"
+	mock "uniquery/interfaces/mock"
+)
+
+func TestResolveUserAttrRowFilters(t *testing.T) {
+	Convey("Test resolveUserAttrRowFilters", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		umaMock := mock.NewMockUserMgmtAccess(mockCtrl)
+		dvs := &dataViewService{umAccess: umaMock}
+
+		userCtx := context.WithValue(testCtx, interfaces.ACCOUNT_INFO_KEY, interfaces.AccountInfo{
+			ID:   "u1",
+			Type: interfaces.ACCESSOR_TYPE_USER,
+		})
+
+		deptRule := &interfaces.DataViewRowColumnRule{
+			RuleID: "r1",
+			Fields: []string{"department"},
+			RowFilters: &cond.CondCfg{
+				Name:      "department",
+				Operation: cond.OperationEq,
+				ValueOptCfg: vopt.ValueOptCfg{
+					ValueFrom: vopt.ValueFrom_User,
+					Value:     interfaces.USER_ATTR_DEPARTMENT_ID,
+				},
+			},
+		}
+
+		Convey("Resolve multi-valued attribute to in", func() {
+			umaMock.EXPECT().GetUserAttributes(gomock.Any(), "u1").Return(map[string]any{
+				interfaces.USER_ATTR_DEPARTMENT_ID: []any{"d1", "d2"},
+			}, nil)
+
+			rules, err := dvs.resolveUserAttrRowFilters(userCtx, []*interfaces.DataViewRowColumnRule{deptRule})
+			So(err, ShouldBeNil)
+			So(len(rules), ShouldEqual, 1)
+			So(rules[0].RowFilters.Operation, ShouldEqual, cond.OperationIn)
+			So(rules[0].RowFilters.ValueFrom, ShouldEqual, vopt.ValueFrom_Const)
+			So(rules[0].RowFilters.Value, ShouldResemble, []any{"d1", "d2"})
+			// 原规则不被修改
+			So(deptRule.RowFilters.ValueFrom, ShouldEqual, vopt.ValueFrom_User)
+		})
+
+		Convey("Resolve id from account info without user management", func() {
+			rule := &interfaces.DataViewRowColumnRule{
+				RuleID: "r2",
+				RowFilters: &cond.CondCfg{
+					Operation: cond.OperationAnd,
+					SubConds: []*cond.CondCfg{
+						{
+							Name:      "owner",
+							Operation: cond.OperationEq,
+							ValueOptCfg: vopt.ValueOptCfg{
+								ValueFrom: vopt.ValueFrom_User,
+								Value:     interfaces.USER_ATTR_ID,
+							},
+						},
+						{
+							Name:      "status",
+							Operation: cond.OperationEq,
+							ValueOptCfg: vopt.ValueOptCfg{
+								ValueFrom: vopt.ValueFrom_Const,
+								Value:     "active",
+							},
+						},
+					},
+				},
+			}
+
+			rules, err := dvs.resolveUserAttrRowFilters(userCtx, []*interfaces.DataViewRowColumnRule{rule})
+			So(err, ShouldBeNil)
+			So(len(rules), ShouldEqual, 1)
+			So(rules[0].RowFilters.SubConds[0].Value, ShouldEqual, "u1")
+			So(rules[0].RowFilters.SubConds[1].Value, ShouldEqual, "active")
+		})
+
+		Convey("Skip rule when attribute is missing", func() {
+			umaMock.EXPECT().GetUserAttributes(gomock.Any(), "u1").Return(map[string]any{}, nil)
+
+			rules, err := dvs.resolveUserAttrRowFilters(userCtx, []*interfaces.DataViewRowColumnRule{deptRule})
+			So(err, ShouldBeNil)
+			So(len(rules), ShouldEqual, 0)
+		})
+
+		Convey("Skip rule for app account", func() {
+			appCtx := context.WithValue(testCtx, interfaces.ACCOUNT_INFO_KEY, interfaces.AccountInfo{
+				ID:   "a1",
+				Type: "app",
+			})
+
+			rules, err := dvs.resolveUserAttrRowFilters(appCtx, []*interfaces.DataViewRowColumnRule{deptRule})
+			So(err, ShouldBeNil)
+			So(len(rules), ShouldEqual, 0)
+		})
+
+		Convey("Get user attributes failed", func() {
+			umaMock.EXPECT().GetUserAttributes(gomock.Any(), "u1").Return(nil, errors.New("error"))
+
+			_, err := dvs.resolveUserAttrRowFilters(userCtx, []*interfaces.DataViewRowColumnRule{deptRule})
+			So(err, ShouldNotBeNil)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
+
+func TestMergeFieldMasks(t *testing.T) {
+	Convey("Test mergeFieldMasks", t, func() {
+		phoneMask := &interfaces.FieldMask{Field: "phone", Type: interfaces.FIELD_MASK_TYPE_PARTIAL, KeepSuffix: 4}
+		emailMask := &interfaces.FieldMask{Field: "email", Type: interfaces.FIELD_MASK_TYPE_HASH}
+
+		rules := []*interfaces.DataViewRowColumnRule{
+			{
+				Fields:     []string{"name", "phone", "email"},
+				FieldMasks: []*interfaces.FieldMask{phoneMask, emailMask},
+			},
+			{
+				Fields:     []string{"email", "phone"},
+				FieldMasks: []*interfaces.FieldMask{{Field: "phone", Type: interfaces.FIELD_MASK_TYPE_NULLIFY}},
+			},
+		}
+
+		masks := mergeFieldMasks(rules)
+		So(len(masks), ShouldEqual, 1)
+		So(masks["phone"], ShouldEqual, phoneMask)
+	})
+}
+
+func TestCheckMaskedFieldsUsage(t *testing.T) {
+	Convey("Test checkMaskedFieldsUsage", t, func() {
+		masks := map[string]*interfaces.FieldMask{
+			"phone": {Field: "phone", Type: interfaces.FIELD_MASK_TYPE_HASH},
+		}
+
+		Convey("Masked field in filters", func() {
+			query := &interfaces.DataViewQueryV1{
+				GlobalFilters: &cond.CondCfg{
+					Operation: cond.OperationOr,
+					SubConds: []*cond.CondCfg{
+						{Name: "name", Operation: cond.OperationEq},
+						{Name: "phone", Operation: cond.OperationLike},
+					},
+				},
+			}
+
+			err := checkMaskedFieldsUsage(testCtx, query, masks)
+			So(err, ShouldNotBeNil)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_DataView_InvalidFieldPermission_MaskedField)
+		})
+
+		Convey("Multi match on all fields", func() {
+			query := &interfaces.DataViewQueryV1{
+				GlobalFilters: &cond.CondCfg{Operation: cond.OperationMultiMatch},
+			}
+
+			err := checkMaskedFieldsUsage(testCtx, query, masks)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Masked field in sort", func() {
+			query := &interfaces.DataViewQueryV1{SortParamsV1: interfaces.SortParamsV1{Sort: "phone"}}
+
+			err := checkMaskedFieldsUsage(testCtx, query, masks)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Unmasked fields", func() {
+			query := &interfaces.DataViewQueryV1{
+				GlobalFilters: &cond.CondCfg{Name: "name", Operation: cond.OperationEq},
+				SortParamsV1:  interfaces.SortParamsV1{Sort: "name"},
+			}
+
+			err := checkMaskedFieldsUsage(testCtx, query, masks)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestMaskValue(t *testing.T) {
+	Convey("Test maskValue", t, func() {
+		Convey("Partial", func() {
+			mask := &interfaces.FieldMask{Type: interfaces.FIELD_MASK_TYPE_PARTIAL, KeepPrefix: 3, KeepSuffix: 4}
+			So(maskValue("13812345678", mask, "v1"), ShouldEqual, "138****5678")
+			So(maskValue("1234", mask, "v1"), ShouldEqual, "****")
+
+			mask = &interfaces.FieldMask{Type: interfaces.FIELD_MASK_TYPE_PARTIAL, KeepPrefix: 1, MaskChar: "#"}
+			So(maskValue("张三丰", mask, "v1"), ShouldEqual, "张##")
+		})
+
+		Convey("Hash", func() {
+			mask := &interfaces.FieldMask{Type: interfaces.FIELD_MASK_TYPE_HASH}
+			h1 := maskValue("a@b.com", mask, "v1")
+			So(h1, ShouldEqual, maskValue("a@b.com", mask, "v1"))
+			So(h1, ShouldNotEqual, maskValue("a@b.com", mask, "v2"))
+			So(len(h1.(string)), ShouldEqual, 64)
+
+			// 未配置密钥时不输出可被枚举还原的哈希值
+			So(maskValue("a@b.com", mask, ""), ShouldBeNil)
+		})
+
+		Convey("Nullify", func() {
+			mask := &interfaces.FieldMask{Type: interfaces.FIELD_MASK_TYPE_NULLIFY}
+			So(maskValue("secret", mask, "v1"), ShouldBeNil)
+		})
+
+		Convey("Bucket number", func() {
+			mask := &interfaces.FieldMask{Type: interfaces.FIELD_MASK_TYPE_BUCKET, BucketSize: 10}
+			So(maskValue(int64(37), mask, "v1"), ShouldEqual, int64(30))
+			So(maskValue(json.Number("42"), mask, "v1"), ShouldEqual, int64(40))
+			So(maskValue(12.5, mask, "v1"), ShouldEqual, float64(10))
+			So(maskValue([]any{int64(5), int64(15)}, mask, "v1"), ShouldResemble, []any{int64(0), int64(10)})
+			So(maskValue("abc", mask, "v1"), ShouldBeNil)
+		})
+
+		Convey("Bucket date", func() {
+			mask := &interfaces.FieldMask{Type: interfaces.FIELD_MASK_TYPE_BUCKET, BucketUnit: interfaces.FIELD_MASK_BUCKET_UNIT_MONTH}
+			So(maskValue("2024-03-15T10:20:30+08:00", mask, "v1"), ShouldEqual, "2024-03-01T00:00:00+08:00")
+			So(maskValue("1990-07-21", mask, "v1"), ShouldEqual, "1990-07-01")
+
+			mask.BucketUnit = interfaces.FIELD_MASK_BUCKET_UNIT_YEAR
+			So(maskValue("1990-07-21 08:00:00", mask, "v1"), ShouldEqual, "1990-01-01 00:00:00")
+		})
+	})
+}
+
+func TestMaskData(t *testing.T) {
+	Convey("Test maskData", t, func() {
+		masks := map[string]*interfaces.FieldMask{
+			"user.phone": {Field: "user.phone", Type: interfaces.FIELD_MASK_TYPE_NULLIFY},
+		}
+		fieldsMap := map[string]*cond.ViewField{
+			"user.phone": {Name: "user.phone", Path: []string{"user", "phone"}},
+		}
+
+		Convey("Flat format", func() {
+			data := map[string]any{"user.phone": "13812345678", "name": "a"}
+			err := maskData(data, masks, fieldsMap, interfaces.Format_Flat, "v1")
+			So(err, ShouldBeNil)
+			So(data["user.phone"], ShouldBeNil)
+			So(data["name"], ShouldEqual, "a")
+		})
+
+		Convey("Original format", func() {
+			data := map[string]any{"user": map[string]any{"phone": "13812345678", "name": "a"}}
+			err := maskData(data, masks, fieldsMap, interfaces.Format_Original, "v1")
+			So(err, ShouldBeNil)
+			So(data["user"].(map[string]any)["phone"], ShouldBeNil)
+			So(data["user"].(map[string]any)["name"], ShouldEqual, "a")
+		})
+	})
+}
+
+func TestGetEffectiveRowColumnRules(t *testing.T) {
+	Convey("Test getEffectiveRowColumnRules", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		psMock := mock.NewMockPermissionService(mockCtrl)
+		dvrcraMock := mock.NewMockDataViewRowColumnRuleAccess(mockCtrl)
+		dvs := &dataViewService{
+			appSetting:  &common.AppSetting{},
+			dvrcrAccess: dvrcraMock,
+			ps:          psMock,
+		}
+
+		rule := &interfaces.DataViewRowColumnRule{RuleID: "r1", ViewID: "v1", Fields: []string{"host"}}
+
+		Convey("Data query permission queries all data", func() {
+			psMock.EXPECT().CheckPermissionWithResult(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+
+			rules, err := dvs.getEffectiveRowColumnRules(testCtx, "v1")
+			So(err, ShouldBeNil)
+			So(rules, ShouldBeNil)
+		})
+
+		Convey("Rules with rule_apply permission are applied", func() {
+			psMock.EXPECT().CheckPermissionWithResult(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+			dvrcraMock.EXPECT().GetRulesByViewID(gomock.Any(), "v1").Return([]*interfaces.DataViewRowColumnRule{rule}, nil)
+			psMock.EXPECT().FilterResources(gomock.Any(), gomock.Any(), []string{"r1"}, gomock.Any(), true).
+				Return(map[string]interfaces.ResourceOps{"r1": {ResourceID: "r1"}}, nil)
+
+			rules, err := dvs.getEffectiveRowColumnRules(testCtx, "v1")
+			So(err, ShouldBeNil)
+			So(len(rules), ShouldEqual, 1)
+			So(rules[0].RuleID, ShouldEqual, "r1")
+		})
+
+		Convey("Forbidden without permission and rules", func() {
+			psMock.EXPECT().CheckPermissionWithResult(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+			dvrcraMock.EXPECT().GetRulesByViewID(gomock.Any(), "v1").Return([]*interfaces.DataViewRowColumnRule{}, nil)
+
+			_, err := dvs.getEffectiveRowColumnRules(testCtx, "v1")
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusForbidden)
+		})
+	})
+}
+
+func TestMaskViewData(t *testing.T) {
+	Convey("Test MaskViewData", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		psMock := mock.NewMockPermissionService(mockCtrl)
+		dvrcraMock := mock.NewMockDataViewRowColumnRuleAccess(mockCtrl)
+		dvs := &dataViewService{
+			appSetting: &common.AppSetting{
+				FieldMaskSetting: common.FieldMaskSetting{HashKey: "secret"},
+			},
+			dvrcrAccess: dvrcraMock,
+			ps:          psMock,
+		}
+
+		rule := &interfaces.DataViewRowColumnRule{
+			RuleID:     "r1",
+			ViewID:     "v1",
+			Fields:     []string{"host", "zone"},
+			FieldMasks: []*interfaces.FieldMask{{Field: "host", Type: interfaces.FIELD_MASK_TYPE_HASH}},
+		}
+
+		Convey("Masked by rules with the configured key", func() {
+			psMock.EXPECT().CheckPermissionWithResult(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+			dvrcraMock.EXPECT().GetRulesByViewID(gomock.Any(), "v1").Return([]*interfaces.DataViewRowColumnRule{rule}, nil)
+			psMock.EXPECT().FilterResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), true).
+				Return(map[string]interfaces.ResourceOps{"r1": {ResourceID: "r1"}}, nil)
+
+			entries := []map[string]any{{"host": "a", "zone": "z1"}}
+			err := dvs.MaskViewData(testCtx, "v1", entries)
+			So(err, ShouldBeNil)
+			So(entries[0]["host"], ShouldEqual, maskValue("a", rule.FieldMasks[0], "secret"))
+			So(entries[0]["zone"], ShouldEqual, "z1")
+		})
+
+		Convey("Unchanged with data query permission", func() {
+			psMock.EXPECT().CheckPermissionWithResult(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+
+			entries := []map[string]any{{"host": "a"}}
+			err := dvs.MaskViewData(testCtx, "v1", entries)
+			So(err, ShouldBeNil)
+			So(entries[0]["host"], ShouldEqual, "a")
+		})
+	})
+}
+
+func TestBuildRowColumnRulesAggFilter(t *testing.T) {
+	Convey("Test buildRowColumnRulesAggFilter", t, func() {
+		view := &interfaces.DataView{
+			ViewID: "v1",
+			Type:   interfaces.ViewType_Atomic,
+			FieldsMap: map[string]*cond.ViewField{
+				"host":  {Name: "host", OriginalName: "host", Type: dtype.DataType_String},
+				"zone":  {Name: "zone", OriginalName: "zone", Type: dtype.DataType_String},
+				"owner": {Name: "owner", OriginalName: "owner", Type: dtype.DataType_String},
+			},
+		}
+		rules := []*interfaces.DataViewRowColumnRule{
+			{
+				RuleID:     "r1",
+				Fields:     []string{"host", "zone"},
+				FieldMasks: []*interfaces.FieldMask{{Field: "host", Type: interfaces.FIELD_MASK_TYPE_NULLIFY}},
+				RowFilters: &cond.CondCfg{
+					Name:      "zone",
+					Operation: cond.OperationEq,
+					ValueOptCfg: vopt.ValueOptCfg{
+						ValueFrom: vopt.ValueFrom_Const,
+						Value:     "z1",
+					},
+				},
+			},
+		}
+
+		Convey("No rules", func() {
+			filterStr, masks, err := buildRowColumnRulesAggFilter(testCtx, nil, view, []string{"owner"}, nil)
+			So(err, ShouldBeNil)
+			So(filterStr, ShouldEqual, "")
+			So(masks, ShouldBeNil)
+		})
+
+		Convey("Row filter and masks of group fields", func() {
+			filterStr, masks, err := buildRowColumnRulesAggFilter(testCtx, rules, view, []string{"host", "zone"}, nil)
+			So(err, ShouldBeNil)
+			So(filterStr, ShouldContainSubstring, `"zone"`)
+			So(filterStr, ShouldContainSubstring, `"z1"`)
+			So(masks, ShouldContainKey, "host")
+		})
+
+		Convey("Group field not allowed by rules", func() {
+			_, _, err := buildRowColumnRulesAggFilter(testCtx, rules, view, []string{"owner"}, nil)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Aggregation on masked field", func() {
+			aggs := map[string]any{
+				"by_zone": map[string]any{
+					"terms": map[string]any{"field": "zone"},
+					"aggs": map[string]any{
+						"hosts": map[string]any{"cardinality": map[string]any{"field": "host.keyword"}},
+					},
+				},
+			}
+			_, _, err := buildRowColumnRulesAggFilter(testCtx, rules, view, nil, aggs)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_DataView_InvalidFieldPermission_MaskedField)
+		})
+
+		Convey("Aggregation with script", func() {
+			aggs := map[string]any{
+				"total": map[string]any{"sum": map[string]any{"script": "doc['owner'].value.length()"}},
+			}
+			_, _, err := buildRowColumnRulesAggFilter(testCtx, rules, view, nil, aggs)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Aggregation on allowed field", func() {
+			aggs := map[string]any{
+				"by_zone": map[string]any{"terms": map[string]any{"field": "zone"}},
+			}
+			filterStr, _, err := buildRowColumnRulesAggFilter(testCtx, rules, view, nil, aggs)
+			So(err, ShouldBeNil)
+			So(filterStr, ShouldNotEqual, "")
+		})
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"uniquery/common"
 	cond "uniquery/common/condition"
 	"uniquery/interfaces"
+	dtype "uniquery/interfaces/data_type"
 )
 
 func buildCountSql(fromTableStr string) string {
@@ -107,6 +108,14 @@ func (g *SQLGenerator) buildNodeSQL(ctx context.Context, nodeID string) (string,
 		sql, err = g.buildSqlNodeSQL(ctx, node)
 	case interfaces.DataScopeNodeType_Output:
 		sql, err = g.buildOutputNodeSQL(ctx, node)
+	case interfaces.DataScopeNodeType_Aggregate:
+		sql, err = g.buildAggregateNodeSQL(ctx, node)
+	case interfaces.DataScopeNodeType_Window:
+		sql, err = g.buildWindowNodeSQL(ctx, node)
+	case interfaces.DataScopeNodeType_Pivot:
+		sql, err = g.buildPivotNodeSQL(ctx, node)
+	case interfaces.DataScopeNodeType_Unpivot:
+		sql, err = g.buildUnpivotNodeSQL(ctx, node)
 	default:
 		return "", fmt.Errorf("unknown node type: %s", node.Type)
 	}
@@ -510,6 +519,281 @@ func (g *SQLGenerator) buildSqlNodeSQL(ctx context.Context, node *interfaces.Dat
 	// return cfg.SQLExpression, nil
 }
 
+// derivedNodeInput 派生节点的输入节点的SQL和字段，columns 为字段 name 到输入SQL中列名的映射
+type derivedNodeInput struct {
+	sql     string
+	fields  []*cond.ViewField
+	columns map[string]string
+}
+
+// column 字段在输入SQL中的列名，field 为 * 时返回 *
+func (in *derivedNodeInput) column(field string) string {
+	if field == "" || field == "*" {
+		return "*"
+	}
+	return in.columns[field]
+}
+
+// buildDerivedNodeInput 生成派生节点的输入节点的SQL，并推导派生节点的输出字段
+func (g *SQLGenerator) buildDerivedNodeInput(ctx context.Context, node *interfaces.DataScopeNode) (*derivedNodeInput, error) {
+	if len(node.InputNodes) != 1 {
+		return nil, fmt.Errorf("%s node %s requires exactly one input node", node.Type, node.ID)
+	}
+
+	inputNodeID := node.InputNodes[0]
+	inputSQL, err := g.buildNodeSQL(ctx, inputNodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	inputNode := g.nodes[inputNodeID]
+	fields := inputNode.OutputFields
+	if inputNode.Type == interfaces.DataScopeNodeType_View {
+		fields = typedViewNodeFields(inputNode)
+	}
+
+	// 视图节点 select 的是原始字段名，其他节点的列名为 name
+	columns := make(map[string]string, len(fields))
+	for _, field := range fields {
+		if inputNode.Type == interfaces.DataScopeNodeType_View {
+			columns[field.Name] = common.QuotationMark(field.OriginalName)
+		} else {
+			columns[field.Name] = common.QuotationMark(field.Name)
+		}
+	}
+
+	outputFields, err := deriveNodeFields(node, fields)
+	if err != nil {
+		return nil, fmt.Errorf("%s node %s config is invalid: %v", node.Type, node.ID, err)
+	}
+	// 维护每个节点的output fields map
+	outputFieldsMap := make(map[string]*cond.ViewField, len(outputFields))
+	for _, field := range outputFields {
+		outputFieldsMap[field.Name] = field
+	}
+	node.OutputFields = outputFields
+	node.OutputFieldsMap = outputFieldsMap
+
+	return &derivedNodeInput{sql: inputSQL, fields: fields, columns: columns}, nil
+}
+
+// wrapFilterSQL 在派生节点的结果上过滤，过滤字段为节点的输出字段
+func wrapFilterSQL(ctx context.Context, sql string, alias string, filters *cond.CondCfg,
+	outputFieldsMap map[string]*cond.ViewField) (string, error) {
+
+	condition, err := buildSQLCondition(ctx, filters, interfaces.ViewType_Custom, outputFieldsMap)
+	if err != nil {
+		return "", err
+	}
+	if condition == "" {
+		return sql, nil
+	}
+	return fmt.Sprintf("SELECT * FROM (%s) AS %s WHERE %s", sql, alias, condition), nil
+}
+
+// aggregateExpr 聚合表达式，count_distinct 转为 COUNT(DISTINCT ...)
+func aggregateExpr(function, arg string) string {
+	if function == interfaces.AggregateFunc_CountDistinct {
+		return fmt.Sprintf("COUNT(DISTINCT %s)", arg)
+	}
+	return fmt.Sprintf("%s(%s)", strings.ToUpper(function), arg)
+}
+
+// buildAggregateNodeSQL 生成aggregate节点的SQL
+// SELECT group_fields, agg(field) AS alias FROM (input) AS agg GROUP BY group_fields
+func (g *SQLGenerator) buildAggregateNodeSQL(ctx context.Context, node *interfaces.DataScopeNode) (string, error) {
+	var cfg interfaces.AggregateNodeCfg
+	err := mapstructure.Decode(node.Config, &cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode aggregate config for node %s: %v", node.ID, err)
+	}
+
+	input, err := g.buildDerivedNodeInput(ctx, node)
+	if err != nil {
+		return "", err
+	}
+
+	selectFields := make([]string, 0, len(cfg.GroupBy)+len(cfg.Aggregations))
+	groupFields := make([]string, 0, len(cfg.GroupBy))
+	for _, field := range cfg.GroupBy {
+		selectFields = append(selectFields, fmt.Sprintf("%s AS %s", input.column(field), common.QuotationMark(field)))
+		groupFields = append(groupFields, input.column(field))
+	}
+	for _, agg := range cfg.Aggregations {
+		selectFields = append(selectFields, fmt.Sprintf("%s AS %s",
+			aggregateExpr(agg.Function, input.column(agg.Field)), common.QuotationMark(agg.Alias)))
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM (%s) AS agg", strings.Join(selectFields, ", "), input.sql)
+	if len(groupFields) > 0 {
+		sql = fmt.Sprintf("%s GROUP BY %s", sql, strings.Join(groupFields, ", "))
+	}
+
+	// having 的字段可以是聚合结果，在外层过滤，避免不同数据库对 having 中引用别名的支持差异
+	return wrapFilterSQL(ctx, sql, "agg_result", cfg.Having, node.OutputFieldsMap)
+}
+
+// buildWindowNodeSQL 生成window节点的SQL
+// SELECT input_fields, func(field) OVER (PARTITION BY ... ORDER BY ... frame) AS alias FROM (input) AS win
+func (g *SQLGenerator) buildWindowNodeSQL(ctx context.Context, node *interfaces.DataScopeNode) (string, error) {
+	var cfg interfaces.WindowNodeCfg
+	err := mapstructure.Decode(node.Config, &cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode window config for node %s: %v", node.ID, err)
+	}
+
+	input, err := g.buildDerivedNodeInput(ctx, node)
+	if err != nil {
+		return "", err
+	}
+
+	overClauses := make([]string, 0, 2)
+	if len(cfg.PartitionBy) > 0 {
+		partitionFields := make([]string, 0, len(cfg.PartitionBy))
+		for _, field := range cfg.PartitionBy {
+			partitionFields = append(partitionFields, input.column(field))
+		}
+		overClauses = append(overClauses, "PARTITION BY "+strings.Join(partitionFields, ", "))
+	}
+	if len(cfg.OrderBy) > 0 {
+		orderFields := make([]string, 0, len(cfg.OrderBy))
+		for _, order := range cfg.OrderBy {
+			direction := common.CE(order.Direction == "", interfaces.SortDirection_Asc, order.Direction)
+			orderFields = append(orderFields, fmt.Sprintf("%s %s", input.column(order.Field), strings.ToUpper(direction)))
+		}
+		overClauses = append(overClauses, "ORDER BY "+strings.Join(orderFields, ", "))
+	}
+	overStr := strings.Join(overClauses, " ")
+	frameStr := buildWindowFrameSQL(cfg.Frame)
+
+	selectFields := make([]string, 0, len(input.fields)+len(cfg.Functions))
+	for _, field := range input.fields {
+		selectFields = append(selectFields, fmt.Sprintf("%s AS %s", input.column(field.Name), common.QuotationMark(field.Name)))
+	}
+	for _, fn := range cfg.Functions {
+		var fnExpr string
+		over := overStr
+		switch fn.Function {
+		case interfaces.WindowFunc_RowNumber, interfaces.WindowFunc_Rank, interfaces.WindowFunc_DenseRank:
+			fnExpr = fmt.Sprintf("%s()", strings.ToUpper(fn.Function))
+		case interfaces.WindowFunc_Lag, interfaces.WindowFunc_Lead:
+			offset := fn.Offset
+			if offset == 0 {
+				offset = 1
+			}
+			fnExpr = fmt.Sprintf("%s(%s, %d)", strings.ToUpper(fn.Function), input.column(fn.Field), offset)
+		default:
+			// 窗口帧只作用于聚合和取值函数，排名和偏移函数忽略窗口帧
+			fnExpr = aggregateExpr(fn.Function, input.column(fn.Field))
+			if frameStr != "" {
+				over = strings.TrimSpace(over + " " + frameStr)
+			}
+		}
+		selectFields = append(selectFields, fmt.Sprintf("%s OVER (%s) AS %s", fnExpr, over, common.QuotationMark(fn.Alias)))
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM (%s) AS win", strings.Join(selectFields, ", "), input.sql)
+	return wrapFilterSQL(ctx, sql, "win_result", cfg.Filters, node.OutputFieldsMap)
+}
+
+// buildWindowFrameSQL 生成窗口帧，例如 ROWS BETWEEN 2 PRECEDING AND CURRENT ROW
+func buildWindowFrameSQL(frame *interfaces.WindowFrame) string {
+	if frame == nil {
+		return ""
+	}
+
+	bound := func(b *interfaces.WindowFrameBound) string {
+		switch b.Type {
+		case interfaces.WindowFrameBound_Preceding:
+			return fmt.Sprintf("%d PRECEDING", b.Offset)
+		case interfaces.WindowFrameBound_Following:
+			return fmt.Sprintf("%d FOLLOWING", b.Offset)
+		default:
+			return strings.ToUpper(strings.ReplaceAll(b.Type, "_", " "))
+		}
+	}
+	return fmt.Sprintf("%s BETWEEN %s AND %s", strings.ToUpper(frame.Unit), bound(frame.Start), bound(frame.End))
+}
+
+// buildPivotNodeSQL 生成pivot节点的SQL，用条件聚合实现行转列，兼容不支持 PIVOT 语法的数据库
+// SELECT group_fields, agg(CASE WHEN pivot_field = value THEN value_field END) AS alias FROM (input) AS pvt GROUP BY group_fields
+func (g *SQLGenerator) buildPivotNodeSQL(ctx context.Context, node *interfaces.DataScopeNode) (string, error) {
+	var cfg interfaces.PivotNodeCfg
+	err := mapstructure.Decode(node.Config, &cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode pivot config for node %s: %v", node.ID, err)
+	}
+
+	input, err := g.buildDerivedNodeInput(ctx, node)
+	if err != nil {
+		return "", err
+	}
+
+	pivotIsNumber := false
+	for _, field := range input.fields {
+		if field.Name == cfg.PivotField {
+			pivotIsNumber = dtype.DataType_IsNumber(field.Type)
+		}
+	}
+
+	selectFields := make([]string, 0, len(cfg.GroupBy)+len(cfg.Values))
+	groupFields := make([]string, 0, len(cfg.GroupBy))
+	for _, field := range cfg.GroupBy {
+		selectFields = append(selectFields, fmt.Sprintf("%s AS %s", input.column(field), common.QuotationMark(field)))
+		groupFields = append(groupFields, input.column(field))
+	}
+	for _, pv := range cfg.Values {
+		value := pv.Value
+		if !pivotIsNumber {
+			value = fmt.Sprintf("'%s'", strings.ReplaceAll(pv.Value, "'", "''"))
+		}
+		caseExpr := fmt.Sprintf("CASE WHEN %s = %s THEN %s END", input.column(cfg.PivotField), value, input.column(cfg.ValueField))
+		selectFields = append(selectFields, fmt.Sprintf("%s AS %s", aggregateExpr(cfg.Function, caseExpr), common.QuotationMark(pv.Alias)))
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM (%s) AS pvt", strings.Join(selectFields, ", "), input.sql)
+	if len(groupFields) > 0 {
+		sql = fmt.Sprintf("%s GROUP BY %s", sql, strings.Join(groupFields, ", "))
+	}
+	return sql, nil
+}
+
+// buildUnpivotNodeSQL 生成unpivot节点的SQL，每一列生成一个 select 后 UNION ALL，兼容不支持 UNPIVOT 语法的数据库
+// SELECT keep_fields, 'column' AS name_field, column AS value_field FROM (input) AS t1 UNION ALL ...
+func (g *SQLGenerator) buildUnpivotNodeSQL(ctx context.Context, node *interfaces.DataScopeNode) (string, error) {
+	var cfg interfaces.UnpivotNodeCfg
+	err := mapstructure.Decode(node.Config, &cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode unpivot config for node %s: %v", node.ID, err)
+	}
+
+	input, err := g.buildDerivedNodeInput(ctx, node)
+	if err != nil {
+		return "", err
+	}
+
+	keepFields := make([]string, 0, len(cfg.KeepFields))
+	for _, field := range cfg.KeepFields {
+		keepFields = append(keepFields, fmt.Sprintf("%s AS %s", input.column(field), common.QuotationMark(field)))
+	}
+
+	unionParts := make([]string, 0, len(cfg.Columns))
+	for i, column := range cfg.Columns {
+		selectFields := append(append([]string{}, keepFields...),
+			fmt.Sprintf("'%s' AS %s", strings.ReplaceAll(column, "'", "''"), common.QuotationMark(cfg.NameField)),
+			fmt.Sprintf("%s AS %s", input.column(column), common.QuotationMark(cfg.ValueField)))
+
+		part := fmt.Sprintf("SELECT %s FROM (%s) AS t%d", strings.Join(selectFields, ", "), input.sql, i+1)
+		// 与 UNPIVOT 语法一致，默认不输出空值
+		if !cfg.IncludeNulls {
+			part = fmt.Sprintf("%s WHERE %s IS NOT NULL", part, input.column(column))
+		}
+		unionParts = append(unionParts, part)
+	}
+
+	return strings.Join(unionParts, " UNION ALL "), nil
+}
+
 // buildOutputNodeSQL 生成output节点的SQL
 func (g *SQLGenerator) buildOutputNodeSQL(ctx context.Context, node *interfaces.DataScopeNode) (string, error) {
 	if len(node.InputNodes) != 1 {
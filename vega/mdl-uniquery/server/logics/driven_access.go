@@ -27,6 +27,7 @@ var (
 	SAccess     interfaces.SearchAccess
 	StAccess    interfaces.StaticAccess
 	TMAccess    interfaces.TraceModelAccess
+	UMAccess    interfaces.UserMgmtAccess
 	VDSAccess   interfaces.VegaDataSourceAccess
 	VGAccess    interfaces.VegaGatewayAccess
 	VVA         interfaces.VegaAccess
@@ -99,6 +100,10 @@ func SetTraceModelAccess(tma interfaces.TraceModelAccess) {
 	TMAccess = tma
 }
 
+func SetUserMgmtAccess(uma interfaces.UserMgmtAccess) {
+	UMAccess = uma
+}
+
 func SetVegaDataSourceAccess(vdsa interfaces.VegaDataSourceAccess) {
 	VDSAccess = vdsa
 }
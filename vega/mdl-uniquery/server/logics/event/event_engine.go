@@ -581,6 +581,11 @@ func (rd *EventEngine) Query(ctx context.Context, query interfaces.EventQuery, e
 		query.Start = query.End - Step
 	}
 
+	//NOTE: 统计检测需要补充基线的历史数据
+	if em.EventModelType == "atomic" && em.DetectRule.Type == interfaces.DETECT_RULE_TYPE_STATISTICAL {
+		return rd.queryStatistical(ctx, query, em)
+	}
+
 	//NOTE: 根据数据源类型产生对应的数据访问器
 
 	dmq := GenerateDataModelQuery(rd.appSetting, em, query)
@@ -606,8 +611,16 @@ func (rd *EventEngine) Judge(ctx context.Context, sr interfaces.SourceRecords, e
 	if em.EventModelType == "atomic" {
 
 		formula := em.DetectRule.Formula
+		records := sr.Records
+		//NOTE: 统计检测先计算正常范围，检测公式基于标注后的记录判定
+		if em.DetectRule.Type == interfaces.DETECT_RULE_TYPE_STATISTICAL {
+			records = statisticalDetect(sr, em.DetectRule)
+			if len(formula) == 0 {
+				formula = defaultStatisticalFormula()
+			}
+		}
 		sort.Sort(interfaces.Formula(formula))
-		for _, r := range sr.Records {
+		for _, r := range records {
 			lastEventLevel := interfaces.EVENT_MODEL_LEVEL_NORMAL
 			result, formulaItem, element := rd.Call(r, em, formula)
 			//NOTE: hit,
@@ -846,6 +859,9 @@ func (rd *EventEngine) GenerationAtomicEvent(ctx context.Context, sr interfaces.
 		TriggerData: interfaces.Records{record},
 		Message:     message,
 	}
+	if em.DetectRule.Type == interfaces.DETECT_RULE_TYPE_STATISTICAL {
+		event.Context.Anomaly = anomalyBandOf(em.DetectRule.DetectAlgo, record)
+	}
 	return event, nil
 }
 
@@ -18,6 +18,7 @@ import (
 	uerrors "uniquery/errors"
 	"uniquery/interfaces"
 	"uniquery/logics"
+	"uniquery/logics/event_lifecycle"
 	"uniquery/logics/permission"
 )
 
@@ -32,8 +33,9 @@ type eventService struct {
 	emAccess   interfaces.EventModelAccess
 	dvAccess   interfaces.DataViewAccess
 	// uAccess    interfaces.UniqueryAccess
-	ibAccess interfaces.IndexBaseAccess
-	ps       interfaces.PermissionService
+	ibAccess  interfaces.IndexBaseAccess
+	ps        interfaces.PermissionService
+	lcService interfaces.EventLifecycleService
 }
 
 func NewEventService(appSetting *common.AppSetting) interfaces.EventService {
@@ -44,8 +46,9 @@ func NewEventService(appSetting *common.AppSetting) interfaces.EventService {
 			emAccess:   logics.EMAccess,
 			dvAccess:   logics.DVAccess,
 			// uAccess:    logics.UAccess,
-			ibAccess: logics.IBAccess,
-			ps:       permission.NewPermissionService(appSetting),
+			ibAccess:  logics.IBAccess,
+			ps:        permission.NewPermissionService(appSetting),
+			lcService: event_lifecycle.NewEventLifecycleService(appSetting),
 		}
 	})
 	return es
@@ -148,6 +151,7 @@ func (ds *eventService) Query(ctx context.Context, queryReq interfaces.EventQuer
 				continue
 			}
 			events, entity, query_total := ds.engine.Apply(ctx, query, event_models[0])
+			events = ds.attachLifecycles(ctx, events)
 
 			if len(events) == 0 {
 				result = append(result, interfaces.IEvents{})
@@ -213,6 +217,65 @@ func (ds *eventService) QuerySingleEventByEventId(ctx context.Context, query int
 		logger.Errorf("QuerySingleEventByEventId failed,error info is: %v", err)
 		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventModel_InternalError)
 	}
+	if events != nil {
+		events = ds.attachLifecycles(ctx, interfaces.IEvents{events})[0]
+	}
 	return events, nil
 
 }
+
+// 确认或恢复事件，事件的查询权限在查询事件详情时校验
+func (ds *eventService) UpdateEventStatus(ctx context.Context, query interfaces.EventDetailsQueryReq,
+	req *interfaces.EventStatusUpdateRequest) (*interfaces.EventLifecycle, error) {
+
+	if ds.lcService == nil || !ds.lcService.Enabled() {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_NotEnabled)
+	}
+
+	event, err := ds.QuerySingleEventByEventId(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusNotFound, uerrors.Uniquery_EventModel_EventNotFound)
+	}
+	return ds.lcService.UpdateStatus(ctx, event, req)
+}
+
+// 为查询到的事件补充生命周期。
+// 生命周期按去重 key 共享，早于本次生命周期的事件属于已恢复的历史事件，不补充
+func (ds *eventService) attachLifecycles(ctx context.Context, events interfaces.IEvents) interfaces.IEvents {
+	if ds.lcService == nil || !ds.lcService.Enabled() {
+		return events
+	}
+
+	for i, event := range events {
+		base := event.GetBaseEvent()
+		lc, err := ds.lcService.GetLifecycle(ctx, base.EventModelId, base.Labels)
+		if err != nil {
+			logger.Errorf("Get lifecycle of event %s failed: %v", base.Id, err)
+			return events
+		}
+		if lc == nil || base.CreateTime < lc.FirstTime {
+			continue
+		}
+		events[i] = withLifecycle(event, lc)
+	}
+	return events
+}
+
+func withLifecycle(event interfaces.IEvent, lc *interfaces.EventLifecycle) interfaces.IEvent {
+	switch e := event.(type) {
+	case interfaces.AtomicEvent:
+		e.Lifecycle = lc
+		return e
+	case interfaces.AggregateEvent:
+		e.Lifecycle = lc
+		return e
+	case interfaces.EventRespData:
+		e.Lifecycle = lc
+		return e
+	default:
+		return event
+	}
+}
@@ -26,6 +26,7 @@ import (
 	uerrors "uniquery/errors"
 	"uniquery/interfaces"
 	"uniquery/logics"
+	"uniquery/logics/event_lifecycle"
 )
 
 var (
@@ -43,6 +44,7 @@ type eventSubService struct {
 	engine      interfaces.EventEngine
 	emAccess    interfaces.EventModelAccess
 	kafkaAccess interfaces.KafkaAccess
+	lcService   interfaces.EventLifecycleService
 	topics      []string
 }
 
@@ -61,6 +63,7 @@ func NewEventSubService(appSetting *common.AppSetting) interfaces.EventSubServic
 			engine:      engine,
 			emAccess:    logics.EMAccess,
 			kafkaAccess: logics.KAccess,
+			lcService:   event_lifecycle.NewEventLifecycleService(appSetting),
 			topics:      []string{},
 		}
 
@@ -365,6 +368,9 @@ func (ess *eventSubService) Invoke(engine interfaces.EventEngine, dataSource str
 		logger.Debugf(" dataSource %s, event_model %s :prepare to judge", dataSource, em.EventModelName)
 		if em.DetectRule.Type == "agi_detect" || em.AggregateRule.Type == "agi_aggregation" {
 			// do nothing
+		} else if em.DetectRule.Type == interfaces.DETECT_RULE_TYPE_STATISTICAL {
+			//NOTE: 统计检测依赖历史数据，只在定期执行时检测
+			continue
 		} else {
 			events, _, err = engine.Judge(ctx, sourceRecords, em)
 			if err != nil {
@@ -376,6 +382,14 @@ func (ess *eventSubService) Invoke(engine interfaces.EventEngine, dataSource str
 			}
 		}
 
+		//NOTE 按去重 key 更新事件状态，抑制窗口内重复的事件不再持久化
+		if ess.lcService != nil {
+			events = ess.lcService.Process(ctx, em, events)
+			if len(events) == 0 {
+				continue
+			}
+		}
+
 		messages := make([]*kafka.Message, 0)
 		kaEntities := make([]*kafka.Message, 0)
 		topic := fmt.Sprintf(interfaces.MODEL_PERSIST_INPUT, ess.appSetting.MQSetting.Tenant)
@@ -81,6 +81,16 @@ func RemoveDuplicates(elements []string) []string {
 	return result
 }
 
+// 统计检测的事件描述：观测值和正常范围
+func flatteAnomalyBand(language string, band *interfaces.AnomalyBand) string {
+	if language == "zh-CN" {
+		return fmt.Sprintf(`当前值为'%.2f',正常范围为['%.2f','%.2f'],基线值为'%.2f'`,
+			band.Observed, band.Lower, band.Upper, band.Expected)
+	}
+	return fmt.Sprintf(`current value is '%.2f', normal range is ['%.2f','%.2f'], baseline is '%.2f'`,
+		band.Observed, band.Lower, band.Upper, band.Expected)
+}
+
 func Compose(ctx context.Context, em interfaces.EventModel, f interfaces.FormulaItem, element map[string]any, record map[string]any) string {
 	//NOTE： 构造事件描述信息
 	language := rest.GetLanguageByCtx(ctx)
@@ -93,6 +103,9 @@ func Compose(ctx context.Context, em interfaces.EventModel, f interfaces.Formula
 		DataSourceName = em.DataSourceName
 	}
 	fieldMapStr := flatteFieldMap(em.DataSourceType, element)
+	if em.DetectRule.Type == interfaces.DETECT_RULE_TYPE_STATISTICAL {
+		fieldMapStr = flatteAnomalyBand(language, anomalyBandOf(em.DetectRule.DetectAlgo, record))
+	}
 	// filterStr := flatteFilters(f.Filter)
 	keys := GetKeysFromFilters(f.Filter)
 	uniKeys := RemoveDuplicates(keys)
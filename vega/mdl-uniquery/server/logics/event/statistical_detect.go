@@ -0,0 +1,406 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"uniquery/common/convert"
+	"uniquery/interfaces"
+)
+
+// 统计检测标注到记录上的字段，检测公式可以基于这些字段配置事件级别
+const (
+	STATISTICAL_FIELD_EXPECTED = "expected"
+	STATISTICAL_FIELD_LOWER    = "lower"
+	STATISTICAL_FIELD_UPPER    = "upper"
+	STATISTICAL_FIELD_SCORE    = "score"
+	STATISTICAL_FIELD_ANOMALY  = "anomaly"
+)
+
+const (
+	DEFAULT_STATISTICAL_STEP        = "1m"
+	DEFAULT_STATISTICAL_SENSITIVITY = 3.0
+	DEFAULT_STATISTICAL_WINDOW      = 60
+	DEFAULT_STATISTICAL_WARM_UP     = 10
+	DEFAULT_STATISTICAL_ALPHA       = 0.3
+	DEFAULT_STATISTICAL_WEEKS       = 4
+
+	// 正态分布下 MAD 到标准差的换算系数
+	madScale = 1.4826
+
+	hourMilliseconds = int64(time.Hour / time.Millisecond)
+	weekMilliseconds = 7 * 24 * hourMilliseconds
+)
+
+// 序列中的一个点
+type statisticalPoint struct {
+	ts     int64
+	value  float64
+	record interfaces.Record
+}
+
+// 某个点的基线值和离散程度
+type statisticalBaseline struct {
+	expected float64
+	spread   float64
+	ok       bool
+}
+
+// 补齐统计检测配置的默认值
+func statisticalConfigOf(dr interfaces.DetectRule) interfaces.StatisticalConfig {
+	cfg := interfaces.StatisticalConfig{}
+	if dr.StatisticalConfig != nil {
+		cfg = *dr.StatisticalConfig
+	}
+
+	if cfg.Step == "" {
+		cfg.Step = DEFAULT_STATISTICAL_STEP
+	}
+	if cfg.Sensitivity <= 0 {
+		cfg.Sensitivity = DEFAULT_STATISTICAL_SENSITIVITY
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DEFAULT_STATISTICAL_WINDOW
+	}
+	if cfg.WarmUp <= 0 {
+		cfg.WarmUp = DEFAULT_STATISTICAL_WARM_UP
+	}
+	if cfg.Alpha <= 0 || cfg.Alpha > 1 {
+		cfg.Alpha = DEFAULT_STATISTICAL_ALPHA
+	}
+	if cfg.Weeks <= 0 {
+		cfg.Weeks = DEFAULT_STATISTICAL_WEEKS
+	}
+	if cfg.Direction == "" {
+		cfg.Direction = interfaces.STATISTICAL_DIRECTION_BOTH
+	}
+	return cfg
+}
+
+// 未配置检测公式时，超出正常范围即产生提示级别的事件
+func defaultStatisticalFormula() interfaces.Formula {
+	return interfaces.Formula{
+		{
+			Level: interfaces.EVENT_MODEL_LEVEL_WARNING,
+			Filter: interfaces.LogicFilter{
+				FilterExpress: interfaces.FilterExpress{
+					Name:      STATISTICAL_FIELD_ANOMALY,
+					Operation: "==",
+					Value:     float64(1),
+				},
+			},
+		},
+	}
+}
+
+// 统计检测需要的查询区间。第一个区间是检测区间及滚动窗口的历史数据，
+// seasonal 额外返回前 N 周同一小时的区间。
+func statisticalQueryRanges(algo string, cfg interfaces.StatisticalConfig, start, end, step int64) [][2]int64 {
+	if algo != interfaces.STATISTICAL_ALGO_SEASONAL {
+		return [][2]int64{{start - int64(cfg.Window)*step, end}}
+	}
+
+	ranges := [][2]int64{{start, end}}
+	hourStart := start - start%hourMilliseconds
+	hourEnd := end - end%hourMilliseconds + hourMilliseconds - 1
+	for k := 1; k <= cfg.Weeks; k++ {
+		offset := int64(k) * weekMilliseconds
+		ranges = append(ranges, [2]int64{hourStart - offset, hourEnd - offset})
+	}
+	return ranges
+}
+
+// 统计检测的数据查询：检测区间之外补充基线所需的历史数据，序列按步长查询
+func (rd *EventEngine) queryStatistical(ctx context.Context, query interfaces.EventQuery,
+	em interfaces.EventModel) (interfaces.SourceRecords, interfaces.Record, error) {
+
+	cfg := statisticalConfigOf(em.DetectRule)
+	if query.Step == "" {
+		query.Step = cfg.Step
+	}
+	stepT, err := convert.ParseDuration(query.Step)
+	if err != nil {
+		return interfaces.SourceRecords{}, nil, err
+	}
+	step := convert.DurationMilliseconds(stepT)
+	if step <= 0 {
+		return interfaces.SourceRecords{}, nil, fmt.Errorf("invalid statistical detect step %s", query.Step)
+	}
+
+	sr := interfaces.SourceRecords{DetectStart: query.Start}
+	for _, r := range statisticalQueryRanges(em.DetectRule.DetectAlgo, cfg, query.Start, query.End, step) {
+		rangeQuery := query
+		rangeQuery.Start, rangeQuery.End = r[0], r[1]
+
+		dmq := GenerateDataModelQuery(rd.appSetting, em, rangeQuery)
+		part, _, err := dmq.FetchSourceRecordsFrom(ctx, "flat")
+		if err != nil {
+			logger.Errorf("statistical detect query [%d,%d] failed, error info is: %v", r[0], r[1], err)
+			return interfaces.SourceRecords{}, nil, err
+		}
+		sr.Records = append(sr.Records, part.Records...)
+	}
+	return sr, nil, nil
+}
+
+// 按序列计算每个点的正常范围，并将基线、上下界、偏离程度和是否异常标注到记录上。
+// 只返回检测区间内完成判定的记录，预热期内的点不参与判定。
+func statisticalDetect(sr interfaces.SourceRecords, dr interfaces.DetectRule) interfaces.Records {
+	cfg := statisticalConfigOf(dr)
+	keys, series := groupStatisticalSeries(sr.Records)
+
+	records := interfaces.Records{}
+	for _, key := range keys {
+		points := series[key]
+
+		var baselines []statisticalBaseline
+		switch dr.DetectAlgo {
+		case interfaces.STATISTICAL_ALGO_ZSCORE:
+			baselines = rollingBaselines(points, cfg, meanStdDev)
+		case interfaces.STATISTICAL_ALGO_MAD:
+			baselines = rollingBaselines(points, cfg, medianAbsDeviation)
+		case interfaces.STATISTICAL_ALGO_EWMA:
+			baselines = ewmaBaselines(points, cfg)
+		case interfaces.STATISTICAL_ALGO_SEASONAL:
+			baselines = seasonalBaselines(points, cfg)
+		default:
+			logger.Errorf("unsupported statistical detect algo %s", dr.DetectAlgo)
+			return records
+		}
+
+		for i, p := range points {
+			if p.ts < sr.DetectStart || !baselines[i].ok {
+				continue
+			}
+			records = append(records, annotateStatisticalRecord(p, baselines[i], cfg))
+		}
+	}
+	return records
+}
+
+// 按标签将记录分组为序列，序列内按时间升序并去掉重复的时间点
+func groupStatisticalSeries(records interfaces.Records) ([]string, map[string][]statisticalPoint) {
+	series := map[string][]statisticalPoint{}
+	for _, r := range records {
+		ts, ok := recordTimestamp(r["@timestamp"])
+		if !ok {
+			continue
+		}
+		value, ok := recordValue(r["value"])
+		if !ok {
+			continue
+		}
+
+		labels := make([]string, 0, len(r))
+		for k, v := range r {
+			if strings.HasPrefix(k, "labels.") {
+				labels = append(labels, fmt.Sprintf("%s=%v", k, v))
+			}
+		}
+		sort.Strings(labels)
+		key := strings.Join(labels, ",")
+		series[key] = append(series[key], statisticalPoint{ts: ts, value: value, record: r})
+	}
+
+	keys := make([]string, 0, len(series))
+	for key, points := range series {
+		sort.SliceStable(points, func(i, j int) bool { return points[i].ts < points[j].ts })
+		uniq := points[:0]
+		for _, p := range points {
+			if len(uniq) > 0 && uniq[len(uniq)-1].ts == p.ts {
+				continue
+			}
+			uniq = append(uniq, p)
+		}
+		series[key] = uniq
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, series
+}
+
+// zscore、mad：以前 window 个点为基线
+func rollingBaselines(points []statisticalPoint, cfg interfaces.StatisticalConfig,
+	estimate func([]float64) (float64, float64)) []statisticalBaseline {
+
+	baselines := make([]statisticalBaseline, len(points))
+	values := make([]float64, 0, cfg.Window)
+	for i := range points {
+		values = values[:0]
+		for j := max(0, i-cfg.Window); j < i; j++ {
+			values = append(values, points[j].value)
+		}
+		if len(values) < cfg.WarmUp {
+			continue
+		}
+		expected, spread := estimate(values)
+		baselines[i] = statisticalBaseline{expected: expected, spread: spread, ok: true}
+	}
+	return baselines
+}
+
+// ewma：以指数加权的均值和方差为基线，每个点判定后再更新
+func ewmaBaselines(points []statisticalPoint, cfg interfaces.StatisticalConfig) []statisticalBaseline {
+	baselines := make([]statisticalBaseline, len(points))
+	if len(points) == 0 {
+		return baselines
+	}
+
+	mean, variance := points[0].value, 0.0
+	for i := 1; i < len(points); i++ {
+		if i >= cfg.WarmUp {
+			baselines[i] = statisticalBaseline{expected: mean, spread: math.Sqrt(variance), ok: true}
+		}
+		diff := points[i].value - mean
+		incr := cfg.Alpha * diff
+		mean += incr
+		variance = (1 - cfg.Alpha) * (variance + diff*incr)
+	}
+	return baselines
+}
+
+// seasonal：以前 N 周同一小时内的点为基线
+func seasonalBaselines(points []statisticalPoint, cfg interfaces.StatisticalConfig) []statisticalBaseline {
+	baselines := make([]statisticalBaseline, len(points))
+	values := []float64{}
+	for i, p := range points {
+		values = values[:0]
+		hourStart := p.ts - p.ts%hourMilliseconds
+		for k := 1; k <= cfg.Weeks; k++ {
+			from := hourStart - int64(k)*weekMilliseconds
+			to := from + hourMilliseconds
+			j := sort.Search(len(points), func(n int) bool { return points[n].ts >= from })
+			for ; j < len(points) && points[j].ts < to; j++ {
+				values = append(values, points[j].value)
+			}
+		}
+		if len(values) < cfg.WarmUp {
+			continue
+		}
+		expected, spread := meanStdDev(values)
+		baselines[i] = statisticalBaseline{expected: expected, spread: spread, ok: true}
+	}
+	return baselines
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+func medianAbsDeviation(values []float64) (float64, float64) {
+	median := medianOf(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	return median, madScale * medianOf(deviations)
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// 在记录副本上标注检测结果，score 为按检测方向的偏离倍数
+func annotateStatisticalRecord(p statisticalPoint, b statisticalBaseline, cfg interfaces.StatisticalConfig) interfaces.Record {
+	lower := b.expected - cfg.Sensitivity*b.spread
+	upper := b.expected + cfg.Sensitivity*b.spread
+
+	deviation := p.value - b.expected
+	var anomaly bool
+	switch cfg.Direction {
+	case interfaces.STATISTICAL_DIRECTION_UP:
+		deviation = math.Max(deviation, 0)
+		anomaly = p.value > upper
+	case interfaces.STATISTICAL_DIRECTION_DOWN:
+		deviation = math.Max(-deviation, 0)
+		anomaly = p.value < lower
+	default:
+		deviation = math.Abs(deviation)
+		anomaly = p.value > upper || p.value < lower
+	}
+	// 离散程度为 0 时避免除零，偏离倍数按极小的离散程度计算
+	spread := math.Max(b.spread, 1e-9*math.Max(math.Abs(b.expected), 1))
+
+	record := maps.Clone(p.record)
+	record["value"] = p.value
+	record[STATISTICAL_FIELD_EXPECTED] = b.expected
+	record[STATISTICAL_FIELD_LOWER] = lower
+	record[STATISTICAL_FIELD_UPPER] = upper
+	record[STATISTICAL_FIELD_SCORE] = deviation / spread
+	if anomaly {
+		record[STATISTICAL_FIELD_ANOMALY] = float64(1)
+	} else {
+		record[STATISTICAL_FIELD_ANOMALY] = float64(0)
+	}
+	return record
+}
+
+// 从标注过的记录中取出正常范围和观测值
+func anomalyBandOf(algo string, record map[string]any) *interfaces.AnomalyBand {
+	band := &interfaces.AnomalyBand{Algo: algo}
+	band.Observed, _ = recordValue(record["value"])
+	band.Expected, _ = recordValue(record[STATISTICAL_FIELD_EXPECTED])
+	band.Lower, _ = recordValue(record[STATISTICAL_FIELD_LOWER])
+	band.Upper, _ = recordValue(record[STATISTICAL_FIELD_UPPER])
+	band.Score, _ = recordValue(record[STATISTICAL_FIELD_SCORE])
+	return band
+}
+
+func recordTimestamp(v any) (int64, bool) {
+	switch t := v.(type) {
+	case int64:
+		return t, true
+	case int:
+		return int64(t), true
+	case float64:
+		return int64(t), true
+	case json.Number:
+		i, err := t.Int64()
+		return i, err == nil
+	}
+	return 0, false
+}
+
+func recordValue(v any) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, !math.IsNaN(t) && !math.IsInf(t, 0)
+	case int64:
+		return float64(t), true
+	case int:
+		return float64(t), true
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
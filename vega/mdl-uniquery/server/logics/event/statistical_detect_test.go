@@ -0,0 +1,212 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package event
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/interfaces"
+)
+
+const testStatisticalStep = int64(60000)
+
+// 生成在 base 附近交替波动的序列，spikeAt 处的值为 spike
+func statisticalTestRecords(start int64, n int, base float64, spikeAt int, spike float64) interfaces.Records {
+	records := interfaces.Records{}
+	for i := 0; i < n; i++ {
+		value := base + float64(i%2)
+		if i == spikeAt {
+			value = spike
+		}
+		records = append(records, map[string]any{
+			"value":       value,
+			"@timestamp":  start + int64(i)*testStatisticalStep,
+			"labels.host": "a",
+		})
+	}
+	return records
+}
+
+func anomalyTimestamps(records interfaces.Records) []int64 {
+	ts := []int64{}
+	for _, r := range records {
+		if r[STATISTICAL_FIELD_ANOMALY] == float64(1) {
+			ts = append(ts, r["@timestamp"].(int64))
+		}
+	}
+	return ts
+}
+
+func Test_StatisticalConfigOf(t *testing.T) {
+	Convey("Test statisticalConfigOf", t, func() {
+		Convey("Defaults", func() {
+			cfg := statisticalConfigOf(interfaces.DetectRule{})
+			So(cfg, ShouldResemble, interfaces.StatisticalConfig{
+				Step:        DEFAULT_STATISTICAL_STEP,
+				Sensitivity: DEFAULT_STATISTICAL_SENSITIVITY,
+				Window:      DEFAULT_STATISTICAL_WINDOW,
+				WarmUp:      DEFAULT_STATISTICAL_WARM_UP,
+				Alpha:       DEFAULT_STATISTICAL_ALPHA,
+				Weeks:       DEFAULT_STATISTICAL_WEEKS,
+				Direction:   interfaces.STATISTICAL_DIRECTION_BOTH,
+			})
+		})
+
+		Convey("Configured values are kept", func() {
+			cfg := statisticalConfigOf(interfaces.DetectRule{
+				StatisticalConfig: &interfaces.StatisticalConfig{Sensitivity: 2, Window: 30, WarmUp: 5, Alpha: 2},
+			})
+			So(cfg.Sensitivity, ShouldEqual, 2)
+			So(cfg.Window, ShouldEqual, 30)
+			So(cfg.WarmUp, ShouldEqual, 5)
+			So(cfg.Alpha, ShouldEqual, DEFAULT_STATISTICAL_ALPHA)
+		})
+	})
+}
+
+func Test_StatisticalQueryRanges(t *testing.T) {
+	Convey("Test statisticalQueryRanges", t, func() {
+		cfg := interfaces.StatisticalConfig{Window: 10, Weeks: 2}
+		start := 100 * weekMilliseconds
+		end := start + 90*testStatisticalStep
+
+		Convey("Rolling window", func() {
+			ranges := statisticalQueryRanges(interfaces.STATISTICAL_ALGO_ZSCORE, cfg, start, end, testStatisticalStep)
+			So(ranges, ShouldResemble, [][2]int64{{start - 10*testStatisticalStep, end}})
+		})
+
+		Convey("Seasonal covers the same hours of last weeks", func() {
+			ranges := statisticalQueryRanges(interfaces.STATISTICAL_ALGO_SEASONAL, cfg, start, end, testStatisticalStep)
+			So(len(ranges), ShouldEqual, 3)
+			So(ranges[0], ShouldResemble, [2]int64{start, end})
+			So(ranges[1], ShouldResemble, [2]int64{start - weekMilliseconds, start + 2*hourMilliseconds - 1 - weekMilliseconds})
+			So(ranges[2][0], ShouldEqual, start-2*weekMilliseconds)
+		})
+	})
+}
+
+func Test_StatisticalDetect(t *testing.T) {
+	Convey("Test statisticalDetect", t, func() {
+		start := int64(1700000000000)
+		cfg := &interfaces.StatisticalConfig{Window: 20, WarmUp: 10}
+		spikeTs := start + 30*testStatisticalStep
+
+		for _, algo := range []string{
+			interfaces.STATISTICAL_ALGO_ZSCORE,
+			interfaces.STATISTICAL_ALGO_MAD,
+			interfaces.STATISTICAL_ALGO_EWMA,
+		} {
+			Convey("Spike is detected by "+algo, func() {
+				sr := interfaces.SourceRecords{
+					Records:     statisticalTestRecords(start, 40, 10, 30, 100),
+					DetectStart: start + 20*testStatisticalStep,
+				}
+				records := statisticalDetect(sr, interfaces.DetectRule{DetectAlgo: algo, StatisticalConfig: cfg})
+				So(len(records), ShouldEqual, 20)
+				So(anomalyTimestamps(records), ShouldContain, spikeTs)
+
+				for _, r := range records {
+					if r["@timestamp"] == spikeTs {
+						So(r[STATISTICAL_FIELD_UPPER], ShouldBeLessThan, 100)
+						So(r[STATISTICAL_FIELD_SCORE], ShouldBeGreaterThan, 3)
+					}
+				}
+			})
+		}
+
+		Convey("Warm-up points are not judged", func() {
+			sr := interfaces.SourceRecords{Records: statisticalTestRecords(start, 15, 10, -1, 0)}
+			records := statisticalDetect(sr, interfaces.DetectRule{
+				DetectAlgo:        interfaces.STATISTICAL_ALGO_ZSCORE,
+				StatisticalConfig: cfg,
+			})
+			So(len(records), ShouldEqual, 5)
+			So(anomalyTimestamps(records), ShouldBeEmpty)
+		})
+
+		Convey("Direction up ignores drops", func() {
+			sr := interfaces.SourceRecords{Records: statisticalTestRecords(start, 40, 10, 30, -100)}
+			records := statisticalDetect(sr, interfaces.DetectRule{
+				DetectAlgo:        interfaces.STATISTICAL_ALGO_ZSCORE,
+				StatisticalConfig: &interfaces.StatisticalConfig{Window: 20, WarmUp: 10, Direction: interfaces.STATISTICAL_DIRECTION_UP},
+			})
+			So(anomalyTimestamps(records), ShouldNotContain, spikeTs)
+		})
+
+		Convey("Seasonal baseline uses the same hour of last weeks", func() {
+			detectStart := 100 * weekMilliseconds
+			records := interfaces.Records{}
+			for k := int64(1); k <= 2; k++ {
+				records = append(records, statisticalTestRecords(detectStart-k*weekMilliseconds, 60, 10, -1, 0)...)
+			}
+			records = append(records, statisticalTestRecords(detectStart, 10, 10, 5, 50)...)
+
+			sr := interfaces.SourceRecords{Records: records, DetectStart: detectStart}
+			detected := statisticalDetect(sr, interfaces.DetectRule{
+				DetectAlgo:        interfaces.STATISTICAL_ALGO_SEASONAL,
+				StatisticalConfig: &interfaces.StatisticalConfig{Weeks: 2, WarmUp: 60},
+			})
+			So(len(detected), ShouldEqual, 10)
+			So(anomalyTimestamps(detected), ShouldResemble, []int64{detectStart + 5*testStatisticalStep})
+			So(detected[0][STATISTICAL_FIELD_EXPECTED], ShouldEqual, 10.5)
+		})
+
+		Convey("Series are detected separately", func() {
+			records := statisticalTestRecords(start, 40, 10, -1, 0)
+			for _, r := range statisticalTestRecords(start, 40, 1000, -1, 0) {
+				r["labels.host"] = "b"
+				records = append(records, r)
+			}
+			records = append(records, records[0])
+
+			sr := interfaces.SourceRecords{Records: records, DetectStart: start + 20*testStatisticalStep}
+			detected := statisticalDetect(sr, interfaces.DetectRule{
+				DetectAlgo:        interfaces.STATISTICAL_ALGO_MAD,
+				StatisticalConfig: cfg,
+			})
+			So(len(detected), ShouldEqual, 40)
+			So(anomalyTimestamps(detected), ShouldBeEmpty)
+		})
+	})
+}
+
+func Test_EventEngine_JudgeStatistical(t *testing.T) {
+	Convey("Test Judge with statistical detect rule", t, func() {
+		start := int64(1700000000000)
+		em := interfaces.EventModel{
+			EventModelID:   "em_statistical",
+			EventModelName: "cpu",
+			EventModelType: "atomic",
+			DataSourceType: "metric_model",
+			DataSource:     []string{"m1"},
+			DetectRule: interfaces.DetectRule{
+				Type:              interfaces.DETECT_RULE_TYPE_STATISTICAL,
+				DetectAlgo:        interfaces.STATISTICAL_ALGO_ZSCORE,
+				StatisticalConfig: &interfaces.StatisticalConfig{Window: 20, WarmUp: 10},
+			},
+		}
+		sr := interfaces.SourceRecords{
+			Records:     statisticalTestRecords(start, 40, 10, 30, 100),
+			DetectStart: start + 20*testStatisticalStep,
+		}
+
+		rd := &EventEngine{}
+		events, total, err := rd.Judge(testENCtx, sr, em)
+		So(err, ShouldBeNil)
+		So(total, ShouldEqual, 1)
+
+		event := events[0].(interfaces.AtomicEvent)
+		So(event.Level, ShouldEqual, interfaces.EVENT_MODEL_LEVEL_WARNING)
+		So(event.TriggerTime, ShouldEqual, start+30*testStatisticalStep)
+		So(event.Context.Anomaly, ShouldNotBeNil)
+		So(event.Context.Anomaly.Algo, ShouldEqual, interfaces.STATISTICAL_ALGO_ZSCORE)
+		So(event.Context.Anomaly.Observed, ShouldEqual, 100)
+		So(event.Context.Anomaly.Expected, ShouldEqual, 10.5)
+		So(event.Message, ShouldContainSubstring, "normal range is")
+	})
+}
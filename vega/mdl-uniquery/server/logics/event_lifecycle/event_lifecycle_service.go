@@ -0,0 +1,864 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package event_lifecycle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"github.com/panjf2000/ants/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/xid"
+
+	"uniquery/common"
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+	"uniquery/logics"
+	"uniquery/logics/permission"
+)
+
+const (
+	defaultSuppressWindow     = 30 * time.Minute
+	defaultRetention          = 7 * 24 * time.Hour
+	defaultNotifyRetries      = 3
+	defaultNotifyRetryBackoff = 2 * time.Second
+	defaultNotifyTimeout      = 10 * time.Second
+
+	// 同时发送的通知数
+	notifyPoolSize = 16
+)
+
+var (
+	elsOnce   sync.Once
+	elService interfaces.EventLifecycleService
+)
+
+// eventRoute 通知路由，按配置顺序匹配
+type eventRoute struct {
+	setting  common.EventRouteSetting
+	notifier notifier
+	// 接口创建的路由的修改时间，用于判断缓存是否失效
+	updateTime int64
+}
+
+// eventLifecycleService 按去重 key 维护事件的生命周期，并按路由发送通知。
+// store 为空时未开启，Process 直接返回原事件。
+// 状态的读改写由 store 按 key 原子完成，redis 存储下多实例并发处理同一事件时也不会重复发出或覆盖状态变更
+type eventLifecycleService struct {
+	store       lifecycleStore
+	ps          interfaces.PermissionService
+	kafkaAccess interfaces.KafkaAccess
+
+	suppressWindow     time.Duration
+	resolveTimeout     time.Duration
+	notifyRetries      int
+	notifyRetryBackoff time.Duration
+	notifyTimeout      time.Duration
+	smtpSetting        common.SMTPSetting
+	routes             []*eventRoute
+	pool               *ants.Pool
+
+	// 接口创建的路由按 id 缓存创建好的通知渠道，路由修改后重建
+	routeMu    sync.Mutex
+	routeCache map[string]*eventRoute
+
+	now func() time.Time
+}
+
+func NewEventLifecycleService(appSetting *common.AppSetting) interfaces.EventLifecycleService {
+	elsOnce.Do(func() {
+		setting := appSetting.EventLifecycleSetting
+		retention := setting.Retention
+		if retention <= 0 {
+			retention = defaultRetention
+		}
+
+		var store lifecycleStore
+		if setting.Enabled {
+			switch setting.Store {
+			case "", interfaces.EventLifecycleStore_Memory:
+				store = newMemoryStore(retention)
+			case interfaces.EventLifecycleStore_Redis:
+				client := redis.NewClient(&redis.Options{
+					Addr:        fmt.Sprintf("%s:%d", appSetting.RedisSetting.Host, appSetting.RedisSetting.Port),
+					Username:    appSetting.RedisSetting.Username,
+					Password:    appSetting.RedisSetting.Password,
+					DialTimeout: 5 * time.Second,
+				})
+				store = newRedisStore(client, retention)
+			default:
+				logger.Fatalf("unsupported event lifecycle store: %s", setting.Store)
+			}
+			logger.Infof("Event lifecycle store: %s, retention: %v", setting.Store, retention)
+		}
+
+		elService = newEventLifecycleService(setting, store, permission.NewPermissionService(appSetting), logics.KAccess)
+	})
+	return elService
+}
+
+func newEventLifecycleService(setting common.EventLifecycleSetting, store lifecycleStore,
+	ps interfaces.PermissionService, kafkaAccess interfaces.KafkaAccess) *eventLifecycleService {
+
+	ls := &eventLifecycleService{
+		store:              store,
+		ps:                 ps,
+		kafkaAccess:        kafkaAccess,
+		suppressWindow:     setting.SuppressWindow,
+		resolveTimeout:     setting.ResolveTimeout,
+		notifyRetries:      setting.NotifyRetries,
+		notifyRetryBackoff: setting.NotifyRetryBackoff,
+		notifyTimeout:      setting.NotifyTimeout,
+		smtpSetting:        setting.SMTP,
+		now:                time.Now,
+	}
+	if ls.suppressWindow <= 0 {
+		ls.suppressWindow = defaultSuppressWindow
+	}
+	if ls.notifyRetries <= 0 {
+		ls.notifyRetries = defaultNotifyRetries
+	}
+	if ls.notifyRetryBackoff <= 0 {
+		ls.notifyRetryBackoff = defaultNotifyRetryBackoff
+	}
+	if ls.notifyTimeout <= 0 {
+		ls.notifyTimeout = defaultNotifyTimeout
+	}
+	ls.pool, _ = ants.NewPool(notifyPoolSize, ants.WithNonblocking(false))
+
+	// 配置错误的路由跳过，不影响其他路由
+	for _, route := range setting.Routes {
+		er, err := ls.newRoute(route)
+		if err != nil {
+			logger.Errorf("Event route %s: %v", route.Name, err)
+			continue
+		}
+		ls.routes = append(ls.routes, er)
+	}
+	ls.routeCache = map[string]*eventRoute{}
+	return ls
+}
+
+// 按路由配置创建通知渠道
+func (ls *eventLifecycleService) newRoute(setting common.EventRouteSetting) (*eventRoute, error) {
+	factory, ok := notifierFactories[setting.Channel]
+	if !ok {
+		return nil, fmt.Errorf("unsupported channel %s", setting.Channel)
+	}
+	n, err := factory(ls, setting)
+	if err != nil {
+		return nil, err
+	}
+	return &eventRoute{setting: setting, notifier: n}, nil
+}
+
+func (ls *eventLifecycleService) Enabled() bool {
+	return ls.store != nil
+}
+
+// 事件的去重 key，由事件模型 id 和排序后的标签计算
+func DedupKey(eventModelID string, labels map[string]string) string {
+	sum := sha256.Sum256([]byte(common.GenerateUniqueKey(eventModelID, labels)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// 按去重 key 更新事件状态，返回需要持久化的事件。
+// 新出现的事件（或已恢复后再次出现）进入 firing 并持久化；仍未恢复的事件只在距上次持久化超过抑制窗口时再持久化一次。
+// 持久化的 firing 事件未被静默时发送通知，已确认的事件不再通知。
+// 读写状态失败时放行事件，避免丢失
+func (ls *eventLifecycleService) Process(ctx context.Context, em interfaces.EventModel, events interfaces.IEvents) interfaces.IEvents {
+	if ls.store == nil || len(events) == 0 {
+		return events
+	}
+
+	silences, err := ls.activeSilences(ctx, em.EventModelID)
+	if err != nil {
+		logger.Errorf("List silences of event model %s failed: %v", em.EventModelID, err)
+	}
+
+	emitted := make(interfaces.IEvents, 0, len(events))
+	notifications := []*interfaces.EventNotification{}
+
+	now := ls.now().UnixMilli()
+	for _, event := range events {
+		base := event.GetBaseEvent()
+		key := DedupKey(em.EventModelID, base.Labels)
+		eventTime := base.CreateTime
+		if eventTime == 0 {
+			eventTime = now
+		}
+
+		emit := false
+		state, err := ls.store.UpdateState(ctx, key, func(state *interfaces.EventLifecycle) (*interfaces.EventLifecycle, error) {
+			emit = false
+			if state != nil {
+				ls.autoResolve(state, now)
+			}
+
+			if state == nil || state.Status == interfaces.EventStatus_Resolved {
+				state = newLifecycle(key, em.EventModelID, base, eventTime, state)
+				emit = true
+			} else {
+				state.Count++
+				state.LastTime = eventTime
+				state.Level = event.GetLevel()
+				if now-state.LastEmitTime >= ls.suppressWindow.Milliseconds() {
+					emit = true
+				} else {
+					state.SuppressCount++
+				}
+			}
+			state.SilencedBy = matchSilences(silences, base.Labels)
+
+			if emit {
+				state.LastEventID = base.Id
+				state.LastEmitTime = now
+			}
+			return state, nil
+		})
+		if err != nil {
+			logger.Errorf("Update lifecycle of event %s failed: %v", base.Id, err)
+			emitted = append(emitted, event)
+			continue
+		}
+
+		if emit {
+			emitted = append(emitted, event)
+			if state.Status == interfaces.EventStatus_Firing && len(state.SilencedBy) == 0 {
+				notifications = append(notifications, newNotification(state, event, interfaces.EventStatus_Firing, "", ""))
+			}
+		}
+	}
+
+	if len(emitted) < len(events) {
+		logger.Debugf("Event model %s: %d of %d events suppressed by dedup", em.EventModelName, len(events)-len(emitted), len(events))
+	}
+	if len(notifications) > 0 {
+		routes := ls.currentRoutes(ctx)
+		for _, n := range notifications {
+			ls.dispatch(routes, n)
+		}
+	}
+	return emitted
+}
+
+// 查询事件所属的生命周期
+func (ls *eventLifecycleService) GetLifecycle(ctx context.Context, eventModelID string, labels map[string]string) (*interfaces.EventLifecycle, error) {
+	if ls.store == nil {
+		return nil, nil
+	}
+
+	state, ok, err := ls.store.GetState(ctx, DedupKey(eventModelID, labels))
+	if err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventLifecycle_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+	if !ok {
+		return nil, nil
+	}
+	ls.autoResolve(state, ls.now().UnixMilli())
+	return state, nil
+}
+
+// 变更事件状态，firing 可确认或恢复，acknowledged 可恢复，已恢复的事件不能再变更
+func (ls *eventLifecycleService) UpdateStatus(ctx context.Context, event interfaces.IEvent,
+	req *interfaces.EventStatusUpdateRequest) (*interfaces.EventLifecycle, error) {
+
+	if ls.store == nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_NotEnabled)
+	}
+
+	base := event.GetBaseEvent()
+	key := DedupKey(base.EventModelId, base.Labels)
+
+	now := ls.now().UnixMilli()
+	operator := accountInfo(ctx).ID
+	state, err := ls.store.UpdateState(ctx, key, func(state *interfaces.EventLifecycle) (*interfaces.EventLifecycle, error) {
+		if state == nil {
+			return nil, rest.NewHTTPError(ctx, http.StatusNotFound, uerrors.Uniquery_EventLifecycle_LifecycleNotFound).
+				WithErrorDetails(fmt.Sprintf("The lifecycle of event '%s' not found", base.Id))
+		}
+
+		ls.autoResolve(state, now)
+		if state.Status == interfaces.EventStatus_Resolved || state.Status == req.Status {
+			return nil, rest.NewHTTPError(ctx, http.StatusConflict, uerrors.Uniquery_EventLifecycle_InvalidTransition).
+				WithErrorDetails(fmt.Sprintf("The event is %s, can not change to %s", state.Status, req.Status))
+		}
+
+		transit(state, req.Status, operator, req.Comment, now)
+		return state, nil
+	})
+	if err != nil {
+		if httpErr, ok := err.(*rest.HTTPError); ok {
+			return nil, httpErr
+		}
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventLifecycle_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+
+	if len(state.SilencedBy) == 0 {
+		ls.dispatch(ls.currentRoutes(ctx), newNotification(state, event, req.Status, operator, req.Comment))
+	}
+	return state, nil
+}
+
+// 创建静默规则，结束时间后自动失效
+func (ls *eventLifecycleService) CreateSilence(ctx context.Context, req *interfaces.EventSilenceRequest) (*interfaces.EventSilence, error) {
+	if ls.store == nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_NotEnabled)
+	}
+
+	err := ls.ps.CheckPermission(ctx, interfaces.Resource{
+		ID:   req.EventModelID,
+		Type: interfaces.RESOURCE_TYPE_EVENT_MODEL,
+	}, []string{interfaces.OPERATION_TYPE_MODIFY})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range req.Matchers {
+		if _, err := newLabelMatcher(m); err != nil {
+			return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_InvalidParameter_Matcher).
+				WithErrorDetails(err.Error())
+		}
+	}
+
+	now := ls.now().UnixMilli()
+	startTime := req.StartTime
+	if startTime == 0 {
+		startTime = now
+	}
+	if req.EndTime <= startTime || req.EndTime <= now {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_InvalidParameter_TimeRange).
+			WithErrorDetails("The end_time must be later than start_time and now")
+	}
+
+	silence := &interfaces.EventSilence{
+		ID:           xid.New().String(),
+		EventModelID: req.EventModelID,
+		Matchers:     req.Matchers,
+		StartTime:    startTime,
+		EndTime:      req.EndTime,
+		Comment:      req.Comment,
+		Creator:      accountInfo(ctx),
+		CreateTime:   now,
+	}
+	if err = ls.store.SetSilence(ctx, silence, time.Duration(req.EndTime-now)*time.Millisecond); err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventLifecycle_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+	return silence, nil
+}
+
+// 查询事件模型未过期的静默规则，按创建时间倒序
+func (ls *eventLifecycleService) ListSilences(ctx context.Context, eventModelID string) ([]*interfaces.EventSilence, error) {
+	if ls.store == nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_NotEnabled)
+	}
+
+	err := ls.ps.CheckPermission(ctx, interfaces.Resource{
+		ID:   eventModelID,
+		Type: interfaces.RESOURCE_TYPE_EVENT_MODEL,
+	}, []string{interfaces.OPERATION_TYPE_VIEW_DETAIL})
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := ls.store.ListSilences(ctx)
+	if err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventLifecycle_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+	silences := []*interfaces.EventSilence{}
+	for _, s := range all {
+		if s.EventModelID == eventModelID {
+			silences = append(silences, s)
+		}
+	}
+	sort.Slice(silences, func(i, j int) bool {
+		return silences[i].CreateTime > silences[j].CreateTime
+	})
+	return silences, nil
+}
+
+// 删除静默规则
+func (ls *eventLifecycleService) DeleteSilence(ctx context.Context, silenceID string) error {
+	if ls.store == nil {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_NotEnabled)
+	}
+
+	silence, ok, err := ls.store.GetSilence(ctx, silenceID)
+	if err != nil {
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventLifecycle_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+	if !ok {
+		return rest.NewHTTPError(ctx, http.StatusNotFound, uerrors.Uniquery_EventLifecycle_SilenceNotFound).
+			WithErrorDetails(fmt.Sprintf("The silence '%s' not found", silenceID))
+	}
+
+	err = ls.ps.CheckPermission(ctx, interfaces.Resource{
+		ID:   silence.EventModelID,
+		Type: interfaces.RESOURCE_TYPE_EVENT_MODEL,
+	}, []string{interfaces.OPERATION_TYPE_MODIFY})
+	if err != nil {
+		return err
+	}
+
+	if err = ls.store.DeleteSilence(ctx, silenceID); err != nil {
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventLifecycle_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+	return nil
+}
+
+// 创建通知路由，需要有路由中所有事件模型的修改权限
+func (ls *eventLifecycleService) CreateRoute(ctx context.Context, req *interfaces.EventRouteRequest) (*interfaces.EventRoute, error) {
+	if ls.store == nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_NotEnabled)
+	}
+
+	if err := ls.checkRoutePermission(ctx, req.EventModelIDs, interfaces.OPERATION_TYPE_MODIFY); err != nil {
+		return nil, err
+	}
+
+	now := ls.now().UnixMilli()
+	route := &interfaces.EventRoute{
+		ID:         xid.New().String(),
+		Creator:    accountInfo(ctx),
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	setRouteRequest(route, req)
+	if _, err := ls.newRoute(routeSetting(route)); err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_InvalidParameter_Route).
+			WithErrorDetails(err.Error())
+	}
+
+	if err := ls.store.SetRoute(ctx, route); err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventLifecycle_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+	return route, nil
+}
+
+// 查询作用于事件模型的通知路由，按匹配顺序返回
+func (ls *eventLifecycleService) ListRoutes(ctx context.Context, eventModelID string) ([]*interfaces.EventRoute, error) {
+	if ls.store == nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_NotEnabled)
+	}
+
+	if err := ls.checkRoutePermission(ctx, []string{eventModelID}, interfaces.OPERATION_TYPE_VIEW_DETAIL); err != nil {
+		return nil, err
+	}
+
+	all, err := ls.store.ListRoutes(ctx)
+	if err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventLifecycle_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+	routes := []*interfaces.EventRoute{}
+	for _, r := range all {
+		if slices.Contains(r.EventModelIDs, eventModelID) {
+			routes = append(routes, r)
+		}
+	}
+	sortRoutes(routes)
+	return routes, nil
+}
+
+// 修改通知路由，需要有修改前后所有事件模型的修改权限
+func (ls *eventLifecycleService) UpdateRoute(ctx context.Context, routeID string,
+	req *interfaces.EventRouteRequest) (*interfaces.EventRoute, error) {
+
+	route, err := ls.getRoute(ctx, routeID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventModelIDs := append(slices.Clone(route.EventModelIDs), req.EventModelIDs...)
+	if err = ls.checkRoutePermission(ctx, eventModelIDs, interfaces.OPERATION_TYPE_MODIFY); err != nil {
+		return nil, err
+	}
+
+	setRouteRequest(route, req)
+	route.UpdateTime = ls.now().UnixMilli()
+	if _, err = ls.newRoute(routeSetting(route)); err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_InvalidParameter_Route).
+			WithErrorDetails(err.Error())
+	}
+
+	if err = ls.store.SetRoute(ctx, route); err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventLifecycle_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+	return route, nil
+}
+
+// 删除通知路由
+func (ls *eventLifecycleService) DeleteRoute(ctx context.Context, routeID string) error {
+	route, err := ls.getRoute(ctx, routeID)
+	if err != nil {
+		return err
+	}
+
+	if err = ls.checkRoutePermission(ctx, route.EventModelIDs, interfaces.OPERATION_TYPE_MODIFY); err != nil {
+		return err
+	}
+
+	if err = ls.store.DeleteRoute(ctx, routeID); err != nil {
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventLifecycle_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+	return nil
+}
+
+func (ls *eventLifecycleService) getRoute(ctx context.Context, routeID string) (*interfaces.EventRoute, error) {
+	if ls.store == nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_EventLifecycle_NotEnabled)
+	}
+
+	route, ok, err := ls.store.GetRoute(ctx, routeID)
+	if err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_EventLifecycle_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+	if !ok {
+		return nil, rest.NewHTTPError(ctx, http.StatusNotFound, uerrors.Uniquery_EventLifecycle_RouteNotFound).
+			WithErrorDetails(fmt.Sprintf("The route '%s' not found", routeID))
+	}
+	return route, nil
+}
+
+func (ls *eventLifecycleService) checkRoutePermission(ctx context.Context, eventModelIDs []string, operation string) error {
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(eventModelIDs))) {
+		err := ls.ps.CheckPermission(ctx, interfaces.Resource{
+			ID:   id,
+			Type: interfaces.RESOURCE_TYPE_EVENT_MODEL,
+		}, []string{operation})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 当前生效的路由：配置的路由在前，接口创建的路由按创建时间排在后面。读取路由失败时只使用配置的路由
+func (ls *eventLifecycleService) currentRoutes(ctx context.Context) []*eventRoute {
+	stored, err := ls.store.ListRoutes(ctx)
+	if err != nil {
+		logger.Errorf("List event routes failed: %v", err)
+		return ls.routes
+	}
+	sortRoutes(stored)
+
+	routes := make([]*eventRoute, 0, len(ls.routes)+len(stored))
+	routes = append(routes, ls.routes...)
+
+	ls.routeMu.Lock()
+	defer ls.routeMu.Unlock()
+
+	cache := make(map[string]*eventRoute, len(stored))
+	for _, r := range stored {
+		er, ok := ls.routeCache[r.ID]
+		if !ok || er.updateTime != r.UpdateTime {
+			er, err = ls.newRoute(routeSetting(r))
+			if err != nil {
+				logger.Errorf("Event route %s: %v", r.Name, err)
+				continue
+			}
+			er.updateTime = r.UpdateTime
+		}
+		cache[r.ID] = er
+		routes = append(routes, er)
+	}
+	ls.routeCache = cache
+	return routes
+}
+
+// 超过 resolveTimeout 未再出现的事件视为已恢复，在下次读取时补记状态变更，不发送通知
+func (ls *eventLifecycleService) autoResolve(state *interfaces.EventLifecycle, now int64) {
+	if ls.resolveTimeout <= 0 || state.Status == interfaces.EventStatus_Resolved {
+		return
+	}
+	resolveTime := state.LastTime + ls.resolveTimeout.Milliseconds()
+	if now > resolveTime {
+		transit(state, interfaces.EventStatus_Resolved, interfaces.EVENT_OPERATOR_SYSTEM, "", resolveTime)
+	}
+}
+
+// 查询事件模型当前生效的静默规则
+func (ls *eventLifecycleService) activeSilences(ctx context.Context, eventModelID string) ([]*silenceMatcher, error) {
+	all, err := ls.store.ListSilences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := ls.now().UnixMilli()
+	silences := []*silenceMatcher{}
+	for _, s := range all {
+		if s.EventModelID != eventModelID || s.StartTime > now || s.EndTime <= now {
+			continue
+		}
+		sm, err := newSilenceMatcher(s)
+		if err != nil {
+			logger.Errorf("Invalid silence %s: %v", s.ID, err)
+			continue
+		}
+		silences = append(silences, sm)
+	}
+	return silences, nil
+}
+
+// 按路由发送通知，未设置 continue 的路由匹配后不再匹配后续路由
+func (ls *eventLifecycleService) dispatch(routes []*eventRoute, n *interfaces.EventNotification) {
+	for _, route := range routes {
+		if !routeMatches(route.setting, n) {
+			continue
+		}
+
+		rn := *n
+		rn.Route = route.setting.Name
+		r := route
+		err := ls.pool.Submit(func() {
+			ls.send(r, &rn)
+		})
+		if err != nil {
+			logger.Errorf("Submit notification of event %s to route %s failed: %v", n.EventID, r.setting.Name, err)
+		}
+
+		if !route.setting.Continue {
+			return
+		}
+	}
+}
+
+// 发送通知，失败时按指数退避重试
+func (ls *eventLifecycleService) send(route *eventRoute, n *interfaces.EventNotification) {
+	backoff := ls.notifyRetryBackoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), ls.notifyTimeout)
+		err := route.notifier.Notify(ctx, n)
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt >= ls.notifyRetries {
+			logger.Errorf("Notify event %s to route %s failed after %d attempts: %v", n.EventID, route.setting.Name, attempt+1, err)
+			return
+		}
+		logger.Warnf("Notify event %s to route %s failed, retry in %v: %v", n.EventID, route.setting.Name, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func routeMatches(route common.EventRouteSetting, n *interfaces.EventNotification) bool {
+	if len(route.EventModelIDs) > 0 && !slices.Contains(route.EventModelIDs, n.EventModelID) {
+		return false
+	}
+	if len(route.Levels) > 0 && !slices.Contains(route.Levels, n.Level) {
+		return false
+	}
+	for k, v := range route.Labels {
+		if n.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func setRouteRequest(route *interfaces.EventRoute, req *interfaces.EventRouteRequest) {
+	route.Name = req.Name
+	route.EventModelIDs = req.EventModelIDs
+	route.Levels = req.Levels
+	route.Labels = req.Labels
+	route.Channel = req.Channel
+	route.WebhookURL = req.WebhookURL
+	route.Headers = req.Headers
+	route.EmailTo = req.EmailTo
+	route.KafkaTopic = req.KafkaTopic
+	route.Continue = req.Continue
+}
+
+func routeSetting(route *interfaces.EventRoute) common.EventRouteSetting {
+	return common.EventRouteSetting{
+		Name:          route.Name,
+		EventModelIDs: route.EventModelIDs,
+		Levels:        route.Levels,
+		Labels:        route.Labels,
+		Channel:       route.Channel,
+		WebhookURL:    route.WebhookURL,
+		Headers:       route.Headers,
+		EmailTo:       route.EmailTo,
+		KafkaTopic:    route.KafkaTopic,
+		Continue:      route.Continue,
+	}
+}
+
+// 按创建时间先后排序，创建时间相同时按 id 排序，保证各实例的匹配顺序一致
+func sortRoutes(routes []*interfaces.EventRoute) {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].CreateTime != routes[j].CreateTime {
+			return routes[i].CreateTime < routes[j].CreateTime
+		}
+		return routes[i].ID < routes[j].ID
+	})
+}
+
+// 新的生命周期，last 为已恢复的上一次生命周期，保留其状态变更记录
+func newLifecycle(key, eventModelID string, base interfaces.BaseEvent, eventTime int64, last *interfaces.EventLifecycle) *interfaces.EventLifecycle {
+	state := &interfaces.EventLifecycle{
+		DedupKey:     key,
+		EventModelID: eventModelID,
+		Labels:       base.Labels,
+		Level:        base.Level,
+		FirstEventID: base.Id,
+		FirstTime:    eventTime,
+		LastTime:     eventTime,
+		Count:        1,
+		Transitions:  []interfaces.EventTransition{},
+	}
+	if last != nil {
+		state.Status = last.Status
+		state.Transitions = last.Transitions
+	}
+	transit(state, interfaces.EventStatus_Firing, interfaces.EVENT_OPERATOR_SYSTEM, "", eventTime)
+	return state
+}
+
+// 记录状态变更，只保留最近的 EVENT_TRANSITION_MAX_SIZE 条
+func transit(state *interfaces.EventLifecycle, to, operator, comment string, t int64) {
+	state.Transitions = append(state.Transitions, interfaces.EventTransition{
+		From:     state.Status,
+		To:       to,
+		Operator: operator,
+		Comment:  comment,
+		Time:     t,
+	})
+	if len(state.Transitions) > interfaces.EVENT_TRANSITION_MAX_SIZE {
+		state.Transitions = state.Transitions[len(state.Transitions)-interfaces.EVENT_TRANSITION_MAX_SIZE:]
+	}
+	state.Status = to
+}
+
+func newNotification(state *interfaces.EventLifecycle, event interfaces.IEvent, status, operator, comment string) *interfaces.EventNotification {
+	base := event.GetBaseEvent()
+	message, _ := event.GenerateMessage().(string)
+	return &interfaces.EventNotification{
+		Status:         status,
+		DedupKey:       state.DedupKey,
+		EventID:        base.Id,
+		EventModelID:   state.EventModelID,
+		EventModelName: base.EventModelName,
+		Title:          base.Title,
+		Level:          event.GetLevel(),
+		LevelName:      base.LevelName,
+		Message:        message,
+		Labels:         state.Labels,
+		TriggerTime:    event.GetTriggerTime(),
+		FirstTime:      state.FirstTime,
+		Count:          state.Count,
+		Operator:       operator,
+		Comment:        comment,
+	}
+}
+
+// silenceMatcher 编译后的静默规则
+type silenceMatcher struct {
+	id       string
+	matchers []*labelMatcher
+}
+
+func newSilenceMatcher(s *interfaces.EventSilence) (*silenceMatcher, error) {
+	sm := &silenceMatcher{id: s.ID}
+	for _, m := range s.Matchers {
+		lm, err := newLabelMatcher(m)
+		if err != nil {
+			return nil, err
+		}
+		sm.matchers = append(sm.matchers, lm)
+	}
+	return sm, nil
+}
+
+// 返回匹配标签的静默规则 id，规则的所有条件都满足时才匹配
+func matchSilences(silences []*silenceMatcher, labels map[string]string) []string {
+	ids := []string{}
+	for _, s := range silences {
+		matched := true
+		for _, m := range s.matchers {
+			if !m.matches(labels) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			ids = append(ids, s.id)
+		}
+	}
+	return ids
+}
+
+// labelMatcher 标签匹配条件，标签不存在时按空字符串匹配，正则需完整匹配
+type labelMatcher struct {
+	name     string
+	value    string
+	operator string
+	re       *regexp.Regexp
+}
+
+func newLabelMatcher(m interfaces.LabelMatcher) (*labelMatcher, error) {
+	lm := &labelMatcher{name: m.Name, value: m.Value, operator: m.Operator}
+	switch m.Operator {
+	case "":
+		lm.operator = interfaces.LabelMatch_Equal
+	case interfaces.LabelMatch_Equal, interfaces.LabelMatch_NotEqual:
+	case interfaces.LabelMatch_Regex, interfaces.LabelMatch_NotRegex:
+		re, err := regexp.Compile("^(?:" + m.Value + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex of label %s: %w", m.Name, err)
+		}
+		lm.re = re
+	default:
+		return nil, fmt.Errorf("unsupported operator %s of label %s", m.Operator, m.Name)
+	}
+	if m.Name == "" {
+		return nil, fmt.Errorf("label name is empty")
+	}
+	return lm, nil
+}
+
+func (lm *labelMatcher) matches(labels map[string]string) bool {
+	v := labels[lm.name]
+	switch lm.operator {
+	case interfaces.LabelMatch_NotEqual:
+		return v != lm.value
+	case interfaces.LabelMatch_Regex:
+		return lm.re.MatchString(v)
+	case interfaces.LabelMatch_NotRegex:
+		return !lm.re.MatchString(v)
+	default:
+		return v == lm.value
+	}
+}
+
+func accountInfo(ctx context.Context) interfaces.AccountInfo {
+	if info, ok := ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo); ok {
+		return info
+	}
+	return interfaces.AccountInfo{}
+}
@@ -0,0 +1,648 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package event_lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/sonic"
+	"github.com/golang/mock/gomock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"github.com/redis/go-redis/v9"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/common"
+	"uniquery/interfaces"
+	imock "uniquery/interfaces/mock"
+)
+
+var (
+	testCtx = context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+
+	testEventModel = interfaces.EventModel{
+		EventModelID:   "em1",
+		EventModelName: "cpu",
+	}
+)
+
+// recordNotifier 记录收到的通知，前 failures 次发送返回错误
+type recordNotifier struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	ch       chan *interfaces.EventNotification
+}
+
+func newRecordNotifier(failures int) *recordNotifier {
+	return &recordNotifier{failures: failures, ch: make(chan *interfaces.EventNotification, 10)}
+}
+
+func (rn *recordNotifier) Notify(ctx context.Context, n *interfaces.EventNotification) error {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	rn.calls++
+	if rn.calls <= rn.failures {
+		return errors.New("send failed")
+	}
+	rn.ch <- n
+	return nil
+}
+
+// 等待通知，超时返回 nil
+func (rn *recordNotifier) wait() *interfaces.EventNotification {
+	select {
+	case n := <-rn.ch:
+		return n
+	case <-time.After(time.Second):
+		return nil
+	}
+}
+
+func newTestService(ps interfaces.PermissionService, notifiers ...*recordNotifier) (*eventLifecycleService, *time.Time) {
+	ls := newEventLifecycleService(common.EventLifecycleSetting{
+		SuppressWindow:     10 * time.Minute,
+		ResolveTimeout:     time.Hour,
+		NotifyRetryBackoff: time.Millisecond,
+	}, newMemoryStore(time.Hour), ps, nil)
+
+	for i, n := range notifiers {
+		ls.routes = append(ls.routes, &eventRoute{
+			setting:  common.EventRouteSetting{Name: "route" + string(rune('a'+i)), Continue: true},
+			notifier: n,
+		})
+	}
+
+	now := time.UnixMilli(1700000000000)
+	ls.now = func() time.Time { return now }
+	return ls, &now
+}
+
+func testEvent(id string, createTime int64, labels map[string]string) interfaces.AtomicEvent {
+	return interfaces.AtomicEvent{
+		BaseEvent: interfaces.BaseEvent{
+			Id:             id,
+			Title:          "cpu high",
+			EventModelId:   testEventModel.EventModelID,
+			EventModelName: testEventModel.EventModelName,
+			Level:          interfaces.EVENT_MODEL_LEVEL_MAJOR,
+			LevelName:      "major",
+			CreateTime:     createTime,
+			Labels:         labels,
+		},
+		Message: "cpu usage is 95%",
+	}
+}
+
+func Test_DedupKey(t *testing.T) {
+	Convey("Test DedupKey", t, func() {
+		k1 := DedupKey("em1", map[string]string{"host": "a", "app": "x"})
+		k2 := DedupKey("em1", map[string]string{"app": "x", "host": "a"})
+		So(k1, ShouldEqual, k2)
+		So(len(k1), ShouldEqual, 32)
+		So(DedupKey("em2", map[string]string{"host": "a", "app": "x"}), ShouldNotEqual, k1)
+		So(DedupKey("em1", map[string]string{"host": "b", "app": "x"}), ShouldNotEqual, k1)
+	})
+}
+
+func Test_EventLifecycleService_Process(t *testing.T) {
+	Convey("Test Process", t, func() {
+		labels := map[string]string{"host": "a"}
+
+		Convey("Disabled service returns events as is", func() {
+			ls := newEventLifecycleService(common.EventLifecycleSetting{}, nil, nil, nil)
+			events := interfaces.IEvents{testEvent("1", 1, labels), testEvent("2", 2, labels)}
+			So(ls.Enabled(), ShouldBeFalse)
+			So(ls.Process(testCtx, testEventModel, events), ShouldResemble, events)
+		})
+
+		Convey("Duplicates in suppress window are dropped", func() {
+			rn := newRecordNotifier(0)
+			ls, now := newTestService(nil, rn)
+			t0 := now.UnixMilli()
+
+			emitted := ls.Process(testCtx, testEventModel, interfaces.IEvents{
+				testEvent("1", t0, labels),
+				testEvent("2", t0, labels),
+				testEvent("3", t0, map[string]string{"host": "b"}),
+			})
+			So(len(emitted), ShouldEqual, 2)
+			So(emitted[0].GetBaseEvent().Id, ShouldEqual, "1")
+			So(emitted[1].GetBaseEvent().Id, ShouldEqual, "3")
+			So(rn.wait(), ShouldNotBeNil)
+			So(rn.wait(), ShouldNotBeNil)
+
+			*now = now.Add(5 * time.Minute)
+			emitted = ls.Process(testCtx, testEventModel, interfaces.IEvents{testEvent("4", now.UnixMilli(), labels)})
+			So(emitted, ShouldBeEmpty)
+
+			lc, err := ls.GetLifecycle(testCtx, testEventModel.EventModelID, labels)
+			So(err, ShouldBeNil)
+			So(lc.Status, ShouldEqual, interfaces.EventStatus_Firing)
+			So(lc.Count, ShouldEqual, 3)
+			So(lc.SuppressCount, ShouldEqual, 2)
+			So(lc.FirstEventID, ShouldEqual, "1")
+			So(lc.LastEventID, ShouldEqual, "1")
+			So(lc.FirstTime, ShouldEqual, t0)
+
+			Convey("Emitted and notified again after the window", func() {
+				*now = now.Add(6 * time.Minute)
+				emitted = ls.Process(testCtx, testEventModel, interfaces.IEvents{testEvent("5", now.UnixMilli(), labels)})
+				So(len(emitted), ShouldEqual, 1)
+
+				n := rn.wait()
+				So(n, ShouldNotBeNil)
+				So(n.EventID, ShouldEqual, "5")
+				So(n.Count, ShouldEqual, 4)
+				So(n.FirstTime, ShouldEqual, t0)
+				So(n.Route, ShouldEqual, "routea")
+			})
+
+			Convey("Acknowledged events are emitted but not notified", func() {
+				_, err := ls.UpdateStatus(testCtx, testEvent("1", t0, labels),
+					&interfaces.EventStatusUpdateRequest{Status: interfaces.EventStatus_Acknowledged})
+				So(err, ShouldBeNil)
+				So(rn.wait().Status, ShouldEqual, interfaces.EventStatus_Acknowledged)
+
+				*now = now.Add(6 * time.Minute)
+				emitted = ls.Process(testCtx, testEventModel, interfaces.IEvents{testEvent("5", now.UnixMilli(), labels)})
+				So(len(emitted), ShouldEqual, 1)
+				So(rn.wait(), ShouldBeNil)
+			})
+
+			Convey("Resolved event fires again with a new lifecycle", func() {
+				_, err := ls.UpdateStatus(testCtx, testEvent("1", t0, labels),
+					&interfaces.EventStatusUpdateRequest{Status: interfaces.EventStatus_Resolved, Comment: "fixed"})
+				So(err, ShouldBeNil)
+				So(rn.wait().Comment, ShouldEqual, "fixed")
+
+				*now = now.Add(time.Minute)
+				emitted = ls.Process(testCtx, testEventModel, interfaces.IEvents{testEvent("6", now.UnixMilli(), labels)})
+				So(len(emitted), ShouldEqual, 1)
+				So(rn.wait().EventID, ShouldEqual, "6")
+
+				lc, _ := ls.GetLifecycle(testCtx, testEventModel.EventModelID, labels)
+				So(lc.Status, ShouldEqual, interfaces.EventStatus_Firing)
+				So(lc.FirstEventID, ShouldEqual, "6")
+				So(lc.Count, ShouldEqual, 1)
+				So(len(lc.Transitions), ShouldEqual, 3)
+				So(lc.Transitions[1], ShouldResemble, interfaces.EventTransition{
+					From: interfaces.EventStatus_Firing, To: interfaces.EventStatus_Resolved,
+					Operator: "", Comment: "fixed", Time: lc.Transitions[1].Time,
+				})
+				So(lc.Transitions[2].From, ShouldEqual, interfaces.EventStatus_Resolved)
+			})
+
+			Convey("Auto resolved after resolve timeout", func() {
+				*now = now.Add(2 * time.Hour)
+				lc, _ := ls.GetLifecycle(testCtx, testEventModel.EventModelID, labels)
+				So(lc.Status, ShouldEqual, interfaces.EventStatus_Resolved)
+				So(lc.Transitions[len(lc.Transitions)-1].Operator, ShouldEqual, interfaces.EVENT_OPERATOR_SYSTEM)
+
+				emitted = ls.Process(testCtx, testEventModel, interfaces.IEvents{testEvent("7", now.UnixMilli(), labels)})
+				So(len(emitted), ShouldEqual, 1)
+				lc, _ = ls.GetLifecycle(testCtx, testEventModel.EventModelID, labels)
+				So(lc.FirstEventID, ShouldEqual, "7")
+			})
+		})
+
+		Convey("Silenced events are emitted but not notified", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			psMock := imock.NewMockPermissionService(mockCtrl)
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+			rn := newRecordNotifier(0)
+			ls, now := newTestService(psMock, rn)
+			silence, err := ls.CreateSilence(testCtx, &interfaces.EventSilenceRequest{
+				EventModelID: testEventModel.EventModelID,
+				Matchers:     []interfaces.LabelMatcher{{Name: "host", Value: "a|c", Operator: interfaces.LabelMatch_Regex}},
+				EndTime:      now.Add(time.Hour).UnixMilli(),
+			})
+			So(err, ShouldBeNil)
+
+			emitted := ls.Process(testCtx, testEventModel, interfaces.IEvents{
+				testEvent("1", now.UnixMilli(), labels),
+				testEvent("2", now.UnixMilli(), map[string]string{"host": "b"}),
+			})
+			So(len(emitted), ShouldEqual, 2)
+			So(rn.wait().EventID, ShouldEqual, "2")
+			So(rn.wait(), ShouldBeNil)
+
+			lc, _ := ls.GetLifecycle(testCtx, testEventModel.EventModelID, labels)
+			So(lc.SilencedBy, ShouldResemble, []string{silence.ID})
+		})
+
+		Convey("Failed notifications are retried", func() {
+			rn := newRecordNotifier(2)
+			ls, now := newTestService(nil, rn)
+			ls.Process(testCtx, testEventModel, interfaces.IEvents{testEvent("1", now.UnixMilli(), labels)})
+			So(rn.wait(), ShouldNotBeNil)
+			So(rn.calls, ShouldEqual, 3)
+		})
+	})
+}
+
+func Test_EventLifecycleService_UpdateStatus(t *testing.T) {
+	Convey("Test UpdateStatus", t, func() {
+		labels := map[string]string{"host": "a"}
+		ls, now := newTestService(nil)
+		ls.Process(testCtx, testEventModel, interfaces.IEvents{testEvent("1", now.UnixMilli(), labels)})
+
+		Convey("Not enabled", func() {
+			ls.store = nil
+			_, err := ls.UpdateStatus(testCtx, testEvent("1", 0, labels), &interfaces.EventStatusUpdateRequest{Status: interfaces.EventStatus_Resolved})
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Lifecycle not found", func() {
+			_, err := ls.UpdateStatus(testCtx, testEvent("2", 0, map[string]string{"host": "b"}),
+				&interfaces.EventStatusUpdateRequest{Status: interfaces.EventStatus_Resolved})
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Acknowledge then resolve", func() {
+			ctx := context.WithValue(testCtx, interfaces.ACCOUNT_INFO_KEY, interfaces.AccountInfo{ID: "u1"})
+			lc, err := ls.UpdateStatus(ctx, testEvent("1", 0, labels), &interfaces.EventStatusUpdateRequest{Status: interfaces.EventStatus_Acknowledged})
+			So(err, ShouldBeNil)
+			So(lc.Status, ShouldEqual, interfaces.EventStatus_Acknowledged)
+			So(lc.Transitions[1].Operator, ShouldEqual, "u1")
+
+			_, err = ls.UpdateStatus(ctx, testEvent("1", 0, labels), &interfaces.EventStatusUpdateRequest{Status: interfaces.EventStatus_Acknowledged})
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusConflict)
+
+			lc, err = ls.UpdateStatus(ctx, testEvent("1", 0, labels), &interfaces.EventStatusUpdateRequest{Status: interfaces.EventStatus_Resolved})
+			So(err, ShouldBeNil)
+			So(lc.Status, ShouldEqual, interfaces.EventStatus_Resolved)
+
+			_, err = ls.UpdateStatus(ctx, testEvent("1", 0, labels), &interfaces.EventStatusUpdateRequest{Status: interfaces.EventStatus_Acknowledged})
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusConflict)
+		})
+
+		Convey("Transitions are capped", func() {
+			state := &interfaces.EventLifecycle{}
+			for i := 0; i < interfaces.EVENT_TRANSITION_MAX_SIZE+5; i++ {
+				transit(state, interfaces.EventStatus_Firing, "", "", int64(i))
+			}
+			So(len(state.Transitions), ShouldEqual, interfaces.EVENT_TRANSITION_MAX_SIZE)
+			So(state.Transitions[0].Time, ShouldEqual, 5)
+		})
+	})
+}
+
+func Test_EventLifecycleService_Silences(t *testing.T) {
+	Convey("Test silences", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		psMock := imock.NewMockPermissionService(mockCtrl)
+		ls, now := newTestService(psMock)
+
+		req := &interfaces.EventSilenceRequest{
+			EventModelID: "em1",
+			Matchers:     []interfaces.LabelMatcher{{Name: "host", Value: "a"}},
+			EndTime:      now.Add(time.Hour).UnixMilli(),
+		}
+
+		Convey("Permission denied", func() {
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(rest.NewHTTPError(testCtx, http.StatusForbidden, rest.PublicError_Forbidden))
+			_, err := ls.CreateSilence(testCtx, req)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Invalid matcher", func() {
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			req.Matchers = []interfaces.LabelMatcher{{Name: "host", Value: "(", Operator: interfaces.LabelMatch_Regex}}
+			_, err := ls.CreateSilence(testCtx, req)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Invalid time range", func() {
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			req.StartTime = now.Add(2 * time.Hour).UnixMilli()
+			_, err := ls.CreateSilence(testCtx, req)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Create, list and delete", func() {
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+			s1, err := ls.CreateSilence(testCtx, req)
+			So(err, ShouldBeNil)
+			So(s1.StartTime, ShouldEqual, now.UnixMilli())
+
+			req.EventModelID = "em2"
+			_, err = ls.CreateSilence(testCtx, req)
+			So(err, ShouldBeNil)
+
+			silences, err := ls.ListSilences(testCtx, "em1")
+			So(err, ShouldBeNil)
+			So(len(silences), ShouldEqual, 1)
+			So(silences[0].ID, ShouldEqual, s1.ID)
+
+			So(ls.DeleteSilence(testCtx, s1.ID), ShouldBeNil)
+			silences, _ = ls.ListSilences(testCtx, "em1")
+			So(silences, ShouldBeEmpty)
+
+			err = ls.DeleteSilence(testCtx, s1.ID)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+		})
+	})
+}
+
+func Test_EventLifecycleService_Routes(t *testing.T) {
+	Convey("Test routes API", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		psMock := imock.NewMockPermissionService(mockCtrl)
+		static := newRecordNotifier(0)
+		ls, now := newTestService(psMock, static)
+
+		var got []string
+		var gotMu sync.Mutex
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			n := interfaces.EventNotification{}
+			_ = sonic.Unmarshal(body, &n)
+			gotMu.Lock()
+			got = append(got, n.Route)
+			gotMu.Unlock()
+		}))
+		defer server.Close()
+
+		req := &interfaces.EventRouteRequest{
+			Name:          "oncall",
+			EventModelIDs: []string{"em1"},
+			Channel:       interfaces.NotifyChannel_Webhook,
+			WebhookURL:    server.URL,
+		}
+
+		Convey("Permission denied", func() {
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(rest.NewHTTPError(testCtx, http.StatusForbidden, rest.PublicError_Forbidden))
+			_, err := ls.CreateRoute(testCtx, req)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("Invalid channel parameters", func() {
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			req.WebhookURL = ""
+			_, err := ls.CreateRoute(testCtx, req)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Create, list, update, delete and dispatch", func() {
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+			r1, err := ls.CreateRoute(testCtx, req)
+			So(err, ShouldBeNil)
+			So(r1.CreateTime, ShouldEqual, now.UnixMilli())
+
+			req.EventModelIDs = []string{"em2"}
+			_, err = ls.CreateRoute(testCtx, req)
+			So(err, ShouldBeNil)
+
+			routes, err := ls.ListRoutes(testCtx, "em1")
+			So(err, ShouldBeNil)
+			So(len(routes), ShouldEqual, 1)
+			So(routes[0].ID, ShouldEqual, r1.ID)
+
+			// 配置的路由设置了 continue，接口创建的路由在其后匹配
+			ls.Process(testCtx, testEventModel, interfaces.IEvents{testEvent("1", now.UnixMilli(), map[string]string{"host": "a"})})
+			So(static.wait(), ShouldNotBeNil)
+			So(func() int {
+				deadline := time.Now().Add(time.Second)
+				for time.Now().Before(deadline) {
+					gotMu.Lock()
+					n := len(got)
+					gotMu.Unlock()
+					if n > 0 {
+						return n
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+				return 0
+			}(), ShouldEqual, 1)
+			So(got[0], ShouldEqual, "oncall")
+
+			*now = now.Add(time.Minute)
+			req.Name = "oncall-2"
+			req.EventModelIDs = []string{"em1"}
+			updated, err := ls.UpdateRoute(testCtx, r1.ID, req)
+			So(err, ShouldBeNil)
+			So(updated.Name, ShouldEqual, "oncall-2")
+			So(updated.CreateTime, ShouldEqual, r1.CreateTime)
+			So(updated.UpdateTime, ShouldEqual, now.UnixMilli())
+
+			// 路由修改后重建缓存的通知渠道
+			routesInUse := ls.currentRoutes(testCtx)
+			So(len(routesInUse), ShouldEqual, 3)
+			So(routesInUse[1].setting.Name, ShouldEqual, "oncall-2")
+
+			So(ls.DeleteRoute(testCtx, r1.ID), ShouldBeNil)
+			routes, _ = ls.ListRoutes(testCtx, "em1")
+			So(routes, ShouldBeEmpty)
+
+			err = ls.DeleteRoute(testCtx, r1.ID)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+		})
+	})
+}
+
+func Test_Store_UpdateState(t *testing.T) {
+	Convey("Test concurrent UpdateState of the same key", t, func() {
+		mr := miniredis.RunT(t)
+		stores := map[string]lifecycleStore{
+			"memory": newMemoryStore(time.Hour),
+			"redis":  newRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), time.Hour),
+		}
+
+		for name, store := range stores {
+			Convey(name, func() {
+				increase := func(state *interfaces.EventLifecycle) (*interfaces.EventLifecycle, error) {
+					if state == nil {
+						state = &interfaces.EventLifecycle{DedupKey: "k1"}
+					}
+					state.Count++
+					return state, nil
+				}
+
+				workers := 8
+				var wg sync.WaitGroup
+				errs := make(chan error, workers)
+				for i := 0; i < workers; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						_, err := store.UpdateState(testCtx, "k1", increase)
+						errs <- err
+					}()
+				}
+				wg.Wait()
+				close(errs)
+				for err := range errs {
+					So(err, ShouldBeNil)
+				}
+
+				state, ok, err := store.GetState(testCtx, "k1")
+				So(err, ShouldBeNil)
+				So(ok, ShouldBeTrue)
+				So(state.Count, ShouldEqual, workers)
+
+				// 返回错误或 nil 时不写入
+				_, err = store.UpdateState(testCtx, "k1", func(state *interfaces.EventLifecycle) (*interfaces.EventLifecycle, error) {
+					state.Count = 100
+					return nil, errors.New("conflict")
+				})
+				So(err, ShouldNotBeNil)
+				state, _, _ = store.GetState(testCtx, "k1")
+				So(state.Count, ShouldEqual, workers)
+			})
+		}
+	})
+
+	Convey("Services sharing a redis store dedup the same event", t, func() {
+		mr := miniredis.RunT(t)
+		store := newRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), time.Hour)
+		setting := common.EventLifecycleSetting{SuppressWindow: 10 * time.Minute}
+		ls1 := newEventLifecycleService(setting, store, nil, nil)
+		ls2 := newEventLifecycleService(setting, store, nil, nil)
+
+		labels := map[string]string{"host": "a"}
+		var wg sync.WaitGroup
+		results := make([]interfaces.IEvents, 2)
+		for i, ls := range []*eventLifecycleService{ls1, ls2} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = ls.Process(testCtx, testEventModel, interfaces.IEvents{testEvent("1", 0, labels)})
+			}()
+		}
+		wg.Wait()
+
+		So(len(results[0])+len(results[1]), ShouldEqual, 1)
+		lc, err := ls1.GetLifecycle(testCtx, testEventModel.EventModelID, labels)
+		So(err, ShouldBeNil)
+		So(lc.Count, ShouldEqual, 2)
+		So(lc.SuppressCount, ShouldEqual, 1)
+	})
+}
+
+func Test_LabelMatcher(t *testing.T) {
+	Convey("Test label matchers", t, func() {
+		labels := map[string]string{"host": "web-1", "env": "prod"}
+		cases := []struct {
+			matcher interfaces.LabelMatcher
+			matched bool
+		}{
+			{interfaces.LabelMatcher{Name: "env", Value: "prod"}, true},
+			{interfaces.LabelMatcher{Name: "env", Value: "prod", Operator: interfaces.LabelMatch_NotEqual}, false},
+			{interfaces.LabelMatcher{Name: "host", Value: "web-.*", Operator: interfaces.LabelMatch_Regex}, true},
+			{interfaces.LabelMatcher{Name: "host", Value: "web", Operator: interfaces.LabelMatch_Regex}, false},
+			{interfaces.LabelMatcher{Name: "host", Value: "db-.*", Operator: interfaces.LabelMatch_NotRegex}, true},
+			{interfaces.LabelMatcher{Name: "zone", Value: ""}, true},
+		}
+		for _, c := range cases {
+			lm, err := newLabelMatcher(c.matcher)
+			So(err, ShouldBeNil)
+			So(lm.matches(labels), ShouldEqual, c.matched)
+		}
+
+		_, err := newLabelMatcher(interfaces.LabelMatcher{Name: "host", Operator: "~"})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func Test_RouteMatches(t *testing.T) {
+	Convey("Test routes", t, func() {
+		n := &interfaces.EventNotification{EventModelID: "em1", Level: 2, Labels: map[string]string{"team": "db"}}
+
+		So(routeMatches(common.EventRouteSetting{}, n), ShouldBeTrue)
+		So(routeMatches(common.EventRouteSetting{EventModelIDs: []string{"em2"}}, n), ShouldBeFalse)
+		So(routeMatches(common.EventRouteSetting{Levels: []int{1, 2}}, n), ShouldBeTrue)
+		So(routeMatches(common.EventRouteSetting{Labels: map[string]string{"team": "web"}}, n), ShouldBeFalse)
+
+		Convey("Stop at the first matched route without continue", func() {
+			first, second := newRecordNotifier(0), newRecordNotifier(0)
+			ls, _ := newTestService(nil)
+			ls.routes = []*eventRoute{
+				{setting: common.EventRouteSetting{Name: "db", Labels: map[string]string{"team": "db"}}, notifier: first},
+				{setting: common.EventRouteSetting{Name: "all"}, notifier: second},
+			}
+			ls.dispatch(ls.routes, n)
+			So(first.wait().Route, ShouldEqual, "db")
+			So(second.wait(), ShouldBeNil)
+		})
+
+		Convey("Invalid routes are skipped", func() {
+			ls := newEventLifecycleService(common.EventLifecycleSetting{Routes: []common.EventRouteSetting{
+				{Name: "r1", Channel: "sms"},
+				{Name: "r2", Channel: interfaces.NotifyChannel_Webhook},
+				{Name: "r3", Channel: interfaces.NotifyChannel_Email, EmailTo: []string{"a@b.c"}},
+				{Name: "r4", Channel: interfaces.NotifyChannel_Kafka, KafkaTopic: "events"},
+			}}, nil, nil, nil)
+			So(len(ls.routes), ShouldEqual, 1)
+			So(ls.routes[0].setting.Name, ShouldEqual, "r4")
+		})
+	})
+}
+
+func Test_Notifiers(t *testing.T) {
+	Convey("Test notifiers", t, func() {
+		ls := newEventLifecycleService(common.EventLifecycleSetting{
+			SMTP: common.SMTPSetting{Host: "smtp.example.com", Port: 25, From: "mdl@example.com"},
+		}, nil, nil, nil)
+		n := &interfaces.EventNotification{
+			Status: interfaces.EventStatus_Firing, EventID: "1", Title: "cpu high",
+			Labels: map[string]string{"host": "a"}, Message: "cpu usage is 95%",
+		}
+
+		Convey("Webhook", func() {
+			var got interfaces.EventNotification
+			var token string
+			status := http.StatusOK
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				_ = sonic.Unmarshal(body, &got)
+				token = r.Header.Get("X-Token")
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			wn, err := newWebhookNotifier(ls, common.EventRouteSetting{WebhookURL: server.URL, Headers: map[string]string{"X-Token": "t"}})
+			So(err, ShouldBeNil)
+			So(wn.Notify(testCtx, n), ShouldBeNil)
+			So(got.EventID, ShouldEqual, "1")
+			So(token, ShouldEqual, "t")
+
+			status = http.StatusBadGateway
+			So(wn.Notify(testCtx, n), ShouldNotBeNil)
+		})
+
+		Convey("Email", func() {
+			en, err := newEmailNotifier(ls, common.EventRouteSetting{EmailTo: []string{"oncall@example.com"}})
+			So(err, ShouldBeNil)
+
+			var addr string
+			var msg []byte
+			en.(*emailNotifier).sendMail = func(a string, auth smtp.Auth, from string, to []string, m []byte) error {
+				addr, msg = a, m
+				return nil
+			}
+			So(en.Notify(testCtx, n), ShouldBeNil)
+			So(addr, ShouldEqual, "smtp.example.com:25")
+			So(string(msg), ShouldContainSubstring, "Subject: [FIRING] cpu high")
+			So(string(msg), ShouldContainSubstring, "Label host: a")
+			So(string(msg), ShouldContainSubstring, "cpu usage is 95%")
+		})
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package event_lifecycle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/smtp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"uniquery/common"
+	"uniquery/interfaces"
+)
+
+// notifier 通知渠道，发送失败时返回错误，由调用方重试
+type notifier interface {
+	Notify(ctx context.Context, n *interfaces.EventNotification) error
+}
+
+// notifierFactory 按路由配置创建通知渠道
+type notifierFactory func(ls *eventLifecycleService, route common.EventRouteSetting) (notifier, error)
+
+// 注册的通知渠道，新增渠道时在此注册
+var notifierFactories = map[string]notifierFactory{
+	interfaces.NotifyChannel_Webhook: newWebhookNotifier,
+	interfaces.NotifyChannel_Email:   newEmailNotifier,
+	interfaces.NotifyChannel_Kafka:   newKafkaNotifier,
+}
+
+// webhookNotifier 以 JSON 格式 POST 通知内容，非 2xx 的响应视为失败
+type webhookNotifier struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newWebhookNotifier(ls *eventLifecycleService, route common.EventRouteSetting) (notifier, error) {
+	if route.WebhookURL == "" {
+		return nil, fmt.Errorf("route %s: webhookUrl is required", route.Name)
+	}
+	return &webhookNotifier{
+		url:     route.WebhookURL,
+		headers: route.Headers,
+		client:  &http.Client{Timeout: ls.notifyTimeout},
+	}, nil
+}
+
+func (wn *webhookNotifier) Notify(ctx context.Context, n *interfaces.EventNotification) error {
+	body, err := sonic.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range wn.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := wn.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook %s responded %d: %s", wn.url, resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// sendMailFunc 与 smtp.SendMail 相同，单测时替换
+type sendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// emailNotifier 通过配置的 SMTP 服务器发送纯文本邮件
+type emailNotifier struct {
+	setting  common.SMTPSetting
+	to       []string
+	sendMail sendMailFunc
+}
+
+func newEmailNotifier(ls *eventLifecycleService, route common.EventRouteSetting) (notifier, error) {
+	if len(route.EmailTo) == 0 {
+		return nil, fmt.Errorf("route %s: emailTo is required", route.Name)
+	}
+	if ls.smtpSetting.Host == "" || ls.smtpSetting.From == "" {
+		return nil, fmt.Errorf("route %s: smtp host and from are required", route.Name)
+	}
+	return &emailNotifier{
+		setting:  ls.smtpSetting,
+		to:       route.EmailTo,
+		sendMail: smtp.SendMail,
+	}, nil
+}
+
+func (en *emailNotifier) Notify(ctx context.Context, n *interfaces.EventNotification) error {
+	var auth smtp.Auth
+	if en.setting.Username != "" {
+		auth = smtp.PlainAuth("", en.setting.Username, en.setting.Password, en.setting.Host)
+	}
+	addr := fmt.Sprintf("%s:%d", en.setting.Host, en.setting.Port)
+	return en.sendMail(addr, auth, en.setting.From, en.to, en.message(n))
+}
+
+func (en *emailNotifier) message(n *interfaces.EventNotification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", en.setting.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(en.to, ", "))
+	fmt.Fprintf(&b, "Subject: [%s] %s\r\n", strings.ToUpper(n.Status), n.Title)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+
+	fmt.Fprintf(&b, "Status: %s\r\n", n.Status)
+	fmt.Fprintf(&b, "Event model: %s (%s)\r\n", n.EventModelName, n.EventModelID)
+	fmt.Fprintf(&b, "Level: %s\r\n", n.LevelName)
+	fmt.Fprintf(&b, "Event id: %s\r\n", n.EventID)
+	fmt.Fprintf(&b, "First time: %s\r\n", time.UnixMilli(n.FirstTime).Format(time.RFC3339))
+	fmt.Fprintf(&b, "Count: %d\r\n", n.Count)
+	for _, k := range slices.Sorted(maps.Keys(n.Labels)) {
+		fmt.Fprintf(&b, "Label %s: %s\r\n", k, n.Labels[k])
+	}
+	if n.Operator != "" {
+		fmt.Fprintf(&b, "Operator: %s\r\n", n.Operator)
+	}
+	if n.Comment != "" {
+		fmt.Fprintf(&b, "Comment: %s\r\n", n.Comment)
+	}
+	if n.Message != "" {
+		fmt.Fprintf(&b, "\r\n%s\r\n", n.Message)
+	}
+	return []byte(b.String())
+}
+
+// kafkaNotifier 把通知内容写入路由配置的 topic，key 为去重 key，同一事件的通知有序
+type kafkaNotifier struct {
+	topic       string
+	kafkaAccess interfaces.KafkaAccess
+}
+
+func newKafkaNotifier(ls *eventLifecycleService, route common.EventRouteSetting) (notifier, error) {
+	if route.KafkaTopic == "" {
+		return nil, fmt.Errorf("route %s: kafkaTopic is required", route.Name)
+	}
+	return &kafkaNotifier{
+		topic:       route.KafkaTopic,
+		kafkaAccess: ls.kafkaAccess,
+	}, nil
+}
+
+func (kn *kafkaNotifier) Notify(ctx context.Context, n *interfaces.EventNotification) error {
+	value, err := sonic.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	producer, err := kn.kafkaAccess.NewTrxProducer(fmt.Sprintf("%s_%s", kn.topic, common.RandStringRunes(5)))
+	if err != nil {
+		return err
+	}
+	defer producer.Close()
+
+	return kn.kafkaAccess.DoProduce(producer, []*kafka.Message{{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &kn.topic,
+			Partition: kafka.PartitionAny,
+		},
+		Key:   []byte(n.DedupKey),
+		Value: value,
+	}})
+}
@@ -0,0 +1,372 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package event_lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/patrickmn/go-cache"
+	"github.com/redis/go-redis/v9"
+
+	"uniquery/interfaces"
+)
+
+const (
+	// Redis 中事件状态、静默规则和通知路由的 key 前缀
+	redisStateKeyPrefix   = "mdl-uniquery:event-lifecycle:state:"
+	redisSilenceKeyPrefix = "mdl-uniquery:event-lifecycle:silence:"
+	redisRouteKeyPrefix   = "mdl-uniquery:event-lifecycle:route:"
+	// 遍历静默规则和路由时每次 SCAN 的数量
+	redisScanCount = 500
+	// 并发修改同一事件状态导致事务失败时的重试次数
+	redisTxMaxRetries = 10
+	// 内存存储清理过期条目的间隔
+	memoryCleanupInterval = 10 * time.Minute
+	// 内存存储按 key 加锁的分片数
+	memoryLockShards = 256
+)
+
+// stateUpdater 由当前状态计算新状态，状态不存在时 state 为 nil，返回 nil 时不写入。
+// 并发修改同一 key 时会以最新状态重新调用，不能有其他副作用
+type stateUpdater func(state *interfaces.EventLifecycle) (*interfaces.EventLifecycle, error)
+
+// lifecycleStore 事件状态、静默规则和通知路由的存储。
+// 事件状态在 retention 后过期，静默规则在结束时间后过期，过期由存储自身完成；通知路由不过期
+type lifecycleStore interface {
+	GetState(ctx context.Context, key string) (*interfaces.EventLifecycle, bool, error)
+	SetState(ctx context.Context, key string, state *interfaces.EventLifecycle) error
+	// UpdateState 对单个 key 原子地读改写，返回写入后的状态
+	UpdateState(ctx context.Context, key string, update stateUpdater) (*interfaces.EventLifecycle, error)
+	GetSilence(ctx context.Context, id string) (*interfaces.EventSilence, bool, error)
+	SetSilence(ctx context.Context, silence *interfaces.EventSilence, ttl time.Duration) error
+	DeleteSilence(ctx context.Context, id string) error
+	ListSilences(ctx context.Context) ([]*interfaces.EventSilence, error)
+	GetRoute(ctx context.Context, id string) (*interfaces.EventRoute, bool, error)
+	SetRoute(ctx context.Context, route *interfaces.EventRoute) error
+	DeleteRoute(ctx context.Context, id string) error
+	ListRoutes(ctx context.Context) ([]*interfaces.EventRoute, error)
+}
+
+// memoryStore 进程内存储，各实例的状态独立，同一事件在不同实例上分别去重，因此只能用于单实例部署。
+// 读改写按 key 分片加锁，不同事件的更新互不阻塞
+type memoryStore struct {
+	states   *cache.Cache
+	silences *cache.Cache
+	routes   *cache.Cache
+	locks    [memoryLockShards]sync.Mutex
+}
+
+func newMemoryStore(retention time.Duration) *memoryStore {
+	return &memoryStore{
+		states:   cache.New(retention, memoryCleanupInterval),
+		silences: cache.New(cache.NoExpiration, memoryCleanupInterval),
+		routes:   cache.New(cache.NoExpiration, memoryCleanupInterval),
+	}
+}
+
+// 存取时复制一份，避免调用方修改存储中的对象
+func (ms *memoryStore) GetState(ctx context.Context, key string) (*interfaces.EventLifecycle, bool, error) {
+	v, ok := ms.states.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return cloneLifecycle(v.(*interfaces.EventLifecycle)), true, nil
+}
+
+func (ms *memoryStore) SetState(ctx context.Context, key string, state *interfaces.EventLifecycle) error {
+	ms.states.SetDefault(key, cloneLifecycle(state))
+	return nil
+}
+
+func (ms *memoryStore) UpdateState(ctx context.Context, key string, update stateUpdater) (*interfaces.EventLifecycle, error) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	lock := &ms.locks[h.Sum32()%memoryLockShards]
+	lock.Lock()
+	defer lock.Unlock()
+
+	state, _, _ := ms.GetState(ctx, key)
+	next, err := update(state)
+	if err != nil || next == nil {
+		return next, err
+	}
+	ms.states.SetDefault(key, cloneLifecycle(next))
+	return next, nil
+}
+
+func (ms *memoryStore) GetSilence(ctx context.Context, id string) (*interfaces.EventSilence, bool, error) {
+	v, ok := ms.silences.Get(id)
+	if !ok {
+		return nil, false, nil
+	}
+	silence := *v.(*interfaces.EventSilence)
+	return &silence, true, nil
+}
+
+func (ms *memoryStore) SetSilence(ctx context.Context, silence *interfaces.EventSilence, ttl time.Duration) error {
+	s := *silence
+	ms.silences.Set(silence.ID, &s, ttl)
+	return nil
+}
+
+func (ms *memoryStore) DeleteSilence(ctx context.Context, id string) error {
+	ms.silences.Delete(id)
+	return nil
+}
+
+func (ms *memoryStore) ListSilences(ctx context.Context) ([]*interfaces.EventSilence, error) {
+	items := ms.silences.Items()
+	silences := make([]*interfaces.EventSilence, 0, len(items))
+	for _, item := range items {
+		s := *item.Object.(*interfaces.EventSilence)
+		silences = append(silences, &s)
+	}
+	return silences, nil
+}
+
+func (ms *memoryStore) GetRoute(ctx context.Context, id string) (*interfaces.EventRoute, bool, error) {
+	v, ok := ms.routes.Get(id)
+	if !ok {
+		return nil, false, nil
+	}
+	return cloneRoute(v.(*interfaces.EventRoute)), true, nil
+}
+
+func (ms *memoryStore) SetRoute(ctx context.Context, route *interfaces.EventRoute) error {
+	ms.routes.Set(route.ID, cloneRoute(route), cache.NoExpiration)
+	return nil
+}
+
+func (ms *memoryStore) DeleteRoute(ctx context.Context, id string) error {
+	ms.routes.Delete(id)
+	return nil
+}
+
+func (ms *memoryStore) ListRoutes(ctx context.Context) ([]*interfaces.EventRoute, error) {
+	items := ms.routes.Items()
+	routes := make([]*interfaces.EventRoute, 0, len(items))
+	for _, item := range items {
+		routes = append(routes, cloneRoute(item.Object.(*interfaces.EventRoute)))
+	}
+	return routes, nil
+}
+
+// redisStore Redis 存储，多实例共享，条目过期由 key 的 TTL 完成。
+// 事件状态的读改写使用 WATCH/MULTI 乐观锁，只锁单个事件的 key
+type redisStore struct {
+	client    redis.UniversalClient
+	retention time.Duration
+}
+
+func newRedisStore(client redis.UniversalClient, retention time.Duration) *redisStore {
+	return &redisStore{
+		client:    client,
+		retention: retention,
+	}
+}
+
+func (rs *redisStore) GetState(ctx context.Context, key string) (*interfaces.EventLifecycle, bool, error) {
+	state := &interfaces.EventLifecycle{}
+	ok, err := rs.get(ctx, redisStateKeyPrefix+key, state)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return state, true, nil
+}
+
+func (rs *redisStore) SetState(ctx context.Context, key string, state *interfaces.EventLifecycle) error {
+	return rs.set(ctx, redisStateKeyPrefix+key, state, rs.retention)
+}
+
+func (rs *redisStore) UpdateState(ctx context.Context, key string, update stateUpdater) (*interfaces.EventLifecycle, error) {
+	redisKey := redisStateKeyPrefix + key
+
+	var result *interfaces.EventLifecycle
+	txf := func(tx *redis.Tx) error {
+		var state *interfaces.EventLifecycle
+		current := &interfaces.EventLifecycle{}
+		ok, err := getJSON(ctx, tx, redisKey, current)
+		if err != nil {
+			return err
+		}
+		if ok {
+			state = current
+		}
+
+		next, err := update(state)
+		if err != nil {
+			return err
+		}
+		result = next
+		if next == nil {
+			return nil
+		}
+
+		b, err := sonic.Marshal(next)
+		if err != nil {
+			return fmt.Errorf("marshal %s failed: %w", redisKey, err)
+		}
+		// key 在 WATCH 之后被其他实例修改时 EXEC 失败，返回 TxFailedErr
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, redisKey, b, rs.retention)
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < redisTxMaxRetries; i++ {
+		err := rs.client.Watch(ctx, txf, redisKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("update %s failed: too many concurrent updates", redisKey)
+}
+
+func (rs *redisStore) GetSilence(ctx context.Context, id string) (*interfaces.EventSilence, bool, error) {
+	silence := &interfaces.EventSilence{}
+	ok, err := rs.get(ctx, redisSilenceKeyPrefix+id, silence)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return silence, true, nil
+}
+
+func (rs *redisStore) SetSilence(ctx context.Context, silence *interfaces.EventSilence, ttl time.Duration) error {
+	return rs.set(ctx, redisSilenceKeyPrefix+silence.ID, silence, ttl)
+}
+
+func (rs *redisStore) DeleteSilence(ctx context.Context, id string) error {
+	return rs.client.Del(ctx, redisSilenceKeyPrefix+id).Err()
+}
+
+func (rs *redisStore) ListSilences(ctx context.Context) ([]*interfaces.EventSilence, error) {
+	silences := []*interfaces.EventSilence{}
+	err := rs.scan(ctx, redisSilenceKeyPrefix, func(key string) error {
+		silence := &interfaces.EventSilence{}
+		ok, err := rs.get(ctx, key, silence)
+		// 遍历期间过期的 key 直接跳过
+		if ok {
+			silences = append(silences, silence)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return silences, nil
+}
+
+func (rs *redisStore) GetRoute(ctx context.Context, id string) (*interfaces.EventRoute, bool, error) {
+	route := &interfaces.EventRoute{}
+	ok, err := rs.get(ctx, redisRouteKeyPrefix+id, route)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return route, true, nil
+}
+
+func (rs *redisStore) SetRoute(ctx context.Context, route *interfaces.EventRoute) error {
+	return rs.set(ctx, redisRouteKeyPrefix+route.ID, route, 0)
+}
+
+func (rs *redisStore) DeleteRoute(ctx context.Context, id string) error {
+	return rs.client.Del(ctx, redisRouteKeyPrefix+id).Err()
+}
+
+func (rs *redisStore) ListRoutes(ctx context.Context) ([]*interfaces.EventRoute, error) {
+	routes := []*interfaces.EventRoute{}
+	err := rs.scan(ctx, redisRouteKeyPrefix, func(key string) error {
+		route := &interfaces.EventRoute{}
+		ok, err := rs.get(ctx, key, route)
+		if ok {
+			routes = append(routes, route)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// scan 遍历前缀下的 key，遍历期间删除或过期的 key 由 fn 自行跳过
+func (rs *redisStore) scan(ctx context.Context, prefix string, fn func(key string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := rs.client.Scan(ctx, cursor, prefix+"*", redisScanCount).Result()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err = fn(key); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func (rs *redisStore) get(ctx context.Context, key string, v any) (bool, error) {
+	return getJSON(ctx, rs.client, key, v)
+}
+
+// getJSON 读取 key 并反序列化，key 不存在时返回 false
+func getJSON(ctx context.Context, getter interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+}, key string, v any) (bool, error) {
+	b, err := getter.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err = sonic.Unmarshal(b, v); err != nil {
+		return false, fmt.Errorf("unmarshal %s failed: %w", key, err)
+	}
+	return true, nil
+}
+
+func (rs *redisStore) set(ctx context.Context, key string, v any, ttl time.Duration) error {
+	b, err := sonic.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s failed: %w", key, err)
+	}
+	return rs.client.Set(ctx, key, b, ttl).Err()
+}
+
+func cloneLifecycle(state *interfaces.EventLifecycle) *interfaces.EventLifecycle {
+	s := *state
+	s.Labels = maps.Clone(state.Labels)
+	s.SilencedBy = append([]string(nil), state.SilencedBy...)
+	s.Transitions = append([]interfaces.EventTransition(nil), state.Transitions...)
+	return &s
+}
+
+func cloneRoute(route *interfaces.EventRoute) *interfaces.EventRoute {
+	r := *route
+	r.Labels = maps.Clone(route.Labels)
+	r.Headers = maps.Clone(route.Headers)
+	r.EventModelIDs = append([]string(nil), route.EventModelIDs...)
+	r.Levels = append([]int(nil), route.Levels...)
+	r.EmailTo = append([]string(nil), route.EmailTo...)
+	return &r
+}
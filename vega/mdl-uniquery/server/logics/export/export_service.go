@@ -0,0 +1,826 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/xid"
+
+	"uniquery/common"
+	"uniquery/common/convert"
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+	dtype "uniquery/interfaces/data_type"
+	"uniquery/logics/data_view"
+)
+
+const (
+	defaultFileDir        = "/tmp/uniquery-export"
+	defaultMaxRunningJobs = 4
+	defaultRetention      = 24 * time.Hour
+
+	// 清理过期文件的间隔
+	cleanupInterval = 10 * time.Minute
+	// 运行中的任务向存储同步进度的间隔，同步时发现任务已被删除则取消任务
+	jobSyncInterval = 5 * time.Second
+	// 未结束的任务超过该时长未同步，视为运行任务的实例已退出
+	jobStaleTimeout = 12 * jobSyncInterval
+	// 导出中的临时文件后缀，完成后重命名
+	partFileSuffix = ".part"
+	// 指标导出的中间文件后缀
+	spoolFileSuffix = ".spool"
+	// 指标导出时每次查询的时间点数
+	metricChunkSteps = 1000
+)
+
+var (
+	esOnce    sync.Once
+	esService interfaces.ExportService
+
+	errMaxRowsExceeded = errors.New("max rows exceeded")
+)
+
+// exportService 导出任务的状态保存在 store 中，导出文件落在配置的目录里。
+// 多实例部署时 store 使用 redis、目录挂载共享存储，任务可以在任意实例上查询、下载和删除；
+// 任务只在创建它的实例上运行，实例退出后未结束的任务在同步超时后标记为失败，残留的文件由定时清理删除。
+type exportService struct {
+	appSetting *common.AppSetting
+	dvService  interfaces.DataViewService
+	mmService  interfaces.MetricModelService
+	store      jobStore
+
+	fileDir   string
+	retention time.Duration
+	maxRows   int64
+	slots     chan struct{}
+
+	// 本实例运行中的任务
+	mu      sync.Mutex
+	running map[string]*exportJob
+}
+
+// exportJob 运行中的导出任务，job 的读写需持有 mu
+type exportJob struct {
+	mu     sync.RWMutex
+	job    interfaces.ExportJob
+	cancel context.CancelFunc
+}
+
+func NewExportService(appSetting *common.AppSetting, mmService interfaces.MetricModelService) interfaces.ExportService {
+	esOnce.Do(func() {
+		var store jobStore
+		switch appSetting.ExportSetting.Store {
+		case "", interfaces.ExportJobStore_Memory:
+			store = newMemoryStore()
+		case interfaces.ExportJobStore_Redis:
+			client := redis.NewClient(&redis.Options{
+				Addr:        fmt.Sprintf("%s:%d", appSetting.RedisSetting.Host, appSetting.RedisSetting.Port),
+				Username:    appSetting.RedisSetting.Username,
+				Password:    appSetting.RedisSetting.Password,
+				DialTimeout: 5 * time.Second,
+			})
+			store = newRedisStore(client)
+		default:
+			logger.Fatalf("unsupported export job store: %s", appSetting.ExportSetting.Store)
+		}
+		logger.Infof("Export job store: %s", appSetting.ExportSetting.Store)
+
+		es := newExportService(appSetting, store, data_view.NewDataViewService(appSetting), mmService)
+		go es.cleanupLoop()
+		esService = es
+	})
+	return esService
+}
+
+func newExportService(appSetting *common.AppSetting, store jobStore, dvService interfaces.DataViewService,
+	mmService interfaces.MetricModelService) *exportService {
+
+	setting := appSetting.ExportSetting
+	fileDir := setting.FileDir
+	if fileDir == "" {
+		fileDir = defaultFileDir
+	}
+	maxRunningJobs := setting.MaxRunningJobs
+	if maxRunningJobs <= 0 {
+		maxRunningJobs = defaultMaxRunningJobs
+	}
+	retention := setting.Retention
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+
+	return &exportService{
+		appSetting: appSetting,
+		dvService:  dvService,
+		mmService:  mmService,
+		store:      store,
+		fileDir:    fileDir,
+		retention:  retention,
+		maxRows:    setting.MaxRows,
+		slots:      make(chan struct{}, maxRunningJobs),
+		running:    make(map[string]*exportJob),
+	}
+}
+
+// 创建导出任务，任务在本实例后台排队执行
+func (es *exportService) CreateExportJob(ctx context.Context, req *interfaces.ExportJobRequest) (*interfaces.ExportJob, error) {
+	switch req.SourceType {
+	case interfaces.ExportSourceType_DataView:
+		if req.ViewQuery == nil {
+			req.ViewQuery = &interfaces.DataViewQueryV2{}
+		}
+	case interfaces.ExportSourceType_MetricModel:
+		if req.MetricQuery == nil {
+			return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_Export_InvalidParameter_SourceQuery).
+				WithErrorDetails("The metric_query is required when export metric model data")
+		}
+	}
+
+	jobID := xid.New().String()
+	fileName, err := exportFileName(req.FileName, req.SourceID, jobID, req.Format)
+	if err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_Export_InvalidParameter_FileName).
+			WithErrorDetails(err.Error())
+	}
+
+	if err = os.MkdirAll(es.fileDir, 0o750); err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_Export_InternalError_CreateFileFailed).
+			WithErrorDetails(err.Error())
+	}
+
+	now := time.Now().UnixMilli()
+	job := &exportJob{
+		job: interfaces.ExportJob{
+			ID:         jobID,
+			SourceType: req.SourceType,
+			SourceID:   req.SourceID,
+			Format:     req.Format,
+			FileName:   fileName,
+			Status:     interfaces.ExportJobStatus_Pending,
+			CreateTime: now,
+			UpdateTime: now,
+			Creator:    accountInfo(ctx),
+			FilePath:   filepath.Join(es.fileDir, jobID+"."+req.Format),
+		},
+	}
+
+	if err = es.store.SetJob(ctx, job.snapshot(), es.retention); err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_Export_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+
+	// 任务脱离请求的生命周期运行，保留 ctx 中的账户和语言信息
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	job.cancel = cancel
+
+	es.mu.Lock()
+	es.running[jobID] = job
+	es.mu.Unlock()
+
+	go es.runJob(jobCtx, job, req)
+
+	return job.snapshot(), nil
+}
+
+// 查询导出任务
+func (es *exportService) GetExportJob(ctx context.Context, jobID string) (*interfaces.ExportJob, error) {
+	return es.getJob(ctx, jobID)
+}
+
+// 打开已完成任务的导出文件
+func (es *exportService) OpenExportFile(ctx context.Context, jobID string) (*interfaces.ExportJob, *os.File, error) {
+	job, err := es.getJob(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job.Status != interfaces.ExportJobStatus_Completed {
+		return nil, nil, rest.NewHTTPError(ctx, http.StatusConflict, uerrors.Uniquery_Export_JobNotCompleted).
+			WithErrorDetails(fmt.Sprintf("The export job '%s' is %s", jobID, job.Status))
+	}
+
+	file, err := os.Open(job.FilePath)
+	if err != nil {
+		return nil, nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_Export_InternalError_OpenFileFailed).
+			WithErrorDetails(err.Error())
+	}
+	return job, file, nil
+}
+
+// 删除导出任务。本实例运行的任务直接取消，其他实例运行的任务在下次同步时发现已删除后取消，文件由任务退出时清理
+func (es *exportService) DeleteExportJob(ctx context.Context, jobID string) error {
+	job, err := es.getJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+
+	if err = es.store.DeleteJob(ctx, jobID); err != nil {
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_Export_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+
+	es.mu.Lock()
+	local, ok := es.running[jobID]
+	es.mu.Unlock()
+	if ok {
+		local.cancel()
+	}
+
+	if job.Status == interfaces.ExportJobStatus_Completed {
+		if err := os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+			return rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_Export_InternalError_DeleteFileFailed).
+				WithErrorDetails(err.Error())
+		}
+	}
+	return nil
+}
+
+// getJob 只返回当前账户创建的任务。本实例运行中的任务返回内存中的最新进度
+func (es *exportService) getJob(ctx context.Context, jobID string) (*interfaces.ExportJob, error) {
+	job, ok, err := es.store.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_Export_InternalError_StoreFailed).
+			WithErrorDetails(err.Error())
+	}
+	if !ok || job.Creator.ID != accountInfo(ctx).ID {
+		return nil, rest.NewHTTPError(ctx, http.StatusNotFound, uerrors.Uniquery_Export_JobNotFound).
+			WithErrorDetails(fmt.Sprintf("The export job '%s' not found", jobID))
+	}
+
+	es.mu.Lock()
+	local, running := es.running[jobID]
+	es.mu.Unlock()
+	if running {
+		return local.snapshot(), nil
+	}
+
+	if isJobFinished(job.Status) || time.Since(time.UnixMilli(job.UpdateTime)) <= jobStaleTimeout {
+		return job, nil
+	}
+
+	// 运行任务的实例已退出，任务不会再结束，标记为失败
+	now := time.Now()
+	job.Status = interfaces.ExportJobStatus_Failed
+	job.Error = "the service instance running the export job exited"
+	job.UpdateTime = now.UnixMilli()
+	job.FinishTime = job.UpdateTime
+	job.ExpireTime = now.Add(es.retention).UnixMilli()
+	if _, err := es.store.UpdateJob(ctx, job, es.retention); err != nil {
+		logger.Warnf("Mark stale export job '%s' failed, %v", jobID, err)
+	}
+	return job, nil
+}
+
+// runJob 等待空闲槽位后执行导出，先写临时文件，成功后重命名
+func (es *exportService) runJob(ctx context.Context, job *exportJob, req *interfaces.ExportJobRequest) {
+	defer func() {
+		es.mu.Lock()
+		delete(es.running, job.snapshot().ID)
+		es.mu.Unlock()
+	}()
+	defer job.cancel()
+
+	// 进度同步在任务结束前停止，避免覆盖结束状态
+	syncCtx, stopSync := context.WithCancel(ctx)
+	syncDone := make(chan struct{})
+	go func() {
+		defer close(syncDone)
+		es.syncLoop(syncCtx, job)
+	}()
+	finish := func(status, errMsg string, fileSize int64) {
+		stopSync()
+		<-syncDone
+		es.finishJob(job, status, errMsg, fileSize)
+	}
+
+	select {
+	case es.slots <- struct{}{}:
+		defer func() { <-es.slots }()
+	case <-ctx.Done():
+		finish(interfaces.ExportJobStatus_Canceled, "", 0)
+		return
+	}
+
+	job.update(func(j *interfaces.ExportJob) {
+		j.Status = interfaces.ExportJobStatus_Running
+	})
+	es.syncJob(ctx, job)
+
+	filePath := job.snapshot().FilePath
+	partPath := filePath + partFileSuffix
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("export panic: %v", r)
+			}
+		}()
+
+		file, err := os.Create(partPath)
+		if err != nil {
+			return err
+		}
+
+		switch req.SourceType {
+		case interfaces.ExportSourceType_DataView:
+			err = es.exportDataView(ctx, job, req, file)
+		case interfaces.ExportSourceType_MetricModel:
+			err = es.exportMetricModel(ctx, job, req, file)
+		default:
+			err = fmt.Errorf("unsupported export source type '%s'", req.SourceType)
+		}
+
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		return os.Rename(partPath, filePath)
+	}()
+
+	if err != nil {
+		_ = os.Remove(partPath)
+
+		if ctx.Err() != nil {
+			finish(interfaces.ExportJobStatus_Canceled, "", 0)
+			return
+		}
+
+		logger.Errorf("Export job '%s' failed, %s", job.snapshot().ID, err.Error())
+		finish(interfaces.ExportJobStatus_Failed, exportErrorMessage(err), 0)
+		return
+	}
+
+	var size int64
+	if info, err := os.Stat(filePath); err == nil {
+		size = info.Size()
+	}
+	finish(interfaces.ExportJobStatus_Completed, "", size)
+}
+
+// syncLoop 定时把运行中任务的进度写入存储，更新时间同时作为实例存活的心跳
+func (es *exportService) syncLoop(ctx context.Context, job *exportJob) {
+	ticker := time.NewTicker(jobSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job.update(func(j *interfaces.ExportJob) {})
+			es.syncJob(ctx, job)
+		}
+	}
+}
+
+// syncJob 更新存储中的任务，任务已被删除时取消任务
+func (es *exportService) syncJob(ctx context.Context, job *exportJob) {
+	snapshot := job.snapshot()
+	ok, err := es.store.UpdateJob(ctx, snapshot, es.retention)
+	if err != nil {
+		logger.Warnf("Sync export job '%s' failed, %v", snapshot.ID, err)
+		return
+	}
+	if !ok {
+		job.cancel()
+	}
+}
+
+// finishJob 保存任务的结束状态，任务在结束后保留 retention。任务已被删除时清理导出文件
+func (es *exportService) finishJob(job *exportJob, status, errMsg string, fileSize int64) {
+	job.finish(status, errMsg, fileSize, es.retention)
+	snapshot := job.snapshot()
+
+	ok, err := es.store.UpdateJob(context.Background(), snapshot, es.retention)
+	if err != nil {
+		logger.Errorf("Save export job '%s' failed, %v", snapshot.ID, err)
+		return
+	}
+	if !ok && status == interfaces.ExportJobStatus_Completed {
+		if err := os.Remove(snapshot.FilePath); err != nil && !os.IsNotExist(err) {
+			logger.Warnf("Remove file of deleted export job '%s' failed, %v", snapshot.ID, err)
+		}
+	}
+}
+
+// exportDataView 以 search_after 方式翻页拉取视图的全部数据，
+// 行列规则、过滤条件等与视图数据查询接口一致，索引库视图使用 pit 保证翻页期间数据一致
+func (es *exportService) exportDataView(ctx context.Context, job *exportJob,
+	req *interfaces.ExportJobRequest, file *os.File) error {
+
+	query := *req.ViewQuery
+	query.Offset = 0
+	query.Limit = interfaces.EXPORT_PAGE_SIZE
+	query.Format = interfaces.Format_Flat
+	query.UseSearchAfter = true
+	query.NeedTotal = true
+	query.IncludeView = true
+	query.SearchAfterParams = interfaces.SearchAfterParams{PitKeepAlive: interfaces.EXPORT_PIT_KEEP_ALIVE}
+
+	defer func() {
+		if query.PitID == "" {
+			return
+		}
+		_, err := es.dvService.DeleteDataViewPits(context.WithoutCancel(ctx), &interfaces.DeletePits{PitIDs: []string{query.PitID}})
+		if err != nil {
+			logger.Warnf("Export job '%s' delete pit failed, %v", job.snapshot().ID, err)
+		}
+	}()
+
+	var writer rowWriter
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		res, err := es.dvService.GetSingleViewData(ctx, req.SourceID, &query)
+		if err != nil {
+			return err
+		}
+
+		if writer == nil {
+			writer, err = newRowWriter(req.Format, file, viewExportColumns(res.View, query.OutputFields))
+			if err != nil {
+				return err
+			}
+			if res.TotalCount != nil {
+				job.setTotal(*res.TotalCount)
+			}
+		}
+
+		if err = es.writeRows(job, writer, res.Entries); err != nil {
+			return err
+		}
+
+		if res.PitID != "" {
+			query.PitID = res.PitID
+		}
+		if len(res.Entries) == 0 || len(res.SearchAfter) == 0 {
+			break
+		}
+		query.SearchAfter = res.SearchAfter
+		query.NeedTotal = false
+		query.IncludeView = false
+	}
+
+	return writer.Close()
+}
+
+// exportMetricModel 把指标数据展开为行，每个数据点一行，列为维度标签、时间和值。
+// 范围查询按时间窗口分批执行，数据点先逐行写入中间文件，收集完全部标签后再按列写出导出文件，内存中只保留一个窗口的数据
+func (es *exportService) exportMetricModel(ctx context.Context, job *exportJob,
+	req *interfaces.ExportJobRequest, file *os.File) error {
+
+	query := *req.MetricQuery
+	query.MetricModelID = req.SourceID
+
+	spoolPath := job.snapshot().FilePath + spoolFileSuffix
+	spool, err := os.Create(spoolPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = spool.Close()
+		_ = os.Remove(spoolPath)
+	}()
+
+	labelSet := make(map[string]struct{})
+	var total int64
+	spoolWriter := bufio.NewWriter(spool)
+	encoder := json.NewEncoder(spoolWriter)
+	for _, chunk := range metricQueryChunks(&query) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		res, _, _, err := es.mmService.Exec(ctx, chunk)
+		if err != nil {
+			return err
+		}
+
+		// 原子指标的标签来自数据视图的字段，按访问者在视图上的行列规则脱敏。模型信息在执行查询时填充
+		maskViewID := ""
+		if chunk.MetricType == interfaces.ATOMIC_METRIC && chunk.DataSource != nil &&
+			chunk.DataSource.Type != interfaces.QueryType_SQL {
+			maskViewID = chunk.DataSource.ID
+		}
+
+		for _, series := range res.Datas {
+			labels := make(map[string]any, len(series.Labels))
+			for name, value := range series.Labels {
+				labels[name] = value
+				labelSet[name] = struct{}{}
+			}
+			if maskViewID != "" {
+				if err = es.dvService.MaskViewData(ctx, maskViewID, []map[string]any{labels}); err != nil {
+					return err
+				}
+			}
+
+			for i := range series.Times {
+				if es.maxRows > 0 && total >= es.maxRows {
+					return fmt.Errorf("%w: the export job can export at most %d rows", errMaxRowsExceeded, es.maxRows)
+				}
+
+				point := metricPoint{Labels: labels, Time: series.Times[i]}
+				if i < len(series.Values) {
+					point.Value = series.Values[i]
+				}
+				if err = encoder.Encode(point); err != nil {
+					return err
+				}
+				total++
+			}
+		}
+	}
+	if err = spoolWriter.Flush(); err != nil {
+		return err
+	}
+	job.setTotal(total)
+
+	columns := make([]*exportColumn, 0, len(labelSet)+2)
+	for name := range labelSet {
+		columns = append(columns, &exportColumn{Name: name, Type: dtype.DataType_String})
+	}
+	sort.Slice(columns, func(i, j int) bool { return columns[i].Name < columns[j].Name })
+	columns = append(columns,
+		&exportColumn{Name: interfaces.ExportMetricColumn_Time, Type: dtype.DataType_Integer},
+		&exportColumn{Name: interfaces.ExportMetricColumn_Value, Type: dtype.DataType_Float})
+
+	writer, err := newRowWriter(req.Format, file, columns)
+	if err != nil {
+		return err
+	}
+
+	if _, err = spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bufio.NewReader(spool))
+	decoder.UseNumber()
+
+	rows := make([]map[string]any, 0, interfaces.EXPORT_PAGE_SIZE)
+	for {
+		var point metricPoint
+		err := decoder.Decode(&point)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if err == nil {
+			row := make(map[string]any, len(point.Labels)+2)
+			for name, value := range point.Labels {
+				row[name] = value
+			}
+			row[interfaces.ExportMetricColumn_Time] = point.Time
+			row[interfaces.ExportMetricColumn_Value] = point.Value
+			rows = append(rows, row)
+		}
+
+		if len(rows) > 0 && (err == io.EOF || len(rows) >= interfaces.EXPORT_PAGE_SIZE) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := es.writeRows(job, writer, rows); err != nil {
+				return err
+			}
+			rows = rows[:0]
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return writer.Close()
+}
+
+// metricPoint 指标导出中间文件的一行
+type metricPoint struct {
+	Labels map[string]any `json:"labels"`
+	Time   any            `json:"time"`
+	Value  any            `json:"value"`
+}
+
+// metricQueryChunks 把固定步长的范围查询按 metricChunkSteps 个步长拆分为多个时间窗口。
+// 窗口边界按步长对齐（与指标查询修正时间的方式一致，考虑时区偏移），相邻窗口的数据点不重复。
+// 即时查询、日历步长、同环比等依赖完整时间范围的查询不拆分
+func metricQueryChunks(query *interfaces.MetricModelQuery) []*interfaces.MetricModelQuery {
+	if query.IsInstantQuery || query.Start == nil || query.End == nil || query.StepStr == nil ||
+		query.RequestMetrics != nil {
+		return []*interfaces.MetricModelQuery{query}
+	}
+	if _, ok := interfaces.CALENDAR_INTERVALS[*query.StepStr]; ok {
+		return []*interfaces.MetricModelQuery{query}
+	}
+	stepT, err := convert.ParseDuration(*query.StepStr)
+	if err != nil || stepT <= 0 {
+		return []*interfaces.MetricModelQuery{query}
+	}
+
+	step := stepT.Milliseconds()
+	start, end := *query.Start, *query.End
+	loc := common.APP_LOCATION
+	if loc == nil {
+		loc = time.Local
+	}
+	_, offset := time.UnixMilli(start).In(loc).Zone()
+	offsetMs := int64(offset) * 1000
+	alignedStart := int64(math.Floor(float64(start+offsetMs)/float64(step)))*step - offsetMs
+
+	chunkSize := step * metricChunkSteps
+	if end-alignedStart < chunkSize {
+		return []*interfaces.MetricModelQuery{query}
+	}
+
+	chunks := make([]*interfaces.MetricModelQuery, 0, (end-alignedStart)/chunkSize+1)
+	for chunkStart := alignedStart; chunkStart <= end; chunkStart += chunkSize {
+		chunkQuery := *query
+		cs := max(chunkStart, start)
+		ce := min(chunkStart+chunkSize-step, end)
+		chunkQuery.Start = &cs
+		chunkQuery.End = &ce
+		chunks = append(chunks, &chunkQuery)
+	}
+	return chunks
+}
+
+func (es *exportService) writeRows(job *exportJob, writer rowWriter, rows []map[string]any) error {
+	exported := job.snapshot().ExportedRows
+	for _, row := range rows {
+		if es.maxRows > 0 && exported >= es.maxRows {
+			return fmt.Errorf("%w: the export job can export at most %d rows", errMaxRowsExceeded, es.maxRows)
+		}
+		if err := writer.WriteRow(row); err != nil {
+			return err
+		}
+		exported++
+	}
+
+	job.update(func(j *interfaces.ExportJob) {
+		j.ExportedRows = exported
+		if j.TotalRows != nil && *j.TotalRows > 0 {
+			j.Progress = min(float64(exported)/float64(*j.TotalRows), 1)
+		}
+	})
+	return nil
+}
+
+// cleanupLoop 定时删除目录中残留的过期文件，任务本身由存储过期
+func (es *exportService) cleanupLoop() {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		es.cleanup(time.Now())
+		<-ticker.C
+	}
+}
+
+func (es *exportService) cleanup(now time.Time) {
+	entries, err := os.ReadDir(es.fileDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warnf("Read export file dir '%s' failed, %v", es.fileDir, err)
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || now.Sub(info.ModTime()) < es.retention {
+			continue
+		}
+
+		// 运行中的任务持续写入临时文件，修改时间不会过期
+		if err := os.Remove(filepath.Join(es.fileDir, entry.Name())); err != nil {
+			logger.Warnf("Remove expired export file '%s' failed, %v", entry.Name(), err)
+		}
+	}
+}
+
+func (job *exportJob) snapshot() *interfaces.ExportJob {
+	job.mu.RLock()
+	defer job.mu.RUnlock()
+
+	snapshot := job.job
+	if job.job.TotalRows != nil {
+		total := *job.job.TotalRows
+		snapshot.TotalRows = &total
+	}
+	return &snapshot
+}
+
+func (job *exportJob) update(fn func(j *interfaces.ExportJob)) {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+
+	fn(&job.job)
+	job.job.UpdateTime = time.Now().UnixMilli()
+}
+
+func (job *exportJob) setTotal(total int64) {
+	job.update(func(j *interfaces.ExportJob) {
+		j.TotalRows = &total
+	})
+}
+
+func (job *exportJob) finish(status, errMsg string, fileSize int64, retention time.Duration) {
+	job.update(func(j *interfaces.ExportJob) {
+		j.Status = status
+		j.Error = errMsg
+		j.FileSize = fileSize
+		j.FinishTime = j.UpdateTime
+		j.ExpireTime = time.Now().Add(retention).UnixMilli()
+		if status == interfaces.ExportJobStatus_Completed {
+			j.Progress = 1
+		}
+	})
+}
+
+func isJobFinished(status string) bool {
+	switch status {
+	case interfaces.ExportJobStatus_Completed, interfaces.ExportJobStatus_Failed, interfaces.ExportJobStatus_Canceled:
+		return true
+	}
+	return false
+}
+
+// viewExportColumns 导出列为请求的 output_fields，未指定时为视图的全部字段
+func viewExportColumns(view *interfaces.DataView, outputFields []string) []*exportColumn {
+	if view == nil {
+		columns := make([]*exportColumn, 0, len(outputFields))
+		for _, name := range outputFields {
+			columns = append(columns, &exportColumn{Name: name})
+		}
+		return columns
+	}
+
+	if len(outputFields) == 0 {
+		columns := make([]*exportColumn, 0, len(view.Fields))
+		for _, field := range view.Fields {
+			columns = append(columns, &exportColumn{Name: field.Name, Type: field.Type})
+		}
+		return columns
+	}
+
+	fieldTypes := make(map[string]string, len(view.Fields))
+	for _, field := range view.Fields {
+		fieldTypes[field.Name] = field.Type
+	}
+	columns := make([]*exportColumn, 0, len(outputFields))
+	for _, name := range outputFields {
+		columns = append(columns, &exportColumn{Name: name, Type: fieldTypes[name]})
+	}
+	return columns
+}
+
+// exportFileName 下载时的文件名，未指定时为 <source_id>_<job_id>.<format>
+func exportFileName(fileName, sourceID, jobID, format string) (string, error) {
+	if fileName == "" {
+		return fmt.Sprintf("%s_%s.%s", sourceID, jobID, format), nil
+	}
+
+	if strings.ContainsAny(fileName, `/\`) || fileName == "." || fileName == ".." {
+		return "", fmt.Errorf("the file name '%s' cannot contain path separators", fileName)
+	}
+	if !strings.HasSuffix(strings.ToLower(fileName), "."+format) {
+		fileName += "." + format
+	}
+	return fileName, nil
+}
+
+// exportErrorMessage 查询返回的 httpErr 取其错误详情
+func exportErrorMessage(err error) string {
+	var httpErr *rest.HTTPError
+	if errors.As(err, &httpErr) {
+		return fmt.Sprintf("%s: %v", httpErr.BaseError.Description, httpErr.BaseError.ErrorDetails)
+	}
+	return err.Error()
+}
+
+func accountInfo(ctx context.Context) interfaces.AccountInfo {
+	if info, ok := ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo); ok {
+		return info
+	}
+	return interfaces.AccountInfo{}
+}
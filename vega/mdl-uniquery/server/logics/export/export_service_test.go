@@ -0,0 +1,475 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package export
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"github.com/redis/go-redis/v9"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/common"
+	cond "uniquery/common/condition"
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+	dtype "uniquery/interfaces/data_type"
+	umock "uniquery/interfaces/mock"
+)
+
+func testExportCtx(accountID string) context.Context {
+	return context.WithValue(context.Background(), interfaces.ACCOUNT_INFO_KEY,
+		interfaces.AccountInfo{ID: accountID, Type: "user"})
+}
+
+// waitJob 等待任务结束
+func waitJob(ctx context.Context, es *exportService, jobID string) *interfaces.ExportJob {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := es.GetExportJob(ctx, jobID)
+		So(err, ShouldBeNil)
+		if job.FinishTime > 0 || time.Now().After(deadline) {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func Test_ExportService_DataView(t *testing.T) {
+	Convey("Test export data view", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		dvService := umock.NewMockDataViewService(mockCtrl)
+		appSetting := &common.AppSetting{
+			ExportSetting: common.ExportSetting{FileDir: t.TempDir()},
+		}
+		es := newExportService(appSetting, newMemoryStore(), dvService, nil)
+		ctx := testExportCtx("u1")
+
+		total := int64(3)
+		view := &interfaces.DataView{
+			ViewID: "v1",
+			Fields: []*cond.ViewField{
+				{Name: "host", Type: dtype.DataType_String},
+				{Name: "cpu", Type: dtype.DataType_Float},
+			},
+		}
+		req := &interfaces.ExportJobRequest{
+			SourceType: interfaces.ExportSourceType_DataView,
+			SourceID:   "v1",
+			Format:     interfaces.ExportFormat_CSV,
+			FileName:   "hosts",
+			ViewQuery:  &interfaces.DataViewQueryV2{},
+		}
+
+		Convey("Export by pages succeed", func() {
+			gomock.InOrder(
+				dvService.EXPECT().GetSingleViewData(gomock.Any(), "v1", gomock.Any()).Return(&interfaces.ViewUniResponseV2{
+					PitID:       "pit1",
+					View:        view,
+					TotalCount:  &total,
+					SearchAfter: []any{2},
+					Entries:     []map[string]any{{"host": "a", "cpu": 0.1}, {"host": "b", "cpu": 0.2}},
+				}, nil),
+				dvService.EXPECT().GetSingleViewData(gomock.Any(), "v1", gomock.Any()).Return(&interfaces.ViewUniResponseV2{
+					PitID:       "pit1",
+					SearchAfter: []any{3},
+					Entries:     []map[string]any{{"host": "c", "cpu": 0.3}},
+				}, nil),
+				dvService.EXPECT().GetSingleViewData(gomock.Any(), "v1", gomock.Any()).Return(&interfaces.ViewUniResponseV2{
+					PitID:   "pit1",
+					Entries: []map[string]any{},
+				}, nil),
+			)
+			dvService.EXPECT().DeleteDataViewPits(gomock.Any(), &interfaces.DeletePits{PitIDs: []string{"pit1"}}).
+				Return(&interfaces.DeletePitsResp{}, nil)
+
+			created, err := es.CreateExportJob(ctx, req)
+			So(err, ShouldBeNil)
+			So(created.Status, ShouldEqual, interfaces.ExportJobStatus_Pending)
+			So(created.FileName, ShouldEqual, "hosts.csv")
+
+			job := waitJob(ctx, es, created.ID)
+			So(job.Status, ShouldEqual, interfaces.ExportJobStatus_Completed)
+			So(job.ExportedRows, ShouldEqual, 3)
+			So(*job.TotalRows, ShouldEqual, 3)
+			So(job.Progress, ShouldEqual, 1)
+
+			_, file, err := es.OpenExportFile(ctx, created.ID)
+			So(err, ShouldBeNil)
+			content, _ := io.ReadAll(file)
+			file.Close()
+			So(string(content), ShouldEqual, "\xEF\xBB\xBFhost,cpu\na,0.1\nb,0.2\nc,0.3\n")
+			So(job.FileSize, ShouldEqual, len(content))
+
+			Convey("Delete completed job removes file", func() {
+				err := es.DeleteExportJob(ctx, created.ID)
+				So(err, ShouldBeNil)
+
+				_, statErr := os.Stat(job.FilePath)
+				So(os.IsNotExist(statErr), ShouldBeTrue)
+
+				_, err = es.GetExportJob(ctx, created.ID)
+				So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+			})
+		})
+
+		Convey("Query failed", func() {
+			expectedErr := rest.NewHTTPError(ctx, http.StatusForbidden, uerrors.Uniquery_InternalError_CheckPermissionFailed).
+				WithErrorDetails("no permission")
+			dvService.EXPECT().GetSingleViewData(gomock.Any(), "v1", gomock.Any()).Return(nil, expectedErr)
+
+			created, err := es.CreateExportJob(ctx, req)
+			So(err, ShouldBeNil)
+
+			job := waitJob(ctx, es, created.ID)
+			So(job.Status, ShouldEqual, interfaces.ExportJobStatus_Failed)
+			So(job.Error, ShouldContainSubstring, "no permission")
+
+			_, err = os.Stat(job.FilePath + partFileSuffix)
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("Exceed max rows", func() {
+			es.maxRows = 1
+			dvService.EXPECT().GetSingleViewData(gomock.Any(), "v1", gomock.Any()).Return(&interfaces.ViewUniResponseV2{
+				View:    view,
+				Entries: []map[string]any{{"host": "a"}, {"host": "b"}},
+			}, nil)
+
+			created, err := es.CreateExportJob(ctx, req)
+			So(err, ShouldBeNil)
+
+			job := waitJob(ctx, es, created.ID)
+			So(job.Status, ShouldEqual, interfaces.ExportJobStatus_Failed)
+			So(job.Error, ShouldContainSubstring, "at most 1 rows")
+		})
+
+		Convey("Invalid file name", func() {
+			req.FileName = "../hosts"
+			_, err := es.CreateExportJob(ctx, req)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_Export_InvalidParameter_FileName)
+		})
+	})
+}
+
+func Test_ExportService_MetricModel(t *testing.T) {
+	Convey("Test export metric model", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mmService := umock.NewMockMetricModelService(mockCtrl)
+		appSetting := &common.AppSetting{
+			ExportSetting: common.ExportSetting{FileDir: t.TempDir()},
+		}
+		es := newExportService(appSetting, newMemoryStore(), nil, mmService)
+		ctx := testExportCtx("u1")
+
+		Convey("Flatten series into rows", func() {
+			mmService.EXPECT().Exec(gomock.Any(), gomock.Any()).Return(interfaces.MetricModelUniResponse{
+				Datas: []interfaces.MetricModelData{
+					{Labels: map[string]string{"host": "a"}, Times: []any{int64(1000), int64(2000)}, Values: []any{1.5, nil}},
+					{Labels: map[string]string{"host": "b", "zone": "z1"}, Times: []any{int64(1000)}, Values: []any{2.0}},
+				},
+			}, 0, 0, nil)
+
+			created, err := es.CreateExportJob(ctx, &interfaces.ExportJobRequest{
+				SourceType:  interfaces.ExportSourceType_MetricModel,
+				SourceID:    "m1",
+				Format:      interfaces.ExportFormat_NDJSON,
+				MetricQuery: &interfaces.MetricModelQuery{},
+			})
+			So(err, ShouldBeNil)
+			So(created.FileName, ShouldEqual, "m1_"+created.ID+".ndjson")
+
+			job := waitJob(ctx, es, created.ID)
+			So(job.Status, ShouldEqual, interfaces.ExportJobStatus_Completed)
+			So(job.ExportedRows, ShouldEqual, 3)
+
+			content, err := os.ReadFile(job.FilePath)
+			So(err, ShouldBeNil)
+			So(string(content), ShouldEqual,
+				`{"__time":1000,"__value":1.5,"host":"a","zone":null}`+"\n"+
+					`{"__time":2000,"__value":null,"host":"a","zone":null}`+"\n"+
+					`{"__time":1000,"__value":2,"host":"b","zone":"z1"}`+"\n")
+		})
+
+		Convey("Labels of atomic metric are masked by the view's row column rules", func() {
+			dvService := umock.NewMockDataViewService(mockCtrl)
+			es.dvService = dvService
+
+			mmService.EXPECT().Exec(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, query *interfaces.MetricModelQuery) (interfaces.MetricModelUniResponse, int, int, error) {
+					query.MetricType = interfaces.ATOMIC_METRIC
+					query.DataSource = &interfaces.MetricDataSource{Type: interfaces.QueryType_DSL, ID: "v1"}
+					return interfaces.MetricModelUniResponse{
+						Datas: []interfaces.MetricModelData{
+							{Labels: map[string]string{"host": "a"}, Times: []any{int64(1000)}, Values: []any{1.5}},
+						},
+					}, 0, 0, nil
+				})
+			dvService.EXPECT().MaskViewData(gomock.Any(), "v1", gomock.Any()).DoAndReturn(
+				func(ctx context.Context, viewID string, entries []map[string]any) error {
+					for _, entry := range entries {
+						entry["host"] = nil
+					}
+					return nil
+				})
+
+			created, err := es.CreateExportJob(ctx, &interfaces.ExportJobRequest{
+				SourceType:  interfaces.ExportSourceType_MetricModel,
+				SourceID:    "m1",
+				Format:      interfaces.ExportFormat_NDJSON,
+				MetricQuery: &interfaces.MetricModelQuery{},
+			})
+			So(err, ShouldBeNil)
+
+			job := waitJob(ctx, es, created.ID)
+			So(job.Status, ShouldEqual, interfaces.ExportJobStatus_Completed)
+
+			content, err := os.ReadFile(job.FilePath)
+			So(err, ShouldBeNil)
+			So(string(content), ShouldEqual, `{"__time":1000,"__value":1.5,"host":null}`+"\n")
+		})
+
+		Convey("Long range query is executed window by window", func() {
+			common.APP_LOCATION = time.UTC
+			step := "1m"
+			start, end := int64(0), 2500*time.Minute.Milliseconds()
+
+			var starts []int64
+			mmService.EXPECT().Exec(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(
+				func(ctx context.Context, query *interfaces.MetricModelQuery) (interfaces.MetricModelUniResponse, int, int, error) {
+					starts = append(starts, *query.Start)
+					return interfaces.MetricModelUniResponse{
+						Datas: []interfaces.MetricModelData{
+							{Labels: map[string]string{"host": "a"}, Times: []any{*query.Start}, Values: []any{1.0}},
+						},
+					}, 0, 0, nil
+				})
+
+			created, err := es.CreateExportJob(ctx, &interfaces.ExportJobRequest{
+				SourceType: interfaces.ExportSourceType_MetricModel,
+				SourceID:   "m1",
+				Format:     interfaces.ExportFormat_CSV,
+				MetricQuery: &interfaces.MetricModelQuery{
+					QueryTimeParams: interfaces.QueryTimeParams{Start: &start, End: &end, StepStr: &step},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			job := waitJob(ctx, es, created.ID)
+			So(job.Status, ShouldEqual, interfaces.ExportJobStatus_Completed)
+			So(job.ExportedRows, ShouldEqual, 3)
+			So(*job.TotalRows, ShouldEqual, 3)
+			So(starts, ShouldResemble, []int64{0, 1000 * time.Minute.Milliseconds(), 2000 * time.Minute.Milliseconds()})
+
+			// 中间文件在导出结束后删除
+			_, err = os.Stat(job.FilePath + spoolFileSuffix)
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("Metric query is required", func() {
+			_, err := es.CreateExportJob(ctx, &interfaces.ExportJobRequest{
+				SourceType: interfaces.ExportSourceType_MetricModel,
+				SourceID:   "m1",
+				Format:     interfaces.ExportFormat_CSV,
+			})
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
+
+func Test_ExportService_Access(t *testing.T) {
+	Convey("Test export job access", t, func() {
+		appSetting := &common.AppSetting{
+			ExportSetting: common.ExportSetting{FileDir: t.TempDir()},
+		}
+		store := newMemoryStore()
+		es := newExportService(appSetting, store, nil, nil)
+		ctx := testExportCtx("u1")
+
+		job := &interfaces.ExportJob{
+			ID:         "job1",
+			Status:     interfaces.ExportJobStatus_Running,
+			UpdateTime: time.Now().UnixMilli(),
+			Creator:    interfaces.AccountInfo{ID: "u1"},
+		}
+		So(store.SetJob(ctx, job, time.Hour), ShouldBeNil)
+
+		Convey("Other account cannot see the job", func() {
+			_, err := es.GetExportJob(testExportCtx("u2"), "job1")
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Download unfinished job", func() {
+			_, _, err := es.OpenExportFile(ctx, "job1")
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusConflict)
+		})
+
+		Convey("Job not synced for a long time is marked failed", func() {
+			job.UpdateTime = time.Now().Add(-2 * jobStaleTimeout).UnixMilli()
+			So(store.SetJob(ctx, job, time.Hour), ShouldBeNil)
+
+			got, err := es.GetExportJob(ctx, "job1")
+			So(err, ShouldBeNil)
+			So(got.Status, ShouldEqual, interfaces.ExportJobStatus_Failed)
+
+			stored, ok, err := store.GetJob(ctx, "job1")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+			So(stored.Status, ShouldEqual, interfaces.ExportJobStatus_Failed)
+		})
+
+		Convey("Deleted job is not found", func() {
+			So(es.DeleteExportJob(ctx, "job1"), ShouldBeNil)
+
+			_, err := es.GetExportJob(ctx, "job1")
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Cleanup expired files", func() {
+			oldFile := filepath.Join(es.fileDir, "old.csv")
+			So(os.WriteFile(oldFile, []byte("x"), 0o600), ShouldBeNil)
+			oldTime := time.Now().Add(-2 * es.retention)
+			So(os.Chtimes(oldFile, oldTime, oldTime), ShouldBeNil)
+			newFile := filepath.Join(es.fileDir, "new.csv")
+			So(os.WriteFile(newFile, []byte("x"), 0o600), ShouldBeNil)
+
+			es.cleanup(time.Now())
+
+			_, err := os.Stat(oldFile)
+			So(os.IsNotExist(err), ShouldBeTrue)
+			_, err = os.Stat(newFile)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func Test_ExportService_SyncJob(t *testing.T) {
+	Convey("Test running job is canceled after deleted from store", t, func() {
+		appSetting := &common.AppSetting{
+			ExportSetting: common.ExportSetting{FileDir: t.TempDir()},
+		}
+		store := newMemoryStore()
+		es := newExportService(appSetting, store, nil, nil)
+		ctx := testExportCtx("u1")
+
+		jobCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		job := &exportJob{
+			job: interfaces.ExportJob{
+				ID:      "job1",
+				Status:  interfaces.ExportJobStatus_Running,
+				Creator: interfaces.AccountInfo{ID: "u1"},
+			},
+			cancel: cancel,
+		}
+		So(store.SetJob(ctx, job.snapshot(), time.Hour), ShouldBeNil)
+
+		job.update(func(j *interfaces.ExportJob) { j.ExportedRows = 10 })
+		es.syncJob(jobCtx, job)
+		stored, _, _ := store.GetJob(ctx, "job1")
+		So(stored.ExportedRows, ShouldEqual, 10)
+		So(jobCtx.Err(), ShouldBeNil)
+
+		// 其他实例删除了任务
+		So(store.DeleteJob(ctx, "job1"), ShouldBeNil)
+		es.syncJob(jobCtx, job)
+		So(jobCtx.Err(), ShouldNotBeNil)
+		_, ok, _ := store.GetJob(ctx, "job1")
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func Test_MetricQueryChunks(t *testing.T) {
+	Convey("Test metricQueryChunks", t, func() {
+		common.APP_LOCATION = time.UTC
+		step := "1m"
+		stepMs := time.Minute.Milliseconds()
+
+		Convey("Long range query is split into aligned windows", func() {
+			start := int64(30 * 1000)
+			end := 2500 * stepMs
+			chunks := metricQueryChunks(&interfaces.MetricModelQuery{
+				QueryTimeParams: interfaces.QueryTimeParams{Start: &start, End: &end, StepStr: &step},
+			})
+
+			So(len(chunks), ShouldEqual, 3)
+			So(*chunks[0].Start, ShouldEqual, start)
+			So(*chunks[0].End, ShouldEqual, 999*stepMs)
+			So(*chunks[1].Start, ShouldEqual, 1000*stepMs)
+			So(*chunks[1].End, ShouldEqual, 1999*stepMs)
+			So(*chunks[2].Start, ShouldEqual, 2000*stepMs)
+			So(*chunks[2].End, ShouldEqual, end)
+		})
+
+		Convey("Short range, instant and calendar step queries are not split", func() {
+			start, end := int64(0), 10*stepMs
+			query := &interfaces.MetricModelQuery{
+				QueryTimeParams: interfaces.QueryTimeParams{Start: &start, End: &end, StepStr: &step},
+			}
+			So(metricQueryChunks(query), ShouldResemble, []*interfaces.MetricModelQuery{query})
+
+			end = 5000 * stepMs
+			query.IsInstantQuery = true
+			So(len(metricQueryChunks(query)), ShouldEqual, 1)
+
+			calendar := interfaces.CALENDAR_STEP_MINUTE
+			query.IsInstantQuery = false
+			query.StepStr = &calendar
+			So(len(metricQueryChunks(query)), ShouldEqual, 1)
+		})
+	})
+}
+
+func Test_RedisStore(t *testing.T) {
+	Convey("Test redis job store", t, func() {
+		mr := miniredis.RunT(t)
+		store := newRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+		ctx := context.Background()
+
+		total := int64(5)
+		job := &interfaces.ExportJob{
+			ID:        "job1",
+			Status:    interfaces.ExportJobStatus_Running,
+			TotalRows: &total,
+			Creator:   interfaces.AccountInfo{ID: "u1", Type: "user"},
+			FilePath:  "/data/job1.csv",
+		}
+
+		ok, err := store.UpdateJob(ctx, job, time.Hour)
+		So(err, ShouldBeNil)
+		So(ok, ShouldBeFalse)
+
+		So(store.SetJob(ctx, job, time.Hour), ShouldBeNil)
+		got, ok, err := store.GetJob(ctx, "job1")
+		So(err, ShouldBeNil)
+		So(ok, ShouldBeTrue)
+		So(got, ShouldResemble, job)
+
+		job.Status = interfaces.ExportJobStatus_Completed
+		ok, err = store.UpdateJob(ctx, job, time.Hour)
+		So(err, ShouldBeNil)
+		So(ok, ShouldBeTrue)
+		got, _, _ = store.GetJob(ctx, "job1")
+		So(got.Status, ShouldEqual, interfaces.ExportJobStatus_Completed)
+
+		So(store.DeleteJob(ctx, "job1"), ShouldBeNil)
+		_, ok, err = store.GetJob(ctx, "job1")
+		So(err, ShouldBeNil)
+		So(ok, ShouldBeFalse)
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/patrickmn/go-cache"
+	"github.com/redis/go-redis/v9"
+
+	"uniquery/interfaces"
+)
+
+const (
+	// Redis 中导出任务的 key 前缀
+	redisJobKeyPrefix = "mdl-uniquery:export:job:"
+	// 内存存储清理过期条目的间隔
+	memoryCleanupInterval = 10 * time.Minute
+)
+
+// jobStore 导出任务的存储，任务在 ttl 后过期，过期由存储自身完成
+type jobStore interface {
+	GetJob(ctx context.Context, jobID string) (*interfaces.ExportJob, bool, error)
+	SetJob(ctx context.Context, job *interfaces.ExportJob, ttl time.Duration) error
+	// UpdateJob 只在任务存在时更新，返回 false 表示任务已被删除或已过期
+	UpdateJob(ctx context.Context, job *interfaces.ExportJob, ttl time.Duration) (bool, error)
+	DeleteJob(ctx context.Context, jobID string) error
+}
+
+// memoryStore 进程内存储，多实例部署时任务只能在创建它的实例上查询和下载
+type memoryStore struct {
+	jobs *cache.Cache
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		jobs: cache.New(cache.NoExpiration, memoryCleanupInterval),
+	}
+}
+
+// 存取时复制一份，避免调用方修改存储中的对象
+func (ms *memoryStore) GetJob(ctx context.Context, jobID string) (*interfaces.ExportJob, bool, error) {
+	v, ok := ms.jobs.Get(jobID)
+	if !ok {
+		return nil, false, nil
+	}
+	return cloneJob(v.(*interfaces.ExportJob)), true, nil
+}
+
+func (ms *memoryStore) SetJob(ctx context.Context, job *interfaces.ExportJob, ttl time.Duration) error {
+	ms.jobs.Set(job.ID, cloneJob(job), ttl)
+	return nil
+}
+
+func (ms *memoryStore) UpdateJob(ctx context.Context, job *interfaces.ExportJob, ttl time.Duration) (bool, error) {
+	// Replace 在 key 不存在时返回错误
+	if err := ms.jobs.Replace(job.ID, cloneJob(job), ttl); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (ms *memoryStore) DeleteJob(ctx context.Context, jobID string) error {
+	ms.jobs.Delete(jobID)
+	return nil
+}
+
+// redisStore Redis 存储，多实例共享，任意实例都可以查询、下载和删除任务
+type redisStore struct {
+	client redis.UniversalClient
+}
+
+func newRedisStore(client redis.UniversalClient) *redisStore {
+	return &redisStore{client: client}
+}
+
+// redisJob 任务中不对外返回的创建者和文件路径也需要持久化
+type redisJob struct {
+	Job      *interfaces.ExportJob  `json:"job"`
+	Creator  interfaces.AccountInfo `json:"creator"`
+	FilePath string                 `json:"file_path"`
+}
+
+func (rs *redisStore) GetJob(ctx context.Context, jobID string) (*interfaces.ExportJob, bool, error) {
+	b, err := rs.client.Get(ctx, redisJobKeyPrefix+jobID).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	var stored redisJob
+	if err = sonic.Unmarshal(b, &stored); err != nil {
+		return nil, false, fmt.Errorf("unmarshal export job %s failed: %w", jobID, err)
+	}
+	if stored.Job == nil {
+		return nil, false, nil
+	}
+	stored.Job.Creator = stored.Creator
+	stored.Job.FilePath = stored.FilePath
+	return stored.Job, true, nil
+}
+
+func (rs *redisStore) SetJob(ctx context.Context, job *interfaces.ExportJob, ttl time.Duration) error {
+	b, err := marshalJob(job)
+	if err != nil {
+		return err
+	}
+	return rs.client.Set(ctx, redisJobKeyPrefix+job.ID, b, ttl).Err()
+}
+
+func (rs *redisStore) UpdateJob(ctx context.Context, job *interfaces.ExportJob, ttl time.Duration) (bool, error) {
+	b, err := marshalJob(job)
+	if err != nil {
+		return false, err
+	}
+	return rs.client.SetXX(ctx, redisJobKeyPrefix+job.ID, b, ttl).Result()
+}
+
+func (rs *redisStore) DeleteJob(ctx context.Context, jobID string) error {
+	return rs.client.Del(ctx, redisJobKeyPrefix+jobID).Err()
+}
+
+func marshalJob(job *interfaces.ExportJob) ([]byte, error) {
+	b, err := sonic.Marshal(redisJob{Job: job, Creator: job.Creator, FilePath: job.FilePath})
+	if err != nil {
+		return nil, fmt.Errorf("marshal export job %s failed: %w", job.ID, err)
+	}
+	return b, nil
+}
+
+func cloneJob(job *interfaces.ExportJob) *interfaces.ExportJob {
+	j := *job
+	if job.TotalRows != nil {
+		total := *job.TotalRows
+		j.TotalRows = &total
+	}
+	return &j
+}
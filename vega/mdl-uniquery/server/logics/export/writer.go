@@ -0,0 +1,324 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+	"github.com/parquet-go/parquet-go"
+
+	"uniquery/interfaces"
+	dtype "uniquery/interfaces/data_type"
+)
+
+const (
+	// parquet 每攒够多少行写一次
+	parquetBatchSize = 1024
+)
+
+// 导出文件的列，type 为视图字段类型，决定 parquet 列的物理类型
+type exportColumn struct {
+	Name string
+	Type string
+}
+
+// rowWriter 按列顺序把查询结果逐行写入导出文件，Close 只刷新缓冲，不关闭底层文件
+type rowWriter interface {
+	WriteRow(row map[string]any) error
+	Close() error
+}
+
+func newRowWriter(format string, w io.Writer, columns []*exportColumn) (rowWriter, error) {
+	switch format {
+	case interfaces.ExportFormat_CSV:
+		return newCSVWriter(w, columns)
+	case interfaces.ExportFormat_NDJSON:
+		return newNDJSONWriter(w, columns), nil
+	case interfaces.ExportFormat_Parquet:
+		return newParquetWriter(w, columns)
+	default:
+		return nil, fmt.Errorf("unsupported export format '%s'", format)
+	}
+}
+
+// csvWriter 首行为列名，文件带 UTF-8 BOM，便于表格软件识别中文
+type csvWriter struct {
+	buf     *bufio.Writer
+	writer  *csv.Writer
+	columns []*exportColumn
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []*exportColumn) (*csvWriter, error) {
+	buf := bufio.NewWriter(w)
+	if _, err := buf.WriteString("\xEF\xBB\xBF"); err != nil {
+		return nil, err
+	}
+
+	writer := csv.NewWriter(buf)
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Name
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &csvWriter{
+		buf:     buf,
+		writer:  writer,
+		columns: columns,
+		record:  make([]string, len(columns)),
+	}, nil
+}
+
+func (cw *csvWriter) WriteRow(row map[string]any) error {
+	for i, col := range cw.columns {
+		cell, err := formatCell(row[col.Name])
+		if err != nil {
+			return fmt.Errorf("format column '%s' failed: %w", col.Name, err)
+		}
+		cw.record[i] = cell
+	}
+	return cw.writer.Write(cw.record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.writer.Flush()
+	if err := cw.writer.Error(); err != nil {
+		return err
+	}
+	return cw.buf.Flush()
+}
+
+// ndjsonWriter 每行一个 JSON 对象，只保留导出的列
+type ndjsonWriter struct {
+	buf     *bufio.Writer
+	columns []*exportColumn
+}
+
+func newNDJSONWriter(w io.Writer, columns []*exportColumn) *ndjsonWriter {
+	return &ndjsonWriter{
+		buf:     bufio.NewWriter(w),
+		columns: columns,
+	}
+}
+
+func (nw *ndjsonWriter) WriteRow(row map[string]any) error {
+	obj := make(map[string]any, len(nw.columns))
+	for _, col := range nw.columns {
+		obj[col.Name] = row[col.Name]
+	}
+
+	// 按列名排序输出，保证相同数据导出的文件一致
+	line, err := sonic.ConfigStd.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	if _, err = nw.buf.Write(line); err != nil {
+		return err
+	}
+	return nw.buf.WriteByte('\n')
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nw.buf.Flush()
+}
+
+// parquetWriter 所有列均为 optional。
+// 整数、浮点、布尔类型的字段写为对应的物理类型，decimal 为避免精度损失写为字符串，其余类型写为字符串
+type parquetWriter struct {
+	writer   *parquet.Writer
+	columns  []*exportColumn
+	convert  []func(any) (parquet.Value, error)
+	colIndex []int
+	rows     []parquet.Row
+}
+
+func newParquetWriter(w io.Writer, columns []*exportColumn) (*parquetWriter, error) {
+	group := make(parquet.Group, len(columns))
+	converters := make([]func(any) (parquet.Value, error), len(columns))
+	for i, col := range columns {
+		node, convert := parquetColumn(col.Type)
+		group[col.Name] = parquet.Optional(node)
+		converters[i] = convert
+	}
+	schema := parquet.NewSchema("export", group)
+
+	// parquet.Group 按列名排序，写入时需要按 schema 中的列序号放置值
+	colIndex := make([]int, len(columns))
+	for i, col := range columns {
+		leaf, ok := schema.Lookup(col.Name)
+		if !ok {
+			return nil, fmt.Errorf("column '%s' not found in parquet schema", col.Name)
+		}
+		colIndex[i] = leaf.ColumnIndex
+	}
+
+	return &parquetWriter{
+		writer:   parquet.NewWriter(w, schema, parquet.Compression(&parquet.Snappy)),
+		columns:  columns,
+		convert:  converters,
+		colIndex: colIndex,
+		rows:     make([]parquet.Row, 0, parquetBatchSize),
+	}, nil
+}
+
+func (pw *parquetWriter) WriteRow(row map[string]any) error {
+	values := make(parquet.Row, len(pw.columns))
+	for i, col := range pw.columns {
+		idx := pw.colIndex[i]
+		v := row[col.Name]
+		if v == nil {
+			values[idx] = parquet.NullValue().Level(0, 0, idx)
+			continue
+		}
+
+		value, err := pw.convert[i](v)
+		if err != nil {
+			return fmt.Errorf("convert column '%s' failed: %w", col.Name, err)
+		}
+		values[idx] = value.Level(0, 1, idx)
+	}
+
+	pw.rows = append(pw.rows, values)
+	if len(pw.rows) >= parquetBatchSize {
+		return pw.flushRows()
+	}
+	return nil
+}
+
+func (pw *parquetWriter) flushRows() error {
+	if len(pw.rows) == 0 {
+		return nil
+	}
+	if _, err := pw.writer.WriteRows(pw.rows); err != nil {
+		return err
+	}
+	pw.rows = pw.rows[:0]
+	return nil
+}
+
+func (pw *parquetWriter) Close() error {
+	if err := pw.flushRows(); err != nil {
+		return err
+	}
+	return pw.writer.Close()
+}
+
+// parquetColumn 返回字段类型对应的 parquet 列类型及值转换函数
+func parquetColumn(fieldType string) (parquet.Node, func(any) (parquet.Value, error)) {
+	switch fieldType {
+	case dtype.DataType_Integer, dtype.DataType_UnsignedInteger:
+		return parquet.Int(64), func(v any) (parquet.Value, error) {
+			i, err := toInt64(v)
+			return parquet.Int64Value(i), err
+		}
+	case dtype.DataType_Float:
+		return parquet.Leaf(parquet.DoubleType), func(v any) (parquet.Value, error) {
+			f, err := toFloat64(v)
+			return parquet.DoubleValue(f), err
+		}
+	case dtype.DataType_Boolean:
+		return parquet.Leaf(parquet.BooleanType), func(v any) (parquet.Value, error) {
+			switch b := v.(type) {
+			case bool:
+				return parquet.BooleanValue(b), nil
+			case string:
+				parsed, err := strconv.ParseBool(b)
+				return parquet.BooleanValue(parsed), err
+			default:
+				return parquet.Value{}, fmt.Errorf("cannot convert %T to boolean", v)
+			}
+		}
+	default:
+		return parquet.String(), func(v any) (parquet.Value, error) {
+			s, err := formatCell(v)
+			return parquet.ByteArrayValue([]byte(s)), err
+		}
+	}
+}
+
+// formatCell 把字段值格式化为文本，数组、对象等复杂类型格式化为 JSON
+func formatCell(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		return fmt.Sprint(val), nil
+	default:
+		b, err := sonic.ConfigStd.Marshal(val)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
+
+func toInt64(v any) (int64, error) {
+	switch val := v.(type) {
+	case int:
+		return int64(val), nil
+	case int32:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	case uint32:
+		return int64(val), nil
+	case uint64:
+		if val > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", val)
+		}
+		return int64(val), nil
+	case float64:
+		if val != math.Trunc(val) {
+			return 0, fmt.Errorf("value %v is not an integer", val)
+		}
+		return int64(val), nil
+	case json.Number:
+		return val.Int64()
+	case string:
+		return strconv.ParseInt(val, 10, 64)
+	default:
+		return 0, fmt.Errorf("cannot convert %T to integer", v)
+	}
+}
+
+func toFloat64(v any) (float64, error) {
+	switch val := v.(type) {
+	case float64:
+		return val, nil
+	case float32:
+		return float64(val), nil
+	case int:
+		return float64(val), nil
+	case int32:
+		return float64(val), nil
+	case int64:
+		return float64(val), nil
+	case json.Number:
+		return val.Float64()
+	case string:
+		return strconv.ParseFloat(val, 64)
+	default:
+		return 0, fmt.Errorf("cannot convert %T to float", v)
+	}
+}
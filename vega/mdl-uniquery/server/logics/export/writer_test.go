@@ -0,0 +1,118 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/interfaces"
+	dtype "uniquery/interfaces/data_type"
+)
+
+var testColumns = []*exportColumn{
+	{Name: "name", Type: dtype.DataType_String},
+	{Name: "count", Type: dtype.DataType_Integer},
+	{Name: "ratio", Type: dtype.DataType_Float},
+	{Name: "ok", Type: dtype.DataType_Boolean},
+	{Name: "tags", Type: dtype.DataType_String},
+}
+
+var testRows = []map[string]any{
+	{"name": "a,b", "count": float64(3), "ratio": 0.5, "ok": true, "tags": []any{"x", "y"}, "extra": "ignored"},
+	{"name": "中文", "count": nil, "ratio": float64(2), "ok": false},
+}
+
+func writeTestRows(format string) (*bytes.Buffer, error) {
+	buf := &bytes.Buffer{}
+	writer, err := newRowWriter(format, buf, testColumns)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range testRows {
+		if err = writer.WriteRow(row); err != nil {
+			return nil, err
+		}
+	}
+	return buf, writer.Close()
+}
+
+func Test_RowWriter_CSV(t *testing.T) {
+	Convey("Test csv writer", t, func() {
+		buf, err := writeTestRows(interfaces.ExportFormat_CSV)
+		So(err, ShouldBeNil)
+
+		expected := "\xEF\xBB\xBF" +
+			"name,count,ratio,ok,tags\n" +
+			"\"a,b\",3,0.5,true,\"[\"\"x\"\",\"\"y\"\"]\"\n" +
+			"中文,,2,false,\n"
+		So(buf.String(), ShouldEqual, expected)
+	})
+}
+
+func Test_RowWriter_NDJSON(t *testing.T) {
+	Convey("Test ndjson writer", t, func() {
+		buf, err := writeTestRows(interfaces.ExportFormat_NDJSON)
+		So(err, ShouldBeNil)
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		So(lines, ShouldResemble, []string{
+			`{"count":3,"name":"a,b","ok":true,"ratio":0.5,"tags":["x","y"]}`,
+			`{"count":null,"name":"中文","ok":false,"ratio":2,"tags":null}`,
+		})
+	})
+}
+
+func Test_RowWriter_Parquet(t *testing.T) {
+	Convey("Test parquet writer", t, func() {
+		Convey("Write and read back", func() {
+			buf, err := writeTestRows(interfaces.ExportFormat_Parquet)
+			So(err, ShouldBeNil)
+
+			file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			So(err, ShouldBeNil)
+			So(file.NumRows(), ShouldEqual, 2)
+
+			reader := parquet.NewReader(file)
+			defer reader.Close()
+
+			rows := make([]map[string]any, 0, 2)
+			for range 2 {
+				row := map[string]any{}
+				So(reader.Read(&row), ShouldBeNil)
+				rows = append(rows, row)
+			}
+
+			So(rows[0]["name"], ShouldEqual, "a,b")
+			So(rows[0]["count"], ShouldEqual, int64(3))
+			So(rows[0]["ratio"], ShouldEqual, 0.5)
+			So(rows[0]["ok"], ShouldEqual, true)
+			So(rows[0]["tags"], ShouldEqual, `["x","y"]`)
+			So(rows[1]["name"], ShouldEqual, "中文")
+			So(rows[1]["count"], ShouldBeNil)
+			So(rows[1]["tags"], ShouldBeNil)
+		})
+
+		Convey("Convert failed", func() {
+			writer, err := newRowWriter(interfaces.ExportFormat_Parquet, &bytes.Buffer{}, testColumns)
+			So(err, ShouldBeNil)
+
+			err = writer.WriteRow(map[string]any{"count": 1.5})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_RowWriter_UnsupportedFormat(t *testing.T) {
+	Convey("Test unsupported format", t, func() {
+		_, err := newRowWriter("xlsx", &bytes.Buffer{}, testColumns)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -38,6 +38,8 @@ import (
 	"uniquery/logics/promql"
 	"uniquery/logics/promql/static"
 	"uniquery/logics/promql/util"
+	"uniquery/logics/result_cache"
+	"uniquery/logics/trace_model"
 	"uniquery/logics/vega_view"
 )
 
@@ -57,6 +59,8 @@ type metricModelService struct {
 	ps            interfaces.PermissionService
 	promqlService interfaces.PromQLService
 	vvs           interfaces.VegaService
+	rCache        interfaces.ResultCache
+	tmService     interfaces.TraceModelService
 }
 
 func NewMetricModelService(appSetting *common.AppSetting) interfaces.MetricModelService {
@@ -68,8 +72,10 @@ func NewMetricModelService(appSetting *common.AppSetting) interfaces.MetricModel
 			mmAccess:     logics.MMAccess,
 			osAccess:     logics.OSAccess,
 			ps:           permission.NewPermissionService(appSetting),
+			rCache:       result_cache.NewResultCache(appSetting),
 			sem:          semaphore.NewWeighted(int64(appSetting.PoolSetting.ExecutePoolSize)),
 			staticAccess: logics.StAccess,
+			tmService:    trace_model.NewTraceModelService(appSetting),
 			vvs:          vega_view.NewVegaService(appSetting),
 		}
 
@@ -129,6 +135,27 @@ func (mms *metricModelService) Simulate(ctx context.Context,
 
 	// span.SetAttributes(attribute.Key("data_source_type").String(query.DataSource.Type),
 	// 	attribute.Key("data_source_id").String(query.DataSource.ID))
+	// 链路模型的RED指标不依赖数据视图, 由链路模型聚合span计算
+	if query.MetricType == interfaces.ATOMIC_METRIC && query.QueryType == interfaces.RED {
+		resp, err := mms.execRED(ctx, query)
+		if err != nil {
+			span.SetStatus(codes.Error, "Eval Metric Model error")
+			o11y.Error(ctx, fmt.Sprintf("Eval Metric Model error: %v", err))
+			return resp, err
+		}
+
+		if query.IncludeModel {
+			resp.Model = interfaces.MetricModel{
+				MetricType:    query.MetricType,
+				DataSource:    query.DataSource,
+				QueryType:     query.QueryType,
+				FormulaConfig: query.FormulaConfig,
+			}
+		}
+		span.SetStatus(codes.Ok, "")
+		return resp, nil
+	}
+
 	dataView := &interfaces.DataView{}
 	ayDims := []interfaces.Field{}
 	if query.MetricType == interfaces.ATOMIC_METRIC {
@@ -288,6 +315,28 @@ func (mms *metricModelService) Exec(ctx context.Context, query *interfaces.Metri
 	query.MetricModelID = metricModel.ModelID
 	query.DateField = metricModel.DateField // 按id查询时,需要把模型配置的时间字段赋值给query的时间字段
 
+	// 链路模型的RED指标不依赖数据视图, 由链路模型聚合span计算, 不匹配持久化任务和缓存
+	if query.MetricType == interfaces.ATOMIC_METRIC && query.QueryType == interfaces.RED {
+		respi, err := mms.execRED(ctx, *query)
+		if err != nil {
+			span.SetAttributes(attribute.Key("model_id").String(query.MetricModelID))
+			span.SetStatus(codes.Error, "Eval Metric Model error")
+			span.End()
+			o11y.Error(ctx, fmt.Sprintf("Eval Metric Model error: %v", err))
+
+			return resps, seriesTotal, pointTotal, err
+		}
+
+		if query.IncludeModel {
+			respi.Model = metricModel
+		}
+		span.SetStatus(codes.Ok, "")
+		span.End()
+		respi.StatusCode = http.StatusOK
+
+		return respi, respi.CurrSeriesNum, respi.PointTotal, nil
+	}
+
 	dataView := &interfaces.DataView{}
 	if query.MetricType == interfaces.ATOMIC_METRIC {
 		// query.DataView = metricModel.DataView
@@ -328,8 +377,13 @@ func (mms *metricModelService) Exec(ctx context.Context, query *interfaces.Metri
 		}
 	}
 
-	// 按 指标类型+查询语言 分情况发起查询
-	respi, err := mms.eval(ctx, *query, metricModel, dataView)
+	// 按 指标类型+查询语言 分情况发起查询，可缓存的范围查询只查询缓存之外的首尾区间
+	var respi interfaces.MetricModelUniResponse
+	if mms.cacheable(*query, metricModel) {
+		respi, err = mms.evalWithCache(ctx, *query, metricModel, dataView)
+	} else {
+		respi, err = mms.eval(ctx, *query, metricModel, dataView)
+	}
 	if err != nil {
 		// 添加异常时的 trace 属性
 		span.SetAttributes(attribute.Key("model_id").String(query.MetricModelID))
@@ -1919,6 +1973,9 @@ func (mms *metricModelService) GetMetricModelFields(ctx context.Context, modelID
 	switch model.MetricType {
 	case interfaces.ATOMIC_METRIC:
 		switch qt, dsType := model.QueryType, model.DataSource.Type; {
+		case qt == interfaces.RED:
+			// RED指标的字段为固定的序列标签
+			fields = append(fields, redLabelFields...)
 		case qt == interfaces.DSL:
 			// 2. dsl时把指标模型身上的字段map转换成数组输出
 			for _, field := range model.FieldsMap {
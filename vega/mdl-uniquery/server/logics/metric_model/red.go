@@ -0,0 +1,120 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package metric_model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+	"uniquery/interfaces/data_type"
+)
+
+// RED指标的序列标签, 与链路模型RED指标接口返回的标签一致
+var redLabelFields = []interfaces.Field{
+	{Name: "service_name", Type: data_type.DataType_String, DisplayName: "service_name"},
+	{Name: "operation", Type: data_type.DataType_String, DisplayName: "operation"},
+	{Name: "metric", Type: data_type.DataType_String, DisplayName: "metric"},
+}
+
+// 查询以链路模型为数据源的RED指标. 数据源id为链路模型id, 按 formula_config 中的指标名, 服务和操作聚合span.
+// 即时查询时整个时间范围计算一个点, 范围查询只支持固定步长
+func (mms *metricModelService) execRED(ctx context.Context, query interfaces.MetricModelQuery) (interfaces.MetricModelUniResponse, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "查询链路模型的RED指标")
+	defer span.End()
+
+	resp := interfaces.MetricModelUniResponse{
+		Datas: []interfaces.MetricModelData{},
+		Step:  query.StepStr,
+	}
+
+	redConfig, err := toREDConfig(ctx, query.FormulaConfig)
+	if err != nil {
+		span.SetStatus(codes.Error, "Invalid red config")
+		return resp, err
+	}
+
+	params := interfaces.REDMetricsQueryParams{
+		TraceAnalyticsQueryParams: interfaces.TraceAnalyticsQueryParams{
+			Start:       *query.Start,
+			End:         *query.End,
+			ServiceName: redConfig.ServiceName,
+		},
+		Operation:      redConfig.Operation,
+		Metrics:        []string{redConfig.Metric},
+		IsInstantQuery: query.IsInstantQuery,
+	}
+	if query.IsInstantQuery {
+		params.StepMs = *query.End - *query.Start
+	} else {
+		// 日历步长未转换为毫秒, span 的时间分桶只支持固定步长
+		if query.Step == nil {
+			span.SetStatus(codes.Error, "Calendar step is not supported")
+			return resp, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_MetricModel_InvalidParameter_Step).
+				WithErrorDetails("The red metric model only supports fixed steps")
+		}
+		params.Step = *query.StepStr
+		params.StepMs = *query.Step
+	}
+	span.SetAttributes(attribute.Key("trace_model_id").String(query.DataSource.ID),
+		attribute.Key("metric").String(redConfig.Metric))
+
+	traceModel, err := mms.tmService.GetTraceModelByID(ctx, query.DataSource.ID)
+	if err != nil {
+		span.SetStatus(codes.Error, "Get trace model by ID failed")
+		return resp, err
+	}
+
+	redResp, err := mms.tmService.GetREDMetrics(ctx, traceModel, params)
+	if err != nil {
+		span.SetStatus(codes.Error, "Get red metrics failed")
+		return resp, err
+	}
+
+	resp.Datas = redResp.Datas
+	resp.SeriesTotal = len(resp.Datas)
+	resp.CurrSeriesNum = len(resp.Datas)
+	for _, data := range resp.Datas {
+		resp.PointTotal += len(data.Times)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return resp, nil
+}
+
+// 把 formula_config 转成 REDConfig
+func toREDConfig(ctx context.Context, formulaConfig any) (interfaces.REDConfig, error) {
+	var redConfig interfaces.REDConfig
+	if config, ok := formulaConfig.(interfaces.REDConfig); ok {
+		redConfig = config
+	} else {
+		jsonData, err := json.Marshal(formulaConfig)
+		if err != nil {
+			return redConfig, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_MetricModel_InvalidParameter_FormulaConfig).
+				WithErrorDetails(fmt.Sprintf("RED Config Marshal error: %s", err.Error()))
+		}
+		err = json.Unmarshal(jsonData, &redConfig)
+		if err != nil {
+			return redConfig, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_MetricModel_InvalidParameter_FormulaConfig).
+				WithErrorDetails(fmt.Sprintf("RED Config Unmarshal error: %s", err.Error()))
+		}
+	}
+
+	if _, ok := interfaces.RED_METRICS[redConfig.Metric]; !ok {
+		return redConfig, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_MetricModel_InvalidParameter_FormulaConfig).
+			WithErrorDetails(fmt.Sprintf("The red metric %s is not one of [rate, errors, error_rate, duration_p50, duration_p95, duration_p99]",
+				redConfig.Metric))
+	}
+	return redConfig, nil
+}
@@ -0,0 +1,119 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package metric_model
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/common"
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+	umock "uniquery/interfaces/mock"
+)
+
+func TestExecRED(t *testing.T) {
+	Convey("Test execRED", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		tmService := umock.NewMockTraceModelService(mockCtrl)
+		mms := MockNewMetricModelService(appSetting, nil, nil, nil, nil, nil, nil)
+		mms.tmService = tmService
+
+		start, end := int64(1700000000000), int64(1700000300000)
+		step, stepStr := int64(60000), "1m"
+		traceModel := interfaces.TraceModel{ID: "tm1"}
+		query := interfaces.MetricModelQuery{
+			MetricType: interfaces.ATOMIC_METRIC,
+			QueryType:  interfaces.RED,
+			DataSource: &interfaces.MetricDataSource{ID: "tm1"},
+			FormulaConfig: map[string]any{
+				"metric":       interfaces.RED_METRIC_ERROR_RATE,
+				"service_name": "order",
+			},
+		}
+		query.Start = &start
+		query.End = &end
+		redResp := interfaces.MetricModelUniResponse{
+			Datas: []interfaces.MetricModelData{
+				{
+					Labels: map[string]string{"service_name": "order", "operation": "create", "metric": interfaces.RED_METRIC_ERROR_RATE},
+					Times:  []any{start, start + step},
+					Values: []any{0.5, nil},
+				},
+			},
+		}
+
+		Convey("Range query with fixed step", func() {
+			query.Step = &step
+			query.StepStr = &stepStr
+			tmService.EXPECT().GetTraceModelByID(gomock.Any(), "tm1").Return(traceModel, nil)
+			tmService.EXPECT().GetREDMetrics(gomock.Any(), traceModel, interfaces.REDMetricsQueryParams{
+				TraceAnalyticsQueryParams: interfaces.TraceAnalyticsQueryParams{Start: start, End: end, ServiceName: "order"},
+				Step:                      stepStr,
+				StepMs:                    step,
+				Metrics:                   []string{interfaces.RED_METRIC_ERROR_RATE},
+			}).Return(redResp, nil)
+
+			resp, err := mms.execRED(testCtx, query)
+			So(err, ShouldBeNil)
+			So(*resp.Step, ShouldEqual, stepStr)
+			So(resp.Datas, ShouldResemble, redResp.Datas)
+			So(resp.CurrSeriesNum, ShouldEqual, 1)
+			So(resp.PointTotal, ShouldEqual, 2)
+		})
+
+		Convey("Instant query computes one point over the whole range", func() {
+			query.IsInstantQuery = true
+			tmService.EXPECT().GetTraceModelByID(gomock.Any(), "tm1").Return(traceModel, nil)
+			tmService.EXPECT().GetREDMetrics(gomock.Any(), traceModel, interfaces.REDMetricsQueryParams{
+				TraceAnalyticsQueryParams: interfaces.TraceAnalyticsQueryParams{Start: start, End: end, ServiceName: "order"},
+				StepMs:                    end - start,
+				Metrics:                   []string{interfaces.RED_METRIC_ERROR_RATE},
+				IsInstantQuery:            true,
+			}).Return(interfaces.MetricModelUniResponse{
+				Datas: []interfaces.MetricModelData{{Times: []any{end}, Values: []any{0.5}}},
+			}, nil)
+
+			resp, err := mms.execRED(testCtx, query)
+			So(err, ShouldBeNil)
+			So(resp.PointTotal, ShouldEqual, 1)
+		})
+
+		Convey("Calendar step is not supported", func() {
+			day := interfaces.CALENDAR_STEP_DAY
+			query.StepStr = &day
+
+			_, err := mms.execRED(testCtx, query)
+			So(err.(*rest.HTTPError).HTTPCode, ShouldEqual, http.StatusBadRequest)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_MetricModel_InvalidParameter_Step)
+		})
+
+		Convey("Invalid red metric", func() {
+			query.FormulaConfig = map[string]any{"metric": "latency"}
+
+			_, err := mms.execRED(testCtx, query)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_MetricModel_InvalidParameter_FormulaConfig)
+		})
+
+		Convey("Trace model not found", func() {
+			query.Step = &step
+			query.StepStr = &stepStr
+			expectedErr := errors.New("not found")
+			tmService.EXPECT().GetTraceModelByID(gomock.Any(), "tm1").Return(interfaces.TraceModel{}, expectedErr)
+
+			_, err := mms.execRED(testCtx, query)
+			So(err, ShouldEqual, expectedErr)
+		})
+	})
+}
@@ -0,0 +1,229 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package metric_model
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"uniquery/common"
+	"uniquery/interfaces"
+	"uniquery/logics/promql"
+	"uniquery/logics/promql/parser"
+	"uniquery/logics/result_cache"
+)
+
+// dsl 中依赖前后时间桶的管道聚合，各时间桶的值与查询范围有关，不能按时间桶缓存
+var dslPipelineAggs = []string{"cumulative_sum", "derivative", "serial_diff", "moving_fn", "moving_avg"}
+
+// 指标模型范围查询缓存的规范化查询条件，模型本身的变化由缓存版本（模型更新时间）区分
+type metricModelCacheQuery struct {
+	Step            string   `json:"step"`
+	Filters         []string `json:"filters"`
+	AnalysisDims    []string `json:"analysis_dimensions"`
+	FilterMode      string   `json:"filter_mode"`
+	FillNull        bool     `json:"fill_null"`
+	IgnoringHCTS    bool     `json:"ignoring_hcts"`
+	ConditionStr    string   `json:"condition"`
+	QueryType       string   `json:"query_type"`
+	Formula         string   `json:"formula"`
+	HasMatchPersist bool     `json:"has_match_persist"`
+	DataViewID      string   `json:"data_view_id"`
+	DataViewUpdate  int64    `json:"data_view_update_time"`
+}
+
+// 判断指标模型的查询能否使用结果缓存。
+// 只缓存原子指标的范围查询，排序、分页、同环比、having 过滤以及跨时间桶的计算不缓存
+func (mms *metricModelService) cacheable(query interfaces.MetricModelQuery, metricModel interfaces.MetricModel) bool {
+	if mms.rCache == nil || query.IsInstantQuery || query.IsModelRequest || query.IgnoringMemoryCache {
+		return false
+	}
+	if query.Start == nil || query.End == nil || query.Step == nil || query.StepStr == nil || *query.Step <= 0 {
+		return false
+	}
+	if query.MetricModelID == "" || metricModel.UpdateTime == 0 {
+		return false
+	}
+	if query.RequestMetrics != nil || len(query.OrderByFields) > 0 || query.HavingCondition != nil ||
+		query.Offset != 0 || query.Limit > 0 {
+		return false
+	}
+	if query.MetricType != interfaces.ATOMIC_METRIC {
+		return false
+	}
+
+	switch query.QueryType {
+	case interfaces.PROMQL:
+		expr, err := parser.ParseExpr(context.Background(), query.Formula)
+		if err != nil {
+			return false
+		}
+		return promql.CacheableExpr(expr)
+	case interfaces.DSL:
+		for _, agg := range dslPipelineAggs {
+			if strings.Contains(query.Formula, agg) {
+				return false
+			}
+		}
+		return true
+	case interfaces.DSL_CONFIG:
+		return true
+	default:
+		return false
+	}
+}
+
+// 按时间桶缓存执行指标模型的范围查询，只对缓存之外的首尾区间发起查询
+func (mms *metricModelService) evalWithCache(ctx context.Context, query interfaces.MetricModelQuery,
+	metricModel interfaces.MetricModel, dataView *interfaces.DataView) (interfaces.MetricModelUniResponse, error) {
+
+	key, err := metricModelCacheKey(query, metricModel, dataView)
+	if err != nil {
+		return mms.eval(ctx, query, metricModel, dataView)
+	}
+
+	fixedStart, fixedEnd := correctingTime(query, common.APP_LOCATION)
+	r := interfaces.CacheRange{
+		Start:      *query.Start,
+		End:        *query.End,
+		FixedStart: fixedStart,
+		FixedEnd:   fixedEnd,
+		Step:       *query.Step,
+	}
+
+	// 整段查询时直接返回原始结果
+	var fullResp *interfaces.MetricModelUniResponse
+	vegaDurationMs := int64(0)
+	fetch := func(ctx context.Context, start, end int64) ([]*interfaces.CachedSeries, bool, error) {
+		q := query
+		q.Start = &start
+		q.End = &end
+
+		resp, err := mms.eval(ctx, q, metricModel, dataView)
+		if err != nil {
+			return nil, false, err
+		}
+		vegaDurationMs += resp.VegaDurationMs
+		if start == *query.Start && end == *query.End {
+			fullResp = &resp
+		}
+
+		// 日历步长和变量步长实际执行的时间桶与请求不一致，不缓存
+		series, ok := toCachedSeries(resp.Datas)
+		return series, ok && !resp.IsCalendar && !resp.IsVariable, nil
+	}
+
+	series, err := mms.rCache.RangeQuery(ctx, key, r, fetch)
+	if err != nil {
+		return interfaces.MetricModelUniResponse{}, err
+	}
+	if fullResp != nil {
+		return *fullResp, nil
+	}
+
+	resp := interfaces.MetricModelUniResponse{
+		Datas:          make([]interfaces.MetricModelData, 0, len(series)),
+		Step:           query.StepStr,
+		CurrSeriesNum:  len(series),
+		SeriesTotal:    len(series),
+		VegaDurationMs: vegaDurationMs,
+	}
+	for _, s := range series {
+		times := make([]any, 0, len(s.Times))
+		for _, t := range s.Times {
+			times = append(times, t)
+		}
+		resp.Datas = append(resp.Datas, interfaces.MetricModelData{
+			Labels:   s.Labels,
+			Times:    times,
+			TimeStrs: s.TimeStrs,
+			Values:   s.Values,
+		})
+		resp.PointTotal += len(times)
+	}
+	return resp, nil
+}
+
+// 缓存 key 中的查询条件与顺序无关，过滤条件和分析维度排序后再计算摘要
+func metricModelCacheKey(query interfaces.MetricModelQuery, metricModel interfaces.MetricModel,
+	dataView *interfaces.DataView) (interfaces.ResultCacheKey, error) {
+
+	filters := make([]string, 0, len(query.Filters))
+	for _, filter := range query.Filters {
+		b, err := sonic.Marshal(filter)
+		if err != nil {
+			return interfaces.ResultCacheKey{}, err
+		}
+		filters = append(filters, string(b))
+	}
+	sort.Strings(filters)
+
+	dims := append([]string{}, query.AnalysisDims...)
+	sort.Strings(dims)
+
+	cq := metricModelCacheQuery{
+		Step:            *query.StepStr,
+		Filters:         filters,
+		AnalysisDims:    dims,
+		FilterMode:      query.FilterMode,
+		FillNull:        query.FillNull,
+		IgnoringHCTS:    query.IgnoringHCTS,
+		ConditionStr:    query.ConditionStr,
+		QueryType:       query.QueryType,
+		Formula:         query.Formula,
+		HasMatchPersist: query.HasMatchPersist,
+	}
+	if dataView != nil {
+		cq.DataViewID = dataView.ViewID
+		cq.DataViewUpdate = dataView.UpdateTime
+	}
+
+	hash, err := result_cache.HashQuery(cq)
+	if err != nil {
+		return interfaces.ResultCacheKey{}, err
+	}
+	return interfaces.ResultCacheKey{
+		Scope:   interfaces.ResultCacheScope_MetricModel,
+		ModelID: metricModel.ModelID,
+		Version: metricModel.UpdateTime,
+		Hash:    hash,
+	}, nil
+}
+
+// 转换成缓存的序列，时间点不是整数时间戳时不缓存
+func toCachedSeries(datas []interfaces.MetricModelData) ([]*interfaces.CachedSeries, bool) {
+	series := make([]*interfaces.CachedSeries, 0, len(datas))
+	for _, data := range datas {
+		cs := &interfaces.CachedSeries{
+			Labels:   data.Labels,
+			Times:    make([]int64, 0, len(data.Times)),
+			TimeStrs: data.TimeStrs,
+			Values:   data.Values,
+		}
+		for _, t := range data.Times {
+			switch v := t.(type) {
+			case int64:
+				cs.Times = append(cs.Times, v)
+			case float64:
+				cs.Times = append(cs.Times, int64(v))
+			case json.Number:
+				n, err := v.Int64()
+				if err != nil {
+					return series, false
+				}
+				cs.Times = append(cs.Times, n)
+			default:
+				return series, false
+			}
+		}
+		series = append(series, cs)
+	}
+	return series, true
+}
@@ -0,0 +1,205 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package metric_model
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/common"
+	"uniquery/interfaces"
+	umock "uniquery/interfaces/mock"
+)
+
+func cacheTestQuery() interfaces.MetricModelQuery {
+	start := int64(1700000000000)
+	end := start + 3600000
+	step := int64(60000)
+	stepStr := "1m"
+	return interfaces.MetricModelQuery{
+		QueryTimeParams: interfaces.QueryTimeParams{
+			Start:   &start,
+			End:     &end,
+			Step:    &step,
+			StepStr: &stepStr,
+		},
+		MetricType:    interfaces.ATOMIC_METRIC,
+		QueryType:     interfaces.PROMQL,
+		Formula:       `sum(rate(cpu_usage[5m])) by (host)`,
+		MetricModelID: "m1",
+		AnalysisDims:  []string{"host", "ip"},
+		Filters: []interfaces.Filter{
+			{Name: "host", Operation: "=", Value: "a"},
+			{Name: "ip", Operation: "=", Value: "b"},
+		},
+	}
+}
+
+func Test_MetricModelService_Cacheable(t *testing.T) {
+	Convey("Test cacheable", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mms := &metricModelService{rCache: umock.NewMockResultCache(mockCtrl)}
+		model := interfaces.MetricModel{ModelID: "m1", UpdateTime: 1}
+
+		Convey("Cacheable promql query", func() {
+			So(mms.cacheable(cacheTestQuery(), model), ShouldBeTrue)
+		})
+
+		Convey("Cache disabled", func() {
+			mms.rCache = nil
+			So(mms.cacheable(cacheTestQuery(), model), ShouldBeFalse)
+		})
+
+		Convey("Instant query", func() {
+			query := cacheTestQuery()
+			query.IsInstantQuery = true
+			So(mms.cacheable(query, model), ShouldBeFalse)
+		})
+
+		Convey("Preview without update time", func() {
+			So(mms.cacheable(cacheTestQuery(), interfaces.MetricModel{ModelID: "m1"}), ShouldBeFalse)
+		})
+
+		Convey("Paged or ordered query", func() {
+			query := cacheTestQuery()
+			query.Limit = 10
+			So(mms.cacheable(query, model), ShouldBeFalse)
+
+			query = cacheTestQuery()
+			query.OrderByFields = []interfaces.OrderField{{Name: "value"}}
+			So(mms.cacheable(query, model), ShouldBeFalse)
+		})
+
+		Convey("Promql depends on query range", func() {
+			query := cacheTestQuery()
+			query.Formula = `cumulative_sum(cpu_usage)`
+			So(mms.cacheable(query, model), ShouldBeFalse)
+
+			query.Formula = `cpu_usage @ start()`
+			So(mms.cacheable(query, model), ShouldBeFalse)
+		})
+
+		Convey("Dsl with pipeline aggregation", func() {
+			query := cacheTestQuery()
+			query.QueryType = interfaces.DSL
+			query.Formula = `{"aggs":{"t":{"date_histogram":{"field":"@timestamp"}}}}`
+			So(mms.cacheable(query, model), ShouldBeTrue)
+
+			query.Formula = `{"aggs":{"t":{"date_histogram":{"field":"@timestamp"},"aggs":{"d":{"derivative":{"buckets_path":"v"}}}}}}`
+			So(mms.cacheable(query, model), ShouldBeFalse)
+		})
+
+		Convey("Derived metric", func() {
+			query := cacheTestQuery()
+			query.MetricType = interfaces.DERIVED_METRIC
+			So(mms.cacheable(query, model), ShouldBeFalse)
+		})
+	})
+}
+
+func Test_MetricModelCacheKey(t *testing.T) {
+	Convey("Test metricModelCacheKey", t, func() {
+		model := interfaces.MetricModel{ModelID: "m1", UpdateTime: 1}
+		dataView := &interfaces.DataView{ViewID: "v1", UpdateTime: 2}
+
+		k1, err := metricModelCacheKey(cacheTestQuery(), model, dataView)
+		So(err, ShouldBeNil)
+		So(k1.Scope, ShouldEqual, interfaces.ResultCacheScope_MetricModel)
+		So(k1.ModelID, ShouldEqual, "m1")
+		So(k1.Version, ShouldEqual, 1)
+
+		Convey("Filter and dimension order does not matter", func() {
+			query := cacheTestQuery()
+			query.Filters[0], query.Filters[1] = query.Filters[1], query.Filters[0]
+			query.AnalysisDims = []string{"ip", "host"}
+			k2, err := metricModelCacheKey(query, model, dataView)
+			So(err, ShouldBeNil)
+			So(k2.Hash, ShouldEqual, k1.Hash)
+		})
+
+		Convey("Time range does not matter", func() {
+			query := cacheTestQuery()
+			start := *query.Start - 3600000
+			query.Start = &start
+			k2, err := metricModelCacheKey(query, model, dataView)
+			So(err, ShouldBeNil)
+			So(k2.Hash, ShouldEqual, k1.Hash)
+		})
+
+		Convey("Step and data view matter", func() {
+			query := cacheTestQuery()
+			stepStr := "5m"
+			query.StepStr = &stepStr
+			k2, err := metricModelCacheKey(query, model, dataView)
+			So(err, ShouldBeNil)
+			So(k2.Hash, ShouldNotEqual, k1.Hash)
+
+			k3, err := metricModelCacheKey(cacheTestQuery(), model, &interfaces.DataView{ViewID: "v1", UpdateTime: 3})
+			So(err, ShouldBeNil)
+			So(k3.Hash, ShouldNotEqual, k1.Hash)
+		})
+	})
+}
+
+func Test_MetricModelService_EvalWithCache(t *testing.T) {
+	Convey("Test evalWithCache", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		common.APP_LOCATION = time.UTC
+		rCache := umock.NewMockResultCache(mockCtrl)
+		mms := &metricModelService{rCache: rCache}
+		query := cacheTestQuery()
+		model := interfaces.MetricModel{ModelID: "m1", UpdateTime: 1}
+
+		Convey("Build response from cached series", func() {
+			rCache.EXPECT().RangeQuery(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, key interfaces.ResultCacheKey, r interfaces.CacheRange,
+					fetch interfaces.RangeFetchFunc) ([]*interfaces.CachedSeries, error) {
+
+					So(r.Step, ShouldEqual, 60000)
+					So(r.FixedStart, ShouldEqual, *query.Start/60000*60000)
+					return []*interfaces.CachedSeries{
+						{Labels: map[string]string{"host": "a"}, Times: []int64{r.FixedStart, r.FixedStart + r.Step}, Values: []any{1.0, 2.0}},
+					}, nil
+				})
+
+			resp, err := mms.evalWithCache(testCtx, query, model, &interfaces.DataView{})
+			So(err, ShouldBeNil)
+			So(resp.Step, ShouldEqual, query.StepStr)
+			So(resp.CurrSeriesNum, ShouldEqual, 1)
+			So(resp.PointTotal, ShouldEqual, 2)
+			So(resp.Datas[0].Times[0], ShouldHaveSameTypeAs, int64(0))
+			So(resp.Datas[0].Values, ShouldResemble, []any{1.0, 2.0})
+		})
+	})
+}
+
+func Test_ToCachedSeries(t *testing.T) {
+	Convey("Test toCachedSeries", t, func() {
+		Convey("Integer timestamps", func() {
+			series, ok := toCachedSeries([]interfaces.MetricModelData{
+				{Labels: map[string]string{"a": "1"}, Times: []any{int64(1), float64(2), json.Number("3")}, Values: []any{1, 2, 3}},
+			})
+			So(ok, ShouldBeTrue)
+			So(series[0].Times, ShouldResemble, []int64{1, 2, 3})
+		})
+
+		Convey("Non timestamp times", func() {
+			_, ok := toCachedSeries([]interfaces.MetricModelData{
+				{Times: []any{"2024-01-01"}, Values: []any{1}},
+			})
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package leafnodes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/tidwall/gjson"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+	"uniquery/logics/promql/parser"
+	"uniquery/logics/promql/static"
+)
+
+// RangeFunctionEval 在原始样本点上计算无法用 opensearch 聚合下推的区间向量函数，例如 quantile_over_time、stddev_over_time、deriv。
+// 按毫秒分桶取回 [第一个求值时间点-range, 最后一个求值时间点] 内各序列的原始样本点，再在每个求值时间点 t 的 (t-range, t] 内计算。
+// 每个样本点占一个桶，取回的时间跨度按常用采集间隔估算超过 MAX_RAW_SAMPLES_PER_SERIES 个点时直接返回 400，
+// 实际样本更密导致桶数超过 opensearch 的 search.max_buckets 时同样返回 400。
+// argsAt 返回求值时间点上函数的标量参数
+func (ln *LeafNodes) RangeFunctionEval(ctx context.Context, expr *parser.MatrixSelector, query *interfaces.Query,
+	rangeFn static.RangeFunction, argsAt func(ts int64) []float64) (parser.Value, int, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "Eval 叶子节点 RangeFunctionEval")
+	defer span.End()
+
+	vs, ok := expr.VectorSelector.(*parser.VectorSelector)
+	if !ok {
+		return nil, http.StatusUnprocessableEntity, uerrors.PromQLError{
+			Typ: uerrors.ErrorExec,
+			Err: fmt.Errorf("leafnodes.RangeFunctionEval: invalid expression type %q", expr.VectorSelector.Type()),
+		}
+	}
+
+	selRange := expr.Range.Milliseconds()
+	if expr.Auto && selRange == 0 {
+		// 未指定区间时，即时查询取查询区间，范围查询取步长
+		if query.IsInstantQuery {
+			selRange = query.End - query.Start
+		} else {
+			selRange = query.Interval
+		}
+	}
+
+	evalTimes := []int64{query.End}
+	if !query.IsInstantQuery {
+		evalTimes = static.StepTimes(*query)
+	}
+	if len(evalTimes) == 0 {
+		return static.Matrix{}, http.StatusOK, nil
+	}
+
+	newQuery := *query
+	newQuery.Start = evalTimes[0] - selRange
+	// dsl 的时间范围右开，求值区间包含右端点
+	newQuery.End = evalTimes[len(evalTimes)-1] + 1
+	// 原始样本点的分桶与步长无关，tsid 分批查询时按常用的采集间隔估算每个序列的数据点数
+	newQuery.SubIntervalWith30min = interfaces.DEFAULT_SCRAPE_INTERVAL.Milliseconds()
+	newQuery.SubIntervalWith2h = newQuery.SubIntervalWith30min
+
+	// 按毫秒分桶时桶数即样本点数，时间跨度过大时 opensearch 会因桶数超限报错，提前拒绝
+	maxSpan := interfaces.DEFAULT_SCRAPE_INTERVAL.Milliseconds() * interfaces.MAX_RAW_SAMPLES_PER_SERIES
+	if newQuery.End-newQuery.Start > maxSpan {
+		span.SetStatus(codes.Error, "Raw samples span exceeded")
+		return nil, http.StatusBadRequest, uerrors.PromQLError{
+			Typ: uerrors.ErrorBadData,
+			Err: fmt.Errorf("the function needs raw samples over %s (query window plus range), which exceeds the limit %s, "+
+				"please shorten the range or the query window",
+				time.Duration(newQuery.End-newQuery.Start)*time.Millisecond, time.Duration(maxSpan)*time.Millisecond),
+		}
+	}
+
+	span.SetAttributes(attribute.Key("start").Int64(newQuery.Start),
+		attribute.Key("end").Int64(newQuery.End),
+		attribute.Key("range").Int64(selRange),
+	)
+
+	// 通用处理： 获取日志分组的索引信息 -> 构造 dsl -> 获取索引库下的所有索引以及对应的分片数 -> 执行 dsl
+	result, status, err := ln.commonProcess(ctx, vs, &newQuery, interfaces.RAW_SAMPLES_AGG)
+	if err != nil {
+		// 记录异常的日志
+		o11y.Error(ctx, fmt.Sprintf("Common Process Error: %v", err))
+		span.SetStatus(codes.Error, "Common Process Error")
+
+		if isTooManyBucketsError(err) {
+			return nil, http.StatusBadRequest, uerrors.PromQLError{
+				Typ: uerrors.ErrorBadData,
+				Err: errors.New("too many raw samples in the query window, which exceeds search.max_buckets of opensearch, " +
+					"please shorten the range or the query window, or narrow the series by label matchers"),
+			}
+		}
+		return nil, status, err
+	}
+	matrixResult, ok := result.(static.Matrix)
+	if ok {
+		return matrixResult, status, err
+	}
+
+	mapResult := result.(MapResult)
+	mat := rangeFunctionMerge(mapResult, evalTimes, selRange, rangeFn, argsAt)
+
+	span.SetStatus(codes.Ok, "")
+	if query.IfNeedAllSeries {
+		return mat, http.StatusOK, nil
+	}
+	return static.PageMatrix{Matrix: mat, TotalSeries: mapResult.TotalSeries}, http.StatusOK, nil
+}
+
+// opensearch 聚合桶数超过 search.max_buckets 时返回 too_many_buckets_exception
+func isTooManyBucketsError(err error) bool {
+	return strings.Contains(err.Error(), "too_many_buckets_exception")
+}
+
+// 合并各分片的原始样本点后，在每个求值时间点的区间内计算区间向量函数
+func rangeFunctionMerge(mapResult MapResult, evalTimes []int64, selRange int64,
+	rangeFn static.RangeFunction, argsAt func(ts int64) []float64) static.Matrix {
+
+	keys := make([]string, 0, len(mapResult.LabelsMap))
+	for key := range mapResult.LabelsMap {
+		keys = append(keys, key)
+	}
+	// 对 keys排序，从小到大
+	sort.Strings(keys)
+
+	mat := make(static.Matrix, 0, len(keys))
+	for _, k := range keys {
+		samples := rawSamples(mapResult.TsValueMap[k])
+
+		points := make([]static.Point, 0, len(evalTimes))
+		// 样本点按时间升序，求值区间随时间点后移，左边界只需向后移动
+		left := 0
+		for _, ts := range evalTimes {
+			rangeStart := ts - selRange
+			for left < len(samples) && samples[left].T <= rangeStart {
+				left++
+			}
+			right := left
+			for right < len(samples) && samples[right].T <= ts {
+				right++
+			}
+			if v, ok := rangeFn(samples[left:right], rangeStart, ts, argsAt(ts)); ok {
+				points = append(points, static.Point{T: ts, V: v})
+			}
+		}
+		if len(points) > 0 {
+			mat = append(mat, static.Series{
+				Metric: parseLabelsStr(k, mapResult.LabelsMap),
+				Points: points,
+			})
+		}
+	}
+	return mat
+}
+
+// 合并 k 个分片上按毫秒分桶的样本点，时间相同的只保留一个，按时间升序返回
+func rawSamples(tsArr [][]gjson.Result) []static.Point {
+	pointMap := make(map[int64]float64)
+	for _, tsArri := range tsArr {
+		for _, pointij := range tsArri {
+			pointMap[pointij.Get("key").Int()] = pointij.Get("value.value").Float()
+		}
+	}
+
+	samples := make([]static.Point, 0, len(pointMap))
+	for t, v := range pointMap {
+		samples = append(samples, static.Point{T: t, V: v})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].T < samples[j].T
+	})
+	return samples
+}
@@ -0,0 +1,167 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package leafnodes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tidwall/gjson"
+
+	"uniquery/interfaces"
+	"uniquery/logics/promql/labels"
+	"uniquery/logics/promql/parser"
+	"uniquery/logics/promql/static"
+)
+
+func bucketsOf(points ...static.Point) []gjson.Result {
+	buckets := make([]gjson.Result, 0, len(points))
+	for _, p := range points {
+		buckets = append(buckets, gjson.Result{
+			Type: gjson.JSON,
+			Raw:  fmt.Sprintf(`{"key":%d,"doc_count":1,"value":{"value":%v}}`, p.T, p.V),
+		})
+	}
+	return buckets
+}
+
+func TestRawSamples(t *testing.T) {
+	Convey("test rawSamples merge buckets of shards", t, func() {
+		tsArr := [][]gjson.Result{
+			bucketsOf(static.Point{T: 3000, V: 3}, static.Point{T: 1000, V: 1}),
+			bucketsOf(static.Point{T: 2000, V: 2}, static.Point{T: 1000, V: 1}),
+		}
+
+		So(rawSamples(tsArr), ShouldResemble, []static.Point{
+			{T: 1000, V: 1},
+			{T: 2000, V: 2},
+			{T: 3000, V: 3},
+		})
+	})
+}
+
+func TestRangeFunctionMerge(t *testing.T) {
+	Convey("test rangeFunctionMerge", t, func() {
+		labelsMap := map[string][]*labels.Label{
+			`a="1"`: {{Name: "a", Value: "1"}},
+		}
+		// 每 5s 一个原始样本点，值为秒级时间戳
+		points := make([]static.Point, 0)
+		for ts := int64(5000); ts <= 60000; ts += 5000 {
+			points = append(points, static.Point{T: ts, V: float64(ts / 1000)})
+		}
+		mapResult := MapResult{
+			LabelsMap: labelsMap,
+			TsValueMap: map[string][][]gjson.Result{
+				`a="1"`: {bucketsOf(points...)},
+			},
+		}
+
+		Convey("quantile_over_time over raw samples", func() {
+			argsAt := func(int64) []float64 { return []float64{0.5} }
+			mat := rangeFunctionMerge(mapResult, []int64{30000, 60000}, 30000,
+				static.RangeFunctions["quantile_over_time"], argsAt)
+
+			So(len(mat), ShouldEqual, 1)
+			So(mat[0].Metric, ShouldResemble, labels.Labels{{Name: "a", Value: "1"}})
+			// (0, 30s] 内 6 个点的中位数为 17.5, (30s, 60s] 内为 47.5
+			So(mat[0].Points, ShouldResemble, []static.Point{
+				{T: 30000, V: 17.5},
+				{T: 60000, V: 47.5},
+			})
+		})
+
+		Convey("scalar argument is taken at each eval time", func() {
+			argsAt := func(ts int64) []float64 {
+				if ts == 30000 {
+					return []float64{0}
+				}
+				return []float64{1}
+			}
+			mat := rangeFunctionMerge(mapResult, []int64{30000, 60000}, 30000,
+				static.RangeFunctions["quantile_over_time"], argsAt)
+
+			So(mat[0].Points, ShouldResemble, []static.Point{
+				{T: 30000, V: 5},
+				{T: 60000, V: 60},
+			})
+		})
+
+		Convey("series without points in any range is dropped", func() {
+			argsAt := func(int64) []float64 { return nil }
+			mat := rangeFunctionMerge(mapResult, []int64{120000}, 30000,
+				static.RangeFunctions["stddev_over_time"], argsAt)
+
+			So(len(mat), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestMakeDSLWithRawSamples(t *testing.T) {
+	Convey("test makeDSL with raw samples aggregation", t, func() {
+		expr := parser.VectorSelector{
+			Name: "cpu",
+			LabelMatchers: []*labels.Matcher{
+				{Type: labels.MatchEqual, Name: labels.MetricName, Value: "cpu"},
+			},
+		}
+		query := &interfaces.Query{
+			Start:                0,
+			End:                  60001,
+			Interval:             15000,
+			SubIntervalWith30min: interfaces.DEFAULT_SCRAPE_INTERVAL.Milliseconds(),
+			MaxSearchSeriesSize:  maxSearchSeriesSize,
+		}
+
+		dsl, status, err := makeDSL(expr, query, []string{interfaces.LABELS_STR}, interfaces.RAW_SAMPLES_AGG, nil, false)
+		So(err, ShouldBeNil)
+		So(status, ShouldEqual, http.StatusOK)
+		// 按毫秒分桶，每个桶取样本点的值
+		So(replace(dsl.String()), ShouldContainSubstring, `"fixed_interval":"1ms"`)
+		So(replace(dsl.String()), ShouldContainSubstring, `"max":{"field":"metrics.cpu"}`)
+	})
+}
+
+func TestRangeFunctionEvalSpanLimit(t *testing.T) {
+	Convey("test RangeFunctionEval rejects a raw samples span over the limit", t, func() {
+		ln := &LeafNodes{}
+		expr := &parser.MatrixSelector{
+			VectorSelector: &parser.VectorSelector{
+				Name: "cpu",
+				LabelMatchers: []*labels.Matcher{
+					{Type: labels.MatchEqual, Name: labels.MetricName, Value: "cpu"},
+				},
+			},
+			Range: time.Hour,
+		}
+		maxSpan := interfaces.DEFAULT_SCRAPE_INTERVAL.Milliseconds() * interfaces.MAX_RAW_SAMPLES_PER_SERIES
+		query := &interfaces.Query{
+			Start:      0,
+			End:        maxSpan,
+			FixedStart: 0,
+			FixedEnd:   maxSpan,
+			Interval:   60000,
+		}
+
+		_, status, err := ln.RangeFunctionEval(testCtx, expr, query, static.RangeFunctions["quantile_over_time"],
+			func(ts int64) []float64 { return []float64{0.5} })
+		So(status, ShouldEqual, http.StatusBadRequest)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "please shorten the range or the query window")
+	})
+}
+
+func TestIsTooManyBucketsError(t *testing.T) {
+	Convey("test isTooManyBucketsError", t, func() {
+		So(isTooManyBucketsError(errors.New(`{"error":{"root_cause":[{"type":"too_many_buckets_exception",`+
+			`"reason":"Trying to create too many buckets. Must be less than or equal to: [65535]"}]},"status":503}`)), ShouldBeTrue)
+		So(isTooManyBucketsError(errors.New("connection refused")), ShouldBeFalse)
+	})
+}
@@ -196,6 +196,8 @@ func makeBatchTsidDSL(expr parser.VectorSelector, query *interfaces.Query, group
 	case interfaces.IRATE_AGG, interfaces.RATE_AGG, interfaces.CHANGES_AGG, interfaces.AVG_OVER_TIME, interfaces.SUM_OVER_TIME,
 		interfaces.MAX_OVER_TIME, interfaces.MIN_OVER_TIME, interfaces.COUNT_OVER_TIME:
 		interval = query.SubIntervalWith2h // tsid按2h路由的子步长来查询
+	case interfaces.RAW_SAMPLES_AGG:
+		interval = 1 // 按毫秒分桶，每个桶即一个原始样本点
 	default:
 		interval = query.Interval
 	}
@@ -293,6 +295,8 @@ func makeDSL(expr parser.VectorSelector, query *interfaces.Query,
 		if isTsid {
 			interval = query.SubIntervalWith2h
 		}
+	case interfaces.RAW_SAMPLES_AGG:
+		interval = 1 // 按毫秒分桶，每个桶即一个原始样本点
 	default:
 		interval = query.Interval
 	}
@@ -460,7 +464,7 @@ func makeAggregation(groupFields []string, aggregationType string, metricName st
 							"value_count": {
 								"field": "%s"
 							}`, metricFiled)
-	case interfaces.MAX_OVER_TIME:
+	case interfaces.MAX_OVER_TIME, interfaces.RAW_SAMPLES_AGG:
 		valueAgg = fmt.Sprintf(`
 							"max": {
 								"field": "%s"
@@ -32,6 +32,7 @@ import (
 	"uniquery/logics/promql/parser"
 	"uniquery/logics/promql/static"
 	"uniquery/logics/promql/util"
+	"uniquery/logics/result_cache"
 )
 
 var (
@@ -43,6 +44,7 @@ type promQLService struct {
 	leafNodes  *leafnodes.LeafNodes
 	appSetting *common.AppSetting
 	mmService  interfaces.MetricModelService
+	rCache     interfaces.ResultCache
 }
 
 type QueryData struct {
@@ -64,6 +66,7 @@ func NewPromQLService(appSetting *common.AppSetting, mmService interfaces.Metric
 		ln := leafnodes.NewLeafNodes(appSetting, logics.OSAccess,
 			logics.LGAccess, data_view.NewDataViewService(appSetting))
 		pqlService = NewPromQLServiceRaw(appSetting, ln, mmService)
+		pqlService.(*promQLService).rCache = result_cache.NewResultCache(appSetting)
 
 		// init pool
 		util.InitAntsPool(appSetting.PoolSetting)
@@ -189,8 +192,13 @@ func (ps *promQLService) Exec(ctx context.Context, query interfaces.Query) (resp
 
 	}
 
-	// 执行 query
-	val, status, err := ps.eval(ctx, expr, &query)
+	// 执行 query，可缓存的范围查询只对缓存之外的首尾区间执行
+	var val parser.Value
+	if ps.cacheable(expr, query) {
+		val, status, err = ps.evalWithCache(ctx, expr, query)
+	} else {
+		val, status, err = ps.eval(ctx, expr, &query)
+	}
 	if err != nil {
 		return res, nil, status, err
 	}
@@ -308,6 +316,11 @@ func (ps *promQLService) eval(ctx context.Context, expr parser.Expr, query *inte
 			static.UnwrapParenExpr(&e.Args[i])
 			a := static.UnwrapStepInvariantExpr(e.Args[i])
 			static.UnwrapParenExpr(&a)
+			if sq, ok := a.(*parser.SubqueryExpr); ok {
+				// 子查询的结果不在 opensearch 中，区间向量函数在内存中计算
+				span.SetStatus(codes.Ok, "")
+				return ps.evalSubqueryCall(ctx, e, i, sq, query)
+			}
 			if _, ok := a.(*parser.MatrixSelector); ok {
 				matrixArgIndex = i
 				matrixArg = true
@@ -344,24 +357,60 @@ func (ps *promQLService) eval(ctx context.Context, expr parser.Expr, query *inte
 		arg := static.UnwrapStepInvariantExpr(e.Args[matrixArgIndex])
 		static.UnwrapParenExpr(&arg)
 		sel := arg.(*parser.MatrixSelector)
+		vs, _ := sel.VectorSelector.(*parser.VectorSelector)
 
 		switch e.Func.Name {
 		case interfaces.IRATE_AGG:
 			span.SetStatus(codes.Ok, "")
-			return ps.leafNodes.IrateEval(ctx, sel, []string{interfaces.LABELS_STR}, interfaces.IRATE_AGG, query)
+			return ps.evalWithModifiers(vs, query, func(q *interfaces.Query) (parser.Value, int, error) {
+				return ps.leafNodes.IrateEval(ctx, sel, []string{interfaces.LABELS_STR}, interfaces.IRATE_AGG, q)
+			})
 		case interfaces.RATE_AGG, interfaces.INCREASE_AGG:
 			span.SetStatus(codes.Ok, "")
-			return ps.leafNodes.RateAggs(ctx, sel, query, call)
+			return ps.evalWithModifiers(vs, query, func(q *interfaces.Query) (parser.Value, int, error) {
+				return ps.leafNodes.RateAggs(ctx, sel, q, call)
+			})
 		case interfaces.CHANGES_AGG:
 			span.SetStatus(codes.Ok, "")
-			return ps.leafNodes.ChangesAggs(ctx, sel, query)
+			return ps.evalWithModifiers(vs, query, func(q *interfaces.Query) (parser.Value, int, error) {
+				return ps.leafNodes.ChangesAggs(ctx, sel, q)
+			})
 		case interfaces.DELTA_AGG:
 			span.SetStatus(codes.Ok, "")
-			return ps.leafNodes.DeltaAggs(ctx, sel, query, call)
+			return ps.evalWithModifiers(vs, query, func(q *interfaces.Query) (parser.Value, int, error) {
+				return ps.leafNodes.DeltaAggs(ctx, sel, q, call)
+			})
 		case interfaces.AVG_OVER_TIME, interfaces.SUM_OVER_TIME, interfaces.MAX_OVER_TIME, interfaces.MIN_OVER_TIME, interfaces.COUNT_OVER_TIME:
 			span.SetStatus(codes.Ok, "")
-			return ps.leafNodes.AggOverTime(ctx, sel, query, e.Func.Name)
+			return ps.evalWithModifiers(vs, query, func(q *interfaces.Query) (parser.Value, int, error) {
+				return ps.leafNodes.AggOverTime(ctx, sel, q, e.Func.Name)
+			})
+		case interfaces.PRESENT_OVER_TIME, interfaces.ABSENT_OVER_TIME:
+			// 区间内是否存在数据点由 count_over_time 下推到 opensearch 计算
+			span.SetStatus(codes.Ok, "")
+			val, status, err := ps.evalWithModifiers(vs, query, func(q *interfaces.Query) (parser.Value, int, error) {
+				return ps.leafNodes.AggOverTime(ctx, sel, q, interfaces.COUNT_OVER_TIME)
+			})
+			if err != nil {
+				return nil, status, err
+			}
+			return presentOrAbsent(val, e, query)
 		default:
+			if rangeFn, ok := static.RangeFunctions[e.Func.Name]; ok {
+				// 无法用 opensearch 聚合下推的区间向量函数，在叶子节点取回的原始样本点上计算
+				argsAt, status, err := ps.rangeFunctionArgs(ctx, e, matrixArgIndex, query)
+				if err != nil {
+					return nil, status, err
+				}
+				span.SetStatus(codes.Ok, "")
+				return ps.evalWithModifiers(vs, query, func(q *interfaces.Query) (parser.Value, int, error) {
+					// 带 offset 或 @ 时叶子节点在平移后的时间轴上求值，参数按原时间轴取
+					shift := query.End - q.End
+					return ps.leafNodes.RangeFunctionEval(ctx, sel, q, rangeFn, func(ts int64) []float64 {
+						return argsAt(ts + shift)
+					})
+				})
+			}
 			span.SetStatus(codes.Error, fmt.Sprintf("unhandled expression of type: %T", e.Func.Name))
 			// 记录异常日志
 			o11y.Error(ctx, fmt.Sprintf("unhandled expression of type: %T", e.Func.Name))
@@ -398,7 +447,9 @@ func (ps *promQLService) eval(ctx context.Context, expr parser.Expr, query *inte
 		// 对应采样逻辑：termsAgg 用 __labels_str, valueAgg 用 sampling
 		// 根据叶子节点 VectorSelector 的属性构建 dsl 查询请求.
 		span.SetStatus(codes.Ok, "")
-		return ps.leafNodes.EvalVectorSelector(ctx, e, []string{interfaces.LABELS_STR}, interfaces.SAMPLING_AGG, query)
+		return ps.evalWithModifiers(e, query, func(q *interfaces.Query) (parser.Value, int, error) {
+			return ps.leafNodes.EvalVectorSelector(ctx, e, []string{interfaces.LABELS_STR}, interfaces.SAMPLING_AGG, q)
+		})
 
 	case *parser.StepInvariantExpr:
 		span.SetStatus(codes.Ok, "")
@@ -507,6 +558,302 @@ func (ps *promQLService) evalStepInvariantExpr(ctx context.Context, expr *parser
 	return res, http.StatusOK, nil
 }
 
+// evalWithModifiers 按叶子节点的 offset 和 @ 修饰符调整查询时间后 eval。
+// offset 在 [start-offset, end-offset] 上查询，结果再后移回查询的时间轴；@ 在指定时间做即时查询，结果重复到每个步长上
+func (ps *promQLService) evalWithModifiers(vs *parser.VectorSelector, query *interfaces.Query,
+	evalFn func(*interfaces.Query) (parser.Value, int, error)) (parser.Value, int, error) {
+
+	if vs == nil || (vs.Offset == 0 && vs.Timestamp == nil) {
+		return evalFn(query)
+	}
+
+	newQuery := modifierQuery(*query, vs.Offset, vs.Timestamp)
+	val, status, err := evalFn(&newQuery)
+	if err != nil {
+		return nil, status, err
+	}
+
+	var (
+		mat         static.Matrix
+		totalSeries int
+		isPage      bool
+	)
+	switch result := val.(type) {
+	case static.Matrix:
+		mat = result
+	case static.PageMatrix:
+		mat, totalSeries, isPage = result.Matrix, result.TotalSeries, true
+	default:
+		return nil, http.StatusUnprocessableEntity, uerrors.PromQLError{
+			Typ: uerrors.ErrorExec,
+			Err: fmt.Errorf("unexpected result in offset or @ modifier evaluation: %T", val),
+		}
+	}
+
+	if vs.Timestamp != nil {
+		mat = static.RepeatAtSteps(mat, *query)
+	} else {
+		mat = static.ShiftMatrix(mat, vs.Offset.Milliseconds(), *query)
+	}
+	if isPage {
+		return static.PageMatrix{Matrix: mat, TotalSeries: totalSeries}, status, nil
+	}
+	return mat, status, nil
+}
+
+// modifierQuery 生成按 offset 和 @ 修饰符调整时间后的查询。带 @ 时为 @ 时间上的即时查询，回看区间沿用即时查询的区间
+func modifierQuery(query interfaces.Query, offset time.Duration, at *int64) interfaces.Query {
+	shift := offset.Milliseconds()
+	if at != nil {
+		lookback := query.End - query.Start
+		if !query.IsInstantQuery || lookback <= 0 {
+			lookback = interfaces.DEFAULT_LOOK_BACK_DELTA.Milliseconds()
+		}
+		query.End = *at - shift
+		query.Start = query.End - lookback
+		query.Interval = lookback
+		query.IntervalStr = ""
+		query.IsInstantQuery = true
+		query.IsCalendar = false
+	} else {
+		query.Start -= shift
+		query.End -= shift
+	}
+	query.FixedStart, query.FixedEnd = static.CorrectingTime(query, common.APP_LOCATION)
+	return query
+}
+
+// evalSubqueryCall eval 作用在子查询上的区间向量函数，例如 max_over_time(rate(x[5m])[1h:1m])。
+// 子查询在 [start-offset-range, end-offset] 上按子查询的步长 eval，函数在每个求值时间点 t 的 (t-offset-range, t-offset] 内的数据点上计算
+func (ps *promQLService) evalSubqueryCall(ctx context.Context, call *parser.Call, matrixArgIndex int,
+	sq *parser.SubqueryExpr, query *interfaces.Query) (parser.Value, int, error) {
+
+	fnName := call.Func.Name
+	if fnName == interfaces.ABSENT_OVER_TIME {
+		// absent_over_time 先按 count_over_time 计算，再取没有数据点的时间点
+		fnName = interfaces.COUNT_OVER_TIME
+	}
+	rangeFn, ok := static.RangeFunctions[fnName]
+	if !ok {
+		return nil, http.StatusBadRequest, uerrors.PromQLError{
+			Typ: uerrors.ErrorBadData,
+			Err: fmt.Errorf(" '%s' is not currently supported on subquery. ", call.Func.Name),
+		}
+	}
+	argsAt, status, err := ps.rangeFunctionArgs(ctx, call, matrixArgIndex, query)
+	if err != nil {
+		return nil, status, err
+	}
+
+	// 求值时间点，带 @ 时只在 @ 时间求值一次
+	var evalTimes []int64
+	switch {
+	case sq.Timestamp != nil:
+		evalTimes = []int64{*sq.Timestamp}
+	case query.IsInstantQuery:
+		evalTimes = []int64{query.End}
+	default:
+		evalTimes = static.StepTimes(*query)
+	}
+	if len(evalTimes) == 0 {
+		return static.Matrix{}, http.StatusOK, nil
+	}
+
+	offset, selRange := sq.Offset.Milliseconds(), sq.Range.Milliseconds()
+	step := sq.Step.Milliseconds()
+	if step == 0 {
+		step = interfaces.DEFAULT_SUBQUERY_STEP.Milliseconds()
+	}
+
+	subQuery := *query
+	subQuery.Start = evalTimes[0] - offset - selRange
+	subQuery.End = evalTimes[len(evalTimes)-1] - offset
+	subQuery.Interval = step
+	subQuery.IntervalStr = ""
+	subQuery.IsInstantQuery = false
+	subQuery.IsCalendar = false
+	subQuery.FixedStart, subQuery.FixedEnd = static.CorrectingTime(subQuery, common.APP_LOCATION)
+
+	val, status, err := ps.eval(ctx, sq.Expr, &subQuery)
+	if err != nil {
+		return nil, status, err
+	}
+
+	var (
+		mat         static.Matrix
+		totalSeries int
+		isPage      bool
+	)
+	switch result := val.(type) {
+	case static.Matrix:
+		mat = result
+	case static.PageMatrix:
+		mat, totalSeries, isPage = result.Matrix, result.TotalSeries, true
+	default:
+		return nil, http.StatusUnprocessableEntity, uerrors.PromQLError{
+			Typ: uerrors.ErrorExec,
+			Err: fmt.Errorf("unexpected result in subquery evaluation: %T", val),
+		}
+	}
+
+	res := make(static.Matrix, 0, len(mat))
+	for _, series := range mat {
+		points := make([]static.Point, 0, len(evalTimes))
+		// 序列的数据点按时间升序，求值区间随时间点后移，左边界只需向后移动
+		left := 0
+		for _, ts := range evalTimes {
+			rangeStart, rangeEnd := ts-offset-selRange, ts-offset
+			for left < len(series.Points) && series.Points[left].T <= rangeStart {
+				left++
+			}
+			right := left
+			for right < len(series.Points) && series.Points[right].T <= rangeEnd {
+				right++
+			}
+			argTs := ts
+			if sq.Timestamp != nil {
+				argTs = query.End
+			}
+			if v, ok := rangeFn(series.Points[left:right], rangeStart, rangeEnd, argsAt(argTs)); ok {
+				points = append(points, static.Point{T: ts, V: v})
+			}
+		}
+		if len(points) > 0 {
+			res = append(res, static.Series{Metric: series.Metric, Points: points})
+		}
+	}
+
+	if sq.Timestamp != nil {
+		res = static.RepeatAtSteps(res, *query)
+	}
+	if call.Func.Name == interfaces.ABSENT_OVER_TIME {
+		return presentOrAbsent(res, call, query)
+	}
+	if isPage {
+		return static.PageMatrix{Matrix: res, TotalSeries: totalSeries}, http.StatusOK, nil
+	}
+	return res, http.StatusOK, nil
+}
+
+// rangeFunctionArgs eval 区间向量函数除区间向量外的标量参数，例如 predict_linear(v, 3600) 中的 3600、
+// quantile_over_time(scalar(x), v[5m]) 中的 scalar(x)。返回按求值时间点取参数值的函数，标量在该时间点无值时取 NaN
+func (ps *promQLService) rangeFunctionArgs(ctx context.Context, call *parser.Call, matrixArgIndex int,
+	query *interfaces.Query) (func(ts int64) []float64, int, error) {
+
+	consts := make([]float64, 0, len(call.Args))
+	// 非常量参数在各个求值时间点上的值，key 为参数在 consts 中的位置
+	stepValues := make(map[int]map[int64]float64)
+	for i, arg := range call.Args {
+		if i == matrixArgIndex {
+			continue
+		}
+		a := static.UnwrapStepInvariantExpr(arg)
+		static.UnwrapParenExpr(&a)
+		if num, ok := a.(*parser.NumberLiteral); ok {
+			consts = append(consts, num.Val)
+			continue
+		}
+
+		argQuery := *query
+		argQuery.IfNeedAllSeries = true
+		val, status, err := ps.eval(ctx, a, &argQuery)
+		if err != nil {
+			return nil, status, err
+		}
+		var mat static.Matrix
+		switch result := val.(type) {
+		case static.Matrix:
+			mat = result
+		case static.PageMatrix:
+			mat = result.Matrix
+		default:
+			return nil, http.StatusUnprocessableEntity, uerrors.PromQLError{
+				Typ: uerrors.ErrorExec,
+				Err: fmt.Errorf("unexpected result in argument evaluation of '%s': %T", call.Func.Name, val),
+			}
+		}
+
+		values := make(map[int64]float64)
+		for _, series := range mat {
+			for _, p := range series.Points {
+				if query.IsInstantQuery {
+					// 即时查询只有一个求值时间点
+					p.T = query.End
+				}
+				values[p.T] = p.V
+			}
+		}
+		stepValues[len(consts)] = values
+		consts = append(consts, math.NaN())
+	}
+
+	if len(stepValues) == 0 {
+		return func(int64) []float64 { return consts }, http.StatusOK, nil
+	}
+	return func(ts int64) []float64 {
+		args := make([]float64, len(consts))
+		copy(args, consts)
+		for i, values := range stepValues {
+			if v, ok := values[ts]; ok {
+				args[i] = v
+			}
+		}
+		return args
+	}, http.StatusOK, nil
+}
+
+// presentOrAbsent 由 count_over_time 的结果计算 present_over_time 和 absent_over_time。
+// present_over_time 在有数据点的时间点上取 1；absent_over_time 在所有序列都没有数据点的时间点上输出值为 1 的序列
+func presentOrAbsent(val parser.Value, call *parser.Call, query *interfaces.Query) (parser.Value, int, error) {
+	var (
+		mat         static.Matrix
+		totalSeries int
+		isPage      bool
+	)
+	switch result := val.(type) {
+	case static.Matrix:
+		mat = result
+	case static.PageMatrix:
+		mat, totalSeries, isPage = result.Matrix, result.TotalSeries, true
+	default:
+		return nil, http.StatusUnprocessableEntity, uerrors.PromQLError{
+			Typ: uerrors.ErrorExec,
+			Err: fmt.Errorf("unexpected result in %s evaluation: %T", call.Func.Name, val),
+		}
+	}
+
+	if call.Func.Name == interfaces.PRESENT_OVER_TIME {
+		for i := range mat {
+			for j := range mat[i].Points {
+				mat[i].Points[j].V = 1
+			}
+		}
+		if isPage {
+			return static.PageMatrix{Matrix: mat, TotalSeries: totalSeries}, http.StatusOK, nil
+		}
+		return mat, http.StatusOK, nil
+	}
+
+	present := make(map[int64]bool)
+	for _, series := range mat {
+		for _, p := range series.Points {
+			present[p.T] = true
+		}
+	}
+	points := make([]static.Point, 0)
+	for _, ts := range static.StepTimes(*query) {
+		// 即时查询只有一个求值时间点，有任意数据点即不输出
+		if present[ts] || (query.IsInstantQuery && len(present) > 0) {
+			continue
+		}
+		points = append(points, static.Point{T: ts, V: 1})
+	}
+	if len(points) == 0 {
+		return static.Matrix{}, http.StatusOK, nil
+	}
+	return static.Matrix{{Metric: static.AbsentLabels(call.Args[0]), Points: points}}, http.StatusOK, nil
+}
+
 // eval 二元运算表达式，分为四种情况： 标量 op 标量， 标量 op 向量， 向量 op 标量， 向量 op 向量
 func (ps *promQLService) evalBinaryExpr(ctx context.Context, expr *parser.BinaryExpr, query *interfaces.Query) (parser.Value, int, error) {
 	switch lt, rt := expr.LHS.Type(), expr.RHS.Type(); {
@@ -760,17 +1107,8 @@ func (ps *promQLService) rangeEval(ctx context.Context, query *interfaces.Query,
 	// 时间修正, 同期值和本期值不同,同期值需要把时间置到同期的时间轴上
 	// fixedStart, fixedEnd := static.CorrectingTime(*query, common.APP_LOCATION)
 
-	// 生成完整的时间点
-	allTimes := make([]int64, 0)
-	if query.IsInstantQuery {
-		// 即时查询时start等于end，只有一个数据点
-		allTimes = append(allTimes, query.FixedStart)
-	} else {
-		for currentTime := query.FixedStart; currentTime <= query.FixedEnd; {
-			allTimes = append(allTimes, currentTime)
-			currentTime = static.GetNextPointTime(*query, currentTime)
-		}
-	}
+	// 生成完整的时间点，即时查询时start等于end，只有一个数据点
+	allTimes := static.StepTimes(*query)
 	// numSteps := int((query.FixedEnd-query.FixedStart)/query.Interval) + 1
 
 	// 用fixedstart和fixedend来做遍历
@@ -1171,6 +1509,10 @@ func (ps *promQLService) evalFieldsInfo(ctx context.Context, expr parser.Expr,
 	case *parser.StepInvariantExpr:
 		span.SetStatus(codes.Ok, "")
 		return map[string]bool{}, http.StatusOK, nil
+
+	case *parser.SubqueryExpr:
+		span.SetStatus(codes.Ok, "")
+		return ps.evalFieldsInfo(ctx, e.Expr, query, fieldName)
 	}
 
 	span.SetStatus(codes.Error, fmt.Sprintf("unhandled expression of type: %T", expr))
@@ -1541,6 +1883,9 @@ func (ps *promQLService) evalLabelsInfo(ctx context.Context, expr parser.Expr, q
 	case *parser.MatrixSelector:
 		span.SetStatus(codes.Ok, "")
 		return ps.evalLabelsInfo(ctx, e.VectorSelector, query)
+	case *parser.SubqueryExpr:
+		span.SetStatus(codes.Ok, "")
+		return ps.evalLabelsInfo(ctx, e.Expr, query)
 	}
 
 	span.SetStatus(codes.Error, fmt.Sprintf("unhandled expression of type: %T", expr))
@@ -7036,3 +7036,342 @@ func TestEvalLabelsInfo(t *testing.T) {
 
 	})
 }
+
+func TestEvalSubqueryAndModifiers(t *testing.T) {
+	Convey("test promql_service eval subquery, offset and @ modifiers ", t, func() {
+		common.APP_LOCATION = time.UTC
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		osaMock := umock.NewMockOpenSearchAccess(mockCtrl)
+		lgaMock := umock.NewMockLogGroupAccess(mockCtrl)
+		dvsMock := umock.NewMockDataViewService(mockCtrl)
+		mmsMock := umock.NewMockMetricModelService(mockCtrl)
+		psMock := mockNewPromqlService(osaMock, lgaMock, dvsMock, mmsMock)
+
+		metric := labels.Labels{&labels.Label{Name: "job", Value: "a"}}
+		newQuery := func(queryStr string) *interfaces.Query {
+			return &interfaces.Query{
+				QueryStr:   queryStr,
+				Start:      1655346000000,
+				End:        1655346060000,
+				FixedStart: 1655346000000,
+				FixedEnd:   1655346060000,
+				Interval:   15000,
+				LogGroupId: "a",
+				Limit:      -1,
+			}
+		}
+
+		// 叶子节点在查询时间轴的每个步长上返回一个数据点，值为该点的秒级时间戳，并记录叶子节点收到的查询
+		var leafQuery interfaces.Query
+		ln := &leafnodes.LeafNodes{}
+		patches := ApplyMethod(ln, "EvalVectorSelector", func(_ *leafnodes.LeafNodes, _ context.Context, _ *parser.VectorSelector,
+			_ []string, _ string, q *interfaces.Query) (parser.Value, int, error) {
+			leafQuery = *q
+			times := []int64{q.End}
+			if !q.IsInstantQuery {
+				times = static.StepTimes(*q)
+			}
+			points := make([]static.Point, 0, len(times))
+			for _, ts := range times {
+				points = append(points, static.Point{T: ts, V: float64(ts / 1000)})
+			}
+			return static.Matrix{{Metric: metric, Points: points}}, http.StatusOK, nil
+		})
+		defer patches.Reset()
+
+		eval := func(query *interfaces.Query) static.Matrix {
+			expr, err := parser.ParseExpr(testCtx, query.QueryStr)
+			So(err, ShouldBeNil)
+			expr, err = static.PreprocessExpr(expr, query.Start, query.End)
+			So(err, ShouldBeNil)
+
+			res, status, err := psMock.eval(testCtx, expr, query)
+			So(err, ShouldBeNil)
+			So(status, ShouldEqual, http.StatusOK)
+			mat, ok := res.(static.Matrix)
+			So(ok, ShouldBeTrue)
+			return mat
+		}
+
+		Convey("foo offset 1m", func() {
+			mat := eval(newQuery(`foo offset 1m`))
+
+			So(leafQuery.Start, ShouldEqual, 1655345940000)
+			So(leafQuery.End, ShouldEqual, 1655346000000)
+			So(mat, ShouldResemble, static.Matrix{{Metric: metric, Points: []static.Point{
+				{T: 1655346000000, V: 1655345940}, {T: 1655346015000, V: 1655345955}, {T: 1655346030000, V: 1655345970},
+				{T: 1655346045000, V: 1655345985}, {T: 1655346060000, V: 1655346000},
+			}}})
+		})
+
+		Convey("foo @ 1655346030", func() {
+			mat := eval(newQuery(`foo @ 1655346030`))
+
+			So(leafQuery.IsInstantQuery, ShouldBeTrue)
+			So(leafQuery.End, ShouldEqual, 1655346030000)
+			So(len(mat), ShouldEqual, 1)
+			So(len(mat[0].Points), ShouldEqual, 5)
+			for _, p := range mat[0].Points {
+				So(p.V, ShouldEqual, 1655346030)
+			}
+		})
+
+		Convey("foo @ end() offset 30s in binary expression", func() {
+			mat := eval(newQuery(`foo - foo @ end() offset 30s`))
+
+			So(mat, ShouldResemble, static.Matrix{{Metric: metric, Points: []static.Point{
+				{T: 1655346000000, V: -30}, {T: 1655346015000, V: -15}, {T: 1655346030000, V: 0},
+				{T: 1655346045000, V: 15}, {T: 1655346060000, V: 30},
+			}}})
+		})
+
+		Convey("max_over_time(foo[1m:15s])", func() {
+			mat := eval(newQuery(`max_over_time(foo[1m:15s])`))
+
+			So(leafQuery.Start, ShouldEqual, 1655345940000)
+			So(leafQuery.End, ShouldEqual, 1655346060000)
+			So(leafQuery.Interval, ShouldEqual, 15000)
+			So(mat, ShouldResemble, static.Matrix{{Metric: metric, Points: []static.Point{
+				{T: 1655346000000, V: 1655346000}, {T: 1655346015000, V: 1655346015}, {T: 1655346030000, V: 1655346030},
+				{T: 1655346045000, V: 1655346045}, {T: 1655346060000, V: 1655346060},
+			}}})
+		})
+
+		Convey("count_over_time(foo[1m:15s] offset 1m)", func() {
+			mat := eval(newQuery(`count_over_time(foo[1m:15s] offset 1m)`))
+
+			So(leafQuery.Start, ShouldEqual, 1655345880000)
+			So(leafQuery.End, ShouldEqual, 1655346000000)
+			So(len(mat), ShouldEqual, 1)
+			for _, p := range mat[0].Points {
+				So(p.V, ShouldEqual, 4)
+			}
+		})
+
+		Convey("rate(foo[1m:15s]) @ start()", func() {
+			mat := eval(newQuery(`rate(foo[1m:15s] @ start())`))
+
+			// 每 15s 增长 15，每秒增长 1
+			So(len(mat), ShouldEqual, 1)
+			So(len(mat[0].Points), ShouldEqual, 5)
+			for _, p := range mat[0].Points {
+				So(p.V, ShouldAlmostEqual, 1, 1e-9)
+			}
+		})
+
+		Convey("subquery with default step", func() {
+			eval(newQuery(`sum_over_time(foo[5m:])`))
+			So(leafQuery.Interval, ShouldEqual, interfaces.DEFAULT_SUBQUERY_STEP.Milliseconds())
+		})
+	})
+}
+
+func TestEvalExtendedFunctions(t *testing.T) {
+	Convey("test promql_service eval functions computed in memory ", t, func() {
+		common.APP_LOCATION = time.UTC
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		osaMock := umock.NewMockOpenSearchAccess(mockCtrl)
+		lgaMock := umock.NewMockLogGroupAccess(mockCtrl)
+		dvsMock := umock.NewMockDataViewService(mockCtrl)
+		mmsMock := umock.NewMockMetricModelService(mockCtrl)
+		psMock := mockNewPromqlService(osaMock, lgaMock, dvsMock, mmsMock)
+
+		metric := labels.Labels{&labels.Label{Name: "job", Value: "a"}}
+		newQuery := func(queryStr string) *interfaces.Query {
+			return &interfaces.Query{
+				QueryStr:   queryStr,
+				Start:      1655346000000,
+				End:        1655346060000,
+				FixedStart: 1655346000000,
+				FixedEnd:   1655346060000,
+				Interval:   15000,
+				LogGroupId: "a",
+				Limit:      -1,
+			}
+		}
+		steps := []int64{1655346000000, 1655346015000, 1655346030000, 1655346045000, 1655346060000}
+
+		// foo 在每个步长上返回一个值为秒级时间戳的数据点，其他指标没有数据
+		ln := &leafnodes.LeafNodes{}
+		patches := ApplyMethod(ln, "EvalVectorSelector", func(_ *leafnodes.LeafNodes, _ context.Context, vs *parser.VectorSelector,
+			_ []string, _ string, q *interfaces.Query) (parser.Value, int, error) {
+			if vs.Name != "foo" {
+				return static.Matrix{}, http.StatusOK, nil
+			}
+			points := make([]static.Point, 0)
+			for _, ts := range static.StepTimes(*q) {
+				points = append(points, static.Point{T: ts, V: float64(ts / 1000)})
+			}
+			return static.Matrix{{Metric: metric, Points: points}}, http.StatusOK, nil
+		})
+		defer patches.Reset()
+
+		// count_over_time 下推，foo 的每个步长上有 4 个数据点
+		var aggName string
+		patches.ApplyMethod(ln, "AggOverTime", func(_ *leafnodes.LeafNodes, _ context.Context, sel *parser.MatrixSelector,
+			_ *interfaces.Query, name string) (parser.Value, int, error) {
+			aggName = name
+			if sel.VectorSelector.(*parser.VectorSelector).Name != "foo" {
+				return static.Matrix{}, http.StatusOK, nil
+			}
+			points := make([]static.Point, 0, len(steps))
+			for _, ts := range steps {
+				points = append(points, static.Point{T: ts, V: 4})
+			}
+			return static.Matrix{{Metric: metric, Points: points}}, http.StatusOK, nil
+		})
+
+		// foo 的原始样本点每 5s 一个，值为秒级时间戳。区间向量函数在每个求值时间点 (t-range, t] 内的原始样本点上计算
+		var rangeQuery interfaces.Query
+		patches.ApplyMethod(ln, "RangeFunctionEval", func(_ *leafnodes.LeafNodes, _ context.Context, sel *parser.MatrixSelector,
+			q *interfaces.Query, rangeFn static.RangeFunction, argsAt func(ts int64) []float64) (parser.Value, int, error) {
+			rangeQuery = *q
+			if sel.VectorSelector.(*parser.VectorSelector).Name != "foo" {
+				return static.Matrix{}, http.StatusOK, nil
+			}
+			evalTimes := []int64{q.End}
+			if !q.IsInstantQuery {
+				evalTimes = static.StepTimes(*q)
+			}
+			selRange := sel.Range.Milliseconds()
+			points := make([]static.Point, 0, len(evalTimes))
+			for _, ts := range evalTimes {
+				samples := make([]static.Point, 0)
+				for t := ts - selRange + 5000; t <= ts; t += 5000 {
+					samples = append(samples, static.Point{T: t, V: float64(t / 1000)})
+				}
+				if v, ok := rangeFn(samples, ts-selRange, ts, argsAt(ts)); ok {
+					points = append(points, static.Point{T: ts, V: v})
+				}
+			}
+			return static.Matrix{{Metric: metric, Points: points}}, http.StatusOK, nil
+		})
+
+		eval := func(query *interfaces.Query) static.Matrix {
+			expr, err := parser.ParseExpr(testCtx, query.QueryStr)
+			So(err, ShouldBeNil)
+			expr, err = static.PreprocessExpr(expr, query.Start, query.End)
+			So(err, ShouldBeNil)
+
+			res, status, err := psMock.eval(testCtx, expr, query)
+			So(err, ShouldBeNil)
+			So(status, ShouldEqual, http.StatusOK)
+			mat, ok := res.(static.Matrix)
+			So(ok, ShouldBeTrue)
+			return mat
+		}
+		// expectEachStep 期望结果为 foo 的序列，每个步长上的值由 f 计算
+		expectEachStep := func(mat static.Matrix, f func(ts int64) float64) {
+			So(len(mat), ShouldEqual, 1)
+			So(mat[0].Metric, ShouldResemble, metric)
+			So(len(mat[0].Points), ShouldEqual, len(steps))
+			for i, p := range mat[0].Points {
+				So(p.T, ShouldEqual, steps[i])
+				So(p.V, ShouldAlmostEqual, f(p.T), 1e-6)
+			}
+		}
+
+		Convey("predict_linear(foo[1m], 3600)", func() {
+			mat := eval(newQuery(`predict_linear(foo[1m], 3600)`))
+
+			// 在叶子节点的原始样本点上计算，查询时间轴不变
+			So(rangeQuery.Start, ShouldEqual, 1655346000000)
+			So(rangeQuery.Interval, ShouldEqual, 15000)
+			expectEachStep(mat, func(ts int64) float64 { return float64(ts/1000) + 3600 })
+		})
+
+		Convey("deriv(foo[1m] offset 1m)", func() {
+			mat := eval(newQuery(`deriv(foo[1m] offset 1m)`))
+
+			So(rangeQuery.Start, ShouldEqual, 1655345940000)
+			So(rangeQuery.End, ShouldEqual, 1655346000000)
+			expectEachStep(mat, func(int64) float64 { return 1 })
+		})
+
+		Convey("quantile_over_time(0.5, foo[1m])", func() {
+			// 区间内 12 个原始样本点 t-55 ... t 的中位数
+			mat := eval(newQuery(`quantile_over_time(0.5, foo[1m])`))
+			expectEachStep(mat, func(ts int64) float64 { return float64(ts/1000) - 27.5 })
+		})
+
+		Convey("quantile_over_time with scalar expression argument", func() {
+			// 标量参数在每个求值时间点上求值，scalar(foo) / scalar(foo) / 2 = 0.5
+			mat := eval(newQuery(`quantile_over_time(scalar(foo) / scalar(foo) / 2, foo[1m])`))
+			expectEachStep(mat, func(ts int64) float64 { return float64(ts/1000) - 27.5 })
+
+			// 参数在带 offset 的选择器上仍按原时间轴取值
+			mat = eval(newQuery(`quantile_over_time(scalar(foo) / scalar(foo), foo[1m] offset 1m)`))
+			expectEachStep(mat, func(ts int64) float64 { return float64(ts/1000) - 60 })
+		})
+
+		Convey("stddev_over_time and last_over_time", func() {
+			// 12 个间隔 5s 的等差样本点的标准差
+			mat := eval(newQuery(`stddev_over_time(foo[1m])`))
+			expectEachStep(mat, func(int64) float64 { return math.Sqrt(25.0 * (144 - 1) / 12) })
+
+			mat = eval(newQuery(`last_over_time(foo[1m])`))
+			expectEachStep(mat, func(ts int64) float64 { return float64(ts / 1000) })
+		})
+
+		Convey("holt_winters(foo[1m], 0.5, 0.5)", func() {
+			// 线性增长的序列平滑后仍为最后一个值
+			mat := eval(newQuery(`holt_winters(foo[1m], 0.5, 0.5)`))
+			expectEachStep(mat, func(ts int64) float64 { return float64(ts / 1000) })
+		})
+
+		Convey("resets(foo[1m])", func() {
+			mat := eval(newQuery(`resets(foo[1m])`))
+			expectEachStep(mat, func(int64) float64 { return 0 })
+		})
+
+		Convey("present_over_time(foo[1m])", func() {
+			mat := eval(newQuery(`present_over_time(foo[1m])`))
+
+			So(aggName, ShouldEqual, interfaces.COUNT_OVER_TIME)
+			expectEachStep(mat, func(int64) float64 { return 1 })
+		})
+
+		Convey("absent_over_time", func() {
+			mat := eval(newQuery(`absent_over_time(foo[1m])`))
+			So(len(mat), ShouldEqual, 0)
+
+			mat = eval(newQuery(`absent_over_time(bar{job="b",env=~"prod.*"}[1m])`))
+			So(aggName, ShouldEqual, interfaces.COUNT_OVER_TIME)
+			So(len(mat), ShouldEqual, 1)
+			So(mat[0].Metric, ShouldResemble, labels.Labels{&labels.Label{Name: "job", Value: "b"}})
+			So(len(mat[0].Points), ShouldEqual, len(steps))
+
+			// 作用在子查询上时按 count_over_time 在内存中计算
+			mat = eval(newQuery(`absent_over_time(bar{job="b"}[1m:15s])`))
+			So(len(mat), ShouldEqual, 1)
+			So(len(mat[0].Points), ShouldEqual, len(steps))
+		})
+
+		Convey("absent", func() {
+			mat := eval(newQuery(`absent(foo)`))
+			So(len(mat), ShouldEqual, 0)
+
+			mat = eval(newQuery(`absent(bar{job="b"})`))
+			So(len(mat), ShouldEqual, 1)
+			So(mat[0].Metric, ShouldResemble, labels.Labels{&labels.Label{Name: "job", Value: "b"}})
+			So(len(mat[0].Points), ShouldEqual, len(steps))
+		})
+
+		Convey("scalar argument evaluation failed", func() {
+			// 范围查询不支持 sort
+			query := newQuery(`predict_linear(foo[1m], scalar(sort(foo)))`)
+			expr, err := parser.ParseExpr(testCtx, query.QueryStr)
+			So(err, ShouldBeNil)
+
+			_, status, err := psMock.eval(testCtx, expr, query)
+			So(err, ShouldNotBeNil)
+			So(status, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
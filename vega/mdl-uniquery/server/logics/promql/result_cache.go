@@ -0,0 +1,171 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package promql
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"uniquery/common"
+	"uniquery/interfaces"
+	"uniquery/logics/promql/labels"
+	"uniquery/logics/promql/parser"
+	"uniquery/logics/promql/static"
+	"uniquery/logics/result_cache"
+)
+
+// promql 范围查询缓存的规范化查询条件
+type promqlCacheQuery struct {
+	Expr       string `json:"expr"`
+	LogGroupId string `json:"log_group_id"`
+	Interval   int64  `json:"interval"`
+}
+
+// CacheableExpr 判断表达式在各时间桶上的值是否只与时间桶本身有关。
+// 使用了 @ start()/end()、累计求和、连续 k 分钟宕机以及引用指标模型的表达式，
+// 其结果依赖查询的起止时间或模型版本，不能按时间桶缓存
+func CacheableExpr(expr parser.Expr) bool {
+	cacheable := true
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		switch n := node.(type) {
+		case *parser.VectorSelector:
+			if n.StartOrEnd != 0 {
+				cacheable = false
+			}
+		case *parser.SubqueryExpr:
+			if n.StartOrEnd != 0 {
+				cacheable = false
+			}
+		case *parser.Call:
+			switch n.Func.Name {
+			case interfaces.CUMULATIVE_SUM, interfaces.K_MINUTE_DOWNTIME, interfaces.FUNC_METRIC_MODEL:
+				cacheable = false
+			}
+		}
+		return nil
+	})
+	return cacheable
+}
+
+// 判断 promql 范围查询能否使用结果缓存，指标模型的查询由指标模型自身缓存
+func (ps *promQLService) cacheable(expr parser.Expr, query interfaces.Query) bool {
+	if ps.rCache == nil || query.IsMetricModel || query.IsInstantQuery || query.IsCalendar ||
+		query.IgnoringMemoryCache || query.Interval <= 0 || query.Offset != 0 || query.Limit > 0 ||
+		len(query.Filters) > 0 {
+		return false
+	}
+	if expr.Type() != parser.ValueTypeVector && expr.Type() != parser.ValueTypeScalar {
+		return false
+	}
+	return CacheableExpr(expr)
+}
+
+// 按时间桶缓存执行范围查询，只对缓存之外的首尾区间执行表达式
+func (ps *promQLService) evalWithCache(ctx context.Context, expr parser.Expr, query interfaces.Query) (parser.Value, int, error) {
+	hash, err := result_cache.HashQuery(promqlCacheQuery{
+		Expr:       expr.String(),
+		LogGroupId: query.LogGroupId,
+		Interval:   query.Interval,
+	})
+	if err != nil {
+		return ps.eval(ctx, expr, &query)
+	}
+
+	key := interfaces.ResultCacheKey{
+		Scope: interfaces.ResultCacheScope_PromQL,
+		Hash:  hash,
+	}
+	r := interfaces.CacheRange{
+		Start:      query.Start,
+		End:        query.End,
+		FixedStart: query.FixedStart,
+		FixedEnd:   query.FixedEnd,
+		Step:       query.Interval,
+	}
+
+	status := 0
+	fetch := func(ctx context.Context, start, end int64) ([]*interfaces.CachedSeries, bool, error) {
+		q := query
+		q.Start = start
+		q.End = end
+		q.FixedStart, q.FixedEnd = static.CorrectingTime(q, common.APP_LOCATION)
+
+		val, s, err := ps.eval(ctx, expr, &q)
+		status = s
+		if err != nil {
+			return nil, false, err
+		}
+
+		var mat static.Matrix
+		switch result := val.(type) {
+		case static.Matrix:
+			mat = result
+		case static.PageMatrix:
+			mat = result.Matrix
+		default:
+			return nil, false, nil
+		}
+		return matrixToCachedSeries(mat), true, nil
+	}
+
+	series, err := ps.rCache.RangeQuery(ctx, key, r, fetch)
+	if err != nil {
+		return nil, status, err
+	}
+	return cachedSeriesToMatrix(series), status, nil
+}
+
+// 非有限值在 JSON 中无法表示，按字符串保存
+func matrixToCachedSeries(mat static.Matrix) []*interfaces.CachedSeries {
+	series := make([]*interfaces.CachedSeries, 0, len(mat))
+	for _, s := range mat {
+		cs := &interfaces.CachedSeries{
+			Labels: s.Metric.Map(),
+			Times:  make([]int64, 0, len(s.Points)),
+			Values: make([]any, 0, len(s.Points)),
+		}
+		for _, p := range s.Points {
+			cs.Times = append(cs.Times, p.T)
+			if math.IsNaN(p.V) || math.IsInf(p.V, 0) {
+				cs.Values = append(cs.Values, strconv.FormatFloat(p.V, 'f', -1, 64))
+			} else {
+				cs.Values = append(cs.Values, p.V)
+			}
+		}
+		series = append(series, cs)
+	}
+	return series
+}
+
+func cachedSeriesToMatrix(series []*interfaces.CachedSeries) static.Matrix {
+	mat := make(static.Matrix, 0, len(series))
+	for _, cs := range series {
+		ls := make([]*labels.Label, 0, len(cs.Labels))
+		for name, value := range cs.Labels {
+			ls = append(ls, &labels.Label{Name: name, Value: value})
+		}
+
+		s := static.Series{
+			Metric: labels.New(ls...),
+			Points: make([]static.Point, 0, len(cs.Times)),
+		}
+		for i, t := range cs.Times {
+			var v float64
+			switch value := cs.Values[i].(type) {
+			case float64:
+				v = value
+			case string:
+				v, _ = strconv.ParseFloat(value, 64)
+			default:
+				v = math.NaN()
+			}
+			s.Points = append(s.Points, static.Point{T: t, V: v})
+		}
+		mat = append(mat, s)
+	}
+	return mat
+}
@@ -0,0 +1,95 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package promql
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/interfaces"
+	"uniquery/logics/promql/labels"
+	"uniquery/logics/promql/parser"
+	"uniquery/logics/promql/static"
+)
+
+func Test_CacheableExpr(t *testing.T) {
+	Convey("Test CacheableExpr", t, func() {
+		cases := []struct {
+			query     string
+			cacheable bool
+		}{
+			{`sum(rate(http_requests_total[5m])) by (job)`, true},
+			{`max_over_time(rate(http_requests_total[1m])[10m:1m])`, true},
+			{`http_requests_total offset 1h`, true},
+			{`http_requests_total @ 1700000000`, true},
+			{`http_requests_total @ start()`, false},
+			{`rate(http_requests_total[5m] @ end())`, false},
+			{`max_over_time(rate(http_requests_total[1m])[10m:1m] @ end())`, false},
+			{`cumulative_sum(http_requests_total)`, false},
+		}
+
+		for _, c := range cases {
+			expr, err := parser.ParseExpr(testCtx, c.query)
+			So(err, ShouldBeNil)
+			So(CacheableExpr(expr), ShouldEqual, c.cacheable)
+		}
+	})
+}
+
+func Test_PromQLService_Cacheable(t *testing.T) {
+	Convey("Test promQLService cacheable", t, func() {
+		ps := &promQLService{}
+		expr, _ := parser.ParseExpr(testCtx, `sum(http_requests_total)`)
+		query := interfaces.Query{Start: 1, End: 3600000, Interval: 60000, Limit: -1}
+
+		Convey("Cache disabled", func() {
+			So(ps.cacheable(expr, query), ShouldBeFalse)
+		})
+
+		ps.rCache = &fakeResultCache{}
+		Convey("Range query", func() {
+			So(ps.cacheable(expr, query), ShouldBeTrue)
+		})
+
+		Convey("Metric model query is cached by the metric model", func() {
+			query.IsMetricModel = true
+			So(ps.cacheable(expr, query), ShouldBeFalse)
+		})
+
+		Convey("Query with dashboard filters", func() {
+			query.Filters = []interfaces.Filter{{Name: "job"}}
+			So(ps.cacheable(expr, query), ShouldBeFalse)
+		})
+	})
+}
+
+func Test_CachedSeriesMatrix(t *testing.T) {
+	Convey("Test matrix and cached series conversion", t, func() {
+		mat := static.Matrix{
+			{
+				Metric: labels.FromStrings("job", "a", "instance", "b"),
+				Points: []static.Point{{T: 1, V: 1.5}, {T: 2, V: math.Inf(1)}, {T: 3, V: math.NaN()}},
+			},
+		}
+
+		series := matrixToCachedSeries(mat)
+		So(series[0].Labels, ShouldResemble, map[string]string{"job": "a", "instance": "b"})
+		So(series[0].Values, ShouldResemble, []any{1.5, "+Inf", "NaN"})
+
+		back := cachedSeriesToMatrix(series)
+		So(labels.Compare(back[0].Metric, mat[0].Metric), ShouldEqual, 0)
+		So(back[0].Points[0], ShouldResemble, static.Point{T: 1, V: 1.5})
+		So(math.IsInf(back[0].Points[1].V, 1), ShouldBeTrue)
+		So(math.IsNaN(back[0].Points[2].V), ShouldBeTrue)
+	})
+}
+
+// fakeResultCache 只用于判断缓存是否开启
+type fakeResultCache struct {
+	interfaces.ResultCache
+}
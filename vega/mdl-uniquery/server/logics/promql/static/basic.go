@@ -535,6 +535,23 @@ func PreprocessExpr(expr parser.Expr, start, end int64) (parser.Expr, error) {
 func preprocessExprHelper(expr parser.Expr, start, end int64) (bool, error) {
 	switch n := expr.(type) {
 	case *parser.VectorSelector:
+		n.Offset = n.OriginalOffset
+		if n.StartOrEnd == parser.START {
+			n.Timestamp = makeInt64Pointer(start)
+		} else if n.StartOrEnd == parser.END {
+			n.Timestamp = makeInt64Pointer(end)
+		}
+		return n.Timestamp != nil, nil
+
+	case *parser.MatrixSelector:
+		return preprocessExprHelper(n.VectorSelector, start, end)
+
+	case *parser.SubqueryExpr:
+		// 子查询内部在子查询自身的时间轴上 eval，内部节点只做预处理，结果不影响子查询是否步长不变
+		if _, err := preprocessExprHelper(n.Expr, start, end); err != nil {
+			return false, err
+		}
+		n.Offset = n.OriginalOffset
 		if n.StartOrEnd == parser.START {
 			n.Timestamp = makeInt64Pointer(start)
 		} else if n.StartOrEnd == parser.END {
@@ -602,6 +619,14 @@ func preprocessExprHelper(expr parser.Expr, start, end int64) (bool, error) {
 		//		n.Args[i] = newStepInvariantExpr(n.Args[i])
 		//	}
 		//}
+
+		// 参数只解析 offset 和 @ start()/end()，函数本身不封装为步长不变，
+		// 带 @ 的叶子节点和子查询在 eval 时会把结果重复到每个步长上
+		for i := range n.Args {
+			if _, err := preprocessExprHelper(n.Args[i], start, end); err != nil {
+				return false, err
+			}
+		}
 		return false, nil
 
 	default:
@@ -1460,3 +1485,67 @@ func GetNextPointTime(query interfaces.Query, currentTime int64) int64 {
 
 	return 0
 }
+
+// StepTimes 生成查询时间轴上的所有时间点，即时查询只有一个时间点
+func StepTimes(query interfaces.Query) []int64 {
+	if query.IsInstantQuery {
+		return []int64{query.FixedStart}
+	}
+
+	allTimes := make([]int64, 0)
+	for currentTime := query.FixedStart; currentTime <= query.FixedEnd; {
+		allTimes = append(allTimes, currentTime)
+		currentTime = GetNextPointTime(query, currentTime)
+	}
+	return allTimes
+}
+
+// ShiftMatrix 把在 [start-offset, end-offset] 上查询的结果后移 offset，放回查询的时间轴上，用于 offset 修饰符。
+// 时间点 T 上的值是 (T-step, T] 内的数据，offset 不是步长的整数倍时，后移后的时间向后对齐到时间轴上。
+func ShiftMatrix(mat Matrix, offset int64, query interfaces.Query) Matrix {
+	align := !query.IsInstantQuery && !query.IsCalendar && query.Interval > 0
+
+	res := make(Matrix, 0, len(mat))
+	for _, series := range mat {
+		points := make([]Point, 0, len(series.Points))
+		for _, p := range series.Points {
+			t := p.T + offset
+			if align {
+				if rem := (t - query.FixedStart) % query.Interval; rem != 0 {
+					if rem > 0 {
+						t += query.Interval - rem
+					} else {
+						t -= rem
+					}
+				}
+				if t < query.FixedStart || t > query.FixedEnd {
+					continue
+				}
+			}
+			points = append(points, Point{T: t, V: p.V})
+		}
+		if len(points) > 0 {
+			res = append(res, Series{Metric: series.Metric, Points: points})
+		}
+	}
+	return res
+}
+
+// RepeatAtSteps 用每个序列的最后一个数据点填满查询时间轴，用于 @ 修饰符：表达式在固定时间求值，结果与步长无关
+func RepeatAtSteps(mat Matrix, query interfaces.Query) Matrix {
+	allTimes := StepTimes(query)
+
+	res := make(Matrix, 0, len(mat))
+	for _, series := range mat {
+		if len(series.Points) == 0 {
+			continue
+		}
+		v := series.Points[len(series.Points)-1].V
+		points := make([]Point, 0, len(allTimes))
+		for _, ts := range allTimes {
+			points = append(points, Point{T: ts, V: v})
+		}
+		res = append(res, Series{Metric: series.Metric, Points: points})
+	}
+	return res
+}
@@ -4023,3 +4023,99 @@ func TestCalculateUnavailableTime(t *testing.T) {
 		})
 	})
 }
+
+func TestPreprocessExprModifiers(t *testing.T) {
+	Convey("Test PreprocessExpr resolves offset and @ modifiers", t, func() {
+		start, end := int64(1655346000000), int64(1655346060000)
+
+		Convey("offset on vector selector", func() {
+			expr, err := parser.ParseExpr(testCtx, "foo offset 1d")
+			So(err, ShouldBeNil)
+			expr, err = PreprocessExpr(expr, start, end)
+			So(err, ShouldBeNil)
+
+			vs := expr.(*parser.VectorSelector)
+			So(vs.Offset, ShouldEqual, 24*time.Hour)
+		})
+
+		Convey("@ end() and offset inside range function", func() {
+			expr, err := parser.ParseExpr(testCtx, "rate(foo[5m] @ end() offset 1h)")
+			So(err, ShouldBeNil)
+			expr, err = PreprocessExpr(expr, start, end)
+			So(err, ShouldBeNil)
+
+			// 函数本身不封装为步长不变
+			call := expr.(*parser.Call)
+			vs := call.Args[0].(*parser.MatrixSelector).VectorSelector.(*parser.VectorSelector)
+			So(vs.Offset, ShouldEqual, time.Hour)
+			So(*vs.Timestamp, ShouldEqual, end)
+		})
+
+		Convey("subquery with @ start()", func() {
+			expr, err := parser.ParseExpr(testCtx, "max_over_time(rate(foo[5m] offset 1m)[1h:1m] @ start() offset 1d)")
+			So(err, ShouldBeNil)
+			expr, err = PreprocessExpr(expr, start, end)
+			So(err, ShouldBeNil)
+
+			sq := expr.(*parser.Call).Args[0].(*parser.SubqueryExpr)
+			So(sq.Offset, ShouldEqual, 24*time.Hour)
+			So(*sq.Timestamp, ShouldEqual, start)
+			So(sq.Step, ShouldEqual, time.Minute)
+
+			inner := sq.Expr.(*parser.Call).Args[0].(*parser.MatrixSelector).VectorSelector.(*parser.VectorSelector)
+			So(inner.Offset, ShouldEqual, time.Minute)
+			So(inner.Timestamp, ShouldBeNil)
+		})
+	})
+}
+
+func TestStepTimes(t *testing.T) {
+	Convey("Test StepTimes", t, func() {
+		query := interfaces.Query{FixedStart: 1655346000000, FixedEnd: 1655346060000, Interval: 15000}
+		So(StepTimes(query), ShouldResemble, []int64{1655346000000, 1655346015000, 1655346030000, 1655346045000, 1655346060000})
+
+		query.IsInstantQuery = true
+		So(StepTimes(query), ShouldResemble, []int64{1655346000000})
+	})
+}
+
+func TestShiftMatrix(t *testing.T) {
+	Convey("Test ShiftMatrix", t, func() {
+		query := interfaces.Query{FixedStart: 1655346000000, FixedEnd: 1655346060000, Interval: 15000}
+		metric := labels.Labels{&labels.Label{Name: "job", Value: "a"}}
+
+		Convey("offset is a multiple of step", func() {
+			mat := Matrix{{Metric: metric, Points: []Point{{T: 1655345940000, V: 1}, {T: 1655345955000, V: 2}}}}
+			So(ShiftMatrix(mat, 60000, query), ShouldResemble, Matrix{
+				{Metric: metric, Points: []Point{{T: 1655346000000, V: 1}, {T: 1655346015000, V: 2}}},
+			})
+		})
+
+		Convey("offset is not a multiple of step, points align to the next step", func() {
+			mat := Matrix{{Metric: metric, Points: []Point{{T: 1655345985000, V: 1}, {T: 1655346045000, V: 2}, {T: 1655346060000, V: 3}}}}
+			So(ShiftMatrix(mat, 10000, query), ShouldResemble, Matrix{
+				{Metric: metric, Points: []Point{{T: 1655346000000, V: 1}, {T: 1655346060000, V: 2}}},
+			})
+		})
+
+		Convey("series without points in range are dropped", func() {
+			mat := Matrix{{Metric: metric, Points: []Point{{T: 1655345900000, V: 1}}}}
+			So(ShiftMatrix(mat, 15000, query), ShouldResemble, Matrix{})
+		})
+	})
+}
+
+func TestRepeatAtSteps(t *testing.T) {
+	Convey("Test RepeatAtSteps", t, func() {
+		query := interfaces.Query{FixedStart: 1655346000000, FixedEnd: 1655346030000, Interval: 15000}
+		metric := labels.Labels{&labels.Label{Name: "job", Value: "a"}}
+		mat := Matrix{
+			{Metric: metric, Points: []Point{{T: 1655345000000, V: 1}, {T: 1655345015000, V: 2}}},
+			{Metric: labels.Labels{}, Points: []Point{}},
+		}
+
+		So(RepeatAtSteps(mat, query), ShouldResemble, Matrix{
+			{Metric: metric, Points: []Point{{T: 1655346000000, V: 2}, {T: 1655346015000, V: 2}, {T: 1655346030000, V: 2}}},
+		})
+	})
+}
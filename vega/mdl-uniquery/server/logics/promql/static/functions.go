@@ -1246,6 +1246,174 @@ func (f funcMetricModel) Call(vals []parser.Value, args parser.Expressions, enh
 	return enh.Out
 }
 
+// === round(Vector parser.ValueTypeVector, toNearest=1 Scalar) Vector ===
+type funcRound struct{}
+
+func (f funcRound) New(args parser.Expressions) FunctionCall {
+	return &funcRound{}
+}
+func (f funcRound) Call(vals []parser.Value, args parser.Expressions, enh *EvalNodeHelper) Vector {
+	// round returns a number rounded to toNearest.
+	// Ties are solved by rounding up.
+	toNearest := float64(1)
+	if len(args) >= 2 {
+		toNearest = vals[1].(Vector)[0].V
+	}
+	// Invert as it seems to cause fewer floating point accuracy issues.
+	toNearestInverse := 1.0 / toNearest
+
+	return simpleFunc(vals, enh, func(v float64) float64 {
+		return math.Floor(v*toNearestInverse+0.5) / toNearestInverse
+	})
+}
+
+// === sgn(Vector parser.ValueTypeVector) Vector ===
+type funcSgn struct{}
+
+func (f funcSgn) New(args parser.Expressions) FunctionCall {
+	return &funcSgn{}
+}
+func (f funcSgn) Call(vals []parser.Value, args parser.Expressions, enh *EvalNodeHelper) Vector {
+	return simpleFunc(vals, enh, func(v float64) float64 {
+		if v < 0 {
+			return -1
+		} else if v > 0 {
+			return 1
+		}
+		return v
+	})
+}
+
+// === 三角函数，例如 sin(Vector parser.ValueTypeVector) Vector ===
+type funcTrigonometric struct {
+	f func(float64) float64
+}
+
+func (f funcTrigonometric) New(args parser.Expressions) FunctionCall {
+	return &funcTrigonometric{f: f.f}
+}
+func (f funcTrigonometric) Call(vals []parser.Value, args parser.Expressions, enh *EvalNodeHelper) Vector {
+	return simpleFunc(vals, enh, f.f)
+}
+
+// === pi() Scalar ===
+type funcPi struct{}
+
+func (f funcPi) New(args parser.Expressions) FunctionCall {
+	return &funcPi{}
+}
+func (f funcPi) Call(vals []parser.Value, args parser.Expressions, enh *EvalNodeHelper) Vector {
+	return Vector{Sample{Point: Point{V: math.Pi}}}
+}
+
+// === timestamp(Vector parser.ValueTypeVector) Vector ===
+type funcTimestamp struct{}
+
+func (f funcTimestamp) New(args parser.Expressions) FunctionCall {
+	return &funcTimestamp{}
+}
+func (f funcTimestamp) Call(vals []parser.Value, args parser.Expressions, enh *EvalNodeHelper) Vector {
+	for _, el := range vals[0].(Vector) {
+		enh.Out = append(enh.Out, Sample{
+			Metric: el.Metric,
+			Point:  Point{V: float64(el.T) / 1000},
+		})
+	}
+	return enh.Out
+}
+
+// === vector(s Scalar) Vector ===
+type funcVector struct{}
+
+func (f funcVector) New(args parser.Expressions) FunctionCall {
+	return &funcVector{}
+}
+func (f funcVector) Call(vals []parser.Value, args parser.Expressions, enh *EvalNodeHelper) Vector {
+	return append(enh.Out, Sample{
+		Metric: labels.Labels{},
+		Point:  Point{V: vals[0].(Vector)[0].V},
+	})
+}
+
+// === scalar(Vector parser.ValueTypeVector) Scalar ===
+type funcScalar struct{}
+
+func (f funcScalar) New(args parser.Expressions) FunctionCall {
+	return &funcScalar{}
+}
+func (f funcScalar) Call(vals []parser.Value, args parser.Expressions, enh *EvalNodeHelper) Vector {
+	v := vals[0].(Vector)
+	if len(v) != 1 {
+		return append(enh.Out, Sample{Point: Point{V: math.NaN()}})
+	}
+	return append(enh.Out, Sample{Point: Point{V: v[0].V}})
+}
+
+// === absent(Vector parser.ValueTypeVector) Vector ===
+type funcAbsent struct{}
+
+func (f funcAbsent) New(args parser.Expressions) FunctionCall {
+	return &funcAbsent{}
+}
+func (f funcAbsent) Call(vals []parser.Value, args parser.Expressions, enh *EvalNodeHelper) Vector {
+	if len(vals[0].(Vector)) > 0 {
+		return enh.Out
+	}
+	return append(enh.Out, Sample{
+		Metric: AbsentLabels(args[0]),
+		Point:  Point{V: 1},
+	})
+}
+
+// AbsentLabels absent 与 absent_over_time 的结果标签：取选择器中的等值匹配条件，同名标签有多个匹配条件时不输出
+func AbsentLabels(expr parser.Expr) labels.Labels {
+	UnwrapParenExpr(&expr)
+	expr = UnwrapStepInvariantExpr(expr)
+	UnwrapParenExpr(&expr)
+
+	var lm []*labels.Matcher
+	switch n := expr.(type) {
+	case *parser.VectorSelector:
+		lm = n.LabelMatchers
+	case *parser.MatrixSelector:
+		if vs, ok := n.VectorSelector.(*parser.VectorSelector); ok {
+			lm = vs.LabelMatchers
+		}
+	case *parser.SubqueryExpr:
+		return AbsentLabels(n.Expr)
+	default:
+		return labels.Labels{}
+	}
+
+	m := make(map[string]string, len(lm))
+	for _, ma := range lm {
+		if ma.Name == labels.MetricName {
+			continue
+		}
+		if _, exist := m[ma.Name]; ma.Type == labels.MatchEqual && !exist {
+			m[ma.Name] = ma.Value
+		} else {
+			delete(m, ma.Name)
+		}
+	}
+	ls := make(labels.Labels, 0, len(m))
+	for name, value := range m {
+		ls = append(ls, &labels.Label{Name: name, Value: value})
+	}
+	return ls.Sort()
+}
+
+// === quantile_over_time, stddev_over_time, deriv, predict_linear 等区间向量函数 ===
+type funcRangeFunction struct{}
+
+func (f funcRangeFunction) New(args parser.Expressions) FunctionCall {
+	return &funcRangeFunction{}
+}
+func (f funcRangeFunction) Call(vals []parser.Value, args parser.Expressions, enh *EvalNodeHelper) Vector {
+	// 返回空，这些函数在 RangeFunctions 中按区间内的数据点计算，不回调此函数。
+	return Vector{}
+}
+
 // FunctionCalls is a list of all functions supported by PromQL, including their types.
 var FunctionCalls = map[string]FunctionCall{
 	"abs":                          &funcAbs{},
@@ -1284,4 +1452,35 @@ var FunctionCalls = map[string]FunctionCall{
 	"clamp_min":                    &funcClampMin{},
 	"continuous_k_minute_downtime": &funcKMinuteDowntime{},
 	"metric_model":                 &funcMetricModel{},
+	"round":                        &funcRound{},
+	"sgn":                          &funcSgn{},
+	"timestamp":                    &funcTimestamp{},
+	"vector":                       &funcVector{},
+	"scalar":                       &funcScalar{},
+	"absent":                       &funcAbsent{},
+	"pi":                           &funcPi{},
+	"acos":                         &funcTrigonometric{f: math.Acos},
+	"acosh":                        &funcTrigonometric{f: math.Acosh},
+	"asin":                         &funcTrigonometric{f: math.Asin},
+	"asinh":                        &funcTrigonometric{f: math.Asinh},
+	"atan":                         &funcTrigonometric{f: math.Atan},
+	"atanh":                        &funcTrigonometric{f: math.Atanh},
+	"cos":                          &funcTrigonometric{f: math.Cos},
+	"cosh":                         &funcTrigonometric{f: math.Cosh},
+	"sin":                          &funcTrigonometric{f: math.Sin},
+	"sinh":                         &funcTrigonometric{f: math.Sinh},
+	"tan":                          &funcTrigonometric{f: math.Tan},
+	"tanh":                         &funcTrigonometric{f: math.Tanh},
+	"deg":                          &funcTrigonometric{f: func(v float64) float64 { return v * 180 / math.Pi }},
+	"rad":                          &funcTrigonometric{f: func(v float64) float64 { return v * math.Pi / 180 }},
+	"quantile_over_time":           &funcRangeFunction{},
+	"stddev_over_time":             &funcRangeFunction{},
+	"stdvar_over_time":             &funcRangeFunction{},
+	"last_over_time":               &funcRangeFunction{},
+	"present_over_time":            &funcRangeFunction{},
+	"absent_over_time":             &funcRangeFunction{},
+	"deriv":                        &funcRangeFunction{},
+	"predict_linear":               &funcRangeFunction{},
+	"holt_winters":                 &funcRangeFunction{},
+	"resets":                       &funcRangeFunction{},
 }
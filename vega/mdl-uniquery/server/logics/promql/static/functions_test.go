@@ -1112,3 +1112,157 @@ func TestFuncDictValues(t *testing.T) {
 
 	})
 }
+
+func TestFuncMathExtended(t *testing.T) {
+	vals := []parser.Value{Vector{
+		{
+			Point:  Point{T: 1650883566000, V: -2.5},
+			Metric: labels.Labels{&labels.Label{Name: "name", Value: keyNode1}},
+		},
+		{
+			Point:  Point{T: 1650883566000, V: 0.5},
+			Metric: labels.Labels{&labels.Label{Name: "name", Value: "node-2"}},
+		},
+	}}
+	args := parser.Expressions{&parser.VectorSelector{}}
+
+	Convey("test extended math functions", t, func() {
+		Convey("invoke round", func() {
+			enh := &EvalNodeHelper{Out: Vector{}}
+			vec := FunctionCalls["round"].New(args).Call(vals, args, enh)
+			// 与 prometheus 一致，.5 向上取整
+			So(vec[0].V, ShouldEqual, -2)
+			So(vec[1].V, ShouldEqual, 1)
+		})
+
+		Convey("invoke round with toNearest", func() {
+			roundArgs := parser.Expressions{&parser.VectorSelector{}, &parser.NumberLiteral{Val: 2}}
+			roundVals := append(vals, Vector{{Point: Point{V: 2}}})
+			enh := &EvalNodeHelper{Out: Vector{}}
+			vec := FunctionCalls["round"].New(roundArgs).Call(roundVals, roundArgs, enh)
+			So(vec[0].V, ShouldEqual, -2)
+			So(vec[1].V, ShouldEqual, 0)
+		})
+
+		Convey("invoke sgn", func() {
+			enh := &EvalNodeHelper{Out: Vector{}}
+			vec := FunctionCalls["sgn"].New(args).Call(vals, args, enh)
+			So(vec[0].V, ShouldEqual, -1)
+			So(vec[1].V, ShouldEqual, 1)
+		})
+
+		Convey("invoke trigonometric functions", func() {
+			tests := map[string]func(float64) float64{
+				"sin": math.Sin, "cos": math.Cos, "tan": math.Tan,
+				"asin": math.Asin, "acos": math.Acos, "atan": math.Atan,
+				"sinh": math.Sinh, "cosh": math.Cosh, "tanh": math.Tanh,
+				"asinh": math.Asinh, "acosh": math.Acosh, "atanh": math.Atanh,
+			}
+			for name, f := range tests {
+				enh := &EvalNodeHelper{Out: Vector{}}
+				vec := FunctionCalls[name].New(args).Call(vals, args, enh)
+				So(vec[1].Metric, ShouldResemble, labels.Labels{&labels.Label{Name: "name", Value: "node-2"}})
+				if math.IsNaN(f(0.5)) {
+					So(math.IsNaN(vec[1].V), ShouldBeTrue)
+				} else {
+					So(vec[1].V, ShouldEqual, f(0.5))
+				}
+			}
+		})
+
+		Convey("invoke deg and rad", func() {
+			enh := &EvalNodeHelper{Out: Vector{}}
+			vec := FunctionCalls["deg"].New(args).Call([]parser.Value{Vector{{Point: Point{V: math.Pi}}}}, args, enh)
+			So(vec[0].V, ShouldEqual, 180)
+
+			enh = &EvalNodeHelper{Out: Vector{}}
+			vec = FunctionCalls["rad"].New(args).Call([]parser.Value{Vector{{Point: Point{V: 180}}}}, args, enh)
+			So(vec[0].V, ShouldEqual, math.Pi)
+		})
+
+		Convey("invoke pi", func() {
+			vec := FunctionCalls["pi"].New(nil).Call(nil, nil, &EvalNodeHelper{Out: Vector{}})
+			So(vec[0].V, ShouldEqual, math.Pi)
+		})
+	})
+}
+
+func TestFuncTypeConversion(t *testing.T) {
+	args := parser.Expressions{&parser.VectorSelector{}}
+
+	Convey("test timestamp, vector and scalar", t, func() {
+		Convey("invoke timestamp", func() {
+			vals := []parser.Value{Vector{{
+				Point:  Point{T: 1650883566000, V: 3},
+				Metric: labels.Labels{&labels.Label{Name: "name", Value: keyNode1}},
+			}}}
+			vec := FunctionCalls["timestamp"].New(args).Call(vals, args, &EvalNodeHelper{Out: Vector{}})
+			So(vec[0].V, ShouldEqual, 1650883566)
+			So(vec[0].Metric, ShouldResemble, labels.Labels{&labels.Label{Name: "name", Value: keyNode1}})
+		})
+
+		Convey("invoke vector", func() {
+			vals := []parser.Value{Vector{{Point: Point{V: 3}}}}
+			vec := FunctionCalls["vector"].New(args).Call(vals, args, &EvalNodeHelper{Out: Vector{}})
+			So(vec, ShouldResemble, Vector{{Metric: labels.Labels{}, Point: Point{V: 3}}})
+		})
+
+		Convey("invoke scalar", func() {
+			vals := []parser.Value{Vector{{Point: Point{V: 3}, Metric: labels.Labels{&labels.Label{Name: "a", Value: "1"}}}}}
+			vec := FunctionCalls["scalar"].New(args).Call(vals, args, &EvalNodeHelper{Out: Vector{}})
+			So(vec[0].V, ShouldEqual, 3)
+
+			// 不是恰好一个元素时为 NaN
+			vals = []parser.Value{Vector{{Point: Point{V: 3}}, {Point: Point{V: 4}}}}
+			vec = FunctionCalls["scalar"].New(args).Call(vals, args, &EvalNodeHelper{Out: Vector{}})
+			So(math.IsNaN(vec[0].V), ShouldBeTrue)
+		})
+	})
+}
+
+func TestFuncAbsent(t *testing.T) {
+	Convey("test absent", t, func() {
+		Convey("vector is not empty", func() {
+			args := parser.Expressions{&parser.VectorSelector{}}
+			vals := []parser.Value{Vector{{Point: Point{V: 1}}}}
+			vec := FunctionCalls["absent"].New(args).Call(vals, args, &EvalNodeHelper{Out: Vector{}})
+			So(len(vec), ShouldEqual, 0)
+		})
+
+		Convey("vector is empty", func() {
+			expr, err := parser.ParseExpr(testCtx, `absent(foo{job="api",instance="a"})`)
+			So(err, ShouldBeNil)
+			args := expr.(*parser.Call).Args
+			vec := FunctionCalls["absent"].New(args).Call([]parser.Value{Vector{}}, args, &EvalNodeHelper{Out: Vector{}})
+			So(vec, ShouldResemble, Vector{{
+				Metric: labels.Labels{
+					&labels.Label{Name: "instance", Value: "a"},
+					&labels.Label{Name: "job", Value: "api"},
+				},
+				Point: Point{V: 1},
+			}})
+		})
+	})
+
+	Convey("test AbsentLabels", t, func() {
+		tests := []struct {
+			input    string
+			expected labels.Labels
+		}{
+			{input: `foo`, expected: labels.Labels{}},
+			{input: `foo{job="api"}`, expected: labels.Labels{&labels.Label{Name: "job", Value: "api"}}},
+			// 非等值匹配以及同名标签的多个匹配条件不输出
+			{input: `{__name__="foo",job=~"api.*",env="prod"}`, expected: labels.Labels{&labels.Label{Name: "env", Value: "prod"}}},
+			{input: `foo{job="api",job="web"}`, expected: labels.Labels{}},
+			{input: `(foo{job="api"}[5m])`, expected: labels.Labels{&labels.Label{Name: "job", Value: "api"}}},
+			{input: `rate(foo[5m])[30m:1m]`, expected: labels.Labels{}},
+			{input: `foo{job="api"}[30m:1m]`, expected: labels.Labels{&labels.Label{Name: "job", Value: "api"}}},
+			{input: `1`, expected: labels.Labels{}},
+		}
+		for _, test := range tests {
+			expr, err := parser.ParseExpr(testCtx, test.input)
+			So(err, ShouldBeNil)
+			So(AbsentLabels(expr), ShouldResemble, test.expected)
+		}
+	})
+}
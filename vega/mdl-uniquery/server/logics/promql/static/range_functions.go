@@ -0,0 +1,357 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package static
+
+import (
+	"fmt"
+	"math"
+)
+
+// RangeFunction 在区间 (rangeStart, rangeEnd] 内按时间升序的数据点上计算区间向量函数的值，
+// args 为函数的标量参数（不含区间向量参数），第二个返回值为 false 表示该区间无结果。
+// 叶子节点的区间向量函数尽量下推到 opensearch 聚合计算，子查询以及无法用聚合下推的函数用这里的实现在数据点上计算。
+type RangeFunction func(points []Point, rangeStart, rangeEnd int64, args []float64) (float64, bool)
+
+// RangeFunctions 在数据点上计算的区间向量函数，用于子查询以及叶子节点取回原始样本点后计算的函数
+var RangeFunctions = map[string]RangeFunction{
+	"rate": func(points []Point, rangeStart, rangeEnd int64, _ []float64) (float64, bool) {
+		return extrapolatedRateOverPoints(points, rangeStart, rangeEnd, true, true)
+	},
+	"increase": func(points []Point, rangeStart, rangeEnd int64, _ []float64) (float64, bool) {
+		return extrapolatedRateOverPoints(points, rangeStart, rangeEnd, true, false)
+	},
+	"delta": func(points []Point, rangeStart, rangeEnd int64, _ []float64) (float64, bool) {
+		return extrapolatedRateOverPoints(points, rangeStart, rangeEnd, false, false)
+	},
+	"irate":              irateOverPoints,
+	"changes":            changesOverPoints,
+	"resets":             resetsOverPoints,
+	"avg_over_time":      avgOverPoints,
+	"sum_over_time":      sumOverPoints,
+	"max_over_time":      maxOverPoints,
+	"min_over_time":      minOverPoints,
+	"count_over_time":    countOverPoints,
+	"last_over_time":     lastOverPoints,
+	"present_over_time":  presentOverPoints,
+	"quantile_over_time": quantileOverPoints,
+	"stddev_over_time":   stddevOverPoints,
+	"stdvar_over_time":   stdvarOverPoints,
+	"deriv":              derivOverPoints,
+	"predict_linear":     predictLinearOverPoints,
+	"holt_winters":       holtWintersOverPoints,
+}
+
+// extrapolatedRateOverPoints 与 extrapolatedRate 相同的外推逻辑，计数器重置在这里按相邻数据点计算
+func extrapolatedRateOverPoints(points []Point, rangeStart, rangeEnd int64, isCounter, isRate bool) (float64, bool) {
+	// No sense in trying to compute a rate without at least two points.
+	if len(points) < 2 {
+		return 0, false
+	}
+	firstPoint, lastPoint := points[0], points[len(points)-1]
+
+	resultValue := lastPoint.V - firstPoint.V
+	if isCounter {
+		prevValue := firstPoint.V
+		for _, p := range points[1:] {
+			if p.V < prevValue {
+				resultValue += prevValue
+			}
+			prevValue = p.V
+		}
+	}
+
+	durationToStart := float64(firstPoint.T-rangeStart) / 1000
+	durationToEnd := float64(rangeEnd-lastPoint.T) / 1000
+
+	sampledInterval := float64(lastPoint.T-firstPoint.T) / 1000
+	averageDurationBetweenSamples := sampledInterval / float64(len(points)-1)
+
+	if isCounter && resultValue > 0 && firstPoint.V >= 0 {
+		// Counters cannot be negative. If we have any slope at all,
+		// we can extrapolate the zero point of the counter.
+		durationToZero := sampledInterval * (firstPoint.V / resultValue)
+		if durationToZero < durationToStart {
+			durationToStart = durationToZero
+		}
+	}
+
+	// If the first/last samples are close to the boundaries of the range,
+	// extrapolate the result.
+	extrapolationThreshold := averageDurationBetweenSamples * 1.1
+	extrapolateToInterval := sampledInterval
+
+	if durationToStart < extrapolationThreshold {
+		extrapolateToInterval += durationToStart
+	} else {
+		extrapolateToInterval += averageDurationBetweenSamples / 2
+	}
+
+	if durationToEnd < extrapolationThreshold {
+		extrapolateToInterval += durationToEnd
+	} else {
+		extrapolateToInterval += averageDurationBetweenSamples / 2
+	}
+	resultValue = resultValue * (extrapolateToInterval / sampledInterval)
+	if isRate {
+		resultValue = resultValue / (float64(rangeEnd-rangeStart) / 1000)
+	}
+	return resultValue, true
+}
+
+// irateOverPoints 用区间内最后两个数据点计算每秒的瞬时增长率
+func irateOverPoints(points []Point, _, _ int64, _ []float64) (float64, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+	lastPoint, previousPoint := points[len(points)-1], points[len(points)-2]
+
+	resultValue := lastPoint.V - previousPoint.V
+	if lastPoint.V < previousPoint.V {
+		// Counter reset.
+		resultValue = lastPoint.V
+	}
+
+	sampledInterval := lastPoint.T - previousPoint.T
+	if sampledInterval == 0 {
+		// Avoid dividing by 0.
+		return 0, false
+	}
+	return resultValue / (float64(sampledInterval) / 1000), true
+}
+
+func changesOverPoints(points []Point, _, _ int64, _ []float64) (float64, bool) {
+	if len(points) == 0 {
+		return 0, false
+	}
+	changes := 0
+	prev := points[0].V
+	for _, p := range points[1:] {
+		if p.V != prev && !(math.IsNaN(p.V) && math.IsNaN(prev)) {
+			changes++
+		}
+		prev = p.V
+	}
+	return float64(changes), true
+}
+
+func avgOverPoints(points []Point, rangeStart, rangeEnd int64, args []float64) (float64, bool) {
+	sum, ok := sumOverPoints(points, rangeStart, rangeEnd, args)
+	if !ok {
+		return 0, false
+	}
+	return sum / float64(len(points)), true
+}
+
+func sumOverPoints(points []Point, _, _ int64, _ []float64) (float64, bool) {
+	if len(points) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, p := range points {
+		sum += p.V
+	}
+	return sum, true
+}
+
+func maxOverPoints(points []Point, _, _ int64, _ []float64) (float64, bool) {
+	if len(points) == 0 {
+		return 0, false
+	}
+	max := points[0].V
+	for _, p := range points[1:] {
+		if p.V > max || math.IsNaN(max) {
+			max = p.V
+		}
+	}
+	return max, true
+}
+
+func minOverPoints(points []Point, _, _ int64, _ []float64) (float64, bool) {
+	if len(points) == 0 {
+		return 0, false
+	}
+	min := points[0].V
+	for _, p := range points[1:] {
+		if p.V < min || math.IsNaN(min) {
+			min = p.V
+		}
+	}
+	return min, true
+}
+
+func countOverPoints(points []Point, _, _ int64, _ []float64) (float64, bool) {
+	if len(points) == 0 {
+		return 0, false
+	}
+	return float64(len(points)), true
+}
+
+func lastOverPoints(points []Point, _, _ int64, _ []float64) (float64, bool) {
+	if len(points) == 0 {
+		return 0, false
+	}
+	return points[len(points)-1].V, true
+}
+
+func presentOverPoints(points []Point, _, _ int64, _ []float64) (float64, bool) {
+	if len(points) == 0 {
+		return 0, false
+	}
+	return 1, true
+}
+
+// resetsOverPoints 计数器重置的次数，即相邻数据点中后一个值小于前一个值的次数
+func resetsOverPoints(points []Point, _, _ int64, _ []float64) (float64, bool) {
+	if len(points) == 0 {
+		return 0, false
+	}
+	resets := 0
+	prev := points[0].V
+	for _, p := range points[1:] {
+		if p.V < prev {
+			resets++
+		}
+		prev = p.V
+	}
+	return float64(resets), true
+}
+
+// quantileOverPoints quantile_over_time(φ, v)，φ 为 args[0]
+func quantileOverPoints(points []Point, _, _ int64, args []float64) (float64, bool) {
+	if len(points) == 0 {
+		return 0, false
+	}
+	values := make([]float64, 0, len(points))
+	for _, p := range points {
+		values = append(values, p.V)
+	}
+	return quantile(args[0], values), true
+}
+
+func stdvarOverPoints(points []Point, _, _ int64, _ []float64) (float64, bool) {
+	if len(points) == 0 {
+		return 0, false
+	}
+	// Welford 算法，避免先求平方和带来的精度损失
+	var count, mean, aux float64
+	for _, p := range points {
+		count++
+		delta := p.V - mean
+		mean += delta / count
+		aux += delta * (p.V - mean)
+	}
+	return aux / count, true
+}
+
+func stddevOverPoints(points []Point, rangeStart, rangeEnd int64, args []float64) (float64, bool) {
+	stdvar, ok := stdvarOverPoints(points, rangeStart, rangeEnd, args)
+	if !ok {
+		return 0, false
+	}
+	return math.Sqrt(stdvar), true
+}
+
+// derivOverPoints 用简单线性回归计算每秒的变化率
+func derivOverPoints(points []Point, _, _ int64, _ []float64) (float64, bool) {
+	// No sense in trying to compute a derivative without at least two points.
+	if len(points) < 2 {
+		return 0, false
+	}
+	// We pass in an arbitrary timestamp that is near the values in use
+	// to avoid floating point accuracy issues.
+	slope, _ := linearRegression(points, points[0].T)
+	return slope, true
+}
+
+// predictLinearOverPoints predict_linear(v, t)，基于线性回归预测区间结束后 t 秒（args[0]）的值
+func predictLinearOverPoints(points []Point, _, rangeEnd int64, args []float64) (float64, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+	slope, intercept := linearRegression(points, rangeEnd)
+	return slope*args[0] + intercept, true
+}
+
+// linearRegression 以 interceptTime 为原点做最小二乘线性回归，返回每秒的斜率和原点处的截距
+func linearRegression(points []Point, interceptTime int64) (slope, intercept float64) {
+	var (
+		n          float64
+		sumX, sumY float64
+		sumXY      float64
+		sumX2      float64
+		initY      = points[0].V
+		constY     = true
+	)
+	for i, p := range points {
+		// Set constY to false if any new y values are encountered.
+		if constY && i > 0 && p.V != initY {
+			constY = false
+		}
+		n += 1.0
+		x := float64(p.T-interceptTime) / 1e3
+		sumX += x
+		sumY += p.V
+		sumXY += x * p.V
+		sumX2 += x * x
+	}
+	if constY {
+		if math.IsInf(initY, 0) {
+			return math.NaN(), math.NaN()
+		}
+		return 0, initY
+	}
+
+	covXY := sumXY - sumX*sumY/n
+	varX := sumX2 - sumX*sumX/n
+
+	slope = covXY / varX
+	intercept = sumY/n - slope*sumX/n
+	return slope, intercept
+}
+
+// holtWintersOverPoints holt_winters(v, sf, tf)，双指数平滑，sf 为平滑因子（args[0]），tf 为趋势因子（args[1]）
+func holtWintersOverPoints(points []Point, _, _ int64, args []float64) (float64, bool) {
+	sf, tf := args[0], args[1]
+	// Sanity check the input.
+	if sf <= 0 || sf >= 1 {
+		panic(fmt.Errorf("invalid smoothing factor. Expected: 0 < sf < 1, got: %f", sf))
+	}
+	if tf <= 0 || tf >= 1 {
+		panic(fmt.Errorf("invalid trend factor. Expected: 0 < tf < 1, got: %f", tf))
+	}
+
+	// Can't do the smoothing operation with less than two points.
+	if len(points) < 2 {
+		return 0, false
+	}
+
+	var s0, s1, b float64
+	// Set initial values.
+	s1 = points[0].V
+	b = points[1].V - points[0].V
+
+	// Run the smoothing operation.
+	for i := 1; i < len(points); i++ {
+		// Scale the raw value against the smoothing factor.
+		x := sf * points[i].V
+		// Scale the last smoothed value with the trend at this point.
+		b = calcTrendValue(i-1, tf, s0, s1, b)
+		y := (1 - sf) * (s1 + b)
+		s0, s1 = s1, x+y
+	}
+	return s1, true
+}
+
+// calcTrendValue calculates the trend value at the given index i in raw data d.
+// This is somewhat analogous to the slope of the trend at the given index.
+func calcTrendValue(i int, tf, s0, s1, b float64) float64 {
+	if i == 0 {
+		return b
+	}
+	x := tf * (s1 - s0)
+	y := (1 - tf) * b
+	return x + y
+}
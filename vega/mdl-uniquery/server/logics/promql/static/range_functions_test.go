@@ -0,0 +1,85 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package static
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRangeFunctions(t *testing.T) {
+	Convey("Test RangeFunctions", t, func() {
+		// 区间 (0, 60000]，每 15s 一个数据点
+		points := []Point{{T: 15000, V: 1}, {T: 30000, V: 3}, {T: 45000, V: 2}, {T: 60000, V: 6}}
+
+		testCases := []struct {
+			name     string
+			points   []Point
+			args     []float64
+			expected float64
+			ok       bool
+		}{
+			{name: "sum_over_time", points: points, expected: 12, ok: true},
+			{name: "avg_over_time", points: points, expected: 3, ok: true},
+			{name: "max_over_time", points: points, expected: 6, ok: true},
+			{name: "min_over_time", points: points, expected: 1, ok: true},
+			{name: "count_over_time", points: points, expected: 4, ok: true},
+			{name: "changes", points: points, expected: 3, ok: true},
+			// 计数器在 45s 重置：增量为 (3-1) + 2 + (6-2) = 8，计数器不为负，起点只外推到零点 45*(1/8)，结果为 8*(45+5.625)/45
+			{name: "increase", points: points, expected: 9, ok: true},
+			{name: "rate", points: points, expected: 9.0 / 60, ok: true},
+			{name: "delta", points: points, expected: 5 * 60.0 / 45, ok: true},
+			{name: "irate", points: points, expected: 4.0 / 15, ok: true},
+			{name: "resets", points: points, expected: 1, ok: true},
+			{name: "last_over_time", points: points, expected: 6, ok: true},
+			{name: "present_over_time", points: points, expected: 1, ok: true},
+			{name: "quantile_over_time", points: points, args: []float64{0.5}, expected: 2.5, ok: true},
+			{name: "quantile_over_time", points: points, args: []float64{0.9}, expected: 5.1, ok: true},
+			{name: "quantile_over_time", points: points, args: []float64{-1}, expected: math.Inf(-1), ok: true},
+			{name: "quantile_over_time", points: points, args: []float64{2}, expected: math.Inf(1), ok: true},
+			{name: "stdvar_over_time", points: points, expected: 3.5, ok: true},
+			{name: "stddev_over_time", points: points, expected: math.Sqrt(3.5), ok: true},
+			// 最小二乘斜率：x 为 15,30,45,60，y 为 1,3,2,6，协方差 105，方差 1125
+			{name: "deriv", points: points, expected: 105.0 / 1125, ok: true},
+			// 回归直线过均值点 (37.5, 3)，预测区间结束后 60s 即 x = 120 处的值
+			{name: "predict_linear", points: points, args: []float64{60}, expected: 3 + 105.0/1125*82.5, ok: true},
+			{name: "predict_linear", points: []Point{{T: 15000, V: 2}, {T: 30000, V: 2}}, args: []float64{600}, expected: 2, ok: true},
+			{name: "holt_winters", points: []Point{{T: 15000, V: 1}, {T: 30000, V: 2}, {T: 45000, V: 3}}, args: []float64{0.5, 0.5}, expected: 3, ok: true},
+			{name: "sum_over_time", points: []Point{}, ok: false},
+			{name: "deriv", points: points[:1], ok: false},
+			{name: "holt_winters", points: points[:1], args: []float64{0.5, 0.5}, ok: false},
+			{name: "rate", points: points[:1], ok: false},
+			{name: "irate", points: []Point{{T: 15000, V: 1}, {T: 15000, V: 2}}, ok: false},
+		}
+
+		for _, tc := range testCases {
+			v, ok := RangeFunctions[tc.name](tc.points, 0, 60000, tc.args)
+			So(ok, ShouldEqual, tc.ok)
+			if tc.ok && !math.IsInf(tc.expected, 0) {
+				So(v, ShouldAlmostEqual, tc.expected, 1e-9)
+			} else if tc.ok {
+				So(v, ShouldEqual, tc.expected)
+			}
+		}
+	})
+
+	Convey("Test max/min over time with NaN", t, func() {
+		points := []Point{{T: 15000, V: math.NaN()}, {T: 30000, V: 3}, {T: 45000, V: 2}}
+
+		v, _ := RangeFunctions["max_over_time"](points, 0, 60000, nil)
+		So(v, ShouldEqual, 3)
+		v, _ = RangeFunctions["min_over_time"](points, 0, 60000, nil)
+		So(v, ShouldEqual, 2)
+	})
+	Convey("Test holt_winters with invalid factors", t, func() {
+		points := []Point{{T: 15000, V: 1}, {T: 30000, V: 2}}
+
+		So(func() { RangeFunctions["holt_winters"](points, 0, 60000, []float64{1, 0.5}) }, ShouldPanic)
+		So(func() { RangeFunctions["holt_winters"](points, 0, 60000, []float64{0.5, 0}) }, ShouldPanic)
+	})
+}
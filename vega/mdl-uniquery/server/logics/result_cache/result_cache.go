@@ -0,0 +1,360 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package result_cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_metric"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"uniquery/common"
+	"uniquery/interfaces"
+)
+
+const (
+	defaultTTL           = time.Hour
+	defaultCompleteDelay = time.Minute
+	defaultMaxSeries     = 1000
+
+	// 可观测性指标
+	RESULT_CACHE_COUNT_NAME        = "uniquery_result_cache_request_count"
+	RESULT_CACHE_COUNT_DESCRIPTION = "metric query result cache request count"
+
+	// 缓存查询结果：hit 全部命中，partial 部分命中（需补查首尾区间），miss 未命中
+	resultHit     = "hit"
+	resultPartial = "partial"
+	resultMiss    = "miss"
+)
+
+var (
+	rcOnce    sync.Once
+	rcService interfaces.ResultCache
+)
+
+// resultCache 按步长对齐的时间桶缓存指标查询结果。
+// 只缓存已完成的时间桶，后续查询只对缓存之外的首尾区间发起查询，再与缓存合并。
+// store 为空时不缓存，直接查询。
+type resultCache struct {
+	store         cacheStore
+	completeDelay time.Duration
+	maxSeries     int
+	now           func() time.Time
+	counter       metric.Int64Counter
+}
+
+func NewResultCache(appSetting *common.AppSetting) interfaces.ResultCache {
+	rcOnce.Do(func() {
+		setting := appSetting.ResultCacheSetting
+		ttl := setting.TTL
+		if ttl <= 0 {
+			ttl = defaultTTL
+		}
+
+		var store cacheStore
+		if setting.Enabled {
+			switch setting.Store {
+			case "", interfaces.ResultCacheStore_Memory:
+				store = newMemoryStore(ttl)
+			case interfaces.ResultCacheStore_Redis:
+				client := redis.NewClient(&redis.Options{
+					Addr:        fmt.Sprintf("%s:%d", appSetting.RedisSetting.Host, appSetting.RedisSetting.Port),
+					Username:    appSetting.RedisSetting.Username,
+					Password:    appSetting.RedisSetting.Password,
+					DialTimeout: 5 * time.Second,
+				})
+				store = newRedisStore(client, ttl)
+			default:
+				logger.Fatalf("unsupported result cache store: %s", setting.Store)
+			}
+			logger.Infof("Result cache store: %s, ttl: %v", setting.Store, ttl)
+		}
+
+		rcService = newResultCache(setting, store)
+	})
+	return rcService
+}
+
+func newResultCache(setting common.ResultCacheSetting, store cacheStore) *resultCache {
+	completeDelay := setting.CompleteDelay
+	if completeDelay <= 0 {
+		completeDelay = defaultCompleteDelay
+	}
+	maxSeries := setting.MaxSeries
+	if maxSeries <= 0 {
+		maxSeries = defaultMaxSeries
+	}
+
+	counter, _ := ar_metric.Meter.Int64Counter(
+		RESULT_CACHE_COUNT_NAME,
+		metric.WithUnit("count"),
+		metric.WithDescription(RESULT_CACHE_COUNT_DESCRIPTION),
+	)
+
+	return &resultCache{
+		store:         store,
+		completeDelay: completeDelay,
+		maxSeries:     maxSeries,
+		now:           time.Now,
+		counter:       counter,
+	}
+}
+
+// 查询时间范围内的数据。
+// 缓存与请求范围相交或相邻时，只补查缓存之前和之后的区间，否则整段查询并替换缓存
+func (rc *resultCache) RangeQuery(ctx context.Context, key interfaces.ResultCacheKey, r interfaces.CacheRange,
+	fetch interfaces.RangeFetchFunc) ([]*interfaces.CachedSeries, error) {
+
+	if rc.store == nil || r.Step <= 0 || r.FixedEnd < r.FixedStart {
+		series, _, err := fetch(ctx, r.Start, r.End)
+		return series, err
+	}
+
+	storeKey := cacheKey(key)
+	lastComplete := rc.lastCompleteBucket(r)
+
+	entry := rc.getEntry(ctx, storeKey, key.Version, r.Step)
+	if entry == nil || r.FixedEnd < entry.Start-r.Step || r.FixedStart > entry.End+r.Step {
+		rc.record(ctx, key.Scope, resultMiss)
+
+		series, cacheable, err := fetch(ctx, r.Start, r.End)
+		if err != nil {
+			return nil, err
+		}
+		if cacheable {
+			rc.save(ctx, storeKey, &cacheEntry{
+				Version: key.Version,
+				Step:    r.Step,
+				Start:   r.FixedStart,
+				End:     min(r.FixedEnd, lastComplete),
+				Series:  series,
+			})
+		}
+		return series, nil
+	}
+
+	parts := make([][]*interfaces.CachedSeries, 0, 3)
+	cacheable := true
+	fetched := false
+
+	// 补查缓存之前的区间，结束时间取缓存第一个桶之前，对齐后为 entry.Start-step
+	if r.FixedStart < entry.Start {
+		head, ok, err := fetch(ctx, r.Start, entry.Start-1)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, head)
+		cacheable = cacheable && ok
+		fetched = true
+	}
+
+	parts = append(parts, entry.Series)
+
+	// 补查缓存之后的区间，包含未完成的时间桶
+	if r.FixedEnd > entry.End {
+		tail, ok, err := fetch(ctx, entry.End+r.Step, r.End)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, tail)
+		cacheable = cacheable && ok
+		fetched = true
+	}
+
+	all := mergeSeries(parts...)
+	if fetched {
+		rc.record(ctx, key.Scope, resultPartial)
+		if cacheable {
+			rc.save(ctx, storeKey, &cacheEntry{
+				Version: key.Version,
+				Step:    r.Step,
+				Start:   min(r.FixedStart, entry.Start),
+				End:     max(entry.End, min(r.FixedEnd, lastComplete)),
+				Series:  all,
+			})
+		}
+	} else {
+		rc.record(ctx, key.Scope, resultHit)
+	}
+
+	return sliceSeries(all, r.FixedStart, r.FixedEnd), nil
+}
+
+// 删除指标模型的全部缓存，各版本的缓存一并删除
+func (rc *resultCache) DeleteModelCache(ctx context.Context, modelIDs []string) error {
+	if rc.store == nil {
+		return nil
+	}
+
+	for _, modelID := range modelIDs {
+		prefix := cacheKeyPrefix(interfaces.ResultCacheScope_MetricModel, modelID)
+		if err := rc.store.DeleteByPrefix(ctx, prefix); err != nil {
+			return fmt.Errorf("delete result cache of metric model '%s' failed: %w", modelID, err)
+		}
+	}
+	return nil
+}
+
+// 读取缓存，读取失败、版本或步长不一致时按未命中处理
+func (rc *resultCache) getEntry(ctx context.Context, storeKey string, version, step int64) *cacheEntry {
+	entry, ok, err := rc.store.Get(ctx, storeKey)
+	if err != nil {
+		logger.Warnf("Get result cache '%s' failed, %v", storeKey, err)
+		return nil
+	}
+	if !ok || entry.Version != version || entry.Step != step || entry.End < entry.Start {
+		return nil
+	}
+	return entry
+}
+
+// 只保存已完成的时间桶，序列数超出上限时不缓存
+func (rc *resultCache) save(ctx context.Context, storeKey string, entry *cacheEntry) {
+	if entry.End < entry.Start || len(entry.Series) > rc.maxSeries {
+		return
+	}
+
+	entry.Series = sliceSeries(entry.Series, entry.Start, entry.End)
+	if err := rc.store.Set(ctx, storeKey, entry); err != nil {
+		logger.Warnf("Set result cache '%s' failed, %v", storeKey, err)
+	}
+}
+
+// lastCompleteBucket 返回最后一个已完成的时间桶。
+// 时间桶 t 覆盖 [t, t+step)，t+step 早于 now-completeDelay 时视为已完成
+func (rc *resultCache) lastCompleteBucket(r interfaces.CacheRange) int64 {
+	n := rc.now().Add(-rc.completeDelay).UnixMilli() - r.Step - r.FixedStart
+	if n < 0 {
+		return r.FixedStart - r.Step
+	}
+	return r.FixedStart + n/r.Step*r.Step
+}
+
+func (rc *resultCache) record(ctx context.Context, scope, result string) {
+	if rc.counter == nil {
+		return
+	}
+	rc.counter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("scope", scope),
+		attribute.String("result", result),
+	))
+}
+
+// HashQuery 计算规范化查询条件的摘要，作为缓存 key 的一部分
+func HashQuery(v any) (string, error) {
+	b, err := sonic.ConfigStd.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func cacheKeyPrefix(scope, modelID string) string {
+	return scope + ":" + modelID + ":"
+}
+
+func cacheKey(key interfaces.ResultCacheKey) string {
+	return cacheKeyPrefix(key.Scope, key.ModelID) + key.Hash
+}
+
+// mergeSeries 按标签合并多段按时间先后排列的序列，同一序列的时间点保持递增
+func mergeSeries(parts ...[]*interfaces.CachedSeries) []*interfaces.CachedSeries {
+	merged := make([]*interfaces.CachedSeries, 0)
+	index := make(map[string]*interfaces.CachedSeries)
+
+	for _, part := range parts {
+		for _, s := range part {
+			key := labelsKey(s.Labels)
+			target, ok := index[key]
+			if !ok {
+				target = &interfaces.CachedSeries{
+					Labels: s.Labels,
+					Times:  make([]int64, 0, len(s.Times)),
+					Values: make([]any, 0, len(s.Times)),
+				}
+				index[key] = target
+				merged = append(merged, target)
+			}
+
+			withTimeStrs := len(s.TimeStrs) == len(s.Times)
+			for i, t := range s.Times {
+				if n := len(target.Times); n > 0 && t <= target.Times[n-1] {
+					continue
+				}
+				target.Times = append(target.Times, t)
+				target.Values = append(target.Values, valueAt(s.Values, i))
+				if withTimeStrs {
+					target.TimeStrs = append(target.TimeStrs, s.TimeStrs[i])
+				}
+			}
+		}
+	}
+	return merged
+}
+
+// sliceSeries 截取 [start, end] 内的时间点，不含时间点的序列被丢弃。
+// 返回的序列与缓存不共享数据，调用方可以修改
+func sliceSeries(series []*interfaces.CachedSeries, start, end int64) []*interfaces.CachedSeries {
+	res := make([]*interfaces.CachedSeries, 0, len(series))
+	for _, s := range series {
+		from := sort.Search(len(s.Times), func(i int) bool { return s.Times[i] >= start })
+		to := sort.Search(len(s.Times), func(i int) bool { return s.Times[i] > end })
+		if from >= to {
+			continue
+		}
+
+		sliced := &interfaces.CachedSeries{
+			Labels: maps.Clone(s.Labels),
+			Times:  append([]int64(nil), s.Times[from:to]...),
+			Values: make([]any, 0, to-from),
+		}
+		for i := from; i < to; i++ {
+			sliced.Values = append(sliced.Values, valueAt(s.Values, i))
+		}
+		if len(s.TimeStrs) == len(s.Times) {
+			sliced.TimeStrs = append([]string(nil), s.TimeStrs[from:to]...)
+		}
+		res = append(res, sliced)
+	}
+	return res
+}
+
+func valueAt(values []any, i int) any {
+	if i < len(values) {
+		return values[i]
+	}
+	return nil
+}
+
+func labelsKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('\xff')
+		b.WriteString(labels[name])
+		b.WriteByte('\xff')
+	}
+	return b.String()
+}
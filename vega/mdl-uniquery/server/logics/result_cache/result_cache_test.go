@@ -0,0 +1,264 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package result_cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/common"
+	"uniquery/interfaces"
+)
+
+const testStep = int64(60000)
+
+var testCtx = context.Background()
+
+// 按时间桶生成序列，值为时间桶的序号
+type fetchRecorder struct {
+	calls [][2]int64
+	err   error
+}
+
+func (fr *fetchRecorder) fetch(ctx context.Context, start, end int64) ([]*interfaces.CachedSeries, bool, error) {
+	fr.calls = append(fr.calls, [2]int64{start, end})
+	if fr.err != nil {
+		return nil, false, fr.err
+	}
+
+	s := &interfaces.CachedSeries{Labels: map[string]string{"host": "a"}}
+	for t := (start + testStep - 1) / testStep * testStep; t <= end; t += testStep {
+		s.Times = append(s.Times, t)
+		s.Values = append(s.Values, float64(t/testStep))
+	}
+	return []*interfaces.CachedSeries{s}, true, nil
+}
+
+func testRange(start, end int64) interfaces.CacheRange {
+	return interfaces.CacheRange{
+		Start:      start,
+		End:        end,
+		FixedStart: start / testStep * testStep,
+		FixedEnd:   end / testStep * testStep,
+		Step:       testStep,
+	}
+}
+
+func newTestResultCache(store cacheStore, now int64) *resultCache {
+	rc := newResultCache(common.ResultCacheSetting{MaxSeries: 10, CompleteDelay: time.Millisecond}, store)
+	rc.now = func() time.Time { return time.UnixMilli(now) }
+	return rc
+}
+
+func bucketsOf(series []*interfaces.CachedSeries) []int64 {
+	if len(series) == 0 {
+		return nil
+	}
+	return series[0].Times
+}
+
+func Test_ResultCache_RangeQuery(t *testing.T) {
+	Convey("Test RangeQuery", t, func() {
+		key := interfaces.ResultCacheKey{
+			Scope:   interfaces.ResultCacheScope_MetricModel,
+			ModelID: "m1",
+			Version: 1,
+			Hash:    "h1",
+		}
+		// 当前时间之前的时间桶都已完成
+		rc := newTestResultCache(newMemoryStore(time.Hour), 100*testStep)
+
+		Convey("Miss and then hit", func() {
+			fr := &fetchRecorder{}
+			series, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+			So(len(bucketsOf(series)), ShouldEqual, 11)
+			So(fr.calls, ShouldResemble, [][2]int64{{10 * testStep, 20 * testStep}})
+
+			series, err = rc.RangeQuery(testCtx, key, testRange(12*testStep, 18*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+			So(bucketsOf(series)[0], ShouldEqual, 12*testStep)
+			So(len(bucketsOf(series)), ShouldEqual, 7)
+			So(series[0].Values[0], ShouldEqual, float64(12))
+			So(len(fr.calls), ShouldEqual, 1)
+		})
+
+		Convey("Partial hit only fetches head and tail", func() {
+			fr := &fetchRecorder{}
+			_, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+
+			series, err := rc.RangeQuery(testCtx, key, testRange(5*testStep+1, 25*testStep+1), fr.fetch)
+			So(err, ShouldBeNil)
+			So(fr.calls[1:], ShouldResemble, [][2]int64{
+				{5*testStep + 1, 10*testStep - 1},
+				{21 * testStep, 25*testStep + 1},
+			})
+
+			buckets := bucketsOf(series)
+			So(len(buckets), ShouldEqual, 20)
+			So(buckets[0], ShouldEqual, 6*testStep)
+			So(buckets[len(buckets)-1], ShouldEqual, 25*testStep)
+
+			// 合并后的结果写回缓存
+			_, err = rc.RangeQuery(testCtx, key, testRange(6*testStep, 25*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+			So(len(fr.calls), ShouldEqual, 3)
+		})
+
+		Convey("Version changed", func() {
+			fr := &fetchRecorder{}
+			_, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+
+			newKey := key
+			newKey.Version = 2
+			_, err = rc.RangeQuery(testCtx, newKey, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+			So(len(fr.calls), ShouldEqual, 2)
+		})
+
+		Convey("Disjoint range replaces the cache", func() {
+			fr := &fetchRecorder{}
+			_, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+
+			_, err = rc.RangeQuery(testCtx, key, testRange(50*testStep, 60*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+			So(fr.calls[1], ShouldResemble, [2]int64{50 * testStep, 60 * testStep})
+
+			_, err = rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+			So(len(fr.calls), ShouldEqual, 3)
+		})
+
+		Convey("Incomplete buckets are fetched again", func() {
+			rc.now = func() time.Time { return time.UnixMilli(15 * testStep) }
+			fr := &fetchRecorder{}
+			_, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+
+			// 时间桶 14 在 15*step 之前未完成，缓存只到 13
+			_, err = rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+			So(fr.calls[1], ShouldResemble, [2]int64{14 * testStep, 20 * testStep})
+		})
+
+		Convey("Too many series are not cached", func() {
+			fetch := func(ctx context.Context, start, end int64) ([]*interfaces.CachedSeries, bool, error) {
+				series := make([]*interfaces.CachedSeries, 0, 11)
+				for i := range 11 {
+					series = append(series, &interfaces.CachedSeries{
+						Labels: map[string]string{"i": string(rune('a' + i))},
+						Times:  []int64{start},
+						Values: []any{1.0},
+					})
+				}
+				return series, true, nil
+			}
+			_, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fetch)
+			So(err, ShouldBeNil)
+
+			_, ok, _ := rc.store.Get(testCtx, cacheKey(key))
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Uncacheable result is not saved", func() {
+			fetch := func(ctx context.Context, start, end int64) ([]*interfaces.CachedSeries, bool, error) {
+				return []*interfaces.CachedSeries{}, false, nil
+			}
+			_, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fetch)
+			So(err, ShouldBeNil)
+
+			_, ok, _ := rc.store.Get(testCtx, cacheKey(key))
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Fetch failed", func() {
+			fr := &fetchRecorder{err: errors.New("opensearch unavailable")}
+			_, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Disabled cache always fetches", func() {
+			rc := newTestResultCache(nil, 100*testStep)
+			fr := &fetchRecorder{}
+			for range 2 {
+				_, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+				So(err, ShouldBeNil)
+			}
+			So(len(fr.calls), ShouldEqual, 2)
+		})
+	})
+}
+
+func Test_ResultCache_DeleteModelCache(t *testing.T) {
+	Convey("Test DeleteModelCache", t, func() {
+		rc := newTestResultCache(newMemoryStore(time.Hour), 100*testStep)
+		fr := &fetchRecorder{}
+
+		k1 := interfaces.ResultCacheKey{Scope: interfaces.ResultCacheScope_MetricModel, ModelID: "m1", Version: 1, Hash: "h1"}
+		k2 := interfaces.ResultCacheKey{Scope: interfaces.ResultCacheScope_MetricModel, ModelID: "m2", Version: 1, Hash: "h1"}
+		for _, k := range []interfaces.ResultCacheKey{k1, k2} {
+			_, err := rc.RangeQuery(testCtx, k, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+		}
+
+		err := rc.DeleteModelCache(testCtx, []string{"m1"})
+		So(err, ShouldBeNil)
+
+		_, ok, _ := rc.store.Get(testCtx, cacheKey(k1))
+		So(ok, ShouldBeFalse)
+		_, ok, _ = rc.store.Get(testCtx, cacheKey(k2))
+		So(ok, ShouldBeTrue)
+	})
+}
+
+func Test_RedisStore(t *testing.T) {
+	Convey("Test redis store", t, func() {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		store := newRedisStore(client, time.Hour)
+		rc := newTestResultCache(store, 100*testStep)
+
+		key := interfaces.ResultCacheKey{Scope: interfaces.ResultCacheScope_MetricModel, ModelID: "m1", Version: 1, Hash: "h1"}
+		fr := &fetchRecorder{}
+
+		Convey("Hit after round trip", func() {
+			_, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+			So(mr.TTL(redisKeyPrefix+cacheKey(key)), ShouldEqual, time.Hour)
+
+			series, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+			So(len(fr.calls), ShouldEqual, 1)
+			So(series[0].Labels, ShouldResemble, map[string]string{"host": "a"})
+			So(series[0].Values[0], ShouldEqual, float64(10))
+		})
+
+		Convey("Delete by prefix", func() {
+			_, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+
+			err = rc.DeleteModelCache(testCtx, []string{"m1"})
+			So(err, ShouldBeNil)
+			So(mr.Exists(redisKeyPrefix+cacheKey(key)), ShouldBeFalse)
+		})
+
+		Convey("Redis unavailable falls back to fetch", func() {
+			mr.Close()
+			series, err := rc.RangeQuery(testCtx, key, testRange(10*testStep, 20*testStep), fr.fetch)
+			So(err, ShouldBeNil)
+			So(len(bucketsOf(series)), ShouldEqual, 11)
+		})
+	})
+}
@@ -0,0 +1,135 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package result_cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/patrickmn/go-cache"
+	"github.com/redis/go-redis/v9"
+
+	"uniquery/interfaces"
+)
+
+const (
+	// Redis 中缓存条目的 key 前缀
+	redisKeyPrefix = "mdl-uniquery:result-cache:"
+	// 按前缀删除时每次 SCAN 的数量
+	redisScanCount = 500
+	// 内存缓存清理过期条目的间隔
+	memoryCleanupInterval = 10 * time.Minute
+)
+
+// 缓存条目，[Start, End] 为已缓存的时间桶范围
+type cacheEntry struct {
+	Version int64                      `json:"version"`
+	Step    int64                      `json:"step"`
+	Start   int64                      `json:"start"`
+	End     int64                      `json:"end"`
+	Series  []*interfaces.CachedSeries `json:"series"`
+}
+
+// cacheStore 缓存条目的存储，条目过期由存储自身完成
+type cacheStore interface {
+	Get(ctx context.Context, key string) (*cacheEntry, bool, error)
+	Set(ctx context.Context, key string, entry *cacheEntry) error
+	DeleteByPrefix(ctx context.Context, prefix string) error
+}
+
+// memoryStore 进程内缓存，多实例部署时各实例缓存独立
+type memoryStore struct {
+	cache *cache.Cache
+}
+
+func newMemoryStore(ttl time.Duration) *memoryStore {
+	return &memoryStore{
+		cache: cache.New(ttl, memoryCleanupInterval),
+	}
+}
+
+func (ms *memoryStore) Get(ctx context.Context, key string) (*cacheEntry, bool, error) {
+	v, ok := ms.cache.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	return v.(*cacheEntry), true, nil
+}
+
+func (ms *memoryStore) Set(ctx context.Context, key string, entry *cacheEntry) error {
+	ms.cache.SetDefault(key, entry)
+	return nil
+}
+
+func (ms *memoryStore) DeleteByPrefix(ctx context.Context, prefix string) error {
+	for key := range ms.cache.Items() {
+		if strings.HasPrefix(key, prefix) {
+			ms.cache.Delete(key)
+		}
+	}
+	return nil
+}
+
+// redisStore Redis 缓存，多实例共享，条目过期由 key 的 TTL 完成
+type redisStore struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+func newRedisStore(client redis.UniversalClient, ttl time.Duration) *redisStore {
+	return &redisStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func (rs *redisStore) Get(ctx context.Context, key string) (*cacheEntry, bool, error) {
+	b, err := rs.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	entry := &cacheEntry{}
+	if err = sonic.Unmarshal(b, entry); err != nil {
+		return nil, false, fmt.Errorf("unmarshal result cache entry failed: %w", err)
+	}
+	// JSON 反序列化后数值为 float64，时间桶已按 int64 声明，值保持 JSON 的表示即可
+	return entry, true, nil
+}
+
+func (rs *redisStore) Set(ctx context.Context, key string, entry *cacheEntry) error {
+	b, err := sonic.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal result cache entry failed: %w", err)
+	}
+	return rs.client.Set(ctx, redisKeyPrefix+key, b, rs.ttl).Err()
+}
+
+func (rs *redisStore) DeleteByPrefix(ctx context.Context, prefix string) error {
+	var cursor uint64
+	for {
+		keys, next, err := rs.client.Scan(ctx, cursor, redisKeyPrefix+prefix+"*", redisScanCount).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err = rs.client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
@@ -16,6 +16,7 @@ import (
 	uerrors "uniquery/errors"
 	"uniquery/interfaces"
 	data_view "uniquery/logics/trace_model/data_source/data_view"
+	jaeger "uniquery/logics/trace_model/data_source/jaeger"
 	tingyun "uniquery/logics/trace_model/data_source/tingyun"
 )
 
@@ -25,6 +26,8 @@ func NewTraceModelAdapter(ctx context.Context, dataSourceType string, appSetting
 		return data_view.NewDataViewAdapter(appSetting), nil
 	case interfaces.SOURCE_TYPE_TINGYUN:
 		return tingyun.NewTingYunAdapter(appSetting), nil
+	case interfaces.SOURCE_TYPE_JAEGER:
+		return jaeger.NewJaegerAdapter(appSetting), nil
 	default:
 		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_InternalError).
 			WithErrorDetails(fmt.Sprintf("Invalid data_source_type %s, TraceModelAdapter cannot be manufactured based on this data_source_type", dataSourceType))
@@ -27,8 +27,10 @@ import (
 	vopt "uniquery/common/value_opt"
 	uerrors "uniquery/errors"
 	"uniquery/interfaces"
+	dtype "uniquery/interfaces/data_type"
 	"uniquery/logics/data_view"
 	"uniquery/logics/dsl"
+	"uniquery/logics/trace_model/data_source/span_stats"
 )
 
 var (
@@ -440,6 +442,175 @@ func (dvAdapter *dataViewAdapter) GetRelatedLogCountMap(ctx context.Context, mod
 	return stats, nil
 }
 
+func (dvAdapter *dataViewAdapter) GetSpanStats(ctx context.Context, model interfaces.TraceModel, params interfaces.SpanStatsQueryParams) (buckets []interfaces.SpanStatsBucket, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 通过dataViewAdapter聚合统计span")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	spanConf, _ := model.SpanConfig.(interfaces.SpanConfigWithDataView)
+
+	// 1. 获取视图字段, text类型的字段需使用keyword子字段聚合
+	view, err := dvAdapter.dvService.GetDataViewByID(ctx, spanConf.DataView.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldTypes := make(map[string]string, len(view.Fields))
+	for _, field := range view.Fields {
+		fieldTypes[field.Name] = field.Type
+	}
+	aggField := func(fieldName string) string {
+		if fieldTypes[fieldName] == interfaces.TEXT_TYPE {
+			return fieldName + "." + dtype.KEYWORD_SUFFIX
+		}
+		return fieldName
+	}
+
+	// 2. 生成聚合配置, 时间分桶基于视图的@timestamp字段
+	aggConf := span_stats.AggConfig{
+		ServiceField: aggField(spanConf.ServiceName.FieldName),
+		ErrorFilter: map[string]any{
+			"terms": map[string]any{
+				aggField(spanConf.Status.FieldName): []string{"error", "Error", "ERROR"},
+			},
+		},
+		TimeField:     interfaces.MetaField_Timestamp,
+		DateHistogram: true,
+		Interval:      params.Interval,
+		MinBound:      params.Start,
+		MaxBound:      params.End,
+	}
+	if params.GroupByOperation {
+		aggConf.OperationField = aggField(spanConf.Name.FieldName)
+	}
+
+	durationToMicros := float64(1)
+	if spanConf.EndTime.FieldName == "" {
+		aggConf.DurationField = spanConf.Duration.FieldName
+		switch spanConf.Duration.FieldUnit {
+		case interfaces.MS:
+			durationToMicros = 1e3
+		case interfaces.NS:
+			durationToMicros = 1e-3
+		}
+	} else {
+		// 未配置耗时字段时, 以结束时间与开始时间之差作为耗时, 二者格式一致
+		aggConf.DurationScript = fmt.Sprintf("doc['%s'].value - doc['%s'].value",
+			spanConf.EndTime.FieldName, spanConf.StartTime.FieldName)
+		switch spanConf.StartTime.FieldFormat {
+		case interfaces.UNIX_MILLIS:
+			durationToMicros = 1e3
+		case interfaces.UNIX_NANOS:
+			durationToMicros = 1e-3
+		}
+	}
+
+	// 3. 基于数据视图执行聚合查询
+	query := &interfaces.DataViewQueryV1{
+		GlobalFilters: dvAdapter.genAnalyticsCondition(spanConf, params.ServiceName, params.Operation),
+		ViewQueryCommonParams: interfaces.ViewQueryCommonParams{
+			Start: params.Start,
+			End:   params.End,
+		},
+	}
+
+	resBytes, err := dvAdapter.dvService.AggregateViewData(ctx, spanConf.DataView.ID, query, span_stats.GenSpanStatsAggs(aggConf))
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err = span_stats.ParseSpanStatsBuckets(resBytes, 1, durationToMicros)
+	if err != nil {
+		errDetails := fmt.Sprintf("Failed to parse span stats from data view, err: %v", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_TraceModel_InternalError_ParseSpanStatsFailed).
+			WithErrorDetails(errDetails)
+	}
+
+	return buckets, nil
+}
+
+func (dvAdapter *dataViewAdapter) ScanBriefSpans(ctx context.Context, model interfaces.TraceModel, params interfaces.TraceAnalyticsQueryParams,
+	handle func(briefSpans []*interfaces.BriefSpan_) error) (err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 通过dataViewAdapter扫描时间范围内的span")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	spanConf, _ := model.SpanConfig.(interfaces.SpanConfigWithDataView)
+
+	// 1. 生成query, 按traceID排序, 通过scroll分页拉取时间范围内的span
+	query := &interfaces.DataViewQueryV1{
+		GlobalFilters: dvAdapter.genAnalyticsCondition(spanConf, params.ServiceName, ""),
+		Scroll:        interfaces.DEFAULT_SEARCH_SCROLL_STR,
+		SortParamsV1: interfaces.SortParamsV1{
+			Sort:      spanConf.TraceID.FieldName,
+			Direction: interfaces.ASC_DIRECTION,
+		},
+		ViewQueryCommonParams: interfaces.ViewQueryCommonParams{
+			Start:  params.Start,
+			End:    params.End,
+			Limit:  interfaces.MAX_SEARCH_SIZE,
+			Format: interfaces.Format_Original,
+		},
+	}
+
+	scrollIDs := make([]string, 0)
+	defer func() {
+		// 删除本次分页查询使用的scrollID
+		go dvAdapter.clearScrollIDs(context.Background(), scrollIDs)
+	}()
+
+	// 2. 基于数据视图逐页查询Span数据
+	for {
+		viewInternalResp, err := dvAdapter.dvService.RetrieveSingleViewData(ctx, spanConf.DataView.ID, query)
+		if err != nil {
+			return err
+		}
+
+		scrollIDs = append(scrollIDs, viewInternalResp.ScrollId)
+		astNodes := viewInternalResp.Datas
+		briefSpans := make([]*interfaces.BriefSpan_, 0, len(astNodes))
+		for _, astNode := range astNodes {
+			abstractSpan := dvAdapter.extractRawSpan(spanConf, astNode, false)
+			briefSpans = append(briefSpans, &interfaces.BriefSpan_{
+				Key:          abstractSpan.SpanID,
+				TraceID:      abstractSpan.TraceID,
+				Name:         abstractSpan.Name,
+				SpanID:       abstractSpan.SpanID,
+				ParentSpanID: abstractSpan.ParentSpanID,
+				StartTime:    abstractSpan.StartTime,
+				EndTime:      abstractSpan.EndTime,
+				Duration:     abstractSpan.Duration,
+				Kind:         abstractSpan.Kind,
+				Status:       abstractSpan.Status,
+				ServiceName:  abstractSpan.ServiceName,
+			})
+		}
+
+		if err := handle(briefSpans); err != nil {
+			return err
+		}
+
+		if len(astNodes) < interfaces.MAX_SEARCH_SIZE {
+			return nil
+		}
+		query.ScrollId = viewInternalResp.ScrollId
+	}
+}
+
 /*
 	私有方法
 */
@@ -763,6 +934,44 @@ func (dvAdapter *dataViewAdapter) modifySpanMap(ctx context.Context, l *sync.Mut
 	_ = g.Wait()
 }
 
+// 生成链路分析的过滤条件, 服务名与操作名为空时不过滤
+func (dvAdapter *dataViewAdapter) genAnalyticsCondition(spanConf interfaces.SpanConfigWithDataView, serviceName, operation string) *cond.CondCfg {
+	subConds := make([]*cond.CondCfg, 0, 2)
+	if serviceName != "" {
+		subConds = append(subConds, &cond.CondCfg{
+			Operation: cond.OperationEq,
+			Name:      spanConf.ServiceName.FieldName,
+			ValueOptCfg: vopt.ValueOptCfg{
+				ValueFrom: vopt.ValueFrom_Const,
+				Value:     serviceName,
+			},
+		})
+	}
+
+	if operation != "" {
+		subConds = append(subConds, &cond.CondCfg{
+			Operation: cond.OperationEq,
+			Name:      spanConf.Name.FieldName,
+			ValueOptCfg: vopt.ValueOptCfg{
+				ValueFrom: vopt.ValueFrom_Const,
+				Value:     operation,
+			},
+		})
+	}
+
+	switch len(subConds) {
+	case 0:
+		return nil
+	case 1:
+		return subConds[0]
+	default:
+		return &cond.CondCfg{
+			Operation: cond.OperationAnd,
+			SubConds:  subConds,
+		}
+	}
+}
+
 // 批量清理scrollID
 func (dvAdapter *dataViewAdapter) clearScrollIDs(ctx context.Context, scrollIDs []string) {
 	if len(scrollIDs) == 0 {
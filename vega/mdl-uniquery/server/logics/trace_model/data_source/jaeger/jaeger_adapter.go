@@ -0,0 +1,1143 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package data_source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"uniquery/common"
+	cond "uniquery/common/condition"
+	vopt "uniquery/common/value_opt"
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+	"uniquery/logics"
+	"uniquery/logics/trace_model/data_source/span_stats"
+)
+
+const (
+	// Jaeger-on-OpenSearch中span引用父span的类型
+	JAEGER_REF_TYPE_CHILD_OF = "CHILD_OF"
+	// Jaeger开启tags-as-fields时, tag名称中的"."会被替换为"@"
+	JAEGER_TAG_DOT_REPLACEMENT = "@"
+	// 关联日志中span_id字段的默认名称, 与OTel日志数据模型保持一致
+	DEFAULT_RELATED_LOG_TRACE_ID_FIELD = "traceId"
+	DEFAULT_RELATED_LOG_SPAN_ID_FIELD  = "spanId"
+	// 扫描span时PIT的保活时间, 扫描结束后PIT自动过期
+	JAEGER_PIT_KEEP_ALIVE = "1m"
+)
+
+var (
+	jaOnce sync.Once
+	ja     interfaces.TraceModelAdapter
+
+	// Jaeger-on-OpenSearch span文档的默认字段映射
+	DEFAULT_JAEGER_FIELD_MAPPING = interfaces.SpanFieldMapping{
+		SpanIndex:    "jaeger-span-*",
+		TraceID:      "traceID",
+		SpanID:       "spanID",
+		ParentSpanID: "parentSpanID",
+		Name:         "operationName",
+		StartTime: interfaces.StartTimeConfig{
+			FieldName:   "startTime",
+			FieldFormat: interfaces.UNIX_MICROS,
+		},
+		Duration: interfaces.DurationConfig{
+			FieldName: "duration",
+			FieldUnit: interfaces.US,
+		},
+		ServiceName: "process.serviceName",
+		KindTag:     "span.kind",
+		StatusTag:   "otel.status_code",
+	}
+)
+
+// 数据连接中存储的Jaeger配置, access_token为Basic认证信息
+type JaegerDetailedConfig struct {
+	Address     string `json:"address"`
+	Protocol    string `json:"protocol"`
+	Username    string `json:"username,omitempty"`
+	AccessToken string `json:"access_token,omitempty"`
+}
+
+type JaegerClientConfig struct {
+	Address     string
+	Protocol    string
+	AccessToken string
+}
+
+// OpenSearch _search 的返回结构
+type jaegerSearchHit struct {
+	Source map[string]any `json:"_source"`
+	Sort   []any          `json:"sort"`
+}
+
+type jaegerSearchResult struct {
+	PitID string `json:"pit_id"`
+	Hits  struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []jaegerSearchHit `json:"hits"`
+	} `json:"hits"`
+	Aggregations map[string]struct {
+		Buckets []struct {
+			Key      any   `json:"key"`
+			DocCount int64 `json:"doc_count"`
+		} `json:"buckets"`
+	} `json:"aggregations"`
+}
+
+type jaegerAdapter struct {
+	appSetting *common.AppSetting
+	dcAccess   interfaces.DataConnectionAccess
+	httpClient rest.HTTPClient
+}
+
+func NewJaegerAdapter(appSetting *common.AppSetting) interfaces.TraceModelAdapter {
+	jaOnce.Do(func() {
+		ja = &jaegerAdapter{
+			appSetting: appSetting,
+			dcAccess:   logics.DCAccess,
+			httpClient: common.NewHTTPClient(),
+		}
+	})
+	return ja
+}
+
+func (jAdapter *jaegerAdapter) GetSpanList(ctx context.Context, model interfaces.TraceModel, params interfaces.SpanListQueryParams) (spanList []interfaces.SpanListEntry, total int64, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 通过jaegerAdapter获取span列表")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	// 1. 获取字段映射与客户端配置
+	mapping := jAdapter.getFieldMapping(model)
+	clientConf, err := jAdapter.getClientConfig(ctx, model)
+	if err != nil {
+		return []interfaces.SpanListEntry{}, 0, err
+	}
+
+	// 2. 生成查询语句
+	if params.Sort != interfaces.DEFAULT_SORT {
+		errDetails := fmt.Sprintf("Jaeger does not support this sort field %v", params.Sort)
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return []interfaces.SpanListEntry{}, 0, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_InvalidParameter_Sort).
+			WithErrorDetails(errDetails)
+	}
+
+	filters := make([]any, 0)
+	if params.TraceID != "_all" {
+		filters = append(filters, termQuery(mapping.TraceID, params.TraceID))
+	}
+
+	if params.Condition != nil {
+		query, err := jAdapter.convertQueryCondition(mapping, params.Condition)
+		if err != nil {
+			logger.Error(err.Error())
+			o11y.Error(ctx, err.Error())
+			return []interfaces.SpanListEntry{}, 0, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_DataView_InvalidParameter_Filters).
+				WithErrorDetails(err.Error())
+		}
+		filters = append(filters, query)
+	}
+
+	body := map[string]any{
+		"from":             params.Offset,
+		"size":             params.Limit,
+		"track_total_hits": true,
+		"query":            boolFilterQuery(filters),
+		"sort": []any{
+			map[string]any{mapping.StartTime.FieldName: map[string]any{"order": params.Direction}},
+		},
+	}
+
+	// 3. 查询span列表
+	result, err := jAdapter.search(ctx, clientConf, mapping.SpanIndex, body)
+	if err != nil {
+		return []interfaces.SpanListEntry{}, 0, err
+	}
+
+	// 4. 解析查询结果
+	spanList = make([]interfaces.SpanListEntry, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		abstractSpan := jAdapter.extractRawSpan(mapping, hit.Source)
+		spanList = append(spanList, jAdapter.genSpanDetail(hit.Source, abstractSpan))
+	}
+
+	return spanList, result.Hits.Total.Value, nil
+}
+
+func (jAdapter *jaegerAdapter) GetSpan(ctx context.Context, model interfaces.TraceModel, params interfaces.SpanQueryParams) (spanDetail interfaces.SpanDetail, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 通过jaegerAdapter获取span详情")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	mapping := jAdapter.getFieldMapping(model)
+	clientConf, err := jAdapter.getClientConfig(ctx, model)
+	if err != nil {
+		return interfaces.SpanDetail{}, err
+	}
+
+	// 1. 根据traceID与spanID构建查询语句
+	filters := []any{termQuery(mapping.SpanID, params.SpanID)}
+	if params.TraceID != "_all" {
+		filters = append(filters, termQuery(mapping.TraceID, params.TraceID))
+	}
+
+	body := map[string]any{
+		"size":  1,
+		"query": boolFilterQuery(filters),
+	}
+
+	// 2. 查询span
+	result, err := jAdapter.search(ctx, clientConf, mapping.SpanIndex, body)
+	if err != nil {
+		return interfaces.SpanDetail{}, err
+	}
+
+	if len(result.Hits.Hits) == 0 {
+		errDetails := fmt.Sprintf("The span whose spanID equals %s was not found!", params.SpanID)
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return interfaces.SpanDetail{}, rest.NewHTTPError(ctx, http.StatusNotFound,
+			uerrors.Uniquery_TraceModel_SpanNotFound).WithErrorDetails(errDetails)
+	}
+
+	rawSpan := result.Hits.Hits[0].Source
+	abstractSpan := jAdapter.extractRawSpan(mapping, rawSpan)
+	return jAdapter.genSpanDetail(rawSpan, abstractSpan), nil
+}
+
+func (jAdapter *jaegerAdapter) GetSpanMap(ctx context.Context, model interfaces.TraceModel,
+	params interfaces.TraceQueryParams) (briefSpanMap map[string]*interfaces.BriefSpan_,
+	detailSpanMap map[string]interfaces.SpanDetail, err error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 通过jaegerAdapter获取span map")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	mapping := jAdapter.getFieldMapping(model)
+	clientConf, err := jAdapter.getClientConfig(ctx, model)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 1. 按开始时间与spanID排序, 通过search_after分页拉取trace下的全部span
+	body := map[string]any{
+		"size":  interfaces.MAX_SEARCH_SIZE,
+		"query": termQuery(mapping.TraceID, params.TraceID),
+		"sort": []any{
+			map[string]any{mapping.StartTime.FieldName: map[string]any{"order": "asc"}},
+			map[string]any{mapping.SpanID: map[string]any{"order": "asc"}},
+		},
+	}
+
+	briefSpanMap = make(map[string]*interfaces.BriefSpan_)
+	detailSpanMap = make(map[string]interfaces.SpanDetail)
+	for {
+		result, err := jAdapter.search(ctx, clientConf, mapping.SpanIndex, body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		hits := result.Hits.Hits
+		for _, hit := range hits {
+			abstractSpan := jAdapter.extractRawSpan(mapping, hit.Source)
+			briefSpanMap[abstractSpan.SpanID] = &interfaces.BriefSpan_{
+				Key:          abstractSpan.SpanID,
+				Name:         abstractSpan.Name,
+				SpanID:       abstractSpan.SpanID,
+				ParentSpanID: abstractSpan.ParentSpanID,
+				StartTime:    abstractSpan.StartTime,
+				EndTime:      abstractSpan.EndTime,
+				Duration:     abstractSpan.Duration,
+				Kind:         abstractSpan.Kind,
+				Status:       abstractSpan.Status,
+				ServiceName:  abstractSpan.ServiceName,
+				Children:     make([]*interfaces.BriefSpan_, 0),
+			}
+			detailSpanMap[abstractSpan.SpanID] = jAdapter.genSpanDetail(hit.Source, abstractSpan)
+		}
+
+		if len(hits) < interfaces.MAX_SEARCH_SIZE {
+			break
+		}
+		body["search_after"] = hits[len(hits)-1].Sort
+	}
+
+	if len(briefSpanMap) == 0 {
+		errDetails := fmt.Sprintf("The trace whose id equal to %v was not found in jaeger storage", params.TraceID)
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return nil, nil, rest.NewHTTPError(ctx, http.StatusNotFound, uerrors.Uniquery_TraceModel_TraceNotFound).
+			WithErrorDetails(errDetails)
+	}
+
+	return briefSpanMap, detailSpanMap, nil
+}
+
+func (jAdapter *jaegerAdapter) GetRelatedLogCountMap(ctx context.Context, model interfaces.TraceModel, params interfaces.TraceQueryParams) (countMap map[string]int64, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 通过jaegerAdapter获取关联日志的统计信息")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	relatedLogConf, _ := model.RelatedLogConfig.(interfaces.RelatedLogConfigWithDataConnection)
+	if relatedLogConf.LogIndex == "" {
+		return map[string]int64{}, nil
+	}
+
+	clientConf, err := jAdapter.getClientConfig(ctx, model)
+	if err != nil {
+		return map[string]int64{}, err
+	}
+
+	// 1. 按span_id字段聚合trace下的日志条数
+	traceIDField, spanIDField := jAdapter.getRelatedLogFields(relatedLogConf)
+	body := map[string]any{
+		"size":  0,
+		"query": termQuery(traceIDField, params.TraceID),
+		"aggs": map[string]any{
+			"span_ids": map[string]any{
+				"terms": map[string]any{
+					"field": spanIDField,
+					"size":  interfaces.MAX_SEARCH_SIZE,
+				},
+			},
+		},
+	}
+
+	result, err := jAdapter.search(ctx, clientConf, relatedLogConf.LogIndex, body)
+	if err != nil {
+		return map[string]int64{}, err
+	}
+
+	countMap = make(map[string]int64)
+	for _, bucket := range result.Aggregations["span_ids"].Buckets {
+		countMap[stringValue(bucket.Key)] = bucket.DocCount
+	}
+
+	return countMap, nil
+}
+
+func (jAdapter *jaegerAdapter) GetSpanRelatedLogList(ctx context.Context, model interfaces.TraceModel, params interfaces.RelatedLogListQueryParams) (entries []interfaces.RelatedLogListEntry, total int64, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 通过jaegerAdapter获取关联日志列表")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	relatedLogConf, _ := model.RelatedLogConfig.(interfaces.RelatedLogConfigWithDataConnection)
+	if relatedLogConf.LogIndex == "" {
+		return []interfaces.RelatedLogListEntry{}, 0, nil
+	}
+
+	clientConf, err := jAdapter.getClientConfig(ctx, model)
+	if err != nil {
+		return []interfaces.RelatedLogListEntry{}, 0, err
+	}
+
+	// 1. 根据traceID与spanID构建查询语句
+	traceIDField, spanIDField := jAdapter.getRelatedLogFields(relatedLogConf)
+	filters := make([]any, 0)
+	if params.TraceID != "_all" {
+		filters = append(filters, termQuery(traceIDField, params.TraceID))
+	}
+
+	if params.SpanID != "_all" {
+		filters = append(filters, termQuery(spanIDField, params.SpanID))
+	}
+
+	if params.Condition != nil {
+		query, err := jAdapter.convertQueryCondition(interfaces.SpanFieldMapping{}, params.Condition)
+		if err != nil {
+			logger.Error(err.Error())
+			o11y.Error(ctx, err.Error())
+			return []interfaces.RelatedLogListEntry{}, 0, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_DataView_InvalidParameter_Filters).
+				WithErrorDetails(err.Error())
+		}
+		filters = append(filters, query)
+	}
+
+	body := map[string]any{
+		"from":             params.Offset,
+		"size":             params.Limit,
+		"track_total_hits": true,
+		"query":            boolFilterQuery(filters),
+		"sort": []any{
+			map[string]any{params.Sort: map[string]any{"order": params.Direction, "unmapped_type": "date"}},
+		},
+	}
+
+	// 2. 查询关联日志
+	result, err := jAdapter.search(ctx, clientConf, relatedLogConf.LogIndex, body)
+	if err != nil {
+		return []interfaces.RelatedLogListEntry{}, 0, err
+	}
+
+	entries = make([]interfaces.RelatedLogListEntry, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		rawLog := hit.Source
+		if rawLog == nil {
+			rawLog = make(map[string]any)
+		}
+		rawLog["__trace_id"] = stringValue(getFieldValue(rawLog, traceIDField))
+		rawLog["__span_id"] = stringValue(getFieldValue(rawLog, spanIDField))
+		entries = append(entries, rawLog)
+	}
+
+	return entries, result.Hits.Total.Value, nil
+}
+
+func (jAdapter *jaegerAdapter) GetSpanStats(ctx context.Context, model interfaces.TraceModel, params interfaces.SpanStatsQueryParams) (buckets []interfaces.SpanStatsBucket, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 通过jaegerAdapter聚合统计span")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	mapping := jAdapter.getFieldMapping(model)
+	clientConf, err := jAdapter.getClientConfig(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 生成聚合配置, 开始时间字段为数值类型, 使用数值直方图按其单位分桶
+	timeScale := startTimeUnitsPerMs(mapping)
+	aggConf := span_stats.AggConfig{
+		ServiceField:  mapping.ServiceName,
+		ErrorFilter:   jAdapter.genErrorFilter(mapping),
+		DurationField: mapping.Duration.FieldName,
+		TimeField:     mapping.StartTime.FieldName,
+		Interval:      params.Interval * timeScale,
+		MinBound:      params.Start * timeScale,
+		MaxBound:      params.End * timeScale,
+	}
+	if params.GroupByOperation {
+		aggConf.OperationField = mapping.Name
+	}
+
+	durationToMicros := float64(1)
+	switch mapping.Duration.FieldUnit {
+	case interfaces.MS:
+		durationToMicros = 1e3
+	case interfaces.NS:
+		durationToMicros = 1e-3
+	}
+
+	// 2. 执行聚合查询
+	body := map[string]any{
+		"size":  0,
+		"query": boolFilterQuery(jAdapter.genAnalyticsFilters(mapping, params.TraceAnalyticsQueryParams, params.Operation)),
+		"aggs":  span_stats.GenSpanStatsAggs(aggConf),
+	}
+
+	respBody, err := jAdapter.rawSearch(ctx, clientConf, mapping.SpanIndex, body)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err = span_stats.ParseSpanStatsBuckets(respBody, timeScale, durationToMicros)
+	if err != nil {
+		errDetails := fmt.Sprintf("Failed to parse span stats from jaeger storage, err: %v", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_TraceModel_InternalError_ParseSpanStatsFailed).
+			WithErrorDetails(errDetails)
+	}
+
+	return buckets, nil
+}
+
+func (jAdapter *jaegerAdapter) ScanBriefSpans(ctx context.Context, model interfaces.TraceModel, params interfaces.TraceAnalyticsQueryParams,
+	handle func(briefSpans []*interfaces.BriefSpan_) error) (err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 通过jaegerAdapter扫描时间范围内的span")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	mapping := jAdapter.getFieldMapping(model)
+	clientConf, err := jAdapter.getClientConfig(ctx, model)
+	if err != nil {
+		return err
+	}
+
+	// 1. 在时间范围内的span上打开PIT, 翻页期间数据不变, 不会因写入而重复或遗漏.
+	// PIT按keep_alive自动过期, 每次翻页续期
+	pitID, err := jAdapter.openPointInTime(ctx, clientConf, mapping.SpanIndex)
+	if err != nil {
+		return err
+	}
+
+	// 2. 仅拉取抽象span所需的字段, 按traceID与spanID排序后通过search_after分页
+	body := map[string]any{
+		"size":  interfaces.MAX_SEARCH_SIZE,
+		"query": boolFilterQuery(jAdapter.genAnalyticsFilters(mapping, params, "")),
+		"_source": []string{
+			mapping.TraceID, mapping.SpanID, mapping.ParentSpanID, mapping.Name, mapping.StartTime.FieldName,
+			mapping.Duration.FieldName, mapping.ServiceName, "references", "tags", "tag",
+		},
+		"sort": []any{
+			map[string]any{mapping.TraceID: map[string]any{"order": "asc"}},
+			map[string]any{mapping.SpanID: map[string]any{"order": "asc"}},
+		},
+	}
+
+	// 3. 逐页拉取, 使用PIT时不指定索引
+	for {
+		body["pit"] = map[string]any{"id": pitID, "keep_alive": JAEGER_PIT_KEEP_ALIVE}
+		result, err := jAdapter.search(ctx, clientConf, "", body)
+		if err != nil {
+			return err
+		}
+		if result.PitID != "" {
+			pitID = result.PitID
+		}
+
+		hits := result.Hits.Hits
+		briefSpans := make([]*interfaces.BriefSpan_, 0, len(hits))
+		for _, hit := range hits {
+			abstractSpan := jAdapter.extractRawSpan(mapping, hit.Source)
+			briefSpans = append(briefSpans, &interfaces.BriefSpan_{
+				Key:          abstractSpan.SpanID,
+				TraceID:      abstractSpan.TraceID,
+				Name:         abstractSpan.Name,
+				SpanID:       abstractSpan.SpanID,
+				ParentSpanID: abstractSpan.ParentSpanID,
+				StartTime:    abstractSpan.StartTime,
+				EndTime:      abstractSpan.EndTime,
+				Duration:     abstractSpan.Duration,
+				Kind:         abstractSpan.Kind,
+				Status:       abstractSpan.Status,
+				ServiceName:  abstractSpan.ServiceName,
+			})
+		}
+
+		if err := handle(briefSpans); err != nil {
+			return err
+		}
+
+		if len(hits) < interfaces.MAX_SEARCH_SIZE {
+			return nil
+		}
+		body["search_after"] = hits[len(hits)-1].Sort
+	}
+}
+
+/*
+	私有方法
+*/
+
+// 合并用户配置的字段映射与默认映射
+func (jAdapter *jaegerAdapter) getFieldMapping(model interfaces.TraceModel) interfaces.SpanFieldMapping {
+	mapping := DEFAULT_JAEGER_FIELD_MAPPING
+
+	spanConf, _ := model.SpanConfig.(interfaces.SpanConfigWithDataConnection)
+	if spanConf.FieldMapping == nil {
+		return mapping
+	}
+
+	custom := spanConf.FieldMapping
+	if custom.SpanIndex != "" {
+		mapping.SpanIndex = custom.SpanIndex
+	}
+	if custom.TraceID != "" {
+		mapping.TraceID = custom.TraceID
+	}
+	if custom.SpanID != "" {
+		mapping.SpanID = custom.SpanID
+	}
+	if custom.ParentSpanID != "" {
+		mapping.ParentSpanID = custom.ParentSpanID
+	}
+	if custom.Name != "" {
+		mapping.Name = custom.Name
+	}
+	if custom.StartTime.FieldName != "" {
+		mapping.StartTime = custom.StartTime
+	}
+	if custom.Duration.FieldName != "" {
+		mapping.Duration = custom.Duration
+	}
+	if custom.ServiceName != "" {
+		mapping.ServiceName = custom.ServiceName
+	}
+	if custom.KindTag != "" {
+		mapping.KindTag = custom.KindTag
+	}
+	if custom.StatusTag != "" {
+		mapping.StatusTag = custom.StatusTag
+	}
+
+	return mapping
+}
+
+func (jAdapter *jaegerAdapter) getRelatedLogFields(conf interfaces.RelatedLogConfigWithDataConnection) (string, string) {
+	traceIDField := DEFAULT_RELATED_LOG_TRACE_ID_FIELD
+	if conf.TraceID.FieldName != "" {
+		traceIDField = conf.TraceID.FieldName
+	}
+
+	spanIDField := DEFAULT_RELATED_LOG_SPAN_ID_FIELD
+	if len(conf.SpanID.FieldNames) > 0 && conf.SpanID.FieldNames[0] != "" {
+		spanIDField = conf.SpanID.FieldNames[0]
+	}
+
+	return traceIDField, spanIDField
+}
+
+// 查询数据连接详情, 并生成访问OpenSearch的客户端配置
+func (jAdapter *jaegerAdapter) getClientConfig(ctx context.Context, model interfaces.TraceModel) (JaegerClientConfig, error) {
+	spanConf, _ := model.SpanConfig.(interfaces.SpanConfigWithDataConnection)
+	conn, isExist, err := jAdapter.dcAccess.GetDataConnectionByID(ctx, spanConf.DataConnection.ID)
+	if err != nil {
+		logger.Errorf("Get data connection by id failed, err: %s", err.Error())
+		return JaegerClientConfig{}, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_TraceModel_InternalError_GetDataConnectionByIDFailed).
+			WithErrorDetails(err.Error())
+	}
+
+	if !isExist {
+		errDetails := fmt.Sprintf("Data connection whose id equal to %s was not found", spanConf.DataConnection.ID)
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return JaegerClientConfig{}, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_TraceModel_InternalError_GetDataConnectionByIDFailed).
+			WithErrorDetails(errDetails)
+	}
+
+	err = jAdapter.processDataConnection(ctx, conn)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return JaegerClientConfig{}, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_TraceModel_InternalError_ProcessDataConnectionFailed).
+			WithErrorDetails(err.Error())
+	}
+
+	conf, _ := conn.DataSourceConfig.(JaegerDetailedConfig)
+	return JaegerClientConfig{
+		Address:     conf.Address,
+		Protocol:    conf.Protocol,
+		AccessToken: conf.AccessToken,
+	}, nil
+}
+
+func (jAdapter *jaegerAdapter) processDataConnection(_ context.Context, conn *interfaces.DataConnection) error {
+	if conn.DataSourceType != interfaces.SOURCE_TYPE_JAEGER {
+		errDetails := fmt.Sprintf("Invalid data_source_type: %v", conn.DataSourceType)
+		logger.Error(errDetails)
+		return errors.New(errDetails)
+	}
+
+	b, err := sonic.Marshal(conn.DataSourceConfig)
+	if err != nil {
+		errDetails := fmt.Sprintf("Marshal field config failed, err: %v", err.Error())
+		logger.Error(errDetails)
+		return err
+	}
+
+	conf := JaegerDetailedConfig{}
+	err = sonic.Unmarshal(b, &conf)
+	if err != nil {
+		errDetails := fmt.Sprintf("Field config cannot be unmarshaled to JaegerDetailedConfig, err: %v", err.Error())
+		logger.Error(errDetails)
+		return err
+	}
+
+	conn.DataSourceConfig = conf
+	return nil
+}
+
+// 将condition.CondCfg转成OpenSearch的查询语句, span元字段会被替换为映射后的原始字段
+func (jAdapter *jaegerAdapter) convertQueryCondition(mapping interfaces.SpanFieldMapping, condCfg *cond.CondCfg) (map[string]any, error) {
+	switch condCfg.Operation {
+	case cond.OperationAnd, cond.OperationOr:
+		subQueries := make([]any, 0, len(condCfg.SubConds))
+		for _, subCond := range condCfg.SubConds {
+			subQuery, err := jAdapter.convertQueryCondition(mapping, subCond)
+			if err != nil {
+				return nil, err
+			}
+			subQueries = append(subQueries, subQuery)
+		}
+
+		if condCfg.Operation == cond.OperationAnd {
+			return boolFilterQuery(subQueries), nil
+		}
+		return map[string]any{
+			"bool": map[string]any{
+				"should":               subQueries,
+				"minimum_should_match": 1,
+			},
+		}, nil
+	}
+
+	if condCfg.ValueOptCfg.ValueFrom != vopt.ValueFrom_Const {
+		return nil, fmt.Errorf("the jaeger condition does not support value from type(%s)", condCfg.ValueFrom)
+	}
+
+	switch condCfg.Operation {
+	case cond.OperationEq:
+		return termQuery(jAdapter.mapFieldName(mapping, condCfg.Name), condCfg.Value), nil
+	case cond.OperationIn:
+		vals, ok := condCfg.Value.([]any)
+		if !ok {
+			return nil, errors.New("the in condition right value should be an array")
+		}
+		return map[string]any{
+			"terms": map[string]any{jAdapter.mapFieldName(mapping, condCfg.Name): vals},
+		}, nil
+	case cond.OperationRange:
+		if condCfg.Name != "__start_time" && condCfg.Name != interfaces.MetaField_Timestamp && condCfg.Name != "timestamp" {
+			return nil, errors.New("the jaeger only supports range queries for fields in [__start_time, @timestamp, timestamp]")
+		}
+
+		val, ok := condCfg.Value.([]any)
+		if !ok || len(val) != 2 {
+			return nil, errors.New("the range condition right value should be an array of length 2")
+		}
+
+		startTime, ok1 := val[0].(float64)
+		endTime, ok2 := val[1].(float64)
+		if !ok1 || !ok2 || startTime > endTime {
+			return nil, errors.New("the range condition right value should be [start, end] in unix_milli")
+		}
+
+		// 条件中的时间为毫秒, 需按开始时间字段的格式换算
+		scale := startTimeUnitsPerMs(mapping)
+		return map[string]any{
+			"range": map[string]any{
+				mapping.StartTime.FieldName: map[string]any{
+					"gte": int64(startTime) * scale,
+					"lte": int64(endTime) * scale,
+				},
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("the jaeger does not support operation %v", condCfg.Operation)
+	}
+}
+
+// 生成链路分析的过滤条件: 开始时间范围, 以及可选的服务名与操作名
+func (jAdapter *jaegerAdapter) genAnalyticsFilters(mapping interfaces.SpanFieldMapping, params interfaces.TraceAnalyticsQueryParams, operation string) []any {
+	scale := startTimeUnitsPerMs(mapping)
+	filters := []any{
+		map[string]any{
+			"range": map[string]any{
+				mapping.StartTime.FieldName: map[string]any{
+					"gte": params.Start * scale,
+					"lte": params.End * scale,
+				},
+			},
+		},
+	}
+
+	if params.ServiceName != "" {
+		filters = append(filters, termQuery(mapping.ServiceName, params.ServiceName))
+	}
+	if operation != "" {
+		filters = append(filters, termQuery(mapping.Name, operation))
+	}
+	return filters
+}
+
+// 生成错误span的过滤条件, 与extractRawSpan中状态的判断保持一致.
+// Jaeger将标签存储为nested的tags数组, 开启tags-as-fields时存储在tag对象中
+func (jAdapter *jaegerAdapter) genErrorFilter(mapping interfaces.SpanFieldMapping) map[string]any {
+	nestedTag := func(key string, values []any) map[string]any {
+		return map[string]any{
+			"nested": map[string]any{
+				"path": "tags",
+				"query": boolFilterQuery([]any{
+					termQuery("tags.key", key),
+					map[string]any{"terms": map[string]any{"tags.value": values}},
+				}),
+				"ignore_unmapped": true,
+			},
+		}
+	}
+
+	statusValues := []any{"error", "Error", "ERROR"}
+	statusTagField := "tag." + strings.ReplaceAll(mapping.StatusTag, ".", JAEGER_TAG_DOT_REPLACEMENT)
+	return map[string]any{
+		"bool": map[string]any{
+			"should": []any{
+				nestedTag(mapping.StatusTag, statusValues),
+				nestedTag("error", []any{"true"}),
+				map[string]any{"terms": map[string]any{statusTagField: statusValues}},
+				termQuery("tag.error", true),
+			},
+			"minimum_should_match": 1,
+		},
+	}
+}
+
+// 将span元字段名转换为映射后的原始字段名
+func (jAdapter *jaegerAdapter) mapFieldName(mapping interfaces.SpanFieldMapping, name string) string {
+	switch name {
+	case "__trace_id":
+		return mapping.TraceID
+	case "__span_id":
+		return mapping.SpanID
+	case "__name":
+		return mapping.Name
+	case "__service_name":
+		return mapping.ServiceName
+	default:
+		return name
+	}
+}
+
+// 将Jaeger span文档抽象为Span
+func (jAdapter *jaegerAdapter) extractRawSpan(mapping interfaces.SpanFieldMapping, rawSpan map[string]any) interfaces.AbstractSpan {
+	abstractSpan := interfaces.AbstractSpan{}
+
+	// 1. 提取Name, TraceID, SpanID
+	abstractSpan.Name = stringValue(getFieldValue(rawSpan, mapping.Name))
+	abstractSpan.TraceID = stringValue(getFieldValue(rawSpan, mapping.TraceID))
+	abstractSpan.SpanID = stringValue(getFieldValue(rawSpan, mapping.SpanID))
+
+	// 2. 提取ParentSpanID, 字段缺失时从references中取CHILD_OF引用
+	abstractSpan.ParentSpanID = stringValue(getFieldValue(rawSpan, mapping.ParentSpanID))
+	if abstractSpan.ParentSpanID == "" {
+		refs, _ := rawSpan["references"].([]any)
+		for _, refInf := range refs {
+			ref, _ := refInf.(map[string]any)
+			if stringValue(ref["refType"]) == JAEGER_REF_TYPE_CHILD_OF {
+				abstractSpan.ParentSpanID = stringValue(ref["spanID"])
+				break
+			}
+		}
+	}
+
+	// 3. 提取StartTime, EndTime和Duration, 统一换算为微秒
+	startTime := toInt64(getFieldValue(rawSpan, mapping.StartTime.FieldName))
+	switch mapping.StartTime.FieldFormat {
+	case interfaces.UNIX_MILLIS:
+		startTime *= 1e3
+	case interfaces.UNIX_NANOS:
+		startTime /= 1e3
+	}
+
+	duration := toInt64(getFieldValue(rawSpan, mapping.Duration.FieldName))
+	switch mapping.Duration.FieldUnit {
+	case interfaces.MS:
+		duration *= 1e3
+	case interfaces.NS:
+		duration /= 1e3
+	}
+
+	abstractSpan.StartTime = startTime
+	abstractSpan.Duration = duration
+	abstractSpan.EndTime = startTime + duration
+
+	// 4. 提取Kind, Jaeger中internal span不携带span.kind
+	kind := strings.ToLower(stringValue(getTagValue(rawSpan, mapping.KindTag)))
+	if kind == "" {
+		abstractSpan.Kind = interfaces.SPAN_KIND_INTERNAL
+	} else if val, ok := interfaces.SPAN_KIND_MAP[kind]; ok {
+		abstractSpan.Kind = val
+	} else {
+		abstractSpan.Kind = interfaces.SPAN_KIND_UNSPECIFIED
+	}
+
+	// 5. 提取Status, 兼容Jaeger原生的error标签
+	status := strings.ToLower(stringValue(getTagValue(rawSpan, mapping.StatusTag)))
+	if val, ok := interfaces.SPAN_STATUS_MAP[status]; ok {
+		abstractSpan.Status = val
+	} else if stringValue(getTagValue(rawSpan, "error")) == "true" {
+		abstractSpan.Status = interfaces.SPAN_STATUS_ERROR
+	} else {
+		abstractSpan.Status = interfaces.SPAN_STATUS_UNSET
+	}
+
+	// 6. 提取ServiceName
+	abstractSpan.ServiceName = stringValue(getFieldValue(rawSpan, mapping.ServiceName))
+
+	return abstractSpan
+}
+
+// 根据abstractSpan补充rawSpan
+func (jAdapter *jaegerAdapter) genSpanDetail(rawSpan map[string]any, abstractSpan interfaces.AbstractSpan) map[string]any {
+	if rawSpan == nil {
+		rawSpan = make(map[string]any)
+	}
+
+	rawSpan["__trace_id"] = abstractSpan.TraceID
+	rawSpan["__span_id"] = abstractSpan.SpanID
+	rawSpan["__parent_span_id"] = abstractSpan.ParentSpanID
+	rawSpan["__name"] = abstractSpan.Name
+	rawSpan["__start_time"] = abstractSpan.StartTime
+	rawSpan["__end_time"] = abstractSpan.EndTime
+	rawSpan["__duration"] = abstractSpan.Duration
+	rawSpan["__kind"] = abstractSpan.Kind
+	rawSpan["__status"] = abstractSpan.Status
+	rawSpan["__service_name"] = abstractSpan.ServiceName
+	return rawSpan
+}
+
+func (jAdapter *jaegerAdapter) search(ctx context.Context, cfg JaegerClientConfig, index string, body map[string]any) (result jaegerSearchResult, err error) {
+	respBody, err := jAdapter.rawSearch(ctx, cfg, index, body)
+	if err != nil {
+		return result, err
+	}
+
+	err = sonic.Unmarshal(respBody, &result)
+	if err != nil {
+		errDetails := fmt.Sprintf("Failed to unmarshal respBody after searching jaeger storage, err: %v", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return result, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed).
+			WithErrorDetails(errDetails)
+	}
+
+	return result, nil
+}
+
+// 执行_search请求, 返回原始响应
+func (jAdapter *jaegerAdapter) rawSearch(ctx context.Context, cfg JaegerClientConfig, index string, body map[string]any) (respBody []byte, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 查询jaeger存储", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	// 使用PIT查询时不指定索引
+	url := fmt.Sprintf("%s://%s/_search", cfg.Protocol, cfg.Address)
+	if index != "" {
+		url = fmt.Sprintf("%s://%s/%s/_search?ignore_unavailable=true", cfg.Protocol, cfg.Address, index)
+	}
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+	if cfg.AccessToken != "" {
+		headers["Authorization"] = "Basic " + cfg.AccessToken
+	}
+
+	span.SetAttributes(attr.Key("jaeger_url").String(url))
+
+	respCode, respBody, err := jAdapter.httpClient.PostNoUnmarshal(ctx, url, headers, body)
+	if err != nil {
+		errDetails := fmt.Sprintf("Failed to search jaeger storage: %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed).
+			WithErrorDetails(errDetails)
+	}
+
+	if respCode != http.StatusOK {
+		errDetails := fmt.Sprintf("Failed to search jaeger storage: %s", string(respBody))
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed).
+			WithErrorDetails(errDetails)
+	}
+
+	return respBody, nil
+}
+
+// 在索引上打开PIT, 返回pit_id
+func (jAdapter *jaegerAdapter) openPointInTime(ctx context.Context, cfg JaegerClientConfig, index string) (pitID string, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 打开jaeger存储的PIT", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	url := fmt.Sprintf("%s://%s/%s/_search/point_in_time?keep_alive=%s", cfg.Protocol, cfg.Address, index, JAEGER_PIT_KEEP_ALIVE)
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+	if cfg.AccessToken != "" {
+		headers["Authorization"] = "Basic " + cfg.AccessToken
+	}
+
+	span.SetAttributes(attr.Key("jaeger_url").String(url))
+
+	respCode, respBody, err := jAdapter.httpClient.PostNoUnmarshal(ctx, url, headers, nil)
+	if err != nil {
+		errDetails := fmt.Sprintf("Failed to create point in time on jaeger storage: %s", err.Error())
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return "", rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed).
+			WithErrorDetails(errDetails)
+	}
+
+	if respCode != http.StatusOK {
+		errDetails := fmt.Sprintf("Failed to create point in time on jaeger storage: %s", string(respBody))
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return "", rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed).
+			WithErrorDetails(errDetails)
+	}
+
+	var pit struct {
+		PitID string `json:"pit_id"`
+	}
+	if err = sonic.Unmarshal(respBody, &pit); err != nil || pit.PitID == "" {
+		errDetails := fmt.Sprintf("Failed to parse point in time from jaeger storage: %s", string(respBody))
+		logger.Error(errDetails)
+		o11y.Error(ctx, errDetails)
+		return "", rest.NewHTTPError(ctx, http.StatusInternalServerError, uerrors.Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed).
+			WithErrorDetails(errDetails)
+	}
+
+	return pit.PitID, nil
+}
+
+// 开始时间字段每毫秒对应的单位数
+func startTimeUnitsPerMs(mapping interfaces.SpanFieldMapping) int64 {
+	switch mapping.StartTime.FieldFormat {
+	case interfaces.UNIX_MICROS:
+		return 1e3
+	case interfaces.UNIX_NANOS:
+		return 1e6
+	default:
+		return 1
+	}
+}
+
+func termQuery(field string, value any) map[string]any {
+	return map[string]any{
+		"term": map[string]any{field: value},
+	}
+}
+
+func boolFilterQuery(filters []any) map[string]any {
+	return map[string]any{
+		"bool": map[string]any{"filter": filters},
+	}
+}
+
+// 按字段路径取值, 优先取完整字段名, 其次按"."逐层取嵌套字段
+func getFieldValue(doc map[string]any, path string) any {
+	if path == "" {
+		return nil
+	}
+
+	if val, ok := doc[path]; ok {
+		return val
+	}
+
+	var cur any = doc
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = m[key]
+	}
+	return cur
+}
+
+// 读取span标签, 兼容tags数组与tags-as-fields两种存储方式
+func getTagValue(doc map[string]any, key string) any {
+	if key == "" {
+		return nil
+	}
+
+	tags, _ := doc["tags"].([]any)
+	for _, tagInf := range tags {
+		tag, _ := tagInf.(map[string]any)
+		if stringValue(tag["key"]) == key {
+			return tag["value"]
+		}
+	}
+
+	tagMap, _ := doc["tag"].(map[string]any)
+	if val, ok := tagMap[strings.ReplaceAll(key, ".", JAEGER_TAG_DOT_REPLACEMENT)]; ok {
+		return val
+	}
+	return nil
+}
+
+// 字段值转字符串, 缺失的字段返回空串
+func stringValue(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toInt64(val any) int64 {
+	switch v := val.(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case json.Number:
+		i, _ := v.Int64()
+		return i
+	case string:
+		i, _ := strconv.ParseInt(v, 10, 64)
+		return i
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,475 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package data_source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	rest "github.com/kweaver-ai/kweaver-go-lib/rest"
+	rmock "github.com/kweaver-ai/kweaver-go-lib/rest/mock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/common"
+	cond "uniquery/common/condition"
+	vopt "uniquery/common/value_opt"
+	uerrors "uniquery/errors"
+	"uniquery/interfaces"
+	umock "uniquery/interfaces/mock"
+)
+
+var (
+	testCtx = context.WithValue(context.Background(), rest.XLangKey, rest.DefaultLanguage)
+
+	jaegerModel = interfaces.TraceModel{
+		SpanSourceType: interfaces.SOURCE_TYPE_DATA_CONNECTION,
+		SpanConfig: interfaces.SpanConfigWithDataConnection{
+			DataConnection: interfaces.DataConnectionConfig{ID: "1"},
+		},
+		EnabledRelatedLog:    interfaces.RELATED_LOG_OPEN,
+		RelatedLogSourceType: interfaces.SOURCE_TYPE_DATA_CONNECTION,
+		RelatedLogConfig: interfaces.RelatedLogConfigWithDataConnection{
+			LogIndex: "otel-logs-*",
+		},
+	}
+
+	jaegerConn = &interfaces.DataConnection{
+		DataSourceType: interfaces.SOURCE_TYPE_JAEGER,
+		DataSourceConfig: map[string]any{
+			"address":      "opensearch:9200",
+			"protocol":     "http",
+			"access_token": "YWRtaW46YWRtaW4=",
+		},
+	}
+
+	rootSpanDoc = `{"traceID":"t1","spanID":"s1","operationName":"GET /api","startTime":1700000000000000,"duration":2000,` +
+		`"references":[],"tags":[{"key":"span.kind","type":"string","value":"server"}],"process":{"serviceName":"gateway"}}`
+	childSpanDoc = `{"traceID":"t1","spanID":"s2","operationName":"select","startTime":1700000000000500,"duration":1000,` +
+		`"references":[{"refType":"CHILD_OF","traceID":"t1","spanID":"s1"}],"tag":{"span@kind":"client","otel@status_code":"ERROR"},` +
+		`"process":{"serviceName":"db"}}`
+)
+
+func MockNewJaegerAdapter(dcAccess interfaces.DataConnectionAccess, httpClient rest.HTTPClient) *jaegerAdapter {
+	return &jaegerAdapter{
+		appSetting: &common.AppSetting{},
+		dcAccess:   dcAccess,
+		httpClient: httpClient,
+	}
+}
+
+func searchResp(total int, docs ...string) []byte {
+	hits := ""
+	for i, doc := range docs {
+		if i > 0 {
+			hits += ","
+		}
+		hits += `{"_source":` + doc + `,"sort":[1]}`
+	}
+	return []byte(fmt.Sprintf(`{"hits":{"total":{"value":%d},"hits":[%s]}}`, total, hits))
+}
+
+func Test_JaegerAdapter_GetSpanList(t *testing.T) {
+	Convey("Test GetSpanList", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDCAccess := umock.NewMockDataConnectionAccess(mockCtrl)
+		mockHttpClient := rmock.NewMockHTTPClient(mockCtrl)
+		jAdapter := MockNewJaegerAdapter(mockDCAccess, mockHttpClient)
+
+		params := interfaces.SpanListQueryParams{
+			TraceID: "_all",
+			PaginationQueryParams: interfaces.PaginationQueryParams{
+				Limit:     10,
+				Sort:      interfaces.DEFAULT_SORT,
+				Direction: "desc",
+			},
+		}
+
+		Convey("Get failed, caused by the error from method 'GetDataConnectionByID'", func() {
+			expectedErr := errors.New("some errors")
+			mockDCAccess.EXPECT().GetDataConnectionByID(gomock.Any(), "1").Return(nil, false, expectedErr)
+
+			_, _, err := jAdapter.GetSpanList(testCtx, jaegerModel, params)
+			So(err, ShouldResemble, rest.NewHTTPError(testCtx, http.StatusInternalServerError,
+				uerrors.Uniquery_TraceModel_InternalError_GetDataConnectionByIDFailed).WithErrorDetails(expectedErr.Error()))
+		})
+
+		Convey("Get failed, caused by the data connection is not jaeger", func() {
+			mockDCAccess.EXPECT().GetDataConnectionByID(gomock.Any(), "1").
+				Return(&interfaces.DataConnection{DataSourceType: interfaces.SOURCE_TYPE_TINGYUN}, true, nil)
+
+			_, _, err := jAdapter.GetSpanList(testCtx, jaegerModel, params)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_InternalError_ProcessDataConnectionFailed)
+		})
+
+		Convey("Get failed, caused by unsupported sort", func() {
+			mockDCAccess.EXPECT().GetDataConnectionByID(gomock.Any(), "1").Return(jaegerConn, true, nil)
+
+			p := params
+			p.Sort = "__duration"
+			_, _, err := jAdapter.GetSpanList(testCtx, jaegerModel, p)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_InvalidParameter_Sort)
+		})
+
+		Convey("Get failed, caused by the opensearch error", func() {
+			mockDCAccess.EXPECT().GetDataConnectionByID(gomock.Any(), "1").Return(jaegerConn, true, nil)
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(http.StatusUnauthorized, []byte("unauthorized"), nil)
+
+			_, _, err := jAdapter.GetSpanList(testCtx, jaegerModel, params)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed)
+		})
+
+		Convey("Get succeed", func() {
+			mockDCAccess.EXPECT().GetDataConnectionByID(gomock.Any(), "1").Return(jaegerConn, true, nil)
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(),
+				"http://opensearch:9200/jaeger-span-*/_search?ignore_unavailable=true", gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, headers map[string]string, _ any) (int, []byte, error) {
+					So(headers["Authorization"], ShouldEqual, "Basic YWRtaW46YWRtaW4=")
+					return http.StatusOK, searchResp(2, rootSpanDoc, childSpanDoc), nil
+				})
+
+			entries, total, err := jAdapter.GetSpanList(testCtx, jaegerModel, params)
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 2)
+			So(len(entries), ShouldEqual, 2)
+			So(entries[0]["__kind"], ShouldEqual, interfaces.SPAN_KIND_SERVER)
+			So(entries[0]["__status"], ShouldEqual, interfaces.SPAN_STATUS_UNSET)
+			So(entries[0]["__service_name"], ShouldEqual, "gateway")
+			So(entries[1]["__parent_span_id"], ShouldEqual, "s1")
+			So(entries[1]["__kind"], ShouldEqual, interfaces.SPAN_KIND_CLIENT)
+			So(entries[1]["__status"], ShouldEqual, interfaces.SPAN_STATUS_ERROR)
+			So(entries[1]["__end_time"], ShouldEqual, int64(1700000000001500))
+		})
+	})
+}
+
+func Test_JaegerAdapter_GetSpan(t *testing.T) {
+	Convey("Test GetSpan", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDCAccess := umock.NewMockDataConnectionAccess(mockCtrl)
+		mockHttpClient := rmock.NewMockHTTPClient(mockCtrl)
+		jAdapter := MockNewJaegerAdapter(mockDCAccess, mockHttpClient)
+		mockDCAccess.EXPECT().GetDataConnectionByID(gomock.Any(), "1").Return(jaegerConn, true, nil)
+
+		Convey("Get failed, caused by the span was not found", func() {
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(http.StatusOK, searchResp(0), nil)
+
+			_, err := jAdapter.GetSpan(testCtx, jaegerModel, interfaces.SpanQueryParams{TraceID: "t1", SpanID: "s3"})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_SpanNotFound)
+		})
+
+		Convey("Get succeed", func() {
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(http.StatusOK, searchResp(1, childSpanDoc), nil)
+
+			detail, err := jAdapter.GetSpan(testCtx, jaegerModel, interfaces.SpanQueryParams{TraceID: "t1", SpanID: "s2"})
+			So(err, ShouldBeNil)
+			So(detail["__span_id"], ShouldEqual, "s2")
+			So(detail["__name"], ShouldEqual, "select")
+		})
+	})
+}
+
+func Test_JaegerAdapter_GetSpanMap(t *testing.T) {
+	Convey("Test GetSpanMap", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDCAccess := umock.NewMockDataConnectionAccess(mockCtrl)
+		mockHttpClient := rmock.NewMockHTTPClient(mockCtrl)
+		jAdapter := MockNewJaegerAdapter(mockDCAccess, mockHttpClient)
+		mockDCAccess.EXPECT().GetDataConnectionByID(gomock.Any(), "1").Return(jaegerConn, true, nil)
+
+		Convey("Get failed, caused by the trace was not found", func() {
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(http.StatusOK, searchResp(0), nil)
+
+			_, _, err := jAdapter.GetSpanMap(testCtx, jaegerModel, interfaces.TraceQueryParams{TraceID: "t1"})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_TraceNotFound)
+		})
+
+		Convey("Get succeed", func() {
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(http.StatusOK, searchResp(2, rootSpanDoc, childSpanDoc), nil)
+
+			briefSpanMap, detailSpanMap, err := jAdapter.GetSpanMap(testCtx, jaegerModel, interfaces.TraceQueryParams{TraceID: "t1"})
+			So(err, ShouldBeNil)
+			So(len(briefSpanMap), ShouldEqual, 2)
+			So(len(detailSpanMap), ShouldEqual, 2)
+			So(briefSpanMap["s2"].ParentSpanID, ShouldEqual, "s1")
+			So(briefSpanMap["s1"].ParentSpanID, ShouldEqual, "")
+		})
+	})
+}
+
+func Test_JaegerAdapter_RelatedLog(t *testing.T) {
+	Convey("Test related log", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDCAccess := umock.NewMockDataConnectionAccess(mockCtrl)
+		mockHttpClient := rmock.NewMockHTTPClient(mockCtrl)
+		jAdapter := MockNewJaegerAdapter(mockDCAccess, mockHttpClient)
+
+		Convey("Return empty when log index is not configured", func() {
+			model := jaegerModel
+			model.RelatedLogConfig = interfaces.RelatedLogConfigWithDataConnection{}
+
+			countMap, err := jAdapter.GetRelatedLogCountMap(testCtx, model, interfaces.TraceQueryParams{TraceID: "t1"})
+			So(err, ShouldBeNil)
+			So(countMap, ShouldResemble, map[string]int64{})
+		})
+
+		Convey("GetRelatedLogCountMap succeed", func() {
+			mockDCAccess.EXPECT().GetDataConnectionByID(gomock.Any(), "1").Return(jaegerConn, true, nil)
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(),
+				"http://opensearch:9200/otel-logs-*/_search?ignore_unavailable=true", gomock.Any(), gomock.Any()).
+				Return(http.StatusOK, []byte(`{"hits":{"total":{"value":3},"hits":[]},`+
+					`"aggregations":{"span_ids":{"buckets":[{"key":"s1","doc_count":2},{"key":"s2","doc_count":1}]}}}`), nil)
+
+			countMap, err := jAdapter.GetRelatedLogCountMap(testCtx, jaegerModel, interfaces.TraceQueryParams{TraceID: "t1"})
+			So(err, ShouldBeNil)
+			So(countMap, ShouldResemble, map[string]int64{"s1": 2, "s2": 1})
+		})
+
+		Convey("GetSpanRelatedLogList succeed", func() {
+			mockDCAccess.EXPECT().GetDataConnectionByID(gomock.Any(), "1").Return(jaegerConn, true, nil)
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(http.StatusOK, searchResp(1, `{"traceId":"t1","spanId":"s1","body":"hello"}`), nil)
+
+			entries, total, err := jAdapter.GetSpanRelatedLogList(testCtx, jaegerModel, interfaces.RelatedLogListQueryParams{
+				TraceID: "t1",
+				SpanID:  "s1",
+				PaginationQueryParams: interfaces.PaginationQueryParams{
+					Limit: 10, Sort: interfaces.DEFAULT_SORT, Direction: "desc",
+				},
+			})
+			So(err, ShouldBeNil)
+			So(total, ShouldEqual, 1)
+			So(entries[0]["__span_id"], ShouldEqual, "s1")
+			So(entries[0]["__trace_id"], ShouldEqual, "t1")
+		})
+	})
+}
+
+func Test_JaegerAdapter_ConvertQueryCondition(t *testing.T) {
+	Convey("Test convertQueryCondition", t, func() {
+		jAdapter := MockNewJaegerAdapter(nil, nil)
+		mapping := DEFAULT_JAEGER_FIELD_MAPPING
+
+		Convey("Convert and/eq/range succeed", func() {
+			condCfg := &cond.CondCfg{
+				Operation: cond.OperationAnd,
+				SubConds: []*cond.CondCfg{
+					{
+						Name:        "__service_name",
+						Operation:   cond.OperationEq,
+						ValueOptCfg: vopt.ValueOptCfg{ValueFrom: vopt.ValueFrom_Const, Value: "gateway"},
+					},
+					{
+						Name:        "@timestamp",
+						Operation:   cond.OperationRange,
+						ValueOptCfg: vopt.ValueOptCfg{ValueFrom: vopt.ValueFrom_Const, Value: []any{float64(1000), float64(2000)}},
+					},
+				},
+			}
+
+			query, err := jAdapter.convertQueryCondition(mapping, condCfg)
+			So(err, ShouldBeNil)
+			So(query, ShouldResemble, map[string]any{
+				"bool": map[string]any{"filter": []any{
+					map[string]any{"term": map[string]any{"process.serviceName": "gateway"}},
+					map[string]any{"range": map[string]any{"startTime": map[string]any{"gte": int64(1000000), "lte": int64(2000000)}}},
+				}},
+			})
+		})
+
+		Convey("Convert failed, caused by unsupported operation", func() {
+			condCfg := &cond.CondCfg{
+				Name:        "__name",
+				Operation:   cond.OperationLike,
+				ValueOptCfg: vopt.ValueOptCfg{ValueFrom: vopt.ValueFrom_Const, Value: "GET"},
+			}
+
+			_, err := jAdapter.convertQueryCondition(mapping, condCfg)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_JaegerAdapter_GetFieldMapping(t *testing.T) {
+	Convey("Test getFieldMapping", t, func() {
+		jAdapter := MockNewJaegerAdapter(nil, nil)
+
+		Convey("Use custom mapping and fill the rest with defaults", func() {
+			model := interfaces.TraceModel{
+				SpanConfig: interfaces.SpanConfigWithDataConnection{
+					FieldMapping: &interfaces.SpanFieldMapping{
+						SpanIndex: "otel-v1-apm-span-*",
+						StartTime: interfaces.StartTimeConfig{FieldName: "startTimeMillis", FieldFormat: interfaces.UNIX_MILLIS},
+					},
+				},
+			}
+
+			mapping := jAdapter.getFieldMapping(model)
+			So(mapping.SpanIndex, ShouldEqual, "otel-v1-apm-span-*")
+			So(mapping.StartTime.FieldFormat, ShouldEqual, interfaces.UNIX_MILLIS)
+			So(mapping.TraceID, ShouldEqual, DEFAULT_JAEGER_FIELD_MAPPING.TraceID)
+		})
+	})
+}
+
+func Test_JaegerAdapter_GetSpanStats(t *testing.T) {
+	Convey("Test GetSpanStats", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDCAccess := umock.NewMockDataConnectionAccess(mockCtrl)
+		mockHttpClient := rmock.NewMockHTTPClient(mockCtrl)
+		jAdapter := MockNewJaegerAdapter(mockDCAccess, mockHttpClient)
+		mockDCAccess.EXPECT().GetDataConnectionByID(gomock.Any(), "1").Return(jaegerConn, true, nil)
+
+		params := interfaces.SpanStatsQueryParams{
+			TraceAnalyticsQueryParams: interfaces.TraceAnalyticsQueryParams{Start: 1700000000000, End: 1700000060000},
+			GroupByOperation:          true,
+			Interval:                  60000,
+		}
+
+		Convey("Get failed, caused by the opensearch error", func() {
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(http.StatusInternalServerError, []byte("error"), nil)
+
+			_, err := jAdapter.GetSpanStats(testCtx, jaegerModel, params)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed)
+		})
+
+		Convey("Get failed, caused by the invalid response", func() {
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(http.StatusOK, []byte("{"), nil)
+
+			_, err := jAdapter.GetSpanStats(testCtx, jaegerModel, params)
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_InternalError_ParseSpanStatsFailed)
+		})
+
+		Convey("Get succeed", func() {
+			respBody := `{"aggregations":{"services":{"buckets":[{"key":"order","doc_count":3,"operations":{"buckets":[` +
+				`{"key":"create","doc_count":3,"timeline":{"buckets":[{"key":1700000000000000,"doc_count":3,` +
+				`"errors":{"doc_count":1},"latency":{"values":{"50.0":1000,"95.0":2000,"99.0":3000}}}]}}]}}]}}}`
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _ map[string]string, body any) (int, []byte, error) {
+					aggs := body.(map[string]any)["aggs"].(map[string]any)
+					services := aggs["services"].(map[string]any)
+					So(services["terms"].(map[string]any)["field"], ShouldEqual, "process.serviceName")
+					operations := services["aggs"].(map[string]any)["operations"].(map[string]any)
+					timeline := operations["aggs"].(map[string]any)["timeline"].(map[string]any)
+					So(timeline["histogram"].(map[string]any)["interval"], ShouldEqual, int64(60000000))
+					return http.StatusOK, []byte(respBody), nil
+				})
+
+			buckets, err := jAdapter.GetSpanStats(testCtx, jaegerModel, params)
+			So(err, ShouldBeNil)
+			So(buckets, ShouldResemble, []interfaces.SpanStatsBucket{
+				{
+					ServiceName: "order", Operation: "create", Time: 1700000000000, Count: 3, ErrorCount: 1,
+					LatencyStats: interfaces.LatencyStats{P50: 1000, P95: 2000, P99: 3000},
+				},
+			})
+		})
+	})
+}
+
+func Test_JaegerAdapter_ScanBriefSpans(t *testing.T) {
+	Convey("Test ScanBriefSpans", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDCAccess := umock.NewMockDataConnectionAccess(mockCtrl)
+		mockHttpClient := rmock.NewMockHTTPClient(mockCtrl)
+		jAdapter := MockNewJaegerAdapter(mockDCAccess, mockHttpClient)
+		mockDCAccess.EXPECT().GetDataConnectionByID(gomock.Any(), "1").Return(jaegerConn, true, nil)
+
+		params := interfaces.TraceAnalyticsQueryParams{Start: 1700000000000, End: 1700000060000}
+		pitResp := []byte(`{"pit_id":"pit-1"}`)
+
+		Convey("Get failed, caused by the point in time error", func() {
+			mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+				Return(http.StatusNotFound, []byte(`{"error":"no such index"}`), nil)
+
+			err := jAdapter.ScanBriefSpans(testCtx, jaegerModel, params, func([]*interfaces.BriefSpan_) error {
+				return nil
+			})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed)
+		})
+
+		Convey("Get failed, caused by the opensearch error", func() {
+			gomock.InOrder(
+				mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+					Return(http.StatusOK, pitResp, nil),
+				mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(http.StatusOK, nil, errors.New("some errors")),
+			)
+
+			err := jAdapter.ScanBriefSpans(testCtx, jaegerModel, params, func([]*interfaces.BriefSpan_) error {
+				return nil
+			})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_InternalError_QueryJaegerStorageFailed)
+		})
+
+		Convey("Get succeed", func() {
+			gomock.InOrder(
+				mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+					DoAndReturn(func(ctx context.Context, url string, headers map[string]string, reqParam any) (int, []byte, error) {
+						So(url, ShouldContainSubstring, "/_search/point_in_time?keep_alive="+JAEGER_PIT_KEEP_ALIVE)
+						return http.StatusOK, pitResp, nil
+					}),
+				mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, url string, headers map[string]string, reqParam any) (int, []byte, error) {
+						// 使用PIT查询时不指定索引
+						So(url, ShouldEqual, "http://opensearch:9200/_search")
+						body := reqParam.(map[string]any)
+						So(body["pit"], ShouldResemble, map[string]any{"id": "pit-1", "keep_alive": JAEGER_PIT_KEEP_ALIVE})
+						return http.StatusOK, searchResp(2, rootSpanDoc, childSpanDoc), nil
+					}),
+			)
+
+			briefSpans := make([]*interfaces.BriefSpan_, 0)
+			err := jAdapter.ScanBriefSpans(testCtx, jaegerModel, params, func(page []*interfaces.BriefSpan_) error {
+				briefSpans = append(briefSpans, page...)
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(len(briefSpans), ShouldEqual, 2)
+			So(briefSpans[1].TraceID, ShouldEqual, briefSpans[0].TraceID)
+			So(briefSpans[1].ParentSpanID, ShouldEqual, "s1")
+			So(briefSpans[1].ServiceName, ShouldEqual, "db")
+			So(briefSpans[1].Status, ShouldEqual, interfaces.SPAN_STATUS_ERROR)
+		})
+
+		Convey("Scan stopped, caused by the error from handle", func() {
+			gomock.InOrder(
+				mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+					Return(http.StatusOK, pitResp, nil),
+				mockHttpClient.EXPECT().PostNoUnmarshal(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(http.StatusOK, searchResp(2, rootSpanDoc, childSpanDoc), nil),
+			)
+
+			expectedErr := errors.New("handle error")
+			err := jAdapter.ScanBriefSpans(testCtx, jaegerModel, params, func([]*interfaces.BriefSpan_) error {
+				return expectedErr
+			})
+			So(err, ShouldEqual, expectedErr)
+		})
+	})
+}
@@ -0,0 +1,241 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package span_stats 基于opensearch聚合统计span的调用量, 错误量与时延分位数,
+// 供落在opensearch上的链路数据源复用
+package span_stats
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+
+	"uniquery/interfaces"
+)
+
+const (
+	AGG_NAME_SERVICES   = "services"
+	AGG_NAME_OPERATIONS = "operations"
+	AGG_NAME_TIMELINE   = "timeline"
+	AGG_NAME_ERRORS     = "errors"
+	AGG_NAME_LATENCY    = "latency"
+)
+
+var latencyPercents = []float64{50, 95, 99}
+
+// 聚合配置, 字段名均为可直接用于聚合的原始字段
+type AggConfig struct {
+	ServiceField   string
+	OperationField string
+	ErrorFilter    map[string]any
+	// 时延取值, DurationScript不为空时优先使用脚本
+	DurationField  string
+	DurationScript string
+	// 时间分桶, Interval为0时不分桶; DateHistogram为false时使用数值直方图,
+	// 此时Interval, MinBound, MaxBound均为时间字段的单位
+	TimeField     string
+	DateHistogram bool
+	Interval      int64
+	MinBound      int64
+	MaxBound      int64
+}
+
+type aggBucket struct {
+	Key        any         `json:"key"`
+	DocCount   int64       `json:"doc_count"`
+	Operations *aggBuckets `json:"operations"`
+	Timeline   *aggBuckets `json:"timeline"`
+	Errors     *aggErrors  `json:"errors"`
+	Latency    *aggLatency `json:"latency"`
+}
+
+type aggBuckets struct {
+	Buckets []aggBucket `json:"buckets"`
+}
+
+type aggErrors struct {
+	DocCount int64 `json:"doc_count"`
+}
+
+type aggLatency struct {
+	Values map[string]*float64 `json:"values"`
+}
+
+type aggResponse struct {
+	Aggregations struct {
+		Services aggBuckets `json:"services"`
+	} `json:"aggregations"`
+}
+
+// 生成按服务, 操作及时间分桶的聚合
+func GenSpanStatsAggs(conf AggConfig) map[string]any {
+	latency := map[string]any{
+		"percents": latencyPercents,
+	}
+	if conf.DurationScript != "" {
+		latency["script"] = map[string]any{"source": conf.DurationScript}
+	} else {
+		latency["field"] = conf.DurationField
+	}
+
+	leafAggs := map[string]any{
+		AGG_NAME_ERRORS:  map[string]any{"filter": conf.ErrorFilter},
+		AGG_NAME_LATENCY: map[string]any{"percentiles": latency},
+	}
+
+	if conf.Interval > 0 {
+		histogram := map[string]any{
+			"field":         conf.TimeField,
+			"min_doc_count": 0,
+		}
+
+		bounds := map[string]any{}
+		if conf.MinBound > 0 {
+			bounds["min"] = conf.MinBound
+		}
+		if conf.MaxBound > 0 {
+			bounds["max"] = conf.MaxBound
+		}
+		if len(bounds) > 0 {
+			histogram["extended_bounds"] = bounds
+		}
+
+		histogramType := "histogram"
+		if conf.DateHistogram {
+			histogramType = "date_histogram"
+			histogram["fixed_interval"] = fmt.Sprintf("%dms", conf.Interval)
+		} else {
+			histogram["interval"] = conf.Interval
+		}
+
+		leafAggs = map[string]any{
+			AGG_NAME_TIMELINE: map[string]any{
+				histogramType: histogram,
+				"aggs":        leafAggs,
+			},
+		}
+	}
+
+	if conf.OperationField != "" {
+		leafAggs = map[string]any{
+			AGG_NAME_OPERATIONS: map[string]any{
+				"terms": map[string]any{
+					"field": conf.OperationField,
+					"size":  interfaces.MAX_TRACE_ANALYTICS_BUCKET_SIZE,
+				},
+				"aggs": leafAggs,
+			},
+		}
+	}
+
+	return map[string]any{
+		AGG_NAME_SERVICES: map[string]any{
+			"terms": map[string]any{
+				"field": conf.ServiceField,
+				"size":  interfaces.MAX_TRACE_ANALYTICS_BUCKET_SIZE,
+			},
+			"aggs": leafAggs,
+		},
+	}
+}
+
+// 解析聚合结果. timeUnitsPerMs为时间分桶字段每毫秒对应的单位数, durationToMicros为时延换算为微秒的系数
+func ParseSpanStatsBuckets(resBytes []byte, timeUnitsPerMs int64, durationToMicros float64) ([]interfaces.SpanStatsBucket, error) {
+	buckets := make([]interfaces.SpanStatsBucket, 0)
+	if len(resBytes) == 0 {
+		return buckets, nil
+	}
+
+	var resp aggResponse
+	err := sonic.Unmarshal(resBytes, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeUnitsPerMs <= 0 {
+		timeUnitsPerMs = 1
+	}
+
+	for _, serviceBucket := range resp.Aggregations.Services.Buckets {
+		serviceName := keyString(serviceBucket.Key)
+		if serviceBucket.Operations == nil {
+			buckets = appendLeafBuckets(buckets, interfaces.SpanStatsBucket{ServiceName: serviceName},
+				serviceBucket, timeUnitsPerMs, durationToMicros)
+			continue
+		}
+
+		for _, opBucket := range serviceBucket.Operations.Buckets {
+			buckets = appendLeafBuckets(buckets, interfaces.SpanStatsBucket{
+				ServiceName: serviceName,
+				Operation:   keyString(opBucket.Key),
+			}, opBucket, timeUnitsPerMs, durationToMicros)
+		}
+	}
+
+	return buckets, nil
+}
+
+func appendLeafBuckets(buckets []interfaces.SpanStatsBucket, base interfaces.SpanStatsBucket, bucket aggBucket,
+	timeUnitsPerMs int64, durationToMicros float64) []interfaces.SpanStatsBucket {
+
+	if bucket.Timeline == nil {
+		return append(buckets, genStatsBucket(base, bucket, durationToMicros))
+	}
+
+	for _, timeBucket := range bucket.Timeline.Buckets {
+		statsBucket := genStatsBucket(base, timeBucket, durationToMicros)
+		statsBucket.Time = keyInt64(timeBucket.Key) / timeUnitsPerMs
+		buckets = append(buckets, statsBucket)
+	}
+	return buckets
+}
+
+func genStatsBucket(base interfaces.SpanStatsBucket, bucket aggBucket, durationToMicros float64) interfaces.SpanStatsBucket {
+	base.Count = bucket.DocCount
+	if bucket.Errors != nil {
+		base.ErrorCount = bucket.Errors.DocCount
+	}
+
+	if bucket.Latency != nil {
+		base.P50 = percentileValue(bucket.Latency.Values, 50) * durationToMicros
+		base.P95 = percentileValue(bucket.Latency.Values, 95) * durationToMicros
+		base.P99 = percentileValue(bucket.Latency.Values, 99) * durationToMicros
+	}
+	return base
+}
+
+// 空桶的分位数为null, 按0处理
+func percentileValue(values map[string]*float64, percent float64) float64 {
+	val, ok := values[strconv.FormatFloat(percent, 'f', 1, 64)]
+	if !ok || val == nil || math.IsNaN(*val) {
+		return 0
+	}
+	return *val
+}
+
+func keyString(key any) string {
+	switch v := key.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func keyInt64(key any) int64 {
+	switch v := key.(type) {
+	case float64:
+		return int64(v)
+	case string:
+		i, _ := strconv.ParseInt(v, 10, 64)
+		return i
+	default:
+		return 0
+	}
+}
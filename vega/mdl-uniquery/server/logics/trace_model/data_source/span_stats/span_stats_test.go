@@ -0,0 +1,111 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package span_stats
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"uniquery/interfaces"
+)
+
+func Test_SpanStats_GenSpanStatsAggs(t *testing.T) {
+	Convey("Test GenSpanStatsAggs", t, func() {
+		errorFilter := map[string]any{"term": map[string]any{"status": "error"}}
+
+		Convey("Gen aggs grouped by service only", func() {
+			aggs := GenSpanStatsAggs(AggConfig{
+				ServiceField:  "service",
+				ErrorFilter:   errorFilter,
+				DurationField: "duration",
+			})
+
+			services := aggs[AGG_NAME_SERVICES].(map[string]any)
+			So(services["terms"], ShouldResemble, map[string]any{
+				"field": "service",
+				"size":  interfaces.MAX_TRACE_ANALYTICS_BUCKET_SIZE,
+			})
+			leafAggs := services["aggs"].(map[string]any)
+			So(leafAggs[AGG_NAME_ERRORS], ShouldResemble, map[string]any{"filter": errorFilter})
+			So(leafAggs[AGG_NAME_LATENCY], ShouldResemble, map[string]any{
+				"percentiles": map[string]any{"percents": latencyPercents, "field": "duration"},
+			})
+		})
+
+		Convey("Gen aggs grouped by operation and date histogram", func() {
+			aggs := GenSpanStatsAggs(AggConfig{
+				ServiceField:   "service",
+				OperationField: "name",
+				ErrorFilter:    errorFilter,
+				DurationScript: "doc['end'].value - doc['start'].value",
+				TimeField:      "@timestamp",
+				DateHistogram:  true,
+				Interval:       60000,
+				MinBound:       1000,
+				MaxBound:       2000,
+			})
+
+			services := aggs[AGG_NAME_SERVICES].(map[string]any)
+			operations := services["aggs"].(map[string]any)[AGG_NAME_OPERATIONS].(map[string]any)
+			timeline := operations["aggs"].(map[string]any)[AGG_NAME_TIMELINE].(map[string]any)
+			So(timeline["date_histogram"], ShouldResemble, map[string]any{
+				"field":           "@timestamp",
+				"min_doc_count":   0,
+				"fixed_interval":  "60000ms",
+				"extended_bounds": map[string]any{"min": int64(1000), "max": int64(2000)},
+			})
+			latency := timeline["aggs"].(map[string]any)[AGG_NAME_LATENCY].(map[string]any)
+			So(latency["percentiles"].(map[string]any)["script"], ShouldResemble,
+				map[string]any{"source": "doc['end'].value - doc['start'].value"})
+		})
+	})
+}
+
+func Test_SpanStats_ParseSpanStatsBuckets(t *testing.T) {
+	Convey("Test ParseSpanStatsBuckets", t, func() {
+		Convey("Parse failed, caused by invalid response", func() {
+			_, err := ParseSpanStatsBuckets([]byte("{"), 1, 1)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Parse succeed with empty response", func() {
+			buckets, err := ParseSpanStatsBuckets(nil, 1, 1)
+			So(err, ShouldBeNil)
+			So(len(buckets), ShouldEqual, 0)
+		})
+
+		Convey("Parse succeed with service buckets", func() {
+			resBytes := []byte(`{"aggregations":{"services":{"buckets":[{"key":"order","doc_count":4,` +
+				`"errors":{"doc_count":1},"latency":{"values":{"50.0":1.5,"95.0":2,"99.0":3}}}]}}}`)
+			buckets, err := ParseSpanStatsBuckets(resBytes, 1, 1000)
+			So(err, ShouldBeNil)
+			So(buckets, ShouldResemble, []interfaces.SpanStatsBucket{
+				{
+					ServiceName: "order", Count: 4, ErrorCount: 1,
+					LatencyStats: interfaces.LatencyStats{P50: 1500, P95: 2000, P99: 3000},
+				},
+			})
+		})
+
+		Convey("Parse succeed with empty time buckets", func() {
+			resBytes := []byte(`{"aggregations":{"services":{"buckets":[{"key":"order","doc_count":1,"operations":{"buckets":[` +
+				`{"key":"create","doc_count":1,"timeline":{"buckets":[` +
+				`{"key":1000000,"doc_count":1,"errors":{"doc_count":0},"latency":{"values":{"50.0":5,"95.0":5,"99.0":5}}},` +
+				`{"key":2000000,"doc_count":0,"errors":{"doc_count":0},"latency":{"values":{"50.0":null,"95.0":null,"99.0":null}}}` +
+				`]}}]}}]}}}`)
+			buckets, err := ParseSpanStatsBuckets(resBytes, 1000, 1)
+			So(err, ShouldBeNil)
+			So(len(buckets), ShouldEqual, 2)
+			So(buckets[0].Time, ShouldEqual, 1000)
+			So(buckets[0].Operation, ShouldEqual, "create")
+			So(buckets[0].P99, ShouldEqual, 5)
+			So(buckets[1].Time, ShouldEqual, 2000)
+			So(buckets[1].Count, ShouldEqual, 0)
+			So(buckets[1].P99, ShouldEqual, 0)
+		})
+	})
+}
@@ -276,6 +276,38 @@ func (tyAdapter *tingYunwAdapter) GetSpanRelatedLogList(ctx context.Context, mod
 	return []interfaces.RelatedLogListEntry{}, 0, nil
 }
 
+// 听云仅提供单条链路的查询接口, 不支持跨链路的聚合分析
+func (tyAdapter *tingYunwAdapter) GetSpanStats(ctx context.Context, model interfaces.TraceModel, params interfaces.SpanStatsQueryParams) (buckets []interfaces.SpanStatsBucket, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 通过tingYunwAdapter聚合统计span")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_TraceModel_UnsupportedTraceAnalytics).
+		WithErrorDetails("The tingyun data source does not support span aggregation")
+}
+
+func (tyAdapter *tingYunwAdapter) ScanBriefSpans(ctx context.Context, model interfaces.TraceModel, params interfaces.TraceAnalyticsQueryParams,
+	handle func(briefSpans []*interfaces.BriefSpan_) error) (err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 通过tingYunwAdapter获取时间范围内的span")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	return rest.NewHTTPError(ctx, http.StatusBadRequest, uerrors.Uniquery_TraceModel_UnsupportedTraceAnalytics).
+		WithErrorDetails("The tingyun data source does not support scanning spans by time range")
+}
+
 /*
 	私有方法
 */
@@ -11,6 +11,7 @@ import (
 	"math"
 	"net/http"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
@@ -287,6 +288,187 @@ func (tms *traceModelService) GetSpanRelatedLogList(ctx context.Context, model i
 	return adapter.GetSpanRelatedLogList(ctx, model, params)
 }
 
+func (tms *traceModelService) GetServiceDependencies(ctx context.Context, model interfaces.TraceModel, params interfaces.TraceAnalyticsQueryParams) (graph interfaces.ServiceDependencyGraph, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 获取服务依赖拓扑")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	// 决策当前模型id的数据查询权限
+	err = tms.ps.CheckPermission(ctx, interfaces.Resource{
+		ID:   model.ID,
+		Type: interfaces.RESOURCE_TYPE_TRACE_MODEL,
+	}, []string{interfaces.OPERATION_TYPE_DATA_QUERY})
+	if err != nil {
+		return graph, err
+	}
+
+	sourceType, err := tms.getUnderlyingDataSouceType(ctx, interfaces.QUERY_CATEGORY_SPAN, model)
+	if err != nil {
+		return graph, err
+	}
+
+	adapter, err := data_source.NewTraceModelAdapter(ctx, sourceType, tms.appSetting)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return graph, err
+	}
+
+	// 指定服务时, 需拉取全部服务的span才能找到其上下游, 最后再按服务过滤
+	scanParams := params
+	scanParams.ServiceName = ""
+
+	var statsBuckets []interfaces.SpanStatsBucket
+	builder := newDependencyEdgeBuilder()
+
+	g, gCtx := errgroup.WithContext(ctx)
+	// 1. 通过聚合统计各服务的调用量, 错误量与时延
+	g.Go(
+		func() error {
+			var gErr error
+			statsBuckets, gErr = adapter.GetSpanStats(gCtx, model, interfaces.SpanStatsQueryParams{
+				TraceAnalyticsQueryParams: scanParams,
+			})
+			return gErr
+		},
+	)
+
+	// 2. 按trace逐个扫描时间范围内的span, 在同一trace内关联父子span所属的服务.
+	// span中没有父span所属服务的字段, 无法直接聚合出调用边, 因此限制扫描的span数
+	g.Go(
+		func() error {
+			return adapter.ScanBriefSpans(gCtx, model, scanParams, func(briefSpans []*interfaces.BriefSpan_) error {
+				builder.add(briefSpans)
+				if builder.spanCount > interfaces.MAX_DEPENDENCY_SCAN_SPANS {
+					errDetails := fmt.Sprintf("More than %d spans in the time range [%d, %d], please narrow the time range",
+						interfaces.MAX_DEPENDENCY_SCAN_SPANS, params.Start, params.End)
+					logger.Error(errDetails)
+					o11y.Error(gCtx, errDetails)
+					return rest.NewHTTPError(gCtx, http.StatusBadRequest, uerrors.Uniquery_TraceModel_SpanScanLimitExceeded).
+						WithErrorDetails(errDetails)
+				}
+				return nil
+			})
+		},
+	)
+
+	if err := g.Wait(); err != nil {
+		return graph, err
+	}
+
+	// 3. 汇总服务间的调用边
+	edges := tms.buildDependencyEdges(ctx, builder)
+
+	// 4. 按服务过滤, 保留与该服务直接相连的边与节点
+	serviceSet := make(map[string]struct{})
+	if params.ServiceName != "" {
+		serviceSet[params.ServiceName] = struct{}{}
+		filteredEdges := make([]interfaces.ServiceDependencyEdge, 0)
+		for _, edge := range edges {
+			if edge.Source == params.ServiceName || edge.Target == params.ServiceName {
+				filteredEdges = append(filteredEdges, edge)
+				serviceSet[edge.Source] = struct{}{}
+				serviceSet[edge.Target] = struct{}{}
+			}
+		}
+		edges = filteredEdges
+	}
+
+	nodes := make([]interfaces.ServiceNode, 0, len(statsBuckets))
+	for _, bucket := range statsBuckets {
+		if _, ok := serviceSet[bucket.ServiceName]; params.ServiceName != "" && !ok {
+			continue
+		}
+
+		nodes = append(nodes, interfaces.ServiceNode{
+			ServiceName: bucket.ServiceName,
+			CallCount:   bucket.Count,
+			ErrorCount:  bucket.ErrorCount,
+			ErrorRate:   errorRate(bucket.ErrorCount, bucket.Count),
+			Latency:     bucket.LatencyStats,
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ServiceName < nodes[j].ServiceName
+	})
+
+	return interfaces.ServiceDependencyGraph{
+		Start:     params.Start,
+		End:       params.End,
+		Nodes:     nodes,
+		Edges:     edges,
+		SpanCount: builder.spanCount,
+	}, nil
+}
+
+func (tms *traceModelService) GetREDMetrics(ctx context.Context, model interfaces.TraceModel, params interfaces.REDMetricsQueryParams) (resp interfaces.MetricModelUniResponse, err error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 获取操作的RED指标")
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, "")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}()
+
+	resp = interfaces.MetricModelUniResponse{
+		Datas: []interfaces.MetricModelData{},
+		Step:  &params.Step,
+	}
+
+	// 决策当前模型id的数据查询权限
+	err = tms.ps.CheckPermission(ctx, interfaces.Resource{
+		ID:   model.ID,
+		Type: interfaces.RESOURCE_TYPE_TRACE_MODEL,
+	}, []string{interfaces.OPERATION_TYPE_DATA_QUERY})
+	if err != nil {
+		return resp, err
+	}
+
+	sourceType, err := tms.getUnderlyingDataSouceType(ctx, interfaces.QUERY_CATEGORY_SPAN, model)
+	if err != nil {
+		return resp, err
+	}
+
+	adapter, err := data_source.NewTraceModelAdapter(ctx, sourceType, tms.appSetting)
+	if err != nil {
+		o11y.Error(ctx, err.Error())
+		return resp, err
+	}
+
+	// 1. 按服务, 操作与步长聚合统计span, 即时查询不按时间分桶
+	interval := params.StepMs
+	if params.IsInstantQuery {
+		interval = 0
+	}
+	buckets, err := adapter.GetSpanStats(ctx, model, interfaces.SpanStatsQueryParams{
+		TraceAnalyticsQueryParams: params.TraceAnalyticsQueryParams,
+		Operation:                 params.Operation,
+		GroupByOperation:          true,
+		Interval:                  interval,
+	})
+	if err != nil {
+		return resp, err
+	}
+	if params.IsInstantQuery {
+		// 即时查询的时间点为结束时间
+		for i := range buckets {
+			buckets[i].Time = params.End
+		}
+	}
+
+	// 2. 转换为指标模型的时间序列格式, 每个服务操作的每个指标为一条序列
+	resp.Datas = tms.convertREDSeries(buckets, params)
+	resp.SeriesTotal = len(resp.Datas)
+	return resp, nil
+}
+
 func (tms *traceModelService) GetTraceModelByID(ctx context.Context, modelID string) (model interfaces.TraceModel, err error) {
 	ctx, span := ar_trace.Tracer.Start(ctx, "logic层: 根据ID查询链路模型对象")
 	defer func() {
@@ -415,7 +597,7 @@ func (tms *traceModelService) getUnderlyingDataSouceType(ctx context.Context, qu
 		return interfaces.SOURCE_TYPE_DATA_VIEW, nil
 	} else if queryCategory == interfaces.QUERY_CATEGORY_RELATED_LOG && model.RelatedLogSourceType == interfaces.SOURCE_TYPE_DATA_VIEW {
 		return interfaces.SOURCE_TYPE_DATA_VIEW, nil
-	} else { // queryCategory == interfaces.QUERY_CATEGORY_SPAN && model.SpanSourceType == interfaces.SOURCE_TYPE_DATA_CONNECTION
+	} else { // 基于数据连接的span与关联日志, 底层数据源均为span所在数据连接的类型
 		spanConfig := model.SpanConfig.(interfaces.SpanConfigWithDataConnection)
 		underlyingSourceType, isExist, err := tms.dcAccess.GetDataConnectionTypeByName(ctx, spanConfig.DataConnection.Name)
 		if err != nil {
@@ -437,6 +619,183 @@ func (tms *traceModelService) getUnderlyingDataSouceType(ctx context.Context, qu
 	}
 }
 
+// 关联父子span, 统计跨服务调用的调用量, 错误量与时延, 以被调方span的状态与耗时为准
+func (tms *traceModelService) buildDependencyEdges(ctx context.Context, builder *dependencyEdgeBuilder) []interfaces.ServiceDependencyEdge {
+	_, span := ar_trace.Tracer.Start(ctx, "logic层: 构建服务依赖边")
+	defer func() {
+		span.SetStatus(codes.Ok, "")
+		span.End()
+	}()
+
+	// 最后一个trace在扫描结束后才能关联
+	builder.flushTrace()
+
+	edges := make([]interfaces.ServiceDependencyEdge, 0, len(builder.edgeMap))
+	for key, edge := range builder.edgeMap {
+		histogram := builder.durationMap[key]
+
+		edge.ErrorRate = errorRate(edge.ErrorCount, edge.CallCount)
+		edge.Latency = interfaces.LatencyStats{
+			P50: histogram.percentile(50),
+			P95: histogram.percentile(95),
+			P99: histogram.percentile(99),
+		}
+		edges = append(edges, *edge)
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Source != edges[j].Source {
+			return edges[i].Source < edges[j].Source
+		}
+		return edges[i].Target < edges[j].Target
+	})
+	return edges
+}
+
+type dependencyEdgeKey struct {
+	source string
+	target string
+}
+
+// 服务依赖边的累加器. span按traceID排序到达, 只缓存当前trace的span,
+// 父子span在trace内按spanID关联, 不同trace中相同的spanID不会互相串联.
+// 每条边的耗时记入对数分桶的直方图, 内存只与边数相关, 与扫描的span数无关
+type dependencyEdgeBuilder struct {
+	edgeMap     map[dependencyEdgeKey]*interfaces.ServiceDependencyEdge
+	durationMap map[dependencyEdgeKey]*durationHistogram
+	traceID     string
+	traceSpans  []*interfaces.BriefSpan_
+	spanCount   int64
+}
+
+func newDependencyEdgeBuilder() *dependencyEdgeBuilder {
+	return &dependencyEdgeBuilder{
+		edgeMap:     make(map[dependencyEdgeKey]*interfaces.ServiceDependencyEdge),
+		durationMap: make(map[dependencyEdgeKey]*durationHistogram),
+	}
+}
+
+func (b *dependencyEdgeBuilder) add(briefSpans []*interfaces.BriefSpan_) {
+	for _, briefSpan := range briefSpans {
+		if briefSpan.TraceID != b.traceID {
+			b.flushTrace()
+			b.traceID = briefSpan.TraceID
+		}
+		b.traceSpans = append(b.traceSpans, briefSpan)
+		b.spanCount++
+	}
+}
+
+// 关联当前trace内的父子span, 累加跨服务调用的边
+func (b *dependencyEdgeBuilder) flushTrace() {
+	if len(b.traceSpans) == 0 {
+		return
+	}
+
+	spanMap := make(map[string]*interfaces.BriefSpan_, len(b.traceSpans))
+	for _, briefSpan := range b.traceSpans {
+		spanMap[briefSpan.SpanID] = briefSpan
+	}
+
+	for _, briefSpan := range b.traceSpans {
+		parentSpan, ok := spanMap[briefSpan.ParentSpanID]
+		if !ok || parentSpan.ServiceName == briefSpan.ServiceName {
+			continue
+		}
+
+		key := dependencyEdgeKey{source: parentSpan.ServiceName, target: briefSpan.ServiceName}
+		edge, ok := b.edgeMap[key]
+		if !ok {
+			edge = &interfaces.ServiceDependencyEdge{
+				Source: key.source,
+				Target: key.target,
+			}
+			b.edgeMap[key] = edge
+			b.durationMap[key] = newDurationHistogram()
+		}
+
+		edge.CallCount++
+		if briefSpan.Status == interfaces.SPAN_STATUS_ERROR {
+			edge.ErrorCount++
+		}
+		b.durationMap[key].add(briefSpan.Duration)
+	}
+
+	b.traceSpans = b.traceSpans[:0]
+}
+
+// 将聚合统计结果转换为RED指标序列. rate为每秒调用量, 无调用的时间点上错误率与时延为空
+func (tms *traceModelService) convertREDSeries(buckets []interfaces.SpanStatsBucket, params interfaces.REDMetricsQueryParams) []interfaces.MetricModelData {
+	metrics := params.Metrics
+	if len(metrics) == 0 {
+		metrics = []string{
+			interfaces.RED_METRIC_RATE,
+			interfaces.RED_METRIC_ERRORS,
+			interfaces.RED_METRIC_ERROR_RATE,
+			interfaces.RED_METRIC_DURATION_P50,
+			interfaces.RED_METRIC_DURATION_P95,
+			interfaces.RED_METRIC_DURATION_P99,
+		}
+	}
+
+	stepSeconds := float64(params.StepMs) / 1000
+	seriesMap := make(map[string]*interfaces.MetricModelData)
+	seriesKeys := make([]string, 0)
+	for _, bucket := range buckets {
+		for _, metric := range metrics {
+			key := strings.Join([]string{bucket.ServiceName, bucket.Operation, metric}, interfaces.DEFAULT_SEPARATOR)
+			series, ok := seriesMap[key]
+			if !ok {
+				series = &interfaces.MetricModelData{
+					Labels: map[string]string{
+						"service_name": bucket.ServiceName,
+						"operation":    bucket.Operation,
+						"metric":       metric,
+					},
+					Times:  make([]any, 0),
+					Values: make([]any, 0),
+				}
+				seriesMap[key] = series
+				seriesKeys = append(seriesKeys, key)
+			}
+
+			var value any
+			switch metric {
+			case interfaces.RED_METRIC_RATE:
+				value = float64(bucket.Count) / stepSeconds
+			case interfaces.RED_METRIC_ERRORS:
+				value = bucket.ErrorCount
+			case interfaces.RED_METRIC_ERROR_RATE:
+				if bucket.Count > 0 {
+					value = errorRate(bucket.ErrorCount, bucket.Count)
+				}
+			case interfaces.RED_METRIC_DURATION_P50:
+				if bucket.Count > 0 {
+					value = bucket.P50
+				}
+			case interfaces.RED_METRIC_DURATION_P95:
+				if bucket.Count > 0 {
+					value = bucket.P95
+				}
+			case interfaces.RED_METRIC_DURATION_P99:
+				if bucket.Count > 0 {
+					value = bucket.P99
+				}
+			}
+
+			series.Times = append(series.Times, bucket.Time)
+			series.Values = append(series.Values, value)
+		}
+	}
+
+	sort.Strings(seriesKeys)
+	datas := make([]interfaces.MetricModelData, 0, len(seriesKeys))
+	for _, key := range seriesKeys {
+		datas = append(datas, *seriesMap[key])
+	}
+	return datas
+}
+
 // 构建Trace树结构
 func (tms *traceModelService) buildTree(ctx context.Context, spanMap map[string]*interfaces.BriefSpan_) *interfaces.BriefSpan_ {
 	_, span := ar_trace.Tracer.Start(ctx, "logic层: 构建trace树结构")
@@ -510,3 +869,63 @@ func (tms *traceModelService) getTraceStatisticData(ctx context.Context, rootSpa
 
 	return sd
 }
+
+func errorRate(errorCount, count int64) float64 {
+	if count == 0 {
+		return 0
+	}
+	return float64(errorCount) / float64(count)
+}
+
+// 耗时直方图的相邻分桶边界之比, 分位数的相对误差不超过1%
+const durationHistogramGamma = 1.02
+
+// 对数分桶的耗时直方图, 第i个桶覆盖(gamma^(i-1), gamma^i], 非正的耗时单独计数
+type durationHistogram struct {
+	buckets   map[int]int64
+	zeroCount int64
+	count     int64
+}
+
+func newDurationHistogram() *durationHistogram {
+	return &durationHistogram{buckets: make(map[int]int64)}
+}
+
+func (h *durationHistogram) add(duration int64) {
+	h.count++
+	if duration <= 0 {
+		h.zeroCount++
+		return
+	}
+	h.buckets[int(math.Ceil(math.Log(float64(duration))/math.Log(durationHistogramGamma)))]++
+}
+
+// 按nearest-rank找到分位数所在的桶, 返回桶的中间值
+func (h *durationHistogram) percentile(percent float64) float64 {
+	if h.count == 0 {
+		return 0
+	}
+
+	rank := int64(math.Ceil(percent / 100 * float64(h.count)))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank <= h.zeroCount {
+		return 0
+	}
+
+	indexes := make([]int, 0, len(h.buckets))
+	for index := range h.buckets {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	seen := h.zeroCount
+	for _, index := range indexes {
+		seen += h.buckets[index]
+		if seen >= rank {
+			return 2 * math.Pow(durationHistogramGamma, float64(index)) / (durationHistogramGamma + 1)
+		}
+	}
+	return 2 * math.Pow(durationHistogramGamma, float64(indexes[len(indexes)-1])) / (durationHistogramGamma + 1)
+}
@@ -723,3 +723,322 @@ func TestGetTraceStatisticData(t *testing.T) {
 		})
 	})
 }
+
+func TestGetServiceDependencies(t *testing.T) {
+	Convey("Test GetServiceDependencies", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		mockTMAccess := umock.NewMockTraceModelAccess(mockCtrl)
+		mockDCAccess := umock.NewMockDataConnectionAccess(mockCtrl)
+		mockTMAdapter := umock.NewMockTraceModelAdapter(mockCtrl)
+		psMock := umock.NewMockPermissionService(mockCtrl)
+
+		mockTMService := MockNewTraceModelService(appSetting, mockTMAccess, mockDCAccess, psMock)
+
+		// 按traceID排序分两页返回, t1跨页, t2中的spanID与t1重复
+		briefSpanPages := [][]*interfaces.BriefSpan_{
+			{
+				{TraceID: "t1", SpanID: "1", ServiceName: "gateway", Duration: 100},
+				{TraceID: "t1", SpanID: "2", ParentSpanID: "1", ServiceName: "order", Duration: 50},
+				{TraceID: "t1", SpanID: "3", ParentSpanID: "1", ServiceName: "order", Duration: 30, Status: interfaces.SPAN_STATUS_ERROR},
+			},
+			{
+				{TraceID: "t1", SpanID: "4", ParentSpanID: "2", ServiceName: "order", Duration: 10},
+				{TraceID: "t1", SpanID: "5", ParentSpanID: "2", ServiceName: "db", Duration: 5},
+				{TraceID: "t2", SpanID: "6", ParentSpanID: "7", ServiceName: "user", Duration: 5},
+				{TraceID: "t2", SpanID: "2", ParentSpanID: "6", ServiceName: "user", Duration: 5},
+			},
+		}
+		scanBriefSpans := func(ctx context.Context, model interfaces.TraceModel, params interfaces.TraceAnalyticsQueryParams,
+			handle func([]*interfaces.BriefSpan_) error) error {
+			for _, page := range briefSpanPages {
+				if err := handle(page); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		statsBuckets := []interfaces.SpanStatsBucket{
+			{ServiceName: "order", Count: 3, ErrorCount: 1},
+			{ServiceName: "gateway", Count: 1},
+			{ServiceName: "db", Count: 1},
+			{ServiceName: "user", Count: 1},
+		}
+
+		Convey("Get failed, caused by the error from method 'CheckPermission'", func() {
+			expectedErr := errors.New("some errors")
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedErr)
+
+			_, err := mockTMService.GetServiceDependencies(testCtx, interfaces.TraceModel{}, interfaces.TraceAnalyticsQueryParams{})
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Get failed, caused by the error from method 'NewTraceModelAdapter'", func() {
+			expectedErr := errors.New("some errors")
+			patch1 := ApplyPrivateMethod(reflect.TypeOf(&traceModelService{}), "getUnderlyingDataSouceType",
+				func(tmService *traceModelService, ctx context.Context, queryCategory string, model interfaces.TraceModel) (string, error) {
+					return "", nil
+				})
+			defer patch1.Reset()
+
+			patch2 := ApplyFuncReturn(data_source.NewTraceModelAdapter, nil, expectedErr)
+			defer patch2.Reset()
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+			_, err := mockTMService.GetServiceDependencies(testCtx, interfaces.TraceModel{}, interfaces.TraceAnalyticsQueryParams{})
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Get failed, caused by the error from method 'ScanBriefSpans'", func() {
+			expectedErr := errors.New("some errors")
+			patch1 := ApplyPrivateMethod(reflect.TypeOf(&traceModelService{}), "getUnderlyingDataSouceType",
+				func(tmService *traceModelService, ctx context.Context, queryCategory string, model interfaces.TraceModel) (string, error) {
+					return "", nil
+				})
+			defer patch1.Reset()
+
+			patch2 := ApplyFuncReturn(data_source.NewTraceModelAdapter, mockTMAdapter, nil)
+			defer patch2.Reset()
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mockTMAdapter.EXPECT().GetSpanStats(gomock.Any(), gomock.Any(), gomock.Any()).Return(statsBuckets, nil)
+			mockTMAdapter.EXPECT().ScanBriefSpans(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedErr)
+
+			_, err := mockTMService.GetServiceDependencies(testCtx, interfaces.TraceModel{}, interfaces.TraceAnalyticsQueryParams{})
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Get succeed", func() {
+			patch1 := ApplyPrivateMethod(reflect.TypeOf(&traceModelService{}), "getUnderlyingDataSouceType",
+				func(tmService *traceModelService, ctx context.Context, queryCategory string, model interfaces.TraceModel) (string, error) {
+					return "", nil
+				})
+			defer patch1.Reset()
+
+			patch2 := ApplyFuncReturn(data_source.NewTraceModelAdapter, mockTMAdapter, nil)
+			defer patch2.Reset()
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mockTMAdapter.EXPECT().GetSpanStats(gomock.Any(), gomock.Any(), gomock.Any()).Return(statsBuckets, nil)
+			mockTMAdapter.EXPECT().ScanBriefSpans(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(scanBriefSpans)
+
+			graph, err := mockTMService.GetServiceDependencies(testCtx, interfaces.TraceModel{},
+				interfaces.TraceAnalyticsQueryParams{Start: 1, End: 2})
+			So(err, ShouldBeNil)
+			So(graph.SpanCount, ShouldEqual, 7)
+			So(len(graph.Nodes), ShouldEqual, 4)
+			So(graph.Nodes[0].ServiceName, ShouldEqual, "db")
+			So(len(graph.Edges), ShouldEqual, 2)
+			So(graph.Edges[0].Source, ShouldEqual, "gateway")
+			So(graph.Edges[0].Target, ShouldEqual, "order")
+			So(graph.Edges[0].CallCount, ShouldEqual, 2)
+			So(graph.Edges[0].ErrorCount, ShouldEqual, 1)
+			So(graph.Edges[0].ErrorRate, ShouldEqual, 0.5)
+			// 分位数来自对数分桶的直方图, 相对误差不超过1%
+			So(graph.Edges[0].Latency.P50, ShouldAlmostEqual, 30, 0.3)
+			So(graph.Edges[0].Latency.P95, ShouldAlmostEqual, 50, 0.5)
+			So(graph.Edges[0].Latency.P99, ShouldAlmostEqual, 50, 0.5)
+			So(graph.Edges[1].Source, ShouldEqual, "order")
+			So(graph.Edges[1].Target, ShouldEqual, "db")
+			So(graph.Edges[1].CallCount, ShouldEqual, 1)
+			So(graph.Edges[1].Latency.P50, ShouldAlmostEqual, 5, 0.05)
+		})
+
+		Convey("Get failed, caused by too many spans in the time range", func() {
+			patch1 := ApplyPrivateMethod(reflect.TypeOf(&traceModelService{}), "getUnderlyingDataSouceType",
+				func(tmService *traceModelService, ctx context.Context, queryCategory string, model interfaces.TraceModel) (string, error) {
+					return "", nil
+				})
+			defer patch1.Reset()
+
+			patch2 := ApplyFuncReturn(data_source.NewTraceModelAdapter, mockTMAdapter, nil)
+			defer patch2.Reset()
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mockTMAdapter.EXPECT().GetSpanStats(gomock.Any(), gomock.Any(), gomock.Any()).Return(statsBuckets, nil)
+
+			scanned := 0
+			mockTMAdapter.EXPECT().ScanBriefSpans(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, model interfaces.TraceModel, params interfaces.TraceAnalyticsQueryParams,
+					handle func([]*interfaces.BriefSpan_) error) error {
+					page := make([]*interfaces.BriefSpan_, interfaces.MAX_SEARCH_SIZE)
+					for i := range page {
+						page[i] = &interfaces.BriefSpan_{TraceID: fmt.Sprintf("t%d", i), SpanID: "1", ServiceName: "gateway"}
+					}
+					for {
+						if err := handle(page); err != nil {
+							return err
+						}
+						scanned += len(page)
+					}
+				})
+
+			_, err := mockTMService.GetServiceDependencies(testCtx, interfaces.TraceModel{},
+				interfaces.TraceAnalyticsQueryParams{Start: 1, End: 2})
+			So(err.(*rest.HTTPError).BaseError.ErrorCode, ShouldEqual, uerrors.Uniquery_TraceModel_SpanScanLimitExceeded)
+			So(scanned, ShouldBeLessThanOrEqualTo, interfaces.MAX_DEPENDENCY_SCAN_SPANS)
+		})
+
+		Convey("Get succeed with service name", func() {
+			patch1 := ApplyPrivateMethod(reflect.TypeOf(&traceModelService{}), "getUnderlyingDataSouceType",
+				func(tmService *traceModelService, ctx context.Context, queryCategory string, model interfaces.TraceModel) (string, error) {
+					return "", nil
+				})
+			defer patch1.Reset()
+
+			patch2 := ApplyFuncReturn(data_source.NewTraceModelAdapter, mockTMAdapter, nil)
+			defer patch2.Reset()
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mockTMAdapter.EXPECT().GetSpanStats(gomock.Any(), gomock.Any(), gomock.Any()).Return(statsBuckets, nil)
+			mockTMAdapter.EXPECT().ScanBriefSpans(gomock.Any(), gomock.Any(), interfaces.TraceAnalyticsQueryParams{}, gomock.Any()).
+				DoAndReturn(scanBriefSpans)
+
+			graph, err := mockTMService.GetServiceDependencies(testCtx, interfaces.TraceModel{},
+				interfaces.TraceAnalyticsQueryParams{ServiceName: "db"})
+			So(err, ShouldBeNil)
+			So(len(graph.Edges), ShouldEqual, 1)
+			So(len(graph.Nodes), ShouldEqual, 2)
+			So(graph.Nodes[0].ServiceName, ShouldEqual, "db")
+			So(graph.Nodes[1].ServiceName, ShouldEqual, "order")
+			So(graph.Nodes[1].ErrorRate, ShouldAlmostEqual, float64(1)/3)
+		})
+	})
+}
+
+func TestGetREDMetrics(t *testing.T) {
+	Convey("Test GetREDMetrics", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		appSetting := &common.AppSetting{}
+		mockTMAccess := umock.NewMockTraceModelAccess(mockCtrl)
+		mockDCAccess := umock.NewMockDataConnectionAccess(mockCtrl)
+		mockTMAdapter := umock.NewMockTraceModelAdapter(mockCtrl)
+		psMock := umock.NewMockPermissionService(mockCtrl)
+
+		mockTMService := MockNewTraceModelService(appSetting, mockTMAccess, mockDCAccess, psMock)
+
+		params := interfaces.REDMetricsQueryParams{
+			Step:    "1m",
+			StepMs:  60000,
+			Metrics: []string{interfaces.RED_METRIC_RATE, interfaces.RED_METRIC_DURATION_P99},
+		}
+
+		Convey("Get failed, caused by the error from method 'getUnderlyingDataSouceType'", func() {
+			expectedErr := errors.New("some errors")
+			patch := ApplyPrivateMethod(reflect.TypeOf(&traceModelService{}), "getUnderlyingDataSouceType",
+				func(tmService *traceModelService, ctx context.Context, queryCategory string, model interfaces.TraceModel) (string, error) {
+					return "", expectedErr
+				})
+			defer patch.Reset()
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+			_, err := mockTMService.GetREDMetrics(testCtx, interfaces.TraceModel{}, params)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Get failed, caused by the error from method 'GetSpanStats'", func() {
+			expectedErr := errors.New("some errors")
+			patch1 := ApplyPrivateMethod(reflect.TypeOf(&traceModelService{}), "getUnderlyingDataSouceType",
+				func(tmService *traceModelService, ctx context.Context, queryCategory string, model interfaces.TraceModel) (string, error) {
+					return "", nil
+				})
+			defer patch1.Reset()
+
+			patch2 := ApplyFuncReturn(data_source.NewTraceModelAdapter, mockTMAdapter, nil)
+			defer patch2.Reset()
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mockTMAdapter.EXPECT().GetSpanStats(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, expectedErr)
+
+			_, err := mockTMService.GetREDMetrics(testCtx, interfaces.TraceModel{}, params)
+			So(err, ShouldResemble, expectedErr)
+		})
+
+		Convey("Get succeed", func() {
+			patch1 := ApplyPrivateMethod(reflect.TypeOf(&traceModelService{}), "getUnderlyingDataSouceType",
+				func(tmService *traceModelService, ctx context.Context, queryCategory string, model interfaces.TraceModel) (string, error) {
+					return "", nil
+				})
+			defer patch1.Reset()
+
+			patch2 := ApplyFuncReturn(data_source.NewTraceModelAdapter, mockTMAdapter, nil)
+			defer patch2.Reset()
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mockTMAdapter.EXPECT().GetSpanStats(gomock.Any(), gomock.Any(), interfaces.SpanStatsQueryParams{
+				GroupByOperation: true,
+				Interval:         60000,
+			}).Return([]interfaces.SpanStatsBucket{
+				{ServiceName: "order", Operation: "create", Time: 0, Count: 120, LatencyStats: interfaces.LatencyStats{P99: 10}},
+				{ServiceName: "order", Operation: "create", Time: 60000},
+			}, nil)
+
+			resp, err := mockTMService.GetREDMetrics(testCtx, interfaces.TraceModel{}, params)
+			So(err, ShouldBeNil)
+			So(resp.SeriesTotal, ShouldEqual, 2)
+			So(*resp.Step, ShouldEqual, "1m")
+			So(resp.Datas[0].Labels["metric"], ShouldEqual, interfaces.RED_METRIC_DURATION_P99)
+			So(resp.Datas[0].Times, ShouldResemble, []any{int64(0), int64(60000)})
+			So(resp.Datas[0].Values, ShouldResemble, []any{float64(10), nil})
+			So(resp.Datas[1].Labels["metric"], ShouldEqual, interfaces.RED_METRIC_RATE)
+			So(resp.Datas[1].Values, ShouldResemble, []any{float64(2), float64(0)})
+		})
+
+		Convey("Get succeed with instant query", func() {
+			patch1 := ApplyPrivateMethod(reflect.TypeOf(&traceModelService{}), "getUnderlyingDataSouceType",
+				func(tmService *traceModelService, ctx context.Context, queryCategory string, model interfaces.TraceModel) (string, error) {
+					return "", nil
+				})
+			defer patch1.Reset()
+
+			patch2 := ApplyFuncReturn(data_source.NewTraceModelAdapter, mockTMAdapter, nil)
+			defer patch2.Reset()
+			psMock.EXPECT().CheckPermission(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			// 即时查询不按时间分桶
+			mockTMAdapter.EXPECT().GetSpanStats(gomock.Any(), gomock.Any(), interfaces.SpanStatsQueryParams{
+				TraceAnalyticsQueryParams: interfaces.TraceAnalyticsQueryParams{Start: 1000, End: 301000},
+				GroupByOperation:          true,
+			}).Return([]interfaces.SpanStatsBucket{
+				{ServiceName: "order", Operation: "create", Count: 600},
+			}, nil)
+
+			resp, err := mockTMService.GetREDMetrics(testCtx, interfaces.TraceModel{}, interfaces.REDMetricsQueryParams{
+				TraceAnalyticsQueryParams: interfaces.TraceAnalyticsQueryParams{Start: 1000, End: 301000},
+				StepMs:                    300000,
+				Metrics:                   []string{interfaces.RED_METRIC_RATE},
+				IsInstantQuery:            true,
+			})
+			So(err, ShouldBeNil)
+			So(resp.SeriesTotal, ShouldEqual, 1)
+			So(resp.Datas[0].Times, ShouldResemble, []any{int64(301000)})
+			So(resp.Datas[0].Values, ShouldResemble, []any{float64(2)})
+		})
+	})
+}
+
+func TestDurationHistogram(t *testing.T) {
+	Convey("Test durationHistogram", t, func() {
+		Convey("Percentile of empty histogram is 0", func() {
+			h := newDurationHistogram()
+			So(h.percentile(50), ShouldEqual, 0)
+		})
+
+		Convey("Percentile within 1% relative error", func() {
+			h := newDurationHistogram()
+			for d := int64(1); d <= 100000; d++ {
+				h.add(d)
+			}
+			So(h.percentile(50), ShouldAlmostEqual, 50000, 500)
+			So(h.percentile(95), ShouldAlmostEqual, 95000, 950)
+			So(h.percentile(99), ShouldAlmostEqual, 99000, 990)
+			So(len(h.buckets), ShouldBeLessThan, 1000)
+		})
+
+		Convey("Non-positive durations are counted as 0", func() {
+			h := newDurationHistogram()
+			h.add(0)
+			h.add(0)
+			h.add(100)
+			So(h.percentile(50), ShouldEqual, 0)
+			So(h.percentile(99), ShouldAlmostEqual, 100, 1)
+		})
+	})
+}
@@ -135,6 +135,7 @@ func main() {
 	logics.SetSearchAccess(access.NewSearchAccess(appSetting))
 	logics.SetStaticAccess(access.NewStaticAccess(appSetting))
 	logics.SetTraceModelAccess(access.NewTraceModelAccess(appSetting))
+	logics.SetUserMgmtAccess(access.NewUserMgmtAccess(appSetting))
 	logics.SetVegaDataSourceAccess(access.NewVegaDataSourceAccess(appSetting))
 	logics.SetVegaGatewayAccess(access.NewVegaGatewayAccess(appSetting))
 	logics.SetVegaViewAccess(access.NewVegaAccess(appSetting))
@@ -294,6 +294,20 @@ SELECT 'mariadb', 'mariadb', 'MariaDB 关系型数据库连接器', 'local', 'ta
     TRUE
 FROM DUAL WHERE NOT EXISTS ( SELECT f_type FROM t_connector_type WHERE f_type = 'mariadb' );
 
+INSERT INTO t_connector_type (f_type, f_name, f_description, f_mode, f_category, f_field_config, f_enabled)
+SELECT 'postgresql', 'postgresql', 'PostgreSQL 关系型数据库连接器', 'local', 'table',
+    '{
+        "host":     {"name":"主机地址","type":"string","description":"PostgreSQL 服务器主机地址","required":true,"encrypted":false},
+        "port":     {"name":"端口号","type":"integer","description":"PostgreSQL 服务器端口","required":true,"encrypted":false},
+        "username": {"name":"用户名","type":"string","description":"数据库用户名","required":true,"encrypted":false},
+        "password": {"name":"密码","type":"string","description":"数据库密码","required":true,"encrypted":true},
+        "database": {"name":"数据库名称","type":"string","description":"要连接的数据库名称","required":true,"encrypted":false},
+        "schemas":  {"name":"模式列表","type":"array","description":"模式名称列表（可选，为空则发现库内全部用户模式）","required":false,"encrypted":false},
+        "options":  {"name":"连接参数","type":"object","description":"连接参数（如 sslmode, connect_timeout 等）","required":false,"encrypted":false}
+    }',
+    TRUE
+FROM DUAL WHERE NOT EXISTS ( SELECT f_type FROM t_connector_type WHERE f_type = 'postgresql' );
+
 INSERT INTO t_connector_type (f_type, f_name, f_description, f_mode, f_category, f_field_config, f_enabled)
 SELECT 'opensearch', 'opensearch', 'OpenSearch 搜索引擎连接器', 'local', 'index',
     '{
@@ -306,6 +320,52 @@ SELECT 'opensearch', 'opensearch', 'OpenSearch 搜索引擎连接器', 'local',
     TRUE
 FROM DUAL WHERE NOT EXISTS ( SELECT f_type FROM t_connector_type WHERE f_type = 'opensearch' );
 
+INSERT INTO t_connector_type (f_type, f_name, f_description, f_mode, f_category, f_field_config, f_enabled)
+SELECT 'kafka', 'kafka', 'Kafka 消息队列连接器', 'local', 'topic',
+    '{
+        "brokers":        {"name":"Broker 地址","type":"array","description":"Kafka broker 地址列表，格式 host:port","required":true,"encrypted":false},
+        "sasl_mechanism": {"name":"SASL 认证机制","type":"string","description":"SASL 认证机制（可选）：PLAIN、SCRAM-SHA-256、SCRAM-SHA-512","required":false,"encrypted":false},
+        "username":       {"name":"用户名","type":"string","description":"SASL 认证用户名","required":false,"encrypted":false},
+        "password":       {"name":"密码","type":"string","description":"SASL 认证密码","required":false,"encrypted":true},
+        "tls":            {"name":"启用 TLS","type":"boolean","description":"是否使用 TLS 连接 broker","required":false,"encrypted":false},
+        "topic_pattern":  {"name":"Topic 模式","type":"string","description":"Topic 匹配模式（可选，如 orders-*）","required":false,"encrypted":false},
+        "sample_size":    {"name":"采样消息数","type":"integer","description":"推断字段结构时每个 topic 采样的消息数，默认 100","required":false,"encrypted":false}
+    }',
+    TRUE
+FROM DUAL WHERE NOT EXISTS ( SELECT f_type FROM t_connector_type WHERE f_type = 'kafka' );
+
+INSERT INTO t_connector_type (f_type, f_name, f_description, f_mode, f_category, f_field_config, f_enabled)
+SELECT 'prometheus', 'prometheus', 'Prometheus 兼容时序库连接器', 'local', 'metric',
+    '{
+        "url":                  {"name":"服务地址","type":"string","description":"Prometheus HTTP API 地址，如 http://localhost:9090","required":true,"encrypted":false},
+        "username":             {"name":"用户名","type":"string","description":"Basic 认证用户名","required":false,"encrypted":false},
+        "password":             {"name":"密码","type":"string","description":"Basic 认证密码","required":false,"encrypted":true},
+        "bearer_token":         {"name":"Bearer Token","type":"string","description":"Bearer 认证令牌","required":false,"encrypted":true},
+        "insecure_skip_verify": {"name":"跳过证书校验","type":"boolean","description":"HTTPS 连接时是否跳过证书校验","required":false,"encrypted":false},
+        "metric_pattern":       {"name":"指标模式","type":"string","description":"指标名匹配模式（可选，如 http_*）","required":false,"encrypted":false}
+    }',
+    TRUE
+FROM DUAL WHERE NOT EXISTS ( SELECT f_type FROM t_connector_type WHERE f_type = 'prometheus' );
+
+INSERT INTO t_connector_type (f_type, f_name, f_description, f_mode, f_category, f_field_config, f_enabled)
+SELECT 's3', 's3', 'S3/MinIO 对象存储连接器', 'local', 'fileset',
+    '{
+        "endpoint":             {"name":"服务地址","type":"string","description":"S3 服务地址，如 s3.amazonaws.com、http://localhost:9000","required":true,"encrypted":false},
+        "access_key_id":        {"name":"Access Key","type":"string","description":"访问密钥 ID","required":false,"encrypted":false},
+        "secret_access_key":    {"name":"Secret Key","type":"string","description":"访问密钥","required":false,"encrypted":true},
+        "region":               {"name":"区域","type":"string","description":"存储区域（可选，如 us-east-1）","required":false,"encrypted":false},
+        "use_ssl":              {"name":"启用 SSL","type":"boolean","description":"服务地址未指定协议时是否使用 HTTPS","required":false,"encrypted":false},
+        "insecure_skip_verify": {"name":"跳过证书校验","type":"boolean","description":"HTTPS 连接时是否跳过证书校验","required":false,"encrypted":false},
+        "bucket":               {"name":"Bucket","type":"string","description":"Bucket 名称（可选，为空时发现全部 bucket）","required":false,"encrypted":false},
+        "prefix":               {"name":"路径前缀","type":"string","description":"只发现该前缀下的文件（可选，如 reference/）","required":false,"encrypted":false},
+        "file_pattern":         {"name":"文件模式","type":"string","description":"文件名匹配模式（可选，如 *.csv）","required":false,"encrypted":false},
+        "csv_delimiter":        {"name":"CSV 分隔符","type":"string","description":"CSV 文件的列分隔符，默认逗号；.tsv 文件固定使用制表符","required":false,"encrypted":false},
+        "sample_size":          {"name":"采样行数","type":"integer","description":"推断字段结构时每个文件采样的行数，默认 100","required":false,"encrypted":false},
+        "max_files":            {"name":"最大文件数","type":"integer","description":"单次发现最多处理的文件数，默认 10000","required":false,"encrypted":false}
+    }',
+    TRUE
+FROM DUAL WHERE NOT EXISTS ( SELECT f_type FROM t_connector_type WHERE f_type = 's3' );
+
 
 -- ==========================================
 -- 7. t_discover_task 发现任务表
@@ -339,3 +399,42 @@ CREATE TABLE IF NOT EXISTS t_discover_task (
     INDEX idx_status (f_status),
     INDEX idx_create_time (f_create_time)
 ) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin COMMENT='发现任务表，记录异步资源发现任务的状态和结果';
+
+-- ==========================================
+-- 8. t_discover_schedule 定时发现配置表
+-- ==========================================
+CREATE TABLE IF NOT EXISTS t_discover_schedule (
+    f_catalog_id              VARCHAR(40) NOT NULL DEFAULT '' COMMENT '所属catalog ID',
+    f_cron_expr               VARCHAR(128) NOT NULL DEFAULT '' COMMENT '标准5段cron表达式',
+    f_enabled                 BOOLEAN NOT NULL DEFAULT TRUE COMMENT '是否启用',
+
+    -- 审计字段
+    f_creator                 VARCHAR(128) NOT NULL DEFAULT '' COMMENT '创建者id',
+    f_creator_type            VARCHAR(20) NOT NULL DEFAULT '' COMMENT '创建者类型',
+    f_create_time             BIGINT(20) NOT NULL DEFAULT 0 COMMENT '创建时间',
+    f_updater                 VARCHAR(128) NOT NULL DEFAULT '' COMMENT '更新者id',
+    f_updater_type            VARCHAR(20) NOT NULL DEFAULT '' COMMENT '更新者类型',
+    f_update_time             BIGINT(20) NOT NULL DEFAULT 0 COMMENT '更新时间',
+
+    -- 索引
+    PRIMARY KEY (f_catalog_id),
+    INDEX idx_enabled (f_enabled)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin COMMENT='定时发现配置表，每个catalog至多一条';
+
+-- ==========================================
+-- 9. t_resource_schema_drift 资源结构漂移记录表
+-- ==========================================
+CREATE TABLE IF NOT EXISTS t_resource_schema_drift (
+    f_id                      VARCHAR(40) NOT NULL DEFAULT '' COMMENT '漂移记录唯一标识',
+    f_resource_id             VARCHAR(40) NOT NULL DEFAULT '' COMMENT '所属resource ID',
+    f_catalog_id              VARCHAR(40) NOT NULL DEFAULT '' COMMENT '所属catalog ID',
+    f_task_id                 VARCHAR(40) NOT NULL DEFAULT '' COMMENT '检测到漂移的发现任务ID',
+    f_source_identifier       VARCHAR(512) NOT NULL DEFAULT '' COMMENT '源端标识',
+    f_changes                 MEDIUMTEXT NOT NULL COMMENT '结构变化列表（JSON格式）：字段新增/删除/类型变化、主键变化、失效等',
+    f_detect_time             BIGINT(20) NOT NULL DEFAULT 0 COMMENT '检测时间',
+
+    -- 索引
+    PRIMARY KEY (f_id),
+    INDEX idx_resource_id (f_resource_id, f_detect_time),
+    INDEX idx_task_id (f_task_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin COMMENT='资源结构漂移记录表，记录每次发现检测到的schema变化';
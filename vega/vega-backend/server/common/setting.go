@@ -51,12 +51,19 @@ type RedisSetting struct {
 	Password string
 }
 
+// QuerySessionSetting 查询游标 session 存储配置项
+type QuerySessionSetting struct {
+	Store string        `mapstructure:"store"` // memory 或 redis，为空时使用 memory；多副本部署需使用 redis
+	TTL   time.Duration `mapstructure:"ttl"`   // 自最后一次访问起的生存时间，为空时为 30m
+}
+
 // AppSetting app配置项
 type AppSetting struct {
 	ServerSetting        ServerSetting             `mapstructure:"server"`
 	LogSetting           logger.LogSetting         `mapstructure:"log"`
 	ObservabilitySetting o11y.ObservabilitySetting `mapstructure:"observability"`
 	CryptoSetting        CryptoSetting             `mapstructure:"crypto"`
+	QuerySessionSetting  QuerySessionSetting       `mapstructure:"querySession"`
 	DepServices          map[string]map[string]any `mapstructure:"depServices"`
 
 	DBSetting         libdb.DBSetting
@@ -67,6 +74,7 @@ type AppSetting struct {
 
 	PermissionUrl string
 	UserMgmtUrl   string
+	DataModelUrl  string
 }
 
 const (
@@ -81,6 +89,7 @@ const (
 	permissionServiceName string = "authorization-private"
 	userMgmtServiceName   string = "user-management"
 	hydraAdminServiceName string = "hydra-admin"
+	dataModelServiceName  string = "data-model"
 
 	DATA_BASE_NAME string = "adp"
 )
@@ -161,6 +170,8 @@ func loadSetting(vp *viper.Viper) {
 		SetUserMgmtSetting()
 	}
 
+	SetDataModelSetting()
+
 	serverInfo := o11y.ServerInfo{
 		ServerName:    version.ServerName,
 		ServerVersion: version.ServerVersion,
@@ -340,6 +351,21 @@ func SetUserMgmtSetting() {
 	appSetting.UserMgmtUrl = fmt.Sprintf("%s://%s:%d", protocol, host, port)
 }
 
+// SetDataModelSetting 血缘登记到 data-model, 未配置 data-model 时不登记血缘
+func SetDataModelSetting() {
+	setting, ok := appSetting.DepServices[dataModelServiceName]
+	if !ok {
+		logger.Warnf("service %s not found in depServices, skipping lineage reporting", dataModelServiceName)
+		return
+	}
+
+	protocol := setting["protocol"].(string)
+	host := setting["host"].(string)
+	port := setting["port"].(int)
+
+	appSetting.DataModelUrl = fmt.Sprintf("%s://%s:%d/api/mdl-data-model/in/v1", protocol, host, port)
+}
+
 // GetAuthEnabled 获取认证开关状态
 // 通过环境变量 AUTH_ENABLED 控制，默认 true（安全优先）
 func GetAuthEnabled() bool {
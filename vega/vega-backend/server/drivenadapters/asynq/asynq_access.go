@@ -41,30 +41,12 @@ func NewAsynqAccess(appSetting *common.AppSetting) interfaces.AsynqAccess {
 
 // CreateClient creates and returns the Asynq client for enqueueing tasks.
 func (aqa *asynqAccess) CreateClient(ctx context.Context) *asynq.Client {
-	redisOpt := asynq.RedisClientOpt{
-		Addr:         fmt.Sprintf("%s:%d", aqa.appSetting.RedisSetting.Host, aqa.appSetting.RedisSetting.Port),
-		Username:     aqa.appSetting.RedisSetting.Username,
-		Password:     aqa.appSetting.RedisSetting.Password,
-		DialTimeout:  5 * time.Second,  // 连接超时（默认 5s，调大）
-		ReadTimeout:  60 * time.Second, // 读超时（默认 3s，调大）
-		WriteTimeout: 60 * time.Second, // 写超时（默认 3s，调大）
-		PoolSize:     20,               // 连接池大小（默认 10，根据并发调大）
-	}
-	return asynq.NewClient(redisOpt)
+	return asynq.NewClient(aqa.redisClientOpt())
 }
 
 // CreateServer creates and returns the Asynq server for processing tasks.
 func (aqa *asynqAccess) CreateServer(ctx context.Context) *asynq.Server {
-	redisOpt := asynq.RedisClientOpt{
-		Addr:         fmt.Sprintf("%s:%d", aqa.appSetting.RedisSetting.Host, aqa.appSetting.RedisSetting.Port),
-		Username:     aqa.appSetting.RedisSetting.Username,
-		Password:     aqa.appSetting.RedisSetting.Password,
-		DialTimeout:  5 * time.Second,  // 连接超时（默认 5s，调大）
-		ReadTimeout:  60 * time.Second, // 读超时（默认 3s，调大）
-		WriteTimeout: 60 * time.Second, // 写超时（默认 3s，调大）
-		PoolSize:     20,               // 连接池大小（默认 10，根据并发调大）
-	}
-	return asynq.NewServer(redisOpt, asynq.Config{
+	return asynq.NewServer(aqa.redisClientOpt(), asynq.Config{
 		Concurrency: 10,
 		Queues: map[string]int{
 			"high":    6,
@@ -76,3 +58,27 @@ func (aqa *asynqAccess) CreateServer(ctx context.Context) *asynq.Server {
 		}),
 	})
 }
+
+// CreatePeriodicTaskManager creates the Asynq periodic task manager with the given config provider.
+// 配置每分钟同步一次，定时配置的增删改在一分钟内生效
+func (aqa *asynqAccess) CreatePeriodicTaskManager(ctx context.Context,
+	provider asynq.PeriodicTaskConfigProvider) (*asynq.PeriodicTaskManager, error) {
+
+	return asynq.NewPeriodicTaskManager(asynq.PeriodicTaskManagerOpts{
+		RedisConnOpt:               aqa.redisClientOpt(),
+		PeriodicTaskConfigProvider: provider,
+		SyncInterval:               1 * time.Minute,
+	})
+}
+
+func (aqa *asynqAccess) redisClientOpt() asynq.RedisClientOpt {
+	return asynq.RedisClientOpt{
+		Addr:         fmt.Sprintf("%s:%d", aqa.appSetting.RedisSetting.Host, aqa.appSetting.RedisSetting.Port),
+		Username:     aqa.appSetting.RedisSetting.Username,
+		Password:     aqa.appSetting.RedisSetting.Password,
+		DialTimeout:  5 * time.Second,  // 连接超时（默认 5s，调大）
+		ReadTimeout:  60 * time.Second, // 读超时（默认 3s，调大）
+		WriteTimeout: 60 * time.Second, // 写超时（默认 3s，调大）
+		PoolSize:     20,               // 连接池大小（默认 10，根据并发调大）
+	}
+}
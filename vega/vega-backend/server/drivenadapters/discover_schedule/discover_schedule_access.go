@@ -0,0 +1,265 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package discover_schedule provides DiscoverSchedule data access operations.
+package discover_schedule
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	libdb "github.com/kweaver-ai/kweaver-go-lib/db"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"vega-backend/common"
+	"vega-backend/interfaces"
+)
+
+const (
+	DISCOVER_SCHEDULE_TABLE_NAME = "t_discover_schedule"
+)
+
+var (
+	dsAccessOnce sync.Once
+	dsAccess     interfaces.DiscoverScheduleAccess
+)
+
+type discoverScheduleAccess struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+}
+
+// NewDiscoverScheduleAccess creates a new DiscoverScheduleAccess.
+func NewDiscoverScheduleAccess(appSetting *common.AppSetting) interfaces.DiscoverScheduleAccess {
+	dsAccessOnce.Do(func() {
+		dsAccess = &discoverScheduleAccess{
+			appSetting: appSetting,
+			db:         libdb.NewDB(&appSetting.DBSetting),
+		}
+	})
+	return dsAccess
+}
+
+// Create creates a new DiscoverSchedule.
+func (da *discoverScheduleAccess) Create(ctx context.Context, schedule *interfaces.DiscoverSchedule) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "Insert into discover_schedule",
+		trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()))
+
+	sqlStr, vals, err := sq.Insert(DISCOVER_SCHEDULE_TABLE_NAME).
+		Columns(
+			"f_catalog_id",
+			"f_cron_expr",
+			"f_enabled",
+			"f_creator",
+			"f_creator_type",
+			"f_create_time",
+			"f_updater",
+			"f_updater_type",
+			"f_update_time",
+		).
+		Values(
+			schedule.CatalogID,
+			schedule.CronExpr,
+			schedule.Enabled,
+			schedule.Creator.ID,
+			schedule.Creator.Type,
+			schedule.CreateTime,
+			schedule.Updater.ID,
+			schedule.Updater.Type,
+			schedule.UpdateTime,
+		).ToSql()
+	if err != nil {
+		logger.Errorf("Failed to build insert discover_schedule sql: %v", err)
+		o11y.Error(ctx, fmt.Sprintf("Failed to build insert discover_schedule sql: %v", err))
+		span.SetStatus(codes.Error, "Build sql failed")
+		return err
+	}
+
+	o11y.Info(ctx, fmt.Sprintf("Insert discover_schedule SQL: %s", sqlStr))
+
+	_, err = da.db.ExecContext(ctx, sqlStr, vals...)
+	if err != nil {
+		logger.Errorf("Insert discover_schedule failed: %v", err)
+		o11y.Error(ctx, fmt.Sprintf("Insert discover_schedule failed: %v", err))
+		span.SetStatus(codes.Error, "Insert failed")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// Update updates the cron expression and enabled status of a DiscoverSchedule.
+func (da *discoverScheduleAccess) Update(ctx context.Context, schedule *interfaces.DiscoverSchedule) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "Update discover_schedule",
+		trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(attr.Key("catalog_id").String(schedule.CatalogID))
+
+	sqlStr, vals, err := sq.Update(DISCOVER_SCHEDULE_TABLE_NAME).
+		SetMap(map[string]any{
+			"f_cron_expr":    schedule.CronExpr,
+			"f_enabled":      schedule.Enabled,
+			"f_updater":      schedule.Updater.ID,
+			"f_updater_type": schedule.Updater.Type,
+			"f_update_time":  schedule.UpdateTime,
+		}).
+		Where(sq.Eq{"f_catalog_id": schedule.CatalogID}).
+		ToSql()
+	if err != nil {
+		span.SetStatus(codes.Error, "Build sql failed")
+		return err
+	}
+
+	_, err = da.db.ExecContext(ctx, sqlStr, vals...)
+	if err != nil {
+		logger.Errorf("Update discover_schedule failed: %v", err)
+		span.SetStatus(codes.Error, "Update failed")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// GetByCatalogID retrieves the DiscoverSchedule of a catalog.
+func (da *discoverScheduleAccess) GetByCatalogID(ctx context.Context, catalogID string) (*interfaces.DiscoverSchedule, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "Query discover_schedule by catalog ID",
+		trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(attr.Key("catalog_id").String(catalogID))
+
+	sqlStr, vals, err := da.selectBuilder().
+		Where(sq.Eq{"f_catalog_id": catalogID}).
+		ToSql()
+	if err != nil {
+		logger.Errorf("Failed to build select discover_schedule sql: %v", err)
+		span.SetStatus(codes.Error, "Build sql failed")
+		return nil, err
+	}
+
+	schedule, err := scanSchedule(da.db.QueryRowContext(ctx, sqlStr, vals...))
+	if err == sql.ErrNoRows {
+		span.SetStatus(codes.Ok, "")
+		return nil, nil
+	}
+	if err != nil {
+		logger.Errorf("Scan discover_schedule failed: %v", err)
+		span.SetStatus(codes.Error, "Scan failed")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return schedule, nil
+}
+
+// ListEnabled lists all enabled DiscoverSchedules.
+func (da *discoverScheduleAccess) ListEnabled(ctx context.Context) ([]*interfaces.DiscoverSchedule, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "List enabled discover_schedules",
+		trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	sqlStr, vals, err := da.selectBuilder().
+		Where(sq.Eq{"f_enabled": true}).
+		ToSql()
+	if err != nil {
+		span.SetStatus(codes.Error, "Build sql failed")
+		return nil, err
+	}
+
+	rows, err := da.db.QueryContext(ctx, sqlStr, vals...)
+	if err != nil {
+		span.SetStatus(codes.Error, "Query failed")
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := make([]*interfaces.DiscoverSchedule, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			span.SetStatus(codes.Error, "Scan row failed")
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return schedules, nil
+}
+
+// DeleteByCatalogIDs deletes DiscoverSchedules by catalog IDs.
+func (da *discoverScheduleAccess) DeleteByCatalogIDs(ctx context.Context, catalogIDs []string) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "Delete discover_schedules",
+		trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(attr.Key("catalog_ids").StringSlice(catalogIDs))
+
+	if len(catalogIDs) == 0 {
+		return nil
+	}
+
+	sqlStr, vals, _ := sq.Delete(DISCOVER_SCHEDULE_TABLE_NAME).
+		Where(sq.Eq{"f_catalog_id": catalogIDs}).
+		ToSql()
+
+	_, err := da.db.ExecContext(ctx, sqlStr, vals...)
+	if err != nil {
+		span.SetStatus(codes.Error, "Delete failed")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+func (da *discoverScheduleAccess) selectBuilder() sq.SelectBuilder {
+	return sq.Select(
+		"f_catalog_id",
+		"f_cron_expr",
+		"f_enabled",
+		"f_creator",
+		"f_creator_type",
+		"f_create_time",
+		"f_updater",
+		"f_updater_type",
+		"f_update_time",
+	).From(DISCOVER_SCHEDULE_TABLE_NAME)
+}
+
+func scanSchedule(row interface{ Scan(dest ...any) error }) (*interfaces.DiscoverSchedule, error) {
+	schedule := &interfaces.DiscoverSchedule{}
+	err := row.Scan(
+		&schedule.CatalogID,
+		&schedule.CronExpr,
+		&schedule.Enabled,
+		&schedule.Creator.ID,
+		&schedule.Creator.Type,
+		&schedule.CreateTime,
+		&schedule.Updater.ID,
+		&schedule.Updater.Type,
+		&schedule.UpdateTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
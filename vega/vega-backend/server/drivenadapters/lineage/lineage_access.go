@@ -0,0 +1,121 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package lineage registers resource lineage to data-model.
+package lineage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+
+	"vega-backend/common"
+	"vega-backend/interfaces"
+)
+
+var (
+	lAccessOnce sync.Once
+	lAccess     interfaces.LineageAccess
+)
+
+type lineageAccess struct {
+	appSetting   *common.AppSetting
+	httpClient   rest.HTTPClient
+	dataModelUrl string
+}
+
+// NewLineageAccess creates a new LineageAccess.
+func NewLineageAccess(appSetting *common.AppSetting) interfaces.LineageAccess {
+	lAccessOnce.Do(func() {
+		lAccess = &lineageAccess{
+			appSetting:   appSetting,
+			httpClient:   common.NewHTTPClient(),
+			dataModelUrl: appSetting.DataModelUrl,
+		}
+	})
+
+	return lAccess
+}
+
+// ReplaceLineageEdges 整体替换 owner 登记到 data-model 的血缘边, 未配置 data-model 时不登记
+func (la *lineageAccess) ReplaceLineageEdges(ctx context.Context, owner interfaces.LineageNode, edges []*interfaces.LineageEdge) error {
+	if la.dataModelUrl == "" {
+		return nil
+	}
+
+	httpUrl := fmt.Sprintf("%s/lineage/owners/%s/%s", la.dataModelUrl, owner.Type, owner.ID)
+
+	if edges == nil {
+		edges = []*interfaces.LineageEdge{}
+	}
+	requestBody := map[string]any{
+		"edges": edges,
+	}
+
+	respCode, result, err := la.httpClient.PutNoUnmarshal(ctx, httpUrl, la.getHeaders(ctx), requestBody)
+	logger.Debugf("put [%s] finished, response code is [%d], result is [%s], error is [%v]", httpUrl, respCode, result, err)
+
+	if err != nil {
+		logger.Errorf("Replace lineage edges request failed: %v", err)
+		return fmt.Errorf("replace lineage edges request failed: %w", err)
+	}
+
+	if respCode != http.StatusNoContent {
+		return la.parseError("replace lineage edges", respCode, result)
+	}
+
+	return nil
+}
+
+// DeleteLineageEdges 删除 owner 登记到 data-model 的血缘边, 未配置 data-model 时不处理
+func (la *lineageAccess) DeleteLineageEdges(ctx context.Context, owner interfaces.LineageNode) error {
+	if la.dataModelUrl == "" {
+		return nil
+	}
+
+	httpUrl := fmt.Sprintf("%s/lineage/owners/%s/%s", la.dataModelUrl, owner.Type, owner.ID)
+
+	respCode, result, err := la.httpClient.DeleteNoUnmarshal(ctx, httpUrl, la.getHeaders(ctx))
+	logger.Debugf("delete [%s] finished, response code is [%d], result is [%s], error is [%v]", httpUrl, respCode, result, err)
+
+	if err != nil {
+		logger.Errorf("Delete lineage edges request failed: %v", err)
+		return fmt.Errorf("delete lineage edges request failed: %w", err)
+	}
+
+	if respCode != http.StatusNoContent {
+		return la.parseError("delete lineage edges", respCode, result)
+	}
+
+	return nil
+}
+
+func (la *lineageAccess) parseError(action string, respCode int, result []byte) error {
+	var baseError rest.BaseError
+	if err := sonic.Unmarshal(result, &baseError); err != nil {
+		logger.Errorf("%s request failed with status code: %d", action, respCode)
+		return fmt.Errorf("%s request failed with status code: %d", action, respCode)
+	}
+
+	logger.Errorf("%s request failed with status code: %d, %s. %v", action, respCode, baseError.Description, baseError.ErrorDetails)
+	return fmt.Errorf("%s request failed with status code: %d, %v", action, respCode, baseError.ErrorDetails)
+}
+
+func (la *lineageAccess) getHeaders(ctx context.Context) map[string]string {
+	accountInfo := interfaces.AccountInfo{}
+	if v := ctx.Value(interfaces.ACCOUNT_INFO_KEY); v != nil {
+		accountInfo = v.(interfaces.AccountInfo)
+	}
+	return map[string]string{
+		interfaces.CONTENT_TYPE_NAME:        interfaces.CONTENT_TYPE_JSON,
+		interfaces.HTTP_HEADER_ACCOUNT_ID:   accountInfo.ID,
+		interfaces.HTTP_HEADER_ACCOUNT_TYPE: accountInfo.Type,
+	}
+}
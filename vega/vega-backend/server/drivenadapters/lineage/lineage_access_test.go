@@ -0,0 +1,98 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package lineage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"vega-backend/common"
+	"vega-backend/interfaces"
+)
+
+func newTestLineageAccess(url string) *lineageAccess {
+	return &lineageAccess{
+		appSetting:   &common.AppSetting{},
+		httpClient:   common.NewHTTPClient(),
+		dataModelUrl: url,
+	}
+}
+
+func TestReplaceLineageEdges(t *testing.T) {
+	owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_RESOURCE, ID: "r1"}
+	edges := []*interfaces.LineageEdge{{
+		Source: interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_CATALOG, ID: "c1"},
+		Target: owner,
+	}}
+
+	var gotMethod, gotPath string
+	var gotBody struct {
+		Edges []*interfaces.LineageEdge `json:"edges"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	la := newTestLineageAccess(server.URL + "/api/mdl-data-model/in/v1")
+	if err := la.ReplaceLineageEdges(context.Background(), owner, edges); err != nil {
+		t.Fatalf("ReplaceLineageEdges failed: %v", err)
+	}
+	if gotMethod != http.MethodPut || gotPath != "/api/mdl-data-model/in/v1/lineage/owners/resource/r1" {
+		t.Errorf("unexpected request %s %s", gotMethod, gotPath)
+	}
+	if len(gotBody.Edges) != 1 || gotBody.Edges[0].Source.ID != "c1" || gotBody.Edges[0].Target != owner {
+		t.Errorf("unexpected edges %+v", gotBody.Edges)
+	}
+}
+
+func TestReplaceLineageEdges_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error_code":"DataModel.Lineage.InvalidParameter.Edges","error_details":"bad edge"}`))
+	}))
+	defer server.Close()
+
+	la := newTestLineageAccess(server.URL)
+	owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_RESOURCE, ID: "r1"}
+	if err := la.ReplaceLineageEdges(context.Background(), owner, nil); err == nil {
+		t.Fatal("expected error for status 400")
+	}
+}
+
+func TestDeleteLineageEdges(t *testing.T) {
+	var gotMethod, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	la := newTestLineageAccess(server.URL)
+	owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_RESOURCE, ID: "r1"}
+	if err := la.DeleteLineageEdges(context.Background(), owner); err != nil {
+		t.Fatalf("DeleteLineageEdges failed: %v", err)
+	}
+	if gotMethod != http.MethodDelete || gotPath != "/lineage/owners/resource/r1" {
+		t.Errorf("unexpected request %s %s", gotMethod, gotPath)
+	}
+}
+
+func TestLineageAccess_WithoutDataModel(t *testing.T) {
+	la := newTestLineageAccess("")
+	owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_RESOURCE, ID: "r1"}
+	if err := la.ReplaceLineageEdges(context.Background(), owner, nil); err != nil {
+		t.Errorf("ReplaceLineageEdges without data-model should be skipped, got %v", err)
+	}
+	if err := la.DeleteLineageEdges(context.Background(), owner); err != nil {
+		t.Errorf("DeleteLineageEdges without data-model should be skipped, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package schema_drift provides SchemaDrift data access operations.
+package schema_drift
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	libdb "github.com/kweaver-ai/kweaver-go-lib/db"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	o11y "github.com/kweaver-ai/kweaver-go-lib/observability"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"vega-backend/common"
+	"vega-backend/interfaces"
+)
+
+const (
+	SCHEMA_DRIFT_TABLE_NAME = "t_resource_schema_drift"
+)
+
+var (
+	sdAccessOnce sync.Once
+	sdAccess     interfaces.SchemaDriftAccess
+)
+
+type schemaDriftAccess struct {
+	appSetting *common.AppSetting
+	db         *sql.DB
+}
+
+// NewSchemaDriftAccess creates a new SchemaDriftAccess.
+func NewSchemaDriftAccess(appSetting *common.AppSetting) interfaces.SchemaDriftAccess {
+	sdAccessOnce.Do(func() {
+		sdAccess = &schemaDriftAccess{
+			appSetting: appSetting,
+			db:         libdb.NewDB(&appSetting.DBSetting),
+		}
+	})
+	return sdAccess
+}
+
+// CreateBatch creates SchemaDrifts.
+func (sa *schemaDriftAccess) CreateBatch(ctx context.Context, drifts []*interfaces.SchemaDrift) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "Insert into resource_schema_drift",
+		trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	span.SetAttributes(
+		attr.Key("db_url").String(libdb.GetDBUrl()),
+		attr.Key("db_type").String(libdb.GetDBType()),
+		attr.Key("count").Int(len(drifts)))
+
+	if len(drifts) == 0 {
+		span.SetStatus(codes.Ok, "")
+		return nil
+	}
+
+	builder := sq.Insert(SCHEMA_DRIFT_TABLE_NAME).
+		Columns(
+			"f_id",
+			"f_resource_id",
+			"f_catalog_id",
+			"f_task_id",
+			"f_source_identifier",
+			"f_changes",
+			"f_detect_time",
+		)
+	for _, drift := range drifts {
+		changesStr, err := sonic.MarshalString(drift.Changes)
+		if err != nil {
+			logger.Errorf("Failed to marshal schema drift changes: %v", err)
+			span.SetStatus(codes.Error, "Marshal changes failed")
+			return err
+		}
+		builder = builder.Values(
+			drift.ID,
+			drift.ResourceID,
+			drift.CatalogID,
+			drift.TaskID,
+			drift.SourceIdentifier,
+			changesStr,
+			drift.DetectTime,
+		)
+	}
+
+	sqlStr, vals, err := builder.ToSql()
+	if err != nil {
+		logger.Errorf("Failed to build insert resource_schema_drift sql: %v", err)
+		o11y.Error(ctx, fmt.Sprintf("Failed to build insert resource_schema_drift sql: %v", err))
+		span.SetStatus(codes.Error, "Build sql failed")
+		return err
+	}
+
+	_, err = sa.db.ExecContext(ctx, sqlStr, vals...)
+	if err != nil {
+		logger.Errorf("Insert resource_schema_drift failed: %v", err)
+		o11y.Error(ctx, fmt.Sprintf("Insert resource_schema_drift failed: %v", err))
+		span.SetStatus(codes.Error, "Insert failed")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+// List lists SchemaDrifts with filters, newest first.
+func (sa *schemaDriftAccess) List(ctx context.Context, params interfaces.SchemaDriftQueryParams) ([]*interfaces.SchemaDrift, int64, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "List resource_schema_drifts",
+		trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	builder := sq.Select(
+		"f_id",
+		"f_resource_id",
+		"f_catalog_id",
+		"f_task_id",
+		"f_source_identifier",
+		"f_changes",
+		"f_detect_time",
+	).From(SCHEMA_DRIFT_TABLE_NAME)
+
+	countBuilder := sq.Select("COUNT(*)").From(SCHEMA_DRIFT_TABLE_NAME)
+
+	if params.ResourceID != "" {
+		builder = builder.Where(sq.Eq{"f_resource_id": params.ResourceID})
+		countBuilder = countBuilder.Where(sq.Eq{"f_resource_id": params.ResourceID})
+	}
+
+	countSql, countVals, _ := countBuilder.ToSql()
+	var total int64
+	err := sa.db.QueryRowContext(ctx, countSql, countVals...).Scan(&total)
+	if err != nil {
+		logger.Errorf("Failed to count resource_schema_drifts: %v", err)
+		span.SetStatus(codes.Error, "Count failed")
+		return nil, 0, err
+	}
+
+	// Pagination
+	if params.Limit > 0 {
+		builder = builder.Limit(uint64(params.Limit)).Offset(uint64(params.Offset))
+	}
+	builder = builder.OrderBy("f_detect_time DESC")
+
+	sqlStr, vals, err := builder.ToSql()
+	if err != nil {
+		span.SetStatus(codes.Error, "Build sql failed")
+		return nil, 0, err
+	}
+
+	rows, err := sa.db.QueryContext(ctx, sqlStr, vals...)
+	if err != nil {
+		span.SetStatus(codes.Error, "Query failed")
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	drifts := make([]*interfaces.SchemaDrift, 0)
+	for rows.Next() {
+		drift := &interfaces.SchemaDrift{}
+		var changesStr sql.NullString
+
+		err := rows.Scan(
+			&drift.ID,
+			&drift.ResourceID,
+			&drift.CatalogID,
+			&drift.TaskID,
+			&drift.SourceIdentifier,
+			&changesStr,
+			&drift.DetectTime,
+		)
+		if err != nil {
+			span.SetStatus(codes.Error, "Scan row failed")
+			return nil, 0, err
+		}
+
+		drift.Changes = []interfaces.SchemaChange{}
+		if changesStr.Valid && changesStr.String != "" {
+			_ = sonic.UnmarshalString(changesStr.String, &drift.Changes)
+		}
+
+		drifts = append(drifts, drift)
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return drifts, total, nil
+}
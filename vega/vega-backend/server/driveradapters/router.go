@@ -63,7 +63,7 @@ func NewRestHandler(appSetting *common.AppSetting) RestHandler {
 		cts:               connector_type.NewConnectorTypeService(appSetting),
 		dts:               discover_task.NewDiscoverTaskService(appSetting),
 		rds:               resource_data.NewResourceDataService(appSetting),
-		querySessionStore: query.NewMemorySessionStore(0),
+		querySessionStore: query.NewQuerySessionStore(appSetting),
 	}
 }
 
@@ -87,6 +87,9 @@ func (r *restHandler) RegisterPublic(engine *gin.Engine) {
 			catalogs.GET("/:ids/health-status", r.GetCatalogHealthStatus)
 			catalogs.POST("/:id/test-connection", r.TestConnection)
 			catalogs.POST("/:id/discover", r.DiscoverCatalogResources)
+			catalogs.GET("/:ids/discover-schedule", r.GetDiscoverSchedule)
+			catalogs.PUT("/:id/discover-schedule", r.verifyJsonContentType(), r.SetDiscoverSchedule)
+			catalogs.DELETE("/:ids/discover-schedule", r.DeleteDiscoverSchedule)
 			catalogs.GET("/:ids/resources", r.ListCatalogResources)
 		}
 
@@ -98,6 +101,7 @@ func (r *restHandler) RegisterPublic(engine *gin.Engine) {
 			resources.GET("/:ids", r.GetResources)
 			resources.PUT("/:id", r.verifyJsonContentType(), r.UpdateResource)
 			resources.DELETE("/:ids", r.DeleteResources)
+			resources.GET("/:ids/schema-drifts", r.ListResourceSchemaDrifts)
 
 			resources.POST("/:id/data", r.verifyJsonContentType(), r.QueryResourceData) // method override GET list and get
 
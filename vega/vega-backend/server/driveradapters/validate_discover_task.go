@@ -0,0 +1,33 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package driveradapters
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"github.com/robfig/cron/v3"
+
+	verrors "vega-backend/errors"
+	"vega-backend/interfaces"
+)
+
+// ValidateDiscoverScheduleRequest 定时发现配置的合法性校验，cron 表达式须为标准 5 段格式
+func ValidateDiscoverScheduleRequest(ctx context.Context, req *interfaces.DiscoverScheduleRequest) error {
+	req.CronExpr = strings.TrimSpace(req.CronExpr)
+	if req.CronExpr == "" {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Task_InvalidParameter_CronExpr).
+			WithErrorDetails("The cron_expr is empty")
+	}
+
+	if _, err := cron.ParseStandard(req.CronExpr); err != nil {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Task_InvalidParameter_CronExpr).
+			WithErrorDetails(err.Error())
+	}
+	return nil
+}
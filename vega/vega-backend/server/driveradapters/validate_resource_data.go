@@ -43,6 +43,18 @@ func ValidateResourceDataQueryParams(ctx context.Context, params *interfaces.Res
 		return err
 	}
 
+	// 校验 topic 读取范围
+	err = validateTopicRange(ctx, params.TopicRange)
+	if err != nil {
+		return err
+	}
+
+	// 校验 metric 查询范围
+	err = validateMetricRange(ctx, params.MetricRange)
+	if err != nil {
+		return err
+	}
+
 	// 过滤条件用map接，然后再decode到condCfg中
 	var actualCond *interfaces.FilterCondCfg
 	err = mapstructure.Decode(params.FilterCondition, &actualCond)
@@ -104,6 +116,71 @@ func validateSortFields(ctx context.Context, sortFields []*interfaces.SortField)
 	return nil
 }
 
+// topic 读取范围校验：offset 范围与时间范围二选一，起点不大于终点
+func validateTopicRange(ctx context.Context, topicRange *interfaces.TopicReadRange) error {
+	if topicRange == nil {
+		return nil
+	}
+
+	for _, p := range topicRange.Partitions {
+		if p < 0 {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_InvalidParameter_TopicRange).
+				WithErrorDetails(fmt.Sprintf("Partition should be >= 0, got %d", p))
+		}
+	}
+
+	hasOffset := topicRange.StartOffset != nil || topicRange.EndOffset != nil
+	hasTime := topicRange.StartTime != 0 || topicRange.EndTime != 0
+	if hasOffset && hasTime {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_InvalidParameter_TopicRange).
+			WithErrorDetails("Offset range and time range cannot be specified at the same time")
+	}
+
+	if (topicRange.StartOffset != nil && *topicRange.StartOffset < 0) ||
+		(topicRange.EndOffset != nil && *topicRange.EndOffset < 0) {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_InvalidParameter_TopicRange).
+			WithErrorDetails("Start offset and end offset should be >= 0")
+	}
+	if topicRange.StartOffset != nil && topicRange.EndOffset != nil && *topicRange.StartOffset > *topicRange.EndOffset {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_InvalidParameter_TopicRange).
+			WithErrorDetails("Start offset should be <= end offset")
+	}
+
+	if topicRange.StartTime < 0 || topicRange.EndTime < 0 {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_InvalidParameter_TopicRange).
+			WithErrorDetails("Start time and end time should be >= 0")
+	}
+	if topicRange.StartTime > 0 && topicRange.EndTime > 0 && topicRange.StartTime > topicRange.EndTime {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_InvalidParameter_TopicRange).
+			WithErrorDetails("Start time should be <= end time")
+	}
+
+	return nil
+}
+
+// metric 查询范围校验：起点小于终点，单条序列点数不超过上限
+func validateMetricRange(ctx context.Context, metricRange *interfaces.MetricQueryRange) error {
+	if metricRange == nil {
+		return nil
+	}
+
+	if metricRange.Start < 0 || metricRange.End < 0 || metricRange.Step < 0 {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_InvalidParameter_MetricRange).
+			WithErrorDetails("Start, end and step should be >= 0")
+	}
+	if metricRange.Start > 0 && metricRange.End > 0 && metricRange.Start >= metricRange.End {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_InvalidParameter_MetricRange).
+			WithErrorDetails("Start should be < end")
+	}
+	if metricRange.Start > 0 && metricRange.End > 0 && metricRange.Step > 0 &&
+		(metricRange.End-metricRange.Start)/metricRange.Step > interfaces.MAX_METRIC_POINTS {
+		return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_InvalidParameter_MetricRange).
+			WithErrorDetails(fmt.Sprintf("The number of points per series should be <= %d, please increase the step", interfaces.MAX_METRIC_POINTS))
+	}
+
+	return nil
+}
+
 func validateFilterCondCfg(ctx context.Context, cfg *interfaces.FilterCondCfg) error {
 	if cfg == nil {
 		return nil
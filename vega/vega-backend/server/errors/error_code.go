@@ -26,6 +26,8 @@ const (
 	VegaBackend_InvalidParameter_ID                       = "VegaBackend.InvalidParameter.ID"
 	VegaBackend_InvalidParameter_OverrideMethod           = "VegaBackend.InvalidParameter.OverrideMethod"
 	VegaBackend_InvalidParameter_Format                   = "VegaBackend.InvalidParameter.Format"
+	VegaBackend_InvalidParameter_TopicRange               = "VegaBackend.InvalidParameter.TopicRange"
+	VegaBackend_InvalidParameter_MetricRange              = "VegaBackend.InvalidParameter.MetricRange"
 	VegaBackend_InvalidParameter_FilterCondition          = "VegaBackend.InvalidParameter.FilterCondition"
 	VegaBackend_InvalidParameter_FilterConditionValue     = "VegaBackend.InvalidParameter.FilterConditionValue"
 	VegaBackend_InvalidParameter_FilterConditionValueFrom = "VegaBackend.InvalidParameter.FilterConditionValueFrom"
@@ -64,6 +66,8 @@ var (
 		VegaBackend_InvalidParameter_ID,
 		VegaBackend_InvalidParameter_OverrideMethod,
 		VegaBackend_InvalidParameter_Format,
+		VegaBackend_InvalidParameter_TopicRange,
+		VegaBackend_InvalidParameter_MetricRange,
 		VegaBackend_InvalidParameter_FilterCondition,
 		VegaBackend_InvalidParameter_FilterConditionValue,
 		VegaBackend_InvalidParameter_FilterConditionValueFrom,
@@ -15,6 +15,7 @@ const (
 	VegaBackend_Resource_InvalidParameter_CatalogID = "VegaBackend.Resource.InvalidParameter.CatalogID"
 	VegaBackend_Resource_LengthExceeded_Name        = "VegaBackend.Resource.LengthExceeded.Name"
 	VegaBackend_Resource_LengthExceeded_Description = "VegaBackend.Resource.LengthExceeded.Description"
+	VegaBackend_Resource_FileScanLimitExceeded      = "VegaBackend.Resource.FileScanLimitExceeded"
 
 	// 403 Forbidden
 	VegaBackend_Resource_NotFound        = "VegaBackend.Resource.NotFound"
@@ -41,6 +42,7 @@ var ResourceErrCodeList = []string{
 	VegaBackend_Resource_InvalidParameter_CatalogID,
 	VegaBackend_Resource_LengthExceeded_Name,
 	VegaBackend_Resource_LengthExceeded_Description,
+	VegaBackend_Resource_FileScanLimitExceeded,
 	VegaBackend_Resource_NotFound,
 	VegaBackend_Resource_NameExists,
 	VegaBackend_Resource_CatalogNotFound,
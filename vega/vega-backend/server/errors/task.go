@@ -8,12 +8,19 @@ package errors
 
 // Task 相关错误码
 const (
+	// 400 Bad Request
+	VegaBackend_Task_InvalidParameter_CronExpr = "VegaBackend.Task.InvalidParameter.CronExpr"
+
 	// 404 Not Found
-	VegaBackend_Task_NotFound = "VegaBackend.Task.NotFound"
+	VegaBackend_Task_NotFound         = "VegaBackend.Task.NotFound"
+	VegaBackend_Task_ScheduleNotFound = "VegaBackend.Task.ScheduleNotFound"
 )
 
 var (
 	TaskErrCodeList = []string{
+		VegaBackend_Task_InvalidParameter_CronExpr,
+
 		VegaBackend_Task_NotFound,
+		VegaBackend_Task_ScheduleNotFound,
 	}
 )
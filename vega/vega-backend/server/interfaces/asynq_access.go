@@ -15,6 +15,8 @@ import (
 const (
 	// DiscoverTaskType is the task type for discover tasks.
 	DiscoverTaskType = "discover:execute"
+	// DiscoverScheduleTaskType is the task type enqueued by the scheduler to trigger a scheduled discover.
+	DiscoverScheduleTaskType = "discover:schedule"
 )
 
 // AsynqAccess defines the interface for creating Asynq client and server.
@@ -25,4 +27,6 @@ type AsynqAccess interface {
 	CreateClient(ctx context.Context) *asynq.Client
 	// CreateServer creates and returns the Asynq server for processing tasks.
 	CreateServer(ctx context.Context) *asynq.Server
+	// CreatePeriodicTaskManager creates the Asynq periodic task manager with the given config provider.
+	CreatePeriodicTaskManager(ctx context.Context, provider asynq.PeriodicTaskConfigProvider) (*asynq.PeriodicTaskManager, error)
 }
@@ -67,18 +67,48 @@ type QueryResult struct {
 
 // FileMeta represents file metadata.
 type FileMeta struct {
-	Path         string `json:"path"`
-	Name         string `json:"name"`
-	Size         int64  `json:"size"`
-	LastModified int64  `json:"last_modified"`
-	ContentType  string `json:"content_type"`
+	Path         string         `json:"path"` // 文件完整路径，如 bucket/dir/data.csv
+	Name         string         `json:"name"`
+	Size         int64          `json:"size"`
+	LastModified int64          `json:"last_modified"`
+	ContentType  string         `json:"content_type"`
+	Bucket       string         `json:"bucket,omitempty"`
+	Key          string         `json:"key,omitempty"` // 对象 key
+	ETag         string         `json:"etag,omitempty"`
+	Format       string         `json:"format,omitempty"` // csv | jsonl | parquet，为空表示不支持读取内容
+	Fields       []FieldMeta    `json:"fields,omitempty"` // 采样文件内容推断出的字段结构
+	Properties   map[string]any `json:"properties,omitempty"`
+}
+
+// FilesetMeta represents fileset (bucket or directory prefix) metadata.
+type FilesetMeta struct {
+	Path      string      `json:"path"` // 文件集完整路径，以 / 结尾，如 bucket/dir/
+	Bucket    string      `json:"bucket"`
+	Prefix    string      `json:"prefix"`
+	FileCount int         `json:"file_count"` // 直接子文件数
+	TotalSize int64       `json:"total_size"` // 直接子文件总大小
+	Fields    []FieldMeta `json:"fields,omitempty"`
+	Files     []*FileMeta `json:"-"` // 直接子文件
 }
 
 // TopicMeta represents message topic metadata.
 type TopicMeta struct {
-	Name       string `json:"name"`
-	Partitions int    `json:"partitions"`
-	Replicas   int    `json:"replicas"`
+	Name       string         `json:"name"`
+	Partitions int            `json:"partitions"`
+	Replicas   int            `json:"replicas"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Fields     []FieldMeta    `json:"fields,omitempty"` // 采样消息推断出的字段结构
+}
+
+// MetricMeta represents time-series metric metadata.
+type MetricMeta struct {
+	Name        string              `json:"name"`
+	Type        string              `json:"type,omitempty"` // counter | gauge | histogram | summary | unknown
+	Help        string              `json:"help,omitempty"`
+	Unit        string              `json:"unit,omitempty"`
+	Labels      []string            `json:"labels,omitempty"`       // 标签名列表（不含 __name__）
+	LabelValues map[string][]string `json:"label_values,omitempty"` // 各标签的取值样例
+	Properties  map[string]any      `json:"properties,omitempty"`
 }
 
 // MetricResult represents time-series query result.
@@ -0,0 +1,31 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package interfaces defines entities, DTOs, and service interfaces.
+package interfaces
+
+// DiscoverSchedule represents a per-catalog discover schedule.
+// 每个 catalog 至多一条定时发现配置，由 discover worker 按 cron 表达式自动触发
+type DiscoverSchedule struct {
+	CatalogID string `json:"catalog_id"`
+	CronExpr  string `json:"cron_expr"` // 标准 5 段 cron 表达式，如 "0 2 * * *"
+	Enabled   bool   `json:"enabled"`
+
+	Creator    AccountInfo `json:"creator"`
+	CreateTime int64       `json:"create_time"`
+	Updater    AccountInfo `json:"updater"`
+	UpdateTime int64       `json:"update_time"`
+}
+
+// DiscoverScheduleRequest represents create/update discover schedule request.
+type DiscoverScheduleRequest struct {
+	CronExpr string `json:"cron_expr"`
+	Enabled  *bool  `json:"enabled"` // 未指定时默认启用
+}
+
+// DiscoverScheduleMessage represents the asynq payload of a scheduled discover trigger.
+type DiscoverScheduleMessage struct {
+	CatalogID string `json:"catalog_id"`
+}
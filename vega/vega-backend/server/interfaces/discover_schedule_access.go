@@ -0,0 +1,25 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package interfaces defines entities, DTOs, and service interfaces.
+package interfaces
+
+import "context"
+
+// DiscoverScheduleAccess defines discover schedule data access interface.
+//
+//go:generate mockgen -source ../interfaces/discover_schedule_access.go -destination ../interfaces/mock/mock_discover_schedule_access.go
+type DiscoverScheduleAccess interface {
+	// Create creates a new DiscoverSchedule.
+	Create(ctx context.Context, schedule *DiscoverSchedule) error
+	// Update updates the cron expression and enabled status of a DiscoverSchedule.
+	Update(ctx context.Context, schedule *DiscoverSchedule) error
+	// GetByCatalogID retrieves the DiscoverSchedule of a catalog.
+	GetByCatalogID(ctx context.Context, catalogID string) (*DiscoverSchedule, error)
+	// ListEnabled lists all enabled DiscoverSchedules.
+	ListEnabled(ctx context.Context) ([]*DiscoverSchedule, error)
+	// DeleteByCatalogIDs deletes DiscoverSchedules by catalog IDs.
+	DeleteByCatalogIDs(ctx context.Context, catalogIDs []string) error
+}
@@ -14,12 +14,26 @@ import "context"
 type DiscoverTaskService interface {
 	// Create creates a new DiscoverTask and sends message to Kafka.
 	Create(ctx context.Context, catalogID string) (string, error)
+	// CreateScheduled creates a scheduled DiscoverTask; skipped when the catalog already has an unfinished task.
+	CreateScheduled(ctx context.Context, catalogID string) (string, error)
 	// GetByID retrieves a DiscoverTask by ID.
 	GetByID(ctx context.Context, id string) (*DiscoverTask, error)
 	// List lists DiscoverTasks for a catalog.
 	List(ctx context.Context, params DiscoverTaskQueryParams) ([]*DiscoverTask, int64, error)
 	// UpdateStatus updates a DiscoverTask's status.
 	UpdateStatus(ctx context.Context, id string, status string, message string, stime int64) error
-	// UpdateResult updates a DiscoverTask's result.
+	// UpdateResult updates a DiscoverTask's result and records its schema drifts.
 	UpdateResult(ctx context.Context, id string, result *DiscoverResult, stime int64) error
+
+	// SetSchedule creates or updates the discover schedule of a catalog.
+	SetSchedule(ctx context.Context, catalogID string, req *DiscoverScheduleRequest) (*DiscoverSchedule, error)
+	// GetSchedule retrieves the discover schedule of a catalog.
+	GetSchedule(ctx context.Context, catalogID string) (*DiscoverSchedule, error)
+	// DeleteSchedule deletes the discover schedule of a catalog.
+	DeleteSchedule(ctx context.Context, catalogID string) error
+	// ListEnabledSchedules lists all enabled discover schedules.
+	ListEnabledSchedules(ctx context.Context) ([]*DiscoverSchedule, error)
+
+	// ListSchemaDrifts lists the schema drift history of a resource.
+	ListSchemaDrifts(ctx context.Context, params SchemaDriftQueryParams) ([]*SchemaDrift, int64, error)
 }
@@ -17,7 +17,11 @@ type DiscoverResult struct {
 	NewCount       int    `json:"new_count"`
 	StaleCount     int    `json:"stale_count"`
 	UnchangedCount int    `json:"unchanged_count"`
+	DriftCount     int    `json:"drift_count"` // 结构发生变化的资源数（含 stale）
 	Message        string `json:"message"`
+
+	// Drifts 本次发现检测到的结构变化报告，按资源聚合
+	Drifts []*SchemaDrift `json:"drifts,omitempty"`
 }
 
 // DiscoverWorker interface defines discover execution functionality.
@@ -0,0 +1,42 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package interfaces
+
+import (
+	"context"
+)
+
+const (
+	// data-model 血缘节点类型
+	LINEAGE_NODE_TYPE_CATALOG  = "catalog"
+	LINEAGE_NODE_TYPE_RESOURCE = "resource"
+)
+
+// LineageNode 血缘节点
+type LineageNode struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// LineageFieldMapping 字段级血缘, 上游节点的字段流向下游节点的字段
+type LineageFieldMapping struct {
+	SourceField string `json:"source_field"`
+	TargetField string `json:"target_field"`
+}
+
+// LineageEdge 登记到 data-model 的血缘边, 下游节点为资源
+type LineageEdge struct {
+	Source LineageNode           `json:"source"`
+	Target LineageNode           `json:"target"`
+	Fields []LineageFieldMapping `json:"fields"`
+}
+
+//go:generate mockgen -source ../interfaces/lineage_access.go -destination ../interfaces/mock/mock_lineage_access.go
+type LineageAccess interface {
+	// 整体替换 owner 在 data-model 中登记的血缘边
+	ReplaceLineageEdges(ctx context.Context, owner LineageNode, edges []*LineageEdge) error
+	DeleteLineageEdges(ctx context.Context, owner LineageNode) error
+}
@@ -49,6 +49,21 @@ func (mr *MockAsynqAccessMockRecorder) CreateClient(ctx interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClient", reflect.TypeOf((*MockAsynqAccess)(nil).CreateClient), ctx)
 }
 
+// CreatePeriodicTaskManager mocks base method.
+func (m *MockAsynqAccess) CreatePeriodicTaskManager(ctx context.Context, provider asynq.PeriodicTaskConfigProvider) (*asynq.PeriodicTaskManager, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePeriodicTaskManager", ctx, provider)
+	ret0, _ := ret[0].(*asynq.PeriodicTaskManager)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePeriodicTaskManager indicates an expected call of CreatePeriodicTaskManager.
+func (mr *MockAsynqAccessMockRecorder) CreatePeriodicTaskManager(ctx, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePeriodicTaskManager", reflect.TypeOf((*MockAsynqAccess)(nil).CreatePeriodicTaskManager), ctx, provider)
+}
+
 // CreateServer mocks base method.
 func (m *MockAsynqAccess) CreateServer(ctx context.Context) *asynq.Server {
 	m.ctrl.T.Helper()
@@ -6,6 +6,7 @@
 package interfaces
 
 import (
+	"errors"
 	"time"
 )
 
@@ -15,14 +16,52 @@ const (
 
 	// 最大查询长度设置为10000
 	MAX_SEARCH_SIZE = 10000
+
+	// topic 单次查询最多扫描的消息数，按分区均分
+	MAX_TOPIC_SCAN_MESSAGES = 10000
+
+	// metric 范围查询单条序列的最大点数，与 Prometheus 的限制一致
+	MAX_METRIC_POINTS = 11000
+
+	// file/fileset 单次查询最多扫描的行数
+	MAX_FILE_SCAN_ROWS = 100000
 )
 
+// ErrFileScanLimitExceeded file/fileset 查询需要扫描的行数超过 MAX_FILE_SCAN_ROWS，
+// 只扫描部分行会得到错误的总数与排序结果，因此直接报错
+var ErrFileScanLimitExceeded = errors.New("file scan rows limit exceeded")
+
 // SortField represents a field to sort by.
 type SortField struct {
 	Field     string `json:"field"`
 	Direction string `json:"direction"`
 }
 
+// TopicReadRange represents the bounded read range of a topic resource.
+// offset 范围与时间范围二选一，均不指定时读取各分区最新的消息
+type TopicReadRange struct {
+	Partitions  []int  `json:"partitions,omitempty"`   // 指定分区，为空时读取全部分区
+	StartOffset *int64 `json:"start_offset,omitempty"` // 起始 offset（包含），作用于每个分区
+	EndOffset   *int64 `json:"end_offset,omitempty"`   // 结束 offset（不包含），作用于每个分区
+	StartTime   int64  `json:"start_time,omitempty"`   // 起始时间（包含），毫秒时间戳
+	EndTime     int64  `json:"end_time,omitempty"`     // 结束时间（不包含），毫秒时间戳
+}
+
+// MetricQueryRange represents the range query window of a metric resource.
+// 均不指定时查询最近 1 小时，步长按范围自动计算
+type MetricQueryRange struct {
+	Start int64 `json:"start,omitempty"` // 起始时间，毫秒时间戳
+	End   int64 `json:"end,omitempty"`   // 结束时间，毫秒时间戳
+	Step  int64 `json:"step,omitempty"`  // 步长，毫秒
+}
+
+// IndexPageCursor represents the deep paging state of an index resource.
+// 首页 PitID 与 SearchAfter 均为空，connector 打开 PIT 并在每页查询后回填下一页的游标
+type IndexPageCursor struct {
+	PitID       string // point in time id
+	SearchAfter []any  // 上一页最后一条文档的排序值
+}
+
 // ResourceDataQueryParams represents query parameters for data retrieval.
 type ResourceDataQueryParams struct {
 	Offset int          `json:"offset,omitempty"`
@@ -33,6 +72,11 @@ type ResourceDataQueryParams struct {
 
 	OutputFields []string `json:"output_fields"` // 指定输出的字段列表
 
+	// TopicRange topic 资源的读取范围（按 offset 或时间），仅对 topic 资源生效
+	TopicRange *TopicReadRange `json:"topic_range,omitempty"`
+	// MetricRange metric 资源的范围查询窗口，仅对 metric 资源生效
+	MetricRange *MetricQueryRange `json:"metric_range,omitempty"`
+
 	NeedTotal bool          `json:"need_total,omitempty"`
 	Format    string        `json:"-"`
 	Timeout   time.Duration `json:"-"` // 超时时间，查询参数
@@ -42,4 +86,7 @@ type ResourceDataQueryParams struct {
 
 	// CursorEncoded keyset 游标值，由 query session 注入；非空时用 WHERE (sort_cols) > cursor 替代 OFFSET
 	CursorEncoded string `json:"-"`
+
+	// IndexCursor 索引资源的 PIT + search_after 分页游标；非空时忽略 Offset，可翻过 max_result_window
+	IndexCursor *IndexPageCursor `json:"-"`
 }
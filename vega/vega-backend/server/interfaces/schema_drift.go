@@ -0,0 +1,50 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package interfaces defines entities, DTOs, and service interfaces.
+package interfaces
+
+const (
+	// SchemaChange type constants.
+	SchemaChangeColumnAdded         string = "column_added"
+	SchemaChangeColumnDropped       string = "column_dropped"
+	SchemaChangeColumnTypeChanged   string = "column_type_changed"
+	SchemaChangePrimaryKeyChanged   string = "primary_key_changed"
+	SchemaChangeResourceStale       string = "resource_stale"       // 源端表/索引已不存在
+	SchemaChangeResourceReactivated string = "resource_reactivated" // 源端表/索引重新出现
+)
+
+// SchemaChange represents a single structural change of a resource.
+type SchemaChange struct {
+	Type   string `json:"type"`
+	Column string `json:"column,omitempty"`
+
+	// 字段类型变化：OldType/NewType 为 VEGA 类型，OldOrigType/NewOrigType 为源端原始类型
+	OldType     string `json:"old_type,omitempty"`
+	NewType     string `json:"new_type,omitempty"`
+	OldOrigType string `json:"old_orig_type,omitempty"`
+	NewOrigType string `json:"new_orig_type,omitempty"`
+
+	// 主键变化
+	OldPKs []string `json:"old_primary_keys,omitempty"`
+	NewPKs []string `json:"new_primary_keys,omitempty"`
+}
+
+// SchemaDrift represents the schema drift of a resource detected by one discover run.
+type SchemaDrift struct {
+	ID               string         `json:"id"`
+	ResourceID       string         `json:"resource_id"`
+	CatalogID        string         `json:"catalog_id"`
+	TaskID           string         `json:"task_id"`
+	SourceIdentifier string         `json:"source_identifier"`
+	Changes          []SchemaChange `json:"changes"`
+	DetectTime       int64          `json:"detect_time"`
+}
+
+// SchemaDriftQueryParams holds schema drift list query parameters.
+type SchemaDriftQueryParams struct {
+	PaginationQueryParams
+	ResourceID string
+}
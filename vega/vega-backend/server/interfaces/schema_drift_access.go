@@ -0,0 +1,19 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package interfaces defines entities, DTOs, and service interfaces.
+package interfaces
+
+import "context"
+
+// SchemaDriftAccess defines schema drift data access interface.
+//
+//go:generate mockgen -source ../interfaces/schema_drift_access.go -destination ../interfaces/mock/mock_schema_drift_access.go
+type SchemaDriftAccess interface {
+	// CreateBatch creates SchemaDrifts.
+	CreateBatch(ctx context.Context, drifts []*SchemaDrift) error
+	// List lists SchemaDrifts with filters, newest first.
+	List(ctx context.Context, params SchemaDriftQueryParams) ([]*SchemaDrift, int64, error)
+}
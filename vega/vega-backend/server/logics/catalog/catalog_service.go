@@ -25,6 +25,7 @@ import (
 
 	"vega-backend/common"
 	catalogAccess "vega-backend/drivenadapters/catalog"
+	discoverScheduleAccess "vega-backend/drivenadapters/discover_schedule"
 	verrors "vega-backend/errors"
 	"vega-backend/interfaces"
 	"vega-backend/logics/connectors/factory"
@@ -46,6 +47,7 @@ type catalogService struct {
 	appSetting *common.AppSetting
 	cipher     kwcrypto.Cipher
 	ca         interfaces.CatalogAccess
+	dsa        interfaces.DiscoverScheduleAccess
 	ps         interfaces.PermissionService
 	ums        interfaces.UserMgmtService
 }
@@ -65,6 +67,7 @@ func NewCatalogService(appSetting *common.AppSetting) interfaces.CatalogService
 			appSetting: appSetting,
 			cipher:     cipher,
 			ca:         catalogAccess.NewCatalogAccess(appSetting),
+			dsa:        discoverScheduleAccess.NewDiscoverScheduleAccess(appSetting),
 			ps:         permission.NewPermissionService(appSetting),
 			ums:        user_mgmt.NewUserMgmtService(appSetting),
 		}
@@ -496,6 +499,12 @@ func (cs *catalogService) DeleteByIDs(ctx context.Context, ids []string) error {
 			WithErrorDetails(err.Error())
 	}
 
+	// 清除定时发现配置
+	if err := cs.dsa.DeleteByCatalogIDs(ctx, ids); err != nil {
+		logger.Errorf("Failed to delete discover schedules of catalogs %v: %v", ids, err)
+		o11y.Error(ctx, fmt.Sprintf("Failed to delete discover schedules: %v", err))
+	}
+
 	//  清除资源策略
 	err = cs.ps.DeleteResources(ctx, interfaces.RESOURCE_TYPE_CATALOG, ids)
 	if err != nil {
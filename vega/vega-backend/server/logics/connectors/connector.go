@@ -62,24 +62,49 @@ type TableConnector interface {
 // Implementations: s3, hdfs, minio, feishu, notion, etc.
 type FileConnector interface {
 	Connector
+
+	// GetFileMeta 识别文件格式，并采样文件内容推断字段结构
+	GetFileMeta(ctx context.Context, file *interfaces.FileMeta) error
+	// ExecuteQuery 读取 file 或 fileset 资源的内容，在内存中完成过滤、排序与分页
+	ExecuteQuery(ctx context.Context, resource *interfaces.Resource,
+		params *interfaces.ResourceDataQueryParams) (*interfaces.QueryResult, error)
 }
 
 // FilesetConnector defines the interface for file/document storage connectors.
 // Implementations: s3, hdfs, minio, feishu, notion, etc.
 type FilesetConnector interface {
-	Connector
+	FileConnector
+
+	// ListFilesets 列出文件集（bucket 及各级目录）及其直接子文件
+	ListFilesets(ctx context.Context) ([]*interfaces.FilesetMeta, error)
 }
 
 // TopicConnector defines the interface for message queue connectors.
 // Implementations: kafka, pulsar, etc.
 type TopicConnector interface {
 	Connector
+
+	ListTopics(ctx context.Context) ([]*interfaces.TopicMeta, error)
+	// GetTopicMeta 获取 topic 的分区 offset、配置，并采样消息推断字段结构
+	GetTopicMeta(ctx context.Context, topic *interfaces.TopicMeta) error
+
+	// ExecuteQuery 在 topic 上执行有界读取（按 offset 或时间范围），过滤条件在读取后计算
+	ExecuteQuery(ctx context.Context, resource *interfaces.Resource,
+		params *interfaces.ResourceDataQueryParams) (*interfaces.QueryResult, error)
 }
 
 // MetricConnector defines the interface for time-series database connectors.
 // Implementations: prometheus, influxdb, etc.
 type MetricConnector interface {
 	Connector
+
+	ListMetrics(ctx context.Context) ([]*interfaces.MetricMeta, error)
+	// GetMetricMeta 获取指标的标签集合
+	GetMetricMeta(ctx context.Context, metric *interfaces.MetricMeta) error
+
+	// ExecuteRangeQuery 执行指标范围查询，过滤条件转换为标签匹配器
+	ExecuteRangeQuery(ctx context.Context, resource *interfaces.Resource,
+		params *interfaces.ResourceDataQueryParams) ([]*interfaces.MetricResult, error)
 }
 
 // IndexConnector defines the interface for search engine connectors.
@@ -89,6 +114,10 @@ type IndexConnector interface {
 
 	ListIndexes(ctx context.Context) ([]*interfaces.IndexMeta, error)
 	GetIndexMeta(ctx context.Context, index *interfaces.IndexMeta) error
+
+	// ExecuteQuery 执行单索引查询，过滤条件与投影下推到搜索引擎
+	ExecuteQuery(ctx context.Context, resource *interfaces.Resource,
+		params *interfaces.ResourceDataQueryParams) (*interfaces.QueryResult, error)
 }
 
 // APIConnector defines the interface for REST/GraphQL API connectors.
@@ -6,10 +6,14 @@
 package factory
 
 import (
+	"vega-backend/logics/connectors/local/fileset/s3"
 	"vega-backend/logics/connectors/local/index/opensearch"
+	"vega-backend/logics/connectors/local/metric/prometheus"
 	"vega-backend/logics/connectors/local/table/mariadb"
 	"vega-backend/logics/connectors/local/table/mysql"
 	"vega-backend/logics/connectors/local/table/oracle"
+	"vega-backend/logics/connectors/local/table/postgresql"
+	"vega-backend/logics/connectors/local/topic/kafka"
 )
 
 // InitLocalConnectors 初始化本地 connector
@@ -18,4 +22,8 @@ func (cf *ConnectorFactory) InitLocalConnectors() {
 	cf.connectors["opensearch"] = opensearch.NewOpenSearchConnector()
 	cf.connectors["oracle"] = oracle.NewOracleConnector()
 	cf.connectors["mariadb"] = mariadb.NewMariaDBConnector()
+	cf.connectors["postgresql"] = postgresql.NewPostgreSQLConnector()
+	cf.connectors["kafka"] = kafka.NewKafkaConnector()
+	cf.connectors["prometheus"] = prometheus.NewPrometheusConnector()
+	cf.connectors["s3"] = s3.NewS3Connector()
 }
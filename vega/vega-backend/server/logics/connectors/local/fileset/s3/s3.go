@@ -0,0 +1,246 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package s3 provides S3-compatible object storage connector implementation.
+package s3
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/mitchellh/mapstructure"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/connectors"
+)
+
+const (
+	// 默认每个文件采样的行数
+	defaultSampleSize = 100
+	// 默认最多发现的文件数
+	defaultMaxFiles = 10000
+)
+
+type s3Config struct {
+	Endpoint           string `mapstructure:"endpoint"`
+	AccessKeyID        string `mapstructure:"access_key_id"`
+	SecretAccessKey    string `mapstructure:"secret_access_key"`
+	Region             string `mapstructure:"region"`
+	UseSSL             bool   `mapstructure:"use_ssl"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+	Bucket             string `mapstructure:"bucket"`
+	Prefix             string `mapstructure:"prefix"`
+	FilePattern        string `mapstructure:"file_pattern"`
+	CSVDelimiter       string `mapstructure:"csv_delimiter"`
+	SampleSize         int    `mapstructure:"sample_size"`
+	MaxFiles           int    `mapstructure:"max_files"`
+}
+
+// S3Connector implements FilesetConnector for S3-compatible object storage.
+// 兼容 S3 协议的对象存储（如 AWS S3、MinIO、Ceph RGW）均可接入
+type S3Connector struct {
+	enabled bool
+	Config  *s3Config
+	client  *minio.Client
+}
+
+// NewS3Connector 创建 S3 connector 构建器
+func NewS3Connector() connectors.FilesetConnector {
+	return &S3Connector{}
+}
+
+// GetType returns the data source type.
+func (c *S3Connector) GetType() string {
+	return "s3"
+}
+
+// GetName returns the data source name.
+func (c *S3Connector) GetName() string {
+	return "s3"
+}
+
+// GetMode returns the connector mode.
+func (c *S3Connector) GetMode() string {
+	return interfaces.ConnectorModeLocal
+}
+
+// GetCategory returns the connector category.
+func (c *S3Connector) GetCategory() string {
+	return interfaces.ConnectorCategoryFileset
+}
+
+// GetEnabled returns the enabled status.
+func (c *S3Connector) GetEnabled() bool {
+	return c.enabled
+}
+
+// SetEnabled sets the enabled status.
+func (c *S3Connector) SetEnabled(enabled bool) {
+	c.enabled = enabled
+}
+
+// GetSensitiveFields returns the sensitive fields for S3 connector.
+func (c *S3Connector) GetSensitiveFields() []string {
+	return []string{"secret_access_key"}
+}
+
+// GetFieldConfig returns the field configuration for S3 connector.
+func (c *S3Connector) GetFieldConfig() map[string]interfaces.ConnectorFieldConfig {
+	return map[string]interfaces.ConnectorFieldConfig{
+		"endpoint":             {Name: "服务地址", Type: "string", Description: "S3 服务地址，如 s3.amazonaws.com、http://localhost:9000", Required: true, Encrypted: false},
+		"access_key_id":        {Name: "Access Key", Type: "string", Description: "访问密钥 ID", Required: false, Encrypted: false},
+		"secret_access_key":    {Name: "Secret Key", Type: "string", Description: "访问密钥", Required: false, Encrypted: true},
+		"region":               {Name: "区域", Type: "string", Description: "存储区域（可选，如 us-east-1）", Required: false, Encrypted: false},
+		"use_ssl":              {Name: "启用 SSL", Type: "boolean", Description: "服务地址未指定协议时是否使用 HTTPS", Required: false, Encrypted: false},
+		"insecure_skip_verify": {Name: "跳过证书校验", Type: "boolean", Description: "HTTPS 连接时是否跳过证书校验", Required: false, Encrypted: false},
+		"bucket":               {Name: "Bucket", Type: "string", Description: "Bucket 名称（可选，为空时发现全部 bucket）", Required: false, Encrypted: false},
+		"prefix":               {Name: "路径前缀", Type: "string", Description: "只发现该前缀下的文件（可选，如 reference/）", Required: false, Encrypted: false},
+		"file_pattern":         {Name: "文件模式", Type: "string", Description: "文件名匹配模式（可选，如 *.csv）", Required: false, Encrypted: false},
+		"csv_delimiter":        {Name: "CSV 分隔符", Type: "string", Description: "CSV 文件的列分隔符，默认逗号；.tsv 文件固定使用制表符", Required: false, Encrypted: false},
+		"sample_size":          {Name: "采样行数", Type: "integer", Description: "推断字段结构时每个文件采样的行数，默认 100", Required: false, Encrypted: false},
+		"max_files":            {Name: "最大文件数", Type: "integer", Description: "单次发现最多处理的文件数，默认 10000", Required: false, Encrypted: false},
+	}
+}
+
+// New creates a new S3 connector.
+func (c *S3Connector) New(cfg interfaces.ConnectorConfig) (connectors.Connector, error) {
+	var sCfg s3Config
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		Result:           &sCfg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 config decoder: %w", err)
+	}
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode s3 config: %w", err)
+	}
+
+	// 服务地址带协议时以协议为准
+	sCfg.Endpoint = strings.TrimRight(strings.TrimSpace(sCfg.Endpoint), "/")
+	if strings.Contains(sCfg.Endpoint, "://") {
+		u, err := url.Parse(sCfg.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return nil, fmt.Errorf("invalid s3 endpoint: %s", sCfg.Endpoint)
+		}
+		sCfg.Endpoint = u.Host
+		sCfg.UseSSL = u.Scheme == "https"
+	}
+	if sCfg.Endpoint == "" {
+		return nil, fmt.Errorf("s3 config endpoint is required")
+	}
+
+	sCfg.Prefix = strings.TrimLeft(sCfg.Prefix, "/")
+	if sCfg.Prefix != "" && sCfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 config bucket is required when prefix is set")
+	}
+	if sCfg.FilePattern != "" {
+		if _, err := path.Match(sCfg.FilePattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid s3 file pattern %s: %w", sCfg.FilePattern, err)
+		}
+	}
+	if sCfg.CSVDelimiter == "" {
+		sCfg.CSVDelimiter = ","
+	}
+	if r, size := utf8.DecodeRuneInString(sCfg.CSVDelimiter); size != len(sCfg.CSVDelimiter) ||
+		r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return nil, fmt.Errorf("invalid s3 csv delimiter: %q", sCfg.CSVDelimiter)
+	}
+	if sCfg.SampleSize <= 0 {
+		sCfg.SampleSize = defaultSampleSize
+	}
+	if sCfg.MaxFiles <= 0 {
+		sCfg.MaxFiles = defaultMaxFiles
+	}
+
+	return &S3Connector{
+		Config: &sCfg,
+	}, nil
+}
+
+// Connect creates the S3 client.
+func (c *S3Connector) Connect(ctx context.Context) error {
+	if c.client != nil {
+		return nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.Config.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+	}
+
+	// 未配置密钥时匿名访问（公共读 bucket）
+	client, err := minio.New(c.Config.Endpoint, &minio.Options{
+		Creds:     credentials.NewStaticV4(c.Config.AccessKeyID, c.Config.SecretAccessKey, ""),
+		Secure:    c.Config.UseSSL,
+		Region:    c.Config.Region,
+		Transport: transport,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create s3 client: %w", err)
+	}
+	c.client = client
+	return nil
+}
+
+// Close closes the connection.
+func (c *S3Connector) Close(ctx context.Context) error {
+	c.client = nil
+	return nil
+}
+
+// Ping checks the connection.
+// 配置了 bucket 时检查 bucket 是否存在，否则列出 bucket
+func (c *S3Connector) Ping(ctx context.Context) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	if c.Config.Bucket != "" {
+		exists, err := c.client.BucketExists(ctx, c.Config.Bucket)
+		if err != nil {
+			return fmt.Errorf("ping failed: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("bucket %s does not exist", c.Config.Bucket)
+		}
+		return nil
+	}
+
+	if _, err := c.client.ListBuckets(ctx); err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	return nil
+}
+
+// TestConnection tests the connection to S3.
+func (c *S3Connector) TestConnection(ctx context.Context) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	return c.Ping(ctx)
+}
+
+// GetMetadata returns the endpoint information.
+func (c *S3Connector) GetMetadata(ctx context.Context) (map[string]any, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("connector not connected")
+	}
+
+	return map[string]any{
+		"endpoint": c.client.EndpointURL().String(),
+		"bucket":   c.Config.Bucket,
+		"prefix":   c.Config.Prefix,
+	}, nil
+}
@@ -0,0 +1,225 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/minio/minio-go/v7"
+
+	"vega-backend/interfaces"
+)
+
+// ListFilesets lists buckets and directory prefixes with their direct files.
+// ListFilesets 列出 bucket 及其下各级目录作为文件集，文件按所在目录归入对应文件集
+// 配置了 prefix 时只发现该前缀下的文件，文件总数超过 max_files 时截断
+func (c *S3Connector) ListFilesets(ctx context.Context) ([]*interfaces.FilesetMeta, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	buckets, err := c.listBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var filesets []*interfaces.FilesetMeta
+	fileCount := 0
+	for _, bucket := range buckets {
+		bucketFilesets, n, err := c.listBucketFilesets(ctx, bucket, c.Config.MaxFiles-fileCount)
+		if err != nil {
+			return nil, err
+		}
+		filesets = append(filesets, bucketFilesets...)
+		fileCount += n
+		if fileCount >= c.Config.MaxFiles {
+			logger.Warnf("S3 discover reached max files %d, stop listing objects", c.Config.MaxFiles)
+			break
+		}
+	}
+	return filesets, nil
+}
+
+// listBuckets 返回配置的 bucket，未配置时列出全部 bucket
+func (c *S3Connector) listBuckets(ctx context.Context) ([]string, error) {
+	if c.Config.Bucket != "" {
+		return []string{c.Config.Bucket}, nil
+	}
+
+	infos, err := c.client.ListBuckets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list buckets: %w", err)
+	}
+	buckets := make([]string, 0, len(infos))
+	for _, info := range infos {
+		buckets = append(buckets, info.Name)
+	}
+	sort.Strings(buckets)
+	return buckets, nil
+}
+
+// listBucketFilesets 递归列出 bucket 内的对象，构建目录文件集，最多收集 limit 个文件
+func (c *S3Connector) listBucketFilesets(ctx context.Context, bucket string,
+	limit int) ([]*interfaces.FilesetMeta, int, error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 文件集从 prefix 所在目录开始，不包含其上级目录
+	root := c.Config.Prefix[:strings.LastIndex(c.Config.Prefix, "/")+1]
+	filesets := map[string]*interfaces.FilesetMeta{}
+	ensure := func(prefix string) *interfaces.FilesetMeta {
+		for dir := prefix; ; dir = parentDir(dir) {
+			if _, ok := filesets[dir]; !ok {
+				filesets[dir] = &interfaces.FilesetMeta{
+					Path:   bucket + "/" + dir,
+					Bucket: bucket,
+					Prefix: dir,
+				}
+			}
+			if dir == root || dir == "" {
+				break
+			}
+		}
+		return filesets[prefix]
+	}
+	ensure(root)
+
+	fileCount := 0
+	for obj := range c.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{
+		Prefix:    c.Config.Prefix,
+		Recursive: true,
+	}) {
+		if obj.Err != nil {
+			return nil, 0, fmt.Errorf("failed to list objects of bucket %s: %w", bucket, obj.Err)
+		}
+		// 目录占位对象只生成文件集
+		if strings.HasSuffix(obj.Key, "/") {
+			ensure(obj.Key)
+			continue
+		}
+		if !c.matchFile(obj.Key) {
+			continue
+		}
+		if fileCount >= limit {
+			break
+		}
+
+		fileset := ensure(parentDir(obj.Key))
+		fileset.Files = append(fileset.Files, newFileMeta(bucket, obj))
+		fileset.FileCount++
+		fileset.TotalSize += obj.Size
+		fileCount++
+	}
+
+	result := make([]*interfaces.FilesetMeta, 0, len(filesets))
+	for _, fileset := range filesets {
+		result = append(result, fileset)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result, fileCount, nil
+}
+
+// matchFile 按 file_pattern 匹配文件名
+func (c *S3Connector) matchFile(key string) bool {
+	if c.Config.FilePattern == "" {
+		return true
+	}
+	matched, _ := path.Match(c.Config.FilePattern, path.Base(key))
+	return matched
+}
+
+// parentDir 返回对象所在目录前缀（以 / 结尾），bucket 根目录为空
+func parentDir(key string) string {
+	key = strings.TrimSuffix(key, "/")
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		return key[:i+1]
+	}
+	return ""
+}
+
+func newFileMeta(bucket string, obj minio.ObjectInfo) *interfaces.FileMeta {
+	fileFormat, _ := detectFormat(obj.Key)
+	return &interfaces.FileMeta{
+		Path:         bucket + "/" + obj.Key,
+		Name:         path.Base(obj.Key),
+		Size:         obj.Size,
+		LastModified: obj.LastModified.UnixMilli(),
+		ContentType:  obj.ContentType,
+		Bucket:       bucket,
+		Key:          obj.Key,
+		ETag:         strings.Trim(obj.ETag, `"`),
+		Format:       fileFormat,
+	}
+}
+
+// GetFileMeta retrieves the schema of a file.
+// GetFileMeta 识别文件格式并推断字段结构
+// 参数:
+//   - ctx: 上下文信息，用于控制请求的超时和取消
+//   - file: 文件元数据，Format、Fields 与 Properties 将被填充
+//
+// 返回值:
+//   - error: 如果操作过程中发生错误，则返回错误信息；不支持读取内容的文件不返回错误
+func (c *S3Connector) GetFileMeta(ctx context.Context, file *interfaces.FileMeta) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	file.Format, _ = detectFormat(file.Key)
+	if file.Format == "" {
+		return nil
+	}
+	if file.Properties == nil {
+		file.Properties = make(map[string]any)
+	}
+
+	reader, err := c.openRowReader(ctx, file.Bucket, file.Key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// parquet 字段结构取自文件 schema，无需采样
+	if pr, ok := reader.(*parquetReader); ok {
+		file.Fields = parquetFields(pr.file.Schema())
+		file.Properties["num_rows"] = pr.file.NumRows()
+		file.Properties["row_groups"] = len(pr.file.RowGroups())
+		return nil
+	}
+
+	var inferrer *schemaInferrer
+	if cr, ok := reader.(*csvReader); ok {
+		inferrer = newSchemaInferrer(true, cr.header)
+		file.Properties["delimiter"] = string(cr.reader.Comma)
+	} else {
+		inferrer = newSchemaInferrer(false, nil)
+	}
+
+	sampled := 0
+	for sampled < c.Config.SampleSize {
+		row, err := reader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("failed to sample file %s: %w", file.Path, err)
+		}
+		inferrer.add(row)
+		sampled++
+	}
+
+	file.Fields = inferrer.result()
+	file.Properties["sampled_rows"] = sampled
+	return nil
+}
@@ -0,0 +1,424 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package s3
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// 支持读取内容的文件格式
+const (
+	FileFormatCSV     = "csv"
+	FileFormatJSONL   = "jsonl"
+	FileFormatParquet = "parquet"
+)
+
+const gzipSuffix = ".gz"
+
+// detectFormat 根据文件扩展名识别格式，csv/jsonl 支持 gzip 压缩（.gz 后缀）
+// 返回格式与 CSV 分隔符覆盖值（.tsv 固定为制表符）
+func detectFormat(key string) (string, rune) {
+	name := strings.ToLower(path.Base(key))
+	compressed := strings.HasSuffix(name, gzipSuffix)
+	name = strings.TrimSuffix(name, gzipSuffix)
+
+	switch path.Ext(name) {
+	case ".csv":
+		return FileFormatCSV, 0
+	case ".tsv":
+		return FileFormatCSV, '\t'
+	case ".jsonl", ".ndjson", ".json":
+		return FileFormatJSONL, 0
+	case ".parquet":
+		if compressed {
+			return "", 0
+		}
+		return FileFormatParquet, 0
+	}
+	return "", 0
+}
+
+// rowReader 逐行读取文件内容
+type rowReader interface {
+	// Next 返回下一行，读完时返回 io.EOF
+	Next() (map[string]any, error)
+	// Close 释放底层对象
+	Close() error
+}
+
+// openRowReader 打开对象并按格式创建行读取器
+// csv 与 jsonl 顺序读取对象内容；parquet 按需读取 footer 与列块
+func (c *S3Connector) openRowReader(ctx context.Context, bucket, key string) (rowReader, error) {
+	fileFormat, delimiter := detectFormat(key)
+	if fileFormat == "" {
+		return nil, fmt.Errorf("unsupported file format: %s", key)
+	}
+
+	obj, err := c.client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s/%s: %w", bucket, key, err)
+	}
+
+	var reader rowReader
+	switch fileFormat {
+	case FileFormatParquet:
+		info, err := obj.Stat()
+		if err != nil {
+			_ = obj.Close()
+			return nil, fmt.Errorf("failed to stat object %s/%s: %w", bucket, key, err)
+		}
+		reader, err = newParquetReader(obj, info.Size)
+		if err != nil {
+			_ = obj.Close()
+			return nil, err
+		}
+	default:
+		var input io.Reader = obj
+		if strings.HasSuffix(strings.ToLower(key), gzipSuffix) {
+			gz, err := gzip.NewReader(obj)
+			if err != nil {
+				_ = obj.Close()
+				return nil, fmt.Errorf("failed to open gzip object %s/%s: %w", bucket, key, err)
+			}
+			input = gz
+		}
+		if fileFormat == FileFormatCSV {
+			if delimiter == 0 {
+				delimiter = []rune(c.Config.CSVDelimiter)[0]
+			}
+			reader, err = newCSVReader(input, delimiter, obj)
+		} else {
+			reader, err = newJSONLReader(input, obj)
+		}
+		if err != nil {
+			_ = obj.Close()
+			return nil, err
+		}
+	}
+	return reader, nil
+}
+
+// csvReader 读取带表头的 CSV 文件，值均为字符串，由字段类型在读取后转换
+type csvReader struct {
+	reader *csv.Reader
+	header []string
+	closer io.Closer
+}
+
+func newCSVReader(input io.Reader, delimiter rune, closer io.Closer) (*csvReader, error) {
+	reader := csv.NewReader(bufio.NewReader(input))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return &csvReader{reader: reader, closer: closer}, nil
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+	return &csvReader{
+		reader: reader,
+		header: normalizeHeader(header),
+		closer: closer,
+	}, nil
+}
+
+// normalizeHeader 去除 BOM 与空白，空列名与重复列名按位置补全
+func normalizeHeader(header []string) []string {
+	names := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, string(utf8BOM))
+		}
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+func (r *csvReader) Next() (map[string]any, error) {
+	if r.header == nil {
+		return nil, io.EOF
+	}
+	record, err := r.reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read csv record: %w", err)
+	}
+
+	// 缺少的列为 null，多余的列忽略
+	row := make(map[string]any, len(r.header))
+	for i, name := range r.header {
+		if i < len(record) {
+			row[name] = record[i]
+		} else {
+			row[name] = nil
+		}
+	}
+	return row, nil
+}
+
+func (r *csvReader) Close() error {
+	return r.closer.Close()
+}
+
+// jsonlReader 读取 JSON Lines 文件，同时兼容顶层为对象数组的 JSON 文件
+type jsonlReader struct {
+	decoder *json.Decoder
+	inArray bool
+	closer  io.Closer
+}
+
+func newJSONLReader(input io.Reader, closer io.Closer) (*jsonlReader, error) {
+	buffered := bufio.NewReader(input)
+	decoder := json.NewDecoder(buffered)
+	decoder.UseNumber()
+
+	r := &jsonlReader{decoder: decoder, closer: closer}
+	first, err := peekNonSpace(buffered)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read json content: %w", err)
+	}
+	if first == '[' {
+		if _, err := decoder.Token(); err != nil {
+			return nil, fmt.Errorf("failed to read json array: %w", err)
+		}
+		r.inArray = true
+	}
+	return r, nil
+}
+
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// peekNonSpace 跳过前导空白与 BOM，返回第一个有效字符但不消费它
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = r.Discard(1)
+		case utf8BOM[0]:
+			if bom, err := r.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+				_, _ = r.Discard(len(utf8BOM))
+				continue
+			}
+			return b[0], nil
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+func (r *jsonlReader) Next() (map[string]any, error) {
+	if r.inArray && !r.decoder.More() {
+		return nil, io.EOF
+	}
+
+	var value any
+	if err := r.decoder.Decode(&value); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to decode json record: %w", err)
+	}
+	obj, ok := normalizeNumber(value).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("json record should be an object, got %T", value)
+	}
+	return obj, nil
+}
+
+func (r *jsonlReader) Close() error {
+	return r.closer.Close()
+}
+
+// normalizeNumber 将 json.Number 转换为 int64 或 float64
+func normalizeNumber(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case map[string]any:
+		for k, item := range val {
+			val[k] = normalizeNumber(item)
+		}
+		return val
+	case []any:
+		for i, item := range val {
+			val[i] = normalizeNumber(item)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// parquetReader 读取 Parquet 文件，按逻辑类型转换字段值
+type parquetReader struct {
+	file     *parquet.File
+	reader   *parquet.Reader
+	converts map[string]func(any) any
+	closer   io.Closer
+}
+
+func newParquetReader(obj *minio.Object, size int64) (*parquetReader, error) {
+	file, err := parquet.OpenFile(obj, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	converts := map[string]func(any) any{}
+	for _, field := range file.Schema().Fields() {
+		if !field.Leaf() {
+			continue
+		}
+		if convert := parquetValueConverter(field.Type().LogicalType()); convert != nil {
+			converts[field.Name()] = convert
+		}
+	}
+
+	return &parquetReader{
+		file:     file,
+		reader:   parquet.NewReader(file),
+		converts: converts,
+		closer:   obj,
+	}, nil
+}
+
+// parquetValueConverter 返回逻辑类型的值转换函数：时间戳与日期转换为时间，时刻转换为 hh:mm:ss 文本，
+// decimal 按精度转换为浮点数，uuid 转换为标准文本格式
+func parquetValueConverter(logical *format.LogicalType) func(any) any {
+	if logical == nil {
+		return nil
+	}
+	switch {
+	case logical.Timestamp != nil:
+		unit := logical.Timestamp.Unit
+		return func(v any) any {
+			n, ok := v.(int64)
+			if !ok {
+				return v
+			}
+			switch {
+			case unit.Nanos != nil:
+				return time.Unix(0, n)
+			case unit.Micros != nil:
+				return time.UnixMicro(n)
+			default:
+				return time.UnixMilli(n)
+			}
+		}
+	case logical.Date != nil:
+		return func(v any) any {
+			if n, ok := v.(int32); ok {
+				return time.Unix(int64(n)*86400, 0).UTC()
+			}
+			return v
+		}
+	case logical.Time != nil:
+		unit := logical.Time.Unit
+		return func(v any) any {
+			var d time.Duration
+			switch n := v.(type) {
+			case int32:
+				d = time.Duration(n) * time.Millisecond
+			case int64:
+				if unit.Nanos != nil {
+					d = time.Duration(n)
+				} else {
+					d = time.Duration(n) * time.Microsecond
+				}
+			default:
+				return v
+			}
+			return time.Unix(0, 0).UTC().Add(d).Format("15:04:05.999999999")
+		}
+	case logical.Decimal != nil:
+		scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(logical.Decimal.Scale)), nil))
+		return func(v any) any {
+			var unscaled *big.Int
+			switch n := v.(type) {
+			case int32:
+				unscaled = big.NewInt(int64(n))
+			case int64:
+				unscaled = big.NewInt(n)
+			case []byte:
+				// 大端补码
+				unscaled = new(big.Int).SetBytes(n)
+				if len(n) > 0 && n[0]&0x80 != 0 {
+					unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(n)*8)))
+				}
+			default:
+				return v
+			}
+			f, _ := new(big.Float).Quo(new(big.Float).SetInt(unscaled), scale).Float64()
+			return f
+		}
+	case logical.UUID != nil:
+		return func(v any) any {
+			b, ok := v.([]byte)
+			if !ok || len(b) != 16 {
+				return v
+			}
+			return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+		}
+	}
+	return nil
+}
+
+func (r *parquetReader) Next() (map[string]any, error) {
+	row := map[string]any{}
+	if err := r.reader.Read(&row); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read parquet row: %w", err)
+	}
+	for name, convert := range r.converts {
+		if v, ok := row[name]; ok && v != nil {
+			row[name] = convert(v)
+		}
+	}
+	return row, nil
+}
+
+func (r *parquetReader) Close() error {
+	_ = r.reader.Close()
+	return r.closer.Close()
+}
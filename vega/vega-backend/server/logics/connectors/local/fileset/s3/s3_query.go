@@ -0,0 +1,187 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/minio/minio-go/v7"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/filter_condition"
+)
+
+// ExecuteQuery 读取 file 资源或 fileset 资源下的直接子文件，
+// 读取后在内存中完成过滤、排序、分页与投影；单次最多扫描 MAX_FILE_SCAN_ROWS 行，
+// 超过时返回 ErrFileScanLimitExceeded，不返回不完整的结果
+func (c *S3Connector) ExecuteQuery(ctx context.Context, resource *interfaces.Resource,
+	params *interfaces.ResourceDataQueryParams) (*interfaces.QueryResult, error) {
+
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	if params.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, params.Timeout)
+		defer cancel()
+	}
+
+	bucket, key, ok := strings.Cut(resource.SourceIdentifier, "/")
+	if !ok || bucket == "" {
+		return nil, fmt.Errorf("invalid source identifier: %s", resource.SourceIdentifier)
+	}
+
+	var keys []string
+	switch resource.Category {
+	case interfaces.ResourceCategoryFile:
+		keys = []string{key}
+	case interfaces.ResourceCategoryFileset:
+		var err error
+		keys, err = c.listDataFiles(ctx, bucket, key)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported resource category: %s", resource.Category)
+	}
+	logger.Debugf("file query: %s, files: %d", resource.SourceIdentifier, len(keys))
+
+	fieldTypes := make(map[string]string, len(resource.SchemaDefinition))
+	for _, prop := range resource.SchemaDefinition {
+		fieldTypes[prop.OriginalName] = prop.Type
+	}
+
+	var rows []map[string]any
+	scanned := 0
+	for _, key := range keys {
+		// 多读一行，用于判断是否还有未扫描的行
+		n, err := c.scanFile(ctx, bucket, key, interfaces.MAX_FILE_SCAN_ROWS+1-scanned, func(row map[string]any) error {
+			for name, v := range row {
+				if vegaType, ok := fieldTypes[name]; ok {
+					row[name] = coerceValue(v, vegaType)
+				}
+			}
+			if params.ActualFilterCond != nil {
+				matched, err := filter_condition.MatchRow(params.ActualFilterCond, row)
+				if err != nil || !matched {
+					return err
+				}
+			}
+			rows = append(rows, row)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		scanned += n
+		if scanned > interfaces.MAX_FILE_SCAN_ROWS {
+			return nil, fmt.Errorf("%w: %s has more than %d rows",
+				interfaces.ErrFileScanLimitExceeded, resource.SourceIdentifier, interfaces.MAX_FILE_SCAN_ROWS)
+		}
+	}
+
+	sortRows(rows, params.Sort)
+
+	result := &interfaces.QueryResult{
+		Columns: params.OutputFields,
+		Total:   int64(len(rows)),
+	}
+
+	limit := params.Limit
+	if limit <= 0 || limit > interfaces.MAX_SEARCH_SIZE {
+		limit = interfaces.MAX_SEARCH_SIZE
+	}
+	start := min(params.Offset, len(rows))
+	end := min(start+limit, len(rows))
+	rows = rows[start:end]
+
+	if len(params.OutputFields) > 0 {
+		for i, row := range rows {
+			projected := make(map[string]any, len(params.OutputFields))
+			for _, field := range params.OutputFields {
+				if v, ok := row[field]; ok {
+					projected[field] = v
+				}
+			}
+			rows[i] = projected
+		}
+	}
+	result.Rows = rows
+
+	return result, nil
+}
+
+// listDataFiles 列出目录下可读取内容的直接子文件，按 key 排序
+func (c *S3Connector) listDataFiles(ctx context.Context, bucket, prefix string) ([]string, error) {
+	var keys []string
+	for obj := range c.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list objects of %s/%s: %w", bucket, prefix, obj.Err)
+		}
+		if strings.HasSuffix(obj.Key, "/") || !c.matchFile(obj.Key) {
+			continue
+		}
+		if fileFormat, _ := detectFormat(obj.Key); fileFormat == "" {
+			continue
+		}
+		keys = append(keys, obj.Key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// scanFile 顺序读取文件，最多读取 limit 行，返回读取的行数
+func (c *S3Connector) scanFile(ctx context.Context, bucket, key string, limit int,
+	handle func(row map[string]any) error) (int, error) {
+
+	reader, err := c.openRowReader(ctx, bucket, key)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	n := 0
+	for n < limit {
+		row, err := reader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return n, fmt.Errorf("failed to read file %s/%s: %w", bucket, key, err)
+		}
+		n++
+		if err := handle(row); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// sortRows 按排序字段在内存中排序，未指定时保持文件中的顺序
+func sortRows(rows []map[string]any, sortFields []*interfaces.SortField) {
+	if len(sortFields) == 0 {
+		return
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, s := range sortFields {
+			cmp := filter_condition.CompareValues(rows[i][s.Field], rows[j][s.Field])
+			if cmp == 0 {
+				continue
+			}
+			if s.Direction == interfaces.DESC_DIRECTION {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
@@ -0,0 +1,311 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package s3
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"vega-backend/interfaces"
+)
+
+// 字段原始类型：csv 均为 text，jsonl 为 JSON 类型
+const (
+	origTypeText    = "text"
+	jsonTypeString  = "string"
+	jsonTypeNumber  = "number"
+	jsonTypeBoolean = "boolean"
+	jsonTypeObject  = "object"
+	jsonTypeArray   = "array"
+	jsonTypeMixed   = "mixed"
+)
+
+// 文本中可识别的时间格式
+var (
+	datetimeLayouts = []string{time.RFC3339Nano, time.DateTime, "2006-01-02T15:04:05", "2006/01/02 15:04:05"}
+	dateLayouts     = []string{time.DateOnly, "2006/01/02"}
+)
+
+// fieldType 推断中的字段类型
+type fieldType struct {
+	vegaType string
+	origType string
+}
+
+// schemaInferrer 根据采样行推断文件的字段结构
+// textual 为 true 时（csv）值均为字符串，需从文本中识别数值、布尔与时间
+type schemaInferrer struct {
+	textual bool
+	fields  map[string]*fieldType
+	order   []string // 字段顺序，csv 按表头顺序，其余按名称排序
+}
+
+func newSchemaInferrer(textual bool, header []string) *schemaInferrer {
+	s := &schemaInferrer{
+		textual: textual,
+		fields:  make(map[string]*fieldType, len(header)),
+	}
+	for _, name := range header {
+		s.fields[name] = &fieldType{}
+		s.order = append(s.order, name)
+	}
+	return s
+}
+
+// add 合并一行数据的字段类型
+func (s *schemaInferrer) add(row map[string]any) {
+	for name, value := range row {
+		ft, exists := s.fields[name]
+		if !exists {
+			ft = &fieldType{}
+			s.fields[name] = ft
+			s.order = append(s.order, name)
+		}
+
+		var vegaType, origType string
+		if s.textual {
+			vegaType, origType = inferTextType(value), origTypeText
+		} else {
+			vegaType, origType = inferValueType(value)
+		}
+		// null 值不参与推断，但记录字段存在
+		if vegaType == "" {
+			continue
+		}
+
+		ft.vegaType = mergeType(ft.vegaType, vegaType)
+		if ft.origType == "" {
+			ft.origType = origType
+		} else if ft.origType != origType {
+			ft.origType = jsonTypeMixed
+		}
+	}
+}
+
+// result 返回推断出的字段，采样中只出现 null 的字段按字符串处理
+func (s *schemaInferrer) result() []interfaces.FieldMeta {
+	order := s.order
+	if !s.textual {
+		order = append([]string(nil), s.order...)
+		sort.Strings(order)
+	}
+
+	fields := make([]interfaces.FieldMeta, 0, len(order))
+	for _, name := range order {
+		ft := s.fields[name]
+		vegaType, origType := ft.vegaType, ft.origType
+		if vegaType == "" {
+			vegaType = interfaces.DataType_String
+		}
+		if s.textual {
+			origType = origTypeText
+		}
+		fields = append(fields, interfaces.FieldMeta{
+			Name:       name,
+			Type:       vegaType,
+			OrigType:   origType,
+			Searchable: vegaType != interfaces.DataType_Json,
+		})
+	}
+	return fields
+}
+
+// inferTextType 从 csv 文本中识别类型，空字符串视为 null
+func inferTextType(v any) string {
+	s, ok := v.(string)
+	if !ok || strings.TrimSpace(s) == "" {
+		return ""
+	}
+	s = strings.TrimSpace(s)
+
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return interfaces.DataType_Integer
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return interfaces.DataType_Float
+	}
+	if _, err := strconv.ParseBool(s); err == nil && len(s) > 1 {
+		return interfaces.DataType_Boolean
+	}
+	if _, ok := parseTime(s, dateLayouts); ok {
+		return interfaces.DataType_Date
+	}
+	if _, ok := parseTime(s, datetimeLayouts); ok {
+		return interfaces.DataType_Datetime
+	}
+	return interfaces.DataType_String
+}
+
+// inferValueType 推断单个 JSON 值的 VEGA 类型与 JSON 原始类型，null 返回空
+func inferValueType(v any) (string, string) {
+	switch val := v.(type) {
+	case nil:
+		return "", ""
+	case bool:
+		return interfaces.DataType_Boolean, jsonTypeBoolean
+	case int64:
+		return interfaces.DataType_Integer, jsonTypeNumber
+	case float64:
+		return interfaces.DataType_Float, jsonTypeNumber
+	case string:
+		if _, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			return interfaces.DataType_Datetime, jsonTypeString
+		}
+		return interfaces.DataType_String, jsonTypeString
+	case map[string]any:
+		return interfaces.DataType_Json, jsonTypeObject
+	case []any:
+		return interfaces.DataType_Json, jsonTypeArray
+	default:
+		return interfaces.DataType_String, jsonTypeString
+	}
+}
+
+// mergeType 合并同一字段在不同行中的类型：整数与浮点合并为浮点，日期与时间合并为时间，其余冲突退化为字符串
+func mergeType(a, b string) string {
+	switch {
+	case a == b:
+		return a
+	case a == "":
+		return b
+	case b == "":
+		return a
+	case (a == interfaces.DataType_Integer && b == interfaces.DataType_Float) ||
+		(a == interfaces.DataType_Float && b == interfaces.DataType_Integer):
+		return interfaces.DataType_Float
+	case (a == interfaces.DataType_Date && b == interfaces.DataType_Datetime) ||
+		(a == interfaces.DataType_Datetime && b == interfaces.DataType_Date):
+		return interfaces.DataType_Datetime
+	default:
+		return interfaces.DataType_String
+	}
+}
+
+// parquetFields 从 Parquet 文件的 schema 读取字段结构
+func parquetFields(schema *parquet.Schema) []interfaces.FieldMeta {
+	fields := make([]interfaces.FieldMeta, 0, len(schema.Fields()))
+	for _, field := range schema.Fields() {
+		vegaType, origType := parquetFieldType(field)
+		fields = append(fields, interfaces.FieldMeta{
+			Name:       field.Name(),
+			Type:       vegaType,
+			OrigType:   origType,
+			Searchable: vegaType != interfaces.DataType_Json && vegaType != interfaces.DataType_Binary,
+		})
+	}
+	return fields
+}
+
+// parquetFieldType 将 Parquet 物理类型与逻辑类型映射为 VEGA 类型，嵌套结构（LIST/MAP/group）为 json
+func parquetFieldType(field parquet.Field) (string, string) {
+	if !field.Leaf() {
+		if logical := field.Type().LogicalType(); logical != nil {
+			return interfaces.DataType_Json, logical.String()
+		}
+		return interfaces.DataType_Json, "GROUP"
+	}
+
+	typ := field.Type()
+	origType := typ.String()
+	if logical := typ.LogicalType(); logical != nil {
+		switch {
+		case logical.UTF8 != nil, logical.Enum != nil, logical.UUID != nil:
+			return interfaces.DataType_String, origType
+		case logical.Json != nil, logical.Bson != nil:
+			return interfaces.DataType_Json, origType
+		case logical.Decimal != nil:
+			return interfaces.DataType_Decimal, origType
+		case logical.Timestamp != nil:
+			return interfaces.DataType_Datetime, origType
+		case logical.Date != nil:
+			return interfaces.DataType_Date, origType
+		case logical.Time != nil:
+			return interfaces.DataType_Time, origType
+		case logical.Integer != nil:
+			if !logical.Integer.IsSigned {
+				return interfaces.DataType_UnsignedInteger, origType
+			}
+			return interfaces.DataType_Integer, origType
+		}
+	}
+
+	switch typ.Kind() {
+	case parquet.Boolean:
+		return interfaces.DataType_Boolean, origType
+	case parquet.Int32, parquet.Int64:
+		return interfaces.DataType_Integer, origType
+	case parquet.Float, parquet.Double:
+		return interfaces.DataType_Float, origType
+	case parquet.Int96:
+		return interfaces.DataType_String, origType
+	default:
+		return interfaces.DataType_Binary, origType
+	}
+}
+
+// coerceValue 按字段类型转换读取到的值：文本转换为数值、布尔与时间，无法转换时保留原值
+// 非字符串类型的空文本视为 null
+func coerceValue(v any, vegaType string) any {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+
+	switch vegaType {
+	case interfaces.DataType_Integer, interfaces.DataType_UnsignedInteger:
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil
+		}
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case interfaces.DataType_Float, interfaces.DataType_Decimal:
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case interfaces.DataType_Boolean:
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil
+		}
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case interfaces.DataType_Date, interfaces.DataType_Datetime:
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil
+		}
+		if t, ok := parseTime(s, datetimeLayouts); ok {
+			return t
+		}
+		if t, ok := parseTime(s, dateLayouts); ok {
+			return t
+		}
+	}
+	return v
+}
+
+func parseTime(s string, layouts []string) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,350 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/connectors"
+	"vega-backend/logics/filter_condition"
+)
+
+var stubModTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type stubContents struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+}
+
+type stubPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type stubListResult struct {
+	XMLName        xml.Name       `xml:"ListBucketResult"`
+	Name           string         `xml:"Name"`
+	Prefix         string         `xml:"Prefix"`
+	KeyCount       int            `xml:"KeyCount"`
+	MaxKeys        int            `xml:"MaxKeys"`
+	IsTruncated    bool           `xml:"IsTruncated"`
+	Contents       []stubContents `xml:"Contents"`
+	CommonPrefixes []stubPrefix   `xml:"CommonPrefixes"`
+}
+
+// newStubServer 模拟 S3 协议（路径风格）：ListObjectsV2、HEAD/GET 对象（支持 Range）
+func newStubServer(t *testing.T, bucket string, objects map[string][]byte) *httptest.Server {
+	t.Helper()
+
+	keys := make([]string, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if name != bucket {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if key == "" {
+			query := r.URL.Query()
+			prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+			result := stubListResult{Name: bucket, Prefix: prefix, MaxKeys: 1000}
+			seen := map[string]bool{}
+			for _, k := range keys {
+				if !strings.HasPrefix(k, prefix) {
+					continue
+				}
+				if delimiter != "" {
+					if i := strings.Index(k[len(prefix):], delimiter); i >= 0 {
+						p := k[:len(prefix)+i+1]
+						if !seen[p] {
+							seen[p] = true
+							result.CommonPrefixes = append(result.CommonPrefixes, stubPrefix{Prefix: p})
+						}
+						continue
+					}
+				}
+				result.Contents = append(result.Contents, stubContents{
+					Key:          k,
+					LastModified: stubModTime.Format(time.RFC3339),
+					ETag:         `"` + k + `"`,
+					Size:         int64(len(objects[k])),
+				})
+			}
+			result.KeyCount = len(result.Contents) + len(result.CommonPrefixes)
+			w.Header().Set("Content-Type", "application/xml")
+			_ = xml.NewEncoder(w).Encode(result)
+			return
+		}
+
+		data, ok := objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"`+key+`"`)
+		http.ServeContent(w, r, key, stubModTime, bytes.NewReader(data))
+	}))
+}
+
+func newTestConnector(t *testing.T, endpoint string, cfg interfaces.ConnectorConfig) *S3Connector {
+	t.Helper()
+
+	cfg["endpoint"] = endpoint
+	cfg["access_key_id"] = "test"
+	cfg["secret_access_key"] = "test-secret"
+	cfg["region"] = "us-east-1"
+	conn, err := NewS3Connector().New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	c := conn.(*S3Connector)
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	return c
+}
+
+func newParquetFile(t *testing.T) []byte {
+	t.Helper()
+
+	type sensor struct {
+		ID      int64     `parquet:"id"`
+		Name    string    `parquet:"name"`
+		Reading float64   `parquet:"reading,optional"`
+		TS      time.Time `parquet:"ts,timestamp(millisecond)"`
+	}
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[sensor](&buf)
+	if _, err := w.Write([]sensor{
+		{ID: 1, Name: "a", Reading: 1.5, TS: time.UnixMilli(1700000000000)},
+		{ID: 2, Name: "b", Reading: 7.25, TS: time.UnixMilli(1700000060000)},
+	}); err != nil {
+		t.Fatalf("write parquet failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close parquet writer failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func testObjects(t *testing.T) map[string][]byte {
+	return map[string][]byte{
+		"ref/countries.csv": []byte("\xef\xbb\xbfcode,population,area,member,founded\n" +
+			"CN,1410000000,9596961.5,true,1949-10-01\n" +
+			"FR,68000000,551695,true,1958-10-04\n" +
+			"IS,380000,,false,1944-06-17\n"),
+		"ref/readme.txt":           []byte("not a data file"),
+		"ref/events/a.jsonl":       []byte(`{"id":1,"type":"click","at":"2025-01-01T00:00:00Z"}` + "\n" + `{"id":2,"type":"view","extra":{"k":"v"}}` + "\n"),
+		"ref/events/b.json":        []byte(`[{"id":3,"type":"click","score":0.5}]`),
+		"ref/sensors/data.parquet": newParquetFile(t),
+		"other/skip.csv":           []byte("a\n1\n"),
+	}
+}
+
+func TestListFilesets(t *testing.T) {
+	server := newStubServer(t, "data", testObjects(t))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := newTestConnector(t, server.URL, interfaces.ConnectorConfig{"bucket": "data", "prefix": "ref/"})
+	var _ connectors.FilesetConnector = c
+
+	filesets, err := c.ListFilesets(ctx)
+	if err != nil {
+		t.Fatalf("ListFilesets failed: %v", err)
+	}
+	paths := make([]string, 0, len(filesets))
+	for _, fs := range filesets {
+		paths = append(paths, fs.Path)
+	}
+	if strings.Join(paths, ",") != "data/ref/,data/ref/events/,data/ref/sensors/" {
+		t.Fatalf("unexpected filesets: %v", paths)
+	}
+	root := filesets[0]
+	if root.FileCount != 2 || root.Files[0].Path != "data/ref/countries.csv" || root.Files[0].Format != FileFormatCSV ||
+		root.Files[1].Format != "" {
+		t.Errorf("unexpected root fileset: %+v", root)
+	}
+
+	csvFile := root.Files[0]
+	if err := c.GetFileMeta(ctx, csvFile); err != nil {
+		t.Fatalf("GetFileMeta failed: %v", err)
+	}
+	want := map[string]string{
+		"code":       interfaces.DataType_String,
+		"population": interfaces.DataType_Integer,
+		"area":       interfaces.DataType_Float,
+		"member":     interfaces.DataType_Boolean,
+		"founded":    interfaces.DataType_Date,
+	}
+	if len(csvFile.Fields) != len(want) || csvFile.Fields[0].Name != "code" {
+		t.Fatalf("unexpected csv fields: %+v", csvFile.Fields)
+	}
+	for _, field := range csvFile.Fields {
+		if want[field.Name] != field.Type {
+			t.Errorf("field %s: expected type %s, got %s", field.Name, want[field.Name], field.Type)
+		}
+	}
+
+	parquetFile := filesets[2].Files[0]
+	if err := c.GetFileMeta(ctx, parquetFile); err != nil {
+		t.Fatalf("GetFileMeta parquet failed: %v", err)
+	}
+	if len(parquetFile.Fields) != 4 || parquetFile.Fields[3].Type != interfaces.DataType_Datetime ||
+		parquetFile.Properties["num_rows"] != int64(2) {
+		t.Errorf("unexpected parquet meta: %+v", parquetFile)
+	}
+}
+
+func TestExecuteQuery(t *testing.T) {
+	server := newStubServer(t, "data", testObjects(t))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := newTestConnector(t, server.URL, interfaces.ConnectorConfig{"bucket": "data"})
+
+	schema := []*interfaces.Property{
+		{Name: "code", Type: interfaces.DataType_String, OriginalName: "code"},
+		{Name: "population", Type: interfaces.DataType_Integer, OriginalName: "population"},
+		{Name: "area", Type: interfaces.DataType_Float, OriginalName: "area"},
+	}
+	fieldsMap := map[string]*interfaces.Property{}
+	for _, prop := range schema {
+		fieldsMap[prop.Name] = prop
+	}
+	cond, err := filter_condition.NewFilterCondition(ctx, &interfaces.FilterCondCfg{
+		Name: "population", Operation: ">",
+		ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_Const, Value: 1000000},
+	}, fieldsMap)
+	if err != nil {
+		t.Fatalf("NewFilterCondition failed: %v", err)
+	}
+
+	result, err := c.ExecuteQuery(ctx, &interfaces.Resource{
+		Category:         interfaces.ResourceCategoryFile,
+		SourceIdentifier: "data/ref/countries.csv",
+		SchemaDefinition: schema,
+	}, &interfaces.ResourceDataQueryParams{
+		ActualFilterCond: cond,
+		Sort:             []*interfaces.SortField{{Field: "population", Direction: interfaces.ASC_DIRECTION}},
+		OutputFields:     []string{"code", "population"},
+		Limit:            10,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteQuery failed: %v", err)
+	}
+	if result.Total != 2 || result.Rows[0]["code"] != "FR" || result.Rows[1]["population"] != int64(1410000000) {
+		t.Errorf("unexpected file result: %+v", result)
+	}
+	if _, ok := result.Rows[0]["area"]; ok {
+		t.Errorf("unexpected projected field area: %+v", result.Rows[0])
+	}
+
+	// 文件集读取目录下的全部数据文件，jsonl 与 json 数组均可读取
+	result, err = c.ExecuteQuery(ctx, &interfaces.Resource{
+		Category:         interfaces.ResourceCategoryFileset,
+		SourceIdentifier: "data/ref/events/",
+		SchemaDefinition: []*interfaces.Property{{Name: "id", Type: interfaces.DataType_Integer, OriginalName: "id"}},
+	}, &interfaces.ResourceDataQueryParams{Offset: 1, Limit: 5})
+	if err != nil {
+		t.Fatalf("ExecuteQuery fileset failed: %v", err)
+	}
+	if result.Total != 3 || len(result.Rows) != 2 || result.Rows[1]["score"] != 0.5 {
+		t.Errorf("unexpected fileset result: %+v", result)
+	}
+
+	result, err = c.ExecuteQuery(ctx, &interfaces.Resource{
+		Category:         interfaces.ResourceCategoryFile,
+		SourceIdentifier: "data/ref/sensors/data.parquet",
+	}, &interfaces.ResourceDataQueryParams{
+		Sort: []*interfaces.SortField{{Field: "reading", Direction: interfaces.DESC_DIRECTION}},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteQuery parquet failed: %v", err)
+	}
+	if result.Total != 2 || result.Rows[0]["name"] != "b" {
+		t.Fatalf("unexpected parquet result: %+v", result)
+	}
+	if ts, ok := result.Rows[0]["ts"].(time.Time); !ok || ts.UnixMilli() != 1700000060000 {
+		t.Errorf("unexpected parquet timestamp: %v", result.Rows[0]["ts"])
+	}
+}
+
+func TestExecuteQuery_ScanLimitExceeded(t *testing.T) {
+	var csv strings.Builder
+	csv.WriteString("id\n")
+	for i := 0; i < interfaces.MAX_FILE_SCAN_ROWS; i++ {
+		csv.WriteString(strconv.Itoa(i) + "\n")
+	}
+	server := newStubServer(t, "data", map[string][]byte{
+		"big/a.csv": []byte(csv.String()),
+		"big/b.csv": []byte("id\n-1\n"),
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	c := newTestConnector(t, server.URL, interfaces.ConnectorConfig{"bucket": "data"})
+
+	// 正好 MAX_FILE_SCAN_ROWS 行时可以完整读取
+	result, err := c.ExecuteQuery(ctx, &interfaces.Resource{
+		Category:         interfaces.ResourceCategoryFile,
+		SourceIdentifier: "data/big/a.csv",
+	}, &interfaces.ResourceDataQueryParams{Limit: 1})
+	if err != nil {
+		t.Fatalf("ExecuteQuery failed: %v", err)
+	}
+	if result.Total != interfaces.MAX_FILE_SCAN_ROWS {
+		t.Errorf("expected total %d, got %d", interfaces.MAX_FILE_SCAN_ROWS, result.Total)
+	}
+
+	// 文件集超过上限时报错，不返回少算的总数
+	_, err = c.ExecuteQuery(ctx, &interfaces.Resource{
+		Category:         interfaces.ResourceCategoryFileset,
+		SourceIdentifier: "data/big/",
+	}, &interfaces.ResourceDataQueryParams{Limit: 1})
+	if !errors.Is(err, interfaces.ErrFileScanLimitExceeded) {
+		t.Fatalf("expected ErrFileScanLimitExceeded, got %v", err)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	cases := map[string]string{
+		"a/b.CSV":      FileFormatCSV,
+		"a/b.tsv.gz":   FileFormatCSV,
+		"a/b.ndjson":   FileFormatJSONL,
+		"b.parquet":    FileFormatParquet,
+		"b.parquet.gz": "",
+		"image.png":    "",
+		"no_extension": "",
+	}
+	for key, expected := range cases {
+		if got, _ := detectFormat(key); got != expected {
+			t.Errorf("detectFormat(%s): expected %q, got %q", key, expected, got)
+		}
+	}
+	if _, delimiter := detectFormat("x.tsv"); delimiter != '\t' {
+		t.Errorf("expected tab delimiter for tsv")
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/filter_condition"
+)
+
+// ConvertFilterCondition 将过滤条件转换为 OpenSearch query DSL
+func (c *OpenSearchConnector) ConvertFilterCondition(ctx context.Context, condition interfaces.FilterCondition) (map[string]any, error) {
+	switch condition.GetOperation() {
+	case filter_condition.OperationAnd:
+		cond, ok := condition.(*filter_condition.AndCond)
+		if !ok {
+			return nil, fmt.Errorf("condition is not *filter_condition.AndCond")
+		}
+		must := make([]any, 0, len(cond.SubConds))
+		for _, subCond := range cond.SubConds {
+			dsl, err := c.ConvertFilterCondition(ctx, subCond)
+			if err != nil {
+				return nil, err
+			}
+			must = append(must, dsl)
+		}
+		return boolQuery("filter", must...), nil
+
+	case filter_condition.OperationOr:
+		cond, ok := condition.(*filter_condition.OrCond)
+		if !ok {
+			return nil, fmt.Errorf("condition is not *filter_condition.OrCond")
+		}
+		should := make([]any, 0, len(cond.SubConds))
+		for _, subCond := range cond.SubConds {
+			dsl, err := c.ConvertFilterCondition(ctx, subCond)
+			if err != nil {
+				return nil, err
+			}
+			should = append(should, dsl)
+		}
+		q := boolQuery("should", should...)
+		q["bool"].(map[string]any)["minimum_should_match"] = 1
+		return q, nil
+
+	default:
+		return c.ConvertFilterConditionWithOpr(ctx, condition)
+	}
+}
+
+// ConvertFilterConditionWithOpr 转换单个叶子条件
+func (c *OpenSearchConnector) ConvertFilterConditionWithOpr(ctx context.Context, condition interfaces.FilterCondition) (map[string]any, error) {
+	switch cond := condition.(type) {
+	case *filter_condition.EqualCond:
+		if cond.Cfg.ValueFrom == interfaces.ValueFrom_Field {
+			return nil, fmt.Errorf("condition [eq] with value_from field is not supported by opensearch")
+		}
+		return map[string]any{"term": map[string]any{cond.Lfield.OriginalName: cond.Value}}, nil
+	case *filter_condition.NotEqualCond:
+		if cond.Cfg.ValueFrom == interfaces.ValueFrom_Field {
+			return nil, fmt.Errorf("condition [not_eq] with value_from field is not supported by opensearch")
+		}
+		return boolQuery("must_not", map[string]any{"term": map[string]any{cond.Lfield.OriginalName: cond.Value}}), nil
+	case *filter_condition.GtCond:
+		return rangeQuery(cond.Lfield.OriginalName, "gt", cond.Value), nil
+	case *filter_condition.GteCond:
+		return rangeQuery(cond.Lfield.OriginalName, "gte", cond.Value), nil
+	case *filter_condition.LtCond:
+		return rangeQuery(cond.Lfield.OriginalName, "lt", cond.Value), nil
+	case *filter_condition.LteCond:
+		return rangeQuery(cond.Lfield.OriginalName, "lte", cond.Value), nil
+	case *filter_condition.InCond:
+		return map[string]any{"terms": map[string]any{cond.Lfield.OriginalName: cond.Value}}, nil
+	case *filter_condition.NotInCond:
+		return boolQuery("must_not", map[string]any{"terms": map[string]any{cond.Lfield.OriginalName: cond.Value}}), nil
+	case *filter_condition.LikeCond:
+		return wildcardQuery(cond.Lfield.OriginalName, "*"+escapeWildcard(cond.Value)+"*"), nil
+	case *filter_condition.NotLikeCond:
+		return boolQuery("must_not", wildcardQuery(cond.Lfield.OriginalName, "*"+escapeWildcard(cond.Value)+"*")), nil
+	case *filter_condition.PrefixCond:
+		return map[string]any{"prefix": map[string]any{cond.Lfield.OriginalName: cond.Value}}, nil
+	case *filter_condition.NotPrefixCond:
+		return boolQuery("must_not", map[string]any{"prefix": map[string]any{cond.Lfield.OriginalName: cond.Value}}), nil
+	case *filter_condition.RangeCond:
+		if len(cond.Value) != 2 {
+			return nil, fmt.Errorf("range condition requires exactly 2 values")
+		}
+		return map[string]any{"range": map[string]any{cond.Lfield.OriginalName: map[string]any{
+			"gte": cond.Value[0], "lte": cond.Value[1]}}}, nil
+	case *filter_condition.OutRangeCond:
+		if len(cond.Value) != 2 {
+			return nil, fmt.Errorf("out_range condition requires exactly 2 values")
+		}
+		q := boolQuery("should",
+			rangeQuery(cond.Lfield.OriginalName, "lt", cond.Value[0]),
+			rangeQuery(cond.Lfield.OriginalName, "gt", cond.Value[1]))
+		q["bool"].(map[string]any)["minimum_should_match"] = 1
+		return q, nil
+	case *filter_condition.BetweenCond:
+		if len(cond.Value) != 2 {
+			return nil, fmt.Errorf("between condition requires exactly 2 values")
+		}
+		return map[string]any{"range": map[string]any{cond.Lfield.OriginalName: map[string]any{
+			"gte": cond.Value[0], "lte": cond.Value[1]}}}, nil
+	case *filter_condition.ExistCond:
+		return existsQuery(cond.Lfield.OriginalName), nil
+	case *filter_condition.NotExistCond:
+		return boolQuery("must_not", existsQuery(cond.Lfield.OriginalName)), nil
+	case *filter_condition.NotNullCond:
+		return existsQuery(cond.Lfield.OriginalName), nil
+	case *filter_condition.NullCond:
+		return boolQuery("must_not", existsQuery(cond.Lfield.OriginalName)), nil
+	case *filter_condition.EmptyCond:
+		return map[string]any{"term": map[string]any{cond.Lfield.OriginalName: ""}}, nil
+	case *filter_condition.NotEmptyCond:
+		return boolQuery("must_not", map[string]any{"term": map[string]any{cond.Lfield.OriginalName: ""}}), nil
+	case *filter_condition.RegexCond:
+		return map[string]any{"regexp": map[string]any{cond.Lfield.OriginalName: cond.Value}}, nil
+	case *filter_condition.TrueCond:
+		return map[string]any{"term": map[string]any{cond.Lfield.OriginalName: true}}, nil
+	case *filter_condition.FalseCond:
+		return map[string]any{"term": map[string]any{cond.Lfield.OriginalName: false}}, nil
+	default:
+		return nil, fmt.Errorf("operation %s is not supported", condition.GetOperation())
+	}
+}
+
+func boolQuery(occur string, clauses ...any) map[string]any {
+	return map[string]any{"bool": map[string]any{occur: clauses}}
+}
+
+func rangeQuery(field, op string, value any) map[string]any {
+	return map[string]any{"range": map[string]any{field: map[string]any{op: value}}}
+}
+
+func existsQuery(field string) map[string]any {
+	return map[string]any{"exists": map[string]any{"field": field}}
+}
+
+func wildcardQuery(field, pattern string) map[string]any {
+	return map[string]any{"wildcard": map[string]any{field: map[string]any{"value": pattern}}}
+}
+
+var wildcardSpecial = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
+
+func escapeWildcard(s string) string {
+	return wildcardSpecial.Replace(s)
+}
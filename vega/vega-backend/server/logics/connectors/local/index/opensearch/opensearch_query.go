@@ -0,0 +1,192 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+
+	"vega-backend/interfaces"
+)
+
+// pitKeepAlive search_after 深分页时 PIT 的保活时间，每次翻页都会续期
+const pitKeepAlive = time.Minute
+
+// ExecuteQuery 在单个索引上执行查询，过滤条件、投影、排序与分页均下推到 OpenSearch
+func (c *OpenSearchConnector) ExecuteQuery(ctx context.Context, resource *interfaces.Resource,
+	params *interfaces.ResourceDataQueryParams) (*interfaces.QueryResult, error) {
+
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	query := map[string]any{"match_all": map[string]any{}}
+	if params.ActualFilterCond != nil {
+		dsl, err := c.ConvertFilterCondition(ctx, params.ActualFilterCond)
+		if err != nil {
+			return nil, err
+		}
+		query = dsl
+	}
+
+	size := params.Limit
+	if size <= 0 || size > interfaces.MAX_SEARCH_SIZE {
+		size = interfaces.MAX_SEARCH_SIZE
+	}
+	body := map[string]any{
+		"query":            query,
+		"size":             size,
+		"track_total_hits": params.NeedTotal,
+	}
+	if len(params.OutputFields) > 0 {
+		body["_source"] = params.OutputFields
+	}
+	sort := make([]map[string]any, 0, len(params.Sort)+1)
+	for _, s := range params.Sort {
+		dir := interfaces.ASC_DIRECTION
+		if s.Direction == interfaces.DESC_DIRECTION {
+			dir = interfaces.DESC_DIRECTION
+		}
+		sort = append(sort, map[string]any{s.Field: map[string]any{"order": dir}})
+	}
+
+	indices := []string{resource.SourceIdentifier}
+	cursor := params.IndexCursor
+	if cursor != nil {
+		// 深分页：在 PIT 快照上按 search_after 翻页，以 _id 作为唯一的排序兜底，保证页间不重复不遗漏
+		if cursor.PitID == "" {
+			pitID, err := c.openPointInTime(ctx, resource.SourceIdentifier)
+			if err != nil {
+				return nil, err
+			}
+			cursor.PitID = pitID
+		}
+		body["pit"] = map[string]any{"id": cursor.PitID, "keep_alive": pitKeepAlive.String()}
+		if len(cursor.SearchAfter) > 0 {
+			body["search_after"] = cursor.SearchAfter
+		}
+		sort = append(sort, map[string]any{"_id": map[string]any{"order": interfaces.ASC_DIRECTION}})
+		// 使用 PIT 时不能再指定索引
+		indices = nil
+	} else {
+		body["from"] = params.Offset
+	}
+	if len(sort) > 0 {
+		body["sort"] = sort
+	}
+
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search body: %w", err)
+	}
+	logger.Debugf("index query: %s, body: %s", resource.SourceIdentifier, string(bodyJSON))
+
+	req := opensearchapi.SearchRequest{
+		Index: indices,
+		Body:  bytes.NewReader(bodyJSON),
+	}
+	resp, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute search: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("failed to execute search: %s", resp.String())
+	}
+
+	var searchResp struct {
+		PitID string `json:"pit_id"`
+		Hits  struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID     string         `json:"_id"`
+				Source map[string]any `json:"_source"`
+				Sort   []any          `json:"sort"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+
+	result := &interfaces.QueryResult{
+		Columns: params.OutputFields,
+		Rows:    make([]map[string]any, 0, len(searchResp.Hits.Hits)),
+		Total:   searchResp.Hits.Total.Value,
+	}
+	for _, hit := range searchResp.Hits.Hits {
+		row := hit.Source
+		if row == nil {
+			row = map[string]any{}
+		}
+		row["_id"] = hit.ID
+		result.Rows = append(result.Rows, row)
+	}
+	if cursor != nil {
+		// PIT id 在查询过程中可能变化，以最新返回的为准
+		if searchResp.PitID != "" {
+			cursor.PitID = searchResp.PitID
+		}
+		if n := len(searchResp.Hits.Hits); n > 0 {
+			cursor.SearchAfter = searchResp.Hits.Hits[n-1].Sort
+		}
+	}
+
+	return result, nil
+}
+
+// openPointInTime 在索引上打开 PIT，供 search_after 深分页使用
+func (c *OpenSearchConnector) openPointInTime(ctx context.Context, index string) (string, error) {
+	req := opensearchapi.PointInTimeCreateRequest{
+		Index:     []string{index},
+		KeepAlive: pitKeepAlive,
+	}
+	resp, pit, err := req.Do(ctx, c.client)
+	if resp != nil {
+		defer resp.Body.Close()
+		if resp.IsError() {
+			return "", fmt.Errorf("failed to create point in time: status %d", resp.StatusCode)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create point in time: %w", err)
+	}
+	if pit == nil || pit.PitID == "" {
+		return "", fmt.Errorf("failed to create point in time: empty pit id")
+	}
+	return pit.PitID, nil
+}
+
+// ClosePointInTime 关闭深分页打开的 PIT，PIT 过期后也会自动释放
+func (c *OpenSearchConnector) ClosePointInTime(ctx context.Context, pitID string) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	req := opensearchapi.PointInTimeDeleteRequest{
+		PitID: []string{pitID},
+	}
+	resp, _, err := req.Do(ctx, c.client)
+	if resp != nil {
+		defer resp.Body.Close()
+		if resp.IsError() {
+			return fmt.Errorf("failed to delete point in time: status %d", resp.StatusCode)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete point in time: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"vega-backend/interfaces"
+)
+
+// newStubServer 模拟 OpenSearch 的 PIT 与 search API，记录每次 search 的请求体
+func newStubServer(t *testing.T, bodies *[]map[string]any, deleted *[]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/logs/_search/point_in_time", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("keep_alive") == "" {
+			t.Errorf("expected keep_alive on pit creation")
+		}
+		_, _ = w.Write([]byte(`{"pit_id":"pit-1","_shards":{"total":1,"successful":1}}`))
+	})
+	mux.HandleFunc("/_search/point_in_time", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			PitID []string `json:"pit_id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		*deleted = append(*deleted, body.PitID...)
+		_, _ = w.Write([]byte(`{"pits":[{"pit_id":"pit-1","successful":true}]}`))
+	})
+	mux.HandleFunc("/logs/_search", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		*bodies = append(*bodies, body)
+		_, _ = w.Write([]byte(`{"hits":{"total":{"value":0},"hits":[]}}`))
+	})
+	mux.HandleFunc("/_search", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		*bodies = append(*bodies, body)
+		if _, ok := body["search_after"]; ok {
+			_, _ = w.Write([]byte(`{"pit_id":"pit-2","hits":{"total":{"value":3},"hits":[
+				{"_id":"c","_source":{"v":3},"sort":["c"]}]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"pit_id":"pit-1","hits":{"total":{"value":3},"hits":[
+			{"_id":"a","_source":{"v":1},"sort":["a"]},
+			{"_id":"b","_source":{"v":2},"sort":["b"]}]}}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func newTestConnector(t *testing.T, url string) *OpenSearchConnector {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(url[len("http://"):])
+	if err != nil {
+		t.Fatalf("parse server url failed: %v", err)
+	}
+	portNum, _ := strconv.Atoi(port)
+	conn, err := NewOpenSearchConnector().New(interfaces.ConnectorConfig{"host": host, "port": portNum})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return conn.(*OpenSearchConnector)
+}
+
+func TestExecuteQuery_SearchAfterPages(t *testing.T) {
+	var bodies []map[string]any
+	var deleted []string
+	server := newStubServer(t, &bodies, &deleted)
+	defer server.Close()
+
+	ctx := context.Background()
+	c := newTestConnector(t, server.URL)
+	resource := &interfaces.Resource{SourceIdentifier: "logs"}
+	cursor := &interfaces.IndexPageCursor{}
+
+	first, err := c.ExecuteQuery(ctx, resource, &interfaces.ResourceDataQueryParams{Offset: 0, Limit: 2, IndexCursor: cursor})
+	if err != nil {
+		t.Fatalf("ExecuteQuery first page failed: %v", err)
+	}
+	if len(first.Rows) != 2 || first.Rows[1]["_id"] != "b" {
+		t.Fatalf("unexpected first page: %v", first.Rows)
+	}
+	if cursor.PitID != "pit-1" || len(cursor.SearchAfter) != 1 || cursor.SearchAfter[0] != "b" {
+		t.Fatalf("unexpected cursor after first page: %+v", cursor)
+	}
+
+	// 第二页的 offset 超过 max_result_window 也不会下推为 from
+	second, err := c.ExecuteQuery(ctx, resource, &interfaces.ResourceDataQueryParams{
+		Offset: interfaces.MAX_SEARCH_SIZE, Limit: 2, IndexCursor: cursor})
+	if err != nil {
+		t.Fatalf("ExecuteQuery second page failed: %v", err)
+	}
+	if len(second.Rows) != 1 || second.Rows[0]["_id"] != "c" || cursor.PitID != "pit-2" {
+		t.Fatalf("unexpected second page: %v, cursor: %+v", second.Rows, cursor)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 search requests, got %d", len(bodies))
+	}
+	for i, body := range bodies {
+		if _, ok := body["from"]; ok {
+			t.Errorf("page %d: from must not be set with search_after", i)
+		}
+		pit, _ := body["pit"].(map[string]any)
+		if pit == nil || pit["keep_alive"] == "" {
+			t.Errorf("page %d: expected pit in body, got %v", i, body["pit"])
+		}
+		sort, _ := body["sort"].([]any)
+		if len(sort) != 1 || sort[0].(map[string]any)["_id"] == nil {
+			t.Errorf("page %d: expected _id tiebreaker sort, got %v", i, body["sort"])
+		}
+	}
+	if after, _ := bodies[1]["search_after"].([]any); len(after) != 1 || after[0] != "b" {
+		t.Errorf("expected search_after [b], got %v", bodies[1]["search_after"])
+	}
+	if pit := bodies[1]["pit"].(map[string]any); pit["id"] != "pit-1" {
+		t.Errorf("expected second page on pit-1, got %v", pit["id"])
+	}
+
+	if err := c.ClosePointInTime(ctx, cursor.PitID); err != nil {
+		t.Fatalf("ClosePointInTime failed: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "pit-2" {
+		t.Errorf("expected pit-2 deleted, got %v", deleted)
+	}
+}
+
+func TestExecuteQuery_FromSizeWithoutCursor(t *testing.T) {
+	var bodies []map[string]any
+	var deleted []string
+	server := newStubServer(t, &bodies, &deleted)
+	defer server.Close()
+
+	c := newTestConnector(t, server.URL)
+	_, err := c.ExecuteQuery(context.Background(), &interfaces.Resource{SourceIdentifier: "logs"},
+		&interfaces.ResourceDataQueryParams{Offset: 20, Limit: 10})
+	if err != nil {
+		t.Fatalf("ExecuteQuery failed: %v", err)
+	}
+	if len(bodies) != 1 || bodies[0]["from"] != float64(20) {
+		t.Fatalf("expected from/size search on the index, got %v", bodies)
+	}
+	if _, ok := bodies[0]["pit"]; ok {
+		t.Errorf("pit must not be used without cursor")
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package prometheus provides Prometheus-compatible metric connector implementation.
+package prometheus
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/mitchellh/mapstructure"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/connectors"
+)
+
+const (
+	defaultRequestTimeout = 30 * time.Second
+	// 响应体大小上限，避免异常响应耗尽内存
+	maxResponseBytes = 64 << 20
+)
+
+type prometheusConfig struct {
+	URL                string `mapstructure:"url"`
+	Username           string `mapstructure:"username"`
+	Password           string `mapstructure:"password"`
+	BearerToken        string `mapstructure:"bearer_token"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+	MetricPattern      string `mapstructure:"metric_pattern"`
+}
+
+// PrometheusConnector implements MetricConnector for Prometheus-compatible HTTP API.
+// 兼容 Prometheus HTTP API 的时序库（如 Thanos、VictoriaMetrics、Mimir）均可接入
+type PrometheusConnector struct {
+	enabled bool
+	Config  *prometheusConfig
+	client  *http.Client
+}
+
+// NewPrometheusConnector 创建 Prometheus connector 构建器
+func NewPrometheusConnector() connectors.MetricConnector {
+	return &PrometheusConnector{}
+}
+
+// GetType returns the data source type.
+func (c *PrometheusConnector) GetType() string {
+	return "prometheus"
+}
+
+// GetName returns the data source name.
+func (c *PrometheusConnector) GetName() string {
+	return "prometheus"
+}
+
+// GetMode returns the connector mode.
+func (c *PrometheusConnector) GetMode() string {
+	return interfaces.ConnectorModeLocal
+}
+
+// GetCategory returns the connector category.
+func (c *PrometheusConnector) GetCategory() string {
+	return interfaces.ConnectorCategoryMetric
+}
+
+// GetEnabled returns the enabled status.
+func (c *PrometheusConnector) GetEnabled() bool {
+	return c.enabled
+}
+
+// SetEnabled sets the enabled status.
+func (c *PrometheusConnector) SetEnabled(enabled bool) {
+	c.enabled = enabled
+}
+
+// GetSensitiveFields returns the sensitive fields for Prometheus connector.
+func (c *PrometheusConnector) GetSensitiveFields() []string {
+	return []string{"password", "bearer_token"}
+}
+
+// GetFieldConfig returns the field configuration for Prometheus connector.
+func (c *PrometheusConnector) GetFieldConfig() map[string]interfaces.ConnectorFieldConfig {
+	return map[string]interfaces.ConnectorFieldConfig{
+		"url":                  {Name: "服务地址", Type: "string", Description: "Prometheus HTTP API 地址，如 http://localhost:9090", Required: true, Encrypted: false},
+		"username":             {Name: "用户名", Type: "string", Description: "Basic 认证用户名", Required: false, Encrypted: false},
+		"password":             {Name: "密码", Type: "string", Description: "Basic 认证密码", Required: false, Encrypted: true},
+		"bearer_token":         {Name: "Bearer Token", Type: "string", Description: "Bearer 认证令牌", Required: false, Encrypted: true},
+		"insecure_skip_verify": {Name: "跳过证书校验", Type: "boolean", Description: "HTTPS 连接时是否跳过证书校验", Required: false, Encrypted: false},
+		"metric_pattern":       {Name: "指标模式", Type: "string", Description: "指标名匹配模式（可选，如 http_*）", Required: false, Encrypted: false},
+	}
+}
+
+// New creates a new Prometheus connector.
+func (c *PrometheusConnector) New(cfg interfaces.ConnectorConfig) (connectors.Connector, error) {
+	var pCfg prometheusConfig
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		Result:           &pCfg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus config decoder: %w", err)
+	}
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode prometheus config: %w", err)
+	}
+
+	pCfg.URL = strings.TrimRight(strings.TrimSpace(pCfg.URL), "/")
+	if pCfg.URL == "" {
+		return nil, fmt.Errorf("prometheus config url is required")
+	}
+	if u, err := url.Parse(pCfg.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid prometheus url: %s", pCfg.URL)
+	}
+
+	return &PrometheusConnector{
+		Config: &pCfg,
+	}, nil
+}
+
+// Connect creates the HTTP client.
+func (c *PrometheusConnector) Connect(ctx context.Context) error {
+	if c.client != nil {
+		return nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.Config.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+	}
+	c.client = &http.Client{
+		Transport: transport,
+		Timeout:   defaultRequestTimeout,
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (c *PrometheusConnector) Close(ctx context.Context) error {
+	if c.client != nil {
+		c.client.CloseIdleConnections()
+	}
+	c.client = nil
+	return nil
+}
+
+// Ping checks the connection by evaluating a constant expression.
+func (c *PrometheusConnector) Ping(ctx context.Context) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	if _, err := c.api(ctx, "/api/v1/query", url.Values{"query": {"1"}}); err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	return nil
+}
+
+// TestConnection tests the connection to Prometheus.
+func (c *PrometheusConnector) TestConnection(ctx context.Context) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	return c.Ping(ctx)
+}
+
+// GetMetadata returns the build info of the server.
+// 部分兼容实现不提供 buildinfo 接口，此时返回空元数据
+func (c *PrometheusConnector) GetMetadata(ctx context.Context) (map[string]any, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("connector not connected")
+	}
+
+	data, err := c.api(ctx, "/api/v1/status/buildinfo", nil)
+	if err != nil {
+		return map[string]any{}, nil
+	}
+	var info map[string]any
+	if err := sonic.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode build info: %w", err)
+	}
+	return info, nil
+}
+
+// api 调用 Prometheus HTTP API，返回 data 字段的原始 JSON
+// 参数较多时（如范围查询）使用 POST 表单提交，避免 URL 超长
+func (c *PrometheusConnector) api(ctx context.Context, path string, params url.Values) ([]byte, error) {
+	var (
+		req *http.Request
+		err error
+	)
+	endpoint := c.Config.URL + path
+	if len(params) == 0 {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(params.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case c.Config.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.Config.BearerToken)
+	case c.Config.Username != "":
+		req.SetBasicAuth(c.Config.Username, c.Config.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of %s: %w", path, err)
+	}
+
+	// Prometheus HTTP API 通用响应格式
+	var apiResp struct {
+		Status    string          `json:"status"`
+		Data      json.RawMessage `json:"data"`
+		ErrorType string          `json:"errorType"`
+		Error     string          `json:"error"`
+	}
+	if err := sonic.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("request %s failed with status %d: %s", path, resp.StatusCode, truncate(string(body), 256))
+	}
+	if apiResp.Status != "success" {
+		return nil, fmt.Errorf("request %s failed: %s: %s", path, apiResp.ErrorType, apiResp.Error)
+	}
+	return apiResp.Data, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,114 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package prometheus
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/filter_condition"
+)
+
+// labelMatcher PromQL 标签匹配器
+type labelMatcher struct {
+	Name  string
+	Op    string // =, !=, =~, !~
+	Value string
+}
+
+func (m labelMatcher) String() string {
+	return m.Name + m.Op + strconv.Quote(m.Value)
+}
+
+// buildSelector 构建指标选择器，如 {__name__="http_requests_total",code="200"}
+// 使用 __name__ 匹配器以兼容含特殊字符的指标名
+func buildSelector(metricName string, matchers []labelMatcher) string {
+	parts := make([]string, 0, len(matchers)+1)
+	parts = append(parts, labelMatcher{Name: metricNameLabel, Op: "=", Value: metricName}.String())
+	for _, m := range matchers {
+		parts = append(parts, m.String())
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// ConvertFilterCondition 将过滤条件转换为标签匹配器
+// 一个选择器内的匹配器之间为 AND 关系，因此只支持 and 组合，标签值均按字符串处理
+func (c *PrometheusConnector) ConvertFilterCondition(condition interfaces.FilterCondition) ([]labelMatcher, error) {
+	if condition == nil {
+		return nil, nil
+	}
+
+	switch cond := condition.(type) {
+	case *filter_condition.AndCond:
+		var matchers []labelMatcher
+		for _, subCond := range cond.SubConds {
+			sub, err := c.ConvertFilterCondition(subCond)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, sub...)
+		}
+		return matchers, nil
+
+	case *filter_condition.EqualCond:
+		if cond.Cfg.ValueFrom == interfaces.ValueFrom_Field {
+			return nil, fmt.Errorf("condition [eq] with value_from field is not supported by prometheus")
+		}
+		return single(cond.Lfield, "=", fmt.Sprint(cond.Value)), nil
+	case *filter_condition.NotEqualCond:
+		if cond.Cfg.ValueFrom == interfaces.ValueFrom_Field {
+			return nil, fmt.Errorf("condition [not_eq] with value_from field is not supported by prometheus")
+		}
+		return single(cond.Lfield, "!=", fmt.Sprint(cond.Value)), nil
+	case *filter_condition.InCond:
+		return single(cond.Lfield, "=~", alternation(cond.Value)), nil
+	case *filter_condition.NotInCond:
+		return single(cond.Lfield, "!~", alternation(cond.Value)), nil
+	case *filter_condition.LikeCond:
+		return single(cond.Lfield, "=~", ".*"+regexp.QuoteMeta(cond.Value)+".*"), nil
+	case *filter_condition.NotLikeCond:
+		return single(cond.Lfield, "!~", ".*"+regexp.QuoteMeta(cond.Value)+".*"), nil
+	case *filter_condition.PrefixCond:
+		return single(cond.Lfield, "=~", regexp.QuoteMeta(cond.Value)+".*"), nil
+	case *filter_condition.NotPrefixCond:
+		return single(cond.Lfield, "!~", regexp.QuoteMeta(cond.Value)+".*"), nil
+	case *filter_condition.RegexCond:
+		return single(cond.Lfield, "=~", cond.Value), nil
+	// 标签不存在与取值为空在 Prometheus 中等价
+	case *filter_condition.ExistCond:
+		return single(cond.Lfield, "!=", ""), nil
+	case *filter_condition.NotExistCond:
+		return single(cond.Lfield, "=", ""), nil
+	case *filter_condition.NotNullCond:
+		return single(cond.Lfield, "!=", ""), nil
+	case *filter_condition.NullCond:
+		return single(cond.Lfield, "=", ""), nil
+	case *filter_condition.NotEmptyCond:
+		return single(cond.Lfield, "!=", ""), nil
+	case *filter_condition.EmptyCond:
+		return single(cond.Lfield, "=", ""), nil
+	default:
+		return nil, fmt.Errorf("operation %s is not supported by prometheus connector", condition.GetOperation())
+	}
+}
+
+func single(field *interfaces.Property, op, value string) []labelMatcher {
+	return []labelMatcher{{Name: field.OriginalName, Op: op, Value: value}}
+}
+
+// alternation 将取值列表转换为正则分支，PromQL 正则匹配默认锚定整个标签值
+func alternation(values []any) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		parts = append(parts, regexp.QuoteMeta(fmt.Sprint(v)))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "|")
+}
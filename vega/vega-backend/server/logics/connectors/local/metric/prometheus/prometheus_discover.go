@@ -0,0 +1,148 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"vega-backend/interfaces"
+)
+
+const (
+	// 发现标签集合时回溯的时间窗口
+	discoverLookback = time.Hour
+	// 发现标签集合时最多读取的序列数
+	maxDiscoverSeries = 10000
+	// 每个标签保留的取值样例数
+	maxLabelValueSamples = 20
+
+	metricNameLabel = "__name__"
+)
+
+// ListMetrics lists all metric names with type/help metadata if available.
+func (c *PrometheusConnector) ListMetrics(ctx context.Context) ([]*interfaces.MetricMeta, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := c.api(ctx, "/api/v1/label/"+metricNameLabel+"/values", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list metrics: %w", err)
+	}
+	var names []string
+	if err := sonic.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("failed to decode metric names: %w", err)
+	}
+
+	// 指标元数据（类型、说明、单位）非所有兼容实现都提供，获取失败时忽略
+	metadata := map[string][]struct {
+		Type string `json:"type"`
+		Help string `json:"help"`
+		Unit string `json:"unit"`
+	}{}
+	if data, err := c.api(ctx, "/api/v1/metadata", nil); err != nil {
+		logger.Warnf("Failed to get metric metadata: %v", err)
+	} else if err := sonic.Unmarshal(data, &metadata); err != nil {
+		logger.Warnf("Failed to decode metric metadata: %v", err)
+	}
+
+	var metrics []*interfaces.MetricMeta
+	for _, name := range names {
+		if c.Config.MetricPattern != "" {
+			matched, err := path.Match(c.Config.MetricPattern, name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid metric pattern %s: %w", c.Config.MetricPattern, err)
+			}
+			if !matched {
+				continue
+			}
+		}
+
+		metric := &interfaces.MetricMeta{Name: name}
+		if items := metadata[name]; len(items) > 0 {
+			metric.Type = items[0].Type
+			metric.Help = items[0].Help
+			metric.Unit = items[0].Unit
+		}
+		metrics = append(metrics, metric)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	return metrics, nil
+}
+
+// GetMetricMeta retrieves the label set of a metric from its recent series.
+// GetMetricMeta 读取指标最近一段时间内的序列，汇总标签名与取值样例
+// 参数:
+//   - ctx: 上下文信息，用于控制请求的超时和取消
+//   - metric: 指标元数据，Labels、LabelValues 与 Properties 将被填充
+//
+// 返回值:
+//   - error: 如果操作过程中发生错误，则返回错误信息
+func (c *PrometheusConnector) GetMetricMeta(ctx context.Context, metric *interfaces.MetricMeta) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	if metric.Properties == nil {
+		metric.Properties = make(map[string]any)
+	}
+
+	end := time.Now()
+	start := end.Add(-discoverLookback)
+	data, err := c.api(ctx, "/api/v1/series", url.Values{
+		"match[]": {buildSelector(metric.Name, nil)},
+		"start":   {formatTimestamp(start.UnixMilli())},
+		"end":     {formatTimestamp(end.UnixMilli())},
+		"limit":   {strconv.Itoa(maxDiscoverSeries)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get series of metric %s: %w", metric.Name, err)
+	}
+	var series []map[string]string
+	if err := sonic.Unmarshal(data, &series); err != nil {
+		return fmt.Errorf("failed to decode series of metric %s: %w", metric.Name, err)
+	}
+
+	values := map[string]map[string]struct{}{}
+	for _, labels := range series {
+		for name, value := range labels {
+			if name == metricNameLabel {
+				continue
+			}
+			if values[name] == nil {
+				values[name] = map[string]struct{}{}
+			}
+			if len(values[name]) < maxLabelValueSamples {
+				values[name][value] = struct{}{}
+			}
+		}
+	}
+
+	metric.Labels = make([]string, 0, len(values))
+	metric.LabelValues = make(map[string][]string, len(values))
+	for name, set := range values {
+		metric.Labels = append(metric.Labels, name)
+		samples := make([]string, 0, len(set))
+		for value := range set {
+			samples = append(samples, value)
+		}
+		sort.Strings(samples)
+		metric.LabelValues[name] = samples
+	}
+	sort.Strings(metric.Labels)
+	metric.Properties["series_count"] = len(series)
+
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"vega-backend/interfaces"
+)
+
+const (
+	// 未指定时间范围时默认查询最近 1 小时
+	defaultQueryRange = time.Hour
+	// 未指定步长时按约 250 个点计算步长，且不小于 1 秒
+	defaultQueryPoints = 250
+	minQueryStep       = time.Second
+)
+
+// ExecuteRangeQuery 在指标上执行范围查询，过滤条件转换为标签匹配器下推到 Prometheus
+func (c *PrometheusConnector) ExecuteRangeQuery(ctx context.Context, resource *interfaces.Resource,
+	params *interfaces.ResourceDataQueryParams) ([]*interfaces.MetricResult, error) {
+
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	start, end, step, err := resolveRange(params.MetricRange, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	matchers, err := c.ConvertFilterCondition(params.ActualFilterCond)
+	if err != nil {
+		return nil, err
+	}
+	query := buildSelector(resource.SourceIdentifier, matchers)
+	logger.Debugf("metric query: %s, start: %d, end: %d, step: %d", query, start, end, step)
+
+	values := url.Values{
+		"query": {query},
+		"start": {formatTimestamp(start)},
+		"end":   {formatTimestamp(end)},
+		"step":  {formatTimestamp(step)},
+	}
+	if params.Timeout > 0 {
+		values.Set("timeout", formatTimestamp(params.Timeout.Milliseconds()))
+	}
+	data, err := c.api(ctx, "/api/v1/query_range", values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute range query: %w", err)
+	}
+
+	return parseMatrix(data, resource.SourceIdentifier)
+}
+
+// resolveRange 计算查询的起止时间与步长（毫秒），补全缺省值并校验点数上限
+func resolveRange(r *interfaces.MetricQueryRange, now time.Time) (int64, int64, int64, error) {
+	if r == nil {
+		r = &interfaces.MetricQueryRange{}
+	}
+
+	end := r.End
+	if end <= 0 {
+		end = now.UnixMilli()
+	}
+	start := r.Start
+	if start <= 0 {
+		start = end - defaultQueryRange.Milliseconds()
+	}
+	if start >= end {
+		return 0, 0, 0, fmt.Errorf("metric range start %d should be less than end %d", start, end)
+	}
+
+	step := r.Step
+	if step <= 0 {
+		step = max((end-start+defaultQueryPoints-1)/defaultQueryPoints, minQueryStep.Milliseconds())
+	}
+	if (end-start)/step > interfaces.MAX_METRIC_POINTS {
+		return 0, 0, 0, fmt.Errorf("metric range exceeds %d points per series, please increase the step",
+			interfaces.MAX_METRIC_POINTS)
+	}
+	return start, end, step, nil
+}
+
+// formatTimestamp 将毫秒转换为 Prometheus API 使用的秒（支持小数）
+func formatTimestamp(ms int64) string {
+	return strconv.FormatFloat(float64(ms)/1000, 'f', -1, 64)
+}
+
+// parseMatrix 解析范围查询返回的 matrix 结果
+func parseMatrix(data []byte, metricName string) ([]*interfaces.MetricResult, error) {
+	var matrix struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][2]any          `json:"values"`
+		} `json:"result"`
+	}
+	if err := sonic.Unmarshal(data, &matrix); err != nil {
+		return nil, fmt.Errorf("failed to decode range query result: %w", err)
+	}
+	if matrix.ResultType != "matrix" {
+		return nil, fmt.Errorf("unexpected range query result type: %s", matrix.ResultType)
+	}
+
+	results := make([]*interfaces.MetricResult, 0, len(matrix.Result))
+	for _, series := range matrix.Result {
+		name := metricName
+		labels := make(map[string]string, len(series.Metric))
+		for k, v := range series.Metric {
+			if k == metricNameLabel {
+				name = v
+				continue
+			}
+			labels[k] = v
+		}
+
+		points := make([]interfaces.MetricValue, 0, len(series.Values))
+		for _, pair := range series.Values {
+			ts, ok := pair[0].(float64)
+			if !ok {
+				return nil, fmt.Errorf("invalid sample timestamp: %v", pair[0])
+			}
+			s, ok := pair[1].(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid sample value: %v", pair[1])
+			}
+			value, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sample value %s: %w", s, err)
+			}
+			// NaN/Inf 无法以 JSON 数值输出，跳过该点
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			points = append(points, interfaces.MetricValue{
+				Timestamp: int64(math.Round(ts * 1000)),
+				Value:     value,
+			})
+		}
+
+		results = append(results, &interfaces.MetricResult{
+			Metric: name,
+			Labels: labels,
+			Values: points,
+		})
+	}
+
+	// 按标签排序，保证分页结果稳定
+	sort.Slice(results, func(i, j int) bool {
+		return labelsKey(results[i].Labels) < labelsKey(results[j].Labels)
+	})
+	return results, nil
+}
+
+func labelsKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(labels[k])
+		sb.WriteByte(',')
+	}
+	return sb.String()
+}
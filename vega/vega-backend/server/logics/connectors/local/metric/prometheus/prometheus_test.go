@@ -0,0 +1,161 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/connectors"
+	"vega-backend/logics/filter_condition"
+)
+
+// newStubServer 模拟 Prometheus HTTP API，记录范围查询收到的参数
+func newStubServer(t *testing.T, queries *[]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/label/__name__/values", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success","data":["up","http_requests_total","go_goroutines"]}`))
+	})
+	mux.HandleFunc("/api/v1/metadata", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success","data":{"http_requests_total":[{"type":"counter","help":"Total requests.","unit":""}]}}`))
+	})
+	mux.HandleFunc("/api/v1/series", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("match[]") != `{__name__="http_requests_total"}` {
+			t.Errorf("unexpected series match: %s", r.Form.Get("match[]"))
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":[
+			{"__name__":"http_requests_total","code":"200","job":"api"},
+			{"__name__":"http_requests_total","code":"500","job":"api"}]}`))
+	})
+	mux.HandleFunc("/api/v1/query_range", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		*queries = append(*queries, r.Form.Get("query"))
+		if r.Form.Get("start") != "1700000000" || r.Form.Get("end") != "1700000060" || r.Form.Get("step") != "30" {
+			t.Errorf("unexpected range params: %v", r.Form)
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{"__name__":"http_requests_total","code":"500","job":"api"},"values":[[1700000000,"3"],[1700000030,"NaN"]]},
+			{"metric":{"__name__":"http_requests_total","code":"200","job":"api"},"values":[[1700000000,"10"],[1700000030,"12.5"]]}]}}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func newTestConnector(t *testing.T, url string) *PrometheusConnector {
+	t.Helper()
+
+	conn, err := NewPrometheusConnector().New(interfaces.ConnectorConfig{"url": url + "/", "metric_pattern": "http_*"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	c := conn.(*PrometheusConnector)
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	return c
+}
+
+func TestDiscoverMetrics(t *testing.T) {
+	var queries []string
+	server := newStubServer(t, &queries)
+	defer server.Close()
+
+	ctx := context.Background()
+	c := newTestConnector(t, server.URL)
+	var _ connectors.MetricConnector = c
+
+	metrics, err := c.ListMetrics(ctx)
+	if err != nil {
+		t.Fatalf("ListMetrics failed: %v", err)
+	}
+	if len(metrics) != 1 || metrics[0].Name != "http_requests_total" || metrics[0].Type != "counter" {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+
+	if err := c.GetMetricMeta(ctx, metrics[0]); err != nil {
+		t.Fatalf("GetMetricMeta failed: %v", err)
+	}
+	if len(metrics[0].Labels) != 2 || metrics[0].Labels[0] != "code" || metrics[0].Labels[1] != "job" {
+		t.Errorf("unexpected labels: %v", metrics[0].Labels)
+	}
+	if codes := metrics[0].LabelValues["code"]; len(codes) != 2 || codes[0] != "200" {
+		t.Errorf("unexpected label values: %v", codes)
+	}
+}
+
+func TestExecuteRangeQuery(t *testing.T) {
+	var queries []string
+	server := newStubServer(t, &queries)
+	defer server.Close()
+
+	ctx := context.Background()
+	c := newTestConnector(t, server.URL)
+
+	fieldsMap := map[string]*interfaces.Property{
+		"code": {Name: "code", Type: interfaces.DataType_String, OriginalName: "code"},
+		"job":  {Name: "job", Type: interfaces.DataType_String, OriginalName: "job"},
+	}
+	cond, err := filter_condition.NewFilterCondition(ctx, &interfaces.FilterCondCfg{
+		Operation: "and",
+		SubConds: []*interfaces.FilterCondCfg{
+			{Name: "job", Operation: "==", ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_Const, Value: "api"}},
+			{Name: "code", Operation: "in", ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_Const, Value: []any{"500", "200"}}},
+		},
+	}, fieldsMap)
+	if err != nil {
+		t.Fatalf("NewFilterCondition failed: %v", err)
+	}
+
+	results, err := c.ExecuteRangeQuery(ctx, &interfaces.Resource{SourceIdentifier: "http_requests_total"},
+		&interfaces.ResourceDataQueryParams{
+			ActualFilterCond: cond,
+			MetricRange:      &interfaces.MetricQueryRange{Start: 1700000000000, End: 1700000060000, Step: 30000},
+		})
+	if err != nil {
+		t.Fatalf("ExecuteRangeQuery failed: %v", err)
+	}
+
+	if len(queries) != 1 || queries[0] != `{__name__="http_requests_total",job="api",code=~"200|500"}` {
+		t.Errorf("unexpected query: %v", queries)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(results))
+	}
+	// 按标签排序，code=200 在前；NaN 点被跳过
+	if results[0].Labels["code"] != "200" || len(results[0].Values) != 2 || results[0].Values[1].Value != 12.5 {
+		t.Errorf("unexpected series[0]: %+v", results[0])
+	}
+	if results[1].Metric != "http_requests_total" || len(results[1].Values) != 1 ||
+		results[1].Values[0].Timestamp != 1700000000000 {
+		t.Errorf("unexpected series[1]: %+v", results[1])
+	}
+}
+
+func TestResolveRange(t *testing.T) {
+	now := time.UnixMilli(7200000)
+
+	// 未指定范围时回溯 1 小时，步长按约 250 个点计算
+	start, end, step, err := resolveRange(nil, now)
+	if err != nil {
+		t.Fatalf("resolveRange failed: %v", err)
+	}
+	if start != 3600000 || end != 7200000 || step != 14400 {
+		t.Errorf("unexpected range: start=%d end=%d step=%d", start, end, step)
+	}
+
+	if _, _, _, err := resolveRange(&interfaces.MetricQueryRange{Start: 1000, End: 1000 + 20000000, Step: 1000}, now); err == nil {
+		t.Errorf("expected error when points exceed limit")
+	}
+	if _, _, _, err := resolveRange(&interfaces.MetricQueryRange{Start: 2000, End: 1000}, now); err == nil {
+		t.Errorf("expected error when start >= end")
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package postgresql provides PostgreSQL database connector implementation.
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	_ "github.com/lib/pq"
+	"github.com/mitchellh/mapstructure"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/connectors"
+)
+
+type postgresqlConfig struct {
+	Host     string         `mapstructure:"host"`
+	Port     int            `mapstructure:"port"`
+	Username string         `mapstructure:"username"`
+	Password string         `mapstructure:"password"`
+	Database string         `mapstructure:"database"`
+	Schemas  []string       `mapstructure:"schemas"`
+	Options  map[string]any `mapstructure:"options"`
+}
+
+var (
+	SYSTEM_SCHEMAS = []string{
+		"information_schema",
+		"pg_catalog",
+		"pg_toast",
+	}
+	SYSTEM_SCHEMAS_MAP = map[string]bool{
+		"information_schema": true,
+		"pg_catalog":         true,
+		"pg_toast":           true,
+	}
+)
+
+const (
+	// IDENTIFIER_MAX_LENGTH PostgreSQL 标识符（库名、模式名）最大长度
+	IDENTIFIER_MAX_LENGTH = 63
+	// PORT_MIN 有效端口最小值
+	PORT_MIN = 1
+	// PORT_MAX 有效端口最大值
+	PORT_MAX = 65535
+)
+
+// PostgreSQLConnector implements TableConnector for PostgreSQL.
+type PostgreSQLConnector struct {
+	enabled bool
+
+	config *postgresqlConfig
+
+	connected bool
+	db        *sql.DB
+}
+
+// NewPostgreSQLConnector 创建 PostgreSQL connector 构建器
+func NewPostgreSQLConnector() connectors.TableConnector {
+	return &PostgreSQLConnector{}
+}
+
+// GetType returns the data source type.
+func (c *PostgreSQLConnector) GetType() string {
+	return "postgresql"
+}
+
+// GetName returns the connector name.
+func (c *PostgreSQLConnector) GetName() string {
+	return "postgresql"
+}
+
+// GetMode returns the connector mode.
+func (c *PostgreSQLConnector) GetMode() string {
+	return interfaces.ConnectorModeLocal
+}
+
+// GetCategory returns the connector category.
+func (c *PostgreSQLConnector) GetCategory() string {
+	return interfaces.ConnectorCategoryTable
+}
+
+// GetEnabled returns the enabled status.
+func (c *PostgreSQLConnector) GetEnabled() bool {
+	return c.enabled
+}
+
+// SetEnabled sets the enabled status.
+func (c *PostgreSQLConnector) SetEnabled(enabled bool) {
+	c.enabled = enabled
+}
+
+// GetSensitiveFields returns the sensitive fields for PostgreSQL connector.
+func (c *PostgreSQLConnector) GetSensitiveFields() []string {
+	return []string{"password"}
+}
+
+// GetFieldConfig returns the field configuration for PostgreSQL connector.
+func (c *PostgreSQLConnector) GetFieldConfig() map[string]interfaces.ConnectorFieldConfig {
+	return map[string]interfaces.ConnectorFieldConfig{
+		"host":     {Name: "主机地址", Type: "string", Description: "PostgreSQL 服务器主机地址", Required: true, Encrypted: false},
+		"port":     {Name: "端口号", Type: "integer", Description: "PostgreSQL 服务器端口", Required: true, Encrypted: false},
+		"username": {Name: "用户名", Type: "string", Description: "数据库用户名", Required: true, Encrypted: false},
+		"password": {Name: "密码", Type: "string", Description: "数据库密码", Required: true, Encrypted: true},
+		"database": {Name: "数据库名称", Type: "string", Description: "要连接的数据库名称", Required: true, Encrypted: false},
+		"schemas":  {Name: "模式列表", Type: "array", Description: "模式名称列表（可选，为空则发现库内全部用户模式）", Required: false, Encrypted: false},
+		"options":  {Name: "连接参数", Type: "object", Description: "连接参数（如 sslmode, connect_timeout 等）", Required: false, Encrypted: false},
+	}
+}
+
+// New creates a new PostgreSQL connector.
+// PostgreSQL 连接必须指定数据库，Schemas 为可选字段，不指定时发现库内全部用户模式。
+func (c *PostgreSQLConnector) New(cfg interfaces.ConnectorConfig) (connectors.Connector, error) {
+	var pCfg postgresqlConfig
+	if err := mapstructure.Decode(cfg, &pCfg); err != nil {
+		return nil, fmt.Errorf("failed to decode postgresql config: %w", err)
+	}
+
+	if pCfg.Host == "" || pCfg.Port == 0 || pCfg.Username == "" || pCfg.Password == "" || pCfg.Database == "" {
+		return nil, fmt.Errorf("postgresql connector config is incomplete")
+	}
+
+	// 验证端口号范围
+	if pCfg.Port < PORT_MIN || pCfg.Port > PORT_MAX {
+		return nil, fmt.Errorf("port %d is out of valid range (%d-%d)", pCfg.Port, PORT_MIN, PORT_MAX)
+	}
+
+	// 验证数据库与模式名称长度（PostgreSQL 标识符最大 63 字节）
+	if len(pCfg.Database) > IDENTIFIER_MAX_LENGTH {
+		return nil, fmt.Errorf("database name '%s' exceeds maximum length of %d characters", pCfg.Database, IDENTIFIER_MAX_LENGTH)
+	}
+	for _, schema := range pCfg.Schemas {
+		if len(schema) > IDENTIFIER_MAX_LENGTH {
+			return nil, fmt.Errorf("schema name '%s' exceeds maximum length of %d characters", schema, IDENTIFIER_MAX_LENGTH)
+		}
+	}
+
+	return &PostgreSQLConnector{
+		config: &pCfg,
+	}, nil
+}
+
+// Connect establishes connection to PostgreSQL database.
+func (c *PostgreSQLConnector) Connect(ctx context.Context) error {
+	if c.connected {
+		return nil
+	}
+
+	// Build DSN
+	values := url.Values{}
+	values.Set("sslmode", "disable")
+
+	// Apply options
+	for k, v := range c.config.Options {
+		values.Set(k, fmt.Sprintf("%v", v))
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.config.Username, c.config.Password),
+		Host:     c.config.Host + ":" + strconv.Itoa(c.config.Port),
+		Path:     "/" + c.config.Database,
+		RawQuery: values.Encode(),
+	}
+
+	db, err := sql.Open("postgres", dsn.String())
+	if err != nil {
+		return err
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return err
+	}
+
+	c.db = db
+	c.connected = true
+
+	return nil
+}
+
+// Close closes the database connection.
+func (c *PostgreSQLConnector) Close(ctx context.Context) error {
+	if c.db != nil {
+		err := c.db.Close()
+		c.connected = false
+		c.db = nil
+		return err
+	}
+	return nil
+}
+
+// Ping checks the database connection.
+func (c *PostgreSQLConnector) Ping(ctx context.Context) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	return c.db.PingContext(ctx)
+}
+
+// TestConnection tests the connection to PostgreSQL database.
+func (c *PostgreSQLConnector) TestConnection(ctx context.Context) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	// 如果配置了 schemas 列表，验证这些模式是否存在
+	if len(c.config.Schemas) > 0 {
+		if err := c.validateSchemas(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateSchemas 验证配置的模式是否存在
+func (c *PostgreSQLConnector) validateSchemas(ctx context.Context) error {
+	rows, err := c.db.QueryContext(ctx, "SELECT nspname FROM pg_catalog.pg_namespace")
+	if err != nil {
+		return fmt.Errorf("failed to list schemas: %w", err)
+	}
+	defer rows.Close()
+
+	existingSchemas := make(map[string]bool)
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			return fmt.Errorf("failed to scan schema name: %w", err)
+		}
+		existingSchemas[schema] = true
+	}
+
+	// 检查配置的模式是否都存在
+	var notFoundSchemas []string
+	for _, schema := range c.config.Schemas {
+		if !existingSchemas[schema] {
+			notFoundSchemas = append(notFoundSchemas, schema)
+		}
+	}
+
+	if len(notFoundSchemas) > 0 {
+		return fmt.Errorf("schemas not found: %v", notFoundSchemas)
+	}
+
+	return nil
+}
@@ -0,0 +1,529 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+
+	"vega-backend/interfaces"
+)
+
+// psql PostgreSQL 使用 $n 占位符
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+// relkind -> TableType；分区父表与外部表按普通表处理
+var relkindToTableType = map[string]string{
+	"r": "table",
+	"p": "table",
+	"f": "table",
+	"v": "view",
+	"m": "materialized_view",
+}
+
+// 外键动作编码，见 pg_constraint.confupdtype / confdeltype
+var fkActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// ListDatabases 列出实例下所有可连接的用户数据库（排除模板库）。
+func (c *PostgreSQLConnector) ListDatabases(ctx context.Context) ([]string, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := c.db.QueryContext(ctx,
+		"SELECT datname FROM pg_catalog.pg_database WHERE NOT datistemplate AND datallowconn ORDER BY datname")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+	defer rows.Close()
+
+	var databases []string
+	for rows.Next() {
+		var db string
+		if err := rows.Scan(&db); err != nil {
+			return nil, fmt.Errorf("failed to scan database name: %w", err)
+		}
+		databases = append(databases, db)
+	}
+	return databases, nil
+}
+
+// ListTables 返回已连接数据库中的表、视图与物化视图。
+// 如果 Config.Schemas 非空，只列出这些模式下的对象；否则遍历所有用户模式。
+// 返回的 TableMeta.Database 字段为所属模式名，与表名组成 "schema.table" 作为资源标识。
+func (c *PostgreSQLConnector) ListTables(ctx context.Context) ([]*interfaces.TableMeta, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	builder := psql.Select(
+		"n.nspname",
+		"c.relname",
+		"c.relkind",
+		"obj_description(c.oid, 'pg_class')",
+		"c.reltuples::bigint",
+		"pg_total_relation_size(c.oid)",
+		"pg_indexes_size(c.oid)",
+		"pg_get_userbyid(c.relowner)",
+	).From("pg_catalog.pg_class c").
+		Join("pg_catalog.pg_namespace n ON n.oid = c.relnamespace").
+		Where(sq.Eq{"c.relkind": []string{"r", "p", "f", "v", "m"}}).
+		Where("NOT c.relispartition")
+
+	// Filter schemas
+	if len(c.config.Schemas) > 0 {
+		builder = builder.Where(sq.Eq{"n.nspname": c.config.Schemas})
+	} else {
+		builder = builder.Where(sq.NotEq{"n.nspname": SYSTEM_SCHEMAS}).
+			Where(sq.NotLike{"n.nspname": "pg\\_temp\\_%"}).
+			Where(sq.NotLike{"n.nspname": "pg\\_toast\\_temp\\_%"})
+	}
+
+	query, args, err := builder.OrderBy("n.nspname", "c.relname").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build list tables query: %w", err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []*interfaces.TableMeta
+	for rows.Next() {
+		var schema, name, relkind string
+		var description, owner sql.NullString
+		var rowCount, totalSize, indexSize sql.NullInt64
+
+		if err := rows.Scan(
+			&schema,
+			&name,
+			&relkind,
+			&description,
+			&rowCount,
+			&totalSize,
+			&indexSize,
+			&owner,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan table info: %w", err)
+		}
+
+		meta := &interfaces.TableMeta{
+			Name:        name,
+			TableType:   relkindToTableType[relkind],
+			Description: description.String,
+			Database:    schema,
+		}
+
+		// Populate Properties
+		meta.Properties = make(map[string]any)
+		meta.Properties["relkind"] = relkind
+		meta.Properties["owner"] = owner.String
+		// reltuples 为 -1 表示从未 ANALYZE
+		meta.Properties["row_count"] = max(rowCount.Int64, 0)
+		meta.Properties["data_length"] = totalSize.Int64 - indexSize.Int64
+		meta.Properties["index_length"] = indexSize.Int64
+
+		tables = append(tables, meta)
+	}
+
+	return tables, nil
+}
+
+// GetTableMeta returns metadata for a specific table.
+// table.Database 为模式名
+func (c *PostgreSQLConnector) GetTableMeta(ctx context.Context, table *interfaces.TableMeta) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	// 1. 获取表基本信息（类型、注释、行数、视图定义）
+	if err := c.fetchTableStatus(ctx, table); err != nil {
+		return fmt.Errorf("failed to fetch table status: %w", err)
+	}
+
+	// 2. 获取字段信息
+	if err := c.fetchColumns(ctx, table); err != nil {
+		return fmt.Errorf("failed to fetch columns: %w", err)
+	}
+
+	// 3. 获取索引信息（同时确定主键）
+	if err := c.fetchIndexes(ctx, table); err != nil {
+		return fmt.Errorf("failed to fetch indexes: %w", err)
+	}
+
+	// 4. 获取外键信息
+	if err := c.fetchForeignKeys(ctx, table); err != nil {
+		return fmt.Errorf("failed to fetch foreign keys: %w", err)
+	}
+
+	return nil
+}
+
+// fetchTableStatus retrieves table status from pg_class.
+func (c *PostgreSQLConnector) fetchTableStatus(ctx context.Context, table *interfaces.TableMeta) error {
+	query := `
+		SELECT c.relkind,
+			obj_description(c.oid, 'pg_class'),
+			c.reltuples::bigint,
+			pg_total_relation_size(c.oid),
+			pg_indexes_size(c.oid),
+			pg_get_userbyid(c.relowner),
+			CASE WHEN c.relkind IN ('v', 'm') THEN pg_get_viewdef(c.oid, true) END
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2`
+
+	var relkind, description, owner, definition sql.NullString
+	var rowCount, totalSize, indexSize sql.NullInt64
+
+	row := c.db.QueryRowContext(ctx, query, table.Database, table.Name)
+	if err := row.Scan(
+		&relkind,
+		&description,
+		&rowCount,
+		&totalSize,
+		&indexSize,
+		&owner,
+		&definition,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	// 初始化 Properties map
+	if table.Properties == nil {
+		table.Properties = make(map[string]any)
+	}
+
+	table.TableType = relkindToTableType[relkind.String]
+	if table.TableType == "" {
+		table.TableType = "table"
+	}
+
+	table.Properties["relkind"] = relkind.String
+	table.Properties["owner"] = owner.String
+	table.Properties["row_count"] = max(rowCount.Int64, 0)
+	table.Properties["data_length"] = totalSize.Int64 - indexSize.Int64
+	table.Properties["index_length"] = indexSize.Int64
+	if definition.Valid {
+		table.Properties["definition"] = definition.String
+	}
+	table.Description = description.String
+
+	return nil
+}
+
+// fetchColumns retrieves column metadata from pg_attribute.
+// 不使用 information_schema.columns，因为其不包含物化视图的字段。
+func (c *PostgreSQLConnector) fetchColumns(ctx context.Context, table *interfaces.TableMeta) error {
+	query := `
+		SELECT a.attname,
+			format_type(a.atttypid, NULL),
+			format_type(a.atttypid, a.atttypmod),
+			a.atttypmod,
+			NOT a.attnotnull,
+			pg_get_expr(d.adbin, d.adrelid),
+			col_description(a.attrelid, a.attnum),
+			co.collname,
+			a.attnum
+		FROM pg_catalog.pg_attribute a
+		JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		LEFT JOIN pg_catalog.pg_collation co ON co.oid = a.attcollation AND a.attcollation <> 0
+		WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`
+
+	rows, err := c.db.QueryContext(ctx, query, table.Database, table.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var columns []interfaces.ColumnMeta
+	for rows.Next() {
+		var name, dataType, columnType string
+		var typmod, position int
+		var nullable bool
+		var columnDefault, description, collation sql.NullString
+
+		if err := rows.Scan(
+			&name,
+			&dataType,
+			&columnType,
+			&typmod,
+			&nullable,
+			&columnDefault,
+			&description,
+			&collation,
+			&position,
+		); err != nil {
+			return err
+		}
+
+		col := interfaces.ColumnMeta{
+			Name:            name,
+			Type:            MapType(dataType),
+			OrigType:        columnType,
+			Nullable:        nullable,
+			DefaultValue:    columnDefault.String,
+			Description:     description.String,
+			Collation:       collation.String,
+			OrdinalPosition: position,
+		}
+		applyTypmod(&col, dataType, typmod)
+		columns = append(columns, col)
+	}
+
+	table.Columns = columns
+	return nil
+}
+
+// applyTypmod 从 atttypmod 解析长度、精度与小数位
+func applyTypmod(col *interfaces.ColumnMeta, dataType string, typmod int) {
+	if typmod < 0 {
+		return
+	}
+	switch strings.TrimSuffix(dataType, "[]") {
+	case "character varying", "character", "bit", "bit varying":
+		col.CharMaxLen = typmod - 4
+		if strings.HasPrefix(dataType, "bit") {
+			col.CharMaxLen = typmod
+		}
+	case "numeric":
+		col.NumPrecision = ((typmod - 4) >> 16) & 0xffff
+		col.NumScale = (typmod - 4) & 0xffff
+	case "timestamp without time zone", "timestamp with time zone",
+		"time without time zone", "time with time zone", "interval":
+		col.DatetimePrecision = typmod & 0xffff
+	}
+}
+
+// fetchIndexes retrieves index metadata from pg_index, and fills primary keys.
+func (c *PostgreSQLConnector) fetchIndexes(ctx context.Context, table *interfaces.TableMeta) error {
+	query := `
+		SELECT ic.relname,
+			COALESCE(a.attname, pg_get_indexdef(i.indexrelid, k.ord::int, true)),
+			i.indisunique,
+			i.indisprimary
+		FROM pg_catalog.pg_index i
+		JOIN pg_catalog.pg_class tc ON tc.oid = i.indrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = tc.relnamespace
+		JOIN pg_catalog.pg_class ic ON ic.oid = i.indexrelid
+		CROSS JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord)
+		LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+		WHERE n.nspname = $1 AND tc.relname = $2 AND k.ord <= i.indnkeyatts
+		ORDER BY ic.relname, k.ord`
+
+	rows, err := c.db.QueryContext(ctx, query, table.Database, table.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	indexMap := make(map[string]*interfaces.IndexInfo)
+	var indexNames []string
+
+	for rows.Next() {
+		var indexName, columnName string
+		var unique, primary bool
+
+		if err := rows.Scan(
+			&indexName,
+			&columnName,
+			&unique,
+			&primary,
+		); err != nil {
+			return err
+		}
+
+		if idx, ok := indexMap[indexName]; ok {
+			idx.Columns = append(idx.Columns, columnName)
+		} else {
+			indexMap[indexName] = &interfaces.IndexInfo{
+				Name:    indexName,
+				Columns: []string{columnName},
+				Unique:  unique,
+				Primary: primary,
+			}
+			indexNames = append(indexNames, indexName)
+		}
+	}
+
+	var indices []interfaces.IndexInfo
+	var pkColumns []string
+	for _, name := range indexNames {
+		idx := indexMap[name]
+		indices = append(indices, *idx)
+		if idx.Primary {
+			pkColumns = idx.Columns
+		}
+	}
+	table.Indices = indices
+	table.PKs = pkColumns
+
+	// 与 MariaDB 的 COLUMN_KEY 保持一致：PRI 主键、UNI 唯一索引首列、MUL 普通索引首列
+	columnKeys := make(map[string]string)
+	for _, pk := range pkColumns {
+		columnKeys[pk] = "PRI"
+	}
+	for _, idx := range indices {
+		first := idx.Columns[0]
+		if columnKeys[first] != "" {
+			continue
+		}
+		if idx.Unique && len(idx.Columns) == 1 {
+			columnKeys[first] = "UNI"
+		} else {
+			columnKeys[first] = "MUL"
+		}
+	}
+	for i := range table.Columns {
+		table.Columns[i].ColumnKey = columnKeys[table.Columns[i].Name]
+	}
+	return nil
+}
+
+// fetchForeignKeys retrieves foreign key metadata from pg_constraint.
+func (c *PostgreSQLConnector) fetchForeignKeys(ctx context.Context, table *interfaces.TableMeta) error {
+	query := `
+		SELECT con.conname,
+			a.attname,
+			rn.nspname,
+			rc.relname,
+			ra.attname,
+			con.confdeltype,
+			con.confupdtype
+		FROM pg_catalog.pg_constraint con
+		JOIN pg_catalog.pg_class c ON c.oid = con.conrelid
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_catalog.pg_class rc ON rc.oid = con.confrelid
+		JOIN pg_catalog.pg_namespace rn ON rn.oid = rc.relnamespace
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+		JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_catalog.pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refattnum
+		WHERE con.contype = 'f' AND n.nspname = $1 AND c.relname = $2
+		ORDER BY con.conname, k.ord`
+
+	rows, err := c.db.QueryContext(ctx, query, table.Database, table.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	fkMap := make(map[string]*interfaces.ForeignKeyInfo)
+	var fkNames []string
+
+	for rows.Next() {
+		var constraintName, columnName, refSchema, refTable, refColumnName string
+		var onDelete, onUpdate string
+
+		if err := rows.Scan(
+			&constraintName,
+			&columnName,
+			&refSchema,
+			&refTable,
+			&refColumnName,
+			&onDelete,
+			&onUpdate,
+		); err != nil {
+			return err
+		}
+
+		if fk, ok := fkMap[constraintName]; ok {
+			fk.Columns = append(fk.Columns, columnName)
+			fk.RefColumns = append(fk.RefColumns, refColumnName)
+		} else {
+			// 与资源标识保持一致，跨模式引用时使用 "schema.table"
+			ref := refTable
+			if refSchema != table.Database {
+				ref = refSchema + "." + refTable
+			}
+			fkMap[constraintName] = &interfaces.ForeignKeyInfo{
+				Name:       constraintName,
+				Columns:    []string{columnName},
+				RefTable:   ref,
+				RefColumns: []string{refColumnName},
+				OnDelete:   fkActions[onDelete],
+				OnUpdate:   fkActions[onUpdate],
+			}
+			fkNames = append(fkNames, constraintName)
+		}
+	}
+
+	var fks []interfaces.ForeignKeyInfo
+	for _, name := range fkNames {
+		fks = append(fks, *fkMap[name])
+	}
+	table.ForeignKeys = fks
+	return nil
+}
+
+// GetMetadata returns the metadata for the catalog.
+func (c *PostgreSQLConnector) GetMetadata(ctx context.Context) (map[string]any, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	// 包含基础信息、字符集、时区、只读以及复制相关信息
+	targetVars := []string{
+		"server_version",
+		"server_encoding",
+		"lc_collate",
+		"lc_ctype",
+		"TimeZone",
+		"max_connections",
+		"default_transaction_read_only",
+		// Replication related
+		"wal_level",
+		"hot_standby",
+		"cluster_name",
+	}
+
+	rows, err := c.db.QueryContext(ctx,
+		"SELECT name, setting FROM pg_catalog.pg_settings WHERE name = ANY($1)", pq.Array(targetVars))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	metadata := make(map[string]any)
+	for rows.Next() {
+		var varName, varValue string
+		if err := rows.Scan(&varName, &varValue); err == nil {
+			metadata[varName] = varValue
+		}
+	}
+
+	var version string
+	if err := c.db.QueryRowContext(ctx, "SELECT version()").Scan(&version); err == nil {
+		metadata["version"] = version
+	}
+
+	// Infer Cluster Mode：处于恢复模式即为备库
+	metadata["cluster_mode"] = "standalone" // Default
+	var inRecovery bool
+	if err := c.db.QueryRowContext(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery); err == nil && inRecovery {
+		metadata["cluster_mode"] = "standby"
+	}
+
+	return metadata, nil
+}
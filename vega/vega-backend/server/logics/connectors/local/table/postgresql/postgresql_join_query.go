@@ -0,0 +1,270 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package postgresql
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"vega-backend/interfaces"
+)
+
+// qualTable 返回带双引号限定的表名；SourceIdentifier 为 "schema.table"
+func qualTable(res *interfaces.Resource) string {
+	ident := res.SourceIdentifier
+	if res.Database != "" && !strings.Contains(ident, ".") {
+		ident = res.Database + "." + ident
+	}
+	parts := strings.SplitN(ident, ".", 2)
+	for i, p := range parts {
+		parts[i] = quoteIdent(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// ExecuteJoinQuery 执行多表 JOIN 查询；支持 keyset 游标或 OFFSET/LIMIT
+func (c *PostgreSQLConnector) ExecuteJoinQuery(ctx context.Context, catalog *interfaces.Catalog, params *interfaces.JoinQueryParams) (*interfaces.QueryResult, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(params.Resources) == 0 {
+		return nil, fmt.Errorf("resources cannot be empty")
+	}
+
+	// 构建 alias -> 限定表名 映射，保持 Resources 顺序以确定驱动表
+	aliases := make([]string, 0, len(params.Resources))
+	aliasToTable := make(map[string]string)
+	aliasToResource := make(map[string]*interfaces.Resource)
+	for i, res := range params.Resources {
+		alias := params.ResourceIDToAlias[res.ID]
+		if alias == "" {
+			alias = fmt.Sprintf("t%d", i+1)
+		}
+		aliases = append(aliases, alias)
+		aliasToTable[alias] = qualTable(res)
+		aliasToResource[alias] = res
+	}
+
+	// 合并所有资源的 fieldMap（带别名前缀）
+	fieldMap := make(map[string]*interfaces.Property)
+	for alias, res := range aliasToResource {
+		for _, prop := range res.SchemaDefinition {
+			key := alias + "." + prop.Name
+			fieldMap[key] = prop
+			fieldMap[prop.Name] = prop // 无前缀也支持
+		}
+	}
+
+	var filterCond sq.Sqlizer
+	if params.ActualFilterCond != nil {
+		var err error
+		filterCond, err = c.ConvertFilterCondition(ctx, params.ActualFilterCond, fieldMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &interfaces.QueryResult{Rows: make([]map[string]any, 0)}
+
+	// 构建 SELECT 字段
+	selectFields := params.OutputFields
+	if len(selectFields) == 0 {
+		selectFields = []string{"*"}
+	}
+	selectExprs := make([]string, 0, len(selectFields))
+	for _, f := range selectFields {
+		if f == "*" {
+			selectExprs = append(selectExprs, "*")
+		} else {
+			selectExprs = append(selectExprs, quoteColumnName(f)+" AS "+quoteIdent(f))
+		}
+	}
+
+	// 构建 FROM 和 JOIN
+	from, err := buildFromClause(aliases, aliasToTable, params.Joins)
+	if err != nil {
+		return nil, err
+	}
+	builder := psql.Select(selectExprs...).From(from)
+
+	// WHERE：filter + keyset
+	var whereConds []sq.Sqlizer
+	if filterCond != nil {
+		whereConds = append(whereConds, filterCond)
+	}
+	if params.CursorEncoded != "" {
+		keysetCond, err := buildKeysetCondition(params.Sort, params.CursorEncoded)
+		if err != nil {
+			return nil, err
+		}
+		if keysetCond != nil {
+			whereConds = append(whereConds, keysetCond)
+		}
+	}
+	if len(whereConds) > 0 {
+		builder = builder.Where(sq.And(whereConds))
+	}
+
+	// ORDER BY
+	sortFields := params.Sort
+	if len(sortFields) == 0 {
+		// 默认补主键
+		if pk := getFirstPK(params.Resources[0]); pk != "" {
+			sortFields = []*interfaces.SortField{{Field: pk, Direction: interfaces.ASC_DIRECTION}}
+		}
+	}
+	for _, sf := range sortFields {
+		dir := "ASC"
+		if sf.Direction == interfaces.DESC_DIRECTION {
+			dir = "DESC"
+		}
+		builder = builder.OrderBy(quoteColumnName(sf.Field) + " " + dir)
+	}
+
+	// NeedTotal: COUNT
+	if params.NeedTotal {
+		countBuilder := psql.Select("COUNT(1)").From(from)
+		if len(whereConds) > 0 {
+			countBuilder = countBuilder.Where(sq.And(whereConds))
+		}
+		cq, ca, err := countBuilder.ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("build count query: %w", err)
+		}
+		var total int64
+		if err := c.db.QueryRowContext(ctx, cq, ca...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("count query: %w", err)
+		}
+		result.Total = total
+	}
+
+	// LIMIT / OFFSET
+	if params.CursorEncoded == "" && params.Offset > 0 {
+		builder = builder.Offset(uint64(params.Offset))
+	}
+	if params.Limit > 0 {
+		builder = builder.Limit(uint64(params.Limit))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+	logger.Debugf("join query: %s, args: %v", query, args)
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	cols, err := scanRows(rows, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Columns = cols
+
+	return result, nil
+}
+
+// buildFromClause 构建 FROM 子句；无 JOIN 的多表查询生成 "FROM t1, t2"
+func buildFromClause(aliases []string, aliasToTable map[string]string, joins []*interfaces.JoinSpec) (string, error) {
+	first := aliases[0]
+	if len(joins) == 0 {
+		parts := make([]string, 0, len(aliases))
+		for _, a := range aliases {
+			parts = append(parts, aliasToTable[a]+" AS "+quoteIdent(a))
+		}
+		return strings.Join(parts, ", "), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(aliasToTable[first] + " AS " + quoteIdent(first))
+
+	// 按 JOIN 顺序添加右表
+	joined := map[string]bool{first: true}
+	for _, j := range joins {
+		rt, ok := aliasToTable[j.RightTableAlias]
+		if !ok {
+			return "", fmt.Errorf("join right alias not in tables: %s", j.RightTableAlias)
+		}
+		if joined[j.RightTableAlias] {
+			continue
+		}
+		onParts := make([]string, 0, len(j.On))
+		for _, on := range j.On {
+			onParts = append(onParts, quoteColumnName(on.LeftField)+" = "+quoteColumnName(on.RightField))
+		}
+
+		var joinType string
+		switch strings.ToUpper(j.Type) {
+		case "LEFT":
+			joinType = "LEFT JOIN"
+		case "RIGHT":
+			joinType = "RIGHT JOIN"
+		case "FULL":
+			joinType = "FULL JOIN"
+		default:
+			joinType = "JOIN"
+		}
+		sb.WriteString(" " + joinType + " " + rt + " AS " + quoteIdent(j.RightTableAlias) +
+			" ON " + strings.Join(onParts, " AND "))
+		joined[j.RightTableAlias] = true
+	}
+	return sb.String(), nil
+}
+
+func getFirstPK(res *interfaces.Resource) string {
+	if res.SourceMetadata == nil {
+		return ""
+	}
+	if v, ok := res.SourceMetadata["primary_keys"]; ok {
+		if arr, ok := v.([]any); ok && len(arr) > 0 {
+			if s, ok := arr[0].(string); ok {
+				return s
+			}
+		}
+		if arr, ok := v.([]string); ok && len(arr) > 0 {
+			return arr[0]
+		}
+	}
+	return ""
+}
+
+// buildKeysetCondition 从编码游标构建 (sort_cols) > (cursor_vals)
+func buildKeysetCondition(sort []*interfaces.SortField, cursorEncoded string) (sq.Sqlizer, error) {
+	if len(sort) == 0 {
+		return nil, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(cursorEncoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode cursor: %w", err)
+	}
+	var vals []any
+	if err := json.Unmarshal(decoded, &vals); err != nil {
+		return nil, fmt.Errorf("unmarshal cursor: %w", err)
+	}
+	if len(vals) != len(sort) {
+		return nil, fmt.Errorf("cursor length mismatch")
+	}
+	// 构建 (col1, col2, ...) > (v1, v2, ...)
+	cols := make([]string, len(sort))
+	for i, sf := range sort {
+		cols[i] = quoteColumnName(sf.Field)
+	}
+	placeholders := make([]string, len(vals))
+	for i := range vals {
+		placeholders[i] = "?"
+	}
+	return sq.Expr("("+strings.Join(cols, ",")+") > ("+strings.Join(placeholders, ",")+")", vals...), nil
+}
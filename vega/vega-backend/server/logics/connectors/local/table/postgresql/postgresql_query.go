@@ -0,0 +1,154 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"vega-backend/interfaces"
+)
+
+// convertValue converts []byte to string for PostgreSQL driver compatibility
+// lib/pq 对 numeric、json 等类型返回 []byte；bytea 也以 []byte 返回，统一转为字符串便于序列化
+func convertValue(v any) any {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
+
+func (c *PostgreSQLConnector) ExecuteQuery(ctx context.Context, resource *interfaces.Resource,
+	params *interfaces.ResourceDataQueryParams) (*interfaces.QueryResult, error) {
+
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	fieldMap := map[string]*interfaces.Property{}
+	for _, prop := range resource.SchemaDefinition {
+		fieldMap[prop.Name] = prop
+	}
+
+	var condition sq.Sqlizer
+	var err error
+	if params.ActualFilterCond != nil {
+		condition, err = c.ConvertFilterCondition(ctx, params.ActualFilterCond, fieldMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	table := qualTable(resource)
+	result := &interfaces.QueryResult{
+		Rows: make([]map[string]any, 0),
+	}
+
+	if params.NeedTotal {
+		countBuilder := psql.Select("COUNT(1)").
+			From(table)
+
+		if condition != nil {
+			countBuilder = countBuilder.Where(condition)
+		}
+
+		query, args, err := countBuilder.ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build query: %w", err)
+		}
+
+		logger.Debugf("count query: %s, args: %v", query, args)
+
+		var total int64
+		row := c.db.QueryRowContext(ctx, query, args...)
+		if err := row.Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to scan total: %w", err)
+		}
+
+		result.Total = total
+	}
+
+	fields := []string{"*"}
+	if len(params.OutputFields) > 0 {
+		fields = make([]string, 0, len(params.OutputFields))
+		for _, f := range params.OutputFields {
+			fields = append(fields, quoteIdent(f))
+		}
+	}
+
+	builder := psql.Select(fields...).
+		From(table)
+
+	if condition != nil {
+		builder = builder.Where(condition)
+	}
+	for _, sf := range params.Sort {
+		dir := "ASC"
+		if sf.Direction == interfaces.DESC_DIRECTION {
+			dir = "DESC"
+		}
+		builder = builder.OrderBy(quoteIdent(sf.Field) + " " + dir)
+	}
+	if params.Offset > 0 {
+		builder = builder.Offset(uint64(params.Offset))
+	}
+	if params.Limit > 0 {
+		builder = builder.Limit(uint64(params.Limit))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	logger.Debugf("query: %s, args: %v", query, args)
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := scanRows(rows, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Columns = columns
+
+	return result, nil
+}
+
+// scanRows 将结果集逐行转为 map 追加到 result.Rows
+func scanRows(rows *sql.Rows, result *interfaces.QueryResult) ([]string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		valuePtrs := make([]any, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]any)
+		for i, col := range columns {
+			row[col] = convertValue(values[i])
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	return columns, rows.Err()
+}
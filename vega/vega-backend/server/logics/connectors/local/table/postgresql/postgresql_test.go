@@ -0,0 +1,266 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package postgresql
+
+import (
+	"context"
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/filter_condition"
+)
+
+func TestMapType(t *testing.T) {
+	tests := []struct {
+		nativeType string
+		expected   string
+	}{
+		{"integer", interfaces.DataType_Integer},
+		{"BIGINT", interfaces.DataType_Integer},
+		{" int8 ", interfaces.DataType_Integer},
+		{"oid", interfaces.DataType_UnsignedInteger},
+		{"double precision", interfaces.DataType_Float},
+		{"numeric", interfaces.DataType_Decimal},
+		{"character varying", interfaces.DataType_String},
+		{"\"char\"", interfaces.DataType_String},
+		{"text", interfaces.DataType_Text},
+		{"timestamp with time zone", interfaces.DataType_Datetime},
+		{"timetz", interfaces.DataType_Time},
+		{"date", interfaces.DataType_Date},
+		{"inet", interfaces.DataType_Ip},
+		{"bool", interfaces.DataType_Boolean},
+		{"bytea", interfaces.DataType_Binary},
+		{"jsonb", interfaces.DataType_Json},
+		{"point", interfaces.DataType_Point},
+		{"geometry", interfaces.DataType_Shape},
+		{"vector", interfaces.DataType_Vector},
+		{"integer[]", interfaces.DataType_Other},
+		{"tsvector", interfaces.DataType_Other},
+	}
+	for _, tt := range tests {
+		if got := MapType(tt.nativeType); got != tt.expected {
+			t.Errorf("MapType(%q) = %s, want %s", tt.nativeType, got, tt.expected)
+		}
+	}
+}
+
+func TestApplyTypmod(t *testing.T) {
+	tests := []struct {
+		dataType string
+		typmod   int
+		expected interfaces.ColumnMeta
+	}{
+		{"character varying", 68, interfaces.ColumnMeta{CharMaxLen: 64}},
+		{"character[]", 14, interfaces.ColumnMeta{CharMaxLen: 10}},
+		{"bit varying", 8, interfaces.ColumnMeta{CharMaxLen: 8}},
+		{"numeric", (10<<16 | 2) + 4, interfaces.ColumnMeta{NumPrecision: 10, NumScale: 2}},
+		{"timestamp with time zone", 3, interfaces.ColumnMeta{DatetimePrecision: 3}},
+		{"numeric", -1, interfaces.ColumnMeta{}},
+		{"integer", 4, interfaces.ColumnMeta{}},
+	}
+	for _, tt := range tests {
+		col := interfaces.ColumnMeta{}
+		applyTypmod(&col, tt.dataType, tt.typmod)
+		if !reflect.DeepEqual(col, tt.expected) {
+			t.Errorf("applyTypmod(%s, %d) = %+v, want %+v", tt.dataType, tt.typmod, col, tt.expected)
+		}
+	}
+}
+
+func TestQuoteIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"id", `"id"`},
+		{"Order", `"Order"`},
+		{`a"b`, `"a""b"`},
+		{"o.user_id", `"o"."user_id"`},
+		{" o . user_id ", `"o"."user_id"`},
+		{"", `""`},
+	}
+	for _, tt := range tests {
+		if got := quoteColumnName(tt.name); got != tt.expected {
+			t.Errorf("quoteColumnName(%q) = %s, want %s", tt.name, got, tt.expected)
+		}
+	}
+
+	tables := []struct {
+		resource *interfaces.Resource
+		expected string
+	}{
+		{&interfaces.Resource{SourceIdentifier: "public.users"}, `"public"."users"`},
+		{&interfaces.Resource{SourceIdentifier: "users", Database: "sales"}, `"sales"."users"`},
+		{&interfaces.Resource{SourceIdentifier: "users"}, `"users"`},
+		{&interfaces.Resource{SourceIdentifier: `public.my"table`}, `"public"."my""table"`},
+		{&interfaces.Resource{SourceIdentifier: "public.a.b", Database: "sales"}, `"public"."a.b"`},
+	}
+	for _, tt := range tables {
+		if got := qualTable(tt.resource); got != tt.expected {
+			t.Errorf("qualTable(%+v) = %s, want %s", tt.resource, got, tt.expected)
+		}
+	}
+}
+
+func TestBuildFromClause(t *testing.T) {
+	aliasToTable := map[string]string{"u": `"public"."users"`, "o": `"public"."orders"`}
+
+	from, err := buildFromClause([]string{"u", "o"}, aliasToTable, nil)
+	if err != nil || from != `"public"."users" AS "u", "public"."orders" AS "o"` {
+		t.Errorf("unexpected from clause without joins: %s, %v", from, err)
+	}
+
+	joins := []*interfaces.JoinSpec{{
+		Type: "left", LeftTableAlias: "u", RightTableAlias: "o",
+		On: []interfaces.JoinOnCond{{LeftField: "u.id", RightField: "o.user_id"}},
+	}}
+	from, err = buildFromClause([]string{"u", "o"}, aliasToTable, joins)
+	expected := `"public"."users" AS "u" LEFT JOIN "public"."orders" AS "o" ON "u"."id" = "o"."user_id"`
+	if err != nil || from != expected {
+		t.Errorf("unexpected from clause with joins: %s, %v", from, err)
+	}
+
+	joins[0].RightTableAlias = "x"
+	if _, err = buildFromClause([]string{"u", "o"}, aliasToTable, joins); err == nil {
+		t.Error("expected error for unknown join alias")
+	}
+}
+
+func TestBuildKeysetCondition(t *testing.T) {
+	sort := []*interfaces.SortField{{Field: "u.id"}, {Field: "name"}}
+	cursor := base64.StdEncoding.EncodeToString([]byte(`[10,"bob"]`))
+
+	cond, err := buildKeysetCondition(sort, cursor)
+	if err != nil {
+		t.Fatalf("buildKeysetCondition failed: %v", err)
+	}
+	sql, args, _ := cond.ToSql()
+	if sql != `("u"."id","name") > (?,?)` || len(args) != 2 || args[1] != "bob" {
+		t.Errorf("unexpected keyset condition: %s %v", sql, args)
+	}
+
+	if _, err = buildKeysetCondition(sort[:1], cursor); err == nil {
+		t.Error("expected cursor length mismatch error")
+	}
+	if _, err = buildKeysetCondition(sort, "%%"); err == nil {
+		t.Error("expected cursor decode error")
+	}
+}
+
+func TestGetFirstPK(t *testing.T) {
+	tests := []struct {
+		metadata map[string]any
+		expected string
+	}{
+		{nil, ""},
+		{map[string]any{"primary_keys": []any{"id", "tenant"}}, "id"},
+		{map[string]any{"primary_keys": []string{"uid"}}, "uid"},
+		{map[string]any{"primary_keys": []any{}}, ""},
+	}
+	for _, tt := range tests {
+		if got := getFirstPK(&interfaces.Resource{SourceMetadata: tt.metadata}); got != tt.expected {
+			t.Errorf("getFirstPK(%v) = %s, want %s", tt.metadata, got, tt.expected)
+		}
+	}
+}
+
+func TestConvertFilterCondition(t *testing.T) {
+	ctx := context.Background()
+	c := &PostgreSQLConnector{}
+	fieldsMap := map[string]*interfaces.Property{
+		"name":       {Name: "name", Type: interfaces.DataType_String, OriginalName: "name"},
+		"alias":      {Name: "alias", Type: interfaces.DataType_String, OriginalName: "Alias"},
+		"age":        {Name: "age", Type: interfaces.DataType_Integer, OriginalName: "age"},
+		"tags":       {Name: "tags", Type: interfaces.DataType_String, OriginalName: "tags"},
+		"active":     {Name: "active", Type: interfaces.DataType_Boolean, OriginalName: "active"},
+		"created_at": {Name: "created_at", Type: interfaces.DataType_Datetime, OriginalName: "created_at"},
+	}
+	constCond := func(name, operation string, value any) *interfaces.FilterCondCfg {
+		return &interfaces.FilterCondCfg{Name: name, Operation: operation,
+			ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_Const, Value: value}}
+	}
+
+	tests := []struct {
+		name     string
+		cfg      *interfaces.FilterCondCfg
+		expected string
+		args     []any
+	}{
+		{"equal", constCond("name", "==", "bob"), `"name" = ?`, []any{"bob"}},
+		{"equal original name", constCond("alias", "==", "b"), `"Alias" = ?`, []any{"b"}},
+		{"not equal", constCond("age", "!=", 3), `"age" <> ?`, []any{3}},
+		{"greater", constCond("age", ">", 3), `"age" > ?`, []any{3}},
+		{"less or equal", constCond("age", "<=", 3), `"age" <= ?`, []any{3}},
+		{"in", constCond("name", "in", []any{"a", "b"}), `"name" IN (?,?)`, []any{"a", "b"}},
+		{"not in", constCond("name", "not_in", []any{"a"}), `"name" NOT IN (?)`, []any{"a"}},
+		{"like escapes wildcards", constCond("name", "like", `50%_a\b`), `"name" LIKE ?`, []any{`%50\%\_a\\b%`}},
+		{"prefix", constCond("name", "prefix", "ab"), `"name" LIKE ?`, []any{"ab%"}},
+		{"range", constCond("age", "range", []any{1, 9}), `("age" >= ? AND "age" <= ?)`, []any{1, 9}},
+		{"out range", constCond("age", "out_range", []any{1, 9}), `("age" < ? OR "age" > ?)`, []any{1, 9}},
+		{"null", constCond("name", "null", nil), `"name" IS NULL`, nil},
+		{"not null", constCond("name", "not_null", nil), `"name" IS NOT NULL`, nil},
+		{"empty", constCond("name", "empty", nil), `"name" = ?`, []any{""}},
+		{"true", constCond("active", "true", nil), `"active" = ?`, []any{true}},
+		{"regex", constCond("name", "regex", "^a.*"), `"name"::text ~ ?`, []any{"^a.*"}},
+		{"contain", constCond("tags", "contain", []any{"x"}),
+			`(? = ANY(string_to_array("tags"::text, ',')))`, []any{"x"}},
+		{"before", constCond("created_at", "before", []any{float64(3), "day"}),
+			`"created_at" < NOW() - make_interval(days => ?)`, []any{3}},
+		{"field compare", &interfaces.FilterCondCfg{Name: "name", Operation: "==",
+			ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_Field, Value: "alias"}},
+			`"name" = "Alias"`, nil},
+		{"and", &interfaces.FilterCondCfg{Operation: "and", SubConds: []*interfaces.FilterCondCfg{
+			constCond("name", "==", "bob"), constCond("age", ">", 3)}},
+			`("name" = ? AND "age" > ?)`, []any{"bob", 3}},
+		{"or", &interfaces.FilterCondCfg{Operation: "or", SubConds: []*interfaces.FilterCondCfg{
+			constCond("name", "==", "bob"), constCond("name", "null", nil)}},
+			`("name" = ? OR "name" IS NULL)`, []any{"bob"}},
+	}
+
+	for _, tt := range tests {
+		cond, err := filter_condition.NewFilterCondition(ctx, tt.cfg, fieldsMap)
+		if err != nil {
+			t.Errorf("%s: NewFilterCondition failed: %v", tt.name, err)
+			continue
+		}
+		sqlizer, err := c.ConvertFilterCondition(ctx, cond, fieldsMap)
+		if err != nil {
+			t.Errorf("%s: ConvertFilterCondition failed: %v", tt.name, err)
+			continue
+		}
+		sql, args, err := sqlizer.ToSql()
+		if err != nil {
+			t.Errorf("%s: ToSql failed: %v", tt.name, err)
+			continue
+		}
+		if sql != tt.expected {
+			t.Errorf("%s: expected sql %s, got %s", tt.name, tt.expected, sql)
+		}
+		if len(args) != len(tt.args) || (len(args) > 0 && !reflect.DeepEqual(args, tt.args)) {
+			t.Errorf("%s: expected args %v, got %v", tt.name, tt.args, args)
+		}
+	}
+}
+
+func TestConvertFilterCondition_Unsupported(t *testing.T) {
+	ctx := context.Background()
+	c := &PostgreSQLConnector{}
+	fieldsMap := map[string]*interfaces.Property{
+		"created_at": {Name: "created_at", Type: interfaces.DataType_Datetime, OriginalName: "created_at"},
+	}
+	cfg := &interfaces.FilterCondCfg{Name: "created_at", Operation: "before",
+		ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_Const, Value: []any{float64(3), "fortnight"}}}
+
+	cond, err := filter_condition.NewFilterCondition(ctx, cfg, fieldsMap)
+	if err != nil {
+		t.Fatalf("NewFilterCondition failed: %v", err)
+	}
+	if _, err = c.ConvertFilterCondition(ctx, cond, fieldsMap); err == nil {
+		t.Error("expected error for unsupported before unit")
+	}
+}
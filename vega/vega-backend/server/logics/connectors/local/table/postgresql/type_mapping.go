@@ -0,0 +1,114 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package postgresql
+
+import (
+	"strings"
+
+	"vega-backend/interfaces"
+)
+
+// TypeMapping maps PostgreSQL native types to VEGA types.
+// 同时收录 format_type 输出的 SQL 标准名称（如 "character varying"）与内部名称（如 "varchar"）。
+var TypeMapping = map[string]string{
+	// Integer types
+	"smallint":    interfaces.DataType_Integer,
+	"integer":     interfaces.DataType_Integer,
+	"bigint":      interfaces.DataType_Integer,
+	"int2":        interfaces.DataType_Integer,
+	"int4":        interfaces.DataType_Integer,
+	"int8":        interfaces.DataType_Integer,
+	"int":         interfaces.DataType_Integer,
+	"smallserial": interfaces.DataType_Integer,
+	"serial":      interfaces.DataType_Integer,
+	"bigserial":   interfaces.DataType_Integer,
+	"serial2":     interfaces.DataType_Integer,
+	"serial4":     interfaces.DataType_Integer,
+	"serial8":     interfaces.DataType_Integer,
+	"oid":         interfaces.DataType_UnsignedInteger,
+
+	// Float types
+	"real":             interfaces.DataType_Float,
+	"double precision": interfaces.DataType_Float,
+	"float4":           interfaces.DataType_Float,
+	"float8":           interfaces.DataType_Float,
+	"float":            interfaces.DataType_Float,
+
+	// Decimal types
+	"numeric": interfaces.DataType_Decimal,
+	"decimal": interfaces.DataType_Decimal,
+	"money":   interfaces.DataType_Decimal,
+
+	// String types
+	"character varying": interfaces.DataType_String,
+	"character":         interfaces.DataType_String,
+	"varchar":           interfaces.DataType_String,
+	"char":              interfaces.DataType_String,
+	"bpchar":            interfaces.DataType_String,
+	"\"char\"":          interfaces.DataType_String,
+	"name":              interfaces.DataType_String,
+	"uuid":              interfaces.DataType_String,
+	"citext":            interfaces.DataType_String,
+
+	// Text types
+	"text": interfaces.DataType_Text,
+	"xml":  interfaces.DataType_Text,
+
+	// Date/Time types
+	"date":                        interfaces.DataType_Date,
+	"timestamp without time zone": interfaces.DataType_Datetime,
+	"timestamp with time zone":    interfaces.DataType_Datetime,
+	"timestamp":                   interfaces.DataType_Datetime,
+	"timestamptz":                 interfaces.DataType_Datetime,
+	"time without time zone":      interfaces.DataType_Time,
+	"time with time zone":         interfaces.DataType_Time,
+	"time":                        interfaces.DataType_Time,
+	"timetz":                      interfaces.DataType_Time,
+
+	// Network types
+	"inet": interfaces.DataType_Ip,
+	"cidr": interfaces.DataType_Ip,
+
+	// Boolean
+	"boolean": interfaces.DataType_Boolean,
+	"bool":    interfaces.DataType_Boolean,
+
+	// Binary types
+	"bytea":       interfaces.DataType_Binary,
+	"bit":         interfaces.DataType_Binary,
+	"bit varying": interfaces.DataType_Binary,
+	"varbit":      interfaces.DataType_Binary,
+
+	// JSON
+	"json":  interfaces.DataType_Json,
+	"jsonb": interfaces.DataType_Json,
+
+	// Geometry
+	"point":    interfaces.DataType_Point,
+	"polygon":  interfaces.DataType_Shape,
+	"box":      interfaces.DataType_Shape,
+	"circle":   interfaces.DataType_Shape,
+	"path":     interfaces.DataType_Shape,
+	"line":     interfaces.DataType_Shape,
+	"lseg":     interfaces.DataType_Shape,
+	"geometry": interfaces.DataType_Shape,
+
+	// pgvector
+	"vector": interfaces.DataType_Vector,
+}
+
+// MapType returns VEGA type for PostgreSQL native type.
+// nativeType 应使用不带 typmod 的类型名（format_type(atttypid, NULL)）；数组类型统一映射为 other。
+func MapType(nativeType string) string {
+	t := strings.ToLower(strings.TrimSpace(nativeType))
+	if strings.HasSuffix(t, "[]") {
+		return interfaces.DataType_Other
+	}
+	if vegaType, ok := TypeMapping[t]; ok {
+		return vegaType
+	}
+	return interfaces.DataType_Other // default
+}
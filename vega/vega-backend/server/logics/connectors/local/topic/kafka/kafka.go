@@ -0,0 +1,254 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+// Package kafka provides Kafka topic connector implementation.
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/connectors"
+)
+
+const (
+	// 默认采样消息数，用于推断字段结构
+	defaultSampleSize = 100
+	// 连接与请求超时时间
+	defaultDialTimeout = 10 * time.Second
+
+	saslMechanismPlain       = "PLAIN"
+	saslMechanismScramSHA256 = "SCRAM-SHA-256"
+	saslMechanismScramSHA512 = "SCRAM-SHA-512"
+)
+
+type kafkaConfig struct {
+	Brokers       []string `mapstructure:"brokers"`
+	SASLMechanism string   `mapstructure:"sasl_mechanism"`
+	Username      string   `mapstructure:"username"`
+	Password      string   `mapstructure:"password"`
+	TLS           bool     `mapstructure:"tls"`
+	TopicPattern  string   `mapstructure:"topic_pattern"`
+	SampleSize    int      `mapstructure:"sample_size"`
+}
+
+// KafkaConnector implements TopicConnector for Kafka.
+type KafkaConnector struct {
+	enabled bool
+	Config  *kafkaConfig
+	client  *kafka.Client
+	dialer  *kafka.Dialer
+}
+
+// NewKafkaConnector 创建 Kafka connector 构建器
+func NewKafkaConnector() connectors.TopicConnector {
+	return &KafkaConnector{}
+}
+
+// GetType returns the data source type.
+func (c *KafkaConnector) GetType() string {
+	return "kafka"
+}
+
+// GetName returns the data source name.
+func (c *KafkaConnector) GetName() string {
+	return "kafka"
+}
+
+// GetMode returns the connector mode.
+func (c *KafkaConnector) GetMode() string {
+	return interfaces.ConnectorModeLocal
+}
+
+// GetCategory returns the connector category.
+func (c *KafkaConnector) GetCategory() string {
+	return interfaces.ConnectorCategoryTopic
+}
+
+// GetEnabled returns the enabled status.
+func (c *KafkaConnector) GetEnabled() bool {
+	return c.enabled
+}
+
+// SetEnabled sets the enabled status.
+func (c *KafkaConnector) SetEnabled(enabled bool) {
+	c.enabled = enabled
+}
+
+// GetSensitiveFields returns the sensitive fields for Kafka connector.
+func (c *KafkaConnector) GetSensitiveFields() []string {
+	return []string{"password"}
+}
+
+// GetFieldConfig returns the field configuration for Kafka connector.
+func (c *KafkaConnector) GetFieldConfig() map[string]interfaces.ConnectorFieldConfig {
+	return map[string]interfaces.ConnectorFieldConfig{
+		"brokers":        {Name: "Broker 地址", Type: "array", Description: "Kafka broker 地址列表，格式 host:port", Required: true, Encrypted: false},
+		"sasl_mechanism": {Name: "SASL 认证机制", Type: "string", Description: "SASL 认证机制（可选）：PLAIN、SCRAM-SHA-256、SCRAM-SHA-512", Required: false, Encrypted: false},
+		"username":       {Name: "用户名", Type: "string", Description: "SASL 认证用户名", Required: false, Encrypted: false},
+		"password":       {Name: "密码", Type: "string", Description: "SASL 认证密码", Required: false, Encrypted: true},
+		"tls":            {Name: "启用 TLS", Type: "boolean", Description: "是否使用 TLS 连接 broker", Required: false, Encrypted: false},
+		"topic_pattern":  {Name: "Topic 模式", Type: "string", Description: "Topic 匹配模式（可选，如 orders-*）", Required: false, Encrypted: false},
+		"sample_size":    {Name: "采样消息数", Type: "integer", Description: "推断字段结构时每个 topic 采样的消息数，默认 100", Required: false, Encrypted: false},
+	}
+}
+
+// New creates a new Kafka connector.
+func (c *KafkaConnector) New(cfg interfaces.ConnectorConfig) (connectors.Connector, error) {
+	var kCfg kafkaConfig
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		Result:           &kCfg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka config decoder: %w", err)
+	}
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode kafka config: %w", err)
+	}
+
+	// 兼容以逗号分隔的 broker 字符串
+	brokers := make([]string, 0, len(kCfg.Brokers))
+	for _, item := range kCfg.Brokers {
+		for _, broker := range strings.Split(item, ",") {
+			if broker = strings.TrimSpace(broker); broker != "" {
+				brokers = append(brokers, broker)
+			}
+		}
+	}
+	kCfg.Brokers = brokers
+	if len(kCfg.Brokers) == 0 {
+		return nil, fmt.Errorf("kafka config brokers is required")
+	}
+	switch strings.ToUpper(kCfg.SASLMechanism) {
+	case "", saslMechanismPlain, saslMechanismScramSHA256, saslMechanismScramSHA512:
+		kCfg.SASLMechanism = strings.ToUpper(kCfg.SASLMechanism)
+	default:
+		return nil, fmt.Errorf("unsupported kafka sasl mechanism: %s", kCfg.SASLMechanism)
+	}
+	if kCfg.SampleSize <= 0 {
+		kCfg.SampleSize = defaultSampleSize
+	}
+
+	return &KafkaConnector{
+		Config: &kCfg,
+	}, nil
+}
+
+// Connect prepares the Kafka client and dialer.
+// kafka-go 的 Client 按请求建立连接，此处仅构建客户端，连通性由 Ping 校验
+func (c *KafkaConnector) Connect(ctx context.Context) error {
+	if c.client != nil {
+		return nil
+	}
+
+	mechanism, err := c.saslMechanism()
+	if err != nil {
+		return err
+	}
+	var tlsConfig *tls.Config
+	if c.Config.TLS {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	c.client = &kafka.Client{
+		Addr: kafka.TCP(c.Config.Brokers...),
+		Transport: &kafka.Transport{
+			DialTimeout: defaultDialTimeout,
+			SASL:        mechanism,
+			TLS:         tlsConfig,
+		},
+		Timeout: defaultDialTimeout,
+	}
+	c.dialer = &kafka.Dialer{
+		Timeout:       defaultDialTimeout,
+		DualStack:     true,
+		SASLMechanism: mechanism,
+		TLS:           tlsConfig,
+	}
+	return nil
+}
+
+// saslMechanism builds the SASL mechanism from config, nil when SASL is not configured.
+func (c *KafkaConnector) saslMechanism() (sasl.Mechanism, error) {
+	switch c.Config.SASLMechanism {
+	case "":
+		return nil, nil
+	case saslMechanismPlain:
+		return plain.Mechanism{Username: c.Config.Username, Password: c.Config.Password}, nil
+	case saslMechanismScramSHA256:
+		return scram.Mechanism(scram.SHA256, c.Config.Username, c.Config.Password)
+	case saslMechanismScramSHA512:
+		return scram.Mechanism(scram.SHA512, c.Config.Username, c.Config.Password)
+	default:
+		return nil, fmt.Errorf("unsupported kafka sasl mechanism: %s", c.Config.SASLMechanism)
+	}
+}
+
+// Close closes the connection.
+func (c *KafkaConnector) Close(ctx context.Context) error {
+	if c.client != nil {
+		if transport, ok := c.client.Transport.(*kafka.Transport); ok {
+			transport.CloseIdleConnections()
+		}
+	}
+	c.client = nil
+	c.dialer = nil
+	return nil
+}
+
+// Ping checks the connection by requesting cluster metadata.
+func (c *KafkaConnector) Ping(ctx context.Context) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	// Topics 为空切片时只返回 broker 信息，不拉取全部 topic
+	if _, err := c.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{}}); err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	return nil
+}
+
+// TestConnection tests the connection to Kafka.
+func (c *KafkaConnector) TestConnection(ctx context.Context) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	return c.Ping(ctx)
+}
+
+// GetMetadata returns the cluster metadata for the catalog.
+func (c *KafkaConnector) GetMetadata(ctx context.Context) (map[string]any, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("connector not connected")
+	}
+
+	resp, err := c.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{}})
+	if err != nil {
+		return nil, fmt.Errorf("get metadata failed: %w", err)
+	}
+
+	brokers := make([]string, 0, len(resp.Brokers))
+	for _, broker := range resp.Brokers {
+		brokers = append(brokers, fmt.Sprintf("%s:%d", broker.Host, broker.Port))
+	}
+	return map[string]any{
+		"cluster_id": resp.ClusterID,
+		"controller": resp.Controller.ID,
+		"brokers":    brokers,
+	}, nil
+}
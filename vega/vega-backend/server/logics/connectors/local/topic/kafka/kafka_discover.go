@@ -0,0 +1,177 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/segmentio/kafka-go"
+
+	"vega-backend/interfaces"
+)
+
+// ListTopics lists all user topics with partition and replica counts.
+func (c *KafkaConnector) ListTopics(ctx context.Context) ([]*interfaces.TopicMeta, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Metadata(ctx, &kafka.MetadataRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics: %w", err)
+	}
+
+	var topics []*interfaces.TopicMeta
+	for _, t := range resp.Topics {
+		// Skip internal topics, e.g. __consumer_offsets
+		if t.Internal || strings.HasPrefix(t.Name, "__") {
+			continue
+		}
+		if t.Error != nil {
+			logger.Warnf("Skip topic %s with metadata error: %v", t.Name, t.Error)
+			continue
+		}
+		if c.Config.TopicPattern != "" {
+			matched, err := path.Match(c.Config.TopicPattern, t.Name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid topic pattern %s: %w", c.Config.TopicPattern, err)
+			}
+			if !matched {
+				continue
+			}
+		}
+
+		replicas := 0
+		if len(t.Partitions) > 0 {
+			replicas = len(t.Partitions[0].Replicas)
+		}
+		topics = append(topics, &interfaces.TopicMeta{
+			Name:       t.Name,
+			Partitions: len(t.Partitions),
+			Replicas:   replicas,
+		})
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	return topics, nil
+}
+
+// GetTopicMeta retrieves topic metadata (partition offsets, configs) and infers fields by sampling messages.
+// GetTopicMeta 获取 topic 的分区 offset 与配置，并采样最新的消息推断字段结构
+// 参数:
+//   - ctx: 上下文信息，用于控制请求的超时和取消
+//   - topic: topic 元数据，Properties 与 Fields 将被填充
+//
+// 返回值:
+//   - error: 如果操作过程中发生错误，则返回错误信息
+func (c *KafkaConnector) GetTopicMeta(ctx context.Context, topic *interfaces.TopicMeta) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	if topic.Properties == nil {
+		topic.Properties = make(map[string]any)
+	}
+
+	// 1. Partition offsets
+	partitions, err := c.topicPartitions(ctx, topic.Name)
+	if err != nil {
+		return err
+	}
+	offsets, err := c.listOffsets(ctx, topic.Name, partitions)
+	if err != nil {
+		return err
+	}
+
+	var messageCount int64
+	partitionProps := make([]map[string]any, 0, len(partitions))
+	for _, p := range partitions {
+		po := offsets[p]
+		messageCount += po.last - po.first
+		partitionProps = append(partitionProps, map[string]any{
+			"partition":    p,
+			"first_offset": po.first,
+			"last_offset":  po.last,
+		})
+	}
+	topic.Properties["partitions"] = partitionProps
+	topic.Properties["message_count"] = messageCount
+
+	// 2. Topic configs
+	if err := c.fetchConfigs(ctx, topic); err != nil {
+		// 无 DescribeConfigs 权限时不影响发现
+		logger.Warnf("Failed to describe configs of topic %s: %v", topic.Name, err)
+	}
+
+	// 3. Sample latest messages to infer fields
+	ranges := sampleRanges(partitions, offsets, c.Config.SampleSize)
+	inferrer := newSchemaInferrer()
+	var mu sync.Mutex
+	_, err = c.readRanges(ctx, topic.Name, ranges, func(msg kafka.Message) (map[string]any, error) {
+		mu.Lock()
+		inferrer.add(msg)
+		mu.Unlock()
+		return nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sample messages: %w", err)
+	}
+	topic.Fields = inferrer.result()
+	topic.Properties["sample_count"] = inferrer.samples
+
+	return nil
+}
+
+// fetchConfigs retrieves non-sensitive topic configs.
+func (c *KafkaConnector) fetchConfigs(ctx context.Context, topic *interfaces.TopicMeta) error {
+	resp, err := c.client.DescribeConfigs(ctx, &kafka.DescribeConfigsRequest{
+		Resources: []kafka.DescribeConfigRequestResource{{
+			ResourceType: kafka.ResourceTypeTopic,
+			ResourceName: topic.Name,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	configs := make(map[string]any)
+	for _, resource := range resp.Resources {
+		if resource.Error != nil {
+			return resource.Error
+		}
+		for _, entry := range resource.ConfigEntries {
+			if entry.IsSensitive {
+				continue
+			}
+			configs[entry.ConfigName] = entry.ConfigValue
+		}
+	}
+	topic.Properties["configs"] = configs
+	return nil
+}
+
+// sampleRanges 将采样数按分区均分，每个分区读取末尾最新的消息
+func sampleRanges(partitions []int, offsets map[int]partitionOffsets, sampleSize int) []partitionRange {
+	if len(partitions) == 0 || sampleSize <= 0 {
+		return nil
+	}
+	perPartition := int64(max((sampleSize+len(partitions)-1)/len(partitions), 1))
+
+	ranges := make([]partitionRange, 0, len(partitions))
+	for _, p := range partitions {
+		po := offsets[p]
+		start := max(po.first, po.last-perPartition)
+		if po.last > start {
+			ranges = append(ranges, partitionRange{partition: p, start: start, end: po.last})
+		}
+	}
+	return ranges
+}
@@ -0,0 +1,405 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/segmentio/kafka-go"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/filter_condition"
+)
+
+const (
+	// 单个分区读取时等待下一条消息的最长时间，超时视为分区已读完（如末尾为事务标记）
+	partitionIdleTimeout = 3 * time.Second
+	// 并发读取的分区数上限
+	maxConcurrentPartitions = 8
+)
+
+// partitionOffsets 分区的起止 offset，last 为高水位（下一条待写入消息的 offset）
+type partitionOffsets struct {
+	first int64
+	last  int64
+}
+
+// partitionRange 分区读取范围 [start, end)
+type partitionRange struct {
+	partition int
+	start     int64
+	end       int64
+}
+
+// ExecuteQuery 在 topic 上执行有界读取：按 offset 或时间范围确定各分区的读取区间，
+// 读取后在内存中完成过滤、排序、分页与投影
+func (c *KafkaConnector) ExecuteQuery(ctx context.Context, resource *interfaces.Resource,
+	params *interfaces.ResourceDataQueryParams) (*interfaces.QueryResult, error) {
+
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	if params.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, params.Timeout)
+		defer cancel()
+	}
+
+	topic := resource.SourceIdentifier
+	readRange := params.TopicRange
+	if readRange == nil {
+		readRange = &interfaces.TopicReadRange{}
+	}
+
+	partitions, err := c.topicPartitions(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+	if len(readRange.Partitions) > 0 {
+		exists := make(map[int]bool, len(partitions))
+		for _, p := range partitions {
+			exists[p] = true
+		}
+		for _, p := range readRange.Partitions {
+			if !exists[p] {
+				return nil, fmt.Errorf("partition %d does not exist in topic %s", p, topic)
+			}
+		}
+		partitions = readRange.Partitions
+	}
+
+	ranges, err := c.resolveRanges(ctx, topic, partitions, readRange)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("topic query: %s, ranges: %+v", topic, ranges)
+
+	rows, err := c.readRanges(ctx, topic, ranges, func(msg kafka.Message) (map[string]any, error) {
+		// 按时间读取时 offset 区间由时间索引确定，再按消息时间精确过滤
+		ts := msg.Time.UnixMilli()
+		if (readRange.StartTime > 0 && ts < readRange.StartTime) ||
+			(readRange.EndTime > 0 && ts >= readRange.EndTime) {
+			return nil, nil
+		}
+		row := decodeMessage(msg)
+		if params.ActualFilterCond != nil {
+			matched, err := filter_condition.MatchRow(params.ActualFilterCond, row)
+			if err != nil || !matched {
+				return nil, err
+			}
+		}
+		return row, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortRows(rows, params.Sort)
+
+	result := &interfaces.QueryResult{
+		Columns: params.OutputFields,
+		Total:   int64(len(rows)),
+	}
+
+	limit := params.Limit
+	if limit <= 0 || limit > interfaces.MAX_SEARCH_SIZE {
+		limit = interfaces.MAX_SEARCH_SIZE
+	}
+	start := min(params.Offset, len(rows))
+	end := min(start+limit, len(rows))
+	rows = rows[start:end]
+
+	if len(params.OutputFields) > 0 {
+		for i, row := range rows {
+			projected := make(map[string]any, len(params.OutputFields))
+			for _, field := range params.OutputFields {
+				if v, ok := row[field]; ok {
+					projected[field] = v
+				}
+			}
+			rows[i] = projected
+		}
+	}
+	result.Rows = rows
+
+	return result, nil
+}
+
+// topicPartitions 获取 topic 的分区 ID 列表
+func (c *KafkaConnector) topicPartitions(ctx context.Context, topic string) ([]int, error) {
+	resp, err := c.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get topic metadata: %w", err)
+	}
+	for _, t := range resp.Topics {
+		if t.Name != topic {
+			continue
+		}
+		if t.Error != nil {
+			return nil, fmt.Errorf("failed to get topic metadata: %w", t.Error)
+		}
+		partitions := make([]int, 0, len(t.Partitions))
+		for _, p := range t.Partitions {
+			partitions = append(partitions, p.ID)
+		}
+		sort.Ints(partitions)
+		return partitions, nil
+	}
+	return nil, fmt.Errorf("topic %s not found", topic)
+}
+
+// listOffsets 获取分区的起始 offset 与高水位
+// 同一请求中同一分区只能出现一次，起始与高水位分两次请求
+func (c *KafkaConnector) listOffsets(ctx context.Context, topic string,
+	partitions []int) (map[int]partitionOffsets, error) {
+
+	firstReqs := make([]kafka.OffsetRequest, 0, len(partitions))
+	lastReqs := make([]kafka.OffsetRequest, 0, len(partitions))
+	for _, p := range partitions {
+		firstReqs = append(firstReqs, kafka.FirstOffsetOf(p))
+		lastReqs = append(lastReqs, kafka.LastOffsetOf(p))
+	}
+
+	offsets := make(map[int]partitionOffsets, len(partitions))
+	for _, reqs := range [][]kafka.OffsetRequest{firstReqs, lastReqs} {
+		resp, err := c.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+			Topics: map[string][]kafka.OffsetRequest{topic: reqs},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list offsets: %w", err)
+		}
+		for _, po := range resp.Topics[topic] {
+			if po.Error != nil {
+				return nil, fmt.Errorf("failed to list offsets of partition %d: %w", po.Partition, po.Error)
+			}
+			item := offsets[po.Partition]
+			if po.FirstOffset >= 0 {
+				item.first = po.FirstOffset
+			}
+			if po.LastOffset >= 0 {
+				item.last = po.LastOffset
+			}
+			offsets[po.Partition] = item
+		}
+	}
+	return offsets, nil
+}
+
+// timeOffsets 按时间查找各分区第一条时间戳不早于 t 的消息 offset，不存在时返回 -1
+func (c *KafkaConnector) timeOffsets(ctx context.Context, topic string, partitions []int,
+	t time.Time) (map[int]int64, error) {
+
+	reqs := make([]kafka.OffsetRequest, 0, len(partitions))
+	for _, p := range partitions {
+		reqs = append(reqs, kafka.TimeOffsetOf(p, t))
+	}
+	resp, err := c.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: reqs},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offsets by time: %w", err)
+	}
+
+	offsets := make(map[int]int64, len(partitions))
+	for _, po := range resp.Topics[topic] {
+		if po.Error != nil {
+			return nil, fmt.Errorf("failed to list offsets by time of partition %d: %w", po.Partition, po.Error)
+		}
+		offsets[po.Partition] = -1
+		for offset := range po.Offsets {
+			offsets[po.Partition] = offset
+		}
+	}
+	return offsets, nil
+}
+
+// resolveRanges 计算各分区的读取区间，单次扫描总量不超过 MAX_TOPIC_SCAN_MESSAGES（按分区均分）
+// 指定起点时从起点向后截断，否则保留区间末尾最新的消息
+func (c *KafkaConnector) resolveRanges(ctx context.Context, topic string, partitions []int,
+	readRange *interfaces.TopicReadRange) ([]partitionRange, error) {
+
+	if len(partitions) == 0 {
+		return nil, nil
+	}
+
+	offsets, err := c.listOffsets(ctx, topic, partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	var startByTime, endByTime map[int]int64
+	if readRange.StartTime > 0 {
+		if startByTime, err = c.timeOffsets(ctx, topic, partitions, time.UnixMilli(readRange.StartTime)); err != nil {
+			return nil, err
+		}
+	}
+	if readRange.EndTime > 0 {
+		if endByTime, err = c.timeOffsets(ctx, topic, partitions, time.UnixMilli(readRange.EndTime)); err != nil {
+			return nil, err
+		}
+	}
+
+	budget := max(int64(interfaces.MAX_TOPIC_SCAN_MESSAGES/len(partitions)), 1)
+	ranges := make([]partitionRange, 0, len(partitions))
+	for _, p := range partitions {
+		po := offsets[p]
+		start, end := po.first, po.last
+		hasStart := false
+
+		switch {
+		case readRange.StartOffset != nil:
+			start = clampOffset(*readRange.StartOffset, po.first, po.last)
+			hasStart = true
+		case startByTime != nil:
+			start = po.last
+			if offset := startByTime[p]; offset >= 0 {
+				start = clampOffset(offset, po.first, po.last)
+			}
+			hasStart = true
+		}
+		switch {
+		case readRange.EndOffset != nil:
+			end = clampOffset(*readRange.EndOffset, start, po.last)
+		case endByTime != nil:
+			if offset := endByTime[p]; offset >= 0 {
+				end = clampOffset(offset, start, po.last)
+			}
+		}
+
+		if end-start > budget {
+			if hasStart {
+				end = start + budget
+			} else {
+				start = end - budget
+			}
+		}
+		if end > start {
+			ranges = append(ranges, partitionRange{partition: p, start: start, end: end})
+		}
+	}
+	return ranges, nil
+}
+
+func clampOffset(offset, lower, upper int64) int64 {
+	return min(max(offset, lower), upper)
+}
+
+// readRanges 并发读取各分区区间内的消息，handle 返回 nil 行时丢弃该消息
+func (c *KafkaConnector) readRanges(ctx context.Context, topic string, ranges []partitionRange,
+	handle func(msg kafka.Message) (map[string]any, error)) ([]map[string]any, error) {
+
+	results := make([][]map[string]any, len(ranges))
+	errs := make([]error, len(ranges))
+	sem := make(chan struct{}, maxConcurrentPartitions)
+
+	var wg sync.WaitGroup
+	for i, r := range ranges {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, r partitionRange) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = c.readPartition(ctx, topic, r, func(msg kafka.Message) error {
+				row, err := handle(msg)
+				if err != nil {
+					return err
+				}
+				if row != nil {
+					results[i] = append(results[i], row)
+				}
+				return nil
+			})
+		}(i, r)
+	}
+	wg.Wait()
+
+	rows := make([]map[string]any, 0)
+	for i := range ranges {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		rows = append(rows, results[i]...)
+	}
+	return rows, nil
+}
+
+// readPartition 读取单个分区 [start, end) 区间内的消息
+func (c *KafkaConnector) readPartition(ctx context.Context, topic string, r partitionRange,
+	handle func(msg kafka.Message) error) error {
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   c.Config.Brokers,
+		Topic:     topic,
+		Partition: r.partition,
+		Dialer:    c.dialer,
+		MinBytes:  1,
+		MaxBytes:  interfaces.MAX_MESSAGE_BYTES,
+		MaxWait:   500 * time.Millisecond,
+	})
+	defer func() {
+		if err := reader.Close(); err != nil {
+			logger.Warnf("Failed to close reader of topic %s partition %d: %v", topic, r.partition, err)
+		}
+	}()
+
+	if err := reader.SetOffset(r.start); err != nil {
+		return fmt.Errorf("failed to seek partition %d to offset %d: %w", r.partition, r.start, err)
+	}
+
+	for {
+		readCtx, cancel := context.WithTimeout(ctx, partitionIdleTimeout)
+		msg, err := reader.ReadMessage(readCtx)
+		cancel()
+		if err != nil {
+			// 外部上下文未结束，仅单条等待超时：区间末尾无可读消息
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				return nil
+			}
+			return fmt.Errorf("failed to read partition %d: %w", r.partition, err)
+		}
+		if msg.Offset >= r.end {
+			return nil
+		}
+		if err := handle(msg); err != nil {
+			return err
+		}
+		if msg.Offset >= r.end-1 {
+			return nil
+		}
+	}
+}
+
+// sortRows 按排序字段在内存中排序，未指定时按消息时间倒序
+func sortRows(rows []map[string]any, sortFields []*interfaces.SortField) {
+	if len(sortFields) == 0 {
+		sortFields = []*interfaces.SortField{
+			{Field: FieldTimestamp, Direction: interfaces.DESC_DIRECTION},
+			{Field: FieldPartition, Direction: interfaces.ASC_DIRECTION},
+			{Field: FieldOffset, Direction: interfaces.DESC_DIRECTION},
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, s := range sortFields {
+			cmp := filter_condition.CompareValues(rows[i][s.Field], rows[j][s.Field])
+			if cmp == 0 {
+				continue
+			}
+			if s.Direction == interfaces.DESC_DIRECTION {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
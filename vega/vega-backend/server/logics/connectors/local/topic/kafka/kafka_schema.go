@@ -0,0 +1,252 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"vega-backend/interfaces"
+)
+
+// 消息元数据字段，每条消息都会输出
+const (
+	FieldPartition = "_partition"
+	FieldOffset    = "_offset"
+	FieldTimestamp = "_timestamp"
+	FieldKey       = "_key"
+	FieldHeaders   = "_headers"
+	// FieldValue 消息体不是 JSON 对象时，原始消息体以字符串输出到该字段
+	FieldValue = "_value"
+)
+
+// JSON 原始类型，记录为字段的 OrigType
+const (
+	jsonTypeString  = "string"
+	jsonTypeNumber  = "number"
+	jsonTypeBoolean = "boolean"
+	jsonTypeObject  = "object"
+	jsonTypeArray   = "array"
+	jsonTypeMixed   = "mixed"
+)
+
+// metadataFields 消息元数据字段定义
+var metadataFields = []interfaces.FieldMeta{
+	{Name: FieldPartition, Type: interfaces.DataType_Integer, OrigType: "int32", Searchable: true},
+	{Name: FieldOffset, Type: interfaces.DataType_Integer, OrigType: "int64", Searchable: true},
+	{Name: FieldTimestamp, Type: interfaces.DataType_Datetime, OrigType: "timestamp", Searchable: true},
+	{Name: FieldKey, Type: interfaces.DataType_String, OrigType: "bytes", Searchable: true},
+	{Name: FieldHeaders, Type: interfaces.DataType_Json, OrigType: "headers", Searchable: false},
+}
+
+// decodeMessage 将消息转换为行数据：JSON 对象消息体展开为顶层字段，其余消息体输出到 _value
+// 元数据字段优先，消息体中的同名字段会被覆盖
+func decodeMessage(msg kafka.Message) map[string]any {
+	row, ok := decodeJSONObject(msg.Value)
+	if !ok {
+		row = map[string]any{FieldValue: string(msg.Value)}
+	}
+
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+
+	row[FieldPartition] = int64(msg.Partition)
+	row[FieldOffset] = msg.Offset
+	row[FieldTimestamp] = msg.Time
+	row[FieldKey] = string(msg.Key)
+	row[FieldHeaders] = headers
+	return row
+}
+
+// decodeJSONObject 解析 JSON 对象消息体，数值保留整数与浮点的区分
+func decodeJSONObject(value []byte) (map[string]any, bool) {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 || value[0] != '{' {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	var obj map[string]any
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, false
+	}
+	for k, v := range obj {
+		obj[k] = normalizeNumber(v)
+	}
+	return obj, true
+}
+
+// normalizeNumber 将 json.Number 转换为 int64 或 float64
+func normalizeNumber(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case map[string]any:
+		for k, item := range val {
+			val[k] = normalizeNumber(item)
+		}
+		return val
+	case []any:
+		for i, item := range val {
+			val[i] = normalizeNumber(item)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// fieldType 推断中的字段类型
+type fieldType struct {
+	vegaType string
+	origType string
+}
+
+// schemaInferrer 根据采样消息推断 topic 的字段结构
+type schemaInferrer struct {
+	fields  map[string]*fieldType
+	hasRaw  bool // 是否存在非 JSON 对象的消息体
+	samples int
+}
+
+func newSchemaInferrer() *schemaInferrer {
+	return &schemaInferrer{fields: map[string]*fieldType{}}
+}
+
+// add 合并一条消息的字段类型
+func (s *schemaInferrer) add(msg kafka.Message) {
+	s.samples++
+
+	obj, ok := decodeJSONObject(msg.Value)
+	if !ok {
+		s.hasRaw = true
+		return
+	}
+	for name, value := range obj {
+		vegaType, origType := inferValueType(value)
+		if origType == "" {
+			// null 值不参与推断，但记录字段存在
+			if _, exists := s.fields[name]; !exists {
+				s.fields[name] = &fieldType{}
+			}
+			continue
+		}
+		ft, exists := s.fields[name]
+		if !exists {
+			s.fields[name] = &fieldType{vegaType: vegaType, origType: origType}
+			continue
+		}
+		ft.vegaType = mergeType(ft.vegaType, vegaType)
+		if ft.origType == "" {
+			ft.origType = origType
+		} else if ft.origType != origType {
+			ft.origType = jsonTypeMixed
+		}
+	}
+}
+
+// result 返回推断出的字段：元数据字段在前，消息体字段按名称排序
+func (s *schemaInferrer) result() []interfaces.FieldMeta {
+	fields := make([]interfaces.FieldMeta, 0, len(metadataFields)+len(s.fields)+1)
+	fields = append(fields, metadataFields...)
+	if s.hasRaw {
+		fields = append(fields, interfaces.FieldMeta{
+			Name: FieldValue, Type: interfaces.DataType_String, OrigType: "bytes", Searchable: true,
+		})
+	}
+
+	names := make([]string, 0, len(s.fields))
+	for name := range s.fields {
+		if isMetadataField(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ft := s.fields[name]
+		vegaType := ft.vegaType
+		if vegaType == "" {
+			// 采样中只出现 null 的字段按字符串处理
+			vegaType = interfaces.DataType_String
+		}
+		fields = append(fields, interfaces.FieldMeta{
+			Name:       name,
+			Type:       vegaType,
+			OrigType:   ft.origType,
+			Searchable: vegaType != interfaces.DataType_Json,
+		})
+	}
+	return fields
+}
+
+// inferValueType 推断单个 JSON 值的 VEGA 类型与 JSON 原始类型，null 返回空
+func inferValueType(v any) (string, string) {
+	switch val := v.(type) {
+	case nil:
+		return "", ""
+	case bool:
+		return interfaces.DataType_Boolean, jsonTypeBoolean
+	case int64:
+		return interfaces.DataType_Integer, jsonTypeNumber
+	case float64:
+		return interfaces.DataType_Float, jsonTypeNumber
+	case string:
+		if _, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			return interfaces.DataType_Datetime, jsonTypeString
+		}
+		return interfaces.DataType_String, jsonTypeString
+	case map[string]any:
+		return interfaces.DataType_Json, jsonTypeObject
+	case []any:
+		return interfaces.DataType_Json, jsonTypeArray
+	default:
+		return interfaces.DataType_String, jsonTypeString
+	}
+}
+
+// mergeType 合并同一字段在不同消息中的类型：整数与浮点合并为浮点，其余冲突退化为字符串
+func mergeType(a, b string) string {
+	switch {
+	case a == b:
+		return a
+	case a == "":
+		return b
+	case b == "":
+		return a
+	case (a == interfaces.DataType_Integer && b == interfaces.DataType_Float) ||
+		(a == interfaces.DataType_Float && b == interfaces.DataType_Integer):
+		return interfaces.DataType_Float
+	default:
+		return interfaces.DataType_String
+	}
+}
+
+func isMetadataField(name string) bool {
+	if name == FieldValue {
+		return true
+	}
+	for _, field := range metadataFields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/filter_condition"
+)
+
+func TestSchemaInferrer(t *testing.T) {
+	inferrer := newSchemaInferrer()
+	inferrer.add(kafka.Message{Value: []byte(`{"id":1,"amount":10,"status":"ok","at":"2025-01-01T00:00:00Z","tags":["a"]}`)})
+	inferrer.add(kafka.Message{Value: []byte(`{"id":2,"amount":10.5,"status":3,"extra":null}`)})
+	inferrer.add(kafka.Message{Value: []byte(`plain text`)})
+
+	types := map[string]string{}
+	for _, field := range inferrer.result() {
+		types[field.Name] = field.Type
+	}
+
+	expected := map[string]string{
+		FieldPartition: interfaces.DataType_Integer,
+		FieldTimestamp: interfaces.DataType_Datetime,
+		FieldValue:     interfaces.DataType_String,
+		"id":           interfaces.DataType_Integer,
+		"amount":       interfaces.DataType_Float,
+		"status":       interfaces.DataType_String,
+		"at":           interfaces.DataType_Datetime,
+		"tags":         interfaces.DataType_Json,
+		"extra":        interfaces.DataType_String,
+	}
+	for name, typ := range expected {
+		if types[name] != typ {
+			t.Errorf("field %s: expected type %s, got %s", name, typ, types[name])
+		}
+	}
+	if inferrer.samples != 3 {
+		t.Errorf("expected 3 samples, got %d", inferrer.samples)
+	}
+}
+
+func TestMatchCondition(t *testing.T) {
+	ctx := context.Background()
+	fieldsMap := map[string]*interfaces.Property{
+		"status":       {Name: "status", Type: interfaces.DataType_String, OriginalName: "status"},
+		"amount":       {Name: "amount", Type: interfaces.DataType_Float, OriginalName: "amount"},
+		FieldTimestamp: {Name: FieldTimestamp, Type: interfaces.DataType_Datetime, OriginalName: FieldTimestamp},
+	}
+	now := time.Now()
+	row := decodeMessage(kafka.Message{
+		Partition: 1,
+		Offset:    42,
+		Time:      now,
+		Value:     []byte(`{"status":"failed","amount":12.5}`),
+	})
+
+	cases := []struct {
+		cfg      *interfaces.FilterCondCfg
+		expected bool
+	}{
+		{&interfaces.FilterCondCfg{Name: "status", Operation: "==", ValueOptCfg: interfaces.ValueOptCfg{Value: "failed"}}, true},
+		{&interfaces.FilterCondCfg{Name: "amount", Operation: ">", ValueOptCfg: interfaces.ValueOptCfg{Value: 20}}, false},
+		{&interfaces.FilterCondCfg{Name: "status", Operation: "in", ValueOptCfg: interfaces.ValueOptCfg{Value: []any{"ok", "failed"}}}, true},
+		{&interfaces.FilterCondCfg{Name: FieldTimestamp, Operation: ">=",
+			ValueOptCfg: interfaces.ValueOptCfg{Value: float64(now.Add(-time.Minute).UnixMilli())}}, true},
+		{&interfaces.FilterCondCfg{Operation: "and", SubConds: []*interfaces.FilterCondCfg{
+			{Name: "status", Operation: "like", ValueOptCfg: interfaces.ValueOptCfg{Value: "fail"}},
+			{Name: "amount", Operation: "<", ValueOptCfg: interfaces.ValueOptCfg{Value: 10}},
+		}}, false},
+	}
+
+	for i, c := range cases {
+		if c.cfg.ValueFrom == "" {
+			c.cfg.ValueFrom = interfaces.ValueFrom_Const
+		}
+		for _, sub := range c.cfg.SubConds {
+			sub.ValueFrom = interfaces.ValueFrom_Const
+		}
+		cond, err := filter_condition.NewFilterCondition(ctx, c.cfg, fieldsMap)
+		if err != nil {
+			t.Fatalf("case %d: NewFilterCondition failed: %v", i, err)
+		}
+		matched, err := filter_condition.MatchRow(cond, row)
+		if err != nil {
+			t.Fatalf("case %d: MatchRow failed: %v", i, err)
+		}
+		if matched != c.expected {
+			t.Errorf("case %d: expected %v, got %v", i, c.expected, matched)
+		}
+	}
+}
+
+func TestSampleRanges(t *testing.T) {
+	offsets := map[int]partitionOffsets{
+		0: {first: 0, last: 100},
+		1: {first: 95, last: 100},
+		2: {first: 10, last: 10},
+	}
+	ranges := sampleRanges([]int{0, 1, 2}, offsets, 30)
+	if len(ranges) != 2 {
+		t.Fatalf("expected 2 ranges, got %+v", ranges)
+	}
+	if ranges[0].start != 90 || ranges[0].end != 100 {
+		t.Errorf("unexpected range of partition 0: %+v", ranges[0])
+	}
+	if ranges[1].start != 95 || ranges[1].end != 100 {
+		t.Errorf("unexpected range of partition 1: %+v", ranges[1])
+	}
+}
@@ -21,7 +21,9 @@ import (
 
 	"vega-backend/common"
 	asynq_access "vega-backend/drivenadapters/asynq"
+	discoverscheduleaccess "vega-backend/drivenadapters/discover_schedule"
 	discovertaskaccess "vega-backend/drivenadapters/discover_task"
+	schemadriftaccess "vega-backend/drivenadapters/schema_drift"
 	"vega-backend/interfaces"
 )
 
@@ -34,6 +36,8 @@ type discoverTaskService struct {
 	appSetting *common.AppSetting
 	client     *asynq.Client
 	dta        interfaces.DiscoverTaskAccess
+	dsa        interfaces.DiscoverScheduleAccess
+	sda        interfaces.SchemaDriftAccess
 }
 
 // NewDiscoverTaskService creates or returns the singleton DiscoverTaskService.
@@ -44,6 +48,8 @@ func NewDiscoverTaskService(appSetting *common.AppSetting) interfaces.DiscoverTa
 			appSetting: appSetting,
 			client:     asynqAccess.CreateClient(context.Background()),
 			dta:        discovertaskaccess.NewDiscoverTaskAccess(appSetting),
+			dsa:        discoverscheduleaccess.NewDiscoverScheduleAccess(appSetting),
+			sda:        schemadriftaccess.NewSchemaDriftAccess(appSetting),
 		}
 	})
 	return dtsService
@@ -64,6 +70,37 @@ func (dts *discoverTaskService) Create(ctx context.Context, catalogID string) (s
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End() // 确保span在函数结束时结束
 
+	return dts.create(ctx, catalogID, interfaces.DiscoverTaskTriggerManual)
+}
+
+// CreateScheduled creates a scheduled DiscoverTask.
+// 若该 catalog 已有未结束（pending/running）的发现任务，则跳过本次调度，返回空任务ID
+func (dts *discoverTaskService) CreateScheduled(ctx context.Context, catalogID string) (string, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "DiscoverTaskService.CreateScheduled",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	for _, status := range []string{interfaces.DiscoverTaskStatusPending, interfaces.DiscoverTaskStatusRunning} {
+		_, total, err := dts.dta.List(ctx, interfaces.DiscoverTaskQueryParams{
+			PaginationQueryParams: interfaces.PaginationQueryParams{Limit: 1},
+			CatalogID:             catalogID,
+			Status:                status,
+		})
+		if err != nil {
+			logger.Errorf("Failed to list unfinished discover tasks: %v", err)
+			return "", err
+		}
+		if total > 0 {
+			logger.Infof("Catalog %s has %s discover task, skip scheduled discover", catalogID, status)
+			return "", nil
+		}
+	}
+
+	return dts.create(ctx, catalogID, interfaces.DiscoverTaskTriggerScheduled)
+}
+
+// create writes a DiscoverTask with the given trigger type and enqueues it.
+func (dts *discoverTaskService) create(ctx context.Context, catalogID string, triggerType string) (string, error) {
 	// Get account info from context
 	accountInfo := interfaces.AccountInfo{}
 	if ai, ok := ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo); ok {
@@ -74,7 +111,7 @@ func (dts *discoverTaskService) Create(ctx context.Context, catalogID string) (s
 	task := &interfaces.DiscoverTask{
 		ID:          xid.New().String(),
 		CatalogID:   catalogID,
-		TriggerType: interfaces.DiscoverTaskTriggerManual,
+		TriggerType: triggerType,
 		Status:      interfaces.DiscoverTaskStatusPending,
 		Progress:    0,
 		Message:     "",
@@ -149,5 +186,119 @@ func (dts *discoverTaskService) UpdateResult(ctx context.Context, id string, res
 		trace.WithSpanKind(trace.SpanKindServer))
 	defer span.End()
 
-	return dts.dta.UpdateResult(ctx, id, result, stime)
+	if result != nil {
+		for _, drift := range result.Drifts {
+			if drift.ID == "" {
+				drift.ID = xid.New().String()
+			}
+			drift.TaskID = id
+		}
+	}
+
+	if err := dts.dta.UpdateResult(ctx, id, result, stime); err != nil {
+		return err
+	}
+
+	// 记录本次发现检测到的 schema 漂移，供按 resource 查询漂移历史
+	if result != nil && len(result.Drifts) > 0 {
+		if err := dts.sda.CreateBatch(ctx, result.Drifts); err != nil {
+			logger.Errorf("Failed to save schema drifts of task %s: %v", id, err)
+			o11y.Error(ctx, "Failed to save schema drifts")
+			return err
+		}
+	}
+	return nil
+}
+
+// SetSchedule creates or updates the discover schedule of a catalog.
+func (dts *discoverTaskService) SetSchedule(ctx context.Context, catalogID string,
+	req *interfaces.DiscoverScheduleRequest) (*interfaces.DiscoverSchedule, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "DiscoverTaskService.SetSchedule",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	accountInfo := interfaces.AccountInfo{}
+	if ai, ok := ctx.Value(interfaces.ACCOUNT_INFO_KEY).(interfaces.AccountInfo); ok {
+		accountInfo = ai
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	schedule, err := dts.dsa.GetByCatalogID(ctx, catalogID)
+	if err != nil {
+		logger.Errorf("Failed to get discover schedule: %v", err)
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	if schedule == nil {
+		schedule = &interfaces.DiscoverSchedule{
+			CatalogID:  catalogID,
+			CronExpr:   req.CronExpr,
+			Enabled:    enabled,
+			Creator:    accountInfo,
+			CreateTime: now,
+			Updater:    accountInfo,
+			UpdateTime: now,
+		}
+		if err := dts.dsa.Create(ctx, schedule); err != nil {
+			logger.Errorf("Failed to create discover schedule: %v", err)
+			o11y.Error(ctx, "Failed to create discover schedule")
+			return nil, err
+		}
+		return schedule, nil
+	}
+
+	schedule.CronExpr = req.CronExpr
+	schedule.Enabled = enabled
+	schedule.Updater = accountInfo
+	schedule.UpdateTime = now
+	if err := dts.dsa.Update(ctx, schedule); err != nil {
+		logger.Errorf("Failed to update discover schedule: %v", err)
+		o11y.Error(ctx, "Failed to update discover schedule")
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// GetSchedule retrieves the discover schedule of a catalog.
+func (dts *discoverTaskService) GetSchedule(ctx context.Context, catalogID string) (*interfaces.DiscoverSchedule, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "DiscoverTaskService.GetSchedule",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	return dts.dsa.GetByCatalogID(ctx, catalogID)
+}
+
+// DeleteSchedule deletes the discover schedule of a catalog.
+func (dts *discoverTaskService) DeleteSchedule(ctx context.Context, catalogID string) error {
+	ctx, span := ar_trace.Tracer.Start(ctx, "DiscoverTaskService.DeleteSchedule",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	return dts.dsa.DeleteByCatalogIDs(ctx, []string{catalogID})
+}
+
+// ListEnabledSchedules lists all enabled discover schedules.
+func (dts *discoverTaskService) ListEnabledSchedules(ctx context.Context) ([]*interfaces.DiscoverSchedule, error) {
+	ctx, span := ar_trace.Tracer.Start(ctx, "DiscoverTaskService.ListEnabledSchedules",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	return dts.dsa.ListEnabled(ctx)
+}
+
+// ListSchemaDrifts lists the schema drift history of a resource.
+func (dts *discoverTaskService) ListSchemaDrifts(ctx context.Context,
+	params interfaces.SchemaDriftQueryParams) ([]*interfaces.SchemaDrift, int64, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "DiscoverTaskService.ListSchemaDrifts",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	return dts.sda.List(ctx, params)
 }
@@ -0,0 +1,372 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package filter_condition
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"vega-backend/interfaces"
+)
+
+// MatchRow 在内存中对一行数据计算过滤条件，与 SQL 语义一致：字段为 null 时比较结果为 false
+// 供无法下推过滤条件的 connector（如 kafka、s3）在读取数据后使用
+func MatchRow(condition interfaces.FilterCondition, row map[string]any) (bool, error) {
+	switch cond := condition.(type) {
+	case *AndCond:
+		for _, subCond := range cond.SubConds {
+			matched, err := MatchRow(subCond, row)
+			if err != nil || !matched {
+				return false, err
+			}
+		}
+		return true, nil
+	case *OrCond:
+		for _, subCond := range cond.SubConds {
+			matched, err := MatchRow(subCond, row)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case *EqualCond:
+		return compareWith(row, cond.Lfield, cond.Rfield, cond.Value, func(c int) bool { return c == 0 }), nil
+	case *NotEqualCond:
+		return compareWith(row, cond.Lfield, cond.Rfield, cond.Value, func(c int) bool { return c != 0 }), nil
+	case *GtCond:
+		return compareWith(row, cond.Lfield, cond.Rfield, cond.Value, func(c int) bool { return c > 0 }), nil
+	case *GteCond:
+		return compareWith(row, cond.Lfield, cond.Rfield, cond.Value, func(c int) bool { return c >= 0 }), nil
+	case *LtCond:
+		return compareWith(row, cond.Lfield, cond.Rfield, cond.Value, func(c int) bool { return c < 0 }), nil
+	case *LteCond:
+		return compareWith(row, cond.Lfield, cond.Rfield, cond.Value, func(c int) bool { return c <= 0 }), nil
+
+	case *InCond:
+		v := row[cond.Lfield.OriginalName]
+		return v != nil && containsValue(cond.Value, v), nil
+	case *NotInCond:
+		v := row[cond.Lfield.OriginalName]
+		return v != nil && !containsValue(cond.Value, v), nil
+
+	case *LikeCond:
+		v := row[cond.Lfield.OriginalName]
+		return v != nil && strings.Contains(toString(v), cond.Value), nil
+	case *NotLikeCond:
+		v := row[cond.Lfield.OriginalName]
+		return v != nil && !strings.Contains(toString(v), cond.Value), nil
+	case *PrefixCond:
+		v := row[cond.Lfield.OriginalName]
+		return v != nil && strings.HasPrefix(toString(v), cond.Value), nil
+	case *NotPrefixCond:
+		v := row[cond.Lfield.OriginalName]
+		return v != nil && !strings.HasPrefix(toString(v), cond.Value), nil
+
+	case *ContainCond:
+		v := row[cond.Lfield.OriginalName]
+		if v == nil {
+			return false, nil
+		}
+		elements := listElements(v)
+		for _, want := range cond.Value {
+			if !containsValue(elements, want) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case *NotContainCond:
+		v := row[cond.Lfield.OriginalName]
+		if v == nil {
+			return false, nil
+		}
+		elements := listElements(v)
+		for _, want := range cond.Value {
+			if !containsValue(elements, want) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case *RangeCond:
+		return inRange(row[cond.Lfield.OriginalName], cond.Value, "range")
+	case *BetweenCond:
+		return inRange(row[cond.Lfield.OriginalName], cond.Value, "between")
+	case *OutRangeCond:
+		v := row[cond.Lfield.OriginalName]
+		if len(cond.Value) != 2 {
+			return false, fmt.Errorf("out_range condition requires exactly 2 values")
+		}
+		if v == nil {
+			return false, nil
+		}
+		return CompareValues(v, cond.Value[0]) < 0 || CompareValues(v, cond.Value[1]) > 0, nil
+
+	case *ExistCond:
+		return row[cond.Lfield.OriginalName] != nil, nil
+	case *NotExistCond:
+		return row[cond.Lfield.OriginalName] == nil, nil
+	case *NotNullCond:
+		return row[cond.Lfield.OriginalName] != nil, nil
+	case *NullCond:
+		return row[cond.Lfield.OriginalName] == nil, nil
+	case *EmptyCond:
+		v := row[cond.Lfield.OriginalName]
+		return v != nil && toString(v) == "", nil
+	case *NotEmptyCond:
+		v := row[cond.Lfield.OriginalName]
+		return v != nil && toString(v) != "", nil
+
+	case *RegexCond:
+		v := row[cond.Lfield.OriginalName]
+		if v == nil || cond.Regexp == nil {
+			return false, nil
+		}
+		return cond.Regexp.MatchString(toString(v))
+	case *TrueCond:
+		b, ok := row[cond.Lfield.OriginalName].(bool)
+		return ok && b, nil
+	case *FalseCond:
+		b, ok := row[cond.Lfield.OriginalName].(bool)
+		return ok && !b, nil
+
+	case *BeforeCond:
+		return matchBefore(row[cond.Lfield.OriginalName], cond.Value)
+	case *CurrentCond:
+		return matchCurrent(row[cond.Lfield.OriginalName], cond.Value)
+
+	default:
+		return false, fmt.Errorf("operation %s is not supported in memory", condition.GetOperation())
+	}
+}
+
+// compareWith 比较字段与常量（或另一字段），任一侧为 null 时返回 false
+func compareWith(row map[string]any, lfield, rfield *interfaces.Property, value any, pred func(int) bool) bool {
+	left := row[lfield.OriginalName]
+	right := value
+	if rfield != nil {
+		right = row[rfield.OriginalName]
+	}
+	if left == nil || right == nil {
+		return false
+	}
+	return pred(CompareValues(left, right))
+}
+
+func inRange(v any, bounds []any, op string) (bool, error) {
+	if len(bounds) != 2 {
+		return false, fmt.Errorf("%s condition requires exactly 2 values", op)
+	}
+	if v == nil {
+		return false, nil
+	}
+	return CompareValues(v, bounds[0]) >= 0 && CompareValues(v, bounds[1]) <= 0, nil
+}
+
+func containsValue(values []any, v any) bool {
+	for _, item := range values {
+		if item != nil && CompareValues(v, item) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// listElements 数组字段取其元素，字符串字段按逗号拆分（与关系型 connector 的 contain 语义一致）
+func listElements(v any) []any {
+	switch val := v.(type) {
+	case []any:
+		return val
+	case string:
+		parts := strings.Split(val, ",")
+		elements := make([]any, 0, len(parts))
+		for _, part := range parts {
+			elements = append(elements, part)
+		}
+		return elements
+	default:
+		return []any{v}
+	}
+}
+
+// beforeUnits before 条件支持的时间单位
+var beforeUnits = map[string]func(t time.Time, n int) time.Time{
+	"year":   func(t time.Time, n int) time.Time { return t.AddDate(-n, 0, 0) },
+	"month":  func(t time.Time, n int) time.Time { return t.AddDate(0, -n, 0) },
+	"week":   func(t time.Time, n int) time.Time { return t.AddDate(0, 0, -7*n) },
+	"day":    func(t time.Time, n int) time.Time { return t.AddDate(0, 0, -n) },
+	"hour":   func(t time.Time, n int) time.Time { return t.Add(-time.Duration(n) * time.Hour) },
+	"minute": func(t time.Time, n int) time.Time { return t.Add(-time.Duration(n) * time.Minute) },
+	"second": func(t time.Time, n int) time.Time { return t.Add(-time.Duration(n) * time.Second) },
+}
+
+// matchBefore 字段时间早于当前时间减去 [n, unit]
+func matchBefore(v any, values []any) (bool, error) {
+	if len(values) != 2 {
+		return false, fmt.Errorf("before condition requires exactly 2 values")
+	}
+	interval, ok := toFloat(values[0])
+	if !ok {
+		return false, fmt.Errorf("condition [before] interval value should be a number")
+	}
+	unit, ok := values[1].(string)
+	if !ok {
+		return false, fmt.Errorf("condition [before] unit value should be a string")
+	}
+	sub, ok := beforeUnits[strings.ToLower(unit)]
+	if !ok {
+		return false, fmt.Errorf("condition [before] unsupported unit: %s", unit)
+	}
+
+	t, ok := toTime(v)
+	if !ok {
+		return false, nil
+	}
+	return t.Before(sub(time.Now(), int(interval))), nil
+}
+
+// matchCurrent 字段时间位于当前年/月/周/日/时/分内
+func matchCurrent(v any, format string) (bool, error) {
+	now := time.Now()
+	var start, end time.Time
+	switch format {
+	case CurrentYear:
+		start = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+		end = start.AddDate(1, 0, 0)
+	case CurrentMonth:
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		end = start.AddDate(0, 1, 0)
+	case CurrentWeek:
+		// 周一为一周的开始
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		start = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		end = start.AddDate(0, 0, 7)
+	case CurrentDay:
+		start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		end = start.AddDate(0, 0, 1)
+	case CurrentHour:
+		start = now.Truncate(time.Hour)
+		end = start.Add(time.Hour)
+	case CurrentMinute:
+		start = now.Truncate(time.Minute)
+		end = start.Add(time.Minute)
+	default:
+		return false, fmt.Errorf("condition [current] unsupported format: %s", format)
+	}
+
+	t, ok := toTime(v)
+	if !ok {
+		return false, nil
+	}
+	return !t.Before(start) && t.Before(end), nil
+}
+
+// CompareValues 比较两个值（用于内存过滤与排序）：null 最小；任一侧为时间时按时间比较，其次按数值、布尔、字符串比较
+func CompareValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	_, aIsTime := a.(time.Time)
+	_, bIsTime := b.(time.Time)
+	if aIsTime || bIsTime {
+		if ta, ok := toTime(a); ok {
+			if tb, ok := toTime(b); ok {
+				return ta.Compare(tb)
+			}
+		}
+	}
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	if ba, ok := a.(bool); ok {
+		if bb, ok := b.(bool); ok {
+			switch {
+			case ba == bb:
+				return 0
+			case !ba:
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(toString(a), toString(b))
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		s := strings.TrimSpace(n)
+		if s == "" {
+			return 0, false
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, false
+		}
+		return f, true
+	}
+	return 0, false
+}
+
+// toTime 将时间、毫秒时间戳或时间字符串转换为时间
+func toTime(v any) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, time.DateTime, time.DateOnly} {
+			if parsed, err := time.ParseInLocation(layout, t, time.Local); err == nil {
+				return parsed, true
+			}
+		}
+		return time.Time{}, false
+	}
+	if ms, ok := toFloat(v); ok {
+		return time.UnixMilli(int64(ms)), true
+	}
+	return time.Time{}, false
+}
+
+func toString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	case time.Time:
+		return s.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
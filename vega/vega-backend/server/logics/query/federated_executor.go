@@ -0,0 +1,754 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kweaver-ai/TelemetrySDK-Go/exporter/v2/ar_trace"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/kweaver-ai/kweaver-go-lib/rest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	verrors "vega-backend/errors"
+	"vega-backend/interfaces"
+	"vega-backend/logics/connectors"
+	"vega-backend/logics/connectors/factory"
+	"vega-backend/logics/filter_condition"
+)
+
+const (
+	// FederatedPageSize 联邦查询下推到单个数据源时每批拉取的行数
+	FederatedPageSize = interfaces.MAX_SEARCH_SIZE
+	// FederatedMaxRowsPerTable 联邦查询单表最多拉取的行数，超过后拒绝执行，避免进程内 JOIN 内存失控
+	FederatedMaxRowsPerTable = 200000
+)
+
+// resourceQuerier 支持单资源查询的 connector，TableConnector 与 IndexConnector 均满足
+type resourceQuerier interface {
+	connectors.Connector
+	ExecuteQuery(ctx context.Context, resource *interfaces.Resource,
+		params *interfaces.ResourceDataQueryParams) (*interfaces.QueryResult, error)
+}
+
+// pointInTimeCloser 使用 PIT 深分页的 connector，拉取结束后释放 PIT
+type pointInTimeCloser interface {
+	ClosePointInTime(ctx context.Context, pitID string) error
+}
+
+// connectorOpener 根据 catalog 创建并连接 connector，测试时可替换
+type connectorOpener func(ctx context.Context, cat *interfaces.Catalog) (resourceQuerier, error)
+
+// federatedTable 参与联邦查询的单张表
+type federatedTable struct {
+	alias    string
+	resource *interfaces.Resource
+	columns  map[string]bool // schema 中的列名
+
+	fields []string                  // 下推的投影列，空表示全部列
+	filter *interfaces.FilterCondCfg // 下推的过滤条件（字段名已去除别名前缀）
+
+	rows []map[string]any // 拉取结果，key 为 alias.col
+}
+
+// federatedPlan 联邦查询执行计划
+type federatedPlan struct {
+	tables   []*federatedTable
+	byAlias  map[string]*federatedTable
+	joins    []*interfaces.JoinSpec
+	residual *interfaces.FilterCondCfg // 无法下推、需在进程内计算的过滤条件
+	sort     []*interfaces.SortField
+	output   []string
+}
+
+// executeFederated 跨 catalog 联邦查询：过滤与投影下推到各数据源，拉回结果后在进程内做 hash join、排序与分页
+func (qs *queryService) executeFederated(ctx context.Context, req *interfaces.QueryExecuteRequest,
+	params *interfaces.JoinQueryParams) (*interfaces.QueryResult, error) {
+
+	ctx, span := ar_trace.Tracer.Start(ctx, "QueryExecuteFederated")
+	defer span.End()
+
+	plan, err := newFederatedPlan(req, params)
+	if err != nil {
+		span.SetStatus(codes.Error, "build federated plan failed")
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Query_InvalidParameter).
+			WithErrorDetails(err.Error())
+	}
+	span.SetAttributes(attribute.Int("federated.tables", len(plan.tables)))
+
+	catalogs, httpErr := qs.getCatalogs(ctx, params.Resources)
+	if httpErr != nil {
+		span.SetStatus(codes.Error, "get catalogs failed")
+		return nil, httpErr
+	}
+
+	opener := qs.openConnector
+	if opener == nil {
+		opener = openResourceQuerier
+	}
+	if err := plan.fetch(ctx, catalogs, opener); err != nil {
+		logger.Errorf("federated fetch failed: %v", err)
+		span.SetStatus(codes.Error, "federated fetch failed")
+		return nil, err
+	}
+
+	rows, err := plan.join()
+	if err != nil {
+		span.SetStatus(codes.Error, "federated join failed")
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Query_InvalidParameter).
+			WithErrorDetails(err.Error())
+	}
+
+	if plan.residual != nil {
+		filtered := make([]map[string]any, 0, len(rows))
+		for _, row := range rows {
+			ok, err := plan.evalCondition(plan.residual, row)
+			if err != nil {
+				span.SetStatus(codes.Error, "evaluate residual filter failed")
+				return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_InvalidParameter_FilterCondition).
+					WithErrorDetails(err.Error())
+			}
+			if ok {
+				filtered = append(filtered, row)
+			}
+		}
+		rows = filtered
+	}
+
+	if err := plan.sortRows(rows); err != nil {
+		span.SetStatus(codes.Error, "sort failed")
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Query_InvalidParameter).
+			WithErrorDetails(err.Error())
+	}
+
+	result := &interfaces.QueryResult{Total: int64(len(rows))}
+	start := min(params.Offset, len(rows))
+	end := min(start+params.Limit, len(rows))
+	page := rows[start:end]
+
+	result.Columns, result.Rows, err = plan.project(page)
+	if err != nil {
+		span.SetStatus(codes.Error, "project output fields failed")
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Query_InvalidParameter).
+			WithErrorDetails(err.Error())
+	}
+
+	return result, nil
+}
+
+// getCatalogs 获取资源所属的全部 catalog
+func (qs *queryService) getCatalogs(ctx context.Context, resources []*interfaces.Resource) (map[string]*interfaces.Catalog, error) {
+	catalogs := make(map[string]*interfaces.Catalog)
+	for _, r := range resources {
+		if _, ok := catalogs[r.CatalogID]; ok {
+			continue
+		}
+		cat, err := qs.cs.GetByID(ctx, r.CatalogID, true)
+		if err != nil {
+			return nil, err
+		}
+		if cat == nil {
+			return nil, rest.NewHTTPError(ctx, http.StatusNotFound, verrors.VegaBackend_Query_CatalogNotFound).
+				WithErrorDetails(fmt.Sprintf("catalog %s not found", r.CatalogID))
+		}
+		catalogs[r.CatalogID] = cat
+	}
+	return catalogs, nil
+}
+
+// openResourceQuerier 通过 connector factory 创建 connector 并建立连接
+func openResourceQuerier(ctx context.Context, cat *interfaces.Catalog) (resourceQuerier, error) {
+	connector, err := factory.GetFactory().CreateConnectorInstance(ctx, cat.ConnectorType, cat.ConnectorCfg)
+	if err != nil {
+		return nil, err
+	}
+	querier, ok := connector.(resourceQuerier)
+	if !ok {
+		return nil, fmt.Errorf("connector %s does not support query", cat.ConnectorType)
+	}
+	if err := querier.Connect(ctx); err != nil {
+		return nil, err
+	}
+	return querier, nil
+}
+
+// newFederatedPlan 生成执行计划：拆分过滤条件并确定每张表需要拉取的列
+func newFederatedPlan(req *interfaces.QueryExecuteRequest, params *interfaces.JoinQueryParams) (*federatedPlan, error) {
+	plan := &federatedPlan{
+		byAlias: make(map[string]*federatedTable),
+		joins:   params.Joins,
+		sort:    params.Sort,
+		output:  params.OutputFields,
+	}
+
+	resourceByID := make(map[string]*interfaces.Resource, len(params.Resources))
+	for _, r := range params.Resources {
+		resourceByID[r.ID] = r
+	}
+	for _, t := range req.Tables {
+		alias := t.Alias
+		if alias == "" {
+			alias = t.ResourceID
+		}
+		res := resourceByID[t.ResourceID]
+		if res == nil {
+			return nil, fmt.Errorf("resource %s not found", t.ResourceID)
+		}
+		if _, dup := plan.byAlias[alias]; dup {
+			return nil, fmt.Errorf("duplicate table alias %s", alias)
+		}
+		ft := &federatedTable{
+			alias:    alias,
+			resource: res,
+			columns:  make(map[string]bool, len(res.SchemaDefinition)),
+		}
+		for _, prop := range res.SchemaDefinition {
+			ft.columns[prop.Name] = true
+		}
+		plan.tables = append(plan.tables, ft)
+		plan.byAlias[alias] = ft
+	}
+
+	// 过滤条件：按 AND 拆分，仅引用单表的子条件下推，其余在进程内计算。
+	// 外连接中补 null 一侧的表不能下推，否则会改变 WHERE 的语义
+	nullable := plan.nullableAliases()
+	var residual []*interfaces.FilterCondCfg
+	pushed := make(map[string][]*interfaces.FilterCondCfg)
+	for _, conj := range splitConjuncts(params.FilterCondCfg) {
+		aliases, err := plan.referencedAliases(conj)
+		if err != nil {
+			return nil, err
+		}
+		if len(aliases) == 1 {
+			for alias := range aliases {
+				if nullable[alias] {
+					residual = append(residual, conj)
+					continue
+				}
+				stripped, err := plan.stripAlias(conj)
+				if err != nil {
+					return nil, err
+				}
+				pushed[alias] = append(pushed[alias], stripped)
+			}
+			continue
+		}
+		residual = append(residual, conj)
+	}
+	for alias, conds := range pushed {
+		plan.byAlias[alias].filter = andConds(conds)
+	}
+	plan.residual = andConds(residual)
+
+	// 投影：未指定输出列时拉取全部列，否则只拉取输出、JOIN、排序与残余过滤涉及的列
+	if len(plan.output) > 0 {
+		needed := make(map[string]map[string]bool)
+		add := func(field string) error {
+			alias, col, err := plan.resolveField(field)
+			if err != nil {
+				return err
+			}
+			if needed[alias] == nil {
+				needed[alias] = make(map[string]bool)
+			}
+			needed[alias][col] = true
+			return nil
+		}
+		for _, f := range plan.output {
+			if err := add(f); err != nil {
+				return nil, err
+			}
+		}
+		for _, j := range plan.joins {
+			for _, on := range j.On {
+				if err := add(on.LeftField); err != nil {
+					return nil, err
+				}
+				if err := add(on.RightField); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, s := range plan.sort {
+			if err := add(s.Field); err != nil {
+				return nil, err
+			}
+		}
+		if err := walkFieldRefs(plan.residual, add); err != nil {
+			return nil, err
+		}
+		for _, ft := range plan.tables {
+			cols := make([]string, 0, len(needed[ft.alias]))
+			for col := range needed[ft.alias] {
+				cols = append(cols, col)
+			}
+			sort.Strings(cols)
+			// 未被引用的表仍会影响结果行数，拉取全部列
+			if len(cols) == 0 {
+				cols = nil
+			}
+			ft.fields = cols
+		}
+	}
+
+	return plan, nil
+}
+
+// nullableAliases 返回在外连接中可能被补 null 的表别名
+func (p *federatedPlan) nullableAliases() map[string]bool {
+	nullable := make(map[string]bool)
+	joined := make([]string, 0, len(p.tables))
+	for _, j := range p.joins {
+		if len(joined) == 0 {
+			joined = append(joined, j.LeftTableAlias)
+		}
+		switch strings.ToLower(j.Type) {
+		case JoinTypeLeft:
+			nullable[j.RightTableAlias] = true
+		case JoinTypeRight:
+			for _, a := range joined {
+				nullable[a] = true
+			}
+		case JoinTypeFull:
+			nullable[j.RightTableAlias] = true
+			for _, a := range joined {
+				nullable[a] = true
+			}
+		}
+		joined = append(joined, j.RightTableAlias)
+	}
+	return nullable
+}
+
+// resolveField 将 "alias.col" 或 "col" 解析为 (alias, col)；无别名时要求列名在所有表中唯一
+func (p *federatedPlan) resolveField(field string) (string, string, error) {
+	field = strings.TrimSpace(field)
+	if idx := strings.Index(field, "."); idx > 0 {
+		if ft, ok := p.byAlias[field[:idx]]; ok {
+			return ft.alias, field[idx+1:], nil
+		}
+	}
+	var owner string
+	for _, ft := range p.tables {
+		if ft.columns[field] {
+			if owner != "" {
+				return "", "", fmt.Errorf("field %s is ambiguous, please qualify it with a table alias", field)
+			}
+			owner = ft.alias
+		}
+	}
+	if owner == "" {
+		return "", "", fmt.Errorf("field %s not found in tables", field)
+	}
+	return owner, field, nil
+}
+
+// referencedAliases 返回过滤条件引用到的表别名集合
+func (p *federatedPlan) referencedAliases(cfg *interfaces.FilterCondCfg) (map[string]bool, error) {
+	aliases := make(map[string]bool)
+	err := walkFieldRefs(cfg, func(field string) error {
+		alias, _, err := p.resolveField(field)
+		if err != nil {
+			return err
+		}
+		aliases[alias] = true
+		return nil
+	})
+	return aliases, err
+}
+
+// stripAlias 复制过滤条件并去掉字段名的别名前缀，便于按单表 schema 构建条件
+func (p *federatedPlan) stripAlias(cfg *interfaces.FilterCondCfg) (*interfaces.FilterCondCfg, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	out := *cfg
+	if cfg.Name != "" {
+		_, col, err := p.resolveField(cfg.Name)
+		if err != nil {
+			return nil, err
+		}
+		out.Name = col
+	}
+	if cfg.ValueFrom == interfaces.ValueFrom_Field {
+		if name, ok := cfg.Value.(string); ok {
+			_, col, err := p.resolveField(name)
+			if err != nil {
+				return nil, err
+			}
+			out.Value = col
+		}
+	}
+	if len(cfg.SubConds) > 0 {
+		out.SubConds = make([]*interfaces.FilterCondCfg, 0, len(cfg.SubConds))
+		for _, sub := range cfg.SubConds {
+			s, err := p.stripAlias(sub)
+			if err != nil {
+				return nil, err
+			}
+			out.SubConds = append(out.SubConds, s)
+		}
+	}
+	return &out, nil
+}
+
+// fetch 并发拉取每张表的数据，分批下推过滤条件与投影
+func (p *federatedPlan) fetch(ctx context.Context, catalogs map[string]*interfaces.Catalog, open connectorOpener) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for _, ft := range p.tables {
+		wg.Add(1)
+		go func(ft *federatedTable) {
+			defer wg.Done()
+			if err := ft.fetch(ctx, catalogs[ft.resource.CatalogID], open); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(ft)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func (ft *federatedTable) fetch(ctx context.Context, cat *interfaces.Catalog, open connectorOpener) error {
+	querier, err := open(ctx, cat)
+	if err != nil {
+		return rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Query_ExecuteFailed).
+			WithErrorDetails(fmt.Sprintf("table %s: %v", ft.alias, err))
+	}
+	defer querier.Close(ctx)
+
+	fieldMap := make(map[string]*interfaces.Property, len(ft.resource.SchemaDefinition))
+	for _, prop := range ft.resource.SchemaDefinition {
+		fieldMap[prop.Name] = prop
+	}
+	var actualCond interfaces.FilterCondition
+	if ft.filter != nil {
+		actualCond, err = filter_condition.NewFilterCondition(ctx, ft.filter, fieldMap)
+		if err != nil {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_InvalidParameter_FilterCondition).
+				WithErrorDetails(fmt.Sprintf("table %s: %v", ft.alias, err))
+		}
+	}
+
+	ft.rows = make([]map[string]any, 0)
+	pageSort := ft.pageSort()
+	// 索引的 from/size 翻页受 max_result_window 限制且顺序不稳定，改用 PIT + search_after
+	var indexCursor *interfaces.IndexPageCursor
+	if ft.resource.Category == interfaces.ResourceCategoryIndex {
+		indexCursor = &interfaces.IndexPageCursor{}
+		defer ft.closePointInTime(ctx, querier, indexCursor)
+	}
+	for offset := 0; ; offset += FederatedPageSize {
+		params := &interfaces.ResourceDataQueryParams{
+			Offset:           offset,
+			Limit:            FederatedPageSize,
+			Sort:             pageSort,
+			OutputFields:     ft.fields,
+			FilterCondCfg:    ft.filter,
+			ActualFilterCond: actualCond,
+			IndexCursor:      indexCursor,
+		}
+		result, err := querier.ExecuteQuery(ctx, ft.resource, params)
+		if errors.Is(err, interfaces.ErrFileScanLimitExceeded) {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Query_FederatedRowLimitExceeded).
+				WithErrorDetails(fmt.Sprintf("table %s: %v", ft.alias, err))
+		}
+		if err != nil {
+			return rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Query_ExecuteFailed).
+				WithErrorDetails(fmt.Sprintf("table %s: %v", ft.alias, err))
+		}
+		for _, row := range result.Rows {
+			prefixed := make(map[string]any, len(row))
+			for k, v := range row {
+				prefixed[ft.alias+"."+k] = v
+			}
+			ft.rows = append(ft.rows, prefixed)
+		}
+		if len(ft.rows) > FederatedMaxRowsPerTable {
+			return rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Query_FederatedRowLimitExceeded).
+				WithErrorDetails(fmt.Sprintf("table %s returns more than %d rows, please add more selective filters",
+					ft.alias, FederatedMaxRowsPerTable))
+		}
+		// 不分页的 connector 会一次性返回全部结果
+		if len(result.Rows) != FederatedPageSize {
+			break
+		}
+	}
+
+	logger.Debugf("federated fetch table %s, rows: %d", ft.alias, len(ft.rows))
+	return nil
+}
+
+// closePointInTime 释放索引深分页打开的 PIT，失败时只记录日志，PIT 过期后会自动释放
+func (ft *federatedTable) closePointInTime(ctx context.Context, querier resourceQuerier,
+	cursor *interfaces.IndexPageCursor) {
+
+	closer, ok := querier.(pointInTimeCloser)
+	if !ok || cursor.PitID == "" {
+		return
+	}
+	if err := closer.ClosePointInTime(ctx, cursor.PitID); err != nil {
+		logger.Warnf("federated fetch table %s, close point in time failed: %v", ft.alias, err)
+	}
+}
+
+// pageSort 返回分页拉取时的排序字段。关系型数据库不保证无 ORDER BY 时的返回顺序，
+// offset/limit 翻页可能重复或遗漏行，因此优先按主键排序，无主键时按全部拉取列排序
+func (ft *federatedTable) pageSort() []*interfaces.SortField {
+	if ft.resource.Category != interfaces.ResourceCategoryTable {
+		return nil
+	}
+
+	sortFields := primaryKeys(ft.resource)
+	for _, pk := range sortFields {
+		if !ft.columns[pk] {
+			sortFields = nil
+			break
+		}
+	}
+	if len(sortFields) == 0 {
+		sortFields = ft.fields
+	}
+	if len(sortFields) == 0 {
+		sortFields = make([]string, 0, len(ft.resource.SchemaDefinition))
+		for _, prop := range ft.resource.SchemaDefinition {
+			sortFields = append(sortFields, prop.Name)
+		}
+	}
+
+	pageSort := make([]*interfaces.SortField, 0, len(sortFields))
+	for _, field := range sortFields {
+		pageSort = append(pageSort, &interfaces.SortField{Field: field, Direction: interfaces.ASC_DIRECTION})
+	}
+	return pageSort
+}
+
+// primaryKeys 读取发现任务保存在 source_metadata 中的主键列
+func primaryKeys(resource *interfaces.Resource) []string {
+	switch v := resource.SourceMetadata["primary_keys"].(type) {
+	case []string:
+		return v
+	case []any:
+		pks := make([]string, 0, len(v))
+		for _, item := range v {
+			if pk, ok := item.(string); ok && pk != "" {
+				pks = append(pks, pk)
+			}
+		}
+		return pks
+	}
+	return nil
+}
+
+// join 按 joins 顺序在进程内做 hash join；未指定 joins 的多表做笛卡尔积
+func (p *federatedPlan) join() ([]map[string]any, error) {
+	if len(p.joins) == 0 {
+		rows := p.tables[0].rows
+		for _, ft := range p.tables[1:] {
+			rows = crossJoin(rows, ft.rows)
+		}
+		return rows, nil
+	}
+
+	joined := map[string]bool{}
+	var rows []map[string]any
+	for i, j := range p.joins {
+		if i == 0 {
+			left := p.byAlias[j.LeftTableAlias]
+			rows = left.rows
+			joined[left.alias] = true
+		}
+
+		newAlias, joinType := j.RightTableAlias, strings.ToLower(j.Type)
+		if joined[j.RightTableAlias] {
+			if joined[j.LeftTableAlias] {
+				return nil, fmt.Errorf("join between %s and %s forms a cycle", j.LeftTableAlias, j.RightTableAlias)
+			}
+			// 右表已在结果中，反向 JOIN 左表，left/right 语义随之互换
+			newAlias = j.LeftTableAlias
+			switch joinType {
+			case JoinTypeLeft:
+				joinType = JoinTypeRight
+			case JoinTypeRight:
+				joinType = JoinTypeLeft
+			}
+		} else if !joined[j.LeftTableAlias] {
+			return nil, fmt.Errorf("join left alias %s is not joined yet", j.LeftTableAlias)
+		}
+
+		var probeKeys, buildKeys []string
+		for _, on := range j.On {
+			la, lc, err := p.resolveField(on.LeftField)
+			if err != nil {
+				return nil, err
+			}
+			ra, rc, err := p.resolveField(on.RightField)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case ra == newAlias && la != newAlias:
+				probeKeys = append(probeKeys, la+"."+lc)
+				buildKeys = append(buildKeys, ra+"."+rc)
+			case la == newAlias && ra != newAlias:
+				probeKeys = append(probeKeys, ra+"."+rc)
+				buildKeys = append(buildKeys, la+"."+lc)
+			default:
+				return nil, fmt.Errorf("join condition %s = %s must reference table %s exactly once",
+					on.LeftField, on.RightField, newAlias)
+			}
+		}
+		if len(probeKeys) == 0 {
+			return nil, fmt.Errorf("join between %s and %s has no on condition", j.LeftTableAlias, j.RightTableAlias)
+		}
+
+		var err error
+		rows, err = hashJoin(rows, p.byAlias[newAlias].rows, probeKeys, buildKeys, joinType)
+		if err != nil {
+			return nil, err
+		}
+		joined[newAlias] = true
+	}
+
+	// 未出现在 joins 中的表按笛卡尔积处理，与单源 SQL 的 FROM t1, t2 语义一致
+	for _, ft := range p.tables {
+		if !joined[ft.alias] {
+			rows = crossJoin(rows, ft.rows)
+		}
+	}
+	return rows, nil
+}
+
+// sortRows 按 sort 字段进行稳定排序，null 值排在最前
+func (p *federatedPlan) sortRows(rows []map[string]any) error {
+	if len(p.sort) == 0 {
+		return nil
+	}
+	keys := make([]string, len(p.sort))
+	for i, s := range p.sort {
+		alias, col, err := p.resolveField(s.Field)
+		if err != nil {
+			return err
+		}
+		keys[i] = alias + "." + col
+	}
+	sort.SliceStable(rows, func(a, b int) bool {
+		for i, key := range keys {
+			c := compareValues(rows[a][key], rows[b][key])
+			if c == 0 {
+				continue
+			}
+			if p.sort[i].Direction == interfaces.DESC_DIRECTION {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	return nil
+}
+
+// project 按 output_fields 输出列；未指定时输出所有 alias.col 列
+func (p *federatedPlan) project(rows []map[string]any) ([]string, []map[string]any, error) {
+	if len(p.output) == 0 {
+		colSet := make(map[string]bool)
+		for _, row := range rows {
+			for k := range row {
+				colSet[k] = true
+			}
+		}
+		cols := make([]string, 0, len(colSet))
+		for k := range colSet {
+			cols = append(cols, k)
+		}
+		sort.Strings(cols)
+		return cols, rows, nil
+	}
+
+	keys := make([]string, len(p.output))
+	for i, f := range p.output {
+		alias, col, err := p.resolveField(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys[i] = alias + "." + col
+	}
+	out := make([]map[string]any, 0, len(rows))
+	for _, row := range rows {
+		projected := make(map[string]any, len(keys))
+		for i, key := range keys {
+			projected[p.output[i]] = row[key]
+		}
+		out = append(out, projected)
+	}
+	return p.output, out, nil
+}
+
+// splitConjuncts 将顶层 AND 条件拆分为子条件列表
+func splitConjuncts(cfg *interfaces.FilterCondCfg) []*interfaces.FilterCondCfg {
+	if cfg == nil || (cfg.Name == "" && cfg.Operation == "" && len(cfg.SubConds) == 0) {
+		return nil
+	}
+	if cfg.Operation == filter_condition.OperationAnd {
+		var out []*interfaces.FilterCondCfg
+		for _, sub := range cfg.SubConds {
+			out = append(out, splitConjuncts(sub)...)
+		}
+		return out
+	}
+	return []*interfaces.FilterCondCfg{cfg}
+}
+
+// andConds 将多个条件合并为一个 AND 条件
+func andConds(conds []*interfaces.FilterCondCfg) *interfaces.FilterCondCfg {
+	switch len(conds) {
+	case 0:
+		return nil
+	case 1:
+		return conds[0]
+	default:
+		return &interfaces.FilterCondCfg{Operation: filter_condition.OperationAnd, SubConds: conds}
+	}
+}
+
+// walkFieldRefs 遍历过滤条件中引用的全部字段
+func walkFieldRefs(cfg *interfaces.FilterCondCfg, fn func(field string) error) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Name != "" {
+		if err := fn(cfg.Name); err != nil {
+			return err
+		}
+	}
+	if cfg.ValueFrom == interfaces.ValueFrom_Field {
+		if name, ok := cfg.Value.(string); ok && name != "" {
+			if err := fn(name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, sub := range cfg.SubConds {
+		if err := walkFieldRefs(sub, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/connectors"
+)
+
+// fakeCatalogService 仅实现 GetByID
+type fakeCatalogService struct {
+	interfaces.CatalogService
+}
+
+func (f *fakeCatalogService) GetByID(ctx context.Context, id string, withSensitiveFields bool) (*interfaces.Catalog, error) {
+	return &interfaces.Catalog{ID: id}, nil
+}
+
+// fakeQuerier 在内存中按下推的过滤条件返回数据，并记录下推参数
+type fakeQuerier struct {
+	connectors.Connector
+	rows   map[string][]map[string]any // resource_id -> rows
+	pushed map[string]*interfaces.ResourceDataQueryParams
+}
+
+func (f *fakeQuerier) Close(ctx context.Context) error { return nil }
+
+func (f *fakeQuerier) ExecuteQuery(ctx context.Context, resource *interfaces.Resource,
+	params *interfaces.ResourceDataQueryParams) (*interfaces.QueryResult, error) {
+
+	f.pushed[resource.ID] = params
+	plan := &federatedPlan{byAlias: map[string]*federatedTable{}}
+	ft := &federatedTable{alias: "x", columns: map[string]bool{}}
+	for _, p := range resource.SchemaDefinition {
+		ft.columns[p.Name] = true
+	}
+	plan.tables = []*federatedTable{ft}
+	plan.byAlias["x"] = ft
+
+	rows := make([]map[string]any, 0)
+	for _, row := range f.rows[resource.ID] {
+		if params.FilterCondCfg != nil {
+			prefixed := map[string]any{}
+			for k, v := range row {
+				prefixed["x."+k] = v
+			}
+			ok, err := plan.evalCondition(params.FilterCondCfg, prefixed)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		rows = append(rows, row)
+	}
+	return &interfaces.QueryResult{Rows: rows, Total: int64(len(rows))}, nil
+}
+
+func newFederatedFixture() (*queryService, *fakeQuerier, []*interfaces.Resource) {
+	users := &interfaces.Resource{
+		ID: "users", CatalogID: "mariadb",
+		SchemaDefinition: []*interfaces.Property{{Name: "id"}, {Name: "name"}, {Name: "dept"}},
+	}
+	orders := &interfaces.Resource{
+		ID: "orders", CatalogID: "oracle",
+		SchemaDefinition: []*interfaces.Property{{Name: "order_id"}, {Name: "user_id"}, {Name: "amount"}},
+	}
+	fq := &fakeQuerier{
+		rows: map[string][]map[string]any{
+			"users": {
+				{"id": int64(1), "name": "alice", "dept": "rd"},
+				{"id": int64(2), "name": "bob", "dept": "rd"},
+				{"id": int64(3), "name": "carol", "dept": "ops"},
+			},
+			"orders": {
+				{"order_id": "o1", "user_id": "1", "amount": 10.5},
+				{"order_id": "o2", "user_id": "1", "amount": 3.0},
+				{"order_id": "o3", "user_id": "2", "amount": 7.0},
+				{"order_id": "o4", "user_id": "9", "amount": 1.0},
+			},
+		},
+		pushed: map[string]*interfaces.ResourceDataQueryParams{},
+	}
+	qs := &queryService{
+		cs: &fakeCatalogService{},
+		openConnector: func(ctx context.Context, cat *interfaces.Catalog) (resourceQuerier, error) {
+			return fq, nil
+		},
+	}
+	return qs, fq, []*interfaces.Resource{users, orders}
+}
+
+func federatedRequest(joinType string) *interfaces.QueryExecuteRequest {
+	return &interfaces.QueryExecuteRequest{
+		Tables: []interfaces.TableInQuery{{ResourceID: "users", Alias: "u"}, {ResourceID: "orders", Alias: "o"}},
+		Joins: []interfaces.JoinSpec{{
+			Type: joinType, LeftTableAlias: "u", RightTableAlias: "o",
+			On: []interfaces.JoinOnCond{{LeftField: "u.id", RightField: "o.user_id"}},
+		}},
+		Limit: 100,
+	}
+}
+
+func runFederated(t *testing.T, qs *queryService, req *interfaces.QueryExecuteRequest,
+	resources []*interfaces.Resource, filter *interfaces.FilterCondCfg) *interfaces.QueryResult {
+
+	t.Helper()
+	params := &interfaces.JoinQueryParams{
+		Resources:     resources,
+		Joins:         ptrSlice(req.Joins),
+		OutputFields:  req.OutputFields,
+		FilterCondCfg: filter,
+		Sort:          req.Sort,
+		Offset:        req.Offset,
+		Limit:         req.Limit,
+	}
+	result, err := qs.executeFederated(context.Background(), req, params)
+	if err != nil {
+		t.Fatalf("executeFederated failed: %v", err)
+	}
+	return result
+}
+
+func TestExecuteFederated_JoinTypes(t *testing.T) {
+	tests := []struct {
+		joinType string
+		total    int64
+	}{
+		{JoinTypeInner, 3}, // alice x2, bob x1；int 与数值字符串可互相匹配
+		{JoinTypeLeft, 4},  // + carol
+		{JoinTypeRight, 4}, // + o4
+		{JoinTypeFull, 5},  // + carol + o4
+	}
+	for _, tt := range tests {
+		qs, _, resources := newFederatedFixture()
+		result := runFederated(t, qs, federatedRequest(tt.joinType), resources, nil)
+		if result.Total != tt.total {
+			t.Errorf("join %s: expected total %d, got %d", tt.joinType, tt.total, result.Total)
+		}
+	}
+}
+
+func TestExecuteFederated_PushdownSortAndPage(t *testing.T) {
+	qs, fq, resources := newFederatedFixture()
+	req := federatedRequest(JoinTypeInner)
+	req.OutputFields = []string{"u.name", "o.amount"}
+	req.Sort = []*interfaces.SortField{{Field: "o.amount", Direction: interfaces.DESC_DIRECTION}}
+	req.Offset = 1
+	req.Limit = 1
+	filter := &interfaces.FilterCondCfg{
+		Operation: "and",
+		SubConds: []*interfaces.FilterCondCfg{
+			{Name: "u.dept", Operation: "==", ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_Const, Value: "rd"}},
+			{Name: "o.amount", Operation: ">", ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_Const, Value: 2}},
+		},
+	}
+
+	result := runFederated(t, qs, req, resources, filter)
+	if result.Total != 3 {
+		t.Fatalf("expected total 3, got %d", result.Total)
+	}
+	if len(result.Rows) != 1 || result.Rows[0]["u.name"] != "bob" || result.Rows[0]["o.amount"] != 7.0 {
+		t.Errorf("unexpected page: %v", result.Rows)
+	}
+
+	// 单表条件应下推且去掉别名前缀，投影只包含需要的列
+	if p := fq.pushed["users"]; p.FilterCondCfg == nil || p.FilterCondCfg.Name != "dept" {
+		t.Errorf("expected users filter pushed down, got %+v", p.FilterCondCfg)
+	}
+	if p := fq.pushed["orders"]; p.FilterCondCfg == nil || p.FilterCondCfg.Name != "amount" {
+		t.Errorf("expected orders filter pushed down, got %+v", p.FilterCondCfg)
+	}
+	if got := fq.pushed["users"].OutputFields; len(got) != 2 || got[0] != "id" || got[1] != "name" {
+		t.Errorf("unexpected users projection: %v", got)
+	}
+}
+
+func TestExecuteFederated_NoPushdownOnNullableSide(t *testing.T) {
+	qs, fq, resources := newFederatedFixture()
+	filter := &interfaces.FilterCondCfg{
+		Name: "o.order_id", Operation: "null",
+	}
+	result := runFederated(t, qs, federatedRequest(JoinTypeLeft), resources, filter)
+
+	if fq.pushed["orders"].FilterCondCfg != nil {
+		t.Errorf("filter on null-supplying side must not be pushed down")
+	}
+	if result.Total != 1 || result.Rows[0]["u.name"] != "carol" {
+		t.Errorf("expected only carol without orders, got %v", result.Rows)
+	}
+}
+
+func TestExecuteFederated_CrossTableResidualFilter(t *testing.T) {
+	qs, _, resources := newFederatedFixture()
+	filter := &interfaces.FilterCondCfg{
+		Name: "o.order_id", Operation: "!=",
+		ValueOptCfg: interfaces.ValueOptCfg{ValueFrom: interfaces.ValueFrom_Field, Value: "u.name"},
+	}
+	result := runFederated(t, qs, federatedRequest(JoinTypeInner), resources, filter)
+	if result.Total != 3 {
+		t.Errorf("expected total 3, got %d", result.Total)
+	}
+}
+
+func TestNewFederatedPlan_AmbiguousField(t *testing.T) {
+	_, _, resources := newFederatedFixture()
+	resources[1].SchemaDefinition = append(resources[1].SchemaDefinition, &interfaces.Property{Name: "name"})
+	req := federatedRequest(JoinTypeInner)
+	req.OutputFields = []string{"name"}
+	_, err := newFederatedPlan(req, &interfaces.JoinQueryParams{
+		Resources: resources, Joins: ptrSlice(req.Joins), OutputFields: req.OutputFields,
+	})
+	if err == nil {
+		t.Fatal("expected ambiguous field error")
+	}
+}
+
+func TestFederatedTable_PageSort(t *testing.T) {
+	schema := []*interfaces.Property{{Name: "id"}, {Name: "name"}, {Name: "dept"}}
+	tests := []struct {
+		name     string
+		category string
+		metadata map[string]any
+		fields   []string
+		want     []string
+	}{
+		{"primary keys", interfaces.ResourceCategoryTable, map[string]any{"primary_keys": []any{"id"}}, []string{"name"}, []string{"id"}},
+		{"unknown primary key", interfaces.ResourceCategoryTable, map[string]any{"primary_keys": []string{"uid"}}, []string{"name"}, []string{"name"}},
+		{"projected fields", interfaces.ResourceCategoryTable, nil, []string{"name", "dept"}, []string{"name", "dept"}},
+		{"all columns", interfaces.ResourceCategoryTable, nil, nil, []string{"id", "name", "dept"}},
+		{"not a table", interfaces.ResourceCategoryIndex, map[string]any{"primary_keys": []any{"id"}}, nil, nil},
+	}
+	for _, tt := range tests {
+		ft := &federatedTable{
+			resource: &interfaces.Resource{Category: tt.category, SourceMetadata: tt.metadata, SchemaDefinition: schema},
+			columns:  map[string]bool{"id": true, "name": true, "dept": true},
+			fields:   tt.fields,
+		}
+		got := ft.pageSort()
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected sort %v, got %d fields", tt.name, tt.want, len(got))
+			continue
+		}
+		for i, sf := range got {
+			if sf.Field != tt.want[i] || sf.Direction != interfaces.ASC_DIRECTION {
+				t.Errorf("%s: expected sort %v, got field %s %s at %d", tt.name, tt.want, sf.Field, sf.Direction, i)
+			}
+		}
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		a, b any
+		want int
+	}{
+		{nil, nil, 0},
+		{nil, 1, -1},
+		{int64(2), 1.5, 1},
+		{"10", int32(10), 0},
+		{"abc", "abd", -1},
+		{false, true, -1},
+	}
+	for _, tt := range tests {
+		if got := compareValues(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareValues(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// fakeIndexQuerier 模拟 OpenSearch 的 PIT + search_after 翻页，拒绝超过 max_result_window 的 from/size 请求
+type fakeIndexQuerier struct {
+	connectors.Connector
+	docs   []map[string]any // 按 _id 升序
+	pages  int
+	closed []string
+}
+
+func (f *fakeIndexQuerier) Close(ctx context.Context) error { return nil }
+
+func (f *fakeIndexQuerier) ExecuteQuery(ctx context.Context, resource *interfaces.Resource,
+	params *interfaces.ResourceDataQueryParams) (*interfaces.QueryResult, error) {
+
+	cursor := params.IndexCursor
+	if cursor == nil {
+		if params.Offset+params.Limit > interfaces.MAX_SEARCH_SIZE {
+			return nil, fmt.Errorf("result window is too large")
+		}
+		return &interfaces.QueryResult{Rows: f.docs[params.Offset:min(params.Offset+params.Limit, len(f.docs))]}, nil
+	}
+
+	f.pages++
+	if cursor.PitID == "" {
+		cursor.PitID = "pit-1"
+	}
+	start := 0
+	if len(cursor.SearchAfter) > 0 {
+		after := cursor.SearchAfter[0].(string)
+		start = sort.Search(len(f.docs), func(i int) bool { return f.docs[i]["_id"].(string) > after })
+	}
+	end := min(start+params.Limit, len(f.docs))
+	rows := f.docs[start:end]
+	if len(rows) > 0 {
+		cursor.SearchAfter = []any{rows[len(rows)-1]["_id"]}
+	}
+	return &interfaces.QueryResult{Rows: rows}, nil
+}
+
+func (f *fakeIndexQuerier) ClosePointInTime(ctx context.Context, pitID string) error {
+	f.closed = append(f.closed, pitID)
+	return nil
+}
+
+func TestFederatedTable_FetchIndexBeyondResultWindow(t *testing.T) {
+	total := FederatedPageSize + FederatedPageSize/2
+	docs := make([]map[string]any, 0, total)
+	for i := 0; i < total; i++ {
+		docs = append(docs, map[string]any{"_id": fmt.Sprintf("%08d", i), "v": i})
+	}
+	fq := &fakeIndexQuerier{docs: docs}
+	ft := &federatedTable{
+		alias:    "l",
+		resource: &interfaces.Resource{ID: "logs", Category: interfaces.ResourceCategoryIndex},
+		columns:  map[string]bool{"_id": true, "v": true},
+	}
+	open := func(ctx context.Context, cat *interfaces.Catalog) (resourceQuerier, error) { return fq, nil }
+
+	if err := ft.fetch(context.Background(), &interfaces.Catalog{}, open); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if len(ft.rows) != total {
+		t.Fatalf("expected %d rows, got %d", total, len(ft.rows))
+	}
+	seen := make(map[string]bool, total)
+	for _, row := range ft.rows {
+		id := row["l._id"].(string)
+		if seen[id] {
+			t.Fatalf("duplicated row %s", id)
+		}
+		seen[id] = true
+	}
+	if fq.pages != 2 {
+		t.Errorf("expected 2 pages, got %d", fq.pages)
+	}
+	if len(fq.closed) != 1 || fq.closed[0] != "pit-1" {
+		t.Errorf("expected pit closed once, got %v", fq.closed)
+	}
+}
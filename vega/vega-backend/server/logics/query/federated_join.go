@@ -0,0 +1,358 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package query
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/filter_condition"
+)
+
+// JOIN 类型
+const (
+	JoinTypeInner = "inner"
+	JoinTypeLeft  = "left"
+	JoinTypeRight = "right"
+	JoinTypeFull  = "full"
+)
+
+// hashJoin 以新表 build 哈希表，用已有结果 probe；null 键不参与匹配，与 SQL 语义一致
+func hashJoin(probe, build []map[string]any, probeKeys, buildKeys []string, joinType string) ([]map[string]any, error) {
+	switch joinType {
+	case "", JoinTypeInner, JoinTypeLeft, JoinTypeRight, JoinTypeFull:
+	default:
+		return nil, fmt.Errorf("unsupported join type %s", joinType)
+	}
+
+	table := make(map[string][]int, len(build))
+	for i, row := range build {
+		key, ok := joinKey(row, buildKeys)
+		if !ok {
+			continue
+		}
+		table[key] = append(table[key], i)
+	}
+
+	keepProbe := joinType == JoinTypeLeft || joinType == JoinTypeFull
+	keepBuild := joinType == JoinTypeRight || joinType == JoinTypeFull
+
+	matched := make([]bool, len(build))
+	out := make([]map[string]any, 0, len(probe))
+	for _, prow := range probe {
+		key, ok := joinKey(prow, probeKeys)
+		var hits []int
+		if ok {
+			hits = table[key]
+		}
+		if len(hits) == 0 {
+			if keepProbe {
+				out = append(out, prow)
+			}
+			continue
+		}
+		for _, idx := range hits {
+			matched[idx] = true
+			out = append(out, mergeRows(prow, build[idx]))
+		}
+	}
+	if keepBuild {
+		for i, brow := range build {
+			if !matched[i] {
+				out = append(out, brow)
+			}
+		}
+	}
+	return out, nil
+}
+
+// crossJoin 笛卡尔积
+func crossJoin(left, right []map[string]any) []map[string]any {
+	out := make([]map[string]any, 0, len(left)*len(right))
+	for _, l := range left {
+		for _, r := range right {
+			out = append(out, mergeRows(l, r))
+		}
+	}
+	return out
+}
+
+func mergeRows(a, b map[string]any) map[string]any {
+	row := make(map[string]any, len(a)+len(b))
+	for k, v := range a {
+		row[k] = v
+	}
+	for k, v := range b {
+		row[k] = v
+	}
+	return row
+}
+
+// joinKey 生成 JOIN 键；不同数据源返回的数值类型不一致（int64/float64/数值字符串），统一归一化后比较
+func joinKey(row map[string]any, keys []string) (string, bool) {
+	var sb strings.Builder
+	for i, k := range keys {
+		v := row[k]
+		if v == nil {
+			return "", false
+		}
+		if i > 0 {
+			sb.WriteByte(0)
+		}
+		if f, ok := toFloat(v); ok {
+			sb.WriteString("n:")
+			sb.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+			continue
+		}
+		if t, ok := v.(time.Time); ok {
+			sb.WriteString("t:")
+			sb.WriteString(t.UTC().Format(time.RFC3339Nano))
+			continue
+		}
+		sb.WriteString("s:")
+		sb.WriteString(toString(v))
+	}
+	return sb.String(), true
+}
+
+// toFloat 将数值或数值字符串转换为 float64
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case []byte:
+		return toFloat(string(n))
+	case string:
+		s := strings.TrimSpace(n)
+		if s == "" {
+			return 0, false
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, false
+		}
+		return f, true
+	case interface{ String() string }:
+		// json.Number、decimal 等
+		return toFloat(n.String())
+	}
+	return 0, false
+}
+
+func toString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	case time.Time:
+		return s.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+// compareValues 比较两个值：null 最小，其次按数值、时间、布尔、字符串比较
+func compareValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Compare(tb)
+		}
+	}
+	if ba, ok := a.(bool); ok {
+		if bb, ok := b.(bool); ok {
+			switch {
+			case ba == bb:
+				return 0
+			case !ba:
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(toString(a), toString(b))
+}
+
+// evalCondition 在进程内对 JOIN 后的行计算无法下推的过滤条件，比较 null 时结果为 false
+func (p *federatedPlan) evalCondition(cfg *interfaces.FilterCondCfg, row map[string]any) (bool, error) {
+	switch cfg.Operation {
+	case filter_condition.OperationAnd:
+		for _, sub := range cfg.SubConds {
+			ok, err := p.evalCondition(sub, row)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case filter_condition.OperationOr:
+		for _, sub := range cfg.SubConds {
+			ok, err := p.evalCondition(sub, row)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	alias, col, err := p.resolveField(cfg.Name)
+	if err != nil {
+		return false, err
+	}
+	left := row[alias+"."+col]
+
+	right := cfg.Value
+	if cfg.ValueFrom == interfaces.ValueFrom_Field {
+		name, _ := cfg.Value.(string)
+		ra, rc, err := p.resolveField(name)
+		if err != nil {
+			return false, err
+		}
+		right = row[ra+"."+rc]
+	}
+
+	switch cfg.Operation {
+	case filter_condition.OperationNull:
+		return left == nil, nil
+	case filter_condition.OperationNotNull:
+		return left != nil, nil
+	case filter_condition.OperationExist:
+		_, ok := row[alias+"."+col]
+		return ok && left != nil, nil
+	case filter_condition.OperationNotExist:
+		_, ok := row[alias+"."+col]
+		return !ok || left == nil, nil
+	case filter_condition.OperationEmpty:
+		return left != nil && toString(left) == "", nil
+	case filter_condition.OperationNotEmpty:
+		return left != nil && toString(left) != "", nil
+	case filter_condition.OperationTrue:
+		return left == true, nil
+	case filter_condition.OperationFalse:
+		return left == false, nil
+	}
+
+	if left == nil {
+		return false, nil
+	}
+
+	switch cfg.Operation {
+	case filter_condition.OperationEqual, filter_condition.OperationEqual2:
+		return right != nil && compareValues(left, right) == 0, nil
+	case filter_condition.OperationNotEqual, filter_condition.OperationNotEqual2:
+		return right != nil && compareValues(left, right) != 0, nil
+	case filter_condition.OperationGt, filter_condition.OperationGt2:
+		return right != nil && compareValues(left, right) > 0, nil
+	case filter_condition.OperationGte, filter_condition.OperationGte2:
+		return right != nil && compareValues(left, right) >= 0, nil
+	case filter_condition.OperationLt, filter_condition.OperationLt2:
+		return right != nil && compareValues(left, right) < 0, nil
+	case filter_condition.OperationLte, filter_condition.OperationLte2:
+		return right != nil && compareValues(left, right) <= 0, nil
+	case filter_condition.OperationIn, filter_condition.OperationNotIn:
+		values, err := toSlice(right)
+		if err != nil {
+			return false, fmt.Errorf("condition [%s] %v", cfg.Operation, err)
+		}
+		found := false
+		for _, v := range values {
+			if v != nil && compareValues(left, v) == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (cfg.Operation == filter_condition.OperationIn), nil
+	case filter_condition.OperationRange, filter_condition.OperationBetween, filter_condition.OperationOutRange:
+		values, err := toSlice(right)
+		if err != nil || len(values) != 2 {
+			return false, fmt.Errorf("condition [%s] requires exactly 2 values", cfg.Operation)
+		}
+		in := compareValues(left, values[0]) >= 0 && compareValues(left, values[1]) <= 0
+		return in == (cfg.Operation != filter_condition.OperationOutRange), nil
+	case filter_condition.OperationLike, filter_condition.OperationContain:
+		return strings.Contains(toString(left), toString(right)), nil
+	case filter_condition.OperationNotLike, filter_condition.OperationNotContain:
+		return !strings.Contains(toString(left), toString(right)), nil
+	case filter_condition.OperationPrefix:
+		return strings.HasPrefix(toString(left), toString(right)), nil
+	case filter_condition.OperationNotPrefix:
+		return !strings.HasPrefix(toString(left), toString(right)), nil
+	case filter_condition.OperationRegex:
+		re, err := regexp.Compile(toString(right))
+		if err != nil {
+			return false, fmt.Errorf("condition [regex] invalid pattern: %v", err)
+		}
+		return re.MatchString(toString(left)), nil
+	default:
+		return false, fmt.Errorf("operation %s is not supported in cross-catalog query", cfg.Operation)
+	}
+}
+
+func toSlice(v any) ([]any, error) {
+	if v == nil {
+		return nil, fmt.Errorf("value should be an array")
+	}
+	if arr, ok := v.([]any); ok {
+		return arr, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("value should be an array")
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out, nil
+}
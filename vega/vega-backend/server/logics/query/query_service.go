@@ -18,6 +18,7 @@ import (
 	"github.com/mitchellh/mapstructure"
 	"github.com/rs/xid"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	verrors "vega-backend/errors"
 	"vega-backend/interfaces"
@@ -34,6 +35,9 @@ type queryService struct {
 	cs interfaces.CatalogService
 	rs interfaces.ResourceService
 	ss interfaces.QuerySessionStore
+
+	// openConnector 联邦查询时创建 connector，为空时使用 connector factory
+	openConnector connectorOpener
 }
 
 // NewQueryService 创建查询服务
@@ -90,10 +94,8 @@ func (qs *queryService) Execute(ctx context.Context, req *interfaces.QueryExecut
 	for _, r := range resources {
 		catalogIDs[r.CatalogID] = true
 	}
-	if len(catalogIDs) > 1 {
-		return nil, rest.NewHTTPError(ctx, http.StatusNotImplemented, verrors.VegaBackend_Query_MultiCatalogNotSupported).
-			WithErrorDetails("暂不支持多数据源 JOIN，计划使用 Trino/DuckDB 实现。")
-	}
+	// 跨 catalog 时走进程内联邦执行
+	federated := len(catalogIDs) > 1
 
 	var catalogID string
 	for id := range catalogIDs {
@@ -117,17 +119,6 @@ func (qs *queryService) Execute(ctx context.Context, req *interfaces.QueryExecut
 		}
 	}
 
-	// 5. 获取 catalog
-	cat, err := qs.cs.GetByID(ctx, catalogID, true)
-	if err != nil {
-		span.SetStatus(codes.Error, "get catalog failed")
-		return nil, err.(*rest.HTTPError)
-	}
-	if cat == nil {
-		return nil, rest.NewHTTPError(ctx, http.StatusNotFound, verrors.VegaBackend_Query_CatalogNotFound).
-			WithErrorDetails(fmt.Sprintf("catalog %s not found", catalogID))
-	}
-
 	// 6. 构建 filter condition
 	var filterCond interfaces.FilterCondition
 	fieldMap := make(map[string]*interfaces.Property)
@@ -175,26 +166,7 @@ func (qs *queryService) Execute(ctx context.Context, req *interfaces.QueryExecut
 	}
 	_ = qs.ss.Touch(ctx, req.QueryID)
 
-	// 8. 创建 connector 并执行
-	connector, err := factory.GetFactory().CreateConnectorInstance(ctx, cat.ConnectorType, cat.ConnectorCfg)
-	if err != nil {
-		span.SetStatus(codes.Error, "create connector failed")
-		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Query_ExecuteFailed).
-			WithErrorDetails(err.Error())
-	}
-	if err := connector.Connect(ctx); err != nil {
-		span.SetStatus(codes.Error, "connect failed")
-		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Query_ExecuteFailed).
-			WithErrorDetails(err.Error())
-	}
-	defer connector.Close(ctx)
-
-	tableConnector, ok := connector.(connectors.TableConnector)
-	if !ok {
-		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Query_ExecuteFailed).
-			WithErrorDetails("connector does not support table join query")
-	}
-
+	// 8. 执行查询
 	joinParams := &interfaces.JoinQueryParams{
 		Resources:         resources,
 		ResourceIDToAlias: resourceIDToAlias,
@@ -209,12 +181,16 @@ func (qs *queryService) Execute(ctx context.Context, req *interfaces.QueryExecut
 		CursorEncoded:     cursorEncoded,
 	}
 
-	result, err := tableConnector.ExecuteJoinQuery(ctx, cat, joinParams)
+	var result *interfaces.QueryResult
+	if federated {
+		// 联邦执行基于 offset 分页，不使用 keyset 游标
+		joinParams.CursorEncoded = ""
+		result, err = qs.executeFederated(ctx, req, joinParams)
+	} else {
+		result, err = qs.executeSingleCatalog(ctx, catalogID, joinParams)
+	}
 	if err != nil {
-		logger.Errorf("ExecuteJoinQuery failed: %v", err)
-		span.SetStatus(codes.Error, "execute query failed")
-		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Query_ExecuteFailed).
-			WithErrorDetails(err.Error())
+		return nil, err
 	}
 
 	// 9. 写回游标
@@ -260,6 +236,51 @@ func (qs *queryService) Execute(ctx context.Context, req *interfaces.QueryExecut
 	return resp, nil
 }
 
+// executeSingleCatalog 单 catalog 查询，整体下推到数据源执行
+func (qs *queryService) executeSingleCatalog(ctx context.Context, catalogID string,
+	joinParams *interfaces.JoinQueryParams) (*interfaces.QueryResult, error) {
+
+	span := trace.SpanFromContext(ctx)
+
+	cat, err := qs.cs.GetByID(ctx, catalogID, true)
+	if err != nil {
+		span.SetStatus(codes.Error, "get catalog failed")
+		return nil, err.(*rest.HTTPError)
+	}
+	if cat == nil {
+		return nil, rest.NewHTTPError(ctx, http.StatusNotFound, verrors.VegaBackend_Query_CatalogNotFound).
+			WithErrorDetails(fmt.Sprintf("catalog %s not found", catalogID))
+	}
+
+	connector, err := factory.GetFactory().CreateConnectorInstance(ctx, cat.ConnectorType, cat.ConnectorCfg)
+	if err != nil {
+		span.SetStatus(codes.Error, "create connector failed")
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Query_ExecuteFailed).
+			WithErrorDetails(err.Error())
+	}
+	if err := connector.Connect(ctx); err != nil {
+		span.SetStatus(codes.Error, "connect failed")
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Query_ExecuteFailed).
+			WithErrorDetails(err.Error())
+	}
+	defer connector.Close(ctx)
+
+	tableConnector, ok := connector.(connectors.TableConnector)
+	if !ok {
+		return nil, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Query_ExecuteFailed).
+			WithErrorDetails("connector does not support table join query")
+	}
+
+	result, err := tableConnector.ExecuteJoinQuery(ctx, cat, joinParams)
+	if err != nil {
+		logger.Errorf("ExecuteJoinQuery failed: %v", err)
+		span.SetStatus(codes.Error, "execute query failed")
+		return nil, rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Query_ExecuteFailed).
+			WithErrorDetails(err.Error())
+	}
+	return result, nil
+}
+
 func (qs *queryService) validateRequest(ctx context.Context, req *interfaces.QueryExecuteRequest) error {
 	if ctx == nil {
 		ctx = context.Background()
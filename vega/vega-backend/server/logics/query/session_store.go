@@ -8,9 +8,14 @@ package query
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/redis/go-redis/v9"
+
+	"vega-backend/common"
 	"vega-backend/interfaces"
 )
 
@@ -21,12 +26,41 @@ const (
 	CleanupInterval = 5 * time.Minute
 )
 
+// session 存储类型
+const (
+	SessionStoreMemory = "memory"
+	SessionStoreRedis  = "redis"
+)
+
+// NewQuerySessionStore 按配置创建 session 存储
+// 内存存储仅在单副本下可用，多副本部署时后续分页请求可能落到其他副本，需使用 redis
+func NewQuerySessionStore(appSetting *common.AppSetting) interfaces.QuerySessionStore {
+	setting := appSetting.QuerySessionSetting
+	switch setting.Store {
+	case "", SessionStoreMemory:
+		return NewMemorySessionStore(setting.TTL)
+	case SessionStoreRedis:
+		client := redis.NewClient(&redis.Options{
+			Addr:        fmt.Sprintf("%s:%d", appSetting.RedisSetting.Host, appSetting.RedisSetting.Port),
+			Username:    appSetting.RedisSetting.Username,
+			Password:    appSetting.RedisSetting.Password,
+			DialTimeout: 5 * time.Second,
+		})
+		logger.Infof("Query session store: redis, ttl: %v", setting.TTL)
+		return NewRedisSessionStore(client, setting.TTL)
+	default:
+		logger.Fatalf("unsupported query session store: %s", setting.Store)
+		return nil
+	}
+}
+
 // memorySessionStore 内存实现的 QuerySessionStore
 type memorySessionStore struct {
 	mu       sync.RWMutex
 	sessions map[string]*querySession
 	ttl      time.Duration
 	stopCh   chan struct{}
+	now      func() time.Time // 便于测试替换时钟
 }
 
 type querySession struct {
@@ -40,6 +74,7 @@ func NewMemorySessionStore(ttl time.Duration) interfaces.QuerySessionStore {
 		sessions: make(map[string]*querySession),
 		ttl:      ttl,
 		stopCh:   make(chan struct{}),
+		now:      time.Now,
 	}
 	if s.ttl == 0 {
 		s.ttl = SessionTTL
@@ -48,13 +83,13 @@ func NewMemorySessionStore(ttl time.Duration) interfaces.QuerySessionStore {
 	return s
 }
 
-// GetCursor 获取游标
+// GetCursor 获取游标，已过期但尚未清理的 session 视为不存在
 func (s *memorySessionStore) GetCursor(ctx context.Context, queryID string, offset int) (string, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	sess, ok := s.sessions[queryID]
-	if !ok || sess == nil {
+	if !ok || sess == nil || s.expired(sess) {
 		return "", false
 	}
 	cursor, ok := sess.cursors[offset]
@@ -67,15 +102,14 @@ func (s *memorySessionStore) SetCursor(ctx context.Context, queryID string, offs
 	defer s.mu.Unlock()
 
 	sess, ok := s.sessions[queryID]
-	if !ok {
+	if !ok || s.expired(sess) {
 		sess = &querySession{
-			cursors:    make(map[int]string),
-			lastAccess: time.Now(),
+			cursors: make(map[int]string),
 		}
 		s.sessions[queryID] = sess
 	}
 	sess.cursors[offset] = cursorEncoded
-	sess.lastAccess = time.Now()
+	sess.lastAccess = s.now()
 	return nil
 }
 
@@ -85,12 +119,21 @@ func (s *memorySessionStore) Touch(ctx context.Context, queryID string) error {
 	defer s.mu.Unlock()
 
 	sess, ok := s.sessions[queryID]
-	if ok && sess != nil {
-		sess.lastAccess = time.Now()
+	if !ok || sess == nil {
+		return nil
 	}
+	if s.expired(sess) {
+		delete(s.sessions, queryID)
+		return nil
+	}
+	sess.lastAccess = s.now()
 	return nil
 }
 
+func (s *memorySessionStore) expired(sess *querySession) bool {
+	return s.now().Sub(sess.lastAccess) > s.ttl
+}
+
 // cleanupLoop 定时清理过期 session
 func (s *memorySessionStore) cleanupLoop() {
 	ticker := time.NewTicker(CleanupInterval)
@@ -110,9 +153,8 @@ func (s *memorySessionStore) cleanup() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	now := time.Now()
 	for id, sess := range s.sessions {
-		if now.Sub(sess.lastAccess) > s.ttl {
+		if s.expired(sess) {
 			delete(s.sessions, id)
 		}
 	}
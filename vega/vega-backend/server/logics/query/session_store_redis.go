@@ -0,0 +1,78 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+	"github.com/redis/go-redis/v9"
+
+	"vega-backend/interfaces"
+)
+
+// sessionKeyPrefix Redis 中 session 的 key 前缀，每个 session 为一个 hash：offset -> cursorEncoded
+const sessionKeyPrefix = "vega-backend:query-session:"
+
+// redisSessionStore Redis 实现的 QuerySessionStore，多副本共享游标
+// session 过期由 Redis key 的 TTL 完成，无需定时清理
+type redisSessionStore struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+// NewRedisSessionStore 创建 Redis session 存储
+func NewRedisSessionStore(client redis.UniversalClient, ttl time.Duration) interfaces.QuerySessionStore {
+	if ttl == 0 {
+		ttl = SessionTTL
+	}
+	return &redisSessionStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// GetCursor 获取游标，Redis 不可用时按无游标处理，查询退化为 OFFSET/LIMIT
+func (s *redisSessionStore) GetCursor(ctx context.Context, queryID string, offset int) (string, bool) {
+	cursor, err := s.client.HGet(ctx, sessionKey(queryID), strconv.Itoa(offset)).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			logger.Warnf("Failed to get query session cursor %s@%d: %v", queryID, offset, err)
+		}
+		return "", false
+	}
+	return cursor, true
+}
+
+// SetCursor 设置游标并刷新 session 的生存时间
+func (s *redisSessionStore) SetCursor(ctx context.Context, queryID string, offset int, cursorEncoded string) error {
+	key := sessionKey(queryID)
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, strconv.Itoa(offset), cursorEncoded)
+		pipe.Expire(ctx, key, s.ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set query session cursor %s@%d: %w", queryID, offset, err)
+	}
+	return nil
+}
+
+// Touch 刷新 session 的生存时间，session 不存在时不做处理
+func (s *redisSessionStore) Touch(ctx context.Context, queryID string) error {
+	if err := s.client.Expire(ctx, sessionKey(queryID), s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to touch query session %s: %w", queryID, err)
+	}
+	return nil
+}
+
+func sessionKey(queryID string) string {
+	return sessionKeyPrefix + queryID
+}
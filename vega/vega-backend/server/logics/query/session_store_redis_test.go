@@ -0,0 +1,72 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"vega-backend/interfaces"
+)
+
+func newTestRedisSessionStore(t *testing.T, ttl time.Duration) (interfaces.QuerySessionStore, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisSessionStore(client, ttl), mr
+}
+
+func TestRedisSessionStore_Contract(t *testing.T) {
+	runSessionStoreContract(t, func(t *testing.T, ttl time.Duration) (interfaces.QuerySessionStore, func(time.Duration)) {
+		store, mr := newTestRedisSessionStore(t, ttl)
+		return store, mr.FastForward
+	})
+}
+
+// 多副本共享同一 Redis 时，任一副本写入的游标对其他副本可见
+func TestRedisSessionStore_SharedAcrossReplicas(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	newReplica := func() interfaces.QuerySessionStore {
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+		return NewRedisSessionStore(client, time.Minute)
+	}
+	podA, podB := newReplica(), newReplica()
+
+	if err := podA.SetCursor(ctx, "q", 0, "c0"); err != nil {
+		t.Fatalf("SetCursor failed: %v", err)
+	}
+	if cursor, ok := podB.GetCursor(ctx, "q", 0); !ok || cursor != "c0" {
+		t.Errorf("expected cursor from other replica, got ok=%v cursor=%q", ok, cursor)
+	}
+	if ttl := mr.TTL(sessionKey("q")); ttl != time.Minute {
+		t.Errorf("expected ttl 1m, got %v", ttl)
+	}
+}
+
+// Redis 不可用时按无游标处理，写入返回错误
+func TestRedisSessionStore_Unavailable(t *testing.T) {
+	ctx := context.Background()
+	store, mr := newTestRedisSessionStore(t, time.Minute)
+	mr.Close()
+
+	if _, ok := store.GetCursor(ctx, "q", 0); ok {
+		t.Error("expected no cursor when redis is unavailable")
+	}
+	if err := store.SetCursor(ctx, "q", 0, "c0"); err == nil {
+		t.Error("expected SetCursor error when redis is unavailable")
+	}
+	if err := store.Touch(ctx, "q"); err == nil {
+		t.Error("expected Touch error when redis is unavailable")
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"vega-backend/interfaces"
 )
 
 func TestMemorySessionStore_GetSetCursor(t *testing.T) {
@@ -86,3 +88,113 @@ func TestMemorySessionStore_MultipleQueryIDs(t *testing.T) {
 		t.Errorf("q2: expected c2, got %q ok=%v", c2, ok2)
 	}
 }
+
+// sessionStoreFactory 创建待测存储，advance 用于推进该存储感知到的时间
+type sessionStoreFactory func(t *testing.T, ttl time.Duration) (store interfaces.QuerySessionStore, advance func(time.Duration))
+
+// runSessionStoreContract 各 QuerySessionStore 实现需满足的共同行为
+func runSessionStoreContract(t *testing.T, newStore sessionStoreFactory) {
+	ctx := context.Background()
+
+	t.Run("GetSetCursor", func(t *testing.T) {
+		store, _ := newStore(t, time.Minute)
+
+		if _, ok := store.GetCursor(ctx, "q", 0); ok {
+			t.Fatal("expected no cursor for new session")
+		}
+		if err := store.SetCursor(ctx, "q", 0, "c0"); err != nil {
+			t.Fatalf("SetCursor failed: %v", err)
+		}
+		if err := store.SetCursor(ctx, "q", 100, "c100"); err != nil {
+			t.Fatalf("SetCursor failed: %v", err)
+		}
+		// 覆盖已有 offset 的游标
+		if err := store.SetCursor(ctx, "q", 0, "c0-new"); err != nil {
+			t.Fatalf("SetCursor failed: %v", err)
+		}
+
+		for offset, expected := range map[int]string{0: "c0-new", 100: "c100"} {
+			if cursor, ok := store.GetCursor(ctx, "q", offset); !ok || cursor != expected {
+				t.Errorf("offset %d: expected %q, got ok=%v cursor=%q", offset, expected, ok, cursor)
+			}
+		}
+		if _, ok := store.GetCursor(ctx, "q", 200); ok {
+			t.Error("expected no cursor for offset 200")
+		}
+	})
+
+	t.Run("IsolatedQueryIDs", func(t *testing.T) {
+		store, _ := newStore(t, time.Minute)
+
+		_ = store.SetCursor(ctx, "q1", 0, "c1")
+		_ = store.SetCursor(ctx, "q2", 0, "c2")
+		if cursor, ok := store.GetCursor(ctx, "q1", 0); !ok || cursor != "c1" {
+			t.Errorf("q1: expected c1, got ok=%v cursor=%q", ok, cursor)
+		}
+		if cursor, ok := store.GetCursor(ctx, "q2", 0); !ok || cursor != "c2" {
+			t.Errorf("q2: expected c2, got ok=%v cursor=%q", ok, cursor)
+		}
+	})
+
+	t.Run("Expire", func(t *testing.T) {
+		store, advance := newStore(t, time.Minute)
+
+		_ = store.SetCursor(ctx, "q", 0, "c0")
+		advance(2 * time.Minute)
+		if _, ok := store.GetCursor(ctx, "q", 0); ok {
+			t.Error("expected session to expire after ttl")
+		}
+		// 过期后 Touch 不能恢复 session
+		if err := store.Touch(ctx, "q"); err != nil {
+			t.Fatalf("Touch failed: %v", err)
+		}
+		if _, ok := store.GetCursor(ctx, "q", 0); ok {
+			t.Error("expected touch not to revive expired session")
+		}
+	})
+
+	t.Run("TouchRefreshesTTL", func(t *testing.T) {
+		store, advance := newStore(t, time.Minute)
+
+		_ = store.SetCursor(ctx, "q", 0, "c0")
+		advance(40 * time.Second)
+		if err := store.Touch(ctx, "q"); err != nil {
+			t.Fatalf("Touch failed: %v", err)
+		}
+		advance(40 * time.Second)
+		if cursor, ok := store.GetCursor(ctx, "q", 0); !ok || cursor != "c0" {
+			t.Errorf("expected session alive after touch, got ok=%v cursor=%q", ok, cursor)
+		}
+
+		// SetCursor 同样刷新生存时间
+		_ = store.SetCursor(ctx, "q", 100, "c100")
+		advance(40 * time.Second)
+		if _, ok := store.GetCursor(ctx, "q", 0); !ok {
+			t.Error("expected session alive after set cursor")
+		}
+		advance(40 * time.Second)
+		if _, ok := store.GetCursor(ctx, "q", 100); ok {
+			t.Error("expected session to expire without access")
+		}
+	})
+
+	t.Run("TouchNonExistent", func(t *testing.T) {
+		store, _ := newStore(t, time.Minute)
+
+		if err := store.Touch(ctx, "non-existent"); err != nil {
+			t.Errorf("Touch non-existent failed: %v", err)
+		}
+		if _, ok := store.GetCursor(ctx, "non-existent", 0); ok {
+			t.Error("expected touch not to create session")
+		}
+	})
+}
+
+func TestMemorySessionStore_Contract(t *testing.T) {
+	runSessionStoreContract(t, func(t *testing.T, ttl time.Duration) (interfaces.QuerySessionStore, func(time.Duration)) {
+		store := NewMemorySessionStore(ttl).(*memorySessionStore)
+		now := time.Now()
+		store.now = func() time.Time { return now }
+		return store, func(d time.Duration) { now = now.Add(d) }
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 
 	"vega-backend/common"
+	lineageAccess "vega-backend/drivenadapters/lineage"
 	resourceAccess "vega-backend/drivenadapters/resource"
 	verrors "vega-backend/errors"
 	"vega-backend/interfaces"
@@ -37,6 +38,7 @@ var (
 type resourceService struct {
 	appSetting *common.AppSetting
 	ds         interfaces.DatasetService
+	la         interfaces.LineageAccess
 	ps         interfaces.PermissionService
 	ra         interfaces.ResourceAccess
 	ums        interfaces.UserMgmtService
@@ -48,6 +50,7 @@ func NewResourceService(appSetting *common.AppSetting) interfaces.ResourceServic
 		rService = &resourceService{
 			appSetting: appSetting,
 			ds:         dataset.NewDatasetService(appSetting),
+			la:         lineageAccess.NewLineageAccess(appSetting),
 			ps:         permission.NewPermissionService(appSetting),
 			ra:         resourceAccess.NewResourceAccess(appSetting),
 			ums:        user_mgmt.NewUserMgmtService(appSetting),
@@ -126,10 +129,26 @@ func (rs *resourceService) Create(ctx context.Context, req *interfaces.ResourceR
 			WithErrorDetails(err.Error())
 	}
 
+	// 登记目录到资源的血缘
+	rs.reportResourceLineage(ctx, resource)
+
 	span.SetStatus(codes.Ok, "")
 	return resource.ID, nil
 }
 
+// reportResourceLineage 将目录到资源的血缘登记到 data-model, 登记失败不影响资源创建，只记录错误
+func (rs *resourceService) reportResourceLineage(ctx context.Context, resource *interfaces.Resource) {
+	owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_RESOURCE, ID: resource.ID}
+	edges := []*interfaces.LineageEdge{{
+		Source: interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_CATALOG, ID: resource.CatalogID},
+		Target: owner,
+		Fields: []interfaces.LineageFieldMapping{},
+	}}
+	if err := rs.la.ReplaceLineageEdges(ctx, owner, edges); err != nil {
+		logger.Warnf("Report lineage of resource %s failed: %v", resource.ID, err)
+	}
+}
+
 // Get retrieves a Resource by ID.
 func (rs *resourceService) GetByID(ctx context.Context, id string) (*interfaces.Resource, error) {
 	ctx, span := ar_trace.Tracer.Start(ctx, "Get resource")
@@ -427,6 +446,14 @@ func (rs *resourceService) DeleteByIDs(ctx context.Context, ids []string) error
 		return err
 	}
 
+	// 删除资源登记的血缘, 删除失败不影响资源删除，只记录错误
+	for _, id := range ids {
+		owner := interfaces.LineageNode{Type: interfaces.LINEAGE_NODE_TYPE_RESOURCE, ID: id}
+		if err := rs.la.DeleteLineageEdges(ctx, owner); err != nil {
+			logger.Warnf("Delete lineage of resource %s failed: %v", id, err)
+		}
+	}
+
 	span.SetStatus(codes.Ok, "")
 	return nil
 }
@@ -3,6 +3,7 @@ package resource_data
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -75,10 +76,11 @@ func (rds *resourceDataService) Query(ctx context.Context, resource *interfaces.
 		}
 		return documents, total, nil
 
-	case interfaces.ResourceCategoryTable:
+	case interfaces.ResourceCategoryTable, interfaces.ResourceCategoryTopic, interfaces.ResourceCategoryMetric,
+		interfaces.ResourceCategoryFile, interfaces.ResourceCategoryFileset:
 		data, total, err := rds.QueryData(ctx, resource, params)
 		if err != nil {
-			span.SetStatus(codes.Error, "Query table data failed")
+			span.SetStatus(codes.Error, "Query resource data failed")
 			return nil, 0, rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Resource_InternalError).
 				WithErrorDetails(err.Error())
 		}
@@ -143,6 +145,59 @@ func (rds *resourceDataService) QueryData(ctx context.Context, resource *interfa
 		}
 		return result.Rows, result.Total, nil
 
+	case interfaces.ResourceCategoryTopic:
+		topicConnector, ok := connector.(connectors.TopicConnector)
+		if !ok {
+			span.SetStatus(codes.Error, "Connector does not support topic operations")
+			return nil, 0, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Resource_InternalError_InvalidCategory).
+				WithErrorDetails(fmt.Sprintf("connector %s does not support topic operations", catalog.ConnectorType))
+		}
+
+		result, err := topicConnector.ExecuteQuery(ctx, resource, params)
+		if err != nil {
+			span.SetStatus(codes.Error, "Execute query failed")
+			return nil, 0, rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Resource_InternalError).
+				WithErrorDetails(fmt.Sprintf("failed to execute query: %v", err))
+		}
+		return result.Rows, result.Total, nil
+
+	case interfaces.ResourceCategoryMetric:
+		metricConnector, ok := connector.(connectors.MetricConnector)
+		if !ok {
+			span.SetStatus(codes.Error, "Connector does not support metric operations")
+			return nil, 0, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Resource_InternalError_InvalidCategory).
+				WithErrorDetails(fmt.Sprintf("connector %s does not support metric operations", catalog.ConnectorType))
+		}
+
+		series, err := metricConnector.ExecuteRangeQuery(ctx, resource, params)
+		if err != nil {
+			span.SetStatus(codes.Error, "Execute range query failed")
+			return nil, 0, rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Resource_InternalError).
+				WithErrorDetails(fmt.Sprintf("failed to execute range query: %v", err))
+		}
+		return metricSeriesPage(series, params), int64(len(series)), nil
+
+	case interfaces.ResourceCategoryFile, interfaces.ResourceCategoryFileset:
+		fileConnector, ok := connector.(connectors.FileConnector)
+		if !ok {
+			span.SetStatus(codes.Error, "Connector does not support file operations")
+			return nil, 0, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Resource_InternalError_InvalidCategory).
+				WithErrorDetails(fmt.Sprintf("connector %s does not support file operations", catalog.ConnectorType))
+		}
+
+		result, err := fileConnector.ExecuteQuery(ctx, resource, params)
+		if errors.Is(err, interfaces.ErrFileScanLimitExceeded) {
+			span.SetStatus(codes.Error, "File scan limit exceeded")
+			return nil, 0, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Resource_FileScanLimitExceeded).
+				WithErrorDetails(err.Error())
+		}
+		if err != nil {
+			span.SetStatus(codes.Error, "Execute query failed")
+			return nil, 0, rest.NewHTTPError(ctx, http.StatusInternalServerError, verrors.VegaBackend_Resource_InternalError).
+				WithErrorDetails(fmt.Sprintf("failed to execute query: %v", err))
+		}
+		return result.Rows, result.Total, nil
+
 	default:
 		span.SetStatus(codes.Error, "Connector does not support table operations")
 		return nil, 0, rest.NewHTTPError(ctx, http.StatusBadRequest, verrors.VegaBackend_Resource_InternalError_InvalidCategory).
@@ -150,3 +205,22 @@ func (rds *resourceDataService) QueryData(ctx context.Context, resource *interfa
 	}
 
 }
+
+// metricSeriesPage 按 offset/limit 对序列分页，每条序列输出为一行 MetricResult
+func metricSeriesPage(series []*interfaces.MetricResult, params *interfaces.ResourceDataQueryParams) []map[string]any {
+	start := min(params.Offset, len(series))
+	end := len(series)
+	if params.Limit > 0 {
+		end = min(start+params.Limit, end)
+	}
+
+	rows := make([]map[string]any, 0, end-start)
+	for _, s := range series[start:end] {
+		rows = append(rows, map[string]any{
+			"metric": s.Metric,
+			"labels": s.Labels,
+			"values": s.Values,
+		})
+	}
+	return rows
+}
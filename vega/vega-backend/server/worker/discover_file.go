@@ -0,0 +1,344 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/connectors"
+)
+
+// discoverFileResources discovers fileset and file resources from a fileset connector.
+// discoverFileResources 从连接器中获取文件集（bucket、目录）与文件列表，与现有资源协调后，
+// 采样文件内容推断字段结构，文件集的字段为其直接子文件字段的合并
+// 参数:
+//   - ctx: 上下文信息，用于控制请求的超时和取消
+//   - catalog: 目录信息
+//   - connector: 连接器接口，用于与数据源进行交互
+//
+// 返回值:
+//   - *interfaces.DiscoverResult: 发现结果，包含新资源、过期资源和未变化资源的统计信息
+//   - error: 错误信息，如果在发现过程中出现错误则返回
+func (dw *discoverWorker) discoverFileResources(ctx context.Context,
+	catalog *interfaces.Catalog, connector connectors.Connector) (*interfaces.DiscoverResult, error) {
+
+	filesetConnector, ok := connector.(connectors.FilesetConnector)
+	if !ok {
+		return nil, fmt.Errorf("connector does not support fileset discover")
+	}
+
+	// Step 1: List Filesets
+	sourceFilesets, err := filesetConnector.ListFilesets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list filesets: %w", err)
+	}
+	logger.Infof("Discovered %d filesets from source", len(sourceFilesets))
+
+	// Step 2: Get Existing Resources
+	existingResources, err := dw.rs.GetByCatalogID(ctx, catalog.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing resources: %w", err)
+	}
+
+	// Step 3: Reconcile
+	result, resources, err := dw.reconcileFileResources(ctx, catalog, sourceFilesets, existingResources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile resources: %w", err)
+	}
+
+	// Step 4: Enrich：采样文件推断字段，同时检测结构漂移
+	if err := dw.enrichFileMetadata(ctx, filesetConnector, result, sourceFilesets, resources); err != nil {
+		return nil, fmt.Errorf("failed to enrich file metadata: %w", err)
+	}
+	finishDiscoverResult(result)
+
+	logger.Infof("Discover completed for catalog %s: new=%d, stale=%d, unchanged=%d, drifted=%d",
+		catalog.ID, result.NewCount, result.StaleCount, result.UnchangedCount, result.DriftCount)
+
+	return result, nil
+}
+
+// reconcileFileResources reconciles source filesets and files with existing resources.
+// 返回源端路径到资源的映射，创建失败的资源不在其中
+func (dw *discoverWorker) reconcileFileResources(ctx context.Context,
+	catalog *interfaces.Catalog, sourceFilesets []*interfaces.FilesetMeta,
+	existingResources []*interfaces.Resource) (*interfaces.DiscoverResult, map[string]*interfaces.Resource, error) {
+
+	result := &interfaces.DiscoverResult{
+		CatalogID: catalog.ID,
+	}
+
+	existingMap := make(map[string]*interfaces.Resource)
+	for _, r := range existingResources {
+		existingMap[r.SourceIdentifier] = r
+	}
+
+	// 文件集路径以 / 结尾，不会与文件路径冲突
+	type sourceItem struct {
+		path     string
+		category string
+	}
+	var sources []sourceItem
+	for _, fileset := range sourceFilesets {
+		sources = append(sources, sourceItem{path: fileset.Path, category: interfaces.ResourceCategoryFileset})
+		for _, file := range fileset.Files {
+			sources = append(sources, sourceItem{path: file.Path, category: interfaces.ResourceCategoryFile})
+		}
+	}
+
+	resources := make(map[string]*interfaces.Resource, len(sources))
+
+	// Handle new and existing
+	for _, source := range sources {
+		if resource, ok := existingMap[source.path]; ok {
+			if resource.Status == interfaces.ResourceStatusStale {
+				if err := dw.rs.UpdateStatus(ctx, resource.ID, interfaces.ResourceStatusActive, ""); err != nil {
+					logger.Errorf("Failed to reactivate resource %s: %v", resource.ID, err)
+				} else {
+					addSchemaDrift(result, resource, interfaces.SchemaChange{
+						Type: interfaces.SchemaChangeResourceReactivated,
+					})
+				}
+			}
+			result.UnchangedCount++
+			resources[source.path] = resource
+		} else {
+			resource, err := dw.createFileResource(ctx, catalog, source.path, source.category)
+			if err != nil {
+				logger.Errorf("Failed to create resource %s: %v", source.path, err)
+			} else {
+				result.NewCount++
+				resources[source.path] = resource
+			}
+		}
+	}
+
+	// Handle stale
+	for sourceIdentifier, existing := range existingMap {
+		if _, ok := resources[sourceIdentifier]; !ok {
+			if existing.Status != interfaces.ResourceStatusStale {
+				if err := dw.rs.UpdateStatus(ctx, existing.ID, interfaces.ResourceStatusStale, ""); err != nil {
+					logger.Errorf("Failed to mark resource %s as stale: %v", existing.ID, err)
+				} else {
+					result.StaleCount++
+					addSchemaDrift(result, existing, interfaces.SchemaChange{
+						Type: interfaces.SchemaChangeResourceStale,
+					})
+				}
+			}
+		}
+	}
+
+	return result, resources, nil
+}
+
+// createFileResource creates a new resource for a fileset or file.
+func (dw *discoverWorker) createFileResource(ctx context.Context, catalog *interfaces.Catalog,
+	path string, category string) (*interfaces.Resource, error) {
+
+	req := &interfaces.ResourceRequest{
+		CatalogID:        catalog.ID,
+		Name:             path,
+		Category:         category,
+		Status:           interfaces.ResourceStatusActive,
+		SourceIdentifier: path,
+	}
+	id, err := dw.rs.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return dw.rs.GetByID(ctx, id)
+}
+
+// enrichFileMetadata enriches file resources with inferred fields, then filesets with merged fields.
+func (dw *discoverWorker) enrichFileMetadata(ctx context.Context,
+	filesetConnector connectors.FilesetConnector, result *interfaces.DiscoverResult,
+	filesets []*interfaces.FilesetMeta, resources map[string]*interfaces.Resource) error {
+
+	for _, fileset := range filesets {
+		for _, file := range fileset.Files {
+			resource, ok := resources[file.Path]
+			if !ok {
+				continue
+			}
+			if err := dw.enrichFile(ctx, filesetConnector, result, resource, file); err != nil {
+				return err
+			}
+		}
+
+		resource, ok := resources[fileset.Path]
+		if !ok {
+			continue
+		}
+		fileset.Fields = mergeFileFields(fileset.Files)
+		if err := dw.enrichFileset(ctx, result, resource, fileset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enrichFile 采样单个文件推断字段；对象未变化时沿用已保存的字段，不再读取内容
+func (dw *discoverWorker) enrichFile(ctx context.Context, fileConnector connectors.FileConnector,
+	result *interfaces.DiscoverResult, resource *interfaces.Resource, file *interfaces.FileMeta) error {
+
+	// 增量发现：对象列举信息（etag、大小、修改时间）的指纹未变化时跳过更新
+	fingerprint := schemaFingerprint(file)
+	if resource.SourceMetadata != nil && fingerprint != "" &&
+		resource.SourceMetadata[schemaFingerprintKey] == fingerprint {
+		logger.Debugf("File %s unchanged since last discover, skip update", file.Path)
+		file.Fields = storedFields(resource)
+		return nil
+	}
+
+	// 单个文件内容无法解析时不影响其他文件，不记录指纹以便下次重试
+	if err := fileConnector.GetFileMeta(ctx, file); err != nil {
+		logger.Warnf("Failed to get metadata for file %s: %v", file.Path, err)
+		fingerprint = ""
+	}
+
+	// 与已保存的 schema_definition 比较，记录字段变化（新建资源尚无 schema，不产生漂移）
+	if len(resource.SchemaDefinition) > 0 {
+		addSchemaDrift(result, resource, diffColumns(storedColumns(resource, "fields"), fieldColumns(file.Fields))...)
+	}
+	resource.SchemaDefinition = fieldProperties(file.Fields)
+
+	sourceMetadata := make(map[string]any)
+	if resource.SourceMetadata != nil {
+		sourceMetadata = resource.SourceMetadata
+	}
+
+	sourceMetadata["bucket"] = file.Bucket
+	sourceMetadata["key"] = file.Key
+	sourceMetadata["size"] = file.Size
+	sourceMetadata["last_modified"] = file.LastModified
+	sourceMetadata["etag"] = file.ETag
+	sourceMetadata["content_type"] = file.ContentType
+	sourceMetadata["format"] = file.Format
+	sourceMetadata["properties"] = file.Properties
+	sourceMetadata["fields"] = file.Fields
+	sourceMetadata[schemaFingerprintKey] = fingerprint
+	resource.SourceMetadata = sourceMetadata
+
+	if err := dw.rs.UpdateResource(ctx, resource); err != nil {
+		logger.Errorf("Failed to update metadata for file %s: %v", file.Path, err)
+		return err
+	}
+
+	logger.Infof("Enriched file %s: format=%s, fields=%d", file.Path, file.Format, len(file.Fields))
+	return nil
+}
+
+// enrichFileset 更新文件集的文件统计与合并后的字段
+func (dw *discoverWorker) enrichFileset(ctx context.Context, result *interfaces.DiscoverResult,
+	resource *interfaces.Resource, fileset *interfaces.FilesetMeta) error {
+
+	fingerprint := schemaFingerprint(fileset)
+	if resource.SourceMetadata != nil && fingerprint != "" &&
+		resource.SourceMetadata[schemaFingerprintKey] == fingerprint {
+		logger.Debugf("Fileset %s unchanged since last discover, skip update", fileset.Path)
+		return nil
+	}
+
+	if len(resource.SchemaDefinition) > 0 {
+		addSchemaDrift(result, resource, diffColumns(storedColumns(resource, "fields"), fieldColumns(fileset.Fields))...)
+	}
+	resource.SchemaDefinition = fieldProperties(fileset.Fields)
+
+	sourceMetadata := make(map[string]any)
+	if resource.SourceMetadata != nil {
+		sourceMetadata = resource.SourceMetadata
+	}
+
+	sourceMetadata["bucket"] = fileset.Bucket
+	sourceMetadata["prefix"] = fileset.Prefix
+	sourceMetadata["file_count"] = fileset.FileCount
+	sourceMetadata["total_size"] = fileset.TotalSize
+	sourceMetadata["fields"] = fileset.Fields
+	sourceMetadata[schemaFingerprintKey] = fingerprint
+	resource.SourceMetadata = sourceMetadata
+
+	if err := dw.rs.UpdateResource(ctx, resource); err != nil {
+		logger.Errorf("Failed to update metadata for fileset %s: %v", fileset.Path, err)
+		return err
+	}
+
+	logger.Infof("Enriched fileset %s: files=%d, fields=%d", fileset.Path, fileset.FileCount, len(fileset.Fields))
+	return nil
+}
+
+// fieldProperties 将推断出的字段转换为 schema_definition
+func fieldProperties(fields []interfaces.FieldMeta) []*interfaces.Property {
+	columns := make([]*interfaces.Property, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, &interfaces.Property{
+			Name:         field.Name,
+			Type:         field.Type,
+			DisplayName:  field.Name,
+			OriginalName: field.Name,
+			Description:  "",
+		})
+	}
+	return columns
+}
+
+// storedFields 从已保存的 schema_definition 还原字段，用于未变化的文件参与文件集字段合并
+func storedFields(resource *interfaces.Resource) []interfaces.FieldMeta {
+	cols := storedColumns(resource, "fields")
+	fields := make([]interfaces.FieldMeta, 0, len(cols))
+	for _, col := range cols {
+		fields = append(fields, interfaces.FieldMeta{
+			Name:       col.Name,
+			Type:       col.Type,
+			OrigType:   col.OrigType,
+			Searchable: col.Type != interfaces.DataType_Json,
+		})
+	}
+	return fields
+}
+
+// mergeFileFields 合并文件集下各文件的字段：按首次出现的顺序取并集，
+// 类型冲突时整数与浮点合并为浮点，日期与时间合并为时间，其余退化为字符串
+func mergeFileFields(files []*interfaces.FileMeta) []interfaces.FieldMeta {
+	var merged []interfaces.FieldMeta
+	index := map[string]int{}
+	for _, file := range files {
+		for _, field := range file.Fields {
+			i, ok := index[field.Name]
+			if !ok {
+				index[field.Name] = len(merged)
+				merged = append(merged, field)
+				continue
+			}
+			if merged[i].Type != field.Type {
+				merged[i].Type = mergeFieldType(merged[i].Type, field.Type)
+				merged[i].Searchable = merged[i].Type != interfaces.DataType_Json
+			}
+			if merged[i].OrigType != field.OrigType {
+				merged[i].OrigType = "mixed"
+			}
+		}
+	}
+	return merged
+}
+
+func mergeFieldType(a, b string) string {
+	switch {
+	case (a == interfaces.DataType_Integer && b == interfaces.DataType_Float) ||
+		(a == interfaces.DataType_Float && b == interfaces.DataType_Integer):
+		return interfaces.DataType_Float
+	case (a == interfaces.DataType_Date && b == interfaces.DataType_Datetime) ||
+		(a == interfaces.DataType_Datetime && b == interfaces.DataType_Date):
+		return interfaces.DataType_Datetime
+	default:
+		return interfaces.DataType_String
+	}
+}
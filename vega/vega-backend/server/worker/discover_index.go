@@ -59,13 +59,14 @@ func (dw *discoverWorker) discoverIndexResources(ctx context.Context,
 		return nil, fmt.Errorf("failed to reconcile resources: %w", err)
 	}
 
-	// Step 4: Enrich ： 为索引项丰富元数据信息
-	if err := dw.enrichIndexMetadata(ctx, indexConnector, items); err != nil {
+	// Step 4: Enrich ： 为索引项丰富元数据信息，同时检测结构漂移
+	if err := dw.enrichIndexMetadata(ctx, indexConnector, result, items); err != nil {
 		return nil, fmt.Errorf("failed to enrich index metadata: %w", err)
 	}
+	finishDiscoverResult(result)
 
-	logger.Infof("Discover completed for catalog %s: new=%d, stale=%d, unchanged=%d",
-		catalog.ID, result.NewCount, result.StaleCount, result.UnchangedCount)
+	logger.Infof("Discover completed for catalog %s: new=%d, stale=%d, unchanged=%d, drifted=%d",
+		catalog.ID, result.NewCount, result.StaleCount, result.UnchangedCount, result.DriftCount)
 
 	return result, nil
 }
@@ -113,6 +114,10 @@ func (dw *discoverWorker) reconcileIndexResources(ctx context.Context,
 			if resource.Status == interfaces.ResourceStatusStale {
 				if err := dw.rs.UpdateStatus(ctx, resource.ID, interfaces.ResourceStatusActive, ""); err != nil {
 					logger.Errorf("Failed to reactivate resource %s: %v", resource.ID, err)
+				} else {
+					addSchemaDrift(result, resource, interfaces.SchemaChange{
+						Type: interfaces.SchemaChangeResourceReactivated,
+					})
 				}
 			}
 			result.UnchangedCount++
@@ -142,14 +147,14 @@ func (dw *discoverWorker) reconcileIndexResources(ctx context.Context,
 					logger.Errorf("Failed to mark resource %s as stale: %v", existing.ID, err)
 				} else {
 					result.StaleCount++
+					addSchemaDrift(result, existing, interfaces.SchemaChange{
+						Type: interfaces.SchemaChangeResourceStale,
+					})
 				}
 			}
 		}
 	}
 
-	result.Message = fmt.Sprintf("Discover completed: %d new, %d stale, %d unchanged",
-		result.NewCount, result.StaleCount, result.UnchangedCount)
-
 	return result, items, nil
 }
 
@@ -177,12 +182,14 @@ func (dw *discoverWorker) createIndexResource(ctx context.Context, catalog *inte
 // 参数:
 //   - ctx: 上下文信息，用于控制请求的超时和取消
 //   - indexConnector: 索引连接器，用于获取索引的元数据
+//   - result: 发现结果，检测到的结构漂移记录到其中
 //   - items: 需要丰富元数据的索引项列表
 //
 // 返回值:
 //   - error: 如果在处理过程中发生错误，则返回错误信息
 func (dw *discoverWorker) enrichIndexMetadata(ctx context.Context,
-	indexConnector connectors.IndexConnector, items []indexDiscoverItem) error {
+	indexConnector connectors.IndexConnector, result *interfaces.DiscoverResult,
+	items []indexDiscoverItem) error {
 
 	// 遍历所有需要处理的索引项
 	for _, item := range items {
@@ -195,6 +202,19 @@ func (dw *discoverWorker) enrichIndexMetadata(ctx context.Context,
 			return err
 		}
 
+		// 增量发现：源端元数据指纹未变化时跳过更新
+		fingerprint := schemaFingerprint(idx)
+		if resource.SourceMetadata != nil && fingerprint != "" &&
+			resource.SourceMetadata[schemaFingerprintKey] == fingerprint {
+			logger.Debugf("Index %s unchanged since last discover, skip update", idx.Name)
+			continue
+		}
+
+		// 与已保存的 schema_definition 比较，记录字段变化（新建资源尚无 schema，不产生漂移）
+		if len(resource.SchemaDefinition) > 0 {
+			addSchemaDrift(result, resource, diffColumns(storedColumns(resource, "mapping"), indexColumns(idx))...)
+		}
+
 		// Map fields to SchemaDefinition
 		var columns []*interfaces.Property
 		for _, field := range idx.Mapping {
@@ -216,6 +236,7 @@ func (dw *discoverWorker) enrichIndexMetadata(ctx context.Context,
 
 		sourceMetadata["properties"] = idx.Properties
 		sourceMetadata["mapping"] = idx.Mapping
+		sourceMetadata[schemaFingerprintKey] = fingerprint
 		resource.SourceMetadata = sourceMetadata
 
 		// Update Resource
@@ -0,0 +1,229 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/connectors"
+)
+
+type metricDiscoverItem struct {
+	resource   *interfaces.Resource
+	metricMeta *interfaces.MetricMeta
+}
+
+// discoverMetricResources discovers metric resources from a metric connector.
+// discoverMetricResources 从连接器中获取指标列表，与现有资源协调后，读取序列汇总标签集合
+// 参数:
+//   - ctx: 上下文信息，用于控制请求的超时和取消
+//   - catalog: 目录信息
+//   - connector: 连接器接口，用于与数据源进行交互
+//
+// 返回值:
+//   - *interfaces.DiscoverResult: 发现结果，包含新资源、过期资源和未变化资源的统计信息
+//   - error: 错误信息，如果在发现过程中出现错误则返回
+func (dw *discoverWorker) discoverMetricResources(ctx context.Context,
+	catalog *interfaces.Catalog, connector connectors.Connector) (*interfaces.DiscoverResult, error) {
+
+	metricConnector, ok := connector.(connectors.MetricConnector)
+	if !ok {
+		return nil, fmt.Errorf("connector does not support metric discover")
+	}
+
+	// Step 1: List Metrics
+	sourceMetrics, err := metricConnector.ListMetrics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list metrics: %w", err)
+	}
+	logger.Infof("Discovered %d metrics from source", len(sourceMetrics))
+
+	// Step 2: Get Existing Resources
+	existingResources, err := dw.rs.GetByCatalogID(ctx, catalog.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing resources: %w", err)
+	}
+
+	// Step 3: Reconcile
+	result, items, err := dw.reconcileMetricResources(ctx, catalog, sourceMetrics, existingResources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile resources: %w", err)
+	}
+
+	// Step 4: Enrich：汇总标签集合，同时检测结构漂移
+	if err := dw.enrichMetricMetadata(ctx, metricConnector, result, items); err != nil {
+		return nil, fmt.Errorf("failed to enrich metric metadata: %w", err)
+	}
+	finishDiscoverResult(result)
+
+	logger.Infof("Discover completed for catalog %s: new=%d, stale=%d, unchanged=%d, drifted=%d",
+		catalog.ID, result.NewCount, result.StaleCount, result.UnchangedCount, result.DriftCount)
+
+	return result, nil
+}
+
+// reconcileMetricResources reconciles source metrics with existing resources.
+func (dw *discoverWorker) reconcileMetricResources(ctx context.Context,
+	catalog *interfaces.Catalog, sourceMetrics []*interfaces.MetricMeta,
+	existingResources []*interfaces.Resource) (*interfaces.DiscoverResult, []metricDiscoverItem, error) {
+
+	result := &interfaces.DiscoverResult{
+		CatalogID: catalog.ID,
+	}
+
+	var items []metricDiscoverItem
+
+	existingMap := make(map[string]*interfaces.Resource)
+	for _, r := range existingResources {
+		existingMap[r.SourceIdentifier] = r
+	}
+
+	sourceMap := make(map[string]*interfaces.MetricMeta)
+	for _, metric := range sourceMetrics {
+		sourceMap[metric.Name] = metric
+	}
+
+	// Handle new and existing
+	for _, metric := range sourceMetrics {
+		sourceIdentifier := metric.Name
+
+		if resource, ok := existingMap[sourceIdentifier]; ok {
+			if resource.Status == interfaces.ResourceStatusStale {
+				if err := dw.rs.UpdateStatus(ctx, resource.ID, interfaces.ResourceStatusActive, ""); err != nil {
+					logger.Errorf("Failed to reactivate resource %s: %v", resource.ID, err)
+				} else {
+					addSchemaDrift(result, resource, interfaces.SchemaChange{
+						Type: interfaces.SchemaChangeResourceReactivated,
+					})
+				}
+			}
+			result.UnchangedCount++
+			items = append(items, metricDiscoverItem{
+				resource:   resource,
+				metricMeta: metric,
+			})
+		} else {
+			resource, err := dw.createMetricResource(ctx, catalog, metric)
+			if err != nil {
+				logger.Errorf("Failed to create resource %s: %v", sourceIdentifier, err)
+			} else {
+				result.NewCount++
+				items = append(items, metricDiscoverItem{
+					resource:   resource,
+					metricMeta: metric,
+				})
+			}
+		}
+	}
+
+	// Handle stale
+	for sourceIdentifier, existing := range existingMap {
+		if _, ok := sourceMap[sourceIdentifier]; !ok {
+			if existing.Status != interfaces.ResourceStatusStale {
+				if err := dw.rs.UpdateStatus(ctx, existing.ID, interfaces.ResourceStatusStale, ""); err != nil {
+					logger.Errorf("Failed to mark resource %s as stale: %v", existing.ID, err)
+				} else {
+					result.StaleCount++
+					addSchemaDrift(result, existing, interfaces.SchemaChange{
+						Type: interfaces.SchemaChangeResourceStale,
+					})
+				}
+			}
+		}
+	}
+
+	return result, items, nil
+}
+
+// createMetricResource creates a new resource for a metric.
+func (dw *discoverWorker) createMetricResource(ctx context.Context, catalog *interfaces.Catalog,
+	metric *interfaces.MetricMeta) (*interfaces.Resource, error) {
+
+	req := &interfaces.ResourceRequest{
+		CatalogID:        catalog.ID,
+		Name:             metric.Name,
+		Category:         interfaces.ResourceCategoryMetric,
+		Status:           interfaces.ResourceStatusActive,
+		SourceIdentifier: metric.Name,
+	}
+	id, err := dw.rs.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return dw.rs.GetByID(ctx, id)
+}
+
+// enrichMetricMetadata enriches metric resources with label sets.
+func (dw *discoverWorker) enrichMetricMetadata(ctx context.Context,
+	metricConnector connectors.MetricConnector, result *interfaces.DiscoverResult,
+	items []metricDiscoverItem) error {
+
+	for _, item := range items {
+		metric := item.metricMeta
+		resource := item.resource
+
+		if err := metricConnector.GetMetricMeta(ctx, metric); err != nil {
+			logger.Warnf("Failed to get metadata for metric %s: %v", metric.Name, err)
+			return err
+		}
+
+		// 增量发现：源端元数据指纹未变化时跳过更新
+		fingerprint := schemaFingerprint(metric)
+		if resource.SourceMetadata != nil && fingerprint != "" &&
+			resource.SourceMetadata[schemaFingerprintKey] == fingerprint {
+			logger.Debugf("Metric %s unchanged since last discover, skip update", metric.Name)
+			continue
+		}
+
+		// 与已保存的 schema_definition 比较，记录标签变化（新建资源尚无 schema，不产生漂移）
+		if len(resource.SchemaDefinition) > 0 {
+			addSchemaDrift(result, resource, diffColumns(storedColumns(resource, "labels"), metricColumns(metric))...)
+		}
+
+		// 每个标签作为一个字符串字段，可用于过滤条件
+		columns := make([]*interfaces.Property, 0, len(metric.Labels))
+		for _, label := range metric.Labels {
+			columns = append(columns, &interfaces.Property{
+				Name:         label,
+				Type:         interfaces.DataType_String,
+				DisplayName:  label,
+				OriginalName: label,
+				Description:  "",
+			})
+		}
+		resource.SchemaDefinition = columns
+		if resource.Description == "" {
+			resource.Description = metric.Help
+		}
+
+		sourceMetadata := make(map[string]any)
+		if resource.SourceMetadata != nil {
+			sourceMetadata = resource.SourceMetadata
+		}
+
+		sourceMetadata["type"] = metric.Type
+		sourceMetadata["help"] = metric.Help
+		sourceMetadata["unit"] = metric.Unit
+		sourceMetadata["labels"] = metric.Labels
+		sourceMetadata["label_values"] = metric.LabelValues
+		sourceMetadata["properties"] = metric.Properties
+		sourceMetadata[schemaFingerprintKey] = fingerprint
+		resource.SourceMetadata = sourceMetadata
+
+		if err := dw.rs.UpdateResource(ctx, resource); err != nil {
+			logger.Errorf("Failed to update metadata for metric %s: %v", metric.Name, err)
+			return err
+		}
+
+		logger.Infof("Enriched metric %s: type=%s, labels=%d", metric.Name, metric.Type, len(columns))
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/hibiken/asynq"
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"vega-backend/interfaces"
+)
+
+const (
+	// 同一 catalog 的同一次调度在该时间窗口内只入队一次，避免多副本重复触发
+	discoverScheduleUniqueTTL = 30 * time.Second
+)
+
+// discoverScheduleProvider 为 asynq PeriodicTaskManager 提供定时发现配置
+type discoverScheduleProvider struct {
+	dts interfaces.DiscoverTaskService
+}
+
+// GetConfigs returns the periodic task configs of all enabled discover schedules.
+// PeriodicTaskManager 周期性调用，定时配置的变更由此同步到调度器
+func (p *discoverScheduleProvider) GetConfigs() ([]*asynq.PeriodicTaskConfig, error) {
+	schedules, err := p.dts.ListEnabledSchedules(context.Background())
+	if err != nil {
+		logger.Errorf("Failed to list enabled discover schedules: %v", err)
+		return nil, err
+	}
+
+	configs := make([]*asynq.PeriodicTaskConfig, 0, len(schedules))
+	for _, schedule := range schedules {
+		payload, err := sonic.Marshal(&interfaces.DiscoverScheduleMessage{
+			CatalogID: schedule.CatalogID,
+		})
+		if err != nil {
+			logger.Errorf("Failed to marshal discover schedule of catalog %s: %v", schedule.CatalogID, err)
+			continue
+		}
+		configs = append(configs, &asynq.PeriodicTaskConfig{
+			Cronspec: schedule.CronExpr,
+			Task:     asynq.NewTask(interfaces.DiscoverScheduleTaskType, payload),
+			Opts: []asynq.Option{
+				asynq.Queue("default"),
+				asynq.MaxRetry(1),
+				asynq.Unique(discoverScheduleUniqueTTL),
+			},
+		})
+	}
+	return configs, nil
+}
+
+// runScheduler starts the periodic task manager which enqueues scheduled discover triggers.
+func (dw *discoverWorker) runScheduler(ctx context.Context) error {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Errorf("Discover scheduler failed: %v", err)
+		}
+	}()
+
+	mgr, err := dw.aqa.CreatePeriodicTaskManager(ctx, &discoverScheduleProvider{dts: dw.dts})
+	if err != nil {
+		logger.Errorf("Failed to create discover scheduler: %v", err)
+		return err
+	}
+
+	logger.Infof("Discover scheduler starting")
+	if err := mgr.Run(); err != nil {
+		logger.Errorf("Discover scheduler failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// processScheduleTask handles a scheduled discover trigger: creates a discover task for the catalog.
+func (dw *discoverWorker) processScheduleTask(ctx context.Context, event *asynq.Task) error {
+	var msg interfaces.DiscoverScheduleMessage
+	if err := sonic.Unmarshal(event.Payload(), &msg); err != nil {
+		logger.Errorf("Failed to unmarshal schedule message: %v", err)
+		return err
+	}
+
+	catalog, err := dw.cs.GetByID(ctx, msg.CatalogID, false)
+	if err != nil {
+		logger.Errorf("Failed to get catalog %s for scheduled discover: %v", msg.CatalogID, err)
+		return err
+	}
+	if catalog == nil || !catalog.Enabled || catalog.Type != interfaces.CatalogTypePhysical {
+		logger.Warnf("Catalog %s is not available for discover, skip scheduled discover", msg.CatalogID)
+		return nil
+	}
+
+	taskID, err := dw.dts.CreateScheduled(ctx, catalog.ID)
+	if err != nil {
+		logger.Errorf("Failed to create scheduled discover task for catalog %s: %v", catalog.ID, err)
+		return err
+	}
+	if taskID != "" {
+		logger.Infof("Scheduled discover task %s created for catalog %s", taskID, catalog.ID)
+	}
+	return nil
+}
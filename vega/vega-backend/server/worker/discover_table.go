@@ -49,13 +49,14 @@ func (dw *discoverWorker) discoverTableResources(ctx context.Context,
 		return nil, fmt.Errorf("failed to reconcile resources: %w", err)
 	}
 
-	// Step 4: 逐个补齐详细元数据:元数据采集就是补充每一个table的元数据信息
-	if err := dw.enrichTableMetadata(ctx, tableConnector, items); err != nil {
+	// Step 4: 逐个补齐详细元数据:元数据采集就是补充每一个table的元数据信息，同时检测结构漂移
+	if err := dw.enrichTableMetadata(ctx, tableConnector, result, items); err != nil {
 		return nil, fmt.Errorf("failed to enrich table metadata: %w", err)
 	}
+	finishDiscoverResult(result)
 
-	logger.Infof("Discover completed for catalog %s: new=%d, stale=%d, unchanged=%d",
-		catalog.ID, result.NewCount, result.StaleCount, result.UnchangedCount)
+	logger.Infof("Discover completed for catalog %s: new=%d, stale=%d, unchanged=%d, drifted=%d",
+		catalog.ID, result.NewCount, result.StaleCount, result.UnchangedCount, result.DriftCount)
 
 	return result, nil
 }
@@ -64,12 +65,14 @@ func (dw *discoverWorker) discoverTableResources(ctx context.Context,
 // 参数:
 //   - ctx: 上下文信息，用于控制请求的超时和取消
 //   - tableConnector: 表连接器，用于获取表的元数据
+//   - result: 发现结果，检测到的结构漂移记录到其中
 //   - items: 表发现项目列表，包含表元数据和资源信息
 //
 // 返回值:
 //   - error: 如果在处理过程中发生错误，则返回错误信息
 func (dw *discoverWorker) enrichTableMetadata(ctx context.Context,
-	tableConnector connectors.TableConnector, items []tableDiscoverItem) error {
+	tableConnector connectors.TableConnector, result *interfaces.DiscoverResult,
+	items []tableDiscoverItem) error {
 
 	// 遍历所有表发现项目
 	for _, item := range items {
@@ -83,6 +86,21 @@ func (dw *discoverWorker) enrichTableMetadata(ctx context.Context,
 			return err
 		}
 
+		// 增量发现：源端元数据指纹未变化时跳过更新
+		fingerprint := schemaFingerprint(table)
+		if resource.SourceMetadata != nil && fingerprint != "" &&
+			resource.SourceMetadata[schemaFingerprintKey] == fingerprint {
+			logger.Debugf("Table %s unchanged since last discover, skip update", table.Name)
+			continue
+		}
+
+		// 与已保存的 schema_definition 比较，记录字段/主键变化（新建资源尚无 schema，不产生漂移）
+		if len(resource.SchemaDefinition) > 0 {
+			changes := diffColumns(storedColumns(resource, "columns"), tableColumns(table))
+			changes = append(changes, diffPrimaryKeys(storedPrimaryKeys(resource), table.PKs)...)
+			addSchemaDrift(result, resource, changes...)
+		}
+
 		// 填充 Resource 元数据 ：schema_definition 字段
 		resource.Database = table.Database
 		resource.SchemaDefinition = []*interfaces.Property{}
@@ -109,6 +127,8 @@ func (dw *discoverWorker) enrichTableMetadata(ctx context.Context,
 		}
 		if len(table.PKs) > 0 {
 			sourceMetadata["primary_keys"] = table.PKs
+		} else {
+			delete(sourceMetadata, "primary_keys")
 		}
 		if len(table.Indices) > 0 {
 			sourceMetadata["indices"] = table.Indices
@@ -116,6 +136,7 @@ func (dw *discoverWorker) enrichTableMetadata(ctx context.Context,
 		if len(table.ForeignKeys) > 0 {
 			sourceMetadata["foreign_keys"] = table.ForeignKeys
 		}
+		sourceMetadata[schemaFingerprintKey] = fingerprint
 		resource.SourceMetadata = sourceMetadata
 
 		// 更新 Resource
@@ -165,6 +186,10 @@ func (dw *discoverWorker) reconcileTableResources(ctx context.Context,
 				// 之前标记为 stale，现在重新激活
 				if err := dw.rs.UpdateStatus(ctx, resource.ID, interfaces.ResourceStatusActive, ""); err != nil {
 					logger.Errorf("Failed to reactivate resource %s: %v", resource.ID, err)
+				} else {
+					addSchemaDrift(result, resource, interfaces.SchemaChange{
+						Type: interfaces.SchemaChangeResourceReactivated,
+					})
 				}
 			}
 			result.UnchangedCount++
@@ -196,14 +221,14 @@ func (dw *discoverWorker) reconcileTableResources(ctx context.Context,
 					logger.Errorf("Failed to mark resource %s as stale: %v", existing.ID, err)
 				} else {
 					result.StaleCount++
+					addSchemaDrift(result, existing, interfaces.SchemaChange{
+						Type: interfaces.SchemaChangeResourceStale,
+					})
 				}
 			}
 		}
 	}
 
-	result.Message = fmt.Sprintf("Discover completed: %d new, %d stale, %d unchanged",
-		result.NewCount, result.StaleCount, result.UnchangedCount)
-
 	return result, items, nil
 }
 
@@ -0,0 +1,223 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kweaver-ai/kweaver-go-lib/logger"
+
+	"vega-backend/interfaces"
+	"vega-backend/logics/connectors"
+)
+
+type topicDiscoverItem struct {
+	resource  *interfaces.Resource
+	topicMeta *interfaces.TopicMeta
+}
+
+// discoverTopicResources discovers topic resources from a topic connector.
+// discoverTopicResources 从连接器中获取 topic 列表，与现有资源协调后，采样消息推断字段结构
+// 参数:
+//   - ctx: 上下文信息，用于控制请求的超时和取消
+//   - catalog: 目录信息
+//   - connector: 连接器接口，用于与数据源进行交互
+//
+// 返回值:
+//   - *interfaces.DiscoverResult: 发现结果，包含新资源、过期资源和未变化资源的统计信息
+//   - error: 错误信息，如果在发现过程中出现错误则返回
+func (dw *discoverWorker) discoverTopicResources(ctx context.Context,
+	catalog *interfaces.Catalog, connector connectors.Connector) (*interfaces.DiscoverResult, error) {
+
+	topicConnector, ok := connector.(connectors.TopicConnector)
+	if !ok {
+		return nil, fmt.Errorf("connector does not support topic discover")
+	}
+
+	// Step 1: List Topics
+	sourceTopics, err := topicConnector.ListTopics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics: %w", err)
+	}
+	logger.Infof("Discovered %d topics from source", len(sourceTopics))
+
+	// Step 2: Get Existing Resources
+	existingResources, err := dw.rs.GetByCatalogID(ctx, catalog.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing resources: %w", err)
+	}
+
+	// Step 3: Reconcile
+	result, items, err := dw.reconcileTopicResources(ctx, catalog, sourceTopics, existingResources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile resources: %w", err)
+	}
+
+	// Step 4: Enrich：采样消息推断字段，同时检测结构漂移
+	if err := dw.enrichTopicMetadata(ctx, topicConnector, result, items); err != nil {
+		return nil, fmt.Errorf("failed to enrich topic metadata: %w", err)
+	}
+	finishDiscoverResult(result)
+
+	logger.Infof("Discover completed for catalog %s: new=%d, stale=%d, unchanged=%d, drifted=%d",
+		catalog.ID, result.NewCount, result.StaleCount, result.UnchangedCount, result.DriftCount)
+
+	return result, nil
+}
+
+// reconcileTopicResources reconciles source topics with existing resources.
+func (dw *discoverWorker) reconcileTopicResources(ctx context.Context,
+	catalog *interfaces.Catalog, sourceTopics []*interfaces.TopicMeta,
+	existingResources []*interfaces.Resource) (*interfaces.DiscoverResult, []topicDiscoverItem, error) {
+
+	result := &interfaces.DiscoverResult{
+		CatalogID: catalog.ID,
+	}
+
+	var items []topicDiscoverItem
+
+	existingMap := make(map[string]*interfaces.Resource)
+	for _, r := range existingResources {
+		existingMap[r.SourceIdentifier] = r
+	}
+
+	sourceMap := make(map[string]*interfaces.TopicMeta)
+	for _, topic := range sourceTopics {
+		sourceMap[topic.Name] = topic
+	}
+
+	// Handle new and existing
+	for _, topic := range sourceTopics {
+		sourceIdentifier := topic.Name
+
+		if resource, ok := existingMap[sourceIdentifier]; ok {
+			if resource.Status == interfaces.ResourceStatusStale {
+				if err := dw.rs.UpdateStatus(ctx, resource.ID, interfaces.ResourceStatusActive, ""); err != nil {
+					logger.Errorf("Failed to reactivate resource %s: %v", resource.ID, err)
+				} else {
+					addSchemaDrift(result, resource, interfaces.SchemaChange{
+						Type: interfaces.SchemaChangeResourceReactivated,
+					})
+				}
+			}
+			result.UnchangedCount++
+			items = append(items, topicDiscoverItem{
+				resource:  resource,
+				topicMeta: topic,
+			})
+		} else {
+			resource, err := dw.createTopicResource(ctx, catalog, topic)
+			if err != nil {
+				logger.Errorf("Failed to create resource %s: %v", sourceIdentifier, err)
+			} else {
+				result.NewCount++
+				items = append(items, topicDiscoverItem{
+					resource:  resource,
+					topicMeta: topic,
+				})
+			}
+		}
+	}
+
+	// Handle stale
+	for sourceIdentifier, existing := range existingMap {
+		if _, ok := sourceMap[sourceIdentifier]; !ok {
+			if existing.Status != interfaces.ResourceStatusStale {
+				if err := dw.rs.UpdateStatus(ctx, existing.ID, interfaces.ResourceStatusStale, ""); err != nil {
+					logger.Errorf("Failed to mark resource %s as stale: %v", existing.ID, err)
+				} else {
+					result.StaleCount++
+					addSchemaDrift(result, existing, interfaces.SchemaChange{
+						Type: interfaces.SchemaChangeResourceStale,
+					})
+				}
+			}
+		}
+	}
+
+	return result, items, nil
+}
+
+// createTopicResource creates a new resource for a topic.
+func (dw *discoverWorker) createTopicResource(ctx context.Context, catalog *interfaces.Catalog,
+	topic *interfaces.TopicMeta) (*interfaces.Resource, error) {
+
+	req := &interfaces.ResourceRequest{
+		CatalogID:        catalog.ID,
+		Name:             topic.Name,
+		Category:         interfaces.ResourceCategoryTopic,
+		Status:           interfaces.ResourceStatusActive,
+		SourceIdentifier: topic.Name,
+	}
+	id, err := dw.rs.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return dw.rs.GetByID(ctx, id)
+}
+
+// enrichTopicMetadata enriches topic resources with partition offsets and inferred fields.
+func (dw *discoverWorker) enrichTopicMetadata(ctx context.Context,
+	topicConnector connectors.TopicConnector, result *interfaces.DiscoverResult,
+	items []topicDiscoverItem) error {
+
+	for _, item := range items {
+		topic := item.topicMeta
+		resource := item.resource
+
+		if err := topicConnector.GetTopicMeta(ctx, topic); err != nil {
+			logger.Warnf("Failed to get metadata for topic %s: %v", topic.Name, err)
+			return err
+		}
+
+		// 增量发现：源端元数据指纹未变化时跳过更新
+		fingerprint := schemaFingerprint(topic)
+		if resource.SourceMetadata != nil && fingerprint != "" &&
+			resource.SourceMetadata[schemaFingerprintKey] == fingerprint {
+			logger.Debugf("Topic %s unchanged since last discover, skip update", topic.Name)
+			continue
+		}
+
+		// 与已保存的 schema_definition 比较，记录字段变化（新建资源尚无 schema，不产生漂移）
+		if len(resource.SchemaDefinition) > 0 {
+			addSchemaDrift(result, resource, diffColumns(storedColumns(resource, "fields"), topicColumns(topic))...)
+		}
+
+		columns := make([]*interfaces.Property, 0, len(topic.Fields))
+		for _, field := range topic.Fields {
+			columns = append(columns, &interfaces.Property{
+				Name:         field.Name,
+				Type:         field.Type,
+				DisplayName:  field.Name,
+				OriginalName: field.Name,
+				Description:  "",
+			})
+		}
+		resource.SchemaDefinition = columns
+
+		sourceMetadata := make(map[string]any)
+		if resource.SourceMetadata != nil {
+			sourceMetadata = resource.SourceMetadata
+		}
+
+		sourceMetadata["partitions"] = topic.Partitions
+		sourceMetadata["replicas"] = topic.Replicas
+		sourceMetadata["properties"] = topic.Properties
+		sourceMetadata["fields"] = topic.Fields
+		sourceMetadata[schemaFingerprintKey] = fingerprint
+		resource.SourceMetadata = sourceMetadata
+
+		if err := dw.rs.UpdateResource(ctx, resource); err != nil {
+			logger.Errorf("Failed to update metadata for topic %s: %v", topic.Name, err)
+			return err
+		}
+
+		logger.Infof("Enriched topic %s: partitions=%d, fields=%d", topic.Name, topic.Partitions, len(columns))
+	}
+	return nil
+}
@@ -64,6 +64,16 @@ func (dw *discoverWorker) Start() {
 			time.Sleep(1 * time.Second)
 		}
 	}()
+
+	// Start discover scheduler in a goroutine
+	go func() {
+		for {
+			if err := dw.runScheduler(context.Background()); err != nil {
+				logger.Errorf("Discover scheduler failed: %v", err)
+			}
+			time.Sleep(1 * time.Second)
+		}
+	}()
 }
 
 func (dw *discoverWorker) Run(ctx context.Context) error {
@@ -78,8 +88,10 @@ func (dw *discoverWorker) Run(ctx context.Context) error {
 	// Register task handler
 	mux := asynq.NewServeMux()
 	mux.Handle(interfaces.DiscoverTaskType, dw)
+	mux.HandleFunc(interfaces.DiscoverScheduleTaskType, dw.processScheduleTask)
 
-	logger.Infof("Discover worker starting, listening for task type: %s", interfaces.DiscoverTaskType)
+	logger.Infof("Discover worker starting, listening for task types: %s, %s",
+		interfaces.DiscoverTaskType, interfaces.DiscoverScheduleTaskType)
 	if err := srv.Run(mux); err != nil {
 		logger.Errorf("Discover worker failed: %v", err)
 		return err
@@ -183,6 +195,13 @@ func (dw *discoverWorker) discoverCatalog(ctx context.Context,
 	// index类型的会到这里，例如open search
 	case interfaces.ConnectorCategoryIndex:
 		return dw.discoverIndexResources(ctx, catalog, connector)
+	// topic类型的会到这里，例如kafka
+	case interfaces.ConnectorCategoryTopic:
+		return dw.discoverTopicResources(ctx, catalog, connector)
+	// metric类型的会到这里，例如prometheus
+	case interfaces.ConnectorCategoryMetric:
+		return dw.discoverMetricResources(ctx, catalog, connector)
+	// file/fileset类型的会到这里，例如s3
 	case interfaces.ConnectorCategoryFile, interfaces.ConnectorCategoryFileset:
 		return dw.discoverFileResources(ctx, catalog, connector)
 	default:
@@ -190,13 +209,6 @@ func (dw *discoverWorker) discoverCatalog(ctx context.Context,
 	}
 }
 
-// discoverFileResources discovers file resources from a file connector.
-func (dw *discoverWorker) discoverFileResources(ctx context.Context,
-	catalog *interfaces.Catalog, connector connectors.Connector) (*interfaces.DiscoverResult, error) {
-	// TODO: 实现文件资源发现逻辑
-	return nil, fmt.Errorf("file resource discover not implemented yet")
-}
-
 // createAndConnectConnector creates and connects a connector for the catalog.
 func (dw *discoverWorker) createAndConnectConnector(ctx context.Context,
 	catalog *interfaces.Catalog) (connectors.Connector, error) {
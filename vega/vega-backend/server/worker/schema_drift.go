@@ -0,0 +1,239 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package worker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/bytedance/sonic"
+
+	"vega-backend/interfaces"
+)
+
+const (
+	// sourceMetadata 中记录上次发现结果指纹的 key，指纹不变时跳过 Resource 更新
+	schemaFingerprintKey = "schema_fingerprint"
+)
+
+// columnSnapshot 字段快照，用于比较前后两次发现的字段结构
+type columnSnapshot struct {
+	Name     string
+	Type     string
+	OrigType string
+}
+
+// diffColumns 比较旧字段与新字段，返回新增、删除、类型变化的字段
+// 旧字段顺序优先，新增字段按新字段顺序追加
+func diffColumns(oldCols, newCols []columnSnapshot) []interfaces.SchemaChange {
+	changes := []interfaces.SchemaChange{}
+
+	newMap := make(map[string]columnSnapshot, len(newCols))
+	for _, col := range newCols {
+		newMap[col.Name] = col
+	}
+	oldMap := make(map[string]columnSnapshot, len(oldCols))
+	for _, col := range oldCols {
+		oldMap[col.Name] = col
+	}
+
+	for _, oldCol := range oldCols {
+		newCol, ok := newMap[oldCol.Name]
+		if !ok {
+			changes = append(changes, interfaces.SchemaChange{
+				Type:        interfaces.SchemaChangeColumnDropped,
+				Column:      oldCol.Name,
+				OldType:     oldCol.Type,
+				OldOrigType: oldCol.OrigType,
+			})
+			continue
+		}
+		// 原始类型缺失时（历史数据未记录）仅比较 VEGA 类型
+		typeChanged := oldCol.Type != newCol.Type
+		if oldCol.OrigType != "" && newCol.OrigType != "" && oldCol.OrigType != newCol.OrigType {
+			typeChanged = true
+		}
+		if typeChanged {
+			changes = append(changes, interfaces.SchemaChange{
+				Type:        interfaces.SchemaChangeColumnTypeChanged,
+				Column:      oldCol.Name,
+				OldType:     oldCol.Type,
+				NewType:     newCol.Type,
+				OldOrigType: oldCol.OrigType,
+				NewOrigType: newCol.OrigType,
+			})
+		}
+	}
+
+	for _, newCol := range newCols {
+		if _, ok := oldMap[newCol.Name]; !ok {
+			changes = append(changes, interfaces.SchemaChange{
+				Type:        interfaces.SchemaChangeColumnAdded,
+				Column:      newCol.Name,
+				NewType:     newCol.Type,
+				NewOrigType: newCol.OrigType,
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffPrimaryKeys 比较主键列（按顺序），不一致时返回主键变化
+func diffPrimaryKeys(oldPKs, newPKs []string) []interfaces.SchemaChange {
+	if slices.Equal(oldPKs, newPKs) {
+		return nil
+	}
+	return []interfaces.SchemaChange{{
+		Type:   interfaces.SchemaChangePrimaryKeyChanged,
+		OldPKs: oldPKs,
+		NewPKs: newPKs,
+	}}
+}
+
+// storedColumns 从 Resource 已保存的 SchemaDefinition 还原字段快照，
+// 原始类型取自 sourceMetadata 中的 origTypeKey（table 为 columns，index 为 mapping，topic/file/fileset 为 fields，metric 为 labels）
+func storedColumns(resource *interfaces.Resource, origTypeKey string) []columnSnapshot {
+	origTypes := map[string]string{}
+	if resource.SourceMetadata != nil {
+		collect := func(item any) {
+			if m, ok := item.(map[string]any); ok {
+				name, _ := m["name"].(string)
+				origType, _ := m["orig_type"].(string)
+				if name != "" {
+					origTypes[name] = origType
+				}
+			}
+		}
+		switch v := resource.SourceMetadata[origTypeKey].(type) {
+		case []any:
+			for _, item := range v {
+				collect(item)
+			}
+		case map[string]any:
+			for _, item := range v {
+				collect(item)
+			}
+		}
+	}
+
+	cols := make([]columnSnapshot, 0, len(resource.SchemaDefinition))
+	for _, prop := range resource.SchemaDefinition {
+		name := prop.OriginalName
+		if name == "" {
+			name = prop.Name
+		}
+		cols = append(cols, columnSnapshot{
+			Name:     name,
+			Type:     prop.Type,
+			OrigType: origTypes[name],
+		})
+	}
+	return cols
+}
+
+// storedPrimaryKeys 从 Resource 的 sourceMetadata 中读取已保存的主键列
+func storedPrimaryKeys(resource *interfaces.Resource) []string {
+	if resource.SourceMetadata == nil {
+		return nil
+	}
+	switch v := resource.SourceMetadata["primary_keys"].(type) {
+	case []string:
+		return v
+	case []any:
+		pks := make([]string, 0, len(v))
+		for _, item := range v {
+			pks = append(pks, fmt.Sprint(item))
+		}
+		return pks
+	}
+	return nil
+}
+
+// tableColumns 将 TableMeta 的字段转换为字段快照
+func tableColumns(table *interfaces.TableMeta) []columnSnapshot {
+	cols := make([]columnSnapshot, 0, len(table.Columns))
+	for _, col := range table.Columns {
+		cols = append(cols, columnSnapshot{Name: col.Name, Type: col.Type, OrigType: col.OrigType})
+	}
+	return cols
+}
+
+// indexColumns 将 IndexMeta 的 mapping 转换为字段快照，按字段名排序保证结果稳定
+func indexColumns(idx *interfaces.IndexMeta) []columnSnapshot {
+	cols := make([]columnSnapshot, 0, len(idx.Mapping))
+	for _, field := range idx.Mapping {
+		cols = append(cols, columnSnapshot{Name: field.Name, Type: field.Type, OrigType: field.OrigType})
+	}
+	sort.Slice(cols, func(i, j int) bool { return cols[i].Name < cols[j].Name })
+	return cols
+}
+
+// topicColumns 将 TopicMeta 推断出的字段转换为字段快照
+func topicColumns(topic *interfaces.TopicMeta) []columnSnapshot {
+	return fieldColumns(topic.Fields)
+}
+
+// fieldColumns 将推断出的字段（topic、file、fileset）转换为字段快照
+func fieldColumns(fields []interfaces.FieldMeta) []columnSnapshot {
+	cols := make([]columnSnapshot, 0, len(fields))
+	for _, field := range fields {
+		cols = append(cols, columnSnapshot{Name: field.Name, Type: field.Type, OrigType: field.OrigType})
+	}
+	return cols
+}
+
+// metricColumns 将 MetricMeta 的标签转换为字段快照，标签值均为字符串
+func metricColumns(metric *interfaces.MetricMeta) []columnSnapshot {
+	cols := make([]columnSnapshot, 0, len(metric.Labels))
+	for _, label := range metric.Labels {
+		cols = append(cols, columnSnapshot{Name: label, Type: interfaces.DataType_String})
+	}
+	return cols
+}
+
+// schemaFingerprint 计算源端元数据指纹，用于增量发现时判断元数据是否有变化
+func schemaFingerprint(meta any) string {
+	data, err := sonic.ConfigStd.Marshal(meta)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// addSchemaDrift 将资源的结构变化合并到发现结果中，同一资源的多次变化聚合为一条漂移记录
+func addSchemaDrift(result *interfaces.DiscoverResult, resource *interfaces.Resource,
+	changes ...interfaces.SchemaChange) {
+
+	if len(changes) == 0 {
+		return
+	}
+	for _, drift := range result.Drifts {
+		if drift.ResourceID == resource.ID {
+			drift.Changes = append(drift.Changes, changes...)
+			return
+		}
+	}
+	result.Drifts = append(result.Drifts, &interfaces.SchemaDrift{
+		ResourceID:       resource.ID,
+		CatalogID:        result.CatalogID,
+		SourceIdentifier: resource.SourceIdentifier,
+		Changes:          changes,
+		DetectTime:       time.Now().UnixMilli(),
+	})
+}
+
+// finishDiscoverResult 汇总漂移数量并生成结果描述
+func finishDiscoverResult(result *interfaces.DiscoverResult) {
+	result.DriftCount = len(result.Drifts)
+	result.Message = fmt.Sprintf("Discover completed: %d new, %d stale, %d unchanged, %d drifted",
+		result.NewCount, result.StaleCount, result.UnchangedCount, result.DriftCount)
+}
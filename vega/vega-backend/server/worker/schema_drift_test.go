@@ -0,0 +1,124 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/bytedance/sonic"
+
+	"vega-backend/interfaces"
+)
+
+func TestDiffColumns(t *testing.T) {
+	oldCols := []columnSnapshot{
+		{Name: "id", Type: "integer", OrigType: "int"},
+		{Name: "name", Type: "string", OrigType: "varchar(64)"},
+		{Name: "age", Type: "integer", OrigType: "int"},
+	}
+	newCols := []columnSnapshot{
+		{Name: "id", Type: "integer", OrigType: "bigint"},
+		{Name: "name", Type: "string", OrigType: "varchar(64)"},
+		{Name: "email", Type: "string", OrigType: "varchar(128)"},
+	}
+
+	changes := diffColumns(oldCols, newCols)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %d: %+v", len(changes), changes)
+	}
+
+	// 原始类型变化（int -> bigint）即使 VEGA 类型相同也视为类型变化
+	if changes[0].Type != interfaces.SchemaChangeColumnTypeChanged || changes[0].Column != "id" ||
+		changes[0].OldOrigType != "int" || changes[0].NewOrigType != "bigint" {
+		t.Errorf("unexpected change[0]: %+v", changes[0])
+	}
+	if changes[1].Type != interfaces.SchemaChangeColumnDropped || changes[1].Column != "age" {
+		t.Errorf("unexpected change[1]: %+v", changes[1])
+	}
+	if changes[2].Type != interfaces.SchemaChangeColumnAdded || changes[2].Column != "email" ||
+		changes[2].NewType != "string" {
+		t.Errorf("unexpected change[2]: %+v", changes[2])
+	}
+
+	// 历史数据缺少原始类型时只比较 VEGA 类型
+	changes = diffColumns([]columnSnapshot{{Name: "id", Type: "integer"}},
+		[]columnSnapshot{{Name: "id", Type: "integer", OrigType: "bigint"}})
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestDiffPrimaryKeys(t *testing.T) {
+	if changes := diffPrimaryKeys([]string{"id"}, []string{"id"}); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+
+	changes := diffPrimaryKeys([]string{"id"}, []string{"id", "tenant_id"})
+	if len(changes) != 1 || changes[0].Type != interfaces.SchemaChangePrimaryKeyChanged {
+		t.Fatalf("expected primary key change, got %+v", changes)
+	}
+	if len(changes[0].OldPKs) != 1 || len(changes[0].NewPKs) != 2 {
+		t.Errorf("unexpected primary keys: %+v", changes[0])
+	}
+}
+
+func TestStoredColumns(t *testing.T) {
+	// 模拟从数据库读出的 Resource：source_metadata 经 JSON 反序列化
+	table := &interfaces.TableMeta{
+		Columns: []interfaces.ColumnMeta{
+			{Name: "id", Type: "integer", OrigType: "int"},
+			{Name: "name", Type: "string", OrigType: "varchar(64)"},
+		},
+		PKs: []string{"id"},
+	}
+	var sourceMetadata map[string]any
+	data, _ := sonic.Marshal(map[string]any{
+		"columns":      table.Columns,
+		"primary_keys": table.PKs,
+	})
+	if err := sonic.Unmarshal(data, &sourceMetadata); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	resource := &interfaces.Resource{
+		SchemaDefinition: []*interfaces.Property{
+			{Name: "id", Type: "integer", OriginalName: "id"},
+			{Name: "name", Type: "string", OriginalName: "name"},
+		},
+		SourceMetadata: sourceMetadata,
+	}
+
+	if changes := diffColumns(storedColumns(resource, "columns"), tableColumns(table)); len(changes) != 0 {
+		t.Errorf("expected no column changes, got %+v", changes)
+	}
+	if changes := diffPrimaryKeys(storedPrimaryKeys(resource), table.PKs); len(changes) != 0 {
+		t.Errorf("expected no primary key changes, got %+v", changes)
+	}
+}
+
+func TestAddSchemaDrift(t *testing.T) {
+	result := &interfaces.DiscoverResult{CatalogID: "c1"}
+	resource := &interfaces.Resource{ID: "r1", SourceIdentifier: "db.t1"}
+
+	addSchemaDrift(result, resource)
+	if len(result.Drifts) != 0 {
+		t.Fatalf("expected no drift without changes, got %d", len(result.Drifts))
+	}
+
+	addSchemaDrift(result, resource, interfaces.SchemaChange{Type: interfaces.SchemaChangeResourceReactivated})
+	addSchemaDrift(result, resource, interfaces.SchemaChange{Type: interfaces.SchemaChangeColumnAdded, Column: "c"})
+	if len(result.Drifts) != 1 || len(result.Drifts[0].Changes) != 2 {
+		t.Fatalf("expected changes aggregated into one drift, got %+v", result.Drifts)
+	}
+	if result.Drifts[0].CatalogID != "c1" || result.Drifts[0].SourceIdentifier != "db.t1" {
+		t.Errorf("unexpected drift: %+v", result.Drifts[0])
+	}
+
+	finishDiscoverResult(result)
+	if result.DriftCount != 1 {
+		t.Errorf("expected drift count 1, got %d", result.DriftCount)
+	}
+}
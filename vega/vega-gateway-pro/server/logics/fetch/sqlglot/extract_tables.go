@@ -0,0 +1,550 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package sqlglot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 查询结构中的子句关键字，表达式扫描到这些关键字时结束
+var clauseKeywords = map[string]bool{
+	"FROM": true, "WHERE": true, "GROUP": true, "HAVING": true, "QUALIFY": true, "WINDOW": true,
+	"ORDER": true, "LIMIT": true, "OFFSET": true, "FETCH": true, "FOR": true, "INTO": true,
+	"CONNECT": true, "START": true, "LOCK": true,
+	"UNION": true, "INTERSECT": true, "EXCEPT": true, "MINUS": true,
+}
+
+// 集合运算关键字
+var setOperators = map[string]bool{
+	"UNION": true, "INTERSECT": true, "EXCEPT": true, "MINUS": true,
+}
+
+// 连接关键字
+var joinKeywords = map[string]bool{
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "CROSS": true,
+	"NATURAL": true, "STRAIGHT_JOIN": true, "OUTER": true, "SEMI": true, "ANTI": true,
+}
+
+// 连接条件与时间旅行表达式的终止关键字
+var (
+	joinConditionTerminators = mergeKeywords(clauseKeywords, joinKeywords, map[string]bool{",": true})
+	tableVersionTerminators  = mergeKeywords(joinConditionTerminators, map[string]bool{"ON": true, "USING": true})
+)
+
+// 不能作为表别名的关键字
+var reservedAliases = map[string]bool{
+	"SELECT": true, "ON": true, "USING": true, "AS": true, "WITH": true, "LATERAL": true,
+	"USE": true, "FORCE": true, "IGNORE": true, "PARTITION": true, "TABLESAMPLE": true, "SAMPLE": true,
+	"PIVOT": true, "UNPIVOT": true, "MATCH_RECOGNIZE": true, "VALUES": true, "SET": true, "RETURNING": true,
+	"AND": true, "OR": true, "NOT": true, "APPLY": true,
+}
+
+// tableExtractor 在词法单元上做轻量的递归下降解析，只识别与表引用相关的查询结构，
+// 其余表达式按括号匹配跳过，跳过时仍会进入其中的子查询
+type tableExtractor struct {
+	tokens []token
+	pos    int
+	tables []*Table
+}
+
+// extractTables 提取 SQL 中第一条语句引用的表，结果与 sqlglot 的 find_all(Table) 一致：
+// 包含 FROM/JOIN 中的物理表和对 CTE 的引用，不包含子查询别名、表函数与 UNNEST
+func extractTables(sql string, dialect string) ([]*Table, error) {
+	rules, ok := dialects[strings.ToLower(dialect)]
+	if !ok {
+		return nil, fmt.Errorf("unknown dialect '%s'", dialect)
+	}
+
+	tokens, err := tokenize(sql, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &tableExtractor{tokens: tokens}
+	for e.isPunct(";") {
+		e.pos++
+	}
+	if e.peek().kind == tokenEOF {
+		return nil, fmt.Errorf("no expression was parsed from '%s'", sql)
+	}
+	if !e.isQueryStart(0) {
+		return nil, e.errorf("unsupported statement")
+	}
+	if err := e.parseQuery(); err != nil {
+		return nil, err
+	}
+	// 只解析第一条语句
+	if !e.isPunct(";") && e.peek().kind != tokenEOF {
+		return nil, e.errorf("unexpected token")
+	}
+
+	tables := e.tables
+	if tables == nil {
+		tables = []*Table{}
+	}
+	return tables, nil
+}
+
+// parseQuery 解析查询：[WITH ...] 查询项 {集合运算 查询项}
+func (e *tableExtractor) parseQuery() error {
+	if e.isWord("WITH") {
+		if err := e.parseWith(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		if err := e.parseQueryTerm(); err != nil {
+			return err
+		}
+		if !setOperators[e.peekWord()] {
+			break
+		}
+		e.pos++
+		if e.isWord("ALL") || e.isWord("DISTINCT") {
+			e.pos++
+		}
+	}
+
+	// 集合运算之后的 ORDER BY/LIMIT 等
+	return e.parseClauses()
+}
+
+// parseWith 解析 CTE 定义：WITH [RECURSIVE] name [(cols)] AS [[NOT] MATERIALIZED] (query), ...
+func (e *tableExtractor) parseWith() error {
+	e.pos++
+	if e.isWord("RECURSIVE") {
+		e.pos++
+	}
+	for {
+		if !e.isIdent() {
+			return e.errorf("expected CTE name")
+		}
+		e.pos++
+		if e.isPunct("(") {
+			if err := e.skipParens(); err != nil {
+				return err
+			}
+		}
+		if !e.isWord("AS") {
+			return e.errorf("expected AS in CTE")
+		}
+		e.pos++
+		if e.isWord("NOT") {
+			e.pos++
+		}
+		if e.isWord("MATERIALIZED") {
+			e.pos++
+		}
+		if err := e.parseParenQuery(); err != nil {
+			return err
+		}
+		if !e.isPunct(",") {
+			return nil
+		}
+		e.pos++
+	}
+}
+
+// parseQueryTerm 解析 SELECT、VALUES 或括号中的查询
+func (e *tableExtractor) parseQueryTerm() error {
+	switch {
+	case e.isPunct("("):
+		return e.parseParenQuery()
+	case e.isWord("WITH"):
+		return e.parseQuery()
+	case e.isWord("VALUES"):
+		e.pos++
+		return e.scanExpr(clauseKeywords)
+	case e.isWord("SELECT"):
+		return e.parseSelect()
+	default:
+		return e.errorf("expected SELECT")
+	}
+}
+
+// parseParenQuery 解析 (query)
+func (e *tableExtractor) parseParenQuery() error {
+	if !e.isPunct("(") {
+		return e.errorf("expected (")
+	}
+	e.pos++
+	if err := e.parseQuery(); err != nil {
+		return err
+	}
+	return e.expectPunct(")")
+}
+
+// parseSelect 解析 SELECT 列表、FROM 及其后的子句
+func (e *tableExtractor) parseSelect() error {
+	e.pos++
+	if err := e.scanExpr(clauseKeywords); err != nil {
+		return err
+	}
+	// SELECT ... INTO var FROM ...
+	if e.isWord("INTO") {
+		e.pos++
+		if err := e.scanExpr(clauseKeywords); err != nil {
+			return err
+		}
+	}
+	if e.isWord("FROM") {
+		e.pos++
+		if err := e.parseFromList(); err != nil {
+			return err
+		}
+	}
+	return e.parseClauses()
+}
+
+// parseClauses 扫描 WHERE、GROUP BY、ORDER BY 等子句，只关心其中的子查询
+func (e *tableExtractor) parseClauses() error {
+	for word := e.peekWord(); clauseKeywords[word] && !setOperators[word]; word = e.peekWord() {
+		e.pos++
+		if err := e.scanExpr(clauseKeywords); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseFromList 解析以逗号或 JOIN 连接的表引用
+func (e *tableExtractor) parseFromList() error {
+	if err := e.parseTableFactor(); err != nil {
+		return err
+	}
+	for {
+		switch {
+		case e.isPunct(","):
+			e.pos++
+		case e.isJoinStart():
+			for !e.isWord("JOIN") && !e.isWord("STRAIGHT_JOIN") && !e.isWord("APPLY") {
+				e.pos++
+			}
+			e.pos++
+		default:
+			return nil
+		}
+
+		if err := e.parseTableFactor(); err != nil {
+			return err
+		}
+		switch {
+		case e.isWord("ON"):
+			e.pos++
+			if err := e.scanExpr(joinConditionTerminators); err != nil {
+				return err
+			}
+		case e.isWord("USING"):
+			e.pos++
+			if err := e.skipParens(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseTableFactor 解析单个表引用：表名、子查询、括号中的连接或表函数，及其别名与修饰
+func (e *tableExtractor) parseTableFactor() error {
+	for e.isWord("LATERAL") || e.isWord("ONLY") {
+		e.pos++
+	}
+
+	switch {
+	case e.isPunct("("):
+		if err := e.parseParenFactor(); err != nil {
+			return err
+		}
+
+	case e.isIdent():
+		parts := []string{e.next().text}
+		for e.isPunct(".") {
+			e.pos++
+			if !e.isIdent() {
+				return e.errorf("expected identifier")
+			}
+			parts = append(parts, e.next().text)
+		}
+
+		// 表函数，如 UNNEST(...)、TABLE(...)、generate_series(...)
+		if e.isPunct("(") {
+			if err := e.skipParens(); err != nil {
+				return err
+			}
+			break
+		}
+		e.tables = append(e.tables, newTable(parts))
+
+	default:
+		return e.errorf("expected table name")
+	}
+
+	return e.parseTableSuffix()
+}
+
+// parseParenFactor 解析括号中的子查询或连接，如 (SELECT ...)、(a JOIN b ON ...)
+// 以多层括号开头时无法预先区分两者，先按子查询解析，失败后回退按连接解析
+func (e *tableExtractor) parseParenFactor() error {
+	if e.isQueryStart(1) {
+		pos, n := e.pos, len(e.tables)
+		err := e.parseParenQuery()
+		if err == nil || !e.isPunctAt(pos+1, "(") {
+			return err
+		}
+		e.pos, e.tables = pos, e.tables[:n]
+	}
+
+	e.pos++
+	if err := e.parseFromList(); err != nil {
+		return err
+	}
+	return e.expectPunct(")")
+}
+
+// parseTableSuffix 跳过表别名及 PARTITION、TABLESAMPLE、索引提示等修饰
+func (e *tableExtractor) parseTableSuffix() error {
+	aliased := false
+	for {
+		switch word := e.peekWord(); {
+		case word == "USE" || word == "FORCE" || word == "IGNORE":
+			// USE INDEX FOR JOIN (...)
+			for !e.isPunct("(") && !e.isQueryEnd() {
+				e.pos++
+			}
+			if err := e.skipParens(); err != nil {
+				return err
+			}
+		case word == "PARTITION" || word == "SAMPLE" || word == "TABLESAMPLE" ||
+			word == "PIVOT" || word == "UNPIVOT" || word == "MATCH_RECOGNIZE":
+			for !e.isPunct("(") && !e.isQueryEnd() {
+				e.pos++
+			}
+			if err := e.skipParens(); err != nil {
+				return err
+			}
+			// TABLESAMPLE ... REPEATABLE (seed)
+			if e.isWord("REPEATABLE") {
+				e.pos++
+				if err := e.skipParens(); err != nil {
+					return err
+				}
+			}
+		case word == "WITH" && strings.EqualFold(e.peekAt(1).text, "ORDINALITY"):
+			e.pos += 2
+		case word == "FOR" && e.peekAt(1).kind == tokenWord &&
+			(strings.EqualFold(e.peekAt(1).text, "SYSTEM_TIME") || strings.EqualFold(e.peekAt(1).text, "TIMESTAMP") ||
+				strings.EqualFold(e.peekAt(1).text, "VERSION")):
+			// 时间旅行：FOR TIMESTAMP AS OF expr
+			e.pos += 2
+			if err := e.scanExpr(tableVersionTerminators); err != nil {
+				return err
+			}
+		case word == "AS" && !aliased:
+			e.pos++
+			if !e.isIdent() {
+				return e.errorf("expected alias")
+			}
+			fallthrough
+		case !aliased && e.isAlias():
+			e.pos++
+			aliased = true
+			if e.isPunct("(") {
+				if err := e.skipParens(); err != nil {
+					return err
+				}
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// scanExpr 扫描表达式直到遇到括号外的终止关键字或查询结束，进入其中的括号查找子查询
+func (e *tableExtractor) scanExpr(terminators map[string]bool) error {
+	for {
+		t := e.peek()
+		switch {
+		case t.kind == tokenEOF, e.isPunct(")"), e.isPunct(";"):
+			return nil
+		case t.kind == tokenPunct && terminators[t.text]:
+			return nil
+		case t.kind == tokenWord && terminators[t.upper]:
+			if joinKeywords[t.upper] && !e.isJoinStart() {
+				e.pos++
+				continue
+			}
+			return nil
+		case e.isPunct("("):
+			if err := e.skipParens(); err != nil {
+				return err
+			}
+		default:
+			e.pos++
+		}
+	}
+}
+
+// skipParens 跳过一对括号，括号内为查询时解析其中的表
+func (e *tableExtractor) skipParens() error {
+	if !e.isPunct("(") {
+		return e.errorf("expected (")
+	}
+	if next := e.peekAt(1); next.kind == tokenWord && (next.upper == "SELECT" || next.upper == "WITH") {
+		return e.parseParenQuery()
+	}
+
+	e.pos++
+	for !e.isPunct(")") {
+		switch {
+		case e.peek().kind == tokenEOF:
+			return e.errorf("expected )")
+		case e.isPunct("("):
+			if err := e.skipParens(); err != nil {
+				return err
+			}
+		default:
+			e.pos++
+		}
+	}
+	e.pos++
+	return nil
+}
+
+// isQueryStart 判断 offset 处（跳过左括号后）是否为查询的开始
+func (e *tableExtractor) isQueryStart(offset int) bool {
+	for e.peekAt(offset).kind == tokenPunct && e.peekAt(offset).text == "(" {
+		offset++
+	}
+	t := e.peekAt(offset)
+	return t.kind == tokenWord && (t.upper == "SELECT" || t.upper == "WITH" || t.upper == "VALUES")
+}
+
+// isQueryEnd 判断当前查询是否结束：右括号、分号、集合运算或输入结束
+func (e *tableExtractor) isQueryEnd() bool {
+	t := e.peek()
+	return t.kind == tokenEOF || e.isPunct(")") || e.isPunct(";") || (t.kind == tokenWord && setOperators[t.upper])
+}
+
+// isJoinStart 判断是否为连接的开始，排除 LEFT(...)、RIGHT(...) 等同名函数
+func (e *tableExtractor) isJoinStart() bool {
+	t := e.peek()
+	if t.kind != tokenWord || !joinKeywords[t.upper] {
+		return false
+	}
+	if t.upper == "JOIN" || t.upper == "STRAIGHT_JOIN" {
+		return true
+	}
+	// 其余关键字后须跟随连接关键字，直到 JOIN 或 APPLY
+	for i := 1; ; i++ {
+		next := e.peekAt(i)
+		if next.kind != tokenWord {
+			return false
+		}
+		if next.upper == "JOIN" || next.upper == "APPLY" {
+			return true
+		}
+		if !joinKeywords[next.upper] {
+			return false
+		}
+	}
+}
+
+// isAlias 判断当前是否为表别名
+func (e *tableExtractor) isAlias() bool {
+	t := e.peek()
+	if t.kind == tokenQuoted {
+		return true
+	}
+	return t.kind == tokenWord && !clauseKeywords[t.upper] && !joinKeywords[t.upper] && !reservedAliases[t.upper]
+}
+
+func (e *tableExtractor) isIdent() bool {
+	kind := e.peek().kind
+	return kind == tokenWord || kind == tokenQuoted
+}
+
+func (e *tableExtractor) isWord(upper string) bool {
+	t := e.peek()
+	return t.kind == tokenWord && t.upper == upper
+}
+
+func (e *tableExtractor) isPunct(text string) bool {
+	t := e.peek()
+	return t.kind == tokenPunct && t.text == text
+}
+
+func (e *tableExtractor) isPunctAt(pos int, text string) bool {
+	return pos < len(e.tokens) && e.tokens[pos].kind == tokenPunct && e.tokens[pos].text == text
+}
+
+func (e *tableExtractor) peekWord() string {
+	if t := e.peek(); t.kind == tokenWord {
+		return t.upper
+	}
+	return ""
+}
+
+func (e *tableExtractor) peek() token {
+	return e.peekAt(0)
+}
+
+func (e *tableExtractor) peekAt(offset int) token {
+	if e.pos+offset >= len(e.tokens) {
+		return e.tokens[len(e.tokens)-1]
+	}
+	return e.tokens[e.pos+offset]
+}
+
+func (e *tableExtractor) next() token {
+	t := e.peek()
+	if t.kind != tokenEOF {
+		e.pos++
+	}
+	return t
+}
+
+func (e *tableExtractor) expectPunct(text string) error {
+	if !e.isPunct(text) {
+		return e.errorf("expected %s", text)
+	}
+	e.pos++
+	return nil
+}
+
+func (e *tableExtractor) errorf(format string, args ...any) error {
+	t := e.peek()
+	near := t.text
+	if t.kind == tokenEOF {
+		near = "end of input"
+	}
+	return fmt.Errorf("%s near '%s' at position %d", fmt.Sprintf(format, args...), near, t.pos)
+}
+
+// newTable 将 [catalog.][schema.]name 形式的名称转换为 Table
+func newTable(parts []string) *Table {
+	table := &Table{Name: parts[len(parts)-1]}
+	switch len(parts) {
+	case 1:
+	case 2:
+		table.Schema = parts[0]
+	default:
+		table.Catalog = parts[0]
+		table.Schema = parts[1]
+		table.Name = strings.Join(parts[2:], ".")
+	}
+	return table
+}
+
+func mergeKeywords(sets ...map[string]bool) map[string]bool {
+	merged := make(map[string]bool)
+	for _, set := range sets {
+		for k := range set {
+			merged[k] = true
+		}
+	}
+	return merged
+}
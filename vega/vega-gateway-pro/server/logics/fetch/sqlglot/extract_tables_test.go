@@ -0,0 +1,96 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package sqlglot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+)
+
+// goldenCase testdata/extract_tables.json 中的用例，期望结果为 generate_golden.py 固定的 sqlglot 版本下 find_all(Table) 的输出，
+// 可通过 testdata/generate_golden.py 重新生成
+type goldenCase struct {
+	Name    string   `json:"name"`
+	Dialect string   `json:"dialect"`
+	SQL     string   `json:"sql"`
+	Tables  []*Table `json:"tables"`
+	Error   bool     `json:"error"`
+}
+
+// tableKeys 将表转换为排序后的 catalog.schema.name 列表，sqlglot 按广度优先遍历语法树，顺序不作比较
+func tableKeys(tables []*Table) []string {
+	keys := make([]string, 0, len(tables))
+	for _, t := range tables {
+		keys = append(keys, fmt.Sprintf("%s|%s|%s", t.Catalog, t.Schema, t.Name))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestExtractTables_Golden(t *testing.T) {
+	data, err := os.ReadFile("testdata/extract_tables.json")
+	if err != nil {
+		t.Fatalf("read golden file failed: %v", err)
+	}
+	var cases []goldenCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatalf("parse golden file failed: %v", err)
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result, err := ExtractTables(c.SQL, c.Dialect)
+			if c.Error {
+				if err == nil {
+					t.Fatalf("expected error, got tables %v", tableKeys(result.Tables))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractTables failed: %v", err)
+			}
+			if result.SQL != c.SQL || result.Dialect != c.Dialect || result.Error != "" {
+				t.Errorf("unexpected result fields: %+v", result)
+			}
+
+			got, want := tableKeys(result.Tables), tableKeys(c.Tables)
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("tables mismatch\n got: %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestExtractTables_ResultJSON(t *testing.T) {
+	result, err := ExtractTables("SELECT * FROM c.s.t", "trino")
+	if err != nil {
+		t.Fatalf("ExtractTables failed: %v", err)
+	}
+	data, _ := json.Marshal(result)
+	expected := `{"tables":[{"catalog":"c","schema":"s","name":"t"}],"sql":"SELECT * FROM c.s.t","dialect":"trino","error":""}`
+	if string(data) != expected {
+		t.Errorf("unexpected json:\n got: %s\nwant: %s", data, expected)
+	}
+
+	result, _ = ExtractTables("SELECT 1", "trino")
+	if data, _ := json.Marshal(result.Tables); string(data) != "[]" {
+		t.Errorf("expected empty tables array, got %s", data)
+	}
+}
+
+func BenchmarkExtractTables(b *testing.B) {
+	sql := "WITH r AS (SELECT * FROM c1.s.orders WHERE ts > now()) " +
+		"SELECT o.id, (SELECT max(x) FROM c1.s.events e WHERE e.oid = o.id) FROM r o " +
+		"LEFT JOIN c2.s.customers c ON o.cid = c.id WHERE o.id IN (SELECT oid FROM c1.s.refunds)"
+	for i := 0; i < b.N; i++ {
+		if _, err := ExtractTables(sql, "trino"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
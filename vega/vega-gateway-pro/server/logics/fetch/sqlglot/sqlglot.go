@@ -38,56 +38,19 @@ type SQLParseResult struct {
 }
 
 // ExtractTables 从SQL中提取所有表名
+// 纯 Go 实现，支持 mysql、oracle、postgres、trino 方言，结果与 sqlglot 的 find_all(Table) 保持一致
 func ExtractTables(sql string, dialect string) (*ExtractTablesResult, error) {
-	cmd := exec.Command("python3", "-c", `
-import sys
-import json
-import sqlglot
-from sqlglot.expressions import Table
-
-try:
-    sql = sys.argv[1]
-    dialect = sys.argv[2]
-    tables = [{
-        "catalog": t.catalog,
-        "schema": t.db,
-        "name": t.name
-    } for t in sqlglot.parse_one(sql, dialect=dialect).find_all(Table)]
-    print(json.dumps({
-        "tables": tables,
-        "sql": sql,
-        "dialect": dialect,
-        "error": None
-    }))
-except Exception as e:
-    print(json.dumps({
-        "tables": [],
-        "sql": sql,
-        "dialect": dialect,
-        "error": str(e)
-    }))
-`, sql, dialect)
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err := cmd.Run()
+	tables, err := extractTables(sql, dialect)
 	if err != nil {
 		logger.Errorf("ExtractTables failed, %s", err.Error())
 		return nil, err
 	}
 
-	var result ExtractTablesResult
-	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
-		logger.Errorf("ExtractTables failed, %s", err.Error())
-		return nil, err
-	}
-
-	if result.Error != "" {
-		logger.Errorf("ExtractTables failed, %s", result.Error)
-		return nil, errors.New(result.Error)
-	}
-
-	return &result, nil
+	return &ExtractTablesResult{
+		Tables:  tables,
+		SQL:     sql,
+		Dialect: dialect,
+	}, nil
 }
 
 // MapDataSourceTypeToDialect 将数据源类型映射到sqlglot方言
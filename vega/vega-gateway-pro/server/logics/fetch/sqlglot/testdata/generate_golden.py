@@ -0,0 +1,49 @@
+"""Regenerate extract_tables.json with sqlglot, the reference for ExtractTables.
+
+The expected tables are the output of find_all(Table) under the pinned sqlglot
+version, commit the regenerated file unchanged.
+
+Usage:
+    pip install sqlglot==26.0.0
+    python3 generate_golden.py          # rewrite extract_tables.json
+    python3 generate_golden.py --check  # exit 1 if extract_tables.json is stale
+"""
+
+import json
+import os
+import sys
+
+import sqlglot
+from sqlglot.expressions import Table
+
+SQLGLOT_VERSION = "26.0.0"
+
+if sqlglot.__version__ != SQLGLOT_VERSION:
+    sys.exit(f"sqlglot {SQLGLOT_VERSION} is required, got {sqlglot.__version__}")
+
+path = os.path.join(os.path.dirname(os.path.abspath(__file__)), "extract_tables.json")
+
+with open(path, encoding="utf-8") as f:
+    original = f.read()
+cases = json.loads(original)
+
+for case in cases:
+    case.pop("tables", None)
+    case.pop("error", None)
+    try:
+        case["tables"] = [
+            {"catalog": t.catalog, "schema": t.db, "name": t.name}
+            for t in sqlglot.parse_one(case["sql"], dialect=case["dialect"]).find_all(Table)
+        ]
+    except Exception:
+        case["error"] = True
+
+generated = json.dumps(cases, indent=2, ensure_ascii=False) + "\n"
+
+if "--check" in sys.argv[1:]:
+    if generated != original:
+        sys.exit("extract_tables.json is stale, run generate_golden.py to regenerate it")
+    sys.exit(0)
+
+with open(path, "w", encoding="utf-8") as f:
+    f.write(generated)
@@ -0,0 +1,186 @@
+// Copyright The kweaver.ai Authors.
+//
+// Licensed under the Apache License, Version 2.0.
+// See the LICENSE file in the project root for details.
+
+package sqlglot
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF    tokenKind = iota
+	tokenWord             // 未加引号的标识符或关键字
+	tokenQuoted           // 加引号的标识符
+	tokenString           // 字符串字面量
+	tokenNumber           // 数值字面量
+	tokenPunct            // 运算符与标点，单字符
+)
+
+type token struct {
+	kind  tokenKind
+	text  string // 标识符为去掉引号后的原文，关键字判断使用 upper
+	upper string
+	pos   int
+}
+
+// dialectRules 各方言的词法差异
+type dialectRules struct {
+	identQuote    byte // 标识符引号
+	doubleIsStr   bool // 双引号为字符串（mysql）
+	backslashEsc  bool // 字符串支持反斜杠转义（mysql）
+	hashComment   bool // # 开头为注释（mysql）
+	hashInIdent   bool // 标识符可包含 #（oracle）
+	dollarQuoting bool // $tag$...$tag$ 字符串（postgres）
+}
+
+var dialects = map[string]dialectRules{
+	"":         {identQuote: '"'},
+	"mysql":    {identQuote: '`', doubleIsStr: true, backslashEsc: true, hashComment: true},
+	"oracle":   {identQuote: '"', hashInIdent: true},
+	"postgres": {identQuote: '"', dollarQuoting: true},
+	"trino":    {identQuote: '"'},
+	"presto":   {identQuote: '"'},
+}
+
+// tokenize 将 SQL 切分为词法单元，注释与空白被丢弃
+func tokenize(sql string, rules dialectRules) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(sql) {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-', c == '#' && rules.hashComment:
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment at position %d", i)
+			}
+			i += end + 4
+
+		case c == '\'' || (c == '"' && rules.doubleIsStr):
+			text, next, err := scanQuoted(sql, i, rules.backslashEsc)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i = next
+
+		case c == rules.identQuote:
+			text, next, err := scanQuoted(sql, i, false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenQuoted, text: text, pos: i})
+			i = next
+
+		case c == '$' && rules.dollarQuoting && isDollarQuoteStart(sql[i:]):
+			tag := sql[i : strings.IndexByte(sql[i+1:], '$')+i+2]
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated dollar-quoted string at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: sql[i+len(tag) : i+len(tag)+end], pos: i})
+			i += len(tag)*2 + end
+
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(sql) && (isDigit(sql[i]) || sql[i] == '.' ||
+				((sql[i] == 'e' || sql[i] == 'E') && i+1 < len(sql) && (isDigit(sql[i+1]) || sql[i+1] == '-' || sql[i+1] == '+'))) {
+				if sql[i] == 'e' || sql[i] == 'E' {
+					i++
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: sql[start:i], pos: start})
+
+		default:
+			r, size := utf8.DecodeRuneInString(sql[i:])
+			if !isIdentStart(r) {
+				tokens = append(tokens, token{kind: tokenPunct, text: sql[i : i+size], pos: i})
+				i += size
+				continue
+			}
+
+			start := i
+			for i < len(sql) {
+				r, size := utf8.DecodeRuneInString(sql[i:])
+				if !isIdentStart(r) && !unicode.IsDigit(r) && r != '$' && !(r == '#' && rules.hashInIdent) {
+					break
+				}
+				i += size
+			}
+			word := sql[start:i]
+			// E'...' 为支持反斜杠转义的字符串
+			if (word == "E" || word == "e") && i < len(sql) && sql[i] == '\'' {
+				text, next, err := scanQuoted(sql, i, true)
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, token{kind: tokenString, text: text, pos: start})
+				i = next
+				continue
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: word, upper: strings.ToUpper(word), pos: start})
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(sql)}), nil
+}
+
+// scanQuoted 读取以 sql[start] 为引号的内容，重复引号转义为一个引号
+func scanQuoted(sql string, start int, backslashEsc bool) (string, int, error) {
+	quote := sql[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\\' && backslashEsc && i+1 < len(sql):
+			i++
+			sb.WriteByte(sql[i])
+		case c == quote && i+1 < len(sql) && sql[i+1] == quote:
+			i++
+			sb.WriteByte(quote)
+		case c == quote:
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quote %c at position %d", quote, start)
+}
+
+// isDollarQuoteStart 判断是否为 $$ 或 $tag$ 开头
+func isDollarQuoteStart(s string) bool {
+	end := strings.IndexByte(s[1:], '$')
+	if end < 0 {
+		return false
+	}
+	for _, r := range s[1 : end+1] {
+		if !isIdentStart(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return end == 0 || !unicode.IsDigit(rune(s[1]))
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}